@@ -142,3 +142,20 @@ type BCCSP interface {
 	// The opts argument should be appropriate for the algorithm used.
 	Decrypt(k Key, ciphertext []byte, opts DecrypterOpts) (plaintext []byte, err error)
 }
+
+// VerifyRequest is a signature to be verified along with others by a BatchVerifier
+type VerifyRequest struct {
+	Key       Key
+	Signature []byte
+	Digest    []byte
+	Opts      SignerOpts
+}
+
+// BatchVerifier is implemented by the CSPs which verify a set of signatures, such as those of
+// the transactions of a block, faster than one signature after the other, by spreading them on
+// several cores, as the software-based CSP does, or by relying on an accelerated implementation.
+type BatchVerifier interface {
+	// VerifyBatch verifies the signatures of the requests, and returns whether each of them is
+	// valid. A malformed signature is invalid, an error fails the whole batch.
+	VerifyBatch(requests []VerifyRequest) (valid []bool, err error)
+}
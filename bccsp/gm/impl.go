@@ -0,0 +1,285 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gm
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"sync"
+
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/gm/sm2"
+	"github.com/hyperledger/fabric/bccsp/gm/sm3"
+	"github.com/hyperledger/fabric/bccsp/gm/sm4"
+	"github.com/hyperledger/fabric/common/flogging"
+)
+
+var logger = flogging.MustGetLogger("bccsp_gm")
+
+// impl is a BCCSP implementation of the Chinese national
+// cryptographic standards: SM2 signatures, SM3 digests and SM4 encryption.
+// It is for testing only, as its SM2 signatures are not constant-time.
+// Keys are kept in memory
+type impl struct {
+	lock sync.RWMutex
+	keys map[string]bccsp.Key
+}
+
+// New returns a new instance of the GM BCCSP. It is for testing only, as
+// its SM2 signatures are not constant-time, and the BCCSP factories do not
+// offer it as a provider
+func New() bccsp.BCCSP {
+	return &impl{keys: make(map[string]bccsp.Key)}
+}
+
+// KeyGen generates a key using opts.
+func (csp *impl) KeyGen(opts bccsp.KeyGenOpts) (bccsp.Key, error) {
+	if opts == nil {
+		return nil, errors.New("Invalid Opts parameter. It must not be nil.")
+	}
+
+	var k bccsp.Key
+	switch opts.(type) {
+	case *bccsp.SM2KeyGenOpts:
+		privKey, err := sm2.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("Failed generating SM2 key: %s", err)
+		}
+		k = &sm2PrivateKey{privKey}
+	case *bccsp.SM4KeyGenOpts:
+		key := make([]byte, sm4.KeySize)
+		if _, err := io.ReadFull(rand.Reader, key); err != nil {
+			return nil, fmt.Errorf("Failed generating SM4 key: %s", err)
+		}
+		k = &sm4Key{key: key}
+	default:
+		return nil, fmt.Errorf("Unsupported 'KeyGenOpts' provided [%v]", opts)
+	}
+
+	if !opts.Ephemeral() {
+		csp.store(k)
+	}
+	return k, nil
+}
+
+// KeyDeriv is not supported by the GM BCCSP
+func (csp *impl) KeyDeriv(k bccsp.Key, opts bccsp.KeyDerivOpts) (bccsp.Key, error) {
+	return nil, errors.New("Key derivation is not supported")
+}
+
+// KeyImport imports a key from its raw representation using opts.
+func (csp *impl) KeyImport(raw interface{}, opts bccsp.KeyImportOpts) (bccsp.Key, error) {
+	if raw == nil {
+		return nil, errors.New("Invalid raw. It must not be nil.")
+	}
+	if opts == nil {
+		return nil, errors.New("Invalid Opts parameter. It must not be nil.")
+	}
+
+	var k bccsp.Key
+	switch opts.(type) {
+	case *bccsp.SM2PKIXPublicKeyImportOpts:
+		der, ok := raw.([]byte)
+		if !ok {
+			return nil, errors.New("Invalid raw material. Expected byte array.")
+		}
+		pubKey, err := sm2.ParsePKIXPublicKey(der)
+		if err != nil {
+			return nil, fmt.Errorf("Failed converting PKIX to SM2 public key [%s]", err)
+		}
+		k = &sm2PublicKey{pubKey}
+	case *bccsp.SM4ImportKeyOpts:
+		key, ok := raw.([]byte)
+		if !ok {
+			return nil, errors.New("Invalid raw material. Expected byte array.")
+		}
+		if len(key) != sm4.KeySize {
+			return nil, fmt.Errorf("Invalid SM4 key length [%d]. Must be %d bytes", len(key), sm4.KeySize)
+		}
+		k = &sm4Key{key: append([]byte{}, key...)}
+	case *bccsp.X509PublicKeyImportOpts:
+		var cert *sm2.Certificate
+		switch r := raw.(type) {
+		case *sm2.Certificate:
+			cert = r
+		case []byte:
+			var err error
+			if cert, err = sm2.ParseCertificate(r); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, errors.New("Invalid raw material. Expected *sm2.Certificate or DER certificate.")
+		}
+		k = &sm2PublicKey{cert.PublicKey}
+	default:
+		return nil, fmt.Errorf("Unsupported 'KeyImportOpts' provided [%v]", opts)
+	}
+
+	if !opts.Ephemeral() {
+		csp.store(k)
+	}
+	return k, nil
+}
+
+// GetKey returns the key this CSP associates to
+// the Subject Key Identifier ski.
+func (csp *impl) GetKey(ski []byte) (bccsp.Key, error) {
+	csp.lock.RLock()
+	defer csp.lock.RUnlock()
+
+	k, ok := csp.keys[hex.EncodeToString(ski)]
+	if !ok {
+		return nil, fmt.Errorf("Key with SKI %x not found", ski)
+	}
+	return k, nil
+}
+
+func (csp *impl) store(k bccsp.Key) {
+	csp.lock.Lock()
+	defer csp.lock.Unlock()
+
+	csp.keys[hex.EncodeToString(k.SKI())] = k
+}
+
+// Hash hashes messages msg using options opts.
+func (csp *impl) Hash(msg []byte, opts bccsp.HashOpts) ([]byte, error) {
+	h, err := csp.GetHash(opts)
+	if err != nil {
+		return nil, err
+	}
+	h.Write(msg)
+	return h.Sum(nil), nil
+}
+
+// GetHash returns and instance of hash.Hash using options opts.
+// SM3 is used when opts is nil.
+func (csp *impl) GetHash(opts bccsp.HashOpts) (hash.Hash, error) {
+	switch opts.(type) {
+	case nil, *bccsp.SM3Opts:
+		return sm3.New(), nil
+	default:
+		return nil, fmt.Errorf("Unsupported 'HashOpts' provided [%v]", opts)
+	}
+}
+
+// Sign signs digest using key k with the default SM2 user identity.
+// The signature is the ASN.1 encoding of (r, s).
+func (csp *impl) Sign(k bccsp.Key, digest []byte, opts bccsp.SignerOpts) ([]byte, error) {
+	if k == nil {
+		return nil, errors.New("Invalid Key. It must not be nil.")
+	}
+	if len(digest) == 0 {
+		return nil, errors.New("Invalid digest. Cannot be empty.")
+	}
+
+	key, ok := k.(*sm2PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("Unsupported 'SignKey' provided [%v]", k)
+	}
+	return sm2.SignASN1(rand.Reader, key.privKey, sm2.DefaultUID, digest)
+}
+
+// Verify verifies signature against key k and digest
+func (csp *impl) Verify(k bccsp.Key, signature, digest []byte, opts bccsp.SignerOpts) (bool, error) {
+	if k == nil {
+		return false, errors.New("Invalid Key. It must not be nil.")
+	}
+	if len(signature) == 0 {
+		return false, errors.New("Invalid signature. Cannot be empty.")
+	}
+	if len(digest) == 0 {
+		return false, errors.New("Invalid digest. Cannot be empty.")
+	}
+
+	var pubKey *sm2.PublicKey
+	switch key := k.(type) {
+	case *sm2PrivateKey:
+		pubKey = key.privKey.Public()
+	case *sm2PublicKey:
+		pubKey = key.pubKey
+	default:
+		return false, fmt.Errorf("Unsupported 'VerifyKey' provided [%v]", k)
+	}
+	return sm2.VerifyASN1(pubKey, sm2.DefaultUID, digest, signature), nil
+}
+
+// Encrypt encrypts plaintext using SM4 in CBC mode with PKCS7 padding.
+// The random IV is prepended to the ciphertext.
+func (csp *impl) Encrypt(k bccsp.Key, plaintext []byte, opts bccsp.EncrypterOpts) ([]byte, error) {
+	key, err := sm4KeyAndOpts(k, opts)
+	if err != nil {
+		return nil, err
+	}
+	block, err := sm4.NewCipher(key.key)
+	if err != nil {
+		return nil, err
+	}
+
+	padding := sm4.BlockSize - len(plaintext)%sm4.BlockSize
+	src := append(append([]byte{}, plaintext...), bytes.Repeat([]byte{byte(padding)}, padding)...)
+
+	ciphertext := make([]byte, sm4.BlockSize+len(src))
+	iv := ciphertext[:sm4.BlockSize]
+	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+		return nil, err
+	}
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext[sm4.BlockSize:], src)
+	return ciphertext, nil
+}
+
+// Decrypt decrypts ciphertext produced by Encrypt
+func (csp *impl) Decrypt(k bccsp.Key, ciphertext []byte, opts bccsp.DecrypterOpts) ([]byte, error) {
+	key, err := sm4KeyAndOpts(k, opts)
+	if err != nil {
+		return nil, err
+	}
+	block, err := sm4.NewCipher(key.key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ciphertext) < 2*sm4.BlockSize || len(ciphertext)%sm4.BlockSize != 0 {
+		return nil, errors.New("Invalid ciphertext. It must be a multiple of the block size")
+	}
+	iv, src := ciphertext[:sm4.BlockSize], ciphertext[sm4.BlockSize:]
+	plaintext := make([]byte, len(src))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, src)
+
+	padding := int(plaintext[len(plaintext)-1])
+	if padding == 0 || padding > sm4.BlockSize {
+		return nil, errors.New("Invalid pkcs7 padding")
+	}
+	for _, b := range plaintext[len(plaintext)-padding:] {
+		if int(b) != padding {
+			return nil, errors.New("Invalid pkcs7 padding")
+		}
+	}
+	return plaintext[:len(plaintext)-padding], nil
+}
+
+func sm4KeyAndOpts(k bccsp.Key, opts interface{}) (*sm4Key, error) {
+	if k == nil {
+		return nil, errors.New("Invalid Key. It must not be nil.")
+	}
+	key, ok := k.(*sm4Key)
+	if !ok {
+		return nil, fmt.Errorf("Unsupported key type [%T]", k)
+	}
+	switch opts.(type) {
+	case *bccsp.SM4CBCPKCS7ModeOpts, bccsp.SM4CBCPKCS7ModeOpts:
+		return key, nil
+	default:
+		logger.Debugf("Unsupported SM4 mode [%T]", opts)
+		return nil, fmt.Errorf("Mode not recognized [%s]", opts)
+	}
+}
@@ -0,0 +1,113 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gm
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/gm/sm3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHash(t *testing.T) {
+	csp := New()
+	msg := []byte("abc")
+	expected := sm3.Sum(msg)
+
+	digest, err := csp.Hash(msg, &bccsp.SM3Opts{})
+	require.NoError(t, err)
+	assert.Equal(t, expected[:], digest)
+	digest, err = csp.Hash(msg, nil)
+	require.NoError(t, err)
+	assert.Equal(t, expected[:], digest)
+
+	_, err = csp.Hash(msg, &bccsp.SHA256Opts{})
+	assert.Error(t, err)
+}
+
+func TestSignVerify(t *testing.T) {
+	csp := New()
+	k, err := csp.KeyGen(&bccsp.SM2KeyGenOpts{})
+	require.NoError(t, err)
+	assert.True(t, k.Private())
+	assert.False(t, k.Symmetric())
+
+	stored, err := csp.GetKey(k.SKI())
+	require.NoError(t, err)
+	assert.Equal(t, k, stored)
+
+	digest, err := csp.Hash([]byte("hello"), nil)
+	require.NoError(t, err)
+	signature, err := csp.Sign(k, digest, nil)
+	require.NoError(t, err)
+
+	pk, err := k.PublicKey()
+	require.NoError(t, err)
+	assert.Equal(t, k.SKI(), pk.SKI())
+	valid, err := csp.Verify(pk, signature, digest, nil)
+	require.NoError(t, err)
+	assert.True(t, valid)
+	valid, err = csp.Verify(k, signature, []byte("another digest"), nil)
+	require.NoError(t, err)
+	assert.False(t, valid)
+
+	// The public key round-trips through its PKIX encoding
+	der, err := pk.Bytes()
+	require.NoError(t, err)
+	imported, err := csp.KeyImport(der, &bccsp.SM2PKIXPublicKeyImportOpts{Temporary: true})
+	require.NoError(t, err)
+	assert.Equal(t, pk.SKI(), imported.SKI())
+	valid, err = csp.Verify(imported, signature, digest, nil)
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	_, err = csp.Sign(pk, digest, nil)
+	assert.Error(t, err)
+	_, err = k.Bytes()
+	assert.Error(t, err)
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	csp := New()
+	k, err := csp.KeyGen(&bccsp.SM4KeyGenOpts{Temporary: true})
+	require.NoError(t, err)
+	assert.True(t, k.Symmetric())
+	_, err = csp.GetKey(k.SKI())
+	assert.Error(t, err, "Ephemeral keys are not stored")
+
+	for _, plaintext := range [][]byte{{}, []byte("hello"), make([]byte, 32)} {
+		ciphertext, err := csp.Encrypt(k, plaintext, &bccsp.SM4CBCPKCS7ModeOpts{})
+		require.NoError(t, err)
+		decrypted, err := csp.Decrypt(k, ciphertext, &bccsp.SM4CBCPKCS7ModeOpts{})
+		require.NoError(t, err)
+		assert.Equal(t, plaintext, decrypted)
+	}
+
+	_, err = csp.Encrypt(k, []byte("hello"), &bccsp.AESCBCPKCS7ModeOpts{})
+	assert.Error(t, err)
+	_, err = csp.Decrypt(k, []byte("short"), &bccsp.SM4CBCPKCS7ModeOpts{})
+	assert.Error(t, err)
+
+	imported, err := csp.KeyImport(make([]byte, 16), &bccsp.SM4ImportKeyOpts{})
+	require.NoError(t, err)
+	_, err = imported.Bytes()
+	assert.Error(t, err)
+	_, err = csp.KeyImport(make([]byte, 32), &bccsp.SM4ImportKeyOpts{})
+	assert.Error(t, err)
+}
+
+func TestUnsupported(t *testing.T) {
+	csp := New()
+	_, err := csp.KeyGen(&bccsp.ECDSAKeyGenOpts{})
+	assert.Error(t, err)
+	_, err = csp.KeyImport([]byte{1}, &bccsp.ECDSAPKIXPublicKeyImportOpts{})
+	assert.Error(t, err)
+	_, err = csp.KeyDeriv(nil, &bccsp.ECDSAReRandKeyOpts{})
+	assert.Error(t, err)
+}
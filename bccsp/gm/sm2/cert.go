@@ -0,0 +1,205 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sm2
+
+import (
+	"bytes"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// OIDSignatureSM2WithSM3 identifies the SM2 signatures of SM3 digests
+var OIDSignatureSM2WithSM3 = asn1.ObjectIdentifier{1, 2, 156, 10197, 1, 501}
+
+// Certificate is an X.509 certificate of an SM2 public key, which crypto/x509 does not parse.
+// Only the fields needed to validate certification chains are decoded
+type Certificate struct {
+	Raw                     []byte
+	RawTBSCertificate       []byte
+	RawSubject              []byte
+	RawIssuer               []byte
+	SerialNumber            *big.Int
+	NotBefore, NotAfter     time.Time
+	PublicKey               *PublicKey
+	SignatureAlgorithm      asn1.ObjectIdentifier
+	Signature               []byte
+	Extensions              []pkix.Extension
+	Subject, Issuer         pkix.Name
+	SubjectKeyId, AuthKeyId []byte
+	IsCA                    bool
+}
+
+type certificate struct {
+	Raw                asn1.RawContent
+	TBSCertificate     tbsCertificate
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	SignatureValue     asn1.BitString
+}
+
+type tbsCertificate struct {
+	Raw                asn1.RawContent
+	Version            int `asn1:"optional,explicit,default:0,tag:0"`
+	SerialNumber       *big.Int
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	Issuer             asn1.RawValue
+	Validity           validity
+	Subject            asn1.RawValue
+	PublicKey          publicKeyInfo
+	UniqueID           asn1.BitString   `asn1:"optional,tag:1"`
+	SubjectUniqueID    asn1.BitString   `asn1:"optional,tag:2"`
+	Extensions         []pkix.Extension `asn1:"optional,explicit,tag:3"`
+}
+
+type validity struct {
+	NotBefore, NotAfter time.Time
+}
+
+type authKeyID struct {
+	ID []byte `asn1:"optional,tag:0"`
+}
+
+type basicConstraints struct {
+	IsCA       bool `asn1:"optional"`
+	MaxPathLen int  `asn1:"optional,default:-1"`
+}
+
+var (
+	oidExtensionSubjectKeyID     = asn1.ObjectIdentifier{2, 5, 29, 14}
+	oidExtensionBasicConstraints = asn1.ObjectIdentifier{2, 5, 29, 19}
+	oidExtensionAuthorityKeyID   = asn1.ObjectIdentifier{2, 5, 29, 35}
+)
+
+// maxChainLength bounds the length of the certification chains built by Verify
+const maxChainLength = 10
+
+// ParseCertificate parses a DER certificate of an SM2 public key
+func ParseCertificate(der []byte) (*Certificate, error) {
+	var cert certificate
+	rest, err := asn1.Unmarshal(der, &cert)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing certificate: %s", err)
+	}
+	if len(rest) != 0 {
+		return nil, errors.New("trailing data after certificate")
+	}
+	tbs := &cert.TBSCertificate
+	if !cert.SignatureAlgorithm.Algorithm.Equal(tbs.SignatureAlgorithm.Algorithm) {
+		return nil, errors.New("signature algorithm mismatch in certificate")
+	}
+
+	pub, err := parsePublicKeyInfo(&tbs.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Certificate{
+		Raw:                cert.Raw,
+		RawTBSCertificate:  tbs.Raw,
+		RawSubject:         tbs.Subject.FullBytes,
+		RawIssuer:          tbs.Issuer.FullBytes,
+		SerialNumber:       tbs.SerialNumber,
+		NotBefore:          tbs.Validity.NotBefore,
+		NotAfter:           tbs.Validity.NotAfter,
+		PublicKey:          pub,
+		SignatureAlgorithm: cert.SignatureAlgorithm.Algorithm,
+		Signature:          cert.SignatureValue.RightAlign(),
+		Extensions:         tbs.Extensions,
+	}
+	for _, name := range []struct {
+		raw  []byte
+		name *pkix.Name
+	}{{c.RawSubject, &c.Subject}, {c.RawIssuer, &c.Issuer}} {
+		var rdn pkix.RDNSequence
+		if _, err := asn1.Unmarshal(name.raw, &rdn); err != nil {
+			return nil, fmt.Errorf("failed parsing certificate name: %s", err)
+		}
+		name.name.FillFromRDNSequence(&rdn)
+	}
+	for _, ext := range tbs.Extensions {
+		switch {
+		case ext.Id.Equal(oidExtensionSubjectKeyID):
+			if _, err := asn1.Unmarshal(ext.Value, &c.SubjectKeyId); err != nil {
+				return nil, fmt.Errorf("failed parsing subject key identifier: %s", err)
+			}
+		case ext.Id.Equal(oidExtensionAuthorityKeyID):
+			var aki authKeyID
+			if _, err := asn1.Unmarshal(ext.Value, &aki); err != nil {
+				return nil, fmt.Errorf("failed parsing authority key identifier: %s", err)
+			}
+			c.AuthKeyId = aki.ID
+		case ext.Id.Equal(oidExtensionBasicConstraints):
+			var bc basicConstraints
+			if _, err := asn1.Unmarshal(ext.Value, &bc); err != nil {
+				return nil, fmt.Errorf("failed parsing basic constraints: %s", err)
+			}
+			c.IsCA = bc.IsCA
+		}
+	}
+	return c, nil
+}
+
+// CheckSignature verifies the SM2 with SM3 signature of signed data by the owner of a public key
+func CheckSignature(pub *PublicKey, algorithm asn1.ObjectIdentifier, signed, signature []byte) error {
+	if !algorithm.Equal(OIDSignatureSM2WithSM3) {
+		return fmt.Errorf("unsupported signature algorithm %s", algorithm)
+	}
+	if !VerifyASN1(pub, DefaultUID, signed, signature) {
+		return errors.New("SM2 signature verification failure")
+	}
+	return nil
+}
+
+// CheckSignatureFrom verifies that the certificate was issued by the parent certificate
+func (c *Certificate) CheckSignatureFrom(parent *Certificate) error {
+	if !bytes.Equal(c.RawIssuer, parent.RawSubject) {
+		return errors.New("the issuer of the certificate is not the subject of the parent")
+	}
+	if len(c.AuthKeyId) > 0 && len(parent.SubjectKeyId) > 0 && !bytes.Equal(c.AuthKeyId, parent.SubjectKeyId) {
+		return errors.New("the authority key of the certificate is not the key of the parent")
+	}
+	return CheckSignature(parent.PublicKey, c.SignatureAlgorithm, c.RawTBSCertificate, c.Signature)
+}
+
+// Verify builds the certification chain of the certificate up to one of the roots, through the
+// intermediate CAs, checking the signatures and the validity periods of its certificates at the
+// given time. It returns the chain, starting with the certificate
+func (c *Certificate) Verify(roots, intermediates []*Certificate, now time.Time) ([]*Certificate, error) {
+	chain := []*Certificate{c}
+	for len(chain) <= maxChainLength {
+		cert := chain[len(chain)-1]
+		if now.Before(cert.NotBefore) || now.After(cert.NotAfter) {
+			return nil, fmt.Errorf("certificate %s is expired or not yet valid", cert.Subject.CommonName)
+		}
+		for _, root := range roots {
+			if bytes.Equal(cert.Raw, root.Raw) {
+				return chain, nil
+			}
+			if root.IsCA && cert.CheckSignatureFrom(root) == nil {
+				if now.Before(root.NotBefore) || now.After(root.NotAfter) {
+					return nil, fmt.Errorf("root certificate %s is expired or not yet valid", root.Subject.CommonName)
+				}
+				return append(chain, root), nil
+			}
+		}
+		var parent *Certificate
+		for _, intermediate := range intermediates {
+			if intermediate.IsCA && !bytes.Equal(cert.Raw, intermediate.Raw) && cert.CheckSignatureFrom(intermediate) == nil {
+				parent = intermediate
+				break
+			}
+		}
+		if parent == nil {
+			return nil, fmt.Errorf("certificate %s is not signed by a known authority", cert.Subject.CommonName)
+		}
+		chain = append(chain, parent)
+	}
+	return nil, errors.New("certification chain too long")
+}
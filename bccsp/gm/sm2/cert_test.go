@@ -0,0 +1,126 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sm2
+
+import (
+	"crypto/rand"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCA struct {
+	cert *Certificate
+	key  *PrivateKey
+}
+
+// issue creates a certificate of a new key, signed by the CA or self-signed if the CA is nil
+func issue(t *testing.T, ca *testCA, name string, isCA bool, notAfter time.Time) *testCA {
+	key, err := GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	subject, err := asn1.Marshal(pkix.Name{CommonName: name}.ToRDNSequence())
+	require.NoError(t, err)
+	issuer, signer := subject, key
+	if ca != nil {
+		issuer, signer = ca.cert.RawSubject, ca.key
+	}
+	var extensions []pkix.Extension
+	if isCA {
+		value, err := asn1.Marshal(basicConstraints{IsCA: true, MaxPathLen: -1})
+		require.NoError(t, err)
+		extensions = append(extensions, pkix.Extension{Id: oidExtensionBasicConstraints, Critical: true, Value: value})
+	}
+	pub, err := MarshalPKIXPublicKey(key.Public())
+	require.NoError(t, err)
+	var pubInfo publicKeyInfo
+	_, err = asn1.Unmarshal(pub, &pubInfo)
+	require.NoError(t, err)
+
+	sigAlg := pkix.AlgorithmIdentifier{Algorithm: OIDSignatureSM2WithSM3}
+	tbs, err := asn1.Marshal(tbsCertificate{
+		Version:            2,
+		SerialNumber:       big.NewInt(time.Now().UnixNano()),
+		SignatureAlgorithm: sigAlg,
+		Issuer:             asn1.RawValue{FullBytes: issuer},
+		Validity:           validity{NotBefore: time.Now().Add(-time.Hour).UTC(), NotAfter: notAfter.UTC()},
+		Subject:            asn1.RawValue{FullBytes: subject},
+		PublicKey:          pubInfo,
+		Extensions:         extensions,
+	})
+	require.NoError(t, err)
+	signature, err := SignASN1(rand.Reader, signer, DefaultUID, tbs)
+	require.NoError(t, err)
+	der, err := asn1.Marshal(certificate{
+		TBSCertificate:     tbsCertificate{Raw: tbs},
+		SignatureAlgorithm: sigAlg,
+		SignatureValue:     asn1.BitString{Bytes: signature, BitLength: len(signature) * 8},
+	})
+	require.NoError(t, err)
+
+	cert, err := ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCA{cert: cert, key: key}
+}
+
+func TestParseCertificate(t *testing.T) {
+	notAfter := time.Now().Add(time.Hour).Truncate(time.Second)
+	root := issue(t, nil, "ca.org1", true, notAfter)
+	cert := root.cert
+	assert.Equal(t, "ca.org1", cert.Subject.CommonName)
+	assert.Equal(t, "ca.org1", cert.Issuer.CommonName)
+	assert.True(t, cert.IsCA)
+	assert.True(t, notAfter.Equal(cert.NotAfter))
+	assert.Equal(t, root.key.X, cert.PublicKey.X)
+	assert.NoError(t, cert.CheckSignatureFrom(cert))
+
+	_, err := ParseCertificate(append(append([]byte{}, cert.Raw...), 0))
+	assert.Error(t, err)
+	_, err = ParseCertificate([]byte("garbage"))
+	assert.Error(t, err)
+}
+
+func TestVerify(t *testing.T) {
+	now := time.Now()
+	notAfter := now.Add(time.Hour)
+	root := issue(t, nil, "ca.org1", true, notAfter)
+	intermediate := issue(t, root, "ica.org1", true, notAfter)
+	leaf := issue(t, intermediate, "peer0.org1", false, notAfter)
+	roots := []*Certificate{root.cert}
+	intermediates := []*Certificate{intermediate.cert}
+
+	chain, err := leaf.cert.Verify(roots, intermediates, now)
+	require.NoError(t, err)
+	assert.Equal(t, []*Certificate{leaf.cert, intermediate.cert, root.cert}, chain)
+
+	chain, err = root.cert.Verify(roots, nil, now)
+	require.NoError(t, err)
+	assert.Equal(t, []*Certificate{root.cert}, chain)
+
+	_, err = leaf.cert.Verify(roots, nil, now)
+	assert.Error(t, err, "The intermediate CA is needed")
+	_, err = leaf.cert.Verify(roots, intermediates, notAfter.Add(time.Minute))
+	assert.Error(t, err, "The certificates are expired")
+
+	// A certificate signed by another key in the name of the root is rejected
+	impostor := issue(t, nil, "ca.org1", true, notAfter)
+	forged := issue(t, impostor, "peer1.org1", false, notAfter)
+	_, err = forged.cert.Verify(roots, nil, now)
+	assert.Error(t, err)
+	assert.Error(t, forged.cert.CheckSignatureFrom(root.cert))
+
+	// Certificates which are not CAs do not issue certificates
+	notCA := issue(t, root, "user.org1", false, notAfter)
+	issued := issue(t, notCA, "peer2.org1", false, notAfter)
+	_, err = issued.cert.Verify(roots, []*Certificate{notCA.cert}, now)
+	assert.Error(t, err)
+}
@@ -0,0 +1,291 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package sm2 implements the SM2 digital signature algorithm over the curve recommended by
+// GB/T 32918-2016, with SM3 digests.
+//
+// This implementation is for testing only. The curve arithmetic relies on the generic
+// implementation of elliptic.CurveParams and the nonce inversion on math/big, neither of which
+// runs in constant time, so the private keys can leak through timing side channels. It must not
+// hold production keys.
+package sm2
+
+import (
+	"crypto/elliptic"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"sync"
+
+	"github.com/hyperledger/fabric/bccsp/gm/sm3"
+)
+
+// DefaultUID is the distinguishing identifier of the signers used when none is agreed upon, as
+// recommended by GM/T 0009-2012
+var DefaultUID = []byte("1234567812345678")
+
+var (
+	initOnce sync.Once
+	p256Sm2  *elliptic.CurveParams
+)
+
+func initP256Sm2() {
+	p256Sm2 = &elliptic.CurveParams{Name: "SM2-P-256"}
+	p256Sm2.P, _ = new(big.Int).SetString("FFFFFFFEFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF00000000FFFFFFFFFFFFFFFF", 16)
+	p256Sm2.N, _ = new(big.Int).SetString("FFFFFFFEFFFFFFFFFFFFFFFFFFFFFFFF7203DF6B21C6052B53BBF40939D54123", 16)
+	p256Sm2.B, _ = new(big.Int).SetString("28E9FA9E9D9F5E344D5A9E4BCF6509A7F39789F515AB8F92DDBCBD414D940E93", 16)
+	p256Sm2.Gx, _ = new(big.Int).SetString("32C4AE2C1F1981195F9904466A39C9948FE30BBFF2660BE1715A4589334C74C7", 16)
+	p256Sm2.Gy, _ = new(big.Int).SetString("BC3736A2F4F6779C59BDCEE36B692153D0A9877CC62A474002DF32E52139F0A0", 16)
+	p256Sm2.BitSize = 256
+}
+
+// P256Sm2 returns the curve recommended for SM2. Its coefficient a is -3, like the NIST curves,
+// which the generic implementation of elliptic.CurveParams relies upon
+func P256Sm2() elliptic.Curve {
+	initOnce.Do(initP256Sm2)
+	return p256Sm2
+}
+
+// PublicKey is an SM2 public key
+type PublicKey struct {
+	elliptic.Curve
+	X, Y *big.Int
+}
+
+// PrivateKey is an SM2 private key
+type PrivateKey struct {
+	PublicKey
+	D *big.Int
+}
+
+// Public returns the public key of the private key
+func (priv *PrivateKey) Public() *PublicKey {
+	return &priv.PublicKey
+}
+
+var one = big.NewInt(1)
+
+// GenerateKey generates an SM2 key pair. The private key lies in [1, n-2], so that 1+d is
+// invertible modulo n
+func GenerateKey(rand io.Reader) (*PrivateKey, error) {
+	c := P256Sm2()
+	d, err := randScalar(c, rand, new(big.Int).Sub(c.Params().N, one))
+	if err != nil {
+		return nil, err
+	}
+	priv := &PrivateKey{D: d}
+	priv.Curve = c
+	priv.X, priv.Y = c.ScalarBaseMult(d.Bytes())
+	return priv, nil
+}
+
+// randScalar returns a random integer in [1, max-1]
+func randScalar(c elliptic.Curve, rand io.Reader, max *big.Int) (*big.Int, error) {
+	b := make([]byte, c.Params().BitSize/8+8)
+	if _, err := io.ReadFull(rand, b); err != nil {
+		return nil, err
+	}
+	k := new(big.Int).SetBytes(b)
+	k.Mod(k, new(big.Int).Sub(max, one))
+	return k.Add(k, one), nil
+}
+
+// ZA returns the hash of the distinguishing identifier of a signer, the curve and its public key,
+// which prefixes the signed messages
+func ZA(pub *PublicKey, uid []byte) ([]byte, error) {
+	if len(uid) >= 8192 {
+		return nil, errors.New("the distinguishing identifier is too long")
+	}
+	params := pub.Curve.Params()
+	a := new(big.Int).Sub(params.P, big.NewInt(3))
+
+	h := sm3.New()
+	entl := len(uid) * 8
+	h.Write([]byte{byte(entl >> 8), byte(entl)})
+	h.Write(uid)
+	for _, v := range []*big.Int{a, params.B, params.Gx, params.Gy, pub.X, pub.Y} {
+		h.Write(padded(v, 32))
+	}
+	return h.Sum(nil), nil
+}
+
+// digest returns the SM3 digest of a message signed by the owner of a public key
+func digest(pub *PublicKey, uid, msg []byte) (*big.Int, error) {
+	za, err := ZA(pub, uid)
+	if err != nil {
+		return nil, err
+	}
+	h := sm3.New()
+	h.Write(za)
+	h.Write(msg)
+	return new(big.Int).SetBytes(h.Sum(nil)), nil
+}
+
+// Sign signs a message, which it hashes along with the distinguishing identifier of the signer
+//
+// Sign is not constant-time, see the package documentation
+func Sign(rand io.Reader, priv *PrivateKey, uid, msg []byte) (r, s *big.Int, err error) {
+	e, err := digest(&priv.PublicKey, uid, msg)
+	if err != nil {
+		return nil, nil, err
+	}
+	n := priv.Curve.Params().N
+	for {
+		k, err := randScalar(priv.Curve, rand, n)
+		if err != nil {
+			return nil, nil, err
+		}
+		r, s = sign(priv, e, k)
+		if r != nil {
+			return r, s, nil
+		}
+	}
+}
+
+// sign signs a digest with the random k, returning nil if k is not suitable
+func sign(priv *PrivateKey, e, k *big.Int) (r, s *big.Int) {
+	n := priv.Curve.Params().N
+	x1, _ := priv.Curve.ScalarBaseMult(k.Bytes())
+	r = new(big.Int).Add(e, x1)
+	r.Mod(r, n)
+	if r.Sign() == 0 || new(big.Int).Add(r, k).Cmp(n) == 0 {
+		return nil, nil
+	}
+
+	// s = (1+d)^-1 * (k - r*d) mod n
+	dInv := new(big.Int).Add(priv.D, one)
+	dInv.ModInverse(dInv, n)
+	s = new(big.Int).Mul(r, priv.D)
+	s.Sub(k, s)
+	s.Mul(s, dInv)
+	s.Mod(s, n)
+	if s.Sign() == 0 {
+		return nil, nil
+	}
+	return r, s
+}
+
+// Verify verifies the signature of a message by the owner of a public key
+func Verify(pub *PublicKey, uid, msg []byte, r, s *big.Int) bool {
+	n := pub.Curve.Params().N
+	if r.Sign() <= 0 || s.Sign() <= 0 || r.Cmp(n) >= 0 || s.Cmp(n) >= 0 {
+		return false
+	}
+	e, err := digest(pub, uid, msg)
+	if err != nil {
+		return false
+	}
+	t := new(big.Int).Add(r, s)
+	t.Mod(t, n)
+	if t.Sign() == 0 {
+		return false
+	}
+	x1, y1 := pub.Curve.ScalarBaseMult(s.Bytes())
+	x2, y2 := pub.Curve.ScalarMult(pub.X, pub.Y, t.Bytes())
+	x, _ := pub.Curve.Add(x1, y1, x2, y2)
+	x.Add(x, e)
+	x.Mod(x, n)
+	return x.Cmp(r) == 0
+}
+
+type signature struct {
+	R, S *big.Int
+}
+
+// SignASN1 signs a message, returning the DER encoding of the signature
+func SignASN1(rand io.Reader, priv *PrivateKey, uid, msg []byte) ([]byte, error) {
+	r, s, err := Sign(rand, priv, uid, msg)
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(signature{r, s})
+}
+
+// VerifyASN1 verifies the DER encoded signature of a message
+func VerifyASN1(pub *PublicKey, uid, msg, sig []byte) bool {
+	var rs signature
+	if rest, err := asn1.Unmarshal(sig, &rs); err != nil || len(rest) != 0 || rs.R == nil || rs.S == nil {
+		return false
+	}
+	return Verify(pub, uid, msg, rs.R, rs.S)
+}
+
+var (
+	oidPublicKeyEC = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+	// OIDNamedCurveP256Sm2 identifies the curve recommended for SM2
+	OIDNamedCurveP256Sm2 = asn1.ObjectIdentifier{1, 2, 156, 10197, 1, 301}
+)
+
+type algorithmIdentifier struct {
+	Algorithm  asn1.ObjectIdentifier
+	Parameters asn1.ObjectIdentifier
+}
+
+type publicKeyInfo struct {
+	Algorithm algorithmIdentifier
+	PublicKey asn1.BitString
+}
+
+// MarshalPKIXPublicKey encodes a public key as a DER SubjectPublicKeyInfo
+func MarshalPKIXPublicKey(pub *PublicKey) ([]byte, error) {
+	point := marshalPoint(pub.X, pub.Y)
+	return asn1.Marshal(publicKeyInfo{
+		Algorithm: algorithmIdentifier{Algorithm: oidPublicKeyEC, Parameters: OIDNamedCurveP256Sm2},
+		PublicKey: asn1.BitString{Bytes: point, BitLength: len(point) * 8},
+	})
+}
+
+// ParsePKIXPublicKey parses a DER SubjectPublicKeyInfo of an SM2 public key
+func ParsePKIXPublicKey(der []byte) (*PublicKey, error) {
+	var info publicKeyInfo
+	rest, err := asn1.Unmarshal(der, &info)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing public key: %s", err)
+	}
+	if len(rest) != 0 {
+		return nil, errors.New("trailing data after public key")
+	}
+	return parsePublicKeyInfo(&info)
+}
+
+func parsePublicKeyInfo(info *publicKeyInfo) (*PublicKey, error) {
+	if !info.Algorithm.Algorithm.Equal(oidPublicKeyEC) || !info.Algorithm.Parameters.Equal(OIDNamedCurveP256Sm2) {
+		return nil, fmt.Errorf("not an SM2 public key: algorithm %s, curve %s", info.Algorithm.Algorithm, info.Algorithm.Parameters)
+	}
+	c := P256Sm2()
+	x, y := unmarshalPoint(c, info.PublicKey.RightAlign())
+	if x == nil {
+		return nil, errors.New("invalid SM2 public key point")
+	}
+	return &PublicKey{Curve: c, X: x, Y: y}, nil
+}
+
+func marshalPoint(x, y *big.Int) []byte {
+	return append(append([]byte{4}, padded(x, 32)...), padded(y, 32)...)
+}
+
+func unmarshalPoint(c elliptic.Curve, data []byte) (x, y *big.Int) {
+	if len(data) != 65 || data[0] != 4 {
+		return nil, nil
+	}
+	x = new(big.Int).SetBytes(data[1:33])
+	y = new(big.Int).SetBytes(data[33:])
+	p := c.Params().P
+	if x.Cmp(p) >= 0 || y.Cmp(p) >= 0 || !c.IsOnCurve(x, y) {
+		return nil, nil
+	}
+	return x, y
+}
+
+func padded(v *big.Int, size int) []byte {
+	b := v.Bytes()
+	if len(b) >= size {
+		return b
+	}
+	return append(make([]byte, size-len(b)), b...)
+}
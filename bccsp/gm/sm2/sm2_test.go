@@ -0,0 +1,95 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sm2
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func hexInt(s string) *big.Int {
+	i, ok := new(big.Int).SetString(s, 16)
+	if !ok {
+		panic("invalid hex " + s)
+	}
+	return i
+}
+
+func TestCurve(t *testing.T) {
+	c := P256Sm2()
+	params := c.Params()
+	assert.True(t, c.IsOnCurve(params.Gx, params.Gy))
+	// The base point has order n
+	x, y := c.ScalarBaseMult(params.N.Bytes())
+	assert.Equal(t, 0, x.Sign())
+	assert.Equal(t, 0, y.Sign())
+}
+
+func TestSignExample(t *testing.T) {
+	// The example of GM/T 0003.5-2012
+	priv := &PrivateKey{D: hexInt("3945208F7B2144B13F36E38AC6D39F95889393692860B51A42FB81EF4DF7C5B8")}
+	priv.Curve = P256Sm2()
+	priv.X, priv.Y = priv.Curve.ScalarBaseMult(priv.D.Bytes())
+	assert.Equal(t, hexInt("09F9DF311E5421A150DD7D161E4BC5C672179FAD1833FC076BB08FF356F35020"), priv.X)
+	assert.Equal(t, hexInt("CCEA490CE26775A52DC6EA718CC1AA600AED05FBF35E084A6632F6072DA9AD13"), priv.Y)
+
+	msg := []byte("message digest")
+	e, err := digest(&priv.PublicKey, DefaultUID, msg)
+	require.NoError(t, err)
+	r, s := sign(priv, e, hexInt("59276E27D506861A16680F3AD9C02DCCEF3CC1FA3CDBE4CE6D54B80DEAC1BC21"))
+	assert.Equal(t, hexInt("F5A03B0648D2C4630EEAC513E1BB81A15944DA3827D5B74143AC7EACEEE720B3"), r)
+	assert.Equal(t, hexInt("B1B6AA29DF212FD8763182BC0D421CA1BB9038FD1F7F42D4840B69C485BBC1AA"), s)
+	assert.True(t, Verify(&priv.PublicKey, DefaultUID, msg, r, s))
+}
+
+func TestSignVerify(t *testing.T) {
+	priv, err := GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	msg := []byte("hello")
+
+	sig, err := SignASN1(rand.Reader, priv, DefaultUID, msg)
+	require.NoError(t, err)
+	assert.True(t, VerifyASN1(priv.Public(), DefaultUID, msg, sig))
+
+	assert.False(t, VerifyASN1(priv.Public(), DefaultUID, []byte("hellO"), sig))
+	assert.False(t, VerifyASN1(priv.Public(), []byte("another signer"), msg, sig))
+	other, err := GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	assert.False(t, VerifyASN1(other.Public(), DefaultUID, msg, sig))
+	assert.False(t, VerifyASN1(priv.Public(), DefaultUID, msg, []byte("not a signature")))
+
+	r, s, err := Sign(rand.Reader, priv, DefaultUID, msg)
+	require.NoError(t, err)
+	assert.True(t, Verify(priv.Public(), DefaultUID, msg, r, s))
+	assert.False(t, Verify(priv.Public(), DefaultUID, msg, r, new(big.Int).Add(s, P256Sm2().Params().N)))
+	assert.False(t, Verify(priv.Public(), DefaultUID, msg, big.NewInt(0), s))
+}
+
+func TestPKIXPublicKey(t *testing.T) {
+	priv, err := GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	der, err := MarshalPKIXPublicKey(priv.Public())
+	require.NoError(t, err)
+	pub, err := ParsePKIXPublicKey(der)
+	require.NoError(t, err)
+	assert.Equal(t, priv.X, pub.X)
+	assert.Equal(t, priv.Y, pub.Y)
+
+	// Points off the curve are rejected
+	der, err = MarshalPKIXPublicKey(&PublicKey{Curve: P256Sm2(), X: priv.X, Y: new(big.Int).Add(priv.Y, one)})
+	require.NoError(t, err)
+	_, err = ParsePKIXPublicKey(der)
+	assert.Error(t, err)
+
+	_, err = ParsePKIXPublicKey([]byte("garbage"))
+	assert.Error(t, err)
+}
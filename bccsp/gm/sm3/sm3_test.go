@@ -0,0 +1,46 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sm3
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSum(t *testing.T) {
+	// The examples of GB/T 32905-2016
+	sum := Sum([]byte("abc"))
+	assert.Equal(t, "66c7f0f462eeedd9d1f2d46bdc10e4e24167c4875cf2f7a2297da02b8f4ba8e0", hex.EncodeToString(sum[:]))
+	sum = Sum([]byte(strings.Repeat("abcd", 16)))
+	assert.Equal(t, "debe9ff92275b8a138604889c18e5a4d6fdb70e5387e5765293dcba39c0c5732", hex.EncodeToString(sum[:]))
+}
+
+func TestHash(t *testing.T) {
+	msg := []byte(strings.Repeat("The quick brown fox jumps over the lazy dog", 10))
+	expected := Sum(msg)
+
+	// Writes of any size give the same digest, which Sum does not finalize
+	h := New()
+	for i := 0; i < len(msg); i += 7 {
+		end := i + 7
+		if end > len(msg) {
+			end = len(msg)
+		}
+		h.Write(msg[i:end])
+	}
+	assert.Equal(t, expected[:], h.Sum(nil))
+	assert.Equal(t, expected[:], h.Sum(nil))
+
+	h.Reset()
+	h.Write(msg)
+	assert.Equal(t, expected[:], h.Sum(nil))
+	assert.Equal(t, Size, h.Size())
+	assert.Equal(t, BlockSize, h.BlockSize())
+}
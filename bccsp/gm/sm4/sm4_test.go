@@ -0,0 +1,44 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sm4
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCipher(t *testing.T) {
+	// The example of GB/T 32907-2016
+	key, _ := hex.DecodeString("0123456789abcdeffedcba9876543210")
+	plaintext := key
+	c, err := NewCipher(key)
+	require.NoError(t, err)
+	assert.Equal(t, BlockSize, c.BlockSize())
+
+	ciphertext := make([]byte, BlockSize)
+	c.Encrypt(ciphertext, plaintext)
+	assert.Equal(t, "681edf34d206965e86b3e94f536e4246", hex.EncodeToString(ciphertext))
+
+	decrypted := make([]byte, BlockSize)
+	c.Decrypt(decrypted, ciphertext)
+	assert.Equal(t, plaintext, decrypted)
+
+	// The second example encrypts the plaintext 1000000 times
+	block := append([]byte{}, plaintext...)
+	for i := 0; i < 1000000; i++ {
+		c.Encrypt(block, block)
+	}
+	assert.Equal(t, "595298c7c6fd271f0402f804c33d3f66", hex.EncodeToString(block))
+}
+
+func TestInvalidKey(t *testing.T) {
+	_, err := NewCipher(make([]byte, 32))
+	assert.Error(t, err)
+}
@@ -60,6 +60,8 @@ const (
 	HMAC = "HMAC"
 	// HMACTruncated256 HMAC truncated at 256 bits.
 	HMACTruncated256 = "HMAC_TRUNCATED_256"
+	// HKDF HMAC-based extract-and-expand key derivation function (RFC 5869).
+	HKDF = "HKDF"
 
 	// SHA Secure Hash Algorithm using default family.
 	// Each BCCSP may or may not support default security level. If not supported than
@@ -191,6 +193,14 @@ func (opts *AESKeyGenOpts) Ephemeral() bool {
 // with PKCS7 padding.
 type AESCBCPKCS7ModeOpts struct{}
 
+// AESGCMModeOpts contains options for AES authenticated encryption in GCM
+// mode. The random nonce is prepended to the ciphertext.
+type AESGCMModeOpts struct {
+	// AdditionalData is authenticated along with the ciphertext but not
+	// encrypted. The same additional data must be given to decrypt it.
+	AdditionalData []byte
+}
+
 // HMACTruncated256AESDeriveKeyOpts contains options for HMAC truncated
 // at 256 bits key derivation.
 type HMACTruncated256AESDeriveKeyOpts struct {
@@ -236,6 +246,25 @@ func (opts *HMACDeriveKeyOpts) Argument() []byte {
 	return opts.Arg
 }
 
+// HKDFDeriveKeyOpts contains options for HKDF key derivation, which derives
+// from an AES key another AES key of the same length, independent of the
+// keys derived with another info.
+type HKDFDeriveKeyOpts struct {
+	Temporary bool
+	Info      []byte
+}
+
+// Algorithm returns the key derivation algorithm identifier (to be used).
+func (opts *HKDFDeriveKeyOpts) Algorithm() string {
+	return HKDF
+}
+
+// Ephemeral returns true if the key to generate has to be ephemeral,
+// false otherwise.
+func (opts *HKDFDeriveKeyOpts) Ephemeral() bool {
+	return opts.Temporary
+}
+
 // AES256ImportKeyOpts contains options for importing AES 256 keys.
 type AES256ImportKeyOpts struct {
 	Temporary bool
@@ -135,13 +135,55 @@ func AESCBCPKCS7Decrypt(key, src []byte) ([]byte, error) {
 	return nil, err
 }
 
+// AESGCMEncrypt encrypts and authenticates src in GCM mode, prepending the random nonce to the ciphertext.
+// The additional data, if any, is authenticated along with src
+func AESGCMEncrypt(key, src, additionalData []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize(), gcm.NonceSize()+len(src)+gcm.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, src, additionalData), nil
+}
+
+// AESGCMDecrypt authenticates and decrypts src, encrypted in GCM mode with the nonce prepended, along
+// with the additional data it was encrypted with
+func AESGCMDecrypt(key, src, additionalData []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(src) < gcm.NonceSize()+gcm.Overhead() {
+		return nil, errors.New("Invalid ciphertext. It is shorter than the nonce and the tag")
+	}
+	return gcm.Open(nil, src[:gcm.NonceSize()], src[gcm.NonceSize():], additionalData)
+}
+
 type aescbcpkcs7Encryptor struct{}
 
 func (*aescbcpkcs7Encryptor) Encrypt(k bccsp.Key, plaintext []byte, opts bccsp.EncrypterOpts) (ciphertext []byte, err error) {
-	switch opts.(type) {
+	switch o := opts.(type) {
 	case *bccsp.AESCBCPKCS7ModeOpts, bccsp.AESCBCPKCS7ModeOpts:
 		// AES in CBC mode with PKCS7 padding
 		return AESCBCPKCS7Encrypt(k.(*aesPrivateKey).privKey, plaintext)
+	case *bccsp.AESGCMModeOpts:
+		// AES in GCM mode
+		return AESGCMEncrypt(k.(*aesPrivateKey).privKey, plaintext, o.AdditionalData)
+	case bccsp.AESGCMModeOpts:
+		return AESGCMEncrypt(k.(*aesPrivateKey).privKey, plaintext, o.AdditionalData)
 	default:
 		return nil, fmt.Errorf("Mode not recognized [%s]", opts)
 	}
@@ -151,10 +193,15 @@ type aescbcpkcs7Decryptor struct{}
 
 func (*aescbcpkcs7Decryptor) Decrypt(k bccsp.Key, ciphertext []byte, opts bccsp.DecrypterOpts) (plaintext []byte, err error) {
 	// check for mode
-	switch opts.(type) {
+	switch o := opts.(type) {
 	case *bccsp.AESCBCPKCS7ModeOpts, bccsp.AESCBCPKCS7ModeOpts:
 		// AES in CBC mode with PKCS7 padding
 		return AESCBCPKCS7Decrypt(k.(*aesPrivateKey).privKey, ciphertext)
+	case *bccsp.AESGCMModeOpts:
+		// AES in GCM mode
+		return AESGCMDecrypt(k.(*aesPrivateKey).privKey, ciphertext, o.AdditionalData)
+	case bccsp.AESGCMModeOpts:
+		return AESGCMDecrypt(k.(*aesPrivateKey).privKey, ciphertext, o.AdditionalData)
 	default:
 		return nil, fmt.Errorf("Mode not recognized [%s]", opts)
 	}
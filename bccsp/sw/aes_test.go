@@ -538,3 +538,46 @@ func TestAESCBCPKCS7EncryptorDecrypt(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, msg, msg2)
 }
+
+// TestAESGCMEncryptorDecrypt tests the GCM mode of aescbcpkcs7Encryptor and
+// aescbcpkcs7Decryptor
+func TestAESGCMEncryptorDecrypt(t *testing.T) {
+	raw, err := GetRandomBytes(32)
+	assert.NoError(t, err)
+
+	k := &aesPrivateKey{privKey: raw, exportable: false}
+
+	msg := []byte("Hello World")
+	ct, err := (&aescbcpkcs7Encryptor{}).Encrypt(k, msg, &bccsp.AESGCMModeOpts{})
+	assert.NoError(t, err)
+	ct2, err := (&aescbcpkcs7Encryptor{}).Encrypt(k, msg, bccsp.AESGCMModeOpts{})
+	assert.NoError(t, err)
+	assert.NotEqual(t, ct, ct2, "Nonces should be random")
+
+	decryptor := &aescbcpkcs7Decryptor{}
+	msg2, err := decryptor.Decrypt(k, ct, &bccsp.AESGCMModeOpts{})
+	assert.NoError(t, err)
+	assert.Equal(t, msg, msg2)
+
+	ct[len(ct)-1] ^= 1
+	_, err = decryptor.Decrypt(k, ct, &bccsp.AESGCMModeOpts{})
+	assert.Error(t, err, "Should not decrypt a tampered ciphertext")
+
+	_, err = decryptor.Decrypt(k, ct[:10], &bccsp.AESGCMModeOpts{})
+	assert.Error(t, err)
+
+	ct, err = (&aescbcpkcs7Encryptor{}).Encrypt(k, msg, &bccsp.AESGCMModeOpts{AdditionalData: []byte("blockfile_000000:0")})
+	assert.NoError(t, err)
+	msg2, err = decryptor.Decrypt(k, ct, bccsp.AESGCMModeOpts{AdditionalData: []byte("blockfile_000000:0")})
+	assert.NoError(t, err)
+	assert.Equal(t, msg, msg2)
+	_, err = decryptor.Decrypt(k, ct, &bccsp.AESGCMModeOpts{AdditionalData: []byte("blockfile_000000:1")})
+	assert.Error(t, err, "Should not decrypt with other additional data")
+	_, err = decryptor.Decrypt(k, ct, &bccsp.AESGCMModeOpts{})
+	assert.Error(t, err, "Should not decrypt without the additional data")
+
+	_, err = AESGCMEncrypt([]byte{1, 2, 3}, msg, nil)
+	assert.Error(t, err)
+	_, err = AESGCMDecrypt([]byte{1, 2, 3}, ct, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,62 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sw
+
+import (
+	"reflect"
+	"runtime"
+	"sync"
+
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/common/errors"
+)
+
+// VerifyBatch implements bccsp.BatchVerifier by verifying the signatures of the requests one by
+// one, concurrently on as many workers as there are CPUs. This is not a batch verification
+// algorithm: each signature costs as much as with Verify, the requests are only spread on the
+// CPUs.
+func (csp *impl) VerifyBatch(requests []bccsp.VerifyRequest) (valid []bool, err error) {
+	// Validate arguments
+	for i, req := range requests {
+		if req.Key == nil {
+			return nil, errors.ErrorWithCallstack(errors.BCCSP, errors.BadRequest, "Invalid Key of request %d. It must not be nil.", i)
+		}
+		if _, found := csp.verifiers[reflect.TypeOf(req.Key)]; !found {
+			return nil, errors.ErrorWithCallstack(errors.BCCSP, errors.NotFound, "Unsupported 'VerifyKey' provided in request %d [%v]", i, req.Key)
+		}
+	}
+
+	valid = make([]bool, len(requests))
+	workers := runtime.NumCPU()
+	if workers > len(requests) {
+		workers = len(requests)
+	}
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				req := requests[i]
+				// A malformed signature or digest fails its verification only
+				ok, err := csp.Verify(req.Key, req.Signature, req.Digest, req.Opts)
+				if err != nil {
+					logger.Debugf("Failed verifying signature of request %d: %s", i, err)
+				}
+				valid[i] = ok && err == nil
+			}
+		}()
+	}
+	for i := range requests {
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+
+	return valid, nil
+}
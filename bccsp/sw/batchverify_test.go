@@ -0,0 +1,54 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sw
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/fabric/bccsp"
+	mocks2 "github.com/hyperledger/fabric/bccsp/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyBatch(t *testing.T) {
+	csp, err := New(256, "SHA2", NewDummyKeyStore())
+	require.NoError(t, err)
+	batchVerifier, ok := csp.(bccsp.BatchVerifier)
+	require.True(t, ok, "The software-based BCCSP should verify batches")
+
+	var requests []bccsp.VerifyRequest
+	for i := 0; i < 10; i++ {
+		k, err := csp.KeyGen(&bccsp.ECDSAP256KeyGenOpts{Temporary: true})
+		require.NoError(t, err)
+		pk, err := k.PublicKey()
+		require.NoError(t, err)
+		digest := sha256.Sum256([]byte(fmt.Sprintf("transaction %d", i)))
+		signature, err := csp.Sign(k, digest[:], nil)
+		require.NoError(t, err)
+		requests = append(requests, bccsp.VerifyRequest{Key: pk, Signature: signature, Digest: digest[:]})
+	}
+	// Signature of another digest, and malformed signature
+	requests[3].Signature = requests[4].Signature
+	requests[7].Signature = []byte("garbage")
+
+	valid, err := batchVerifier.VerifyBatch(requests)
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{true, true, true, false, true, true, true, false, true, true}, valid)
+
+	valid, err = batchVerifier.VerifyBatch(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, valid)
+
+	_, err = batchVerifier.VerifyBatch([]bccsp.VerifyRequest{requests[0], {Signature: requests[1].Signature, Digest: requests[1].Digest}})
+	assert.Error(t, err, "A request without key should fail the batch")
+
+	_, err = batchVerifier.VerifyBatch([]bccsp.VerifyRequest{{Key: &mocks2.MockKey{}, Signature: []byte{1}, Digest: []byte{1}}})
+	assert.Error(t, err, "A request with an unsupported key should fail the batch")
+}
@@ -1220,6 +1220,36 @@ func TestHMACKeyDerivOverAES256Key(t *testing.T) {
 	}
 }
 
+func TestHKDFKeyDerivOverAES256Key(t *testing.T) {
+
+	k, err := currentBCCSP.KeyGen(&bccsp.AESKeyGenOpts{Temporary: true})
+	if err != nil {
+		t.Fatalf("Failed generating AES_256 key [%s]", err)
+	}
+
+	derive := func(info string) []byte {
+		dk, err := currentBCCSP.KeyDeriv(k, &bccsp.HKDFDeriveKeyOpts{Temporary: true, Info: []byte(info)})
+		if err != nil {
+			t.Fatalf("Failed deriving AES_256 key [%s]", err)
+		}
+		if !dk.Private() || !dk.Symmetric() {
+			t.Fatal("Failed deriving AES_256 key. Derived key should be private and symmetric")
+		}
+		return dk.(*aesPrivateKey).privKey
+	}
+
+	encKey, macKey := derive("enc"), derive("mac")
+	if len(encKey) != 32 {
+		t.Fatalf("Failed deriving AES_256 key. Derived key should be 32 bytes long, not %d", len(encKey))
+	}
+	if !bytes.Equal(encKey, derive("enc")) {
+		t.Fatal("Failed deriving AES_256 key. The same info should derive the same key")
+	}
+	if bytes.Equal(encKey, macKey) || bytes.Equal(encKey, k.(*aesPrivateKey).privKey) {
+		t.Fatal("Failed deriving AES_256 key. Distinct infos should derive independent keys")
+	}
+}
+
 func TestAES256KeyImport(t *testing.T) {
 
 	raw, err := GetRandomBytes(32)
@@ -24,8 +24,10 @@ import (
 	"math/big"
 
 	"crypto/hmac"
+	"io"
 
 	"github.com/hyperledger/fabric/bccsp"
+	"golang.org/x/crypto/hkdf"
 )
 
 type ecdsaPublicKeyKeyDeriver struct{}
@@ -151,6 +153,15 @@ func (kd *aesPrivateKeyKeyDeriver) KeyDeriv(k bccsp.Key, opts bccsp.KeyDerivOpts
 		mac := hmac.New(kd.bccsp.conf.hashFunction, aesK.privKey)
 		mac.Write(hmacOpts.Argument())
 		return &aesPrivateKey{mac.Sum(nil), true}, nil
+
+	case *bccsp.HKDFDeriveKeyOpts:
+		hkdfOpts := opts.(*bccsp.HKDFDeriveKeyOpts)
+
+		derived := make([]byte, len(aesK.privKey))
+		if _, err := io.ReadFull(hkdf.New(kd.bccsp.conf.hashFunction, aesK.privKey, nil, hkdfOpts.Info), derived); err != nil {
+			return nil, fmt.Errorf("Failed deriving key with HKDF [%s]", err)
+		}
+		return &aesPrivateKey{derived, false}, nil
 	default:
 		return nil, fmt.Errorf("Unsupported 'KeyDerivOpts' provided [%v]", opts)
 	}
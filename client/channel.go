@@ -0,0 +1,213 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package client
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	cb "github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+// Request is the invocation of a chaincode
+type Request struct {
+	Chaincode string
+	Args      [][]byte
+	// Transient is the transient data of the proposal, passed to the chaincode but not
+	// recorded in the transaction
+	Transient map[string][]byte
+}
+
+// Result is the outcome of a transaction committed by the peer
+type Result struct {
+	TxID string
+	// Response is the response of the chaincode to the proposal
+	Response *pb.Response
+	// Endorsers are the endpoints of the peers which endorsed the proposal
+	Endorsers      []string
+	BlockNumber    uint64
+	ValidationCode pb.TxValidationCode
+}
+
+// ChannelClient submits and evaluates the transactions of a channel through a peer
+type ChannelClient struct {
+	channelID string
+	signer    Signer
+	gateway   pb.GatewayClient
+	endorser  pb.EndorserClient
+}
+
+// NewChannelClient creates the client of channel channelID, connected to a peer whose Gateway
+// service is enabled
+func NewChannelClient(conn *grpc.ClientConn, channelID string, signer Signer) *ChannelClient {
+	return &ChannelClient{
+		channelID: channelID,
+		signer:    signer,
+		gateway:   pb.NewGatewayClient(conn),
+		endorser:  pb.NewEndorserClient(conn),
+	}
+}
+
+// Submit submits a transaction and waits for its commit by the peer. The peer collects the
+// endorsements required by the endorsement policy of the chaincode. A transaction committed
+// but invalidated returns its result along with the error
+func (c *ChannelClient) Submit(ctx context.Context, req *Request) (*Result, error) {
+	prop, txID, err := c.createProposal(req)
+	if err != nil {
+		return nil, err
+	}
+	signedProp, err := signProposal(c.signer, prop)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := c.gateway.Submit(ctx)
+	if err != nil {
+		return nil, err
+	}
+	err = stream.Send(&pb.SubmitRequest{Type: &pb.SubmitRequest_Proposal{Proposal: signedProp}})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare transaction %s: %s", txID, err)
+	}
+	prepared := resp.GetPrepared()
+	if prepared == nil {
+		return nil, fmt.Errorf("expected the prepared transaction %s", txID)
+	}
+	if prepared.TxId != txID {
+		return nil, fmt.Errorf("prepared transaction %s instead of %s", prepared.TxId, txID)
+	}
+
+	// The peer assembled the transaction, which is only signed once checked to carry the
+	// proposal of the client
+	if err := checkPayload(prepared.Payload, txID, c.channelID); err != nil {
+		return nil, err
+	}
+	signature, err := c.signer.Sign(prepared.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed signing the transaction: %s", err)
+	}
+	err = stream.Send(&pb.SubmitRequest{Type: &pb.SubmitRequest_Signature{Signature: signature}})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{TxID: txID, Response: prepared.Response, Endorsers: prepared.Endorsers}
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return nil, fmt.Errorf("stream closed before the commit of transaction %s", txID)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to commit transaction %s: %s", txID, err)
+		}
+		status := resp.GetStatus()
+		if status == nil || status.Stage != pb.TransactionStatus_COMMITTED {
+			continue
+		}
+		result.BlockNumber = status.BlockNumber
+		result.ValidationCode = status.ValidationCode
+		if status.ValidationCode != pb.TxValidationCode_VALID {
+			return result, fmt.Errorf("transaction %s was invalidated with code %s", txID, status.ValidationCode)
+		}
+		return result, nil
+	}
+}
+
+// Evaluate has the peer simulate a transaction, and returns the response of the chaincode
+// without endorsing the transaction nor submitting it to the ordering service
+func (c *ChannelClient) Evaluate(ctx context.Context, req *Request) (*pb.Response, error) {
+	return c.evaluate(ctx, req, 0)
+}
+
+// EvaluateAt evaluates a transaction like Evaluate, but against the state of the channel as of
+// the given height of the ledger of the peer, i.e. after the commit of the blocks below the height.
+// The chaincode may only read the state and the history of keys, for audit queries such as the
+// balance of an account at a block
+func (c *ChannelClient) EvaluateAt(ctx context.Context, req *Request, height uint64) (*pb.Response, error) {
+	if height == 0 {
+		return nil, fmt.Errorf("the height must be at least 1")
+	}
+	return c.evaluate(ctx, req, height)
+}
+
+// evaluate evaluates a transaction against the current state, or the state as of height if it
+// is not 0
+func (c *ChannelClient) evaluate(ctx context.Context, req *Request, height uint64) (*pb.Response, error) {
+	prop, txID, err := c.createProposal(req)
+	if err != nil {
+		return nil, err
+	}
+	signedProp, err := signProposal(c.signer, prop)
+	if err != nil {
+		return nil, err
+	}
+	var resp *pb.ProposalResponse
+	if height == 0 {
+		resp, err = c.endorser.SimulateProposal(ctx, signedProp)
+	} else {
+		resp, err = c.endorser.SimulateProposalAt(ctx, &pb.HistoricalProposal{SignedProposal: signedProp, Height: height})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate transaction %s: %s", txID, err)
+	}
+	if resp.Response == nil {
+		return nil, fmt.Errorf("missing response to transaction %s", txID)
+	}
+	if resp.Response.Status >= shim.ERRORTHRESHOLD {
+		return nil, fmt.Errorf("transaction %s failed with status %d: %s", txID, resp.Response.Status, resp.Response.Message)
+	}
+	return resp.Response, nil
+}
+
+func (c *ChannelClient) createProposal(req *Request) (*pb.Proposal, string, error) {
+	if req.Chaincode == "" {
+		return nil, "", fmt.Errorf("missing chaincode name")
+	}
+	creator, err := c.signer.Serialize()
+	if err != nil {
+		return nil, "", err
+	}
+	cis := &pb.ChaincodeInvocationSpec{
+		ChaincodeSpec: &pb.ChaincodeSpec{
+			Type:        pb.ChaincodeSpec_GOLANG,
+			ChaincodeId: &pb.ChaincodeID{Name: req.Chaincode},
+			Input:       &pb.ChaincodeInput{Args: req.Args},
+		},
+	}
+	return utils.CreateChaincodeProposalWithTransient(cb.HeaderType_ENDORSER_TRANSACTION, c.channelID, cis, creator, req.Transient)
+}
+
+// checkPayload checks that the payload of a prepared transaction is the one of transaction txID
+// on channelID
+func checkPayload(payloadBytes []byte, txID, channelID string) error {
+	payload, err := utils.UnmarshalPayload(payloadBytes)
+	if err != nil {
+		return err
+	}
+	if payload.Header == nil {
+		return fmt.Errorf("missing header in the prepared transaction %s", txID)
+	}
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return err
+	}
+	if chdr.TxId != txID || chdr.ChannelId != channelID {
+		return fmt.Errorf("the prepared transaction %s of channel %s does not match transaction %s of channel %s", chdr.TxId, chdr.ChannelId, txID, channelID)
+	}
+	return nil
+}
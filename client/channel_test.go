@@ -0,0 +1,220 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package client
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+// mockGateway prepares the transactions with the header of their proposal, and commits them
+// with validationCode
+type mockGateway struct {
+	signer         *mockSigner
+	validationCode pb.TxValidationCode
+	// otherTxID, if set, replaces the ID of the prepared transactions
+	otherTxID string
+	signed    chan bool
+}
+
+func (g *mockGateway) Submit(stream pb.Gateway_SubmitServer) error {
+	req, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	signedProp := req.GetProposal()
+	if !g.signer.verify(signedProp.ProposalBytes, signedProp.Signature) {
+		return fmt.Errorf("invalid signature of the proposal")
+	}
+	prop, err := utils.GetProposal(signedProp.ProposalBytes)
+	if err != nil {
+		return err
+	}
+	hdr, err := utils.GetHeader(prop.Header)
+	if err != nil {
+		return err
+	}
+	chdr, err := utils.UnmarshalChannelHeader(hdr.ChannelHeader)
+	if err != nil {
+		return err
+	}
+	if g.otherTxID != "" {
+		chdr.TxId = g.otherTxID
+		hdr.ChannelHeader = utils.MarshalOrPanic(chdr)
+	}
+	payload := &cb.Payload{Header: hdr, Data: []byte("transaction")}
+	prepared := &pb.PreparedTransaction{
+		TxId:      chdr.TxId,
+		Response:  &pb.Response{Status: 200, Payload: []byte("result")},
+		Payload:   utils.MarshalOrPanic(payload),
+		Endorsers: []string{"peer0:7051", "peer1:7051"},
+	}
+	if err := stream.Send(&pb.SubmitResponse{Type: &pb.SubmitResponse_Prepared{Prepared: prepared}}); err != nil {
+		return err
+	}
+
+	req, err = stream.Recv()
+	if err != nil {
+		g.signed <- false
+		return nil
+	}
+	g.signed <- g.signer.verify(prepared.Payload, req.GetSignature())
+	for _, status := range []*pb.TransactionStatus{
+		{Stage: pb.TransactionStatus_SUBMITTED, TxId: chdr.TxId},
+		{Stage: pb.TransactionStatus_COMMITTED, TxId: chdr.TxId, BlockNumber: 7, ValidationCode: g.validationCode},
+	} {
+		if err := stream.Send(&pb.SubmitResponse{Type: &pb.SubmitResponse_Status{Status: status}}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *mockGateway) Prepare(context.Context, *pb.SignedProposal) (*pb.PreparedTransaction, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (g *mockGateway) Commit(*cb.Envelope, pb.Gateway_CommitServer) error {
+	return fmt.Errorf("not implemented")
+}
+
+// mockEndorser answers the proposals with process, recording the height of the last historical one
+type mockEndorser struct {
+	process func(*pb.ChaincodeSpec) *pb.Response
+	height  uint64
+}
+
+func (e *mockEndorser) SimulateProposalAt(ctx context.Context, historicalProp *pb.HistoricalProposal) (*pb.ProposalResponse, error) {
+	e.height = historicalProp.Height
+	return e.SimulateProposal(ctx, historicalProp.SignedProposal)
+}
+
+func (e *mockEndorser) SimulateProposal(ctx context.Context, signedProp *pb.SignedProposal) (*pb.ProposalResponse, error) {
+	resp, err := e.ProcessProposal(ctx, signedProp)
+	if err != nil {
+		return nil, err
+	}
+	resp.Endorsement = nil
+	return resp, nil
+}
+
+func (e *mockEndorser) ProcessProposal(ctx context.Context, signedProp *pb.SignedProposal) (*pb.ProposalResponse, error) {
+	prop, err := utils.GetProposal(signedProp.ProposalBytes)
+	if err != nil {
+		return nil, err
+	}
+	cis, err := utils.GetChaincodeInvocationSpec(prop)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ProposalResponse{
+		Response:    e.process(cis.ChaincodeSpec),
+		Payload:     []byte("results"),
+		Endorsement: &pb.Endorsement{Endorser: []byte("peer0"), Signature: []byte("signature")},
+	}, nil
+}
+
+func TestChannelClientSubmit(t *testing.T) {
+	signer := newMockSigner(t)
+	gateway := &mockGateway{signer: signer, signed: make(chan bool, 1)}
+	conn, stop := startServer(t, func(srv *grpc.Server) {
+		pb.RegisterGatewayServer(srv, gateway)
+	})
+	defer stop()
+	client := NewChannelClient(conn, "mychannel", signer)
+
+	result, err := client.Submit(context.Background(), &Request{Chaincode: "mycc", Args: [][]byte{[]byte("invoke")}})
+	require.NoError(t, err)
+	assert.True(t, <-gateway.signed, "Expected the transaction to be signed by the client")
+	assert.NotEmpty(t, result.TxID)
+	assert.Equal(t, []byte("result"), result.Response.Payload)
+	assert.Equal(t, []string{"peer0:7051", "peer1:7051"}, result.Endorsers)
+	assert.Equal(t, uint64(7), result.BlockNumber)
+	assert.Equal(t, pb.TxValidationCode_VALID, result.ValidationCode)
+
+	gateway.validationCode = pb.TxValidationCode_MVCC_READ_CONFLICT
+	result, err = client.Submit(context.Background(), &Request{Chaincode: "mycc"})
+	assert.Error(t, err, "Expected an error for an invalidated transaction")
+	<-gateway.signed
+	require.NotNil(t, result)
+	assert.Equal(t, pb.TxValidationCode_MVCC_READ_CONFLICT, result.ValidationCode)
+
+	_, err = client.Submit(context.Background(), &Request{})
+	assert.Error(t, err, "Expected an error without chaincode")
+}
+
+func TestChannelClientSubmitOtherTransaction(t *testing.T) {
+	signer := newMockSigner(t)
+	gateway := &mockGateway{signer: signer, signed: make(chan bool, 1), otherTxID: "other"}
+	conn, stop := startServer(t, func(srv *grpc.Server) {
+		pb.RegisterGatewayServer(srv, gateway)
+	})
+	defer stop()
+
+	_, err := NewChannelClient(conn, "mychannel", signer).Submit(context.Background(), &Request{Chaincode: "mycc"})
+	assert.Error(t, err)
+	assert.False(t, <-gateway.signed, "Expected the client not to sign another transaction")
+
+	assert.Error(t, checkPayload(utils.MarshalOrPanic(&cb.Payload{}), "tx", "mychannel"))
+	assert.Error(t, checkPayload([]byte("garbage"), "tx", "mychannel"))
+}
+
+func TestChannelClientEvaluate(t *testing.T) {
+	signer := newMockSigner(t)
+	endorser := &mockEndorser{process: func(spec *pb.ChaincodeSpec) *pb.Response {
+		if spec.ChaincodeId.Name != "mycc" {
+			return &pb.Response{Status: 500, Message: "chaincode not found"}
+		}
+		return &pb.Response{Status: 200, Payload: spec.Input.Args[0]}
+	}}
+	conn, stop := startServer(t, func(srv *grpc.Server) {
+		pb.RegisterEndorserServer(srv, endorser)
+	})
+	defer stop()
+	client := NewChannelClient(conn, "mychannel", signer)
+
+	resp, err := client.Evaluate(context.Background(), &Request{Chaincode: "mycc", Args: [][]byte{[]byte("query")}})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("query"), resp.Payload)
+
+	_, err = client.Evaluate(context.Background(), &Request{Chaincode: "othercc"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "chaincode not found")
+
+	resp, err = client.EvaluateAt(context.Background(), &Request{Chaincode: "mycc", Args: [][]byte{[]byte("balance")}}, 5)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("balance"), resp.Payload)
+	assert.Equal(t, uint64(5), endorser.height)
+
+	_, err = client.EvaluateAt(context.Background(), &Request{Chaincode: "mycc"}, 0)
+	assert.Error(t, err)
+}
+
+func TestCreateProposalTransient(t *testing.T) {
+	client := &ChannelClient{channelID: "mychannel", signer: newMockSigner(t)}
+	prop, txID, err := client.createProposal(&Request{Chaincode: "mycc", Transient: map[string][]byte{"key": []byte("secret")}})
+	require.NoError(t, err)
+	cpp, err := utils.GetChaincodeProposalPayload(prop.Payload)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("secret"), cpp.TransientMap["key"])
+
+	hdr, err := utils.GetHeader(prop.Header)
+	require.NoError(t, err)
+	chdr := &cb.ChannelHeader{}
+	require.NoError(t, proto.Unmarshal(hdr.ChannelHeader, chdr))
+	assert.Equal(t, txID, chdr.TxId)
+	assert.Equal(t, "mychannel", chdr.ChannelId)
+}
@@ -0,0 +1,177 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	ledgerutil "github.com/hyperledger/fabric/core/ledger/util"
+	cb "github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+)
+
+// ChaincodeEvents returns the chaincode events of the valid transactions of a block, in the
+// order of the transactions
+func ChaincodeEvents(block *cb.Block) []*pb.ChaincodeEvent {
+	if block.Header == nil || block.Data == nil {
+		return nil
+	}
+	var flags ledgerutil.TxValidationFlags
+	if block.Metadata != nil && len(block.Metadata.Metadata) > int(cb.BlockMetadataIndex_TRANSACTIONS_FILTER) {
+		flags = ledgerutil.TxValidationFlags(block.Metadata.Metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	}
+
+	var events []*pb.ChaincodeEvent
+	for i, data := range block.Data.Data {
+		if i >= len(flags) || !flags.IsValid(i) {
+			continue
+		}
+		event, err := chaincodeEvent(data)
+		if err != nil {
+			logger.Warningf("Skipping the event of transaction %d of block %d: %s", i, block.Header.Number, err)
+			continue
+		}
+		if event != nil {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// chaincodeEvent returns the chaincode event of an endorser transaction, if any
+func chaincodeEvent(envBytes []byte) (*pb.ChaincodeEvent, error) {
+	env, err := utils.GetEnvelopeFromBlock(envBytes)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := utils.GetPayload(env)
+	if err != nil {
+		return nil, err
+	}
+	if payload.Header == nil {
+		return nil, fmt.Errorf("missing header")
+	}
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return nil, err
+	}
+	if chdr.Type != int32(cb.HeaderType_ENDORSER_TRANSACTION) {
+		return nil, nil
+	}
+	tx, err := utils.GetTransaction(payload.Data)
+	if err != nil {
+		return nil, err
+	}
+	if len(tx.Actions) == 0 {
+		return nil, fmt.Errorf("missing transaction action")
+	}
+	ccActionPayload, err := utils.GetChaincodeActionPayload(tx.Actions[0].Payload)
+	if err != nil {
+		return nil, err
+	}
+	if ccActionPayload.Action == nil {
+		return nil, fmt.Errorf("missing chaincode action")
+	}
+	prp, err := utils.GetProposalResponsePayload(ccActionPayload.Action.ProposalResponsePayload)
+	if err != nil {
+		return nil, err
+	}
+	action, err := utils.GetChaincodeAction(prp.Extension)
+	if err != nil {
+		return nil, err
+	}
+	if len(action.Events) == 0 {
+		return nil, nil
+	}
+	event, err := utils.GetChaincodeEvents(action.Events)
+	if err != nil {
+		return nil, err
+	}
+	if event.EventName == "" {
+		return nil, nil
+	}
+	return event, nil
+}
+
+type chunkKey struct {
+	chaincodeID string
+	name        string
+	eventID     string
+}
+
+// EventAssembler reassembles the chaincode events split into chunks with shim.SetEventChunk.
+// The chunks of an event are kept until all of them are added, in any order
+type EventAssembler struct {
+	lock    sync.Mutex
+	pending map[chunkKey][][]byte
+}
+
+// NewEventAssembler creates an assembler without pending chunks
+func NewEventAssembler() *EventAssembler {
+	return &EventAssembler{pending: make(map[chunkKey][][]byte)}
+}
+
+// Add adds a chaincode event, and returns the complete event once available. An event which
+// is not chunked is returned as is. For a chunk, nil is returned until the last chunk of its
+// event is added, which returns the event named without the chunk prefix, whose payload is the
+// data of the chunks in order and whose transaction is the one of the last chunk added
+func (a *EventAssembler) Add(event *pb.ChaincodeEvent) (*pb.ChaincodeEvent, error) {
+	if !strings.HasPrefix(event.EventName, shim.ChunkedEventPrefix) {
+		return event, nil
+	}
+	chunk := &pb.ChaincodeEventChunk{}
+	if err := proto.Unmarshal(event.Payload, chunk); err != nil {
+		return nil, fmt.Errorf("malformed chunk of event %s: %s", event.EventName, err)
+	}
+	if chunk.Count == 0 || chunk.Index >= chunk.Count {
+		return nil, fmt.Errorf("chunk %d is out of the %d chunks of event %s", chunk.Index, chunk.Count, chunk.EventId)
+	}
+
+	name := strings.TrimPrefix(event.EventName, shim.ChunkedEventPrefix)
+	key := chunkKey{chaincodeID: event.ChaincodeId, name: name, eventID: chunk.EventId}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	chunks, ok := a.pending[key]
+	if !ok {
+		chunks = make([][]byte, chunk.Count)
+		a.pending[key] = chunks
+	}
+	if int(chunk.Count) != len(chunks) {
+		return nil, fmt.Errorf("chunk %d of event %s has a count of %d instead of %d", chunk.Index, chunk.EventId, chunk.Count, len(chunks))
+	}
+	if chunk.Data == nil {
+		chunk.Data = []byte{}
+	}
+	chunks[chunk.Index] = chunk.Data
+	for _, data := range chunks {
+		if data == nil {
+			return nil, nil
+		}
+	}
+
+	delete(a.pending, key)
+	return &pb.ChaincodeEvent{
+		ChaincodeId: event.ChaincodeId,
+		TxId:        event.TxId,
+		EventName:   name,
+		Payload:     bytes.Join(chunks, nil),
+	}, nil
+}
+
+// Pending returns the number of events whose chunks are partially added
+func (a *EventAssembler) Pending() int {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	return len(a.pending)
+}
@@ -0,0 +1,113 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package client
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	ledgerutil "github.com/hyperledger/fabric/core/ledger/util"
+	cb "github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// eventTx returns an endorser transaction carrying event
+func eventTx(event *pb.ChaincodeEvent) []byte {
+	action := &pb.ChaincodeAction{}
+	if event != nil {
+		action.Events = utils.MarshalOrPanic(event)
+	}
+	prp := &pb.ProposalResponsePayload{Extension: utils.MarshalOrPanic(action)}
+	ccActionPayload := &pb.ChaincodeActionPayload{Action: &pb.ChaincodeEndorsedAction{ProposalResponsePayload: utils.MarshalOrPanic(prp)}}
+	tx := &pb.Transaction{Actions: []*pb.TransactionAction{{Payload: utils.MarshalOrPanic(ccActionPayload)}}}
+	chdr := utils.MakeChannelHeader(cb.HeaderType_ENDORSER_TRANSACTION, 0, "mychannel", 0)
+	payload := &cb.Payload{Header: utils.MakePayloadHeader(chdr, &cb.SignatureHeader{}), Data: utils.MarshalOrPanic(tx)}
+	return utils.MarshalOrPanic(&cb.Envelope{Payload: utils.MarshalOrPanic(payload)})
+}
+
+func chunkEvents(t *testing.T, name, eventID string, payload []byte, size int) []*pb.ChaincodeEvent {
+	stub := &chunkStub{}
+	chunks, err := shim.SplitEventPayload(eventID, payload, size)
+	require.NoError(t, err)
+	for _, chunk := range chunks {
+		require.NoError(t, shim.SetEventChunk(stub, name, chunk))
+	}
+	return stub.events
+}
+
+// chunkStub records the events set by shim.SetEventChunk
+type chunkStub struct {
+	shim.ChaincodeStubInterface
+	events []*pb.ChaincodeEvent
+}
+
+func (s *chunkStub) SetEvent(name string, payload []byte) error {
+	s.events = append(s.events, &pb.ChaincodeEvent{ChaincodeId: "mycc", EventName: name, Payload: payload})
+	return nil
+}
+
+func TestChaincodeEvents(t *testing.T) {
+	block := cb.NewBlock(3, nil)
+	block.Data.Data = [][]byte{
+		eventTx(&pb.ChaincodeEvent{ChaincodeId: "mycc", EventName: "valid"}),
+		eventTx(&pb.ChaincodeEvent{ChaincodeId: "mycc", EventName: "invalid"}),
+		eventTx(nil),
+		[]byte("garbage"),
+		eventTx(&pb.ChaincodeEvent{ChaincodeId: "mycc", EventName: "unflagged"}),
+	}
+	flags := ledgerutil.NewTxValidationFlags(4)
+	flags.SetFlag(1, pb.TxValidationCode_MVCC_READ_CONFLICT)
+	block.Metadata.Metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER] = flags
+
+	events := ChaincodeEvents(block)
+	require.Len(t, events, 1)
+	assert.Equal(t, "valid", events[0].EventName)
+}
+
+func TestEventAssembler(t *testing.T) {
+	a := NewEventAssembler()
+	plain := &pb.ChaincodeEvent{ChaincodeId: "mycc", EventName: "plain", Payload: []byte("payload")}
+	event, err := a.Add(plain)
+	require.NoError(t, err)
+	assert.Equal(t, plain, event, "Expected the events which are not chunked as is")
+
+	chunks := chunkEvents(t, "report", "ev1", []byte("0123456789"), 4)
+	require.Len(t, chunks, 3)
+	others := chunkEvents(t, "report", "ev2", []byte("abcdef"), 4)
+	for _, chunk := range []*pb.ChaincodeEvent{chunks[2], others[0], chunks[0]} {
+		event, err = a.Add(chunk)
+		require.NoError(t, err)
+		assert.Nil(t, event)
+	}
+	assert.Equal(t, 2, a.Pending())
+
+	event, err = a.Add(chunks[1])
+	require.NoError(t, err)
+	require.NotNil(t, event)
+	assert.Equal(t, "report", event.EventName)
+	assert.Equal(t, "mycc", event.ChaincodeId)
+	assert.Equal(t, []byte("0123456789"), event.Payload)
+	assert.Equal(t, 1, a.Pending())
+
+	_, err = a.Add(&pb.ChaincodeEvent{EventName: shim.ChunkedEventPrefix + "report", Payload: []byte("garbage")})
+	assert.Error(t, err)
+	mismatched := chunkEvents(t, "report", "ev2", []byte("abcdefghijkl"), 4)
+	_, err = a.Add(mismatched[2])
+	assert.Error(t, err, "Expected an error for a chunk whose count differs from the other chunks")
+}
+
+func TestEventAssemblerEmptyPayload(t *testing.T) {
+	chunks := chunkEvents(t, "empty", "ev1", nil, 4)
+	require.Len(t, chunks, 1)
+	event, err := NewEventAssembler().Add(chunks[0])
+	require.NoError(t, err)
+	require.NotNil(t, event)
+	assert.Empty(t, event.Payload)
+}
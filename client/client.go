@@ -0,0 +1,136 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package client is the client library of the Go applications of the network:
+//   - ChannelClient submits transactions through the Gateway service of a peer, which collects
+//     the endorsements satisfying the endorsement policy of the chaincode, and waits for their
+//     commit, or evaluates them on the peer without ordering them
+//   - EventClient receives the blocks committed by a peer from its event hub, and notifies the
+//     commit of the transactions
+//   - LifecycleClient installs, instantiates and upgrades chaincodes, and creates and joins
+//     channels
+//
+// The clients sign their messages with the identity of the application, and never use the
+// local MSP of the process.
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/crypto/fips"
+	"github.com/hyperledger/fabric/core/comm"
+	"github.com/hyperledger/fabric/msp"
+	cb "github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	logging "github.com/op/go-logging"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+var logger = logging.MustGetLogger("client")
+
+// Signer is the identity of the application, which creates and signs its messages.
+// msp.SigningIdentity implements it
+type Signer interface {
+	// Serialize returns the serialized identity, the creator of the messages
+	Serialize() ([]byte, error)
+
+	// Sign signs a message
+	Sign(msg []byte) ([]byte, error)
+}
+
+// LoadSigner loads the signing identity of the MSP mspID from the directory mspDir, laid out as
+// the local MSP of a peer. The MSP is loaded apart from the local MSP of the process
+func LoadSigner(mspDir, mspID string) (msp.SigningIdentity, error) {
+	if mspID == "" {
+		return nil, fmt.Errorf("the MSP must have an ID")
+	}
+	conf, err := msp.GetLocalMspConfig(mspDir, nil, mspID)
+	if err != nil {
+		return nil, err
+	}
+	m, err := msp.NewBccspMsp()
+	if err != nil {
+		return nil, err
+	}
+	if err := m.Setup(conf); err != nil {
+		return nil, err
+	}
+	return m.GetDefaultSigningIdentity()
+}
+
+// ConnectionConfig configures the connections to the peers and the orderers
+type ConnectionConfig struct {
+	// RootCAs are the PEM encoded certificates of the TLS CAs of the servers. TLS is disabled
+	// if there are none
+	RootCAs [][]byte
+
+	// ServerNameOverride overrides the host name of the servers verified against their TLS
+	// certificates
+	ServerNameOverride string
+}
+
+// Dial connects to the peer or the orderer at endpoint, blocking until the connection is up
+func Dial(endpoint string, conf ConnectionConfig) (*grpc.ClientConn, error) {
+	if len(conf.RootCAs) == 0 {
+		return comm.NewClientConnectionWithAddress(endpoint, true, false, nil)
+	}
+
+	pool := x509.NewCertPool()
+	for _, ca := range conf.RootCAs {
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("invalid TLS root CA certificate")
+		}
+	}
+	creds := credentials.NewTLS(fips.ConfigureTLS(&tls.Config{
+		RootCAs:    pool,
+		ServerName: conf.ServerNameOverride,
+	}))
+	return comm.NewClientConnectionWithAddress(endpoint, true, true, creds)
+}
+
+// signProposal signs a proposal with the identity of the client
+func signProposal(signer Signer, prop *pb.Proposal) (*pb.SignedProposal, error) {
+	propBytes, err := utils.GetBytesProposal(prop)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := signer.Sign(propBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed signing the proposal: %s", err)
+	}
+	return &pb.SignedProposal{ProposalBytes: propBytes, Signature: signature}, nil
+}
+
+// signPayload signs the marshaled payload of a transaction with the identity of the client
+func signPayload(signer Signer, payloadBytes []byte) (*cb.Envelope, error) {
+	signature, err := signer.Sign(payloadBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed signing the transaction: %s", err)
+	}
+	return &cb.Envelope{Payload: payloadBytes, Signature: signature}, nil
+}
+
+// createSignedEnvelope creates an envelope of type txType on a channel, signed by the client
+func createSignedEnvelope(signer Signer, txType cb.HeaderType, channelID string, dataMsg proto.Message) (*cb.Envelope, error) {
+	creator, err := signer.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	payload, err := utils.CreateUnsignedEnvelope(txType, channelID, creator, dataMsg, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	payloadBytes, err := utils.GetBytesPayload(payload)
+	if err != nil {
+		return nil, err
+	}
+	return signPayload(signer, payloadBytes)
+}
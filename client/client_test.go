@@ -0,0 +1,88 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package client
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/asn1"
+	"math/big"
+	"net"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	mspprotos "github.com/hyperledger/fabric/protos/msp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+type mockSigner struct {
+	key *ecdsa.PrivateKey
+}
+
+func newMockSigner(t *testing.T) *mockSigner {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return &mockSigner{key: key}
+}
+
+func (s *mockSigner) Serialize() ([]byte, error) {
+	return proto.Marshal(&mspprotos.SerializedIdentity{Mspid: "Org1MSP", IdBytes: []byte("client")})
+}
+
+func (s *mockSigner) Sign(msg []byte) ([]byte, error) {
+	digest := sha256.Sum256(msg)
+	r, sig, err := ecdsa.Sign(rand.Reader, s.key, digest[:])
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(struct{ R, S *big.Int }{r, sig})
+}
+
+// verify checks that the client signed msg
+func (s *mockSigner) verify(msg, signature []byte) bool {
+	sig := struct{ R, S *big.Int }{}
+	if _, err := asn1.Unmarshal(signature, &sig); err != nil {
+		return false
+	}
+	digest := sha256.Sum256(msg)
+	return ecdsa.Verify(&s.key.PublicKey, digest[:], sig.R, sig.S)
+}
+
+// startServer serves the services registered by register on a local port, and returns the
+// connection of a client to it
+func startServer(t *testing.T, register func(*grpc.Server)) (*grpc.ClientConn, func()) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv := grpc.NewServer()
+	register(srv)
+	go srv.Serve(lis)
+
+	conn, err := Dial(lis.Addr().String(), ConnectionConfig{})
+	require.NoError(t, err)
+	return conn, func() {
+		conn.Close()
+		srv.Stop()
+	}
+}
+
+func TestSignProposal(t *testing.T) {
+	signer := newMockSigner(t)
+	prop, _, err := (&ChannelClient{channelID: "mychannel", signer: signer}).createProposal(&Request{Chaincode: "mycc"})
+	require.NoError(t, err)
+	signedProp, err := signProposal(signer, prop)
+	require.NoError(t, err)
+	assert.True(t, signer.verify(signedProp.ProposalBytes, signedProp.Signature))
+}
+
+func TestDialInvalidRootCA(t *testing.T) {
+	_, err := Dial("127.0.0.1:0", ConnectionConfig{RootCAs: [][]byte{[]byte("not a certificate")}})
+	assert.Error(t, err)
+}
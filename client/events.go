@@ -0,0 +1,240 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package client
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	ledgerutil "github.com/hyperledger/fabric/core/ledger/util"
+	cb "github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+// TxStatus is the status of a transaction committed by the peer
+type TxStatus struct {
+	TxID           string
+	BlockNumber    uint64
+	ValidationCode pb.TxValidationCode
+}
+
+type txKey struct {
+	channelID string
+	txID      string
+}
+
+// EventClient receives the blocks committed by a peer from its event hub
+type EventClient struct {
+	signer Signer
+	events pb.EventsClient
+
+	lock     sync.Mutex
+	started  bool
+	done     chan struct{}
+	err      error
+	handlers []func(*cb.Block)
+	watchers map[txKey][]chan *TxStatus
+}
+
+// NewEventClient creates the client of the event hub of a peer
+func NewEventClient(conn *grpc.ClientConn, signer Signer) *EventClient {
+	return &EventClient{
+		signer:   signer,
+		events:   pb.NewEventsClient(conn),
+		done:     make(chan struct{}),
+		watchers: make(map[txKey][]chan *TxStatus),
+	}
+}
+
+// Start registers for the block events of the peer, and then receives them until ctx is done
+// or the stream of events fails
+func (c *EventClient) Start(ctx context.Context) error {
+	c.lock.Lock()
+	if c.started {
+		c.lock.Unlock()
+		return fmt.Errorf("event client already started")
+	}
+	c.started = true
+	c.lock.Unlock()
+
+	stream, err := c.events.Chat(ctx)
+	if err != nil {
+		return c.stop(err)
+	}
+	creator, err := c.signer.Serialize()
+	if err != nil {
+		return c.stop(err)
+	}
+	register := &pb.Event{
+		Event: &pb.Event_Register{
+			Register: &pb.Register{Events: []*pb.Interest{{EventType: pb.EventType_BLOCK}}},
+		},
+		Creator: creator,
+	}
+	eventBytes, err := proto.Marshal(register)
+	if err != nil {
+		return c.stop(err)
+	}
+	signature, err := c.signer.Sign(eventBytes)
+	if err != nil {
+		return c.stop(fmt.Errorf("failed signing the registration: %s", err))
+	}
+	if err := stream.Send(&pb.SignedEvent{EventBytes: eventBytes, Signature: signature}); err != nil {
+		return c.stop(err)
+	}
+
+	// The event hub acknowledges the registration with the same event
+	ack, err := stream.Recv()
+	if err != nil {
+		return c.stop(fmt.Errorf("failed to register for the block events: %s", err))
+	}
+	if ack.GetRegister() == nil {
+		return c.stop(fmt.Errorf("invalid acknowledgement of the registration %T", ack.Event))
+	}
+
+	go func() {
+		for {
+			evt, err := stream.Recv()
+			if err != nil {
+				c.stop(err)
+				return
+			}
+			if block := evt.GetBlock(); block != nil {
+				c.dispatch(block)
+			}
+		}
+	}()
+	return nil
+}
+
+// stop records the error which ended the stream of events, and returns it
+func (c *EventClient) stop(err error) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	logger.Infof("Stream of events stopped: %s", err)
+	c.err = err
+	close(c.done)
+	return err
+}
+
+// Done returns a channel closed once the stream of events ends, which Err then tells why
+func (c *EventClient) Done() <-chan struct{} {
+	return c.done
+}
+
+// Err returns the error which ended the stream of events, nil while it is up
+func (c *EventClient) Err() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.err
+}
+
+// RegisterBlockHandler registers a handler called with each block received. The handlers are
+// called in turn as the blocks are received, and must not block
+func (c *EventClient) RegisterBlockHandler(handler func(*cb.Block)) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.handlers = append(c.handlers, handler)
+}
+
+// WatchTx returns a channel receiving the status of transaction txID of channel channelID once
+// committed, and a function to stop watching it. The commits of the blocks received before
+// the call are not notified, hence a transaction must be watched before being submitted
+func (c *EventClient) WatchTx(channelID, txID string) (<-chan *TxStatus, func()) {
+	key := txKey{channelID: channelID, txID: txID}
+	status := make(chan *TxStatus, 1)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.watchers[key] = append(c.watchers[key], status)
+
+	return status, func() {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+		watchers := c.watchers[key]
+		for i, w := range watchers {
+			if w == status {
+				watchers = append(watchers[:i], watchers[i+1:]...)
+				break
+			}
+		}
+		if len(watchers) == 0 {
+			delete(c.watchers, key)
+		} else {
+			c.watchers[key] = watchers
+		}
+	}
+}
+
+// WaitForCommit waits for the status of a transaction watched with WatchTx
+func (c *EventClient) WaitForCommit(ctx context.Context, status <-chan *TxStatus) (*TxStatus, error) {
+	select {
+	case s := <-status:
+		return s, nil
+	case <-c.done:
+		return nil, fmt.Errorf("stream of events stopped: %s", c.Err())
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// dispatch passes a block to the handlers, and notifies the commit of the transactions watched
+func (c *EventClient) dispatch(block *cb.Block) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, handler := range c.handlers {
+		handler(block)
+	}
+	if len(c.watchers) == 0 || block.Header == nil || block.Data == nil {
+		return
+	}
+
+	var flags ledgerutil.TxValidationFlags
+	if block.Metadata != nil && len(block.Metadata.Metadata) > int(cb.BlockMetadataIndex_TRANSACTIONS_FILTER) {
+		flags = ledgerutil.TxValidationFlags(block.Metadata.Metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	}
+	for i, data := range block.Data.Data {
+		chdr, err := channelHeader(data)
+		if err != nil {
+			logger.Warningf("Skipping transaction %d of block %d: %s", i, block.Header.Number, err)
+			continue
+		}
+		key := txKey{channelID: chdr.ChannelId, txID: chdr.TxId}
+		watchers := c.watchers[key]
+		if len(watchers) == 0 {
+			continue
+		}
+		status := &TxStatus{TxID: chdr.TxId, BlockNumber: block.Header.Number, ValidationCode: pb.TxValidationCode_INVALID_OTHER_REASON}
+		if i < len(flags) {
+			status.ValidationCode = flags.Flag(i)
+		}
+		for _, w := range watchers {
+			w <- status
+		}
+		delete(c.watchers, key)
+	}
+}
+
+func channelHeader(envBytes []byte) (*cb.ChannelHeader, error) {
+	env, err := utils.GetEnvelopeFromBlock(envBytes)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := utils.GetPayload(env)
+	if err != nil {
+		return nil, err
+	}
+	if payload.Header == nil {
+		return nil, fmt.Errorf("missing header")
+	}
+	return utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+}
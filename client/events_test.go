@@ -0,0 +1,140 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package client
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	ledgerutil "github.com/hyperledger/fabric/core/ledger/util"
+	cb "github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+// mockEventHub acknowledges the registration of the client, and then streams the events sent
+// to events until it is closed
+type mockEventHub struct {
+	signer *mockSigner
+	events chan *pb.Event
+}
+
+func (h *mockEventHub) Chat(stream pb.Events_ChatServer) error {
+	signedEvt, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	if !h.signer.verify(signedEvt.EventBytes, signedEvt.Signature) {
+		return fmt.Errorf("invalid signature of the registration")
+	}
+	evt := &pb.Event{}
+	if err := proto.Unmarshal(signedEvt.EventBytes, evt); err != nil {
+		return err
+	}
+	interests := evt.GetRegister().GetEvents()
+	if len(interests) != 1 || interests[0].EventType != pb.EventType_BLOCK {
+		return fmt.Errorf("expected the registration for the block events")
+	}
+	if err := stream.Send(evt); err != nil {
+		return err
+	}
+
+	for evt := range h.events {
+		if err := stream.Send(evt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// blockEvent returns the event of block number of channel channelID, holding the transactions
+// txIDs validated with codes
+func blockEvent(number uint64, channelID string, txIDs []string, codes []pb.TxValidationCode) *pb.Event {
+	block := cb.NewBlock(number, nil)
+	flags := ledgerutil.NewTxValidationFlags(len(txIDs))
+	for i, txID := range txIDs {
+		chdr := utils.MakeChannelHeader(cb.HeaderType_ENDORSER_TRANSACTION, 0, channelID, 0)
+		chdr.TxId = txID
+		payload := &cb.Payload{Header: utils.MakePayloadHeader(chdr, &cb.SignatureHeader{})}
+		env := &cb.Envelope{Payload: utils.MarshalOrPanic(payload)}
+		block.Data.Data = append(block.Data.Data, utils.MarshalOrPanic(env))
+		flags.SetFlag(i, codes[i])
+	}
+	block.Metadata.Metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER] = flags
+	return &pb.Event{Event: &pb.Event_Block{Block: block}}
+}
+
+func TestEventClient(t *testing.T) {
+	signer := newMockSigner(t)
+	hub := &mockEventHub{signer: signer, events: make(chan *pb.Event)}
+	conn, stop := startServer(t, func(srv *grpc.Server) {
+		pb.RegisterEventsServer(srv, hub)
+	})
+	defer stop()
+
+	client := NewEventClient(conn, signer)
+	blocks := make(chan uint64, 10)
+	client.RegisterBlockHandler(func(block *cb.Block) {
+		blocks <- block.Header.Number
+	})
+	require.NoError(t, client.Start(context.Background()))
+	assert.Error(t, client.Start(context.Background()), "Expected an error when started twice")
+
+	watch1, stop1 := client.WatchTx("mychannel", "tx1")
+	defer stop1()
+	watch2, stop2 := client.WatchTx("mychannel", "tx2")
+	defer stop2()
+	_, stopOther := client.WatchTx("otherchannel", "tx1")
+	stopOther()
+
+	hub.events <- blockEvent(5, "otherchannel", []string{"tx1"}, []pb.TxValidationCode{pb.TxValidationCode_VALID})
+	hub.events <- blockEvent(6, "mychannel", []string{"tx0", "tx1", "tx2"},
+		[]pb.TxValidationCode{pb.TxValidationCode_VALID, pb.TxValidationCode_VALID, pb.TxValidationCode_MVCC_READ_CONFLICT})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	status, err := client.WaitForCommit(ctx, watch1)
+	require.NoError(t, err)
+	assert.Equal(t, &TxStatus{TxID: "tx1", BlockNumber: 6, ValidationCode: pb.TxValidationCode_VALID}, status)
+	status, err = client.WaitForCommit(ctx, watch2)
+	require.NoError(t, err)
+	assert.Equal(t, &TxStatus{TxID: "tx2", BlockNumber: 6, ValidationCode: pb.TxValidationCode_MVCC_READ_CONFLICT}, status)
+	assert.Equal(t, uint64(5), <-blocks)
+	assert.Equal(t, uint64(6), <-blocks)
+
+	watch3, stop3 := client.WatchTx("mychannel", "tx3")
+	defer stop3()
+	close(hub.events)
+	select {
+	case <-client.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the stream of events to stop")
+	}
+	assert.Error(t, client.Err())
+	_, err = client.WaitForCommit(ctx, watch3)
+	assert.Error(t, err, "Expected an error once the stream of events stopped")
+}
+
+func TestEventClientRegistrationFailure(t *testing.T) {
+	// The event hub rejects the registrations signed by another identity
+	hub := &mockEventHub{signer: newMockSigner(t), events: make(chan *pb.Event)}
+	conn, stop := startServer(t, func(srv *grpc.Server) {
+		pb.RegisterEventsServer(srv, hub)
+	})
+	defer stop()
+
+	client := NewEventClient(conn, newMockSigner(t))
+	assert.Error(t, client.Start(context.Background()))
+	assert.Error(t, client.Err())
+	<-client.Done()
+}
@@ -0,0 +1,281 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package client
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/hyperledger/fabric/core/scc/cscc"
+	"github.com/hyperledger/fabric/core/scc/lscc"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+// genesisBlockRetryInterval is the interval between the requests of the genesis block of a
+// channel being created
+const genesisBlockRetryInterval = 200 * time.Millisecond
+
+// LifecycleClient manages the chaincodes of a peer and the channels it joins
+type LifecycleClient struct {
+	signer   Signer
+	endorser pb.EndorserClient
+	orderer  ab.AtomicBroadcastClient
+	events   *EventClient
+}
+
+// NewLifecycleClient creates the lifecycle client of a peer. The connection to the ordering
+// service is only required to instantiate and upgrade chaincodes and to create channels, and
+// the event client of the peer, if not nil, to wait for the commit of the instantiations and
+// of the upgrades
+func NewLifecycleClient(peer, orderer *grpc.ClientConn, signer Signer, events *EventClient) *LifecycleClient {
+	c := &LifecycleClient{
+		signer:   signer,
+		endorser: pb.NewEndorserClient(peer),
+		events:   events,
+	}
+	if orderer != nil {
+		c.orderer = ab.NewAtomicBroadcastClient(orderer)
+	}
+	return c
+}
+
+// Install installs a chaincode on the peer
+func (c *LifecycleClient) Install(ctx context.Context, cds *pb.ChaincodeDeploymentSpec) error {
+	creator, err := c.signer.Serialize()
+	if err != nil {
+		return err
+	}
+	prop, _, err := utils.CreateInstallProposalFromCDS(cds, creator)
+	if err != nil {
+		return err
+	}
+	_, err = c.endorse(ctx, prop)
+	return err
+}
+
+// Instantiate instantiates a chaincode installed on the peer on channelID, with the given
+// endorsement policy, and returns the ID of the transaction
+func (c *LifecycleClient) Instantiate(ctx context.Context, channelID string, cds *pb.ChaincodeDeploymentSpec, policy *cb.SignaturePolicyEnvelope) (string, error) {
+	return c.deploy(ctx, channelID, cds, policy, utils.CreateDeployProposalFromCDS)
+}
+
+// Upgrade upgrades a chaincode instantiated on channelID to the version of cds, installed on
+// the peer, and returns the ID of the transaction
+func (c *LifecycleClient) Upgrade(ctx context.Context, channelID string, cds *pb.ChaincodeDeploymentSpec, policy *cb.SignaturePolicyEnvelope) (string, error) {
+	return c.deploy(ctx, channelID, cds, policy, utils.CreateUpgradeProposalFromCDS)
+}
+
+type deployProposalFunc func(chainID string, cds *pb.ChaincodeDeploymentSpec, creator []byte, policy []byte, escc []byte, vscc []byte) (*pb.Proposal, string, error)
+
+// deploy has the peer endorse the deployment of a chaincode, and submits it to the ordering
+// service
+func (c *LifecycleClient) deploy(ctx context.Context, channelID string, cds *pb.ChaincodeDeploymentSpec, policy *cb.SignaturePolicyEnvelope, createProposal deployProposalFunc) (string, error) {
+	if c.orderer == nil {
+		return "", fmt.Errorf("not connected to the ordering service")
+	}
+	creator, err := c.signer.Serialize()
+	if err != nil {
+		return "", err
+	}
+	policyBytes, err := proto.Marshal(policy)
+	if err != nil {
+		return "", err
+	}
+	prop, txID, err := createProposal(channelID, cds, creator, policyBytes, []byte("escc"), []byte("vscc"))
+	if err != nil {
+		return "", err
+	}
+	resp, err := c.endorse(ctx, prop)
+	if err != nil {
+		return "", err
+	}
+	payload, err := utils.CreateUnsignedTx(prop, resp)
+	if err != nil {
+		return "", err
+	}
+	payloadBytes, err := utils.GetBytesPayload(payload)
+	if err != nil {
+		return "", err
+	}
+	env, err := signPayload(c.signer, payloadBytes)
+	if err != nil {
+		return "", err
+	}
+
+	if c.events == nil {
+		return txID, broadcast(ctx, c.orderer, env)
+	}
+	watch, stopWatching := c.events.WatchTx(channelID, txID)
+	defer stopWatching()
+	if err := broadcast(ctx, c.orderer, env); err != nil {
+		return "", err
+	}
+	status, err := c.events.WaitForCommit(ctx, watch)
+	if err != nil {
+		return txID, fmt.Errorf("failed to wait for the commit of transaction %s: %s", txID, err)
+	}
+	if status.ValidationCode != pb.TxValidationCode_VALID {
+		return txID, fmt.Errorf("transaction %s was invalidated with code %s", txID, status.ValidationCode)
+	}
+	return txID, nil
+}
+
+// InstalledChaincodes returns the chaincodes installed on the peer
+func (c *LifecycleClient) InstalledChaincodes(ctx context.Context) ([]*pb.ChaincodeInfo, error) {
+	return c.queryChaincodes(ctx, "", lscc.GETINSTALLEDCHAINCODES)
+}
+
+// InstantiatedChaincodes returns the chaincodes instantiated on channelID
+func (c *LifecycleClient) InstantiatedChaincodes(ctx context.Context, channelID string) ([]*pb.ChaincodeInfo, error) {
+	return c.queryChaincodes(ctx, channelID, lscc.GETCHAINCODES)
+}
+
+func (c *LifecycleClient) queryChaincodes(ctx context.Context, channelID, function string) ([]*pb.ChaincodeInfo, error) {
+	resp, err := c.invokeSystemChaincode(ctx, cb.HeaderType_ENDORSER_TRANSACTION, channelID, "lscc", []byte(function))
+	if err != nil {
+		return nil, err
+	}
+	cqr := &pb.ChaincodeQueryResponse{}
+	if err := proto.Unmarshal(resp.Response.Payload, cqr); err != nil {
+		return nil, fmt.Errorf("invalid list of chaincodes: %s", err)
+	}
+	return cqr.Chaincodes, nil
+}
+
+// CreateChannel signs and submits the transaction creating a channel, as output by configtxgen,
+// and returns the genesis block of the channel once created
+func (c *LifecycleClient) CreateChannel(ctx context.Context, configTx *cb.Envelope) (*cb.Block, error) {
+	if c.orderer == nil {
+		return nil, fmt.Errorf("not connected to the ordering service")
+	}
+	payload, err := utils.ExtractPayload(configTx)
+	if err != nil {
+		return nil, err
+	}
+	if payload.Header == nil {
+		return nil, fmt.Errorf("missing header in the channel creation transaction")
+	}
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return nil, err
+	}
+	if cb.HeaderType(chdr.Type) != cb.HeaderType_CONFIG_UPDATE || chdr.ChannelId == "" {
+		return nil, fmt.Errorf("the transaction does not update the config of a channel")
+	}
+	configUpdateEnv := &cb.ConfigUpdateEnvelope{}
+	if err := proto.Unmarshal(payload.Data, configUpdateEnv); err != nil {
+		return nil, fmt.Errorf("invalid config update: %s", err)
+	}
+
+	creator, err := c.signer.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	configSig, err := utils.CreateUnsignedConfigSignature(creator)
+	if err != nil {
+		return nil, err
+	}
+	configSig.Signature, err = c.signer.Sign(utils.ConfigSignatureBytes(configSig, configUpdateEnv.ConfigUpdate))
+	if err != nil {
+		return nil, fmt.Errorf("failed signing the config update: %s", err)
+	}
+	configUpdateEnv.Signatures = append(configUpdateEnv.Signatures, configSig)
+
+	env, err := createSignedEnvelope(c.signer, cb.HeaderType_CONFIG_UPDATE, chdr.ChannelId, configUpdateEnv)
+	if err != nil {
+		return nil, err
+	}
+	if err := broadcast(ctx, c.orderer, env); err != nil {
+		return nil, fmt.Errorf("failed to create channel %s: %s", chdr.ChannelId, err)
+	}
+
+	// The ordering service creates the channel asynchronously
+	for {
+		block, err := fetchBlock(ctx, c.orderer, c.signer, chdr.ChannelId, 0)
+		if err == nil {
+			return block, nil
+		}
+		logger.Debugf("Genesis block of channel %s not available yet: %s", chdr.ChannelId, err)
+		select {
+		case <-time.After(genesisBlockRetryInterval):
+		case <-ctx.Done():
+			return nil, fmt.Errorf("failed to fetch the genesis block of channel %s: %s", chdr.ChannelId, err)
+		}
+	}
+}
+
+// JoinChannel joins the peer to the channel of a genesis block
+func (c *LifecycleClient) JoinChannel(ctx context.Context, genesisBlock *cb.Block) error {
+	blockBytes, err := proto.Marshal(genesisBlock)
+	if err != nil {
+		return err
+	}
+	_, err = c.invokeSystemChaincode(ctx, cb.HeaderType_CONFIG, "", "cscc", []byte(cscc.JoinChain), blockBytes)
+	return err
+}
+
+// Channels returns the channels the peer has joined
+func (c *LifecycleClient) Channels(ctx context.Context) ([]string, error) {
+	resp, err := c.invokeSystemChaincode(ctx, cb.HeaderType_ENDORSER_TRANSACTION, "", "cscc", []byte(cscc.GetChannels))
+	if err != nil {
+		return nil, err
+	}
+	cqr := &pb.ChannelQueryResponse{}
+	if err := proto.Unmarshal(resp.Response.Payload, cqr); err != nil {
+		return nil, fmt.Errorf("invalid list of channels: %s", err)
+	}
+	channels := make([]string, len(cqr.Channels))
+	for i, ch := range cqr.Channels {
+		channels[i] = ch.ChannelId
+	}
+	return channels, nil
+}
+
+func (c *LifecycleClient) invokeSystemChaincode(ctx context.Context, typ cb.HeaderType, channelID, ccName string, args ...[]byte) (*pb.ProposalResponse, error) {
+	creator, err := c.signer.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	cis := &pb.ChaincodeInvocationSpec{
+		ChaincodeSpec: &pb.ChaincodeSpec{
+			Type:        pb.ChaincodeSpec_GOLANG,
+			ChaincodeId: &pb.ChaincodeID{Name: ccName},
+			Input:       &pb.ChaincodeInput{Args: args},
+		},
+	}
+	prop, _, err := utils.CreateProposalFromCIS(typ, channelID, cis, creator)
+	if err != nil {
+		return nil, err
+	}
+	return c.endorse(ctx, prop)
+}
+
+// endorse has the peer endorse a proposal, and checks the response of the chaincode
+func (c *LifecycleClient) endorse(ctx context.Context, prop *pb.Proposal) (*pb.ProposalResponse, error) {
+	signedProp, err := signProposal(c.signer, prop)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.endorser.ProcessProposal(ctx, signedProp)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Response == nil {
+		return nil, fmt.Errorf("missing response to the proposal")
+	}
+	if resp.Response.Status >= shim.ERRORTHRESHOLD {
+		return nil, fmt.Errorf("proposal failed with status %d: %s", resp.Response.Status, resp.Response.Message)
+	}
+	return resp, nil
+}
@@ -0,0 +1,226 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package client
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/core/scc/cscc"
+	"github.com/hyperledger/fabric/core/scc/lscc"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+// mockOrderer accepts the envelopes signed by the client, and delivers the blocks requested
+// once unavailable answered the requests with NOT_FOUND
+type mockOrderer struct {
+	signer      *mockSigner
+	status      cb.Status
+	onBroadcast func(*cb.Envelope)
+
+	lock        sync.Mutex
+	unavailable int
+	seeks       int
+}
+
+func (o *mockOrderer) Broadcast(stream ab.AtomicBroadcast_BroadcastServer) error {
+	for {
+		env, err := stream.Recv()
+		if err != nil {
+			return nil
+		}
+		if !o.signer.verify(env.Payload, env.Signature) {
+			return fmt.Errorf("invalid signature of the envelope")
+		}
+		if o.onBroadcast != nil {
+			o.onBroadcast(env)
+		}
+		if err := stream.Send(&ab.BroadcastResponse{Status: o.status}); err != nil {
+			return err
+		}
+	}
+}
+
+func (o *mockOrderer) Deliver(stream ab.AtomicBroadcast_DeliverServer) error {
+	env, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	if !o.signer.verify(env.Payload, env.Signature) {
+		return fmt.Errorf("invalid signature of the envelope")
+	}
+	payload, err := utils.UnmarshalPayload(env.Payload)
+	if err != nil {
+		return err
+	}
+	seekInfo := &ab.SeekInfo{}
+	if err := proto.Unmarshal(payload.Data, seekInfo); err != nil {
+		return err
+	}
+
+	o.lock.Lock()
+	o.seeks++
+	unavailable := o.seeks <= o.unavailable
+	o.lock.Unlock()
+	if unavailable {
+		return stream.Send(&ab.DeliverResponse{Type: &ab.DeliverResponse_Status{Status: cb.Status_NOT_FOUND}})
+	}
+	block := cb.NewBlock(seekInfo.Start.GetSpecified().Number, nil)
+	return stream.Send(&ab.DeliverResponse{Type: &ab.DeliverResponse_Block{Block: block}})
+}
+
+func newLifecycleEndorser(joined *[]byte) *mockEndorser {
+	return &mockEndorser{process: func(spec *pb.ChaincodeSpec) *pb.Response {
+		args := spec.Input.Args
+		switch {
+		case spec.ChaincodeId.Name == "cscc" && string(args[0]) == cscc.GetChannels:
+			cqr := &pb.ChannelQueryResponse{Channels: []*pb.ChannelInfo{{ChannelId: "ch1"}, {ChannelId: "ch2"}}}
+			return &pb.Response{Status: 200, Payload: utils.MarshalOrPanic(cqr)}
+		case spec.ChaincodeId.Name == "cscc" && string(args[0]) == cscc.JoinChain:
+			*joined = args[1]
+			return &pb.Response{Status: 200}
+		case spec.ChaincodeId.Name == "lscc" && string(args[0]) == lscc.GETINSTALLEDCHAINCODES:
+			cqr := &pb.ChaincodeQueryResponse{Chaincodes: []*pb.ChaincodeInfo{{Name: "mycc", Version: "1.0"}}}
+			return &pb.Response{Status: 200, Payload: utils.MarshalOrPanic(cqr)}
+		case spec.ChaincodeId.Name == "lscc" && string(args[0]) == lscc.INSTALL:
+			return &pb.Response{Status: 200}
+		case spec.ChaincodeId.Name == "lscc" && string(args[0]) == lscc.DEPLOY:
+			return &pb.Response{Status: 200}
+		}
+		return &pb.Response{Status: 500, Message: "unexpected proposal"}
+	}}
+}
+
+func TestLifecycleClientInstantiate(t *testing.T) {
+	signer := newMockSigner(t)
+	hub := &mockEventHub{signer: signer, events: make(chan *pb.Event, 1)}
+	var joined []byte
+	peer, stopPeer := startServer(t, func(srv *grpc.Server) {
+		pb.RegisterEndorserServer(srv, newLifecycleEndorser(&joined))
+		pb.RegisterEventsServer(srv, hub)
+	})
+	defer stopPeer()
+	defer close(hub.events)
+
+	code := pb.TxValidationCode_VALID
+	orderer, stopOrderer := startServer(t, func(srv *grpc.Server) {
+		ab.RegisterAtomicBroadcastServer(srv, &mockOrderer{signer: signer, status: cb.Status_SUCCESS, onBroadcast: func(env *cb.Envelope) {
+			chdr, err := channelHeader(utils.MarshalOrPanic(env))
+			require.NoError(t, err)
+			hub.events <- blockEvent(3, chdr.ChannelId, []string{chdr.TxId}, []pb.TxValidationCode{code})
+		}})
+	})
+	defer stopOrderer()
+
+	events := NewEventClient(peer, signer)
+	require.NoError(t, events.Start(context.Background()))
+	client := NewLifecycleClient(peer, orderer, signer, events)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	cds := &pb.ChaincodeDeploymentSpec{ChaincodeSpec: &pb.ChaincodeSpec{
+		Type:        pb.ChaincodeSpec_GOLANG,
+		ChaincodeId: &pb.ChaincodeID{Name: "mycc", Version: "1.0"},
+		Input:       &pb.ChaincodeInput{},
+	}}
+	require.NoError(t, client.Install(ctx, cds))
+	installed, err := client.InstalledChaincodes(ctx)
+	require.NoError(t, err)
+	require.Len(t, installed, 1)
+	assert.Equal(t, "mycc", installed[0].Name)
+
+	txID, err := client.Instantiate(ctx, "mychannel", cds, cauthdsl.SignedByMspMember("Org1MSP"))
+	require.NoError(t, err)
+	assert.NotEmpty(t, txID)
+
+	code = pb.TxValidationCode_ENDORSEMENT_POLICY_FAILURE
+	_, err = client.Instantiate(ctx, "mychannel", cds, cauthdsl.SignedByMspMember("Org1MSP"))
+	assert.Error(t, err, "Expected an error for an invalidated instantiation")
+
+	_, err = client.Upgrade(ctx, "mychannel", cds, cauthdsl.SignedByMspMember("Org1MSP"))
+	assert.Error(t, err, "Expected an error for a proposal failing")
+}
+
+func TestLifecycleClientChannels(t *testing.T) {
+	signer := newMockSigner(t)
+	var joined []byte
+	peer, stopPeer := startServer(t, func(srv *grpc.Server) {
+		pb.RegisterEndorserServer(srv, newLifecycleEndorser(&joined))
+	})
+	defer stopPeer()
+	orderer, stopOrderer := startServer(t, func(srv *grpc.Server) {
+		ab.RegisterAtomicBroadcastServer(srv, &mockOrderer{signer: signer, status: cb.Status_SUCCESS, unavailable: 2})
+	})
+	defer stopOrderer()
+	client := NewLifecycleClient(peer, orderer, signer, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	channels, err := client.Channels(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ch1", "ch2"}, channels)
+
+	configUpdateEnv := &cb.ConfigUpdateEnvelope{ConfigUpdate: []byte("config update")}
+	configTx, err := createSignedEnvelope(signer, cb.HeaderType_CONFIG_UPDATE, "newchannel", configUpdateEnv)
+	require.NoError(t, err)
+	block, err := client.CreateChannel(ctx, configTx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), block.Header.Number)
+
+	require.NoError(t, client.JoinChannel(ctx, block))
+	assert.Equal(t, utils.MarshalOrPanic(block), joined)
+
+	notConfig, err := createSignedEnvelope(signer, cb.HeaderType_ENDORSER_TRANSACTION, "newchannel", configUpdateEnv)
+	require.NoError(t, err)
+	_, err = client.CreateChannel(ctx, notConfig)
+	assert.Error(t, err, "Expected an error for a transaction which is not a config update")
+
+	noOrderer := NewLifecycleClient(peer, nil, signer, nil)
+	_, err = noOrderer.CreateChannel(ctx, configTx)
+	assert.Error(t, err, "Expected an error without ordering service")
+}
+
+func TestCreateChannelTimeout(t *testing.T) {
+	signer := newMockSigner(t)
+	orderer, stop := startServer(t, func(srv *grpc.Server) {
+		ab.RegisterAtomicBroadcastServer(srv, &mockOrderer{signer: signer, status: cb.Status_SUCCESS, unavailable: 1000})
+	})
+	defer stop()
+	client := NewLifecycleClient(orderer, orderer, signer, nil)
+
+	configTx, err := createSignedEnvelope(signer, cb.HeaderType_CONFIG_UPDATE, "newchannel", &cb.ConfigUpdateEnvelope{})
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	_, err = client.CreateChannel(ctx, configTx)
+	assert.Error(t, err)
+}
+
+func TestBroadcastRejected(t *testing.T) {
+	signer := newMockSigner(t)
+	orderer, stop := startServer(t, func(srv *grpc.Server) {
+		ab.RegisterAtomicBroadcastServer(srv, &mockOrderer{signer: signer, status: cb.Status_BAD_REQUEST})
+	})
+	defer stop()
+
+	env, err := createSignedEnvelope(signer, cb.HeaderType_CONFIG_UPDATE, "mychannel", &cb.ConfigUpdateEnvelope{})
+	require.NoError(t, err)
+	err = broadcast(context.Background(), ab.NewAtomicBroadcastClient(orderer), env)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "BAD_REQUEST")
+}
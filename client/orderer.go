@@ -0,0 +1,76 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package client
+
+import (
+	"fmt"
+
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"golang.org/x/net/context"
+)
+
+// broadcast sends an envelope to the ordering service and waits for its acknowledgement
+func broadcast(ctx context.Context, orderer ab.AtomicBroadcastClient, env *cb.Envelope) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := orderer.Broadcast(ctx)
+	if err != nil {
+		return err
+	}
+	if err := stream.Send(env); err != nil {
+		return err
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	if resp.Status != cb.Status_SUCCESS {
+		if resp.Error != nil {
+			return fmt.Errorf("the ordering service rejected the transaction with status %s: %s", resp.Status, resp.Error.Message)
+		}
+		return fmt.Errorf("the ordering service rejected the transaction with status %s", resp.Status)
+	}
+	return nil
+}
+
+// fetchBlock fetches block number of a channel from the ordering service, waiting for the
+// block to be cut if it does not exist yet
+func fetchBlock(ctx context.Context, orderer ab.AtomicBroadcastClient, signer Signer, channelID string, number uint64) (*cb.Block, error) {
+	position := &ab.SeekPosition{Type: &ab.SeekPosition_Specified{Specified: &ab.SeekSpecified{Number: number}}}
+	seekInfo := &ab.SeekInfo{
+		Start:    position,
+		Stop:     position,
+		Behavior: ab.SeekInfo_BLOCK_UNTIL_READY,
+	}
+	env, err := createSignedEnvelope(signer, cb.HeaderType_DELIVER_SEEK_INFO, channelID, seekInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := orderer.Deliver(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := stream.Send(env); err != nil {
+		return nil, err
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+	switch t := resp.Type.(type) {
+	case *ab.DeliverResponse_Block:
+		return t.Block, nil
+	case *ab.DeliverResponse_Status:
+		return nil, fmt.Errorf("failed to fetch block %d of channel %s: status %s", number, channelID, t.Status)
+	default:
+		return nil, fmt.Errorf("unexpected response %T to the request of block %d of channel %s", t, number, channelID)
+	}
+}
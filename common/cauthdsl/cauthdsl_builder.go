@@ -113,6 +113,25 @@ func SignedByMspAdmin(mspId string) *cb.SignaturePolicyEnvelope {
 	return p
 }
 
+// SignedByMspOU creates a SignaturePolicyEnvelope requiring 1 signature
+// from any member of the specified MSP whose certificate carries the
+// specified organizational unit, certified by any of the CAs the MSP
+// recognizes for that organizational unit
+func SignedByMspOU(mspId, ou string) *cb.SignaturePolicyEnvelope {
+	principal := &msp.MSPPrincipal{
+		PrincipalClassification: msp.MSPPrincipal_ORGANIZATION_UNIT,
+		Principal:               utils.MarshalOrPanic(&msp.OrganizationUnit{MspIdentifier: mspId, OrganizationalUnitIdentifier: ou})}
+
+	// create the policy: it requires exactly 1 signature from the first (and only) principal
+	p := &cb.SignaturePolicyEnvelope{
+		Version:    0,
+		Rule:       NOutOf(1, []*cb.SignaturePolicy{SignedBy(0)}),
+		Identities: []*msp.MSPPrincipal{principal},
+	}
+
+	return p
+}
+
 //wrapper for generating "any of a given role" type policies
 func signedByAnyOfGivenRole(role msp.MSPRole_MSPRoleType, ids []string) *cb.SignaturePolicyEnvelope {
 	// we create an array of principals, one principal
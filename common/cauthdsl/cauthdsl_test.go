@@ -10,6 +10,7 @@ import (
 	"bytes"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/hyperledger/fabric/msp"
 	cb "github.com/hyperledger/fabric/protos/common"
@@ -54,6 +55,10 @@ func (id *mockIdentity) GetOrganizationalUnits() []*msp.OUIdentifier {
 	return nil
 }
 
+func (id *mockIdentity) ExpiresAt() time.Time {
+	return time.Time{}
+}
+
 func (id *mockIdentity) Verify(msg []byte, sig []byte) error {
 	if bytes.Compare(sig, invalidSignature) == 0 {
 		return errors.New("Invalid signature")
@@ -0,0 +1,94 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cauthdsl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/msp"
+	cb "github.com/hyperledger/fabric/protos/common"
+	mb "github.com/hyperledger/fabric/protos/msp"
+)
+
+// explain evaluates the rule as the function compiled from it does, and returns the decision tree of the
+// evaluation, remember to call deduplicate on identities before passing them to this function
+func explain(rule *cb.SignaturePolicy, identities []*mb.MSPPrincipal, deserializer msp.IdentityDeserializer, signedData []*cb.SignedData, used []bool) *cb.PolicyDecision {
+	switch t := rule.Type.(type) {
+	case *cb.SignaturePolicy_NOutOf_:
+		decision := &cb.PolicyDecision{Rule: fmt.Sprintf("OutOf(%d)", t.NOutOf.N)}
+		verified := int32(0)
+		_used := make([]bool, len(used))
+		for _, subRule := range t.NOutOf.Rules {
+			copy(_used, used)
+			subDecision := explain(subRule, identities, deserializer, signedData, _used)
+			if subDecision.Satisfied {
+				verified++
+				copy(used, _used)
+			}
+			decision.SubDecisions = append(decision.SubDecisions, subDecision)
+		}
+		decision.Satisfied = verified >= t.NOutOf.N
+		if !decision.Satisfied {
+			decision.Error = fmt.Sprintf("%d of %d rules satisfied, %d required", verified, len(t.NOutOf.Rules), t.NOutOf.N)
+		}
+		return decision
+	case *cb.SignaturePolicy_SignedBy:
+		principal := identities[t.SignedBy]
+		decision := &cb.PolicyDecision{Rule: fmt.Sprintf("SignedBy(%s)", principalString(principal))}
+		for i, sd := range signedData {
+			check := &cb.IdentityCheck{Index: uint32(i)}
+			decision.IdentityChecks = append(decision.IdentityChecks, check)
+			if used[i] {
+				check.Error = "identity already used by another rule"
+				continue
+			}
+			identity, err := deserializer.DeserializeIdentity(sd.Identity)
+			if err != nil {
+				check.Error = fmt.Sprintf("principal deserialization failure: %s", err)
+				continue
+			}
+			check.MspId = identity.GetMSPIdentifier()
+			if err := identity.SatisfiesPrincipal(principal); err != nil {
+				check.Error = fmt.Sprintf("identity does not satisfy principal: %s", err)
+				continue
+			}
+			check.PrincipalMatched = true
+			if err := identity.Verify(sd.Data, sd.Signature); err != nil {
+				check.Error = fmt.Sprintf("signature is invalid: %s", err)
+				continue
+			}
+			check.SignatureValid = true
+			used[i] = true
+			decision.Satisfied = true
+			return decision
+		}
+		decision.Error = "no identity satisfies the principal with a valid signature"
+		return decision
+	default:
+		return &cb.PolicyDecision{Error: fmt.Sprintf("Unknown type: %T:%v", t, t)}
+	}
+}
+
+// principalString describes a principal as the policy parser expects it, such as Org1MSP.member, when
+// possible
+func principalString(principal *mb.MSPPrincipal) string {
+	switch principal.PrincipalClassification {
+	case mb.MSPPrincipal_ROLE:
+		role := &mb.MSPRole{}
+		if err := proto.Unmarshal(principal.Principal, role); err == nil {
+			return fmt.Sprintf("%s.%s", role.MspIdentifier, strings.ToLower(role.Role.String()))
+		}
+	case mb.MSPPrincipal_ORGANIZATION_UNIT:
+		ou := &mb.OrganizationUnit{}
+		if err := proto.Unmarshal(principal.Principal, ou); err == nil {
+			return fmt.Sprintf("%s.OU=%s", ou.MspIdentifier, ou.OrganizationalUnitIdentifier)
+		}
+	}
+	return principal.PrincipalClassification.String()
+}
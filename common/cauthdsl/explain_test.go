@@ -0,0 +1,58 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cauthdsl
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplain(t *testing.T) {
+	provider := NewPolicyProvider(&mockDeserializer{})
+	envelope := Envelope(And(SignedBy(0), SignedBy(1)), signers)
+	p, _, err := provider.NewPolicy(utils.MarshalOrPanic(envelope))
+	require.NoError(t, err)
+
+	signedData, _ := toSignedData(msgs, signers, [][]byte{validSignature, invalidSignature})
+	decision := policies.Explain(p, signedData)
+	assert.Equal(t, p.Evaluate(signedData) == nil, decision.Satisfied, "Should decide as Evaluate does")
+	assert.False(t, decision.Satisfied)
+	assert.Equal(t, "OutOf(2)", decision.Rule)
+	assert.Equal(t, "1 of 2 rules satisfied, 2 required", decision.Error)
+	require.Len(t, decision.SubDecisions, 2)
+
+	signedBy0 := decision.SubDecisions[0]
+	assert.True(t, signedBy0.Satisfied)
+	assert.Equal(t, "SignedBy(IDENTITY)", signedBy0.Rule)
+	require.Len(t, signedBy0.IdentityChecks, 1)
+	assert.True(t, signedBy0.IdentityChecks[0].PrincipalMatched)
+	assert.True(t, signedBy0.IdentityChecks[0].SignatureValid)
+	assert.Equal(t, "Mock", signedBy0.IdentityChecks[0].MspId)
+
+	signedBy1 := decision.SubDecisions[1]
+	assert.False(t, signedBy1.Satisfied)
+	require.Len(t, signedBy1.IdentityChecks, 2)
+	assert.Equal(t, "identity already used by another rule", signedBy1.IdentityChecks[0].Error)
+	assert.True(t, signedBy1.IdentityChecks[1].PrincipalMatched)
+	assert.False(t, signedBy1.IdentityChecks[1].SignatureValid)
+	assert.Equal(t, "signature is invalid: Invalid signature", signedBy1.IdentityChecks[1].Error)
+
+	signedData, _ = toSignedData(msgs, signers, [][]byte{validSignature, validSignature})
+	decision = policies.Explain(p, signedData)
+	assert.True(t, decision.Satisfied)
+	assert.Empty(t, decision.Error)
+}
+
+func TestPrincipalString(t *testing.T) {
+	assert.Equal(t, "Org1MSP.member", principalString(SignedByMspMember("Org1MSP").Identities[0]))
+	assert.Equal(t, "Org1MSP.admin", principalString(SignedByMspAdmin("Org1MSP").Identities[0]))
+	assert.Equal(t, "IDENTITY", principalString(Envelope(SignedBy(0), signers).Identities[0]))
+}
@@ -45,13 +45,17 @@ func (pr *provider) NewPolicy(data []byte) (policies.Policy, proto.Message, erro
 	}
 
 	return &policy{
-		evaluator: compiled,
+		evaluator:    compiled,
+		envelope:     sigPolicy,
+		deserializer: pr.deserializer,
 	}, sigPolicy, nil
 
 }
 
 type policy struct {
-	evaluator func([]*cb.SignedData, []bool) bool
+	evaluator    func([]*cb.SignedData, []bool) bool
+	envelope     *cb.SignaturePolicyEnvelope
+	deserializer msp.IdentityDeserializer
 }
 
 // Evaluate takes a set of SignedData and evaluates whether this set of signatures satisfies the policy
@@ -66,3 +70,13 @@ func (p *policy) Evaluate(signatureSet []*cb.SignedData) error {
 	}
 	return nil
 }
+
+// Explain evaluates the policy as Evaluate does, and returns the decision tree of the evaluation
+func (p *policy) Explain(signatureSet []*cb.SignedData) *cb.PolicyDecision {
+	if p == nil {
+		return &cb.PolicyDecision{Error: "No such policy"}
+	}
+
+	signedData := deduplicate(signatureSet)
+	return explain(p.envelope.Rule, p.envelope.Identities, p.deserializer, signedData, make([]bool, len(signedData)))
+}
@@ -28,7 +28,7 @@ import (
 	"github.com/hyperledger/fabric/protos/utils"
 )
 
-var regex *regexp.Regexp = regexp.MustCompile("^([[:alnum:]]+)([.])(member|admin)$")
+var regex *regexp.Regexp = regexp.MustCompile("^([[:alnum:]]+)([.])(member|admin|OU[(]([[:alnum:]._-]+)[)])$")
 var regexErr *regexp.Regexp = regexp.MustCompile("^No parameter '([^']+)' found[.]$")
 
 func and(args ...interface{}) (interface{}, error) {
@@ -131,26 +131,34 @@ func secondPass(args ...interface{}) (interface{}, error) {
 		switch t := principal.(type) {
 		/* if it's a string, we expect it to be formed as
 		   <MSP_ID> . <ROLE>, where MSP_ID is the MSP identifier
-		   and ROLE is either a member of an admin*/
+		   and ROLE is either a member of an admin, or as
+		   <MSP_ID> . OU(<OU>), where OU is an organizational unit
+		   of the MSP*/
 		case string:
 			/* split the string */
 			subm := regex.FindAllStringSubmatch(t, -1)
-			if subm == nil || len(subm) != 1 || len(subm[0]) != 4 {
+			if subm == nil || len(subm) != 1 || len(subm[0]) != 5 {
 				return nil, fmt.Errorf("Error parsing principal %s", t)
 			}
 
-			/* get the right role */
-			var r msp.MSPRole_MSPRoleType
-			if subm[0][3] == "member" {
-				r = msp.MSPRole_MEMBER
-			} else {
-				r = msp.MSPRole_ADMIN
-			}
-
 			/* build the principal we've been told */
-			p := &msp.MSPPrincipal{
-				PrincipalClassification: msp.MSPPrincipal_ROLE,
-				Principal:               utils.MarshalOrPanic(&msp.MSPRole{MspIdentifier: subm[0][1], Role: r})}
+			var p *msp.MSPPrincipal
+			switch subm[0][3] {
+			case "member":
+				p = &msp.MSPPrincipal{
+					PrincipalClassification: msp.MSPPrincipal_ROLE,
+					Principal:               utils.MarshalOrPanic(&msp.MSPRole{MspIdentifier: subm[0][1], Role: msp.MSPRole_MEMBER})}
+			case "admin":
+				p = &msp.MSPPrincipal{
+					PrincipalClassification: msp.MSPPrincipal_ROLE,
+					Principal:               utils.MarshalOrPanic(&msp.MSPRole{MspIdentifier: subm[0][1], Role: msp.MSPRole_ADMIN})}
+			default:
+				/* the identity may be certified for the OU by any
+				   of the CAs the MSP recognizes for it */
+				p = &msp.MSPPrincipal{
+					PrincipalClassification: msp.MSPPrincipal_ORGANIZATION_UNIT,
+					Principal:               utils.MarshalOrPanic(&msp.OrganizationUnit{MspIdentifier: subm[0][1], OrganizationalUnitIdentifier: subm[0][4]})}
+			}
 			ctx.principals = append(ctx.principals, p)
 
 			/* create a SignaturePolicy that requires a signature from
@@ -202,7 +210,9 @@ func newContext() *context {
 //
 // where
 //	- ORG is a string (representing the MSP identifier)
-//	- ROLE is either the string "member" or the string "admin" representing the required role
+//	- ROLE is either the string "member" or the string "admin" representing the required role,
+//	  or OU(UNIT) where UNIT is the identifier of an organizational unit of the MSP, such as
+//	  "department-a", which the certificate of the signer must carry
 func FromString(policy string) (*common.SignaturePolicyEnvelope, error) {
 	// first we translate the and/or business into outof gates
 	intermediate, err := govaluate.NewEvaluableExpressionWithFunctions(policy, map[string]govaluate.ExpressionFunction{"AND": and, "and": and, "OR": or, "or": or})
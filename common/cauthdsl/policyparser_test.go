@@ -130,9 +130,46 @@ func TestComplex2(t *testing.T) {
 	assert.True(t, reflect.DeepEqual(p1, p2))
 }
 
+func TestOrganizationalUnit(t *testing.T) {
+	p1, err := FromString("AND('A.OU(department-a)', OR('B.member', 'B.OU(dept_b.team1)'))")
+	assert.NoError(t, err)
+
+	principals := make([]*msp.MSPPrincipal, 0)
+
+	principals = append(principals, &msp.MSPPrincipal{
+		PrincipalClassification: msp.MSPPrincipal_ROLE,
+		Principal:               utils.MarshalOrPanic(&msp.MSPRole{Role: msp.MSPRole_MEMBER, MspIdentifier: "B"})})
+
+	principals = append(principals, &msp.MSPPrincipal{
+		PrincipalClassification: msp.MSPPrincipal_ORGANIZATION_UNIT,
+		Principal:               utils.MarshalOrPanic(&msp.OrganizationUnit{MspIdentifier: "B", OrganizationalUnitIdentifier: "dept_b.team1"})})
+
+	principals = append(principals, &msp.MSPPrincipal{
+		PrincipalClassification: msp.MSPPrincipal_ORGANIZATION_UNIT,
+		Principal:               utils.MarshalOrPanic(&msp.OrganizationUnit{MspIdentifier: "A", OrganizationalUnitIdentifier: "department-a"})})
+
+	p2 := &common.SignaturePolicyEnvelope{
+		Version:    0,
+		Rule:       And(SignedBy(2), Or(SignedBy(0), SignedBy(1))),
+		Identities: principals,
+	}
+
+	assert.True(t, reflect.DeepEqual(p1, p2))
+
+	p3, err := FromString("OR('A.OU(department-a)')")
+	assert.NoError(t, err)
+	assert.True(t, reflect.DeepEqual(SignedByMspOU("A", "department-a"), p3))
+}
+
 func TestBadStringsNoPanic(t *testing.T) {
 	_, err := FromString("OR('A.member', 'Bmember')")
 	assert.Error(t, err)
 	_, err = FromString("OR('A.member', Bmember)")
 	assert.Error(t, err)
+	_, err = FromString("OR('A.member', 'B.OU(depart ment)')")
+	assert.Error(t, err)
+	_, err = FromString("OR('A.member', 'B.department-a')")
+	assert.Error(t, err)
+	_, err = FromString("OR('A.member', 'B.peer')")
+	assert.Error(t, err)
 }
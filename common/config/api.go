@@ -47,6 +47,17 @@ type ApplicationOrg interface {
 type Application interface {
 	// Organizations returns a map of org ID to ApplicationOrg
 	Organizations() map[string]ApplicationOrg
+
+	// TxIDWindow returns the number of blocks within which a transaction ID must be
+	// unique, 0 if it must be unique in the whole history of the channel
+	TxIDWindow() uint64
+
+	// HasCapability returns whether the capability is enabled on the channel
+	HasCapability(capability string) bool
+
+	// RWSetLimits returns the caps on the size of the read set and of the write set
+	// of each transaction, 0 when unbounded
+	RWSetLimits() (maxReadSetBytes, maxWriteSetBytes uint64)
 }
 
 // Channel gives read only access to the channel configuration
@@ -61,6 +72,10 @@ type Channel interface {
 
 	// OrdererAddresses returns the list of valid orderer addresses to connect to to invoke Broadcast/Deliver
 	OrdererAddresses() []string
+
+	// ConsortiumName returns the name of the consortium this channel was created under, which is empty for the
+	// ordering system channel
+	ConsortiumName() string
 }
 
 // Consortiums represents the set of consortiums serviced by an ordering service
@@ -80,6 +95,9 @@ type Orderer interface {
 	// ConsensusType returns the configured consensus type
 	ConsensusType() string
 
+	// ConsensusState returns the configured state of the channel, normal or maintenance
+	ConsensusState() ab.ConsensusType_State
+
 	// BatchSize returns the maximum number of messages to include in a block
 	BatchSize() *ab.BatchSize
 
@@ -89,6 +107,10 @@ type Orderer interface {
 	// MaxChannelsCount returns the maximum count of channels to allow for an ordering network
 	MaxChannelsCount() uint64
 
+	// BlockSchedule returns the schedule of the blocks cut regardless of the batch
+	// size and timeout, whose MaxInterval is zero if the channel has none
+	BlockSchedule() BlockSchedule
+
 	// KafkaBrokers returns the addresses (IP:port notation) of a set of "bootstrap"
 	// Kafka brokers, i.e. this is not necessarily the entire set of Kafka brokers
 	// used for ordering
@@ -96,6 +118,9 @@ type Orderer interface {
 
 	// Organizations returns the organizations for the ordering service
 	Organizations() map[string]Org
+
+	// HasCapability returns whether the capability is enabled on the orderers of the channel
+	HasCapability(capability string) bool
 }
 
 type ValueProposer interface {
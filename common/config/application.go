@@ -20,13 +20,51 @@ import (
 	"fmt"
 
 	"github.com/hyperledger/fabric/common/config/msp"
+	pb "github.com/hyperledger/fabric/protos/peer"
 )
 
 const (
 	// ApplicationGroupKey is the group name for the Application config
 	ApplicationGroupKey = "Application"
+
+	// TxIDWindowKey is the key name for the TxIDWindow ConfigValue
+	TxIDWindowKey = "TxIDWindow"
+
+	// CapabilitiesKey is the key name for the Capabilities ConfigValue
+	CapabilitiesKey = "Capabilities"
+
+	// RWSetLimitsKey is the key name for the RWSetLimits ConfigValue
+	RWSetLimitsKey = "RWSetLimits"
+
+	// RangeQueryHashingCapability is the capability letting the endorsers summarize the
+	// results of the range queries of the transactions as merkle hashes in their read sets
+	RangeQueryHashingCapability = "RangeQueryHashing"
+
+	// InstantiationPolicyBindingCapability is the capability binding the default instantiation
+	// policy of a chaincode to the MSP of its instantiator, and checking the new instantiation
+	// policy of an upgrade on commit
+	InstantiationPolicyBindingCapability = "InstantiationPolicyBinding"
+
+	// TxIDWindowCapability is the capability enforcing the uniqueness of the transaction IDs
+	// within the TxIDWindow of the channel, and within each block whatever the window
+	TxIDWindowCapability = "TxIDWindow"
+
+	// RWSetLimitsCapability is the capability enforcing the RWSetLimits of the channel, beyond
+	// which the transactions are invalidated with the RWSET_SIZE_EXCEEDED validation code
+	RWSetLimitsCapability = "RWSetLimits"
+
+	// CustomTransactionsCapability is the capability letting the peers validate and commit the
+	// custom transactions with the processors registered for their header types
+	CustomTransactionsCapability = "CustomTransactions"
 )
 
+// ApplicationProtos is the set of config values of the application group
+type ApplicationProtos struct {
+	TxIDWindow   *pb.TxIDWindow
+	Capabilities *pb.Capabilities
+	RWSetLimits  *pb.RWSetLimits
+}
+
 // ApplicationGroup represents the application config group
 type ApplicationGroup struct {
 	*Proposer
@@ -36,6 +74,7 @@ type ApplicationGroup struct {
 
 type ApplicationConfig struct {
 	*standardValues
+	protos *ApplicationProtos
 
 	applicationGroup *ApplicationGroup
 	applicationOrgs  map[string]ApplicationOrg
@@ -61,17 +100,18 @@ func (ag *ApplicationGroup) Allocate() Values {
 }
 
 func NewApplicationConfig(ag *ApplicationGroup) *ApplicationConfig {
-	sv, err := NewStandardValues(&(struct{}{}))
+	ac := &ApplicationConfig{
+		applicationGroup: ag,
+		protos:           &ApplicationProtos{},
+	}
+
+	var err error
+	ac.standardValues, err = NewStandardValues(ac.protos)
 	if err != nil {
 		logger.Panicf("Programming error: %s", err)
 	}
 
-	return &ApplicationConfig{
-		applicationGroup: ag,
-
-		// Currently there are no config values
-		standardValues: sv,
-	}
+	return ac
 }
 
 func (ac *ApplicationConfig) Validate(tx interface{}, groups map[string]ValueProposer) error {
@@ -94,3 +134,21 @@ func (ac *ApplicationConfig) Commit() {
 func (ac *ApplicationConfig) Organizations() map[string]ApplicationOrg {
 	return ac.applicationOrgs
 }
+
+// TxIDWindow returns the number of blocks within which a transaction ID must be unique,
+// 0 if it must be unique in the whole history of the channel
+func (ac *ApplicationConfig) TxIDWindow() uint64 {
+	return ac.protos.TxIDWindow.GetBlocks()
+}
+
+// HasCapability returns whether the capability is enabled on the channel
+func (ac *ApplicationConfig) HasCapability(capability string) bool {
+	_, ok := ac.protos.Capabilities.GetCapabilities()[capability]
+	return ok
+}
+
+// RWSetLimits returns the caps on the size of the read set and of the write set of each
+// transaction, 0 when unbounded
+func (ac *ApplicationConfig) RWSetLimits() (maxReadSetBytes, maxWriteSetBytes uint64) {
+	return ac.protos.RWSetLimits.GetMaxReadSetBytes(), ac.protos.RWSetLimits.GetMaxWriteSetBytes()
+}
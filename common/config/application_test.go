@@ -19,7 +19,10 @@ package config
 import (
 	"testing"
 
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
 	logging "github.com/op/go-logging"
+	"github.com/stretchr/testify/assert"
 )
 
 func init() {
@@ -29,3 +32,34 @@ func init() {
 func TestApplicationInterface(t *testing.T) {
 	_ = Application((*ApplicationGroup)(nil))
 }
+
+func TestApplicationTxIDWindow(t *testing.T) {
+	ac := NewApplicationConfig(NewApplicationGroup(nil))
+	assert.Equal(t, uint64(0), ac.TxIDWindow(), "Should default to the whole history")
+	_, err := ac.Deserialize(TxIDWindowKey, utils.MarshalOrPanic(&pb.TxIDWindow{Blocks: 100}))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(100), ac.TxIDWindow())
+}
+
+func TestApplicationCapabilities(t *testing.T) {
+	ac := NewApplicationConfig(NewApplicationGroup(nil))
+	assert.False(t, ac.HasCapability(RangeQueryHashingCapability), "Should default to no capability")
+	_, err := ac.Deserialize(CapabilitiesKey, utils.MarshalOrPanic(&pb.Capabilities{
+		Capabilities: map[string]*pb.Capability{RangeQueryHashingCapability: {}},
+	}))
+	assert.NoError(t, err)
+	assert.True(t, ac.HasCapability(RangeQueryHashingCapability))
+	assert.False(t, ac.HasCapability("Unknown"))
+}
+
+func TestApplicationRWSetLimits(t *testing.T) {
+	ac := NewApplicationConfig(NewApplicationGroup(nil))
+	maxReadSetBytes, maxWriteSetBytes := ac.RWSetLimits()
+	assert.Equal(t, uint64(0), maxReadSetBytes, "Should default to unbounded")
+	assert.Equal(t, uint64(0), maxWriteSetBytes, "Should default to unbounded")
+	_, err := ac.Deserialize(RWSetLimitsKey, utils.MarshalOrPanic(&pb.RWSetLimits{MaxReadSetBytes: 1024, MaxWriteSetBytes: 2048}))
+	assert.NoError(t, err)
+	maxReadSetBytes, maxWriteSetBytes = ac.RWSetLimits()
+	assert.Equal(t, uint64(1024), maxReadSetBytes)
+	assert.Equal(t, uint64(2048), maxWriteSetBytes)
+}
@@ -36,3 +36,40 @@ func applicationConfigGroup(orgID string, key string, value []byte) *cb.ConfigGr
 func TemplateAnchorPeers(orgID string, anchorPeers []*pb.AnchorPeer) *cb.ConfigGroup {
 	return applicationConfigGroup(orgID, AnchorPeersKey, utils.MarshalOrPanic(&pb.AnchorPeers{AnchorPeers: anchorPeers}))
 }
+
+// TemplateTxIDWindow creates a headerless config item representing the number of blocks
+// within which a transaction ID must be unique
+func TemplateTxIDWindow(blocks uint64) *cb.ConfigGroup {
+	result := cb.NewConfigGroup()
+	result.Groups[ApplicationGroupKey] = cb.NewConfigGroup()
+	result.Groups[ApplicationGroupKey].Values[TxIDWindowKey] = &cb.ConfigValue{
+		Value: utils.MarshalOrPanic(&pb.TxIDWindow{Blocks: blocks}),
+	}
+	return result
+}
+
+// TemplateCapabilities creates a headerless config item enabling the capabilities
+// of an application channel
+func TemplateCapabilities(capabilities []string) *cb.ConfigGroup {
+	result := cb.NewConfigGroup()
+	result.Groups[ApplicationGroupKey] = cb.NewConfigGroup()
+	value := &pb.Capabilities{Capabilities: make(map[string]*pb.Capability)}
+	for _, capability := range capabilities {
+		value.Capabilities[capability] = &pb.Capability{}
+	}
+	result.Groups[ApplicationGroupKey].Values[CapabilitiesKey] = &cb.ConfigValue{
+		Value: utils.MarshalOrPanic(value),
+	}
+	return result
+}
+
+// TemplateRWSetLimits creates a headerless config item representing the caps on the size
+// of the read set and of the write set of each transaction
+func TemplateRWSetLimits(maxReadSetBytes, maxWriteSetBytes uint64) *cb.ConfigGroup {
+	result := cb.NewConfigGroup()
+	result.Groups[ApplicationGroupKey] = cb.NewConfigGroup()
+	result.Groups[ApplicationGroupKey].Values[RWSetLimitsKey] = &cb.ConfigValue{
+		Value: utils.MarshalOrPanic(&pb.RWSetLimits{MaxReadSetBytes: maxReadSetBytes, MaxWriteSetBytes: maxWriteSetBytes}),
+	}
+	return result
+}
@@ -0,0 +1,53 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import "time"
+
+// BlockSchedule is the schedule of the blocks of a channel cut regardless of
+// the batch size and timeout, so that the block cadence serves as a heartbeat
+type BlockSchedule struct {
+	// MaxInterval is the maximum interval between two blocks, zero if the
+	// schedule is disabled
+	MaxInterval time.Duration
+
+	// StartHour and EndHour bound the hours of the day, in UTC, during which
+	// the schedule applies, from StartHour included to EndHour excluded. The
+	// window wraps around midnight when StartHour is greater than EndHour, and
+	// spans the whole day when they are equal
+	StartHour int
+	EndHour   int
+}
+
+// NextHeartbeat returns the time at which a block is due when no block is cut
+// after the one cut at lastBlock, or the zero time if the schedule is disabled.
+// A heartbeat falling outside of the window is postponed to its next start
+func (bs BlockSchedule) NextHeartbeat(lastBlock time.Time) time.Time {
+	if bs.MaxInterval <= 0 {
+		return time.Time{}
+	}
+	next := lastBlock.Add(bs.MaxInterval).UTC()
+	if bs.inWindow(next.Hour()) {
+		return next
+	}
+	start := time.Date(next.Year(), next.Month(), next.Day(), bs.StartHour, 0, 0, 0, time.UTC)
+	if start.Before(next) {
+		start = start.AddDate(0, 0, 1)
+	}
+	return start
+}
+
+func (bs BlockSchedule) inWindow(hour int) bool {
+	switch {
+	case bs.StartHour == bs.EndHour:
+		return true
+	case bs.StartHour < bs.EndHour:
+		return hour >= bs.StartHour && hour < bs.EndHour
+	default:
+		return hour >= bs.StartHour || hour < bs.EndHour
+	}
+}
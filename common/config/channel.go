@@ -22,6 +22,7 @@ import (
 
 	"github.com/hyperledger/fabric/bccsp"
 	"github.com/hyperledger/fabric/common/config/msp"
+	"github.com/hyperledger/fabric/common/crypto/fips"
 	"github.com/hyperledger/fabric/common/util"
 	cb "github.com/hyperledger/fabric/protos/common"
 )
@@ -214,6 +215,12 @@ func (cc *ChannelConfig) Validate(tx interface{}, groups map[string]ValuePropose
 }
 
 func (cc *ChannelConfig) validateHashingAlgorithm() error {
+	if fips.Enabled() {
+		if err := fips.CheckHashingAlgorithm(cc.protos.HashingAlgorithm.Name); err != nil {
+			return fmt.Errorf("Channel is not FIPS compliant: %s", err)
+		}
+	}
+
 	switch cc.protos.HashingAlgorithm.Name {
 	case bccsp.SHA256:
 		cc.hashingAlgorithm = util.ComputeSHA256
@@ -22,6 +22,7 @@ import (
 	"sync"
 
 	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/msp/cache"
 	mspprotos "github.com/hyperledger/fabric/protos/msp"
 )
 
@@ -39,7 +40,12 @@ type mspConfigStore struct {
 type MSPConfigHandler struct {
 	pendingConfig map[interface{}]*mspConfigStore
 	pendingLock   sync.RWMutex
-	msp.MSPManager
+
+	// MSPManager is the manager of the committed MSPs. It is replaced when
+	// a config update commits, while the policies and services holding the
+	// handler keep using it, so it must only be accessed under lock
+	MSPManager msp.MSPManager
+	lock       sync.RWMutex
 }
 
 func NewMSPConfigHandler() *MSPConfigHandler {
@@ -77,10 +83,49 @@ func (bh *MSPConfigHandler) CommitProposals(tx interface{}) {
 		panic("Programming error, called BeginConfig multiply for the same tx")
 	}
 
+	bh.lock.Lock()
 	bh.MSPManager = pendingConfig.proposedMgr
+	bh.lock.Unlock()
 	delete(bh.pendingConfig, tx)
 }
 
+// Manager returns the manager of the committed MSPs, or nil if the committed
+// config defines no MSPs
+func (bh *MSPConfigHandler) Manager() msp.MSPManager {
+	bh.lock.RLock()
+	defer bh.lock.RUnlock()
+	return bh.MSPManager
+}
+
+// Setup sets up the manager of the committed MSPs
+func (bh *MSPConfigHandler) Setup(msps []msp.MSP) error {
+	mgr := bh.Manager()
+	if mgr == nil {
+		return fmt.Errorf("no MSPs are defined")
+	}
+	return mgr.Setup(msps)
+}
+
+// GetMSPs returns the committed MSPs
+func (bh *MSPConfigHandler) GetMSPs() (map[string]msp.MSP, error) {
+	mgr := bh.Manager()
+	if mgr == nil {
+		return nil, fmt.Errorf("no MSPs are defined")
+	}
+	return mgr.GetMSPs()
+}
+
+// DeserializeIdentity deserializes an identity with the committed MSPs, so
+// that the identities of the organizations added or removed by a config
+// update are accepted or rejected as soon as it commits
+func (bh *MSPConfigHandler) DeserializeIdentity(serializedIdentity []byte) (msp.Identity, error) {
+	mgr := bh.Manager()
+	if mgr == nil {
+		return nil, fmt.Errorf("no MSPs are defined")
+	}
+	return mgr.DeserializeIdentity(serializedIdentity)
+}
+
 // ProposeValue called when config is added to a proposal
 func (bh *MSPConfigHandler) ProposeMSP(tx interface{}, mspConfig *mspprotos.MSPConfig) (msp.MSP, error) {
 	bh.pendingLock.RLock()
@@ -100,6 +145,7 @@ func (bh *MSPConfigHandler) ProposeMSP(tx interface{}, mspConfig *mspprotos.MSPC
 	if err != nil {
 		return nil, fmt.Errorf("Creating the MSP manager failed, err %s", err)
 	}
+	mspInst = cache.New(mspInst)
 
 	// set it up
 	err = mspInst.Setup(mspConfig)
@@ -47,6 +47,17 @@ const (
 
 	// KafkaBrokersKey is the cb.ConfigItem type key name for the KafkaBrokers message
 	KafkaBrokersKey = "KafkaBrokers"
+
+	// BlockScheduleKey is the cb.ConfigItem type key name for the BlockSchedule message
+	BlockScheduleKey = "BlockSchedule"
+
+	// BlockScheduleCapability is the capability letting the orderers cut the blocks due by the
+	// BlockSchedule of the channel, empty when no message is pending
+	BlockScheduleCapability = "BlockSchedule"
+
+	// MaintenanceCapability is the capability letting the orderers honor the maintenance state of
+	// the ConsensusType of the channel
+	MaintenanceCapability = "Maintenance"
 )
 
 // OrdererProtos is used as the source of the OrdererConfig
@@ -56,6 +67,8 @@ type OrdererProtos struct {
 	BatchTimeout        *ab.BatchTimeout
 	KafkaBrokers        *ab.KafkaBrokers
 	ChannelRestrictions *ab.ChannelRestrictions
+	BlockSchedule       *ab.BlockSchedule
+	Capabilities        *ab.Capabilities
 }
 
 // Config is stores the orderer component configuration
@@ -91,7 +104,8 @@ type OrdererConfig struct {
 	ordererGroup *OrdererGroup
 	orgs         map[string]Org
 
-	batchTimeout time.Duration
+	batchTimeout  time.Duration
+	blockSchedule BlockSchedule
 }
 
 // NewOrdererConfig creates a new instance of the orderer config
@@ -119,6 +133,11 @@ func (oc *OrdererConfig) ConsensusType() string {
 	return oc.protos.ConsensusType.Type
 }
 
+// ConsensusState returns the configured state of the channel, normal or maintenance
+func (oc *OrdererConfig) ConsensusState() ab.ConsensusType_State {
+	return oc.protos.ConsensusType.State
+}
+
 // BatchSize returns the maximum number of messages to include in a block
 func (oc *OrdererConfig) BatchSize() *ab.BatchSize {
 	return oc.protos.BatchSize
@@ -129,6 +148,12 @@ func (oc *OrdererConfig) BatchTimeout() time.Duration {
 	return oc.batchTimeout
 }
 
+// BlockSchedule returns the schedule of the blocks cut regardless of the batch
+// size and timeout, whose MaxInterval is zero if the channel has none
+func (oc *OrdererConfig) BlockSchedule() BlockSchedule {
+	return oc.blockSchedule
+}
+
 // KafkaBrokers returns the addresses (IP:port notation) of a set of "bootstrap"
 // Kafka brokers, i.e. this is not necessarily the entire set of Kafka brokers
 // used for ordering
@@ -146,11 +171,18 @@ func (oc *OrdererConfig) Organizations() map[string]Org {
 	return oc.orgs
 }
 
+// HasCapability returns whether the capability is enabled on the orderers of the channel
+func (oc *OrdererConfig) HasCapability(capability string) bool {
+	_, ok := oc.protos.Capabilities.GetCapabilities()[capability]
+	return ok
+}
+
 func (oc *OrdererConfig) Validate(tx interface{}, groups map[string]ValueProposer) error {
 	for _, validator := range []func() error{
 		oc.validateConsensusType,
 		oc.validateBatchSize,
 		oc.validateBatchTimeout,
+		oc.validateBlockSchedule,
 		oc.validateKafkaBrokers,
 	} {
 		if err := validator(); err != nil {
@@ -175,6 +207,9 @@ func (oc *OrdererConfig) validateConsensusType() error {
 		// The first config we accept the consensus type regardless
 		return fmt.Errorf("Attempted to change the consensus type from %s to %s after init", oc.ordererGroup.ConsensusType(), oc.protos.ConsensusType.Type)
 	}
+	if _, ok := ab.ConsensusType_State_name[int32(oc.protos.ConsensusType.State)]; !ok {
+		return fmt.Errorf("Attempted to set the consensus state to an invalid value: %d", oc.protos.ConsensusType.State)
+	}
 	return nil
 }
 
@@ -206,6 +241,26 @@ func (oc *OrdererConfig) validateBatchTimeout() error {
 	return nil
 }
 
+func (oc *OrdererConfig) validateBlockSchedule() error {
+	schedule := oc.protos.BlockSchedule
+	oc.blockSchedule = BlockSchedule{StartHour: int(schedule.StartHour), EndHour: int(schedule.EndHour)}
+	if schedule.MaxInterval == "" {
+		return nil
+	}
+	var err error
+	oc.blockSchedule.MaxInterval, err = time.ParseDuration(schedule.MaxInterval)
+	if err != nil {
+		return fmt.Errorf("Attempted to set the block schedule max interval to a invalid value: %s", err)
+	}
+	if oc.blockSchedule.MaxInterval <= 0 {
+		return fmt.Errorf("Attempted to set the block schedule max interval to a non-positive value: %s", oc.blockSchedule.MaxInterval)
+	}
+	if schedule.StartHour > 23 || schedule.EndHour > 23 {
+		return fmt.Errorf("Attempted to set the block schedule hours to invalid values: %d-%d", schedule.StartHour, schedule.EndHour)
+	}
+	return nil
+}
+
 func (oc *OrdererConfig) validateKafkaBrokers() error {
 	for _, broker := range oc.protos.KafkaBrokers.Brokers {
 		if !brokerEntrySeemsValid(broker) {
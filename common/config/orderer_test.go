@@ -18,8 +18,10 @@ package config
 
 import (
 	"testing"
+	"time"
 
 	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
 
 	logging "github.com/op/go-logging"
 	"github.com/stretchr/testify/assert"
@@ -44,6 +46,16 @@ func TestConsensusType(t *testing.T) {
 		protos:       &OrdererProtos{ConsensusType: &ab.ConsensusType{Type: "foo"}},
 	}
 	assert.Error(t, oc.validateConsensusType(), "Should have failed to change consensus type")
+
+	oc = &OrdererConfig{
+		ordererGroup: &OrdererGroup{OrdererConfig: &OrdererConfig{protos: &OrdererProtos{ConsensusType: &ab.ConsensusType{Type: "foo"}}}},
+		protos:       &OrdererProtos{ConsensusType: &ab.ConsensusType{Type: "foo", State: ab.ConsensusType_STATE_MAINTENANCE}},
+	}
+	assert.NoError(t, oc.validateConsensusType(), "Should have entered maintenance mode")
+	assert.Equal(t, ab.ConsensusType_STATE_MAINTENANCE, oc.ConsensusState())
+
+	oc.protos.ConsensusType.State = ab.ConsensusType_State(7)
+	assert.Error(t, oc.validateConsensusType(), "Should have failed to set an unknown consensus state")
 }
 
 func TestBatchSize(t *testing.T) {
@@ -76,6 +88,57 @@ func TestBatchTimeout(t *testing.T) {
 	assert.Error(t, oc.validateBatchTimeout(), "Zero batch timeout")
 }
 
+func TestBlockSchedule(t *testing.T) {
+	oc := &OrdererConfig{protos: &OrdererProtos{BlockSchedule: &ab.BlockSchedule{}}}
+	assert.NoError(t, oc.validateBlockSchedule(), "No block schedule")
+	assert.Zero(t, oc.BlockSchedule().MaxInterval)
+	assert.True(t, oc.BlockSchedule().NextHeartbeat(time.Now()).IsZero(), "Disabled block schedule")
+
+	oc = &OrdererConfig{protos: &OrdererProtos{BlockSchedule: &ab.BlockSchedule{MaxInterval: "10m", StartHour: 22, EndHour: 6}}}
+	assert.NoError(t, oc.validateBlockSchedule(), "Valid block schedule")
+	assert.Equal(t, BlockSchedule{MaxInterval: 10 * time.Minute, StartHour: 22, EndHour: 6}, oc.BlockSchedule())
+
+	oc = &OrdererConfig{protos: &OrdererProtos{BlockSchedule: &ab.BlockSchedule{MaxInterval: "10"}}}
+	assert.Error(t, oc.validateBlockSchedule(), "Unparsable max interval")
+
+	oc = &OrdererConfig{protos: &OrdererProtos{BlockSchedule: &ab.BlockSchedule{MaxInterval: "-1s"}}}
+	assert.Error(t, oc.validateBlockSchedule(), "Negative max interval")
+
+	oc = &OrdererConfig{protos: &OrdererProtos{BlockSchedule: &ab.BlockSchedule{MaxInterval: "1s", EndHour: 24}}}
+	assert.Error(t, oc.validateBlockSchedule(), "Invalid end hour")
+}
+
+func TestOrdererCapabilities(t *testing.T) {
+	oc := NewOrdererConfig(NewOrdererGroup(nil))
+	assert.False(t, oc.HasCapability(BlockScheduleCapability), "Should default to no capability")
+	_, err := oc.Deserialize(CapabilitiesKey, utils.MarshalOrPanic(&ab.Capabilities{
+		Capabilities: map[string]*ab.Capability{BlockScheduleCapability: {}},
+	}))
+	assert.NoError(t, err)
+	assert.True(t, oc.HasCapability(BlockScheduleCapability))
+	assert.False(t, oc.HasCapability("Unknown"))
+}
+
+func TestNextHeartbeat(t *testing.T) {
+	at := func(day, hour, min int) time.Time {
+		return time.Date(2017, time.July, day, hour, min, 0, 0, time.UTC)
+	}
+
+	allDay := BlockSchedule{MaxInterval: 10 * time.Minute}
+	assert.Equal(t, at(1, 12, 10), allDay.NextHeartbeat(at(1, 12, 0)))
+	assert.Equal(t, at(2, 0, 5), allDay.NextHeartbeat(at(1, 23, 55)))
+
+	daytime := BlockSchedule{MaxInterval: 10 * time.Minute, StartHour: 8, EndHour: 18}
+	assert.Equal(t, at(1, 12, 10), daytime.NextHeartbeat(at(1, 12, 0)))
+	assert.Equal(t, at(2, 8, 0), daytime.NextHeartbeat(at(1, 17, 55)), "Postponed to the next day")
+	assert.Equal(t, at(1, 8, 0), daytime.NextHeartbeat(at(1, 3, 0)), "Postponed to the start of the day")
+
+	overnight := BlockSchedule{MaxInterval: time.Hour, StartHour: 22, EndHour: 6}
+	assert.Equal(t, at(2, 1, 30), overnight.NextHeartbeat(at(2, 0, 30)))
+	assert.Equal(t, at(1, 22, 0), overnight.NextHeartbeat(at(1, 5, 30)), "Postponed to the start of the night")
+	assert.Equal(t, at(1, 23, 0), overnight.NextHeartbeat(at(1, 22, 0)))
+}
+
 func TestKafkaBrokers(t *testing.T) {
 	oc := &OrdererConfig{protos: &OrdererProtos{KafkaBrokers: &ab.KafkaBrokers{Brokers: []string{"127.0.0.1:9092", "foo.bar:9092"}}}}
 	assert.NoError(t, oc.validateKafkaBrokers(), "Valid kafka brokers")
@@ -51,6 +51,20 @@ func TemplateChannelRestrictions(maxChannels uint64) *cb.ConfigGroup {
 	return ordererConfigGroup(ChannelRestrictionsKey, utils.MarshalOrPanic(&ab.ChannelRestrictions{MaxCount: maxChannels}))
 }
 
+// TemplateBlockSchedule creates a headerless config item representing the block schedule
+func TemplateBlockSchedule(blockSchedule *ab.BlockSchedule) *cb.ConfigGroup {
+	return ordererConfigGroup(BlockScheduleKey, utils.MarshalOrPanic(blockSchedule))
+}
+
+// TemplateOrdererCapabilities creates a headerless config item enabling the capabilities of the orderers of a channel
+func TemplateOrdererCapabilities(capabilities []string) *cb.ConfigGroup {
+	value := &ab.Capabilities{Capabilities: make(map[string]*ab.Capability)}
+	for _, capability := range capabilities {
+		value.Capabilities[capability] = &ab.Capability{}
+	}
+	return ordererConfigGroup(CapabilitiesKey, utils.MarshalOrPanic(value))
+}
+
 // TemplateKafkaBrokers creates a headerless config item representing the kafka brokers
 func TemplateKafkaBrokers(brokers []string) *cb.ConfigGroup {
 	return ordererConfigGroup(KafkaBrokersKey, utils.MarshalOrPanic(&ab.KafkaBrokers{Brokers: brokers}))
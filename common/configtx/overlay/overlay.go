@@ -0,0 +1,256 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package overlay builds the config updates of channels from overlays, which each apply a change
+// to the current config of a channel, such as adding or removing an organization. The overlays
+// are applied to a copy of the config, and the update is computed from the difference, so that
+// the common flows of channel administration are a single call:
+//
+//	envelope, err := overlay.Update(channelID, config, overlay.AddOrg(&overlay.Org{...}))
+//
+// The envelope still has to be signed by enough administrators to satisfy the mod policies of
+// the groups it modifies before being submitted to the ordering service.
+package overlay
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/common/config"
+	configmsp "github.com/hyperledger/fabric/common/config/msp"
+	"github.com/hyperledger/fabric/common/tools/configtxlator/update"
+	"github.com/hyperledger/fabric/msp"
+	cb "github.com/hyperledger/fabric/protos/common"
+	mspprotos "github.com/hyperledger/fabric/protos/msp"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+)
+
+// Overlay applies a change to a copy of the config of a channel
+type Overlay func(conf *cb.Config) error
+
+// Update applies the overlays, in order, to a copy of the config of the channel, and returns the
+// unsigned envelope of the config update from the config to the result
+func Update(channelID string, conf *cb.Config, overlays ...Overlay) (*cb.Envelope, error) {
+	updated := proto.Clone(conf).(*cb.Config)
+	for _, overlay := range overlays {
+		if err := overlay(updated); err != nil {
+			return nil, err
+		}
+	}
+
+	configUpdate, err := update.Compute(conf, updated)
+	if err != nil {
+		return nil, err
+	}
+	configUpdate.ChannelId = channelID
+
+	return utils.CreateSignedEnvelope(cb.HeaderType_CONFIG_UPDATE, channelID, nil, &cb.ConfigUpdateEnvelope{
+		ConfigUpdate: utils.MarshalOrPanic(configUpdate),
+	}, 0, 0)
+}
+
+// Org describes an organization added to a channel
+type Org struct {
+	// Name is the key of the group of the organization, it defaults to the MSP ID
+	Name string
+	// MSPConfig is the verifying MSP configuration of the organization, as returned by
+	// msp.GetVerifyingMspConfig
+	MSPConfig *mspprotos.MSPConfig
+	// MemberAdmins uses the members of the MSP as the admins of the organization rather than
+	// the holders of its admin certificates, as the Role.MEMBER AdminPrincipal of configtxgen
+	MemberAdmins bool
+	// AnchorPeers are the anchor peers of the organization, only set by AddOrg
+	AnchorPeers []*pb.AnchorPeer
+	// Policies overrides the default policies of the organization, by name
+	Policies map[string]*cb.Policy
+}
+
+// AddOrg adds the organization to the application group of an application channel
+func AddOrg(org *Org) Overlay {
+	return func(conf *cb.Config) error {
+		application, err := subGroup(conf.ChannelGroup, config.ApplicationGroupKey)
+		if err != nil {
+			return err
+		}
+		group, err := orgGroup(org, application)
+		if err != nil {
+			return err
+		}
+		if len(org.AnchorPeers) > 0 {
+			group.Values[config.AnchorPeersKey] = &cb.ConfigValue{
+				Value:     utils.MarshalOrPanic(&pb.AnchorPeers{AnchorPeers: org.AnchorPeers}),
+				ModPolicy: configmsp.AdminsPolicyKey,
+			}
+		}
+		application.Groups[orgName(org)] = group
+		return nil
+	}
+}
+
+// AddConsortiumOrg adds the organization to a consortium of the orderer system channel, so that
+// it can create channels and be part of the channels created afterwards
+func AddConsortiumOrg(consortium string, org *Org) Overlay {
+	return func(conf *cb.Config) error {
+		consortiums, err := subGroup(conf.ChannelGroup, config.ConsortiumsGroupKey)
+		if err != nil {
+			return err
+		}
+		consortiumGroup, err := subGroup(consortiums, consortium)
+		if err != nil {
+			return err
+		}
+		group, err := orgGroup(org, consortiumGroup)
+		if err != nil {
+			return err
+		}
+		consortiumGroup.Groups[orgName(org)] = group
+		return nil
+	}
+}
+
+// RemoveOrg removes the organization with the given name from the application group of an
+// application channel. The last organization cannot be removed
+func RemoveOrg(name string) Overlay {
+	return func(conf *cb.Config) error {
+		application, err := subGroup(conf.ChannelGroup, config.ApplicationGroupKey)
+		if err != nil {
+			return err
+		}
+		if _, ok := application.Groups[name]; !ok {
+			return fmt.Errorf("organization %s is not part of the channel", name)
+		}
+		if len(application.Groups) == 1 {
+			return fmt.Errorf("cannot remove %s, the last organization of the channel", name)
+		}
+		delete(application.Groups, name)
+		return nil
+	}
+}
+
+// SetAnchorPeers replaces the anchor peers of the organization of the application channel whose
+// MSP has the given MSP ID
+func SetAnchorPeers(mspID string, anchorPeers []*pb.AnchorPeer) Overlay {
+	return func(conf *cb.Config) error {
+		application, err := subGroup(conf.ChannelGroup, config.ApplicationGroupKey)
+		if err != nil {
+			return err
+		}
+		name, err := orgOfMSP(application, mspID)
+		if err != nil {
+			return err
+		}
+		group := application.Groups[name]
+		value, ok := group.Values[config.AnchorPeersKey]
+		if !ok {
+			value = &cb.ConfigValue{ModPolicy: configmsp.AdminsPolicyKey}
+			group.Values[config.AnchorPeersKey] = value
+		}
+		value.Value = utils.MarshalOrPanic(&pb.AnchorPeers{AnchorPeers: anchorPeers})
+		return nil
+	}
+}
+
+func subGroup(group *cb.ConfigGroup, key string) (*cb.ConfigGroup, error) {
+	sub, ok := group.GetGroups()[key]
+	if !ok {
+		return nil, fmt.Errorf("config has no %s group", key)
+	}
+	return sub, nil
+}
+
+func orgName(org *Org) string {
+	if org.Name != "" {
+		return org.Name
+	}
+	return mspID(org.MSPConfig)
+}
+
+func mspID(mspConfig *mspprotos.MSPConfig) string {
+	fabricConfig := &mspprotos.FabricMSPConfig{}
+	if err := proto.Unmarshal(mspConfig.GetConfig(), fabricConfig); err != nil {
+		return ""
+	}
+	return fabricConfig.Name
+}
+
+// orgOfMSP returns the name of the organization of parent whose MSP has the given MSP ID
+func orgOfMSP(parent *cb.ConfigGroup, id string) (string, error) {
+	for name, group := range parent.Groups {
+		if value, ok := group.Values[configmsp.MSPKey]; ok {
+			mspConfig := &mspprotos.MSPConfig{}
+			if err := proto.Unmarshal(value.Value, mspConfig); err == nil && mspID(mspConfig) == id {
+				return name, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("no organization with MSP ID %s", id)
+}
+
+// orgGroup creates the group of an organization joining the organizations of parent. The
+// organization defines every policy its siblings' implicit meta policies refer to, so that it
+// takes part in them: Admins is signed by its admins, the other policies by its members
+func orgGroup(org *Org, parent *cb.ConfigGroup) (*cb.ConfigGroup, error) {
+	if org.MSPConfig == nil {
+		return nil, fmt.Errorf("organization has no MSP configuration")
+	}
+	if org.MSPConfig.Type != int32(msp.FABRIC) {
+		return nil, fmt.Errorf("unsupported MSP type %d", org.MSPConfig.Type)
+	}
+	id := mspID(org.MSPConfig)
+	if id == "" {
+		return nil, fmt.Errorf("MSP configuration has no MSP ID")
+	}
+	name := orgName(org)
+	if _, ok := parent.Groups[name]; ok {
+		return nil, fmt.Errorf("organization %s already exists", name)
+	}
+	if sibling, err := orgOfMSP(parent, id); err == nil {
+		return nil, fmt.Errorf("MSP ID %s is already used by organization %s", id, sibling)
+	}
+
+	group := cb.NewConfigGroup()
+	group.ModPolicy = configmsp.AdminsPolicyKey
+	group.Values[configmsp.MSPKey] = &cb.ConfigValue{
+		Value:     utils.MarshalOrPanic(org.MSPConfig),
+		ModPolicy: configmsp.AdminsPolicyKey,
+	}
+
+	policyNames := map[string]bool{
+		configmsp.ReadersPolicyKey: true,
+		configmsp.WritersPolicyKey: true,
+		configmsp.AdminsPolicyKey:  true,
+	}
+	for _, policy := range parent.Policies {
+		if policy.GetPolicy().GetType() != int32(cb.Policy_IMPLICIT_META) {
+			continue
+		}
+		implicitMeta := &cb.ImplicitMetaPolicy{}
+		if err := proto.Unmarshal(policy.Policy.Value, implicitMeta); err != nil {
+			return nil, fmt.Errorf("malformed implicit meta policy: %s", err)
+		}
+		policyNames[implicitMeta.SubPolicy] = true
+	}
+	for name := range policyNames {
+		group.Policies[name] = &cb.ConfigPolicy{Policy: defaultPolicy(name, id, org.MemberAdmins), ModPolicy: configmsp.AdminsPolicyKey}
+	}
+	for name, policy := range org.Policies {
+		group.Policies[name] = &cb.ConfigPolicy{Policy: policy, ModPolicy: configmsp.AdminsPolicyKey}
+	}
+	return group, nil
+}
+
+func defaultPolicy(name, mspID string, memberAdmins bool) *cb.Policy {
+	envelope := cauthdsl.SignedByMspMember(mspID)
+	if name == configmsp.AdminsPolicyKey && !memberAdmins {
+		envelope = cauthdsl.SignedByMspAdmin(mspID)
+	}
+	return &cb.Policy{
+		Type:  int32(cb.Policy_SIGNATURE),
+		Value: utils.MarshalOrPanic(envelope),
+	}
+}
@@ -0,0 +1,166 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package overlay
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/common/config"
+	configmsp "github.com/hyperledger/fabric/common/config/msp"
+	"github.com/hyperledger/fabric/common/policies"
+	cb "github.com/hyperledger/fabric/protos/common"
+	mspprotos "github.com/hyperledger/fabric/protos/msp"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mspConfig(mspID string) *mspprotos.MSPConfig {
+	return &mspprotos.MSPConfig{Config: utils.MarshalOrPanic(&mspprotos.FabricMSPConfig{Name: mspID})}
+}
+
+func orgGroupOf(mspID string) *cb.ConfigGroup {
+	group := cb.NewConfigGroup()
+	group.Values[configmsp.MSPKey] = &cb.ConfigValue{Value: utils.MarshalOrPanic(mspConfig(mspID))}
+	return group
+}
+
+// channelConfig returns the config of a channel with Org1 and Org2, whose application group
+// requires a majority of the endorsement policies of the organizations
+func channelConfig() *cb.Config {
+	application := cb.NewConfigGroup()
+	application.ModPolicy = configmsp.AdminsPolicyKey
+	application.Policies[configmsp.AdminsPolicyKey] = policies.ImplicitMetaPolicyWithSubPolicy(configmsp.AdminsPolicyKey, cb.ImplicitMetaPolicy_MAJORITY)
+	application.Policies[configmsp.WritersPolicyKey] = policies.ImplicitMetaPolicyWithSubPolicy(configmsp.WritersPolicyKey, cb.ImplicitMetaPolicy_ANY)
+	application.Policies["Endorsement"] = policies.ImplicitMetaPolicyWithSubPolicy("Endorsers", cb.ImplicitMetaPolicy_MAJORITY)
+	application.Groups["Org1"] = orgGroupOf("Org1MSP")
+	application.Groups["Org2"] = orgGroupOf("Org2MSP")
+
+	channel := cb.NewConfigGroup()
+	channel.Groups[config.ApplicationGroupKey] = application
+	return &cb.Config{ChannelGroup: channel}
+}
+
+func configUpdate(t *testing.T, env *cb.Envelope) *cb.ConfigUpdate {
+	payload, err := utils.UnmarshalPayload(env.Payload)
+	require.NoError(t, err)
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	require.NoError(t, err)
+	assert.Equal(t, int32(cb.HeaderType_CONFIG_UPDATE), chdr.Type)
+	assert.Equal(t, "mychannel", chdr.ChannelId)
+
+	configUpdateEnv := &cb.ConfigUpdateEnvelope{}
+	require.NoError(t, proto.Unmarshal(payload.Data, configUpdateEnv))
+	update := &cb.ConfigUpdate{}
+	require.NoError(t, proto.Unmarshal(configUpdateEnv.ConfigUpdate, update))
+	assert.Equal(t, "mychannel", update.ChannelId)
+	return update
+}
+
+func TestAddOrg(t *testing.T) {
+	conf := channelConfig()
+	anchorPeers := []*pb.AnchorPeer{{Host: "peer0.org3.example.com", Port: 7051}}
+	env, err := Update("mychannel", conf, AddOrg(&Org{MSPConfig: mspConfig("Org3MSP"), AnchorPeers: anchorPeers}))
+	require.NoError(t, err)
+	assert.Equal(t, channelConfig(), conf, "Should not have modified the config")
+
+	update := configUpdate(t, env)
+	application := update.WriteSet.Groups[config.ApplicationGroupKey]
+	assert.Equal(t, uint64(1), application.Version, "Should have bumped the version of the application group")
+	org3 := application.Groups["Org3MSP"]
+	require.NotNil(t, org3, "Should have named the organization after its MSP ID")
+	assert.Equal(t, configmsp.AdminsPolicyKey, org3.ModPolicy)
+	assert.Equal(t, utils.MarshalOrPanic(mspConfig("Org3MSP")), org3.Values[configmsp.MSPKey].Value)
+	assert.Equal(t, utils.MarshalOrPanic(&pb.AnchorPeers{AnchorPeers: anchorPeers}), org3.Values[config.AnchorPeersKey].Value)
+
+	assert.Len(t, org3.Policies, 4, "Should have defined the standard policies and the endorsers policy")
+	assert.Equal(t, utils.MarshalOrPanic(cauthdsl.SignedByMspAdmin("Org3MSP")), org3.Policies[configmsp.AdminsPolicyKey].Policy.Value)
+	assert.Equal(t, utils.MarshalOrPanic(cauthdsl.SignedByMspMember("Org3MSP")), org3.Policies["Endorsers"].Policy.Value)
+	assert.Equal(t, utils.MarshalOrPanic(cauthdsl.SignedByMspMember("Org3MSP")), org3.Policies[configmsp.ReadersPolicyKey].Policy.Value)
+}
+
+func TestAddOrgPolicies(t *testing.T) {
+	custom := &cb.Policy{Type: int32(cb.Policy_SIGNATURE), Value: utils.MarshalOrPanic(cauthdsl.SignedByMspAdmin("Org3MSP"))}
+	env, err := Update("mychannel", channelConfig(), AddOrg(&Org{
+		Name:         "Org3",
+		MSPConfig:    mspConfig("Org3MSP"),
+		MemberAdmins: true,
+		Policies:     map[string]*cb.Policy{"Endorsers": custom},
+	}))
+	require.NoError(t, err)
+	org3 := configUpdate(t, env).WriteSet.Groups[config.ApplicationGroupKey].Groups["Org3"]
+	require.NotNil(t, org3)
+	assert.Equal(t, utils.MarshalOrPanic(cauthdsl.SignedByMspMember("Org3MSP")), org3.Policies[configmsp.AdminsPolicyKey].Policy.Value)
+	assert.Equal(t, custom, org3.Policies["Endorsers"].Policy)
+}
+
+func TestAddOrgErrors(t *testing.T) {
+	for name, org := range map[string]*Org{
+		"no MSP configuration": {},
+		"no MSP ID":            {MSPConfig: &mspprotos.MSPConfig{}},
+		"unsupported MSP type": {MSPConfig: &mspprotos.MSPConfig{Type: 1, Config: mspConfig("Org3MSP").Config}},
+		"existing group":       {Name: "Org1", MSPConfig: mspConfig("Org3MSP")},
+		"existing MSP ID":      {Name: "Org3", MSPConfig: mspConfig("Org2MSP")},
+	} {
+		_, err := Update("mychannel", channelConfig(), AddOrg(org))
+		assert.Error(t, err, "Should have failed with %s", name)
+	}
+
+	_, err := Update("mychannel", &cb.Config{ChannelGroup: cb.NewConfigGroup()}, AddOrg(&Org{MSPConfig: mspConfig("Org3MSP")}))
+	assert.Error(t, err, "Should have failed without an application group")
+}
+
+func TestAddConsortiumOrg(t *testing.T) {
+	consortium := cb.NewConfigGroup()
+	consortium.Groups["Org1"] = orgGroupOf("Org1MSP")
+	consortiums := cb.NewConfigGroup()
+	consortiums.Groups["SampleConsortium"] = consortium
+	channel := cb.NewConfigGroup()
+	channel.Groups[config.ConsortiumsGroupKey] = consortiums
+	conf := &cb.Config{ChannelGroup: channel}
+
+	_, err := Update("mychannel", conf, AddConsortiumOrg("OtherConsortium", &Org{MSPConfig: mspConfig("Org3MSP")}))
+	assert.Error(t, err, "Should have failed with an unknown consortium")
+
+	env, err := Update("mychannel", conf, AddConsortiumOrg("SampleConsortium", &Org{MSPConfig: mspConfig("Org3MSP")}))
+	require.NoError(t, err)
+	consortium = configUpdate(t, env).WriteSet.Groups[config.ConsortiumsGroupKey].Groups["SampleConsortium"]
+	assert.NotNil(t, consortium.Groups["Org3MSP"])
+	assert.Len(t, consortium.Groups["Org3MSP"].Policies, 3)
+}
+
+func TestRemoveOrg(t *testing.T) {
+	env, err := Update("mychannel", channelConfig(), RemoveOrg("Org2"))
+	require.NoError(t, err)
+	application := configUpdate(t, env).WriteSet.Groups[config.ApplicationGroupKey]
+	assert.Equal(t, uint64(1), application.Version)
+	assert.Contains(t, application.Groups, "Org1")
+	assert.NotContains(t, application.Groups, "Org2")
+
+	_, err = Update("mychannel", channelConfig(), RemoveOrg("Org3"))
+	assert.Error(t, err, "Should have failed to remove an unknown organization")
+	_, err = Update("mychannel", channelConfig(), RemoveOrg("Org1"), RemoveOrg("Org2"))
+	assert.Error(t, err, "Should have failed to remove the last organization")
+}
+
+func TestSetAnchorPeers(t *testing.T) {
+	anchorPeers := []*pb.AnchorPeer{{Host: "peer0.org2.example.com", Port: 7051}}
+	env, err := Update("mychannel", channelConfig(), SetAnchorPeers("Org2MSP", anchorPeers))
+	require.NoError(t, err)
+	application := configUpdate(t, env).WriteSet.Groups[config.ApplicationGroupKey]
+	assert.Equal(t, uint64(0), application.Version, "Should not have modified the application group")
+	value := application.Groups["Org2"].Values[config.AnchorPeersKey]
+	require.NotNil(t, value)
+	assert.Equal(t, configmsp.AdminsPolicyKey, value.ModPolicy)
+	assert.Equal(t, utils.MarshalOrPanic(&pb.AnchorPeers{AnchorPeers: anchorPeers}), value.Value)
+
+	_, err = Update("mychannel", channelConfig(), SetAnchorPeers("Org3MSP", anchorPeers))
+	assert.Error(t, err, "Should have failed with an unknown MSP ID")
+}
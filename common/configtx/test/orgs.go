@@ -0,0 +1,230 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package test
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"path/filepath"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/common/config"
+	configtxmsp "github.com/hyperledger/fabric/common/config/msp"
+	"github.com/hyperledger/fabric/common/configtx"
+	configtxapi "github.com/hyperledger/fabric/common/configtx/api"
+	genesisconfig "github.com/hyperledger/fabric/common/configtx/tool/localconfig"
+	"github.com/hyperledger/fabric/common/configtx/tool/provisional"
+	fabriccrypto "github.com/hyperledger/fabric/common/crypto"
+	"github.com/hyperledger/fabric/common/genesis"
+	"github.com/hyperledger/fabric/common/tools/configtxlator/update"
+	"github.com/hyperledger/fabric/common/tools/cryptogen/ca"
+	"github.com/hyperledger/fabric/common/tools/cryptogen/csp"
+	"github.com/hyperledger/fabric/msp"
+	cb "github.com/hyperledger/fabric/protos/common"
+	mspproto "github.com/hyperledger/fabric/protos/msp"
+	"github.com/hyperledger/fabric/protos/utils"
+)
+
+// Org is an organization whose CA and members are generated for the tests
+type Org struct {
+	Name  string
+	Admin *Member
+
+	ca  *ca.CA
+	dir string
+}
+
+// NewOrg generates the CA and the admin of an organization, whose MSP ID is its name, storing
+// their keys under dir
+func NewOrg(dir, name string) (*Org, error) {
+	dir = filepath.Join(dir, name)
+	signCA, err := ca.NewCA(filepath.Join(dir, "ca"), name, "ca."+name)
+	if err != nil {
+		return nil, err
+	}
+	org := &Org{Name: name, ca: signCA, dir: dir}
+	if org.Admin, err = org.NewMember("Admin@" + name); err != nil {
+		return nil, err
+	}
+	return org, nil
+}
+
+// NewMember generates a member of the organization, signed by its CA
+func (o *Org) NewMember(name string) (*Member, error) {
+	dir := filepath.Join(o.dir, name)
+	priv, signer, err := csp.GeneratePrivateKey(dir)
+	if err != nil {
+		return nil, err
+	}
+	pub, err := csp.GetECPublicKey(priv)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := o.ca.SignCertificate(dir, name, nil, pub, x509.KeyUsageDigitalSignature, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Member{MSPID: o.Name, Cert: encodeCert(cert), signer: signer}, nil
+}
+
+// MSPConfig returns the configuration of the MSP of the organization
+func (o *Org) MSPConfig() *mspproto.MSPConfig {
+	return &mspproto.MSPConfig{
+		Type: int32(msp.FABRIC),
+		Config: utils.MarshalOrPanic(&mspproto.FabricMSPConfig{
+			Name:      o.Name,
+			RootCerts: [][]byte{encodeCert(o.ca.SignCert)},
+			Admins:    [][]byte{o.Admin.Cert},
+			CryptoConfig: &mspproto.FabricCryptoConfig{
+				SignatureHashFamily:            bccsp.SHA2,
+				IdentityIdentifierHashFunction: bccsp.SHA256,
+			},
+		}),
+	}
+}
+
+// applicationGroup returns the group of the organization within the Application group of a
+// channel config, modified by its admins
+func (o *Org) applicationGroup() (*cb.ConfigGroup, error) {
+	template := configtx.NewModPolicySettingTemplate(configtxmsp.AdminsPolicyKey, configtx.NewSimpleTemplate(o.template(config.ApplicationGroupKey)))
+	configUpdateEnv, err := template.Envelope("")
+	if err != nil {
+		return nil, err
+	}
+	configUpdate, err := configtx.UnmarshalConfigUpdate(configUpdateEnv.ConfigUpdate)
+	if err != nil {
+		return nil, err
+	}
+	return configUpdate.WriteSet.Groups[config.ApplicationGroupKey].Groups[o.Name], nil
+}
+
+func (o *Org) template(groupKey string) *cb.ConfigGroup {
+	return configtxmsp.TemplateGroupMSPWithAdminRolePrincipal([]string{groupKey, o.Name}, o.MSPConfig(), true)
+}
+
+// Member is a member of an Org, which signs with its key
+type Member struct {
+	MSPID string
+	// Cert is the certificate of the member, PEM encoded
+	Cert []byte
+
+	signer crypto.Signer
+}
+
+// Serialize returns the serialized identity of the member
+func (m *Member) Serialize() []byte {
+	id, err := msp.NewSerializedIdentity(m.MSPID, m.Cert)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+// NewSignatureHeader creates a signature header with the identity of the member
+func (m *Member) NewSignatureHeader() (*cb.SignatureHeader, error) {
+	nonce, err := fabriccrypto.GetRandomNonce()
+	if err != nil {
+		return nil, err
+	}
+	return &cb.SignatureHeader{Creator: m.Serialize(), Nonce: nonce}, nil
+}
+
+// Sign signs a message as the MSPs of the channel verify it
+func (m *Member) Sign(message []byte) ([]byte, error) {
+	digest := sha256.Sum256(message)
+	return m.signer.Sign(rand.Reader, digest[:], nil)
+}
+
+// MakeGenesisBlockFromOrgs creates the genesis block of an application channel of the given
+// orderer and application organizations, with the policies of the sample configuration
+func MakeGenesisBlockFromOrgs(chainID string, ordererOrg *Org, orgs ...*Org) (*cb.Block, error) {
+	genConf := genesisconfig.Load(genesisconfig.SampleInsecureProfile)
+	genConf.Application = &genesisconfig.Application{}
+	groups := []*cb.ConfigGroup{ordererOrg.template(config.OrdererGroupKey)}
+	for _, org := range orgs {
+		groups = append(groups, org.template(config.ApplicationGroupKey))
+	}
+	template := configtx.NewModPolicySettingTemplate(
+		configtxmsp.AdminsPolicyKey,
+		configtx.NewCompositeTemplate(provisional.New(genConf).ChannelTemplate(), configtx.NewSimpleTemplate(groups...)),
+	)
+	return genesis.NewFactoryImpl(template).Block(chainID)
+}
+
+// AddApplicationOrg adds an organization to the Application group of a channel, with a config
+// update signed by the given members. It returns the config envelope applied
+func AddApplicationOrg(cm configtxapi.Manager, org *Org, signers ...*Member) (*cb.ConfigEnvelope, error) {
+	group, err := org.applicationGroup()
+	if err != nil {
+		return nil, err
+	}
+	return UpdateConfig(cm, func(c *cb.Config) {
+		c.ChannelGroup.Groups[config.ApplicationGroupKey].Groups[org.Name] = group
+	}, signers...)
+}
+
+// RemoveApplicationOrg removes an organization from the Application group of a channel, with a
+// config update signed by the given members. It returns the config envelope applied
+func RemoveApplicationOrg(cm configtxapi.Manager, org *Org, signers ...*Member) (*cb.ConfigEnvelope, error) {
+	return UpdateConfig(cm, func(c *cb.Config) {
+		delete(c.ChannelGroup.Groups[config.ApplicationGroupKey].Groups, org.Name)
+	}, signers...)
+}
+
+// UpdateConfig applies the modification of the config of a channel with a config update signed by
+// the given members, as a config transaction does. It returns the config envelope applied
+func UpdateConfig(cm configtxapi.Manager, modify func(*cb.Config), signers ...*Member) (*cb.ConfigEnvelope, error) {
+	original := cm.ConfigEnvelope().Config
+	updated := proto.Clone(original).(*cb.Config)
+	modify(updated)
+
+	configUpdate, err := makeConfigUpdate(cm.ChainID(), original, updated, signers...)
+	if err != nil {
+		return nil, err
+	}
+	configEnv, err := cm.ProposeConfigUpdate(configUpdate)
+	if err != nil {
+		return nil, err
+	}
+	if err := cm.Apply(configEnv); err != nil {
+		return nil, err
+	}
+	return configEnv, nil
+}
+
+func makeConfigUpdate(chainID string, original, updated *cb.Config, signers ...*Member) (*cb.Envelope, error) {
+	configUpdate, err := update.Compute(original, updated)
+	if err != nil {
+		return nil, err
+	}
+	configUpdate.ChannelId = chainID
+	configUpdateEnv := &cb.ConfigUpdateEnvelope{ConfigUpdate: utils.MarshalOrPanic(configUpdate)}
+	for _, signer := range signers {
+		sigHdr, err := signer.NewSignatureHeader()
+		if err != nil {
+			return nil, err
+		}
+		sigHdrBytes := utils.MarshalOrPanic(sigHdr)
+		signature, err := signer.Sign(append(append([]byte{}, sigHdrBytes...), configUpdateEnv.ConfigUpdate...))
+		if err != nil {
+			return nil, err
+		}
+		configUpdateEnv.Signatures = append(configUpdateEnv.Signatures, &cb.ConfigSignature{
+			SignatureHeader: sigHdrBytes,
+			Signature:       signature,
+		})
+	}
+	return utils.CreateSignedEnvelope(cb.HeaderType_CONFIG_UPDATE, chainID, signers[0], configUpdateEnv, 0, 0)
+}
+
+func encodeCert(cert *x509.Certificate) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+}
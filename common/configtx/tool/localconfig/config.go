@@ -96,6 +96,15 @@ type Consortium struct {
 // Application encodes the application-level configuration needed in config transactions.
 type Application struct {
 	Organizations []*Organization `yaml:"Organizations"`
+	TxIDWindow    uint64          `yaml:"TxIDWindow"`
+	Capabilities  []string        `yaml:"Capabilities"`
+	RWSetLimits   RWSetLimits     `yaml:"RWSetLimits"`
+}
+
+// RWSetLimits caps the size of the read set and of the write set of each transaction.
+type RWSetLimits struct {
+	MaxReadSetBytes  uint64 `yaml:"MaxReadSetBytes"`
+	MaxWriteSetBytes uint64 `yaml:"MaxWriteSetBytes"`
 }
 
 // Organization encodes the organization-level configuration needed in config transactions.
@@ -130,9 +139,11 @@ type Orderer struct {
 	Addresses     []string        `yaml:"Addresses"`
 	BatchTimeout  time.Duration   `yaml:"BatchTimeout"`
 	BatchSize     BatchSize       `yaml:"BatchSize"`
+	BlockSchedule BlockSchedule   `yaml:"BlockSchedule"`
 	Kafka         Kafka           `yaml:"Kafka"`
 	Organizations []*Organization `yaml:"Organizations"`
 	MaxChannels   uint64          `yaml:"MaxChannels"`
+	Capabilities  []string        `yaml:"Capabilities"`
 }
 
 // BatchSize contains configuration affecting the size of batches.
@@ -142,6 +153,14 @@ type BatchSize struct {
 	PreferredMaxBytes uint32 `yaml:"PreferredMaxBytes"`
 }
 
+// BlockSchedule contains configuration affecting the blocks cut regardless
+// of the batch size and timeout.
+type BlockSchedule struct {
+	MaxInterval time.Duration `yaml:"MaxInterval"`
+	StartHour   uint32        `yaml:"StartHour"`
+	EndHour     uint32        `yaml:"EndHour"`
+}
+
 // Kafka contains configuration for the Kafka-based orderer.
 type Kafka struct {
 	Brokers []string `yaml:"Brokers"`
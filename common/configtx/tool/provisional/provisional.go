@@ -126,6 +126,18 @@ func New(conf *genesisconfig.Profile) Generator {
 			policies.TemplateImplicitMetaMajorityPolicy([]string{config.OrdererGroupKey}, configvaluesmsp.AdminsPolicyKey),
 		}
 
+		if conf.Orderer.BlockSchedule.MaxInterval > 0 {
+			bs.ordererGroups = append(bs.ordererGroups, config.TemplateBlockSchedule(&ab.BlockSchedule{
+				MaxInterval: conf.Orderer.BlockSchedule.MaxInterval.String(),
+				StartHour:   conf.Orderer.BlockSchedule.StartHour,
+				EndHour:     conf.Orderer.BlockSchedule.EndHour,
+			}))
+		}
+
+		if len(conf.Orderer.Capabilities) > 0 {
+			bs.ordererGroups = append(bs.ordererGroups, config.TemplateOrdererCapabilities(conf.Orderer.Capabilities))
+		}
+
 		for _, org := range conf.Orderer.Organizations {
 			mspConfig, err := msp.GetVerifyingMspConfig(org.MSPDir, org.ID)
 			if err != nil {
@@ -177,6 +189,17 @@ func New(conf *genesisconfig.Profile) Generator {
 			bs.applicationGroups = append(bs.applicationGroups, config.TemplateAnchorPeers(org.Name, anchorProtos))
 		}
 
+		if conf.Application.TxIDWindow > 0 {
+			bs.applicationGroups = append(bs.applicationGroups, config.TemplateTxIDWindow(conf.Application.TxIDWindow))
+		}
+
+		if len(conf.Application.Capabilities) > 0 {
+			bs.applicationGroups = append(bs.applicationGroups, config.TemplateCapabilities(conf.Application.Capabilities))
+		}
+
+		if limits := conf.Application.RWSetLimits; limits.MaxReadSetBytes > 0 || limits.MaxWriteSetBytes > 0 {
+			bs.applicationGroups = append(bs.applicationGroups, config.TemplateRWSetLimits(limits.MaxReadSetBytes, limits.MaxWriteSetBytes))
+		}
 	}
 
 	if conf.Consortiums != nil {
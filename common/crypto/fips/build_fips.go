@@ -0,0 +1,12 @@
+// +build fips
+
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fips
+
+// buildEnabled is set by the fips build tag
+const buildEnabled = true
@@ -0,0 +1,160 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package fips restricts the cryptography of a peer or an orderer to the primitives approved by
+// FIPS 140-2: ECDSA over the NIST curves, RSA keys of at least 2048 bits, the SHA-2 hash family,
+// and TLS 1.2 with ECDHE key exchange and AES-GCM encryption.
+//
+// FIPS mode is enabled by the configuration of the node, or at build time with the fips build
+// tag, in which case it cannot be turned off
+package fips
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/factory"
+	"github.com/hyperledger/fabric/common/flogging"
+	mspproto "github.com/hyperledger/fabric/protos/msp"
+)
+
+var logger = flogging.MustGetLogger("common/crypto/fips")
+
+var enabled int32
+
+func init() {
+	if buildEnabled {
+		enabled = 1
+	}
+}
+
+// Enable turns FIPS mode on for the lifetime of the process. It must be called before the BCCSP
+// and the MSPs are initialized
+func Enable() {
+	if atomic.CompareAndSwapInt32(&enabled, 0, 1) {
+		logger.Info("FIPS mode enabled")
+	}
+}
+
+// Enabled returns whether FIPS mode is on
+func Enabled() bool {
+	return atomic.LoadInt32(&enabled) == 1
+}
+
+// CheckBCCSPOpts checks that the BCCSP is configured with an approved hash family and security
+// level. The defaults of the BCCSP are approved
+func CheckBCCSPOpts(opts *factory.FactoryOpts) error {
+	if opts == nil {
+		return nil
+	}
+	switch opts.ProviderName {
+	case "", "SW":
+		if opts.SwOpts == nil {
+			return nil
+		}
+		return checkSecurity(opts.SwOpts.HashFamily, opts.SwOpts.SecLevel)
+	case "PKCS11":
+		return checkPKCS11Opts(opts)
+	default:
+		return fmt.Errorf("BCCSP provider %s is not approved", opts.ProviderName)
+	}
+}
+
+func checkSecurity(hashFamily string, secLevel int) error {
+	if hashFamily != bccsp.SHA2 {
+		return fmt.Errorf("hash family %s is not approved", hashFamily)
+	}
+	if secLevel != 256 && secLevel != 384 {
+		return fmt.Errorf("security level %d is not approved", secLevel)
+	}
+	return nil
+}
+
+// CheckHashingAlgorithm checks that a channel hashing algorithm is approved
+func CheckHashingAlgorithm(name string) error {
+	if name != bccsp.SHA256 {
+		return fmt.Errorf("hashing algorithm %s is not approved", name)
+	}
+	return nil
+}
+
+// CheckFabricMSPConfig checks that the crypto config and the certificates of an MSP use approved
+// algorithms
+func CheckFabricMSPConfig(conf *mspproto.FabricMSPConfig) error {
+	if cc := conf.CryptoConfig; cc != nil {
+		if cc.SignatureHashFamily != "" && cc.SignatureHashFamily != bccsp.SHA2 {
+			return fmt.Errorf("signature hash family %s is not approved", cc.SignatureHashFamily)
+		}
+		switch cc.IdentityIdentifierHashFunction {
+		case "", bccsp.SHA256, bccsp.SHA384:
+		default:
+			return fmt.Errorf("identity identifier hash function %s is not approved", cc.IdentityIdentifierHashFunction)
+		}
+	}
+
+	certs := [][][]byte{conf.RootCerts, conf.IntermediateCerts, conf.Admins, conf.TlsRootCerts, conf.TlsIntermediateCerts}
+	if conf.SigningIdentity != nil {
+		certs = append(certs, [][]byte{conf.SigningIdentity.PublicSigner})
+	}
+	for _, pems := range certs {
+		for _, pemBytes := range pems {
+			if err := checkPEMCertificate(pemBytes); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func checkPEMCertificate(pemBytes []byte) error {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		// Left to the MSP to reject
+		return nil
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil
+	}
+	if err := CheckCertificate(cert); err != nil {
+		return fmt.Errorf("certificate %s: %s", cert.Subject.CommonName, err)
+	}
+	return nil
+}
+
+// CheckCertificate checks that the key and the signature of a certificate use approved
+// algorithms
+func CheckCertificate(cert *x509.Certificate) error {
+	switch key := cert.PublicKey.(type) {
+	case *ecdsa.PublicKey:
+		switch key.Curve {
+		case elliptic.P256(), elliptic.P384(), elliptic.P521():
+		default:
+			return fmt.Errorf("elliptic curve %s is not approved", key.Curve.Params().Name)
+		}
+	case *rsa.PublicKey:
+		if key.N.BitLen() < 2048 {
+			return fmt.Errorf("RSA keys of %d bits are not approved", key.N.BitLen())
+		}
+	default:
+		return fmt.Errorf("public key algorithm %s is not approved", cert.PublicKeyAlgorithm)
+	}
+
+	switch cert.SignatureAlgorithm {
+	case x509.ECDSAWithSHA256, x509.ECDSAWithSHA384, x509.ECDSAWithSHA512,
+		x509.SHA256WithRSA, x509.SHA384WithRSA, x509.SHA512WithRSA,
+		x509.SHA256WithRSAPSS, x509.SHA384WithRSAPSS, x509.SHA512WithRSAPSS:
+	default:
+		return fmt.Errorf("signature algorithm %s is not approved", cert.SignatureAlgorithm)
+	}
+	return nil
+}
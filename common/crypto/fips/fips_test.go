@@ -0,0 +1,151 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fips
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/factory"
+	mspproto "github.com/hyperledger/fabric/protos/msp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCert(t *testing.T, key crypto.Signer, sigAlg x509.SignatureAlgorithm) *x509.Certificate {
+	template := &x509.Certificate{
+		SerialNumber:       big.NewInt(1),
+		Subject:            pkix.Name{CommonName: "ca.org1"},
+		NotBefore:          time.Now().Add(-time.Hour),
+		NotAfter:           time.Now().Add(time.Hour),
+		SignatureAlgorithm: sigAlg,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert
+}
+
+func newECDSAKey(t *testing.T, curve elliptic.Curve) crypto.Signer {
+	key, err := ecdsa.GenerateKey(curve, rand.Reader)
+	require.NoError(t, err)
+	return key
+}
+
+func newRSAKey(t *testing.T, bits int) crypto.Signer {
+	key, err := rsa.GenerateKey(rand.Reader, bits)
+	require.NoError(t, err)
+	return key
+}
+
+func TestEnable(t *testing.T) {
+	defer func(e int32) { enabled = e }(enabled)
+	assert.Equal(t, buildEnabled, Enabled())
+	Enable()
+	assert.True(t, Enabled())
+}
+
+func TestCheckBCCSPOpts(t *testing.T) {
+	assert.NoError(t, CheckBCCSPOpts(nil))
+	assert.NoError(t, CheckBCCSPOpts(&factory.FactoryOpts{ProviderName: "SW"}))
+	assert.NoError(t, CheckBCCSPOpts(&factory.FactoryOpts{
+		ProviderName: "SW",
+		SwOpts:       &factory.SwOpts{HashFamily: bccsp.SHA2, SecLevel: 384},
+	}))
+	assert.Error(t, CheckBCCSPOpts(&factory.FactoryOpts{
+		ProviderName: "SW",
+		SwOpts:       &factory.SwOpts{HashFamily: bccsp.SHA3, SecLevel: 256},
+	}))
+	assert.Error(t, CheckBCCSPOpts(&factory.FactoryOpts{
+		ProviderName: "SW",
+		SwOpts:       &factory.SwOpts{HashFamily: bccsp.SHA2, SecLevel: 128},
+	}))
+	assert.Error(t, CheckBCCSPOpts(&factory.FactoryOpts{ProviderName: "Plugin"}))
+}
+
+func TestCheckHashingAlgorithm(t *testing.T) {
+	assert.NoError(t, CheckHashingAlgorithm(bccsp.SHA256))
+	assert.Error(t, CheckHashingAlgorithm(bccsp.SHA3_256))
+}
+
+func TestCheckCertificate(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		key     crypto.Signer
+		sigAlg  x509.SignatureAlgorithm
+		success bool
+	}{
+		{"P256", newECDSAKey(t, elliptic.P256()), x509.ECDSAWithSHA256, true},
+		{"P384", newECDSAKey(t, elliptic.P384()), x509.ECDSAWithSHA384, true},
+		{"P224", newECDSAKey(t, elliptic.P224()), x509.ECDSAWithSHA256, false},
+		{"RSA2048", newRSAKey(t, 2048), x509.SHA256WithRSA, true},
+		{"RSA1024", newRSAKey(t, 1024), x509.SHA256WithRSA, false},
+		{"SHA1", newECDSAKey(t, elliptic.P256()), x509.ECDSAWithSHA1, false},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			err := CheckCertificate(newCert(t, test.key, test.sigAlg))
+			if test.success {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestCheckFabricMSPConfig(t *testing.T) {
+	encode := func(cert *x509.Certificate) []byte {
+		return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+	}
+	approved := encode(newCert(t, newECDSAKey(t, elliptic.P256()), x509.ECDSAWithSHA256))
+	weak := encode(newCert(t, newRSAKey(t, 1024), x509.SHA256WithRSA))
+
+	conf := &mspproto.FabricMSPConfig{Name: "Org1MSP", RootCerts: [][]byte{approved}}
+	assert.NoError(t, CheckFabricMSPConfig(conf))
+
+	conf.CryptoConfig = &mspproto.FabricCryptoConfig{
+		SignatureHashFamily:            bccsp.SHA2,
+		IdentityIdentifierHashFunction: bccsp.SHA384,
+	}
+	assert.NoError(t, CheckFabricMSPConfig(conf))
+
+	conf.CryptoConfig.IdentityIdentifierHashFunction = bccsp.SHA3_256
+	assert.Error(t, CheckFabricMSPConfig(conf))
+	conf.CryptoConfig = &mspproto.FabricCryptoConfig{SignatureHashFamily: bccsp.SHA3}
+	assert.Error(t, CheckFabricMSPConfig(conf))
+	conf.CryptoConfig = nil
+
+	conf.TlsRootCerts = [][]byte{weak}
+	err := CheckFabricMSPConfig(conf)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "ca.org1")
+}
+
+func TestConfigureTLS(t *testing.T) {
+	defer func(e int32) { enabled = e }(enabled)
+	enabled = 0
+	config := ConfigureTLS(&tls.Config{})
+	assert.Nil(t, config.CipherSuites)
+
+	Enable()
+	config = ConfigureTLS(&tls.Config{})
+	assert.Equal(t, CipherSuites, config.CipherSuites)
+	assert.Equal(t, uint16(tls.VersionTLS12), config.MinVersion)
+	assert.Equal(t, uint16(tls.VersionTLS12), config.MaxVersion)
+}
@@ -0,0 +1,19 @@
+// +build nopkcs11
+
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fips
+
+import (
+	"errors"
+
+	"github.com/hyperledger/fabric/bccsp/factory"
+)
+
+func checkPKCS11Opts(opts *factory.FactoryOpts) error {
+	return errors.New("PKCS11 is not supported by this build")
+}
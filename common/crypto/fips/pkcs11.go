@@ -0,0 +1,18 @@
+// +build !nopkcs11
+
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fips
+
+import "github.com/hyperledger/fabric/bccsp/factory"
+
+func checkPKCS11Opts(opts *factory.FactoryOpts) error {
+	if opts.Pkcs11Opts == nil {
+		return nil
+	}
+	return checkSecurity(opts.Pkcs11Opts.HashFamily, opts.Pkcs11Opts.SecLevel)
+}
@@ -0,0 +1,31 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fips
+
+import "crypto/tls"
+
+// CipherSuites are the approved TLS 1.2 cipher suites
+var CipherSuites = []uint16{
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+}
+
+// ConfigureTLS restricts a TLS config to TLS 1.2 with the approved cipher suites and curves if
+// FIPS mode is on, and leaves it as is otherwise
+func ConfigureTLS(config *tls.Config) *tls.Config {
+	if !Enabled() {
+		return config
+	}
+	config.MinVersion = tls.VersionTLS12
+	config.MaxVersion = tls.VersionTLS12
+	config.CipherSuites = CipherSuites
+	config.CurvePreferences = []tls.CurveID{tls.CurveP256, tls.CurveP384, tls.CurveP521}
+	config.PreferServerCipherSuites = true
+	return config
+}
@@ -0,0 +1,280 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package keyring encrypts the data at rest with AES-GCM, through the BCCSP, under keys which can be
+// rotated without re-encrypting the data already written.
+//
+// A keyring is described by a YAML file such as
+//
+//	# The ID of the key encrypting the data written from now on
+//	Active: key2
+//	Keys:
+//	  # The data encrypted with the older keys remain readable while they are in the keyring
+//	  - ID: key1
+//	    # A file holding a 256 bit AES key, raw or base64 encoded. Relative paths are relative
+//	    # to the keyring file
+//	    File: key1.key
+//	  - ID: key2
+//	    # The hex encoded SKI of an AES key of the BCCSP, such as a key held by a HSM
+//	    SKI: 6c8b4d0f...
+//
+// Each piece of data is encrypted with the ID of its key, hence rotating the keys amounts to adding a
+// new key to the keyring and making it the active one. The data is bound to its location, such as
+// the offset of a block in its file or the key of a value in a database, which is authenticated as
+// additional data, so that the encrypted data cannot be moved to another location undetected.
+//
+// The data which must be looked up, such as the keys of a database, are replaced by tokens instead,
+// the HMACs of the data under the keys, which are looked up under each key of the keyring.
+//
+// The keys of the keyring are not used as they are: an encryption key and a token key are derived
+// from each of them with HKDF, so that no key is used both to encrypt and to compute HMACs.
+package keyring
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+
+	"github.com/hyperledger/fabric/bccsp"
+	"gopkg.in/yaml.v2"
+)
+
+// header starts the encrypted data, followed by the version of their format. Its leading zero byte
+// cannot start a marshaled protobuf message
+const header = "\x00kr"
+
+// version is the version of the format of the encrypted data written by the keyring
+const version = 2
+
+// The infos from which the encryption and the token keys are derived from the keys of the keyring
+var (
+	encryptionKeyInfo = []byte("fabric keyring encryption key")
+	tokenKeyInfo      = []byte("fabric keyring token key")
+)
+
+// maxKeyIDLength is the maximum length of the key IDs, which are prefixed with their length in a byte
+const maxKeyIDLength = 255
+
+// Keyring encrypts the data with its active key, and decrypts the data encrypted with any of its keys
+type Keyring struct {
+	csp    bccsp.BCCSP
+	active string
+	keys   map[string]*derivedKeys
+}
+
+// derivedKeys are the keys derived from a key of the keyring
+type derivedKeys struct {
+	encryption bccsp.Key
+	token      bccsp.Key
+}
+
+// New creates a keyring from the AES keys of the BCCSP by their ID, encrypting with the active one
+func New(csp bccsp.BCCSP, active string, keys map[string]bccsp.Key) (*Keyring, error) {
+	if _, ok := keys[active]; !ok {
+		return nil, fmt.Errorf("active key %s is not in the keyring", active)
+	}
+	derived := make(map[string]*derivedKeys)
+	for id, key := range keys {
+		if id == "" || len(id) > maxKeyIDLength {
+			return nil, fmt.Errorf("key ID %s must be 1 to %d bytes long", id, maxKeyIDLength)
+		}
+		if !key.Symmetric() {
+			return nil, fmt.Errorf("key %s is not a symmetric key", id)
+		}
+		encryptionKey, err := csp.KeyDeriv(key, &bccsp.HKDFDeriveKeyOpts{Temporary: true, Info: encryptionKeyInfo})
+		if err != nil {
+			return nil, fmt.Errorf("failed deriving the encryption key of key %s: %s", id, err)
+		}
+		tokenKey, err := csp.KeyDeriv(key, &bccsp.HKDFDeriveKeyOpts{Temporary: true, Info: tokenKeyInfo})
+		if err != nil {
+			return nil, fmt.Errorf("failed deriving the token key of key %s: %s", id, err)
+		}
+		derived[id] = &derivedKeys{encryption: encryptionKey, token: tokenKey}
+	}
+	return &Keyring{csp: csp, active: active, keys: derived}, nil
+}
+
+type keyConfig struct {
+	ID   string `yaml:"ID"`
+	File string `yaml:"File"`
+	SKI  string `yaml:"SKI"`
+}
+
+type keyringConfig struct {
+	Active string      `yaml:"Active"`
+	Keys   []keyConfig `yaml:"Keys"`
+}
+
+// Load creates a keyring from its YAML file, importing the keys of the files in the BCCSP
+func Load(path string, csp bccsp.BCCSP) (*Keyring, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading keyring %s: %s", path, err)
+	}
+	conf := &keyringConfig{}
+	if err := yaml.Unmarshal(data, conf); err != nil {
+		return nil, fmt.Errorf("failed parsing keyring %s: %s", path, err)
+	}
+
+	keys := make(map[string]bccsp.Key)
+	for _, kc := range conf.Keys {
+		if _, ok := keys[kc.ID]; ok {
+			return nil, fmt.Errorf("duplicate key %s in keyring %s", kc.ID, path)
+		}
+		key, err := loadKey(kc, filepath.Dir(path), csp)
+		if err != nil {
+			return nil, fmt.Errorf("failed loading key %s of keyring %s: %s", kc.ID, path, err)
+		}
+		keys[kc.ID] = key
+	}
+	return New(csp, conf.Active, keys)
+}
+
+func loadKey(kc keyConfig, dir string, csp bccsp.BCCSP) (bccsp.Key, error) {
+	switch {
+	case kc.File != "" && kc.SKI != "":
+		return nil, fmt.Errorf("both a file and a SKI are set")
+	case kc.SKI != "":
+		ski, err := hex.DecodeString(kc.SKI)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SKI: %s", err)
+		}
+		return csp.GetKey(ski)
+	case kc.File != "":
+		file := kc.File
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(dir, file)
+		}
+		raw, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if len(raw) != 32 {
+			decoded, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(raw)))
+			if err != nil {
+				return nil, fmt.Errorf("key file %s holds neither 32 raw bytes nor base64", file)
+			}
+			raw = decoded
+		}
+		return csp.KeyImport(raw, &bccsp.AES256ImportKeyOpts{Temporary: true})
+	default:
+		return nil, fmt.Errorf("neither a file nor a SKI is set")
+	}
+}
+
+// ActiveKeyID returns the ID of the key which encrypts the data
+func (kr *Keyring) ActiveKeyID() string {
+	return kr.active
+}
+
+// Encrypt encrypts the plaintext with the active key, bound to the location given as additional data,
+// which must be given again to decrypt it
+func (kr *Keyring) Encrypt(plaintext, additionalData []byte) ([]byte, error) {
+	prefix := make([]byte, 0, len(header)+2+len(kr.active))
+	prefix = append(prefix, header...)
+	prefix = append(prefix, version, byte(len(kr.active)))
+	prefix = append(prefix, kr.active...)
+
+	opts := &bccsp.AESGCMModeOpts{AdditionalData: authenticatedData(prefix, additionalData)}
+	ciphertext, err := kr.csp.Encrypt(kr.keys[kr.active].encryption, plaintext, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed encrypting with key %s: %s", kr.active, err)
+	}
+	return append(prefix, ciphertext...), nil
+}
+
+// Decrypt decrypts the data encrypted with any of the keys of the keyring, which fails unless the
+// additional data is the one they were encrypted with
+func (kr *Keyring) Decrypt(data, additionalData []byte) ([]byte, error) {
+	id, ciphertext, err := split(data)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := kr.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("data encrypted with key %s which is not in the keyring", id)
+	}
+	prefix := data[:len(data)-len(ciphertext)]
+	opts := &bccsp.AESGCMModeOpts{AdditionalData: authenticatedData(prefix, additionalData)}
+	plaintext, err := kr.csp.Decrypt(key.encryption, ciphertext, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed decrypting with key %s: %s", id, err)
+	}
+	return plaintext, nil
+}
+
+// authenticatedData returns the data authenticated along with the ciphertext: the prefix of the
+// encrypted data, which ends with the length-prefixed ID of the key, followed by the additional data
+func authenticatedData(prefix, additionalData []byte) []byte {
+	return append(append([]byte{}, prefix...), additionalData...)
+}
+
+// Token returns the token of the data under the active key, which is the same every time
+func (kr *Keyring) Token(data []byte) ([]byte, error) {
+	return kr.token(kr.active, data)
+}
+
+// Tokens returns the tokens of the data under each of the keys, under the active key first
+func (kr *Keyring) Tokens(data []byte) ([][]byte, error) {
+	ids := make([]string, 0, len(kr.keys))
+	for id := range kr.keys {
+		if id != kr.active {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	tokens := make([][]byte, 0, len(kr.keys))
+	for _, id := range append([]string{kr.active}, ids...) {
+		token, err := kr.token(id, data)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens, nil
+}
+
+func (kr *Keyring) token(id string, data []byte) ([]byte, error) {
+	mac, err := kr.csp.KeyDeriv(kr.keys[id].token, &bccsp.HMACDeriveKeyOpts{Temporary: true, Arg: data})
+	if err != nil {
+		return nil, fmt.Errorf("failed computing token with key %s: %s", id, err)
+	}
+	token, err := mac.Bytes()
+	if err != nil {
+		return nil, fmt.Errorf("failed computing token with key %s: %s", id, err)
+	}
+	return token, nil
+}
+
+// IsEncrypted tells whether the data were encrypted by a keyring
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(header))
+}
+
+// KeyID returns the ID of the key which encrypted the data
+func KeyID(data []byte) (string, error) {
+	id, _, err := split(data)
+	return id, err
+}
+
+func split(data []byte) (string, []byte, error) {
+	if !IsEncrypted(data) || len(data) < len(header)+2 {
+		return "", nil, fmt.Errorf("data not encrypted by a keyring")
+	}
+	if v := data[len(header)]; v != version {
+		return "", nil, fmt.Errorf("data encrypted in version %d of the keyring format, only version %d is supported", v, version)
+	}
+	idLength := int(data[len(header)+1])
+	if len(data) < len(header)+2+idLength {
+		return "", nil, fmt.Errorf("truncated key ID")
+	}
+	id := string(data[len(header)+2 : len(header)+2+idLength])
+	return id, data[len(header)+2+idLength:], nil
+}
@@ -0,0 +1,160 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package keyring
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestKeyring(t *testing.T, dir string, active string, ids ...string) {
+	conf := "Active: " + active + "\nKeys:\n"
+	for i, id := range ids {
+		raw := make([]byte, 32)
+		raw[0] = byte(i)
+		content := raw
+		if i%2 == 1 {
+			content = []byte(base64.StdEncoding.EncodeToString(raw) + "\n")
+		}
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, id+".key"), content, 0600))
+		conf += "  - ID: " + id + "\n    File: " + id + ".key\n"
+	}
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "keyring.yaml"), []byte(conf), 0600))
+}
+
+func TestKeyring(t *testing.T) {
+	dir, err := ioutil.TempDir("", "keyring")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	csp, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	require.NoError(t, err)
+
+	newTestKeyring(t, dir, "key1", "key1")
+	kr, err := Load(filepath.Join(dir, "keyring.yaml"), csp)
+	require.NoError(t, err)
+	assert.Equal(t, "key1", kr.ActiveKeyID())
+
+	plaintext := []byte("block")
+	location := []byte("blockfile_000000:0")
+	encrypted1, err := kr.Encrypt(plaintext, location)
+	require.NoError(t, err)
+	assert.True(t, IsEncrypted(encrypted1))
+	assert.False(t, IsEncrypted(plaintext))
+	id, err := KeyID(encrypted1)
+	assert.NoError(t, err)
+	assert.Equal(t, "key1", id)
+
+	// Rotate the keys
+	newTestKeyring(t, dir, "key2", "key1", "key2")
+	kr, err = Load(filepath.Join(dir, "keyring.yaml"), csp)
+	require.NoError(t, err)
+	encrypted2, err := kr.Encrypt(plaintext, location)
+	require.NoError(t, err)
+	id, _ = KeyID(encrypted2)
+	assert.Equal(t, "key2", id)
+
+	for _, encrypted := range [][]byte{encrypted1, encrypted2} {
+		decrypted, err := kr.Decrypt(encrypted, location)
+		assert.NoError(t, err)
+		assert.Equal(t, plaintext, decrypted)
+		_, err = kr.Decrypt(encrypted, []byte("blockfile_000000:1"))
+		assert.Error(t, err, "Should not decrypt the data at another location")
+	}
+
+	// Drop the first key
+	newTestKeyring(t, dir, "key2", "key0", "key2")
+	kr, err = Load(filepath.Join(dir, "keyring.yaml"), csp)
+	require.NoError(t, err)
+	_, err = kr.Decrypt(encrypted1, location)
+	assert.EqualError(t, err, "data encrypted with key key1 which is not in the keyring")
+
+	tampered := append([]byte{}, encrypted2...)
+	tampered[len(tampered)-1] ^= 1
+	_, err = kr.Decrypt(tampered, location)
+	assert.Error(t, err)
+	_, err = kr.Decrypt(plaintext, location)
+	assert.Error(t, err)
+	_, err = kr.Decrypt(encrypted2[:len(header)+3], location)
+	assert.Error(t, err)
+
+	// The key ID is authenticated too
+	renamed := append([]byte{}, encrypted2...)
+	renamed[len(header)+2+len("key")] = '0'
+	_, err = kr.Decrypt(renamed, location)
+	assert.Contains(t, fmt.Sprint(err), "failed decrypting with key key0", "Should not decrypt under another key ID")
+
+	oldVersion := append([]byte{}, encrypted2...)
+	oldVersion[len(header)] = 1
+	_, err = kr.Decrypt(oldVersion, location)
+	assert.EqualError(t, err, "data encrypted in version 1 of the keyring format, only version 2 is supported")
+}
+
+func TestKeyringTokens(t *testing.T) {
+	csp, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	require.NoError(t, err)
+	key1, err := csp.KeyGen(&bccsp.AES256KeyGenOpts{Temporary: true})
+	require.NoError(t, err)
+	key2, err := csp.KeyGen(&bccsp.AES256KeyGenOpts{Temporary: true})
+	require.NoError(t, err)
+
+	kr1, err := New(csp, "key1", map[string]bccsp.Key{"key1": key1})
+	require.NoError(t, err)
+	token1, err := kr1.Token([]byte("key"))
+	require.NoError(t, err)
+	again, err := kr1.Token([]byte("key"))
+	require.NoError(t, err)
+	assert.Equal(t, token1, again, "Tokens should be deterministic")
+	other, err := kr1.Token([]byte("other key"))
+	require.NoError(t, err)
+	assert.NotEqual(t, token1, other)
+
+	// Rotate the keys
+	kr2, err := New(csp, "key2", map[string]bccsp.Key{"key1": key1, "key2": key2})
+	require.NoError(t, err)
+	token2, err := kr2.Token([]byte("key"))
+	require.NoError(t, err)
+	assert.NotEqual(t, token1, token2)
+	tokens, err := kr2.Tokens([]byte("key"))
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{token2, token1}, tokens, "Should return the token under the active key first")
+}
+
+func TestKeyringFromBCCSP(t *testing.T) {
+	csp, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	require.NoError(t, err)
+	key, err := csp.KeyGen(&bccsp.AESKeyGenOpts{Temporary: true})
+	require.NoError(t, err)
+
+	kr, err := New(csp, "hsm", map[string]bccsp.Key{"hsm": key})
+	require.NoError(t, err)
+	encrypted, err := kr.Encrypt([]byte("block"), nil)
+	require.NoError(t, err)
+	decrypted, err := kr.Decrypt(encrypted, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("block"), decrypted)
+
+	_, err = New(csp, "missing", map[string]bccsp.Key{"hsm": key})
+	assert.Error(t, err)
+	_, err = loadKey(keyConfig{ID: "hsm", SKI: hex.EncodeToString(key.SKI())}, "", csp)
+	assert.Error(t, err, "The dummy keystore holds no key")
+	_, err = loadKey(keyConfig{ID: "hsm", SKI: "zz"}, "", csp)
+	assert.Error(t, err)
+	_, err = loadKey(keyConfig{ID: "hsm", SKI: "00", File: "key"}, "", csp)
+	assert.Error(t, err)
+	_, err = loadKey(keyConfig{ID: "hsm"}, "", csp)
+	assert.Error(t, err)
+}
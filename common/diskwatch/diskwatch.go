@@ -0,0 +1,226 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package diskwatch monitors the free space of the file systems holding the
+// ledgers of a node, so that the node stops writing before a full disk makes
+// an append fail halfway through a block. The state of the watcher is
+// published as the diskwatch.quiesced gauge, 1 while quiesced, and as the
+// diskwatch.free_bytes.<path> gauges of the watched paths.
+package diskwatch
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/metrics"
+	gometrics "github.com/rcrowley/go-metrics"
+)
+
+var logger = flogging.MustGetLogger("diskwatch")
+
+// freeSpace returns the number of bytes available to the process on the file system holding path
+var freeSpace = statFreeSpace
+
+// Conf configures a Watcher
+type Conf struct {
+	// Paths are the directories whose file systems are watched
+	Paths []string
+	// Interval between two checks of the free space
+	Interval time.Duration
+	// WarningFreeSpace is the free space in bytes below which a warning is logged. The node resumes
+	// writing once the free space of all the paths is back above this threshold
+	WarningFreeSpace uint64
+	// QuiesceFreeSpace is the free space in bytes below which the node stops writing
+	QuiesceFreeSpace uint64
+}
+
+// Watcher periodically checks the free space of the configured paths and quiesces the node when it
+// runs low. While quiesced, WaitWritable blocks the writers of the node
+type Watcher struct {
+	conf Conf
+
+	lock     sync.RWMutex
+	quiesced bool
+	writable chan struct{}
+	warned   map[string]bool
+	free     map[string]uint64
+
+	quiescedGauge gometrics.Gauge
+	freeGauges    map[string]gometrics.Gauge
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// New creates a Watcher for the given configuration
+func New(conf Conf) (*Watcher, error) {
+	if len(conf.Paths) == 0 {
+		return nil, fmt.Errorf("no path to watch")
+	}
+	if conf.Interval <= 0 {
+		return nil, fmt.Errorf("interval must be positive, got %s", conf.Interval)
+	}
+	if conf.WarningFreeSpace < conf.QuiesceFreeSpace {
+		return nil, fmt.Errorf("warning threshold (%d bytes) is lower than the quiesce threshold (%d bytes)",
+			conf.WarningFreeSpace, conf.QuiesceFreeSpace)
+	}
+	writable := make(chan struct{})
+	close(writable)
+	w := &Watcher{
+		conf:          conf,
+		writable:      writable,
+		warned:        make(map[string]bool),
+		free:          make(map[string]uint64),
+		quiescedGauge: gometrics.GetOrRegisterGauge("diskwatch.quiesced", metrics.Registry),
+		freeGauges:    make(map[string]gometrics.Gauge),
+		stop:          make(chan struct{}),
+	}
+	w.quiescedGauge.Update(0)
+	for _, path := range conf.Paths {
+		w.freeGauges[path] = gometrics.GetOrRegisterGauge("diskwatch.free_bytes."+path, metrics.Registry)
+	}
+	return w, nil
+}
+
+// Start checks the free space once and then keeps checking it every interval until Stop is invoked
+func (w *Watcher) Start() {
+	w.Check()
+	go func() {
+		ticker := time.NewTicker(w.conf.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				w.Check()
+			case <-w.stop:
+				return
+			}
+		}
+	}()
+	logger.Infof("Watching the free space of %v every %s", w.conf.Paths, w.conf.Interval)
+}
+
+// Stop stops the periodic checks. A quiesced node remains quiesced
+func (w *Watcher) Stop() {
+	w.stopOnce.Do(func() { close(w.stop) })
+}
+
+// Check measures the free space of the watched paths, logs the thresholds crossed and quiesces or
+// resumes the node accordingly
+func (w *Watcher) Check() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	quiesce, resume := false, true
+	for _, path := range w.conf.Paths {
+		free, err := freeSpace(path)
+		if err != nil {
+			logger.Warningf("Could not determine the free space of [%s]: %s", path, err)
+			resume = false
+			continue
+		}
+		w.free[path] = free
+		w.freeGauges[path].Update(int64(free))
+
+		switch {
+		case free < w.conf.WarningFreeSpace && !w.warned[path]:
+			logger.Warningf("Free space of [%s] is down to %d bytes, below the warning threshold of %d bytes",
+				path, free, w.conf.WarningFreeSpace)
+			w.warned[path] = true
+		case free >= w.conf.WarningFreeSpace && w.warned[path]:
+			logger.Infof("Free space of [%s] is back to %d bytes", path, free)
+			w.warned[path] = false
+		}
+		if free < w.conf.QuiesceFreeSpace {
+			quiesce = true
+		}
+		if free < w.conf.WarningFreeSpace {
+			resume = false
+		}
+	}
+
+	switch {
+	case quiesce && !w.quiesced:
+		logger.Criticalf("Free space of %v is below %d bytes, quiescing writes until space is reclaimed",
+			w.conf.Paths, w.conf.QuiesceFreeSpace)
+		w.quiesced = true
+		w.writable = make(chan struct{})
+		w.quiescedGauge.Update(1)
+	case !quiesce && resume && w.quiesced:
+		logger.Warningf("Free space of %v is back above %d bytes, resuming writes", w.conf.Paths, w.conf.WarningFreeSpace)
+		w.quiesced = false
+		close(w.writable)
+		w.quiescedGauge.Update(0)
+	}
+}
+
+// Quiesced returns whether the node should refuse writes. A nil Watcher is never quiesced
+func (w *Watcher) Quiesced() bool {
+	if w == nil {
+		return false
+	}
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+	return w.quiesced
+}
+
+// HealthCheck returns an error while the node is quiesced, so that the watcher can report the
+// health of the disks of the node. A nil Watcher is always healthy
+func (w *Watcher) HealthCheck() error {
+	if w.Quiesced() {
+		return fmt.Errorf("writes are quiesced until the free space of %v is back above %d bytes",
+			w.conf.Paths, w.conf.WarningFreeSpace)
+	}
+	return nil
+}
+
+// WaitWritable blocks while the node is quiesced. A nil Watcher never blocks
+func (w *Watcher) WaitWritable() {
+	if w == nil {
+		return
+	}
+	w.lock.RLock()
+	writable := w.writable
+	w.lock.RUnlock()
+
+	select {
+	case <-writable:
+	default:
+		logger.Warningf("Write is blocked until free space of %v is reclaimed", w.conf.Paths)
+		<-writable
+	}
+}
+
+// FreeSpace returns the free space in bytes of each watched path as of the last check
+func (w *Watcher) FreeSpace() map[string]uint64 {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+	free := make(map[string]uint64, len(w.free))
+	for path, bytes := range w.free {
+		free[path] = bytes
+	}
+	return free
+}
+
+// existingAncestor returns the closest ancestor of path (or path itself) which exists,
+// as ledger directories may not have been created yet
+func existingAncestor(path string) string {
+	path = filepath.Clean(path)
+	for {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return path
+		}
+		path = parent
+	}
+}
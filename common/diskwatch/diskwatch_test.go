@@ -0,0 +1,158 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package diskwatch
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/common/metrics"
+	gometrics "github.com/rcrowley/go-metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeDisks struct {
+	lock sync.Mutex
+	free map[string]uint64
+}
+
+func (fd *fakeDisks) set(path string, free uint64) {
+	fd.lock.Lock()
+	defer fd.lock.Unlock()
+	fd.free[path] = free
+}
+
+func (fd *fakeDisks) freeSpace(path string) (uint64, error) {
+	fd.lock.Lock()
+	defer fd.lock.Unlock()
+	free, ok := fd.free[path]
+	if !ok {
+		return 0, fmt.Errorf("no such path")
+	}
+	return free, nil
+}
+
+func withFakeDisks() *fakeDisks {
+	fd := &fakeDisks{free: make(map[string]uint64)}
+	freeSpace = fd.freeSpace
+	return fd
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(Conf{Interval: time.Second})
+	assert.Error(t, err, "Should require a path")
+	_, err = New(Conf{Paths: []string{"/a"}})
+	assert.Error(t, err, "Should require an interval")
+	_, err = New(Conf{Paths: []string{"/a"}, Interval: time.Second, WarningFreeSpace: 10, QuiesceFreeSpace: 20})
+	assert.Error(t, err, "Should require the warning threshold to be above the quiesce threshold")
+	_, err = New(Conf{Paths: []string{"/a"}, Interval: time.Second, WarningFreeSpace: 20, QuiesceFreeSpace: 10})
+	assert.NoError(t, err)
+}
+
+func TestCheck(t *testing.T) {
+	fd := withFakeDisks()
+	defer func() { freeSpace = statFreeSpace }()
+	fd.set("/a", 1000)
+	fd.set("/b", 1000)
+
+	w, err := New(Conf{Paths: []string{"/a", "/b"}, Interval: time.Hour, WarningFreeSpace: 500, QuiesceFreeSpace: 100})
+	assert.NoError(t, err)
+	w.Check()
+	assert.False(t, w.Quiesced())
+	assert.Equal(t, map[string]uint64{"/a": 1000, "/b": 1000}, w.FreeSpace())
+
+	fd.set("/b", 300)
+	w.Check()
+	assert.False(t, w.Quiesced(), "Should only warn between the thresholds")
+	assert.True(t, w.warned["/b"])
+
+	fd.set("/b", 50)
+	w.Check()
+	assert.True(t, w.Quiesced())
+	assert.Error(t, w.HealthCheck())
+	assert.Equal(t, int64(1), gometrics.GetOrRegisterGauge("diskwatch.quiesced", metrics.Registry).Value())
+	assert.Equal(t, int64(50), gometrics.GetOrRegisterGauge("diskwatch.free_bytes./b", metrics.Registry).Value())
+
+	// Resuming requires the free space to be back above the warning threshold
+	fd.set("/b", 300)
+	w.Check()
+	assert.True(t, w.Quiesced())
+
+	fd.set("/b", 800)
+	w.Check()
+	assert.False(t, w.Quiesced())
+	assert.False(t, w.warned["/b"])
+	assert.NoError(t, w.HealthCheck())
+	assert.Equal(t, int64(0), gometrics.GetOrRegisterGauge("diskwatch.quiesced", metrics.Registry).Value())
+
+	// A path whose free space is unknown does not quiesce the node, but prevents it from resuming
+	fd.set("/a", 10)
+	w.Check()
+	assert.True(t, w.Quiesced())
+	fd.set("/a", 1000)
+	delete(fd.free, "/b")
+	w.Check()
+	assert.True(t, w.Quiesced())
+}
+
+func TestWaitWritable(t *testing.T) {
+	fd := withFakeDisks()
+	defer func() { freeSpace = statFreeSpace }()
+	fd.set("/a", 50)
+
+	var nilWatcher *Watcher
+	assert.False(t, nilWatcher.Quiesced())
+	assert.NoError(t, nilWatcher.HealthCheck())
+	nilWatcher.WaitWritable()
+
+	w, err := New(Conf{Paths: []string{"/a"}, Interval: 10 * time.Millisecond, WarningFreeSpace: 500, QuiesceFreeSpace: 100})
+	assert.NoError(t, err)
+	w.Start()
+	defer w.Stop()
+	assert.True(t, w.Quiesced())
+
+	done := make(chan struct{})
+	go func() {
+		w.WaitWritable()
+		close(done)
+	}()
+	select {
+	case <-done:
+		t.Fatal("Should have blocked the writer while quiesced")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	fd.set("/a", 1000)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Should have released the writer once space was reclaimed")
+	}
+	assert.False(t, w.Quiesced())
+	w.WaitWritable()
+}
+
+func TestStatFreeSpace(t *testing.T) {
+	dir, err := ioutil.TempDir("", "diskwatch")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	free, err := statFreeSpace(dir)
+	assert.NoError(t, err)
+	assert.True(t, free > 0)
+
+	// Directories which do not exist yet are accounted to the file system of their closest ancestor
+	free, err = statFreeSpace(filepath.Join(dir, "not", "created"))
+	assert.NoError(t, err)
+	assert.True(t, free > 0)
+	assert.Equal(t, dir, existingAncestor(filepath.Join(dir, "not", "created")))
+}
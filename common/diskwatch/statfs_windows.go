@@ -0,0 +1,13 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package diskwatch
+
+import "fmt"
+
+func statFreeSpace(path string) (uint64, error) {
+	return 0, fmt.Errorf("free space of [%s] cannot be determined on this platform", existingAncestor(path))
+}
@@ -0,0 +1,151 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package flogging
+
+import (
+	"bytes"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"regexp"
+
+	"github.com/op/go-logging"
+)
+
+const redactedText = "[REDACTED]"
+
+var (
+	// pemPattern matches the PEM encoded blocks, such as the certificates of the serialized identities
+	pemPattern = regexp.MustCompile(`-----BEGIN [A-Z0-9 ]+-----[^-]*-----END [A-Z0-9 ]+-----`)
+
+	// subjectPattern matches the attributes of the distinguished names which identify a person
+	subjectPattern = regexp.MustCompile(`\b(CN|OU|O|L|ST|STREET|POSTALCODE|SERIALNUMBER|emailAddress)=[^,+/\]\n]+`)
+
+	// emailPattern matches the email addresses
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+)
+
+// RedactionRules configure the masking of the sensitive data written in the logs, so that
+// debug logging can be enabled in regulated environments. Nothing is masked by default.
+type RedactionRules struct {
+	// Payloads masks the byte slices passed to the log calls, such as the payloads of the
+	// envelopes or the values of the private data
+	Payloads bool
+
+	// Types masks the arguments of the log calls of these types, as printed by %T, such as
+	// *common.Envelope or *peer.ChaincodeProposalPayload
+	Types []string
+
+	// Certificates masks the X.509 certificates passed to the log calls, the PEM encoded
+	// blocks, and the personal attributes of the subjects and the email addresses in the
+	// formatted records
+	Certificates bool
+
+	// Patterns masks the text matching these regular expressions in the formatted records
+	Patterns []string
+}
+
+// redactor masks the arguments and the formatted text of the log records
+type redactor struct {
+	payloads     bool
+	types        map[string]bool
+	certificates bool
+	patterns     []*regexp.Regexp
+}
+
+// newRedactor returns the redactor of the rules, or nil if they mask nothing
+func newRedactor(rules RedactionRules) (*redactor, error) {
+	if !rules.Payloads && !rules.Certificates && len(rules.Types) == 0 && len(rules.Patterns) == 0 {
+		return nil, nil
+	}
+
+	r := &redactor{
+		payloads:     rules.Payloads,
+		types:        make(map[string]bool),
+		certificates: rules.Certificates,
+	}
+	for _, t := range rules.Types {
+		r.types[t] = true
+	}
+	for _, p := range rules.Patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %s: %s", p, err)
+		}
+		r.patterns = append(r.patterns, re)
+	}
+	return r, nil
+}
+
+// redactedValue replaces a masked argument, and prints the same whatever the verb
+type redactedValue string
+
+func (v redactedValue) Format(f fmt.State, verb rune) {
+	io.WriteString(f, string(v))
+}
+
+func (r *redactor) redactArg(arg interface{}) interface{} {
+	switch a := arg.(type) {
+	case []byte:
+		if r.payloads {
+			return redactedValue(fmt.Sprintf("[REDACTED %d bytes]", len(a)))
+		}
+	case *x509.Certificate, x509.Certificate, []*x509.Certificate:
+		if r.certificates {
+			return redactedValue(redactedText)
+		}
+	}
+	if len(r.types) != 0 && r.types[fmt.Sprintf("%T", arg)] {
+		return redactedValue(redactedText)
+	}
+	return arg
+}
+
+func (r *redactor) redactText(text []byte) []byte {
+	if r.certificates {
+		text = pemPattern.ReplaceAll(text, []byte(redactedText))
+		text = subjectPattern.ReplaceAll(text, []byte("$1="+redactedText))
+		text = emailPattern.ReplaceAll(text, []byte(redactedText))
+	}
+	for _, re := range r.patterns {
+		text = re.ReplaceAll(text, []byte(redactedText))
+	}
+	return text
+}
+
+// redactingFormatter masks the sensitive data of the records formatted by another formatter
+type redactingFormatter struct {
+	formatter logging.Formatter
+	redactor  *redactor
+}
+
+func (rf *redactingFormatter) Format(calldepth int, rec *logging.Record, output io.Writer) error {
+	// The arguments are masked before the message is formatted from them
+	for i, arg := range rec.Args {
+		rec.Args[i] = rf.redactor.redactArg(arg)
+	}
+	var buf bytes.Buffer
+	if err := rf.formatter.Format(calldepth+1, rec, &buf); err != nil {
+		return err
+	}
+	_, err := output.Write(rf.redactor.redactText(buf.Bytes()))
+	return err
+}
+
+// InitBackendWithRedaction sets up the logging backend like InitBackend, masking the
+// sensitive data of the records according to the redaction rules
+func InitBackendWithRedaction(formatter logging.Formatter, output io.Writer, rules RedactionRules) error {
+	r, err := newRedactor(rules)
+	if err != nil {
+		return err
+	}
+	if r != nil {
+		formatter = &redactingFormatter{formatter: formatter, redactor: r}
+	}
+	InitBackend(formatter, output)
+	return nil
+}
@@ -0,0 +1,59 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package flogging_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPEM = `-----BEGIN CERTIFICATE-----
+MIICjDCCAjKgAwIBAgIUBEVwsSx0TmqdbzNwleNBBzoIT0wwCgYIKoZIzj0EAwIw
+-----END CERTIFICATE-----`
+
+func TestInitBackendWithRedaction(t *testing.T) {
+	defer flogging.Reset()
+	logger := flogging.MustGetLogger("testredaction")
+
+	t.Run("NoRules", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, flogging.InitBackendWithRedaction(flogging.SetFormat("%{message}"), buf, flogging.RedactionRules{}))
+		logger.Infof("payload %x", []byte("secret"))
+		assert.Equal(t, "payload 736563726574\n", buf.String())
+	})
+
+	t.Run("Payloads", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		rules := flogging.RedactionRules{Payloads: true, Types: []string{"*common.Envelope"}}
+		require.NoError(t, flogging.InitBackendWithRedaction(flogging.SetFormat("%{message}"), buf, rules))
+		logger.Infof("payload %x of envelope %v, block %d", []byte("secret"), &cb.Envelope{Payload: []byte("secret")}, 3)
+		assert.Equal(t, "payload [REDACTED 6 bytes] of envelope [REDACTED], block 3\n", buf.String())
+	})
+
+	t.Run("Certificates", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, flogging.InitBackendWithRedaction(flogging.SetFormat("%{message}"), buf, flogging.RedactionRules{Certificates: true}))
+		logger.Infof("creator %s of alice@org1.example.com with subject CN=Alice Smith,OU=client,O=Org1", testPEM)
+		assert.Equal(t, "creator [REDACTED] of [REDACTED] with subject CN=[REDACTED],OU=[REDACTED],O=[REDACTED]\n", buf.String())
+	})
+
+	t.Run("Patterns", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		rules := flogging.RedactionRules{Patterns: []string{`ssn:\d+`}}
+		require.NoError(t, flogging.InitBackendWithRedaction(flogging.SetFormat("%{message}"), buf, rules))
+		logger.Info("patient ssn:123456789 admitted")
+		assert.Equal(t, "patient [REDACTED] admitted\n", buf.String())
+
+		rules.Patterns = []string{"("}
+		assert.Error(t, flogging.InitBackendWithRedaction(flogging.SetFormat("%{message}"), buf, rules))
+	})
+}
@@ -0,0 +1,119 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package inclusionproof produces and verifies proofs that a transaction is included in a block of
+// a channel. A proof carries the header of the block along with the signatures of the ordering
+// service over it, so that a third party holding only a config block of the channel can verify it
+// against the BlockValidation policy of the channel.
+//
+// The hash of the block data is a flat SHA-256 over the concatenation of the transactions rather
+// than a Merkle tree, and the concatenation does not delimit the transactions. A proof therefore
+// carries all the entries of the block data, and the verifier checks that each of them is an
+// Envelope of the channel in its canonical encoding, which fixes where each entry starts and ends:
+// an envelope hidden inside a field of another transaction, such as a chaincode argument, cannot
+// be split out of the block data as an entry of its own.
+package inclusionproof
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/flogging"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+)
+
+var logger = flogging.MustGetLogger("inclusionproof")
+
+// New creates the proof that the transaction at position txIndex is included in the block
+func New(block *cb.Block, txIndex int) (*cb.InclusionProof, error) {
+	if block == nil || block.Header == nil || block.Data == nil || block.Metadata == nil {
+		return nil, fmt.Errorf("block is incomplete")
+	}
+	if txIndex < 0 || txIndex >= len(block.Data.Data) {
+		return nil, fmt.Errorf("block %d has no transaction at position %d", block.Header.Number, txIndex)
+	}
+	if len(block.Metadata.Metadata) <= int(cb.BlockMetadataIndex_SIGNATURES) {
+		return nil, fmt.Errorf("block %d has no signatures", block.Header.Number)
+	}
+
+	env, err := utils.UnmarshalEnvelope(block.Data.Data[txIndex])
+	if err != nil {
+		return nil, err
+	}
+	chdr, err := channelHeader(env)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cb.InclusionProof{
+		ChannelId:  chdr.ChannelId,
+		Header:     block.Header,
+		Signatures: block.Metadata.Metadata[cb.BlockMetadataIndex_SIGNATURES],
+		TxIndex:    uint64(txIndex),
+		Data:       block.Data.Data,
+	}, nil
+}
+
+// DataHash recomputes the hash of the block data from the proof
+func DataHash(proof *cb.InclusionProof) []byte {
+	return (&cb.BlockData{Data: proof.Data}).Hash()
+}
+
+// checkConsistency checks that the proof is well formed, that its entries hash to the data hash
+// of the header and that each of them is an Envelope of the channel in its canonical encoding. It
+// returns the proven transaction envelope
+func checkConsistency(proof *cb.InclusionProof) (*cb.Envelope, error) {
+	if proof.Header == nil {
+		return nil, fmt.Errorf("proof has no block header")
+	}
+	if proof.TxIndex >= uint64(len(proof.Data)) {
+		return nil, fmt.Errorf("block %d has no transaction at position %d", proof.Header.Number, proof.TxIndex)
+	}
+	if !bytes.Equal(DataHash(proof), proof.Header.DataHash) {
+		return nil, fmt.Errorf("transactions do not hash to the data hash of block %d", proof.Header.Number)
+	}
+
+	var proven *cb.Envelope
+	for i, data := range proof.Data {
+		env, err := utils.UnmarshalEnvelope(data)
+		if err != nil {
+			return nil, fmt.Errorf("entry %d of block %d is not an envelope: %s", i, proof.Header.Number, err)
+		}
+		// A canonical encoding holds the payload and the signature only, each once and in order, so
+		// that the entries of the block data cannot be split differently
+		canonical, err := proto.Marshal(env)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(canonical, data) {
+			return nil, fmt.Errorf("entry %d of block %d is not a canonically encoded envelope", i, proof.Header.Number)
+		}
+		chdr, err := channelHeader(env)
+		if err != nil {
+			return nil, fmt.Errorf("entry %d of block %d has an invalid header: %s", i, proof.Header.Number, err)
+		}
+		if chdr.ChannelId != proof.ChannelId {
+			return nil, fmt.Errorf("entry %d of block %d belongs to channel %s instead of %s", i, proof.Header.Number, chdr.ChannelId, proof.ChannelId)
+		}
+		if uint64(i) == proof.TxIndex {
+			proven = env
+		}
+	}
+	return proven, nil
+}
+
+func channelHeader(env *cb.Envelope) (*cb.ChannelHeader, error) {
+	payload, err := utils.UnmarshalPayload(env.Payload)
+	if err != nil {
+		return nil, err
+	}
+	if payload.Header == nil {
+		return nil, fmt.Errorf("transaction has no header")
+	}
+	return utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+}
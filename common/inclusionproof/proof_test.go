@@ -0,0 +1,207 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package inclusionproof
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/config"
+	configtxtest "github.com/hyperledger/fabric/common/configtx/test"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/common/localmsp"
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	mspmgmt "github.com/hyperledger/fabric/msp/mgmt"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	if err := mspmgmt.LoadDevMsp(); err != nil {
+		fmt.Printf("Could not load the MSP: %s\n", err)
+		os.Exit(-1)
+	}
+	os.Exit(m.Run())
+}
+
+// makeBlock creates a block whose transactions write key<i> in namespace ns, padded to
+// different lengths, and signs it the way the orderer does
+func makeBlock(t *testing.T, numTxs int) *cb.Block {
+	var results [][]byte
+	for i := 0; i < numTxs; i++ {
+		rwsBuilder := rwsetutil.NewRWSetBuilder()
+		rwsBuilder.AddToWriteSet("ns", fmt.Sprintf("key%d", i), []byte(strings.Repeat("v", 10*i+1)))
+		simRes, err := rwsBuilder.GetTxReadWriteSet().ToProtoBytes()
+		assert.NoError(t, err)
+		results = append(results, simRes)
+	}
+	block := testutil.ConstructBlock(t, 5, []byte("previous"), results, true)
+
+	return resign(block)
+}
+
+// resign signs the header of the block the way the orderer does
+func resign(block *cb.Block) *cb.Block {
+	signer := localmsp.NewSigner()
+	sig := &cb.MetadataSignature{SignatureHeader: utils.MarshalOrPanic(utils.NewSignatureHeaderOrPanic(signer))}
+	sig.Signature = utils.SignOrPanic(signer, util.ConcatenateBytes(nil, sig.SignatureHeader, block.Header.Bytes()))
+	block.Metadata.Metadata[cb.BlockMetadataIndex_SIGNATURES] = utils.MarshalOrPanic(&cb.Metadata{Signatures: []*cb.MetadataSignature{sig}})
+	return block
+}
+
+func newVerifier(t *testing.T) *Verifier {
+	genesis, err := configtxtest.MakeGenesisBlock(util.GetTestChainID())
+	assert.NoError(t, err)
+	v, err := NewVerifier(genesis)
+	assert.NoError(t, err)
+	return v
+}
+
+func TestProof(t *testing.T) {
+	block := makeBlock(t, 5)
+	v := newVerifier(t)
+
+	for i := range block.Data.Data {
+		proof, err := New(block, i)
+		assert.NoError(t, err)
+		assert.Equal(t, util.GetTestChainID(), proof.ChannelId)
+		assert.Equal(t, block.Header.DataHash, DataHash(proof))
+
+		env, err := v.Verify(proof)
+		assert.NoError(t, err, "Proof of transaction %d should verify", i)
+		assert.Equal(t, block.Data.Data[i], utils.MarshalOrPanic(env))
+
+		ok, err := HasWrite(env, "ns", fmt.Sprintf("key%d", i), []byte(strings.Repeat("v", 10*i+1)))
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		ok, err = HasWrite(env, "ns", fmt.Sprintf("key%d", i), []byte("other"))
+		assert.NoError(t, err)
+		assert.False(t, ok)
+		ok, err = HasWrite(env, "other", fmt.Sprintf("key%d", i), []byte(strings.Repeat("v", 10*i+1)))
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	}
+
+	// Proofs survive a round trip through their wire format
+	proof, err := New(block, 1)
+	assert.NoError(t, err)
+	decoded := &cb.InclusionProof{}
+	assert.NoError(t, proto.Unmarshal(utils.MarshalOrPanic(proof), decoded))
+	_, err = v.Verify(decoded)
+	assert.NoError(t, err)
+
+	_, err = New(block, 5)
+	assert.Error(t, err)
+	_, err = New(block, -1)
+	assert.Error(t, err)
+	_, err = New(&cb.Block{}, 0)
+	assert.Error(t, err)
+}
+
+func TestVerifyTampering(t *testing.T) {
+	block := makeBlock(t, 3)
+	v := newVerifier(t)
+
+	tamper := func(f func(proof *cb.InclusionProof)) error {
+		proof, err := New(block, 1)
+		assert.NoError(t, err)
+		proof = proto.Clone(proof).(*cb.InclusionProof)
+		f(proof)
+		_, err = v.Verify(proof)
+		return err
+	}
+
+	assert.NoError(t, tamper(func(proof *cb.InclusionProof) {}))
+
+	assert.Error(t, tamper(func(proof *cb.InclusionProof) {
+		env, _ := utils.UnmarshalEnvelope(proof.Data[1])
+		env.Signature = []byte("forged")
+		proof.Data[1] = utils.MarshalOrPanic(env)
+	}), "A modified transaction should not hash to the data hash")
+	assert.Error(t, tamper(func(proof *cb.InclusionProof) { proof.Data = proof.Data[:2] }))
+	assert.Error(t, tamper(func(proof *cb.InclusionProof) { proof.TxIndex = 3 }))
+	assert.Error(t, tamper(func(proof *cb.InclusionProof) {
+		// The same bytes split differently hash to the same data hash
+		proof.Data = [][]byte{proof.Data[0], append(append([]byte{}, proof.Data[1]...), proof.Data[2]...)}
+	}), "Entries which are not canonical envelopes should be rejected")
+	assert.Error(t, tamper(func(proof *cb.InclusionProof) { proof.Header.Number++ }),
+		"The orderer signature should not verify over a modified header")
+	assert.Error(t, tamper(func(proof *cb.InclusionProof) { proof.Header = nil }))
+	assert.Error(t, tamper(func(proof *cb.InclusionProof) { proof.Signatures = nil }))
+	assert.Error(t, tamper(func(proof *cb.InclusionProof) { proof.Signatures = []byte("garbage") }))
+	assert.Error(t, tamper(func(proof *cb.InclusionProof) { proof.ChannelId = "other" }))
+}
+
+func TestVerifyHiddenEnvelope(t *testing.T) {
+	// A transaction carrying the bytes of a whole envelope in its payload, such as in a chaincode
+	// argument, cannot be split so that the hidden envelope becomes an entry of the block
+	hidden := makeBlock(t, 1).Data.Data[0]
+	block := makeBlock(t, 1)
+	env, err := utils.UnmarshalEnvelope(block.Data.Data[0])
+	assert.NoError(t, err)
+	payload, err := utils.UnmarshalPayload(env.Payload)
+	assert.NoError(t, err)
+	payload.Data = hidden
+	env.Payload = utils.MarshalOrPanic(payload)
+	envBytes := utils.MarshalOrPanic(env)
+	block.Data.Data[0] = envBytes
+	block.Header.DataHash = block.Data.Hash()
+	block = resign(block)
+
+	offset := bytes.Index(envBytes, hidden)
+	assert.True(t, offset > 0)
+	proof, err := New(block, 0)
+	assert.NoError(t, err)
+	proof.Data = [][]byte{envBytes[:offset], hidden, envBytes[offset+len(hidden):]}
+	proof.TxIndex = 1
+	assert.Equal(t, block.Header.DataHash, DataHash(proof))
+	_, err = newVerifier(t).Verify(proof)
+	assert.Error(t, err)
+}
+
+func TestVerifyCreatorSignature(t *testing.T) {
+	block := makeBlock(t, 2)
+	env, err := utils.UnmarshalEnvelope(block.Data.Data[1])
+	assert.NoError(t, err)
+	env.Signature = []byte("forged")
+	block.Data.Data[1] = utils.MarshalOrPanic(env)
+	block.Header.DataHash = block.Data.Hash()
+	block = resign(block)
+
+	v := newVerifier(t)
+	proof, err := New(block, 0)
+	assert.NoError(t, err)
+	_, err = v.Verify(proof)
+	assert.NoError(t, err)
+	proof, err = New(block, 1)
+	assert.NoError(t, err)
+	_, err = v.Verify(proof)
+	assert.Error(t, err, "A transaction whose creator signature is invalid should be rejected")
+}
+
+func TestNewVerifier(t *testing.T) {
+	_, err := NewVerifier(&cb.Block{})
+	assert.Error(t, err)
+	_, err = NewVerifier(makeBlock(t, 1))
+	assert.Error(t, err, "Should require a config block")
+
+	genesis, err := configtxtest.MakeGenesisBlock(util.GetTestChainID())
+	assert.NoError(t, err)
+	channelID, orgs, err := ConfigOrgs(genesis, config.OrdererGroupKey)
+	assert.NoError(t, err)
+	assert.Equal(t, util.GetTestChainID(), channelID)
+	assert.Len(t, orgs, 1)
+	assert.NotEmpty(t, orgs[0].RootCerts)
+	_, _, err = ConfigOrgs(genesis, "Other")
+	assert.Error(t, err)
+}
@@ -0,0 +1,214 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package inclusionproof
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/config"
+	"github.com/hyperledger/fabric/common/configtx"
+	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/msp"
+	cb "github.com/hyperledger/fabric/protos/common"
+	mspprotos "github.com/hyperledger/fabric/protos/msp"
+	"github.com/hyperledger/fabric/protos/utils"
+)
+
+// Verifier verifies the proofs of a channel against the BlockValidation policy of the channel and
+// the MSPs of its organizations, as defined by a config block of the channel
+type Verifier struct {
+	channelID       string
+	blockValidation policies.Policy
+	mspManager      msp.MSPManager
+}
+
+// NewVerifier creates a Verifier from a config block of a channel, such as its genesis block. The
+// proofs of the blocks signed after an update of the config require a Verifier built from the
+// config block of the update
+func NewVerifier(configBlock *cb.Block) (*Verifier, error) {
+	if configBlock == nil || configBlock.Data == nil || len(configBlock.Data.Data) == 0 {
+		return nil, fmt.Errorf("config block has no data")
+	}
+	env, err := utils.ExtractEnvelope(configBlock, 0)
+	if err != nil {
+		return nil, err
+	}
+	manager, err := configtx.NewManagerImpl(env, configtx.NewInitializer(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config block: %s", err)
+	}
+	policy, ok := manager.PolicyManager().GetPolicy(policies.BlockValidation)
+	if !ok {
+		return nil, fmt.Errorf("config of channel %s has no %s policy", manager.ChainID(), policies.BlockValidation)
+	}
+	return &Verifier{
+		channelID:       manager.ChainID(),
+		blockValidation: policy,
+		mspManager:      manager.MSPManager(),
+	}, nil
+}
+
+// ConfigOrgs returns the ID of the channel and the MSPs of the organizations of a group of the
+// config held by a config block, such as the ordering service organizations of config.OrdererGroupKey
+func ConfigOrgs(configBlock *cb.Block, groupKey string) (string, []*mspprotos.FabricMSPConfig, error) {
+	if configBlock == nil || configBlock.Data == nil || len(configBlock.Data.Data) == 0 {
+		return "", nil, fmt.Errorf("config block has no data")
+	}
+	env, err := utils.UnmarshalEnvelope(configBlock.Data.Data[0])
+	if err != nil {
+		return "", nil, err
+	}
+	payload, err := utils.UnmarshalPayload(env.Payload)
+	if err != nil {
+		return "", nil, err
+	}
+	if payload.Header == nil {
+		return "", nil, fmt.Errorf("config block has no header")
+	}
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return "", nil, err
+	}
+	configEnv, err := configtx.UnmarshalConfigEnvelope(payload.Data)
+	if err != nil {
+		return "", nil, err
+	}
+	if configEnv.Config == nil || configEnv.Config.ChannelGroup == nil {
+		return "", nil, fmt.Errorf("config block has no channel config")
+	}
+	group, ok := configEnv.Config.ChannelGroup.Groups[groupKey]
+	if !ok {
+		return "", nil, fmt.Errorf("config block has no %s config", groupKey)
+	}
+
+	var orgNames []string
+	for orgName := range group.Groups {
+		orgNames = append(orgNames, orgName)
+	}
+	sort.Strings(orgNames)
+	var orgs []*mspprotos.FabricMSPConfig
+	for _, orgName := range orgNames {
+		value, ok := group.Groups[orgName].Values[config.MSPKey]
+		if !ok {
+			return "", nil, fmt.Errorf("organization %s has no MSP", orgName)
+		}
+		mspConfig := &mspprotos.MSPConfig{}
+		if err := proto.Unmarshal(value.Value, mspConfig); err != nil {
+			return "", nil, fmt.Errorf("error unmarshaling the MSP of organization %s: %s", orgName, err)
+		}
+		fabricConfig := &mspprotos.FabricMSPConfig{}
+		if err := proto.Unmarshal(mspConfig.Config, fabricConfig); err != nil {
+			return "", nil, fmt.Errorf("error unmarshaling the MSP of organization %s: %s", orgName, err)
+		}
+		orgs = append(orgs, fabricConfig)
+	}
+	return chdr.ChannelId, orgs, nil
+}
+
+// Verify checks that the entries of the proof hash to the data hash of the block header, that the
+// header is signed according to the BlockValidation policy of the channel and that the proven
+// transaction is signed by its creator. It returns the transaction envelope. The proof does not
+// carry the validation code of the transaction, which the orderer signatures do not cover
+func (v *Verifier) Verify(proof *cb.InclusionProof) (*cb.Envelope, error) {
+	if proof.ChannelId != v.channelID {
+		return nil, fmt.Errorf("proof is for channel %s instead of %s", proof.ChannelId, v.channelID)
+	}
+	env, err := checkConsistency(proof)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := v.VerifyMetadata(proof.Header, proof.Signatures); err != nil {
+		return nil, err
+	}
+
+	payload, err := utils.UnmarshalPayload(env.Payload)
+	if err != nil {
+		return nil, err
+	}
+	shdr, err := utils.GetSignatureHeader(payload.Header.SignatureHeader)
+	if err != nil {
+		return nil, err
+	}
+	if err := v.VerifyCreator(shdr.Creator, env.Payload, env.Signature); err != nil {
+		return nil, fmt.Errorf("invalid signature of transaction %d of block %d: %s", proof.TxIndex, proof.Header.Number, err)
+	}
+	return env, nil
+}
+
+// VerifyMetadata checks that an encoded metadata of a block, such as its signatures or its last
+// config, is signed along with the block header according to the BlockValidation policy of the
+// channel. It returns the value of the metadata
+func (v *Verifier) VerifyMetadata(header *cb.BlockHeader, metadata []byte) ([]byte, error) {
+	md := &cb.Metadata{}
+	if err := proto.Unmarshal(metadata, md); err != nil {
+		return nil, fmt.Errorf("error unmarshaling the metadata of block %d: %s", header.Number, err)
+	}
+	headerBytes := header.Bytes()
+	var signatureSet []*cb.SignedData
+	for _, sig := range md.Signatures {
+		shdr, err := utils.GetSignatureHeader(sig.SignatureHeader)
+		if err != nil {
+			logger.Debugf("Ignoring signature of block %d: %s", header.Number, err)
+			continue
+		}
+		signatureSet = append(signatureSet, &cb.SignedData{
+			Identity:  shdr.Creator,
+			Data:      util.ConcatenateBytes(md.Value, sig.SignatureHeader, headerBytes),
+			Signature: sig.Signature,
+		})
+	}
+	if err := v.blockValidation.Evaluate(signatureSet); err != nil {
+		return nil, fmt.Errorf("block %d does not satisfy the %s policy: %s", header.Number, policies.BlockValidation, err)
+	}
+	return md.Value, nil
+}
+
+// VerifyCreator checks that the message is signed by the serialized identity creator, which is a
+// valid identity of an MSP of the channel
+func (v *Verifier) VerifyCreator(creator, message, signature []byte) error {
+	id, err := v.mspManager.DeserializeIdentity(creator)
+	if err != nil {
+		return err
+	}
+	if err := id.Validate(); err != nil {
+		return err
+	}
+	return id.Verify(message, signature)
+}
+
+// HasWrite returns whether the transaction writes value to key in the namespace of a chaincode
+func HasWrite(env *cb.Envelope, namespace, key string, value []byte) (bool, error) {
+	envBytes, err := proto.Marshal(env)
+	if err != nil {
+		return false, err
+	}
+	action, err := utils.GetActionFromEnvelope(envBytes)
+	if err != nil {
+		return false, err
+	}
+	txRWSet := &rwsetutil.TxRwSet{}
+	if err := txRWSet.FromProtoBytes(action.Results); err != nil {
+		return false, err
+	}
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		if nsRWSet.NameSpace != namespace || nsRWSet.KvRwSet == nil {
+			continue
+		}
+		for _, write := range nsRWSet.KvRwSet.Writes {
+			if write.Key == key && !write.IsDelete && bytes.Equal(write.Value, value) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
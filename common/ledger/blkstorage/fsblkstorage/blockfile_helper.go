@@ -14,13 +14,15 @@ import (
 	"strings"
 
 	"github.com/davecgh/go-spew/spew"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/crypto/keyring"
 	"github.com/hyperledger/fabric/protos/common"
 )
 
 // constructCheckpointInfoFromBlockFiles scans the last blockfile (if any) and construct the checkpoint info
 // if the last file contains no block or only a partially written block (potentially because of a crash while writing block to the file),
 // this scans the second last file (if any)
-func constructCheckpointInfoFromBlockFiles(rootDir string) (*checkpointInfo, error) {
+func constructCheckpointInfoFromBlockFiles(rootDir string, ledgerID string, kr *keyring.Keyring) (*checkpointInfo, error) {
 	logger.Debugf("Retrieving checkpoint info from block files")
 	var lastFileNum int
 	var numBlocksInFile int
@@ -28,6 +30,8 @@ func constructCheckpointInfoFromBlockFiles(rootDir string) (*checkpointInfo, err
 	var lastBlockNumber uint64
 
 	var lastBlockBytes []byte
+	var lastBlockFileNum int
+	var endOffsetOfLastBlockBytes int64
 	var lastBlock *common.Block
 	var err error
 
@@ -48,18 +52,27 @@ func constructCheckpointInfoFromBlockFiles(rootDir string) (*checkpointInfo, err
 		logger.Errorf("Error while scanning last file [file num=%d]: %s", lastFileNum, err)
 		return nil, err
 	}
+	lastBlockFileNum, endOffsetOfLastBlockBytes = lastFileNum, endOffsetLastBlock
 
 	if numBlocksInFile == 0 && lastFileNum > 0 {
 		secondLastFileNum := lastFileNum - 1
 		fileInfo := getFileInfoOrPanic(rootDir, secondLastFileNum)
 		logger.Debugf("Second last Block file info: FileName=[%s], FileSize=[%d]", fileInfo.Name(), fileInfo.Size())
-		if lastBlockBytes, _, _, err = scanForLastCompleteBlock(rootDir, secondLastFileNum, 0); err != nil {
+		if lastBlockBytes, endOffsetOfLastBlockBytes, _, err = scanForLastCompleteBlock(rootDir, secondLastFileNum, 0); err != nil {
 			logger.Errorf("Error while scanning second last file [file num=%d]: %s", secondLastFileNum, err)
 			return nil, err
 		}
+		lastBlockFileNum = secondLastFileNum
 	}
 
 	if lastBlockBytes != nil {
+		// The last block is preceded in its file by its length
+		startOffset := endOffsetOfLastBlockBytes - int64(len(lastBlockBytes)) - int64(len(proto.EncodeVarint(uint64(len(lastBlockBytes)))))
+		location := blockLocation(ledgerID, lastBlockFileNum, startOffset)
+		if lastBlockBytes, err = decryptBlockBytes(kr, location, lastBlockBytes); err != nil {
+			logger.Errorf("Error decrypting last block: %s", err)
+			return nil, err
+		}
 		if lastBlock, err = deserializeBlock(lastBlockBytes); err != nil {
 			logger.Errorf("Error deserializing last block: %s. Block bytes length = %d", err, len(lastBlockBytes))
 			return nil, err
@@ -25,7 +25,7 @@ func TestConstructCheckpointInfoFromBlockFiles(t *testing.T) {
 	defer env.Cleanup()
 
 	// checkpoint constructed on an empty block folder should return CPInfo with isChainEmpty: true
-	cpInfo, err := constructCheckpointInfoFromBlockFiles(blkStoreDir)
+	cpInfo, err := constructCheckpointInfoFromBlockFiles(blkStoreDir, "", nil)
 	testutil.AssertNoError(t, err, "")
 	testutil.AssertEquals(t, cpInfo, &checkpointInfo{isChainEmpty: true, lastBlockNumber: 0, latestFileChunksize: 0, latestFileChunkSuffixNum: 0})
 
@@ -81,7 +81,7 @@ func TestConstructCheckpointInfoFromBlockFiles(t *testing.T) {
 }
 
 func checkCPInfoFromFile(t *testing.T, blkStoreDir string, expectedCPInfo *checkpointInfo) {
-	cpInfo, err := constructCheckpointInfoFromBlockFiles(blkStoreDir)
+	cpInfo, err := constructCheckpointInfoFromBlockFiles(blkStoreDir, "", nil)
 	testutil.AssertNoError(t, err, "")
 	testutil.AssertEquals(t, cpInfo, expectedCPInfo)
 }
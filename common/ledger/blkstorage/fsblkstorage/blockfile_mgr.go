@@ -25,6 +25,7 @@ import (
 	"github.com/davecgh/go-spew/spew"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/crypto/keyring"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/util"
@@ -45,10 +46,12 @@ var (
 )
 
 type blockfileMgr struct {
+	ledgerID          string
 	rootDir           string
 	conf              *Conf
 	db                *leveldbhelper.DBHandle
 	index             index
+	mappedFiles       *mappedFiles
 	cpInfo            *checkpointInfo
 	cpInfoCond        *sync.Cond
 	currentFileWriter *blockfileWriter
@@ -105,7 +108,7 @@ func newBlockfileMgr(id string, conf *Conf, indexConfig *blkstorage.IndexConfig,
 		panic(fmt.Sprintf("Error: %s", err))
 	}
 	// Instantiate the manager, i.e. blockFileMgr structure
-	mgr := &blockfileMgr{rootDir: rootDir, conf: conf, db: indexStore}
+	mgr := &blockfileMgr{ledgerID: id, rootDir: rootDir, conf: conf, db: indexStore}
 
 	// cp = checkpointInfo, retrieve from the database the file suffix or number of where blocks were stored.
 	// It also retrieves the current size of that file and the last block number that was written to that file.
@@ -116,7 +119,7 @@ func newBlockfileMgr(id string, conf *Conf, indexConfig *blkstorage.IndexConfig,
 	}
 	if cpInfo == nil {
 		logger.Info(`Getting block information from block storage`)
-		if cpInfo, err = constructCheckpointInfoFromBlockFiles(rootDir); err != nil {
+		if cpInfo, err = constructCheckpointInfoFromBlockFiles(rootDir, id, conf.keyring); err != nil {
 			panic(fmt.Sprintf("Could not build checkpoint info from block files: %s", err))
 		}
 		logger.Debugf("Info constructed by scanning the blocks dir = %s", spew.Sdump(cpInfo))
@@ -141,8 +144,18 @@ func newBlockfileMgr(id string, conf *Conf, indexConfig *blkstorage.IndexConfig,
 		panic(fmt.Sprintf("Could not truncate current file to known size in db: %s", err))
 	}
 
-	// Create a new KeyValue store database handler for the blocks index in the keyvalue database
-	mgr.index = newBlockIndex(indexConfig, indexStore)
+	// Create a new KeyValue store database handler for the blocks index in the keyvalue database,
+	// or split the index into shards of their own when configured for very large ledgers
+	if conf.scaleConf.IndexShardSize > 0 {
+		if mgr.index, err = newShardedIndex(conf.getIndexShardsDir(id), conf.scaleConf, indexConfig, conf.indexDBConf, indexStore); err != nil {
+			panic(fmt.Sprintf("Could not open the shards of the block index: %s", err))
+		}
+	} else {
+		mgr.index = newBlockIndex(indexConfig, indexStore)
+	}
+	if conf.scaleConf.MmapReads {
+		mgr.mappedFiles = newMappedFiles(rootDir, conf.scaleConf.MaxMappedFiles)
+	}
 
 	// Update the manager with the checkpoint info and the file writer
 	mgr.cpInfo = cpInfo
@@ -221,6 +234,12 @@ func deriveBlockfilePath(rootDir string, suffixNum int) string {
 
 func (mgr *blockfileMgr) close() {
 	mgr.currentFileWriter.close()
+	if index, ok := mgr.index.(*shardedIndex); ok {
+		index.close()
+	}
+	if mgr.mappedFiles != nil {
+		mgr.mappedFiles.close()
+	}
 }
 
 func (mgr *blockfileMgr) moveToNextFile() {
@@ -248,7 +267,7 @@ func (mgr *blockfileMgr) addBlock(block *common.Block) error {
 	if block.Header.Number != mgr.getBlockchainInfo().Height {
 		return fmt.Errorf("Block number should have been %d but was %d", mgr.getBlockchainInfo().Height, block.Header.Number)
 	}
-	blockBytes, info, err := serializeBlock(block)
+	plainBlockBytes, info, err := serializeBlock(block)
 	if err != nil {
 		return fmt.Errorf("Error while serializing block: %s", err)
 	}
@@ -256,8 +275,10 @@ func (mgr *blockfileMgr) addBlock(block *common.Block) error {
 	//Get the location / offset where each transaction starts in the block and where the block ends
 	txOffsets := info.txOffsets
 	currentOffset := mgr.cpInfo.latestFileChunksize
+	blockBytes, err := encryptBlockBytes(mgr.conf.keyring,
+		blockLocation(mgr.ledgerID, mgr.cpInfo.latestFileChunkSuffixNum, int64(currentOffset)), plainBlockBytes)
 	if err != nil {
-		return fmt.Errorf("Error while serializing block: %s", err)
+		return fmt.Errorf("Error while encrypting block: %s", err)
 	}
 	blockBytesLen := len(blockBytes)
 	blockBytesEncodedLen := proto.EncodeVarint(uint64(blockBytesLen))
@@ -268,6 +289,14 @@ func (mgr *blockfileMgr) addBlock(block *common.Block) error {
 	if currentOffset+totalBytesToAppend > mgr.conf.maxBlockfileSize {
 		mgr.moveToNextFile()
 		currentOffset = 0
+		// The encrypted block is bound to its location, and is as long wherever it is written
+		if mgr.conf.keyring != nil {
+			blockBytes, err = encryptBlockBytes(mgr.conf.keyring,
+				blockLocation(mgr.ledgerID, mgr.cpInfo.latestFileChunkSuffixNum, 0), plainBlockBytes)
+			if err != nil {
+				return fmt.Errorf("Error while encrypting block: %s", err)
+			}
+		}
 	}
 	//append blockBytesEncodedLen to the file
 	err = mgr.currentFileWriter.append(blockBytesEncodedLen, false)
@@ -300,7 +329,7 @@ func (mgr *blockfileMgr) addBlock(block *common.Block) error {
 	}
 
 	//Index block file location pointer updated with file suffex and offset for the new block
-	blockFLP := &fileLocPointer{fileSuffixNum: newCPInfo.latestFileChunkSuffixNum}
+	blockFLP := &fileLocPointer{fileSuffixNum: newCPInfo.latestFileChunkSuffixNum, encrypted: mgr.conf.keyring != nil}
 	blockFLP.offset = currentOffset
 	// shift the txoffset because we prepend length of bytes before block bytes
 	for _, txOffset := range txOffsets {
@@ -387,6 +416,11 @@ func (mgr *blockfileMgr) syncIndex() error {
 		if blockBytes == nil {
 			break
 		}
+		encrypted := keyring.IsEncrypted(blockBytes)
+		location := blockLocation(mgr.ledgerID, blockPlacementInfo.fileNum, blockPlacementInfo.blockStartOffset)
+		if blockBytes, err = decryptBlockBytes(mgr.conf.keyring, location, blockBytes); err != nil {
+			return err
+		}
 		info, err := extractSerializedBlockInfo(blockBytes)
 		if err != nil {
 			return err
@@ -403,7 +437,7 @@ func (mgr *blockfileMgr) syncIndex() error {
 		blockIdxInfo.blockHash = info.blockHeader.Hash()
 		blockIdxInfo.blockNum = info.blockHeader.Number
 		blockIdxInfo.flp = &fileLocPointer{fileSuffixNum: blockPlacementInfo.fileNum,
-			locPointer: locPointer{offset: int(blockPlacementInfo.blockStartOffset)}}
+			locPointer: locPointer{offset: int(blockPlacementInfo.blockStartOffset)}, encrypted: encrypted}
 		blockIdxInfo.txOffsets = info.txOffsets
 		blockIdxInfo.metadata = info.metadata
 
@@ -508,6 +542,9 @@ func (mgr *blockfileMgr) retrieveTransactionByID(txID string) (*common.Envelope,
 	if err != nil {
 		return nil, err
 	}
+	if loc.encrypted {
+		return mgr.fetchTransactionEnvelopeByTxID(loc, txID)
+	}
 	return mgr.fetchTransactionEnvelope(loc)
 }
 
@@ -517,6 +554,9 @@ func (mgr *blockfileMgr) retrieveTransactionByBlockNumTranNum(blockNum uint64, t
 	if err != nil {
 		return nil, err
 	}
+	if loc.encrypted {
+		return mgr.fetchTransactionEnvelopeByTranNum(loc, tranNum)
+	}
 	return mgr.fetchTransactionEnvelope(loc)
 }
 
@@ -534,6 +574,9 @@ func (mgr *blockfileMgr) fetchBlock(lp *fileLocPointer) (*common.Block, error) {
 
 func (mgr *blockfileMgr) fetchTransactionEnvelope(lp *fileLocPointer) (*common.Envelope, error) {
 	logger.Debugf("Entering fetchTransactionEnvelope() %v\n", lp)
+	if lp.encrypted {
+		return nil, errTxInEncryptedBlock
+	}
 	var err error
 	var txEnvelopeBytes []byte
 	if txEnvelopeBytes, err = mgr.fetchRawBytes(lp); err != nil {
@@ -544,6 +587,13 @@ func (mgr *blockfileMgr) fetchTransactionEnvelope(lp *fileLocPointer) (*common.E
 }
 
 func (mgr *blockfileMgr) fetchBlockBytes(lp *fileLocPointer) ([]byte, error) {
+	if mgr.isMappable(lp.fileSuffixNum) {
+		b, err := mgr.mappedFiles.readBlockBytes(lp.fileSuffixNum, lp.offset)
+		if err == nil {
+			return decryptBlockBytesAt(mgr.conf.keyring, mgr.ledgerID, lp, b)
+		}
+		logger.Debugf("Reading block at [%s] from the file rather than the map: %s", lp, err)
+	}
 	stream, err := newBlockfileStream(mgr.rootDir, lp.fileSuffixNum, int64(lp.offset))
 	if err != nil {
 		return nil, err
@@ -553,10 +603,17 @@ func (mgr *blockfileMgr) fetchBlockBytes(lp *fileLocPointer) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
-	return b, nil
+	return decryptBlockBytesAt(mgr.conf.keyring, mgr.ledgerID, lp, b)
 }
 
 func (mgr *blockfileMgr) fetchRawBytes(lp *fileLocPointer) ([]byte, error) {
+	if mgr.isMappable(lp.fileSuffixNum) {
+		b, err := mgr.mappedFiles.read(lp.fileSuffixNum, lp.offset, lp.bytesLength)
+		if err == nil {
+			return b, nil
+		}
+		logger.Debugf("Reading bytes at [%s] from the file rather than the map: %s", lp, err)
+	}
 	filePath := deriveBlockfilePath(mgr.rootDir, lp.fileSuffixNum)
 	reader, err := newBlockfileReader(filePath)
 	if err != nil {
@@ -570,6 +627,16 @@ func (mgr *blockfileMgr) fetchRawBytes(lp *fileLocPointer) ([]byte, error) {
 	return b, nil
 }
 
+// isMappable tells whether a block file is read through a memory map, which requires the file to be complete
+func (mgr *blockfileMgr) isMappable(fileNum int) bool {
+	if mgr.mappedFiles == nil {
+		return false
+	}
+	mgr.cpInfoCond.L.Lock()
+	defer mgr.cpInfoCond.L.Unlock()
+	return fileNum < mgr.cpInfo.latestFileChunkSuffixNum
+}
+
 //Get the current checkpoint information that is stored in the database
 func (mgr *blockfileMgr) loadCurrentInfo() (*checkpointInfo, error) {
 	var b []byte
@@ -0,0 +1,128 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fsblkstorage
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/golang/protobuf/proto"
+)
+
+// mappedFiles reads the block files of a ledger through memory maps, which spares each retrieval the opening,
+// the seeking and the buffered reading of the file. Only the complete block files must be read, as they are
+// never written again. The bytes read are copied out of the maps, so that the least recently used files may be
+// unmapped beyond max
+type mappedFiles struct {
+	rootDir string
+	max     int
+
+	lock  sync.RWMutex
+	files map[int]*mappedFile
+	clock uint64
+}
+
+type mappedFile struct {
+	data     []byte
+	lastUsed uint64
+}
+
+func newMappedFiles(rootDir string, max int) *mappedFiles {
+	return &mappedFiles{rootDir: rootDir, max: max, files: make(map[int]*mappedFile)}
+}
+
+// read returns a copy of the length bytes at offset of a block file
+func (m *mappedFiles) read(fileNum, offset, length int) ([]byte, error) {
+	return m.withFile(fileNum, func(data []byte) ([]byte, error) {
+		if offset < 0 || length < 0 || offset+length > len(data) {
+			return nil, fmt.Errorf("[%d] bytes at offset [%d] are beyond the end of block file [%d]", length, offset, fileNum)
+		}
+		return append([]byte(nil), data[offset:offset+length]...), nil
+	})
+}
+
+// readBlockBytes returns a copy of the bytes of the block at offset of a block file, which are preceded by
+// their length
+func (m *mappedFiles) readBlockBytes(fileNum, offset int) ([]byte, error) {
+	return m.withFile(fileNum, func(data []byte) ([]byte, error) {
+		if offset < 0 || offset >= len(data) {
+			return nil, fmt.Errorf("offset [%d] is beyond the end of block file [%d]", offset, fileNum)
+		}
+		length, n := proto.DecodeVarint(data[offset:])
+		if n == 0 || uint64(len(data)-offset-n) < length {
+			return nil, fmt.Errorf("block at offset [%d] of block file [%d] is truncated", offset, fileNum)
+		}
+		start := offset + n
+		return append([]byte(nil), data[start:start+int(length)]...), nil
+	})
+}
+
+// withFile calls read with the mapped bytes of a block file, which is not unmapped until read returns
+func (m *mappedFiles) withFile(fileNum int, read func(data []byte) ([]byte, error)) ([]byte, error) {
+	m.lock.RLock()
+	file, ok := m.files[fileNum]
+	if !ok {
+		m.lock.RUnlock()
+		if err := m.mapFile(fileNum); err != nil {
+			return nil, err
+		}
+		m.lock.RLock()
+		// the file may have been unmapped again by then
+		if file, ok = m.files[fileNum]; !ok {
+			m.lock.RUnlock()
+			return m.withFile(fileNum, read)
+		}
+	}
+	defer m.lock.RUnlock()
+	atomic.StoreUint64(&file.lastUsed, atomic.AddUint64(&m.clock, 1))
+	return read(file.data)
+}
+
+func (m *mappedFiles) mapFile(fileNum int) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if _, ok := m.files[fileNum]; ok {
+		return nil
+	}
+	data, err := mmapFile(deriveBlockfilePath(m.rootDir, fileNum))
+	if err != nil {
+		return err
+	}
+	for len(m.files) >= m.max {
+		m.unmapLeastRecentlyUsed()
+	}
+	logger.Debugf("Mapped block file [%d] of [%s]", fileNum, m.rootDir)
+	m.files[fileNum] = &mappedFile{data: data}
+	return nil
+}
+
+func (m *mappedFiles) unmapLeastRecentlyUsed() {
+	var lru int
+	var lruFile *mappedFile
+	for fileNum, file := range m.files {
+		if lruFile == nil || atomic.LoadUint64(&file.lastUsed) < atomic.LoadUint64(&lruFile.lastUsed) {
+			lru, lruFile = fileNum, file
+		}
+	}
+	m.unmap(lru, lruFile)
+}
+
+func (m *mappedFiles) unmap(fileNum int, file *mappedFile) {
+	if err := munmapFile(file.data); err != nil {
+		logger.Errorf("Error while unmapping block file [%d] of [%s]: %s", fileNum, m.rootDir, err)
+	}
+	delete(m.files, fileNum)
+}
+
+func (m *mappedFiles) close() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for fileNum, file := range m.files {
+		m.unmap(fileNum, file)
+	}
+}
@@ -118,7 +118,7 @@ func (index *blockIndex) indexBlock(blockIdxInfo *blockIdxInfo) error {
 	//Index3 Used to find a transaction by it's transaction id
 	if _, ok := index.indexItemsMap[blkstorage.IndexableAttrTxID]; ok {
 		for _, txoffset := range txOffsets {
-			txFlp := newFileLocationPointer(flp.fileSuffixNum, flp.offset, txoffset.loc)
+			txFlp := newTxLocationPointer(flp, txoffset.loc)
 			logger.Debugf("Adding txLoc [%s] for tx ID: [%s] to index", txFlp, txoffset.txID)
 			txFlpBytes, marshalErr := txFlp.marshal()
 			if marshalErr != nil {
@@ -131,7 +131,7 @@ func (index *blockIndex) indexBlock(blockIdxInfo *blockIdxInfo) error {
 	//Index4 - Store BlockNumTranNum will be used to query history data
 	if _, ok := index.indexItemsMap[blkstorage.IndexableAttrBlockNumTranNum]; ok {
 		for txIterator, txoffset := range txOffsets {
-			txFlp := newFileLocationPointer(flp.fileSuffixNum, flp.offset, txoffset.loc)
+			txFlp := newTxLocationPointer(flp, txoffset.loc)
 			logger.Debugf("Adding txLoc [%s] for tx number:[%d] ID: [%s] to blockNumTranNum index", txFlp, txIterator, txoffset.txID)
 			txFlpBytes, marshalErr := txFlp.marshal()
 			if marshalErr != nil {
@@ -310,10 +310,13 @@ func (lp *locPointer) String() string {
 		lp.offset, lp.bytesLength)
 }
 
-// fileLocPointer
+// fileLocPointer locates a block or a transaction in the block files. The location of a transaction
+// of an encrypted block is that of its block, as the offsets of the transactions within the plaintext
+// block do not apply to the encrypted bytes in the file
 type fileLocPointer struct {
 	fileSuffixNum int
 	locPointer
+	encrypted bool
 }
 
 func newFileLocationPointer(fileSuffixNum int, beginningOffset int, relativeLP *locPointer) *fileLocPointer {
@@ -323,6 +326,15 @@ func newFileLocationPointer(fileSuffixNum int, beginningOffset int, relativeLP *
 	return flp
 }
 
+// newTxLocationPointer returns the location of a transaction of the block at blockFLP
+func newTxLocationPointer(blockFLP *fileLocPointer, relativeLP *locPointer) *fileLocPointer {
+	if blockFLP.encrypted {
+		txFLP := *blockFLP
+		return &txFLP
+	}
+	return newFileLocationPointer(blockFLP.fileSuffixNum, blockFLP.offset, relativeLP)
+}
+
 func (flp *fileLocPointer) marshal() ([]byte, error) {
 	buffer := proto.NewBuffer([]byte{})
 	e := buffer.EncodeVarint(uint64(flp.fileSuffixNum))
@@ -337,6 +349,12 @@ func (flp *fileLocPointer) marshal() ([]byte, error) {
 	if e != nil {
 		return nil, e
 	}
+	// The pointers into plaintext blocks keep the encoding which predates the encryption
+	if flp.encrypted {
+		if e = buffer.EncodeVarint(1); e != nil {
+			return nil, e
+		}
+	}
 	return buffer.Bytes(), nil
 }
 
@@ -358,11 +376,14 @@ func (flp *fileLocPointer) unmarshal(b []byte) error {
 		return e
 	}
 	flp.bytesLength = int(i)
+	// The encrypted marker is only present in the pointers into encrypted blocks
+	i, e = buffer.DecodeVarint()
+	flp.encrypted = e == nil && i == 1
 	return nil
 }
 
 func (flp *fileLocPointer) String() string {
-	return fmt.Sprintf("fileSuffixNum=%d, %s", flp.fileSuffixNum, flp.locPointer.String())
+	return fmt.Sprintf("fileSuffixNum=%d, %s, encrypted=%t", flp.fileSuffixNum, flp.locPointer.String(), flp.encrypted)
 }
 
 func (blockIdxInfo *blockIdxInfo) String() string {
@@ -0,0 +1,394 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fsblkstorage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/util"
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/protos/peer"
+	"github.com/syndtr/goleveldb/leveldb/filter"
+	leveldbutil "github.com/syndtr/goleveldb/leveldb/util"
+)
+
+const (
+	shardedIndexCheckpointKeyStr = "shardedIndexCheckpointKey"
+	indexShardSizeKeyStr         = "indexShardSize"
+	shardFilterKeyPrefix         = "shardFilter"
+	// shardBloomFilterBits is the number of bits per key of the bloom filters of the shards when none is
+	// configured, as the lookups by hash and by transaction ID probe the shards until found
+	shardBloomFilterBits = 10
+)
+
+var (
+	shardedIndexCheckpointKey = []byte(shardedIndexCheckpointKeyStr)
+	indexShardSizeKey         = []byte(indexShardSizeKeyStr)
+
+	// shardKeysFilter filters the block hashes and transaction IDs of the complete shards
+	shardKeysFilter = filter.NewBloomFilter(shardBloomFilterBits)
+)
+
+// shardedIndex is an index split into shards by block ranges, the shard n indexing the blocks n*shardSize to
+// (n+1)*shardSize-1 in a leveldb of its own, so that the size of the leveldbs, hence the cost of their
+// compactions and lookups, stays bounded however long the chain. The lookups by block number go to a single
+// shard, while the lookups by hash and by transaction ID probe the shards from the newest one, which finds the
+// latest transaction with a given ID as the single index does. The shards are opened on demand and the least
+// recently used ones are closed beyond maxOpen. The checkpoint of the index and its shard size are kept in db
+//
+// So that a lookup missing most shards does not open them all, each shard is given a bloom filter of its block
+// hashes and transaction IDs once its last block is indexed, which the probes check before opening the shard.
+// The filters are kept in db and in memory, at about shardBloomFilterBits bits per block and transaction
+type shardedIndex struct {
+	dir         string
+	shardSize   uint64
+	maxOpen     int
+	indexConfig *blkstorage.IndexConfig
+	dbConf      leveldbhelper.Conf
+	db          *leveldbhelper.DBHandle
+
+	lock   sync.RWMutex
+	shards map[uint64]*indexShard
+	clock  uint64
+
+	filtersLock sync.Mutex
+	filters     map[uint64][]byte
+}
+
+type indexShard struct {
+	*blockIndex
+	provider *leveldbhelper.Provider
+	lastUsed uint64
+}
+
+func newShardedIndex(dir string, scaleConf ScaleConf, indexConfig *blkstorage.IndexConfig,
+	dbConf leveldbhelper.Conf, db *leveldbhelper.DBHandle) (*shardedIndex, error) {
+	if dbConf.BloomFilterBits <= 0 {
+		dbConf.BloomFilterBits = shardBloomFilterBits
+	}
+	// all the shards but the last one are never written again
+	dbConf.CompactionInterval = 0
+	index := &shardedIndex{
+		dir:         dir,
+		shardSize:   scaleConf.IndexShardSize,
+		maxOpen:     scaleConf.MaxOpenShards,
+		indexConfig: indexConfig,
+		dbConf:      dbConf,
+		db:          db,
+		shards:      make(map[uint64]*indexShard),
+		filters:     make(map[uint64][]byte),
+	}
+
+	shardSizeBytes, err := db.Get(indexShardSizeKey)
+	if err != nil {
+		return nil, err
+	}
+	if shardSizeBytes != nil && decodeBlockNum(shardSizeBytes) != index.shardSize {
+		previousShardSize := decodeBlockNum(shardSizeBytes)
+		logger.Warningf("The shard size of the block index changed from %d to %d blocks, rebuilding the index",
+			previousShardSize, index.shardSize)
+		lastBlockIndexed, err := index.getLastBlockIndexed()
+		if err != nil && err != errIndexEmpty {
+			return nil, err
+		}
+		if err == nil {
+			for shardNum := uint64(0); shardNum <= lastBlockIndexed/previousShardSize; shardNum++ {
+				if err := db.Delete(constructShardFilterKey(shardNum), false); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if err := db.Delete(shardedIndexCheckpointKey, true); err != nil {
+			return nil, err
+		}
+		if err := os.RemoveAll(dir); err != nil {
+			return nil, err
+		}
+	}
+	if err := db.Put(indexShardSizeKey, encodeBlockNum(index.shardSize), true); err != nil {
+		return nil, err
+	}
+	return index, nil
+}
+
+func (index *shardedIndex) getLastBlockIndexed() (uint64, error) {
+	blockNumBytes, err := index.db.Get(shardedIndexCheckpointKey)
+	if err != nil {
+		return 0, err
+	}
+	if blockNumBytes == nil {
+		return 0, errIndexEmpty
+	}
+	return decodeBlockNum(blockNumBytes), nil
+}
+
+func (index *shardedIndex) indexBlock(blockIdxInfo *blockIdxInfo) error {
+	if len(index.indexConfig.AttrsToIndex) == 0 {
+		logger.Debug("Not indexing block... as nothing to index")
+		return nil
+	}
+	err := index.withShard(blockIdxInfo.blockNum/index.shardSize, true, func(shard *indexShard) error {
+		return shard.indexBlock(blockIdxInfo)
+	})
+	if err != nil {
+		return err
+	}
+	// The shard is written first, so that a crash in between only indexes the block again
+	if err := index.db.Put(shardedIndexCheckpointKey, encodeBlockNum(blockIdxInfo.blockNum), true); err != nil {
+		return err
+	}
+	if (blockIdxInfo.blockNum+1)%index.shardSize == 0 {
+		// The filter is otherwise built by the first probe needing it
+		if _, err := index.shardFilter(blockIdxInfo.blockNum / index.shardSize); err != nil {
+			logger.Warningf("Error building the filter of the block index shard [%d]: %s", blockIdxInfo.blockNum/index.shardSize, err)
+		}
+	}
+	return nil
+}
+
+func (index *shardedIndex) getBlockLocByHash(blockHash []byte) (*fileLocPointer, error) {
+	var flp *fileLocPointer
+	err := index.probe(blockHash, func(shard *indexShard) (err error) {
+		flp, err = shard.getBlockLocByHash(blockHash)
+		return err
+	})
+	return flp, err
+}
+
+func (index *shardedIndex) getBlockLocByBlockNum(blockNum uint64) (*fileLocPointer, error) {
+	var flp *fileLocPointer
+	err := index.withShard(blockNum/index.shardSize, false, func(shard *indexShard) (err error) {
+		flp, err = shard.getBlockLocByBlockNum(blockNum)
+		return err
+	})
+	return flp, err
+}
+
+func (index *shardedIndex) getTxLoc(txID string) (*fileLocPointer, error) {
+	var flp *fileLocPointer
+	err := index.probe([]byte(txID), func(shard *indexShard) (err error) {
+		flp, err = shard.getTxLoc(txID)
+		return err
+	})
+	return flp, err
+}
+
+func (index *shardedIndex) getTXLocByBlockNumTranNum(blockNum uint64, tranNum uint64) (*fileLocPointer, error) {
+	var flp *fileLocPointer
+	err := index.withShard(blockNum/index.shardSize, false, func(shard *indexShard) (err error) {
+		flp, err = shard.getTXLocByBlockNumTranNum(blockNum, tranNum)
+		return err
+	})
+	return flp, err
+}
+
+func (index *shardedIndex) getBlockLocByTxID(txID string) (*fileLocPointer, error) {
+	var flp *fileLocPointer
+	err := index.probe([]byte(txID), func(shard *indexShard) (err error) {
+		flp, err = shard.getBlockLocByTxID(txID)
+		return err
+	})
+	return flp, err
+}
+
+func (index *shardedIndex) getTxValidationCodeByTxID(txID string) (peer.TxValidationCode, error) {
+	code := peer.TxValidationCode(-1)
+	err := index.probe([]byte(txID), func(shard *indexShard) (err error) {
+		code, err = shard.getTxValidationCodeByTxID(txID)
+		return err
+	})
+	if err != nil {
+		return peer.TxValidationCode(-1), err
+	}
+	return code, nil
+}
+
+// probe calls lookup on the shards from the newest one until it finds what it looks up, skipping the complete
+// shards whose filter does not contain key, the block hash or transaction ID looked up
+func (index *shardedIndex) probe(key []byte, lookup func(shard *indexShard) error) error {
+	lastBlockIndexed, err := index.getLastBlockIndexed()
+	if err == errIndexEmpty {
+		return blkstorage.ErrNotFoundInIndex
+	}
+	if err != nil {
+		return err
+	}
+	lastShardNum := lastBlockIndexed / index.shardSize
+	if (lastBlockIndexed+1)%index.shardSize != 0 {
+		// The last shard is still written, hence has no filter yet
+		if err := index.withShard(lastShardNum, false, lookup); err != blkstorage.ErrNotFoundInIndex {
+			return err
+		}
+		if lastShardNum == 0 {
+			return blkstorage.ErrNotFoundInIndex
+		}
+		lastShardNum--
+	}
+	for shardNum := lastShardNum; ; shardNum-- {
+		shardFilter, err := index.shardFilter(shardNum)
+		if err != nil {
+			return err
+		}
+		if shardFilter == nil || shardKeysFilter.Contains(shardFilter, key) {
+			if err := index.withShard(shardNum, false, lookup); err != blkstorage.ErrNotFoundInIndex {
+				return err
+			}
+		}
+		if shardNum == 0 {
+			return blkstorage.ErrNotFoundInIndex
+		}
+	}
+}
+
+// shardFilter returns the filter of the block hashes and transaction IDs of a complete shard, building it from
+// the shard if it is neither in memory nor in db, or nil if the shard is missing
+func (index *shardedIndex) shardFilter(shardNum uint64) ([]byte, error) {
+	index.filtersLock.Lock()
+	defer index.filtersLock.Unlock()
+	if shardFilter, ok := index.filters[shardNum]; ok {
+		return shardFilter, nil
+	}
+	shardFilter, err := index.db.Get(constructShardFilterKey(shardNum))
+	if err != nil {
+		return nil, err
+	}
+	if shardFilter == nil {
+		err = index.withShard(shardNum, false, func(shard *indexShard) (err error) {
+			shardFilter, err = shard.buildFilter()
+			return err
+		})
+		if err == blkstorage.ErrNotFoundInIndex {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		logger.Debugf("Built the filter of the block index shard [%d], of %d bytes", shardNum, len(shardFilter))
+		if err := index.db.Put(constructShardFilterKey(shardNum), shardFilter, true); err != nil {
+			return nil, err
+		}
+	}
+	index.filters[shardNum] = shardFilter
+	return shardFilter, nil
+}
+
+// buildFilter returns the bloom filter of the block hashes and transaction IDs indexed by the shard
+func (shard *indexShard) buildFilter() ([]byte, error) {
+	generator := shardKeysFilter.NewGenerator()
+	prefixes := []byte{blockHashIdxKeyPrefix}
+	// The transaction IDs are read from the first of their indexes the shard keeps
+	for _, attr := range []struct {
+		attr   blkstorage.IndexableAttr
+		prefix byte
+	}{
+		{blkstorage.IndexableAttrTxID, txIDIdxKeyPrefix},
+		{blkstorage.IndexableAttrBlockTxID, blockTxIDIdxKeyPrefix},
+		{blkstorage.IndexableAttrTxValidationCode, txValidationResultIdxKeyPrefix},
+	} {
+		if shard.indexItemsMap[attr.attr] {
+			prefixes = append(prefixes, attr.prefix)
+			break
+		}
+	}
+	for _, prefix := range prefixes {
+		itr := shard.db.GetIterator([]byte{prefix}, []byte{prefix + 1})
+		for itr.Next() {
+			generator.Add(itr.Key()[1:])
+		}
+		err := itr.Error()
+		itr.Release()
+		if err != nil {
+			return nil, err
+		}
+	}
+	buffer := &leveldbutil.Buffer{}
+	generator.Generate(buffer)
+	return buffer.Bytes(), nil
+}
+
+func constructShardFilterKey(shardNum uint64) []byte {
+	return append([]byte(shardFilterKeyPrefix), encodeBlockNum(shardNum)...)
+}
+
+// withShard calls f with a shard, which is created if missing and create is set. The shard is not closed until
+// f returns. A missing shard is reported as blkstorage.ErrNotFoundInIndex
+func (index *shardedIndex) withShard(shardNum uint64, create bool, f func(shard *indexShard) error) error {
+	index.lock.RLock()
+	shard, ok := index.shards[shardNum]
+	if !ok {
+		index.lock.RUnlock()
+		if err := index.openShard(shardNum, create); err != nil {
+			return err
+		}
+		index.lock.RLock()
+		// the shard may have been closed again by then
+		if shard, ok = index.shards[shardNum]; !ok {
+			index.lock.RUnlock()
+			return index.withShard(shardNum, create, f)
+		}
+	}
+	defer index.lock.RUnlock()
+	atomic.StoreUint64(&shard.lastUsed, atomic.AddUint64(&index.clock, 1))
+	return f(shard)
+}
+
+func (index *shardedIndex) openShard(shardNum uint64, create bool) error {
+	index.lock.Lock()
+	defer index.lock.Unlock()
+	if _, ok := index.shards[shardNum]; ok {
+		return nil
+	}
+	dbPath := filepath.Join(index.dir, fmt.Sprintf("%06d", shardNum))
+	if !create {
+		exists, _, err := util.FileExists(dbPath)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return blkstorage.ErrNotFoundInIndex
+		}
+	}
+	for len(index.shards) >= index.maxOpen {
+		index.closeLeastRecentlyUsed()
+	}
+	logger.Debugf("Opening the block index shard [%s]", dbPath)
+	dbConf := index.dbConf
+	dbConf.DBPath = dbPath
+	provider := leveldbhelper.NewProvider(&dbConf)
+	index.shards[shardNum] = &indexShard{
+		blockIndex: newBlockIndex(index.indexConfig, provider.GetDBHandle("")),
+		provider:   provider,
+	}
+	return nil
+}
+
+func (index *shardedIndex) closeLeastRecentlyUsed() {
+	var lru uint64
+	var lruShard *indexShard
+	for shardNum, shard := range index.shards {
+		if lruShard == nil || atomic.LoadUint64(&shard.lastUsed) < atomic.LoadUint64(&lruShard.lastUsed) {
+			lru, lruShard = shardNum, shard
+		}
+	}
+	logger.Debugf("Closing the block index shard [%d]", lru)
+	lruShard.provider.Close()
+	delete(index.shards, lru)
+}
+
+func (index *shardedIndex) close() {
+	index.lock.Lock()
+	defer index.lock.Unlock()
+	for shardNum, shard := range index.shards {
+		shard.provider.Close()
+		delete(index.shards, shardNum)
+	}
+}
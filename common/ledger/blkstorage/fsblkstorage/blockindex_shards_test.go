@@ -0,0 +1,216 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fsblkstorage
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/common/ledger/util"
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	ledgerUtil "github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/hyperledger/fabric/protos/common"
+	putil "github.com/hyperledger/fabric/protos/utils"
+)
+
+// newScaleTestConf returns a Conf whose block files hold three of the test blocks following the first one
+func newScaleTestConf(t *testing.T, path string, blocks []*common.Block, scaleConf *ScaleConf) *Conf {
+	blockBytes, _, err := serializeBlock(blocks[1])
+	testutil.AssertNoError(t, err, "Error while serializing block")
+	maxFileSize := 3*(len(blockBytes)+len(proto.EncodeVarint(uint64(len(blockBytes))))) + 1
+	return NewConfWithScaleConf(path, maxFileSize, nil, nil, scaleConf)
+}
+
+func TestShardedIndex(t *testing.T) {
+	blocks := testutil.ConstructTestBlocks(t, 10)
+	path := testPath()
+	env := newTestEnv(t, newScaleTestConf(t, path, blocks, &ScaleConf{IndexShardSize: 3, MaxOpenShards: 2}))
+	defer env.Cleanup()
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, "testledger")
+	blkfileMgrWrapper.addBlocks(blocks)
+	blkfileMgr := blkfileMgrWrapper.blockfileMgr
+
+	index, ok := blkfileMgr.index.(*shardedIndex)
+	testutil.AssertEquals(t, ok, true)
+	shards, err := util.ListSubdirs(env.provider.conf.getIndexShardsDir("testledger"))
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, shards, []string{"000000", "000001", "000002", "000003"})
+	testutil.AssertEquals(t, len(index.shards) <= 2, true)
+	lastBlockIndexed, err := index.getLastBlockIndexed()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, lastBlockIndexed, uint64(9))
+
+	testShardedIndexLookups(t, blkfileMgr, blocks)
+	_, err = blkfileMgr.retrieveBlockByNumber(10)
+	testutil.AssertSame(t, err, blkstorage.ErrNotFoundInIndex)
+	_, err = blkfileMgr.retrieveBlockByHash([]byte("unknown"))
+	testutil.AssertSame(t, err, blkstorage.ErrNotFoundInIndex)
+	_, err = blkfileMgr.retrieveTxValidationCodeByTxID("unknown")
+	testutil.AssertSame(t, err, blkstorage.ErrNotFoundInIndex)
+	blkfileMgrWrapper.close()
+
+	// The index is rebuilt when the shard size changes
+	env.provider.Close()
+	env = newTestEnv(t, newScaleTestConf(t, path, blocks, &ScaleConf{IndexShardSize: 5, MaxOpenShards: 1}))
+	blkfileMgrWrapper = newTestBlockfileWrapper(env, "testledger")
+	defer blkfileMgrWrapper.close()
+	shards, err = util.ListSubdirs(env.provider.conf.getIndexShardsDir("testledger"))
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, shards, []string{"000000", "000001"})
+	testShardedIndexLookups(t, blkfileMgrWrapper.blockfileMgr, blocks)
+}
+
+func TestShardedIndexFilters(t *testing.T) {
+	blocks := testutil.ConstructTestBlocks(t, 10)
+	env := newTestEnv(t, newScaleTestConf(t, testPath(), blocks, &ScaleConf{IndexShardSize: 3, MaxOpenShards: 4}))
+	defer env.Cleanup()
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, "testledger")
+	defer blkfileMgrWrapper.close()
+	blkfileMgrWrapper.addBlocks(blocks)
+	index := blkfileMgrWrapper.blockfileMgr.index.(*shardedIndex)
+
+	// The complete shards have a filter, the last one is still written
+	testutil.AssertEquals(t, len(index.filters), 3)
+	for shardNum := uint64(0); shardNum < 3; shardNum++ {
+		shardFilter, err := index.db.Get(constructShardFilterKey(shardNum))
+		testutil.AssertNoError(t, err, "")
+		testutil.AssertEquals(t, shardFilter, index.filters[shardNum])
+	}
+
+	// A miss only opens the last shard
+	index.close()
+	_, err := blkfileMgrWrapper.blockfileMgr.retrieveTransactionByID("unknown")
+	testutil.AssertSame(t, err, blkstorage.ErrNotFoundInIndex)
+	testutil.AssertEquals(t, len(index.shards), 1)
+	_, ok := index.shards[3]
+	testutil.AssertEquals(t, ok, true)
+
+	// The filters missing, e.g. those of an index sharded before the filters, are built by the probes
+	index.filters = make(map[uint64][]byte)
+	for shardNum := uint64(0); shardNum < 3; shardNum++ {
+		testutil.AssertNoError(t, index.db.Delete(constructShardFilterKey(shardNum), true), "")
+	}
+	testShardedIndexLookups(t, blkfileMgrWrapper.blockfileMgr, blocks)
+	testutil.AssertEquals(t, len(index.filters), 3)
+}
+
+// BenchmarkShardedIndexMiss looks up transaction IDs missing from an index of 64 shards, of which 16 are open
+func BenchmarkShardedIndexMiss(b *testing.B) {
+	path := testPath()
+	defer os.RemoveAll(path)
+	provider := leveldbhelper.NewProvider(&leveldbhelper.Conf{DBPath: path + "/index"})
+	defer provider.Close()
+	indexConfig := &blkstorage.IndexConfig{AttrsToIndex: []blkstorage.IndexableAttr{
+		blkstorage.IndexableAttrBlockHash,
+		blkstorage.IndexableAttrBlockNum,
+		blkstorage.IndexableAttrTxID,
+	}}
+	index, err := newShardedIndex(path+"/shards", ScaleConf{IndexShardSize: 10, MaxOpenShards: 16}, indexConfig,
+		leveldbhelper.Conf{}, provider.GetDBHandle(""))
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer index.close()
+
+	metadata := &common.BlockMetadata{Metadata: make([][]byte, len(common.BlockMetadataIndex_name))}
+	metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = ledgerUtil.NewTxValidationFlags(100)
+	for blockNum := uint64(0); blockNum < 640; blockNum++ {
+		blockIdxInfo := &blockIdxInfo{
+			blockNum:  blockNum,
+			blockHash: []byte(fmt.Sprintf("hash%d", blockNum)),
+			flp:       &fileLocPointer{locPointer: locPointer{offset: int(blockNum)}},
+			metadata:  metadata,
+		}
+		for txNum := 0; txNum < 100; txNum++ {
+			blockIdxInfo.txOffsets = append(blockIdxInfo.txOffsets, &txindexInfo{
+				txID: fmt.Sprintf("tx%d-%d", blockNum, txNum),
+				loc:  &locPointer{offset: txNum},
+			})
+		}
+		if err := index.indexBlock(blockIdxInfo); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := index.getTxLoc(fmt.Sprintf("unknown%d", i)); err != blkstorage.ErrNotFoundInIndex {
+			b.Fatal(err)
+		}
+	}
+}
+
+func testShardedIndexLookups(t *testing.T, blkfileMgr *blockfileMgr, blocks []*common.Block) {
+	for i, block := range blocks {
+		b, err := blkfileMgr.retrieveBlockByNumber(uint64(i))
+		testutil.AssertNoError(t, err, fmt.Sprintf("Error while retrieving block [%d] by number", i))
+		testutil.AssertEquals(t, b, block)
+		b, err = blkfileMgr.retrieveBlockByHash(block.Header.Hash())
+		testutil.AssertNoError(t, err, fmt.Sprintf("Error while retrieving block [%d] by hash", i))
+		testutil.AssertEquals(t, b, block)
+
+		flags := ledgerUtil.TxValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+		for j, txEnvelopeBytes := range block.Data.Data {
+			txEnvelope, err := putil.GetEnvelopeFromBlock(txEnvelopeBytes)
+			testutil.AssertNoError(t, err, "")
+			txid, err := extractTxID(txEnvelopeBytes)
+			testutil.AssertNoError(t, err, "")
+
+			tx, err := blkfileMgr.retrieveTransactionByID(txid)
+			testutil.AssertNoError(t, err, fmt.Sprintf("Error while retrieving tx [%s] by ID", txid))
+			testutil.AssertEquals(t, tx, txEnvelope)
+			tx, err = blkfileMgr.retrieveTransactionByBlockNumTranNum(uint64(i), uint64(j))
+			testutil.AssertNoError(t, err, fmt.Sprintf("Error while retrieving tx [%d:%d]", i, j))
+			testutil.AssertEquals(t, tx, txEnvelope)
+			b, err = blkfileMgr.retrieveBlockByTxID(txid)
+			testutil.AssertNoError(t, err, fmt.Sprintf("Error while retrieving block by tx [%s]", txid))
+			testutil.AssertEquals(t, b, block)
+			code, err := blkfileMgr.retrieveTxValidationCodeByTxID(txid)
+			testutil.AssertNoError(t, err, "")
+			testutil.AssertEquals(t, code, flags.Flag(j))
+		}
+	}
+}
+
+func TestMmapReads(t *testing.T) {
+	blocks := testutil.ConstructTestBlocks(t, 10)
+	env := newTestEnv(t, newScaleTestConf(t, testPath(), blocks, &ScaleConf{MmapReads: true, MaxMappedFiles: 2}))
+	defer env.Cleanup()
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, "testledger")
+	defer blkfileMgrWrapper.close()
+	blkfileMgrWrapper.addBlocks(blocks)
+	blkfileMgr := blkfileMgrWrapper.blockfileMgr
+	latestFileNum := blkfileMgr.cpInfo.latestFileChunkSuffixNum
+	testutil.AssertEquals(t, latestFileNum > 2, true)
+
+	blkfileMgrWrapper.testGetBlockByHash(blocks)
+	blkfileMgrWrapper.testGetBlockByNumber(blocks, 0)
+	for _, block := range blocks {
+		txid, err := extractTxID(block.Data.Data[0])
+		testutil.AssertNoError(t, err, "")
+		tx, err := blkfileMgr.retrieveTransactionByID(txid)
+		testutil.AssertNoError(t, err, "")
+		txEnvelope, err := putil.GetEnvelopeFromBlock(block.Data.Data[0])
+		testutil.AssertNoError(t, err, "")
+		testutil.AssertEquals(t, tx, txEnvelope)
+	}
+	// The current file is read from the file, and only the least recently used files are unmapped
+	testutil.AssertEquals(t, len(blkfileMgr.mappedFiles.files), 2)
+	_, mapped := blkfileMgr.mappedFiles.files[latestFileNum]
+	testutil.AssertEquals(t, mapped, false)
+
+	_, err := blkfileMgr.mappedFiles.read(0, 0, 1<<20)
+	testutil.AssertError(t, err, "Expected an error for bytes beyond the end of the file")
+	_, err = blkfileMgr.mappedFiles.readBlockBytes(0, 1<<20)
+	testutil.AssertError(t, err, "Expected an error for a block beyond the end of the file")
+	_, err = blkfileMgr.mappedFiles.readBlockBytes(latestFileNum+1, 0)
+	testutil.AssertError(t, err, "Expected an error for a missing file")
+}
@@ -82,10 +82,17 @@ func (itr *blocksItr) Next() (ledger.QueryResult, error) {
 			return nil, err
 		}
 	}
-	nextBlockBytes, err := itr.stream.nextBlockBytes()
+	nextBlockBytes, placementInfo, err := itr.stream.nextBlockBytesAndPlacementInfo()
 	if err != nil {
 		return nil, err
 	}
+	var location []byte
+	if placementInfo != nil {
+		location = blockLocation(itr.mgr.ledgerID, placementInfo.fileNum, placementInfo.blockStartOffset)
+	}
+	if nextBlockBytes, err = decryptBlockBytes(itr.mgr.conf.keyring, location, nextBlockBytes); err != nil {
+		return nil, err
+	}
 	itr.blockNumToRetrieve++
 	return deserializeBlock(nextBlockBytes)
 }
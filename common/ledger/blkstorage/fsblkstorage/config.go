@@ -16,35 +16,101 @@ limitations under the License.
 
 package fsblkstorage
 
-import "path/filepath"
+import (
+	"path/filepath"
+
+	"github.com/hyperledger/fabric/common/crypto/keyring"
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+)
 
 const (
 	// ChainsDir is the name of the directory containing the channel ledgers.
 	ChainsDir = "chains"
 	// IndexDir is the name of the directory containing all block indexes across ledgers.
-	IndexDir                = "index"
+	IndexDir = "index"
+	// IndexShardsDir is the name of the directory containing the shards of the block indexes, by ledger.
+	IndexShardsDir          = "indexShards"
 	defaultMaxBlockfileSize = 64 * 1024 * 1024 // bytes
+	defaultMaxOpenShards    = 16
+	defaultMaxMappedFiles   = 64
 )
 
 // Conf encapsulates all the configurations for `FsBlockStore`
 type Conf struct {
 	blockStorageDir  string
 	maxBlockfileSize int
+	indexDBConf      leveldbhelper.Conf
+	keyring          *keyring.Keyring
+	scaleConf        ScaleConf
+}
+
+// ScaleConf tunes the block stores of the very large ledgers
+type ScaleConf struct {
+	// IndexShardSize is the number of consecutive blocks indexed by each shard of the block index of a ledger,
+	// each shard being a leveldb of its own. The index is kept in the single leveldb shared by all the ledgers
+	// when 0
+	IndexShardSize uint64
+	// MaxOpenShards is the number of shards of the index of a ledger kept open, 16 when 0
+	MaxOpenShards int
+	// MmapReads reads the blocks and the transactions of the complete block files through memory maps
+	MmapReads bool
+	// MaxMappedFiles is the number of block files of a ledger kept mapped, 64 when 0
+	MaxMappedFiles int
 }
 
 // NewConf constructs new `Conf`.
 // blockStorageDir is the top level folder under which `FsBlockStore` manages its data
 func NewConf(blockStorageDir string, maxBlockfileSize int) *Conf {
+	return NewConfWithIndexDBConf(blockStorageDir, maxBlockfileSize, nil)
+}
+
+// NewConfWithIndexDBConf constructs new `Conf` with the given tuning for the leveldb that holds the block indexes.
+// The DBPath in indexDBConf is ignored as the index is always maintained under blockStorageDir
+func NewConfWithIndexDBConf(blockStorageDir string, maxBlockfileSize int, indexDBConf *leveldbhelper.Conf) *Conf {
 	if maxBlockfileSize <= 0 {
 		maxBlockfileSize = defaultMaxBlockfileSize
 	}
-	return &Conf{blockStorageDir, maxBlockfileSize}
+	conf := &Conf{blockStorageDir: blockStorageDir, maxBlockfileSize: maxBlockfileSize}
+	if indexDBConf != nil {
+		conf.indexDBConf = *indexDBConf
+	}
+	conf.indexDBConf.DBPath = conf.getIndexDir()
+	return conf
+}
+
+// NewConfWithKeyring constructs new `Conf` like NewConfWithIndexDBConf, whose block files are encrypted with the keyring.
+// The blocks written before are still read in plaintext. The index records which blocks are encrypted, and locates the
+// transactions of the encrypted blocks at their block, from which they are read once decrypted
+func NewConfWithKeyring(blockStorageDir string, maxBlockfileSize int, indexDBConf *leveldbhelper.Conf, keyring *keyring.Keyring) *Conf {
+	conf := NewConfWithIndexDBConf(blockStorageDir, maxBlockfileSize, indexDBConf)
+	conf.keyring = keyring
+	return conf
+}
+
+// NewConfWithScaleConf constructs new `Conf` like NewConfWithKeyring, tuned for very large ledgers by scaleConf.
+// Changing the IndexShardSize rebuilds the index of the ledgers from their block files when they are opened
+func NewConfWithScaleConf(blockStorageDir string, maxBlockfileSize int, indexDBConf *leveldbhelper.Conf, keyring *keyring.Keyring, scaleConf *ScaleConf) *Conf {
+	conf := NewConfWithKeyring(blockStorageDir, maxBlockfileSize, indexDBConf, keyring)
+	if scaleConf != nil {
+		conf.scaleConf = *scaleConf
+	}
+	if conf.scaleConf.MaxOpenShards <= 0 {
+		conf.scaleConf.MaxOpenShards = defaultMaxOpenShards
+	}
+	if conf.scaleConf.MaxMappedFiles <= 0 {
+		conf.scaleConf.MaxMappedFiles = defaultMaxMappedFiles
+	}
+	return conf
 }
 
 func (conf *Conf) getIndexDir() string {
 	return filepath.Join(conf.blockStorageDir, IndexDir)
 }
 
+func (conf *Conf) getIndexShardsDir(ledgerid string) string {
+	return filepath.Join(conf.blockStorageDir, IndexShardsDir, ledgerid)
+}
+
 func (conf *Conf) getChainsDir() string {
 	return filepath.Join(conf.blockStorageDir, ChainsDir)
 }
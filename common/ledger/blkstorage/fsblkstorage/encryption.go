@@ -0,0 +1,85 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fsblkstorage
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hyperledger/fabric/common/crypto/keyring"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/protos/common"
+	putil "github.com/hyperledger/fabric/protos/utils"
+)
+
+// blockLocation returns the location of a block in the block files of a ledger, the number of its
+// file and the offset of its length in the file, to which its encryption is bound. An encrypted block
+// copied to another location, in the same ledger or in another one, fails to decrypt
+func blockLocation(ledgerID string, fileNum int, offset int64) []byte {
+	return []byte(fmt.Sprintf("%s/%d/%d", ledgerID, fileNum, offset))
+}
+
+// encryptBlockBytes encrypts the serialized block with the active key of the keyring, if any, for
+// the location it is written to
+func encryptBlockBytes(kr *keyring.Keyring, location []byte, blockBytes []byte) ([]byte, error) {
+	if kr == nil {
+		return blockBytes, nil
+	}
+	return kr.Encrypt(blockBytes, location)
+}
+
+// decryptBlockBytes decrypts the bytes read from the location in the block files, which the blocks
+// written before the encryption was enabled are not
+func decryptBlockBytes(kr *keyring.Keyring, location []byte, blockBytes []byte) ([]byte, error) {
+	if blockBytes == nil || !keyring.IsEncrypted(blockBytes) {
+		return blockBytes, nil
+	}
+	if kr == nil {
+		return nil, errors.New("block is encrypted but no keyring is configured")
+	}
+	return kr.Decrypt(blockBytes, location)
+}
+
+// errTxInEncryptedBlock is returned when a transaction of an encrypted block is read at its offset
+// in the file, which only applies to the plaintext block
+var errTxInEncryptedBlock = errors.New("transaction is located in an encrypted block")
+
+// decryptBlockBytesAt decrypts the bytes of the block of the ledger at lp, which must be encrypted if
+// the index recorded the block as encrypted
+func decryptBlockBytesAt(kr *keyring.Keyring, ledgerID string, lp *fileLocPointer, blockBytes []byte) ([]byte, error) {
+	if lp.encrypted && !keyring.IsEncrypted(blockBytes) {
+		return nil, fmt.Errorf("block at [%s] is indexed as encrypted but is not", lp)
+	}
+	return decryptBlockBytes(kr, blockLocation(ledgerID, lp.fileSuffixNum, int64(lp.offset)), blockBytes)
+}
+
+// The index locates the transactions of the encrypted blocks at the location of their block, from
+// which they are read once the block is decrypted. The block cannot be read without the keyring
+
+func (mgr *blockfileMgr) fetchTransactionEnvelopeByTxID(lp *fileLocPointer, txID string) (*common.Envelope, error) {
+	block, err := mgr.fetchBlock(lp)
+	if err != nil {
+		return nil, err
+	}
+	for _, txEnvelopeBytes := range block.Data.Data {
+		if id, err := extractTxID(txEnvelopeBytes); err == nil && id == txID {
+			return putil.GetEnvelopeFromBlock(txEnvelopeBytes)
+		}
+	}
+	return nil, blkstorage.ErrNotFoundInIndex
+}
+
+func (mgr *blockfileMgr) fetchTransactionEnvelopeByTranNum(lp *fileLocPointer, tranNum uint64) (*common.Envelope, error) {
+	block, err := mgr.fetchBlock(lp)
+	if err != nil {
+		return nil, err
+	}
+	if tranNum >= uint64(len(block.Data.Data)) {
+		return nil, fmt.Errorf("block %d has no transaction %d", block.Header.Number, tranNum)
+	}
+	return putil.GetEnvelopeFromBlock(block.Data.Data[tranNum])
+}
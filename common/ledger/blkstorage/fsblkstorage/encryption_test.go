@@ -0,0 +1,193 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fsblkstorage
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric/common/crypto/keyring"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	putil "github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestKeyring(t *testing.T) *keyring.Keyring {
+	csp, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	require.NoError(t, err)
+	key, err := csp.KeyGen(&bccsp.AES256KeyGenOpts{Temporary: true})
+	require.NoError(t, err)
+	kr, err := keyring.New(csp, "key1", map[string]bccsp.Key{"key1": key})
+	require.NoError(t, err)
+	return kr
+}
+
+func TestBlockfileMgrEncryption(t *testing.T) {
+	path := testPath()
+	defer os.RemoveAll(path)
+	ledgerid := "testLedger"
+	kr := newTestKeyring(t)
+	blocks := testutil.ConstructTestBlocks(t, 10)
+
+	// The blocks written before the encryption is enabled stay in plaintext
+	env := newTestEnv(t, NewConf(path, 0))
+	w := newTestBlockfileWrapper(env, ledgerid)
+	w.addBlocks(blocks[:5])
+	w.close()
+	env.provider.Close()
+
+	env = newTestEnv(t, NewConfWithKeyring(path, 0, nil, kr))
+	w = newTestBlockfileWrapper(env, ledgerid)
+	w.addBlocks(blocks[5:])
+	w.testGetBlockByNumber(blocks, 0)
+	w.testGetBlockByHash(blocks)
+
+	itr, err := w.blockfileMgr.retrieveBlocks(0)
+	require.NoError(t, err)
+	for _, block := range blocks {
+		next, err := itr.Next()
+		require.NoError(t, err)
+		assert.Equal(t, block, next)
+	}
+	itr.Close()
+
+	txID, err := extractTxID(blocks[7].Data.Data[0])
+	require.NoError(t, err)
+	block, err := w.blockfileMgr.retrieveBlockByTxID(txID)
+	assert.NoError(t, err)
+	assert.Equal(t, blocks[7], block)
+	txEnvelope, err := w.blockfileMgr.retrieveTransactionByID(txID)
+	assert.NoError(t, err)
+	assert.Equal(t, blocks[7].Data.Data[0], putil.MarshalOrPanic(txEnvelope))
+	txEnvelope, err = w.blockfileMgr.retrieveTransactionByBlockNumTranNum(2, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, blocks[2].Data.Data[0], putil.MarshalOrPanic(txEnvelope))
+	_, err = w.blockfileMgr.retrieveTransactionByBlockNumTranNum(7, 100)
+	assert.Error(t, err)
+
+	// The index records which blocks are encrypted, and the transactions of the encrypted blocks
+	// are never read at their plaintext offsets
+	loc, err := w.blockfileMgr.index.getTxLoc(txID)
+	require.NoError(t, err)
+	assert.True(t, loc.encrypted)
+	_, err = w.blockfileMgr.fetchTransactionEnvelope(loc)
+	assert.Equal(t, errTxInEncryptedBlock, err)
+	loc, err = w.blockfileMgr.index.getTXLocByBlockNumTranNum(2, 0)
+	require.NoError(t, err)
+	assert.False(t, loc.encrypted)
+	w.blockfileMgr.conf.keyring = nil
+	_, err = w.blockfileMgr.retrieveTransactionByID(txID)
+	assert.EqualError(t, err, "block is encrypted but no keyring is configured", "Should fail closed without the keyring")
+	w.blockfileMgr.conf.keyring = kr
+
+	content, err := ioutil.ReadFile(deriveBlockfilePath(env.provider.conf.getLedgerBlockDir(ledgerid), 0))
+	require.NoError(t, err)
+	assert.True(t, bytes.Contains(content, blocks[2].Data.Data[0]), "Should have written the older blocks in plaintext")
+	assert.False(t, bytes.Contains(content, blocks[7].Data.Data[0]), "Should have encrypted the newer blocks")
+	w.close()
+	env.provider.Close()
+
+	// The checkpoint is rebuilt from the encrypted files once the index is lost
+	os.RemoveAll(env.provider.conf.getIndexDir())
+	env = newTestEnv(t, NewConfWithKeyring(path, 0, nil, kr))
+	w = newTestBlockfileWrapper(env, ledgerid)
+	assert.Equal(t, uint64(9), w.blockfileMgr.cpInfo.lastBlockNumber)
+	w.testGetBlockByNumber(blocks, 0)
+	txEnvelope, err = w.blockfileMgr.retrieveTransactionByID(txID)
+	assert.NoError(t, err, "Should have recorded the encrypted blocks when rebuilding the index")
+	assert.Equal(t, blocks[7].Data.Data[0], putil.MarshalOrPanic(txEnvelope))
+	w.close()
+	env.provider.Close()
+
+	// The ledger cannot be opened without the keyring once it holds encrypted blocks
+	env = newTestEnv(t, NewConf(path, 0))
+	defer env.Cleanup()
+	assert.Panics(t, func() { newTestBlockfileWrapper(env, ledgerid) })
+}
+
+func TestFileLocPointerEncryptedMarker(t *testing.T) {
+	plain := &fileLocPointer{fileSuffixNum: 1, locPointer: locPointer{offset: 2, bytesLength: 3}}
+	b, err := plain.marshal()
+	require.NoError(t, err)
+	assert.Equal(t, []byte{1, 2, 3}, b, "Should keep the encoding of the pointers into plaintext blocks")
+	flp := &fileLocPointer{}
+	require.NoError(t, flp.unmarshal(b))
+	assert.Equal(t, plain, flp)
+
+	encrypted := &fileLocPointer{fileSuffixNum: 1, locPointer: locPointer{offset: 2, bytesLength: 3}, encrypted: true}
+	b, err = encrypted.marshal()
+	require.NoError(t, err)
+	assert.Equal(t, []byte{1, 2, 3, 1}, b)
+	flp = &fileLocPointer{}
+	require.NoError(t, flp.unmarshal(b))
+	assert.Equal(t, encrypted, flp)
+
+	assert.Equal(t, encrypted, newTxLocationPointer(encrypted, &locPointer{offset: 10, bytesLength: 5}), "Should locate the transactions of encrypted blocks at their block")
+
+	_, err = decryptBlockBytesAt(nil, "testLedger", encrypted, []byte("plaintext"))
+	assert.Error(t, err, "Should refuse plaintext bytes at a location indexed as encrypted")
+}
+
+func TestEncryptedBlockBoundToLocation(t *testing.T) {
+	kr := newTestKeyring(t)
+	blockBytes := []byte("block")
+	encrypted, err := encryptBlockBytes(kr, blockLocation("ch1", 1, 100), blockBytes)
+	require.NoError(t, err)
+	decrypted, err := decryptBlockBytes(kr, blockLocation("ch1", 1, 100), encrypted)
+	assert.NoError(t, err)
+	assert.Equal(t, blockBytes, decrypted)
+	for _, location := range [][]byte{blockLocation("ch1", 1, 101), blockLocation("ch1", 2, 100), blockLocation("ch2", 1, 100)} {
+		_, err = decryptBlockBytes(kr, location, encrypted)
+		assert.Error(t, err, "Should not decrypt a block moved to %s", location)
+	}
+}
+
+func TestBlockfileMgrEncryptionAcrossFiles(t *testing.T) {
+	path := testPath()
+	defer os.RemoveAll(path)
+	ledgerid := "testLedger"
+	kr := newTestKeyring(t)
+	blocks := testutil.ConstructTestBlocks(t, 10)
+
+	// The blocks rolled over to the next file are encrypted for their location in that file
+	blockBytes, _, err := serializeBlock(blocks[5])
+	require.NoError(t, err)
+	env := newTestEnv(t, NewConfWithKeyring(path, 3*len(blockBytes), nil, kr))
+	defer env.Cleanup()
+	w := newTestBlockfileWrapper(env, ledgerid)
+	w.addBlocks(blocks)
+	assert.True(t, w.blockfileMgr.cpInfo.latestFileChunkSuffixNum > 0, "Should have rolled over to the next files")
+	w.testGetBlockByNumber(blocks, 0)
+	w.testGetBlockByHash(blocks)
+	itr, err := w.blockfileMgr.retrieveBlocks(0)
+	require.NoError(t, err)
+	for _, block := range blocks {
+		next, err := itr.Next()
+		require.NoError(t, err)
+		assert.Equal(t, block, next)
+	}
+	itr.Close()
+	w.close()
+	env.provider.Close()
+
+	// A block copied over the next one in its file is not decrypted at its new location
+	rootDir := env.provider.conf.getLedgerBlockDir(ledgerid)
+	content, err := ioutil.ReadFile(deriveBlockfilePath(rootDir, 0))
+	require.NoError(t, err)
+	length, n := proto.DecodeVarint(content)
+	first := content[:n+int(length)]
+	require.NoError(t, ioutil.WriteFile(deriveBlockfilePath(rootDir, 0), append(append([]byte{}, first...), first...), 0600))
+	os.RemoveAll(env.provider.conf.getIndexDir())
+	env = newTestEnv(t, NewConfWithKeyring(path, 3*len(blockBytes), nil, kr))
+	assert.Panics(t, func() { newTestBlockfileWrapper(env, ledgerid) }, "Should not index a moved block")
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package fsblkstorage
 
 import (
+	"os"
+
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/util"
 	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
@@ -31,7 +33,8 @@ type FsBlockstoreProvider struct {
 
 // NewProvider constructs a filesystem based block store provider
 func NewProvider(conf *Conf, indexConfig *blkstorage.IndexConfig) blkstorage.BlockStoreProvider {
-	p := leveldbhelper.NewProvider(&leveldbhelper.Conf{DBPath: conf.getIndexDir()})
+	indexDBConf := conf.indexDBConf
+	p := leveldbhelper.NewProvider(&indexDBConf)
 	return &FsBlockstoreProvider{conf, indexConfig, p}
 }
 
@@ -59,6 +62,52 @@ func (p *FsBlockstoreProvider) List() ([]string, error) {
 	return util.ListSubdirs(p.conf.getChainsDir())
 }
 
+// Compact compacts the leveldb that holds the block indexes
+func (p *FsBlockstoreProvider) Compact() error {
+	return p.leveldbProvider.Compact()
+}
+
+// Lost tells whether the blocks of the given ledger are missing from its block files, as if the files were removed
+// or truncated since the blocks were written: the block file of the checkpoint saved in the index is missing or
+// shorter than checkpointed, or, without a checkpoint, there is no block file at all. An empty block storage is
+// lost too, as a ledger holds its genesis block at least. The ledger must not be open
+func (p *FsBlockstoreProvider) Lost(ledgerid string) (bool, error) {
+	rootDir := p.conf.getLedgerBlockDir(ledgerid)
+	mgr := &blockfileMgr{db: p.leveldbProvider.GetDBHandle(ledgerid)}
+	cpInfo, err := mgr.loadCurrentInfo()
+	if err != nil {
+		return false, err
+	}
+	if cpInfo == nil {
+		exists, _, err := util.FileExists(rootDir)
+		if err != nil || !exists {
+			return !exists, err
+		}
+		lastFileNum, err := retrieveLastFileSuffix(rootDir)
+		return lastFileNum == -1, err
+	}
+	if cpInfo.isChainEmpty {
+		return true, nil
+	}
+	exists, size, err := util.FileExists(deriveBlockfilePath(rootDir, cpInfo.latestFileChunkSuffixNum))
+	if err != nil {
+		return false, err
+	}
+	return !exists || size < int64(cpInfo.latestFileChunksize), nil
+}
+
+// Drop removes the block files and the index of the given ledger, the block files first, so that a ledger
+// partially dropped is still lost. The ledger must not be open
+func (p *FsBlockstoreProvider) Drop(ledgerid string) error {
+	if err := os.RemoveAll(p.conf.getLedgerBlockDir(ledgerid)); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(p.conf.getIndexShardsDir(ledgerid)); err != nil {
+		return err
+	}
+	return p.leveldbProvider.GetDBHandle(ledgerid).DeleteAll()
+}
+
 // Close closes the FsBlockstoreProvider
 func (p *FsBlockstoreProvider) Close() {
 	p.leveldbProvider.Close()
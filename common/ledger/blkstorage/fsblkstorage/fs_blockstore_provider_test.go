@@ -17,6 +17,7 @@ limitations under the License.
 package fsblkstorage
 
 import (
+	"os"
 	"testing"
 
 	"fmt"
@@ -55,6 +56,52 @@ func TestMultipleBlockStores(t *testing.T) {
 	checkWithWrongInputs(t, store2, 10)
 }
 
+func TestLostAndDrop(t *testing.T) {
+	env := newTestEnv(t, NewConf(testPath(), 0))
+	defer env.Cleanup()
+	provider := env.provider
+
+	blocks := testutil.ConstructTestBlocks(t, 5)
+	for _, ledgerid := range []string{"ledger1", "ledger2"} {
+		store, _ := provider.OpenBlockStore(ledgerid)
+		for _, b := range blocks {
+			store.AddBlock(b)
+		}
+		store.Shutdown()
+	}
+	lost, err := provider.Lost("ledger1")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, lost, false)
+
+	// The block files are removed, the checkpoint in the index remains
+	testutil.AssertNoError(t, os.RemoveAll(provider.conf.getLedgerBlockDir("ledger1")), "")
+	lost, err = provider.Lost("ledger1")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, lost, true)
+
+	testutil.AssertNoError(t, provider.Drop("ledger1"), "")
+	lost, err = provider.Lost("ledger1")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, lost, true)
+	exists, err := provider.Exists("ledger1")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, exists, false)
+
+	store, _ := provider.OpenBlockStore("ledger1")
+	defer store.Shutdown()
+	bcInfo, _ := store.GetBlockchainInfo()
+	testutil.AssertEquals(t, bcInfo.Height, uint64(0))
+	for _, b := range blocks {
+		testutil.AssertNoError(t, store.AddBlock(b), "")
+	}
+	checkBlocks(t, blocks, store)
+
+	// The other ledgers are left untouched
+	lost, err = provider.Lost("ledger2")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, lost, false)
+}
+
 func checkBlocks(t *testing.T, expectedBlocks []*common.Block, store blkstorage.BlockStore) {
 	bcInfo, _ := store.GetBlockchainInfo()
 	testutil.AssertEquals(t, bcInfo.Height, uint64(len(expectedBlocks)))
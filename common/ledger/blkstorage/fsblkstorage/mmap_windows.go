@@ -0,0 +1,17 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fsblkstorage
+
+import "fmt"
+
+func mmapFile(filePath string) ([]byte, error) {
+	return nil, fmt.Errorf("[%s] cannot be mapped on this platform", filePath)
+}
+
+func munmapFile(data []byte) error {
+	return nil
+}
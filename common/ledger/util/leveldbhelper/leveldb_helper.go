@@ -19,10 +19,12 @@ package leveldbhelper
 import (
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/ledger/util"
 	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/filter"
 	"github.com/syndtr/goleveldb/leveldb/iterator"
 	"github.com/syndtr/goleveldb/leveldb/opt"
 	goleveldbutil "github.com/syndtr/goleveldb/leveldb/util"
@@ -38,8 +40,42 @@ const (
 )
 
 // Conf configuration for `DB`
+// Apart from DBPath, a zero value for any of the fields leaves the corresponding goleveldb default in place
 type Conf struct {
 	DBPath string
+	// BlockCacheCapacity is the capacity (in bytes) of the cache for uncompressed blocks
+	BlockCacheCapacity int
+	// WriteBuffer is the size (in bytes) of the memdb that is flushed to a sorted table on disk when full
+	WriteBuffer int
+	// BloomFilterBits is the number of bits per key used by the bloom filter. A bloom filter is used only if this is set
+	BloomFilterBits int
+	// CompactionTableSize is the limit (in bytes) on the size of a sorted table generated by compaction
+	CompactionTableSize int
+	// CompactionL0Trigger is the number of level-0 tables that triggers a compaction
+	CompactionL0Trigger int
+	// CompactionInterval is the interval at which a full compaction of the db is scheduled. A zero value disables
+	// the scheduled compaction
+	CompactionInterval time.Duration
+	// CompactionThroughput is the limit (in bytes per second) on the throughput of the full compactions of the db,
+	// whether scheduled or triggered on demand. A zero value leaves them unlimited
+	CompactionThroughput int
+}
+
+// compactionChunkSize is the approximate size (in bytes) of the key ranges compacted one at a time
+// when the compaction throughput is limited
+var compactionChunkSize = 4 * 1024 * 1024
+
+func (conf *Conf) options() *opt.Options {
+	dbOpts := &opt.Options{
+		BlockCacheCapacity:  conf.BlockCacheCapacity,
+		WriteBuffer:         conf.WriteBuffer,
+		CompactionTableSize: conf.CompactionTableSize,
+		CompactionL0Trigger: conf.CompactionL0Trigger,
+	}
+	if conf.BloomFilterBits > 0 {
+		dbOpts.Filter = filter.NewBloomFilter(conf.BloomFilterBits)
+	}
+	return dbOpts
 }
 
 // DB - a wrapper on an actual store
@@ -52,6 +88,8 @@ type DB struct {
 	readOpts        *opt.ReadOptions
 	writeOptsNoSync *opt.WriteOptions
 	writeOptsSync   *opt.WriteOptions
+
+	stopCompaction chan struct{}
 }
 
 // CreateDB constructs a `DB`
@@ -76,7 +114,7 @@ func (dbInst *DB) Open() {
 	if dbInst.dbState == opened {
 		return
 	}
-	dbOpts := &opt.Options{}
+	dbOpts := dbInst.conf.options()
 	dbPath := dbInst.conf.DBPath
 	var err error
 	var dirEmpty bool
@@ -88,6 +126,10 @@ func (dbInst *DB) Open() {
 		panic(fmt.Sprintf("Error while trying to open DB: %s", err))
 	}
 	dbInst.dbState = opened
+	if dbInst.conf.CompactionInterval > 0 {
+		dbInst.stopCompaction = make(chan struct{})
+		go dbInst.scheduleCompaction(dbInst.conf.CompactionInterval, dbInst.stopCompaction)
+	}
 }
 
 // Close closes the underlying db
@@ -97,6 +139,10 @@ func (dbInst *DB) Close() {
 	if dbInst.dbState == closed {
 		return
 	}
+	if dbInst.stopCompaction != nil {
+		close(dbInst.stopCompaction)
+		dbInst.stopCompaction = nil
+	}
 	if err := dbInst.db.Close(); err != nil {
 		logger.Errorf("Error while closing DB: %s", err)
 	}
@@ -163,3 +209,74 @@ func (dbInst *DB) WriteBatch(batch *leveldb.Batch, sync bool) error {
 	}
 	return nil
 }
+
+// Compact compacts the entire key range of the underlying db
+func (dbInst *DB) Compact() error {
+	dbInst.mux.Lock()
+	defer dbInst.mux.Unlock()
+	if dbInst.dbState == closed {
+		return fmt.Errorf("DB [%s] is not open", dbInst.conf.DBPath)
+	}
+	logger.Infof("Compacting DB [%s]", dbInst.conf.DBPath)
+	startTime := time.Now()
+	var err error
+	if dbInst.conf.CompactionThroughput > 0 {
+		err = dbInst.compactThrottled(dbInst.conf.CompactionThroughput)
+	} else {
+		err = dbInst.db.CompactRange(goleveldbutil.Range{})
+	}
+	if err != nil {
+		logger.Errorf("Error while compacting DB [%s]: %s", dbInst.conf.DBPath, err)
+		return err
+	}
+	logger.Infof("Compacted DB [%s] in %s", dbInst.conf.DBPath, time.Since(startTime))
+	return nil
+}
+
+// compactThrottled compacts the db one key range of about compactionChunkSize bytes at a time, and
+// waits after each range for as long as compacting it at the given throughput would have taken.
+// goleveldb offers no way to limit the throughput of a single compaction, nor of the compactions
+// it triggers itself
+func (dbInst *DB) compactThrottled(throughput int) error {
+	itr := dbInst.db.NewIterator(nil, &opt.ReadOptions{DontFillCache: true})
+	defer itr.Release()
+	var start []byte
+	size := 0
+	for itr.Next() {
+		size += len(itr.Key()) + len(itr.Value())
+		if size < compactionChunkSize {
+			continue
+		}
+		limit := append([]byte(nil), itr.Key()...)
+		if err := dbInst.compactRange(start, limit, size, throughput); err != nil {
+			return err
+		}
+		start, size = limit, 0
+	}
+	if err := itr.Error(); err != nil {
+		return err
+	}
+	return dbInst.compactRange(start, nil, size, throughput)
+}
+
+func (dbInst *DB) compactRange(start, limit []byte, size int, throughput int) error {
+	startTime := time.Now()
+	if err := dbInst.db.CompactRange(goleveldbutil.Range{Start: start, Limit: limit}); err != nil {
+		return err
+	}
+	time.Sleep(time.Duration(size)*time.Second/time.Duration(throughput) - time.Since(startTime))
+	return nil
+}
+
+func (dbInst *DB) scheduleCompaction(interval time.Duration, stop chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			dbInst.Compact()
+		case <-stop:
+			return
+		}
+	}
+}
@@ -17,9 +17,11 @@ limitations under the License.
 package leveldbhelper
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/hyperledger/fabric/common/ledger/testutil"
 	"github.com/syndtr/goleveldb/leveldb"
@@ -112,7 +114,7 @@ func TestLevelDBHelper(t *testing.T) {
 func TestCreateDBInEmptyDir(t *testing.T) {
 	testutil.AssertNoError(t, os.RemoveAll(testDBPath), "")
 	testutil.AssertNoError(t, os.MkdirAll(testDBPath, 0775), "")
-	db := CreateDB(&Conf{testDBPath})
+	db := CreateDB(&Conf{DBPath: testDBPath})
 	defer db.Close()
 	defer func() {
 		if r := recover(); r != nil {
@@ -128,7 +130,7 @@ func TestCreateDBInNonEmptyDir(t *testing.T) {
 	file, err := os.Create(filepath.Join(testDBPath, "dummyfile.txt"))
 	testutil.AssertNoError(t, err, "")
 	file.Close()
-	db := CreateDB(&Conf{testDBPath})
+	db := CreateDB(&Conf{DBPath: testDBPath})
 	defer db.Close()
 	defer func() {
 		if r := recover(); r == nil {
@@ -137,3 +139,49 @@ func TestCreateDBInNonEmptyDir(t *testing.T) {
 	}()
 	db.Open()
 }
+
+func TestLevelDBHelperCompact(t *testing.T) {
+	testutil.AssertNoError(t, os.RemoveAll(testDBPath), "")
+	defer os.RemoveAll(testDBPath)
+	db := CreateDB(&Conf{DBPath: testDBPath, BloomFilterBits: 10, CompactionInterval: 10 * time.Millisecond})
+	testutil.AssertError(t, db.Compact(), "Expected an error when compacting a closed db")
+
+	db.Open()
+	db.Put([]byte("key1"), []byte("value1"), false)
+	db.Delete([]byte("key1"), false)
+	db.Put([]byte("key2"), []byte("value2"), false)
+	testutil.AssertNoError(t, db.Compact(), "")
+	// let the scheduled compaction run at least once
+	time.Sleep(50 * time.Millisecond)
+
+	val, err := db.Get([]byte("key2"))
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, string(val), "value2")
+	db.Close()
+	// close should stop the scheduled compaction
+	db.Close()
+}
+
+func TestLevelDBHelperCompactThrottled(t *testing.T) {
+	testutil.AssertNoError(t, os.RemoveAll(testDBPath), "")
+	defer os.RemoveAll(testDBPath)
+	defer func(size int) { compactionChunkSize = size }(compactionChunkSize)
+	compactionChunkSize = 1024
+	db := CreateDB(&Conf{DBPath: testDBPath, CompactionThroughput: 100 * 1024})
+	db.Open()
+	defer db.Close()
+	value := make([]byte, 100)
+	for i := 0; i < 100; i++ {
+		db.Put([]byte(fmt.Sprintf("key%03d", i)), value, false)
+	}
+	startTime := time.Now()
+	testutil.AssertNoError(t, db.Compact(), "")
+	// about 10 KB compacted at 100 KB/s
+	testutil.AssertEquals(t, time.Since(startTime) >= 90*time.Millisecond, true)
+
+	for i := 0; i < 100; i++ {
+		val, err := db.Get([]byte(fmt.Sprintf("key%03d", i)))
+		testutil.AssertNoError(t, err, "")
+		testutil.AssertEquals(t, val, value)
+	}
+}
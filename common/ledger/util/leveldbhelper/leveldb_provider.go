@@ -27,6 +27,9 @@ import (
 var dbNameKeySep = []byte{0x00}
 var lastKeyIndicator = byte(0x01)
 
+// deleteAllBatchSize is the number of keys deleted by each batch of DeleteAll
+const deleteAllBatchSize = 1000
+
 // Provider enables to use a single leveldb as multiple logical leveldbs
 type Provider struct {
 	db        *DB
@@ -53,6 +56,11 @@ func (p *Provider) GetDBHandle(dbName string) *DBHandle {
 	return dbHandle
 }
 
+// Compact compacts the underlying leveldb, which is shared by all the handles
+func (p *Provider) Compact() error {
+	return p.db.Compact()
+}
+
 // Close closes the underlying leveldb
 func (p *Provider) Close() {
 	p.db.Close()
@@ -96,6 +104,29 @@ func (h *DBHandle) WriteBatch(batch *UpdateBatch, sync bool) error {
 	return nil
 }
 
+// DeleteAll deletes all the keys of the named db, in batches of bounded size. The keys written concurrently
+// may survive
+func (h *DBHandle) DeleteAll() error {
+	itr := h.GetIterator(nil, nil)
+	defer itr.Release()
+	levelBatch := &leveldb.Batch{}
+	for itr.Next() {
+		// The iterator reuses the buffer of the key
+		levelBatch.Delete(append([]byte(nil), itr.Iterator.Key()...))
+		if levelBatch.Len() < deleteAllBatchSize {
+			continue
+		}
+		if err := h.db.WriteBatch(levelBatch, false); err != nil {
+			return err
+		}
+		levelBatch.Reset()
+	}
+	if err := itr.Error(); err != nil {
+		return err
+	}
+	return h.db.WriteBatch(levelBatch, true)
+}
+
 // GetIterator gets an handle to iterator. The iterator should be released after the use.
 // The resultset contains all the keys that are present in the db between the startKey (inclusive) and the endKey (exclusive).
 // A nil startKey represents the first available key and a nil endKey represent a logical key after the last available key
@@ -0,0 +1,90 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package ledgerbundle exports ranges of the blocks of a channel into signed bundles, which an
+// auditor verifies offline. A bundle carries, along with the blocks, the last config block of the
+// channel as of the first of them and the certificates of the ordering service organizations of
+// that config, and is signed by the exporter.
+//
+// A bundle is self-verifying: it proves that its blocks are chained and signed by the ordering
+// service organizations it carries, and that it is signed by the exporter. The auditor only has to
+// check these certificates, as well as the identity of the exporter, against trusted copies. The
+// validation codes of the transactions are not signed by the ordering service, they are attested
+// by the exporter only.
+package ledgerbundle
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/config"
+	"github.com/hyperledger/fabric/common/crypto"
+	"github.com/hyperledger/fabric/common/inclusionproof"
+	"github.com/hyperledger/fabric/common/util"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+)
+
+// New creates the bundle of consecutive blocks of a channel, whose config block is the last
+// config block of the channel as of the first of the blocks, and signs it
+func New(configBlock *cb.Block, blocks []*cb.Block, signer crypto.LocalSigner) (*cb.LedgerBundle, error) {
+	if len(blocks) == 0 {
+		return nil, fmt.Errorf("no block to export")
+	}
+	for i, block := range blocks {
+		if block == nil || block.Header == nil {
+			return nil, fmt.Errorf("block %d of the bundle has no header", i)
+		}
+		if i > 0 && block.Header.Number != blocks[0].Header.Number+uint64(i) {
+			return nil, fmt.Errorf("block %d follows block %d", block.Header.Number, blocks[i-1].Header.Number)
+		}
+	}
+	if configBlock == nil || configBlock.Header == nil {
+		return nil, fmt.Errorf("config block has no header")
+	}
+	lastConfig, err := utils.GetLastConfigIndexFromBlock(blocks[0])
+	if err != nil {
+		return nil, err
+	}
+	if lastConfig != configBlock.Header.Number {
+		return nil, fmt.Errorf("the last config of block %d is block %d, not block %d", blocks[0].Header.Number, lastConfig, configBlock.Header.Number)
+	}
+
+	channelID, orgs, err := inclusionproof.ConfigOrgs(configBlock, config.OrdererGroupKey)
+	if err != nil {
+		return nil, err
+	}
+	content := &cb.LedgerBundleContent{
+		ChannelId:   channelID,
+		ConfigBlock: configBlock,
+		Blocks:      blocks,
+	}
+	for _, org := range orgs {
+		content.OrdererOrgs = append(content.OrdererOrgs, &cb.LedgerBundleOrg{
+			MspId:             org.Name,
+			RootCerts:         org.RootCerts,
+			IntermediateCerts: org.IntermediateCerts,
+		})
+	}
+	contentBytes, err := proto.Marshal(content)
+	if err != nil {
+		return nil, err
+	}
+
+	shdr, err := signer.NewSignatureHeader()
+	if err != nil {
+		return nil, err
+	}
+	shdrBytes, err := proto.Marshal(shdr)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := signer.Sign(util.ConcatenateBytes(contentBytes, shdrBytes))
+	if err != nil {
+		return nil, err
+	}
+	return &cb.LedgerBundle{Content: contentBytes, SignatureHeader: shdrBytes, Signature: signature}, nil
+}
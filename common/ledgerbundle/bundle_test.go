@@ -0,0 +1,164 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledgerbundle
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	configtxtest "github.com/hyperledger/fabric/common/configtx/test"
+	"github.com/hyperledger/fabric/common/localmsp"
+	"github.com/hyperledger/fabric/common/util"
+	mspmgmt "github.com/hyperledger/fabric/msp/mgmt"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	if err := mspmgmt.LoadDevMsp(); err != nil {
+		fmt.Printf("Could not load the MSP: %s\n", err)
+		os.Exit(-1)
+	}
+	os.Exit(m.Run())
+}
+
+// sign signs the metadata of the block the way the orderer does
+func sign(block *cb.Block, index cb.BlockMetadataIndex, value []byte) {
+	signer := localmsp.NewSigner()
+	sig := &cb.MetadataSignature{SignatureHeader: utils.MarshalOrPanic(utils.NewSignatureHeaderOrPanic(signer))}
+	sig.Signature = utils.SignOrPanic(signer, util.ConcatenateBytes(value, sig.SignatureHeader, block.Header.Bytes()))
+	block.Metadata.Metadata[index] = utils.MarshalOrPanic(&cb.Metadata{Value: value, Signatures: []*cb.MetadataSignature{sig}})
+}
+
+// makeLedger returns the genesis block of a channel followed by numBlocks signed blocks. The
+// block configIndex, if positive, updates the config of the channel
+func makeLedger(t *testing.T, numBlocks int, configIndex uint64) []*cb.Block {
+	genesis, err := configtxtest.MakeGenesisBlock(util.GetTestChainID())
+	assert.NoError(t, err)
+	blocks := []*cb.Block{genesis}
+	lastConfig := uint64(0)
+	for i := 1; i <= numBlocks; i++ {
+		block := cb.NewBlock(uint64(i), blocks[i-1].Header.Hash())
+		block.Data.Data = [][]byte{[]byte(fmt.Sprintf("tx%d", i))}
+		if uint64(i) == configIndex {
+			block.Data.Data = genesis.Data.Data
+			lastConfig = configIndex
+		}
+		block.Header.DataHash = block.Data.Hash()
+		sign(block, cb.BlockMetadataIndex_SIGNATURES, nil)
+		sign(block, cb.BlockMetadataIndex_LAST_CONFIG, utils.MarshalOrPanic(&cb.LastConfig{Index: lastConfig}))
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
+// resign signs the content of a bundle anew, so that its verification checks the content
+func resign(t *testing.T, content *cb.LedgerBundleContent) *cb.LedgerBundle {
+	contentBytes := utils.MarshalOrPanic(content)
+	signer := localmsp.NewSigner()
+	shdr, err := signer.NewSignatureHeader()
+	assert.NoError(t, err)
+	shdrBytes := utils.MarshalOrPanic(shdr)
+	signature, err := signer.Sign(util.ConcatenateBytes(contentBytes, shdrBytes))
+	assert.NoError(t, err)
+	return &cb.LedgerBundle{Content: contentBytes, SignatureHeader: shdrBytes, Signature: signature}
+}
+
+func TestBundle(t *testing.T) {
+	blocks := makeLedger(t, 3, 0)
+
+	for _, start := range []int{0, 1, 2} {
+		bundle, err := New(blocks[0], blocks[start:], localmsp.NewSigner())
+		assert.NoError(t, err)
+
+		// Bundles survive a round trip through their wire format
+		decoded := &cb.LedgerBundle{}
+		assert.NoError(t, proto.Unmarshal(utils.MarshalOrPanic(bundle), decoded))
+		content, exporter, err := Verify(decoded)
+		assert.NoError(t, err, "Bundle of the blocks from %d should verify", start)
+		assert.Equal(t, util.GetTestChainID(), content.ChannelId)
+		assert.Len(t, content.Blocks, len(blocks)-start)
+		assert.Len(t, content.OrdererOrgs, 1)
+		assert.Equal(t, "DEFAULT", exporter.Mspid)
+	}
+
+	_, err := New(blocks[0], nil, localmsp.NewSigner())
+	assert.Error(t, err)
+	_, err = New(blocks[0], []*cb.Block{blocks[1], blocks[3]}, localmsp.NewSigner())
+	assert.Error(t, err, "Should require consecutive blocks")
+	_, err = New(blocks[1], blocks[1:], localmsp.NewSigner())
+	assert.Error(t, err, "Should require the last config block of the first block")
+}
+
+func TestBundleConfigUpdate(t *testing.T) {
+	blocks := makeLedger(t, 4, 2)
+
+	bundle, err := New(blocks[0], blocks[1:], localmsp.NewSigner())
+	assert.NoError(t, err)
+	_, _, err = Verify(bundle)
+	assert.NoError(t, err)
+
+	bundle, err = New(blocks[2], blocks[3:], localmsp.NewSigner())
+	assert.NoError(t, err)
+	_, _, err = Verify(bundle)
+	assert.NoError(t, err, "A config block signed by the orderer should verify")
+
+	_, err = New(blocks[0], blocks[3:], localmsp.NewSigner())
+	assert.Error(t, err, "Should require the last config block of the first block")
+}
+
+func TestVerifyTampering(t *testing.T) {
+	blocks := makeLedger(t, 3, 0)
+
+	tamper := func(f func(content *cb.LedgerBundleContent)) error {
+		bundle, err := New(blocks[0], blocks[1:], localmsp.NewSigner())
+		assert.NoError(t, err)
+		content := &cb.LedgerBundleContent{}
+		assert.NoError(t, proto.Unmarshal(bundle.Content, content))
+		f(content)
+		_, _, err = Verify(resign(t, content))
+		return err
+	}
+
+	assert.NoError(t, tamper(func(content *cb.LedgerBundleContent) {}))
+	assert.NoError(t, tamper(func(content *cb.LedgerBundleContent) {
+		content.Blocks[0].Metadata.Metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER] = []byte{1}
+	}), "The validation codes are not covered by the orderer signatures")
+
+	assert.Error(t, tamper(func(content *cb.LedgerBundleContent) { content.Blocks[1].Data.Data[0] = []byte("forged") }),
+		"A modified transaction should not hash to the data hash")
+	assert.Error(t, tamper(func(content *cb.LedgerBundleContent) {
+		content.Blocks[1].Data.Data[0] = []byte("forged")
+		content.Blocks[1].Header.DataHash = content.Blocks[1].Data.Hash()
+	}), "The orderer signature should not verify over a modified header")
+	assert.Error(t, tamper(func(content *cb.LedgerBundleContent) {
+		content.Blocks = append(content.Blocks[:1], content.Blocks[2:]...)
+	}), "Should require consecutive blocks")
+	assert.Error(t, tamper(func(content *cb.LedgerBundleContent) {
+		content.Blocks[1].Metadata.Metadata[cb.BlockMetadataIndex_SIGNATURES] = nil
+	}))
+	assert.Error(t, tamper(func(content *cb.LedgerBundleContent) {
+		content.Blocks[1].Metadata.Metadata[cb.BlockMetadataIndex_LAST_CONFIG] = nil
+	}))
+	assert.Error(t, tamper(func(content *cb.LedgerBundleContent) { content.Blocks = nil }))
+	assert.Error(t, tamper(func(content *cb.LedgerBundleContent) { content.ChannelId = "other" }))
+	assert.Error(t, tamper(func(content *cb.LedgerBundleContent) { content.OrdererOrgs = nil }))
+	assert.Error(t, tamper(func(content *cb.LedgerBundleContent) { content.OrdererOrgs[0].MspId = "other" }))
+	assert.Error(t, tamper(func(content *cb.LedgerBundleContent) { content.ConfigBlock = blocks[1] }))
+
+	// The signature of the exporter covers the content of the bundle
+	bundle, err := New(blocks[0], blocks[1:], localmsp.NewSigner())
+	assert.NoError(t, err)
+	bundle.Signature = []byte("forged")
+	_, _, err = Verify(bundle)
+	assert.Error(t, err)
+	_, _, err = Verify(&cb.LedgerBundle{Content: []byte("garbage")})
+	assert.Error(t, err)
+}
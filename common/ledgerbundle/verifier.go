@@ -0,0 +1,174 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledgerbundle
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/config"
+	"github.com/hyperledger/fabric/common/inclusionproof"
+	"github.com/hyperledger/fabric/common/util"
+	cb "github.com/hyperledger/fabric/protos/common"
+	mspprotos "github.com/hyperledger/fabric/protos/msp"
+	"github.com/hyperledger/fabric/protos/utils"
+)
+
+// Verify verifies a bundle offline. It checks that the ordering service organizations of the
+// bundle are those of its config block, that its blocks are chained and signed according to the
+// BlockValidation policy of its config block, or of the config blocks among them, and that the
+// bundle is signed by an identity of an MSP of its config block. It returns the content of the bundle
+// and the identity of the exporter
+func Verify(bundle *cb.LedgerBundle) (*cb.LedgerBundleContent, *mspprotos.SerializedIdentity, error) {
+	content := &cb.LedgerBundleContent{}
+	if err := proto.Unmarshal(bundle.Content, content); err != nil {
+		return nil, nil, fmt.Errorf("error unmarshaling the content of the bundle: %s", err)
+	}
+	if len(content.Blocks) == 0 {
+		return nil, nil, fmt.Errorf("bundle has no block")
+	}
+
+	orderers, err := ordererVerifier(content)
+	if err != nil {
+		return nil, nil, err
+	}
+	exporter, err := verifyExporter(bundle, orderers)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := verifyBlock(orderers, content.ConfigBlock); err != nil {
+		return nil, nil, fmt.Errorf("invalid config block: %s", err)
+	}
+	if err := verifyBlocks(orderers, content); err != nil {
+		return nil, nil, err
+	}
+	return content, exporter, nil
+}
+
+// ordererVerifier checks that the ordering service organizations of the bundle are those of its
+// config block, and returns the verifier of the signatures under the config of the block
+func ordererVerifier(content *cb.LedgerBundleContent) (*inclusionproof.Verifier, error) {
+	channelID, orgs, err := inclusionproof.ConfigOrgs(content.ConfigBlock, config.OrdererGroupKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config block: %s", err)
+	}
+	if channelID != content.ChannelId {
+		return nil, fmt.Errorf("config block is for channel %s instead of %s", channelID, content.ChannelId)
+	}
+	if len(orgs) != len(content.OrdererOrgs) {
+		return nil, fmt.Errorf("bundle has %d orderer organizations instead of the %d of its config block", len(content.OrdererOrgs), len(orgs))
+	}
+	for i, org := range orgs {
+		bundleOrg := content.OrdererOrgs[i]
+		if !proto.Equal(bundleOrg, &cb.LedgerBundleOrg{MspId: org.Name, RootCerts: org.RootCerts, IntermediateCerts: org.IntermediateCerts}) {
+			return nil, fmt.Errorf("orderer organization %s of the bundle differs from the one of its config block", bundleOrg.MspId)
+		}
+	}
+
+	v, err := inclusionproof.NewVerifier(content.ConfigBlock)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config block: %s", err)
+	}
+	return v, nil
+}
+
+// verifyExporter checks the signature of the bundle by an identity of an MSP of its config block,
+// and returns this identity
+func verifyExporter(bundle *cb.LedgerBundle, verifier *inclusionproof.Verifier) (*mspprotos.SerializedIdentity, error) {
+	shdr, err := utils.GetSignatureHeader(bundle.SignatureHeader)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifier.VerifyCreator(shdr.Creator, util.ConcatenateBytes(bundle.Content, bundle.SignatureHeader), bundle.Signature); err != nil {
+		return nil, fmt.Errorf("invalid signature of the bundle: %s", err)
+	}
+	exporter := &mspprotos.SerializedIdentity{}
+	if err := proto.Unmarshal(shdr.Creator, exporter); err != nil {
+		return nil, fmt.Errorf("error unmarshaling the exporter identity: %s", err)
+	}
+	return exporter, nil
+}
+
+// verifyBlocks checks that the blocks of the bundle are chained, that their last config is the
+// config block of the bundle, or a config block among them, and that they are signed according
+// to the BlockValidation policy of their last config
+func verifyBlocks(orderers *inclusionproof.Verifier, content *cb.LedgerBundleContent) error {
+	configHeader := content.ConfigBlock.Header
+	lastConfig := configHeader.Number
+	previous := configHeader
+	for i, block := range content.Blocks {
+		if block.Header == nil || block.Data == nil || block.Metadata == nil {
+			return fmt.Errorf("block %d of the bundle is incomplete", i)
+		}
+		number := block.Header.Number
+		if i > 0 && number != previous.Number+1 {
+			return fmt.Errorf("block %d follows block %d", number, previous.Number)
+		}
+		if number == previous.Number+1 && !bytes.Equal(block.Header.PreviousHash, previous.Hash()) {
+			return fmt.Errorf("block %d is not chained to block %d", number, previous.Number)
+		}
+		if number == configHeader.Number && !proto.Equal(block.Header, configHeader) {
+			return fmt.Errorf("block %d differs from the config block", number)
+		}
+		previous = block.Header
+
+		if err := verifyBlock(orderers, block); err != nil {
+			return err
+		}
+		if number == 0 {
+			// the genesis block is not signed by the ordering service
+			continue
+		}
+		value, err := orderers.VerifyMetadata(block.Header, metadata(block, cb.BlockMetadataIndex_LAST_CONFIG))
+		if err != nil {
+			return fmt.Errorf("invalid last config of block %d: %s", number, err)
+		}
+		lc := &cb.LastConfig{}
+		if err := proto.Unmarshal(value, lc); err != nil {
+			return fmt.Errorf("error unmarshaling the last config of block %d: %s", number, err)
+		}
+		switch lc.Index {
+		case lastConfig:
+		case number:
+			// the block updates the config of the channel, whose ordering service organizations
+			// sign the following blocks
+			if orderers, err = inclusionproof.NewVerifier(block); err != nil {
+				return fmt.Errorf("invalid config block %d: %s", number, err)
+			}
+			lastConfig = number
+		default:
+			return fmt.Errorf("the last config of block %d is block %d instead of block %d", number, lc.Index, lastConfig)
+		}
+	}
+	return nil
+}
+
+// verifyBlock checks that the data of the block hashes to its header, and that its header is
+// signed by the ordering service, unless it is the genesis block
+func verifyBlock(orderers *inclusionproof.Verifier, block *cb.Block) error {
+	if block.Header == nil || block.Data == nil {
+		return fmt.Errorf("block is incomplete")
+	}
+	if !bytes.Equal(block.Data.Hash(), block.Header.DataHash) {
+		return fmt.Errorf("the data of block %d does not hash to its header", block.Header.Number)
+	}
+	if block.Header.Number == 0 {
+		return nil
+	}
+	if _, err := orderers.VerifyMetadata(block.Header, metadata(block, cb.BlockMetadataIndex_SIGNATURES)); err != nil {
+		return err
+	}
+	return nil
+}
+
+func metadata(block *cb.Block, index cb.BlockMetadataIndex) []byte {
+	if block.Metadata == nil || len(block.Metadata.Metadata) <= int(index) {
+		return nil
+	}
+	return block.Metadata.Metadata[index]
+}
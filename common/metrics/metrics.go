@@ -0,0 +1,23 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package metrics holds the runtime metrics of a node. They are published as the
+// "metrics" expvar variable, and so served in JSON at /debug/vars by the profiling
+// service of the node when it is enabled.
+package metrics
+
+import (
+	"expvar"
+
+	gometrics "github.com/rcrowley/go-metrics"
+)
+
+// Registry holds the metrics of the node
+var Registry = gometrics.NewRegistry()
+
+func init() {
+	expvar.Publish("metrics", expvar.Func(func() interface{} { return Registry }))
+}
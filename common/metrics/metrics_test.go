@@ -0,0 +1,24 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package metrics
+
+import (
+	"encoding/json"
+	"expvar"
+	"testing"
+
+	gometrics "github.com/rcrowley/go-metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublished(t *testing.T) {
+	gometrics.GetOrRegisterGauge("test.gauge", Registry).Update(42)
+
+	var published map[string]map[string]int64
+	assert.NoError(t, json.Unmarshal([]byte(expvar.Get("metrics").String()), &published))
+	assert.Equal(t, map[string]int64{"value": 42}, published["test.gauge"])
+}
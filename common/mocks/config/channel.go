@@ -30,6 +30,8 @@ type Channel struct {
 	BlockDataHashingStructureWidthVal uint32
 	// OrdererAddressesVal is returned as the result of OrdererAddresses()
 	OrdererAddressesVal []string
+	// ConsortiumNameVal is returned as the result of ConsortiumName()
+	ConsortiumNameVal string
 }
 
 // HashingAlgorithm returns the HashingAlgorithmVal if set, otherwise a fake simple hash function
@@ -49,3 +51,8 @@ func (scm *Channel) BlockDataHashingStructureWidth() uint32 {
 func (scm *Channel) OrdererAddresses() []string {
 	return scm.OrdererAddressesVal
 }
+
+// ConsortiumName returns the ConsortiumNameVal
+func (scm *Channel) ConsortiumName() string {
+	return scm.ConsortiumNameVal
+}
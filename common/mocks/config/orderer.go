@@ -27,16 +27,22 @@ import (
 type Orderer struct {
 	// ConsensusTypeVal is returned as the result of ConsensusType()
 	ConsensusTypeVal string
+	// ConsensusStateVal is returned as the result of ConsensusState()
+	ConsensusStateVal ab.ConsensusType_State
 	// BatchSizeVal is returned as the result of BatchSize()
 	BatchSizeVal *ab.BatchSize
 	// BatchTimeoutVal is returned as the result of BatchTimeout()
 	BatchTimeoutVal time.Duration
+	// BlockScheduleVal is returned as the result of BlockSchedule()
+	BlockScheduleVal config.BlockSchedule
 	// KafkaBrokersVal is returned as the result of KafkaBrokers()
 	KafkaBrokersVal []string
 	// MaxChannelsCountVal is returns as the result of MaxChannelsCount()
 	MaxChannelsCountVal uint64
 	// OrganizationsVal is returned as the result of Organizations()
 	OrganizationsVal map[string]config.Org
+	// CapabilitiesVal are the capabilities for which HasCapability() returns true
+	CapabilitiesVal []string
 }
 
 // ConsensusType returns the ConsensusTypeVal
@@ -44,6 +50,11 @@ func (scm *Orderer) ConsensusType() string {
 	return scm.ConsensusTypeVal
 }
 
+// ConsensusState returns the ConsensusStateVal
+func (scm *Orderer) ConsensusState() ab.ConsensusType_State {
+	return scm.ConsensusStateVal
+}
+
 // BatchSize returns the BatchSizeVal
 func (scm *Orderer) BatchSize() *ab.BatchSize {
 	return scm.BatchSizeVal
@@ -54,6 +65,11 @@ func (scm *Orderer) BatchTimeout() time.Duration {
 	return scm.BatchTimeoutVal
 }
 
+// BlockSchedule returns the BlockScheduleVal
+func (scm *Orderer) BlockSchedule() config.BlockSchedule {
+	return scm.BlockScheduleVal
+}
+
 // KafkaBrokers returns the KafkaBrokersVal
 func (scm *Orderer) KafkaBrokers() []string {
 	return scm.KafkaBrokersVal
@@ -68,3 +84,13 @@ func (scm *Orderer) MaxChannelsCount() uint64 {
 func (scm *Orderer) Organizations() map[string]config.Org {
 	return scm.OrganizationsVal
 }
+
+// HasCapability returns whether the capability is in CapabilitiesVal
+func (scm *Orderer) HasCapability(capability string) bool {
+	for _, enabled := range scm.CapabilitiesVal {
+		if enabled == capability {
+			return true
+		}
+	}
+	return false
+}
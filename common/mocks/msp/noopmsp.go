@@ -17,6 +17,8 @@ limitations under the License.
 package msp
 
 import (
+	"time"
+
 	m "github.com/hyperledger/fabric/msp"
 	"github.com/hyperledger/fabric/protos/msp"
 )
@@ -111,6 +113,10 @@ func (id *noopidentity) GetOrganizationalUnits() []*m.OUIdentifier {
 	return nil
 }
 
+func (id *noopidentity) ExpiresAt() time.Time {
+	return time.Time{}
+}
+
 func (id *noopidentity) Verify(msg []byte, sig []byte) error {
 	return nil
 }
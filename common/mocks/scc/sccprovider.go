@@ -23,17 +23,19 @@ import (
 )
 
 type MocksccProviderFactory struct {
-	Qe   *lm.MockQueryExecutor
-	QErr error
+	Qe           *lm.MockQueryExecutor
+	QErr         error
+	Capabilities []string
 }
 
 func (c *MocksccProviderFactory) NewSystemChaincodeProvider() sysccprovider.SystemChaincodeProvider {
-	return &mocksccProviderImpl{Qe: c.Qe, QErr: c.QErr}
+	return &mocksccProviderImpl{Qe: c.Qe, QErr: c.QErr, Capabilities: c.Capabilities}
 }
 
 type mocksccProviderImpl struct {
-	Qe   *lm.MockQueryExecutor
-	QErr error
+	Qe           *lm.MockQueryExecutor
+	QErr         error
+	Capabilities []string
 }
 
 func (c *mocksccProviderImpl) IsSysCC(name string) bool {
@@ -51,3 +53,12 @@ func (c *mocksccProviderImpl) IsSysCCAndNotInvokableExternal(name string) bool {
 func (c *mocksccProviderImpl) GetQueryExecutorForLedger(cid string) (ledger.QueryExecutor, error) {
 	return c.Qe, c.QErr
 }
+
+func (c *mocksccProviderImpl) HasCapability(cid, capability string) bool {
+	for _, enabled := range c.Capabilities {
+		if enabled == capability {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package policies
+
+import (
+	cb "github.com/hyperledger/fabric/protos/common"
+)
+
+// Explainer is implemented by the policies which can explain their decisions
+type Explainer interface {
+	// Explain evaluates the policy as Evaluate does, and returns the decision tree of the evaluation
+	Explain(signatureSet []*cb.SignedData) *cb.PolicyDecision
+}
+
+// Explain returns the decision of the policy over the signature set, with the decisions of its rules
+// if the policy is an Explainer, or else only its outcome
+func Explain(policy Policy, signatureSet []*cb.SignedData) *cb.PolicyDecision {
+	if explainer, ok := policy.(Explainer); ok {
+		return explainer.Explain(signatureSet)
+	}
+	decision := &cb.PolicyDecision{Satisfied: true}
+	if err := policy.Evaluate(signatureSet); err != nil {
+		decision.Satisfied = false
+		decision.Error = err.Error()
+	}
+	return decision
+}
+
+// ExplainPath returns the decision of the policy with the given path of the manager over the
+// signature set, and false if the manager has no such policy
+func ExplainPath(manager Manager, path string, signatureSet []*cb.SignedData) (*cb.PolicyDecision, bool) {
+	policy, ok := manager.GetPolicy(path)
+	if !ok {
+		return nil, false
+	}
+	decision := Explain(policy, signatureSet)
+	decision.Policy = path
+	return decision, true
+}
@@ -28,6 +28,8 @@ type implicitMetaPolicy struct {
 	conf        *cb.ImplicitMetaPolicy
 	threshold   int
 	subPolicies []Policy
+	// subPolicyPaths are the paths of the sub-policies, relative to the manager of the policy
+	subPolicyPaths []string
 }
 
 // NewPolicy creates a new policy based on the policy bytes
@@ -44,9 +46,11 @@ func newImplicitMetaPolicy(data []byte) (*implicitMetaPolicy, error) {
 
 func (imp *implicitMetaPolicy) initialize(config *policyConfig) {
 	imp.subPolicies = make([]Policy, len(config.managers))
+	imp.subPolicyPaths = make([]string, len(config.managers))
 	i := 0
-	for _, manager := range config.managers {
+	for name, manager := range config.managers {
 		imp.subPolicies[i], _ = manager.GetPolicy(imp.conf.SubPolicy)
+		imp.subPolicyPaths[i] = name + PathSeparator + imp.conf.SubPolicy
 		i++
 	}
 
@@ -81,3 +85,23 @@ func (imp *implicitMetaPolicy) Evaluate(signatureSet []*cb.SignedData) error {
 	}
 	return fmt.Errorf("Failed to reach implicit threshold of %d sub-policies, required %d remaining", imp.threshold, remaining)
 }
+
+// Explain evaluates all the sub-policies, rather than stopping once the threshold is reached, so that the
+// decision explains each of them
+func (imp *implicitMetaPolicy) Explain(signatureSet []*cb.SignedData) *cb.PolicyDecision {
+	decision := &cb.PolicyDecision{Rule: fmt.Sprintf("%s %s", imp.conf.Rule, imp.conf.SubPolicy)}
+	remaining := imp.threshold
+	for i, policy := range imp.subPolicies {
+		subDecision := Explain(policy, signatureSet)
+		subDecision.Policy = imp.subPolicyPaths[i]
+		if subDecision.Satisfied && remaining > 0 {
+			remaining--
+		}
+		decision.SubDecisions = append(decision.SubDecisions, subDecision)
+	}
+	decision.Satisfied = remaining == 0
+	if !decision.Satisfied {
+		decision.Error = fmt.Sprintf("Failed to reach implicit threshold of %d sub-policies, required %d remaining", imp.threshold, remaining)
+	}
+	return decision
+}
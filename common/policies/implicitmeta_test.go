@@ -99,3 +99,30 @@ func TestImplicitMetaMajority(t *testing.T) {
 	assert.Error(t, runPolicyTest(cb.ImplicitMetaPolicy_MAJORITY, 10, 0))
 	assert.NoError(t, runPolicyTest(cb.ImplicitMetaPolicy_MAJORITY, 0, 0))
 }
+
+func TestImplicitMetaExplain(t *testing.T) {
+	imp, err := newImplicitMetaPolicy(utils.MarshalOrPanic(&cb.ImplicitMetaPolicy{
+		Rule:      cb.ImplicitMetaPolicy_MAJORITY,
+		SubPolicy: TestPolicyName,
+	}))
+	assert.NoError(t, err)
+	imp.initialize(&policyConfig{
+		managers: makeManagers(3, 1),
+	})
+
+	decision := Explain(imp, nil)
+	assert.False(t, decision.Satisfied)
+	assert.Equal(t, "MAJORITY "+TestPolicyName, decision.Rule)
+	assert.Equal(t, imp.Evaluate(nil).Error(), decision.Error)
+	assert.Len(t, decision.SubDecisions, 3, "Should have explained all the sub-policies")
+	satisfied := 0
+	for _, subDecision := range decision.SubDecisions {
+		assert.Contains(t, subDecision.Policy, PathSeparator+TestPolicyName)
+		if subDecision.Satisfied {
+			satisfied++
+		} else {
+			assert.Equal(t, "Reject", subDecision.Rule)
+		}
+	}
+	assert.Equal(t, 1, satisfied)
+}
@@ -56,6 +56,9 @@ const (
 	// ChannelApplicationAdmins is the label for the channel's application admin policy
 	ChannelApplicationAdmins = PathSeparator + ChannelPrefix + PathSeparator + ApplicationPrefix + PathSeparator + "Admins"
 
+	// ChannelOrdererAdmins is the label for the channel's orderer admin policy
+	ChannelOrdererAdmins = PathSeparator + ChannelPrefix + PathSeparator + OrdererPrefix + PathSeparator + "Admins"
+
 	// BlockValidation is the label for the policy which should validate the block signatures for the channel
 	BlockValidation = PathSeparator + ChannelPrefix + PathSeparator + OrdererPrefix + PathSeparator + "BlockValidation"
 )
@@ -163,6 +166,10 @@ func (rp rejectPolicy) Evaluate(signedData []*cb.SignedData) error {
 	return fmt.Errorf("No such policy type: %s", rp)
 }
 
+func (rp rejectPolicy) Explain(signedData []*cb.SignedData) *cb.PolicyDecision {
+	return &cb.PolicyDecision{Rule: "Reject", Error: rp.Evaluate(signedData).Error()}
+}
+
 // Basepath returns the basePath the manager was instnatiated with
 func (pm *ManagerImpl) BasePath() string {
 	return pm.basePath
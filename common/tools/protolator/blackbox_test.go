@@ -23,6 +23,12 @@ import (
 	genesisconfig "github.com/hyperledger/fabric/common/configtx/tool/localconfig"
 	"github.com/hyperledger/fabric/common/configtx/tool/provisional"
 	. "github.com/hyperledger/fabric/common/tools/protolator"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/rwset"
+	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/stretchr/testify/assert"
@@ -68,3 +74,43 @@ func TestGenesisBlock(t *testing.T) {
 	bidirectionalMarshal(t, gb)
 
 }
+
+func TestPayloadData(t *testing.T) {
+	results := &rwset.TxReadWriteSet{
+		NsRwset: []*rwset.NsReadWriteSet{{
+			Namespace: "mycc",
+			Rwset: utils.MarshalOrPanic(&kvrwset.KVRWSet{
+				Writes: []*kvrwset.KVWrite{{Key: "key", Value: []byte("value")}},
+			}),
+		}},
+	}
+	prp := &pb.ProposalResponsePayload{
+		Extension: utils.MarshalOrPanic(&pb.ChaincodeAction{Results: utils.MarshalOrPanic(results)}),
+	}
+	ccap := &pb.ChaincodeActionPayload{
+		Action: &pb.ChaincodeEndorsedAction{ProposalResponsePayload: utils.MarshalOrPanic(prp)},
+	}
+	tx := &pb.Transaction{
+		Actions: []*pb.TransactionAction{{Payload: utils.MarshalOrPanic(ccap)}},
+	}
+
+	for name, payload := range map[string]*cb.Payload{
+		"EndorserTransaction": {
+			Header: &cb.Header{ChannelHeader: utils.MarshalOrPanic(&cb.ChannelHeader{Type: int32(cb.HeaderType_ENDORSER_TRANSACTION)})},
+			Data:   utils.MarshalOrPanic(tx),
+		},
+		"SeekInfo": {
+			Header: &cb.Header{ChannelHeader: utils.MarshalOrPanic(&cb.ChannelHeader{Type: int32(cb.HeaderType_DELIVER_SEEK_INFO)})},
+			Data:   utils.MarshalOrPanic(&ab.SeekInfo{Start: &ab.SeekPosition{Type: &ab.SeekPosition_Oldest{Oldest: &ab.SeekOldest{}}}}),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			bidirectionalMarshal(t, payload)
+		})
+	}
+
+	// The writes of the transaction are decoded
+	var buffer bytes.Buffer
+	assert.NoError(t, DeepMarshalJSON(&buffer, tx))
+	assert.Contains(t, buffer.String(), `"key": "key"`)
+}
@@ -27,6 +27,8 @@ import (
 )
 
 func TestPlainDynamicMsg(t *testing.T) {
+	defer func(factories []protoFieldFactory) { fieldFactories = factories }(fieldFactories)
+
 	fromPrefix := "from"
 	toPrefix := "to"
 	tppff := &testProtoPlainFieldFactory{
@@ -61,6 +63,8 @@ func TestPlainDynamicMsg(t *testing.T) {
 }
 
 func TestMapDynamicMsg(t *testing.T) {
+	defer func(factories []protoFieldFactory) { fieldFactories = factories }(fieldFactories)
+
 	fromPrefix := "from"
 	toPrefix := "to"
 	tppff := &testProtoPlainFieldFactory{
@@ -98,6 +102,8 @@ func TestMapDynamicMsg(t *testing.T) {
 }
 
 func TestSliceDynamicMsg(t *testing.T) {
+	defer func(factories []protoFieldFactory) { fieldFactories = factories }(fieldFactories)
+
 	fromPrefix := "from"
 	toPrefix := "to"
 	tppff := &testProtoPlainFieldFactory{
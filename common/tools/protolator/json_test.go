@@ -61,6 +61,8 @@ func (tpff *testProtoPlainFieldFactory) NewProtoField(msg proto.Message, fieldNa
 }
 
 func TestSimpleMsgPlainField(t *testing.T) {
+	defer func(factories []protoFieldFactory) { fieldFactories = factories }(fieldFactories)
+
 	fromPrefix := "from"
 	toPrefix := "to"
 	tppff := &testProtoPlainFieldFactory{
@@ -125,6 +127,8 @@ func (tpff *testProtoMapFieldFactory) NewProtoField(msg proto.Message, fieldName
 }
 
 func TestSimpleMsgMapField(t *testing.T) {
+	defer func(factories []protoFieldFactory) { fieldFactories = factories }(fieldFactories)
+
 	fromPrefix := "from"
 	toPrefix := "to"
 	tpmff := &testProtoMapFieldFactory{
@@ -189,6 +193,8 @@ func (tpff *testProtoSliceFieldFactory) NewProtoField(msg proto.Message, fieldNa
 }
 
 func TestSimpleMsgSliceField(t *testing.T) {
+	defer func(factories []protoFieldFactory) { fieldFactories = factories }(fieldFactories)
+
 	fromPrefix := "from"
 	toPrefix := "to"
 	tpsff := &testProtoSliceFieldFactory{
@@ -232,6 +238,8 @@ func (tpff testProtoFailFactory) NewProtoField(msg proto.Message, fieldName stri
 }
 
 func TestFailFactory(t *testing.T) {
+	defer func(factories []protoFieldFactory) { fieldFactories = factories }(fieldFactories)
+
 	fieldFactories = []protoFieldFactory{&testProtoFailFactory{}}
 
 	var buffer bytes.Buffer
@@ -26,6 +26,8 @@ import (
 )
 
 func TestPlainNestedMsg(t *testing.T) {
+	defer func(factories []protoFieldFactory) { fieldFactories = factories }(fieldFactories)
+
 	fromPrefix := "from"
 	toPrefix := "to"
 	tppff := &testProtoPlainFieldFactory{
@@ -57,6 +59,8 @@ func TestPlainNestedMsg(t *testing.T) {
 }
 
 func TestMapNestedMsg(t *testing.T) {
+	defer func(factories []protoFieldFactory) { fieldFactories = factories }(fieldFactories)
+
 	fromPrefix := "from"
 	toPrefix := "to"
 	tppff := &testProtoPlainFieldFactory{
@@ -91,6 +95,8 @@ func TestMapNestedMsg(t *testing.T) {
 }
 
 func TestSliceNestedMsg(t *testing.T) {
+	defer func(factories []protoFieldFactory) { fieldFactories = factories }(fieldFactories)
+
 	fromPrefix := "from"
 	toPrefix := "to"
 	tppff := &testProtoPlainFieldFactory{
@@ -37,6 +37,8 @@ func extractSimpleMsgPlainField(source []byte) string {
 }
 
 func TestPlainStaticallyOpaqueMsg(t *testing.T) {
+	defer func(factories []protoFieldFactory) { fieldFactories = factories }(fieldFactories)
+
 	fromPrefix := "from"
 	toPrefix := "to"
 	tppff := &testProtoPlainFieldFactory{
@@ -68,6 +70,8 @@ func TestPlainStaticallyOpaqueMsg(t *testing.T) {
 }
 
 func TestMapStaticallyOpaqueMsg(t *testing.T) {
+	defer func(factories []protoFieldFactory) { fieldFactories = factories }(fieldFactories)
+
 	fromPrefix := "from"
 	toPrefix := "to"
 	tppff := &testProtoPlainFieldFactory{
@@ -102,6 +106,8 @@ func TestMapStaticallyOpaqueMsg(t *testing.T) {
 }
 
 func TestSliceStaticallyOpaqueMsg(t *testing.T) {
+	defer func(factories []protoFieldFactory) { fieldFactories = factories }(fieldFactories)
+
 	fromPrefix := "from"
 	toPrefix := "to"
 	tppff := &testProtoPlainFieldFactory{
@@ -37,6 +37,8 @@ func extractNestedMsgPlainField(source []byte) string {
 }
 
 func TestPlainVariablyOpaqueMsg(t *testing.T) {
+	defer func(factories []protoFieldFactory) { fieldFactories = factories }(fieldFactories)
+
 	fromPrefix := "from"
 	toPrefix := "to"
 	tppff := &testProtoPlainFieldFactory{
@@ -71,6 +73,8 @@ func TestPlainVariablyOpaqueMsg(t *testing.T) {
 }
 
 func TestMapVariablyOpaqueMsg(t *testing.T) {
+	defer func(factories []protoFieldFactory) { fieldFactories = factories }(fieldFactories)
+
 	fromPrefix := "from"
 	toPrefix := "to"
 	tppff := &testProtoPlainFieldFactory{
@@ -108,6 +112,8 @@ func TestMapVariablyOpaqueMsg(t *testing.T) {
 }
 
 func TestSliceVariablyOpaqueMsg(t *testing.T) {
+	defer func(factories []protoFieldFactory) { fieldFactories = factories }(fieldFactories)
+
 	fromPrefix := "from"
 	toPrefix := "to"
 	tppff := &testProtoPlainFieldFactory{
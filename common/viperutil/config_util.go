@@ -117,7 +117,7 @@ func customDecodeHook() mapstructure.DecodeHookFunc {
 
 func byteSizeDecodeHook() mapstructure.DecodeHookFunc {
 	return func(f reflect.Kind, t reflect.Kind, data interface{}) (interface{}, error) {
-		if f != reflect.String || t != reflect.Uint32 {
+		if f != reflect.String || (t != reflect.Uint32 && t != reflect.Uint64) {
 			return data, nil
 		}
 		raw := data.(string)
@@ -141,7 +141,7 @@ func byteSizeDecodeHook() mapstructure.DecodeHookFunc {
 			case "k":
 				size = size << 10
 			}
-			if size > math.MaxUint32 {
+			if t == reflect.Uint32 && size > math.MaxUint32 {
 				return size, fmt.Errorf("value '%s' overflows uint32", raw)
 			}
 			return size, nil
@@ -267,19 +267,24 @@ func kafkaVersionDecodeHook() mapstructure.DecodeHookFunc {
 			return data, nil
 		}
 
-		v, err := version.NewVersion(data.(string))
-		if err != nil {
-			return nil, fmt.Errorf("Unable to parse Kafka version: %s", err)
-		}
+		return ParseKafkaVersion(data.(string))
+	}
+}
 
-		for kafkaVersion, constraints := range kafkaVersionConstraints {
-			if constraints.Check(v) {
-				return kafkaVersion, nil
-			}
-		}
+// ParseKafkaVersion returns the supported Kafka protocol version matching a version string such as 0.10.2.0
+func ParseKafkaVersion(data string) (sarama.KafkaVersion, error) {
+	v, err := version.NewVersion(data)
+	if err != nil {
+		return sarama.KafkaVersion{}, fmt.Errorf("Unable to parse Kafka version: %s", err)
+	}
 
-		return nil, fmt.Errorf("Unsupported Kafka version: '%s'", data)
+	for kafkaVersion, constraints := range kafkaVersionConstraints {
+		if constraints.Check(v) {
+			return kafkaVersion, nil
+		}
 	}
+
+	return sarama.KafkaVersion{}, fmt.Errorf("Unsupported Kafka version: '%s'", data)
 }
 
 // EnhancedExactUnmarshal is intended to unmarshal a config file into a structure
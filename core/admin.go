@@ -17,8 +17,17 @@ limitations under the License.
 package core
 
 import (
+	"fmt"
+
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/core/audit"
+	"github.com/hyperledger/fabric/core/chaincode"
+	"github.com/hyperledger/fabric/core/ledger/cceventmgmt"
+	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
+	"github.com/hyperledger/fabric/core/peer"
+	cb "github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	"golang.org/x/net/context"
 )
@@ -28,11 +37,13 @@ var log = flogging.MustGetLogger("server")
 // NewAdminServer creates and returns a Admin service instance.
 func NewAdminServer() *ServerAdmin {
 	s := new(ServerAdmin)
+	s.policyManagers = peer.NewChannelPolicyManagerGetter()
 	return s
 }
 
 // ServerAdmin implementation of the Admin service for the Peer
 type ServerAdmin struct {
+	policyManagers policies.ChannelPolicyManagerGetter
 }
 
 // GetStatus reports the status of the server
@@ -60,14 +71,84 @@ func (*ServerAdmin) GetModuleLogLevel(ctx context.Context, request *pb.LogLevelR
 // SetModuleLogLevel sets the logging level for the specified module
 func (*ServerAdmin) SetModuleLogLevel(ctx context.Context, request *pb.LogLevelRequest) (*pb.LogLevelResponse, error) {
 	logLevelString, err := flogging.SetModuleLevel(request.LogModule, request.LogLevel)
+	audit.Record(audit.OperationSetLogLevel, "", audit.InvokerFromContext(ctx),
+		map[string]string{"module": request.LogModule, "level": request.LogLevel}, err)
 	logResponse := &pb.LogLevelResponse{LogModule: request.LogModule, LogLevel: logLevelString}
 	return logResponse, err
 }
 
 // RevertLogLevels reverts the log levels for all modules to the level
 // defined at the end of peer startup.
-func (*ServerAdmin) RevertLogLevels(context.Context, *empty.Empty) (*empty.Empty, error) {
+func (*ServerAdmin) RevertLogLevels(ctx context.Context, _ *empty.Empty) (*empty.Empty, error) {
 	err := flogging.RevertToPeerStartupLevels()
+	audit.Record(audit.OperationRevertLogLevels, "", audit.InvokerFromContext(ctx), nil, err)
 
 	return &empty.Empty{}, err
 }
+
+// CompactLedgers triggers a compaction of the local databases backing the
+// ledgers of the peer
+func (*ServerAdmin) CompactLedgers(ctx context.Context, _ *empty.Empty) (*empty.Empty, error) {
+	err := ledgermgmt.Compact()
+	audit.Record(audit.OperationCompactLedgers, "", audit.InvokerFromContext(ctx), nil, err)
+
+	return &empty.Empty{}, err
+}
+
+// ExplainPolicy returns the decision tree of the evaluation of a policy of a
+// channel over the signed data of an envelope, to debug authorization failures
+func (s *ServerAdmin) ExplainPolicy(ctx context.Context, request *pb.PolicyExplanationRequest) (*cb.PolicyDecision, error) {
+	if request.Envelope == nil {
+		return nil, fmt.Errorf("no envelope to evaluate the policy over")
+	}
+	signedData, err := request.Envelope.AsSignedData()
+	if err != nil {
+		return nil, fmt.Errorf("failed getting the signed data of the envelope: %s", err)
+	}
+	policyManager, ok := s.policyManagers.Manager(request.ChannelId)
+	if !ok {
+		return nil, fmt.Errorf("channel %s not found", request.ChannelId)
+	}
+	decision, ok := policies.ExplainPath(policyManager, request.Policy, signedData)
+	if !ok {
+		return nil, fmt.Errorf("policy %s not found in channel %s", request.Policy, request.ChannelId)
+	}
+	return decision, nil
+}
+
+// GetIndexDeployments returns the status of the deployment of the indexes shipped
+// in the packages of the chaincodes deployed on a channel
+func (*ServerAdmin) GetIndexDeployments(ctx context.Context, request *pb.IndexDeploymentsRequest) (*pb.IndexDeployments, error) {
+	return &pb.IndexDeployments{Deployments: cceventmgmt.GetMgr().Deployments(request.ChannelId)}, nil
+}
+
+// BuildChaincodeImages builds the images of the chaincodes installed on the peer, or
+// of the chaincode requested, ahead of their first launch
+func (*ServerAdmin) BuildChaincodeImages(ctx context.Context, request *pb.ChaincodeImagesRequest) (*pb.ChaincodeImages, error) {
+	chaincodeSupport := chaincode.GetChain()
+	if chaincodeSupport == nil {
+		return nil, fmt.Errorf("chaincode support is not initialized")
+	}
+	images, err := chaincodeSupport.BuildImages(ctx, request.ChaincodeName, request.ChaincodeVersion)
+	audit.Record(audit.OperationBuildImages, "", audit.InvokerFromContext(ctx),
+		map[string]string{"name": request.ChaincodeName, "version": request.ChaincodeVersion}, err)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ChaincodeImages{Images: images}, nil
+}
+
+// RemoveStaleChaincodeImages removes the images built by the peer of the chaincodes
+// which are no longer installed on the peer
+func (*ServerAdmin) RemoveStaleChaincodeImages(ctx context.Context, _ *empty.Empty) (*pb.ChaincodeImages, error) {
+	chaincodeSupport := chaincode.GetChain()
+	if chaincodeSupport == nil {
+		return nil, fmt.Errorf("chaincode support is not initialized")
+	}
+	images, err := chaincodeSupport.RemoveStaleImages(ctx)
+	audit.Record(audit.OperationRemoveImages, "", audit.InvokerFromContext(ctx), nil, err)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ChaincodeImages{Images: images}, nil
+}
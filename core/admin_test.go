@@ -18,12 +18,17 @@ package core
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/hyperledger/fabric/common/flogging"
+	mockpolicies "github.com/hyperledger/fabric/common/mocks/policies"
+	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/core/testutil"
+	cb "github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -68,3 +73,45 @@ func TestLoggingCalls(t *testing.T) {
 	assert.Equal(t, flogging.DefaultLevel(), logResponse.LogLevel, "log level should have been the default")
 	assert.Nil(t, err, "Error should have been nil")
 }
+
+type mockPolicyManagerGetter map[string]policies.Manager
+
+func (m mockPolicyManagerGetter) Manager(channelID string) (policies.Manager, bool) {
+	manager, ok := m[channelID]
+	return manager, ok
+}
+
+func TestExplainPolicy(t *testing.T) {
+	server := &ServerAdmin{policyManagers: mockPolicyManagerGetter{
+		"mychannel": &mockpolicies.Manager{PolicyMap: map[string]policies.Policy{
+			policies.ChannelApplicationWriters: &mockpolicies.Policy{Err: fmt.Errorf("not a writer")},
+		}},
+	}}
+	env := &cb.Envelope{Payload: utils.MarshalOrPanic(&cb.Payload{Header: &cb.Header{
+		SignatureHeader: utils.MarshalOrPanic(&cb.SignatureHeader{Creator: []byte("creator")}),
+	}})}
+
+	decision, err := server.ExplainPolicy(context.Background(), &pb.PolicyExplanationRequest{
+		ChannelId: "mychannel",
+		Policy:    policies.ChannelApplicationWriters,
+		Envelope:  env,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, &cb.PolicyDecision{Policy: policies.ChannelApplicationWriters, Error: "not a writer"}, decision)
+
+	for _, request := range []*pb.PolicyExplanationRequest{
+		{ChannelId: "mychannel", Policy: policies.ChannelApplicationWriters},
+		{ChannelId: "mychannel", Policy: policies.ChannelApplicationWriters, Envelope: &cb.Envelope{Payload: []byte("garbage")}},
+		{ChannelId: "otherchannel", Policy: policies.ChannelApplicationWriters, Envelope: env},
+		{ChannelId: "mychannel", Policy: policies.ChannelApplicationAdmins, Envelope: env},
+	} {
+		_, err := server.ExplainPolicy(context.Background(), request)
+		assert.Error(t, err)
+	}
+}
+
+func TestGetIndexDeployments(t *testing.T) {
+	deployments, err := NewAdminServer().GetIndexDeployments(context.Background(), &pb.IndexDeploymentsRequest{ChannelId: "nochannel"})
+	assert.NoError(t, err)
+	assert.Empty(t, deployments.Deployments)
+}
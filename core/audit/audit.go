@@ -0,0 +1,298 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package audit records the administrative operations performed on the peer
+// in an append-only trail of signed, hash chained json entries, one per line,
+// suitable for ingestion by log collectors.
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric/common/flogging"
+)
+
+var logger = flogging.MustGetLogger("audit")
+
+// The administrative operations recorded in the audit trail
+const (
+	OperationJoinChannel          = "JoinChannel"
+	OperationSetLogLevel          = "SetLogLevel"
+	OperationRevertLogLevels      = "RevertLogLevels"
+	OperationCompactLedgers       = "CompactLedgers"
+	OperationInstallChaincode     = "InstallChaincode"
+	OperationInstantiateChaincode = "InstantiateChaincode"
+	OperationUpgradeChaincode     = "UpgradeChaincode"
+	OperationBuildImages          = "BuildChaincodeImages"
+	OperationRemoveImages         = "RemoveChaincodeImages"
+)
+
+// backupTimeFormat is the format of the suffix appended to the name of rotated audit files
+const backupTimeFormat = "20060102T150405.000000000"
+
+// Conf configures the audit trail
+type Conf struct {
+	// Path of the active audit file
+	Path string
+	// MaxFileSize is the size in bytes beyond which the active audit file is rotated, 0 disables rotation
+	MaxFileSize int64
+	// MaxBackups is the number of rotated audit files retained, 0 retains all of them
+	MaxBackups int
+}
+
+// Signer signs the entries of the audit trail
+type Signer interface {
+	Sign(message []byte) ([]byte, error)
+}
+
+// Entry is a single record of the audit trail
+type Entry struct {
+	Sequence  uint64            `json:"seq"`
+	Timestamp time.Time         `json:"timestamp"`
+	Operation string            `json:"operation"`
+	ChannelID string            `json:"channelId,omitempty"`
+	Invoker   *Invoker          `json:"invoker,omitempty"`
+	Details   map[string]string `json:"details,omitempty"`
+	Error     string            `json:"error,omitempty"`
+	// PrevHash is the SHA-256 hash of the previous line of the trail
+	PrevHash []byte `json:"prevHash,omitempty"`
+	// Signature is computed over the json encoding of the entry with an empty signature
+	Signature []byte `json:"signature,omitempty"`
+}
+
+// Trail appends entries to the audit files
+type Trail struct {
+	lock     sync.Mutex
+	conf     Conf
+	signer   Signer
+	file     *os.File
+	size     int64
+	sequence uint64
+	prevHash []byte
+}
+
+// NewTrail opens the audit trail described by conf. The sequence number and the hash chain are resumed
+// from the last entry of the active audit file, if any
+func NewTrail(conf *Conf, signer Signer) (*Trail, error) {
+	if conf.Path == "" {
+		return nil, fmt.Errorf("audit file path must be specified")
+	}
+	if err := os.MkdirAll(filepath.Dir(conf.Path), 0755); err != nil {
+		return nil, err
+	}
+	t := &Trail{conf: *conf, signer: signer}
+	if err := t.resume(); err != nil {
+		return nil, err
+	}
+	if err := t.openFile(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (t *Trail) resume() error {
+	file, err := os.Open(t.conf.Path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var lastLine []byte
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) != 0 {
+			lastLine = append(lastLine[:0], scanner.Bytes()...)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if lastLine == nil {
+		return nil
+	}
+	last := &Entry{}
+	if err := json.Unmarshal(lastLine, last); err != nil {
+		return fmt.Errorf("error resuming audit trail from [%s]: %s", t.conf.Path, err)
+	}
+	t.sequence = last.Sequence
+	t.prevHash = hash(lastLine)
+	return nil
+}
+
+func (t *Trail) openFile() error {
+	file, err := os.OpenFile(t.conf.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	t.file = file
+	t.size = info.Size()
+	return nil
+}
+
+// Append signs the entry, chains it to the previous one and writes it to the active audit file.
+// The sequence number, the timestamp, the hash and the signature of the entry are set by Append
+func (t *Trail) Append(entry *Entry) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.file == nil {
+		return fmt.Errorf("audit trail is closed")
+	}
+
+	entry.Sequence = t.sequence + 1
+	entry.Timestamp = time.Now().UTC()
+	entry.PrevHash = t.prevHash
+	entry.Signature = nil
+	if t.signer != nil {
+		unsigned, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		if entry.Signature, err = t.signer.Sign(unsigned); err != nil {
+			return fmt.Errorf("error signing audit entry: %s", err)
+		}
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	if t.conf.MaxFileSize > 0 && t.size > 0 && t.size+int64(len(line))+1 > t.conf.MaxFileSize {
+		if err := t.rotate(); err != nil {
+			return fmt.Errorf("error rotating audit file: %s", err)
+		}
+	}
+	n, err := t.file.Write(append(line, '\n'))
+	t.size += int64(n)
+	if err != nil {
+		return err
+	}
+	if err := t.file.Sync(); err != nil {
+		return err
+	}
+	t.sequence = entry.Sequence
+	t.prevHash = hash(line)
+	return nil
+}
+
+func (t *Trail) rotate() error {
+	if err := t.file.Close(); err != nil {
+		return err
+	}
+	t.file = nil
+	backup := t.conf.Path + "." + time.Now().UTC().Format(backupTimeFormat)
+	if err := os.Rename(t.conf.Path, backup); err != nil {
+		// Keep appending to the active file rather than losing the entries
+		if openErr := t.openFile(); openErr != nil {
+			logger.Errorf("Error reopening audit file: %s", openErr)
+		}
+		return err
+	}
+	logger.Infof("Rotated audit file to [%s]", backup)
+	if err := t.removeOldBackups(); err != nil {
+		logger.Warningf("Error removing old audit files: %s", err)
+	}
+	return t.openFile()
+}
+
+func (t *Trail) removeOldBackups() error {
+	if t.conf.MaxBackups <= 0 {
+		return nil
+	}
+	backups, err := filepath.Glob(t.conf.Path + ".*")
+	if err != nil {
+		return err
+	}
+	// The timestamp suffixes sort chronologically
+	sort.Strings(backups)
+	for len(backups) > t.conf.MaxBackups {
+		if err := os.Remove(backups[0]); err != nil {
+			return err
+		}
+		backups = backups[1:]
+	}
+	return nil
+}
+
+// Close closes the active audit file
+func (t *Trail) Close() error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.file == nil {
+		return nil
+	}
+	err := t.file.Close()
+	t.file = nil
+	return err
+}
+
+func hash(line []byte) []byte {
+	h := sha256.Sum256(line)
+	return h[:]
+}
+
+var (
+	defaultTrailLock sync.RWMutex
+	defaultTrail     *Trail
+)
+
+// Init opens the audit trail to which Record appends. Until Init is invoked, Record only logs the operations
+func Init(conf *Conf, signer Signer) error {
+	t, err := NewTrail(conf, signer)
+	if err != nil {
+		return err
+	}
+	defaultTrailLock.Lock()
+	defer defaultTrailLock.Unlock()
+	if defaultTrail != nil {
+		defaultTrail.Close()
+	}
+	defaultTrail = t
+	logger.Infof("Audit trail is recorded in [%s]", conf.Path)
+	return nil
+}
+
+// Record appends an entry for the given operation to the audit trail. opErr is the outcome of the operation.
+// Failures to record are logged and do not affect the outcome of the operation
+func Record(operation string, channelID string, invoker *Invoker, details map[string]string, opErr error) {
+	entry := &Entry{
+		Operation: operation,
+		ChannelID: channelID,
+		Invoker:   invoker,
+		Details:   details,
+	}
+	if opErr != nil {
+		entry.Error = opErr.Error()
+	}
+
+	defaultTrailLock.RLock()
+	defer defaultTrailLock.RUnlock()
+	if defaultTrail == nil {
+		logger.Debugf("Audit trail not initialized, operation [%s] on channel [%s] by %s not recorded", operation, channelID, invoker)
+		return
+	}
+	if err := defaultTrail.Append(entry); err != nil {
+		logger.Errorf("Error recording operation [%s] on channel [%s] in the audit trail: %s", operation, channelID, err)
+	}
+}
@@ -0,0 +1,151 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/protos/msp"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+const testDir = "/tmp/fabric/audittest"
+
+type mockSigner struct{}
+
+func (mockSigner) Sign(message []byte) ([]byte, error) {
+	return hash(append([]byte("signed"), message...)), nil
+}
+
+func readLines(t *testing.T, path string) [][]byte {
+	file, err := os.Open(path)
+	assert.NoError(t, err)
+	defer file.Close()
+	var lines [][]byte
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, append([]byte(nil), scanner.Bytes()...))
+	}
+	return lines
+}
+
+func unmarshalEntry(t *testing.T, line []byte) *Entry {
+	entry := &Entry{}
+	assert.NoError(t, json.Unmarshal(line, entry))
+	return entry
+}
+
+func TestTrailAppendAndResume(t *testing.T) {
+	os.RemoveAll(testDir)
+	defer os.RemoveAll(testDir)
+	conf := &Conf{Path: filepath.Join(testDir, "audit.log")}
+
+	trail, err := NewTrail(conf, mockSigner{})
+	assert.NoError(t, err)
+	assert.NoError(t, trail.Append(&Entry{Operation: OperationJoinChannel, ChannelID: "ch1", Invoker: &Invoker{MSPID: "Org1MSP"}}))
+	assert.NoError(t, trail.Append(&Entry{Operation: OperationSetLogLevel, Details: map[string]string{"module": "gossip"}}))
+	assert.NoError(t, trail.Close())
+	assert.Error(t, trail.Append(&Entry{Operation: OperationCompactLedgers}), "Should not append to a closed trail")
+
+	// The sequence and the hash chain are resumed after a restart
+	trail, err = NewTrail(conf, mockSigner{})
+	assert.NoError(t, err)
+	assert.NoError(t, trail.Append(&Entry{Operation: OperationCompactLedgers, Error: "failed"}))
+	assert.NoError(t, trail.Close())
+
+	lines := readLines(t, conf.Path)
+	assert.Len(t, lines, 3)
+	var prevHash []byte
+	for i, line := range lines {
+		entry := unmarshalEntry(t, line)
+		assert.Equal(t, uint64(i+1), entry.Sequence)
+		assert.Equal(t, prevHash, entry.PrevHash)
+		signature := entry.Signature
+		entry.Signature = nil
+		unsigned, _ := json.Marshal(entry)
+		expectedSignature, _ := mockSigner{}.Sign(unsigned)
+		assert.Equal(t, expectedSignature, signature)
+		prevHash = hash(line)
+	}
+	assert.Equal(t, "ch1", unmarshalEntry(t, lines[0]).ChannelID)
+	assert.Equal(t, "Org1MSP", unmarshalEntry(t, lines[0]).Invoker.MSPID)
+}
+
+func TestTrailRotation(t *testing.T) {
+	os.RemoveAll(testDir)
+	defer os.RemoveAll(testDir)
+	conf := &Conf{Path: filepath.Join(testDir, "audit.log"), MaxFileSize: 300, MaxBackups: 2}
+
+	trail, err := NewTrail(conf, nil)
+	assert.NoError(t, err)
+	defer trail.Close()
+	for i := 0; i < 10; i++ {
+		assert.NoError(t, trail.Append(&Entry{Operation: OperationSetLogLevel, Details: map[string]string{"module": "gossip", "level": "debug"}}))
+	}
+
+	backups, err := filepath.Glob(conf.Path + ".*")
+	assert.NoError(t, err)
+	assert.Len(t, backups, 2, "Should have retained only the configured number of rotated files")
+	info, err := os.Stat(conf.Path)
+	assert.NoError(t, err)
+	assert.True(t, info.Size() <= conf.MaxFileSize)
+
+	// The hash chain spans the rotated files
+	lastRotated, err := ioutil.ReadFile(backups[1])
+	assert.NoError(t, err)
+	rotatedLines := bytes.Split(bytes.TrimSpace(lastRotated), []byte("\n"))
+	activeLines := readLines(t, conf.Path)
+	assert.Equal(t, hash(rotatedLines[len(rotatedLines)-1]), unmarshalEntry(t, activeLines[0]).PrevHash)
+	assert.Equal(t, uint64(10), unmarshalEntry(t, activeLines[len(activeLines)-1]).Sequence)
+}
+
+func TestRecord(t *testing.T) {
+	os.RemoveAll(testDir)
+	defer os.RemoveAll(testDir)
+
+	// Recording before initialization is a no-op
+	Record(OperationJoinChannel, "ch1", nil, nil, nil)
+
+	conf := &Conf{Path: filepath.Join(testDir, "audit.log")}
+	assert.NoError(t, Init(conf, mockSigner{}))
+	defer func() {
+		defaultTrail.Close()
+		defaultTrail = nil
+	}()
+	Record(OperationJoinChannel, "ch2", &Invoker{Address: "127.0.0.1:1234"}, nil, errors.New("bad block"))
+
+	lines := readLines(t, conf.Path)
+	assert.Len(t, lines, 1)
+	entry := unmarshalEntry(t, lines[0])
+	assert.Equal(t, OperationJoinChannel, entry.Operation)
+	assert.Equal(t, "ch2", entry.ChannelID)
+	assert.Equal(t, "127.0.0.1:1234", entry.Invoker.Address)
+	assert.Equal(t, "bad block", entry.Error)
+	assert.NotNil(t, entry.Signature)
+}
+
+func TestNewTrailWithoutPath(t *testing.T) {
+	_, err := NewTrail(&Conf{}, nil)
+	assert.Error(t, err)
+}
+
+func TestInvoker(t *testing.T) {
+	creator, _ := proto.Marshal(&msp.SerializedIdentity{Mspid: "Org1MSP", IdBytes: []byte("not a certificate")})
+	assert.Equal(t, &Invoker{MSPID: "Org1MSP"}, invokerFromCreator(creator))
+	assert.Equal(t, &Invoker{}, invokerFromCreator([]byte("garbage")))
+	assert.Equal(t, &Invoker{}, InvokerFromSignedProposal(&pb.SignedProposal{ProposalBytes: []byte("garbage")}))
+	assert.Equal(t, "Org1MSP 127.0.0.1:1234", (&Invoker{MSPID: "Org1MSP", Address: "127.0.0.1:1234"}).String())
+}
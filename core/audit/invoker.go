@@ -0,0 +1,87 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package audit
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/protos/msp"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/peer"
+)
+
+// Invoker identifies the originator of an administrative operation
+type Invoker struct {
+	MSPID      string `json:"mspId,omitempty"`
+	CommonName string `json:"commonName,omitempty"`
+	Address    string `json:"address,omitempty"`
+}
+
+// String returns a compact description of the invoker
+func (i *Invoker) String() string {
+	if i == nil {
+		return "<unknown>"
+	}
+	var parts []string
+	for _, p := range []string{i.MSPID, i.CommonName, i.Address} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// InvokerFromSignedProposal identifies the creator of a signed proposal. The fields which cannot be
+// extracted are left empty
+func InvokerFromSignedProposal(sp *pb.SignedProposal) *Invoker {
+	invoker := &Invoker{}
+	if sp == nil {
+		return invoker
+	}
+	prop, err := utils.GetProposal(sp.ProposalBytes)
+	if err != nil {
+		return invoker
+	}
+	hdr, err := utils.GetHeader(prop.Header)
+	if err != nil {
+		return invoker
+	}
+	shdr, err := utils.GetSignatureHeader(hdr.SignatureHeader)
+	if err != nil {
+		return invoker
+	}
+	return invokerFromCreator(shdr.Creator)
+}
+
+func invokerFromCreator(creator []byte) *Invoker {
+	invoker := &Invoker{}
+	sID := &msp.SerializedIdentity{}
+	if err := proto.Unmarshal(creator, sID); err != nil {
+		return invoker
+	}
+	invoker.MSPID = sID.Mspid
+	if block, _ := pem.Decode(sID.IdBytes); block != nil {
+		if cert, err := x509.ParseCertificate(block.Bytes); err == nil {
+			invoker.CommonName = cert.Subject.CommonName
+		}
+	}
+	return invoker
+}
+
+// InvokerFromContext identifies the remote end of the gRPC call of ctx by its address
+func InvokerFromContext(ctx context.Context) *Invoker {
+	invoker := &Invoker{}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		invoker.Address = p.Addr.String()
+	}
+	return invoker
+}
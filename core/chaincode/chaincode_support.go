@@ -181,6 +181,11 @@ func NewChaincodeSupport(getCCEndpoint func() (*pb.PeerEndpoint, error), userrun
 		theChaincodeSupport.keepalive = time.Duration(t) * time.Second
 	}
 
+	if rt := viper.GetDuration("chaincode.reconnectTimeout"); rt > 0 {
+		chaincodeLogger.Debugf("Setting reconnect timeout value to %s", rt)
+		theChaincodeSupport.reconnectTimeout = rt
+	}
+
 	//default chaincode execute timeout is 30 secs
 	execto := time.Duration(30) * time.Second
 	if eto := viper.GetDuration("chaincode.executetimeout"); eto <= time.Duration(1)*time.Second {
@@ -192,6 +197,8 @@ func NewChaincodeSupport(getCCEndpoint func() (*pb.PeerEndpoint, error), userrun
 
 	theChaincodeSupport.executetimeout = execto
 
+	theChaincodeSupport.maxAppMetrics = viper.GetInt("chaincode.metrics.maxPerChaincode")
+
 	viper.SetEnvPrefix("CORE")
 	viper.AutomaticEnv()
 	replacer := strings.NewReplacer(".", "_")
@@ -235,10 +242,12 @@ type ChaincodeSupport struct {
 	peerTLSKeyFile    string
 	peerTLSSvrHostOrd string
 	keepalive         time.Duration
+	reconnectTimeout  time.Duration
 	chaincodeLogLevel string
 	shimLogLevel      string
 	logFormat         string
 	executetimeout    time.Duration
+	maxAppMetrics     int
 	userRunsCC        bool
 	peerTLS           bool
 }
@@ -256,6 +265,42 @@ func newDuplicateChaincodeHandlerError(chaincodeHandler *Handler) error {
 	return &DuplicateChaincodeHandlerError{ChaincodeID: chaincodeHandler.ChaincodeID}
 }
 
+// ExecuteErrorCode tells why a chaincode could not execute a transaction
+type ExecuteErrorCode int
+
+const (
+	// ExecuteTimeout means the chaincode did not complete the transaction within the execute timeout
+	ExecuteTimeout ExecuteErrorCode = iota + 1
+
+	// ChaincodeUnavailable means the chaincode lost its stream with the peer and did not register again
+	// within the reconnect timeout. The transaction may succeed once the chaincode is launched again
+	ChaincodeUnavailable
+)
+
+// ExecuteError is returned when a transaction could not be executed by the chaincode itself, as opposed to
+// the errors returned by the chaincode
+type ExecuteError struct {
+	Code    ExecuteErrorCode
+	Message string
+}
+
+func (e *ExecuteError) Error() string {
+	return e.Message
+}
+
+// Status returns the status of the proposal response reporting the error: 503 when the chaincode is
+// unavailable and 504 when it timed out, so that clients can retry the proposal
+func (e *ExecuteError) Status() int32 {
+	if e.Code == ChaincodeUnavailable {
+		return 503
+	}
+	return 504
+}
+
+func newExecuteError(code ExecuteErrorCode, format string, args ...interface{}) error {
+	return &ExecuteError{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
 func (chaincodeSupport *ChaincodeSupport) registerHandler(chaincodehandler *Handler) error {
 	key := chaincodehandler.ChaincodeID.Name
 
@@ -269,8 +314,12 @@ func (chaincodeSupport *ChaincodeSupport) registerHandler(chaincodehandler *Hand
 		return newDuplicateChaincodeHandlerError(chaincodehandler)
 	}
 	//a placeholder, unregistered handler will be setup by transaction processing that comes
-	//through via consensus. In this case we swap the handler and give it the notify channel
+	//through via consensus. In this case we swap the handler and give it the notify channel.
+	//The handler may also be the one of the chaincode which lost its stream, whose transactions
+	//in flight are taken over
+	var previous *Handler
 	if chrte2 != nil {
+		previous = chrte2.handler
 		chaincodehandler.readyNotify = chrte2.handler.readyNotify
 		chrte2.handler = chaincodehandler
 	} else {
@@ -287,6 +336,10 @@ func (chaincodeSupport *ChaincodeSupport) registerHandler(chaincodehandler *Hand
 	//now we are ready to receive messages and send back responses
 	chaincodehandler.txCtxs = make(map[string]*transactionContext)
 	chaincodehandler.txidMap = make(map[string]bool)
+	if previous != nil && previous.reconnect != nil {
+		chaincodehandler.takeOver(previous)
+		chaincodeLogger.Infof("chaincode %s registered again, resuming %d transactions in flight", key, len(chaincodehandler.txCtxs))
+	}
 
 	chaincodeLogger.Debugf("registered handler complete for chaincode %s", key)
 
@@ -337,6 +390,10 @@ func (chaincodeSupport *ChaincodeSupport) sendReady(context context.Context, ccc
 	if notfy != nil {
 		select {
 		case ccMsg := <-notfy:
+			if ccMsg == nil {
+				err = newExecuteError(ChaincodeUnavailable, "Error initializing container %s: the chaincode is no longer available", canName)
+				break
+			}
 			if ccMsg.Type == pb.ChaincodeMessage_ERROR {
 				err = fmt.Errorf("Error initializing container %s: %s", canName, string(ccMsg.Payload))
 			}
@@ -395,6 +452,16 @@ func (chaincodeSupport *ChaincodeSupport) getArgsAndEnv(cccid *ccprovider.CCCont
 	if chaincodeSupport.logFormat != "" {
 		envs = append(envs, "CORE_CHAINCODE_LOGGING_FORMAT="+chaincodeSupport.logFormat)
 	}
+
+	//the chaincode detects a dead peer from the missing keepalives, and reconnects
+	//for as long as the peer waits for it
+	if chaincodeSupport.keepalive > 0 {
+		envs = append(envs, fmt.Sprintf("CORE_CHAINCODE_KEEPALIVE=%d", int(chaincodeSupport.keepalive/time.Second)))
+	}
+
+	if chaincodeSupport.reconnectTimeout > 0 {
+		envs = append(envs, "CORE_CHAINCODE_RECONNECTTIMEOUT="+chaincodeSupport.reconnectTimeout.String())
+	}
 	switch cLang {
 	case pb.ChaincodeSpec_GOLANG, pb.ChaincodeSpec_CAR:
 		args = []string{"chaincode", fmt.Sprintf("-peer.address=%s", chaincodeSupport.peerAddress)}
@@ -491,6 +558,7 @@ func (chaincodeSupport *ChaincodeSupport) launchAndWaitForRegister(ctxt context.
 		chaincodeSupport.runningChaincodes.Unlock()
 		return err
 	}
+	getCCMetrics(cccid.Name).launched()
 
 	//wait for REGISTER state
 	select {
@@ -574,6 +642,11 @@ func (chaincodeSupport *ChaincodeSupport) Launch(context context.Context, cccid
 	var err error
 	//if its in the map, there must be a connected stream...nothing to do
 	if chrte, ok = chaincodeSupport.chaincodeHasBeenLaunched(canName); ok {
+		if reconnect := chrte.handler.reconnect; reconnect != nil {
+			chaincodeSupport.runningChaincodes.Unlock()
+			_, err = chaincodeSupport.waitForReconnect(canName, reconnect)
+			return cID, cMsg, err
+		}
 		if !chrte.handler.registered {
 			chaincodeSupport.runningChaincodes.Unlock()
 			chaincodeLogger.Debugf("premature execution - chaincode (%s) launched and waiting for registration", canName)
@@ -729,24 +802,50 @@ func (chaincodeSupport *ChaincodeSupport) Execute(ctxt context.Context, cccid *c
 		chaincodeLogger.Debugf("cannot execute-chaincode is not running: %s", canName)
 		return nil, fmt.Errorf("Cannot execute transaction for %s", canName)
 	}
+	handler, reconnect := chrte.handler, chrte.handler.reconnect
 	chaincodeSupport.runningChaincodes.Unlock()
 
-	var notfy chan *pb.ChaincodeMessage
+	ccMetrics := getCCMetrics(cccid.Name)
+	done := ccMetrics.executionStarted(msg.Type)
+	var ccresp *pb.ChaincodeMessage
 	var err error
-	if notfy, err = chrte.handler.sendExecuteMessage(ctxt, cccid.ChainID, msg, cccid.SignedProposal, cccid.Proposal); err != nil {
-		return nil, fmt.Errorf("Error sending %s: %s", msg.Type.String(), err)
+	defer func() {
+		done(ccresp, err)
+		ccMetrics.recordApp(ccresp, chaincodeSupport.maxAppMetrics)
+	}()
+
+	if reconnect != nil {
+		if handler, err = chaincodeSupport.waitForReconnect(canName, reconnect); err != nil {
+			return nil, err
+		}
 	}
-	var ccresp *pb.ChaincodeMessage
+
+	var notfy chan *pb.ChaincodeMessage
+	if notfy, err = handler.sendExecuteMessage(ctxt, cccid.ChainID, msg, cccid.SignedProposal, cccid.Proposal); err != nil {
+		err = fmt.Errorf("Error sending %s: %s", msg.Type.String(), err)
+		return nil, err
+	}
+	var delivered bool
 	select {
-	case ccresp = <-notfy:
+	case ccresp, delivered = <-notfy:
 		//response is sent to user or calling chaincode. ChaincodeMessage_ERROR
 		//are typically treated as error
+		if !delivered {
+			err = newExecuteError(ChaincodeUnavailable, "Chaincode %s lost its stream and did not register again within %s", canName, chaincodeSupport.reconnectTimeout)
+		}
 	case <-time.After(timeout):
-		err = fmt.Errorf("Timeout expired while executing transaction")
+		err = newExecuteError(ExecuteTimeout, "Timeout expired while executing transaction")
 	}
 
-	//our responsibility to delete transaction context if sendExecuteMessage succeeded
-	chrte.handler.deleteTxContext(msg.Txid)
+	//our responsibility to delete transaction context if sendExecuteMessage succeeded.
+	//The chaincode may have registered again in the meantime, with a new handler
+	//holding the context
+	chaincodeSupport.runningChaincodes.Lock()
+	if chrte, ok = chaincodeSupport.chaincodeHasBeenLaunched(canName); ok {
+		handler = chrte.handler
+	}
+	chaincodeSupport.runningChaincodes.Unlock()
+	handler.deleteTxContext(msg.Txid)
 
 	return ccresp, err
 }
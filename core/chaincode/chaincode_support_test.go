@@ -134,6 +134,13 @@ func (meqe *mockExecQuerySimulator) GetTxSimulationResults() ([]byte, error) {
 	return meqe.txsim.GetTxSimulationResults()
 }
 
+func (meqe *mockExecQuerySimulator) GetHeight() (uint64, error) {
+	if meqe.txsim == nil {
+		return 0, fmt.Errorf("GetHeight txsimulator not initialed")
+	}
+	return meqe.txsim.GetHeight()
+}
+
 //initialize peer and start up. If security==enabled, login as vp
 func initMockPeer(chainIDs ...string) error {
 	peer.MockInitialize()
@@ -84,6 +84,9 @@ func ExecuteChaincode(ctxt context.Context, cccid *ccprovider.CCContext, args []
 	res, ccevent, err = Execute(ctxt, cccid, spec)
 	if err != nil {
 		chaincodeLogger.Errorf("Error executing chaincode: %s", err)
+		if ee, ok := err.(*ExecuteError); ok {
+			return nil, nil, &ExecuteError{Code: ee.Code, Message: fmt.Sprintf("Error executing chaincode: %s", ee.Message)}
+		}
 		return nil, nil, fmt.Errorf("Error executing chaincode: %s", err)
 	}
 
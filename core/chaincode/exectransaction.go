@@ -42,7 +42,9 @@ func Execute(ctxt context.Context, cccid *ccprovider.CCContext, spec interface{}
 	}
 
 	_, cMsg, err := theChaincodeSupport.Launch(ctxt, cccid, spec)
-	if err != nil {
+	if _, ok := err.(*ExecuteError); ok {
+		return nil, nil, err
+	} else if err != nil {
 		return nil, nil, fmt.Errorf("%s", err)
 	}
 
@@ -53,7 +55,10 @@ func Execute(ctxt context.Context, cccid *ccprovider.CCContext, spec interface{}
 	}
 
 	resp, err := theChaincodeSupport.Execute(ctxt, cccid, ccMsg, theChaincodeSupport.executetimeout)
-	if err != nil {
+	if _, ok := err.(*ExecuteError); ok {
+		// Keep the code for the endorser
+		return nil, nil, err
+	} else if err != nil {
 		// Rollback transaction
 		return nil, nil, fmt.Errorf("Failed to execute transaction (%s)", err)
 	} else if resp == nil {
@@ -102,6 +102,14 @@ type Handler struct {
 	// used to do Send after making sure the state transition is complete
 	nextState chan *nextStateInfo
 
+	// closed when the stream with the chaincode has ended
+	streamDone chan struct{}
+
+	// set when the handler lost its stream and waits for the chaincode to register
+	// again, and shared with the handler registering in its place. It is closed once
+	// the chaincode is ready again, or the reconnect timeout expired
+	reconnect chan struct{}
+
 	policyChecker policy.PolicyChecker
 }
 
@@ -268,14 +276,33 @@ func (handler *Handler) deregister() error {
 	return nil
 }
 
+// disconnect is called once the stream with the chaincode has ended. A ready chaincode
+// may register again within the reconnect timeout, otherwise the handler is deregistered
+func (handler *Handler) disconnect() {
+	close(handler.streamDone)
+	if handler.registered && handler.FSM.Current() == readystate && handler.chaincodeSupport.reconnectTimeout > 0 {
+		handler.chaincodeSupport.awaitReconnect(handler)
+		return
+	}
+	handler.deregister()
+}
+
 func (handler *Handler) triggerNextState(msg *pb.ChaincodeMessage, send bool) {
 	//this will send Async
-	handler.nextState <- &nextStateInfo{msg: msg, sendToCC: send, sendSync: false}
+	select {
+	case handler.nextState <- &nextStateInfo{msg: msg, sendToCC: send, sendSync: false}:
+	case <-handler.streamDone:
+		chaincodeLogger.Debugf("[%s]Stream ended, dropping %s", shorttxid(msg.Txid), msg.Type)
+	}
 }
 
 func (handler *Handler) triggerNextStateSync(msg *pb.ChaincodeMessage) {
 	//this will send sync
-	handler.nextState <- &nextStateInfo{msg: msg, sendToCC: true, sendSync: true}
+	select {
+	case handler.nextState <- &nextStateInfo{msg: msg, sendToCC: true, sendSync: true}:
+	case <-handler.streamDone:
+		chaincodeLogger.Debugf("[%s]Stream ended, dropping %s", shorttxid(msg.Txid), msg.Type)
+	}
 }
 
 func (handler *Handler) waitForKeepaliveTimer() <-chan time.Time {
@@ -289,12 +316,17 @@ func (handler *Handler) waitForKeepaliveTimer() <-chan time.Time {
 }
 
 func (handler *Handler) processStream() error {
-	defer handler.deregister()
-	msgAvail := make(chan *pb.ChaincodeMessage)
+	defer handler.disconnect()
+	//buffered so that the pending Recv routine does not block once the stream ended
+	msgAvail := make(chan *pb.ChaincodeMessage, 1)
 	var nsInfo *nextStateInfo
 	var in *pb.ChaincodeMessage
 	var err error
 
+	//the chaincode answers each KEEPALIVE, so its stream is dead when nothing
+	//was received for a few keepalive intervals
+	lastRecv := time.Now()
+
 	//recv is used to spin Recv routine after previous received msg
 	//has been processed
 	recv := true
@@ -334,6 +366,7 @@ func (handler *Handler) processStream() error {
 				return err
 			}
 			chaincodeLogger.Debugf("[%s]Received message %s from shim", shorttxid(in.Txid), in.Type.String())
+			lastRecv = time.Now()
 			if in.Type.String() == pb.ChaincodeMessage_ERROR.String() {
 				chaincodeLogger.Errorf("Got error: %s", string(in.Payload))
 			}
@@ -361,6 +394,12 @@ func (handler *Handler) processStream() error {
 				continue
 			}
 
+			if silence := time.Since(lastRecv); silence > keepaliveMisses*handler.chaincodeSupport.keepalive {
+				err = fmt.Errorf("No message received from chaincode for %s, ending chaincode support stream", silence)
+				chaincodeLogger.Error(err)
+				return err
+			}
+
 			//if no error message from serialSend, KEEPALIVE happy, and don't care about error
 			//(maybe it'll work later)
 			handler.serialSendAsync(&pb.ChaincodeMessage{Type: pb.ChaincodeMessage_KEEPALIVE}, nil)
@@ -406,6 +445,7 @@ func newChaincodeSupportHandler(chaincodeSupport *ChaincodeSupport, peerChatStre
 	v.chaincodeSupport = chaincodeSupport
 	//we want this to block
 	v.nextState = make(chan *nextStateInfo)
+	v.streamDone = make(chan struct{})
 
 	v.FSM = fsm.NewFSM(
 		createdstate,
@@ -1319,6 +1359,13 @@ func (handler *Handler) enterBusyState(e *fsm.Event, state string) {
 }
 
 func (handler *Handler) enterEstablishedState(e *fsm.Event, state string) {
+	if handler.reconnect != nil {
+		//the chaincode registered again after losing its stream: it was ready
+		//before, so take it to ready state again at once
+		chaincodeLogger.Debug("sending READY to reconnected chaincode")
+		go handler.triggerNextState(&pb.ChaincodeMessage{Type: pb.ChaincodeMessage_READY}, true)
+		return
+	}
 	handler.notifyDuringStartup(true)
 }
 
@@ -1331,6 +1378,9 @@ func (handler *Handler) enterReadyState(e *fsm.Event, state string) {
 	}
 	chaincodeLogger.Debugf("[%s]Entered state %s", shorttxid(msg.Txid), state)
 	handler.notify(msg)
+	if handler.reconnect != nil {
+		handler.chaincodeSupport.reconnected(handler)
+	}
 }
 
 func (handler *Handler) enterEndState(e *fsm.Event, state string) {
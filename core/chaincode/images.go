@@ -0,0 +1,117 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/hyperledger/fabric/core/chaincode/platforms"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/container"
+	"github.com/hyperledger/fabric/core/container/ccintf"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"golang.org/x/net/context"
+)
+
+// BuildImages builds the images of the chaincodes installed on the peer ahead of their first
+// launch, so that their first invocation does not wait for the build. Only the image of the
+// chaincode of the given name and version is built if name is not empty
+func (chaincodeSupport *ChaincodeSupport) BuildImages(ctxt context.Context, name, version string) ([]*pb.ChaincodeImage, error) {
+	var installed []*pb.ChaincodeInfo
+	if name != "" {
+		installed = []*pb.ChaincodeInfo{{Name: name, Version: version}}
+	} else {
+		resp, err := ccprovider.GetInstalledChaincodes()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the installed chaincodes: %s", err)
+		}
+		installed = resp.Chaincodes
+	}
+
+	images := make([]*pb.ChaincodeImage, 0, len(installed))
+	for _, info := range installed {
+		image := &pb.ChaincodeImage{ChaincodeName: info.Name, ChaincodeVersion: info.Version}
+		built, err := chaincodeSupport.buildImage(ctxt, info.Name, info.Version)
+		switch {
+		case err != nil:
+			chaincodeLogger.Errorf("Failed to build the image of chaincode %s:%s: %s", info.Name, info.Version, err)
+			image.State = pb.ChaincodeImage_FAILED
+			image.Error = err.Error()
+		case built:
+			chaincodeLogger.Infof("Built the image of chaincode %s:%s", info.Name, info.Version)
+			image.State = pb.ChaincodeImage_BUILT
+		default:
+			image.State = pb.ChaincodeImage_EXISTING
+		}
+		images = append(images, image)
+	}
+	return images, nil
+}
+
+func (chaincodeSupport *ChaincodeSupport) buildImage(ctxt context.Context, name, version string) (bool, error) {
+	ccpack, err := ccprovider.GetChaincodeFromFS(name, version)
+	if err != nil {
+		return false, err
+	}
+	cds := ccpack.GetDepSpec()
+	vmtype, _ := chaincodeSupport.getVMType(cds)
+
+	bir := container.BuildImageReq{
+		CCID:    ccintf.CCID{ChaincodeSpec: cds.ChaincodeSpec, NetworkID: chaincodeSupport.peerNetworkID, PeerID: chaincodeSupport.peerID, Version: version},
+		Builder: func() (io.Reader, error) { return platforms.GenerateDockerBuild(cds) },
+	}
+	resp, err := container.VMCProcess(ctxt, vmtype, bir)
+	if err != nil {
+		return false, err
+	}
+	if resp.(container.VMCResp).Err != nil {
+		return false, resp.(container.VMCResp).Err
+	}
+	return resp.(container.VMCResp).Resp.(bool), nil
+}
+
+// RemoveStaleImages removes the images built by the peer of the chaincodes which are no longer
+// installed on the peer, such as the former versions of the chaincodes upgraded. An image still
+// used by a container is not removed
+func (chaincodeSupport *ChaincodeSupport) RemoveStaleImages(ctxt context.Context) ([]*pb.ChaincodeImage, error) {
+	resp, err := ccprovider.GetInstalledChaincodes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the installed chaincodes: %s", err)
+	}
+	installed := make(map[string]bool)
+	for _, info := range resp.Chaincodes {
+		installed[info.Name+":"+info.Version] = true
+	}
+
+	ccids, err := container.ListImages(ctxt, container.DOCKER, ccintf.CCID{NetworkID: chaincodeSupport.peerNetworkID, PeerID: chaincodeSupport.peerID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the images of the chaincodes: %s", err)
+	}
+
+	var images []*pb.ChaincodeImage
+	for _, ccid := range ccids {
+		name := ccid.ChaincodeSpec.ChaincodeId.Name
+		if installed[name+":"+ccid.Version] {
+			continue
+		}
+		image := &pb.ChaincodeImage{ChaincodeName: name, ChaincodeVersion: ccid.Version, State: pb.ChaincodeImage_REMOVED}
+		resp, err := container.VMCProcess(ctxt, container.DOCKER, container.DestroyImageReq{CCID: ccid})
+		if err == nil {
+			err = resp.(container.VMCResp).Err
+		}
+		if err != nil {
+			chaincodeLogger.Errorf("Failed to remove the image of chaincode %s:%s: %s", name, ccid.Version, err)
+			image.State = pb.ChaincodeImage_FAILED
+			image.Error = err.Error()
+		} else {
+			chaincodeLogger.Infof("Removed the image of chaincode %s:%s", name, ccid.Version)
+		}
+		images = append(images, image)
+	}
+	return images, nil
+}
@@ -0,0 +1,144 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric/common/metrics"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	gometrics "github.com/rcrowley/go-metrics"
+)
+
+// ccMetrics are the runtime metrics of the chaincodes of a name, whatever their version. They
+// are registered as chaincode.<name>.<metric>
+type ccMetrics struct {
+	ccName string
+	// executeDuration and initDuration time the invocations and the inits
+	executeDuration gometrics.Timer
+	initDuration    gometrics.Timer
+	// errors counts the executions which failed or timed out, and those the shim answered with an error
+	errors gometrics.Counter
+	// launches counts the containers launched, and restarts those launched after the first one
+	launches gometrics.Counter
+	restarts gometrics.Counter
+	// activeExecutions is the number of executions in progress
+	activeExecutions gometrics.Counter
+}
+
+func getCCMetrics(ccName string) *ccMetrics {
+	name := func(metric string) string {
+		return fmt.Sprintf("chaincode.%s.%s", ccName, metric)
+	}
+	return &ccMetrics{
+		ccName:           ccName,
+		executeDuration:  gometrics.GetOrRegisterTimer(name("execute.duration"), metrics.Registry),
+		initDuration:     gometrics.GetOrRegisterTimer(name("init.duration"), metrics.Registry),
+		errors:           gometrics.GetOrRegisterCounter(name("errors"), metrics.Registry),
+		launches:         gometrics.GetOrRegisterCounter(name("launches"), metrics.Registry),
+		restarts:         gometrics.GetOrRegisterCounter(name("restarts"), metrics.Registry),
+		activeExecutions: gometrics.GetOrRegisterCounter(name("active_executions"), metrics.Registry),
+	}
+}
+
+// executionStarted records the start of the execution of a message, and returns the function
+// recording its end with the response and error of the execution
+func (m *ccMetrics) executionStarted(msgType pb.ChaincodeMessage_Type) func(resp *pb.ChaincodeMessage, err error) {
+	m.activeExecutions.Inc(1)
+	start := time.Now()
+	return func(resp *pb.ChaincodeMessage, err error) {
+		m.activeExecutions.Dec(1)
+		if msgType == pb.ChaincodeMessage_INIT {
+			m.initDuration.UpdateSince(start)
+		} else {
+			m.executeDuration.UpdateSince(start)
+		}
+		if err != nil || resp == nil || resp.Type == pb.ChaincodeMessage_ERROR {
+			m.errors.Inc(1)
+		}
+	}
+}
+
+// launched records the launch of a container. Launches are serialized per chaincode, so
+// any launch after the first one is a restart
+func (m *ccMetrics) launched() {
+	if m.launches.Count() > 0 {
+		m.restarts.Inc(1)
+	}
+	m.launches.Inc(1)
+}
+
+// validAppMetricName matches the names of the metrics the chaincodes may emit through the shim
+var validAppMetricName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]{0,63}$`)
+
+// appMetricCounts serializes the registration of the metrics the chaincodes emit through the shim,
+// and counts them by chaincode name
+var appMetricCounts = struct {
+	sync.Mutex
+	counts map[string]int
+}{counts: make(map[string]int)}
+
+// recordApp applies the updates of the metrics the chaincode emitted through the shim with its response
+// to a transaction. They are registered as chaincode.<name>.app.<metric>, at most max of them per
+// chaincode name unless max is 0. As the chaincode is not trusted, the updates of malformed metrics,
+// of metrics of another type than the one registered under their name, and of metrics beyond max are
+// dropped with a warning
+func (m *ccMetrics) recordApp(resp *pb.ChaincodeMessage, max int) {
+	if resp == nil {
+		return
+	}
+	for _, update := range resp.Metrics {
+		if err := m.recordAppMetric(update, max); err != nil {
+			chaincodeLogger.Warningf("Dropping the update of metric %q of chaincode %s: %s", update.Name, m.ccName, err)
+		}
+	}
+}
+
+func (m *ccMetrics) recordAppMetric(update *pb.ChaincodeMetric, max int) error {
+	if !validAppMetricName.MatchString(update.Name) {
+		return fmt.Errorf("invalid metric name")
+	}
+	if update.Type == pb.ChaincodeMetric_COUNTER && update.Increment < 0 {
+		return fmt.Errorf("negative counter increment %d", update.Increment)
+	}
+	name := fmt.Sprintf("chaincode.%s.app.%s", m.ccName, update.Name)
+
+	appMetricCounts.Lock()
+	defer appMetricCounts.Unlock()
+	existing := metrics.Registry.Get(name)
+	if existing == nil && max > 0 && appMetricCounts.counts[m.ccName] >= max {
+		return fmt.Errorf("the chaincode emitted %d metrics already", max)
+	}
+	switch update.Type {
+	case pb.ChaincodeMetric_COUNTER:
+		counter, ok := existing.(gometrics.Counter)
+		if existing == nil {
+			counter = gometrics.NewCounter()
+			metrics.Registry.Register(name, counter)
+			appMetricCounts.counts[m.ccName]++
+		} else if !ok {
+			return fmt.Errorf("the metric is not a counter")
+		}
+		counter.Inc(update.Increment)
+	case pb.ChaincodeMetric_GAUGE:
+		gauge, ok := existing.(gometrics.GaugeFloat64)
+		if existing == nil {
+			gauge = gometrics.NewGaugeFloat64()
+			metrics.Registry.Register(name, gauge)
+			appMetricCounts.counts[m.ccName]++
+		} else if !ok {
+			return fmt.Errorf("the metric is not a gauge")
+		}
+		gauge.Update(update.Value)
+	default:
+		return fmt.Errorf("unknown metric type %s", update.Type)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/metrics"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	gometrics "github.com/rcrowley/go-metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCCMetrics(t *testing.T) {
+	m := getCCMetrics("metricscc")
+	assert.Equal(t, m.errors, getCCMetrics("metricscc").errors, "Should have registered the metrics once")
+
+	done := m.executionStarted(pb.ChaincodeMessage_INIT)
+	assert.Equal(t, int64(1), m.activeExecutions.Count())
+	done(&pb.ChaincodeMessage{Type: pb.ChaincodeMessage_COMPLETED}, nil)
+	assert.Equal(t, int64(0), m.activeExecutions.Count())
+	assert.Equal(t, int64(1), m.initDuration.Count())
+	assert.Equal(t, int64(0), m.errors.Count())
+
+	m.executionStarted(pb.ChaincodeMessage_TRANSACTION)(&pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR}, nil)
+	m.executionStarted(pb.ChaincodeMessage_TRANSACTION)(nil, errors.New("Timeout expired while executing transaction"))
+	assert.Equal(t, int64(2), m.executeDuration.Count())
+	assert.Equal(t, int64(2), m.errors.Count())
+
+	m.launched()
+	assert.Equal(t, int64(0), m.restarts.Count())
+	m.launched()
+	assert.Equal(t, int64(2), m.launches.Count())
+	assert.Equal(t, int64(1), m.restarts.Count())
+}
+
+func TestAppMetrics(t *testing.T) {
+	m := getCCMetrics("appmetricscc")
+	m.recordApp(nil, 0)
+	m.recordApp(&pb.ChaincodeMessage{Metrics: []*pb.ChaincodeMetric{
+		{Name: "transfers", Type: pb.ChaincodeMetric_COUNTER, Increment: 2},
+		{Name: "pending", Type: pb.ChaincodeMetric_GAUGE, Value: 1.5},
+		{Name: "in.valid", Type: pb.ChaincodeMetric_COUNTER, Increment: 1},
+		{Name: "negative", Type: pb.ChaincodeMetric_COUNTER, Increment: -1},
+	}}, 3)
+	m.recordApp(&pb.ChaincodeMessage{Metrics: []*pb.ChaincodeMetric{
+		{Name: "transfers", Type: pb.ChaincodeMetric_COUNTER, Increment: 3},
+		{Name: "pending", Type: pb.ChaincodeMetric_COUNTER, Increment: 1},
+		{Name: "rejected", Type: pb.ChaincodeMetric_COUNTER, Increment: 1},
+		{Name: "beyond", Type: pb.ChaincodeMetric_GAUGE, Value: 1},
+	}}, 3)
+
+	assert.Equal(t, int64(5), metrics.Registry.Get("chaincode.appmetricscc.app.transfers").(gometrics.Counter).Count())
+	assert.Equal(t, 1.5, metrics.Registry.Get("chaincode.appmetricscc.app.pending").(gometrics.GaugeFloat64).Value(),
+		"Expected the update of a gauge as a counter to be dropped")
+	assert.Equal(t, int64(1), metrics.Registry.Get("chaincode.appmetricscc.app.rejected").(gometrics.Counter).Count())
+	assert.Nil(t, metrics.Registry.Get("chaincode.appmetricscc.app.in.valid"))
+	assert.Nil(t, metrics.Registry.Get("chaincode.appmetricscc.app.negative"))
+	assert.Nil(t, metrics.Registry.Get("chaincode.appmetricscc.app.beyond"), "Expected the metrics beyond the maximum to be dropped")
+}
@@ -0,0 +1,198 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package golang
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"io/ioutil"
+	"path"
+	"strconv"
+	"strings"
+
+	pb "github.com/hyperledger/fabric/protos/peer"
+)
+
+// stateWrites are the methods of the chaincode stub writing to the state
+var stateWrites = map[string]bool{"PutState": true, "DelState": true}
+
+// nonDeterministicImports are the packages whose values differ between peers
+var nonDeterministicImports = map[string]bool{"math/rand": true, "crypto/rand": true}
+
+// clockFuncs are the functions of package time reading the clock of the peer
+var clockFuncs = map[string]bool{"Now": true, "Since": true, "Until": true}
+
+// Analyze inspects the source of the main package of a Go chaincode for the constructs which
+// make its execution differ between peers, and so its endorsements mismatch: the iteration over
+// maps writing to the state, the use of random numbers or of the clock, package variables, which
+// keep state between transactions, and goroutines writing to the state. It returns a description
+// of each construct found, prefixed with its position. The analysis is syntactic and only types
+// the declarations of the package itself, so it is neither sound nor complete
+func Analyze(cds *pb.ChaincodeDeploymentSpec) ([]string, error) {
+	if len(cds.CodePackage) == 0 {
+		return nil, nil
+	}
+	pkg, err := decodeUrl(cds.ChaincodeSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	gr, err := gzip.NewReader(bytes.NewReader(cds.CodePackage))
+	if err != nil {
+		return nil, fmt.Errorf("failure opening codepackage gzip stream: %s", err)
+	}
+	tr := tar.NewReader(gr)
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failure reading codepackage: %s", err)
+		}
+		name := strings.TrimPrefix(header.Name, "/")
+		if path.Dir(name) != path.Join("src", pkg) || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		src, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("failure reading %s: %s", name, err)
+		}
+		file, err := parser.ParseFile(fset, path.Base(name), src, 0)
+		if err != nil {
+			return nil, fmt.Errorf("failure parsing %s: %s", name, err)
+		}
+		files = append(files, file)
+	}
+
+	// the imported packages are not part of the code package, the type checker only resolves the
+	// types declared by the chaincode, which is enough to tell the maps it ranges over
+	info := &types.Info{Types: make(map[ast.Expr]types.TypeAndValue)}
+	conf := types.Config{Importer: emptyImporter{}, Error: func(error) {}}
+	conf.Check(pkg, fset, files, info)
+
+	a := &analyzer{fset: fset, info: info, funcs: make(map[string]*ast.FuncDecl)}
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil {
+				a.funcs[fn.Name.Name] = fn
+			}
+		}
+	}
+	for _, file := range files {
+		a.analyzeFile(file)
+	}
+	return a.findings, nil
+}
+
+type emptyImporter struct{}
+
+func (emptyImporter) Import(importPath string) (*types.Package, error) {
+	pkg := types.NewPackage(importPath, path.Base(importPath))
+	pkg.MarkComplete()
+	return pkg, nil
+}
+
+type analyzer struct {
+	fset     *token.FileSet
+	info     *types.Info
+	funcs    map[string]*ast.FuncDecl
+	findings []string
+}
+
+func (a *analyzer) report(pos token.Pos, format string, args ...interface{}) {
+	position := a.fset.Position(pos)
+	a.findings = append(a.findings, fmt.Sprintf("%s:%d: %s", position.Filename, position.Line, fmt.Sprintf(format, args...)))
+}
+
+func (a *analyzer) analyzeFile(file *ast.File) {
+	timeName := ""
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		if nonDeterministicImports[importPath] {
+			a.report(spec.Pos(), "imports %s, whose values differ between peers (see shim.NewTxRand)", importPath)
+		}
+		if importPath == "time" {
+			timeName = "time"
+			if spec.Name != nil {
+				timeName = spec.Name.Name
+			}
+		}
+	}
+
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			for _, name := range spec.(*ast.ValueSpec).Names {
+				if name.Name != "_" {
+					a.report(name.Pos(), "package variable %s keeps state between transactions, which differs between peers", name.Name)
+				}
+			}
+		}
+	}
+
+	ast.Inspect(file, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.SelectorExpr:
+			if x, ok := n.X.(*ast.Ident); ok && timeName != "" && x.Name == timeName && clockFuncs[n.Sel.Name] {
+				a.report(n.Pos(), "calls time.%s, whose value differs between peers", n.Sel.Name)
+			}
+		case *ast.RangeStmt:
+			if t := a.info.TypeOf(n.X); t != nil {
+				if _, isMap := t.Underlying().(*types.Map); isMap && writesState(n.Body) {
+					a.report(n.Pos(), "iterates over a map, whose order is random, and writes to the state")
+				}
+			}
+		case *ast.GoStmt:
+			if a.goroutineWritesState(n.Call) {
+				a.report(n.Pos(), "starts a goroutine writing to the state")
+			}
+		}
+		return true
+	})
+}
+
+func (a *analyzer) goroutineWritesState(call *ast.CallExpr) bool {
+	switch fun := call.Fun.(type) {
+	case *ast.FuncLit:
+		return writesState(fun.Body)
+	case *ast.Ident:
+		if fn, ok := a.funcs[fun.Name]; ok && fn.Body != nil {
+			return writesState(fn.Body)
+		}
+	}
+	return false
+}
+
+// writesState returns true if the node calls a method of the stub writing to the state
+func writesState(node ast.Node) bool {
+	found := false
+	ast.Inspect(node, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			if sel, ok := call.Fun.(*ast.SelectorExpr); ok && stateWrites[sel.Sel.Name] {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
@@ -0,0 +1,123 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package golang
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"testing"
+
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const deterministicChaincode = `package main
+
+import (
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	pb "github.com/hyperledger/fabric/protos/peer"
+)
+
+type SimpleChaincode struct{}
+
+func (t *SimpleChaincode) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
+	_, args := stub.GetFunctionAndParameters()
+	for _, arg := range args {
+		stub.PutState(arg, []byte(arg))
+	}
+	return shim.Success(nil)
+}
+
+func main() {
+	shim.Start(new(SimpleChaincode))
+}
+`
+
+const nonDeterministicChaincode = `package main
+
+import (
+	"math/rand"
+	clock "time"
+
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	pb "github.com/hyperledger/fabric/protos/peer"
+)
+
+var counter int
+
+type SimpleChaincode struct {
+	values map[string][]byte
+}
+
+func (t *SimpleChaincode) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
+	for k, v := range t.values {
+		stub.PutState(k, v)
+	}
+	stub.PutState("now", []byte(clock.Now().String()))
+	go write(stub)
+	go func() {
+		stub.DelState("key")
+	}()
+	return shim.Success([]byte{byte(rand.Int())})
+}
+
+func write(stub shim.ChaincodeStubInterface) {
+	stub.PutState("key", []byte("value"))
+}
+`
+
+func codePackage(t *testing.T, files map[string]string) []byte {
+	buf := &bytes.Buffer{}
+	gw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gw)
+	for name, src := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0100644, Size: int64(len(src))}))
+		_, err := tw.Write([]byte(src))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+	return buf.Bytes()
+}
+
+func TestAnalyze(t *testing.T) {
+	spec := &pb.ChaincodeSpec{Type: pb.ChaincodeSpec_GOLANG, ChaincodeId: &pb.ChaincodeID{Path: "example.com/cc"}}
+
+	findings, err := Analyze(&pb.ChaincodeDeploymentSpec{ChaincodeSpec: spec, CodePackage: codePackage(t, map[string]string{
+		"src/example.com/cc/cc.go":                  deterministicChaincode,
+		"src/example.com/cc/cc_test.go":             "package main\n\nvar fixture = 1\n",
+		"src/example.com/cc/vendor/dep/dep.go":      "package dep\n\nvar State int\n",
+		"src/github.com/other/package/package.go":   "package other\n\nvar State int\n",
+		"src/example.com/cc/vendor/dep/nogo/README": "not go",
+	})})
+	require.NoError(t, err)
+	assert.Empty(t, findings, "Should only have analyzed the main package of the chaincode")
+
+	findings, err = Analyze(&pb.ChaincodeDeploymentSpec{ChaincodeSpec: spec, CodePackage: codePackage(t, map[string]string{
+		"src/example.com/cc/cc.go": nonDeterministicChaincode,
+	})})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"cc.go:4: imports math/rand, whose values differ between peers (see shim.NewTxRand)",
+		"cc.go:11: package variable counter keeps state between transactions, which differs between peers",
+		"cc.go:18: iterates over a map, whose order is random, and writes to the state",
+		"cc.go:21: calls time.Now, whose value differs between peers",
+		"cc.go:22: starts a goroutine writing to the state",
+		"cc.go:23: starts a goroutine writing to the state",
+	}, findings)
+
+	_, err = Analyze(&pb.ChaincodeDeploymentSpec{ChaincodeSpec: spec, CodePackage: codePackage(t, map[string]string{
+		"src/example.com/cc/cc.go": "package main\n\nfunc main() {",
+	})})
+	assert.Error(t, err, "Should have failed to parse the chaincode")
+
+	findings, err = Analyze(&pb.ChaincodeDeploymentSpec{ChaincodeSpec: spec})
+	assert.NoError(t, err)
+	assert.Empty(t, findings, "Should not have analyzed a deployment spec without code package")
+}
@@ -0,0 +1,110 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"time"
+)
+
+// keepaliveMisses is the number of keepalive intervals without any message received from the
+// chaincode after which its stream is considered dead
+const keepaliveMisses = 3
+
+// awaitReconnect keeps the handler of a ready chaincode which lost its stream, along with its
+// transactions in flight, for the reconnect timeout. If the chaincode registers again in the
+// meantime, the new handler takes the transactions over, otherwise they fail
+func (chaincodeSupport *ChaincodeSupport) awaitReconnect(handler *Handler) {
+	key := handler.ChaincodeID.Name
+	chaincodeSupport.runningChaincodes.Lock()
+	defer chaincodeSupport.runningChaincodes.Unlock()
+	if chrte, ok := chaincodeSupport.chaincodeHasBeenLaunched(key); !ok || chrte.handler != handler {
+		// Already deregistered
+		return
+	}
+
+	chaincodeLogger.Warningf("Lost the stream of chaincode %s, waiting %s for it to register again", key, chaincodeSupport.reconnectTimeout)
+	handler.registered = false
+	handler.reconnect = make(chan struct{})
+	time.AfterFunc(chaincodeSupport.reconnectTimeout, func() { chaincodeSupport.reconnectExpired(handler) })
+}
+
+// reconnectExpired deregisters the handler which lost its stream, and fails its transactions in
+// flight, unless the chaincode registered again
+func (chaincodeSupport *ChaincodeSupport) reconnectExpired(handler *Handler) {
+	key := handler.ChaincodeID.Name
+	chaincodeSupport.runningChaincodes.Lock()
+	defer chaincodeSupport.runningChaincodes.Unlock()
+	closeReconnect(handler.reconnect)
+	if chrte, ok := chaincodeSupport.chaincodeHasBeenLaunched(key); !ok || chrte.handler != handler {
+		return
+	}
+
+	delete(chaincodeSupport.runningChaincodes.chaincodeMap, key)
+	chaincodeLogger.Errorf("Chaincode %s did not register again within %s, failing its transactions in flight", key, chaincodeSupport.reconnectTimeout)
+	handler.failTransactions()
+}
+
+// reconnected is called once the handler of a chaincode which registered again is ready, to
+// release the transactions waiting for it
+func (chaincodeSupport *ChaincodeSupport) reconnected(handler *Handler) {
+	chaincodeSupport.runningChaincodes.Lock()
+	defer chaincodeSupport.runningChaincodes.Unlock()
+	closeReconnect(handler.reconnect)
+}
+
+// waitForReconnect waits for a chaincode which lost its stream to be ready again, and returns its
+// new handler, or an ExecuteError if it did not register again within the reconnect timeout
+func (chaincodeSupport *ChaincodeSupport) waitForReconnect(canName string, reconnect chan struct{}) (*Handler, error) {
+	select {
+	case <-reconnect:
+	case <-time.After(chaincodeSupport.reconnectTimeout):
+	}
+
+	chaincodeSupport.runningChaincodes.Lock()
+	defer chaincodeSupport.runningChaincodes.Unlock()
+	chrte, ok := chaincodeSupport.chaincodeHasBeenLaunched(canName)
+	if !ok || !chrte.handler.registered || chrte.handler.FSM.Current() != readystate {
+		return nil, newExecuteError(ChaincodeUnavailable, "Chaincode %s lost its stream and did not register again within %s", canName, chaincodeSupport.reconnectTimeout)
+	}
+	return chrte.handler, nil
+}
+
+// closeReconnect closes the reconnect channel unless it is already closed. Call this under the lock
+// of the running chaincodes
+func closeReconnect(reconnect chan struct{}) {
+	select {
+	case <-reconnect:
+	default:
+		close(reconnect)
+	}
+}
+
+// takeOver moves the transactions in flight of the handler which lost its stream to the handler
+// of the chaincode registering again
+func (handler *Handler) takeOver(previous *Handler) {
+	previous.Lock()
+	defer previous.Unlock()
+	for txid, txctx := range previous.txCtxs {
+		handler.txCtxs[txid] = txctx
+	}
+	previous.txCtxs = nil
+	handler.reconnect = previous.reconnect
+}
+
+// failTransactions closes the response notifiers of the transactions in flight, which Execute
+// reports as ChaincodeUnavailable
+func (handler *Handler) failTransactions() {
+	handler.Lock()
+	defer handler.Unlock()
+	for txid, txctx := range handler.txCtxs {
+		for _, v := range txctx.queryIteratorMap {
+			v.Close()
+		}
+		close(txctx.responseNotifier)
+		delete(handler.txCtxs, txid)
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"testing"
+	"time"
+
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/looplab/fsm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newReconnectTestHandler(cs *ChaincodeSupport, name string, txids ...string) *Handler {
+	h := &Handler{ChaincodeID: &pb.ChaincodeID{Name: name}, chaincodeSupport: cs, registered: true, txCtxs: make(map[string]*transactionContext)}
+	h.FSM = fsm.NewFSM(readystate, nil, nil)
+	for _, txid := range txids {
+		h.txCtxs[txid] = &transactionContext{responseNotifier: make(chan *pb.ChaincodeMessage, 1)}
+	}
+	cs.runningChaincodes.chaincodeMap[name] = &chaincodeRTEnv{handler: h}
+	return h
+}
+
+func TestReconnect(t *testing.T) {
+	cs := &ChaincodeSupport{
+		runningChaincodes: &runningChaincodes{chaincodeMap: make(map[string]*chaincodeRTEnv), launchStarted: make(map[string]bool)},
+		reconnectTimeout:  200 * time.Millisecond,
+	}
+
+	t.Run("Resumed", func(t *testing.T) {
+		lost := newReconnectTestHandler(cs, "resumed:0", "tx1")
+		notfy := lost.txCtxs["tx1"].responseNotifier
+		cs.awaitReconnect(lost)
+		assert.False(t, lost.registered)
+		require.NotNil(t, lost.reconnect)
+
+		registered := &Handler{ChaincodeID: &pb.ChaincodeID{Name: "resumed:0"}, chaincodeSupport: cs}
+		require.NoError(t, cs.registerHandler(registered))
+		assert.Nil(t, lost.txCtxs)
+		assert.Contains(t, registered.txCtxs, "tx1", "Should have taken the transaction in flight over")
+
+		registered.notify(&pb.ChaincodeMessage{Type: pb.ChaincodeMessage_COMPLETED, Txid: "tx1"})
+		resp := <-notfy
+		assert.Equal(t, pb.ChaincodeMessage_COMPLETED, resp.Type)
+
+		registered.FSM = fsm.NewFSM(readystate, nil, nil)
+		cs.reconnected(registered)
+		h, err := cs.waitForReconnect("resumed:0", registered.reconnect)
+		assert.NoError(t, err)
+		assert.Equal(t, registered, h)
+
+		// The expiration of the timeout leaves the new handler alone
+		time.Sleep(2 * cs.reconnectTimeout)
+		_, ok := cs.chaincodeHasBeenLaunched("resumed:0")
+		assert.True(t, ok)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		lost := newReconnectTestHandler(cs, "expired:0", "tx2")
+		notfy := lost.txCtxs["tx2"].responseNotifier
+		cs.awaitReconnect(lost)
+
+		_, err := cs.waitForReconnect("expired:0", lost.reconnect)
+		require.Error(t, err)
+		ee, ok := err.(*ExecuteError)
+		require.True(t, ok, "Should have returned an ExecuteError")
+		assert.Equal(t, ChaincodeUnavailable, ee.Code)
+		assert.Equal(t, int32(503), ee.Status())
+
+		_, delivered := <-notfy
+		assert.False(t, delivered, "Should have failed the transaction in flight")
+		_, ok = cs.chaincodeHasBeenLaunched("expired:0")
+		assert.False(t, ok, "Should have deregistered the handler")
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		cs.reconnectTimeout = 0
+		defer func() { cs.reconnectTimeout = 200 * time.Millisecond }()
+		lost := newReconnectTestHandler(cs, "disabled:0")
+		lost.streamDone = make(chan struct{})
+		lost.disconnect()
+		_, ok := cs.chaincodeHasBeenLaunched("disabled:0")
+		assert.False(t, ok, "Should have deregistered the handler at once")
+
+		// Messages triggered after the end of the stream are dropped
+		lost.triggerNextState(&pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE}, true)
+	})
+}
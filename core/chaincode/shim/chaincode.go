@@ -19,12 +19,14 @@ limitations under the License.
 package shim
 
 import (
+	"crypto/sha256"
 	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"os"
 	"strings"
+	"time"
 	"unicode/utf8"
 
 	"github.com/golang/protobuf/proto"
@@ -51,6 +53,13 @@ const (
 	maxUnicodeRuneValue   = utf8.MaxRune //U+10FFFF - maximum (and unallocated) code point
 	compositeKeyNamespace = "\x00"
 	emptyKeySubstitute    = "\x01"
+
+	// keepaliveMisses is the number of keepalive intervals without any message received
+	// from the peer after which the stream is considered dead
+	keepaliveMisses = 3
+
+	// maxReconnectBackoff caps the delay between the attempts to reconnect to the peer
+	maxReconnectBackoff = 5 * time.Second
 )
 
 // ChaincodeStub is an object passed to chaincode for shim side handling of
@@ -58,6 +67,7 @@ const (
 type ChaincodeStub struct {
 	TxID           string
 	chaincodeEvent *pb.ChaincodeEvent
+	metrics        metricUpdates
 	args           [][]byte
 	handler        *Handler
 	signedProposal *pb.SignedProposal
@@ -81,9 +91,12 @@ var streamGetter peerStreamGetter
 
 //the non-mock user CC stream establishment func
 func userChaincodeStreamGetter(name string) (PeerChaincodeStream, error) {
-	flag.StringVar(&peerAddress, "peer.address", "", "peer address")
+	//the stream is established again when reconnecting
+	if flag.Lookup("peer.address") == nil {
+		flag.StringVar(&peerAddress, "peer.address", "", "peer address")
 
-	flag.Parse()
+		flag.Parse()
+	}
 
 	chaincodeLogger.Debugf("Peer address: %s", getPeerAddress())
 
@@ -133,9 +146,43 @@ func Start(cc Chaincode) error {
 		return err
 	}
 
-	err = chatWithPeer(chaincodename, stream, cc)
+	// The peer waits for the chaincode to register again for the reconnect timeout
+	// after losing the stream, keeping the transactions in flight
+	handler := newChaincodeHandler(stream, cc)
+	reconnectTimeout := viper.GetDuration("chaincode.reconnecttimeout")
+	for reconnected := false; ; reconnected = true {
+		err = chatWithPeer(chaincodename, handler, reconnected)
+		if reconnectTimeout <= 0 {
+			return err
+		}
 
-	return err
+		chaincodeLogger.Warningf("Lost the stream with the peer (%s), reconnecting", err)
+		if stream, err = reconnect(chaincodename, reconnectTimeout); err != nil {
+			return err
+		}
+		handler.resume(stream)
+	}
+}
+
+// reconnect establishes a new stream with the peer, retrying with an exponential backoff
+// until the timeout expires
+func reconnect(chaincodename string, timeout time.Duration) (PeerChaincodeStream, error) {
+	deadline := time.Now().Add(timeout)
+	backoff := 100 * time.Millisecond
+	for {
+		stream, err := streamGetter(chaincodename)
+		if err == nil {
+			return stream, nil
+		}
+		if time.Now().Add(backoff).After(deadline) {
+			return nil, fmt.Errorf("Error reconnecting to peer within %s: %s", timeout, err)
+		}
+		chaincodeLogger.Debugf("Error reconnecting to peer, retrying in %s: %s", backoff, err)
+		time.Sleep(backoff)
+		if backoff *= 2; backoff > maxReconnectBackoff {
+			backoff = maxReconnectBackoff
+		}
+	}
 }
 
 // IsEnabledForLogLevel checks to see if the chaincodeLogger is enabled for a specific logging level
@@ -212,7 +259,7 @@ func StartInProc(env []string, args []string, cc Chaincode, recv <-chan *pb.Chai
 
 	stream := newInProcStream(recv, send)
 	chaincodeLogger.Debugf("starting chat with peer using name=%s", chaincodename)
-	err := chatWithPeer(chaincodename, stream, cc)
+	err := chatWithPeer(chaincodename, newChaincodeHandler(stream, cc), false)
 	return err
 }
 
@@ -236,11 +283,12 @@ func newPeerClientConnection() (*grpc.ClientConn, error) {
 	return comm.NewClientConnectionWithAddress(peerAddress, true, false, nil)
 }
 
-func chatWithPeer(chaincodename string, stream PeerChaincodeStream, cc Chaincode) error {
-
-	// Create the shim handler responsible for all control logic
-	handler := newChaincodeHandler(stream, cc)
-
+// chatWithPeer registers the chaincode on the stream of the handler, responsible for all
+// control logic, and handles the messages until the stream ends. After a reconnection,
+// the messages of the transactions in flight are held back until the peer took the
+// chaincode to ready state again
+func chatWithPeer(chaincodename string, handler *Handler, reconnected bool) error {
+	stream := handler.ChatStream
 	defer stream.CloseSend()
 	// Send the ChaincodeID during register.
 	chaincodeID := &pb.ChaincodeID{Name: chaincodename}
@@ -253,11 +301,22 @@ func chatWithPeer(chaincodename string, stream PeerChaincodeStream, cc Chaincode
 	if err = handler.serialSend(&pb.ChaincodeMessage{Type: pb.ChaincodeMessage_REGISTER, Payload: payload}); err != nil {
 		return fmt.Errorf("Error sending chaincode REGISTER: %s", err)
 	}
+	nextState := handler.nextState
+	if reconnected {
+		nextState = nil
+	}
+
+	//the peer sends a KEEPALIVE every keepalive interval when configured, so the
+	//stream is dead when nothing was received for a few intervals
+	keepalive := time.Duration(viper.GetInt("chaincode.keepalive")) * time.Second
+	lastRecv := time.Now()
+
 	waitc := make(chan struct{})
 	errc := make(chan error)
 	go func() {
 		defer close(waitc)
-		msgAvail := make(chan *pb.ChaincodeMessage)
+		//buffered so that the pending Recv routine does not block once the stream ended
+		msgAvail := make(chan *pb.ChaincodeMessage, 1)
 		var nsInfo *nextStateInfo
 		var in *pb.ChaincodeMessage
 		recv := true
@@ -273,6 +332,10 @@ func chatWithPeer(chaincodename string, stream PeerChaincodeStream, cc Chaincode
 					msgAvail <- in2
 				}()
 			}
+			var silence <-chan time.Time
+			if keepalive > 0 {
+				silence = time.After(lastRecv.Add(keepaliveMisses * keepalive).Sub(time.Now()))
+			}
 			select {
 			case sendErr := <-errc:
 				//serialSendAsync successful?
@@ -295,8 +358,13 @@ func chatWithPeer(chaincodename string, stream PeerChaincodeStream, cc Chaincode
 					return
 				}
 				chaincodeLogger.Debugf("[%s]Received message %s from shim", shorttxid(in.Txid), in.Type.String())
+				lastRecv = time.Now()
 				recv = true
-			case nsInfo = <-handler.nextState:
+			case <-silence:
+				err = fmt.Errorf("No message received from peer for %s, ending chaincode stream", time.Since(lastRecv))
+				chaincodeLogger.Error(err)
+				return
+			case nsInfo = <-nextState:
 				in = nsInfo.msg
 				if in == nil {
 					panic("nil msg")
@@ -311,6 +379,10 @@ func chatWithPeer(chaincodename string, stream PeerChaincodeStream, cc Chaincode
 				return
 			}
 
+			if in.Type == pb.ChaincodeMessage_READY {
+				nextState = handler.nextState
+			}
+
 			//keepalive messages are PONGs to the fabric's PINGs
 			if in.Type == pb.ChaincodeMessage_KEEPALIVE {
 				chaincodeLogger.Debug("Sending KEEPALIVE response")
@@ -705,6 +777,32 @@ func (stub *ChaincodeStub) GetTxTimestamp() (*timestamp.Timestamp, error) {
 	return chdr.GetTimestamp(), nil
 }
 
+// GetTxSeed documentation can be found in interfaces.go
+func (stub *ChaincodeStub) GetTxSeed() ([]byte, error) {
+	if stub.proposal == nil {
+		return nil, errors.New("no proposal to derive the seed of the transaction from")
+	}
+	hdr, err := utils.GetHeader(stub.proposal.Header)
+	if err != nil {
+		return nil, err
+	}
+	chdr, err := utils.UnmarshalChannelHeader(hdr.ChannelHeader)
+	if err != nil {
+		return nil, err
+	}
+	return txSeed(chdr.ChannelId, stub.TxID), nil
+}
+
+// txSeed hashes the channel and transaction IDs, each followed by a zero byte so that
+// they cannot be shifted into one another
+func txSeed(channelID, txID string) []byte {
+	h := sha256.New()
+	h.Write([]byte("fabric/txseed\x00"))
+	h.Write([]byte(channelID + "\x00"))
+	h.Write([]byte(txID + "\x00"))
+	return h.Sum(nil)
+}
+
 // ------------- ChaincodeEvent API ----------------------
 
 // SetEvent documentation can be found in interfaces.go
@@ -716,6 +814,24 @@ func (stub *ChaincodeStub) SetEvent(name string, payload []byte) error {
 	return nil
 }
 
+// ------------- Metrics API ----------------------
+
+// IncCounter documentation can be found in interfaces.go
+func (stub *ChaincodeStub) IncCounter(name string, delta int64) error {
+	if stub.metrics == nil {
+		stub.metrics = make(metricUpdates)
+	}
+	return stub.metrics.incCounter(name, delta)
+}
+
+// SetGauge documentation can be found in interfaces.go
+func (stub *ChaincodeStub) SetGauge(name string, value float64) error {
+	if stub.metrics == nil {
+		stub.metrics = make(metricUpdates)
+	}
+	return stub.metrics.setGauge(name, value)
+}
+
 // ------------- Logging Control and Chaincode Loggers ---------------
 
 // As independent programs, Go language chaincodes can use any logging
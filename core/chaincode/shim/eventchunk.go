@@ -0,0 +1,66 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shim
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	pb "github.com/hyperledger/fabric/protos/peer"
+)
+
+// ChunkedEventPrefix prefixes the names of the events carrying a ChaincodeEventChunk
+// as payload. As a transaction carries a single event, an event larger than the
+// maximum event payload size of the peers is split into chunks set by as many
+// transactions, and reassembled by the clients from the chunks of its event ID
+const ChunkedEventPrefix = "chunk:"
+
+// SplitEventPayload splits payload into chunks of at most size bytes, identified
+// by eventID. At least one chunk is returned, even for an empty payload
+func SplitEventPayload(eventID string, payload []byte, size int) ([]*pb.ChaincodeEventChunk, error) {
+	if eventID == "" {
+		return nil, errors.New("Event ID can not be nil string.")
+	}
+	if size <= 0 {
+		return nil, fmt.Errorf("Invalid chunk size %d", size)
+	}
+	count := (len(payload) + size - 1) / size
+	if count == 0 {
+		count = 1
+	}
+	chunks := make([]*pb.ChaincodeEventChunk, count)
+	for i := range chunks {
+		end := (i + 1) * size
+		if end > len(payload) {
+			end = len(payload)
+		}
+		chunks[i] = &pb.ChaincodeEventChunk{
+			EventId: eventID,
+			Index:   uint32(i),
+			Count:   uint32(count),
+			Data:    payload[i*size : end],
+		}
+	}
+	return chunks, nil
+}
+
+// SetEventChunk sets the event of the transaction to a chunk of the event name.
+// The chunks may be set in any order, by transactions of any block
+func SetEventChunk(stub ChaincodeStubInterface, name string, chunk *pb.ChaincodeEventChunk) error {
+	if chunk.EventId == "" {
+		return errors.New("Event ID can not be nil string.")
+	}
+	if chunk.Index >= chunk.Count {
+		return fmt.Errorf("Chunk index %d is out of the %d chunks of event %s", chunk.Index, chunk.Count, chunk.EventId)
+	}
+	payload, err := proto.Marshal(chunk)
+	if err != nil {
+		return err
+	}
+	return stub.SetEvent(ChunkedEventPrefix+name, payload)
+}
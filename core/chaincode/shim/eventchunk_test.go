@@ -0,0 +1,62 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shim
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+// eventStub records the event set by the chaincode
+type eventStub struct {
+	*MockStub
+	event *pb.ChaincodeEvent
+}
+
+func (s *eventStub) SetEvent(name string, payload []byte) error {
+	s.event = &pb.ChaincodeEvent{EventName: name, Payload: payload}
+	return nil
+}
+
+func TestSplitEventPayload(t *testing.T) {
+	chunks, err := SplitEventPayload("ev1", []byte("0123456789"), 4)
+	assert.NoError(t, err)
+	if assert.Len(t, chunks, 3) {
+		assert.Equal(t, []byte("0123"), chunks[0].Data)
+		assert.Equal(t, []byte("89"), chunks[2].Data)
+		for i, chunk := range chunks {
+			assert.Equal(t, "ev1", chunk.EventId)
+			assert.Equal(t, uint32(i), chunk.Index)
+			assert.Equal(t, uint32(3), chunk.Count)
+		}
+	}
+
+	chunks, err = SplitEventPayload("ev1", nil, 4)
+	assert.NoError(t, err)
+	assert.Len(t, chunks, 1, "Expected a single chunk for an empty payload")
+
+	_, err = SplitEventPayload("", []byte("payload"), 4)
+	assert.Error(t, err)
+	_, err = SplitEventPayload("ev1", []byte("payload"), 0)
+	assert.Error(t, err)
+}
+
+func TestSetEventChunk(t *testing.T) {
+	stub := &eventStub{MockStub: NewMockStub("chunks", nil)}
+	chunk := &pb.ChaincodeEventChunk{EventId: "ev1", Index: 1, Count: 2, Data: []byte("data")}
+	assert.NoError(t, SetEventChunk(stub, "report", chunk))
+	assert.Equal(t, ChunkedEventPrefix+"report", stub.event.EventName)
+	set := &pb.ChaincodeEventChunk{}
+	assert.NoError(t, proto.Unmarshal(stub.event.Payload, set))
+	assert.True(t, proto.Equal(chunk, set))
+
+	assert.Error(t, SetEventChunk(stub, "report", &pb.ChaincodeEventChunk{Index: 0, Count: 1}), "Expected an error without event ID")
+	assert.Error(t, SetEventChunk(stub, "report", &pb.ChaincodeEventChunk{EventId: "ev1", Index: 2, Count: 2}), "Expected an error for an index out of range")
+}
@@ -163,9 +163,13 @@ func newChaincodeHandler(peerChatStream PeerChaincodeStream, chaincode Chaincode
 	}
 	v.responseChannel = make(map[string]chan pb.ChaincodeMessage)
 	v.nextState = make(chan *nextStateInfo)
+	v.FSM = v.newFSM()
+	return v
+}
 
-	// Create the shim side FSM
-	v.FSM = fsm.NewFSM(
+// newFSM creates the shim side FSM, in created state
+func (handler *Handler) newFSM() *fsm.FSM {
+	return fsm.NewFSM(
 		"created",
 		fsm.Events{
 			{Name: pb.ChaincodeMessage_REGISTERED.String(), Src: []string{"created"}, Dst: "established"},
@@ -180,14 +184,32 @@ func newChaincodeHandler(peerChatStream PeerChaincodeStream, chaincode Chaincode
 			{Name: pb.ChaincodeMessage_COMPLETED.String(), Src: []string{"ready"}, Dst: "ready"},
 		},
 		fsm.Callbacks{
-			"before_" + pb.ChaincodeMessage_REGISTERED.String():  func(e *fsm.Event) { v.beforeRegistered(e) },
-			"after_" + pb.ChaincodeMessage_RESPONSE.String():     func(e *fsm.Event) { v.afterResponse(e) },
-			"after_" + pb.ChaincodeMessage_ERROR.String():        func(e *fsm.Event) { v.afterError(e) },
-			"before_" + pb.ChaincodeMessage_INIT.String():        func(e *fsm.Event) { v.beforeInit(e) },
-			"before_" + pb.ChaincodeMessage_TRANSACTION.String(): func(e *fsm.Event) { v.beforeTransaction(e) },
+			"before_" + pb.ChaincodeMessage_REGISTERED.String():  func(e *fsm.Event) { handler.beforeRegistered(e) },
+			"after_" + pb.ChaincodeMessage_RESPONSE.String():     func(e *fsm.Event) { handler.afterResponse(e) },
+			"after_" + pb.ChaincodeMessage_ERROR.String():        func(e *fsm.Event) { handler.afterError(e) },
+			"before_" + pb.ChaincodeMessage_INIT.String():        func(e *fsm.Event) { handler.beforeInit(e) },
+			"before_" + pb.ChaincodeMessage_TRANSACTION.String(): func(e *fsm.Event) { handler.beforeTransaction(e) },
 		},
 	)
-	return v
+}
+
+// resume prepares the handler to register again on a new stream, after the previous one
+// was lost. The transactions in flight carry on, except for their requests waiting for a
+// response of the peer, which may have been lost with the stream: these fail
+func (handler *Handler) resume(stream PeerChaincodeStream) {
+	handler.serialLock.Lock()
+	handler.ChatStream = stream
+	handler.serialLock.Unlock()
+
+	handler.FSM = handler.newFSM()
+
+	handler.Lock()
+	defer handler.Unlock()
+	for txid, c := range handler.responseChannel {
+		msg := pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: []byte("The stream with the peer was lost"), Txid: txid}
+		chaincodeLogger.Warningf("[%s]Failing the request in flight: the stream with the peer was lost", shorttxid(txid))
+		go func(c chan pb.ChaincodeMessage) { c <- msg }(c)
+	}
 }
 
 // beforeRegistered is called to handle the REGISTERED message.
@@ -209,7 +231,14 @@ func (handler *Handler) handleInit(msg *pb.ChaincodeMessage) {
 
 		send := true
 
+		// The stub is created upfront, so that the metrics updated by the
+		// chaincode are sent whatever the outcome
+		stub := new(ChaincodeStub)
+
 		defer func() {
+			if nextStateMsg != nil {
+				nextStateMsg.Metrics = stub.metrics.list()
+			}
 			handler.triggerNextState(nextStateMsg, send)
 		}()
 
@@ -232,8 +261,7 @@ func (handler *Handler) handleInit(msg *pb.ChaincodeMessage) {
 		}
 
 		// Call chaincode's Run
-		// Create the ChaincodeStub which the chaincode can use to callback
-		stub := new(ChaincodeStub)
+		// Initialize the ChaincodeStub which the chaincode can use to callback
 		err := stub.init(handler, msg.Txid, input, msg.Proposal)
 		if nextStateMsg = errFunc(err, nil, stub.chaincodeEvent, "[%s]Init get error response [%s]. Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_ERROR.String()); nextStateMsg != nil {
 			return
@@ -286,7 +314,14 @@ func (handler *Handler) handleTransaction(msg *pb.ChaincodeMessage) {
 
 		send := true
 
+		// The stub is created upfront, so that the metrics updated by the
+		// chaincode are sent whatever the outcome
+		stub := new(ChaincodeStub)
+
 		defer func() {
+			if nextStateMsg != nil {
+				nextStateMsg.Metrics = stub.metrics.list()
+			}
 			handler.triggerNextState(nextStateMsg, send)
 		}()
 
@@ -307,8 +342,7 @@ func (handler *Handler) handleTransaction(msg *pb.ChaincodeMessage) {
 		}
 
 		// Call chaincode's Run
-		// Create the ChaincodeStub which the chaincode can use to callback
-		stub := new(ChaincodeStub)
+		// Initialize the ChaincodeStub which the chaincode can use to callback
 		err := stub.init(handler, msg.Txid, input, msg.Proposal)
 		if nextStateMsg = errFunc(err, stub.chaincodeEvent, "[%s]Transaction execution failed. Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_ERROR.String()); nextStateMsg != nil {
 			return
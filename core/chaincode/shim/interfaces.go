@@ -188,10 +188,32 @@ type ChaincodeStubInterface interface {
 	// client's timestamp, and will have the same value across all endorsers.
 	GetTxTimestamp() (*timestamp.Timestamp, error)
 
+	// GetTxSeed returns a 32 bytes seed derived from the channel and the ID of
+	// the transaction, which is the same on all the endorsers and differs
+	// between transactions. The chaincode seeds its pseudo-random choices, e.g.
+	// to break ties, with it (see NewTxRand) rather than reading a source of
+	// randomness, whose values differ between the endorsers. As the seed is
+	// derived from public values, and the client chooses the nonce the ID of
+	// the transaction is computed from, it must not be relied upon where the
+	// client must not predict or choose the outcome.
+	GetTxSeed() ([]byte, error)
+
 	// SetEvent allows the chaincode to propose an event on the transaction
 	// proposal. If the transaction is validated and successfully committed,
 	// the event will be delivered to the current event listeners.
 	SetEvent(name string, payload []byte) error
+
+	// IncCounter adds delta, which must not be negative, to the counter `name`
+	// of the chaincode, and SetGauge sets its gauge `name` to value. The names
+	// are made of at most 64 letters, digits and underscores, and a name is
+	// either a counter or a gauge. The metrics updated by the transaction are
+	// sent to the peer with its response, whatever its outcome, so that e.g.
+	// the rejected transfers can be counted, and the peer aggregates them
+	// under chaincode.<chaincode name>.app.<name>. As the metrics are updated
+	// by the peers executing the chaincode, they count the simulations of the
+	// proposals endorsed by the peer rather than the transactions committed.
+	IncCounter(name string, delta int64) error
+	SetGauge(name string, value float64) error
 }
 
 // CommonIteratorInterface allows a chaincode to check whether any more result
@@ -0,0 +1,80 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shim
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	pb "github.com/hyperledger/fabric/protos/peer"
+)
+
+// validMetricName matches the names of the metrics a chaincode may emit
+var validMetricName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]{0,63}$`)
+
+func checkMetricName(name string) error {
+	if !validMetricName.MatchString(name) {
+		return fmt.Errorf("Invalid metric name %q: it must be at most 64 letters, digits and underscores, and not start with a digit", name)
+	}
+	return nil
+}
+
+// metricUpdates aggregates the updates of the metrics of the chaincode by a transaction, by name
+type metricUpdates map[string]*pb.ChaincodeMetric
+
+func (u metricUpdates) get(name string, metricType pb.ChaincodeMetric_Type) (*pb.ChaincodeMetric, error) {
+	if err := checkMetricName(name); err != nil {
+		return nil, err
+	}
+	metric, ok := u[name]
+	if !ok {
+		metric = &pb.ChaincodeMetric{Name: name, Type: metricType}
+		u[name] = metric
+	} else if metric.Type != metricType {
+		return nil, fmt.Errorf("Metric %s is a %s, not a %s", name, metric.Type, metricType)
+	}
+	return metric, nil
+}
+
+func (u metricUpdates) incCounter(name string, delta int64) error {
+	if delta < 0 {
+		return fmt.Errorf("Counter %s can not be decremented", name)
+	}
+	metric, err := u.get(name, pb.ChaincodeMetric_COUNTER)
+	if err != nil {
+		return err
+	}
+	metric.Increment += delta
+	return nil
+}
+
+func (u metricUpdates) setGauge(name string, value float64) error {
+	metric, err := u.get(name, pb.ChaincodeMetric_GAUGE)
+	if err != nil {
+		return err
+	}
+	metric.Value = value
+	return nil
+}
+
+// list returns the updates sorted by name, or nil if there are none
+func (u metricUpdates) list() []*pb.ChaincodeMetric {
+	if len(u) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(u))
+	for name := range u {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	metrics := make([]*pb.ChaincodeMetric, len(names))
+	for i, name := range names {
+		metrics[i] = u[name]
+	}
+	return metrics
+}
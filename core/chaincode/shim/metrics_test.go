@@ -0,0 +1,101 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shim
+
+import (
+	"strings"
+	"testing"
+
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// metricsCC counts the transfers and sets the gauge of the pending ones, and
+// rejects the transfers of a negative amount
+type metricsCC struct{}
+
+func (metricsCC) Init(stub ChaincodeStubInterface) pb.Response {
+	return Success(nil)
+}
+
+func (metricsCC) Invoke(stub ChaincodeStubInterface) pb.Response {
+	_, args := stub.GetFunctionAndParameters()
+	if len(args) > 0 && strings.HasPrefix(args[0], "-") {
+		stub.IncCounter("transfers_rejected", 1)
+		return Error("negative amount")
+	}
+	stub.IncCounter("transfers", 1)
+	stub.IncCounter("transfers", 1)
+	stub.SetGauge("pending", 3)
+	return Success(nil)
+}
+
+func TestMetricUpdates(t *testing.T) {
+	updates := make(metricUpdates)
+	assert.Nil(t, updates.list())
+
+	assert.NoError(t, updates.incCounter("b_counter", 2))
+	assert.NoError(t, updates.incCounter("b_counter", 3))
+	assert.NoError(t, updates.setGauge("a_gauge", 1.5))
+	assert.NoError(t, updates.setGauge("a_gauge", 0.5))
+	assert.Error(t, updates.incCounter("b_counter", -1), "Expected an error for a decrement")
+	assert.Error(t, updates.setGauge("b_counter", 1), "Expected an error for a counter set as a gauge")
+	assert.Error(t, updates.incCounter("a_gauge", 1), "Expected an error for a gauge incremented as a counter")
+	for _, name := range []string{"", "1st", "with.dot", "with space", strings.Repeat("a", 65)} {
+		assert.Error(t, updates.incCounter(name, 1), "Expected an error for name %q", name)
+	}
+
+	metrics := updates.list()
+	require.Len(t, metrics, 2)
+	assert.Equal(t, &pb.ChaincodeMetric{Name: "a_gauge", Type: pb.ChaincodeMetric_GAUGE, Value: 0.5}, metrics[0])
+	assert.Equal(t, &pb.ChaincodeMetric{Name: "b_counter", Type: pb.ChaincodeMetric_COUNTER, Increment: 5}, metrics[1])
+}
+
+func TestMetricsSentWithResponse(t *testing.T) {
+	handler := &Handler{cc: metricsCC{}, nextState: make(chan *nextStateInfo, 1)}
+
+	execute := func(txid string, args ...string) *pb.ChaincodeMessage {
+		input := &pb.ChaincodeInput{Args: [][]byte{[]byte("transfer")}}
+		for _, arg := range args {
+			input.Args = append(input.Args, []byte(arg))
+		}
+		handler.handleTransaction(&pb.ChaincodeMessage{Type: pb.ChaincodeMessage_TRANSACTION, Txid: txid, Payload: utils.MarshalOrPanic(input)})
+		return (<-handler.nextState).msg
+	}
+
+	msg := execute("1", "10")
+	assert.Equal(t, pb.ChaincodeMessage_COMPLETED, msg.Type)
+	assert.Equal(t, []*pb.ChaincodeMetric{
+		{Name: "pending", Type: pb.ChaincodeMetric_GAUGE, Value: 3},
+		{Name: "transfers", Type: pb.ChaincodeMetric_COUNTER, Increment: 2},
+	}, msg.Metrics)
+
+	msg = execute("2", "-10")
+	assert.Equal(t, []*pb.ChaincodeMetric{{Name: "transfers_rejected", Type: pb.ChaincodeMetric_COUNTER, Increment: 1}}, msg.Metrics,
+		"Expected the metrics of a rejected transaction to be sent")
+
+	handler.handleTransaction(&pb.ChaincodeMessage{Type: pb.ChaincodeMessage_TRANSACTION, Txid: "3", Payload: []byte("garbage")})
+	msg = (<-handler.nextState).msg
+	assert.Equal(t, pb.ChaincodeMessage_ERROR, msg.Type)
+	assert.Nil(t, msg.Metrics)
+}
+
+func TestMockStubMetrics(t *testing.T) {
+	stub := NewMockStub("metrics", metricsCC{})
+	stub.MockInvoke("1", [][]byte{[]byte("transfer"), []byte("10")})
+	stub.MockInvoke("2", [][]byte{[]byte("transfer"), []byte("-10")})
+	stub.MockInvoke("3", [][]byte{[]byte("transfer"), []byte("5")})
+	assert.Equal(t, map[string]int64{"transfers": 4, "transfers_rejected": 1}, stub.Counters)
+	assert.Equal(t, map[string]float64{"pending": 3}, stub.Gauges)
+
+	assert.Error(t, stub.SetGauge("transfers", 1))
+	assert.Error(t, stub.IncCounter("pending", 1))
+	assert.Error(t, stub.IncCounter("transfers", -1))
+	assert.Error(t, stub.IncCounter("in valid", 1))
+}
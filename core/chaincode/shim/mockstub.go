@@ -64,6 +64,10 @@ type MockStub struct {
 
 	// mocked signedProposal
 	signedProposal *pb.SignedProposal
+
+	// Counters and Gauges hold the metrics emitted by the chaincode, accumulated over the transactions
+	Counters map[string]int64
+	Gauges   map[string]float64
 }
 
 func (stub *MockStub) GetTxID() string {
@@ -317,11 +321,44 @@ func (stub *MockStub) GetTxTimestamp() (*timestamp.Timestamp, error) {
 	return stub.TxTimestamp, nil
 }
 
+// GetTxSeed derives the seed from the TxID alone, as the MockStub has no channel
+func (stub *MockStub) GetTxSeed() ([]byte, error) {
+	if stub.TxID == "" {
+		return nil, errors.New("TxID not set.")
+	}
+	return txSeed("", stub.TxID), nil
+}
+
 // Not implemented
 func (stub *MockStub) SetEvent(name string, payload []byte) error {
 	return nil
 }
 
+func (stub *MockStub) IncCounter(name string, delta int64) error {
+	if err := checkMetricName(name); err != nil {
+		return err
+	}
+	if _, ok := stub.Gauges[name]; ok {
+		return fmt.Errorf("Metric %s is a GAUGE, not a COUNTER", name)
+	}
+	if delta < 0 {
+		return fmt.Errorf("Counter %s can not be decremented", name)
+	}
+	stub.Counters[name] += delta
+	return nil
+}
+
+func (stub *MockStub) SetGauge(name string, value float64) error {
+	if err := checkMetricName(name); err != nil {
+		return err
+	}
+	if _, ok := stub.Counters[name]; ok {
+		return fmt.Errorf("Metric %s is a COUNTER, not a GAUGE", name)
+	}
+	stub.Gauges[name] = value
+	return nil
+}
+
 // Constructor to initialise the internal State map
 func NewMockStub(name string, cc Chaincode) *MockStub {
 	mockLogger.Debug("MockStub(", name, cc, ")")
@@ -331,6 +368,8 @@ func NewMockStub(name string, cc Chaincode) *MockStub {
 	s.State = make(map[string][]byte)
 	s.Invokables = make(map[string]*MockStub)
 	s.Keys = list.New()
+	s.Counters = make(map[string]int64)
+	s.Gauges = make(map[string]float64)
 
 	return s
 }
@@ -0,0 +1,63 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shim
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+// TxRand generates pseudo-random numbers from the seed of a transaction, which
+// are the same on all the endorsers of the transaction. The numbers are the
+// hashes of the seed and of a counter, so that, unlike those of math/rand, they
+// do not depend on the Go version the chaincode is built with. TxRand is not
+// safe for concurrent use
+type TxRand struct {
+	seed    []byte
+	counter uint64
+}
+
+// NewTxRand returns a TxRand seeded with the GetTxSeed of the stub
+func NewTxRand(stub ChaincodeStubInterface) (*TxRand, error) {
+	seed, err := stub.GetTxSeed()
+	if err != nil {
+		return nil, err
+	}
+	return &TxRand{seed: seed}, nil
+}
+
+// Uint64 returns a pseudo-random uint64
+func (r *TxRand) Uint64() uint64 {
+	h := sha256.New()
+	h.Write(r.seed)
+	binary.Write(h, binary.BigEndian, r.counter)
+	r.counter++
+	return binary.BigEndian.Uint64(h.Sum(nil))
+}
+
+// Intn returns a pseudo-random int in [0, n). It panics if n <= 0
+func (r *TxRand) Intn(n int) int {
+	if n <= 0 {
+		panic("invalid argument to Intn")
+	}
+	// reject the values beyond the largest multiple of n, which would favor the
+	// smallest results
+	max := ^uint64(0) - ^uint64(0)%uint64(n)
+	for {
+		if v := r.Uint64(); v < max {
+			return int(v % uint64(n))
+		}
+	}
+}
+
+// Shuffle pseudo-randomizes the order of n elements, swapping the elements
+// with indexes i and j with swap
+func (r *TxRand) Shuffle(n int, swap func(i, j int)) {
+	for i := n - 1; i > 0; i-- {
+		swap(i, r.Intn(i+1))
+	}
+}
@@ -0,0 +1,80 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shim
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTxSeed(t *testing.T) {
+	cis := &pb.ChaincodeInvocationSpec{ChaincodeSpec: &pb.ChaincodeSpec{ChaincodeId: &pb.ChaincodeID{Name: "mycc"}}}
+	prop, txid, err := utils.CreateChaincodeProposal(common.HeaderType_ENDORSER_TRANSACTION, "mychannel", cis, []byte("creator"))
+	require.NoError(t, err)
+
+	stub := &ChaincodeStub{TxID: txid, proposal: prop}
+	seed, err := stub.GetTxSeed()
+	assert.NoError(t, err)
+	assert.Len(t, seed, 32)
+	assert.Equal(t, txSeed("mychannel", txid), seed)
+	assert.NotEqual(t, txSeed("otherchannel", txid), seed)
+	assert.NotEqual(t, txSeed("mychannel", txid+"1"), seed)
+	assert.NotEqual(t, txSeed("mychannel"+txid[:1], txid[1:]), seed, "Expected the channel and transaction IDs not to be shifted")
+
+	_, err = (&ChaincodeStub{TxID: txid}).GetTxSeed()
+	assert.Error(t, err, "Expected an error without a proposal")
+
+	mock := NewMockStub("mycc", nil)
+	_, err = mock.GetTxSeed()
+	assert.Error(t, err, "Expected an error outside of a transaction")
+	mock.MockTransactionStart(txid)
+	seed, err = mock.GetTxSeed()
+	assert.NoError(t, err)
+	assert.Equal(t, txSeed("", txid), seed)
+}
+
+func TestTxRand(t *testing.T) {
+	mock := NewMockStub("mycc", nil)
+	_, err := NewTxRand(mock)
+	assert.Error(t, err)
+
+	mock.MockTransactionStart("tx1")
+	r1, err := NewTxRand(mock)
+	require.NoError(t, err)
+	r2, err := NewTxRand(mock)
+	require.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, r1.Uint64(), r2.Uint64(), "Expected the same values from the same seed")
+	}
+	mock.MockTransactionStart("tx2")
+	r3, err := NewTxRand(mock)
+	require.NoError(t, err)
+	assert.NotEqual(t, r1.Uint64(), r3.Uint64(), "Expected other values for another transaction")
+
+	counts := make([]int, 3)
+	for i := 0; i < 300; i++ {
+		v := r1.Intn(3)
+		require.True(t, v >= 0 && v < 3)
+		counts[v]++
+	}
+	for _, count := range counts {
+		assert.True(t, count > 50, "Expected the values to be spread, got %v", counts)
+	}
+	assert.Panics(t, func() { r1.Intn(0) })
+
+	values := []int{0, 1, 2, 3, 4, 5, 6, 7}
+	r3.Shuffle(len(values), func(i, j int) { values[i], values[j] = values[j], values[i] })
+	assert.NotEqual(t, []int{0, 1, 2, 3, 4, 5, 6, 7}, values)
+	sort.Ints(values)
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7}, values)
+}
@@ -0,0 +1,237 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric/common/metrics"
+	gometrics "github.com/rcrowley/go-metrics"
+)
+
+// BreakerConfig configures an EndpointBreaker
+type BreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures after which an
+	// endpoint is removed from rotation
+	FailureThreshold int
+	// OpenInterval is the time during which an endpoint stays out of rotation.
+	// Once it elapses, the endpoint is tried again, and removed anew if it
+	// fails once more
+	OpenInterval time.Duration
+	// ProbeInterval is the time after which an endpoint which was not called
+	// is ranked first, so that its health is measured again. 0 disables the
+	// probing
+	ProbeInterval time.Duration
+}
+
+// healthSmoothing is the weight of the last call in the moving averages of the
+// latency and of the error rate of an endpoint
+const healthSmoothing = 0.2
+
+// minSuccessRate bounds the success rate dividing the latency of an endpoint in
+// its expected latency
+const minSuccessRate = 0.01
+
+// EndpointBreaker is a circuit breaker over a set of endpoints. It keeps the
+// endpoints which keep failing out of rotation for a while, and publishes the
+// health of each endpoint as the metrics <prefix>.<endpoint>.failures, counting
+// the failed calls, <prefix>.<endpoint>.latency, timing the successful ones,
+// and <prefix>.<endpoint>.open, which is 1 while the endpoint is out of
+// rotation and 0 otherwise. It also keeps a moving average of the latency and
+// of the error rate of each endpoint, by which Ranked orders the endpoints, the
+// latter being published as <prefix>.<endpoint>.errorRate.
+type EndpointBreaker struct {
+	sync.Mutex
+	prefix    string
+	conf      BreakerConfig
+	endpoints map[string]*endpointHealth
+}
+
+type endpointHealth struct {
+	// consecutiveFailures is the number of failures since the last success
+	consecutiveFailures int
+	// openUntil is the end of the time the endpoint is out of rotation
+	openUntil time.Time
+	// latency is the moving average of the latency of the successful calls,
+	// 0 until the first one
+	latency time.Duration
+	// errorRate is the moving average of the rate of failed calls
+	errorRate float64
+	// lastCall is the start of the last call, zero until the first one
+	lastCall time.Time
+	failures gometrics.Counter
+	timer    gometrics.Timer
+	open     gometrics.Gauge
+	errors   gometrics.GaugeFloat64
+}
+
+// NewEndpointBreaker creates an EndpointBreaker publishing its metrics under
+// the given prefix
+func NewEndpointBreaker(prefix string, conf BreakerConfig) *EndpointBreaker {
+	return &EndpointBreaker{
+		prefix:    prefix,
+		conf:      conf,
+		endpoints: make(map[string]*endpointHealth),
+	}
+}
+
+func (b *EndpointBreaker) health(endpoint string) *endpointHealth {
+	h, exists := b.endpoints[endpoint]
+	if !exists {
+		name := func(metric string) string {
+			return b.prefix + "." + endpoint + "." + metric
+		}
+		h = &endpointHealth{
+			failures: gometrics.GetOrRegisterCounter(name("failures"), metrics.Registry),
+			timer:    gometrics.GetOrRegisterTimer(name("latency"), metrics.Registry),
+			open:     gometrics.GetOrRegisterGauge(name("open"), metrics.Registry),
+			errors:   gometrics.GetOrRegisterGaugeFloat64(name("errorRate"), metrics.Registry),
+		}
+		b.endpoints[endpoint] = h
+	}
+	return h
+}
+
+// Available returns the given endpoints which are in rotation, in the same
+// order, or all of them if none is, so that the callers keep trying
+func (b *EndpointBreaker) Available(endpoints []string) []string {
+	b.Lock()
+	defer b.Unlock()
+
+	var available []string
+	now := time.Now()
+	for _, endpoint := range endpoints {
+		h := b.health(endpoint)
+		if now.Before(h.openUntil) {
+			continue
+		}
+		h.open.Update(0)
+		available = append(available, endpoint)
+	}
+	if len(available) == 0 {
+		return endpoints
+	}
+	return available
+}
+
+// Done records the outcome of a call to the endpoint which started at start
+func (b *EndpointBreaker) Done(endpoint string, start time.Time, err error) {
+	b.Lock()
+	defer b.Unlock()
+
+	h := b.health(endpoint)
+	h.record(start, err)
+	if err == nil {
+		h.timer.UpdateSince(start)
+		h.consecutiveFailures = 0
+		h.openUntil = time.Time{}
+		h.open.Update(0)
+		return
+	}
+
+	h.failures.Inc(1)
+	h.consecutiveFailures++
+	// An endpoint which was taken out of rotation before goes out again on its
+	// first failure
+	if h.consecutiveFailures >= b.conf.FailureThreshold || !h.openUntil.IsZero() {
+		logger.Warningf("Removing endpoint %s from rotation for %s after %d consecutive failures, last one: %s",
+			endpoint, b.conf.OpenInterval, h.consecutiveFailures, err)
+		h.openUntil = time.Now().Add(b.conf.OpenInterval)
+		h.open.Update(1)
+	}
+}
+
+// Ranked returns the endpoints of Available, the healthiest first: the lowest
+// expected latency first, that is the average latency of an endpoint divided by
+// its success rate. The endpoints never called, and, if probing is enabled, the
+// ones not called for ProbeInterval, come first, so that a degraded endpoint is
+// measured again once it may have recovered rather than being left aside for
+// good. Equally healthy endpoints keep their order.
+func (b *EndpointBreaker) Ranked(endpoints []string) []string {
+	available := b.Available(endpoints)
+
+	b.Lock()
+	defer b.Unlock()
+
+	now := time.Now()
+	ranked := &rankedEndpoints{
+		endpoints: append([]string(nil), available...),
+		probe:     make([]bool, len(available)),
+		expected:  make([]float64, len(available)),
+	}
+	for i, endpoint := range available {
+		h := b.health(endpoint)
+		ranked.probe[i] = h.lastCall.IsZero() || (b.conf.ProbeInterval > 0 && now.Sub(h.lastCall) >= b.conf.ProbeInterval)
+		ranked.expected[i] = h.expectedLatency()
+	}
+	sort.Stable(ranked)
+	return ranked.endpoints
+}
+
+// record updates the moving averages of the endpoint with a call which started
+// at start
+func (h *endpointHealth) record(start time.Time, err error) {
+	first := h.lastCall.IsZero()
+	h.lastCall = start
+
+	failed := 0.0
+	if err != nil {
+		failed = 1
+	}
+	if first {
+		h.errorRate = failed
+	} else {
+		h.errorRate += healthSmoothing * (failed - h.errorRate)
+	}
+	h.errors.Update(h.errorRate)
+
+	if err != nil {
+		return
+	}
+	latency := time.Since(start)
+	if h.latency == 0 {
+		h.latency = latency
+	} else {
+		h.latency += time.Duration(healthSmoothing * float64(latency-h.latency))
+	}
+}
+
+// expectedLatency returns the expected time for a successful call to the
+// endpoint, +Inf if no call ever succeeded
+func (h *endpointHealth) expectedLatency() float64 {
+	if h.latency == 0 {
+		return math.Inf(1)
+	}
+	return float64(h.latency) / math.Max(1-h.errorRate, minSuccessRate)
+}
+
+// rankedEndpoints sorts endpoints to probe first, then by expected latency
+type rankedEndpoints struct {
+	endpoints []string
+	probe     []bool
+	expected  []float64
+}
+
+func (r *rankedEndpoints) Len() int {
+	return len(r.endpoints)
+}
+
+func (r *rankedEndpoints) Less(i, j int) bool {
+	if r.probe[i] != r.probe[j] {
+		return r.probe[i]
+	}
+	return r.expected[i] < r.expected[j]
+}
+
+func (r *rankedEndpoints) Swap(i, j int) {
+	r.endpoints[i], r.endpoints[j] = r.endpoints[j], r.endpoints[i]
+	r.probe[i], r.probe[j] = r.probe[j], r.probe[i]
+	r.expected[i], r.expected[j] = r.expected[j], r.expected[i]
+}
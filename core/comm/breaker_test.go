@@ -0,0 +1,95 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/common/metrics"
+	gometrics "github.com/rcrowley/go-metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEndpointBreaker(t *testing.T) {
+	b := NewEndpointBreaker("test.breaker", BreakerConfig{FailureThreshold: 2, OpenInterval: 100 * time.Millisecond})
+	endpoints := []string{"a:7050", "b:7050", "c:7050"}
+	failure := errors.New("unavailable")
+
+	assert.Equal(t, endpoints, b.Available(endpoints))
+
+	b.Done("a:7050", time.Now(), failure)
+	assert.Equal(t, endpoints, b.Available(endpoints), "Should keep an endpoint below the threshold in rotation")
+	b.Done("a:7050", time.Now(), failure)
+	assert.Equal(t, []string{"b:7050", "c:7050"}, b.Available(endpoints))
+
+	b.Done("b:7050", time.Now(), failure)
+	b.Done("b:7050", time.Now(), nil)
+	b.Done("b:7050", time.Now(), failure)
+	assert.Equal(t, []string{"b:7050", "c:7050"}, b.Available(endpoints), "A success should reset the consecutive failures")
+
+	counter := gometrics.GetOrRegisterCounter("test.breaker.a:7050.failures", metrics.Registry)
+	assert.Equal(t, int64(2), counter.Count())
+	gauge := gometrics.GetOrRegisterGauge("test.breaker.a:7050.open", metrics.Registry)
+	assert.Equal(t, int64(1), gauge.Value())
+	timer := gometrics.GetOrRegisterTimer("test.breaker.b:7050.latency", metrics.Registry)
+	assert.Equal(t, int64(1), timer.Count())
+
+	time.Sleep(150 * time.Millisecond)
+	assert.Equal(t, endpoints, b.Available(endpoints), "Should try the endpoint again after the interval")
+	assert.Equal(t, int64(0), gauge.Value())
+	b.Done("a:7050", time.Now(), failure)
+	assert.Equal(t, []string{"b:7050", "c:7050"}, b.Available(endpoints), "Should remove the endpoint on its first failure after the interval")
+
+	time.Sleep(150 * time.Millisecond)
+	b.Done("a:7050", time.Now(), nil)
+	b.Done("a:7050", time.Now(), failure)
+	assert.Equal(t, endpoints, b.Available(endpoints), "Should close the breaker on a success")
+
+	b.Done("b:7050", time.Now(), failure)
+	b.Done("b:7050", time.Now(), failure)
+	b.Done("c:7050", time.Now(), failure)
+	b.Done("c:7050", time.Now(), failure)
+	b.Done("a:7050", time.Now(), failure)
+	assert.Equal(t, endpoints, b.Available(endpoints), "Should keep trying when all the endpoints are out of rotation")
+}
+
+func TestEndpointBreakerRanked(t *testing.T) {
+	b := NewEndpointBreaker("test.ranked", BreakerConfig{FailureThreshold: 5, OpenInterval: time.Minute, ProbeInterval: 200 * time.Millisecond})
+	endpoints := []string{"a:7050", "b:7050", "c:7050", "d:7050"}
+	failure := errors.New("unavailable")
+
+	assert.Equal(t, endpoints, b.Ranked(endpoints), "Should keep the order of the endpoints never called")
+
+	b.Done("a:7050", time.Now().Add(-12*time.Millisecond), nil)
+	b.Done("b:7050", time.Now().Add(-10*time.Millisecond), nil)
+	b.Done("c:7050", time.Now(), failure)
+	assert.Equal(t, []string{"d:7050", "b:7050", "a:7050", "c:7050"}, b.Ranked(endpoints),
+		"Should probe the endpoint never called, then rank the fastest first and the ones never successful last")
+
+	b.Done("d:7050", time.Now().Add(-8*time.Millisecond), nil)
+	for i := 0; i < 4; i++ {
+		b.Done("d:7050", time.Now(), failure)
+	}
+	assert.Equal(t, []string{"b:7050", "a:7050", "d:7050", "c:7050"}, b.Ranked(endpoints),
+		"Should rank an endpoint whose calls keep failing after the slower but reliable ones")
+	gauge := gometrics.GetOrRegisterGaugeFloat64("test.ranked.d:7050.errorRate", metrics.Registry)
+	assert.InDelta(t, 0.5904, gauge.Value(), 0.001)
+
+	time.Sleep(250 * time.Millisecond)
+	b.Done("a:7050", time.Now().Add(-12*time.Millisecond), nil)
+	b.Done("b:7050", time.Now().Add(-10*time.Millisecond), nil)
+	b.Done("d:7050", time.Now().Add(-8*time.Millisecond), nil)
+	assert.Equal(t, []string{"c:7050", "b:7050", "a:7050", "d:7050"}, b.Ranked(endpoints),
+		"Should probe the endpoint not called for the probe interval first")
+
+	for i := 0; i < 4; i++ {
+		b.Done("c:7050", time.Now(), failure)
+	}
+	assert.Equal(t, []string{"b:7050", "a:7050", "d:7050"}, b.Ranked(endpoints), "Should leave out the endpoints out of rotation")
+}
@@ -11,10 +11,12 @@ import (
 	"crypto/x509"
 	"encoding/pem"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"sync"
 	"time"
 
+	"github.com/hyperledger/fabric/common/crypto/fips"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/core/config"
 	"github.com/spf13/viper"
@@ -105,7 +107,7 @@ func (cas *CASupport) GetDeliverServiceCredentials(channelID string) (credential
 		}
 	}
 	tlsConfig.RootCAs = certPool
-	creds = credentials.NewTLS(tlsConfig)
+	creds = credentials.NewTLS(fips.ConfigureTLS(tlsConfig))
 	return creds, nil
 }
 
@@ -133,7 +135,7 @@ func (cas *CASupport) GetPeerCredentials(tlsCert tls.Certificate) credentials.Tr
 		}
 	}
 	tlsConfig.RootCAs = certPool
-	creds = credentials.NewTLS(tlsConfig)
+	creds = credentials.NewTLS(fips.ConfigureTLS(tlsConfig))
 	return creds
 }
 
@@ -201,15 +203,16 @@ func InitTLSForPeer() credentials.TransportCredentials {
 	if viper.GetString("peer.tls.serverhostoverride") != "" {
 		sn = viper.GetString("peer.tls.serverhostoverride")
 	}
-	var creds credentials.TransportCredentials
+	tlsConfig := &tls.Config{ServerName: sn}
 	if config.GetPath("peer.tls.rootcert.file") != "" {
-		var err error
-		creds, err = credentials.NewClientTLSFromFile(config.GetPath("peer.tls.rootcert.file"), sn)
+		rootCert, err := ioutil.ReadFile(config.GetPath("peer.tls.rootcert.file"))
 		if err != nil {
 			grpclog.Fatalf("Failed to create TLS credentials %v", err)
 		}
-	} else {
-		creds = credentials.NewClientTLSFromCert(nil, sn)
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(rootCert) {
+			grpclog.Fatalf("Failed to create TLS credentials: failed to append certificates")
+		}
 	}
-	return creds
+	return credentials.NewTLS(fips.ConfigureTLS(tlsConfig))
 }
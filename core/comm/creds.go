@@ -11,6 +11,7 @@ import (
 	"errors"
 	"net"
 
+	"github.com/hyperledger/fabric/common/crypto/fips"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc/credentials"
 )
@@ -36,6 +37,7 @@ func NewServerTransportCredentials(serverConfig *tls.Config) credentials.Transpo
 	// override TLS version and ensure it is 1.2
 	serverConfig.MinVersion = tls.VersionTLS12
 	serverConfig.MaxVersion = tls.VersionTLS12
+	fips.ConfigureTLS(serverConfig)
 	return &serverCreds{serverConfig}
 }
 
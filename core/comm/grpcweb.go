@@ -0,0 +1,201 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/context"
+	"golang.org/x/net/http2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	grpcWebContentType     = "application/grpc-web"
+	grpcWebTextContentType = "application/grpc-web-text"
+
+	// grpcWebTrailerFlag marks the frame carrying the trailers at the end of the body
+	grpcWebTrailerFlag = 0x80
+
+	// grpcWebMetadataKey is set in the metadata of the methods called through gRPC-web
+	grpcWebMetadataKey = "x-grpc-web"
+)
+
+// IsGRPCWeb returns true if ctx is the context of a method called through gRPC-web. As such
+// clients close their side of the stream once they sent their messages, the end of the messages
+// received does not mean the end of the stream
+func IsGRPCWeb(ctx context.Context) bool {
+	md, ok := metadata.FromIncomingContext(ctx)
+	return ok && len(md[grpcWebMetadataKey]) > 0
+}
+
+// NewGRPCWebHandler returns a handler serving the methods of a gRPC server to the clients which
+// speak gRPC-web, i.e. browsers, without a proxy translating their requests. gRPC-web carries the
+// messages over HTTP/1.1 or HTTP/2, in binary or base64 encoded bodies, with the trailers sent in
+// the body after the messages. As browsers send the whole body of the request at once, only the
+// unary and server streaming methods can be called, and the bidirectional streams whose client
+// sends all its messages first. The cross-origin requests are answered for the origins allowed,
+// all of them if allowedOrigins contains "*", and rejected for the others
+func NewGRPCWebHandler(server *grpc.Server, allowedOrigins []string) http.Handler {
+	origins := make(map[string]bool)
+	for _, origin := range allowedOrigins {
+		origins[origin] = true
+	}
+	return &grpcWebHandler{server: server, allowedOrigins: origins}
+}
+
+type grpcWebHandler struct {
+	server         *grpc.Server
+	allowedOrigins map[string]bool
+}
+
+func (h *grpcWebHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if origin := r.Header.Get("Origin"); origin != "" {
+		if !h.allowedOrigins["*"] && !h.allowedOrigins[origin] {
+			http.Error(w, fmt.Sprintf("origin %s is not allowed", origin), http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		w.Header().Add("Vary", "Origin")
+		w.Header().Set("Access-Control-Expose-Headers", "grpc-status, grpc-message")
+		if r.Method == http.MethodOptions {
+			w.Header().Set("Access-Control-Allow-Methods", "POST")
+			w.Header().Set("Access-Control-Allow-Headers", r.Header.Get("Access-Control-Request-Headers"))
+			w.Header().Set("Access-Control-Max-Age", "600")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+
+	contentType := r.Header.Get("Content-Type")
+	if r.Method != http.MethodPost || !strings.HasPrefix(contentType, grpcWebContentType) {
+		http.Error(w, "expected a gRPC-web request", http.StatusUnsupportedMediaType)
+		return
+	}
+	text := strings.HasPrefix(contentType, grpcWebTextContentType)
+
+	// The gRPC server handles the request as if it were a gRPC request, sent over HTTP/2
+	req := *r
+	req.ProtoMajor, req.ProtoMinor, req.Proto = 2, 0, "HTTP/2.0"
+	req.Header = make(http.Header, len(r.Header))
+	for k, vv := range r.Header {
+		req.Header[k] = vv
+	}
+	req.Header.Set("Content-Type", "application/grpc+proto")
+	req.Header.Set(grpcWebMetadataKey, "1")
+	req.Header.Del("Content-Length")
+	req.ContentLength = -1
+	if text {
+		req.Body = struct {
+			io.Reader
+			io.Closer
+		}{base64.NewDecoder(base64.StdEncoding, r.Body), r.Body}
+	}
+
+	rw := &grpcWebResponseWriter{w: w, header: make(http.Header), text: text}
+	h.server.ServeHTTP(rw, &req)
+	rw.finish()
+}
+
+// grpcWebResponseWriter translates the response of the gRPC server: the headers are written as
+// such, the messages are encoded if needed, and the trailers are written in the body
+type grpcWebResponseWriter struct {
+	w           http.ResponseWriter
+	header      http.Header
+	text        bool
+	wroteHeader bool
+}
+
+func (rw *grpcWebResponseWriter) Header() http.Header {
+	return rw.header
+}
+
+func (rw *grpcWebResponseWriter) WriteHeader(code int) {
+	if rw.wroteHeader {
+		return
+	}
+	rw.wroteHeader = true
+
+	h := rw.w.Header()
+	for k, vv := range rw.header {
+		if k == "Trailer" || strings.HasPrefix(k, http2.TrailerPrefix) {
+			continue
+		}
+		h[k] = vv
+	}
+	if rw.text {
+		h.Set("Content-Type", grpcWebTextContentType+"+proto")
+	} else {
+		h.Set("Content-Type", grpcWebContentType+"+proto")
+	}
+	rw.w.WriteHeader(code)
+}
+
+func (rw *grpcWebResponseWriter) Write(p []byte) (int, error) {
+	rw.WriteHeader(http.StatusOK)
+	if !rw.text {
+		return rw.w.Write(p)
+	}
+	if _, err := rw.w.Write([]byte(base64.StdEncoding.EncodeToString(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (rw *grpcWebResponseWriter) Flush() {
+	rw.WriteHeader(http.StatusOK)
+	if f, ok := rw.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (rw *grpcWebResponseWriter) CloseNotify() <-chan bool {
+	if cn, ok := rw.w.(http.CloseNotifier); ok {
+		return cn.CloseNotify()
+	}
+	return make(chan bool)
+}
+
+// finish writes the frame of the trailers set by the gRPC server, i.e. its status and the
+// trailing metadata of the method
+func (rw *grpcWebResponseWriter) finish() {
+	trailers := make(map[string][]string)
+	for k, vv := range rw.header {
+		switch {
+		case k == "Grpc-Status" || k == "Grpc-Message":
+			trailers[strings.ToLower(k)] = vv
+		case strings.HasPrefix(k, http2.TrailerPrefix):
+			trailers[strings.ToLower(strings.TrimPrefix(k, http2.TrailerPrefix))] = vv
+		}
+	}
+	names := make([]string, 0, len(trailers))
+	for name := range trailers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	buf := &bytes.Buffer{}
+	for _, name := range names {
+		for _, v := range trailers[name] {
+			fmt.Fprintf(buf, "%s: %s\r\n", name, v)
+		}
+	}
+	frame := make([]byte, 5+buf.Len())
+	frame[0] = grpcWebTrailerFlag
+	binary.BigEndian.PutUint32(frame[1:5], uint32(buf.Len()))
+	copy(frame[5:], buf.Bytes())
+	rw.Write(frame)
+	rw.Flush()
+}
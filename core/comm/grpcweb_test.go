@@ -0,0 +1,186 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+type testGatewayServer struct{}
+
+func (tgs *testGatewayServer) Submit(stream pb.Gateway_SubmitServer) error {
+	return fmt.Errorf("not implemented")
+}
+
+func (tgs *testGatewayServer) Prepare(ctx context.Context, signedProp *pb.SignedProposal) (*pb.PreparedTransaction, error) {
+	if !IsGRPCWeb(ctx) {
+		return nil, fmt.Errorf("not called through gRPC-web")
+	}
+	if len(signedProp.ProposalBytes) == 0 {
+		return nil, fmt.Errorf("empty proposal")
+	}
+	return &pb.PreparedTransaction{TxId: string(signedProp.ProposalBytes)}, nil
+}
+
+func (tgs *testGatewayServer) Commit(env *cb.Envelope, stream pb.Gateway_CommitServer) error {
+	txID := string(env.Payload)
+	if err := stream.Send(&pb.TransactionStatus{Stage: pb.TransactionStatus_SUBMITTED, TxId: txID}); err != nil {
+		return err
+	}
+	return stream.Send(&pb.TransactionStatus{Stage: pb.TransactionStatus_COMMITTED, TxId: txID, BlockNumber: 3})
+}
+
+// grpcWebRequest frames the message in the body of a gRPC-web request
+func grpcWebRequest(t *testing.T, url string, msg proto.Message, text bool) *http.Request {
+	data, err := proto.Marshal(msg)
+	require.NoError(t, err)
+	body := make([]byte, 5+len(data))
+	binary.BigEndian.PutUint32(body[1:5], uint32(len(data)))
+	copy(body[5:], data)
+
+	contentType := "application/grpc-web+proto"
+	if text {
+		body = []byte(base64.StdEncoding.EncodeToString(body))
+		contentType = "application/grpc-web-text+proto"
+	}
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", contentType)
+	return req
+}
+
+// grpcWebResponse returns the messages and the trailers framed in the body of a gRPC-web response
+func grpcWebResponse(t *testing.T, resp *http.Response) ([][]byte, string) {
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	resp.Body.Close()
+	if resp.Header.Get("Content-Type") == "application/grpc-web-text+proto" {
+		// Each write is encoded on its own, and so padded
+		decoded := []byte{}
+		for len(body) > 0 {
+			n := bytes.IndexByte(body, '=')
+			if n < 0 {
+				n = len(body)
+			}
+			for n < len(body) && body[n] == '=' {
+				n++
+			}
+			chunk, err := base64.StdEncoding.DecodeString(string(body[:n]))
+			require.NoError(t, err)
+			decoded = append(decoded, chunk...)
+			body = body[n:]
+		}
+		body = decoded
+	}
+
+	var messages [][]byte
+	trailers := ""
+	for len(body) >= 5 {
+		n := int(binary.BigEndian.Uint32(body[1:5]))
+		require.True(t, len(body) >= 5+n, "Truncated frame")
+		if body[0] == grpcWebTrailerFlag {
+			trailers = string(body[5 : 5+n])
+		} else {
+			messages = append(messages, body[5:5+n])
+		}
+		body = body[5+n:]
+	}
+	assert.Empty(t, body)
+	return messages, trailers
+}
+
+func TestGRPCWebHandler(t *testing.T) {
+	server := grpc.NewServer()
+	pb.RegisterGatewayServer(server, &testGatewayServer{})
+	assert.False(t, IsGRPCWeb(context.Background()))
+
+	httpServer := httptest.NewServer(NewGRPCWebHandler(server, []string{"https://app.example.com"}))
+	defer httpServer.Close()
+
+	t.Run("Unary", func(t *testing.T) {
+		req := grpcWebRequest(t, httpServer.URL+"/protos.Gateway/Prepare", &pb.SignedProposal{ProposalBytes: []byte("tx1")}, false)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/grpc-web+proto", resp.Header.Get("Content-Type"))
+		assert.Empty(t, resp.Header.Get("Access-Control-Allow-Origin"))
+
+		messages, trailers := grpcWebResponse(t, resp)
+		require.Len(t, messages, 1)
+		prepared := &pb.PreparedTransaction{}
+		require.NoError(t, proto.Unmarshal(messages[0], prepared))
+		assert.Equal(t, "tx1", prepared.TxId)
+		assert.Equal(t, "grpc-status: 0\r\n", trailers)
+	})
+
+	t.Run("ServerStreamingText", func(t *testing.T) {
+		req := grpcWebRequest(t, httpServer.URL+"/protos.Gateway/Commit", &cb.Envelope{Payload: []byte("tx1")}, true)
+		req.Header.Set("Origin", "https://app.example.com")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		assert.Equal(t, "application/grpc-web-text+proto", resp.Header.Get("Content-Type"))
+		assert.Equal(t, "https://app.example.com", resp.Header.Get("Access-Control-Allow-Origin"))
+
+		messages, trailers := grpcWebResponse(t, resp)
+		require.Len(t, messages, 2)
+		status := &pb.TransactionStatus{}
+		require.NoError(t, proto.Unmarshal(messages[1], status))
+		assert.Equal(t, &pb.TransactionStatus{Stage: pb.TransactionStatus_COMMITTED, TxId: "tx1", BlockNumber: 3}, status)
+		assert.Equal(t, "grpc-status: 0\r\n", trailers)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		req := grpcWebRequest(t, httpServer.URL+"/protos.Gateway/Prepare", &pb.SignedProposal{}, false)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		messages, trailers := grpcWebResponse(t, resp)
+		assert.Empty(t, messages)
+		assert.Equal(t, "grpc-message: empty proposal\r\ngrpc-status: 2\r\n", trailers)
+	})
+
+	t.Run("Preflight", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodOptions, httpServer.URL+"/protos.Gateway/Prepare", nil)
+		require.NoError(t, err)
+		req.Header.Set("Origin", "https://app.example.com")
+		req.Header.Set("Access-Control-Request-Headers", "content-type, x-grpc-web")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		assert.Equal(t, "https://app.example.com", resp.Header.Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "content-type, x-grpc-web", resp.Header.Get("Access-Control-Allow-Headers"))
+		assert.Equal(t, "grpc-status, grpc-message", resp.Header.Get("Access-Control-Expose-Headers"))
+
+		req.Header.Set("Origin", "https://evil.example.com")
+		resp, err = http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("NotGRPCWeb", func(t *testing.T) {
+		resp, err := http.Post(httpServer.URL+"/protos.Gateway/Prepare", "application/json", bytes.NewReader([]byte("{}")))
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusUnsupportedMediaType, resp.StatusCode)
+	})
+}
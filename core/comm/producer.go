@@ -28,6 +28,10 @@ import (
 
 var logger = flogging.MustGetLogger("ConnProducer")
 
+// EndpointDisableInterval is the time during which an endpoint disabled
+// with DisableEndpoint is not selected to produce connections
+var EndpointDisableInterval = time.Second * 10
+
 // ConnectionFactory creates a connection to a certain endpoint
 type ConnectionFactory func(endpoint string) (*grpc.ClientConn, error)
 
@@ -41,12 +45,16 @@ type ConnectionProducer interface {
 	// UpdateEndpoints updates the endpoints of the ConnectionProducer
 	// to be the given endpoints
 	UpdateEndpoints(endpoints []string)
+	// DisableEndpoint makes the ConnectionProducer skip the given endpoint
+	// for EndpointDisableInterval, unless all the endpoints are disabled
+	DisableEndpoint(endpoint string)
 }
 
 type connProducer struct {
 	sync.RWMutex
-	endpoints []string
-	connect   ConnectionFactory
+	endpoints         []string
+	disabledEndpoints map[string]time.Time
+	connect           ConnectionFactory
 }
 
 // NewConnectionProducer creates a new ConnectionProducer with given endpoints and connection factory.
@@ -55,7 +63,7 @@ func NewConnectionProducer(factory ConnectionFactory, endpoints []string) Connec
 	if len(endpoints) == 0 {
 		return nil
 	}
-	return &connProducer{endpoints: endpoints, connect: factory}
+	return &connProducer{endpoints: endpoints, disabledEndpoints: make(map[string]time.Time), connect: factory}
 }
 
 // NewConnection creates a new connection.
@@ -65,7 +73,7 @@ func (cp *connProducer) NewConnection() (*grpc.ClientConn, string, error) {
 	cp.RLock()
 	defer cp.RUnlock()
 
-	endpoints := shuffle(cp.endpoints)
+	endpoints := shuffle(cp.enabledEndpoints())
 	for _, endpoint := range endpoints {
 		conn, err := cp.connect(endpoint)
 		if err != nil {
@@ -89,6 +97,32 @@ func (cp *connProducer) UpdateEndpoints(endpoints []string) {
 	cp.endpoints = endpoints
 }
 
+// DisableEndpoint makes the ConnectionProducer skip the given endpoint
+// for EndpointDisableInterval, unless all the endpoints are disabled
+func (cp *connProducer) DisableEndpoint(endpoint string) {
+	cp.Lock()
+	defer cp.Unlock()
+	logger.Warning("Disabling endpoint", endpoint, "for", EndpointDisableInterval)
+	cp.disabledEndpoints[endpoint] = time.Now().Add(EndpointDisableInterval)
+}
+
+// enabledEndpoints returns the endpoints which are not disabled, or all
+// the endpoints if all of them are disabled
+func (cp *connProducer) enabledEndpoints() []string {
+	var endpoints []string
+	now := time.Now()
+	for _, endpoint := range cp.endpoints {
+		if until, disabled := cp.disabledEndpoints[endpoint]; disabled && now.Before(until) {
+			continue
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+	if len(endpoints) == 0 {
+		return cp.endpoints
+	}
+	return endpoints
+}
+
 func shuffle(a []string) []string {
 	n := len(a)
 	returnedSlice := make([]string, n)
@@ -19,6 +19,7 @@ package comm
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/grpc"
@@ -109,3 +110,41 @@ func TestUpdateEndpoints(t *testing.T) {
 	conn, _, err = producer.NewConnection()
 	assert.Equal(t, "b", conn2Endpoint[fmt.Sprintf("%p", conn)])
 }
+
+func TestDisableEndpoint(t *testing.T) {
+	defer func(interval time.Duration) {
+		EndpointDisableInterval = interval
+	}(EndpointDisableInterval)
+	EndpointDisableInterval = time.Millisecond * 100
+
+	connFactory := func(endpoint string) (*grpc.ClientConn, error) {
+		return &grpc.ClientConn{}, nil
+	}
+	producer := NewConnectionProducer(connFactory, []string{"a", "b"})
+	producer.DisableEndpoint("a")
+	for i := 0; i < 100; i++ {
+		_, endpoint, err := producer.NewConnection()
+		assert.NoError(t, err)
+		assert.Equal(t, "b", endpoint, "Should not have selected the disabled endpoint")
+	}
+
+	// When all the endpoints are disabled, they are all selected again
+	producer.DisableEndpoint("b")
+	selected := make(map[string]struct{})
+	for i := 0; i < 100; i++ {
+		_, endpoint, err := producer.NewConnection()
+		assert.NoError(t, err)
+		selected[endpoint] = struct{}{}
+	}
+	assert.Len(t, selected, 2)
+
+	// The endpoints are enabled again after the interval
+	producer.DisableEndpoint("a")
+	time.Sleep(EndpointDisableInterval)
+	selected = make(map[string]struct{})
+	for i := 0; i < 100; i++ {
+		_, endpoint, _ := producer.NewConnection()
+		selected[endpoint] = struct{}{}
+	}
+	assert.Len(t, selected, 2)
+}
@@ -0,0 +1,174 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txvalidator
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric/common/metrics"
+	gometrics "github.com/rcrowley/go-metrics"
+)
+
+// saturation is the CPU utilization above which adding validation workers is pointless
+const saturation = 0.9
+
+// ConcurrencyConf configures the number of workers validating the transactions of a block
+type ConcurrencyConf struct {
+	// Workers is the number of workers, or the initial number of workers in the adaptive
+	// mode. 0 defaults to the number of CPUs
+	Workers int
+	// Adaptive tunes the number of workers between MinWorkers and MaxWorkers after each
+	// block, based on the size of the block and the CPU utilization while validating it
+	Adaptive bool
+	// MinWorkers is the lower bound of the adaptive mode. 0 defaults to 1
+	MinWorkers int
+	// MaxWorkers is the upper bound of the adaptive mode. 0 defaults to twice the number of CPUs
+	MaxWorkers int
+}
+
+// Concurrency decides the number of workers validating the transactions of the blocks of the
+// channels. The current number of workers is published as the validator.workers metric, and
+// the CPU utilization while validating the last block as validator.cpu_utilization
+type Concurrency struct {
+	lock     sync.Mutex
+	level    int
+	min, max int
+	adaptive bool
+
+	// cpuTime returns the CPU time consumed by the process so far
+	cpuTime func() time.Duration
+	numCPU  int
+
+	workersGauge     gometrics.Gauge
+	utilizationGauge gometrics.GaugeFloat64
+}
+
+// NewConcurrency creates a Concurrency for the given configuration
+func NewConcurrency(conf ConcurrencyConf) (*Concurrency, error) {
+	numCPU := runtime.NumCPU()
+	if conf.Workers == 0 {
+		conf.Workers = numCPU
+	}
+	if conf.MinWorkers == 0 {
+		conf.MinWorkers = 1
+	}
+	if conf.MaxWorkers == 0 {
+		conf.MaxWorkers = 2 * numCPU
+	}
+	if conf.Workers < 0 || conf.MinWorkers < 0 || conf.MaxWorkers < 0 {
+		return nil, fmt.Errorf("number of workers must not be negative")
+	}
+	if conf.Adaptive {
+		if conf.MinWorkers > conf.MaxWorkers {
+			return nil, fmt.Errorf("minimum number of workers (%d) is above the maximum (%d)", conf.MinWorkers, conf.MaxWorkers)
+		}
+		if conf.Workers < conf.MinWorkers {
+			conf.Workers = conf.MinWorkers
+		}
+		if conf.Workers > conf.MaxWorkers {
+			conf.Workers = conf.MaxWorkers
+		}
+	}
+	c := &Concurrency{
+		level:            conf.Workers,
+		min:              conf.MinWorkers,
+		max:              conf.MaxWorkers,
+		adaptive:         conf.Adaptive,
+		cpuTime:          processCPUTime,
+		numCPU:           numCPU,
+		workersGauge:     gometrics.GetOrRegisterGauge("validator.workers", metrics.Registry),
+		utilizationGauge: gometrics.GetOrRegisterGaugeFloat64("validator.cpu_utilization", metrics.Registry),
+	}
+	c.workersGauge.Update(int64(c.level))
+	return c, nil
+}
+
+// Level returns the current number of workers
+func (c *Concurrency) Level() int {
+	if c == nil {
+		return 1
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.level
+}
+
+// validate runs validateTx for each of the numTxs transactions of a block on the current number
+// of workers, and tunes the number of workers in the adaptive mode. A nil Concurrency validates
+// the transactions one after the other
+func (c *Concurrency) validate(numTxs int, validateTx func(tIdx int)) {
+	var startCPU time.Duration
+	if c != nil {
+		startCPU = c.cpuTime()
+	}
+	start := time.Now()
+
+	c.forEach(numTxs, validateTx)
+
+	if c != nil {
+		c.observe(numTxs, time.Since(start), c.cpuTime()-startCPU)
+	}
+}
+
+// forEach runs f for each of the numTxs transactions of a block on the current number of workers,
+// without tuning it
+func (c *Concurrency) forEach(numTxs int, f func(tIdx int)) {
+	workers := c.Level()
+	if workers > numTxs {
+		workers = numTxs
+	}
+
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for tIdx := range indices {
+				f(tIdx)
+			}
+		}()
+	}
+	for tIdx := 0; tIdx < numTxs; tIdx++ {
+		indices <- tIdx
+	}
+	close(indices)
+	wg.Wait()
+}
+
+// observe records the validation of a block of numTxs transactions, which took elapsed and
+// consumed cpu, and tunes the number of workers in the adaptive mode: the workers are reduced
+// while the CPUs are saturated, and added while blocks keep all the workers busy
+func (c *Concurrency) observe(numTxs int, elapsed, cpu time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	utilization := 0.0
+	if elapsed > 0 {
+		utilization = float64(cpu) / float64(elapsed) / float64(c.numCPU)
+	}
+	c.utilizationGauge.Update(utilization)
+	if !c.adaptive {
+		return
+	}
+
+	previous := c.level
+	switch {
+	case utilization >= saturation && c.level > c.min:
+		c.level--
+	case utilization < saturation && numTxs > c.level && c.level < c.max:
+		c.level++
+	}
+	if c.level != previous {
+		logger.Debugf("Validating with %d workers instead of %d after a block of %d transactions at %.0f%% CPU utilization",
+			c.level, previous, numTxs, 100*utilization)
+		c.workersGauge.Update(int64(c.level))
+	}
+}
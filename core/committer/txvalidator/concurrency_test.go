@@ -0,0 +1,98 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txvalidator
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewConcurrency(t *testing.T) {
+	c, err := NewConcurrency(ConcurrencyConf{})
+	require.NoError(t, err)
+	assert.Equal(t, runtime.NumCPU(), c.Level())
+	assert.Equal(t, 1, c.min)
+	assert.Equal(t, 2*runtime.NumCPU(), c.max)
+
+	c, err = NewConcurrency(ConcurrencyConf{Workers: 16, Adaptive: true, MinWorkers: 2, MaxWorkers: 8})
+	require.NoError(t, err)
+	assert.Equal(t, 8, c.Level(), "Should have bounded the initial number of workers")
+
+	for _, conf := range []ConcurrencyConf{
+		{Workers: -1},
+		{Adaptive: true, MinWorkers: 4, MaxWorkers: 2},
+	} {
+		_, err := NewConcurrency(conf)
+		assert.Error(t, err, "Should have rejected %+v", conf)
+	}
+
+	assert.Equal(t, 1, (*Concurrency)(nil).Level())
+}
+
+func TestValidateAllTransactions(t *testing.T) {
+	c, err := NewConcurrency(ConcurrencyConf{Workers: 4})
+	require.NoError(t, err)
+	for _, concurrency := range []*Concurrency{nil, c} {
+		var lock sync.Mutex
+		validated := make(map[int]int)
+		concurrency.validate(100, func(tIdx int) {
+			lock.Lock()
+			defer lock.Unlock()
+			validated[tIdx]++
+		})
+		assert.Len(t, validated, 100)
+		for tIdx, count := range validated {
+			assert.Equal(t, 1, count, "Should have validated transaction %d once", tIdx)
+		}
+	}
+	c.validate(0, func(tIdx int) { t.Fatal("Should not have validated any transaction") })
+}
+
+func TestAdaptiveConcurrency(t *testing.T) {
+	c, err := NewConcurrency(ConcurrencyConf{Workers: 2, Adaptive: true, MinWorkers: 1, MaxWorkers: 3})
+	require.NoError(t, err)
+	c.numCPU = 2
+
+	c.observe(10, time.Second, time.Second)
+	assert.Equal(t, 3, c.Level(), "Should have added a worker while the CPUs are idle")
+	c.observe(10, time.Second, time.Second)
+	assert.Equal(t, 3, c.Level(), "Should not have gone above the maximum")
+	c.observe(2, time.Second, time.Second)
+	assert.Equal(t, 3, c.Level(), "Should not have added workers for small blocks")
+
+	c.observe(10, time.Second, 2*time.Second)
+	assert.Equal(t, 2, c.Level(), "Should have removed a worker while the CPUs are saturated")
+	c.observe(10, time.Second, 2*time.Second)
+	c.observe(10, time.Second, 2*time.Second)
+	assert.Equal(t, 1, c.Level(), "Should not have gone below the minimum")
+	assert.Equal(t, 1.0, c.utilizationGauge.Value())
+	assert.Equal(t, int64(1), c.workersGauge.Value())
+
+	c.adaptive = false
+	c.observe(10, time.Second, 0)
+	assert.Equal(t, 1, c.Level(), "Should not have tuned the number of workers")
+}
+
+func TestValidateObservesCPUTime(t *testing.T) {
+	c, err := NewConcurrency(ConcurrencyConf{Workers: 1, Adaptive: true, MaxWorkers: 4})
+	require.NoError(t, err)
+	c.numCPU = 1
+	var cpu time.Duration
+	c.cpuTime = func() time.Duration { return cpu }
+	c.validate(10, func(tIdx int) {
+		if tIdx == 0 {
+			// the process consumed all the CPU while validating the block
+			cpu = time.Hour
+		}
+	})
+	assert.Equal(t, 1, c.Level(), "Should not have added a worker while the CPU is saturated")
+}
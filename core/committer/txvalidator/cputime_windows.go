@@ -0,0 +1,14 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txvalidator
+
+import "time"
+
+// The CPU time is not measured on this platform, so the adaptive mode only follows the block sizes
+func processCPUTime() time.Duration {
+	return 0
+}
@@ -0,0 +1,66 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txvalidator
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+)
+
+// rwSetLimits caps the size of the read set and of the write set of the transactions of a channel,
+// as the size of the protobuf encoding of their reads and range queries, and of their writes, across
+// all the namespaces. The zero value leaves the sizes unbounded
+type rwSetLimits struct {
+	maxReadSetBytes  uint64
+	maxWriteSetBytes uint64
+}
+
+// check returns RWSET_SIZE_EXCEEDED if the read-write set of the endorser transaction envBytes
+// exceeds the limits, or the code VSCC returns for a read-write set which cannot be read
+func (l rwSetLimits) check(envBytes []byte) peer.TxValidationCode {
+	if l.maxReadSetBytes == 0 && l.maxWriteSetBytes == 0 {
+		return peer.TxValidationCode_VALID
+	}
+
+	respPayload, err := utils.GetActionFromEnvelope(envBytes)
+	if err != nil {
+		return peer.TxValidationCode_BAD_RESPONSE_PAYLOAD
+	}
+	txRWSet := &rwsetutil.TxRwSet{}
+	if err := txRWSet.FromProtoBytes(respPayload.Results); err != nil {
+		return peer.TxValidationCode_BAD_RWSET
+	}
+
+	readSetBytes, writeSetBytes := rwSetSizes(txRWSet)
+	if l.maxReadSetBytes > 0 && readSetBytes > l.maxReadSetBytes {
+		logger.Warningf("Read set of %d bytes exceeds the limit of %d bytes", readSetBytes, l.maxReadSetBytes)
+		return peer.TxValidationCode_RWSET_SIZE_EXCEEDED
+	}
+	if l.maxWriteSetBytes > 0 && writeSetBytes > l.maxWriteSetBytes {
+		logger.Warningf("Write set of %d bytes exceeds the limit of %d bytes", writeSetBytes, l.maxWriteSetBytes)
+		return peer.TxValidationCode_RWSET_SIZE_EXCEEDED
+	}
+	return peer.TxValidationCode_VALID
+}
+
+// rwSetSizes returns the size of the read set and of the write set of a transaction
+func rwSetSizes(txRWSet *rwsetutil.TxRwSet) (readSetBytes, writeSetBytes uint64) {
+	for _, ns := range txRWSet.NsRwSets {
+		for _, read := range ns.KvRwSet.Reads {
+			readSetBytes += uint64(proto.Size(read))
+		}
+		for _, rqi := range ns.KvRwSet.RangeQueriesInfo {
+			readSetBytes += uint64(proto.Size(rqi))
+		}
+		for _, write := range ns.KvRwSet.Writes {
+			writeSetBytes += uint64(proto.Size(write))
+		}
+	}
+	return readSetBytes, writeSetBytes
+}
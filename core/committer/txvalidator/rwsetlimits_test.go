@@ -0,0 +1,88 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txvalidator
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/config"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	ledgerUtil "github.com/hyperledger/fabric/core/ledger/util"
+	mocktxvalidator "github.com/hyperledger/fabric/core/mocks/txvalidator"
+	"github.com/hyperledger/fabric/core/mocks/validator"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+	"github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRWSetSizes(t *testing.T) {
+	rwsetBuilder := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder.AddToReadSet("cc1", "key1", version.NewHeight(1, 1))
+	rwsetBuilder.AddToRangeQuerySet("cc1", &kvrwset.RangeQueryInfo{StartKey: "a", EndKey: "z"})
+	rwsetBuilder.AddToReadSet("cc2", "key2", nil)
+	rwsetBuilder.AddToWriteSet("cc2", "key2", []byte("value"))
+
+	readSetBytes, writeSetBytes := rwSetSizes(rwsetBuilder.GetTxReadWriteSet())
+	assert.Equal(t, uint64(10+8+6), readSetBytes)
+	assert.Equal(t, uint64(13), writeSetBytes)
+}
+
+func TestRWSetLimitsValidation(t *testing.T) {
+	rwset := func(valueSize int) []byte {
+		rwsetBuilder := rwsetutil.NewRWSetBuilder()
+		rwsetBuilder.AddToReadSet("cc", "key", nil)
+		rwsetBuilder.AddToWriteSet("cc", "key", bytes.Repeat([]byte("v"), valueSize))
+		rws, err := rwsetBuilder.GetTxReadWriteSet().ToProtoBytes()
+		require.NoError(t, err)
+		return rws
+	}
+	block := common.NewBlock(1, nil)
+	for _, simRes := range [][]byte{rwset(10), rwset(1000), []byte("barf")} {
+		env, _, err := testutil.ConstructTransaction(t, simRes, true)
+		require.NoError(t, err)
+		block.Data.Data = append(block.Data.Data, utils.MarshalOrPanic(env))
+	}
+
+	l := &mockLedger{}
+	l.On("GetTransactionByID", mock.Anything).Return((*peer.ProcessedTransaction)(nil), errors.New("not found"))
+	capabilities := []string{config.RWSetLimitsCapability}
+	validate := func(maxReadSetBytes, maxWriteSetBytes uint64) ledgerUtil.TxValidationFlags {
+		tValidator := &txValidator{
+			support: &mocktxvalidator.Support{LedgerVal: l, MaxReadSetBytesVal: maxReadSetBytes, MaxWriteSetBytesVal: maxWriteSetBytes, CapabilitiesVal: capabilities},
+			vscc:    &validator.MockVsccValidator{},
+		}
+		require.NoError(t, tValidator.Validate(block))
+		return ledgerUtil.TxValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	}
+
+	txsfltr := validate(0, 0)
+	assert.True(t, txsfltr.IsValid(0))
+	assert.True(t, txsfltr.IsValid(1), "Should leave the sizes unbounded without limits")
+	assert.True(t, txsfltr.IsValid(2), "Should leave the unreadable read-write sets to VSCC without limits")
+
+	txsfltr = validate(0, 100)
+	assert.True(t, txsfltr.IsValid(0))
+	assert.True(t, txsfltr.IsSetTo(1, peer.TxValidationCode_RWSET_SIZE_EXCEEDED))
+	assert.True(t, txsfltr.IsSetTo(2, peer.TxValidationCode_BAD_RWSET))
+
+	txsfltr = validate(2, 0)
+	assert.True(t, txsfltr.IsSetTo(0, peer.TxValidationCode_RWSET_SIZE_EXCEEDED))
+	assert.True(t, txsfltr.IsSetTo(1, peer.TxValidationCode_RWSET_SIZE_EXCEEDED))
+
+	capabilities = nil
+	txsfltr = validate(2, 2)
+	assert.True(t, txsfltr.IsValid(0), "Should leave the sizes unbounded without the capability")
+	assert.True(t, txsfltr.IsValid(1), "Should leave the sizes unbounded without the capability")
+}
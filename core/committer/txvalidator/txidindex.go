@@ -0,0 +1,105 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txvalidator
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+)
+
+// txIDIndex holds the transaction IDs of a window of the most recent blocks of a channel, so that
+// duplicate transaction IDs are detected without a lookup in the whole history of the channel. The
+// index is kept in memory, and rebuilt from the ledger when it does not cover the window of the
+// block being validated, i.e. after the peer starts, after the window grows, or when a block is
+// validated again because its commit failed. The zero value is an empty index
+type txIDIndex struct {
+	// from and to delimit the indexed blocks, from inclusive and to exclusive
+	from, to uint64
+	blocks   map[uint64][]string
+	// counts is the number of indexed blocks with each transaction ID, which may be more than one
+	// for the transaction IDs reused beyond the window
+	counts map[string]int
+}
+
+// prepare makes the index cover the window of blocks preceding the block with the given number
+func (idx *txIDIndex) prepare(l ledger.PeerLedger, number, window uint64) error {
+	from := uint64(0)
+	if number > window {
+		from = number - window
+	}
+	if idx.to == number && idx.from <= from {
+		for ; idx.from < from; idx.from++ {
+			idx.evict(idx.from)
+		}
+		return nil
+	}
+
+	logger.Infof("Indexing the transaction IDs of blocks %d to %d to detect duplicates", from, number)
+	idx.from, idx.to = from, from
+	idx.blocks = make(map[uint64][]string)
+	idx.counts = make(map[string]int)
+	for n := from; n < number; n++ {
+		block, err := l.GetBlockByNumber(n)
+		if err != nil {
+			return fmt.Errorf("error retrieving block %d to index its transaction IDs: %s", n, err)
+		}
+		idx.add(n, blockTxIDs(block))
+	}
+	return nil
+}
+
+// contains returns true if a block of the index has a transaction with the given ID
+func (idx *txIDIndex) contains(txID string) bool {
+	return idx.counts[txID] > 0
+}
+
+// add indexes the transaction IDs of the block following the indexed blocks
+func (idx *txIDIndex) add(number uint64, txIDs []string) {
+	if idx.blocks == nil {
+		idx.blocks = make(map[uint64][]string)
+		idx.counts = make(map[string]int)
+	}
+	idx.blocks[number] = txIDs
+	for _, txID := range txIDs {
+		idx.counts[txID]++
+	}
+	idx.to = number + 1
+}
+
+func (idx *txIDIndex) evict(number uint64) {
+	for _, txID := range idx.blocks[number] {
+		if idx.counts[txID]--; idx.counts[txID] == 0 {
+			delete(idx.counts, txID)
+		}
+	}
+	delete(idx.blocks, number)
+}
+
+// blockTxIDs returns the IDs of the transactions of a committed block, whether valid or not, as
+// the ledger indexes them
+func blockTxIDs(block *common.Block) []string {
+	var txIDs []string
+	for _, d := range block.Data.Data {
+		env, err := utils.GetEnvelopeFromBlock(d)
+		if err != nil {
+			continue
+		}
+		payload, err := utils.GetPayload(env)
+		if err != nil || payload.Header == nil {
+			continue
+		}
+		chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+		if err != nil || chdr.TxId == "" {
+			continue
+		}
+		txIDs = append(txIDs, chdr.TxId)
+	}
+	return txIDs
+}
@@ -0,0 +1,129 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txvalidator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/config"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	ledgerUtil "github.com/hyperledger/fabric/core/ledger/util"
+	mocktxvalidator "github.com/hyperledger/fabric/core/mocks/txvalidator"
+	"github.com/hyperledger/fabric/core/mocks/validator"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func txIDsBlock(number uint64, txIDs ...string) *common.Block {
+	block := common.NewBlock(number, nil)
+	for _, txID := range txIDs {
+		env := &common.Envelope{Payload: utils.MarshalOrPanic(&common.Payload{
+			Header: &common.Header{
+				ChannelHeader: utils.MarshalOrPanic(&common.ChannelHeader{
+					TxId:      txID,
+					Type:      int32(common.HeaderType_ENDORSER_TRANSACTION),
+					ChannelId: "testchainid",
+				}),
+			},
+			Data: []byte("test"),
+		})}
+		block.Data.Data = append(block.Data.Data, utils.MarshalOrPanic(env))
+	}
+	return block
+}
+
+func TestTxIDIndex(t *testing.T) {
+	l := &mockLedger{}
+	l.On("GetBlockByNumber", uint64(1)).Return(txIDsBlock(1, "tx1"))
+	l.On("GetBlockByNumber", uint64(2)).Return(txIDsBlock(2, "tx2", "tx1"))
+
+	idx := &txIDIndex{}
+	require.NoError(t, idx.prepare(l, 3, 2))
+	assert.True(t, idx.contains("tx1"))
+	assert.True(t, idx.contains("tx2"))
+	l.AssertNumberOfCalls(t, "GetBlockByNumber", 2)
+
+	idx.add(3, []string{"tx3"})
+	require.NoError(t, idx.prepare(l, 4, 2))
+	l.AssertNumberOfCalls(t, "GetBlockByNumber", 2)
+	assert.True(t, idx.contains("tx1"), "Should still hold tx1 of block 2")
+	idx.add(4, nil)
+	require.NoError(t, idx.prepare(l, 5, 2))
+	assert.False(t, idx.contains("tx1"), "Should have evicted the blocks beyond the window")
+	assert.False(t, idx.contains("tx2"))
+	assert.True(t, idx.contains("tx3"))
+
+	require.NoError(t, idx.prepare(l, 3, 2), "Should rebuild the index for an earlier block")
+	assert.True(t, idx.contains("tx2"))
+	assert.False(t, idx.contains("tx3"))
+}
+
+func TestTxIDWindowValidation(t *testing.T) {
+	envs := make([]*common.Envelope, 4)
+	for i := range envs {
+		var err error
+		envs[i], _, err = testutil.ConstructTransaction(t, []byte("simulation results"), true)
+		require.NoError(t, err)
+	}
+	envBlock := func(number uint64, envs ...*common.Envelope) *common.Block {
+		block := common.NewBlock(number, nil)
+		for _, env := range envs {
+			block.Data.Data = append(block.Data.Data, utils.MarshalOrPanic(env))
+		}
+		return block
+	}
+
+	l := &mockLedger{}
+	l.On("GetBlockByNumber", uint64(1)).Return(envBlock(1, envs[0]))
+	l.On("GetBlockByNumber", uint64(2)).Return(envBlock(2, envs[1]))
+	tValidator := &txValidator{
+		support: &mocktxvalidator.Support{LedgerVal: l, TxIDWindowVal: 1},
+		vscc:    &validator.MockVsccValidator{},
+	}
+
+	// Without the capability, the window is ignored and the duplicates within the block are accepted
+	history := &mockLedger{}
+	history.On("GetTransactionByID", mock.Anything).Return(&peer.ProcessedTransaction{}, errors.New("not found"))
+	tValidator.support = &mocktxvalidator.Support{LedgerVal: history, TxIDWindowVal: 1}
+	block := envBlock(3, envs[0], envs[1], envs[2], envs[2])
+	require.NoError(t, tValidator.Validate(block))
+	txsfltr := ledgerUtil.TxValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	for i := range block.Data.Data {
+		assert.True(t, txsfltr.IsValid(i))
+	}
+	history.AssertNotCalled(t, "GetBlockByNumber", mock.Anything)
+
+	// With the capability, the duplicates within the block are rejected whatever the window
+	tValidator.support = &mocktxvalidator.Support{LedgerVal: history, CapabilitiesVal: []string{config.TxIDWindowCapability}}
+	block = envBlock(3, envs[2], envs[2])
+	require.NoError(t, tValidator.Validate(block))
+	txsfltr = ledgerUtil.TxValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	assert.True(t, txsfltr.IsValid(0))
+	assert.True(t, txsfltr.IsSetTo(1, peer.TxValidationCode_DUPLICATE_TXID), "Should have detected the duplicate in the block")
+
+	tValidator.support = &mocktxvalidator.Support{LedgerVal: l, TxIDWindowVal: 1, CapabilitiesVal: []string{config.TxIDWindowCapability}}
+	block = envBlock(3, envs[0], envs[1], envs[2], envs[2])
+	require.NoError(t, tValidator.Validate(block))
+	txsfltr = ledgerUtil.TxValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	assert.True(t, txsfltr.IsValid(0), "Should have accepted the ID of a transaction beyond the window")
+	assert.True(t, txsfltr.IsSetTo(1, peer.TxValidationCode_DUPLICATE_TXID))
+	assert.True(t, txsfltr.IsValid(2))
+	assert.True(t, txsfltr.IsSetTo(3, peer.TxValidationCode_DUPLICATE_TXID), "Should have detected the duplicate in the block")
+
+	block = envBlock(4, envs[2], envs[3])
+	require.NoError(t, tValidator.Validate(block))
+	txsfltr = ledgerUtil.TxValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	assert.True(t, txsfltr.IsSetTo(0, peer.TxValidationCode_DUPLICATE_TXID))
+	assert.True(t, txsfltr.IsValid(1))
+	l.AssertNumberOfCalls(t, "GetBlockByNumber", 1)
+	l.AssertNotCalled(t, "GetTransactionByID")
+}
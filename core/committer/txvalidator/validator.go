@@ -20,6 +20,7 @@ import (
 	"fmt"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/config"
 	"github.com/hyperledger/fabric/common/configtx"
 	"github.com/hyperledger/fabric/common/flogging"
 	coreUtil "github.com/hyperledger/fabric/common/util"
@@ -28,6 +29,7 @@ import (
 	"github.com/hyperledger/fabric/core/common/sysccprovider"
 	"github.com/hyperledger/fabric/core/common/validation"
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/customtx"
 	ledgerUtil "github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/msp"
 
@@ -54,6 +56,18 @@ type Support interface {
 	// GetMSPIDs returns the IDs for the application MSPs
 	// that have been defined in the channel
 	GetMSPIDs(cid string) []string
+
+	// TxIDWindow returns the number of blocks within which a transaction ID
+	// must be unique, 0 if it must be unique in the whole history of the channel
+	TxIDWindow() uint64
+
+	// HasCapability returns whether the capability is enabled in the
+	// application config of the channel
+	HasCapability(capability string) bool
+
+	// RWSetLimits returns the caps on the size of the read set and of the
+	// write set of each transaction, 0 when unbounded
+	RWSetLimits() (maxReadSetBytes, maxWriteSetBytes uint64)
 }
 
 //Validator interface which defines API to validate block transactions
@@ -82,8 +96,12 @@ type vsccValidatorImpl struct {
 // reference to the ledger to enable tx simulation
 // and execution of vscc
 type txValidator struct {
-	support Support
-	vscc    vsccValidator
+	support     Support
+	vscc        vsccValidator
+	concurrency *Concurrency
+	// txIDs indexes the transaction IDs of the blocks of the window, when the
+	// channel declares one
+	txIDs txIDIndex
 }
 
 // VSCCInfoLookupFailureError error to indicate inability
@@ -127,14 +145,18 @@ func init() {
 	logger = flogging.MustGetLogger("txvalidator")
 }
 
-// NewTxValidator creates new transactions validator
-func NewTxValidator(support Support) Validator {
+// NewTxValidator creates new transactions validator, validating the transactions
+// of a block with the number of workers decided by concurrency, or one after the
+// other if concurrency is nil
+func NewTxValidator(support Support, concurrency *Concurrency) Validator {
 	// Encapsulates interface implementation
 	return &txValidator{support,
 		&vsccValidatorImpl{
 			support:     support,
 			ccprovider:  ccprovider.GetChaincodeProvider(),
-			sccprovider: sysccprovider.GetSystemChaincodeProvider()}}
+			sccprovider: sysccprovider.GetSystemChaincodeProvider()},
+		concurrency,
+		txIDIndex{}}
 }
 
 func (v *txValidator) chainExists(chain string) bool {
@@ -142,6 +164,22 @@ func (v *txValidator) chainExists(chain string) bool {
 	return true
 }
 
+// txValidationResult is the outcome of the validation of a transaction of a block
+type txValidationResult struct {
+	// flagged is false for nil transactions, whose flag is left unset
+	flagged bool
+	code    peer.TxValidationCode
+	// invokeCC and upgradeCC are the chaincodes invoked and upgraded by a valid endorser transaction
+	invokeCC  *sysccprovider.ChaincodeInstance
+	upgradeCC *sysccprovider.ChaincodeInstance
+	// configEnvelope is set for a config transaction, which is applied in block order
+	configEnvelope *common.ConfigEnvelope
+	// txID is the ID of the transaction, if its header could be read
+	txID string
+	// err fails the validation of the whole block
+	err error
+}
+
 func (v *txValidator) Validate(block *common.Block) error {
 	logger.Debug("START Block Validation")
 	defer logger.Debug("END Block Validation")
@@ -151,118 +189,74 @@ func (v *txValidator) Validate(block *common.Block) error {
 	txsChaincodeNames := make(map[int]*sysccprovider.ChaincodeInstance)
 	// upgradedChaincodes records all the chaincodes that are upgrded in a block
 	txsUpgradedChaincodes := make(map[int]*sysccprovider.ChaincodeInstance)
-	for tIdx, d := range block.Data.Data {
-		if d != nil {
-			if env, err := utils.GetEnvelopeFromBlock(d); err != nil {
-				logger.Warningf("Error getting tx from block(%s)", err)
-				txsfltr.SetFlag(tIdx, peer.TxValidationCode_INVALID_OTHER_REASON)
-			} else if env != nil {
-				// validate the transaction: here we check that the transaction
-				// is properly formed, properly signed and that the security
-				// chain binding proposal to endorsements to tx holds. We do
-				// NOT check the validity of endorsements, though. That's a
-				// job for VSCC below
-				logger.Debug("Validating transaction peer.ValidateTransaction()")
-				var payload *common.Payload
-				var err error
-				var txResult peer.TxValidationCode
-
-				if payload, txResult = validation.ValidateTransaction(env); txResult != peer.TxValidationCode_VALID {
-					logger.Errorf("Invalid transaction with index %d", tIdx)
-					txsfltr.SetFlag(tIdx, txResult)
-					continue
-				}
-
-				chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
-				if err != nil {
-					logger.Warningf("Could not unmarshal channel header, err %s, skipping", err)
-					txsfltr.SetFlag(tIdx, peer.TxValidationCode_INVALID_OTHER_REASON)
-					continue
-				}
 
-				channel := chdr.ChannelId
-				logger.Debugf("Transaction is for chain %s", channel)
+	// The window is read before validating the block, as a config transaction
+	// changing it is alone in its block. Without the TxIDWindow capability, the
+	// window is ignored and duplicates within the block are not checked, as
+	// before, so that all the peers of the channel validate alike
+	var window uint64
+	checkTxIDs := v.support.HasCapability(config.TxIDWindowCapability)
+	if checkTxIDs {
+		window = v.support.TxIDWindow()
+	}
+	if window > 0 {
+		if err := v.txIDs.prepare(v.support.Ledger(), block.Header.Number, window); err != nil {
+			return err
+		}
+	}
 
-				if !v.chainExists(channel) {
-					logger.Errorf("Dropping transaction for non-existent chain %s", channel)
-					txsfltr.SetFlag(tIdx, peer.TxValidationCode_TARGET_CHAIN_NOT_FOUND)
-					continue
-				}
+	// The read-write set limits are only enforced with the RWSetLimits capability, for the same reason
+	var limits rwSetLimits
+	if v.support.HasCapability(config.RWSetLimitsCapability) {
+		limits.maxReadSetBytes, limits.maxWriteSetBytes = v.support.RWSetLimits()
+	}
 
-				if common.HeaderType(chdr.Type) == common.HeaderType_ENDORSER_TRANSACTION {
-					// Check duplicate transactions
-					txID := chdr.TxId
-					if _, err := v.support.Ledger().GetTransactionByID(txID); err == nil {
-						logger.Error("Duplicate transaction found, ", txID, ", skipping")
-						txsfltr.SetFlag(tIdx, peer.TxValidationCode_DUPLICATE_TXID)
-						continue
-					}
-
-					// Validate tx with vscc and policy
-					logger.Debug("Validating transaction vscc tx validate")
-					err, cde := v.vscc.VSCCValidateTx(payload, d, env)
-					if err != nil {
-						txID := txID
-						logger.Errorf("VSCCValidateTx for transaction txId = %s returned error %s", txID, err)
-						switch err.(type) {
-						case *VSCCExecutionFailureError:
-							return err
-						case *VSCCInfoLookupFailureError:
-							return err
-						default:
-							txsfltr.SetFlag(tIdx, cde)
-							continue
-						}
-					}
-
-					invokeCC, upgradeCC, err := v.getTxCCInstance(payload)
-					if err != nil {
-						logger.Errorf("Get chaincode instance from transaction txId = %s returned error %s", txID, err)
-						txsfltr.SetFlag(tIdx, peer.TxValidationCode_INVALID_OTHER_REASON)
-						continue
-					}
-					txsChaincodeNames[tIdx] = invokeCC
-					if upgradeCC != nil {
-						logger.Infof("Find chaincode upgrade transaction for chaincode %s on chain %s with new version %s", upgradeCC.ChaincodeName, upgradeCC.ChainID, upgradeCC.ChaincodeVersion)
-						txsUpgradedChaincodes[tIdx] = upgradeCC
-					}
-				} else if common.HeaderType(chdr.Type) == common.HeaderType_CONFIG {
-					configEnvelope, err := configtx.UnmarshalConfigEnvelope(payload.Data)
-					if err != nil {
-						err := fmt.Errorf("Error unmarshaling config which passed initial validity checks: %s", err)
-						logger.Critical(err)
-						return err
-					}
-
-					if err := v.support.Apply(configEnvelope); err != nil {
-						err := fmt.Errorf("Error validating config which passed initial validity checks: %s", err)
-						logger.Critical(err)
-						return err
-					}
-					logger.Debugf("config transaction received for chain %s", channel)
-				} else {
-					logger.Warningf("Unknown transaction type [%s] in block number [%d] transaction index [%d]",
-						common.HeaderType(chdr.Type), block.Header.Number, tIdx)
-					txsfltr.SetFlag(tIdx, peer.TxValidationCode_UNKNOWN_TX_TYPE)
-					continue
-				}
+	// The signatures of the creators of the transactions are verified in a batch, the transactions
+	// are then validated by concurrent workers and the results collected in block order
+	sigErrs := v.checkCreatorSignatures(block)
+	results := make([]*txValidationResult, len(block.Data.Data))
+	v.concurrency.validate(len(block.Data.Data), func(tIdx int) {
+		results[tIdx] = v.validateTx(block, tIdx, window, limits, sigErrs[tIdx])
+	})
 
-				if _, err := proto.Marshal(env); err != nil {
-					logger.Warningf("Cannot marshal transaction due to %s", err)
-					txsfltr.SetFlag(tIdx, peer.TxValidationCode_MARSHAL_TX_ERROR)
-					continue
-				}
-				// Succeeded to pass down here, transaction is valid
-				txsfltr.SetFlag(tIdx, peer.TxValidationCode_VALID)
-			} else {
-				logger.Warning("Nil tx from block")
-				txsfltr.SetFlag(tIdx, peer.TxValidationCode_NIL_ENVELOPE)
+	var blockTxIDs []string
+	seenTxIDs := make(map[string]bool)
+	for tIdx, result := range results {
+		if result.err != nil {
+			return result.err
+		}
+		if result.txID != "" {
+			if checkTxIDs && result.code == peer.TxValidationCode_VALID && result.configEnvelope == nil && seenTxIDs[result.txID] {
+				logger.Error("Duplicate transaction found in block, ", result.txID, ", skipping")
+				result = &txValidationResult{flagged: true, code: peer.TxValidationCode_DUPLICATE_TXID, txID: result.txID}
+			}
+			blockTxIDs = append(blockTxIDs, result.txID)
+			seenTxIDs[result.txID] = true
+		}
+		if result.configEnvelope != nil {
+			if err := v.support.Apply(result.configEnvelope); err != nil {
+				err := fmt.Errorf("Error validating config which passed initial validity checks: %s", err)
+				logger.Critical(err)
+				return err
 			}
 		}
+		if result.flagged {
+			txsfltr.SetFlag(tIdx, result.code)
+		}
+		if result.invokeCC != nil {
+			txsChaincodeNames[tIdx] = result.invokeCC
+		}
+		if result.upgradeCC != nil {
+			txsUpgradedChaincodes[tIdx] = result.upgradeCC
+		}
 	}
 
 	txsfltr = v.invalidTXsForUpgradeCC(txsChaincodeNames, txsUpgradedChaincodes, txsfltr)
 
+	if window > 0 {
+		v.txIDs.add(block.Header.Number, blockTxIDs)
+	}
+
 	// Initialize metadata structure
 	utils.InitBlockMetadata(block)
 
@@ -271,6 +265,161 @@ func (v *txValidator) Validate(block *common.Block) error {
 	return nil
 }
 
+// checkCreatorSignatures checks the signatures of the creators of the transactions of a block in
+// a batch, after deserializing and validating the creators on the workers, and returns the outcome
+// of the check of each transaction
+func (v *txValidator) checkCreatorSignatures(block *common.Block) []error {
+	envs := make([]*common.Envelope, len(block.Data.Data))
+	v.concurrency.forEach(len(block.Data.Data), func(tIdx int) {
+		if d := block.Data.Data[tIdx]; d != nil {
+			if env, err := utils.GetEnvelopeFromBlock(d); err == nil {
+				envs[tIdx] = env
+			}
+		}
+	})
+	return validation.CheckCreatorSignatures(envs, v.concurrency.forEach)
+}
+
+// validateTx validates the transaction at index tIdx of a block, given the outcome of the check of
+// the signature of its creator, and checks the uniqueness of its ID within the window of blocks, or
+// in the whole history if window is 0, and the size of its read-write set against the limits. It may
+// run concurrently with the validation of the other transactions of the block
+func (v *txValidator) validateTx(block *common.Block, tIdx int, window uint64, limits rwSetLimits, sigErr error) *txValidationResult {
+	d := block.Data.Data[tIdx]
+	if d == nil {
+		return &txValidationResult{}
+	}
+	var txID string
+	invalid := func(code peer.TxValidationCode) *txValidationResult {
+		return &txValidationResult{flagged: true, code: code, txID: txID}
+	}
+
+	env, err := utils.GetEnvelopeFromBlock(d)
+	if err != nil {
+		logger.Warningf("Error getting tx from block(%s)", err)
+		return invalid(peer.TxValidationCode_INVALID_OTHER_REASON)
+	}
+	if env == nil {
+		logger.Warning("Nil tx from block")
+		return invalid(peer.TxValidationCode_NIL_ENVELOPE)
+	}
+
+	// validate the transaction: here we check that the transaction
+	// is properly formed, properly signed and that the security
+	// chain binding proposal to endorsements to tx holds. We do
+	// NOT check the validity of endorsements, though. That's a
+	// job for VSCC below
+	logger.Debug("Validating transaction peer.ValidateTransaction()")
+	payload, txResult := validation.ValidateTransactionWithCheckedSignature(env, sigErr)
+	if txResult != peer.TxValidationCode_VALID {
+		logger.Errorf("Invalid transaction with index %d", tIdx)
+		return invalid(txResult)
+	}
+
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		logger.Warningf("Could not unmarshal channel header, err %s, skipping", err)
+		return invalid(peer.TxValidationCode_INVALID_OTHER_REASON)
+	}
+
+	channel := chdr.ChannelId
+	txID = chdr.TxId
+	logger.Debugf("Transaction is for chain %s", channel)
+
+	if !v.chainExists(channel) {
+		logger.Errorf("Dropping transaction for non-existent chain %s", channel)
+		return invalid(peer.TxValidationCode_TARGET_CHAIN_NOT_FOUND)
+	}
+
+	result := &txValidationResult{flagged: true, code: peer.TxValidationCode_VALID, txID: txID}
+	if common.HeaderType(chdr.Type) == common.HeaderType_ENDORSER_TRANSACTION {
+		// Check duplicate transactions
+		if v.isDuplicate(txID, window) {
+			logger.Error("Duplicate transaction found, ", txID, ", skipping")
+			return invalid(peer.TxValidationCode_DUPLICATE_TXID)
+		}
+
+		// Check the size of the read-write set before the costlier VSCC
+		if code := limits.check(d); code != peer.TxValidationCode_VALID {
+			logger.Errorf("Read-write set of transaction txId = %s exceeds the limits of the channel, skipping", txID)
+			return invalid(code)
+		}
+
+		// Validate tx with vscc and policy
+		logger.Debug("Validating transaction vscc tx validate")
+		err, cde := v.vscc.VSCCValidateTx(payload, d, env)
+		if err != nil {
+			logger.Errorf("VSCCValidateTx for transaction txId = %s returned error %s", txID, err)
+			switch err.(type) {
+			case *VSCCExecutionFailureError:
+				return &txValidationResult{err: err}
+			case *VSCCInfoLookupFailureError:
+				return &txValidationResult{err: err}
+			default:
+				return invalid(cde)
+			}
+		}
+
+		invokeCC, upgradeCC, err := v.getTxCCInstance(payload)
+		if err != nil {
+			logger.Errorf("Get chaincode instance from transaction txId = %s returned error %s", txID, err)
+			return invalid(peer.TxValidationCode_INVALID_OTHER_REASON)
+		}
+		result.invokeCC = invokeCC
+		if upgradeCC != nil {
+			logger.Infof("Find chaincode upgrade transaction for chaincode %s on chain %s with new version %s", upgradeCC.ChaincodeName, upgradeCC.ChainID, upgradeCC.ChaincodeVersion)
+			result.upgradeCC = upgradeCC
+		}
+	} else if common.HeaderType(chdr.Type) == common.HeaderType_CONFIG {
+		configEnvelope, err := configtx.UnmarshalConfigEnvelope(payload.Data)
+		if err != nil {
+			err := fmt.Errorf("Error unmarshaling config which passed initial validity checks: %s", err)
+			logger.Critical(err)
+			return &txValidationResult{err: err}
+		}
+		// The config is applied once all the transactions are validated, the orderers
+		// cut a block for each config transaction
+		result.configEnvelope = configEnvelope
+		logger.Debugf("config transaction received for chain %s", channel)
+	} else if !v.support.HasCapability(config.CustomTransactionsCapability) {
+		// The peers which do not have a processor for the type invalidate the transaction when it
+		// is well formed, and so must those which have one until the capability is enabled
+		logger.Warningf("Custom transaction txId = %s of type %s is invalid without the %s capability",
+			txID, common.HeaderType(chdr.Type), config.CustomTransactionsCapability)
+		return invalid(peer.TxValidationCode_BAD_COMMON_HEADER)
+	} else if processor := customtx.GetProcessor(common.HeaderType(chdr.Type)); processor != nil {
+		if v.isDuplicate(txID, window) {
+			logger.Error("Duplicate transaction found, ", txID, ", skipping")
+			return invalid(peer.TxValidationCode_DUPLICATE_TXID)
+		}
+		if code := processor.Validate(channel, payload); code != peer.TxValidationCode_VALID {
+			logger.Errorf("Custom transaction txId = %s of type %s is invalid with code %s", txID, common.HeaderType(chdr.Type), code)
+			return invalid(code)
+		}
+	} else {
+		logger.Warningf("Unknown transaction type [%s] in block number [%d] transaction index [%d]",
+			common.HeaderType(chdr.Type), block.Header.Number, tIdx)
+		return invalid(peer.TxValidationCode_UNKNOWN_TX_TYPE)
+	}
+
+	if _, err := proto.Marshal(env); err != nil {
+		logger.Warningf("Cannot marshal transaction due to %s", err)
+		result.code = peer.TxValidationCode_MARSHAL_TX_ERROR
+	}
+	// Succeeded to pass down here, transaction is valid
+	return result
+}
+
+// isDuplicate returns true if a transaction of the window of blocks, or of the whole history of
+// the channel if window is 0, has the given ID
+func (v *txValidator) isDuplicate(txID string, window uint64) bool {
+	if window > 0 {
+		return v.txIDs.contains(txID)
+	}
+	_, err := v.support.Ledger().GetTransactionByID(txID)
+	return err == nil
+}
+
 // generateCCKey generates a unique identifier for chaincode in specific chain
 func (v *txValidator) generateCCKey(ccName, chainID string) string {
 	return fmt.Sprintf("%s/%s", ccName, chainID)
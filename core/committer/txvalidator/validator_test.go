@@ -23,6 +23,7 @@ import (
 	"testing"
 
 	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/common/config"
 	ctxt "github.com/hyperledger/fabric/common/configtx/test"
 	ledger2 "github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
@@ -32,6 +33,7 @@ import (
 	ccp "github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/common/sysccprovider"
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/customtx"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
 	lutils "github.com/hyperledger/fabric/core/ledger/util"
@@ -59,7 +61,7 @@ func setupLedgerAndValidator(t *testing.T) (ledger.PeerLedger, Validator) {
 	assert.NoError(t, err)
 	theLedger, err := ledgermgmt.CreateLedger(gb)
 	assert.NoError(t, err)
-	theValidator := NewTxValidator(&mockSupport{l: theLedger})
+	theValidator := NewTxValidator(&mockSupport{l: theLedger}, nil)
 
 	return theLedger, theValidator
 }
@@ -133,7 +135,11 @@ func putCCInfo(theLedger ledger.PeerLedger, ccname string, policy []byte, t *tes
 }
 
 type mockSupport struct {
-	l ledger.PeerLedger
+	l                ledger.PeerLedger
+	txIDWindow       uint64
+	maxReadSetBytes  uint64
+	maxWriteSetBytes uint64
+	capabilities     []string
 }
 
 func (m *mockSupport) Ledger() ledger.PeerLedger {
@@ -152,6 +158,23 @@ func (m *mockSupport) GetMSPIDs(cid string) []string {
 	return []string{"DEFAULT"}
 }
 
+func (m *mockSupport) TxIDWindow() uint64 {
+	return m.txIDWindow
+}
+
+func (m *mockSupport) HasCapability(capability string) bool {
+	for _, c := range m.capabilities {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *mockSupport) RWSetLimits() (uint64, uint64) {
+	return m.maxReadSetBytes, m.maxWriteSetBytes
+}
+
 func assertInvalid(block *common.Block, t *testing.T, code peer.TxValidationCode) {
 	txsFilter := lutils.TxValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
 	assert.True(t, txsFilter.IsInvalid(0))
@@ -212,6 +235,63 @@ func TestInvokeOK(t *testing.T) {
 	assertValid(b, t)
 }
 
+// codeProcessor processes custom transactions whose data is their validation code
+type codeProcessor struct{}
+
+func (*codeProcessor) Validate(channelID string, payload *common.Payload) peer.TxValidationCode {
+	return peer.TxValidationCode(payload.Data[0])
+}
+
+func (*codeProcessor) SimulationResults(payload *common.Payload) ([]byte, error) {
+	return nil, nil
+}
+
+func getCustomEnv(txType common.HeaderType, data []byte, t *testing.T) *common.Envelope {
+	nonce := utils.CreateNonceOrPanic()
+	txID, err := utils.ComputeProposalTxID(nonce, signerSerialized)
+	assert.NoError(t, err)
+	chdr := utils.MakeChannelHeader(txType, 0, util.GetTestChainID(), 0)
+	chdr.TxId = txID
+	payloadBytes := utils.MarshalOrPanic(&common.Payload{
+		Header: utils.MakePayloadHeader(chdr, utils.MakeSignatureHeader(signerSerialized, nonce)),
+		Data:   data,
+	})
+	sig, err := signer.Sign(payloadBytes)
+	assert.NoError(t, err)
+	return &common.Envelope{Payload: payloadBytes, Signature: sig}
+}
+
+func TestCustomTx(t *testing.T) {
+	const customTx = common.HeaderType(100)
+	customtx.Register(customTx, &codeProcessor{})
+	defer customtx.Unregister(customTx)
+
+	l, v := setupLedgerAndValidator(t)
+	defer ledgermgmt.CleanupTestEnv()
+	defer l.Close()
+
+	b := &common.Block{Data: &common.BlockData{Data: [][]byte{
+		utils.MarshalOrPanic(getCustomEnv(customTx, []byte{byte(peer.TxValidationCode_VALID)}, t)),
+	}}}
+	err := v.Validate(b)
+	assert.NoError(t, err)
+	txsFilter := lutils.TxValidationFlags(b.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	assert.True(t, txsFilter.IsSetTo(0, peer.TxValidationCode_BAD_COMMON_HEADER), "Should not support a processor without the capability")
+
+	v.(*txValidator).support.(*mockSupport).capabilities = []string{config.CustomTransactionsCapability}
+	b = &common.Block{Data: &common.BlockData{Data: [][]byte{
+		utils.MarshalOrPanic(getCustomEnv(customTx, []byte{byte(peer.TxValidationCode_VALID)}, t)),
+		utils.MarshalOrPanic(getCustomEnv(customTx, []byte{byte(peer.TxValidationCode_ILLEGAL_WRITESET)}, t)),
+		utils.MarshalOrPanic(getCustomEnv(common.HeaderType(101), []byte{byte(peer.TxValidationCode_VALID)}, t)),
+	}}}
+	err = v.Validate(b)
+	assert.NoError(t, err)
+	txsFilter = lutils.TxValidationFlags(b.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	assert.True(t, txsFilter.IsValid(0))
+	assert.True(t, txsFilter.IsSetTo(1, peer.TxValidationCode_ILLEGAL_WRITESET), "Should invalidate with the code of the processor")
+	assert.True(t, txsFilter.IsSetTo(2, peer.TxValidationCode_BAD_COMMON_HEADER), "Should not support a type without a processor")
+}
+
 func TestInvokeOKSCC(t *testing.T) {
 	l, v := setupLedgerAndValidator(t)
 	defer ledgermgmt.CleanupTestEnv()
@@ -439,11 +519,21 @@ func (m *mockLedger) NewHistoryQueryExecutor() (ledger.HistoryQueryExecutor, err
 	return args.Get(0).(ledger.HistoryQueryExecutor), nil
 }
 
+// NewHistoricalQueryExecutor historical query executor
+func (m *mockLedger) NewHistoricalQueryExecutor(height uint64) (ledger.HistoricalQueryExecutor, error) {
+	args := m.Called(height)
+	return args.Get(0).(ledger.HistoricalQueryExecutor), nil
+}
+
 // Prune prune using policy
 func (m *mockLedger) Prune(policy ledger2.PrunePolicy) error {
 	return nil
 }
 
+// SetRangeQueryHashing sets the range query hashing
+func (m *mockLedger) SetRangeQueryHashing(enabled bool) {
+}
+
 func (m *mockLedger) GetBlockchainInfo() (*common.BlockchainInfo, error) {
 	args := m.Called()
 	return args.Get(0).(*common.BlockchainInfo), nil
@@ -510,7 +600,7 @@ func (exec *mockQueryExecutor) Done() {
 // returned from the function call.
 func TestLedgerIsNoAvailable(t *testing.T) {
 	theLedger := new(mockLedger)
-	validator := NewTxValidator(&mockSupport{l: theLedger})
+	validator := NewTxValidator(&mockSupport{l: theLedger}, nil)
 
 	ccID := "mycc"
 	tx := getEnv(ccID, createRWset(t, ccID), t)
@@ -534,7 +624,7 @@ func TestLedgerIsNoAvailable(t *testing.T) {
 
 func TestValidationInvalidEndorsing(t *testing.T) {
 	theLedger := new(mockLedger)
-	validator := NewTxValidator(&mockSupport{l: theLedger})
+	validator := NewTxValidator(&mockSupport{l: theLedger}, nil)
 
 	ccID := "mycc"
 	tx := getEnv(ccID, createRWset(t, ccID), t)
@@ -17,8 +17,11 @@ limitations under the License.
 package ccprovider
 
 import (
+	"archive/tar"
+	"compress/gzip"
 	"context"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -242,6 +245,50 @@ func CheckInsantiationPolicy(name, version string, cdLedger *ChaincodeData) erro
 	return nil
 }
 
+// statedbArtifactsDir is the directory of the artifacts of the state databases,
+// such as the CouchDB indexes, in the code package of a chaincode
+const statedbArtifactsDir = "META-INF/statedb/"
+
+// ExtractStatedbArtifactsFromCCPackage returns the artifacts of the state databases shipped
+// in the code package of a chaincode, keyed by their path relative to META-INF/statedb,
+// e.g. couchdb/indexes/indexOwner.json
+func ExtractStatedbArtifactsFromCCPackage(ccpack CCPackage) (map[string][]byte, error) {
+	cds := ccpack.GetDepSpec()
+	if cds == nil {
+		return nil, fmt.Errorf("nil deployment spec from the CC package")
+	}
+	artifacts := make(map[string][]byte)
+	if len(cds.CodePackage) == 0 {
+		return artifacts, nil
+	}
+
+	gr, err := gzip.NewReader(bytes.NewReader(cds.CodePackage))
+	if err != nil {
+		return nil, fmt.Errorf("error reading the code package of chaincode %s: %s", cds.ChaincodeSpec.ChaincodeId.Name, err)
+	}
+	defer gr.Close()
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading the code package of chaincode %s: %s", cds.ChaincodeSpec.ChaincodeId.Name, err)
+		}
+		idx := strings.Index(hdr.Name, statedbArtifactsDir)
+		if idx < 0 || hdr.Typeflag == tar.TypeDir {
+			continue
+		}
+		content, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s from the code package of chaincode %s: %s", hdr.Name, cds.ChaincodeSpec.ChaincodeId.Name, err)
+		}
+		artifacts[hdr.Name[idx+len(statedbArtifactsDir):]] = content
+	}
+	return artifacts, nil
+}
+
 // GetCCPackage tries each known package implementation one by one
 // till the right package is found
 func GetCCPackage(buf []byte) (CCPackage, error) {
@@ -303,7 +350,12 @@ func GetInstalledChaincodes() (*pb.ChaincodeQueryResponse, error) {
 			// since this is just an installed chaincode these should be blank
 			input, escc, vscc := "", "", ""
 
-			ccInfo := &pb.ChaincodeInfo{Name: name, Version: version, Path: path, Input: input, Escc: escc, Vscc: vscc}
+			signer, err := GetChaincodeSigner(name, version)
+			if err != nil {
+				ccproviderLogger.Errorf("Unreadable signer of chaincode file %s: %s", file.Name(), err)
+			}
+
+			ccInfo := &pb.ChaincodeInfo{Name: name, Version: version, Path: path, Input: input, Escc: escc, Vscc: vscc, Signer: signer}
 
 			// add this specific chaincode's metadata to the array of all chaincodes
 			ccInfoArray = append(ccInfoArray, ccInfo)
@@ -0,0 +1,106 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ccprovider
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+)
+
+// signersDir is the directory of the install path where the signers of the installed
+// packages are recorded. It has no period, so that it is never taken for a package
+const signersDir = "signers"
+
+// CreateDetachedSignature signs the fingerprint of a chaincode package, as returned by
+// GetId, with signer. The signature is a serialized Endorsement holding the serialized
+// identity of signer, which is shipped next to the package rather than in it, so that
+// a build pipeline can vouch for a package without repackaging it
+func CreateDetachedSignature(ccpack CCPackage, signer msp.SigningIdentity) ([]byte, error) {
+	endorser, err := signer.Serialize()
+	if err != nil {
+		return nil, fmt.Errorf("Error serializing the signer: %s", err)
+	}
+	signature, err := signer.Sign(ccpack.GetId())
+	if err != nil {
+		return nil, fmt.Errorf("Error signing the package: %s", err)
+	}
+	return proto.Marshal(&pb.Endorsement{Endorser: endorser, Signature: signature})
+}
+
+// GetDetachedSignatureSignedData returns the signed data of a detached signature of a
+// chaincode package, against which the policy of the trusted signers can be evaluated
+func GetDetachedSignatureSignedData(ccpack CCPackage, signature []byte) ([]*common.SignedData, error) {
+	endorsement := &pb.Endorsement{}
+	if err := proto.Unmarshal(signature, endorsement); err != nil {
+		return nil, fmt.Errorf("Error unmarshalling the detached signature: %s", err)
+	}
+	if len(endorsement.Endorser) == 0 || len(endorsement.Signature) == 0 {
+		return nil, fmt.Errorf("Invalid detached signature: missing signer or signature")
+	}
+	return []*common.SignedData{{
+		Data:      ccpack.GetId(),
+		Identity:  endorsement.Endorser,
+		Signature: endorsement.Signature,
+	}}, nil
+}
+
+// PutChaincodeSigner records the serialized identity of the verified signer of a
+// chaincode package. It is recorded before the package is installed, and replaces
+// any previous record atomically, so that an installed package never shows without
+// its signer
+func PutChaincodeSigner(ccname string, ccversion string, signer []byte) error {
+	dir := filepath.Join(chaincodeInstallPath, signersDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(dir, "."+ccname+"."+ccversion)
+	if err != nil {
+		return err
+	}
+	if _, err = tmp.Write(signer); err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), 0644)
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), filepath.Join(dir, ccname+"."+ccversion))
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+	return err
+}
+
+// RemoveChaincodeSigner removes the record of the signer of a chaincode package, if any
+func RemoveChaincodeSigner(ccname string, ccversion string) error {
+	err := os.Remove(filepath.Join(chaincodeInstallPath, signersDir, ccname+"."+ccversion))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// GetChaincodeSigner returns the serialized identity of the verified signer of an
+// installed chaincode package, or nil if it was installed without detached signature
+func GetChaincodeSigner(ccname string, ccversion string) ([]byte, error) {
+	signer, err := ioutil.ReadFile(filepath.Join(chaincodeInstallPath, signersDir, ccname+"."+ccversion))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return signer, err
+}
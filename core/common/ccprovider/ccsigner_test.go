@@ -0,0 +1,72 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ccprovider
+
+import (
+	"os"
+	"testing"
+
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetachedSignatureSignedData(t *testing.T) {
+	cds := &pb.ChaincodeDeploymentSpec{ChaincodeSpec: &pb.ChaincodeSpec{Type: 1, ChaincodeId: &pb.ChaincodeID{Name: "testcc", Version: "0"}}, CodePackage: []byte("code")}
+	ccpack, _, _, err := processCDS(cds, false)
+	assert.NoError(t, err)
+
+	signature := utils.MarshalOrPanic(&pb.Endorsement{Endorser: []byte("signer"), Signature: []byte("signature")})
+	sd, err := GetDetachedSignatureSignedData(ccpack, signature)
+	assert.NoError(t, err)
+	assert.Len(t, sd, 1)
+	assert.Equal(t, ccpack.GetId(), sd[0].Data)
+	assert.Equal(t, []byte("signer"), sd[0].Identity)
+	assert.Equal(t, []byte("signature"), sd[0].Signature)
+
+	_, err = GetDetachedSignatureSignedData(ccpack, []byte("garbage"))
+	assert.Error(t, err)
+	_, err = GetDetachedSignatureSignedData(ccpack, utils.MarshalOrPanic(&pb.Endorsement{Endorser: []byte("signer")}))
+	assert.Error(t, err, "Expected an error for a missing signature")
+}
+
+func TestChaincodeSigner(t *testing.T) {
+	ccdir := setupccdir()
+	defer os.RemoveAll(ccdir)
+
+	for _, version := range []string{"0", "1"} {
+		cds := &pb.ChaincodeDeploymentSpec{ChaincodeSpec: &pb.ChaincodeSpec{Type: 1, ChaincodeId: &pb.ChaincodeID{Name: "testcc", Version: version}}, CodePackage: []byte("code")}
+		_, _, _, err := processCDS(cds, true)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, PutChaincodeSigner("testcc", "1", []byte("stale")))
+	assert.NoError(t, PutChaincodeSigner("testcc", "1", []byte("signer")))
+	assert.NoError(t, PutChaincodeSigner("testcc", "2", []byte("signer")))
+	assert.NoError(t, RemoveChaincodeSigner("testcc", "2"))
+	assert.NoError(t, RemoveChaincodeSigner("testcc", "2"), "Expected no error when no signer is recorded")
+
+	signer, err := GetChaincodeSigner("testcc", "0")
+	assert.NoError(t, err)
+	assert.Nil(t, signer)
+	signer, err = GetChaincodeSigner("testcc", "1")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("signer"), signer)
+	signer, err = GetChaincodeSigner("testcc", "2")
+	assert.NoError(t, err)
+	assert.Nil(t, signer)
+
+	cqr, err := GetInstalledChaincodes()
+	assert.NoError(t, err)
+	assert.Len(t, cqr.Chaincodes, 2, "Expected the signers not to be taken for packages")
+	for _, ccInfo := range cqr.Chaincodes {
+		if ccInfo.Version == "1" {
+			assert.Equal(t, []byte("signer"), ccInfo.Signer)
+		} else {
+			assert.Nil(t, ccInfo.Signer)
+		}
+	}
+}
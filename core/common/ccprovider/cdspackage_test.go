@@ -17,6 +17,9 @@ limitations under the License.
 package ccprovider
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -218,3 +221,31 @@ func TestValidateCCErrorPaths(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "nil data", "Unexpected error returned")
 }
+
+func TestExtractStatedbArtifactsFromCCPackage(t *testing.T) {
+	buf := &bytes.Buffer{}
+	gz := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range map[string]string{
+		"src/github.com/example/cc/cc.go":                                            "package main",
+		"src/github.com/example/cc/META-INF/statedb/couchdb/indexes/indexOwner.json": `{"index":{"fields":["owner"]}}`,
+	} {
+		tw.WriteHeader(&tar.Header{Name: name, Size: int64(len(content)), Mode: 0644})
+		tw.Write([]byte(content))
+	}
+	tw.Close()
+	gz.Close()
+
+	cds := &pb.ChaincodeDeploymentSpec{ChaincodeSpec: &pb.ChaincodeSpec{Type: 1, ChaincodeId: &pb.ChaincodeID{Name: "testcc", Version: "0"}}, CodePackage: buf.Bytes()}
+	ccpack, _, _, err := processCDS(cds, false)
+	assert.NoError(t, err)
+	artifacts, err := ExtractStatedbArtifactsFromCCPackage(ccpack)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"couchdb/indexes/indexOwner.json": []byte(`{"index":{"fields":["owner"]}}`)}, artifacts)
+
+	cds.CodePackage = []byte("code")
+	ccpack, _, _, err = processCDS(cds, false)
+	assert.NoError(t, err)
+	_, err = ExtractStatedbArtifactsFromCCPackage(ccpack)
+	assert.Error(t, err, "Should have failed reading a code package which is not a tar.gz")
+}
@@ -41,6 +41,10 @@ type SystemChaincodeProvider interface {
 	// That's useful for system chaincodes that require unfettered
 	// access to the ledger
 	GetQueryExecutorForLedger(cid string) (ledger.QueryExecutor, error)
+
+	// HasCapability returns true if the capability is enabled
+	// on the supplied channel
+	HasCapability(cid, capability string) bool
 }
 
 var sccFactory SystemChaincodeProviderFactory
@@ -358,6 +358,37 @@ func TestBadTx(t *testing.T) {
 	}
 }
 
+func TestCheckCreatorSignatures(t *testing.T) {
+	prop, err := getProposal()
+	assert.NoError(t, err)
+	presp, err := utils.CreateProposalResponse(prop.Header, prop.Payload, &peer.Response{Status: 200}, []byte("simulation_result"), nil, getChaincodeID(), nil, signer)
+	assert.NoError(t, err)
+
+	goodTx, err := utils.CreateSignedTx(prop, signer, presp)
+	assert.NoError(t, err)
+	badSigTx, err := utils.CreateSignedTx(prop, signer, presp)
+	assert.NoError(t, err)
+	badSigTx.Signature = goodTx.Signature[:len(goodTx.Signature)-1]
+	badPayloadTx := &common.Envelope{Payload: []byte("garbage"), Signature: goodTx.Signature}
+
+	envs := []*common.Envelope{goodTx, nil, badSigTx, badPayloadTx}
+	sigErrs := CheckCreatorSignatures(envs, nil)
+	assert.Len(t, sigErrs, 4)
+	assert.NoError(t, sigErrs[0])
+	assert.NoError(t, sigErrs[1], "A nil envelope should not have been checked")
+	assert.Error(t, sigErrs[2])
+	assert.NoError(t, sigErrs[3], "A malformed envelope should not have been checked")
+
+	// The outcome of the batch matches the one of ValidateTransaction
+	for i, env := range envs {
+		_, expected := ValidateTransaction(env)
+		_, actual := ValidateTransactionWithCheckedSignature(env, sigErrs[i])
+		assert.Equal(t, expected, actual)
+	}
+	_, txResult := ValidateTransactionWithCheckedSignature(badSigTx, sigErrs[2])
+	assert.Equal(t, peer.TxValidationCode_BAD_CREATOR_SIGNATURE, txResult)
+}
+
 func Test2EndorsersAgree(t *testing.T) {
 	// get a toy proposal
 	prop, err := getProposal()
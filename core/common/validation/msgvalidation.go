@@ -22,6 +22,8 @@ import (
 	"fmt"
 
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/ledger/customtx"
+	"github.com/hyperledger/fabric/msp"
 	mspmgmt "github.com/hyperledger/fabric/msp/mgmt"
 	"github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric/protos/peer"
@@ -141,20 +143,39 @@ func ValidateProposalMessage(signedProp *pb.SignedProposal) (*pb.Proposal, *comm
 func checkSignatureFromCreator(creatorBytes []byte, sig []byte, msg []byte, ChainID string) error {
 	putilsLogger.Debugf("checkSignatureFromCreator starts")
 
+	creator, err := getValidCreator(creatorBytes, sig, msg, ChainID)
+	if err != nil {
+		return err
+	}
+
+	// validate the signature
+	err = creator.Verify(msg, sig)
+	if err != nil {
+		return fmt.Errorf("The creator's signature over the proposal is not valid, err %s", err)
+	}
+
+	putilsLogger.Debugf("checkSignatureFromCreator exists successfully")
+
+	return nil
+}
+
+// getValidCreator returns the identity of the creator of a signed message, once checked that it
+// is a valid certificate
+func getValidCreator(creatorBytes []byte, sig []byte, msg []byte, ChainID string) (msp.Identity, error) {
 	// check for nil argument
 	if creatorBytes == nil || sig == nil || msg == nil {
-		return errors.New("Nil arguments")
+		return nil, errors.New("Nil arguments")
 	}
 
 	mspObj := mspmgmt.GetIdentityDeserializer(ChainID)
 	if mspObj == nil {
-		return fmt.Errorf("could not get msp for chain [%s]", ChainID)
+		return nil, fmt.Errorf("could not get msp for chain [%s]", ChainID)
 	}
 
 	// get the identity of the creator
 	creator, err := mspObj.DeserializeIdentity(creatorBytes)
 	if err != nil {
-		return fmt.Errorf("Failed to deserialize creator identity, err %s", err)
+		return nil, fmt.Errorf("Failed to deserialize creator identity, err %s", err)
 	}
 
 	putilsLogger.Debugf("checkSignatureFromCreator info: creator is %s", creator.GetIdentifier())
@@ -162,20 +183,67 @@ func checkSignatureFromCreator(creatorBytes []byte, sig []byte, msg []byte, Chai
 	// ensure that creator is a valid certificate
 	err = creator.Validate()
 	if err != nil {
-		return fmt.Errorf("The creator certificate is not valid, err %s", err)
+		return nil, fmt.Errorf("The creator certificate is not valid, err %s", err)
 	}
 
 	putilsLogger.Debugf("checkSignatureFromCreator info: creator is valid")
 
-	// validate the signature
-	err = creator.Verify(msg, sig)
-	if err != nil {
-		return fmt.Errorf("The creator's signature over the proposal is not valid, err %s", err)
+	return creator, nil
+}
+
+// CheckCreatorSignatures checks the signatures of the creators of the envelopes, as
+// ValidateTransaction does for each of them, but verifies the signatures in a batch. It returns
+// the outcome of the check of each envelope, to be passed to
+// ValidateTransactionWithCheckedSignature. The envelopes which ValidateTransaction rejects before
+// checking their signature are not checked. The creators are deserialized and validated by forEach,
+// which calls its function with each index below n, possibly concurrently, and returns once all
+// the calls have returned. A nil forEach makes the calls one after the other
+func CheckCreatorSignatures(envs []*common.Envelope, forEach func(n int, f func(i int))) []error {
+	if forEach == nil {
+		forEach = func(n int, f func(i int)) {
+			for i := 0; i < n; i++ {
+				f(i)
+			}
+		}
 	}
 
-	putilsLogger.Debugf("checkSignatureFromCreator exists successfully")
+	errs := make([]error, len(envs))
+	validCreators := make([]msp.Identity, len(envs))
+	forEach(len(envs), func(i int) {
+		e := envs[i]
+		if e == nil {
+			return
+		}
+		payload, err := utils.GetPayload(e)
+		if err != nil {
+			return
+		}
+		chdr, shdr, err := validateCommonHeader(payload.Header)
+		if err != nil {
+			return
+		}
+		validCreators[i], errs[i] = getValidCreator(shdr.Creator, e.Signature, e.Payload, chdr.ChannelId)
+	})
+
+	var creators []msp.Identity
+	var msgs, sigs [][]byte
+	var indices []int
+	for i, creator := range validCreators {
+		if creator == nil {
+			continue
+		}
+		creators = append(creators, creator)
+		msgs = append(msgs, envs[i].Payload)
+		sigs = append(sigs, envs[i].Signature)
+		indices = append(indices, i)
+	}
 
-	return nil
+	for j, err := range msp.VerifyBatch(creators, msgs, sigs) {
+		if err != nil {
+			errs[indices[j]] = fmt.Errorf("The creator's signature over the proposal is not valid, err %s", err)
+		}
+	}
+	return errs
 }
 
 // checks for a valid SignatureHeader
@@ -208,7 +276,8 @@ func validateChannelHeader(cHdr *common.ChannelHeader) error {
 	// validate the header type
 	if common.HeaderType(cHdr.Type) != common.HeaderType_ENDORSER_TRANSACTION &&
 		common.HeaderType(cHdr.Type) != common.HeaderType_CONFIG_UPDATE &&
-		common.HeaderType(cHdr.Type) != common.HeaderType_CONFIG {
+		common.HeaderType(cHdr.Type) != common.HeaderType_CONFIG &&
+		customtx.GetProcessor(common.HeaderType(cHdr.Type)) == nil {
 		return fmt.Errorf("invalid header type %s", common.HeaderType(cHdr.Type))
 	}
 
@@ -359,6 +428,19 @@ func validateEndorserTransaction(data []byte, hdr *common.Header) error {
 
 // ValidateTransaction checks that the transaction envelope is properly formed
 func ValidateTransaction(e *common.Envelope) (*common.Payload, pb.TxValidationCode) {
+	return validateTransaction(e, checkSignatureFromCreator)
+}
+
+// ValidateTransactionWithCheckedSignature checks that the transaction envelope is properly formed,
+// as ValidateTransaction does, given the outcome of the check of the signature of its creator by
+// CheckCreatorSignatures
+func ValidateTransactionWithCheckedSignature(e *common.Envelope, sigErr error) (*common.Payload, pb.TxValidationCode) {
+	return validateTransaction(e, func(creatorBytes []byte, sig []byte, msg []byte, ChainID string) error {
+		return sigErr
+	})
+}
+
+func validateTransaction(e *common.Envelope, checkSignature func(creatorBytes []byte, sig []byte, msg []byte, ChainID string) error) (*common.Payload, pb.TxValidationCode) {
 	putilsLogger.Debugf("ValidateTransactionEnvelope starts for envelope %p", e)
 
 	// check for nil argument
@@ -384,7 +466,7 @@ func ValidateTransaction(e *common.Envelope) (*common.Payload, pb.TxValidationCo
 	}
 
 	// validate the signature in the envelope
-	err = checkSignatureFromCreator(shdr.Creator, e.Signature, e.Payload, chdr.ChannelId)
+	err = checkSignature(shdr.Creator, e.Signature, e.Payload, chdr.ChannelId)
 	if err != nil {
 		putilsLogger.Errorf("checkSignatureFromCreator returns err %s", err)
 		return nil, pb.TxValidationCode_BAD_CREATOR_SIGNATURE
@@ -430,6 +512,16 @@ func ValidateTransaction(e *common.Envelope) (*common.Payload, pb.TxValidationCo
 			return payload, pb.TxValidationCode_VALID
 		}
 	default:
-		return nil, pb.TxValidationCode_UNSUPPORTED_TX_PAYLOAD
+		if customtx.GetProcessor(common.HeaderType(chdr.Type)) == nil {
+			return nil, pb.TxValidationCode_UNSUPPORTED_TX_PAYLOAD
+		}
+		// The ledger indexes the custom transactions by ID too, which must be computed as the
+		// one of an endorser transaction for the duplicates to be caught. The processor of the
+		// type validates the rest of the transaction
+		if err = utils.CheckProposalTxID(chdr.TxId, shdr.Nonce, shdr.Creator); err != nil {
+			putilsLogger.Errorf("CheckProposalTxID returns err %s", err)
+			return nil, pb.TxValidationCode_BAD_PROPOSAL_TXID
+		}
+		return payload, pb.TxValidationCode_VALID
 	}
 }
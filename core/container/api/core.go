@@ -35,3 +35,13 @@ type VM interface {
 	Destroy(ctxt context.Context, ccid ccintf.CCID, force bool, noprune bool) error
 	GetVMName(ccID ccintf.CCID, format func(string) (string, error)) (string, error)
 }
+
+// ImageManager is implemented by the VMs running the chaincodes in images, which can be
+// built ahead of the launch of the chaincodes and removed once they are no longer needed
+type ImageManager interface {
+	// BuildImage builds the image of the chaincode unless it exists already, and returns
+	// whether it was built
+	BuildImage(ctxt context.Context, ccid ccintf.CCID, builder BuildSpecFactory) (bool, error)
+	// ListImages returns the chaincodes whose image was built by the peer of ccid
+	ListImages(ctxt context.Context, ccid ccintf.CCID) ([]ccintf.CCID, error)
+}
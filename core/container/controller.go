@@ -225,6 +225,40 @@ func (di DestroyImageReq) getCCID() ccintf.CCID {
 	return di.CCID
 }
 
+//BuildImageReq - properties for building the image of a chaincode ahead of its launch.
+//The response is true if the image was built, false if it existed already
+type BuildImageReq struct {
+	ccintf.CCID
+	Builder api.BuildSpecFactory
+}
+
+func (bi BuildImageReq) do(ctxt context.Context, v api.VM) VMCResp {
+	im, ok := v.(api.ImageManager)
+	if !ok {
+		return VMCResp{Err: fmt.Errorf("VM %T does not run the chaincodes in images", v)}
+	}
+	built, err := im.BuildImage(ctxt, bi.CCID, bi.Builder)
+	if err != nil {
+		return VMCResp{Err: err}
+	}
+	return VMCResp{Resp: built}
+}
+
+func (bi BuildImageReq) getCCID() ccintf.CCID {
+	return bi.CCID
+}
+
+//ListImages returns the chaincodes whose image was built by the peer of ccid with
+//the VMs of type vmtype
+func ListImages(ctxt context.Context, vmtype string, ccid ccintf.CCID) ([]ccintf.CCID, error) {
+	v := vmcontroller.newVM(vmtype)
+	im, ok := v.(api.ImageManager)
+	if !ok {
+		return nil, fmt.Errorf("VM type %s does not run the chaincodes in images", vmtype)
+	}
+	return im.ListImages(ctxt, ccid)
+}
+
 //VMCProcess should be used as follows
 //   . construct a context
 //   . construct req of the right type (e.g., CreateImageReq)
@@ -30,10 +30,12 @@ import (
 
 	"github.com/fsouza/go-dockerclient"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/metadata"
 	"github.com/hyperledger/fabric/common/util"
 	container "github.com/hyperledger/fabric/core/container/api"
 	"github.com/hyperledger/fabric/core/container/ccintf"
 	cutil "github.com/hyperledger/fabric/core/container/util"
+	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/op/go-logging"
 	"github.com/spf13/viper"
 	"golang.org/x/net/context"
@@ -78,6 +80,12 @@ type dockerClient interface {
 	KillContainer(opts docker.KillContainerOptions) error
 	// RemoveContainer removes a docker container, returns an error in case of failure
 	RemoveContainer(opts docker.RemoveContainerOptions) error
+	// InspectImage returns an image by its name or ID, returns docker.ErrNoSuchImage
+	// if it does not exist
+	InspectImage(name string) (*docker.Image, error)
+	// ListImages returns the images matching the options, returns an error in case
+	// of failure
+	ListImages(opts docker.ListImagesOptions) ([]docker.APIImages, error)
 }
 
 // NewDockerVM returns a new DockerVM instance
@@ -426,6 +434,76 @@ func (vm *DockerVM) Destroy(ctxt context.Context, ccid ccintf.CCID, force bool,
 	return err
 }
 
+// BuildImage builds the image of the chaincode ahead of its launch, unless it exists
+// already, and returns whether it was built
+func (vm *DockerVM) BuildImage(ctxt context.Context, ccid ccintf.CCID, builder container.BuildSpecFactory) (bool, error) {
+	imageID, err := vm.GetVMName(ccid, formatImageName)
+	if err != nil {
+		return false, err
+	}
+
+	client, err := vm.getClientFnc()
+	if err != nil {
+		return false, fmt.Errorf("Error creating docker client: %s", err)
+	}
+
+	_, err = client.InspectImage(imageID)
+	if err == nil {
+		dockerLogger.Debugf("Image %s exists already", imageID)
+		return false, nil
+	}
+	if err != docker.ErrNoSuchImage {
+		return false, err
+	}
+
+	reader, err := builder()
+	if err != nil {
+		return false, fmt.Errorf("Error creating image builder for image %s: %s", imageID, err)
+	}
+	if err = vm.deployImage(client, ccid, nil, nil, reader); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ListImages returns the chaincodes whose image was built by the peer of ccid, as
+// identified by the labels of the images and by their names
+func (vm *DockerVM) ListImages(ctxt context.Context, ccid ccintf.CCID) ([]ccintf.CCID, error) {
+	client, err := vm.getClientFnc()
+	if err != nil {
+		return nil, fmt.Errorf("Error creating docker client: %s", err)
+	}
+
+	nameLabel := metadata.BaseDockerLabel + ".chaincode.id.name"
+	versionLabel := metadata.BaseDockerLabel + ".chaincode.id.version"
+	images, err := client.ListImages(docker.ListImagesOptions{Filters: map[string][]string{"label": {nameLabel}}})
+	if err != nil {
+		return nil, err
+	}
+
+	var chaincodes []ccintf.CCID
+	for _, image := range images {
+		chaincode := ccintf.CCID{
+			ChaincodeSpec: &pb.ChaincodeSpec{ChaincodeId: &pb.ChaincodeID{Name: image.Labels[nameLabel]}},
+			NetworkID:     ccid.NetworkID,
+			PeerID:        ccid.PeerID,
+			Version:       image.Labels[versionLabel],
+		}
+		imageID, err := vm.GetVMName(chaincode, formatImageName)
+		if err != nil {
+			continue
+		}
+		// the images of the chaincodes built by other peers sharing the docker daemon have other names
+		for _, tag := range image.RepoTags {
+			if tag == imageID || strings.HasPrefix(tag, imageID+":") {
+				chaincodes = append(chaincodes, chaincode)
+				break
+			}
+		}
+	}
+	return chaincodes, nil
+}
+
 // GetVMName generates the VM name from peer information. It accepts a format
 // function parameter to allow different formatting based on the desired use of
 // the name.
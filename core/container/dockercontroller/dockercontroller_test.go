@@ -34,6 +34,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/common/metadata"
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/chaincode/platforms"
 	"github.com/hyperledger/fabric/core/container/ccintf"
@@ -227,6 +228,73 @@ func Test_Destroy(t *testing.T) {
 	testerr(t, err, true)
 }
 
+func TestBuildImage(t *testing.T) {
+	dvm := DockerVM{getClientFnc: getMockClient}
+	ccid := ccintf.CCID{ChaincodeSpec: &pb.ChaincodeSpec{ChaincodeId: &pb.ChaincodeID{Name: "simple"}}}
+	ctx := context.Background()
+	bldr := func() (io.Reader, error) { return getCodeChainBytesInMem(), nil }
+
+	// Failure case: dockerClient.InspectImage returns an error other than docker.ErrNoSuchImage
+	inspectImgErr = true
+	_, err := dvm.BuildImage(ctx, ccid, bldr)
+	testerr(t, err, false)
+	inspectImgErr = false
+
+	// Failure case: the image does not exist and dockerClient.BuildImage returns error
+	buildErr = true
+	_, err = dvm.BuildImage(ctx, ccid, bldr)
+	testerr(t, err, false)
+	buildErr = false
+
+	// Success case: the image does not exist and gets built
+	built, err := dvm.BuildImage(ctx, ccid, bldr)
+	testerr(t, err, true)
+	assert.True(t, built)
+
+	// Success case: the image exists already
+	imageExists = true
+	built, err = dvm.BuildImage(ctx, ccid, bldr)
+	testerr(t, err, true)
+	assert.False(t, built)
+	imageExists = false
+}
+
+func TestListImages(t *testing.T) {
+	dvm := DockerVM{getClientFnc: getMockClient}
+	ccid := ccintf.CCID{NetworkID: "dev", PeerID: "peer0"}
+	ctx := context.Background()
+
+	image := func(peerID, name, version string) docker.APIImages {
+		cc := ccintf.CCID{ChaincodeSpec: &pb.ChaincodeSpec{ChaincodeId: &pb.ChaincodeID{Name: name}}, NetworkID: "dev", PeerID: peerID, Version: version}
+		imageID, err := dvm.GetVMName(cc, formatImageName)
+		assert.NoError(t, err)
+		return docker.APIImages{
+			RepoTags: []string{imageID + ":latest"},
+			Labels: map[string]string{
+				metadata.BaseDockerLabel + ".chaincode.id.name":    name,
+				metadata.BaseDockerLabel + ".chaincode.id.version": version,
+			},
+		}
+	}
+	listedImages = []docker.APIImages{image("peer0", "mycc", "1.0"), image("peer1", "mycc", "1.0"), image("peer0", "mycc", "2.0")}
+	defer func() { listedImages = nil }()
+
+	ccids, err := dvm.ListImages(ctx, ccid)
+	testerr(t, err, true)
+	// the image built by the other peer is not listed
+	assert.Len(t, ccids, 2)
+	assert.Equal(t, "mycc", ccids[0].ChaincodeSpec.ChaincodeId.Name)
+	assert.Equal(t, "1.0", ccids[0].Version)
+	assert.Equal(t, "2.0", ccids[1].Version)
+	assert.Equal(t, "peer0", ccids[1].PeerID)
+
+	// Failure case: getMockClient returns error
+	getClientErr = true
+	_, err = dvm.ListImages(ctx, ccid)
+	testerr(t, err, false)
+	getClientErr = false
+}
+
 type testCase struct {
 	name           string
 	ccid           ccintf.CCID
@@ -296,7 +364,9 @@ type mockClient struct {
 }
 
 var getClientErr, createErr, noSuchImgErr, buildErr, removeImgErr,
-	startErr, stopErr, killErr, removeErr bool
+	startErr, stopErr, killErr, removeErr, inspectImgErr, imageExists bool
+
+var listedImages []docker.APIImages
 
 func (c *mockClient) CreateContainer(options docker.CreateContainerOptions) (*docker.Container, error) {
 	if createErr {
@@ -336,6 +406,19 @@ func (c *mockClient) RemoveImageExtended(id string, opts docker.RemoveImageOptio
 	return nil
 }
 
+func (c *mockClient) InspectImage(name string) (*docker.Image, error) {
+	if inspectImgErr {
+		return nil, errors.New("Error inspecting image")
+	} else if !imageExists {
+		return nil, docker.ErrNoSuchImage
+	}
+	return &docker.Image{ID: name}, nil
+}
+
+func (c *mockClient) ListImages(opts docker.ListImagesOptions) ([]docker.APIImages, error) {
+	return listedImages, nil
+}
+
 func (c *mockClient) StopContainer(id string, timeout uint) error {
 	if stopErr {
 		return errors.New("Error stopping container")
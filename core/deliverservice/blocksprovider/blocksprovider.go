@@ -83,8 +83,9 @@ type streamClient interface {
 	// Close closes the stream and its underlying connection
 	Close()
 
-	// Disconnect disconnects from the remote node
-	Disconnect()
+	// Disconnect disconnects from the remote node, and if disableEndpoint
+	// is true, avoids reconnecting to it for a while
+	Disconnect(disableEndpoint bool)
 }
 
 // blocksProviderImpl the actual implementation for BlocksProvider interface
@@ -128,6 +129,14 @@ func NewBlocksProvider(chainID string, client streamClient, gossip GossipService
 func (b *blocksProviderImpl) DeliverBlocks() {
 	errorStatusCounter := 0
 	statusCounter := 0
+	backoff := func() {
+		maxDelay := float64(MaxRetryDelay)
+		currDelay := float64(time.Duration(math.Pow(2, float64(statusCounter))) * 100 * time.Millisecond)
+		time.Sleep(time.Duration(math.Min(maxDelay, currDelay)))
+		if currDelay < maxDelay {
+			statusCounter++
+		}
+	}
 	defer b.client.Close()
 	for !b.isDone() {
 		msg, err := b.client.Recv()
@@ -152,17 +161,11 @@ func (b *blocksProviderImpl) DeliverBlocks() {
 				errorStatusCounter = 0
 				logger.Warningf("[%s] Got error %v", b.chainID, t)
 			}
-			maxDelay := float64(MaxRetryDelay)
-			currDelay := float64(time.Duration(math.Pow(2, float64(statusCounter))) * 100 * time.Millisecond)
-			time.Sleep(time.Duration(math.Min(maxDelay, currDelay)))
-			if currDelay < maxDelay {
-				statusCounter++
-			}
-			b.client.Disconnect()
+			backoff()
+			b.client.Disconnect(false)
 			continue
 		case *orderer.DeliverResponse_Block:
 			errorStatusCounter = 0
-			statusCounter = 0
 			seqNum := t.Block.Header.Number
 
 			marshaledBlock, err := proto.Marshal(t.Block)
@@ -171,9 +174,14 @@ func (b *blocksProviderImpl) DeliverBlocks() {
 				continue
 			}
 			if err := b.mcs.VerifyBlock(gossipcommon.ChainID(b.chainID), seqNum, marshaledBlock); err != nil {
-				logger.Errorf("[%s] Error verifying block with sequnce number %d, due to %s", b.chainID, seqNum, err)
+				logger.Errorf("[%s] Error verifying block with sequence number %d, due to %s", b.chainID, seqNum, err)
+				// The block is not signed according to the block validation policy of the channel,
+				// so the orderer may be compromised: fetch the blocks from another orderer instead
+				backoff()
+				b.client.Disconnect(true)
 				continue
 			}
+			statusCounter = 0
 
 			numberOfPeers := len(b.gossip.PeersOfChannel(gossipcommon.ChainID(b.chainID)))
 			// Create payload with a block received
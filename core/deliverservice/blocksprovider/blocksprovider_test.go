@@ -386,3 +386,34 @@ func TestBlockVerificationFailure(t *testing.T) {
 	mcs.On("VerifyBlock", mock.Anything).Return(errors.New("Invalid signature"))
 	makeTestCase(uint64(0), mcs, false, rcvr)(t)
 }
+
+func TestBlockVerificationFailureRefetch(t *testing.T) {
+	// Test emulate an orderer sending a block which fails verification,
+	// and ensures the blocks provider disconnects from it, disables its endpoint,
+	// and delivers the block once it is fetched again
+	bd := mocks.MockBlocksDeliverer{DisconnectCalled: make(chan struct{}, 10)}
+	mcs := &mockMCS{}
+	mcs.On("VerifyBlock", mock.Anything).Return(errors.New("Invalid signature")).Once()
+	mcs.On("VerifyBlock", mock.Anything).Return(nil)
+	gossipServiceAdapter := &mocks.MockGossipServiceAdapter{GossipBlockDisseminations: make(chan uint64, 2)}
+	provider := &blocksProviderImpl{
+		chainID:              "***TEST_CHAINID***",
+		gossip:               gossipServiceAdapter,
+		client:               &bd,
+		mcs:                  mcs,
+		wrongStatusThreshold: wrongStatusThreshold,
+	}
+	defer provider.Stop()
+	bd.MockRecv = mocks.MockRecv
+
+	go provider.DeliverBlocks()
+
+	select {
+	case seq := <-gossipServiceAdapter.GossipBlockDisseminations:
+		assert.Equal(t, uint64(1), seq)
+	case <-time.After(time.Second * 10):
+		assert.Fail(t, "Didn't receive a block within a timely manner")
+	}
+	assert.Len(t, bd.DisconnectCalled, 1)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&bd.DisabledCnt))
+}
@@ -122,7 +122,7 @@ func (bc *broadcastClient) doAction(action func() (interface{}, error)) (interfa
 	}
 	resp, err := action()
 	if err != nil {
-		bc.Disconnect()
+		bc.Disconnect(false)
 		return nil, err
 	}
 	return resp, nil
@@ -150,22 +150,22 @@ func (bc *broadcastClient) connect() error {
 		conn.Close()
 		return err
 	}
-	err = bc.afterConnect(conn, abc, cf)
+	err = bc.afterConnect(conn, endpoint, abc, cf)
 	if err == nil {
 		return nil
 	}
 	logger.Warning("Failed running post-connection procedures:", err)
 	// If we reached here, lets make sure connection is closed
 	// and nullified before we return
-	bc.Disconnect()
+	bc.Disconnect(false)
 	return err
 }
 
-func (bc *broadcastClient) afterConnect(conn *grpc.ClientConn, abc orderer.AtomicBroadcast_DeliverClient, cf context.CancelFunc) error {
+func (bc *broadcastClient) afterConnect(conn *grpc.ClientConn, endpoint string, abc orderer.AtomicBroadcast_DeliverClient, cf context.CancelFunc) error {
 	logger.Debug("Entering")
 	defer logger.Debug("Exiting")
 	bc.Lock()
-	bc.conn = &connection{ClientConn: conn, cancel: cf}
+	bc.conn = &connection{ClientConn: conn, endpoint: endpoint, cancel: cf}
 	bc.BlocksDeliverer = abc
 	if bc.shouldStop() {
 		bc.Unlock()
@@ -216,8 +216,9 @@ func (bc *broadcastClient) Close() {
 	bc.conn.Close()
 }
 
-// Disconnect makes the client close the existing connection
-func (bc *broadcastClient) Disconnect() {
+// Disconnect makes the client close the existing connection, and if
+// disableEndpoint is true, avoid reconnecting to the same endpoint
+func (bc *broadcastClient) Disconnect(disableEndpoint bool) {
 	logger.Debug("Entering")
 	defer logger.Debug("Exiting")
 	bc.Lock()
@@ -225,6 +226,9 @@ func (bc *broadcastClient) Disconnect() {
 	if bc.conn == nil {
 		return
 	}
+	if disableEndpoint {
+		bc.prod.DisableEndpoint(bc.conn.endpoint)
+	}
 	bc.conn.Close()
 	bc.conn = nil
 	bc.BlocksDeliverer = nil
@@ -233,7 +237,8 @@ func (bc *broadcastClient) Disconnect() {
 type connection struct {
 	sync.Once
 	*grpc.ClientConn
-	cancel context.CancelFunc
+	endpoint string
+	cancel   context.CancelFunc
 }
 
 func (c *connection) Close() error {
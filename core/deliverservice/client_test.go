@@ -153,6 +153,11 @@ func (cp *connProducer) UpdateEndpoints(endpoints []string) {
 	panic("Not implemented")
 }
 
+// DisableEndpoint makes the ConnectionProducer skip the given endpoint
+func (cp *connProducer) DisableEndpoint(endpoint string) {
+	panic("Not implemented")
+}
+
 func TestOrderingServiceConnFailure(t *testing.T) {
 	testOrderingServiceConnFailure(t, blockDelivererConsumerWithRecv)
 	testOrderingServiceConnFailure(t, blockDelivererConsumerWithSend)
@@ -644,7 +649,7 @@ func TestDisconnect(t *testing.T) {
 		stopChan <- struct{}{}
 	}()
 	waitForConnectionToSomeOSN()
-	cl.Disconnect()
+	cl.Disconnect(false)
 
 	i := 0
 	for (os1.ConnCount() == 0 || os2.ConnCount() == 0) && i < 100 {
@@ -653,7 +658,7 @@ func TestDisconnect(t *testing.T) {
 		if i == 100 {
 			assert.Fail(t, "Didn't switch to other instance after many attempts")
 		}
-		cl.Disconnect()
+		cl.Disconnect(false)
 		time.Sleep(time.Millisecond * 500)
 	}
 	cl.Close()
@@ -73,8 +73,9 @@ type MockBlocksDeliverer struct {
 	CloseCalled      chan struct{}
 	Pos              uint64
 	grpc.ClientStream
-	RecvCnt  int32
-	MockRecv func(mock *MockBlocksDeliverer) (*orderer.DeliverResponse, error)
+	RecvCnt     int32
+	DisabledCnt int32
+	MockRecv    func(mock *MockBlocksDeliverer) (*orderer.DeliverResponse, error)
 }
 
 // Recv gets responses from the ordering service, currently mocked to return
@@ -123,7 +124,13 @@ func (mock *MockBlocksDeliverer) Send(env *common.Envelope) error {
 	return nil
 }
 
-func (mock *MockBlocksDeliverer) Disconnect() {
+func (mock *MockBlocksDeliverer) Disconnect(disableEndpoint bool) {
+	if disableEndpoint {
+		atomic.AddInt32(&mock.DisabledCnt, 1)
+	}
+	if mock.DisconnectCalled == nil {
+		return
+	}
 	mock.DisconnectCalled <- struct{}{}
 }
 
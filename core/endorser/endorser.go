@@ -22,6 +22,7 @@ import (
 	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/common/validation"
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	"github.com/hyperledger/fabric/core/peer"
 	"github.com/hyperledger/fabric/core/policy"
 	syscc "github.com/hyperledger/fabric/core/scc"
@@ -30,6 +31,7 @@ import (
 	"github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	putils "github.com/hyperledger/fabric/protos/utils"
+	"github.com/spf13/viper"
 )
 
 // >>>>> begin errors section >>>>>
@@ -53,11 +55,17 @@ var endorserLogger = flogging.MustGetLogger("endorser")
 // Endorser provides the Endorser service ProcessProposal
 type Endorser struct {
 	policyChecker policy.PolicyChecker
+	// readOnly refuses to endorse the proposals whose simulation writes to the ledger
+	readOnly bool
+	// maxEventPayloadSize is the maximum size of the payload of the chaincode events
+	// endorsed, 0 meaning no limit
+	maxEventPayloadSize int
 }
 
 // NewEndorserServer creates and returns a new Endorser server instance.
 func NewEndorserServer() pb.EndorserServer {
 	e := new(Endorser)
+	e.maxEventPayloadSize = viper.GetInt("chaincode.maxEventPayloadSize")
 	e.policyChecker = policy.NewPolicyChecker(
 		peer.NewChannelPolicyManagerGetter(),
 		mgmt.GetLocalMSP(),
@@ -67,6 +75,15 @@ func NewEndorserServer() pb.EndorserServer {
 	return e
 }
 
+// NewReadOnlyEndorserServer creates and returns a new Endorser server instance for a
+// read-only replica, which only endorses the proposals whose simulation does not write
+// to the ledger, such as queries
+func NewReadOnlyEndorserServer() pb.EndorserServer {
+	e := NewEndorserServer().(*Endorser)
+	e.readOnly = true
+	return e
+}
+
 // checkACL checks that the supplied proposal complies
 // with the writers policy of the chain
 func (e *Endorser) checkACL(signedProp *pb.SignedProposal, chdr *common.ChannelHeader, shdr *common.SignatureHeader, hdrext *pb.ChaincodeHeaderExtension) error {
@@ -94,6 +111,49 @@ func (*Endorser) getHistoryQueryExecutor(ledgername string) (ledger.HistoryQuery
 	return lgr.NewHistoryQueryExecutor()
 }
 
+func (*Endorser) getHistoricalQueryExecutor(ledgername string, height uint64) (ledger.HistoricalQueryExecutor, error) {
+	lgr := peer.GetLedger(ledgername)
+	if lgr == nil {
+		return nil, fmt.Errorf("channel does not exist: %s", ledgername)
+	}
+	return lgr.NewHistoricalQueryExecutor(height)
+}
+
+// historicalTxSimulator simulates a proposal against the state as of a past height of the
+// ledger. It is read-only, so that the simulation has no results
+type historicalTxSimulator struct {
+	ledger.HistoricalQueryExecutor
+	height uint64
+}
+
+func (s *historicalTxSimulator) SetState(namespace string, key string, value []byte) error {
+	return s.errReadOnly()
+}
+
+func (s *historicalTxSimulator) DeleteState(namespace string, key string) error {
+	return s.errReadOnly()
+}
+
+func (s *historicalTxSimulator) SetStateMultipleKeys(namespace string, kvs map[string][]byte) error {
+	return s.errReadOnly()
+}
+
+func (s *historicalTxSimulator) ExecuteUpdate(query string) error {
+	return s.errReadOnly()
+}
+
+func (s *historicalTxSimulator) GetTxSimulationResults() ([]byte, error) {
+	return nil, nil
+}
+
+func (s *historicalTxSimulator) GetHeight() (uint64, error) {
+	return s.height, nil
+}
+
+func (s *historicalTxSimulator) errReadOnly() error {
+	return fmt.Errorf("the state as of height %d is read-only", s.height)
+}
+
 //call specified chaincode (system or user)
 func (e *Endorser) callChaincode(ctxt context.Context, chainID string, version string, txid string, signedProp *pb.SignedProposal, prop *pb.Proposal, cis *pb.ChaincodeInvocationSpec, cid *pb.ChaincodeID, txsim ledger.TxSimulator) (*pb.Response, *pb.ChaincodeEvent, error) {
 	endorserLogger.Debugf("Entry - txid: %s channel id: %s version: %s", txid, chainID, version)
@@ -368,6 +428,32 @@ func (e *Endorser) endorseProposal(ctx context.Context, chainID string, txid str
 
 // ProcessProposal process the Proposal
 func (e *Endorser) ProcessProposal(ctx context.Context, signedProp *pb.SignedProposal) (*pb.ProposalResponse, error) {
+	return e.processProposal(ctx, signedProp, false, 0)
+}
+
+// SimulateProposal executes the proposal like ProcessProposal, but returns the simulation
+// results and the response of the chaincode without endorsing them, so that applications
+// can preview the outcome of a transaction. Only the proposals of application chaincodes
+// on a channel are simulated, as those of system chaincodes, such as installing or joining
+// a channel, have effects beyond the simulation
+func (e *Endorser) SimulateProposal(ctx context.Context, signedProp *pb.SignedProposal) (*pb.ProposalResponse, error) {
+	return e.processProposal(ctx, signedProp, true, 0)
+}
+
+// SimulateProposalAt simulates the proposal like SimulateProposal, but against the state as of
+// the given height of the ledger, read from the history database. The chaincode reads the state
+// left by the blocks below the height, with the definition of the chaincode then, and cannot write
+func (e *Endorser) SimulateProposalAt(ctx context.Context, historicalProp *pb.HistoricalProposal) (*pb.ProposalResponse, error) {
+	if historicalProp.Height == 0 {
+		err := errors.New("the height of a historical proposal must be at least 1")
+		return &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}, err
+	}
+	return e.processProposal(ctx, historicalProp.SignedProposal, true, historicalProp.Height)
+}
+
+// processProposal processes the proposal against the current state, or against the state as of
+// atHeight if it is not 0
+func (e *Endorser) processProposal(ctx context.Context, signedProp *pb.SignedProposal, simulateOnly bool, atHeight uint64) (*pb.ProposalResponse, error) {
 	endorserLogger.Debugf("Entry")
 	defer endorserLogger.Debugf("Exit")
 	// at first, we check whether the message is valid
@@ -396,6 +482,11 @@ func (e *Endorser) ProcessProposal(ctx context.Context, signedProp *pb.SignedPro
 
 	chainID := chdr.ChannelId
 
+	if simulateOnly && (chainID == "" || syscc.IsSysCC(hdrExt.ChaincodeId.Name)) {
+		err = fmt.Errorf("Only the proposals of application chaincodes on a channel can be simulated, not of chaincode %s on channel [%s]", hdrExt.ChaincodeId.Name, chainID)
+		return &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}, err
+	}
+
 	// Check for uniqueness of prop.TxID with ledger
 	// Notice that ValidateProposalMessage has already verified
 	// that TxID is computed properly
@@ -435,10 +526,27 @@ func (e *Endorser) ProcessProposal(ctx context.Context, signedProp *pb.SignedPro
 	// Also obtain a history query executor for history queries, since tx simulator does not cover history
 	var txsim ledger.TxSimulator
 	var historyQueryExecutor ledger.HistoryQueryExecutor
-	if chainID != "" {
+	var height uint64
+	if chainID != "" && atHeight > 0 {
+		historicalQueryExecutor, err := e.getHistoricalQueryExecutor(chainID, atHeight)
+		if err != nil {
+			return &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}, err
+		}
+		txsim = &historicalTxSimulator{historicalQueryExecutor, atHeight}
+		height = atHeight
+		ctx = context.WithValue(ctx, chaincode.HistoryQueryExecutorKey, historicalQueryExecutor)
+
+		defer txsim.Done()
+	} else if chainID != "" {
 		if txsim, err = e.getTxSimulator(chainID); err != nil {
 			return &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}, err
 		}
+		// the height has to be read while the simulator holds the state, i.e. before the
+		// simulation results are retrieved
+		if height, err = txsim.GetHeight(); err != nil {
+			txsim.Done()
+			return &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}, err
+		}
 		if historyQueryExecutor, err = e.getHistoryQueryExecutor(chainID); err != nil {
 			return &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}, err
 		}
@@ -459,7 +567,12 @@ func (e *Endorser) ProcessProposal(ctx context.Context, signedProp *pb.SignedPro
 	//1 -- simulate
 	cd, res, simulationResult, ccevent, err := e.simulateProposal(ctx, chainID, txid, signedProp, prop, hdrExt.ChaincodeId, txsim)
 	if err != nil {
-		return &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}, err
+		status := int32(500)
+		// The chaincode timing out or being unavailable is transient, and worth a retry
+		if ee, ok := err.(*chaincode.ExecuteError); ok {
+			status = ee.Status()
+		}
+		return &pb.ProposalResponse{Response: &pb.Response{Status: status, Message: err.Error()}}, err
 	}
 	if res != nil {
 		if res.Status >= shim.ERROR {
@@ -475,11 +588,38 @@ func (e *Endorser) ProcessProposal(ctx context.Context, signedProp *pb.SignedPro
 			if err != nil {
 				return &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}, err
 			}
+			pResp.SimulationHeight = height
 
 			return pResp, &chaincodeError{res.Status, res.Message}
 		}
 	}
 
+	if simulateOnly {
+		var cceventBytes []byte
+		if ccevent != nil {
+			if cceventBytes, err = putils.GetBytesChaincodeEvent(ccevent); err != nil {
+				return nil, fmt.Errorf("failed to marshal event bytes - %s", err)
+			}
+		}
+		pResp, err := putils.CreateUnendorsedProposalResponse(prop.Header, prop.Payload, res, simulationResult, cceventBytes, hdrExt.ChaincodeId, hdrExt.PayloadVisibility)
+		if err != nil {
+			return &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}, err
+		}
+		pResp.SimulationHeight = height
+		endorserLogger.Debugf("Simulated txid: %s without endorsing it", txid)
+		return pResp, nil
+	}
+
+	if err := e.checkEventPayloadSize(ccevent); err != nil {
+		return &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}, err
+	}
+
+	if e.readOnly {
+		if err := checkReadOnly(simulationResult); err != nil {
+			return &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}, err
+		}
+	}
+
 	//2 -- endorse and get a marshalled ProposalResponse message
 	var pResp *pb.ProposalResponse
 
@@ -493,6 +633,7 @@ func (e *Endorser) ProcessProposal(ctx context.Context, signedProp *pb.SignedPro
 			return &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}, err
 		}
 		if pResp != nil {
+			pResp.SimulationHeight = height
 			if res.Status >= shim.ERRORTHRESHOLD {
 				endorserLogger.Debugf("endorseProposal() resulted in chaincode error for txid: %s", txid)
 				return pResp, &chaincodeError{res.Status, res.Message}
@@ -504,6 +645,7 @@ func (e *Endorser) ProcessProposal(ctx context.Context, signedProp *pb.SignedPro
 	// contains the "return value" from the
 	// chaincode invocation
 	pResp.Response.Payload = res.Payload
+	pResp.SimulationHeight = height
 
 	return pResp, nil
 }
@@ -535,3 +677,29 @@ func (e *Endorser) commitTxSimulation(proposal *pb.Proposal, chainID string, sig
 
 	return nil
 }
+
+// checkReadOnly returns an error if the simulation results write to the ledger
+func checkReadOnly(simulationResult []byte) error {
+	if simulationResult == nil {
+		return nil
+	}
+	txRWSet := &rwsetutil.TxRwSet{}
+	if err := txRWSet.FromProtoBytes(simulationResult); err != nil {
+		return fmt.Errorf("failed to unmarshal the simulation results: %s", err)
+	}
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		if len(nsRWSet.KvRwSet.Writes) > 0 {
+			return fmt.Errorf("this peer is a read-only replica, it does not endorse proposals writing to the ledger (namespace %s)", nsRWSet.NameSpace)
+		}
+	}
+	return nil
+}
+
+// checkEventPayloadSize returns an error if the payload of the chaincode event exceeds the
+// maximum size of the endorsed events
+func (e *Endorser) checkEventPayloadSize(ccevent *pb.ChaincodeEvent) error {
+	if ccevent == nil || e.maxEventPayloadSize <= 0 || len(ccevent.Payload) <= e.maxEventPayloadSize {
+		return nil
+	}
+	return fmt.Errorf("the payload of chaincode event %s is %d bytes, over the maximum of %d bytes; split it into chunks with shim.SetEventChunk", ccevent.EventName, len(ccevent.Payload), e.maxEventPayloadSize)
+}
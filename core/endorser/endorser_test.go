@@ -40,6 +40,7 @@ import (
 	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/config"
 	"github.com/hyperledger/fabric/core/container"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	"github.com/hyperledger/fabric/core/peer"
 	syscc "github.com/hyperledger/fabric/core/scc"
 	"github.com/hyperledger/fabric/core/testutil"
@@ -724,6 +725,60 @@ func TestInvokeSccFail(t *testing.T) {
 	}
 }
 
+// TestSimulateSccFail makes sure that simulating the proposal of a system chaincode fails
+func TestSimulateSccFail(t *testing.T) {
+	creator, _ := signer.Serialize()
+	spec := &pb.ChaincodeSpec{Type: 1, ChaincodeId: &pb.ChaincodeID{Name: "lscc"}, Input: &pb.ChaincodeInput{Args: util.ToChaincodeArgs("getinstalledchaincodes")}}
+	invocation := &pb.ChaincodeInvocationSpec{ChaincodeSpec: spec}
+	prop, _, err := pbutils.CreateProposalFromCIS(common.HeaderType_ENDORSER_TRANSACTION, util.GetTestChainID(), invocation, creator)
+	assert.NoError(t, err)
+	signedProp, err := getSignedProposal(prop, signer)
+	assert.NoError(t, err)
+
+	resp, err := endorserServer.SimulateProposal(context.Background(), signedProp)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Only the proposals of application chaincodes on a channel can be simulated")
+	assert.Equal(t, int32(500), resp.Response.Status)
+}
+
+// TestSimulateAtFailures makes sure that simulating a proposal at height 0 or over the height of
+// the ledger fails
+func TestSimulateAtFailures(t *testing.T) {
+	creator, _ := signer.Serialize()
+	spec := &pb.ChaincodeSpec{Type: 1, ChaincodeId: &pb.ChaincodeID{Name: "ex02"}, Input: &pb.ChaincodeInput{Args: util.ToChaincodeArgs("query", "a")}}
+	invocation := &pb.ChaincodeInvocationSpec{ChaincodeSpec: spec}
+	prop, _, err := pbutils.CreateProposalFromCIS(common.HeaderType_ENDORSER_TRANSACTION, util.GetTestChainID(), invocation, creator)
+	assert.NoError(t, err)
+	signedProp, err := getSignedProposal(prop, signer)
+	assert.NoError(t, err)
+
+	resp, err := endorserServer.SimulateProposalAt(context.Background(), &pb.HistoricalProposal{SignedProposal: signedProp})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "must be at least 1")
+	assert.Equal(t, int32(500), resp.Response.Status)
+
+	resp, err = endorserServer.SimulateProposalAt(context.Background(), &pb.HistoricalProposal{SignedProposal: signedProp, Height: 1000})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "out of the range of the ledger")
+	assert.Equal(t, int32(500), resp.Response.Status)
+}
+
+// TestHistoricalTxSimulatorReadOnly makes sure that the simulator of a historical proposal
+// refuses writes and has no simulation results
+func TestHistoricalTxSimulatorReadOnly(t *testing.T) {
+	txsim := &historicalTxSimulator{height: 3}
+	assert.Error(t, txsim.SetState("ns", "key", []byte("value")))
+	assert.Error(t, txsim.DeleteState("ns", "key"))
+	assert.Error(t, txsim.SetStateMultipleKeys("ns", map[string][]byte{"key": []byte("value")}))
+	assert.Error(t, txsim.ExecuteUpdate("{}"))
+	results, err := txsim.GetTxSimulationResults()
+	assert.NoError(t, err)
+	assert.Nil(t, results)
+	height, err := txsim.GetHeight()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), height)
+}
+
 func newTempDir() string {
 	tempDir, err := ioutil.TempDir("", "fabric-")
 	if err != nil {
@@ -732,6 +787,37 @@ func newTempDir() string {
 	return tempDir
 }
 
+func TestCheckReadOnly(t *testing.T) {
+	assert.NoError(t, checkReadOnly(nil), "Should accept a proposal without simulation results")
+
+	builder := rwsetutil.NewRWSetBuilder()
+	builder.AddToReadSet("mycc", "key", nil)
+	results, err := builder.GetTxReadWriteSet().ToProtoBytes()
+	assert.NoError(t, err)
+	assert.NoError(t, checkReadOnly(results), "Should accept a proposal only reading the ledger")
+
+	builder.AddToWriteSet("mycc", "key", []byte("value"))
+	results, err = builder.GetTxReadWriteSet().ToProtoBytes()
+	assert.NoError(t, err)
+	err = checkReadOnly(results)
+	assert.Error(t, err, "Should refuse a proposal writing to the ledger")
+	assert.Contains(t, err.Error(), "read-only replica")
+
+	assert.Error(t, checkReadOnly([]byte("garbage")), "Should refuse malformed simulation results")
+}
+
+func TestCheckEventPayloadSize(t *testing.T) {
+	e := &Endorser{maxEventPayloadSize: 4}
+	assert.NoError(t, e.checkEventPayloadSize(nil), "Should accept a proposal without event")
+	assert.NoError(t, e.checkEventPayloadSize(&pb.ChaincodeEvent{EventName: "ev", Payload: []byte("1234")}))
+	err := e.checkEventPayloadSize(&pb.ChaincodeEvent{EventName: "ev", Payload: []byte("12345")})
+	assert.Error(t, err, "Should refuse an event over the maximum payload size")
+	assert.Contains(t, err.Error(), "over the maximum of 4 bytes")
+
+	e.maxEventPayloadSize = 0
+	assert.NoError(t, e.checkEventPayloadSize(&pb.ChaincodeEvent{EventName: "ev", Payload: []byte("12345")}), "Should not limit the events without maximum")
+}
+
 func TestMain(m *testing.M) {
 	setupTestConfig()
 
@@ -0,0 +1,357 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package gateway implements the Gateway service of the peer, which submits transactions on
+// behalf of the clients: it endorses their proposals on the peers required by the endorsement
+// policy of the chaincode, sends the transactions they sign to the ordering service, and waits
+// for the peer to commit them
+package gateway
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/msp"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	logging "github.com/op/go-logging"
+	"golang.org/x/net/context"
+)
+
+var logger = logging.MustGetLogger("gateway")
+
+// Peer is a remote peer of a channel
+type Peer struct {
+	Endpoint string
+	MSPID    string
+}
+
+// Support provides the gateway with the services of the peer
+type Support interface {
+	// PeersOfChannel returns the remote peers of a channel
+	PeersOfChannel(channelID string) []Peer
+
+	// Endorse has a remote peer endorse a proposal
+	Endorse(ctx context.Context, endpoint string, signedProp *pb.SignedProposal) (*pb.ProposalResponse, error)
+
+	// EndorsementPolicy returns the endorsement policy of a chaincode instantiated on a channel
+	EndorsementPolicy(channelID, chaincodeName string) (policies.Policy, error)
+
+	// Broadcast sends a transaction to the ordering service of a channel
+	Broadcast(ctx context.Context, channelID string, env *cb.Envelope) error
+
+	// CommitStatus waits for a transaction to be committed by the peer
+	CommitStatus(ctx context.Context, channelID, txID string) (blockNumber uint64, code pb.TxValidationCode, err error)
+
+	// ConfigSequence returns the sequence number of the current config of a channel
+	ConfigSequence(channelID string) uint64
+}
+
+type server struct {
+	endpoint string
+	endorser pb.EndorserServer
+	support  Support
+	timeout  time.Duration
+	plans    *planCache
+}
+
+// NewServer creates the Gateway service of the peer at endpoint, endorsing the proposals with the
+// endorser of the peer first. Each endorsement, either local or remote, is bounded by timeout
+func NewServer(endpoint string, endorser pb.EndorserServer, support Support, timeout time.Duration) pb.GatewayServer {
+	return &server{
+		endpoint: endpoint,
+		endorser: endorser,
+		support:  support,
+		timeout:  timeout,
+		plans:    newPlanCache(),
+	}
+}
+
+func (s *server) Submit(stream pb.Gateway_SubmitServer) error {
+	req, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	signedProp := req.GetProposal()
+	if signedProp == nil {
+		return fmt.Errorf("expected a proposal as the first message of the stream")
+	}
+
+	prepared, chdr, err := s.prepare(stream.Context(), signedProp)
+	if err != nil {
+		return err
+	}
+	err = stream.Send(&pb.SubmitResponse{Type: &pb.SubmitResponse_Prepared{Prepared: prepared}})
+	if err != nil {
+		return err
+	}
+
+	req, err = stream.Recv()
+	if err == io.EOF {
+		logger.Debugf("[channel: %s] Transaction %s abandoned by the client", chdr.ChannelId, chdr.TxId)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	signature := req.GetSignature()
+	if signature == nil {
+		return fmt.Errorf("expected the signature of transaction %s", chdr.TxId)
+	}
+
+	env := &cb.Envelope{Payload: prepared.Payload, Signature: signature}
+	return s.commit(stream.Context(), chdr.ChannelId, chdr.TxId, env, func(status *pb.TransactionStatus) error {
+		return stream.Send(&pb.SubmitResponse{Type: &pb.SubmitResponse_Status{Status: status}})
+	})
+}
+
+func (s *server) Prepare(ctx context.Context, signedProp *pb.SignedProposal) (*pb.PreparedTransaction, error) {
+	prepared, _, err := s.prepare(ctx, signedProp)
+	return prepared, err
+}
+
+func (s *server) Commit(env *cb.Envelope, stream pb.Gateway_CommitServer) error {
+	payload, err := utils.UnmarshalPayload(env.Payload)
+	if err != nil {
+		return err
+	}
+	if payload.Header == nil {
+		return fmt.Errorf("missing header in the payload of the transaction")
+	}
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return err
+	}
+	if cb.HeaderType(chdr.Type) != cb.HeaderType_ENDORSER_TRANSACTION {
+		return fmt.Errorf("invalid transaction type %d, only endorser transactions can be committed", chdr.Type)
+	}
+	if chdr.ChannelId == "" {
+		return fmt.Errorf("transactions without channel cannot be committed")
+	}
+	return s.commit(stream.Context(), chdr.ChannelId, chdr.TxId, env, stream.Send)
+}
+
+// prepare endorses the proposal and assembles the transaction to be signed by the client
+func (s *server) prepare(ctx context.Context, signedProp *pb.SignedProposal) (*pb.PreparedTransaction, *cb.ChannelHeader, error) {
+	prop, chdr, ccName, err := parseProposal(signedProp)
+	if err != nil {
+		return nil, nil, err
+	}
+	logger.Debugf("[channel: %s] Submitting transaction %s of chaincode %s", chdr.ChannelId, chdr.TxId, ccName)
+
+	endorsed, err := s.endorse(ctx, chdr.ChannelId, ccName, signedProp)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to endorse transaction %s: %s", chdr.TxId, err)
+	}
+
+	responses := make([]*pb.ProposalResponse, len(endorsed))
+	endorsers := make([]string, len(endorsed))
+	for i, e := range endorsed {
+		responses[i] = e.response
+		endorsers[i] = e.endpoint
+	}
+	payload, err := utils.CreateUnsignedTx(prop, responses...)
+	if err != nil {
+		return nil, nil, err
+	}
+	payloadBytes, err := utils.GetBytesPayload(payload)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &pb.PreparedTransaction{
+		TxId:      chdr.TxId,
+		Response:  responses[0].Response,
+		Payload:   payloadBytes,
+		Endorsers: endorsers,
+	}, chdr, nil
+}
+
+// commit sends the signed transaction to the ordering service, and then sends its status once
+// submitted and once committed by the peer
+func (s *server) commit(ctx context.Context, channelID, txID string, env *cb.Envelope, send func(*pb.TransactionStatus) error) error {
+	if err := s.support.Broadcast(ctx, channelID, env); err != nil {
+		return fmt.Errorf("failed to submit transaction %s to the ordering service: %s", txID, err)
+	}
+	err := send(&pb.TransactionStatus{
+		Stage: pb.TransactionStatus_SUBMITTED,
+		TxId:  txID,
+	})
+	if err != nil {
+		return err
+	}
+
+	blockNumber, code, err := s.support.CommitStatus(ctx, channelID, txID)
+	if err != nil {
+		return fmt.Errorf("failed to wait for the commit of transaction %s: %s", txID, err)
+	}
+	return send(&pb.TransactionStatus{
+		Stage:          pb.TransactionStatus_COMMITTED,
+		TxId:           txID,
+		BlockNumber:    blockNumber,
+		ValidationCode: code,
+	})
+}
+
+// parseProposal returns the proposal, its channel header and the name of the chaincode it invokes
+func parseProposal(signedProp *pb.SignedProposal) (*pb.Proposal, *cb.ChannelHeader, string, error) {
+	prop, err := utils.GetProposal(signedProp.ProposalBytes)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	hdr, err := utils.GetHeader(prop.Header)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	chdr, err := utils.UnmarshalChannelHeader(hdr.ChannelHeader)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	if cb.HeaderType(chdr.Type) != cb.HeaderType_ENDORSER_TRANSACTION {
+		return nil, nil, "", fmt.Errorf("invalid proposal type %d, only endorser transactions can be submitted", chdr.Type)
+	}
+	if chdr.ChannelId == "" {
+		return nil, nil, "", fmt.Errorf("proposals without channel cannot be submitted")
+	}
+	hdrExt, err := utils.GetChaincodeHeaderExtension(hdr)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	if hdrExt.ChaincodeId == nil || hdrExt.ChaincodeId.Name == "" {
+		return nil, nil, "", fmt.Errorf("proposal does not specify a chaincode")
+	}
+	return prop, chdr, hdrExt.ChaincodeId.Name, nil
+}
+
+type endorsement struct {
+	endpoint string
+	mspID    string
+	response *pb.ProposalResponse
+}
+
+// endorse collects the endorsements of the proposal, starting with the one of the local peer,
+// until they satisfy the endorsement policy. The peers of the endorsement plan of the chaincode
+// are asked first, and then the remote peers of the organizations which did not endorse the
+// proposal yet
+func (s *server) endorse(ctx context.Context, channelID, ccName string, signedProp *pb.SignedProposal) ([]*endorsement, error) {
+	local, err := s.endorseWith(ctx, func(ctx context.Context) (*pb.ProposalResponse, error) {
+		return s.endorser.ProcessProposal(ctx, signedProp)
+	})
+	if err != nil {
+		return nil, err
+	}
+	endorsed := []*endorsement{{endpoint: s.endpoint, mspID: endorserMSPID(local), response: local}}
+
+	policy, err := s.support.EndorsementPolicy(channelID, ccName)
+	if err != nil {
+		return nil, err
+	}
+	if policy.Evaluate(signedData(endorsed)) == nil {
+		return endorsed, nil
+	}
+
+	peers := s.support.PeersOfChannel(channelID)
+	configSequence := s.support.ConfigSequence(channelID)
+	endorsedOrgs := map[string]bool{endorsed[0].mspID: true}
+	asked := map[string]bool{}
+
+	// ask has a remote peer endorse the proposal, and returns whether the endorsements
+	// collected satisfy the endorsement policy
+	ask := func(p Peer) bool {
+		asked[p.Endpoint] = true
+		resp, err := s.endorseWith(ctx, func(ctx context.Context) (*pb.ProposalResponse, error) {
+			return s.support.Endorse(ctx, p.Endpoint, signedProp)
+		})
+		if err != nil {
+			logger.Warningf("[channel: %s] Peer %s failed to endorse the proposal: %s", channelID, p.Endpoint, err)
+			return false
+		}
+		if !bytes.Equal(resp.Payload, local.Payload) {
+			logger.Warningf("[channel: %s] Peer %s simulated the proposal with different results", channelID, p.Endpoint)
+			return false
+		}
+		endorsed = append(endorsed, &endorsement{endpoint: p.Endpoint, mspID: p.MSPID, response: resp})
+		endorsedOrgs[p.MSPID] = true
+		return policy.Evaluate(signedData(endorsed)) == nil
+	}
+	satisfied := func() ([]*endorsement, error) {
+		planPeers := make([]Peer, len(endorsed)-1)
+		for i, e := range endorsed[1:] {
+			planPeers[i] = Peer{Endpoint: e.endpoint, MSPID: e.mspID}
+		}
+		s.plans.put(channelID, ccName, configSequence, peers, planPeers)
+		return endorsed, nil
+	}
+
+	for _, p := range s.plans.get(channelID, ccName, configSequence, peers) {
+		if ask(p) {
+			return satisfied()
+		}
+	}
+
+	// A first round asks a peer of each of the organizations which did not endorse the
+	// proposal yet, and a second round the other peers, for the policies requiring several
+	// peers of an organization
+	for round := 0; round < 2; round++ {
+		for _, p := range peers {
+			if asked[p.Endpoint] || (round == 0 && endorsedOrgs[p.MSPID]) {
+				continue
+			}
+			if ask(p) {
+				return satisfied()
+			}
+		}
+	}
+	s.plans.invalidate(channelID, ccName)
+	return nil, fmt.Errorf("the endorsements of the %d peers which endorsed the proposal do not satisfy the endorsement policy of chaincode %s", len(endorsed), ccName)
+}
+
+// endorseWith bounds an endorsement with the timeout of the gateway and checks its response
+func (s *server) endorseWith(ctx context.Context, endorse func(context.Context) (*pb.ProposalResponse, error)) (*pb.ProposalResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	resp, err := endorse(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Response == nil {
+		return nil, fmt.Errorf("missing response")
+	}
+	if resp.Response.Status != shim.OK {
+		return nil, fmt.Errorf("proposal failed with status %d: %s", resp.Response.Status, resp.Response.Message)
+	}
+	if resp.Endorsement == nil {
+		return nil, fmt.Errorf("missing endorsement")
+	}
+	return resp, nil
+}
+
+func endorserMSPID(resp *pb.ProposalResponse) string {
+	sid := &msp.SerializedIdentity{}
+	if err := proto.Unmarshal(resp.Endorsement.Endorser, sid); err != nil {
+		return ""
+	}
+	return sid.Mspid
+}
+
+// signedData returns the endorsements as they are evaluated against the endorsement policy
+// during the validation of the transaction
+func signedData(endorsed []*endorsement) []*cb.SignedData {
+	sd := make([]*cb.SignedData, len(endorsed))
+	for i, e := range endorsed {
+		sd[i] = &cb.SignedData{
+			Data:      append(append([]byte{}, e.response.Payload...), e.response.Endorsement.Endorser...),
+			Identity:  e.response.Endorsement.Endorser,
+			Signature: e.response.Endorsement.Signature,
+		}
+	}
+	return sd
+}
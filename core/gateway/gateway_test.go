@@ -0,0 +1,435 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/policies"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/msp"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+func endorsedResponse(mspID, endpoint string, payload []byte) *pb.ProposalResponse {
+	return &pb.ProposalResponse{
+		Response: &pb.Response{Status: 200, Payload: []byte("result")},
+		Payload:  payload,
+		Endorsement: &pb.Endorsement{
+			Endorser:  utils.MarshalOrPanic(&msp.SerializedIdentity{Mspid: mspID, IdBytes: []byte(endpoint)}),
+			Signature: []byte(endpoint),
+		},
+	}
+}
+
+type mockEndorser struct {
+	resp *pb.ProposalResponse
+	err  error
+}
+
+func (me *mockEndorser) ProcessProposal(ctx context.Context, signedProp *pb.SignedProposal) (*pb.ProposalResponse, error) {
+	return me.resp, me.err
+}
+
+func (me *mockEndorser) SimulateProposal(ctx context.Context, signedProp *pb.SignedProposal) (*pb.ProposalResponse, error) {
+	return me.resp, me.err
+}
+
+func (me *mockEndorser) SimulateProposalAt(ctx context.Context, historicalProp *pb.HistoricalProposal) (*pb.ProposalResponse, error) {
+	return me.resp, me.err
+}
+
+// orgsPolicy requires an endorsement of each of its organizations, and count endorsements overall
+type orgsPolicy struct {
+	orgs  []string
+	count int
+}
+
+func (op *orgsPolicy) Evaluate(signatureSet []*cb.SignedData) error {
+	orgs := map[string]bool{}
+	for _, sd := range signatureSet {
+		sid := &msp.SerializedIdentity{}
+		if err := proto.Unmarshal(sd.Identity, sid); err != nil {
+			return err
+		}
+		orgs[sid.Mspid] = true
+	}
+	for _, org := range op.orgs {
+		if !orgs[org] {
+			return fmt.Errorf("missing endorsement of %s", org)
+		}
+	}
+	if len(signatureSet) < op.count {
+		return fmt.Errorf("%d endorsements, %d required", len(signatureSet), op.count)
+	}
+	return nil
+}
+
+type mockSupport struct {
+	peers     []Peer
+	responses map[string]*pb.ProposalResponse
+	policy    policies.Policy
+	rejection cb.Status
+	sequence  uint64
+
+	asked     []string
+	broadcast []*cb.Envelope
+}
+
+func (ms *mockSupport) PeersOfChannel(channelID string) []Peer {
+	return ms.peers
+}
+
+func (ms *mockSupport) Endorse(ctx context.Context, endpoint string, signedProp *pb.SignedProposal) (*pb.ProposalResponse, error) {
+	ms.asked = append(ms.asked, endpoint)
+	resp, ok := ms.responses[endpoint]
+	if !ok {
+		return nil, fmt.Errorf("connection refused")
+	}
+	return resp, nil
+}
+
+func (ms *mockSupport) EndorsementPolicy(channelID, chaincodeName string) (policies.Policy, error) {
+	if chaincodeName != "mycc" {
+		return nil, fmt.Errorf("chaincode %s is not instantiated on channel %s", chaincodeName, channelID)
+	}
+	return ms.policy, nil
+}
+
+func (ms *mockSupport) Broadcast(ctx context.Context, channelID string, env *cb.Envelope) error {
+	if ms.rejection != cb.Status_UNKNOWN {
+		return fmt.Errorf("rejected with status %s", ms.rejection)
+	}
+	ms.broadcast = append(ms.broadcast, env)
+	return nil
+}
+
+func (ms *mockSupport) CommitStatus(ctx context.Context, channelID, txID string) (uint64, pb.TxValidationCode, error) {
+	return 5, pb.TxValidationCode_VALID, nil
+}
+
+func (ms *mockSupport) ConfigSequence(channelID string) uint64 {
+	return ms.sequence
+}
+
+// mockStream serves the requests queued, and io.EOF once they are exhausted
+type mockStream struct {
+	grpc.ServerStream
+	requests  []*pb.SubmitRequest
+	responses []*pb.SubmitResponse
+}
+
+func (ms *mockStream) Context() context.Context {
+	return context.Background()
+}
+
+func (ms *mockStream) Recv() (*pb.SubmitRequest, error) {
+	if len(ms.requests) == 0 {
+		return nil, io.EOF
+	}
+	req := ms.requests[0]
+	ms.requests = ms.requests[1:]
+	return req, nil
+}
+
+func (ms *mockStream) Send(resp *pb.SubmitResponse) error {
+	ms.responses = append(ms.responses, resp)
+	return nil
+}
+
+func newProposal(t *testing.T, ccName string) (*pb.SignedProposal, string) {
+	spec := &pb.ChaincodeInvocationSpec{ChaincodeSpec: &pb.ChaincodeSpec{
+		ChaincodeId: &pb.ChaincodeID{Name: ccName},
+		Input:       &pb.ChaincodeInput{Args: [][]byte{[]byte("invoke")}},
+	}}
+	creator := utils.MarshalOrPanic(&msp.SerializedIdentity{Mspid: "Org1MSP", IdBytes: []byte("client")})
+	prop, txID, err := utils.CreateChaincodeProposal(cb.HeaderType_ENDORSER_TRANSACTION, "mychannel", spec, creator)
+	assert.NoError(t, err)
+	return &pb.SignedProposal{ProposalBytes: utils.MarshalOrPanic(prop), Signature: []byte("signature")}, txID
+}
+
+func proposalRequest(signedProp *pb.SignedProposal) *pb.SubmitRequest {
+	return &pb.SubmitRequest{Type: &pb.SubmitRequest_Proposal{Proposal: signedProp}}
+}
+
+func signatureRequest(signature []byte) *pb.SubmitRequest {
+	return &pb.SubmitRequest{Type: &pb.SubmitRequest_Signature{Signature: signature}}
+}
+
+func TestSubmit(t *testing.T) {
+	payload := []byte("proposal response payload")
+	support := &mockSupport{
+		peers: []Peer{
+			{Endpoint: "peer1.org1:7051", MSPID: "Org1MSP"},
+			{Endpoint: "peer0.org2:7051", MSPID: "Org2MSP"},
+			{Endpoint: "peer1.org2:7051", MSPID: "Org2MSP"},
+			{Endpoint: "peer0.org3:7051", MSPID: "Org3MSP"},
+		},
+		responses: map[string]*pb.ProposalResponse{
+			"peer1.org1:7051": endorsedResponse("Org1MSP", "peer1.org1:7051", payload),
+			"peer1.org2:7051": endorsedResponse("Org2MSP", "peer1.org2:7051", payload),
+			"peer0.org3:7051": endorsedResponse("Org3MSP", "peer0.org3:7051", payload),
+		},
+		policy: &orgsPolicy{orgs: []string{"Org1MSP", "Org2MSP"}},
+	}
+	endorser := &mockEndorser{resp: endorsedResponse("Org1MSP", "peer0.org1:7051", payload)}
+	gw := NewServer("peer0.org1:7051", endorser, support, time.Second)
+
+	signedProp, txID := newProposal(t, "mycc")
+	stream := &mockStream{requests: []*pb.SubmitRequest{proposalRequest(signedProp), signatureRequest([]byte("tx signature"))}}
+	assert.NoError(t, gw.Submit(stream))
+
+	// The peers of Org2 are asked until one of them endorses, and those of Org1 are not
+	assert.Equal(t, []string{"peer0.org2:7051", "peer1.org2:7051"}, support.asked)
+
+	if !assert.Len(t, stream.responses, 3) {
+		return
+	}
+	prepared := stream.responses[0].GetPrepared()
+	assert.Equal(t, txID, prepared.TxId)
+	assert.Equal(t, []byte("result"), prepared.Response.Payload)
+	assert.Equal(t, []string{"peer0.org1:7051", "peer1.org2:7051"}, prepared.Endorsers)
+
+	tx, err := utils.GetTransaction(utils.UnmarshalPayloadOrPanic(prepared.Payload).Data)
+	assert.NoError(t, err)
+	ccap, err := utils.GetChaincodeActionPayload(tx.Actions[0].Payload)
+	assert.NoError(t, err)
+	assert.Equal(t, payload, ccap.Action.ProposalResponsePayload)
+	assert.Len(t, ccap.Action.Endorsements, 2)
+
+	assert.Equal(t, []*cb.Envelope{{Payload: prepared.Payload, Signature: []byte("tx signature")}}, support.broadcast)
+	assert.Equal(t, &pb.TransactionStatus{Stage: pb.TransactionStatus_SUBMITTED, TxId: txID}, stream.responses[1].GetStatus())
+	assert.Equal(t, &pb.TransactionStatus{
+		Stage:          pb.TransactionStatus_COMMITTED,
+		TxId:           txID,
+		BlockNumber:    5,
+		ValidationCode: pb.TxValidationCode_VALID,
+	}, stream.responses[2].GetStatus())
+}
+
+func TestSubmitEndorsementRounds(t *testing.T) {
+	payload := []byte("proposal response payload")
+	support := &mockSupport{
+		peers: []Peer{
+			{Endpoint: "peer1.org1:7051", MSPID: "Org1MSP"},
+			{Endpoint: "peer0.org2:7051", MSPID: "Org2MSP"},
+			{Endpoint: "peer1.org2:7051", MSPID: "Org2MSP"},
+		},
+		responses: map[string]*pb.ProposalResponse{
+			"peer1.org1:7051": endorsedResponse("Org1MSP", "peer1.org1:7051", payload),
+			// Simulated against a different state
+			"peer0.org2:7051": endorsedResponse("Org2MSP", "peer0.org2:7051", []byte("other payload")),
+			"peer1.org2:7051": endorsedResponse("Org2MSP", "peer1.org2:7051", payload),
+		},
+		policy: &orgsPolicy{orgs: []string{"Org2MSP"}, count: 3},
+	}
+	endorser := &mockEndorser{resp: endorsedResponse("Org1MSP", "peer0.org1:7051", payload)}
+	gw := NewServer("peer0.org1:7051", endorser, support, time.Second)
+
+	signedProp, _ := newProposal(t, "mycc")
+	stream := &mockStream{requests: []*pb.SubmitRequest{proposalRequest(signedProp)}}
+	assert.NoError(t, gw.Submit(stream), "The client may abandon the transaction")
+	assert.Empty(t, support.broadcast)
+
+	// The second round asks the peers of the organizations which already endorsed
+	assert.Equal(t, []string{"peer0.org2:7051", "peer1.org2:7051", "peer1.org1:7051"}, support.asked)
+	if assert.Len(t, stream.responses, 1) {
+		assert.Equal(t, []string{"peer0.org1:7051", "peer1.org2:7051", "peer1.org1:7051"}, stream.responses[0].GetPrepared().Endorsers)
+	}
+}
+
+func TestSubmitEndorsementPlan(t *testing.T) {
+	payload := []byte("proposal response payload")
+	support := &mockSupport{
+		peers: []Peer{
+			{Endpoint: "peer0.org2:7051", MSPID: "Org2MSP"},
+			{Endpoint: "peer1.org2:7051", MSPID: "Org2MSP"},
+			{Endpoint: "peer0.org3:7051", MSPID: "Org3MSP"},
+		},
+		responses: map[string]*pb.ProposalResponse{
+			"peer1.org2:7051": endorsedResponse("Org2MSP", "peer1.org2:7051", payload),
+			"peer0.org3:7051": endorsedResponse("Org3MSP", "peer0.org3:7051", payload),
+		},
+		policy: &orgsPolicy{orgs: []string{"Org1MSP", "Org2MSP", "Org3MSP"}},
+	}
+	endorser := &mockEndorser{resp: endorsedResponse("Org1MSP", "peer0.org1:7051", payload)}
+	gw := NewServer("peer0.org1:7051", endorser, support, time.Second)
+	signedProp, _ := newProposal(t, "mycc")
+
+	prepare := func() []string {
+		support.asked = nil
+		prepared, err := gw.Prepare(context.Background(), signedProp)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"peer0.org1:7051", "peer1.org2:7051", "peer0.org3:7051"}, prepared.Endorsers)
+		return support.asked
+	}
+	searched := []string{"peer0.org2:7051", "peer1.org2:7051", "peer0.org3:7051"}
+	planned := []string{"peer1.org2:7051", "peer0.org3:7051"}
+
+	assert.Equal(t, searched, prepare())
+	assert.Equal(t, planned, prepare(), "The peers of the plan should be asked first")
+
+	// The plan is computed again when the config of the channel changes
+	support.sequence++
+	assert.Equal(t, searched, prepare())
+	assert.Equal(t, planned, prepare())
+
+	// or when the peers of the channel change
+	support.peers = append(support.peers, Peer{Endpoint: "peer1.org3:7051", MSPID: "Org3MSP"})
+	assert.Equal(t, searched, prepare())
+	assert.Equal(t, planned, prepare())
+
+	// A peer of the plan failing to endorse is replaced
+	support.responses["peer1.org3:7051"] = support.responses["peer0.org3:7051"]
+	delete(support.responses, "peer0.org3:7051")
+	support.asked = nil
+	prepared, err := gw.Prepare(context.Background(), signedProp)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"peer0.org1:7051", "peer1.org2:7051", "peer1.org3:7051"}, prepared.Endorsers)
+	assert.Equal(t, []string{"peer1.org2:7051", "peer0.org3:7051", "peer1.org3:7051"}, support.asked)
+	support.asked = nil
+	_, err = gw.Prepare(context.Background(), signedProp)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"peer1.org2:7051", "peer1.org3:7051"}, support.asked)
+
+	// and the plan is discarded when the policy cannot be satisfied
+	support.responses = map[string]*pb.ProposalResponse{}
+	_, err = gw.Prepare(context.Background(), signedProp)
+	assert.Error(t, err)
+	assert.Nil(t, gw.(*server).plans.get("mychannel", "mycc", support.sequence, support.peers))
+}
+
+func TestSubmitFailures(t *testing.T) {
+	payload := []byte("proposal response payload")
+	newSupport := func() *mockSupport {
+		return &mockSupport{
+			peers: []Peer{{Endpoint: "peer0.org2:7051", MSPID: "Org2MSP"}},
+			responses: map[string]*pb.ProposalResponse{
+				"peer0.org2:7051": endorsedResponse("Org2MSP", "peer0.org2:7051", payload),
+			},
+			policy: &orgsPolicy{orgs: []string{"Org1MSP", "Org2MSP"}},
+		}
+	}
+	endorser := &mockEndorser{resp: endorsedResponse("Org1MSP", "peer0.org1:7051", payload)}
+	signedProp, _ := newProposal(t, "mycc")
+
+	t.Run("NotAProposal", func(t *testing.T) {
+		stream := &mockStream{requests: []*pb.SubmitRequest{signatureRequest([]byte("signature"))}}
+		assert.Error(t, NewServer("peer0.org1:7051", endorser, newSupport(), time.Second).Submit(stream))
+	})
+
+	t.Run("MalformedProposal", func(t *testing.T) {
+		stream := &mockStream{requests: []*pb.SubmitRequest{proposalRequest(&pb.SignedProposal{ProposalBytes: []byte("garbage")})}}
+		assert.Error(t, NewServer("peer0.org1:7051", endorser, newSupport(), time.Second).Submit(stream))
+	})
+
+	t.Run("UnknownChaincode", func(t *testing.T) {
+		signedProp, _ := newProposal(t, "othercc")
+		stream := &mockStream{requests: []*pb.SubmitRequest{proposalRequest(signedProp)}}
+		assert.Error(t, NewServer("peer0.org1:7051", endorser, newSupport(), time.Second).Submit(stream))
+	})
+
+	t.Run("LocalEndorsementFailure", func(t *testing.T) {
+		support := newSupport()
+		failing := &mockEndorser{resp: &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: "chaincode error"}}}
+		stream := &mockStream{requests: []*pb.SubmitRequest{proposalRequest(signedProp)}}
+		err := NewServer("peer0.org1:7051", failing, support, time.Second).Submit(stream)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "chaincode error")
+		assert.Empty(t, support.asked, "Remote peers should not be asked after a local failure")
+
+		stream = &mockStream{requests: []*pb.SubmitRequest{proposalRequest(signedProp)}}
+		err = NewServer("peer0.org1:7051", &mockEndorser{err: fmt.Errorf("access denied")}, support, time.Second).Submit(stream)
+		assert.Error(t, err)
+	})
+
+	t.Run("PolicyNotSatisfied", func(t *testing.T) {
+		support := newSupport()
+		support.policy = &orgsPolicy{orgs: []string{"Org1MSP", "Org2MSP", "Org3MSP"}}
+		stream := &mockStream{requests: []*pb.SubmitRequest{proposalRequest(signedProp)}}
+		assert.Error(t, NewServer("peer0.org1:7051", endorser, support, time.Second).Submit(stream))
+		assert.Empty(t, stream.responses)
+	})
+
+	t.Run("MissingSignature", func(t *testing.T) {
+		support := newSupport()
+		stream := &mockStream{requests: []*pb.SubmitRequest{proposalRequest(signedProp), proposalRequest(signedProp)}}
+		assert.Error(t, NewServer("peer0.org1:7051", endorser, support, time.Second).Submit(stream))
+		assert.Empty(t, support.broadcast)
+	})
+
+	t.Run("Rejected", func(t *testing.T) {
+		support := newSupport()
+		support.rejection = cb.Status_FORBIDDEN
+		stream := &mockStream{requests: []*pb.SubmitRequest{proposalRequest(signedProp), signatureRequest([]byte("signature"))}}
+		assert.Error(t, NewServer("peer0.org1:7051", endorser, support, time.Second).Submit(stream))
+		assert.Len(t, stream.responses, 1, "Only the prepared transaction should have been sent")
+	})
+}
+
+type mockCommitStream struct {
+	grpc.ServerStream
+	statuses []*pb.TransactionStatus
+}
+
+func (ms *mockCommitStream) Context() context.Context {
+	return context.Background()
+}
+
+func (ms *mockCommitStream) Send(status *pb.TransactionStatus) error {
+	ms.statuses = append(ms.statuses, status)
+	return nil
+}
+
+func TestPrepareCommit(t *testing.T) {
+	payload := []byte("proposal response payload")
+	support := &mockSupport{
+		peers: []Peer{{Endpoint: "peer0.org2:7051", MSPID: "Org2MSP"}},
+		responses: map[string]*pb.ProposalResponse{
+			"peer0.org2:7051": endorsedResponse("Org2MSP", "peer0.org2:7051", payload),
+		},
+		policy: &orgsPolicy{orgs: []string{"Org1MSP", "Org2MSP"}},
+	}
+	endorser := &mockEndorser{resp: endorsedResponse("Org1MSP", "peer0.org1:7051", payload)}
+	gw := NewServer("peer0.org1:7051", endorser, support, time.Second)
+
+	signedProp, txID := newProposal(t, "mycc")
+	prepared, err := gw.Prepare(context.Background(), signedProp)
+	assert.NoError(t, err)
+	assert.Equal(t, txID, prepared.TxId)
+	assert.Equal(t, []string{"peer0.org1:7051", "peer0.org2:7051"}, prepared.Endorsers)
+	assert.Empty(t, support.broadcast)
+
+	_, err = gw.Prepare(context.Background(), &pb.SignedProposal{ProposalBytes: []byte("garbage")})
+	assert.Error(t, err)
+
+	env := &cb.Envelope{Payload: prepared.Payload, Signature: []byte("tx signature")}
+	stream := &mockCommitStream{}
+	assert.NoError(t, gw.Commit(env, stream))
+	assert.Equal(t, []*cb.Envelope{env}, support.broadcast)
+	assert.Equal(t, []*pb.TransactionStatus{
+		{Stage: pb.TransactionStatus_SUBMITTED, TxId: txID},
+		{Stage: pb.TransactionStatus_COMMITTED, TxId: txID, BlockNumber: 5, ValidationCode: pb.TxValidationCode_VALID},
+	}, stream.statuses)
+
+	configTx := &cb.Envelope{Payload: utils.MarshalOrPanic(&cb.Payload{Header: &cb.Header{
+		ChannelHeader: utils.MarshalOrPanic(&cb.ChannelHeader{Type: int32(cb.HeaderType_CONFIG_UPDATE), ChannelId: "mychannel"}),
+	}})}
+	stream = &mockCommitStream{}
+	assert.Error(t, gw.Commit(configTx, stream), "Only endorser transactions should be committed")
+	assert.Error(t, gw.Commit(&cb.Envelope{Payload: []byte("garbage")}, stream))
+	assert.Empty(t, stream.statuses)
+	assert.Len(t, support.broadcast, 1)
+}
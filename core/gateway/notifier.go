@@ -0,0 +1,126 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/hyperledger/fabric/core/ledger/util"
+	cb "github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+)
+
+// commit is the outcome of a transaction committed by the peer
+type commit struct {
+	blockNumber uint64
+	code        pb.TxValidationCode
+}
+
+// Notifier notifies the submissions waiting for the transactions committed by the peer. It is
+// set as the commit listener of the peer
+type Notifier struct {
+	lock    sync.Mutex
+	waiters map[string]map[string][]chan commit
+}
+
+// NewNotifier creates a Notifier
+func NewNotifier() *Notifier {
+	return &Notifier{waiters: make(map[string]map[string][]chan commit)}
+}
+
+// BlockCommitted notifies the submissions waiting for the transactions of a block
+func (n *Notifier) BlockCommitted(channelID string, block *cb.Block) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	waiters := n.waiters[channelID]
+	if len(waiters) == 0 || block.Data == nil {
+		return
+	}
+	var flags util.TxValidationFlags
+	if block.Metadata != nil && len(block.Metadata.Metadata) > int(cb.BlockMetadataIndex_TRANSACTIONS_FILTER) {
+		flags = util.TxValidationFlags(block.Metadata.Metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	}
+
+	for i, envBytes := range block.Data.Data {
+		txID, err := txIDOf(envBytes)
+		if err != nil {
+			continue
+		}
+		chans, ok := waiters[txID]
+		if !ok {
+			continue
+		}
+		c := commit{blockNumber: block.Header.Number, code: pb.TxValidationCode_VALID}
+		if i < len(flags) {
+			c.code = flags.Flag(i)
+		}
+		for _, ch := range chans {
+			ch <- c
+		}
+		// A later transaction with the same ID is a duplicate, which the first one decides of
+		delete(waiters, txID)
+	}
+	if len(waiters) == 0 {
+		delete(n.waiters, channelID)
+	}
+}
+
+// wait registers for the commit of a transaction, returning the channel on which it is notified
+// and the function unregistering it
+func (n *Notifier) wait(channelID, txID string) (<-chan commit, func()) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	ch := make(chan commit, 1)
+	if n.waiters[channelID] == nil {
+		n.waiters[channelID] = make(map[string][]chan commit)
+	}
+	n.waiters[channelID][txID] = append(n.waiters[channelID][txID], ch)
+
+	return ch, func() {
+		n.lock.Lock()
+		defer n.lock.Unlock()
+
+		chans := n.waiters[channelID][txID]
+		for i := range chans {
+			if chans[i] == ch {
+				chans = append(chans[:i], chans[i+1:]...)
+				break
+			}
+		}
+		if len(chans) == 0 {
+			delete(n.waiters[channelID], txID)
+		} else {
+			n.waiters[channelID][txID] = chans
+		}
+		if len(n.waiters[channelID]) == 0 {
+			delete(n.waiters, channelID)
+		}
+	}
+}
+
+func txIDOf(envBytes []byte) (string, error) {
+	env, err := utils.GetEnvelopeFromBlock(envBytes)
+	if err != nil {
+		return "", err
+	}
+	payload, err := utils.GetPayload(env)
+	if err != nil {
+		return "", err
+	}
+	if payload.Header == nil {
+		return "", fmt.Errorf("missing header")
+	}
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return "", err
+	}
+	return chdr.TxId, nil
+}
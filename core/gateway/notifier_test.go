@@ -0,0 +1,78 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/core/ledger/util"
+	cb "github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func committedBlock(number uint64, txIDs ...string) *cb.Block {
+	block := cb.NewBlock(number, nil)
+	flags := util.NewTxValidationFlags(len(txIDs))
+	for i, txID := range txIDs {
+		payload := &cb.Payload{Header: &cb.Header{ChannelHeader: utils.MarshalOrPanic(&cb.ChannelHeader{TxId: txID})}}
+		block.Data.Data = append(block.Data.Data, utils.MarshalOrPanic(&cb.Envelope{Payload: utils.MarshalOrPanic(payload)}))
+		if i > 0 {
+			flags.SetFlag(i, pb.TxValidationCode_MVCC_READ_CONFLICT)
+		}
+	}
+	block.Metadata.Metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER] = flags
+	return block
+}
+
+func TestNotifier(t *testing.T) {
+	n := NewNotifier()
+
+	committed1, cancel1 := n.wait("mychannel", "tx1")
+	defer cancel1()
+	committed2, cancel2 := n.wait("mychannel", "tx2")
+	defer cancel2()
+	otherChannel, cancelOther := n.wait("otherchannel", "tx1")
+	defer cancelOther()
+
+	block := committedBlock(3, "tx1", "tx2")
+	// Transactions which cannot be parsed are skipped
+	block.Data.Data = append([][]byte{[]byte("garbage")}, block.Data.Data...)
+	block.Metadata.Metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER] = append([]byte{0}, block.Metadata.Metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER]...)
+	n.BlockCommitted("mychannel", block)
+
+	assert.Equal(t, commit{blockNumber: 3, code: pb.TxValidationCode_VALID}, <-committed1)
+	assert.Equal(t, commit{blockNumber: 3, code: pb.TxValidationCode_MVCC_READ_CONFLICT}, <-committed2)
+	select {
+	case <-otherChannel:
+		t.Fatal("The transactions of another channel should not be notified")
+	default:
+	}
+	assert.Len(t, n.waiters["mychannel"], 0, "Notified transactions should be unregistered")
+
+	// Only the first of the transactions with the same ID is notified
+	dup, cancelDup := n.wait("mychannel", "tx1")
+	n.BlockCommitted("mychannel", committedBlock(4, "tx3", "tx1", "tx1"))
+	assert.Equal(t, commit{blockNumber: 4, code: pb.TxValidationCode_MVCC_READ_CONFLICT}, <-dup)
+	cancelDup()
+
+	cancelOther()
+	assert.Empty(t, n.waiters, "Cancelled waits should be unregistered")
+}
+
+func TestNotifierCancel(t *testing.T) {
+	n := NewNotifier()
+	_, cancel1 := n.wait("mychannel", "tx1")
+	committed2, cancel2 := n.wait("mychannel", "tx1")
+	defer cancel2()
+	cancel1()
+
+	n.BlockCommitted("mychannel", committedBlock(1, "tx1"))
+	assert.Equal(t, commit{blockNumber: 1, code: pb.TxValidationCode_VALID}, <-committed2)
+	assert.Empty(t, n.waiters)
+}
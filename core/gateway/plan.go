@@ -0,0 +1,86 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+type planKey struct {
+	channelID string
+	chaincode string
+}
+
+// endorsementPlan is the layout of the remote peers whose endorsements, along with the one of
+// the local peer, last satisfied the endorsement policy of a chaincode. It holds as long as
+// neither the config of the channel nor the peers of the channel change
+type endorsementPlan struct {
+	configSequence uint64
+	membership     string
+	peers          []Peer
+}
+
+// planCache caches the endorsement plans by channel and chaincode, so that the gateway asks the
+// peers of the plan first rather than searching again for the peers satisfying the endorsement
+// policy for every transaction. The endorsements are still evaluated against the policy, so a
+// stale plan, such as after an upgrade of the chaincode, only costs the endorsements it misses
+type planCache struct {
+	lock  sync.Mutex
+	plans map[planKey]*endorsementPlan
+}
+
+func newPlanCache() *planCache {
+	return &planCache{plans: make(map[planKey]*endorsementPlan)}
+}
+
+// get returns the peers of the plan of the chaincode, or nil if there is none or if the config
+// sequence or the peers of the channel changed since the plan was computed
+func (pc *planCache) get(channelID, chaincode string, configSequence uint64, peers []Peer) []Peer {
+	pc.lock.Lock()
+	defer pc.lock.Unlock()
+	key := planKey{channelID: channelID, chaincode: chaincode}
+	plan, ok := pc.plans[key]
+	if !ok {
+		return nil
+	}
+	if plan.configSequence != configSequence || plan.membership != membership(peers) {
+		logger.Debugf("[channel: %s] Discarding the endorsement plan of chaincode %s computed for another config or other peers", channelID, chaincode)
+		delete(pc.plans, key)
+		return nil
+	}
+	return plan.peers
+}
+
+// put records the peers of the plan of the chaincode
+func (pc *planCache) put(channelID, chaincode string, configSequence uint64, peers []Peer, planPeers []Peer) {
+	pc.lock.Lock()
+	defer pc.lock.Unlock()
+	pc.plans[planKey{channelID: channelID, chaincode: chaincode}] = &endorsementPlan{
+		configSequence: configSequence,
+		membership:     membership(peers),
+		peers:          planPeers,
+	}
+}
+
+// invalidate discards the plan of the chaincode
+func (pc *planCache) invalidate(channelID, chaincode string) {
+	pc.lock.Lock()
+	defer pc.lock.Unlock()
+	delete(pc.plans, planKey{channelID: channelID, chaincode: chaincode})
+}
+
+// membership returns a fingerprint of the peers of a channel, independent of their order
+func membership(peers []Peer) string {
+	members := make([]string, len(peers))
+	for i, p := range peers {
+		members[i] = p.MSPID + "/" + p.Endpoint
+	}
+	sort.Strings(members)
+	return strings.Join(members, ",")
+}
@@ -0,0 +1,189 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/core/comm"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/peer"
+	gcommon "github.com/hyperledger/fabric/gossip/common"
+	"github.com/hyperledger/fabric/gossip/service"
+	mspmgmt "github.com/hyperledger/fabric/msp/mgmt"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+type peerSupport struct {
+	notifier       *Notifier
+	peerDialOpts   func() []grpc.DialOption
+	ordererBreaker *comm.EndpointBreaker
+}
+
+// NewSupport creates the Support of the gateway backed by the services of the peer. The remote
+// peers are dialed with the options returned by peerDialOpts, the commits are notified by
+// notifier, which must be set as the commit listener of the peer, and the orderers which keep
+// failing are taken out of rotation, and the others ranked by their health, by ordererBreaker
+func NewSupport(notifier *Notifier, peerDialOpts func() []grpc.DialOption, ordererBreaker *comm.EndpointBreaker) Support {
+	return &peerSupport{
+		notifier:       notifier,
+		peerDialOpts:   peerDialOpts,
+		ordererBreaker: ordererBreaker,
+	}
+}
+
+// PeersOfChannel returns the peers of the channel known to gossip, skipping those whose
+// organization is unknown yet
+func (ps *peerSupport) PeersOfChannel(channelID string) []Peer {
+	gossip := service.GetGossipService()
+	var peers []Peer
+	for _, member := range gossip.PeersOfChannel(gcommon.ChainID(channelID)) {
+		org := gossip.PeerOrg(member.PKIid)
+		if org == nil {
+			continue
+		}
+		peers = append(peers, Peer{Endpoint: member.Endpoint, MSPID: string(org)})
+	}
+	return peers
+}
+
+func (ps *peerSupport) Endorse(ctx context.Context, endpoint string, signedProp *pb.SignedProposal) (*pb.ProposalResponse, error) {
+	conn, err := grpc.DialContext(ctx, endpoint, append(ps.peerDialOpts(), grpc.WithBlock())...)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	return pb.NewEndorserClient(conn).ProcessProposal(ctx, signedProp)
+}
+
+func (ps *peerSupport) EndorsementPolicy(channelID, chaincodeName string) (policies.Policy, error) {
+	l := peer.GetLedger(channelID)
+	if l == nil {
+		return nil, fmt.Errorf("channel %s not found", channelID)
+	}
+	qe, err := l.NewQueryExecutor()
+	if err != nil {
+		return nil, err
+	}
+	defer qe.Done()
+
+	cdBytes, err := qe.GetState("lscc", chaincodeName)
+	if err != nil {
+		return nil, err
+	}
+	if cdBytes == nil {
+		return nil, fmt.Errorf("chaincode %s is not instantiated on channel %s", chaincodeName, channelID)
+	}
+	cd := &ccprovider.ChaincodeData{}
+	if err := proto.Unmarshal(cdBytes, cd); err != nil {
+		return nil, fmt.Errorf("invalid definition of chaincode %s: %s", chaincodeName, err)
+	}
+
+	policy, _, err := cauthdsl.NewPolicyProvider(mspmgmt.GetManagerForChain(channelID)).NewPolicy(cd.Policy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endorsement policy of chaincode %s: %s", chaincodeName, err)
+	}
+	return policy, nil
+}
+
+// Broadcast sends the transaction to the orderers of the channel in rotation in turn, the
+// healthiest first, until one of them accepts or rejects it. A transaction rejected by an orderer is not sent to the others,
+// unless the orderer is unavailable
+func (ps *peerSupport) Broadcast(ctx context.Context, channelID string, env *cb.Envelope) error {
+	addresses := peer.GetOrdererAddresses(channelID)
+	if len(addresses) == 0 {
+		return fmt.Errorf("no ordering service endpoint for channel %s", channelID)
+	}
+	var creds credentials.TransportCredentials
+	if comm.TLSEnabled() {
+		var err error
+		if creds, err = comm.GetCASupport().GetDeliverServiceCredentials(channelID); err != nil {
+			return err
+		}
+	}
+
+	var err error
+	for _, address := range ps.ordererBreaker.Ranked(addresses) {
+		var status cb.Status
+		start := time.Now()
+		status, err = broadcast(ctx, address, creds, env)
+		if err == nil && status == cb.Status_SERVICE_UNAVAILABLE {
+			err = fmt.Errorf("orderer %s is unavailable", address)
+		}
+		ps.ordererBreaker.Done(address, start, err)
+		if err != nil {
+			logger.Warningf("[channel: %s] Failed to send transaction to orderer %s: %s", channelID, address, err)
+			continue
+		}
+		if status != cb.Status_SUCCESS {
+			return fmt.Errorf("orderer %s rejected the transaction with status %s", address, status)
+		}
+		return nil
+	}
+	return err
+}
+
+func broadcast(ctx context.Context, address string, creds credentials.TransportCredentials, env *cb.Envelope) (cb.Status, error) {
+	conn, err := comm.NewClientConnectionWithAddress(address, true, creds != nil, creds)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	client, err := ab.NewAtomicBroadcastClient(conn).Broadcast(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer client.CloseSend()
+	if err := client.Send(env); err != nil {
+		return 0, err
+	}
+	resp, err := client.Recv()
+	if err != nil {
+		return 0, err
+	}
+	return resp.Status, nil
+}
+
+func (ps *peerSupport) ConfigSequence(channelID string) uint64 {
+	return peer.GetConfigSequence(channelID)
+}
+
+// CommitStatus checks the ledger after registering with the notifier, as the transaction may
+// have been committed before
+func (ps *peerSupport) CommitStatus(ctx context.Context, channelID, txID string) (uint64, pb.TxValidationCode, error) {
+	committed, cancel := ps.notifier.wait(channelID, txID)
+	defer cancel()
+
+	l := peer.GetLedger(channelID)
+	if l == nil {
+		return 0, 0, fmt.Errorf("channel %s not found", channelID)
+	}
+	if code, err := l.GetTxValidationCodeByTxID(txID); err == nil {
+		block, err := l.GetBlockByTxID(txID)
+		if err != nil {
+			return 0, 0, err
+		}
+		return block.Header.Number, code, nil
+	}
+
+	select {
+	case c := <-committed:
+		return c.blockNumber, c.code, nil
+	case <-ctx.Done():
+		return 0, 0, ctx.Err()
+	}
+}
@@ -0,0 +1,316 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package backup takes periodic backups of the ledger data of the peer. The commits
+// of the ledgers are held back while a backup is taken, so that the block stores and
+// the state, history and block index databases are consistent with each other in it.
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric/common/flogging"
+)
+
+var logger = flogging.MustGetLogger("ledgerbackup")
+
+// Supported values of Conf.Method
+const (
+	// MethodArchive writes a gzipped tar archive of the ledger data into Conf.Directory
+	MethodArchive = "archive"
+	// MethodCommand runs Conf.Command, e.g. to take a file system snapshot of the ledger data
+	MethodCommand = "command"
+)
+
+const (
+	archivePrefix = "ledgers-"
+	archiveSuffix = ".tar.gz"
+	// archiveTimeFormat sorts the archives in chronological order
+	archiveTimeFormat = "20060102T150405Z"
+)
+
+// Conf configures a Scheduler
+type Conf struct {
+	// Interval between two backups
+	Interval time.Duration
+	// Method is either MethodArchive or MethodCommand
+	Method string
+	// Directory receives the archives of MethodArchive
+	Directory string
+	// Command is run with sh by MethodCommand, with the path of the ledger data in
+	// the LEDGER_ROOT environment variable
+	Command string
+	// Retention is the number of most recent archives kept in Directory, 0 keeps them all
+	Retention int
+}
+
+// Quiescer holds back the commits of the ledgers while snapshot runs on the ledger data
+// under rootPath, and returns the error of snapshot
+type Quiescer func(snapshot func(rootPath string) error) error
+
+// Scheduler takes a backup of the ledger data every interval
+type Scheduler struct {
+	conf    Conf
+	quiesce Quiescer
+	now     func() time.Time
+
+	lock     sync.Mutex
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// New creates a Scheduler for the given configuration
+func New(conf Conf, quiesce Quiescer) (*Scheduler, error) {
+	if conf.Interval <= 0 {
+		return nil, fmt.Errorf("interval must be positive, got %s", conf.Interval)
+	}
+	switch conf.Method {
+	case MethodArchive:
+		if conf.Directory == "" {
+			return nil, fmt.Errorf("no directory to write the archives to")
+		}
+	case MethodCommand:
+		if conf.Command == "" {
+			return nil, fmt.Errorf("no command to run")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported backup method [%s], supported values are [%s] and [%s]",
+			conf.Method, MethodArchive, MethodCommand)
+	}
+	if conf.Retention < 0 {
+		return nil, fmt.Errorf("retention must not be negative, got %d", conf.Retention)
+	}
+	return &Scheduler{
+		conf:    conf,
+		quiesce: quiesce,
+		now:     time.Now,
+		stop:    make(chan struct{}),
+	}, nil
+}
+
+// Start takes a backup every interval until Stop is invoked
+func (s *Scheduler) Start() {
+	go func() {
+		ticker := time.NewTicker(s.conf.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := s.Backup(); err != nil {
+					logger.Errorf("Backup of the ledgers failed: %s", err)
+				}
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+	logger.Infof("Backing up the ledgers every %s with method %s", s.conf.Interval, s.conf.Method)
+}
+
+// Stop stops the periodic backups. A backup in progress runs to completion
+func (s *Scheduler) Stop() {
+	s.stopOnce.Do(func() { close(s.stop) })
+}
+
+// Backup takes a backup of the ledger data now
+func (s *Scheduler) Backup() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	start := s.now()
+	switch s.conf.Method {
+	case MethodCommand:
+		if err := s.quiesce(s.runCommand); err != nil {
+			return err
+		}
+	case MethodArchive:
+		if err := s.archive(start); err != nil {
+			return err
+		}
+		if err := s.prune(); err != nil {
+			return fmt.Errorf("error removing the old archives: %s", err)
+		}
+	}
+	logger.Infof("Backup of the ledgers completed in %s", s.now().Sub(start))
+	return nil
+}
+
+func (s *Scheduler) runCommand(rootPath string) error {
+	cmd := exec.Command("sh", "-c", s.conf.Command)
+	cmd.Env = append(os.Environ(), "LEDGER_ROOT="+rootPath)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("backup command failed: %s: %s", err, strings.TrimSpace(string(output)))
+	}
+	logger.Debugf("Backup command output: %s", output)
+	return nil
+}
+
+// stagedFile is a file of the ledger data captured while the commits were held back
+type stagedFile struct {
+	name string // the path relative to the ledger data root
+	path string // the path of the link or copy in the staging directory
+	size int64  // the size of the file when it was captured
+	mode os.FileMode
+}
+
+// archive captures the ledger data in a staging directory while the commits are held back, and
+// writes the archive once they resume, so that commits are only held back for as long as it takes
+// to link the files. The files of the stores are either immutable or only ever appended to, so
+// archiving them up to their captured size yields the data as of the capture
+func (s *Scheduler) archive(start time.Time) error {
+	if err := os.MkdirAll(s.conf.Directory, 0755); err != nil {
+		return err
+	}
+	staging, err := ioutil.TempDir(s.conf.Directory, ".staging-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(staging)
+
+	var files []*stagedFile
+	err = s.quiesce(func(rootPath string) error {
+		files, err = stage(rootPath, staging)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("error capturing the ledger data: %s", err)
+	}
+
+	name := filepath.Join(s.conf.Directory, archivePrefix+start.UTC().Format(archiveTimeFormat)+archiveSuffix)
+	tmpName := name + ".tmp"
+	if err := writeArchive(tmpName, files); err != nil {
+		os.Remove(tmpName)
+		return fmt.Errorf("error writing archive %s: %s", name, err)
+	}
+	if err := os.Rename(tmpName, name); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+	logger.Infof("Archived %d files of ledger data into %s", len(files), name)
+	return nil
+}
+
+// stage hard links the files under rootPath into staging, copying them when they cannot be linked
+func stage(rootPath, staging string) ([]*stagedFile, error) {
+	var files []*stagedFile
+	err := filepath.Walk(rootPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() || info.Name() == "LOCK" {
+			return nil
+		}
+		name, err := filepath.Rel(rootPath, path)
+		if err != nil {
+			return err
+		}
+		staged := filepath.Join(staging, fmt.Sprintf("%d", len(files)))
+		if err := os.Link(path, staged); err != nil {
+			if err := copyFile(path, staged, info.Size()); err != nil {
+				return err
+			}
+		}
+		files = append(files, &stagedFile{name: filepath.ToSlash(name), path: staged, size: info.Size(), mode: info.Mode()})
+		return nil
+	})
+	return files, err
+}
+
+func copyFile(src, dst string, size int64) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.CopyN(out, in, size); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+func writeArchive(name string, files []*stagedFile) error {
+	out, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+	for _, file := range files {
+		if err := archiveFile(tw, file); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	return out.Sync()
+}
+
+func archiveFile(tw *tar.Writer, file *stagedFile) error {
+	in, err := os.Open(file.path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	header := &tar.Header{
+		Name:     file.name,
+		Mode:     int64(file.mode.Perm()),
+		Size:     file.size,
+		Typeflag: tar.TypeReg,
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err = io.CopyN(tw, in, file.size)
+	return err
+}
+
+// prune removes the oldest archives beyond the retention
+func (s *Scheduler) prune() error {
+	if s.conf.Retention == 0 {
+		return nil
+	}
+	entries, err := ioutil.ReadDir(s.conf.Directory)
+	if err != nil {
+		return err
+	}
+	var archives []string
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), archivePrefix) && strings.HasSuffix(entry.Name(), archiveSuffix) {
+			archives = append(archives, entry.Name())
+		}
+	}
+	sort.Strings(archives)
+	for len(archives) > s.conf.Retention {
+		logger.Infof("Removing archive %s beyond the retention of %d archives", archives[0], s.conf.Retention)
+		if err := os.Remove(filepath.Join(s.conf.Directory, archives[0])); err != nil {
+			return err
+		}
+		archives = archives[1:]
+	}
+	return nil
+}
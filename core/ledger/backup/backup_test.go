@@ -0,0 +1,151 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readArchive(t *testing.T, name string) map[string]string {
+	f, err := os.Open(name)
+	require.NoError(t, err)
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	tr := tar.NewReader(gz)
+	contents := make(map[string]string)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return contents
+		}
+		require.NoError(t, err)
+		data, err := ioutil.ReadAll(tr)
+		require.NoError(t, err)
+		contents[header.Name] = string(data)
+	}
+}
+
+func TestNew(t *testing.T) {
+	for _, conf := range []Conf{
+		{Interval: 0, Method: MethodArchive, Directory: "/tmp"},
+		{Interval: time.Hour, Method: MethodArchive},
+		{Interval: time.Hour, Method: MethodCommand},
+		{Interval: time.Hour, Method: "rsync"},
+		{Interval: time.Hour, Method: MethodArchive, Directory: "/tmp", Retention: -1},
+	} {
+		_, err := New(conf, nil)
+		assert.Error(t, err, "Should have rejected %+v", conf)
+	}
+	_, err := New(Conf{Interval: time.Hour, Method: MethodCommand, Command: "true"}, nil)
+	assert.NoError(t, err)
+}
+
+func TestArchive(t *testing.T) {
+	root, err := ioutil.TempDir("", "ledgerbackup")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+	ledgers, dir := filepath.Join(root, "ledgersData"), filepath.Join(root, "backup")
+	require.NoError(t, os.MkdirAll(filepath.Join(ledgers, "chains", "mychannel"), 0755))
+	blockfile := filepath.Join(ledgers, "chains", "mychannel", "blockfile_000000")
+	require.NoError(t, ioutil.WriteFile(blockfile, []byte("block0"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(ledgers, "CURRENT"), []byte("MANIFEST-000001"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(ledgers, "LOCK"), nil, 0644))
+
+	quiesced := 0
+	quiesce := func(snapshot func(rootPath string) error) error {
+		quiesced++
+		if err := snapshot(ledgers); err != nil {
+			return err
+		}
+		// A commit appends to the block file once the commits resume
+		f, err := os.OpenFile(blockfile, os.O_APPEND|os.O_WRONLY, 0644)
+		require.NoError(t, err)
+		defer f.Close()
+		_, err = f.WriteString("block1")
+		return err
+	}
+
+	s, err := New(Conf{Interval: time.Hour, Method: MethodArchive, Directory: dir, Retention: 2}, quiesce)
+	require.NoError(t, err)
+	backupTime := time.Date(2017, 6, 1, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return backupTime }
+
+	require.NoError(t, s.Backup())
+	assert.Equal(t, 1, quiesced)
+	assert.Equal(t, map[string]string{
+		"chains/mychannel/blockfile_000000": "block0",
+		"CURRENT":                           "MANIFEST-000001",
+	}, readArchive(t, filepath.Join(dir, "ledgers-20170601T120000Z.tar.gz")),
+		"Should have archived the files as of the time the commits were held back")
+
+	for i := 0; i < 2; i++ {
+		backupTime = backupTime.Add(time.Hour)
+		require.NoError(t, s.Backup())
+	}
+	entries, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.Equal(t, []string{"ledgers-20170601T130000Z.tar.gz", "ledgers-20170601T140000Z.tar.gz"}, names,
+		"Should have kept the most recent archives only, and removed the staging directories")
+	assert.Equal(t, "block0block1block1", readArchive(t, filepath.Join(dir, names[1]))["chains/mychannel/blockfile_000000"])
+}
+
+func TestCommand(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ledgerbackup")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	out := filepath.Join(dir, "out")
+
+	quiesce := func(snapshot func(rootPath string) error) error {
+		return snapshot("/var/hyperledger/production/ledgersData")
+	}
+	s, err := New(Conf{Interval: time.Hour, Method: MethodCommand, Command: "printf %s \"$LEDGER_ROOT\" > " + out}, quiesce)
+	require.NoError(t, err)
+	require.NoError(t, s.Backup())
+	data, err := ioutil.ReadFile(out)
+	require.NoError(t, err)
+	assert.Equal(t, "/var/hyperledger/production/ledgersData", string(data))
+
+	s, err = New(Conf{Interval: time.Hour, Method: MethodCommand, Command: "echo snapshot failed; exit 1"}, quiesce)
+	require.NoError(t, err)
+	err = s.Backup()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "snapshot failed")
+}
+
+func TestStartStop(t *testing.T) {
+	backups := make(chan struct{}, 10)
+	quiesce := func(snapshot func(rootPath string) error) error {
+		backups <- struct{}{}
+		return nil
+	}
+	s, err := New(Conf{Interval: 10 * time.Millisecond, Method: MethodCommand, Command: "true"}, quiesce)
+	require.NoError(t, err)
+	s.Start()
+	defer s.Stop()
+	select {
+	case <-backups:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Should have taken a backup after the interval")
+	}
+	s.Stop()
+	s.Stop()
+}
@@ -0,0 +1,63 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cceventmgmt
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/hyperledger/fabric/core/common/ccprovider"
+)
+
+// ChaincodeDefinition captures the info about a chaincode deployed on a channel
+type ChaincodeDefinition struct {
+	Name    string
+	Version string
+	Hash    []byte
+}
+
+func (cdef *ChaincodeDefinition) String() string {
+	return fmt.Sprintf("Name=%s, Version=%s, Hash=%#v", cdef.Name, cdef.Version, cdef.Hash)
+}
+
+// ChaincodeLifecycleEventListener is implemented by the ledgers, in order to prepare
+// their state databases for the chaincodes deployed on their channel
+type ChaincodeLifecycleEventListener interface {
+	// HandleChaincodeDeploy is invoked with the artifacts of the state databases shipped
+	// in the package of a chaincode deployed on the channel, keyed by their path relative
+	// to META-INF/statedb, and returns the artifacts deployed
+	HandleChaincodeDeploy(chaincodeDefinition *ChaincodeDefinition, dbArtifacts map[string][]byte) ([]string, error)
+}
+
+// ChaincodeInfoProvider provides the info about the chaincodes installed on the peer
+type ChaincodeInfoProvider interface {
+	// RetrieveChaincodeArtifacts returns the artifacts of the state databases shipped in
+	// the package of the chaincode, or false if the chaincode is not installed
+	RetrieveChaincodeArtifacts(chaincodeDefinition *ChaincodeDefinition) (installed bool, dbArtifacts map[string][]byte, err error)
+}
+
+// chaincodeInfoProviderImpl retrieves the chaincodes installed in the file system of the peer
+type chaincodeInfoProviderImpl struct {
+}
+
+// RetrieveChaincodeArtifacts implements function in the interface ChaincodeInfoProvider
+func (p *chaincodeInfoProviderImpl) RetrieveChaincodeArtifacts(chaincodeDefinition *ChaincodeDefinition) (installed bool, dbArtifacts map[string][]byte, err error) {
+	ccpack, err := ccprovider.GetChaincodeFromFS(chaincodeDefinition.Name, chaincodeDefinition.Version)
+	if err != nil {
+		logger.Debugf("Chaincode [%s] is not installed: %s", chaincodeDefinition, err)
+		return false, nil, nil
+	}
+	if len(chaincodeDefinition.Hash) > 0 && !bytes.Equal(ccpack.GetId(), chaincodeDefinition.Hash) {
+		logger.Warningf("Chaincode [%s] installed with a different hash [%#v]", chaincodeDefinition, ccpack.GetId())
+		return false, nil, nil
+	}
+	dbArtifacts, err = ccprovider.ExtractStatedbArtifactsFromCCPackage(ccpack)
+	if err != nil {
+		return true, nil, err
+	}
+	return true, dbArtifacts, nil
+}
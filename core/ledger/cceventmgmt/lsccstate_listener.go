@@ -0,0 +1,41 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cceventmgmt
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
+)
+
+// LSCCNamespace is the namespace of the state of the lifecycle system chaincode, where the
+// chaincodes deployed on a channel are recorded
+const LSCCNamespace = "lscc"
+
+// KVLedgerLSCCStateListener listens for the state changes committed to the lscc namespace,
+// which record the chaincodes deployed or upgraded on a channel
+type KVLedgerLSCCStateListener struct {
+}
+
+// HandleStateUpdates implements function in interface ledger.StateListener
+func (listener *KVLedgerLSCCStateListener) HandleStateUpdates(ledgerID string, stateUpdates map[string][]byte) error {
+	var chaincodeDefinitions []*ChaincodeDefinition
+	for key, value := range stateUpdates {
+		if value == nil {
+			continue
+		}
+		cd := &ccprovider.ChaincodeData{}
+		if err := proto.Unmarshal(value, cd); err != nil {
+			logger.Warningf("Channel [%s]: Ignoring the lscc entry [%s] which is not a chaincode data: %s", ledgerID, key, err)
+			continue
+		}
+		chaincodeDefinitions = append(chaincodeDefinitions, &ChaincodeDefinition{Name: cd.Name, Version: cd.Version, Hash: cd.Id})
+	}
+	if len(chaincodeDefinitions) > 0 {
+		GetMgr().HandleChaincodeDeploy(ledgerID, chaincodeDefinitions)
+	}
+	return nil
+}
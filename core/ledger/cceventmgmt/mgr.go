@@ -0,0 +1,150 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cceventmgmt
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/hyperledger/fabric/common/flogging"
+	pb "github.com/hyperledger/fabric/protos/peer"
+)
+
+var logger = flogging.MustGetLogger("cceventmgmt")
+
+var mgr = newMgr(&chaincodeInfoProviderImpl{})
+
+// GetMgr returns the reference to singleton event manager
+func GetMgr() *Mgr {
+	return mgr
+}
+
+// Mgr encapsulates important interactions (such as the deployment of the indexes) between
+// the chaincode lifecycle and the ledgers. A chaincode deployed on a channel before being
+// installed on the peer is handled once it gets installed
+type Mgr struct {
+	lock         sync.Mutex
+	infoProvider ChaincodeInfoProvider
+	listeners    map[string]ChaincodeLifecycleEventListener
+	// deployments holds the status of the deployment of the chaincodes, by ledger and chaincode name
+	deployments map[string]map[string]*deployment
+}
+
+type deployment struct {
+	definition *ChaincodeDefinition
+	status     *pb.IndexDeployment
+}
+
+func newMgr(infoProvider ChaincodeInfoProvider) *Mgr {
+	return &Mgr{
+		infoProvider: infoProvider,
+		listeners:    make(map[string]ChaincodeLifecycleEventListener),
+		deployments:  make(map[string]map[string]*deployment),
+	}
+}
+
+// Register registers the listener of the chaincode lifecycle events of a ledger
+func (m *Mgr) Register(ledgerID string, l ChaincodeLifecycleEventListener) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.listeners[ledgerID] = l
+}
+
+// HandleChaincodeDeploy is invoked when chaincodes are deployed, or upgraded, on a channel.
+// The artifacts of the chaincodes installed on the peer are handed over to the ledger, and
+// the other chaincodes are remembered until they are installed
+func (m *Mgr) HandleChaincodeDeploy(ledgerID string, chaincodeDefinitions []*ChaincodeDefinition) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for _, chaincodeDefinition := range chaincodeDefinitions {
+		installed, dbArtifacts, err := m.infoProvider.RetrieveChaincodeArtifacts(chaincodeDefinition)
+		if !installed {
+			logger.Infof("Channel [%s]: Chaincode [%s] is not installed, its indexes will be deployed once it is installed", ledgerID, chaincodeDefinition)
+			m.setStatus(ledgerID, chaincodeDefinition, pb.IndexDeployment_PENDING_INSTALL, nil, nil)
+			continue
+		}
+		if err != nil {
+			logger.Errorf("Channel [%s]: Error retrieving the artifacts of chaincode [%s]: %s", ledgerID, chaincodeDefinition, err)
+			m.setStatus(ledgerID, chaincodeDefinition, pb.IndexDeployment_FAILED, nil, err)
+			continue
+		}
+		m.deploy(ledgerID, chaincodeDefinition, dbArtifacts)
+	}
+}
+
+// HandleChaincodeInstall is invoked when a chaincode is installed on the peer, in order to
+// deploy its artifacts on the channels where it was deployed before being installed
+func (m *Mgr) HandleChaincodeInstall(chaincodeDefinition *ChaincodeDefinition, dbArtifacts map[string][]byte) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for ledgerID, deployments := range m.deployments {
+		d, ok := deployments[chaincodeDefinition.Name]
+		if !ok || d.status.State != pb.IndexDeployment_PENDING_INSTALL || d.definition.Version != chaincodeDefinition.Version {
+			continue
+		}
+		if len(d.definition.Hash) > 0 && len(chaincodeDefinition.Hash) > 0 && string(d.definition.Hash) != string(chaincodeDefinition.Hash) {
+			logger.Warningf("Channel [%s]: Chaincode [%s] installed with a different hash than deployed [%#v]", ledgerID, chaincodeDefinition, d.definition.Hash)
+			continue
+		}
+		m.deploy(ledgerID, d.definition, dbArtifacts)
+	}
+}
+
+// Deployments returns the status of the deployment of the chaincodes deployed on the
+// channel since the peer started, sorted by chaincode name
+func (m *Mgr) Deployments(ledgerID string) []*pb.IndexDeployment {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	var names []string
+	for name := range m.deployments[ledgerID] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	statuses := make([]*pb.IndexDeployment, len(names))
+	for i, name := range names {
+		statuses[i] = m.deployments[ledgerID][name].status
+	}
+	return statuses
+}
+
+func (m *Mgr) deploy(ledgerID string, chaincodeDefinition *ChaincodeDefinition, dbArtifacts map[string][]byte) {
+	listener, ok := m.listeners[ledgerID]
+	if !ok {
+		logger.Warningf("Channel [%s]: No ledger registered to deploy chaincode [%s]", ledgerID, chaincodeDefinition)
+		return
+	}
+	deployed, err := listener.HandleChaincodeDeploy(chaincodeDefinition, dbArtifacts)
+	if err != nil {
+		logger.Errorf("Channel [%s]: Error deploying the indexes of chaincode [%s]: %s", ledgerID, chaincodeDefinition, err)
+		m.setStatus(ledgerID, chaincodeDefinition, pb.IndexDeployment_FAILED, deployed, err)
+		return
+	}
+	logger.Infof("Channel [%s]: Deployed the indexes %v of chaincode [%s]", ledgerID, deployed, chaincodeDefinition)
+	m.setStatus(ledgerID, chaincodeDefinition, pb.IndexDeployment_DEPLOYED, deployed, nil)
+}
+
+func (m *Mgr) setStatus(ledgerID string, chaincodeDefinition *ChaincodeDefinition, state pb.IndexDeployment_State, indexes []string, err error) {
+	deployments, ok := m.deployments[ledgerID]
+	if !ok {
+		deployments = make(map[string]*deployment)
+		m.deployments[ledgerID] = deployments
+	}
+	now := time.Now()
+	status := &pb.IndexDeployment{
+		ChaincodeName:    chaincodeDefinition.Name,
+		ChaincodeVersion: chaincodeDefinition.Version,
+		State:            state,
+		Indexes:          indexes,
+		Timestamp:        &timestamp.Timestamp{Seconds: now.Unix(), Nanos: int32(now.Nanosecond())},
+	}
+	if err != nil {
+		status.Error = err.Error()
+	}
+	deployments[chaincodeDefinition.Name] = &deployment{definition: chaincodeDefinition, status: status}
+}
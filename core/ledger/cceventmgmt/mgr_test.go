@@ -0,0 +1,125 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cceventmgmt
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/fabric/core/common/ccprovider"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCCEventMgmt(t *testing.T) {
+	cc1Def := &ChaincodeDefinition{Name: "cc1", Version: "v1", Hash: []byte("cc1")}
+	cc1DBArtifacts := map[string][]byte{"couchdb/indexes/index1.json": []byte("index1")}
+	cc2Def := &ChaincodeDefinition{Name: "cc2", Version: "v1", Hash: []byte("cc2")}
+	cc2DBArtifacts := map[string][]byte{"couchdb/indexes/index2.json": []byte("index2")}
+	cc3Def := &ChaincodeDefinition{Name: "cc3", Version: "v1", Hash: []byte("cc3")}
+
+	infoProvider := newMockProvider()
+	infoProvider.setInstalled(cc1Def, cc1DBArtifacts)
+	infoProvider.setErr(cc3Def, fmt.Errorf("corrupted package"))
+	mgr := newMgr(infoProvider)
+
+	listener := &mockListener{}
+	mgr.Register("channel1", listener)
+
+	// cc1 is installed, cc2 is not, and the package of cc3 cannot be read
+	mgr.HandleChaincodeDeploy("channel1", []*ChaincodeDefinition{cc1Def, cc2Def, cc3Def})
+	assert.Equal(t, cc1Def, listener.deployedDef)
+	assert.Equal(t, cc1DBArtifacts, listener.deployedArtifacts)
+
+	deployments := mgr.Deployments("channel1")
+	assert.Len(t, deployments, 3)
+	assert.Equal(t, "cc1", deployments[0].ChaincodeName)
+	assert.Equal(t, pb.IndexDeployment_DEPLOYED, deployments[0].State)
+	assert.Equal(t, []string{"couchdb/indexes/index1.json"}, deployments[0].Indexes)
+	assert.Equal(t, pb.IndexDeployment_PENDING_INSTALL, deployments[1].State)
+	assert.Equal(t, pb.IndexDeployment_FAILED, deployments[2].State)
+	assert.Equal(t, "corrupted package", deployments[2].Error)
+
+	// the installation of another version of cc2 is not deployed
+	mgr.HandleChaincodeInstall(&ChaincodeDefinition{Name: "cc2", Version: "v2"}, cc2DBArtifacts)
+	assert.Equal(t, pb.IndexDeployment_PENDING_INSTALL, mgr.Deployments("channel1")[1].State)
+
+	// the installation of cc2 deploys its indexes on the channel
+	mgr.HandleChaincodeInstall(cc2Def, cc2DBArtifacts)
+	assert.Equal(t, cc2Def, listener.deployedDef)
+	assert.Equal(t, cc2DBArtifacts, listener.deployedArtifacts)
+	assert.Equal(t, pb.IndexDeployment_DEPLOYED, mgr.Deployments("channel1")[1].State)
+
+	// a failure of the ledger is recorded
+	listener.err = fmt.Errorf("couchdb unreachable")
+	mgr.HandleChaincodeDeploy("channel1", []*ChaincodeDefinition{cc1Def})
+	deployments = mgr.Deployments("channel1")
+	assert.Equal(t, pb.IndexDeployment_FAILED, deployments[0].State)
+	assert.Equal(t, "couchdb unreachable", deployments[0].Error)
+
+	assert.Empty(t, mgr.Deployments("channel2"))
+}
+
+func TestLSCCStateListener(t *testing.T) {
+	cc1Def := &ChaincodeDefinition{Name: "cc1", Version: "v1", Hash: []byte("cc1")}
+	infoProvider := newMockProvider()
+	infoProvider.setInstalled(cc1Def, map[string][]byte{"couchdb/indexes/index1.json": []byte("index1")})
+	listener := &mockListener{}
+	defer func(m *Mgr) { mgr = m }(mgr)
+	mgr = newMgr(infoProvider)
+	mgr.Register("channel1", listener)
+
+	stateListener := &KVLedgerLSCCStateListener{}
+	err := stateListener.HandleStateUpdates("channel1", map[string][]byte{
+		"cc1":      utils.MarshalOrPanic(&ccprovider.ChaincodeData{Name: "cc1", Version: "v1", Id: []byte("cc1")}),
+		"deleted":  nil,
+		"notadata": []byte("garbage"),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, cc1Def, listener.deployedDef)
+	assert.Len(t, GetMgr().Deployments("channel1"), 1)
+}
+
+type mockProvider struct {
+	installed map[string]map[string][]byte
+	errs      map[string]error
+}
+
+func newMockProvider() *mockProvider {
+	return &mockProvider{installed: make(map[string]map[string][]byte), errs: make(map[string]error)}
+}
+
+func (p *mockProvider) setInstalled(chaincodeDefinition *ChaincodeDefinition, dbArtifacts map[string][]byte) {
+	p.installed[chaincodeDefinition.String()] = dbArtifacts
+}
+
+func (p *mockProvider) setErr(chaincodeDefinition *ChaincodeDefinition, err error) {
+	p.installed[chaincodeDefinition.String()] = nil
+	p.errs[chaincodeDefinition.String()] = err
+}
+
+func (p *mockProvider) RetrieveChaincodeArtifacts(chaincodeDefinition *ChaincodeDefinition) (installed bool, dbArtifacts map[string][]byte, err error) {
+	dbArtifacts, installed = p.installed[chaincodeDefinition.String()]
+	return installed, dbArtifacts, p.errs[chaincodeDefinition.String()]
+}
+
+type mockListener struct {
+	deployedDef       *ChaincodeDefinition
+	deployedArtifacts map[string][]byte
+	err               error
+}
+
+func (l *mockListener) HandleChaincodeDeploy(chaincodeDefinition *ChaincodeDefinition, dbArtifacts map[string][]byte) ([]string, error) {
+	l.deployedDef = chaincodeDefinition
+	l.deployedArtifacts = dbArtifacts
+	var deployed []string
+	for name := range dbArtifacts {
+		deployed = append(deployed, name)
+	}
+	return deployed, l.err
+}
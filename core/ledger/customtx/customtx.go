@@ -0,0 +1,71 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package customtx holds the processors of the custom transactions, i.e. the transactions whose
+// header type is neither ENDORSER_TRANSACTION nor CONFIG, such as token or identity transactions.
+// The peer validates and commits a custom transaction with the processor registered for its header
+// type, and invalidates it with BAD_COMMON_HEADER when there is none or when the CustomTransactions
+// capability of the channel is not enabled. As every peer of a channel must reach the same result, the
+// capability may only be enabled once they all have the same processors registered.
+package customtx
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/peer"
+)
+
+// Processor validates and commits the custom transactions of a header type. Its methods must be
+// deterministic, as every peer of the channel runs them on the same transactions, and may be called
+// concurrently
+type Processor interface {
+	// Validate checks the well-formed transaction before its block is committed, as VSCC does for
+	// the endorser transactions. The ID of the transaction is checked for duplicates beforehand
+	Validate(channelID string, payload *common.Payload) peer.TxValidationCode
+
+	// SimulationResults returns the serialized read-write set, a rwset.TxReadWriteSet, that the
+	// valid transaction applies to the state of the channel. It is checked for read conflicts and
+	// committed like the results of an endorser transaction. nil results leave the state untouched
+	SimulationResults(payload *common.Payload) ([]byte, error)
+}
+
+var processors = struct {
+	sync.RWMutex
+	byType map[common.HeaderType]Processor
+}{byType: make(map[common.HeaderType]Processor)}
+
+// Register registers the processor of the custom transactions of a header type. The processors
+// must be registered before the peer joins or initializes its channels. It panics if the header
+// type is ENDORSER_TRANSACTION, CONFIG or CONFIG_UPDATE, or if it already has a processor
+func Register(txType common.HeaderType, processor Processor) {
+	switch txType {
+	case common.HeaderType_ENDORSER_TRANSACTION, common.HeaderType_CONFIG, common.HeaderType_CONFIG_UPDATE:
+		panic(fmt.Sprintf("transactions of type %s cannot have a custom processor", txType))
+	}
+
+	processors.Lock()
+	defer processors.Unlock()
+	if _, exists := processors.byType[txType]; exists {
+		panic(fmt.Sprintf("transactions of type %s already have a processor", txType))
+	}
+	processors.byType[txType] = processor
+}
+
+// GetProcessor returns the processor of the custom transactions of a header type, nil if it has none
+func GetProcessor(txType common.HeaderType) Processor {
+	processors.RLock()
+	defer processors.RUnlock()
+	return processors.byType[txType]
+}
+
+// Unregister removes the processor of a header type, for the tests
+func Unregister(txType common.HeaderType) {
+	processors.Lock()
+	defer processors.Unlock()
+	delete(processors.byType, txType)
+}
@@ -0,0 +1,41 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package customtx
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockProcessor struct{}
+
+func (*mockProcessor) Validate(channelID string, payload *common.Payload) peer.TxValidationCode {
+	return peer.TxValidationCode_VALID
+}
+
+func (*mockProcessor) SimulationResults(payload *common.Payload) ([]byte, error) {
+	return nil, nil
+}
+
+func TestRegister(t *testing.T) {
+	const tokenTx = common.HeaderType(100)
+	defer Unregister(tokenTx)
+
+	assert.Nil(t, GetProcessor(tokenTx))
+	processor := &mockProcessor{}
+	Register(tokenTx, processor)
+	assert.Equal(t, processor, GetProcessor(tokenTx))
+	assert.Nil(t, GetProcessor(common.HeaderType(101)))
+
+	assert.Panics(t, func() { Register(tokenTx, &mockProcessor{}) }, "Should not replace a processor")
+	for _, txType := range []common.HeaderType{common.HeaderType_ENDORSER_TRANSACTION, common.HeaderType_CONFIG, common.HeaderType_CONFIG_UPDATE} {
+		assert.Panics(t, func() { Register(txType, &mockProcessor{}) }, "Should not register a processor of type %s", txType)
+	}
+}
@@ -34,6 +34,7 @@ type HistoryDBProvider interface {
 // HistoryDB - an interface that a history database should implement
 type HistoryDB interface {
 	NewHistoryQueryExecutor(blockStore blkstorage.BlockStore) (ledger.HistoryQueryExecutor, error)
+	NewHistoricalQueryExecutor(blockStore blkstorage.BlockStore, height uint64) (ledger.HistoricalQueryExecutor, error)
 	Commit(block *common.Block) error
 	GetLastSavepoint() (*version.Height, error)
 	ShouldRecover(lastAvailableBlock uint64) (bool, uint64, error)
@@ -17,6 +17,7 @@ limitations under the License.
 package historyleveldb
 
 import (
+	"github.com/hyperledger/fabric/common/crypto/keyring"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
@@ -38,19 +39,37 @@ var emptyValue = []byte{}
 // HistoryDBProvider implements interface HistoryDBProvider
 type HistoryDBProvider struct {
 	dbProvider *leveldbhelper.Provider
+	keyring    *keyring.Keyring
 }
 
 // NewHistoryDBProvider instantiates HistoryDBProvider
 func NewHistoryDBProvider() *HistoryDBProvider {
+	return NewHistoryDBProviderWithKeyring(nil)
+}
+
+// NewHistoryDBProviderWithKeyring instantiates HistoryDBProvider whose history records are keyed
+// by the tokens of the namespaces and keys under the keyring, if any, rather than by the namespaces
+// and keys themselves. The records written before are still found
+func NewHistoryDBProviderWithKeyring(kr *keyring.Keyring) *HistoryDBProvider {
 	dbPath := ledgerconfig.GetHistoryLevelDBPath()
 	logger.Debugf("constructing HistoryDBProvider dbPath=%s", dbPath)
-	dbProvider := leveldbhelper.NewProvider(&leveldbhelper.Conf{DBPath: dbPath})
-	return &HistoryDBProvider{dbProvider}
+	dbProvider := leveldbhelper.NewProvider(ledgerconfig.GetLevelDBConf(dbPath))
+	return &HistoryDBProvider{dbProvider, kr}
 }
 
 // GetDBHandle gets the handle to a named database
 func (provider *HistoryDBProvider) GetDBHandle(dbName string) (historydb.HistoryDB, error) {
-	return newHistoryDB(provider.dbProvider.GetDBHandle(dbName), dbName), nil
+	return newHistoryDB(provider.dbProvider.GetDBHandle(dbName), dbName, provider.keyring), nil
+}
+
+// Compact compacts the underlying db
+func (provider *HistoryDBProvider) Compact() error {
+	return provider.dbProvider.Compact()
+}
+
+// Drop deletes all the keys of the named db, which must not be in use
+func (provider *HistoryDBProvider) Drop(dbName string) error {
+	return provider.dbProvider.GetDBHandle(dbName).DeleteAll()
 }
 
 // Close closes the underlying db
@@ -60,13 +79,14 @@ func (provider *HistoryDBProvider) Close() {
 
 // historyDB implements HistoryDB interface
 type historyDB struct {
-	db     *leveldbhelper.DBHandle
-	dbName string
+	db      *leveldbhelper.DBHandle
+	dbName  string
+	keyring *keyring.Keyring
 }
 
 // newHistoryDB constructs an instance of HistoryDB
-func newHistoryDB(db *leveldbhelper.DBHandle, dbName string) *historyDB {
-	return &historyDB{db, dbName}
+func newHistoryDB(db *leveldbhelper.DBHandle, dbName string, kr *keyring.Keyring) *historyDB {
+	return &historyDB{db, dbName, kr}
 }
 
 // Open implements method in HistoryDB interface
@@ -151,7 +171,10 @@ func (historyDB *historyDB) Commit(block *common.Block) error {
 					writeKey := kvWrite.Key
 
 					//composite key for history records is in the form ns~key~blockNo~tranNo
-					compositeHistoryKey := historydb.ConstructCompositeHistoryKey(ns, writeKey, blockNo, tranNo)
+					compositeHistoryKey, err := historyDB.constructCompositeHistoryKey(ns, writeKey, blockNo, tranNo)
+					if err != nil {
+						return err
+					}
 
 					// No value is required, write an empty byte array (emptyValue) since Put() of nil is not allowed
 					dbBatch.Put(compositeHistoryKey, emptyValue)
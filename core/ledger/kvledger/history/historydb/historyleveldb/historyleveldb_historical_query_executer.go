@@ -0,0 +1,116 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package historyleveldb
+
+import (
+	"errors"
+	"fmt"
+
+	commonledger "github.com/hyperledger/fabric/common/ledger"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/util"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	"github.com/hyperledger/fabric/protos/ledger/queryresult"
+)
+
+// errNotSupportedAtHeight is returned by the queries which cannot be answered from the history database
+var errNotSupportedAtHeight = errors.New("range and rich queries are not supported at a past height of the ledger")
+
+// NewHistoricalQueryExecutor implements method in HistoryDB interface
+func (historyDB *historyDB) NewHistoricalQueryExecutor(blockStore blkstorage.BlockStore, height uint64) (ledger.HistoricalQueryExecutor, error) {
+	if !ledgerconfig.IsHistoryDBEnabled() {
+		return nil, errors.New("History tracking not enabled - historyDatabase is false")
+	}
+	savepoint, err := historyDB.GetLastSavepoint()
+	if err != nil {
+		return nil, err
+	}
+	if savepoint == nil || savepoint.BlockNum+1 < height {
+		return nil, fmt.Errorf("the history database of channel [%s] is below height %d", historyDB.dbName, height)
+	}
+	return &LevelHistoryDBHistoricalQueryExecutor{historyDB, blockStore, height}, nil
+}
+
+// LevelHistoryDBHistoricalQueryExecutor is a query executor reading the state as of a past height
+// of the ledger, from the latest modification of each key below the height in the LevelDB history DB
+type LevelHistoryDBHistoricalQueryExecutor struct {
+	historyDB  *historyDB
+	blockStore blkstorage.BlockStore
+	height     uint64
+}
+
+// GetState implements method in interface `ledger.QueryExecutor`
+func (q *LevelHistoryDBHistoricalQueryExecutor) GetState(namespace string, key string) ([]byte, error) {
+	compositePartialKeys, err := q.historyDB.constructPartialCompositeHistoryKeys(namespace, key)
+	if err != nil {
+		return nil, err
+	}
+	endSuffix := util.EncodeOrderPreservingVarUint64(q.height)
+	var latest *historyCursor
+	for _, compositeStartKey := range compositePartialKeys {
+		compositeEndKey := append(append([]byte{}, compositeStartKey...), endSuffix...)
+
+		cursor := &historyCursor{compositePartialKey: compositeStartKey, dbItr: q.historyDB.db.GetIterator(compositeStartKey, compositeEndKey)}
+		cursor.last()
+		cursor.dbItr.Release()
+		if cursor.valid && (latest == nil || latest.before(cursor)) {
+			latest = cursor
+		}
+	}
+	if latest == nil {
+		return nil, nil
+	}
+
+	tranEnvelope, err := q.blockStore.RetrieveTxByBlockNumTranNum(latest.blockNum, latest.tranNum)
+	if err != nil {
+		return nil, err
+	}
+	queryResult, err := getKeyModificationFromTran(tranEnvelope, namespace, key)
+	if err != nil {
+		return nil, err
+	}
+	modification := queryResult.(*queryresult.KeyModification)
+	if modification.IsDelete {
+		return nil, nil
+	}
+	return modification.Value, nil
+}
+
+// GetStateMultipleKeys implements method in interface `ledger.QueryExecutor`
+func (q *LevelHistoryDBHistoricalQueryExecutor) GetStateMultipleKeys(namespace string, keys []string) ([][]byte, error) {
+	values := make([][]byte, len(keys))
+	for i, key := range keys {
+		value, err := q.GetState(namespace, key)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+// GetStateRangeScanIterator implements method in interface `ledger.QueryExecutor`
+func (q *LevelHistoryDBHistoricalQueryExecutor) GetStateRangeScanIterator(namespace string, startKey string, endKey string) (commonledger.ResultsIterator, error) {
+	return nil, errNotSupportedAtHeight
+}
+
+// ExecuteQuery implements method in interface `ledger.QueryExecutor`
+func (q *LevelHistoryDBHistoricalQueryExecutor) ExecuteQuery(namespace, query string) (commonledger.ResultsIterator, error) {
+	return nil, errNotSupportedAtHeight
+}
+
+// GetHistoryForKey implements method in interface `ledger.HistoryQueryExecutor`. The history
+// is limited to the modifications below the height
+func (q *LevelHistoryDBHistoricalQueryExecutor) GetHistoryForKey(namespace string, key string) (commonledger.ResultsIterator, error) {
+	return q.historyDB.getHistoryForKey(namespace, key, util.EncodeOrderPreservingVarUint64(q.height), q.blockStore)
+}
+
+// Done implements method in interface `ledger.QueryExecutor`
+func (q *LevelHistoryDBHistoricalQueryExecutor) Done() {
+	// nothing to release, the iterators are released as the queries complete
+}
@@ -44,40 +44,94 @@ func (q *LevelHistoryDBQueryExecutor) GetHistoryForKey(namespace string, key str
 		return nil, errors.New("History tracking not enabled - historyDatabase is false")
 	}
 
-	var compositeStartKey []byte
-	var compositeEndKey []byte
-	compositeStartKey = historydb.ConstructPartialCompositeHistoryKey(namespace, key, false)
-	compositeEndKey = historydb.ConstructPartialCompositeHistoryKey(namespace, key, true)
-
-	// range scan to find any history records starting with namespace~key
-	dbItr := q.historyDB.db.GetIterator(compositeStartKey, compositeEndKey)
-	return newHistoryScanner(compositeStartKey, namespace, key, dbItr, q.blockStore), nil
+	return q.historyDB.getHistoryForKey(namespace, key, []byte{0xff}, q.blockStore)
 }
 
-//historyScanner implements ResultsIterator for iterating through history results
-type historyScanner struct {
-	compositePartialKey []byte //compositePartialKey includes namespace~key
-	namespace           string
-	key                 string
+// getHistoryForKey returns the scanner of the history records of namespace~key whose blocknum~trannum
+// suffix is below endSuffix
+func (historyDB *historyDB) getHistoryForKey(namespace string, key string, endSuffix []byte, blockStore blkstorage.BlockStore) (commonledger.ResultsIterator, error) {
+	compositePartialKeys, err := historyDB.constructPartialCompositeHistoryKeys(namespace, key)
+	if err != nil {
+		return nil, err
+	}
+	var cursors []*historyCursor
+	for _, compositeStartKey := range compositePartialKeys {
+		compositeEndKey := append(append([]byte{}, compositeStartKey...), endSuffix...)
+
+		// range scan to find any history records starting with namespace~key
+		dbItr := historyDB.db.GetIterator(compositeStartKey, compositeEndKey)
+		cursors = append(cursors, newHistoryCursor(compositeStartKey, dbItr))
+	}
+	return newHistoryScanner(namespace, key, cursors, blockStore), nil
+}
+
+// historyCursor iterates through the history records starting with a composite partial key
+type historyCursor struct {
+	compositePartialKey []byte //compositePartialKey includes namespace~key, or its token
 	dbItr               iterator.Iterator
-	blockStore          blkstorage.BlockStore
+	valid               bool
+	blockNum            uint64
+	tranNum             uint64
 }
 
-func newHistoryScanner(compositePartialKey []byte, namespace string, key string,
-	dbItr iterator.Iterator, blockStore blkstorage.BlockStore) *historyScanner {
-	return &historyScanner{compositePartialKey, namespace, key, dbItr, blockStore}
+func newHistoryCursor(compositePartialKey []byte, dbItr iterator.Iterator) *historyCursor {
+	cursor := &historyCursor{compositePartialKey: compositePartialKey, dbItr: dbItr}
+	cursor.next()
+	return cursor
 }
 
-func (scanner *historyScanner) Next() (commonledger.QueryResult, error) {
-	if !scanner.dbItr.Next() {
-		return nil, nil
+func (cursor *historyCursor) next() {
+	if cursor.valid = cursor.dbItr.Next(); cursor.valid {
+		cursor.parseKey()
 	}
-	historyKey := scanner.dbItr.Key() // history key is in the form namespace~key~blocknum~trannum
+}
+
+// last moves the cursor to the last history record, i.e. the latest one
+func (cursor *historyCursor) last() {
+	if cursor.valid = cursor.dbItr.Last(); cursor.valid {
+		cursor.parseKey()
+	}
+}
+
+func (cursor *historyCursor) parseKey() {
+	historyKey := cursor.dbItr.Key() // history key is in the form namespace~key~blocknum~trannum
 
 	// SplitCompositeKey(namespace~key~blocknum~trannum, namespace~key~) will return the blocknum~trannum in second position
-	_, blockNumTranNumBytes := historydb.SplitCompositeHistoryKey(historyKey, scanner.compositePartialKey)
-	blockNum, bytesConsumed := util.DecodeOrderPreservingVarUint64(blockNumTranNumBytes[0:])
-	tranNum, _ := util.DecodeOrderPreservingVarUint64(blockNumTranNumBytes[bytesConsumed:])
+	_, blockNumTranNumBytes := historydb.SplitCompositeHistoryKey(historyKey, cursor.compositePartialKey)
+	var bytesConsumed int
+	cursor.blockNum, bytesConsumed = util.DecodeOrderPreservingVarUint64(blockNumTranNumBytes[0:])
+	cursor.tranNum, _ = util.DecodeOrderPreservingVarUint64(blockNumTranNumBytes[bytesConsumed:])
+}
+
+func (cursor *historyCursor) before(other *historyCursor) bool {
+	return cursor.blockNum < other.blockNum || (cursor.blockNum == other.blockNum && cursor.tranNum < other.tranNum)
+}
+
+// historyScanner implements ResultsIterator for iterating through history results.
+// It merges the cursors of the partial keys of namespace~key by height
+type historyScanner struct {
+	namespace  string
+	key        string
+	cursors    []*historyCursor
+	blockStore blkstorage.BlockStore
+}
+
+func newHistoryScanner(namespace string, key string, cursors []*historyCursor, blockStore blkstorage.BlockStore) *historyScanner {
+	return &historyScanner{namespace, key, cursors, blockStore}
+}
+
+func (scanner *historyScanner) Next() (commonledger.QueryResult, error) {
+	var cursor *historyCursor
+	for _, c := range scanner.cursors {
+		if c.valid && (cursor == nil || c.before(cursor)) {
+			cursor = c
+		}
+	}
+	if cursor == nil {
+		return nil, nil
+	}
+	blockNum, tranNum := cursor.blockNum, cursor.tranNum
+	cursor.next()
 	logger.Debugf("Found history record for namespace:%s key:%s at blockNumTranNum %v:%v\n",
 		scanner.namespace, scanner.key, blockNum, tranNum)
 
@@ -98,7 +152,9 @@ func (scanner *historyScanner) Next() (commonledger.QueryResult, error) {
 }
 
 func (scanner *historyScanner) Close() {
-	scanner.dbItr.Release()
+	for _, cursor := range scanner.cursors {
+		cursor.dbItr.Release()
+	}
 }
 
 // getTxIDandKeyWriteValueFromTran inspects a transaction for writes to a given key
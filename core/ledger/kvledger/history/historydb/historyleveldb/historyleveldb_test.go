@@ -21,8 +21,12 @@ import (
 	"strconv"
 	"testing"
 
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/sw"
 	configtxtest "github.com/hyperledger/fabric/common/configtx/test"
+	"github.com/hyperledger/fabric/common/crypto/keyring"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/ledger/queryresult"
@@ -188,6 +192,65 @@ func TestHistory(t *testing.T) {
 	testutil.AssertEquals(t, count, 4)
 }
 
+func TestHistoryWithKeyring(t *testing.T) {
+
+	env := NewTestHistoryEnv(t)
+	defer env.cleanup()
+	provider := env.testBlockStorageEnv.provider
+	ledger1id := "ledger1"
+	store1, err := provider.OpenBlockStore(ledger1id)
+	testutil.AssertNoError(t, err, "Error upon provider.OpenBlockStore()")
+	defer store1.Shutdown()
+
+	csp, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	testutil.AssertNoError(t, err, "")
+	key1, _ := csp.KeyGen(&bccsp.AES256KeyGenOpts{Temporary: true})
+	key2, _ := csp.KeyGen(&bccsp.AES256KeyGenOpts{Temporary: true})
+	kr1, _ := keyring.New(csp, "key1", map[string]bccsp.Key{"key1": key1})
+	kr2, _ := keyring.New(csp, "key2", map[string]bccsp.Key{"key1": key1, "key2": key2})
+	plainHistoryDB := env.testHistoryDB.(*historyDB)
+
+	bg, gb := testutil.NewBlockGenerator(t, ledger1id, false)
+	testutil.AssertNoError(t, store1.AddBlock(gb), "")
+	testutil.AssertNoError(t, plainHistoryDB.Commit(gb), "")
+
+	// block1 is recorded in plaintext, block2 with the first key, and block3 after the rotation of the keys
+	for i, historyDB := range []*historyDB{
+		plainHistoryDB,
+		newHistoryDB(plainHistoryDB.db, plainHistoryDB.dbName, kr1),
+		newHistoryDB(plainHistoryDB.db, plainHistoryDB.dbName, kr2),
+	} {
+		simulator, _ := env.txmgr.NewTxSimulator()
+		simulator.SetState("ns1", "key7", []byte("value"+strconv.Itoa(i+1)))
+		simulator.Done()
+		simRes, _ := simulator.GetTxSimulationResults()
+		block := bg.NextBlock([][]byte{simRes})
+		testutil.AssertNoError(t, store1.AddBlock(block), "")
+		testutil.AssertNoError(t, historyDB.Commit(block), "")
+	}
+
+	countHistory := func(historyDB *historyDB) int {
+		qhistory, err := historyDB.NewHistoryQueryExecutor(store1)
+		testutil.AssertNoError(t, err, "Error upon NewHistoryQueryExecutor")
+		itr, err := qhistory.GetHistoryForKey("ns1", "key7")
+		testutil.AssertNoError(t, err, "Error upon GetHistoryForKey()")
+		defer itr.Close()
+		count := 0
+		for {
+			kmod, err := itr.Next()
+			testutil.AssertNoError(t, err, "")
+			if kmod == nil {
+				break
+			}
+			count++
+			testutil.AssertEquals(t, kmod.(*queryresult.KeyModification).Value, []byte("value"+strconv.Itoa(count)))
+		}
+		return count
+	}
+	testutil.AssertEquals(t, countHistory(newHistoryDB(plainHistoryDB.db, plainHistoryDB.dbName, kr2)), 3)
+	testutil.AssertEquals(t, countHistory(plainHistoryDB), 1)
+}
+
 func TestHistoryForInvalidTran(t *testing.T) {
 
 	env := NewTestHistoryEnv(t)
@@ -231,6 +294,76 @@ func TestHistoryForInvalidTran(t *testing.T) {
 	testutil.AssertNil(t, kmod)
 }
 
+// TestHistoricalQueries tests that the state as of a past height is read from the latest
+// modification of each key below the height
+func TestHistoricalQueries(t *testing.T) {
+
+	env := NewTestHistoryEnv(t)
+	defer env.cleanup()
+	provider := env.testBlockStorageEnv.provider
+	store1, err := provider.OpenBlockStore("ledger1")
+	testutil.AssertNoError(t, err, "Error upon provider.OpenBlockStore()")
+	defer store1.Shutdown()
+
+	bg, gb := testutil.NewBlockGenerator(t, "ledger1", false)
+	testutil.AssertNoError(t, store1.AddBlock(gb), "")
+	testutil.AssertNoError(t, env.testHistoryDB.Commit(gb), "")
+
+	commit := func(update func(simulator ledger.TxSimulator)) {
+		simulator, _ := env.txmgr.NewTxSimulator()
+		update(simulator)
+		simulator.Done()
+		simRes, _ := simulator.GetTxSimulationResults()
+		block := bg.NextBlock([][]byte{simRes})
+		testutil.AssertNoError(t, store1.AddBlock(block), "")
+		testutil.AssertNoError(t, env.testHistoryDB.Commit(block), "")
+	}
+	commit(func(simulator ledger.TxSimulator) {
+		simulator.SetState("ns1", "key7", []byte("value1"))
+		simulator.SetState("ns1", "key8", []byte("value8"))
+	})
+	commit(func(simulator ledger.TxSimulator) { simulator.SetState("ns1", "key7", []byte("value2")) })
+	commit(func(simulator ledger.TxSimulator) { simulator.DeleteState("ns1", "key7") })
+
+	for height, expected := range map[uint64][][]byte{
+		1: {nil, nil},
+		2: {[]byte("value1"), []byte("value8")},
+		3: {[]byte("value2"), []byte("value8")},
+		4: {nil, []byte("value8")},
+	} {
+		qe, err := env.testHistoryDB.NewHistoricalQueryExecutor(store1, height)
+		testutil.AssertNoError(t, err, "Error upon NewHistoricalQueryExecutor")
+		values, err := qe.GetStateMultipleKeys("ns1", []string{"key7", "key8"})
+		testutil.AssertNoError(t, err, "Error upon GetStateMultipleKeys()")
+		testutil.AssertEquals(t, values, expected)
+		qe.Done()
+	}
+
+	qe, err := env.testHistoryDB.NewHistoricalQueryExecutor(store1, 3)
+	testutil.AssertNoError(t, err, "Error upon NewHistoricalQueryExecutor")
+	itr, err := qe.GetHistoryForKey("ns1", "key7")
+	testutil.AssertNoError(t, err, "Error upon GetHistoryForKey()")
+	defer itr.Close()
+	var values []string
+	for {
+		kmod, err := itr.Next()
+		testutil.AssertNoError(t, err, "Error upon Next()")
+		if kmod == nil {
+			break
+		}
+		values = append(values, string(kmod.(*queryresult.KeyModification).Value))
+	}
+	testutil.AssertEquals(t, values, []string{"value1", "value2"})
+
+	_, err = qe.GetStateRangeScanIterator("ns1", "", "")
+	testutil.AssertError(t, err, "Expected an error for a range scan at a past height")
+	_, err = qe.ExecuteQuery("ns1", "{}")
+	testutil.AssertError(t, err, "Expected an error for a rich query at a past height")
+
+	_, err = env.testHistoryDB.NewHistoricalQueryExecutor(store1, 5)
+	testutil.AssertError(t, err, "Expected an error for a height over the one of the history database")
+}
+
 //TestSavepoint tests that save points get written after each block and get returned via GetBlockNumfromSavepoint
 func TestHistoryDisabled(t *testing.T) {
 
@@ -245,6 +378,9 @@ func TestHistoryDisabled(t *testing.T) {
 
 	_, err2 := qhistory.GetHistoryForKey("ns1", "key7")
 	testutil.AssertError(t, err2, "Error should have been returned for GetHistoryForKey() when history disabled")
+
+	_, err = env.testHistoryDB.NewHistoricalQueryExecutor(nil, 1)
+	testutil.AssertError(t, err, "Error should have been returned for NewHistoricalQueryExecutor() when history disabled")
 }
 
 //TestGenesisBlockNoError tests that Genesis blocks are ignored by history processing
@@ -0,0 +1,45 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package historyleveldb
+
+import (
+	"github.com/hyperledger/fabric/common/ledger/util"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/history/historydb"
+)
+
+// With a keyring, the history records are keyed by token~blocknum~trannum, where the token of
+// namespace~key is the same for all its records under a key of the keyring. The tokens are of
+// a fixed length, so that the records of a token are never scanned with those of another
+
+// constructCompositeHistoryKey builds the key of a history record, replacing namespace~key by its
+// token under the active key of the keyring if any
+func (historyDB *historyDB) constructCompositeHistoryKey(ns string, key string, blocknum uint64, trannum uint64) ([]byte, error) {
+	if historyDB.keyring == nil {
+		return historydb.ConstructCompositeHistoryKey(ns, key, blocknum, trannum), nil
+	}
+	token, err := historyDB.keyring.Token(historydb.ConstructPartialCompositeHistoryKey(ns, key, false))
+	if err != nil {
+		return nil, err
+	}
+	compositeKey := append(token, util.EncodeOrderPreservingVarUint64(blocknum)...)
+	return append(compositeKey, util.EncodeOrderPreservingVarUint64(trannum)...), nil
+}
+
+// constructPartialCompositeHistoryKeys builds the prefixes of the history records of namespace~key,
+// the tokens under each key of the keyring if any followed by namespace~key for the records written
+// before the keyring
+func (historyDB *historyDB) constructPartialCompositeHistoryKeys(ns string, key string) ([][]byte, error) {
+	partialKey := historydb.ConstructPartialCompositeHistoryKey(ns, key, false)
+	if historyDB.keyring == nil {
+		return [][]byte{partialKey}, nil
+	}
+	tokens, err := historyDB.keyring.Tokens(partialKey)
+	if err != nil {
+		return nil, err
+	}
+	return append(tokens, partialKey), nil
+}
@@ -19,16 +19,19 @@ package kvledger
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/hyperledger/fabric/common/flogging"
 	commonledger "github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/cceventmgmt"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/history/historydb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/txmgr"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/txmgr/lockbasedtxmgr"
 	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/peer"
 )
@@ -38,61 +41,84 @@ var logger = flogging.MustGetLogger("kvledger")
 // KVLedger provides an implementation of `ledger.PeerLedger`.
 // This implementation provides a key-value based data model
 type kvLedger struct {
-	ledgerID   string
-	blockStore blkstorage.BlockStore
-	txtmgmt    txmgr.TxMgr
-	historyDB  historydb.HistoryDB
+	ledgerID    string
+	blockStore  blkstorage.BlockStore
+	versionedDB statedb.VersionedDB
+	txtmgmt     txmgr.TxMgr
+	historyDB   historydb.HistoryDB
 }
 
 // NewKVLedger constructs new `KVLedger`
 func newKVLedger(ledgerID string, blockStore blkstorage.BlockStore,
-	versionedDB statedb.VersionedDB, historyDB historydb.HistoryDB) (*kvLedger, error) {
+	versionedDB statedb.VersionedDB, historyDB historydb.HistoryDB, stateListeners map[string]ledger.StateListener) (*kvLedger, error) {
 
 	logger.Debugf("Creating KVLedger ledgerID=%s: ", ledgerID)
 
 	//Initialize transaction manager using state database
 	var txmgmt txmgr.TxMgr
-	txmgmt = lockbasedtxmgr.NewLockBasedTxMgr(versionedDB)
+	txmgmt = lockbasedtxmgr.NewLockBasedTxMgrWithStateListeners(ledgerID, versionedDB, stateListeners)
 
 	// Create a kvLedger for this chain/ledger, which encasulates the underlying
 	// id store, blockstore, txmgr (state database), history database
-	l := &kvLedger{ledgerID, blockStore, txmgmt, historyDB}
+	l := &kvLedger{ledgerID, blockStore, versionedDB, txmgmt, historyDB}
+
+	// Register the ledger for the chaincode lifecycle events, before the recovery
+	// may commit the deployments of chaincodes
+	cceventmgmt.GetMgr().Register(ledgerID, l)
 
 	//Recover both state DB and history DB if they are out of sync with block storage
 	if err := l.recoverDBs(); err != nil {
-		panic(fmt.Errorf(`Error during state DB recovery:%s`, err))
+		l.Close()
+		return nil, fmt.Errorf("error during the recovery of ledger [%s]: %s", ledgerID, err)
 	}
 
 	return l, nil
 }
 
 //Recover the state database and history database (if exist)
-//by recommitting last valid blocks
+//by recommitting last valid blocks. A database ahead of the block
+//storage cannot be rolled back, it fails the recovery
 func (l *kvLedger) recoverDBs() error {
 	logger.Debugf("Entering recoverDB()")
-	//If there is no block in blockstorage, nothing to recover.
-	info, _ := l.blockStore.GetBlockchainInfo()
-	if info.Height == 0 {
-		logger.Debug("Block storage is empty.")
-		return nil
+	info, err := l.blockStore.GetBlockchainInfo()
+	if err != nil {
+		return err
+	}
+	lastAvailableBlockNum := uint64(0)
+	if info.Height > 0 {
+		lastAvailableBlockNum = info.Height - 1
+	}
+	recoverables := []*recoverer{
+		{name: "state DB", recoverable: l.txtmgmt},
+		{name: "history DB", recoverable: l.historyDB},
 	}
-	lastAvailableBlockNum := info.Height - 1
-	recoverables := []recoverable{l.txtmgmt, l.historyDB}
 	recoverers := []*recoverer{}
-	for _, recoverable := range recoverables {
-		recoverFlag, firstBlockNum, err := recoverable.ShouldRecover(lastAvailableBlockNum)
+	for _, r := range recoverables {
+		recoverFlag, firstBlockNum, err := r.recoverable.ShouldRecover(lastAvailableBlockNum)
 		if err != nil {
 			return err
 		}
-		if recoverFlag {
-			recoverers = append(recoverers, &recoverer{firstBlockNum, recoverable})
+		if firstBlockNum > info.Height {
+			return fmt.Errorf("the %s has committed block %d while the block storage ends at height %d, as if the block storage "+
+				"lost blocks or was restored from an older backup. The %s cannot be rolled back, it must be removed so that it is "+
+				"rebuilt from the block storage", r.name, firstBlockNum-1, info.Height, r.name)
+		}
+		if recoverFlag && info.Height > 0 {
+			logger.Infof("Ledger [%s]: the %s is behind the block storage, at height %d instead of %d", l.ledgerID, r.name, firstBlockNum, info.Height)
+			recoverers = append(recoverers, &recoverer{name: r.name, firstBlockNum: firstBlockNum, recoverable: r.recoverable})
 		}
 	}
+	//If there is no block in blockstorage, nothing to recover.
+	if info.Height == 0 {
+		logger.Debug("Block storage is empty.")
+		return nil
+	}
 	if len(recoverers) == 0 {
+		logger.Debugf("Ledger [%s]: the databases are consistent with the block storage at height %d", l.ledgerID, info.Height)
 		return nil
 	}
 	if len(recoverers) == 1 {
-		return l.recommitLostBlocks(recoverers[0].firstBlockNum, lastAvailableBlockNum, recoverers[0].recoverable)
+		return l.recommitLostBlocks(recoverers[0].firstBlockNum, lastAvailableBlockNum, recoverers[0])
 	}
 
 	// both dbs need to be recovered
@@ -103,30 +129,36 @@ func (l *kvLedger) recoverDBs() error {
 	if recoverers[0].firstBlockNum != recoverers[1].firstBlockNum {
 		// bring the lagger db equal to the other db
 		if err := l.recommitLostBlocks(recoverers[0].firstBlockNum, recoverers[1].firstBlockNum-1,
-			recoverers[0].recoverable); err != nil {
+			recoverers[0]); err != nil {
 			return err
 		}
 	}
 	// get both the db upto block storage
 	return l.recommitLostBlocks(recoverers[1].firstBlockNum, lastAvailableBlockNum,
-		recoverers[0].recoverable, recoverers[1].recoverable)
+		recoverers[0], recoverers[1])
 }
 
 //recommitLostBlocks retrieves blocks in specified range and commit the write set to either
 //state DB or history DB or both
-func (l *kvLedger) recommitLostBlocks(firstBlockNum uint64, lastBlockNum uint64, recoverables ...recoverable) error {
+func (l *kvLedger) recommitLostBlocks(firstBlockNum uint64, lastBlockNum uint64, recoverers ...*recoverer) error {
+	names := make([]string, len(recoverers))
+	for i, r := range recoverers {
+		names[i] = r.name
+	}
+	logger.Infof("Ledger [%s]: recommitting blocks %d to %d to the %s", l.ledgerID, firstBlockNum, lastBlockNum, strings.Join(names, " and "))
 	var err error
 	var block *common.Block
 	for blockNumber := firstBlockNum; blockNumber <= lastBlockNum; blockNumber++ {
 		if block, err = l.GetBlockByNumber(blockNumber); err != nil {
 			return err
 		}
-		for _, r := range recoverables {
-			if err := r.CommitLostBlock(block); err != nil {
-				return err
+		for _, r := range recoverers {
+			if err := r.recoverable.CommitLostBlock(block); err != nil {
+				return fmt.Errorf("failed to recommit block %d to the %s: %s", blockNumber, r.name, err)
 			}
 		}
 	}
+	logger.Infof("Ledger [%s]: recommitted blocks %d to %d to the %s", l.ledgerID, firstBlockNum, lastBlockNum, strings.Join(names, " and "))
 	return nil
 }
 
@@ -187,6 +219,11 @@ func (l *kvLedger) Prune(policy commonledger.PrunePolicy) error {
 	return errors.New("Not yet implemented")
 }
 
+// SetRangeQueryHashing sets whether the transaction simulators summarize the results of the range queries
+func (l *kvLedger) SetRangeQueryHashing(enabled bool) {
+	l.txtmgmt.SetRangeQueryHashing(enabled)
+}
+
 // NewTxSimulator returns new `ledger.TxSimulator`
 func (l *kvLedger) NewTxSimulator() (ledger.TxSimulator, error) {
 	return l.txtmgmt.NewTxSimulator()
@@ -207,6 +244,19 @@ func (l *kvLedger) NewHistoryQueryExecutor() (ledger.HistoryQueryExecutor, error
 	return l.historyDB.NewHistoryQueryExecutor(l.blockStore)
 }
 
+// NewHistoricalQueryExecutor gives handle to a query executor reading the state as of the given height,
+// from the history database and the blocks
+func (l *kvLedger) NewHistoricalQueryExecutor(height uint64) (ledger.HistoricalQueryExecutor, error) {
+	info, err := l.blockStore.GetBlockchainInfo()
+	if err != nil {
+		return nil, err
+	}
+	if height == 0 || height > info.Height {
+		return nil, fmt.Errorf("height %d is out of the range of the ledger, of height %d", height, info.Height)
+	}
+	return l.historyDB.NewHistoricalQueryExecutor(l.blockStore, height)
+}
+
 // Commit commits the valid block (returned in the method RemoveInvalidTransactionsAndPrepare) and related state changes
 func (l *kvLedger) Commit(block *common.Block) error {
 	var err error
@@ -240,6 +290,29 @@ func (l *kvLedger) Commit(block *common.Block) error {
 	return nil
 }
 
+// HandleChaincodeDeploy implements function in interface cceventmgmt.ChaincodeLifecycleEventListener.
+// It creates the indexes shipped in the package of the chaincode for the state database, if the
+// state database supports them
+func (l *kvLedger) HandleChaincodeDeploy(chaincodeDefinition *cceventmgmt.ChaincodeDefinition, dbArtifacts map[string][]byte) ([]string, error) {
+	indexCapable, ok := l.versionedDB.(statedb.IndexCapable)
+	if !ok {
+		return nil, nil
+	}
+	indexesDir := indexCapable.GetDBType() + "/indexes/"
+	indexes := make(map[string][]byte)
+	for path, content := range dbArtifacts {
+		if strings.HasPrefix(path, indexesDir) && !strings.Contains(path[len(indexesDir):], "/") {
+			indexes[path[len(indexesDir):]] = content
+		}
+	}
+	if len(indexes) == 0 {
+		return nil, nil
+	}
+	names := util.GetSortedKeys(indexes)
+	logger.Debugf("Channel [%s]: Deploying indexes %v of chaincode [%s]", l.ledgerID, names, chaincodeDefinition)
+	return names, indexCapable.ProcessIndexesForChaincodeDeploy(chaincodeDefinition.Name, indexes)
+}
+
 // Close closes `KVLedger`
 func (l *kvLedger) Close() {
 	l.blockStore.Shutdown()
@@ -22,6 +22,8 @@ import (
 	"fmt"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/bccsp/factory"
+	"github.com/hyperledger/fabric/common/crypto/keyring"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage/fsblkstorage"
 	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
@@ -55,14 +57,27 @@ type Provider struct {
 	blockStoreProvider blkstorage.BlockStoreProvider
 	vdbProvider        statedb.VersionedDBProvider
 	historydbProvider  historydb.HistoryDBProvider
+	stateListeners     map[string]ledger.StateListener
 }
 
 // NewProvider instantiates a new Provider.
 // This is not thread-safe and assumed to be synchronized be the caller
 func NewProvider() (ledger.PeerLedgerProvider, error) {
+	return NewProviderWithStateListeners(nil)
+}
+
+// NewProviderWithStateListeners instantiates a new Provider, whose ledgers notify the state
+// listeners, keyed by namespace, of the updates of their namespace committed to the state
+func NewProviderWithStateListeners(stateListeners map[string]ledger.StateListener) (ledger.PeerLedgerProvider, error) {
 
 	logger.Info("Initializing ledger provider")
 
+	// Load the keyring encrypting the ledger data, if any
+	kr, err := loadKeyring()
+	if err != nil {
+		return nil, err
+	}
+
 	// Initialize the ID store (inventory of chainIds/ledgerIds)
 	idStore := openIDStore(ledgerconfig.GetLedgerProviderPath())
 
@@ -77,33 +92,64 @@ func NewProvider() (ledger.PeerLedgerProvider, error) {
 	}
 	indexConfig := &blkstorage.IndexConfig{AttrsToIndex: attrsToIndex}
 	blockStoreProvider := fsblkstorage.NewProvider(
-		fsblkstorage.NewConf(ledgerconfig.GetBlockStorePath(), ledgerconfig.GetMaxBlockfileSize()),
+		fsblkstorage.NewConfWithScaleConf(
+			ledgerconfig.GetBlockStorePath(),
+			ledgerconfig.GetMaxBlockfileSize(),
+			ledgerconfig.GetLevelDBConf(ledgerconfig.GetBlockStorePath()),
+			kr,
+			ledgerconfig.GetBlockStoreScaleConf()),
 		indexConfig)
 
 	// Initialize the versioned database (state database)
-	var vdbProvider statedb.VersionedDBProvider
-	if !ledgerconfig.IsCouchDBEnabled() {
-		logger.Debug("Constructing leveldb VersionedDBProvider")
-		vdbProvider = stateleveldb.NewVersionedDBProvider()
-	} else {
-		logger.Debug("Constructing CouchDB VersionedDBProvider")
-		var err error
-		vdbProvider, err = statecouchdb.NewVersionedDBProvider()
-		if err != nil {
-			return nil, err
-		}
+	vdbProvider, err := newVersionedDBProvider(ledgerconfig.GetStateDatabase(), kr)
+	if err != nil {
+		return nil, err
 	}
 
 	// Initialize the history database (index for history of values by key)
 	var historydbProvider historydb.HistoryDBProvider
-	historydbProvider = historyleveldb.NewHistoryDBProvider()
+	historydbProvider = historyleveldb.NewHistoryDBProviderWithKeyring(kr)
 
 	logger.Info("ledger provider Initialized")
-	provider := &Provider{idStore, blockStoreProvider, vdbProvider, historydbProvider}
+	provider := &Provider{idStore, blockStoreProvider, vdbProvider, historydbProvider, stateListeners}
 	provider.recoverUnderConstructionLedger()
 	return provider, nil
 }
 
+// loadKeyring loads the keyring encrypting the ledger data from the BCCSP, or returns nil if the
+// ledger data are not encrypted
+func loadKeyring() (*keyring.Keyring, error) {
+	path := ledgerconfig.GetKeyringPath()
+	if path == "" {
+		return nil, nil
+	}
+	kr, err := keyring.Load(path, factory.GetDefault())
+	if err != nil {
+		return nil, fmt.Errorf("error loading the ledger keyring: %s", err)
+	}
+	logger.Infof("Encrypting the ledger data with key %s", kr.ActiveKeyID())
+	return kr, nil
+}
+
+// newVersionedDBProvider constructs the VersionedDBProvider for the given state database backend,
+// whose values are encrypted with the keyring if any
+func newVersionedDBProvider(stateDatabase string, kr *keyring.Keyring) (statedb.VersionedDBProvider, error) {
+	switch stateDatabase {
+	case ledgerconfig.StateDatabaseGoLevelDB:
+		logger.Debug("Constructing leveldb VersionedDBProvider")
+		return stateleveldb.NewVersionedDBProviderWithKeyring(kr), nil
+	case ledgerconfig.StateDatabaseCouchDB:
+		if kr != nil {
+			return nil, fmt.Errorf("the ledger keyring cannot encrypt the state database [%s]", stateDatabase)
+		}
+		logger.Debug("Constructing CouchDB VersionedDBProvider")
+		return statecouchdb.NewVersionedDBProvider()
+	default:
+		return nil, fmt.Errorf("unsupported state database [%s], supported values are [%s] and [%s]",
+			stateDatabase, ledgerconfig.StateDatabaseGoLevelDB, ledgerconfig.StateDatabaseCouchDB)
+	}
+}
+
 // Create implements the corresponding method from interface ledger.PeerLedgerProvider
 // This functions sets a under construction flag before doing any thing related to ledger creation and
 // upon a successful ledger creation with the committed genesis block, removes the flag and add entry into
@@ -174,7 +220,7 @@ func (provider *Provider) openInternal(ledgerID string) (ledger.PeerLedger, erro
 
 	// Create a kvLedger for this chain/ledger, which encasulates the underlying data stores
 	// (id store, blockstore, state database, history database)
-	l, err := newKVLedger(ledgerID, blockStore, vDB, historyDB)
+	l, err := newKVLedger(ledgerID, blockStore, vDB, historyDB, provider.stateListeners)
 	if err != nil {
 		return nil, err
 	}
@@ -191,6 +237,79 @@ func (provider *Provider) List() ([]string, error) {
 	return provider.idStore.getAllLedgerIds()
 }
 
+// Lost implements the corresponding method from interface ledger.PeerLedgerProvider
+func (provider *Provider) Lost(ledgerID string) (bool, error) {
+	exists, err := provider.idStore.ledgerIDExists(ledgerID)
+	if err != nil {
+		return false, err
+	}
+	if !exists {
+		return false, ErrNonExistingLedgerID
+	}
+	detector, ok := provider.blockStoreProvider.(lossDetector)
+	if !ok {
+		return false, nil
+	}
+	return detector.Lost(ledgerID)
+}
+
+// lossDetector is implemented by the block stores that detect the loss of the blocks of a ledger
+type lossDetector interface {
+	Lost(ledgerID string) (bool, error)
+}
+
+// Drop implements the corresponding method from interface ledger.PeerLedgerProvider
+// The block storage is dropped first, so that a ledger partially dropped by a crash is still lost, and the ledger
+// id is removed from the created ledgers list last
+func (provider *Provider) Drop(ledgerID string) error {
+	exists, err := provider.idStore.ledgerIDExists(ledgerID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrNonExistingLedgerID
+	}
+	logger.Infof("Dropping the data of ledger [%s]", ledgerID)
+	for _, store := range []interface{}{provider.blockStoreProvider, provider.vdbProvider, provider.historydbProvider} {
+		d, ok := store.(dropper)
+		if !ok {
+			return fmt.Errorf("the data of ledger [%s] cannot be dropped from %T", ledgerID, store)
+		}
+		if err := d.Drop(ledgerID); err != nil {
+			return err
+		}
+	}
+	return provider.idStore.deleteLedgerID(ledgerID)
+}
+
+// dropper is implemented by the stores that can remove the data of a ledger
+type dropper interface {
+	Drop(ledgerID string) error
+}
+
+// compactor is implemented by the stores that are backed by goleveldb
+type compactor interface {
+	Compact() error
+}
+
+// Compact implements the corresponding method from interface ledger.PeerLedgerProvider
+// The block index, state and history dbs are compacted if they are backed by goleveldb
+func (provider *Provider) Compact() error {
+	if err := provider.idStore.db.Compact(); err != nil {
+		return err
+	}
+	for _, store := range []interface{}{provider.blockStoreProvider, provider.vdbProvider, provider.historydbProvider} {
+		c, ok := store.(compactor)
+		if !ok {
+			continue
+		}
+		if err := c.Compact(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Close implements the corresponding method from interface ledger.PeerLedgerProvider
 func (provider *Provider) Close() {
 	provider.idStore.close()
@@ -303,6 +422,10 @@ func (s *idStore) createLedgerID(ledgerID string, gb *common.Block) error {
 	return s.db.WriteBatch(batch, true)
 }
 
+func (s *idStore) deleteLedgerID(ledgerID string) error {
+	return s.db.Delete(s.encodeLedgerKey(ledgerID), true)
+}
+
 func (s *idStore) ledgerIDExists(ledgerID string) (bool, error) {
 	key := s.encodeLedgerKey(ledgerID)
 	val := []byte{}
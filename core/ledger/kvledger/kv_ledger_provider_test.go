@@ -17,7 +17,9 @@ limitations under the License.
 package kvledger
 
 import (
+	"bytes"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"testing"
@@ -33,6 +35,68 @@ import (
 	"github.com/spf13/viper"
 )
 
+func TestLedgerProviderUnsupportedStateDatabase(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	viper.Set("ledger.state.stateDatabase", "unknowndb")
+	defer viper.Set("ledger.state.stateDatabase", "goleveldb")
+	_, err := NewProvider()
+	testutil.AssertError(t, err, "Expected an error for an unsupported state database")
+}
+
+func TestLedgerProviderKeyring(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	dir, err := ioutil.TempDir("", "keyring")
+	testutil.AssertNoError(t, err, "")
+	defer os.RemoveAll(dir)
+	testutil.AssertNoError(t, ioutil.WriteFile(filepath.Join(dir, "key1.key"), make([]byte, 32), 0600), "")
+	keyringPath := filepath.Join(dir, "keyring.yaml")
+	testutil.AssertNoError(t, ioutil.WriteFile(keyringPath, []byte("Active: key1\nKeys:\n  - ID: key1\n    File: key1.key\n"), 0600), "")
+	viper.Set("ledger.encryption.keyring", keyringPath)
+	defer viper.Set("ledger.encryption.keyring", "")
+
+	provider, err := NewProvider()
+	testutil.AssertNoError(t, err, "")
+	ledgerID := constructTestLedgerID(0)
+	genesisBlock, _ := configtxtest.MakeGenesisBlock(ledgerID)
+	ledger, err := provider.Create(genesisBlock)
+	testutil.AssertNoError(t, err, "")
+	bcInfo, err := ledger.GetBlockchainInfo()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, bcInfo.Height, uint64(1))
+	ledger.Close()
+	provider.Close()
+	content, err := ioutil.ReadFile(filepath.Join(ledgerconfig.GetBlockStorePath(), fsblkstorage.ChainsDir, ledgerID, "blockfile_000000"))
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, bytes.Contains(content, []byte(ledgerID)), false)
+
+	viper.Set("ledger.state.stateDatabase", ledgerconfig.StateDatabaseCouchDB)
+	_, err = NewProvider()
+	viper.Set("ledger.state.stateDatabase", "goleveldb")
+	testutil.AssertError(t, err, "Expected an error for the CouchDB state database with a keyring")
+
+	viper.Set("ledger.encryption.keyring", filepath.Join(dir, "missing.yaml"))
+	_, err = NewProvider()
+	testutil.AssertError(t, err, "Expected an error for a missing keyring")
+}
+
+func TestLedgerProviderCompact(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	provider, _ := NewProvider()
+	defer provider.Close()
+	genesisBlock, _ := configtxtest.MakeGenesisBlock(constructTestLedgerID(0))
+	ledger, err := provider.Create(genesisBlock)
+	testutil.AssertNoError(t, err, "")
+	defer ledger.Close()
+
+	testutil.AssertNoError(t, provider.Compact(), "")
+	bcInfo, err := ledger.GetBlockchainInfo()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, bcInfo.Height, uint64(1))
+}
+
 func TestLedgerProvider(t *testing.T) {
 	env := newTestEnv(t)
 	defer env.cleanup()
@@ -166,6 +230,70 @@ func TestMultipleLedgerBasicRW(t *testing.T) {
 	}
 }
 
+func TestLedgerProviderLostAndDrop(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	provider, _ := NewProvider()
+	ledgerIDs := []string{constructTestLedgerID(0), constructTestLedgerID(1)}
+	genesisBlocks := make([]*common.Block, len(ledgerIDs))
+	for i, ledgerID := range ledgerIDs {
+		bg, gb := testutil.NewBlockGenerator(t, ledgerID, false)
+		genesisBlocks[i] = gb
+		l, err := provider.Create(gb)
+		testutil.AssertNoError(t, err, "")
+		s, _ := l.NewTxSimulator()
+		testutil.AssertNoError(t, s.SetState("ns", "testKey", []byte("testValue")), "")
+		s.Done()
+		res, err := s.GetTxSimulationResults()
+		testutil.AssertNoError(t, err, "")
+		testutil.AssertNoError(t, l.Commit(bg.NextBlock([][]byte{res})), "")
+		l.Close()
+	}
+	lost, err := provider.Lost(ledgerIDs[0])
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, lost, false)
+	_, err = provider.Lost(constructTestLedgerID(2))
+	testutil.AssertEquals(t, err, ErrNonExistingLedgerID)
+	provider.Close()
+
+	// The block files of the first ledger are lost, its index, state and history remain
+	testutil.AssertNoError(t, os.RemoveAll(filepath.Join(ledgerconfig.GetBlockStorePath(), fsblkstorage.ChainsDir, ledgerIDs[0])), "")
+	provider, _ = NewProvider()
+	defer provider.Close()
+	lost, err = provider.Lost(ledgerIDs[0])
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, lost, true)
+
+	testutil.AssertNoError(t, provider.Drop(ledgerIDs[0]), "")
+	existingLedgerIDs, err := provider.List()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, existingLedgerIDs, ledgerIDs[1:])
+	testutil.AssertEquals(t, provider.Drop(ledgerIDs[0]), ErrNonExistingLedgerID)
+
+	// The ledger is created again as when joining the channel, without the state of the dropped one
+	l, err := provider.Create(genesisBlocks[0])
+	testutil.AssertNoError(t, err, "")
+	defer l.Close()
+	bcInfo, err := l.GetBlockchainInfo()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, bcInfo.Height, uint64(1))
+	q, _ := l.NewQueryExecutor()
+	val, err := q.GetState("ns", "testKey")
+	q.Done()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertNil(t, val)
+
+	// The other ledger is left untouched
+	l2, err := provider.Open(ledgerIDs[1])
+	testutil.AssertNoError(t, err, "")
+	defer l2.Close()
+	q, _ = l2.NewQueryExecutor()
+	val, err = q.GetState("ns", "testKey")
+	q.Done()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, val, []byte("testValue"))
+}
+
 func TestLedgerBackup(t *testing.T) {
 	ledgerid := "TestLedger"
 	originalPath := "/tmp/fabric/ledgertests/kvledger1"
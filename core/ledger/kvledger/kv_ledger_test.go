@@ -111,6 +111,37 @@ func TestKVLedgerBlockStorage(t *testing.T) {
 
 }
 
+func TestKVLedgerHistoricalQueryExecutor(t *testing.T) {
+	ledgertestutil.SetupCoreYAMLConfig()
+	env := newTestEnv(t)
+	defer env.cleanup()
+	provider, _ := NewProvider()
+	defer provider.Close()
+
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	ledger, _ := provider.Create(gb)
+	defer ledger.Close()
+	for _, value := range []string{"value1", "value2"} {
+		simulator, _ := ledger.NewTxSimulator()
+		simulator.SetState("ns1", "key1", []byte(value))
+		simulator.Done()
+		simRes, _ := simulator.GetTxSimulationResults()
+		testutil.AssertNoError(t, ledger.Commit(bg.NextBlock([][]byte{simRes})), "")
+	}
+
+	// read the state as of the height at which block1 was the last block
+	qe, err := ledger.NewHistoricalQueryExecutor(2)
+	testutil.AssertNoError(t, err, "Error upon NewHistoricalQueryExecutor")
+	value, _ := qe.GetState("ns1", "key1")
+	testutil.AssertEquals(t, value, []byte("value1"))
+	qe.Done()
+
+	_, err = ledger.NewHistoricalQueryExecutor(0)
+	testutil.AssertError(t, err, "Expected an error for height 0")
+	_, err = ledger.NewHistoricalQueryExecutor(4)
+	testutil.AssertError(t, err, "Expected an error for a height over the one of the ledger")
+}
+
 func TestKVLedgerDBRecovery(t *testing.T) {
 	ledgertestutil.SetupCoreYAMLConfig()
 	env := newTestEnv(t)
@@ -441,6 +472,36 @@ func TestKVLedgerDBRecovery(t *testing.T) {
 	simulator.Done()
 }
 
+func TestKVLedgerDBAheadOfBlockStorage(t *testing.T) {
+	ledgertestutil.SetupCoreYAMLConfig()
+	env := newTestEnv(t)
+	defer env.cleanup()
+	provider, _ := NewProvider()
+
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	ledger, err := provider.Create(gb)
+	assert.NoError(t, err)
+
+	simulator, _ := ledger.NewTxSimulator()
+	simulator.SetState("ns1", "key1", []byte("value1.1"))
+	simulator.Done()
+	simRes, _ := simulator.GetTxSimulationResults()
+	block1 := bg.NextBlock([][]byte{simRes})
+
+	// the block is committed to the state DB, but not to the block storage, as when
+	// the block storage is restored from an older backup
+	assert.NoError(t, ledger.(*kvLedger).txtmgmt.ValidateAndPrepare(block1, true))
+	assert.NoError(t, ledger.(*kvLedger).txtmgmt.Commit())
+	ledger.Close()
+	provider.Close()
+
+	provider, _ = NewProvider()
+	defer provider.Close()
+	_, err = provider.Open("testLedger")
+	assert.Error(t, err, "The state DB ahead of the block storage should fail the recovery")
+	assert.Contains(t, err.Error(), "the state DB has committed block 1 while the block storage ends at height 1")
+}
+
 func TestLedgerWithCouchDbEnabledWithBinaryAndJSONData(t *testing.T) {
 
 	//call a helper method to load the core.yaml
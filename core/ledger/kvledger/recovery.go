@@ -28,6 +28,8 @@ type recoverable interface {
 }
 
 type recoverer struct {
+	// name of the database, in the logs
+	name          string
 	firstBlockNum uint64
 	recoverable   recoverable
 }
@@ -0,0 +1,64 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"github.com/hyperledger/fabric/core/ledger/kvledger/stateexport"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+)
+
+// ExportState exports the given namespaces of the state of ledger ledgerID into dir.
+// This is an offline operation and must not be invoked while the peer is running
+func ExportState(ledgerID string, namespaces []string, dir string) (*stateexport.Manifest, error) {
+	var manifest *stateexport.Manifest
+	err := withStateDB(ledgerID, func(db statedb.VersionedDB) error {
+		var err error
+		manifest, err = stateexport.Export(ledgerID, db, namespaces, dir)
+		return err
+	})
+	return manifest, err
+}
+
+// ImportState imports the state exported in dir into the state of ledger ledgerID. The ledger must already
+// exist on this peer (i.e., the peer must have joined the channel).
+// This is an offline operation and must not be invoked while the peer is running
+func ImportState(ledgerID string, dir string) (*stateexport.Manifest, error) {
+	var manifest *stateexport.Manifest
+	err := withStateDB(ledgerID, func(db statedb.VersionedDB) error {
+		var err error
+		manifest, err = stateexport.Import(db, dir)
+		return err
+	})
+	return manifest, err
+}
+
+func withStateDB(ledgerID string, f func(db statedb.VersionedDB) error) error {
+	idStore := openIDStore(ledgerconfig.GetLedgerProviderPath())
+	exists, err := idStore.ledgerIDExists(ledgerID)
+	idStore.close()
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrNonExistingLedgerID
+	}
+	kr, err := loadKeyring()
+	if err != nil {
+		return err
+	}
+	vdbProvider, err := newVersionedDBProvider(ledgerconfig.GetStateDatabase(), kr)
+	if err != nil {
+		return err
+	}
+	defer vdbProvider.Close()
+	db, err := vdbProvider.GetDBHandle(ledgerID)
+	if err != nil {
+		return err
+	}
+	return f(db)
+}
@@ -0,0 +1,45 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	configtxtest "github.com/hyperledger/fabric/common/configtx/test"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+)
+
+func TestExportImportState(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	provider, _ := NewProvider()
+	genesisBlock, _ := configtxtest.MakeGenesisBlock(constructTestLedgerID(0))
+	ledger, err := provider.Create(genesisBlock)
+	testutil.AssertNoError(t, err, "")
+	ledger.Close()
+	provider.Close()
+
+	exportDir := filepath.Join(ledgerconfig.GetRootPath(), "export")
+	_, err = ExportState(constructTestLedgerID(1), []string{"ns1"}, exportDir)
+	testutil.AssertEquals(t, err, ErrNonExistingLedgerID)
+
+	manifest, err := ExportState(constructTestLedgerID(0), []string{"ns1"}, exportDir)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, manifest.LedgerID, constructTestLedgerID(0))
+	testutil.AssertEquals(t, len(manifest.Namespaces), 1)
+	testutil.AssertEquals(t, manifest.Namespaces[0].NumEntries, uint64(0))
+	_, err = os.Stat(filepath.Join(exportDir, manifest.Namespaces[0].File))
+	testutil.AssertNoError(t, err, "")
+
+	_, err = ImportState(constructTestLedgerID(1), exportDir)
+	testutil.AssertEquals(t, err, ErrNonExistingLedgerID)
+	_, err = ImportState(constructTestLedgerID(0), exportDir)
+	testutil.AssertNoError(t, err, "")
+}
@@ -0,0 +1,259 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package stateexport
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+)
+
+var logger = flogging.MustGetLogger("stateexport")
+
+// ManifestFile is the name of the file, within an export directory, that describes the exported namespaces
+const ManifestFile = "manifest.json"
+
+// importBatchSize is the number of entries applied to the state db in a single batch during import
+const importBatchSize = 1000
+
+// Manifest describes the contents of an export directory
+type Manifest struct {
+	LedgerID   string            `json:"ledgerId"`
+	SavePoint  *Height           `json:"savePoint,omitempty"`
+	Namespaces []*NamespaceEntry `json:"namespaces"`
+}
+
+// NamespaceEntry describes the export file of a single namespace
+type NamespaceEntry struct {
+	Namespace  string `json:"namespace"`
+	File       string `json:"file"`
+	NumEntries uint64 `json:"numEntries"`
+	// SHA256 is the hex encoded SHA-256 hash of the export file
+	SHA256 string `json:"sha256"`
+}
+
+// Height is the portable representation of a version.Height
+type Height struct {
+	BlockNum uint64 `json:"blockNum"`
+	TxNum    uint64 `json:"txNum"`
+}
+
+// Entry is a single exported key, written as one json object per line in the export file of a namespace
+type Entry struct {
+	Key     string  `json:"key"`
+	Value   []byte  `json:"value"`
+	Version *Height `json:"version"`
+}
+
+func toHeight(h *version.Height) *Height {
+	if h == nil {
+		return nil
+	}
+	return &Height{BlockNum: h.BlockNum, TxNum: h.TxNum}
+}
+
+func (h *Height) toVersion() *version.Height {
+	return version.NewHeight(h.BlockNum, h.TxNum)
+}
+
+// Export writes the state of the given namespaces of db into dir, one file per namespace, along with a manifest
+// that records the number of entries and the SHA-256 hash of each file. dir is created if missing and must be empty
+func Export(ledgerID string, db statedb.VersionedDB, namespaces []string, dir string) (*Manifest, error) {
+	if err := createEmptyDir(dir); err != nil {
+		return nil, err
+	}
+	savePoint, err := db.GetLatestSavePoint()
+	if err != nil {
+		return nil, err
+	}
+	manifest := &Manifest{LedgerID: ledgerID, SavePoint: toHeight(savePoint)}
+	for i, ns := range namespaces {
+		nsEntry := &NamespaceEntry{Namespace: ns, File: fmt.Sprintf("%d.jsonl", i)}
+		if err := exportNamespace(db, nsEntry, filepath.Join(dir, nsEntry.File)); err != nil {
+			return nil, err
+		}
+		logger.Infof("Exported [%d] entries of namespace [%s] of ledger [%s]", nsEntry.NumEntries, ns, ledgerID)
+		manifest.Namespaces = append(manifest.Namespaces, nsEntry)
+	}
+	manifestBytes, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, ManifestFile), manifestBytes, 0644); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+func exportNamespace(db statedb.VersionedDB, nsEntry *NamespaceEntry, path string) error {
+	itr, err := db.GetStateRangeScanIterator(nsEntry.Namespace, "", "")
+	if err != nil {
+		return err
+	}
+	defer itr.Close()
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	hasher := sha256.New()
+	writer := bufio.NewWriter(io.MultiWriter(file, hasher))
+	encoder := json.NewEncoder(writer)
+	for {
+		res, err := itr.Next()
+		if err != nil {
+			return err
+		}
+		if res == nil {
+			break
+		}
+		kv := res.(*statedb.VersionedKV)
+		if err := encoder.Encode(&Entry{Key: kv.Key, Value: kv.Value, Version: toHeight(kv.Version)}); err != nil {
+			return err
+		}
+		nsEntry.NumEntries++
+	}
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+	nsEntry.SHA256 = hex.EncodeToString(hasher.Sum(nil))
+	return file.Sync()
+}
+
+// ReadManifest reads the manifest of the export in dir
+func ReadManifest(dir string) (*Manifest, error) {
+	manifestBytes, err := ioutil.ReadFile(filepath.Join(dir, ManifestFile))
+	if err != nil {
+		return nil, err
+	}
+	manifest := &Manifest{}
+	if err := json.Unmarshal(manifestBytes, manifest); err != nil {
+		return nil, fmt.Errorf("error while unmarshalling manifest: %s", err)
+	}
+	return manifest, nil
+}
+
+// Verify checks the number of entries and the hash of each of the export files in dir against the manifest
+func Verify(dir string) (*Manifest, error) {
+	manifest, err := ReadManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, nsEntry := range manifest.Namespaces {
+		if err := readNamespace(nsEntry, filepath.Join(dir, nsEntry.File), func(*Entry) error { return nil }); err != nil {
+			return nil, err
+		}
+	}
+	return manifest, nil
+}
+
+// Import verifies the export in dir and applies all of its entries to db. The existing save point of db is retained.
+// If db has no save point, the save point recorded in the manifest is used
+func Import(db statedb.VersionedDB, dir string) (*Manifest, error) {
+	manifest, err := Verify(dir)
+	if err != nil {
+		return nil, err
+	}
+	savePoint, err := db.GetLatestSavePoint()
+	if err != nil {
+		return nil, err
+	}
+	if savePoint == nil {
+		if manifest.SavePoint == nil {
+			return nil, fmt.Errorf("neither the state db nor the export in [%s] has a save point", dir)
+		}
+		savePoint = manifest.SavePoint.toVersion()
+	}
+	for _, nsEntry := range manifest.Namespaces {
+		batch := statedb.NewUpdateBatch()
+		numInBatch := 0
+		applyEntry := func(entry *Entry) error {
+			batch.Put(nsEntry.Namespace, entry.Key, entry.Value, entry.Version.toVersion())
+			numInBatch++
+			if numInBatch < importBatchSize {
+				return nil
+			}
+			if err := db.ApplyUpdates(batch, savePoint); err != nil {
+				return err
+			}
+			batch = statedb.NewUpdateBatch()
+			numInBatch = 0
+			return nil
+		}
+		if err := readNamespace(nsEntry, filepath.Join(dir, nsEntry.File), applyEntry); err != nil {
+			return nil, err
+		}
+		if err := db.ApplyUpdates(batch, savePoint); err != nil {
+			return nil, err
+		}
+		logger.Infof("Imported [%d] entries of namespace [%s]", nsEntry.NumEntries, nsEntry.Namespace)
+	}
+	return manifest, nil
+}
+
+// readNamespace decodes the entries of the export file of a namespace, invoking handle for each of them.
+// The hash and the number of entries are checked against nsEntry only after the entire file is read, hence
+// callers that apply entries as they are read are expected to have verified the file beforehand
+func readNamespace(nsEntry *NamespaceEntry, path string, handle func(*Entry) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	hasher := sha256.New()
+	decoder := json.NewDecoder(io.TeeReader(bufio.NewReader(file), hasher))
+	var numEntries uint64
+	for {
+		entry := &Entry{}
+		if err := decoder.Decode(entry); err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("error while decoding export file of namespace [%s]: %s", nsEntry.Namespace, err)
+		}
+		if entry.Version == nil {
+			return fmt.Errorf("missing version for key [%s] in export file of namespace [%s]", entry.Key, nsEntry.Namespace)
+		}
+		if err := handle(entry); err != nil {
+			return err
+		}
+		numEntries++
+	}
+	if hash := hex.EncodeToString(hasher.Sum(nil)); hash != nsEntry.SHA256 {
+		return fmt.Errorf("hash mismatch for export file of namespace [%s]: expected [%s], computed [%s]",
+			nsEntry.Namespace, nsEntry.SHA256, hash)
+	}
+	if numEntries != nsEntry.NumEntries {
+		return fmt.Errorf("entry count mismatch for export file of namespace [%s]: expected [%d], found [%d]",
+			nsEntry.Namespace, nsEntry.NumEntries, numEntries)
+	}
+	return nil
+}
+
+func createEmptyDir(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	if len(files) != 0 {
+		return fmt.Errorf("export directory [%s] is not empty", dir)
+	}
+	return nil
+}
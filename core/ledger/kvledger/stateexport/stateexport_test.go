@@ -0,0 +1,124 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package stateexport
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/stateleveldb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+const testExportDir = "/tmp/fabric/ledgertests/kvledger/stateexport/export"
+
+func TestMain(m *testing.M) {
+	viper.Set("peer.fileSystemPath", "/tmp/fabric/ledgertests/kvledger/stateexport")
+	os.Exit(m.Run())
+}
+
+func populateDB(t *testing.T, db statedb.VersionedDB) {
+	batch := statedb.NewUpdateBatch()
+	for i := 0; i < 5; i++ {
+		batch.Put("ns1", fmt.Sprintf("key%d", i), []byte(fmt.Sprintf("value%d", i)), version.NewHeight(1, uint64(i)))
+	}
+	batch.Put("ns2", "key1", []byte("ns2-value1"), version.NewHeight(2, 1))
+	batch.Put("ns3", "key1", []byte("ns3-value1"), version.NewHeight(2, 2))
+	assert.NoError(t, db.ApplyUpdates(batch, version.NewHeight(2, 2)))
+}
+
+func TestExportImport(t *testing.T) {
+	os.RemoveAll(testExportDir)
+	defer os.RemoveAll(testExportDir)
+
+	env := stateleveldb.NewTestVDBEnv(t)
+	sourceDB, err := env.DBProvider.GetDBHandle("source")
+	assert.NoError(t, err)
+	populateDB(t, sourceDB)
+
+	manifest, err := Export("source", sourceDB, []string{"ns1", "ns2"}, testExportDir)
+	assert.NoError(t, err)
+	assert.Len(t, manifest.Namespaces, 2)
+	assert.Equal(t, uint64(5), manifest.Namespaces[0].NumEntries)
+	assert.Equal(t, uint64(1), manifest.Namespaces[1].NumEntries)
+	assert.Equal(t, &Height{BlockNum: 2, TxNum: 2}, manifest.SavePoint)
+
+	readManifest, err := ReadManifest(testExportDir)
+	assert.NoError(t, err)
+	assert.Equal(t, manifest, readManifest)
+
+	// an export into a non-empty directory is not allowed
+	_, err = Export("source", sourceDB, []string{"ns1"}, testExportDir)
+	assert.Error(t, err)
+
+	// the target without a save point takes the save point of the export
+	targetDB, err := env.DBProvider.GetDBHandle("target")
+	assert.NoError(t, err)
+	_, err = Import(targetDB, testExportDir)
+	assert.NoError(t, err)
+	savePoint, err := targetDB.GetLatestSavePoint()
+	assert.NoError(t, err)
+	assert.Equal(t, version.NewHeight(2, 2), savePoint)
+
+	for i := 0; i < 5; i++ {
+		vv, err := targetDB.GetState("ns1", fmt.Sprintf("key%d", i))
+		assert.NoError(t, err)
+		assert.Equal(t, &statedb.VersionedValue{Value: []byte(fmt.Sprintf("value%d", i)), Version: version.NewHeight(1, uint64(i))}, vv)
+	}
+	vv, err := targetDB.GetState("ns2", "key1")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("ns2-value1"), vv.Value)
+	vv, err = targetDB.GetState("ns3", "key1")
+	assert.NoError(t, err)
+	assert.Nil(t, vv)
+
+	// the target with a save point retains it
+	otherDB, err := env.DBProvider.GetDBHandle("other")
+	assert.NoError(t, err)
+	assert.NoError(t, otherDB.ApplyUpdates(statedb.NewUpdateBatch(), version.NewHeight(5, 0)))
+	_, err = Import(otherDB, testExportDir)
+	assert.NoError(t, err)
+	savePoint, err = otherDB.GetLatestSavePoint()
+	assert.NoError(t, err)
+	assert.Equal(t, version.NewHeight(5, 0), savePoint)
+	env.Cleanup()
+}
+
+func TestImportTamperedExport(t *testing.T) {
+	os.RemoveAll(testExportDir)
+	defer os.RemoveAll(testExportDir)
+
+	env := stateleveldb.NewTestVDBEnv(t)
+	defer env.Cleanup()
+	sourceDB, err := env.DBProvider.GetDBHandle("source")
+	assert.NoError(t, err)
+	populateDB(t, sourceDB)
+	manifest, err := Export("source", sourceDB, []string{"ns1"}, testExportDir)
+	assert.NoError(t, err)
+
+	exportFile := filepath.Join(testExportDir, manifest.Namespaces[0].File)
+	content, err := ioutil.ReadFile(exportFile)
+	assert.NoError(t, err)
+	content[len(content)-2] = ' '
+	assert.NoError(t, ioutil.WriteFile(exportFile, content, 0644))
+
+	_, err = Verify(testExportDir)
+	assert.Error(t, err)
+	targetDB, err := env.DBProvider.GetDBHandle("target")
+	assert.NoError(t, err)
+	_, err = Import(targetDB, testExportDir)
+	assert.Error(t, err)
+	vv, err := targetDB.GetState("ns1", "key0")
+	assert.NoError(t, err)
+	assert.Nil(t, vv)
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package commontests
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
@@ -488,3 +489,43 @@ func TestQuery(t *testing.T, dbProvider statedb.VersionedDBProvider) {
 	testutil.AssertNil(t, queryResult2)
 
 }
+
+// BenchmarkApplyUpdates measures the commit throughput of the db by applying batches of benchmarkBatchSize keys
+func BenchmarkApplyUpdates(b *testing.B, dbProvider statedb.VersionedDBProvider) {
+	db, err := dbProvider.GetDBHandle("benchmarkapplyupdates")
+	testutil.AssertNoError(b, err, "")
+	value := make([]byte, benchmarkValueSize)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		batch := statedb.NewUpdateBatch()
+		for j := 0; j < benchmarkBatchSize; j++ {
+			batch.Put("ns", fmt.Sprintf("key_%d_%d", i, j), value, version.NewHeight(uint64(i), uint64(j)))
+		}
+		if err := db.ApplyUpdates(batch, version.NewHeight(uint64(i), benchmarkBatchSize)); err != nil {
+			b.Fatalf("Error while applying updates: %s", err)
+		}
+	}
+}
+
+// BenchmarkGetState measures the read latency of the db for keys that are present in the db
+func BenchmarkGetState(b *testing.B, dbProvider statedb.VersionedDBProvider) {
+	db, err := dbProvider.GetDBHandle("benchmarkgetstate")
+	testutil.AssertNoError(b, err, "")
+	batch := statedb.NewUpdateBatch()
+	value := make([]byte, benchmarkValueSize)
+	for j := 0; j < benchmarkBatchSize; j++ {
+		batch.Put("ns", fmt.Sprintf("key_%d", j), value, version.NewHeight(1, uint64(j)))
+	}
+	testutil.AssertNoError(b, db.ApplyUpdates(batch, version.NewHeight(1, benchmarkBatchSize)), "")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := db.GetState("ns", fmt.Sprintf("key_%d", i%benchmarkBatchSize)); err != nil {
+			b.Fatalf("Error while reading state: %s", err)
+		}
+	}
+}
+
+const (
+	benchmarkBatchSize = 500
+	benchmarkValueSize = 200
+)
@@ -262,3 +262,63 @@ func arrayContains(sourceArray []string, selectItem string) bool {
 	_, ok := set[selectItem]
 	return ok
 }
+
+/*
+ApplyIndexWrapper parses the index definition of a chaincode passed to CouchDB
+the wrapper prepends the wrapper "data." to all fields specified in the index,
+as the queries of the chaincode are rewritten by ApplyQueryWrapper
+
+Example:
+
+Source Index:
+{"index":{"fields":["owner",{"size":"desc"}]},"ddoc":"indexOwnerDoc","name":"indexOwner","type":"json"}
+
+Result Wrapped Index:
+{"ddoc":"indexOwnerDoc","index":{"fields":["data.owner",{"data.size":"desc"}]},"name":"indexOwner","type":"json"}
+
+*/
+func ApplyIndexWrapper(indexDefinition string) (string, error) {
+
+	//create a generic map for the index json
+	jsonIndexMap := make(map[string]interface{})
+
+	//unmarshal the index json into the generic map
+	decoder := json.NewDecoder(bytes.NewBuffer([]byte(indexDefinition)))
+	decoder.UseNumber()
+	if err := decoder.Decode(&jsonIndexMap); err != nil {
+		return "", err
+	}
+
+	index, ok := jsonIndexMap["index"].(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("the index definition has no \"index\" object")
+	}
+	fields, ok := index[jsonQueryFields].([]interface{})
+	if !ok || len(fields) == 0 {
+		return "", fmt.Errorf("the index definition has no \"fields\" array")
+	}
+
+	for i, field := range fields {
+		switch fieldValue := field.(type) {
+		case string:
+			//This is a simple string, so wrap the field
+			fields[i] = fmt.Sprintf("%v.%v", dataWrapper, fieldValue)
+		case map[string]interface{}:
+			//This is a field with a sort direction, so wrap its key
+			wrappedField := make(map[string]interface{})
+			for fieldName, direction := range fieldValue {
+				wrappedField[fmt.Sprintf("%v.%v", dataWrapper, fieldName)] = direction
+			}
+			fields[i] = wrappedField
+		default:
+			return "", fmt.Errorf("invalid field %v in the index definition", field)
+		}
+	}
+
+	//Marshal the updated json index
+	editedIndex, _ := json.Marshal(jsonIndexMap)
+
+	logger.Debugf("Rewritten index with data wrapper: %s", editedIndex)
+
+	return string(editedIndex), nil
+}
@@ -381,3 +381,26 @@ func TestQueryWithLargeInteger(t *testing.T) {
 	testutil.AssertEquals(t, strings.Count(wrappedQuery, "{\"$eq\":1000007}"), 1)
 
 }
+
+// TestIndexWrapper tests the wrapping of the fields of an index definition
+func TestIndexWrapper(t *testing.T) {
+
+	rawIndex := `{"index":{"fields":["owner",{"size":"desc"}]},"ddoc":"indexOwnerDoc","name":"indexOwner","type":"json"}`
+
+	wrappedIndex, err := ApplyIndexWrapper(rawIndex)
+
+	//Make sure the index did not throw an exception
+	testutil.AssertNoError(t, err, "Unexpected error thrown when for index JSON")
+
+	testutil.AssertEquals(t, wrappedIndex,
+		`{"ddoc":"indexOwnerDoc","index":{"fields":["data.owner",{"data.size":"desc"}]},"name":"indexOwner","type":"json"}`)
+
+	_, err = ApplyIndexWrapper(`{"ddoc":"indexOwnerDoc"}`)
+	testutil.AssertError(t, err, "Expected an error for an index without fields")
+
+	_, err = ApplyIndexWrapper(`{"index":{"fields":[1]}}`)
+	testutil.AssertError(t, err, "Expected an error for an invalid field")
+
+	_, err = ApplyIndexWrapper(`{"index":`)
+	testutil.AssertError(t, err, "Expected an error for an invalid JSON")
+}
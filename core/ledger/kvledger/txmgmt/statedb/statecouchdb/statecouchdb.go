@@ -30,6 +30,7 @@ import (
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
 	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/core/ledger/util/couchdb"
 )
 
@@ -82,6 +83,23 @@ func (provider *VersionedDBProvider) GetDBHandle(dbName string) (statedb.Version
 	return vdb, nil
 }
 
+// Drop drops the CouchDB database of the named db, which must not be in use
+func (provider *VersionedDBProvider) Drop(dbName string) error {
+	provider.mux.Lock()
+	defer provider.mux.Unlock()
+
+	// Creates the database if it is missing, so that dropping it succeeds
+	db, err := couchdb.CreateCouchDatabase(*provider.couchInstance, dbName)
+	if err != nil {
+		return err
+	}
+	if _, err := db.DropDatabase(); err != nil {
+		return err
+	}
+	delete(provider.databases, dbName)
+	return nil
+}
+
 // Close closes the underlying db instance
 func (provider *VersionedDBProvider) Close() {
 	// No close needed on Couch
@@ -250,6 +268,37 @@ func (vdb *VersionedDB) ExecuteQuery(namespace, query string) (statedb.ResultsIt
 	return newQueryScanner(*queryResult), nil
 }
 
+// GetDBType implements method in statedb.IndexCapable interface
+func (vdb *VersionedDB) GetDBType() string {
+	return "couchdb"
+}
+
+// ProcessIndexesForChaincodeDeploy implements method in statedb.IndexCapable interface.
+// The fields of the indexes are wrapped like the fields of the queries of the chaincode,
+// and the indexes which already exist are left untouched
+func (vdb *VersionedDB) ProcessIndexesForChaincodeDeploy(namespace string, fileEntries map[string][]byte) error {
+	var failed []string
+	for _, fileName := range util.GetSortedKeys(fileEntries) {
+		indexDefinition, err := ApplyIndexWrapper(string(fileEntries[fileName]))
+		if err != nil {
+			logger.Errorf("Invalid index definition [%s] of chaincode [%s]: %s", fileName, namespace, err)
+			failed = append(failed, fileName)
+			continue
+		}
+		resp, err := vdb.db.CreateIndex(indexDefinition)
+		if err != nil {
+			logger.Errorf("Error creating index [%s] of chaincode [%s] in database [%s]: %s", fileName, namespace, vdb.dbName, err)
+			failed = append(failed, fileName)
+			continue
+		}
+		logger.Infof("Index [%s] of chaincode [%s] in database [%s]: %s", resp.Name, namespace, vdb.dbName, resp.Result)
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("error creating the indexes %v of chaincode [%s], see the logs for details", failed, namespace)
+	}
+	return nil
+}
+
 // ApplyUpdates implements method in VersionedDB interface
 func (vdb *VersionedDB) ApplyUpdates(batch *statedb.UpdateBatch, height *version.Height) error {
 
@@ -60,6 +60,17 @@ type VersionedDB interface {
 	Close()
 }
 
+// IndexCapable is implemented by the VersionedDBs which support the indexes
+// shipped by the chaincodes in their packages
+type IndexCapable interface {
+	// GetDBType returns the type of the db, which names the directory of its
+	// artifacts under META-INF/statedb in the packages of the chaincodes
+	GetDBType() string
+	// ProcessIndexesForChaincodeDeploy creates the indexes defined by the given
+	// files, keyed by their names, for the namespace of a chaincode
+	ProcessIndexesForChaincodeDeploy(namespace string, fileEntries map[string][]byte) error
+}
+
 // CompositeKey encloses Namespace and Key components
 type CompositeKey struct {
 	Namespace string
@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"errors"
 
+	"github.com/hyperledger/fabric/common/crypto/keyring"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
@@ -37,19 +38,37 @@ var savePointKey = []byte{0x00}
 // VersionedDBProvider implements interface VersionedDBProvider
 type VersionedDBProvider struct {
 	dbProvider *leveldbhelper.Provider
+	keyring    *keyring.Keyring
 }
 
 // NewVersionedDBProvider instantiates VersionedDBProvider
 func NewVersionedDBProvider() *VersionedDBProvider {
+	return NewVersionedDBProviderWithKeyring(nil)
+}
+
+// NewVersionedDBProviderWithKeyring instantiates VersionedDBProvider whose values are encrypted
+// with the keyring, if any. The keys stay in plaintext, so that they can be range scanned, and the
+// values written before are still read in plaintext
+func NewVersionedDBProviderWithKeyring(kr *keyring.Keyring) *VersionedDBProvider {
 	dbPath := ledgerconfig.GetStateLevelDBPath()
 	logger.Debugf("constructing VersionedDBProvider dbPath=%s", dbPath)
-	dbProvider := leveldbhelper.NewProvider(&leveldbhelper.Conf{DBPath: dbPath})
-	return &VersionedDBProvider{dbProvider}
+	dbProvider := leveldbhelper.NewProvider(ledgerconfig.GetLevelDBConf(dbPath))
+	return &VersionedDBProvider{dbProvider, kr}
 }
 
 // GetDBHandle gets the handle to a named database
 func (provider *VersionedDBProvider) GetDBHandle(dbName string) (statedb.VersionedDB, error) {
-	return newVersionedDB(provider.dbProvider.GetDBHandle(dbName), dbName), nil
+	return newVersionedDB(provider.dbProvider.GetDBHandle(dbName), dbName, provider.keyring), nil
+}
+
+// Compact compacts the underlying db
+func (provider *VersionedDBProvider) Compact() error {
+	return provider.dbProvider.Compact()
+}
+
+// Drop deletes all the keys of the named db, which must not be in use
+func (provider *VersionedDBProvider) Drop(dbName string) error {
+	return provider.dbProvider.GetDBHandle(dbName).DeleteAll()
 }
 
 // Close closes the underlying db
@@ -59,13 +78,14 @@ func (provider *VersionedDBProvider) Close() {
 
 // VersionedDB implements VersionedDB interface
 type versionedDB struct {
-	db     *leveldbhelper.DBHandle
-	dbName string
+	db      *leveldbhelper.DBHandle
+	dbName  string
+	keyring *keyring.Keyring
 }
 
 // newVersionedDB constructs an instance of VersionedDB
-func newVersionedDB(db *leveldbhelper.DBHandle, dbName string) *versionedDB {
-	return &versionedDB{db, dbName}
+func newVersionedDB(db *leveldbhelper.DBHandle, dbName string, kr *keyring.Keyring) *versionedDB {
+	return &versionedDB{db, dbName, kr}
 }
 
 // Open implements method in VersionedDB interface
@@ -95,7 +115,10 @@ func (vdb *versionedDB) GetState(namespace string, key string) (*statedb.Version
 	if dbVal == nil {
 		return nil, nil
 	}
-	val, ver := statedb.DecodeValue(dbVal)
+	val, ver, err := decodeValue(vdb.keyring, valueLocation(vdb.dbName, compositeKey), dbVal)
+	if err != nil {
+		return nil, err
+	}
 	return &statedb.VersionedValue{Value: val, Version: ver}, nil
 }
 
@@ -122,7 +145,7 @@ func (vdb *versionedDB) GetStateRangeScanIterator(namespace string, startKey str
 		compositeEndKey[len(compositeEndKey)-1] = lastKeyIndicator
 	}
 	dbItr := vdb.db.GetIterator(compositeStartKey, compositeEndKey)
-	return newKVScanner(namespace, dbItr), nil
+	return newKVScanner(vdb.dbName, namespace, dbItr, vdb.keyring), nil
 }
 
 // ExecuteQuery implements method in VersionedDB interface
@@ -143,7 +166,11 @@ func (vdb *versionedDB) ApplyUpdates(batch *statedb.UpdateBatch, height *version
 			if vv.Value == nil {
 				dbBatch.Delete(compositeKey)
 			} else {
-				dbBatch.Put(compositeKey, statedb.EncodeValue(vv.Value, vv.Version))
+				dbVal, err := encodeValue(vdb.keyring, valueLocation(vdb.dbName, compositeKey), vv.Value, vv.Version)
+				if err != nil {
+					return err
+				}
+				dbBatch.Put(compositeKey, dbVal)
 			}
 		}
 	}
@@ -177,13 +204,47 @@ func splitCompositeKey(compositeKey []byte) (string, string) {
 	return string(split[0]), string(split[1])
 }
 
+// valueLocation returns the location of a value, the database of its channel and its key, to which
+// its encryption is bound. An encrypted value copied to another key or channel fails to decrypt
+func valueLocation(dbName string, compositeKey []byte) []byte {
+	return append(append([]byte(dbName), compositeKeySep...), compositeKey...)
+}
+
+// encodeValue encodes the value with its version, encrypted with the keyring if any for its location
+func encodeValue(kr *keyring.Keyring, location []byte, value []byte, version *version.Height) ([]byte, error) {
+	dbVal := statedb.EncodeValue(value, version)
+	if kr == nil {
+		return dbVal, nil
+	}
+	return kr.Encrypt(dbVal, location)
+}
+
+// decodeValue decodes the value and its version, decrypting them if they were encrypted. The encoded
+// versions start with the lengths of the block and transaction numbers, which never match the header
+// of the encrypted values
+func decodeValue(kr *keyring.Keyring, location []byte, dbVal []byte) ([]byte, *version.Height, error) {
+	if keyring.IsEncrypted(dbVal) {
+		if kr == nil {
+			return nil, nil, errors.New("state value is encrypted but no keyring is configured")
+		}
+		var err error
+		if dbVal, err = kr.Decrypt(dbVal, location); err != nil {
+			return nil, nil, err
+		}
+	}
+	val, ver := statedb.DecodeValue(dbVal)
+	return val, ver, nil
+}
+
 type kvScanner struct {
+	dbName    string
 	namespace string
 	dbItr     iterator.Iterator
+	keyring   *keyring.Keyring
 }
 
-func newKVScanner(namespace string, dbItr iterator.Iterator) *kvScanner {
-	return &kvScanner{namespace, dbItr}
+func newKVScanner(dbName string, namespace string, dbItr iterator.Iterator, kr *keyring.Keyring) *kvScanner {
+	return &kvScanner{dbName, namespace, dbItr, kr}
 }
 
 func (scanner *kvScanner) Next() (statedb.QueryResult, error) {
@@ -195,7 +256,10 @@ func (scanner *kvScanner) Next() (statedb.QueryResult, error) {
 	dbValCopy := make([]byte, len(dbVal))
 	copy(dbValCopy, dbVal)
 	_, key := splitCompositeKey(dbKey)
-	value, version := statedb.DecodeValue(dbValCopy)
+	value, version, err := decodeValue(scanner.keyring, valueLocation(scanner.dbName, dbKey), dbValCopy)
+	if err != nil {
+		return nil, err
+	}
 	return &statedb.VersionedKV{
 		CompositeKey:   statedb.CompositeKey{Namespace: scanner.namespace, Key: key},
 		VersionedValue: statedb.VersionedValue{Value: value, Version: version}}, nil
@@ -17,9 +17,13 @@ limitations under the License.
 package stateleveldb
 
 import (
+	"bytes"
 	"os"
 	"testing"
 
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric/common/crypto/keyring"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/commontests"
@@ -56,6 +60,18 @@ func TestIterator(t *testing.T) {
 	commontests.TestIterator(t, env.DBProvider)
 }
 
+func BenchmarkApplyUpdates(b *testing.B) {
+	env := NewTestVDBEnv(b)
+	defer env.Cleanup()
+	commontests.BenchmarkApplyUpdates(b, env.DBProvider)
+}
+
+func BenchmarkGetState(b *testing.B) {
+	env := NewTestVDBEnv(b)
+	defer env.Cleanup()
+	commontests.BenchmarkGetState(b, env.DBProvider)
+}
+
 func TestEncodeDecodeValueAndVersion(t *testing.T) {
 	testValueAndVersionEncodeing(t, []byte("value1"), version.NewHeight(1, 2))
 	testValueAndVersionEncodeing(t, []byte{}, version.NewHeight(50, 50))
@@ -68,6 +84,69 @@ func testValueAndVersionEncodeing(t *testing.T, value []byte, version *version.H
 	testutil.AssertEquals(t, ver, version)
 }
 
+func newTestKeyring(t *testing.T) *keyring.Keyring {
+	csp, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	testutil.AssertNoError(t, err, "")
+	key, err := csp.KeyGen(&bccsp.AES256KeyGenOpts{Temporary: true})
+	testutil.AssertNoError(t, err, "")
+	kr, err := keyring.New(csp, "key1", map[string]bccsp.Key{"key1": key})
+	testutil.AssertNoError(t, err, "")
+	return kr
+}
+
+func TestEncryption(t *testing.T) {
+	removeDBPath(t, "TestEncryption")
+	defer removeDBPath(t, "TestEncryption")
+	kr := newTestKeyring(t)
+
+	// The values written before the encryption is enabled stay in plaintext
+	provider := NewVersionedDBProvider()
+	db, _ := provider.GetDBHandle("testencryption")
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns", "key1", []byte("value1"), version.NewHeight(1, 1))
+	db.ApplyUpdates(batch, version.NewHeight(1, 1))
+	provider.Close()
+
+	provider = NewVersionedDBProviderWithKeyring(kr)
+	commontests.TestBasicRW(t, provider)
+	commontests.TestIterator(t, provider)
+	db, _ = provider.GetDBHandle("testencryption")
+	batch = statedb.NewUpdateBatch()
+	batch.Put("ns", "key2", []byte("value2"), version.NewHeight(2, 1))
+	db.ApplyUpdates(batch, version.NewHeight(2, 1))
+
+	dbVal, _ := db.(*versionedDB).db.Get(constructCompositeKey("ns", "key2"))
+	testutil.AssertEquals(t, keyring.IsEncrypted(dbVal), true)
+	testutil.AssertEquals(t, bytes.Contains(dbVal, []byte("value2")), false)
+
+	// An encrypted value copied to another key does not decrypt
+	db.(*versionedDB).db.Put(constructCompositeKey("ns", "key3"), dbVal, true)
+	_, err := db.GetState("ns", "key3")
+	testutil.AssertError(t, err, "Should not decrypt a value moved to another key")
+	db.(*versionedDB).db.Delete(constructCompositeKey("ns", "key3"), true)
+
+	itr, err := db.GetStateRangeScanIterator("ns", "", "")
+	testutil.AssertNoError(t, err, "")
+	for i, expected := range []string{"value1", "value2"} {
+		result, err := itr.Next()
+		testutil.AssertNoError(t, err, "")
+		testutil.AssertEquals(t, result.(*statedb.VersionedKV).Value, []byte(expected))
+		testutil.AssertEquals(t, result.(*statedb.VersionedKV).Version, version.NewHeight(uint64(i+1), 1))
+	}
+	itr.Close()
+	provider.Close()
+
+	// The encrypted values cannot be read without the keyring
+	provider = NewVersionedDBProvider()
+	defer provider.Close()
+	db, _ = provider.GetDBHandle("testencryption")
+	vv, err := db.GetState("ns", "key1")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, vv.Value, []byte("value1"))
+	_, err = db.GetState("ns", "key2")
+	testutil.AssertError(t, err, "Should not read an encrypted value without the keyring")
+}
+
 func TestCompositeKey(t *testing.T) {
 	testCompositeKey(t, "ledger1", "ns", "key")
 	testCompositeKey(t, "ledger2", "ns", "")
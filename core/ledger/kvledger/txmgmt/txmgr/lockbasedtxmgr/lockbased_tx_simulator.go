@@ -77,6 +77,16 @@ func (s *lockBasedTxSimulator) GetTxSimulationResults() ([]byte, error) {
 	return s.rwsetBuilder.GetTxReadWriteSet().ToProtoBytes()
 }
 
+// GetHeight implements method in interface `ledger.TxSimulator`
+func (s *lockBasedTxSimulator) GetHeight() (uint64, error) {
+	s.helper.checkDone()
+	savepoint, err := s.helper.txmgr.GetLastSavepoint()
+	if err != nil || savepoint == nil {
+		return 0, err
+	}
+	return savepoint.BlockNum + 1, nil
+}
+
 // ExecuteUpdate implements method in interface `ledger.TxSimulator`
 func (s *lockBasedTxSimulator) ExecuteUpdate(query string) error {
 	return errors.New("Not supported")
@@ -18,6 +18,7 @@ package lockbasedtxmgr
 
 import (
 	"sync"
+	"sync/atomic"
 
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/core/ledger"
@@ -33,17 +34,27 @@ var logger = flogging.MustGetLogger("lockbasedtxmgr")
 // LockBasedTxMgr a simple implementation of interface `txmgmt.TxMgr`.
 // This implementation uses a read-write lock to prevent conflicts between transaction simulation and committing
 type LockBasedTxMgr struct {
-	db           statedb.VersionedDB
-	validator    validator.Validator
-	batch        *statedb.UpdateBatch
-	currentBlock *common.Block
-	commitRWLock sync.RWMutex
+	ledgerID       string
+	db             statedb.VersionedDB
+	validator      validator.Validator
+	stateListeners map[string]ledger.StateListener
+	batch          *statedb.UpdateBatch
+	currentBlock   *common.Block
+	commitRWLock   sync.RWMutex
+	// rangeQueryHashing is set to 1 when the results of the range queries are summarized as merkle hashes
+	rangeQueryHashing int32
 }
 
 // NewLockBasedTxMgr constructs a new instance of NewLockBasedTxMgr
 func NewLockBasedTxMgr(db statedb.VersionedDB) *LockBasedTxMgr {
+	return NewLockBasedTxMgrWithStateListeners("", db, nil)
+}
+
+// NewLockBasedTxMgrWithStateListeners constructs a new instance of NewLockBasedTxMgr for the given ledger,
+// which notifies the state listeners, keyed by namespace, of the updates of their namespace it commits
+func NewLockBasedTxMgrWithStateListeners(ledgerID string, db statedb.VersionedDB, stateListeners map[string]ledger.StateListener) *LockBasedTxMgr {
 	db.Open()
-	return &LockBasedTxMgr{db: db, validator: statebasedval.NewValidator(db)}
+	return &LockBasedTxMgr{ledgerID: ledgerID, db: db, validator: statebasedval.NewValidator(db), stateListeners: stateListeners}
 }
 
 // GetLastSavepoint returns the block num recorded in savepoint,
@@ -79,6 +90,19 @@ func (txmgr *LockBasedTxMgr) ValidateAndPrepare(block *common.Block, doMVCCValid
 	return err
 }
 
+// SetRangeQueryHashing implements method in interface `txmgmt.TxMgr`
+func (txmgr *LockBasedTxMgr) SetRangeQueryHashing(enabled bool) {
+	var flag int32
+	if enabled {
+		flag = 1
+	}
+	atomic.StoreInt32(&txmgr.rangeQueryHashing, flag)
+}
+
+func (txmgr *LockBasedTxMgr) isRangeQueryHashingEnabled() bool {
+	return atomic.LoadInt32(&txmgr.rangeQueryHashing) == 1
+}
+
 // Shutdown implements method in interface `txmgmt.TxMgr`
 func (txmgr *LockBasedTxMgr) Shutdown() {
 	txmgr.db.Close()
@@ -99,9 +123,28 @@ func (txmgr *LockBasedTxMgr) Commit() error {
 		return err
 	}
 	logger.Debugf("Updates committed to state database")
+	txmgr.invokeStateListeners()
 	return nil
 }
 
+// invokeStateListeners notifies the state listeners of the updates of their namespace
+// in the batch committed. The failures of the listeners do not fail the commit
+func (txmgr *LockBasedTxMgr) invokeStateListeners() {
+	for namespace, listener := range txmgr.stateListeners {
+		updates := txmgr.batch.GetUpdates(namespace)
+		if len(updates) == 0 {
+			continue
+		}
+		stateUpdates := make(map[string][]byte, len(updates))
+		for key, vv := range updates {
+			stateUpdates[key] = vv.Value
+		}
+		if err := listener.HandleStateUpdates(txmgr.ledgerID, stateUpdates); err != nil {
+			logger.Errorf("Channel [%s]: Error handling the state updates of namespace [%s]: %s", txmgr.ledgerID, namespace, err)
+		}
+	}
+}
+
 // Rollback implements method in interface `txmgmt.TxMgr`
 func (txmgr *LockBasedTxMgr) Rollback() {
 	txmgr.batch = nil
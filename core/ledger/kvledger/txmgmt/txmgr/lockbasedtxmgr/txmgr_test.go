@@ -25,9 +25,14 @@ import (
 
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/stateleveldb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
 	ledgertestutil "github.com/hyperledger/fabric/core/ledger/testutil"
 	"github.com/hyperledger/fabric/protos/ledger/queryresult"
+	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
 )
 
 func TestMain(m *testing.M) {
@@ -117,6 +122,36 @@ func testTxSimulatorWithExistingData(t *testing.T, env testEnv) {
 	testutil.AssertEquals(t, vv.Version, version.NewHeight(1, 0))
 }
 
+func TestTxSimulatorHeight(t *testing.T) {
+	for _, testEnv := range testEnvs {
+		t.Run(testEnv.getName(), func(t *testing.T) {
+			testLedgerID := "testtxsimulatorheight"
+			testEnv.init(t, testLedgerID)
+			testTxSimulatorHeight(t, testEnv)
+			testEnv.cleanup()
+		})
+	}
+}
+
+func testTxSimulatorHeight(t *testing.T, env testEnv) {
+	txMgr := env.getTxMgr()
+	txMgrHelper := newTxMgrTestHelper(t, txMgr)
+	s1, _ := txMgr.NewTxSimulator()
+	height, err := s1.GetHeight()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, height, uint64(0))
+	s1.SetState("ns1", "key1", []byte("value1"))
+	txRWSet1, _ := s1.GetTxSimulationResults()
+	txMgrHelper.validateAndCommitRWSet(txRWSet1)
+
+	// the block generator starts with block 1
+	s2, _ := txMgr.NewTxSimulator()
+	height, err = s2.GetHeight()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, height, uint64(2))
+	s2.Done()
+}
+
 func TestTxValidation(t *testing.T) {
 	for _, testEnv := range testEnvs {
 		t.Logf("Running test for TestEnv = %s", testEnv.getName())
@@ -621,3 +656,99 @@ func TestValidateKey(t *testing.T) {
 		testEnv.cleanup()
 	}
 }
+
+type mockStateListener struct {
+	ledgerID     string
+	stateUpdates map[string][]byte
+}
+
+func (l *mockStateListener) HandleStateUpdates(ledgerID string, stateUpdates map[string][]byte) error {
+	l.ledgerID = ledgerID
+	l.stateUpdates = stateUpdates
+	return nil
+}
+
+func TestStateListener(t *testing.T) {
+	testLedgerID := "teststatelistener"
+	testDBEnv := stateleveldb.NewTestVDBEnv(t)
+	defer testDBEnv.Cleanup()
+	testDB, err := testDBEnv.DBProvider.GetDBHandle(testLedgerID)
+	testutil.AssertNoError(t, err, "")
+
+	listener := &mockStateListener{}
+	txMgr := NewLockBasedTxMgrWithStateListeners(testLedgerID, testDB, map[string]ledger.StateListener{"ns1": listener})
+	defer txMgr.Shutdown()
+	txMgrHelper := newTxMgrTestHelper(t, txMgr)
+
+	s1, _ := txMgr.NewTxSimulator()
+	s1.SetState("ns2", "key1", []byte("value1"))
+	s1.Done()
+	txRWSet1, _ := s1.GetTxSimulationResults()
+	txMgrHelper.validateAndCommitRWSet(txRWSet1)
+	testutil.AssertNil(t, listener.stateUpdates)
+
+	s2, _ := txMgr.NewTxSimulator()
+	s2.SetState("ns1", "key1", []byte("value1"))
+	s2.SetState("ns1", "key2", []byte("value2"))
+	s2.SetState("ns2", "key2", []byte("value2"))
+	s2.Done()
+	txRWSet2, _ := s2.GetTxSimulationResults()
+	txMgrHelper.validateAndCommitRWSet(txRWSet2)
+	testutil.AssertEquals(t, listener.ledgerID, testLedgerID)
+	testutil.AssertEquals(t, listener.stateUpdates, map[string][]byte{"key1": []byte("value1"), "key2": []byte("value2")})
+
+	s3, _ := txMgr.NewTxSimulator()
+	s3.DeleteState("ns1", "key1")
+	s3.Done()
+	txRWSet3, _ := s3.GetTxSimulationResults()
+	txMgrHelper.validateAndCommitRWSet(txRWSet3)
+	testutil.AssertEquals(t, listener.stateUpdates, map[string][]byte{"key1": nil})
+}
+
+func TestRangeQueryHashing(t *testing.T) {
+	testDBEnv := stateleveldb.NewTestVDBEnv(t)
+	defer testDBEnv.Cleanup()
+	testDB, err := testDBEnv.DBProvider.GetDBHandle("testrangequeryhashing")
+	testutil.AssertNoError(t, err, "")
+	txMgr := NewLockBasedTxMgr(testDB)
+	defer txMgr.Shutdown()
+	txMgrHelper := newTxMgrTestHelper(t, txMgr)
+
+	// more keys than the maximum degree of the merkle tree
+	numKeys := int(ledgerconfig.GetMaxDegreeQueryReadsHashing()) + 10
+	s1, _ := txMgr.NewTxSimulator()
+	for i := 0; i < numKeys; i++ {
+		s1.SetState("ns1", fmt.Sprintf("key_%03d", i), []byte(fmt.Sprintf("value_%03d", i)))
+	}
+	s1.Done()
+	txRWSet1, _ := s1.GetTxSimulationResults()
+	txMgrHelper.validateAndCommitRWSet(txRWSet1)
+
+	rangeQueryInfo := func() *kvrwset.RangeQueryInfo {
+		s, _ := txMgr.NewTxSimulator()
+		itr, err := s.GetStateRangeScanIterator("ns1", "", "")
+		testutil.AssertNoError(t, err, "")
+		for {
+			kv, _ := itr.Next()
+			if kv == nil {
+				break
+			}
+		}
+		itr.Close()
+		s.Done()
+		simResBytes, _ := s.GetTxSimulationResults()
+		txRWSet := &rwsetutil.TxRwSet{}
+		testutil.AssertNoError(t, txRWSet.FromProtoBytes(simResBytes), "")
+		return txRWSet.NsRwSets[0].KvRwSet.RangeQueriesInfo[0]
+	}
+
+	// without the capability, every key read is recorded
+	rqi := rangeQueryInfo()
+	testutil.AssertNil(t, rqi.GetReadsMerkleHashes())
+	testutil.AssertEquals(t, len(rqi.GetRawReads().KvReads), numKeys)
+
+	txMgr.SetRangeQueryHashing(true)
+	rqi = rangeQueryInfo()
+	testutil.AssertNil(t, rqi.GetRawReads())
+	testutil.AssertNotNil(t, rqi.GetReadsMerkleHashes())
+}
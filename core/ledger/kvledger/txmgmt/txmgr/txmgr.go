@@ -32,5 +32,6 @@ type TxMgr interface {
 	CommitLostBlock(block *common.Block) error
 	Commit() error
 	Rollback()
+	SetRangeQueryHashing(enabled bool)
 	Shutdown()
 }
@@ -18,6 +18,7 @@ package statebasedval
 
 import (
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/ledger/customtx"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
@@ -48,17 +49,35 @@ func (v *Validator) validateEndorserTX(envBytes []byte, doMVCCValidation bool, u
 	if err != nil {
 		return nil, peer.TxValidationCode_NIL_TXACTION, nil
 	}
+	return v.validateResults(respPayload.Results, doMVCCValidation, updates)
+}
+
+// validateCustomTX validates the simulation results of a custom transaction, returned by the
+// processor of its type. A nil read-write set and a valid code leave the state untouched
+func (v *Validator) validateCustomTX(processor customtx.Processor, payload *common.Payload, doMVCCValidation bool, updates *statedb.UpdateBatch) (*rwsetutil.TxRwSet, peer.TxValidationCode, error) {
+	results, err := processor.SimulationResults(payload)
+	if err != nil {
+		logger.Warningf("Error getting the simulation results of a custom transaction: %s", err)
+		return nil, peer.TxValidationCode_INVALID_OTHER_REASON, nil
+	}
+	if results == nil {
+		return nil, peer.TxValidationCode_VALID, nil
+	}
+	return v.validateResults(results, doMVCCValidation, updates)
+}
 
+// validateResults unmarshals the simulation results of a transaction and validates them against the
+// state, returning a nil read-write set if they are invalid
+func (v *Validator) validateResults(results []byte, doMVCCValidation bool, updates *statedb.UpdateBatch) (*rwsetutil.TxRwSet, peer.TxValidationCode, error) {
 	//preparation for extracting RWSet from transaction
 	txRWSet := &rwsetutil.TxRwSet{}
 
-	// Get the Result from the Action
-	// and then Unmarshal it into a TxReadWriteSet using custom unmarshalling
-
-	if err = txRWSet.FromProtoBytes(respPayload.Results); err != nil {
+	// Unmarshal the results into a TxReadWriteSet using custom unmarshalling
+	if err := txRWSet.FromProtoBytes(results); err != nil {
 		return nil, peer.TxValidationCode_INVALID_OTHER_REASON, nil
 	}
 
+	var err error
 	txResult := peer.TxValidationCode_VALID
 
 	//mvccvalidation, may invalidate transaction
@@ -113,14 +132,18 @@ func (v *Validator) ValidateAndPrepareBatch(block *common.Block, doMVCCValidatio
 
 		txType := common.HeaderType(chdr.Type)
 
-		if txType != common.HeaderType_ENDORSER_TRANSACTION {
+		var txRWSet *rwsetutil.TxRwSet
+		var txResult peer.TxValidationCode
+		if txType == common.HeaderType_ENDORSER_TRANSACTION {
+			txRWSet, txResult, err = v.validateEndorserTX(envBytes, doMVCCValidation, updates)
+		} else if processor := customtx.GetProcessor(txType); processor != nil {
+			txRWSet, txResult, err = v.validateCustomTX(processor, payload, doMVCCValidation, updates)
+		} else {
 			logger.Debugf("Skipping mvcc validation for Block [%d] Transaction index [%d] because, the transaction type is [%s]",
 				block.Header.Number, txIndex, txType)
 			continue
 		}
 
-		txRWSet, txResult, err := v.validateEndorserTX(envBytes, doMVCCValidation, updates)
-
 		if err != nil {
 			return nil, err
 		}
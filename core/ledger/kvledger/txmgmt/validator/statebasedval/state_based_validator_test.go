@@ -17,10 +17,12 @@ limitations under the License.
 package statebasedval
 
 import (
+	"errors"
 	"os"
 	"testing"
 
 	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/core/ledger/customtx"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/stateleveldb"
@@ -29,6 +31,7 @@ import (
 	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
 	"github.com/hyperledger/fabric/protos/peer"
+	putils "github.com/hyperledger/fabric/protos/utils"
 	"github.com/spf13/viper"
 )
 
@@ -267,3 +270,73 @@ func buildTestHashResults(t *testing.T, maxDegree int, kvReads []*kvrwset.KVRead
 	testutil.AssertNotNil(t, h)
 	return h
 }
+
+// rwsetProcessor processes custom transactions whose data is their read-write set
+type rwsetProcessor struct{}
+
+func (*rwsetProcessor) Validate(channelID string, payload *common.Payload) peer.TxValidationCode {
+	return peer.TxValidationCode_VALID
+}
+
+func (*rwsetProcessor) SimulationResults(payload *common.Payload) ([]byte, error) {
+	if string(payload.Data) == "barf" {
+		return nil, errors.New("unreadable transaction")
+	}
+	return payload.Data, nil
+}
+
+func TestCustomTxValidation(t *testing.T) {
+	const customTx, unknownTx = common.HeaderType(100), common.HeaderType(101)
+	customtx.Register(customTx, &rwsetProcessor{})
+	defer customtx.Unregister(customTx)
+
+	testDBEnv := stateleveldb.NewTestVDBEnv(t)
+	defer testDBEnv.Cleanup()
+	db, err := testDBEnv.DBProvider.GetDBHandle("TestDB")
+	testutil.AssertNoError(t, err, "")
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 0))
+	db.ApplyUpdates(batch, version.NewHeight(1, 0))
+	validator := NewValidator(db)
+
+	rwsetBuilder1 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder1.AddToReadSet("ns1", "key1", version.NewHeight(1, 0))
+	rwsetBuilder1.AddToWriteSet("ns1", "key1", []byte("value1_new"))
+	rwsetBuilder2 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder2.AddToReadSet("ns1", "key1", version.NewHeight(1, 0))
+	rwsetBuilder2.AddToWriteSet("ns1", "key2", []byte("value2"))
+	results := func(rwsetBuilder *rwsetutil.RWSetBuilder) []byte {
+		sr, err := rwsetBuilder.GetTxReadWriteSet().ToProtoBytes()
+		testutil.AssertNoError(t, err, "")
+		return sr
+	}
+
+	block := common.NewBlock(2, nil)
+	for _, tx := range []struct {
+		txType common.HeaderType
+		data   []byte
+	}{
+		{customTx, results(rwsetBuilder1)},
+		{customTx, results(rwsetBuilder2)},
+		{customTx, nil},
+		{customTx, []byte("barf")},
+		{unknownTx, []byte("barf")},
+	} {
+		env := &common.Envelope{Payload: putils.MarshalOrPanic(&common.Payload{
+			Header: &common.Header{ChannelHeader: putils.MarshalOrPanic(&common.ChannelHeader{Type: int32(tx.txType)})},
+			Data:   tx.data,
+		})}
+		block.Data.Data = append(block.Data.Data, putils.MarshalOrPanic(env))
+	}
+
+	updates, err := validator.ValidateAndPrepareBatch(block, true)
+	testutil.AssertNoError(t, err, "")
+	txsFltr := util.TxValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	testutil.AssertEquals(t, txsFltr.IsValid(0), true)
+	testutil.AssertEquals(t, txsFltr.Flag(1), peer.TxValidationCode_MVCC_READ_CONFLICT)
+	testutil.AssertEquals(t, txsFltr.IsValid(2), true)
+	testutil.AssertEquals(t, txsFltr.Flag(3), peer.TxValidationCode_INVALID_OTHER_REASON)
+	testutil.AssertEquals(t, txsFltr.IsValid(4), true)
+	testutil.AssertEquals(t, updates.Get("ns1", "key1").Value, []byte("value1_new"))
+	testutil.AssertNil(t, updates.Get("ns1", "key2"))
+}
@@ -34,6 +34,14 @@ type PeerLedgerProvider interface {
 	Exists(ledgerID string) (bool, error)
 	// List lists the ids of the existing ledgers
 	List() ([]string, error)
+	// Lost tells whether the blocks of the existing ledger with the given id are missing from the block storage,
+	// as if the block files were lost, in which case the ledger cannot be opened
+	Lost(ledgerID string) (bool, error)
+	// Drop removes the data of the existing ledger with the given id, which must not be open, so that the ledger
+	// can be created again
+	Drop(ledgerID string) error
+	// Compact triggers a compaction of the local databases backing the ledgers
+	Compact() error
 	// Close closes the PeerLedgerProvider
 	Close()
 }
@@ -62,8 +70,16 @@ type PeerLedger interface {
 	// A client can obtain more than one 'HistoryQueryExecutor's for parallel execution.
 	// Any synchronization should be performed at the implementation level if required
 	NewHistoryQueryExecutor() (HistoryQueryExecutor, error)
+	// NewHistoricalQueryExecutor gives handle to a query executor reading the state as of the given
+	// height of the ledger, i.e. after the commit of the blocks below the height. It requires the
+	// history database
+	NewHistoricalQueryExecutor(height uint64) (HistoricalQueryExecutor, error)
 	//Prune prunes the blocks/transactions that satisfy the given policy
 	Prune(policy commonledger.PrunePolicy) error
+	// SetRangeQueryHashing sets whether the transaction simulators summarize the results of the
+	// range queries as merkle hashes in the read sets, rather than recording every key read, as
+	// enabled by the capabilities of the channel
+	SetRangeQueryHashing(enabled bool)
 }
 
 // ValidatedLedger represents the 'final ledger' after filtering out invalid transactions from PeerLedger.
@@ -104,6 +120,14 @@ type HistoryQueryExecutor interface {
 	GetHistoryForKey(namespace string, key string) (commonledger.ResultsIterator, error)
 }
 
+// HistoricalQueryExecutor executes the queries against the state as of a past height of the ledger.
+// The history of a key is limited to the modifications below the height. Range scans and rich
+// queries are not supported, as the history database is only indexed by key
+type HistoricalQueryExecutor interface {
+	QueryExecutor
+	HistoryQueryExecutor
+}
+
 // TxSimulator simulates a transaction on a consistent snapshot of the 'as recent state as possible'
 // Set* methods are for supporting KV-based data model. ExecuteUpdate method is for supporting a rich datamodel and query support
 type TxSimulator interface {
@@ -124,4 +148,15 @@ type TxSimulator interface {
 	// Different ledger implementation (or configurations of a single implementation) may want to represent the above two pieces
 	// of information in different way in order to support different data-models or optimize the information representations.
 	GetTxSimulationResults() ([]byte, error)
+	// GetHeight returns the height of the ledger, i.e. the number of blocks committed to the state
+	// the simulation reads from. It has to be called before the simulation results are retrieved
+	GetHeight() (uint64, error)
+}
+
+// StateListener allows custom code to perform additional work once the state updates
+// of the namespace it is registered for are committed
+type StateListener interface {
+	// HandleStateUpdates is invoked with the updates of the namespace committed to the
+	// state of the ledger, keyed by key, where the deleted keys have a nil value
+	HandleStateUpdates(ledgerID string, stateUpdates map[string][]byte) error
 }
@@ -19,17 +19,30 @@ package ledgerconfig
 import (
 	"path/filepath"
 
+	"github.com/hyperledger/fabric/common/ledger/blkstorage/fsblkstorage"
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
 	"github.com/hyperledger/fabric/core/config"
 	"github.com/spf13/viper"
 )
 
+// Supported values for the ledger.state.stateDatabase property
+const (
+	StateDatabaseGoLevelDB = "goleveldb"
+	StateDatabaseCouchDB   = "CouchDB"
+)
+
 //IsCouchDBEnabled exposes the useCouchDB variable
 func IsCouchDBEnabled() bool {
+	return GetStateDatabase() == StateDatabaseCouchDB
+}
+
+// GetStateDatabase returns the configured state database backend, defaulting to goleveldb
+func GetStateDatabase() string {
 	stateDatabase := viper.GetString("ledger.state.stateDatabase")
-	if stateDatabase == "CouchDB" {
-		return true
+	if stateDatabase == "" {
+		return StateDatabaseGoLevelDB
 	}
-	return false
+	return stateDatabase
 }
 
 // GetRootPath returns the filesystem path.
@@ -59,11 +72,42 @@ func GetBlockStorePath() string {
 	return filepath.Join(GetRootPath(), "chains")
 }
 
+// GetKeyringPath returns the path of the keyring encrypting the block stores, the state level db and
+// the history level db, or an empty string if they are not encrypted
+func GetKeyringPath() string {
+	return config.GetPath("ledger.encryption.keyring")
+}
+
 // GetMaxBlockfileSize returns maximum size of the block file
 func GetMaxBlockfileSize() int {
 	return 64 * 1024 * 1024
 }
 
+// GetLevelDBConf returns the goleveldb configuration for the db at the given path.
+// The tuning options are shared by the state, history and block index level dbs
+func GetLevelDBConf(dbPath string) *leveldbhelper.Conf {
+	return &leveldbhelper.Conf{
+		DBPath:               dbPath,
+		BlockCacheCapacity:   int(viper.GetSizeInBytes("ledger.state.levelDBConfig.blockCacheSize")),
+		WriteBuffer:          int(viper.GetSizeInBytes("ledger.state.levelDBConfig.writeBufferSize")),
+		BloomFilterBits:      viper.GetInt("ledger.state.levelDBConfig.bloomFilterBits"),
+		CompactionTableSize:  int(viper.GetSizeInBytes("ledger.state.levelDBConfig.compactionTableSize")),
+		CompactionL0Trigger:  viper.GetInt("ledger.state.levelDBConfig.compactionL0Trigger"),
+		CompactionInterval:   viper.GetDuration("ledger.state.levelDBConfig.compactionInterval"),
+		CompactionThroughput: int(viper.GetSizeInBytes("ledger.state.levelDBConfig.compactionThroughput")),
+	}
+}
+
+// GetBlockStoreScaleConf returns the tuning of the block stores for very large channels
+func GetBlockStoreScaleConf() *fsblkstorage.ScaleConf {
+	return &fsblkstorage.ScaleConf{
+		IndexShardSize: uint64(viper.GetInt("ledger.blockchain.indexShardSize")),
+		MaxOpenShards:  viper.GetInt("ledger.blockchain.maxOpenIndexShards"),
+		MmapReads:      viper.GetBool("ledger.blockchain.mmapReads"),
+		MaxMappedFiles: viper.GetInt("ledger.blockchain.maxMappedFiles"),
+	}
+}
+
 //GetQueryLimit exposes the queryLimit variable
 func GetQueryLimit() int {
 	queryLimit := viper.GetInt("ledger.state.couchDBConfig.queryLimit")
@@ -79,12 +123,6 @@ func IsHistoryDBEnabled() bool {
 	return viper.GetBool("ledger.history.enableHistoryDatabase")
 }
 
-// IsQueryReadsHashingEnabled enables or disables computing of hash
-// of range query results for phantom item validation
-func IsQueryReadsHashingEnabled() bool {
-	return true
-}
-
 // GetMaxDegreeQueryReadsHashing return the maximum degree of the merkle tree for hashes of
 // of range query results for phantom item validation
 // For more details - see description in kvledger/txmgmt/rwset/query_results_helper.go
@@ -18,6 +18,7 @@ package ledgerconfig
 
 import (
 	"testing"
+	"time"
 
 	"github.com/hyperledger/fabric/common/ledger/testutil"
 	ledgertestutil "github.com/hyperledger/fabric/core/ledger/testutil"
@@ -46,6 +47,15 @@ func TestIsCouchDBEnabled(t *testing.T) {
 	testutil.AssertEquals(t, updatedValue, true) //test config returns true
 }
 
+func TestGetStateDatabase(t *testing.T) {
+	setUpCoreYAMLConfig()
+	defer ledgertestutil.ResetConfigToDefaultValues()
+	viper.Set("ledger.state.stateDatabase", "")
+	testutil.AssertEquals(t, GetStateDatabase(), StateDatabaseGoLevelDB)
+	viper.Set("ledger.state.stateDatabase", "CouchDB")
+	testutil.AssertEquals(t, GetStateDatabase(), StateDatabaseCouchDB)
+}
+
 func TestIsHistoryDBEnabledDefault(t *testing.T) {
 	setUpCoreYAMLConfig()
 	defaultValue := IsHistoryDBEnabled()
@@ -68,6 +78,49 @@ func TestIsHistoryDBEnabledFalse(t *testing.T) {
 	testutil.AssertEquals(t, updatedValue, false) //test config returns false
 }
 
+func TestGetLevelDBConf(t *testing.T) {
+	setUpCoreYAMLConfig()
+	conf := GetLevelDBConf("/tmp/leveldb")
+	testutil.AssertEquals(t, conf.DBPath, "/tmp/leveldb")
+	testutil.AssertEquals(t, conf.BlockCacheCapacity, 0)
+	testutil.AssertEquals(t, conf.CompactionInterval, time.Duration(0))
+
+	viper.Set("ledger.state.levelDBConfig.blockCacheSize", "8 MB")
+	viper.Set("ledger.state.levelDBConfig.bloomFilterBits", 10)
+	viper.Set("ledger.state.levelDBConfig.compactionInterval", "24h")
+	viper.Set("ledger.state.levelDBConfig.compactionThroughput", "10 MB")
+	defer func() {
+		viper.Set("ledger.state.levelDBConfig.blockCacheSize", 0)
+		viper.Set("ledger.state.levelDBConfig.bloomFilterBits", 0)
+		viper.Set("ledger.state.levelDBConfig.compactionInterval", "0s")
+		viper.Set("ledger.state.levelDBConfig.compactionThroughput", 0)
+	}()
+	conf = GetLevelDBConf("/tmp/leveldb")
+	testutil.AssertEquals(t, conf.BlockCacheCapacity, 8*1024*1024)
+	testutil.AssertEquals(t, conf.BloomFilterBits, 10)
+	testutil.AssertEquals(t, conf.CompactionInterval, 24*time.Hour)
+	testutil.AssertEquals(t, conf.CompactionThroughput, 10*1024*1024)
+}
+
+func TestGetBlockStoreScaleConf(t *testing.T) {
+	setUpCoreYAMLConfig()
+	conf := GetBlockStoreScaleConf()
+	testutil.AssertEquals(t, conf.IndexShardSize, uint64(0))
+	testutil.AssertEquals(t, conf.MaxOpenShards, 16)
+	testutil.AssertEquals(t, conf.MmapReads, false)
+	testutil.AssertEquals(t, conf.MaxMappedFiles, 64)
+
+	viper.Set("ledger.blockchain.indexShardSize", 1000000)
+	viper.Set("ledger.blockchain.mmapReads", true)
+	defer func() {
+		viper.Set("ledger.blockchain.indexShardSize", 0)
+		viper.Set("ledger.blockchain.mmapReads", false)
+	}()
+	conf = GetBlockStoreScaleConf()
+	testutil.AssertEquals(t, conf.IndexShardSize, uint64(1000000))
+	testutil.AssertEquals(t, conf.MmapReads, true)
+}
+
 func setUpCoreYAMLConfig() {
 	//call a helper method to load the core.yaml
 	ledgertestutil.SetupCoreYAMLConfig()
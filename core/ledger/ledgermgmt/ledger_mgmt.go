@@ -24,7 +24,9 @@ import (
 
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/cceventmgmt"
 	"github.com/hyperledger/fabric/core/ledger/kvledger"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
 	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/utils"
 )
@@ -43,6 +45,9 @@ var lock sync.Mutex
 var initialized bool
 var once sync.Once
 
+// commitLock is held by the commits to the opened ledgers, and exclusively by a backup
+var commitLock sync.RWMutex
+
 // Initialize initializes ledgermgmt
 func Initialize() {
 	once.Do(func() {
@@ -56,7 +61,9 @@ func initialize() {
 	defer lock.Unlock()
 	initialized = true
 	openedLedgers = make(map[string]ledger.PeerLedger)
-	provider, err := kvledger.NewProvider()
+	provider, err := kvledger.NewProviderWithStateListeners(map[string]ledger.StateListener{
+		cceventmgmt.LSCCNamespace: &cceventmgmt.KVLedgerLSCCStateListener{},
+	})
 	if err != nil {
 		panic(fmt.Errorf("Error in instantiating ledger provider: %s", err))
 	}
@@ -121,6 +128,59 @@ func GetLedgerIDs() ([]string, error) {
 	return ledgerProvider.List()
 }
 
+// IsLedgerLost tells whether the blocks of the ledger with the given id are missing from the block storage, in
+// which case the ledger cannot be opened
+func IsLedgerLost(id string) (bool, error) {
+	lock.Lock()
+	defer lock.Unlock()
+	if !initialized {
+		return false, ErrLedgerMgmtNotInitialized
+	}
+	return ledgerProvider.Lost(id)
+}
+
+// DropLedger removes the data of the ledger with the given id, which must not be opened, so that it can be created
+// again from its genesis block
+func DropLedger(id string) error {
+	lock.Lock()
+	defer lock.Unlock()
+	if !initialized {
+		return ErrLedgerMgmtNotInitialized
+	}
+	if _, ok := openedLedgers[id]; ok {
+		return ErrLedgerAlreadyOpened
+	}
+	logger.Infof("Dropping ledger [%s]", id)
+	return ledgerProvider.Drop(id)
+}
+
+// Compact compacts the local databases backing the ledgers
+func Compact() error {
+	lock.Lock()
+	defer lock.Unlock()
+	if !initialized {
+		return ErrLedgerMgmtNotInitialized
+	}
+	logger.Infof("Compacting ledger databases")
+	return ledgerProvider.Compact()
+}
+
+// Backup holds back the commits to all the ledgers while snapshot runs on the ledger data
+// under rootPath, so that the block stores and the databases are consistent with each other
+// in the snapshot. The commits in progress complete before snapshot is invoked
+func Backup(snapshot func(rootPath string) error) error {
+	lock.Lock()
+	defer lock.Unlock()
+	if !initialized {
+		return ErrLedgerMgmtNotInitialized
+	}
+	commitLock.Lock()
+	defer commitLock.Unlock()
+	logger.Infof("Holding back the commits for a backup of the ledgers")
+	defer logger.Infof("Resuming the commits after the backup of the ledgers")
+	return snapshot(ledgerconfig.GetRootPath())
+}
+
 // Close closes all the opened ledgers and any resources held for ledger management
 func Close() {
 	logger.Infof("Closing ledger mgmt")
@@ -147,6 +207,13 @@ type closableLedger struct {
 	ledger.PeerLedger
 }
 
+// Commit commits the block to the actual ledger, waiting for the completion of a backup in progress
+func (l *closableLedger) Commit(block *common.Block) error {
+	commitLock.RLock()
+	defer commitLock.RUnlock()
+	return l.PeerLedger.Commit(block)
+}
+
 // Close closes the actual ledger and removes the entries from opened ledgers map
 func (l *closableLedger) Close() {
 	lock.Lock()
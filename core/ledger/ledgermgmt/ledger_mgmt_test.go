@@ -19,12 +19,14 @@ package ledgermgmt
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"os"
 
 	"github.com/hyperledger/fabric/common/configtx/test"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
 	"github.com/spf13/viper"
 )
 
@@ -91,6 +93,47 @@ func TestLedgerMgmt(t *testing.T) {
 	Close()
 }
 
+func TestBackup(t *testing.T) {
+	InitializeTestEnv()
+	defer CleanupTestEnv()
+
+	gb, _ := test.MakeGenesisBlock(constructTestLedgerID(0))
+	l, err := CreateLedger(gb)
+	testutil.AssertNoError(t, err, "")
+
+	snapshotting := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- Backup(func(rootPath string) error {
+			testutil.AssertEquals(t, rootPath, ledgerconfig.GetRootPath())
+			close(snapshotting)
+			<-release
+			return fmt.Errorf("snapshot error")
+		})
+	}()
+	<-snapshotting
+
+	committed := make(chan struct{})
+	go func() {
+		l.Commit(testutil.ConstructTestBlock(t, 1, 1, 10))
+		close(committed)
+	}()
+	select {
+	case <-committed:
+		t.Fatal("Should have held back the commit during the backup")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(release)
+	testutil.AssertError(t, <-done, "Should have returned the error of the snapshot")
+	select {
+	case <-committed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Should have resumed the commit after the backup")
+	}
+}
+
 func constructTestLedgerID(i int) string {
 	return fmt.Sprintf("ledger_%06d", i)
 }
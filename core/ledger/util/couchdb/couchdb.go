@@ -201,6 +201,13 @@ type BatchUpdateResponse struct {
 	Rev    string `json:"rev"`
 }
 
+//CreateIndexResponse contains the response of CouchDB to an index creation
+type CreateIndexResponse struct {
+	Result string `json:"result"`
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+}
+
 //Base64Attachment contains the definition for an attached file for couchdb
 type Base64Attachment struct {
 	ContentType    string `json:"content_type"`
@@ -381,6 +388,22 @@ func (couchInstance *CouchInstance) VerifyCouchConfig() (*ConnectionInfo, *DBRet
 	return dbResponse, couchDBReturn, nil
 }
 
+//HealthCheck checks that CouchDB responds, in a single attempt
+func (couchInstance *CouchInstance) HealthCheck() error {
+	connectURL, err := url.Parse(couchInstance.conf.URL)
+	if err != nil {
+		return err
+	}
+	connectURL.Path = "/"
+
+	resp, _, err := couchInstance.handleRequest(http.MethodGet, connectURL.String(), nil, "", "", 1, true)
+	if err != nil {
+		return fmt.Errorf("Unable to connect to CouchDB: %s", err.Error())
+	}
+	closeResponseBody(resp)
+	return nil
+}
+
 //DropDatabase provides method to drop an existing database
 func (dbclient *CouchDatabase) DropDatabase() (*DBOperationResponse, error) {
 
@@ -1012,6 +1035,44 @@ func (dbclient *CouchDatabase) QueryDocuments(query string) (*[]QueryResult, err
 
 }
 
+// CreateIndex method provides a function creating an index, whose result is
+// "created", or "exists" if an identical index already exists
+func (dbclient *CouchDatabase) CreateIndex(indexdefinition string) (*CreateIndexResponse, error) {
+
+	logger.Debugf("Entering CreateIndex()  indexdefinition=%s", indexdefinition)
+
+	//Test to see if this is a valid JSON
+	if IsJSON(indexdefinition) != true {
+		return nil, fmt.Errorf("JSON format is not valid")
+	}
+
+	indexURL, err := url.Parse(dbclient.CouchInstance.conf.URL)
+	if err != nil {
+		logger.Errorf("URL parse error: %s", err.Error())
+		return nil, err
+	}
+
+	indexURL.Path = dbclient.DBName + "/_index"
+
+	//get the number of retries
+	maxRetries := dbclient.CouchInstance.conf.MaxRetries
+
+	resp, _, err := dbclient.CouchInstance.handleRequest(http.MethodPost, indexURL.String(), []byte(indexdefinition), "", "", maxRetries, true)
+	if err != nil {
+		return nil, err
+	}
+	defer closeResponseBody(resp)
+
+	couchDBReturn := &CreateIndexResponse{}
+	if err = json.NewDecoder(resp.Body).Decode(couchDBReturn); err != nil {
+		return nil, err
+	}
+
+	logger.Debugf("Exiting CreateIndex()  result=%s  name=%s", couchDBReturn.Result, couchDBReturn.Name)
+
+	return couchDBReturn, nil
+}
+
 //BatchRetrieveIDRevision - batch method to retrieve IDs and revisions
 func (dbclient *CouchDatabase) BatchRetrieveIDRevision(keys []string) ([]*DocMetadata, error) {
 
@@ -266,6 +266,47 @@ func TestDBCreateEnsureFullCommit(t *testing.T) {
 	}
 }
 
+func TestDBCreateIndex(t *testing.T) {
+
+	if ledgerconfig.IsCouchDBEnabled() {
+
+		database := "testdbcreateindex"
+		err := cleanup(database)
+		testutil.AssertNoError(t, err, fmt.Sprintf("Error when trying to cleanup  Error: %s", err))
+		defer cleanup(database)
+
+		if err == nil {
+			//create a new instance and database object
+			couchInstance, err := CreateCouchInstance(couchDBDef.URL, couchDBDef.Username, couchDBDef.Password,
+				couchDBDef.MaxRetries, couchDBDef.MaxRetriesOnStartup, couchDBDef.RequestTimeout)
+			testutil.AssertNoError(t, err, fmt.Sprintf("Error when trying to create couch instance"))
+			db := CouchDatabase{CouchInstance: *couchInstance, DBName: database}
+
+			//create a new database
+			_, errdb := db.CreateDatabaseIfNotExist()
+			testutil.AssertNoError(t, errdb, fmt.Sprintf("Error when trying to create database"))
+
+			indexDef := `{"index":{"fields":["data.owner"]},"ddoc":"indexOwnerDoc","name":"indexOwner","type":"json"}`
+
+			//Create the index
+			resp, indexerr := db.CreateIndex(indexDef)
+			testutil.AssertNoError(t, indexerr, fmt.Sprintf("Error when trying to create an index"))
+			testutil.AssertEquals(t, resp.Result, "created")
+			testutil.AssertEquals(t, resp.Name, "indexOwner")
+
+			//Create the same index again
+			resp, indexerr = db.CreateIndex(indexDef)
+			testutil.AssertNoError(t, indexerr, fmt.Sprintf("Error when trying to create an existing index"))
+			testutil.AssertEquals(t, resp.Result, "exists")
+
+			//Create an invalid index
+			_, indexerr = db.CreateIndex(`{"index":`)
+			testutil.AssertError(t, indexerr, fmt.Sprintf("Error should have been thrown for an invalid index"))
+
+		}
+	}
+}
+
 func TestDBBadDatabaseName(t *testing.T) {
 
 	if ledgerconfig.IsCouchDBEnabled() {
@@ -28,6 +28,12 @@ type Support struct {
 	LedgerVal     ledger.PeerLedger
 	MSPManagerVal msp.MSPManager
 	ApplyVal      error
+	TxIDWindowVal uint64
+	// CapabilitiesVal lists the capabilities reported as enabled by HasCapability
+	CapabilitiesVal []string
+	// MaxReadSetBytesVal and MaxWriteSetBytesVal are returned by RWSetLimits
+	MaxReadSetBytesVal  uint64
+	MaxWriteSetBytesVal uint64
 }
 
 // Ledger returns LedgerVal
@@ -52,3 +58,23 @@ func (ms *Support) PolicyManager() policies.Manager {
 func (cs *Support) GetMSPIDs(cid string) []string {
 	return []string{"DEFAULT"}
 }
+
+// TxIDWindow returns TxIDWindowVal
+func (ms *Support) TxIDWindow() uint64 {
+	return ms.TxIDWindowVal
+}
+
+// HasCapability returns whether the capability is listed in CapabilitiesVal
+func (ms *Support) HasCapability(capability string) bool {
+	for _, c := range ms.CapabilitiesVal {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
+
+// RWSetLimits returns MaxReadSetBytesVal and MaxWriteSetBytesVal
+func (ms *Support) RWSetLimits() (uint64, uint64) {
+	return ms.MaxReadSetBytesVal, ms.MaxWriteSetBytesVal
+}
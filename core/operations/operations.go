@@ -0,0 +1,243 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package operations implements the operations endpoint of the peer, an HTTP endpoint serving
+// the data of the ops dashboards as JSON: the membership and the height of the channels of the
+// peer, the chaincodes installed on the peer and committed on its channels, and the health of
+// the services the peer depends on
+package operations
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	logging "github.com/op/go-logging"
+)
+
+var logger = logging.MustGetLogger("operations")
+
+// Member is a peer of a channel, as known to gossip
+type Member struct {
+	Endpoint string `json:"endpoint"`
+	MSPID    string `json:"mspid,omitempty"`
+	// Height is the height of the ledger of the channel the member advertises, 0 if unknown
+	Height uint64 `json:"height,omitempty"`
+}
+
+// Chaincode is a chaincode installed on the peer or committed on a channel
+type Chaincode struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Path    string `json:"path,omitempty"`
+}
+
+// Channel is the status of a channel of the peer. A channel whose data cannot be read carries
+// the error instead of the missing data
+type Channel struct {
+	ID         string      `json:"id"`
+	Height     uint64      `json:"height"`
+	Members    []Member    `json:"members"`
+	Chaincodes []Chaincode `json:"chaincodes"`
+	Error      string      `json:"error,omitempty"`
+}
+
+// Dependency is the health of a service the peer depends on
+type Dependency struct {
+	Name    string `json:"name"`
+	Healthy bool   `json:"healthy"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Dashboard is the consolidated data of the peer served to the ops dashboards
+type Dashboard struct {
+	PeerID              string       `json:"peerId"`
+	Time                time.Time    `json:"time"`
+	Channels            []Channel    `json:"channels"`
+	InstalledChaincodes []Chaincode  `json:"installedChaincodes"`
+	Dependencies        []Dependency `json:"dependencies"`
+	InstalledError      string       `json:"installedChaincodesError,omitempty"`
+}
+
+// Support provides the operations endpoint with the data of the peer
+type Support interface {
+	// Channels returns the channels the peer has joined
+	Channels() []string
+
+	// Height returns the height of the ledger of a channel
+	Height(channelID string) (uint64, error)
+
+	// Members returns the remote peers of a channel
+	Members(channelID string) []Member
+
+	// CommittedChaincodes returns the chaincodes instantiated on a channel
+	CommittedChaincodes(channelID string) ([]Chaincode, error)
+
+	// InstalledChaincodes returns the chaincodes installed on the peer
+	InstalledChaincodes() ([]Chaincode, error)
+}
+
+// HealthChecker checks the health of a service the peer depends on
+type HealthChecker interface {
+	// HealthCheck returns an error if the service is unavailable
+	HealthCheck() error
+}
+
+// HealthCheckerFunc is an adapter to use a function as a HealthChecker
+type HealthCheckerFunc func() error
+
+// HealthCheck calls f()
+func (f HealthCheckerFunc) HealthCheck() error {
+	return f()
+}
+
+// Handler serves the dashboard of the peer
+type Handler struct {
+	peerID             string
+	support            Support
+	healthCheckTimeout time.Duration
+
+	lock     sync.RWMutex
+	checkers map[string]HealthChecker
+}
+
+// NewHandler creates the handler serving the dashboard of the peer peerID, whose dependencies
+// are given healthCheckTimeout to report their health
+func NewHandler(peerID string, support Support, healthCheckTimeout time.Duration) *Handler {
+	return &Handler{
+		peerID:             peerID,
+		support:            support,
+		healthCheckTimeout: healthCheckTimeout,
+		checkers:           make(map[string]HealthChecker),
+	}
+}
+
+// RegisterChecker registers the health checker of the dependency name, replacing the previous
+// one if any
+func (h *Handler) RegisterChecker(name string, checker HealthChecker) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.checkers[name] = checker
+}
+
+// Dashboard gathers the data of the dashboard
+func (h *Handler) Dashboard() *Dashboard {
+	dashboard := &Dashboard{
+		PeerID:              h.peerID,
+		Time:                time.Now(),
+		Channels:            []Channel{},
+		InstalledChaincodes: []Chaincode{},
+		Dependencies:        h.dependencies(),
+	}
+
+	channels := h.support.Channels()
+	sort.Strings(channels)
+	for _, channelID := range channels {
+		dashboard.Channels = append(dashboard.Channels, h.channel(channelID))
+	}
+
+	installed, err := h.support.InstalledChaincodes()
+	if err != nil {
+		logger.Warningf("Failed reading the installed chaincodes: %s", err)
+		dashboard.InstalledError = err.Error()
+	} else if installed != nil {
+		dashboard.InstalledChaincodes = installed
+	}
+	return dashboard
+}
+
+func (h *Handler) channel(channelID string) Channel {
+	channel := Channel{
+		ID:         channelID,
+		Members:    h.support.Members(channelID),
+		Chaincodes: []Chaincode{},
+	}
+	if channel.Members == nil {
+		channel.Members = []Member{}
+	}
+	sort.Sort(membersByEndpoint(channel.Members))
+
+	var err error
+	if channel.Height, err = h.support.Height(channelID); err != nil {
+		logger.Warningf("[channel: %s] Failed reading the ledger height: %s", channelID, err)
+		channel.Error = err.Error()
+		return channel
+	}
+	chaincodes, err := h.support.CommittedChaincodes(channelID)
+	if err != nil {
+		logger.Warningf("[channel: %s] Failed reading the committed chaincodes: %s", channelID, err)
+		channel.Error = err.Error()
+		return channel
+	}
+	if chaincodes != nil {
+		channel.Chaincodes = chaincodes
+	}
+	return channel
+}
+
+// dependencies runs the health checks concurrently, a check which does not return within the
+// timeout reporting the dependency as unhealthy
+func (h *Handler) dependencies() []Dependency {
+	h.lock.RLock()
+	names := make([]string, 0, len(h.checkers))
+	for name := range h.checkers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	results := make([]chan error, len(names))
+	for i, name := range names {
+		results[i] = make(chan error, 1)
+		go func(checker HealthChecker, result chan<- error) {
+			result <- checker.HealthCheck()
+		}(h.checkers[name], results[i])
+	}
+	h.lock.RUnlock()
+
+	// Closed once the timeout elapses, so that it fails all the pending checks
+	timeout := make(chan struct{})
+	timer := time.AfterFunc(h.healthCheckTimeout, func() { close(timeout) })
+	defer timer.Stop()
+
+	dependencies := make([]Dependency, len(names))
+	for i, name := range names {
+		dependencies[i] = Dependency{Name: name}
+		var err error
+		select {
+		case err = <-results[i]:
+		case <-timeout:
+			err = fmt.Errorf("health check timed out after %s", h.healthCheckTimeout)
+		}
+		if err != nil {
+			dependencies[i].Error = err.Error()
+		} else {
+			dependencies[i].Healthy = true
+		}
+	}
+	return dependencies
+}
+
+type membersByEndpoint []Member
+
+func (m membersByEndpoint) Len() int           { return len(m) }
+func (m membersByEndpoint) Swap(i, j int)      { m[i], m[j] = m[j], m[i] }
+func (m membersByEndpoint) Less(i, j int) bool { return m[i].Endpoint < m[j].Endpoint }
+
+// ServeHTTP serves the dashboard as JSON to the GET requests
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(h.Dashboard()); err != nil {
+		logger.Warningf("Failed writing the dashboard: %s", err)
+	}
+}
@@ -0,0 +1,154 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package operations
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockSupport struct {
+	channels   []string
+	heights    map[string]uint64
+	members    map[string][]Member
+	committed  map[string][]Chaincode
+	installed  []Chaincode
+	installErr error
+}
+
+func (s *mockSupport) Channels() []string {
+	return s.channels
+}
+
+func (s *mockSupport) Height(channelID string) (uint64, error) {
+	height, exists := s.heights[channelID]
+	if !exists {
+		return 0, errors.New("ledger unavailable")
+	}
+	return height, nil
+}
+
+func (s *mockSupport) Members(channelID string) []Member {
+	return s.members[channelID]
+}
+
+func (s *mockSupport) CommittedChaincodes(channelID string) ([]Chaincode, error) {
+	return s.committed[channelID], nil
+}
+
+func (s *mockSupport) InstalledChaincodes() ([]Chaincode, error) {
+	return s.installed, s.installErr
+}
+
+func newMockSupport() *mockSupport {
+	return &mockSupport{
+		channels: []string{"zchannel", "achannel", "broken"},
+		heights:  map[string]uint64{"achannel": 10, "zchannel": 3},
+		members: map[string][]Member{
+			"achannel": {
+				{Endpoint: "peer1:7051", MSPID: "Org2MSP", Height: 9},
+				{Endpoint: "peer0:7051", MSPID: "Org1MSP", Height: 10},
+			},
+		},
+		committed: map[string][]Chaincode{
+			"achannel": {{Name: "mycc", Version: "1.0"}},
+		},
+		installed: []Chaincode{{Name: "mycc", Version: "1.0", Path: "github.com/mycc"}},
+	}
+}
+
+func TestDashboard(t *testing.T) {
+	handler := NewHandler("peer0", newMockSupport(), time.Second)
+	handler.RegisterChecker("couchdb", HealthCheckerFunc(func() error { return nil }))
+	handler.RegisterChecker("docker", HealthCheckerFunc(func() error { return errors.New("connection refused") }))
+
+	dashboard := handler.Dashboard()
+	assert.Equal(t, "peer0", dashboard.PeerID)
+	require.Len(t, dashboard.Channels, 3)
+
+	achannel := dashboard.Channels[0]
+	assert.Equal(t, "achannel", achannel.ID)
+	assert.Equal(t, uint64(10), achannel.Height)
+	assert.Empty(t, achannel.Error)
+	assert.Equal(t, []Member{
+		{Endpoint: "peer0:7051", MSPID: "Org1MSP", Height: 10},
+		{Endpoint: "peer1:7051", MSPID: "Org2MSP", Height: 9},
+	}, achannel.Members, "Expected the members sorted by endpoint")
+	assert.Equal(t, []Chaincode{{Name: "mycc", Version: "1.0"}}, achannel.Chaincodes)
+
+	broken := dashboard.Channels[1]
+	assert.Equal(t, "broken", broken.ID)
+	assert.Equal(t, "ledger unavailable", broken.Error)
+	assert.Equal(t, []Member{}, broken.Members)
+	assert.Equal(t, []Chaincode{}, broken.Chaincodes)
+
+	zchannel := dashboard.Channels[2]
+	assert.Equal(t, "zchannel", zchannel.ID)
+	assert.Equal(t, uint64(3), zchannel.Height)
+	assert.Equal(t, []Chaincode{}, zchannel.Chaincodes)
+
+	assert.Equal(t, []Chaincode{{Name: "mycc", Version: "1.0", Path: "github.com/mycc"}}, dashboard.InstalledChaincodes)
+	assert.Empty(t, dashboard.InstalledError)
+	assert.Equal(t, []Dependency{
+		{Name: "couchdb", Healthy: true},
+		{Name: "docker", Error: "connection refused"},
+	}, dashboard.Dependencies)
+}
+
+func TestDashboardInstalledError(t *testing.T) {
+	support := newMockSupport()
+	support.installErr = errors.New("permission denied")
+	dashboard := NewHandler("peer0", support, time.Second).Dashboard()
+	assert.Equal(t, []Chaincode{}, dashboard.InstalledChaincodes)
+	assert.Equal(t, "permission denied", dashboard.InstalledError)
+	assert.Equal(t, []Dependency{}, dashboard.Dependencies)
+}
+
+func TestHealthCheckTimeout(t *testing.T) {
+	handler := NewHandler("peer0", &mockSupport{}, 100*time.Millisecond)
+	release := make(chan struct{})
+	defer close(release)
+	handler.RegisterChecker("hanging", HealthCheckerFunc(func() error {
+		<-release
+		return nil
+	}))
+	handler.RegisterChecker("healthy", HealthCheckerFunc(func() error { return nil }))
+
+	start := time.Now()
+	dependencies := handler.Dashboard().Dependencies
+	assert.True(t, time.Since(start) < 5*time.Second, "Expected the hanging check to time out")
+	require.Len(t, dependencies, 2)
+	assert.Equal(t, "hanging", dependencies[0].Name)
+	assert.False(t, dependencies[0].Healthy)
+	assert.Contains(t, dependencies[0].Error, "timed out")
+	assert.Equal(t, Dependency{Name: "healthy", Healthy: true}, dependencies[1])
+}
+
+func TestServeHTTP(t *testing.T) {
+	handler := NewHandler("peer0", newMockSupport(), time.Second)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/dashboard", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	dashboard := &Dashboard{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), dashboard))
+	assert.Equal(t, "peer0", dashboard.PeerID)
+	assert.Len(t, dashboard.Channels, 3)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/dashboard", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	assert.Equal(t, http.MethodGet, rec.Header().Get("Allow"))
+}
@@ -0,0 +1,112 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package operations
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/peer"
+	gcommon "github.com/hyperledger/fabric/gossip/common"
+	"github.com/hyperledger/fabric/gossip/service"
+	"github.com/hyperledger/fabric/gossip/state"
+	"github.com/hyperledger/fabric/protos/ledger/queryresult"
+)
+
+type peerSupport struct{}
+
+// NewSupport creates the Support of the operations endpoint backed by the ledgers, the gossip
+// service and the file system of the peer
+func NewSupport() Support {
+	return &peerSupport{}
+}
+
+func (*peerSupport) Channels() []string {
+	var channels []string
+	for _, info := range peer.GetChannelsInfo() {
+		channels = append(channels, info.ChannelId)
+	}
+	return channels
+}
+
+func (*peerSupport) Height(channelID string) (uint64, error) {
+	l := peer.GetLedger(channelID)
+	if l == nil {
+		return 0, fmt.Errorf("channel %s not found", channelID)
+	}
+	info, err := l.GetBlockchainInfo()
+	if err != nil {
+		return 0, err
+	}
+	return info.Height, nil
+}
+
+// Members returns the peers of the channel known to gossip, with the ledger height they
+// advertise
+func (*peerSupport) Members(channelID string) []Member {
+	gossip := service.GetGossipService()
+	var members []Member
+	for _, member := range gossip.PeersOfChannel(gcommon.ChainID(channelID)) {
+		m := Member{Endpoint: member.Endpoint}
+		if org := gossip.PeerOrg(member.PKIid); org != nil {
+			m.MSPID = string(org)
+		}
+		if metastate, err := state.FromBytes(member.Metadata); err == nil {
+			m.Height = metastate.LedgerHeight
+		}
+		members = append(members, m)
+	}
+	return members
+}
+
+// CommittedChaincodes returns the chaincodes defined in the lscc namespace of the channel
+func (*peerSupport) CommittedChaincodes(channelID string) ([]Chaincode, error) {
+	l := peer.GetLedger(channelID)
+	if l == nil {
+		return nil, fmt.Errorf("channel %s not found", channelID)
+	}
+	qe, err := l.NewQueryExecutor()
+	if err != nil {
+		return nil, err
+	}
+	defer qe.Done()
+
+	itr, err := qe.GetStateRangeScanIterator("lscc", "", "")
+	if err != nil {
+		return nil, err
+	}
+	defer itr.Close()
+
+	var chaincodes []Chaincode
+	for {
+		result, err := itr.Next()
+		if err != nil {
+			return nil, err
+		}
+		if result == nil {
+			return chaincodes, nil
+		}
+		cd := &ccprovider.ChaincodeData{}
+		if err := proto.Unmarshal(result.(*queryresult.KV).Value, cd); err != nil {
+			return nil, fmt.Errorf("invalid definition of chaincode %s: %s", result.(*queryresult.KV).Key, err)
+		}
+		chaincodes = append(chaincodes, Chaincode{Name: cd.Name, Version: cd.Version})
+	}
+}
+
+func (*peerSupport) InstalledChaincodes() ([]Chaincode, error) {
+	cqr, err := ccprovider.GetInstalledChaincodes()
+	if err != nil {
+		return nil, err
+	}
+	var chaincodes []Chaincode
+	for _, cc := range cqr.Chaincodes {
+		chaincodes = append(chaincodes, Chaincode{Name: cc.Name, Version: cc.Version, Path: cc.Path})
+	}
+	return chaincodes, nil
+}
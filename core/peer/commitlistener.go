@@ -0,0 +1,47 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package peer
+
+import (
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/protos/common"
+)
+
+// CommitListener is notified of the blocks committed to the ledgers of the chains
+type CommitListener interface {
+	BlockCommitted(cid string, block *common.Block)
+}
+
+// commitListener is notified of the blocks committed, nil if none is set
+var commitListener CommitListener
+
+// SetCommitListener sets the listener notified of the blocks committed to the chains
+// initialized or created afterwards
+func SetCommitListener(l CommitListener) {
+	commitListener = l
+}
+
+type listenedLedger struct {
+	ledger.PeerLedger
+	cid      string
+	listener CommitListener
+}
+
+func (ll *listenedLedger) Commit(block *common.Block) error {
+	if err := ll.PeerLedger.Commit(block); err != nil {
+		return err
+	}
+	ll.listener.BlockCommitted(ll.cid, block)
+	return nil
+}
+
+func withCommitListener(cid string, l ledger.PeerLedger) ledger.PeerLedger {
+	if commitListener == nil {
+		return l
+	}
+	return &listenedLedger{PeerLedger: l, cid: cid, listener: commitListener}
+}
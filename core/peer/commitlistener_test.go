@@ -0,0 +1,37 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package peer
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockCommitListener struct {
+	notified chan *common.Block
+}
+
+func (mcl *mockCommitListener) BlockCommitted(cid string, block *common.Block) {
+	mcl.notified <- block
+}
+
+func TestWithCommitListener(t *testing.T) {
+	mcl := &mockCommitLedger{committed: make(chan *common.Block, 1)}
+	assert.Equal(t, mcl, withCommitListener("mychannel", mcl), "Should not wrap the ledger without a listener")
+
+	listener := &mockCommitListener{notified: make(chan *common.Block, 1)}
+	SetCommitListener(listener)
+	defer SetCommitListener(nil)
+
+	l := withCommitListener("mychannel", mcl)
+	block := common.NewBlock(1, nil)
+	assert.NoError(t, l.Commit(block))
+	assert.Equal(t, block, <-mcl.committed)
+	assert.Equal(t, block, <-listener.notified, "The listener should be notified once the block is committed")
+}
@@ -0,0 +1,41 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package peer
+
+import (
+	"github.com/hyperledger/fabric/common/diskwatch"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/protos/common"
+)
+
+// diskWatcher quiesces the commit of blocks when the peer runs low on disk space, nil if disabled
+var diskWatcher *diskwatch.Watcher
+
+// SetDiskWatcher sets the watcher which holds back the commit of blocks while the peer is quiesced.
+// It applies to the chains initialized or created afterwards
+func SetDiskWatcher(watcher *diskwatch.Watcher) {
+	diskWatcher = watcher
+}
+
+// quiescingLedger blocks commits while the disk watcher has quiesced the peer, rather than
+// letting them fail halfway through a block on a full disk. Queries are still served
+type quiescingLedger struct {
+	ledger.PeerLedger
+	watcher *diskwatch.Watcher
+}
+
+func (ql *quiescingLedger) Commit(block *common.Block) error {
+	ql.watcher.WaitWritable()
+	return ql.PeerLedger.Commit(block)
+}
+
+func withDiskWatch(l ledger.PeerLedger) ledger.PeerLedger {
+	if diskWatcher == nil {
+		return l
+	}
+	return &quiescingLedger{PeerLedger: l, watcher: diskWatcher}
+}
@@ -0,0 +1,53 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package peer
+
+import (
+	"math"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/common/diskwatch"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockCommitLedger struct {
+	ledger.PeerLedger
+	committed chan *common.Block
+}
+
+func (mcl *mockCommitLedger) Commit(block *common.Block) error {
+	mcl.committed <- block
+	return nil
+}
+
+func TestWithDiskWatch(t *testing.T) {
+	mcl := &mockCommitLedger{committed: make(chan *common.Block, 1)}
+	assert.Equal(t, mcl, withDiskWatch(mcl), "Should not wrap the ledger without a watcher")
+
+	// No file system has that much free space
+	watcher, err := diskwatch.New(diskwatch.Conf{
+		Paths:            []string{os.TempDir()},
+		Interval:         time.Hour,
+		WarningFreeSpace: math.MaxUint64,
+		QuiesceFreeSpace: math.MaxUint64,
+	})
+	assert.NoError(t, err)
+	watcher.Check()
+	SetDiskWatcher(watcher)
+	defer SetDiskWatcher(nil)
+
+	go withDiskWatch(mcl).Commit(common.NewBlock(0, nil))
+	select {
+	case <-mcl.committed:
+		t.Fatal("Should have held back the commit while quiesced")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,49 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package peer
+
+import (
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/events/bridge"
+	"github.com/hyperledger/fabric/protos/common"
+)
+
+// eventBridge republishes the events of the chains to a message bus, nil if disabled
+var eventBridge *bridge.Bridge
+
+// SetEventBridge sets the bridge which republishes the events of the chains initialized or
+// created afterwards
+func SetEventBridge(b *bridge.Bridge) {
+	eventBridge = b
+}
+
+// notifyingLedger notifies the event bridge of each block committed, so that it publishes the
+// events of the block without waiting for its next poll of the ledger
+type notifyingLedger struct {
+	ledger.PeerLedger
+	cid    string
+	bridge *bridge.Bridge
+}
+
+func (nl *notifyingLedger) Commit(block *common.Block) error {
+	if err := nl.PeerLedger.Commit(block); err != nil {
+		return err
+	}
+	nl.bridge.Notify(nl.cid)
+	return nil
+}
+
+func withEventBridge(cid string, l ledger.PeerLedger) ledger.PeerLedger {
+	if eventBridge == nil {
+		return l
+	}
+	if err := eventBridge.Register(cid, l); err != nil {
+		peerLogger.Errorf("Events of channel %s will not be published to the message bus: %s", cid, err)
+		return l
+	}
+	return &notifyingLedger{PeerLedger: l, cid: cid, bridge: eventBridge}
+}
@@ -0,0 +1,58 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package peer
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/events/bridge"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockInfoLedger struct {
+	mockCommitLedger
+}
+
+func (mil *mockInfoLedger) GetBlockchainInfo() (*common.BlockchainInfo, error) {
+	return &common.BlockchainInfo{Height: 1}, nil
+}
+
+type mockPublisher struct{}
+
+func (mp *mockPublisher) Publish(topic string, key, value []byte) error {
+	return nil
+}
+
+func (mp *mockPublisher) Close() error {
+	return nil
+}
+
+func TestWithEventBridge(t *testing.T) {
+	mil := &mockInfoLedger{mockCommitLedger{committed: make(chan *common.Block, 1)}}
+	assert.Equal(t, mil, withEventBridge("mychannel", mil), "Should not wrap the ledger without a bridge")
+
+	dir, err := ioutil.TempDir("", "eventbridge")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	b, err := bridge.New(bridge.Config{BlockTopic: "blocks", CheckpointDir: dir, RetryInterval: time.Hour}, &mockPublisher{})
+	assert.NoError(t, err)
+	defer b.Close()
+	SetEventBridge(b)
+	defer SetEventBridge(nil)
+
+	l := withEventBridge("mychannel", mil)
+	assert.NoError(t, l.Commit(common.NewBlock(1, nil)))
+	assert.NotNil(t, <-mil.committed)
+	_, err = ioutil.ReadFile(dir + "/mychannel")
+	assert.NoError(t, err, "Should have registered the channel with the bridge")
+
+	assert.Equal(t, mil, withEventBridge("mychannel", mil), "Should not wrap the ledger of a channel registered twice")
+}
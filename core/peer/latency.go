@@ -0,0 +1,66 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package peer
+
+import (
+	"time"
+
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	gometrics "github.com/rcrowley/go-metrics"
+)
+
+// measuringLedger measures the latency of the transactions committed, from the times recorded by the
+// orderer in the TRACE_IDS metadata of their block. The time from the receipt of each transaction by
+// the orderer to its commit is published as the peer.latency.<chain>.end_to_end_ms histogram, and the
+// time from the cut of each block to its commit as peer.latency.<chain>.commit_ms. The latencies are
+// only meaningful when the clocks of the orderers and of the peer are synchronized
+type measuringLedger struct {
+	ledger.PeerLedger
+	now      func() time.Time
+	endToEnd gometrics.Histogram
+	commit   gometrics.Histogram
+}
+
+func (ml *measuringLedger) Commit(block *common.Block) error {
+	if err := ml.PeerLedger.Commit(block); err != nil {
+		return err
+	}
+	traces, err := utils.GetTraceMetadataFromBlock(block)
+	if err != nil {
+		peerLogger.Warningf("Not measuring the latency of block %d: %s", block.Header.Number, err)
+		return nil
+	}
+	if traces == nil {
+		return nil
+	}
+
+	committed := ml.now()
+	if traces.OrderedAt != 0 {
+		ml.commit.Update(int64(committed.Sub(time.Unix(0, traces.OrderedAt)) / time.Millisecond))
+	}
+	for _, receivedAt := range traces.ReceivedAt {
+		if receivedAt != 0 {
+			ml.endToEnd.Update(int64(committed.Sub(time.Unix(0, receivedAt)) / time.Millisecond))
+		}
+	}
+	return nil
+}
+
+func withLatency(cid string, l ledger.PeerLedger) ledger.PeerLedger {
+	histogram := func(name string) gometrics.Histogram {
+		return gometrics.GetOrRegisterHistogram("peer.latency."+cid+"."+name, metrics.Registry, gometrics.NewExpDecaySample(1028, 0.015))
+	}
+	return &measuringLedger{
+		PeerLedger: l,
+		now:        time.Now,
+		endToEnd:   histogram("end_to_end_ms"),
+		commit:     histogram("commit_ms"),
+	}
+}
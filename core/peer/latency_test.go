@@ -0,0 +1,46 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package peer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithLatency(t *testing.T) {
+	mcl := &mockCommitLedger{committed: make(chan *common.Block, 3)}
+	l := withLatency("latencychannel", mcl).(*measuringLedger)
+	committed := time.Unix(1500000000, 0)
+	l.now = func() time.Time { return committed }
+
+	block := common.NewBlock(1, nil)
+	block.Metadata.Metadata[common.BlockMetadataIndex_TRACE_IDS] = utils.MarshalOrPanic(&common.Metadata{
+		Value: utils.MarshalOrPanic(&ab.TraceMetadata{
+			TraceIds:   []string{"trace1", "trace2", "trace3"},
+			ReceivedAt: []int64{committed.Add(-900 * time.Millisecond).UnixNano(), 0, committed.Add(-700 * time.Millisecond).UnixNano()},
+			OrderedAt:  committed.Add(-200 * time.Millisecond).UnixNano(),
+		}),
+	})
+	assert.NoError(t, l.Commit(block))
+	assert.Equal(t, block, <-mcl.committed)
+
+	assert.NoError(t, l.Commit(common.NewBlock(2, nil)), "Should commit the blocks without trace metadata")
+	garbled := common.NewBlock(3, nil)
+	garbled.Metadata.Metadata[common.BlockMetadataIndex_TRACE_IDS] = []byte("garbage")
+	assert.NoError(t, l.Commit(garbled), "Should commit the blocks whose trace metadata is malformed")
+
+	assert.Equal(t, int64(2), l.endToEnd.Count(), "Should only measure the transactions whose receipt time is known")
+	assert.Equal(t, int64(900), l.endToEnd.Max())
+	assert.Equal(t, int64(700), l.endToEnd.Min())
+	assert.Equal(t, int64(1), l.commit.Count())
+	assert.Equal(t, int64(200), l.commit.Max())
+}
@@ -57,6 +57,30 @@ func (cs *chainSupport) GetMSPIDs(cid string) []string {
 	return GetMSPIDs(cid)
 }
 
+// TxIDWindow returns the transaction ID uniqueness window of the application config of the
+// channel, 0 when it has none
+func (cs *chainSupport) TxIDWindow() uint64 {
+	if cs.Application == nil {
+		return 0
+	}
+	return cs.Application.TxIDWindow()
+}
+
+// RWSetLimits returns the read-write set size limits of the application config of the channel,
+// 0 when it has none
+func (cs *chainSupport) RWSetLimits() (maxReadSetBytes, maxWriteSetBytes uint64) {
+	if cs.Application == nil {
+		return 0, 0
+	}
+	return cs.Application.RWSetLimits()
+}
+
+// HasCapability returns whether the capability is enabled in the application config of the
+// channel, false when it has none
+func (cs *chainSupport) HasCapability(capability string) bool {
+	return cs.Application != nil && cs.Application.HasCapability(capability)
+}
+
 // chain is a local struct to manage objects in a chain
 type chain struct {
 	cs        *chainSupport
@@ -101,6 +125,23 @@ func Initialize(init func(string)) {
 	}
 	for _, cid := range ledgerIds {
 		peerLogger.Infof("Loading chain %s", cid)
+		lost, err := ledgermgmt.IsLedgerLost(cid)
+		if err != nil {
+			peerLogger.Warningf("Failed to check ledger %s(%s)", cid, err)
+			continue
+		}
+		if lost {
+			if genesisBlockFetcher == nil {
+				panic(fmt.Errorf("The blocks of ledger %s are missing from the block storage: restore the ledger data, or enable ledger.recovery to join the channel again", cid))
+			}
+			peerLogger.Warningf("The blocks of ledger %s are missing from the block storage, joining the channel again", cid)
+			if err = recoverChain(cid, genesisBlockFetcher); err != nil {
+				peerLogger.Errorf("Failed to recover chain %s(%s), the recovery is attempted again at the next start", cid, err)
+				continue
+			}
+			InitChain(cid)
+			continue
+		}
 		if ledger, err = ledgermgmt.OpenLedger(cid); err != nil {
 			peerLogger.Warningf("Failed to load ledger %s(%s)", cid, err)
 			peerLogger.Debugf("Error while loading ledger %s with message %s. We continue to the next ledger rather than abort.", cid, err)
@@ -195,10 +236,15 @@ func createChain(cid string, ledger ledger.PeerLedger, cb *common.Block) error {
 		updateTrustedRoots(cm)
 	}
 
+	capabilitiesCallbackWrapper := func(cm configtxapi.Manager) {
+		ac, ok := configtxInitializer.ApplicationConfig()
+		ledger.SetRangeQueryHashing(ok && ac.HasCapability(config.RangeQueryHashingCapability))
+	}
+
 	configtxManager, err := configtx.NewManagerImpl(
 		envelopeConfig,
 		configtxInitializer,
-		[]func(cm configtxapi.Manager){gossipCallbackWrapper, trustedRootsCallbackWrapper},
+		[]func(cm configtxapi.Manager){gossipCallbackWrapper, trustedRootsCallbackWrapper, capabilitiesCallbackWrapper},
 	)
 	if err != nil {
 		return err
@@ -217,7 +263,7 @@ func createChain(cid string, ledger ledger.PeerLedger, cb *common.Block) error {
 		ledger:      ledger,
 	}
 
-	c := committer.NewLedgerCommitterReactive(ledger, txvalidator.NewTxValidator(cs), func(block *common.Block) error {
+	c := committer.NewLedgerCommitterReactive(withCommitListener(cid, withEventBridge(cid, withLatency(cid, withDiskWatch(ledger)))), txvalidator.NewTxValidator(cs, validationConcurrency), func(block *common.Block) error {
 		chainID, err := utils.GetChainIDFromBlock(block)
 		if err != nil {
 			return err
@@ -322,6 +368,39 @@ func GetPolicyManager(cid string) policies.Manager {
 	return nil
 }
 
+// GetOrdererAddresses returns the addresses of the ordering service of the chain with chain ID.
+// Note that this call returns nil if chain cid has not been created.
+func GetOrdererAddresses(cid string) []string {
+	chains.RLock()
+	defer chains.RUnlock()
+	if c, ok := chains.list[cid]; ok {
+		return c.cs.ChannelConfig().OrdererAddresses()
+	}
+	return nil
+}
+
+// GetConfigSequence returns the sequence number of the current config of the chain with chain ID.
+// Note that this call returns 0 if chain cid has not been created.
+func GetConfigSequence(cid string) uint64 {
+	chains.RLock()
+	defer chains.RUnlock()
+	if c, ok := chains.list[cid]; ok {
+		return c.cs.Sequence()
+	}
+	return 0
+}
+
+// HasCapability returns whether the capability is enabled on the chain with chain ID. Note that
+// this call returns false if chain cid has not been created.
+func HasCapability(cid, capability string) bool {
+	chains.RLock()
+	defer chains.RUnlock()
+	if c, ok := chains.list[cid]; ok {
+		return c.cs.HasCapability(capability)
+	}
+	return false
+}
+
 // GetCurrConfigBlock returns the cached config block of the specified chain.
 // Note that this call returns nil if chain cid has not been created.
 func GetCurrConfigBlock(cid string) *common.Block {
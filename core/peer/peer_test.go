@@ -211,6 +211,19 @@ func TestCreateChainFromBlock(t *testing.T) {
 		t.Fatal("got a bogus PolicyManager")
 	}
 
+	// Correct orderer addresses
+	if addresses := GetOrdererAddresses(testChainID); len(addresses) == 0 {
+		t.Fatal("failed to get the orderer addresses")
+	}
+
+	// Bad orderer addresses
+	if addresses := GetOrdererAddresses("BogusChain"); addresses != nil {
+		t.Fatal("got bogus orderer addresses")
+	}
+
+	// Bad config sequence
+	assert.Equal(t, uint64(0), GetConfigSequence("BogusChain"))
+
 	// PolicyManagerGetter
 	pmg := NewChannelPolicyManagerGetter()
 	assert.NotNil(t, pmg, "PolicyManagerGetter should not be nil")
@@ -0,0 +1,54 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package peer
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+)
+
+// GenesisBlockFetcher fetches the genesis block of a channel from the ordering service
+type GenesisBlockFetcher func(cid string) (*common.Block, error)
+
+// genesisBlockFetcher fetches the genesis blocks of the channels whose ledger lost its blocks, nil if
+// their recovery is disabled
+var genesisBlockFetcher GenesisBlockFetcher
+
+// SetGenesisBlockFetcher enables the recovery of the channels whose ledger lost its blocks, as when the
+// block files are removed or truncated: rather than refusing to start, Initialize joins them again with
+// the genesis blocks fetched by fetch. It applies to the chains initialized afterwards
+func SetGenesisBlockFetcher(fetch GenesisBlockFetcher) {
+	genesisBlockFetcher = fetch
+}
+
+// recoverChain joins again the channel whose ledger lost its blocks. The genesis block is fetched first,
+// so that the ledger is left untouched if it cannot be, then the data left of the ledger are dropped and
+// the chain is created from the genesis block as when joining the channel. The blocks are then pulled
+// again from the ordering service and the other peers, replaying the channel
+func recoverChain(cid string, fetch GenesisBlockFetcher) error {
+	block, err := fetch(cid)
+	if err != nil {
+		return fmt.Errorf("cannot fetch the genesis block: %s", err)
+	}
+	if block == nil || block.Header == nil || block.Header.Number != 0 {
+		return fmt.Errorf("the block fetched is not a genesis block")
+	}
+	chainID, err := utils.GetChainIDFromBlock(block)
+	if err != nil {
+		return fmt.Errorf("malformed genesis block: %s", err)
+	}
+	if chainID != cid {
+		return fmt.Errorf("the genesis block fetched is the one of channel %s", chainID)
+	}
+	if err = ledgermgmt.DropLedger(cid); err != nil {
+		return fmt.Errorf("cannot drop the ledger: %s", err)
+	}
+	return CreateChainFromBlock(block)
+}
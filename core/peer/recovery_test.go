@@ -0,0 +1,42 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package peer
+
+import (
+	"errors"
+	"testing"
+
+	configtxtest "github.com/hyperledger/fabric/common/configtx/test"
+	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecoverChainFailures(t *testing.T) {
+	MockInitialize()
+
+	gb, err := configtxtest.MakeGenesisBlock("recoverchain")
+	require.NoError(t, err)
+	otherGB, err := configtxtest.MakeGenesisBlock("otherchain")
+	require.NoError(t, err)
+	_, err = ledgermgmt.CreateLedger(gb)
+	require.NoError(t, err)
+
+	for name, fetch := range map[string]GenesisBlockFetcher{
+		"FetchError": func(string) (*common.Block, error) { return nil, errors.New("no orderer") },
+		"NotGenesis": func(string) (*common.Block, error) { return common.NewBlock(1, nil), nil },
+		"OtherChain": func(string) (*common.Block, error) { return otherGB, nil },
+		// The ledger is opened, and cannot be dropped
+		"Opened": func(string) (*common.Block, error) { return gb, nil },
+	} {
+		assert.Error(t, recoverChain("recoverchain", fetch), name)
+		ids, err := ledgermgmt.GetLedgerIDs()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"recoverchain"}, ids, "%s: expected the ledger to be left untouched", name)
+	}
+}
@@ -0,0 +1,19 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package peer
+
+import "github.com/hyperledger/fabric/core/committer/txvalidator"
+
+// validationConcurrency decides the number of workers validating the transactions of the
+// blocks, nil to validate them one after the other
+var validationConcurrency *txvalidator.Concurrency
+
+// SetValidationConcurrency sets the concurrency of the validation of the transactions of the
+// blocks. It applies to the chains initialized or created afterwards
+func SetValidationConcurrency(concurrency *txvalidator.Concurrency) {
+	validationConcurrency = concurrency
+}
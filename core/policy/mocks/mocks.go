@@ -22,6 +22,7 @@ import (
 	"fmt"
 
 	"errors"
+	"time"
 
 	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/msp"
@@ -116,6 +117,10 @@ func (id *MockIdentity) GetOrganizationalUnits() []*msp.OUIdentifier {
 	return nil
 }
 
+func (id *MockIdentity) ExpiresAt() time.Time {
+	return time.Time{}
+}
+
 func (id *MockIdentity) Verify(msg []byte, sig []byte) error {
 	fmt.Printf("VERIFY [% x], [% x], [% x]\n", string(id.msg), string(msg), string(sig))
 	if bytes.Equal(id.msg, msg) {
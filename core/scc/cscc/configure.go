@@ -29,6 +29,7 @@ import (
 	"github.com/hyperledger/fabric/common/config"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/core/audit"
 	"github.com/hyperledger/fabric/core/chaincode/shim"
 	"github.com/hyperledger/fabric/core/peer"
 	"github.com/hyperledger/fabric/core/policy"
@@ -131,7 +132,13 @@ func (e *PeerConfiger) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
 				"for channel [%s]: [%s]", cid, err))
 		}
 
-		return joinChain(cid, block)
+		res := joinChain(cid, block)
+		var joinErr error
+		if res.Status != shim.OK {
+			joinErr = errors.New(res.Message)
+		}
+		audit.Record(audit.OperationJoinChannel, cid, audit.InvokerFromSignedProposal(sp), nil, joinErr)
+		return res
 	case GetConfigBlock:
 		// 2. check the channel reader policy
 		if err = e.policyChecker.CheckPolicy(string(args[1]), policies.ChannelApplicationReaders, sp); err != nil {
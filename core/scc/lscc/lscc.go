@@ -19,22 +19,29 @@ package lscc
 import (
 	"fmt"
 	"regexp"
+	"strings"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/common/config"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/core/audit"
+	"github.com/hyperledger/fabric/core/chaincode/platforms/golang"
 	"github.com/hyperledger/fabric/core/chaincode/shim"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/common/sysccprovider"
+	"github.com/hyperledger/fabric/core/ledger/cceventmgmt"
 	"github.com/hyperledger/fabric/core/peer"
 	"github.com/hyperledger/fabric/core/policy"
 	"github.com/hyperledger/fabric/core/policyprovider"
 	"github.com/hyperledger/fabric/msp/mgmt"
 	mspmgmt "github.com/hyperledger/fabric/msp/mgmt"
 	"github.com/hyperledger/fabric/protos/common"
+	mspprotos "github.com/hyperledger/fabric/protos/msp"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/spf13/viper"
 )
 
 //The life cycle system chaincode manages chaincodes deployed
@@ -236,6 +243,20 @@ func (f InstantiationPolicyMissing) Error() string {
 	return "instantiation policy missing"
 }
 
+//InvalidPackageSignatureErr when the detached signature of an installed chaincode package is missing or not trusted
+type InvalidPackageSignatureErr string
+
+func (f InvalidPackageSignatureErr) Error() string {
+	return fmt.Sprintf("invalid chaincode package signature(%s)", string(f))
+}
+
+//NonDeterministicCCErr when the analysis of an installed chaincode finds non-deterministic constructs
+type NonDeterministicCCErr string
+
+func (f NonDeterministicCCErr) Error() string {
+	return fmt.Sprintf("chaincode is not deterministic(%s)", string(f))
+}
+
 //-------------- helper functions ------------------
 //create the chaincode on the given chain
 func (lscc *LifeCycleSysCC) createChaincode(stub shim.ChaincodeStubInterface, cd *ccprovider.ChaincodeData) error {
@@ -449,8 +470,9 @@ func isValidCCNameOrVersion(ccNameOrVersion string, regExp string) bool {
 	return true
 }
 
-// executeInstall implements the "install" Invoke transaction
-func (lscc *LifeCycleSysCC) executeInstall(stub shim.ChaincodeStubInterface, ccbytes []byte) error {
+// executeInstall implements the "install" Invoke transaction. signature is the optional
+// detached signature of the package
+func (lscc *LifeCycleSysCC) executeInstall(stub shim.ChaincodeStubInterface, ccbytes []byte, signature []byte) error {
 	ccpack, err := ccprovider.GetCCPackage(ccbytes)
 	if err != nil {
 		return err
@@ -470,36 +492,189 @@ func (lscc *LifeCycleSysCC) executeInstall(stub shim.ChaincodeStubInterface, ccb
 		return err
 	}
 
+	if err = analyzeChaincode(cds); err != nil {
+		return err
+	}
+
+	signer, err := verifyPackageSignature(ccpack, signature)
+	if err != nil {
+		return err
+	}
+
+	//record the signer before the package, so that the package is never installed without it,
+	//and clear the record left by a failed install of a signed package otherwise
+	if signer != nil {
+		err = ccprovider.PutChaincodeSigner(cds.ChaincodeSpec.ChaincodeId.Name, cds.ChaincodeSpec.ChaincodeId.Version, signer)
+	} else {
+		err = ccprovider.RemoveChaincodeSigner(cds.ChaincodeSpec.ChaincodeId.Name, cds.ChaincodeSpec.ChaincodeId.Version)
+	}
+	if err != nil {
+		return fmt.Errorf("Error recording the signer of chaincode %s:%s(%s)", cds.ChaincodeSpec.ChaincodeId.Name, cds.ChaincodeSpec.ChaincodeId.Version, err)
+	}
+
 	//everything checks out..lets write the package to the FS
 	if err = ccpack.PutChaincodeToFS(); err != nil {
+		if signer != nil {
+			ccprovider.RemoveChaincodeSigner(cds.ChaincodeSpec.ChaincodeId.Name, cds.ChaincodeSpec.ChaincodeId.Version)
+		}
 		return fmt.Errorf("Error installing chaincode code %s:%s(%s)", cds.ChaincodeSpec.ChaincodeId.Name, cds.ChaincodeSpec.ChaincodeId.Version, err)
 	}
 
-	return err
+	//deploy the indexes of the chaincode on the channels where it was deployed before being installed
+	dbArtifacts, err := ccprovider.ExtractStatedbArtifactsFromCCPackage(ccpack)
+	if err != nil {
+		logger.Errorf("Error extracting the statedb artifacts of chaincode %s:%s(%s)", cds.ChaincodeSpec.ChaincodeId.Name, cds.ChaincodeSpec.ChaincodeId.Version, err)
+		return nil
+	}
+	cceventmgmt.GetMgr().HandleChaincodeInstall(&cceventmgmt.ChaincodeDefinition{
+		Name:    cds.ChaincodeSpec.ChaincodeId.Name,
+		Version: cds.ChaincodeSpec.ChaincodeId.Version,
+		Hash:    ccpack.GetId(),
+	}, dbArtifacts)
+
+	return nil
+}
+
+// analyzeChaincode looks for non-deterministic constructs in the source of a Go chaincode being
+// installed, as configured by chaincode.golang.analysis: "warn" logs them, "reject" refuses the
+// chaincode, and anything else skips the analysis
+func analyzeChaincode(cds *pb.ChaincodeDeploymentSpec) error {
+	mode := viper.GetString("chaincode.golang.analysis")
+	if cds.ChaincodeSpec.Type != pb.ChaincodeSpec_GOLANG || (mode != "warn" && mode != "reject") {
+		return nil
+	}
+
+	ccid := cds.ChaincodeSpec.ChaincodeId.Name + ":" + cds.ChaincodeSpec.ChaincodeId.Version
+	findings, err := golang.Analyze(cds)
+	if err != nil {
+		if mode == "reject" {
+			return NonDeterministicCCErr(fmt.Sprintf("analysis of %s failed: %s", ccid, err))
+		}
+		logger.Warningf("Analysis of chaincode %s failed: %s", ccid, err)
+		return nil
+	}
+	if len(findings) == 0 {
+		return nil
+	}
+	if mode == "reject" {
+		return NonDeterministicCCErr(fmt.Sprintf("%s: %s", ccid, strings.Join(findings, "; ")))
+	}
+	for _, finding := range findings {
+		logger.Warningf("Chaincode %s may not be deterministic: %s", ccid, finding)
+	}
+	return nil
 }
 
-// getInstantiationPolicy retrieves the instantiation policy from a SignedCDSPackage
-func (lscc *LifeCycleSysCC) getInstantiationPolicy(channel string, ccpack ccprovider.CCPackage) ([]byte, error) {
-	var ip []byte
-	var err error
-	// if ccpack is a SignedCDSPackage, return its IP, otherwise use a default IP
+// verifyPackageSignature verifies the detached signature of a chaincode package being installed
+// against chaincode.signing.policy, which defaults to the members of the local MSP, and returns
+// the serialized identity of its signer. A package without signature is refused if
+// chaincode.signing.required is set, and installed without signer otherwise
+func verifyPackageSignature(ccpack ccprovider.CCPackage, signature []byte) ([]byte, error) {
+	if len(signature) == 0 {
+		if viper.GetBool("chaincode.signing.required") {
+			return nil, InvalidPackageSignatureErr("the package must be signed")
+		}
+		return nil, nil
+	}
+
+	sd, err := ccprovider.GetDetachedSignatureSignedData(ccpack, signature)
+	if err != nil {
+		return nil, InvalidPackageSignatureErr(err.Error())
+	}
+
+	localMSP := mspmgmt.GetLocalMSP()
+	policyStr := viper.GetString("chaincode.signing.policy")
+	if policyStr == "" {
+		mspID, err := localMSP.GetIdentifier()
+		if err != nil {
+			return nil, fmt.Errorf("Error getting the identifier of the local MSP: %s", err)
+		}
+		policyStr = fmt.Sprintf("OR('%s.member')", mspID)
+	}
+	policyEnvelope, err := cauthdsl.FromString(policyStr)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid chaincode.signing.policy %s: %s", policyStr, err)
+	}
+	pol, _, err := cauthdsl.NewPolicyProvider(localMSP).NewPolicy(utils.MarshalOrPanic(policyEnvelope))
+	if err != nil {
+		return nil, fmt.Errorf("Invalid chaincode.signing.policy %s: %s", policyStr, err)
+	}
+	if err = pol.Evaluate(sd); err != nil {
+		return nil, InvalidPackageSignatureErr(fmt.Sprintf("the signer is not trusted: %s", err))
+	}
+	return sd[0].Identity, nil
+}
+
+// installAuditDetails extracts the name and version of the chaincode of an install package for the audit trail
+func installAuditDetails(ccbytes []byte) map[string]string {
+	ccpack, err := ccprovider.GetCCPackage(ccbytes)
+	if err != nil {
+		return nil
+	}
+	return chaincodeAuditDetails(ccpack.GetDepSpec())
+}
+
+// deployAuditDetails extracts the name and version of the chaincode of a deployment spec for the audit trail
+func deployAuditDetails(depSpec []byte) map[string]string {
+	cds, err := utils.GetChaincodeDeploymentSpec(depSpec)
+	if err != nil {
+		return nil
+	}
+	return chaincodeAuditDetails(cds)
+}
+
+func chaincodeAuditDetails(cds *pb.ChaincodeDeploymentSpec) map[string]string {
+	if cds == nil || cds.ChaincodeSpec == nil || cds.ChaincodeSpec.ChaincodeId == nil {
+		return nil
+	}
+	return map[string]string{
+		"name":    cds.ChaincodeSpec.ChaincodeId.Name,
+		"version": cds.ChaincodeSpec.ChaincodeId.Version,
+	}
+}
+
+// getInstantiationPolicy retrieves the instantiation policy of a chaincode instance. It is the
+// policy of the package if it is a SignedCDSPackage. Otherwise, if the channel has the
+// InstantiationPolicyBinding capability, an upgrade keeps the policy of the instance being
+// upgraded, passed as current, and an instantiation uses a default policy only satisfied by the
+// admins of the MSP of the instantiator, so that no other organization of the channel can upgrade
+// the chaincode afterwards. Without the capability the default policy is satisfied by the admins
+// of any MSP of the channel
+func (lscc *LifeCycleSysCC) getInstantiationPolicy(stub shim.ChaincodeStubInterface, channel string, ccpack ccprovider.CCPackage, current []byte) ([]byte, error) {
+	// if ccpack is a SignedCDSPackage, return its IP, otherwise use the current or a default IP
 	sccpack, isSccpack := ccpack.(*ccprovider.SignedCDSPackage)
 	if isSccpack {
-		ip = sccpack.GetInstantiationPolicy()
+		ip := sccpack.GetInstantiationPolicy()
 		if ip == nil {
 			return nil, fmt.Errorf("Instantiation policy cannot be null for a SignedCCDeploymentSpec")
 		}
-	} else {
+		return ip, nil
+	}
+
+	if !lscc.sccprovider.HasCapability(channel, config.InstantiationPolicyBindingCapability) {
 		// the default instantiation policy allows any of the channel MSP admins
 		// to be able to instantiate
-		mspids := peer.GetMSPIDs(channel)
-
-		p := cauthdsl.SignedByAnyAdmin(mspids)
-		ip, err = utils.Marshal(p)
+		ip, err := utils.Marshal(cauthdsl.SignedByAnyAdmin(peer.GetMSPIDs(channel)))
 		if err != nil {
 			return nil, fmt.Errorf("Error marshalling default instantiation policy")
 		}
+		return ip, nil
+	}
 
+	if current != nil {
+		return current, nil
+	}
+	_, creator, err := getProposalCreator(stub)
+	if err != nil {
+		return nil, err
+	}
+	sId := &mspprotos.SerializedIdentity{}
+	if err = proto.Unmarshal(creator, sId); err != nil {
+		return nil, fmt.Errorf("Error unmarshalling the creator of the proposal: %s", err)
+	}
+	ip, err := utils.Marshal(cauthdsl.SignedByMspAdmin(sId.Mspid))
+	if err != nil {
+		return nil, fmt.Errorf("Error marshalling default instantiation policy")
 	}
 	return ip, nil
 }
@@ -516,28 +691,15 @@ func (lscc *LifeCycleSysCC) checkInstantiationPolicy(stub shim.ChaincodeStubInte
 	if err != nil {
 		return err
 	}
-	// get the signed instantiation proposal
-	signedProp, err := stub.GetSignedProposal()
-	if err != nil {
-		return err
-	}
-	proposal, err := utils.GetProposal(signedProp.ProposalBytes)
-	if err != nil {
-		return err
-	}
-	// get the signature header of the proposal
-	header, err := utils.GetHeader(proposal.Header)
-	if err != nil {
-		return err
-	}
-	shdr, err := utils.GetSignatureHeader(header.SignatureHeader)
+	// get the signed instantiation proposal and its creator
+	signedProp, creator, err := getProposalCreator(stub)
 	if err != nil {
 		return err
 	}
 	// construct signed data we can evaluate the instantiation policy against
 	sd := []*common.SignedData{&common.SignedData{
 		Data:      signedProp.ProposalBytes,
-		Identity:  shdr.Creator,
+		Identity:  creator,
 		Signature: signedProp.Signature,
 	}}
 	err = instPol.Evaluate(sd)
@@ -547,6 +709,29 @@ func (lscc *LifeCycleSysCC) checkInstantiationPolicy(stub shim.ChaincodeStubInte
 	return nil
 }
 
+// getProposalCreator returns the signed proposal being executed and the identity of its creator,
+// taken from the signature header of the proposal
+func getProposalCreator(stub shim.ChaincodeStubInterface) (*pb.SignedProposal, []byte, error) {
+	signedProp, err := stub.GetSignedProposal()
+	if err != nil {
+		return nil, nil, err
+	}
+	proposal, err := utils.GetProposal(signedProp.ProposalBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	// get the signature header of the proposal
+	header, err := utils.GetHeader(proposal.Header)
+	if err != nil {
+		return nil, nil, err
+	}
+	shdr, err := utils.GetSignatureHeader(header.SignatureHeader)
+	if err != nil {
+		return nil, nil, err
+	}
+	return signedProp, shdr.Creator, nil
+}
+
 // executeDeploy implements the "instantiate" Invoke transaction
 func (lscc *LifeCycleSysCC) executeDeploy(stub shim.ChaincodeStubInterface, chainname string, depSpec []byte, policy []byte, escc []byte, vscc []byte) (*ccprovider.ChaincodeData, error) {
 	cds, err := utils.GetChaincodeDeploymentSpec(depSpec)
@@ -588,7 +773,7 @@ func (lscc *LifeCycleSysCC) executeDeploy(stub shim.ChaincodeStubInterface, chai
 	cd.Policy = policy
 
 	// retrieve and evaluate instantiation policy
-	cd.InstantiationPolicy, err = lscc.getInstantiationPolicy(chainname, ccpack)
+	cd.InstantiationPolicy, err = lscc.getInstantiationPolicy(stub, chainname, ccpack, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -656,6 +841,7 @@ func (lscc *LifeCycleSysCC) executeUpgrade(stub shim.ChaincodeStubInterface, cha
 	}
 
 	//get the new cd to upgrade to this is guaranteed to be not nil
+	currentInstantiationPolicy := cd.InstantiationPolicy
 	cd = ccpack.GetChaincodeData()
 
 	//retain chaincode specific data and fill channel specific ones
@@ -664,7 +850,7 @@ func (lscc *LifeCycleSysCC) executeUpgrade(stub shim.ChaincodeStubInterface, cha
 	cd.Policy = policy
 
 	// retrieve and evaluate new instantiation policy
-	cd.InstantiationPolicy, err = lscc.getInstantiationPolicy(chainName, ccpack)
+	cd.InstantiationPolicy, err = lscc.getInstantiationPolicy(stub, chainName, ccpack, currentInstantiationPolicy)
 	if err != nil {
 		return nil, err
 	}
@@ -726,7 +912,14 @@ func (lscc *LifeCycleSysCC) Invoke(stub shim.ChaincodeStubInterface) pb.Response
 
 		depSpec := args[1]
 
-		err := lscc.executeInstall(stub, depSpec)
+		// optional argument: args[2] is the detached signature of the package
+		var signature []byte
+		if len(args) > 2 {
+			signature = args[2]
+		}
+
+		err := lscc.executeInstall(stub, depSpec, signature)
+		audit.Record(audit.OperationInstallChaincode, "", audit.InvokerFromSignedProposal(sp), installAuditDetails(depSpec), err)
 		if err != nil {
 			return shim.Error(err.Error())
 		}
@@ -779,6 +972,7 @@ func (lscc *LifeCycleSysCC) Invoke(stub shim.ChaincodeStubInterface) pb.Response
 		}
 
 		cd, err := lscc.executeDeploy(stub, chainname, depSpec, policy, escc, vscc)
+		audit.Record(audit.OperationInstantiateChaincode, chainname, audit.InvokerFromSignedProposal(sp), deployAuditDetails(depSpec), err)
 		if err != nil {
 			return shim.Error(err.Error())
 		}
@@ -832,6 +1026,7 @@ func (lscc *LifeCycleSysCC) Invoke(stub shim.ChaincodeStubInterface) pb.Response
 		}
 
 		cd, err := lscc.executeUpgrade(stub, chainname, depSpec, policy, escc, vscc)
+		audit.Record(audit.OperationUpgradeChaincode, chainname, audit.InvokerFromSignedProposal(sp), deployAuditDetails(depSpec), err)
 		if err != nil {
 			return shim.Error(err.Error())
 		}
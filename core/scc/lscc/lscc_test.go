@@ -27,6 +27,7 @@ import (
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/common/config"
 	"github.com/hyperledger/fabric/common/mocks/scc"
 	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/common/util"
@@ -45,6 +46,7 @@ import (
 	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/utils"
 	putils "github.com/hyperledger/fabric/protos/utils"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -99,7 +101,7 @@ func testInstall(t *testing.T, ccname string, version string, path string, expec
 	}
 
 	// Init the policy checker
-	identityDeserializer := &policymocks.MockIdentityDeserializer{[]byte("Alice"), []byte("msg1")}
+	identityDeserializer := &policymocks.MockIdentityDeserializer{Identity: []byte("Alice"), Msg: []byte("msg1")}
 	policyManagerGetter := &policymocks.MockChannelPolicyManagerGetter{
 		Managers: map[string]policies.Manager{
 			"test": &policymocks.MockChannelPolicyManager{MockPolicy: &policymocks.MockPolicy{Deserializer: identityDeserializer}},
@@ -174,6 +176,130 @@ func testInstall(t *testing.T, ccname string, version string, path string, expec
 	}
 }
 
+//TestAnalyzeChaincode tests the analysis of the chaincodes being installed in each mode
+func TestAnalyzeChaincode(t *testing.T) {
+	defer viper.Set("chaincode.golang.analysis", "")
+
+	path := "example.com/cc"
+	cds := func(src string) *pb.ChaincodeDeploymentSpec {
+		codePackageBytes := bytes.NewBuffer(nil)
+		gz := gzip.NewWriter(codePackageBytes)
+		tw := tar.NewWriter(gz)
+		assert.NoError(t, cutil.WriteBytesToPackage("src/"+path+"/cc.go", []byte(src), tw))
+		tw.Close()
+		gz.Close()
+		spec := &pb.ChaincodeSpec{Type: pb.ChaincodeSpec_GOLANG, ChaincodeId: &pb.ChaincodeID{Name: "cc", Path: path, Version: "0"}}
+		return &pb.ChaincodeDeploymentSpec{ChaincodeSpec: spec, CodePackage: codePackageBytes.Bytes()}
+	}
+	nonDeterministic := cds("package main\n\nvar counter int\n")
+	deterministic := cds("package main\n\nfunc main() {}\n")
+
+	for _, mode := range []string{"", "off", "warn"} {
+		viper.Set("chaincode.golang.analysis", mode)
+		assert.NoError(t, analyzeChaincode(nonDeterministic), "Should not have rejected the chaincode in mode %q", mode)
+	}
+
+	viper.Set("chaincode.golang.analysis", "reject")
+	assert.NoError(t, analyzeChaincode(deterministic))
+	err := analyzeChaincode(nonDeterministic)
+	assert.IsType(t, NonDeterministicCCErr(""), err)
+	assert.Contains(t, err.Error(), "package variable counter")
+	assert.Error(t, analyzeChaincode(cds("package main\n\nfunc main() {")), "Should have rejected a chaincode failing the analysis")
+
+	nonDeterministic.ChaincodeSpec.Type = pb.ChaincodeSpec_JAVA
+	assert.NoError(t, analyzeChaincode(nonDeterministic), "Should only have analyzed Go chaincodes")
+}
+
+//TestVerifyPackageSignature tests the verification of the detached signatures of the packages being installed
+func TestVerifyPackageSignature(t *testing.T) {
+	defer viper.Set("chaincode.signing.required", false)
+	defer viper.Set("chaincode.signing.policy", "")
+
+	cds, err := constructDeploymentSpec("signedcc", "example.com/cc", "0", nil, false)
+	assert.NoError(t, err)
+	ccpack, err := ccprovider.GetCCPackage(utils.MarshalOrPanic(cds))
+	assert.NoError(t, err)
+	signature, err := ccprovider.CreateDetachedSignature(ccpack, id)
+	assert.NoError(t, err)
+
+	signer, err := verifyPackageSignature(ccpack, nil)
+	assert.NoError(t, err, "Should have accepted a package without signature")
+	assert.Nil(t, signer)
+
+	signer, err = verifyPackageSignature(ccpack, signature)
+	assert.NoError(t, err, "Should have trusted the members of the local MSP by default")
+	assert.Equal(t, sid, signer)
+
+	viper.Set("chaincode.signing.required", true)
+	_, err = verifyPackageSignature(ccpack, nil)
+	assert.IsType(t, InvalidPackageSignatureErr(""), err)
+	_, err = verifyPackageSignature(ccpack, []byte("garbage"))
+	assert.IsType(t, InvalidPackageSignatureErr(""), err)
+
+	other, err := constructDeploymentSpec("signedcc", "example.com/cc", "1", nil, false)
+	assert.NoError(t, err)
+	otherpack, err := ccprovider.GetCCPackage(utils.MarshalOrPanic(other))
+	assert.NoError(t, err)
+	_, err = verifyPackageSignature(otherpack, signature)
+	assert.IsType(t, InvalidPackageSignatureErr(""), err, "Should have rejected the signature of another package")
+
+	viper.Set("chaincode.signing.policy", "OR('OtherMSP.member')")
+	_, err = verifyPackageSignature(ccpack, signature)
+	assert.IsType(t, InvalidPackageSignatureErr(""), err, "Should have rejected an untrusted signer")
+
+	viper.Set("chaincode.signing.policy", "AND(")
+	_, err = verifyPackageSignature(ccpack, signature)
+	assert.Error(t, err)
+}
+
+//TestInstallWithSignature tests that the verified signer of an installed package is recorded
+func TestInstallWithSignature(t *testing.T) {
+	scc := new(LifeCycleSysCC)
+	stub := shim.NewMockStub("lscc", scc)
+
+	if res := stub.MockInit("1", nil); res.Status != shim.OK {
+		fmt.Println("Init failed", string(res.Message))
+		t.FailNow()
+	}
+
+	// Init the policy checker
+	identityDeserializer := &policymocks.MockIdentityDeserializer{Identity: []byte("Alice"), Msg: []byte("msg1")}
+	policyManagerGetter := &policymocks.MockChannelPolicyManagerGetter{
+		Managers: map[string]policies.Manager{
+			"test": &policymocks.MockChannelPolicyManager{MockPolicy: &policymocks.MockPolicy{Deserializer: identityDeserializer}},
+		},
+	}
+	scc.policyChecker = policy.NewPolicyChecker(
+		policyManagerGetter,
+		identityDeserializer,
+		&policymocks.MockMSPPrincipalGetter{Principal: []byte("Alice")},
+	)
+
+	cds, err := constructDeploymentSpec("signedcc", "example.com/cc", "0", nil, false)
+	assert.NoError(t, err)
+	b := utils.MarshalOrPanic(cds)
+	ccpack, err := ccprovider.GetCCPackage(b)
+	assert.NoError(t, err)
+	signature, err := ccprovider.CreateDetachedSignature(ccpack, id)
+	assert.NoError(t, err)
+
+	defer os.RemoveAll(lscctestpath + "/signers")
+	defer os.Remove(lscctestpath + "/signedcc.0")
+	sProp, _ := utils.MockSignedEndorserProposalOrPanic("", &pb.ChaincodeSpec{}, []byte("Alice"), []byte("msg1"))
+	identityDeserializer.Msg = sProp.ProposalBytes
+	sProp.Signature = sProp.ProposalBytes
+	res := stub.MockInvokeWithSignedProposal("1", [][]byte{[]byte(INSTALL), b, signature}, sProp)
+	assert.Equal(t, int32(shim.OK), res.Status, res.Message)
+
+	res = stub.MockInvokeWithSignedProposal("1", [][]byte{[]byte(GETINSTALLEDCHAINCODES)}, sProp)
+	assert.Equal(t, int32(shim.OK), res.Status, res.Message)
+	cqr := &pb.ChaincodeQueryResponse{}
+	assert.NoError(t, proto.Unmarshal(res.Payload, cqr))
+	assert.Len(t, cqr.Chaincodes, 1)
+	assert.Equal(t, "signedcc", cqr.Chaincodes[0].Name)
+	assert.Equal(t, sid, cqr.Chaincodes[0].Signer)
+}
+
 //TestReinstall tests the install function
 func TestReinstall(t *testing.T) {
 	scc := new(LifeCycleSysCC)
@@ -253,7 +379,7 @@ func testDeploy(t *testing.T, ccname string, version string, path string, forceB
 	}
 
 	// Init the policy checker
-	identityDeserializer := &policymocks.MockIdentityDeserializer{[]byte("Alice"), []byte("msg1")}
+	identityDeserializer := &policymocks.MockIdentityDeserializer{Identity: []byte("Alice"), Msg: []byte("msg1")}
 	policyManagerGetter := &policymocks.MockChannelPolicyManagerGetter{
 		Managers: map[string]policies.Manager{
 			"test": &policymocks.MockChannelPolicyManager{MockPolicy: &policymocks.MockPolicy{Deserializer: identityDeserializer}},
@@ -382,7 +508,7 @@ func TestMultipleDeploy(t *testing.T) {
 	}
 
 	// Init the policy checker
-	identityDeserializer := &policymocks.MockIdentityDeserializer{[]byte("Alice"), []byte("msg1")}
+	identityDeserializer := &policymocks.MockIdentityDeserializer{Identity: []byte("Alice"), Msg: []byte("msg1")}
 	policyManagerGetter := &policymocks.MockChannelPolicyManagerGetter{
 		Managers: map[string]policies.Manager{
 			"test": &policymocks.MockChannelPolicyManager{MockPolicy: &policymocks.MockPolicy{Deserializer: identityDeserializer}},
@@ -468,7 +594,7 @@ func TestRetryFailedDeploy(t *testing.T) {
 		t.FailNow()
 	}
 	// Init the policy checker
-	identityDeserializer := &policymocks.MockIdentityDeserializer{[]byte("Alice"), []byte("msg1")}
+	identityDeserializer := &policymocks.MockIdentityDeserializer{Identity: []byte("Alice"), Msg: []byte("msg1")}
 	policyManagerGetter := &policymocks.MockChannelPolicyManagerGetter{
 		Managers: map[string]policies.Manager{
 			"test": &policymocks.MockChannelPolicyManager{MockPolicy: &policymocks.MockPolicy{Deserializer: identityDeserializer}},
@@ -532,7 +658,7 @@ func TestTamperChaincode(t *testing.T) {
 	}
 
 	// Init the policy checker
-	identityDeserializer := &policymocks.MockIdentityDeserializer{[]byte("Alice"), []byte("msg1")}
+	identityDeserializer := &policymocks.MockIdentityDeserializer{Identity: []byte("Alice"), Msg: []byte("msg1")}
 	policyManagerGetter := &policymocks.MockChannelPolicyManagerGetter{
 		Managers: map[string]policies.Manager{
 			"test": &policymocks.MockChannelPolicyManager{MockPolicy: &policymocks.MockPolicy{Deserializer: identityDeserializer}},
@@ -629,7 +755,7 @@ func TestIPolDeployDefaultFail(t *testing.T) {
 	}
 
 	// Init the policy checker
-	identityDeserializer := &policymocks.MockIdentityDeserializer{[]byte("Alice"), []byte("msg1")}
+	identityDeserializer := &policymocks.MockIdentityDeserializer{Identity: []byte("Alice"), Msg: []byte("msg1")}
 	policyManagerGetter := &policymocks.MockChannelPolicyManagerGetter{
 		Managers: map[string]policies.Manager{
 			chainid: &policymocks.MockChannelPolicyManager{MockPolicy: &policymocks.MockPolicy{Deserializer: identityDeserializer}},
@@ -675,7 +801,7 @@ func testIPolDeploy(t *testing.T, iPol string, successExpected bool) {
 	}
 
 	// Init the policy checker
-	identityDeserializer := &policymocks.MockIdentityDeserializer{[]byte("Alice"), []byte("msg1")}
+	identityDeserializer := &policymocks.MockIdentityDeserializer{Identity: []byte("Alice"), Msg: []byte("msg1")}
 	policyManagerGetter := &policymocks.MockChannelPolicyManagerGetter{
 		Managers: map[string]policies.Manager{
 			chainid: &policymocks.MockChannelPolicyManager{MockPolicy: &policymocks.MockPolicy{Deserializer: identityDeserializer}},
@@ -813,6 +939,74 @@ func testUpgrade(t *testing.T, ccname string, version string, newccname string,
 	}
 }
 
+//TestIPolDefault tests that the default instantiation policy of a chaincode package without a
+//policy is bound to the MSP of the instantiator, and kept on upgrade
+func TestIPolDefault(t *testing.T) {
+	sysccprovider.RegisterSystemChaincodeProviderFactory(&scc.MocksccProviderFactory{Capabilities: []string{config.InstantiationPolicyBindingCapability}})
+	defer sysccprovider.RegisterSystemChaincodeProviderFactory(&scc.MocksccProviderFactory{})
+
+	scc := new(LifeCycleSysCC)
+	stub := shim.NewMockStub("lscc", scc)
+	if res := stub.MockInit("1", nil); res.Status != shim.OK {
+		t.Fatalf("Init failed %s", string(res.Message))
+	}
+
+	path := "github.com/hyperledger/fabric/examples/chaincode/go/chaincode_example02"
+	cds, err := constructDeploymentSpec("example02", path, "0", [][]byte{[]byte("init"), []byte("a"), []byte("100"), []byte("b"), []byte("200")}, true)
+	assert.NoError(t, err)
+	defer os.Remove(lscctestpath + "/example02.0")
+	cdsbytes, err := proto.Marshal(cds)
+	assert.NoError(t, err)
+
+	sProp, _ := putils.MockSignedEndorserProposal2OrPanic(chainid, &pb.ChaincodeSpec{}, id)
+	args := [][]byte{[]byte(DEPLOY), []byte(chainid), cdsbytes}
+	res := stub.MockInvokeWithSignedProposal("1", args, sProp)
+	assert.Equal(t, int32(shim.OK), res.Status, res.Message)
+	cd := &ccprovider.ChaincodeData{}
+	assert.NoError(t, proto.Unmarshal(res.Payload, cd))
+	expected := putils.MarshalOrPanic(cauthdsl.SignedByMspAdmin(mspid))
+	assert.Equal(t, expected, cd.InstantiationPolicy, "Should have bound the chaincode to the admins of the instantiator's MSP")
+
+	cds, err = constructDeploymentSpec("example02", path, "1", [][]byte{[]byte("init"), []byte("a"), []byte("100"), []byte("b"), []byte("200")}, true)
+	assert.NoError(t, err)
+	defer os.Remove(lscctestpath + "/example02.1")
+	cdsbytes, err = proto.Marshal(cds)
+	assert.NoError(t, err)
+
+	args = [][]byte{[]byte(UPGRADE), []byte(chainid), cdsbytes}
+	res = stub.MockInvokeWithSignedProposal("1", args, sProp)
+	assert.Equal(t, int32(shim.OK), res.Status, res.Message)
+	cd = &ccprovider.ChaincodeData{}
+	assert.NoError(t, proto.Unmarshal(res.Payload, cd))
+	assert.Equal(t, expected, cd.InstantiationPolicy, "Should have kept the instantiation policy on upgrade")
+}
+
+//TestIPolDefaultWithoutCapability tests that the default instantiation policy is satisfied by the
+//admins of any MSP of the channel when the channel lacks the InstantiationPolicyBinding capability
+func TestIPolDefaultWithoutCapability(t *testing.T) {
+	scc := new(LifeCycleSysCC)
+	stub := shim.NewMockStub("lscc", scc)
+	if res := stub.MockInit("1", nil); res.Status != shim.OK {
+		t.Fatalf("Init failed %s", string(res.Message))
+	}
+
+	path := "github.com/hyperledger/fabric/examples/chaincode/go/chaincode_example02"
+	cds, err := constructDeploymentSpec("example02", path, "0", [][]byte{[]byte("init"), []byte("a"), []byte("100"), []byte("b"), []byte("200")}, true)
+	assert.NoError(t, err)
+	defer os.Remove(lscctestpath + "/example02.0")
+	cdsbytes, err := proto.Marshal(cds)
+	assert.NoError(t, err)
+
+	sProp, _ := putils.MockSignedEndorserProposal2OrPanic(chainid, &pb.ChaincodeSpec{}, id)
+	args := [][]byte{[]byte(DEPLOY), []byte(chainid), cdsbytes}
+	res := stub.MockInvokeWithSignedProposal("1", args, sProp)
+	assert.Equal(t, int32(shim.OK), res.Status, res.Message)
+	cd := &ccprovider.ChaincodeData{}
+	assert.NoError(t, proto.Unmarshal(res.Payload, cd))
+	expected := putils.MarshalOrPanic(cauthdsl.SignedByAnyAdmin([]string{"DEFAULT"}))
+	assert.Equal(t, expected, cd.InstantiationPolicy)
+}
+
 //TestIPolUpgrade tests chaincode deploy with an instantiation policy
 func TestIPolUpgrade(t *testing.T) {
 	// default policy, this should succeed
@@ -829,7 +1023,7 @@ func testIPolUpgrade(t *testing.T, iPol string, successExpected bool) {
 		t.Fatalf("Init failed %s", string(res.Message))
 	}
 	// Init the policy checker
-	identityDeserializer := &policymocks.MockIdentityDeserializer{[]byte("Alice"), []byte("msg1")}
+	identityDeserializer := &policymocks.MockIdentityDeserializer{Identity: []byte("Alice"), Msg: []byte("msg1")}
 	policyManagerGetter := &policymocks.MockChannelPolicyManagerGetter{
 		Managers: map[string]policies.Manager{
 			chainid: &policymocks.MockChannelPolicyManager{MockPolicy: &policymocks.MockPolicy{Deserializer: identityDeserializer}},
@@ -919,7 +1113,7 @@ func TestGetAPIsWithoutInstall(t *testing.T) {
 		t.FailNow()
 	}
 	// Init the policy checker
-	identityDeserializer := &policymocks.MockIdentityDeserializer{[]byte("Alice"), []byte("msg1")}
+	identityDeserializer := &policymocks.MockIdentityDeserializer{Identity: []byte("Alice"), Msg: []byte("msg1")}
 	policyManagerGetter := &policymocks.MockChannelPolicyManagerGetter{
 		Managers: map[string]policies.Manager{
 			"test": &policymocks.MockChannelPolicyManager{MockPolicy: &policymocks.MockPolicy{Deserializer: identityDeserializer}},
@@ -1020,7 +1214,7 @@ func TestGetInstalledChaincodesAccessRights(t *testing.T) {
 	}
 
 	// Init the policy checker
-	identityDeserializer := &policymocks.MockIdentityDeserializer{[]byte("Alice"), []byte("msg1")}
+	identityDeserializer := &policymocks.MockIdentityDeserializer{Identity: []byte("Alice"), Msg: []byte("msg1")}
 	policyManagerGetter := &policymocks.MockChannelPolicyManagerGetter{
 		Managers: map[string]policies.Manager{
 			"test": &policymocks.MockChannelPolicyManager{MockPolicy: &policymocks.MockPolicy{Deserializer: identityDeserializer}},
@@ -1064,7 +1258,7 @@ func TestGetChaincodesAccessRights(t *testing.T) {
 	}
 
 	// Init the policy checker
-	identityDeserializer := &policymocks.MockIdentityDeserializer{[]byte("Alice"), []byte("msg1")}
+	identityDeserializer := &policymocks.MockIdentityDeserializer{Identity: []byte("Alice"), Msg: []byte("msg1")}
 	policyManagerGetter := &policymocks.MockChannelPolicyManagerGetter{
 		Managers: map[string]policies.Manager{
 			"test": &policymocks.MockChannelPolicyManager{MockPolicy: &policymocks.MockPolicy{Deserializer: identityDeserializer}},
@@ -1108,7 +1302,7 @@ func TestGetCCAccessRights(t *testing.T) {
 	}
 
 	// Init the policy checker
-	identityDeserializer := &policymocks.MockIdentityDeserializer{[]byte("Alice"), []byte("msg1")}
+	identityDeserializer := &policymocks.MockIdentityDeserializer{Identity: []byte("Alice"), Msg: []byte("msg1")}
 	policyManagerGetter := &policymocks.MockChannelPolicyManagerGetter{
 		Managers: map[string]policies.Manager{
 			"test": &policymocks.MockChannelPolicyManager{MockPolicy: &policymocks.MockPolicy{Deserializer: identityDeserializer}},
@@ -21,6 +21,8 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/inclusionproof"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
 	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/core/chaincode/shim"
@@ -147,6 +149,10 @@ func TestQueryGetBlockByTxID(t *testing.T) {
 	args := [][]byte{[]byte(GetBlockByTxID), []byte(chainid), []byte("")}
 	res := stub.MockInvoke("1", args)
 	assert.Equal(t, int32(shim.ERROR), res.Status, "GetBlockByTxID should have failed with blank txId.")
+
+	args = [][]byte{[]byte(GetTransactionProof), []byte(chainid), []byte("")}
+	res = stub.MockInvoke("2", args)
+	assert.Equal(t, int32(shim.ERROR), res.Status, "GetTransactionProof should have failed with blank txId.")
 }
 
 func TestFailingAccessControl(t *testing.T) {
@@ -244,6 +250,14 @@ func TestQueryGeneratedBlock(t *testing.T) {
 					args = [][]byte{[]byte(GetTransactionByID), []byte(chainid), []byte(chdr.TxId)}
 					res = stub.MockInvoke("4", args)
 					assert.Equal(t, int32(shim.OK), res.Status, "GetTransactionById should have succeeded for txid: %s", chdr.TxId)
+
+					args = [][]byte{[]byte(GetTransactionProof), []byte(chainid), []byte(chdr.TxId)}
+					res = stub.MockInvoke("5", args)
+					assert.Equal(t, int32(shim.OK), res.Status, "GetTransactionProof should have succeeded for txid: %s", chdr.TxId)
+					proof := &common.InclusionProof{}
+					assert.NoError(t, proto.Unmarshal(res.Payload, proof))
+					assert.Equal(t, ebytes, proof.Data[proof.TxIndex])
+					assert.Equal(t, block1.Header.DataHash, inclusionproof.DataHash(proof))
 				}
 			}
 		}
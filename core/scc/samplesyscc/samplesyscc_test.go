@@ -229,6 +229,18 @@ func (*mockStub) GetTxTimestamp() (*timestamp.Timestamp, error) {
 	panic("implement me")
 }
 
+func (*mockStub) GetTxSeed() ([]byte, error) {
+	panic("implement me")
+}
+
 func (*mockStub) SetEvent(name string, payload []byte) error {
 	panic("implement me")
 }
+
+func (*mockStub) IncCounter(name string, delta int64) error {
+	panic("implement me")
+}
+
+func (*mockStub) SetGauge(name string, value float64) error {
+	panic("implement me")
+}
@@ -65,6 +65,11 @@ func (c *sccProviderImpl) GetQueryExecutorForLedger(cid string) (ledger.QueryExe
 	return l.NewQueryExecutor()
 }
 
+// HasCapability returns true if the capability is enabled on the specified channel
+func (c *sccProviderImpl) HasCapability(cid, capability string) bool {
+	return peer.HasCapability(cid, capability)
+}
+
 // IsSysCCAndNotInvokableExternal returns true if the supplied chaincode is
 // ia system chaincode and it NOT nvokable
 func (c *sccProviderImpl) IsSysCCAndNotInvokableExternal(name string) bool {
@@ -24,6 +24,7 @@ import (
 	"github.com/golang/protobuf/proto"
 
 	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/common/config"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/core/chaincode/shim"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
@@ -379,8 +380,26 @@ func (vscc *ValidatorOneValidSignature) ValidateLSCCInvocation(stub shim.Chainco
 				return err
 			}
 
+			/*********************************************************/
+			/* security check 3 - check the new instantiation policy */
+			/*********************************************************/
+			// the upgrade must not hand the chaincode over to identities
+			// its creator does not belong to; the check is only made on
+			// the channels with the capability, so that all the peers of
+			// a channel keep validating its upgrades alike
+			if vscc.sccprovider.HasCapability(chid, config.InstantiationPolicyBindingCapability) {
+				pol = cdRWSet.InstantiationPolicy
+				if pol == nil {
+					return fmt.Errorf("No installation policy was specified")
+				}
+				err = vscc.checkInstantiationPolicy(chid, env, pol, payl)
+				if err != nil {
+					return err
+				}
+			}
+
 			/**********************************************************/
-			/* security check 3 - existing cc's version was different */
+			/* security check 4 - existing cc's version was different */
 			/**********************************************************/
 			if cdLedger.Version == cdsArgs.ChaincodeSpec.ChaincodeId.Version {
 				return fmt.Errorf("Existing version of the cc on the ledger (%s) should be different from the upgraded one", cdsArgs.ChaincodeSpec.ChaincodeId.Version)
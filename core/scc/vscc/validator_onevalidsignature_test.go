@@ -28,6 +28,7 @@ import (
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/common/config"
 	lm "github.com/hyperledger/fabric/common/mocks/ledger"
 	"github.com/hyperledger/fabric/common/mocks/scc"
 	"github.com/hyperledger/fabric/common/util"
@@ -1143,7 +1144,7 @@ func TestValidateUpgradeOK(t *testing.T) {
 
 	ccver = "2"
 
-	simresres, err := createCCDataRWset(ccname, ccname, ccver, nil)
+	simresres, err := createCCDataRWset(ccname, ccname, ccver, cauthdsl.MarshaledAcceptAllPolicy)
 	assert.NoError(t, err)
 
 	tx, err := createLSCCTx(ccname, ccver, lscc.UPGRADE, simresres)
@@ -1166,6 +1167,37 @@ func TestValidateUpgradeOK(t *testing.T) {
 	if res := stub.MockInvoke("1", args); res.Status != shim.OK {
 		t.Fatalf("vscc invoke returned err %s", res.Message)
 	}
+
+	// the new instantiation policy is only checked on the channels with the capability
+	simresres, err = createCCDataRWset(ccname, ccname, ccver, cauthdsl.MarshaledRejectAllPolicy)
+	assert.NoError(t, err)
+
+	tx, err = createLSCCTx(ccname, ccver, lscc.UPGRADE, simresres)
+	if err != nil {
+		t.Fatalf("createTx returned err %s", err)
+	}
+
+	envBytes, err = utils.GetBytesEnvelope(tx)
+	if err != nil {
+		t.Fatalf("GetBytesEnvelope returned err %s", err)
+	}
+
+	args = [][]byte{[]byte("dv"), envBytes, policy}
+	if res := stub.MockInvoke("1", args); res.Status != shim.OK {
+		t.Fatalf("vscc invoke returned err %s", res.Message)
+	}
+
+	// bad path: the new instantiation policy is not satisfied by the creator
+	sysccprovider.RegisterSystemChaincodeProviderFactory(&scc.MocksccProviderFactory{
+		Qe:           lm.NewMockQueryExecutor(State),
+		Capabilities: []string{config.InstantiationPolicyBindingCapability},
+	})
+	if res := stub.MockInit("1", [][]byte{}); res.Status != shim.OK {
+		t.Fatalf("Init failed %s", res.Message)
+	}
+	if res := stub.MockInvoke("1", args); res.Status == shim.OK {
+		t.Fatalf("vscc invocation should have failed")
+	}
 }
 
 func TestInvalidateUpgradeBadVersion(t *testing.T) {
@@ -1297,7 +1329,7 @@ func TestValidateUpgradeWithPoliciesOK(t *testing.T) {
 
 	ccver = "2"
 
-	simresres, err := createCCDataRWset(ccname, ccname, ccver, nil)
+	simresres, err := createCCDataRWset(ccname, ccname, ccver, cauthdsl.MarshaledAcceptAllPolicy)
 	assert.NoError(t, err)
 
 	tx, err := createLSCCTx(ccname, ccver, lscc.UPGRADE, simresres)
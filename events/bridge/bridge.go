@@ -0,0 +1,272 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package bridge republishes the block and chaincode events of the channels of a peer to an
+// external message bus, so that enterprise systems can integrate with the ledger without running
+// an SDK event listener. Blocks are read back from the ledger and the number of the next block to
+// publish is checkpointed once all its events have been acknowledged by the bus, which makes the
+// delivery at-least-once: after a crash, the events of the block being published are sent again.
+package bridge
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/protos/common"
+)
+
+var logger = flogging.MustGetLogger("eventbridge")
+
+// Publisher publishes messages to a message bus
+type Publisher interface {
+	// Publish sends a message to a topic and returns once the bus acknowledged it
+	Publish(topic string, key, value []byte) error
+
+	// Close releases the connections to the bus
+	Close() error
+}
+
+// Ledger is the part of the ledger of a channel the bridge reads the blocks from
+type Ledger interface {
+	GetBlockchainInfo() (*common.BlockchainInfo, error)
+	GetBlockByNumber(blockNumber uint64) (*common.Block, error)
+}
+
+// Config configures a Bridge
+type Config struct {
+	// BlockTopic is the topic the filtered block events are published to, none if empty
+	BlockTopic string
+	// ChaincodeEventTopic is the topic the chaincode events are published to, none if empty
+	ChaincodeEventTopic string
+	// Chaincodes restricts the chaincode events published to those of the listed chaincodes.
+	// The events of all chaincodes are published if empty
+	Chaincodes []string
+	// CheckpointDir is the directory holding the checkpoint of each channel
+	CheckpointDir string
+	// RetryInterval is the delay before publishing a block again after a failure. The ledger
+	// is also polled at this interval in case a commit notification is missed
+	RetryInterval time.Duration
+	// FromGenesis makes the channels without a checkpoint publish their events from the genesis
+	// block rather than from the next block committed
+	FromGenesis bool
+}
+
+// Bridge publishes the events of the channels registered with it
+type Bridge struct {
+	conf      Config
+	publisher Publisher
+
+	lock     sync.Mutex
+	channels map[string]*channel
+	stop     chan struct{}
+	wg       sync.WaitGroup
+}
+
+type channel struct {
+	id     string
+	ledger Ledger
+	notify chan struct{}
+}
+
+// New creates a Bridge publishing to publisher
+func New(conf Config, publisher Publisher) (*Bridge, error) {
+	if conf.BlockTopic == "" && conf.ChaincodeEventTopic == "" {
+		return nil, fmt.Errorf("no topic to publish to")
+	}
+	if conf.CheckpointDir == "" {
+		return nil, fmt.Errorf("no checkpoint directory")
+	}
+	if conf.RetryInterval <= 0 {
+		return nil, fmt.Errorf("retry interval must be positive, got %s", conf.RetryInterval)
+	}
+	if err := os.MkdirAll(conf.CheckpointDir, 0755); err != nil {
+		return nil, fmt.Errorf("error creating the checkpoint directory: %s", err)
+	}
+	return &Bridge{
+		conf:      conf,
+		publisher: publisher,
+		channels:  make(map[string]*channel),
+		stop:      make(chan struct{}),
+	}, nil
+}
+
+// Register starts publishing the events of a channel, resuming from its checkpoint
+func (b *Bridge) Register(channelID string, ledger Ledger) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if _, exists := b.channels[channelID]; exists {
+		return fmt.Errorf("channel %s is already registered", channelID)
+	}
+	next, err := b.loadCheckpoint(channelID, ledger)
+	if err != nil {
+		return err
+	}
+	// Record the starting point so that the blocks committed before a restart are not skipped
+	if err := b.saveCheckpoint(channelID, next); err != nil {
+		return fmt.Errorf("error saving the checkpoint of channel %s: %s", channelID, err)
+	}
+	ch := &channel{id: channelID, ledger: ledger, notify: make(chan struct{}, 1)}
+	b.channels[channelID] = ch
+
+	b.wg.Add(1)
+	go b.run(ch, next)
+	logger.Infof("Publishing the events of channel %s from block %d", channelID, next)
+	return nil
+}
+
+// Notify signals that a block was committed to a channel
+func (b *Bridge) Notify(channelID string) {
+	b.lock.Lock()
+	ch, exists := b.channels[channelID]
+	b.lock.Unlock()
+	if !exists {
+		return
+	}
+	select {
+	case ch.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Close stops publishing and closes the publisher
+func (b *Bridge) Close() error {
+	close(b.stop)
+	b.wg.Wait()
+	return b.publisher.Close()
+}
+
+func (b *Bridge) run(ch *channel, next uint64) {
+	defer b.wg.Done()
+	ticker := time.NewTicker(b.conf.RetryInterval)
+	defer ticker.Stop()
+
+	for {
+		next = b.publishAvailable(ch, next)
+		select {
+		case <-ch.notify:
+		case <-ticker.C:
+		case <-b.stop:
+			return
+		}
+	}
+}
+
+// publishAvailable publishes the events of the blocks committed from next onwards and returns the
+// number of the next block to publish. It stops at the first failure, to be retried later
+func (b *Bridge) publishAvailable(ch *channel, next uint64) uint64 {
+	info, err := ch.ledger.GetBlockchainInfo()
+	if err != nil {
+		logger.Errorf("Channel [%s]: Failed reading the ledger height: %s", ch.id, err)
+		return next
+	}
+	for ; next < info.Height; next++ {
+		select {
+		case <-b.stop:
+			return next
+		default:
+		}
+
+		block, err := ch.ledger.GetBlockByNumber(next)
+		if err != nil {
+			logger.Errorf("Channel [%s]: Failed reading block %d: %s", ch.id, next, err)
+			return next
+		}
+		if err := b.publishBlock(ch.id, block); err != nil {
+			logger.Warningf("Channel [%s]: Failed publishing the events of block %d, retrying in %s: %s",
+				ch.id, next, b.conf.RetryInterval, err)
+			return next
+		}
+		if err := b.saveCheckpoint(ch.id, next+1); err != nil {
+			// The events of the block will be published again on restart
+			logger.Errorf("Channel [%s]: Failed saving the checkpoint after block %d: %s", ch.id, next, err)
+		}
+	}
+	return next
+}
+
+func (b *Bridge) publishBlock(channelID string, block *common.Block) error {
+	blockEvent, ccEvents, err := extractEvents(channelID, block)
+	if err != nil {
+		return err
+	}
+	key := []byte(channelID)
+
+	if b.conf.BlockTopic != "" {
+		if err := b.publisher.Publish(b.conf.BlockTopic, key, marshalOrPanic(blockEvent)); err != nil {
+			return err
+		}
+	}
+	if b.conf.ChaincodeEventTopic == "" {
+		return nil
+	}
+	for _, ccEvent := range ccEvents {
+		if !b.publishesChaincode(ccEvent.ChaincodeID) {
+			continue
+		}
+		if err := b.publisher.Publish(b.conf.ChaincodeEventTopic, key, marshalOrPanic(ccEvent)); err != nil {
+			return err
+		}
+	}
+	logger.Debugf("Channel [%s]: Published the events of block %d", channelID, block.Header.Number)
+	return nil
+}
+
+func (b *Bridge) publishesChaincode(chaincodeID string) bool {
+	if len(b.conf.Chaincodes) == 0 {
+		return true
+	}
+	for _, cc := range b.conf.Chaincodes {
+		if cc == chaincodeID {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *Bridge) checkpointPath(channelID string) string {
+	return filepath.Join(b.conf.CheckpointDir, channelID)
+}
+
+// loadCheckpoint returns the number of the next block to publish for a channel
+func (b *Bridge) loadCheckpoint(channelID string, ledger Ledger) (uint64, error) {
+	raw, err := ioutil.ReadFile(b.checkpointPath(channelID))
+	if err == nil {
+		next, err := strconv.ParseUint(strings.TrimSpace(string(raw)), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid checkpoint for channel %s: %s", channelID, err)
+		}
+		return next, nil
+	}
+	if !os.IsNotExist(err) {
+		return 0, fmt.Errorf("error reading the checkpoint of channel %s: %s", channelID, err)
+	}
+	if b.conf.FromGenesis {
+		return 0, nil
+	}
+	info, err := ledger.GetBlockchainInfo()
+	if err != nil {
+		return 0, fmt.Errorf("error reading the height of channel %s: %s", channelID, err)
+	}
+	return info.Height, nil
+}
+
+// saveCheckpoint atomically records the number of the next block to publish for a channel
+func (b *Bridge) saveCheckpoint(channelID string, next uint64) error {
+	path := b.checkpointPath(channelID)
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, []byte(strconv.FormatUint(next, 10)), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,301 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bridge
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/Shopify/sarama/mocks"
+	mmsp "github.com/hyperledger/fabric/common/mocks/msp"
+	"github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockLedger struct {
+	lock   sync.Mutex
+	blocks []*common.Block
+}
+
+func (ml *mockLedger) append(block *common.Block) {
+	ml.lock.Lock()
+	defer ml.lock.Unlock()
+	ml.blocks = append(ml.blocks, block)
+}
+
+func (ml *mockLedger) GetBlockchainInfo() (*common.BlockchainInfo, error) {
+	ml.lock.Lock()
+	defer ml.lock.Unlock()
+	return &common.BlockchainInfo{Height: uint64(len(ml.blocks))}, nil
+}
+
+func (ml *mockLedger) GetBlockByNumber(blockNumber uint64) (*common.Block, error) {
+	ml.lock.Lock()
+	defer ml.lock.Unlock()
+	if blockNumber >= uint64(len(ml.blocks)) {
+		return nil, fmt.Errorf("no block %d", blockNumber)
+	}
+	return ml.blocks[blockNumber], nil
+}
+
+type message struct {
+	topic string
+	key   string
+	value []byte
+}
+
+type mockPublisher struct {
+	lock     sync.Mutex
+	fail     bool
+	messages []message
+	closed   bool
+}
+
+func (mp *mockPublisher) setFail(fail bool) {
+	mp.lock.Lock()
+	defer mp.lock.Unlock()
+	mp.fail = fail
+}
+
+func (mp *mockPublisher) Publish(topic string, key, value []byte) error {
+	mp.lock.Lock()
+	defer mp.lock.Unlock()
+	if mp.fail {
+		return fmt.Errorf("bus is down")
+	}
+	mp.messages = append(mp.messages, message{topic: topic, key: string(key), value: value})
+	return nil
+}
+
+func (mp *mockPublisher) Close() error {
+	mp.lock.Lock()
+	defer mp.lock.Unlock()
+	mp.closed = true
+	return nil
+}
+
+// blockEvents returns the numbers of the blocks published so far
+func (mp *mockPublisher) blockEvents(t *testing.T) []uint64 {
+	mp.lock.Lock()
+	defer mp.lock.Unlock()
+	var numbers []uint64
+	for _, msg := range mp.messages {
+		if msg.topic != "blocks" {
+			continue
+		}
+		event := &BlockEvent{}
+		assert.NoError(t, json.Unmarshal(msg.value, event))
+		numbers = append(numbers, event.Number)
+	}
+	return numbers
+}
+
+func makeTx(t *testing.T, chaincode string, event *pb.ChaincodeEvent) []byte {
+	var events []byte
+	if event != nil {
+		events = utils.MarshalOrPanic(event)
+	}
+	signer, err := mmsp.NewNoopMsp().GetDefaultSigningIdentity()
+	assert.NoError(t, err)
+	creator, err := signer.Serialize()
+	assert.NoError(t, err)
+	ccid := &pb.ChaincodeID{Name: chaincode, Version: "1"}
+	prop, _, err := utils.CreateChaincodeProposal(common.HeaderType_ENDORSER_TRANSACTION, "mychannel",
+		&pb.ChaincodeInvocationSpec{ChaincodeSpec: &pb.ChaincodeSpec{ChaincodeId: ccid}}, creator)
+	assert.NoError(t, err)
+	presp, err := utils.CreateProposalResponse(prop.Header, prop.Payload, &pb.Response{Status: 200}, nil, events, ccid, nil, signer)
+	assert.NoError(t, err)
+	env, err := utils.CreateSignedTx(prop, signer, presp)
+	assert.NoError(t, err)
+	return utils.MarshalOrPanic(env)
+}
+
+func makeBlock(number uint64, txs ...[]byte) *common.Block {
+	block := common.NewBlock(number, nil)
+	block.Data.Data = txs
+	block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = make([]byte, len(txs))
+	return block
+}
+
+func waitFor(t *testing.T, condition func() bool) {
+	for deadline := time.Now().Add(time.Second); !condition(); time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("Condition not met within a second")
+		}
+	}
+}
+
+func newTestBridge(t *testing.T, dir string, conf Config, pub Publisher) *Bridge {
+	conf.CheckpointDir = dir
+	if conf.RetryInterval == 0 {
+		conf.RetryInterval = 10 * time.Millisecond
+	}
+	b, err := New(conf, pub)
+	assert.NoError(t, err)
+	return b
+}
+
+func TestNew(t *testing.T) {
+	dir, err := ioutil.TempDir("", "eventbridge")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	_, err = New(Config{CheckpointDir: dir, RetryInterval: time.Second}, &mockPublisher{})
+	assert.Error(t, err, "Should require a topic")
+	_, err = New(Config{BlockTopic: "blocks", RetryInterval: time.Second}, &mockPublisher{})
+	assert.Error(t, err, "Should require a checkpoint directory")
+	_, err = New(Config{BlockTopic: "blocks", CheckpointDir: dir}, &mockPublisher{})
+	assert.Error(t, err, "Should require a retry interval")
+	_, err = New(Config{BlockTopic: "blocks", CheckpointDir: filepath.Join(dir, "sub"), RetryInterval: time.Second}, &mockPublisher{})
+	assert.NoError(t, err)
+}
+
+func TestExtractEvents(t *testing.T) {
+	block := makeBlock(3,
+		makeTx(t, "mycc", &pb.ChaincodeEvent{ChaincodeId: "mycc", TxId: "tx1", EventName: "transfer", Payload: []byte("100")}),
+		makeTx(t, "mycc", &pb.ChaincodeEvent{ChaincodeId: "mycc", EventName: "transfer"}),
+		makeTx(t, "othercc", nil),
+		[]byte("garbage"),
+	)
+	block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER][1] = uint8(pb.TxValidationCode_MVCC_READ_CONFLICT)
+	block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER][3] = uint8(pb.TxValidationCode_BAD_PAYLOAD)
+
+	blockEvent, ccEvents, err := extractEvents("mychannel", block)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), blockEvent.Number)
+	assert.Len(t, blockEvent.Transactions, 4)
+	assert.Equal(t, "ENDORSER_TRANSACTION", blockEvent.Transactions[0].Type)
+	assert.Equal(t, "mycc", blockEvent.Transactions[0].ChaincodeID)
+	assert.Equal(t, "VALID", blockEvent.Transactions[0].ValidationCode)
+	assert.Equal(t, "MVCC_READ_CONFLICT", blockEvent.Transactions[1].ValidationCode)
+	assert.Equal(t, "othercc", blockEvent.Transactions[2].ChaincodeID)
+	assert.Equal(t, Transaction{ValidationCode: "BAD_PAYLOAD"}, blockEvent.Transactions[3],
+		"Malformed transactions should be reported without details")
+
+	// Only the valid transactions publish their chaincode event
+	assert.Len(t, ccEvents, 1)
+	assert.Equal(t, &ChaincodeEvent{
+		ChannelID:   "mychannel",
+		BlockNumber: 3,
+		TxID:        blockEvent.Transactions[0].TxID,
+		ChaincodeID: "mycc",
+		EventName:   "transfer",
+		Payload:     []byte("100"),
+	}, ccEvents[0])
+
+	_, _, err = extractEvents("mychannel", &common.Block{})
+	assert.Error(t, err)
+}
+
+func TestBridge(t *testing.T) {
+	dir, err := ioutil.TempDir("", "eventbridge")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ledger := &mockLedger{}
+	ledger.append(makeBlock(0))
+	ledger.append(makeBlock(1, makeTx(t, "mycc", &pb.ChaincodeEvent{ChaincodeId: "mycc", EventName: "a"})))
+
+	pub := &mockPublisher{}
+	conf := Config{BlockTopic: "blocks", ChaincodeEventTopic: "events", Chaincodes: []string{"mycc"}, RetryInterval: time.Hour}
+	b := newTestBridge(t, dir, conf, pub)
+
+	// Without a checkpoint, only the blocks committed from now on are published
+	assert.NoError(t, b.Register("mychannel", ledger))
+	assert.Error(t, b.Register("mychannel", ledger))
+	ledger.append(makeBlock(2,
+		makeTx(t, "mycc", &pb.ChaincodeEvent{ChaincodeId: "mycc", EventName: "b"}),
+		makeTx(t, "othercc", &pb.ChaincodeEvent{ChaincodeId: "othercc", EventName: "c"})))
+	b.Notify("mychannel")
+	b.Notify("otherchannel")
+	waitFor(t, func() bool { return len(pub.blockEvents(t)) == 1 })
+	assert.NoError(t, b.Close())
+	assert.True(t, pub.closed)
+
+	assert.Equal(t, []uint64{2}, pub.blockEvents(t))
+	assert.Len(t, pub.messages, 2, "Should have filtered out the events of othercc")
+	assert.Equal(t, "events", pub.messages[1].topic)
+	assert.Equal(t, "mychannel", pub.messages[1].key)
+	ccEvent := &ChaincodeEvent{}
+	assert.NoError(t, json.Unmarshal(pub.messages[1].value, ccEvent))
+	assert.Equal(t, "b", ccEvent.EventName)
+
+	checkpoint, err := ioutil.ReadFile(filepath.Join(dir, "mychannel"))
+	assert.NoError(t, err)
+	assert.Equal(t, "3", string(checkpoint))
+
+	// A restarted bridge resumes from the checkpoint
+	ledger.append(makeBlock(3))
+	pub = &mockPublisher{}
+	b = newTestBridge(t, dir, conf, pub)
+	assert.NoError(t, b.Register("mychannel", ledger))
+	waitFor(t, func() bool { return len(pub.blockEvents(t)) == 1 })
+	assert.NoError(t, b.Close())
+	assert.Equal(t, []uint64{3}, pub.blockEvents(t))
+}
+
+func TestBridgeRetry(t *testing.T) {
+	dir, err := ioutil.TempDir("", "eventbridge")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ledger := &mockLedger{}
+	ledger.append(makeBlock(0))
+	ledger.append(makeBlock(1))
+
+	pub := &mockPublisher{fail: true}
+	b := newTestBridge(t, dir, Config{BlockTopic: "blocks", FromGenesis: true}, pub)
+	assert.NoError(t, b.Register("mychannel", ledger))
+	defer b.Close()
+
+	time.Sleep(50 * time.Millisecond)
+	assert.Empty(t, pub.blockEvents(t))
+	checkpoint, err := ioutil.ReadFile(filepath.Join(dir, "mychannel"))
+	assert.NoError(t, err)
+	assert.Equal(t, "0", string(checkpoint), "Should not move the checkpoint past unpublished blocks")
+
+	// Publishing resumes once the bus is back, without missing any block
+	pub.setFail(false)
+	waitFor(t, func() bool { return len(pub.blockEvents(t)) == 2 })
+	assert.Equal(t, []uint64{0, 1}, pub.blockEvents(t))
+}
+
+func TestKafkaPublisher(t *testing.T) {
+	producer := mocks.NewSyncProducer(t, nil)
+	producer.ExpectSendMessageWithCheckerFunctionAndSucceed(func(value []byte) error {
+		if string(value) != "event" {
+			return fmt.Errorf("unexpected value %s", value)
+		}
+		return nil
+	})
+	producer.ExpectSendMessageAndFail(sarama.ErrNotEnoughReplicas)
+
+	kp := &kafkaPublisher{producer: producer}
+	assert.NoError(t, kp.Publish("blocks", []byte("mychannel"), []byte("event")))
+	assert.Equal(t, sarama.ErrNotEnoughReplicas, kp.Publish("blocks", []byte("mychannel"), []byte("event")))
+	assert.NoError(t, kp.Close())
+}
+
+func TestCheckpointCorrupted(t *testing.T) {
+	dir, err := ioutil.TempDir("", "eventbridge")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "mychannel"), []byte("garbage"), 0644))
+	b := newTestBridge(t, dir, Config{BlockTopic: "blocks"}, &mockPublisher{})
+	assert.Error(t, b.Register("mychannel", &mockLedger{}))
+	assert.NoError(t, b.Close())
+}
@@ -0,0 +1,160 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bridge
+
+import (
+	"encoding/json"
+	"fmt"
+
+	ledgerutil "github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+)
+
+// BlockEvent is the JSON message published for each block. It lists the transactions of the
+// block without their payload
+type BlockEvent struct {
+	ChannelID    string        `json:"channel_id"`
+	Number       uint64        `json:"number"`
+	Transactions []Transaction `json:"transactions"`
+}
+
+// Transaction summarizes a transaction of a block
+type Transaction struct {
+	TxID           string `json:"tx_id"`
+	Type           string `json:"type"`
+	ValidationCode string `json:"validation_code"`
+	ChaincodeID    string `json:"chaincode_id,omitempty"`
+}
+
+// ChaincodeEvent is the JSON message published for each event set by a valid transaction
+type ChaincodeEvent struct {
+	ChannelID   string `json:"channel_id"`
+	BlockNumber uint64 `json:"block_number"`
+	TxID        string `json:"tx_id"`
+	ChaincodeID string `json:"chaincode_id"`
+	EventName   string `json:"event_name"`
+	Payload     []byte `json:"payload"`
+}
+
+// extractEvents returns the block event and the chaincode events of a block
+func extractEvents(channelID string, block *common.Block) (*BlockEvent, []*ChaincodeEvent, error) {
+	if block.Header == nil || block.Data == nil {
+		return nil, nil, fmt.Errorf("block is incomplete")
+	}
+	blockEvent := &BlockEvent{ChannelID: channelID, Number: block.Header.Number, Transactions: []Transaction{}}
+	var ccEvents []*ChaincodeEvent
+
+	var txFilter ledgerutil.TxValidationFlags
+	if block.Metadata != nil && len(block.Metadata.Metadata) > int(common.BlockMetadataIndex_TRANSACTIONS_FILTER) {
+		txFilter = ledgerutil.TxValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	}
+
+	for i, envBytes := range block.Data.Data {
+		tx := Transaction{ValidationCode: pb.TxValidationCode_VALID.String()}
+		if i < len(txFilter) {
+			tx.ValidationCode = txFilter.Flag(i).String()
+		}
+		// Malformed transactions are committed as invalid ones, they are reported without details
+		// rather than holding back the events of the channel
+		payload, chdr, err := header(envBytes)
+		if err != nil {
+			logger.Warningf("Channel [%s]: Transaction %d of block %d is malformed: %s", channelID, i, block.Header.Number, err)
+			blockEvent.Transactions = append(blockEvent.Transactions, tx)
+			continue
+		}
+		tx.TxID = chdr.TxId
+		tx.Type = common.HeaderType(chdr.Type).String()
+
+		if common.HeaderType(chdr.Type) == common.HeaderType_ENDORSER_TRANSACTION {
+			if ccEvent := chaincodeEvent(&tx, payload); ccEvent != nil {
+				ccEvent.ChannelID = channelID
+				ccEvent.BlockNumber = block.Header.Number
+				ccEvents = append(ccEvents, ccEvent)
+			}
+		}
+		blockEvent.Transactions = append(blockEvent.Transactions, tx)
+	}
+	return blockEvent, ccEvents, nil
+}
+
+func header(envBytes []byte) (*common.Payload, *common.ChannelHeader, error) {
+	env, err := utils.GetEnvelopeFromBlock(envBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	payload, err := utils.GetPayload(env)
+	if err != nil {
+		return nil, nil, err
+	}
+	if payload.Header == nil {
+		return nil, nil, fmt.Errorf("transaction has no header")
+	}
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return nil, nil, err
+	}
+	return payload, chdr, nil
+}
+
+// chaincodeEvent sets the chaincode of an endorser transaction and returns the event it set, if
+// the transaction is valid
+func chaincodeEvent(tx *Transaction, payload *common.Payload) *ChaincodeEvent {
+	action, err := chaincodeAction(payload)
+	if err != nil {
+		logger.Warningf("Failed extracting the chaincode action of transaction %s: %s", tx.TxID, err)
+		return nil
+	}
+	if action.ChaincodeId != nil {
+		tx.ChaincodeID = action.ChaincodeId.Name
+	}
+	if len(action.Events) == 0 || tx.ValidationCode != pb.TxValidationCode_VALID.String() {
+		return nil
+	}
+	event, err := utils.GetChaincodeEvents(action.Events)
+	if err != nil {
+		logger.Warningf("Failed unmarshaling the chaincode event of transaction %s: %s", tx.TxID, err)
+		return nil
+	}
+	return &ChaincodeEvent{
+		TxID:        tx.TxID,
+		ChaincodeID: event.ChaincodeId,
+		EventName:   event.EventName,
+		Payload:     event.Payload,
+	}
+}
+
+func chaincodeAction(payload *common.Payload) (*pb.ChaincodeAction, error) {
+	tx, err := utils.GetTransaction(payload.Data)
+	if err != nil {
+		return nil, err
+	}
+	if len(tx.Actions) == 0 {
+		return nil, fmt.Errorf("transaction has no action")
+	}
+	ccActionPayload, err := utils.GetChaincodeActionPayload(tx.Actions[0].Payload)
+	if err != nil {
+		return nil, err
+	}
+	if ccActionPayload.Action == nil {
+		return nil, fmt.Errorf("transaction has no endorsed action")
+	}
+	prp, err := utils.GetProposalResponsePayload(ccActionPayload.Action.ProposalResponsePayload)
+	if err != nil {
+		return nil, err
+	}
+	return utils.GetChaincodeAction(prp.Extension)
+}
+
+func marshalOrPanic(v interface{}) []byte {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return raw
+}
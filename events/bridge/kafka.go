@@ -0,0 +1,60 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bridge
+
+import (
+	"crypto/tls"
+
+	"github.com/Shopify/sarama"
+)
+
+// KafkaConfig configures the connection to a Kafka cluster
+type KafkaConfig struct {
+	Brokers []string
+	// Version is the Kafka protocol version, e.g. 0.10.2.0
+	Version sarama.KafkaVersion
+	// TLS is the client TLS configuration, nil for plain connections
+	TLS *tls.Config
+}
+
+type kafkaPublisher struct {
+	producer sarama.SyncProducer
+}
+
+// NewKafkaPublisher creates a Publisher producing to a Kafka cluster. The messages are keyed by
+// channel, so that the events of a channel land in the same partition in the order of the blocks
+func NewKafkaPublisher(conf KafkaConfig) (Publisher, error) {
+	config := sarama.NewConfig()
+	config.Version = conf.Version
+	config.Net.TLS.Enable = conf.TLS != nil
+	config.Net.TLS.Config = conf.TLS
+	// Messages are acknowledged once all the in-sync replicas got them, and a single request is in
+	// flight so that retries do not reorder them
+	config.Net.MaxOpenRequests = 1
+	config.Producer.RequiredAcks = sarama.WaitForAll
+	config.Producer.Return.Successes = true
+	config.Producer.Partitioner = sarama.NewHashPartitioner
+
+	producer, err := sarama.NewSyncProducer(conf.Brokers, config)
+	if err != nil {
+		return nil, err
+	}
+	return &kafkaPublisher{producer: producer}, nil
+}
+
+func (kp *kafkaPublisher) Publish(topic string, key, value []byte) error {
+	_, _, err := kp.producer.SendMessage(&sarama.ProducerMessage{
+		Topic: topic,
+		Key:   sarama.ByteEncoder(key),
+		Value: sarama.ByteEncoder(value),
+	})
+	return err
+}
+
+func (kp *kafkaPublisher) Close() error {
+	return kp.producer.Close()
+}
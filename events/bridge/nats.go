@@ -0,0 +1,143 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bridge
+
+import (
+	"bufio"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NATSConfig configures the connection to a NATS server
+type NATSConfig struct {
+	// Address is the host:port of the server
+	Address string
+	// Timeout bounds the connection and the acknowledgement of each message
+	Timeout time.Duration
+	// TLS is the client TLS configuration, nil for plain connections
+	TLS *tls.Config
+}
+
+// natsPublisher speaks the client protocol of NATS. Each message is followed by a PING, and is
+// acknowledged once the server answers with a PONG, as the server processes the commands of a
+// connection in order. The connection is reopened on the next message after a failure
+type natsPublisher struct {
+	conf NATSConfig
+
+	lock   sync.Mutex
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// NewNATSPublisher creates a Publisher publishing to a NATS server. Topics are NATS subjects, and
+// the keys of the messages are dropped
+func NewNATSPublisher(conf NATSConfig) (Publisher, error) {
+	if conf.Timeout <= 0 {
+		return nil, fmt.Errorf("timeout must be positive, got %s", conf.Timeout)
+	}
+	np := &natsPublisher{conf: conf}
+	if err := np.connect(); err != nil {
+		return nil, err
+	}
+	return np, nil
+}
+
+func (np *natsPublisher) connect() error {
+	conn, err := net.DialTimeout("tcp", np.conf.Address, np.conf.Timeout)
+	if err != nil {
+		return err
+	}
+	conn.SetDeadline(time.Now().Add(np.conf.Timeout))
+	reader := bufio.NewReader(conn)
+
+	// The server greets with its INFO before any upgrade to TLS
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	if !strings.HasPrefix(line, "INFO ") {
+		conn.Close()
+		return fmt.Errorf("unexpected greeting from NATS server %s: %q", np.conf.Address, strings.TrimSpace(line))
+	}
+	if np.conf.TLS != nil {
+		tlsConn := tls.Client(conn, np.conf.TLS)
+		if err := tlsConn.Handshake(); err != nil {
+			conn.Close()
+			return err
+		}
+		conn = tlsConn
+		reader = bufio.NewReader(conn)
+	}
+
+	np.conn, np.reader = conn, reader
+	if err := np.roundTrip("CONNECT {\"verbose\":false,\"pedantic\":false,\"name\":\"fabric-peer\"}\r\n"); err != nil {
+		np.disconnect()
+		return err
+	}
+	return nil
+}
+
+func (np *natsPublisher) disconnect() {
+	if np.conn != nil {
+		np.conn.Close()
+		np.conn, np.reader = nil, nil
+	}
+}
+
+// roundTrip sends commands followed by a PING and waits for the PONG. The PINGs of the server
+// are answered meanwhile
+func (np *natsPublisher) roundTrip(commands string) error {
+	np.conn.SetDeadline(time.Now().Add(np.conf.Timeout))
+	if _, err := np.conn.Write([]byte(commands + "PING\r\n")); err != nil {
+		return err
+	}
+	for {
+		line, err := np.reader.ReadString('\n')
+		if err != nil {
+			return err
+		}
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "PONG":
+			return nil
+		case line == "PING":
+			if _, err := np.conn.Write([]byte("PONG\r\n")); err != nil {
+				return err
+			}
+		case strings.HasPrefix(line, "-ERR"):
+			return fmt.Errorf("NATS server %s refused the message: %s", np.conf.Address, line)
+		}
+	}
+}
+
+func (np *natsPublisher) Publish(subject string, key, value []byte) error {
+	np.lock.Lock()
+	defer np.lock.Unlock()
+
+	if np.conn == nil {
+		if err := np.connect(); err != nil {
+			return err
+		}
+	}
+	err := np.roundTrip(fmt.Sprintf("PUB %s %d\r\n%s\r\n", subject, len(value), value))
+	if err != nil {
+		np.disconnect()
+	}
+	return err
+}
+
+func (np *natsPublisher) Close() error {
+	np.lock.Lock()
+	defer np.lock.Unlock()
+	np.disconnect()
+	return nil
+}
@@ -0,0 +1,101 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bridge
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeNATSServer accepts connections and records the messages published, refusing the subjects
+// starting with "forbidden"
+type fakeNATSServer struct {
+	listener  net.Listener
+	published chan string
+}
+
+func newFakeNATSServer(t *testing.T) *fakeNATSServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	s := &fakeNATSServer{listener: listener, published: make(chan string, 10)}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakeNATSServer) serve(conn net.Conn) {
+	defer conn.Close()
+	fmt.Fprintf(conn, "INFO {\"server_id\":\"fake\"}\r\n")
+	reader := bufio.NewReader(conn)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "PING":
+			// The server may ping the client as well
+			fmt.Fprintf(conn, "PING\r\n")
+			fmt.Fprintf(conn, "PONG\r\n")
+		case "PUB":
+			var size int
+			fmt.Sscanf(fields[2], "%d", &size)
+			payload := make([]byte, size+2)
+			if _, err := io.ReadFull(reader, payload); err != nil {
+				return
+			}
+			if strings.HasPrefix(fields[1], "forbidden") {
+				fmt.Fprintf(conn, "-ERR 'Permissions Violation for Publish to %s'\r\n", fields[1])
+				continue
+			}
+			s.published <- fields[1] + " " + string(payload[:size])
+		}
+	}
+}
+
+func TestNATSPublisher(t *testing.T) {
+	server := newFakeNATSServer(t)
+	address := server.listener.Addr().String()
+
+	_, err := NewNATSPublisher(NATSConfig{Address: address})
+	assert.Error(t, err, "Should require a timeout")
+
+	pub, err := NewNATSPublisher(NATSConfig{Address: address, Timeout: time.Second})
+	assert.NoError(t, err)
+	assert.NoError(t, pub.Publish("fabric.blocks", []byte("mychannel"), []byte("{\"number\":1}")))
+	assert.Equal(t, "fabric.blocks {\"number\":1}", <-server.published)
+
+	assert.Error(t, pub.Publish("forbidden.blocks", nil, []byte("event")))
+
+	// The connection is reopened after a failure
+	assert.NoError(t, pub.Publish("fabric.blocks", nil, []byte("again")))
+	assert.Equal(t, "fabric.blocks again", <-server.published)
+	assert.NoError(t, pub.Close())
+
+	// Messages are not acknowledged without a server
+	server.listener.Close()
+	_, err = NewNATSPublisher(NATSConfig{Address: address, Timeout: time.Second})
+	assert.Error(t, err)
+}
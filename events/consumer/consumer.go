@@ -42,6 +42,7 @@ type EventsClient struct {
 	regTimeout  time.Duration
 	stream      ehpb.Events_ChatClient
 	adapter     EventAdapter
+	ackWindow   uint32
 }
 
 //NewEventsClient Returns a new grpc.ClientConn to the configured local PEER.
@@ -54,7 +55,15 @@ func NewEventsClient(peerAddress string, regTimeout time.Duration, adapter Event
 		regTimeout = 60 * time.Second
 		err = fmt.Errorf("regTimeout > 60, setting to 60 sec")
 	}
-	return &EventsClient{sync.RWMutex{}, peerAddress, regTimeout, nil, adapter}, err
+	return &EventsClient{sync.RWMutex{}, peerAddress, regTimeout, nil, adapter, 0}, err
+}
+
+// SetAckWindow registers the events in acknowledged mode, in which the peer sends at most window events ahead
+// of the acknowledgements of the client. Once started, the client acknowledges each event after the adapter
+// received it, while the clients calling Recv acknowledge the events with Ack. It must be called before
+// registering
+func (ec *EventsClient) SetAckWindow(window uint32) {
+	ec.ackWindow = window
 }
 
 //newEventsClientConnectionWithAddress Returns a new grpc.ClientConn to the configured local PEER.
@@ -101,7 +110,7 @@ func (ec *EventsClient) RegisterAsync(ies []*ehpb.Interest) error {
 	if err != nil {
 		return fmt.Errorf("error getting creator from MSP: %s", err)
 	}
-	emsg := &ehpb.Event{Event: &ehpb.Event_Register{Register: &ehpb.Register{Events: ies}}, Creator: creator}
+	emsg := &ehpb.Event{Event: &ehpb.Event_Register{Register: &ehpb.Register{Events: ies, AckWindow: ec.ackWindow}}, Creator: creator}
 
 	if err = ec.send(emsg); err != nil {
 		consumerLogger.Errorf("error on Register send %s\n", err)
@@ -155,6 +164,22 @@ func (ec *EventsClient) UnregisterAsync(ies []*ehpb.Interest) error {
 	return err
 }
 
+// Ack acknowledges the events up to sequence, the Sequence of the last event received, when registered in
+// acknowledged mode
+func (ec *EventsClient) Ack(sequence uint64) error {
+	creator, err := getCreatorFromLocalMSP()
+	if err != nil {
+		return fmt.Errorf("error getting creator from MSP: %s", err)
+	}
+	emsg := &ehpb.Event{Event: &ehpb.Event_Ack{Ack: &ehpb.Ack{Sequence: sequence}}, Creator: creator}
+
+	if err = ec.send(emsg); err != nil {
+		err = fmt.Errorf("error on ack send %s\n", err)
+	}
+
+	return err
+}
+
 // Recv receives next event - use when client has not called Start
 func (ec *EventsClient) Recv() (*ehpb.Event, error) {
 	in, err := ec.stream.Recv()
@@ -196,6 +221,11 @@ func (ec *EventsClient) processEvents() error {
 				return err
 			}
 		}
+		if in.Sequence > 0 {
+			if err := ec.Ack(in.Sequence); err != nil {
+				return err
+			}
+		}
 	}
 }
 
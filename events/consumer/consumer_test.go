@@ -17,6 +17,7 @@ import (
 	coreutil "github.com/hyperledger/fabric/core/testutil"
 	"github.com/hyperledger/fabric/events/producer"
 	"github.com/hyperledger/fabric/msp/mgmt/testtools"
+	"github.com/hyperledger/fabric/protos/common"
 	ehpb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
@@ -236,6 +237,40 @@ func TestStop(t *testing.T) {
 
 }
 
+type recordingAdapter struct {
+	MockAdapter
+	events chan *ehpb.Event
+}
+
+func (a *recordingAdapter) Recv(msg *ehpb.Event) (bool, error) {
+	a.events <- msg
+	return true, nil
+}
+
+func TestAckWindow(t *testing.T) {
+	adapter := &recordingAdapter{events: make(chan *ehpb.Event, 10)}
+	client, _ := NewEventsClient(peerAddress, 5*time.Second, adapter)
+	client.SetAckWindow(1)
+	if err := client.Start(); err != nil {
+		t.Fatalf("Error client start %s", err)
+	}
+	defer client.Stop()
+
+	for i := uint64(1); i <= 3; i++ {
+		block := &common.Block{Header: &common.BlockHeader{Number: i}, Data: &common.BlockData{}}
+		assert.NoError(t, producer.Send(&ehpb.Event{Event: &ehpb.Event_Block{Block: block}}))
+	}
+	// Each event is only sent once the previous one is acknowledged
+	for i := uint64(1); i <= 3; i++ {
+		select {
+		case e := <-adapter.events:
+			assert.Equal(t, i, e.Sequence)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Expected event %d", i)
+		}
+	}
+}
+
 func TestMain(m *testing.M) {
 	err := msptesttools.LoadMSPSetupForTesting()
 	if err != nil {
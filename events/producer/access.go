@@ -0,0 +1,113 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package producer
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+)
+
+// AccessControl restricts the registrations of the clients of the events server
+type AccessControl struct {
+	// Policies maps the event types to the name of the channel policy, such as
+	// /Channel/Application/Readers, which the creator of a registration for the events of the type must satisfy.
+	// The event types which are not in the map can be registered by any member of the organization of the peer
+	Policies map[pb.EventType]string
+
+	// PolicyManager returns the policy manager of a channel, or nil if the peer has not joined the channel
+	PolicyManager func(channelID string) policies.Manager
+
+	// Channels returns the channels the peer has joined
+	Channels func() []string
+
+	// MaxSubscriptions is the maximum number of interests registered at once by the same identity, over all its
+	// streams, or 0 for no maximum
+	MaxSubscriptions int
+}
+
+// authorize returns an error if the creator of the registration does not satisfy the policy of the event type of
+// an interest. The block events are only sent for the channel of the interest, when it is set, while the chaincode
+// and rejection events do not tell their channel, so the policy must be satisfied on all the channels for them
+func (ac *AccessControl) authorize(interests []*pb.Interest, signedData *common.SignedData) error {
+	for _, interest := range interests {
+		policyName, ok := ac.Policies[interest.EventType]
+		if !ok || policyName == "" {
+			continue
+		}
+		channels := ac.Channels()
+		if interest.EventType == pb.EventType_BLOCK && interest.ChainID != "" {
+			channels = []string{interest.ChainID}
+		}
+		for _, channelID := range channels {
+			pm := ac.PolicyManager(channelID)
+			if pm == nil {
+				return fmt.Errorf("access denied to the %s events of channel %s: the peer has not joined the channel", interest.EventType, channelID)
+			}
+			policy, ok := pm.GetPolicy(policyName)
+			if !ok {
+				return fmt.Errorf("access denied to the %s events of channel %s: policy %s not found", interest.EventType, channelID, policyName)
+			}
+			if err := policy.Evaluate([]*common.SignedData{signedData}); err != nil {
+				return fmt.Errorf("access denied to the %s events of channel %s: the creator does not satisfy policy %s: %s", interest.EventType, channelID, policyName, err)
+			}
+		}
+	}
+	return nil
+}
+
+// subscriptionCounter counts the interests registered by each identity
+type subscriptionCounter struct {
+	sync.Mutex
+	counts map[string]int
+}
+
+var subscriptions = &subscriptionCounter{counts: make(map[string]int)}
+
+// acquire counts n more interests for the identity, unless it would exceed max, in which case false is returned
+func (sc *subscriptionCounter) acquire(identity string, n, max int) bool {
+	sc.Lock()
+	defer sc.Unlock()
+	if max > 0 && sc.counts[identity]+n > max {
+		return false
+	}
+	sc.counts[identity] += n
+	return true
+}
+
+func (sc *subscriptionCounter) release(identity string, n int) {
+	sc.Lock()
+	defer sc.Unlock()
+	sc.counts[identity] -= n
+	if sc.counts[identity] <= 0 {
+		delete(sc.counts, identity)
+	}
+}
+
+// blockChannel returns the channel of a block event, or an empty string if it cannot be told
+func blockChannel(block *common.Block) string {
+	if block == nil || block.Data == nil || len(block.Data.Data) == 0 {
+		return ""
+	}
+	env, err := utils.GetEnvelopeFromBlock(block.Data.Data[0])
+	if err != nil {
+		return ""
+	}
+	payload, err := utils.GetPayload(env)
+	if err != nil || payload.Header == nil {
+		return ""
+	}
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return ""
+	}
+	return chdr.ChannelId
+}
@@ -0,0 +1,221 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package producer
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	pb "github.com/hyperledger/fabric/protos/peer"
+)
+
+const (
+	// AckOverflowEvict closes the stream of a consumer whose buffer is full
+	AckOverflowEvict = "evict"
+	// AckOverflowDrop drops the oldest event buffered for a consumer whose buffer is full
+	AckOverflowDrop = "drop"
+
+	defaultAckMaxBuffered = 1000
+	defaultAckMaxWindow   = 100
+)
+
+// AckDelivery configures the acknowledged delivery of the events, in which the events of a stream are only sent
+// up to the window of its consumer ahead of its acknowledgements, the others being buffered, so that a slow
+// consumer holds a bounded number of events in the peer
+type AckDelivery struct {
+	// MaxBuffered is the maximum number of events buffered for a stream, beyond which Overflow applies
+	MaxBuffered int
+
+	// Overflow is the policy applied to the streams whose buffer is full, AckOverflowEvict or AckOverflowDrop
+	Overflow string
+
+	// Timeout is how long a consumer may take to acknowledge an event before being evicted, or 0 for no limit
+	Timeout time.Duration
+
+	// MaxWindow caps the windows requested by the consumers
+	MaxWindow uint32
+}
+
+// ackStream delivers the events of a stream registered in acknowledged mode. The events are numbered and
+// queued by enqueue, which never blocks the event processor, and sent by run as the acknowledgements of the
+// consumer leave room in its window
+type ackStream struct {
+	conf   AckDelivery
+	window int
+	send   func(*pb.Event) error
+	evict  func(error)
+
+	lock     sync.Mutex
+	pending  []*pb.Event
+	inflight []inflightEvent
+	sequence uint64
+	dropped  uint64
+	wake     chan struct{}
+	done     chan struct{}
+	stopped  bool
+}
+
+type inflightEvent struct {
+	sequence uint64
+	sentAt   time.Time
+}
+
+func newAckStream(conf AckDelivery, window uint32, send func(*pb.Event) error, evict func(error)) *ackStream {
+	if conf.MaxBuffered <= 0 {
+		conf.MaxBuffered = defaultAckMaxBuffered
+	}
+	if conf.MaxWindow == 0 {
+		conf.MaxWindow = defaultAckMaxWindow
+	}
+	if window > conf.MaxWindow {
+		window = conf.MaxWindow
+	}
+	return &ackStream{
+		conf:   conf,
+		window: int(window),
+		send:   send,
+		evict:  evict,
+		wake:   make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+}
+
+// enqueue numbers an event and queues it for delivery, applying the overflow policy if the buffer is full
+func (s *ackStream) enqueue(e *pb.Event) {
+	s.lock.Lock()
+	if s.stopped {
+		s.lock.Unlock()
+		return
+	}
+	if len(s.pending) >= s.conf.MaxBuffered {
+		if s.conf.Overflow != AckOverflowDrop {
+			s.lock.Unlock()
+			s.fail(fmt.Errorf("slow consumer: %d events buffered without acknowledgement", s.conf.MaxBuffered))
+			return
+		}
+		s.pending[0] = nil
+		s.pending = s.pending[1:]
+		s.dropped++
+		if s.dropped == 1 || s.dropped%uint64(s.conf.MaxBuffered) == 0 {
+			logger.Warningf("Dropped %d events of a slow consumer", s.dropped)
+		}
+	}
+	// the event is shared by the streams, so it is copied to be numbered
+	numbered := *e
+	s.sequence++
+	numbered.Sequence = s.sequence
+	s.pending = append(s.pending, &numbered)
+	s.lock.Unlock()
+	s.signal()
+}
+
+// ack acknowledges the events up to sequence
+func (s *ackStream) ack(sequence uint64) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if sequence > s.sequence {
+		return fmt.Errorf("acknowledgement of event %d, which was not sent", sequence)
+	}
+	acked := 0
+	for acked < len(s.inflight) && s.inflight[acked].sequence <= sequence {
+		acked++
+	}
+	s.inflight = s.inflight[acked:]
+	if acked > 0 {
+		s.signal()
+	}
+	return nil
+}
+
+func (s *ackStream) signal() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run sends the events queued while the window allows it, until the stream is stopped or the consumer evicted
+func (s *ackStream) run() {
+	for {
+		next, deadline := s.next()
+		if next != nil {
+			if err := s.send(next); err != nil {
+				s.fail(err)
+				return
+			}
+			continue
+		}
+		var timer *time.Timer
+		var timeout <-chan time.Time
+		if !deadline.IsZero() {
+			timer = time.NewTimer(deadline.Sub(time.Now()))
+			timeout = timer.C
+		}
+		select {
+		case <-s.wake:
+		case <-timeout:
+			if s.expired() {
+				s.fail(fmt.Errorf("slow consumer: no acknowledgement within %s", s.conf.Timeout))
+				return
+			}
+		case <-s.done:
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		select {
+		case <-s.done:
+			return
+		default:
+		}
+	}
+}
+
+// next dequeues the next event to send, if the window allows it, and otherwise returns the time by which the
+// oldest event in flight must be acknowledged, zero if none
+func (s *ackStream) next() (*pb.Event, time.Time) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if len(s.pending) > 0 && len(s.inflight) < s.window {
+		next := s.pending[0]
+		s.pending[0] = nil
+		s.pending = s.pending[1:]
+		s.inflight = append(s.inflight, inflightEvent{sequence: next.Sequence, sentAt: time.Now()})
+		return next, time.Time{}
+	}
+	if len(s.inflight) == 0 || s.conf.Timeout <= 0 {
+		return nil, time.Time{}
+	}
+	return nil, s.inflight[0].sentAt.Add(s.conf.Timeout)
+}
+
+func (s *ackStream) expired() bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return len(s.inflight) > 0 && time.Since(s.inflight[0].sentAt) >= s.conf.Timeout
+}
+
+// fail stops the delivery and evicts the consumer, unless the delivery was stopped already
+func (s *ackStream) fail(err error) {
+	if s.stop() {
+		s.evict(err)
+	}
+}
+
+// stop stops the delivery and releases the events buffered. It returns false if the delivery was stopped already
+func (s *ackStream) stop() bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.stopped {
+		return false
+	}
+	s.stopped = true
+	s.pending = nil
+	close(s.done)
+	return true
+}
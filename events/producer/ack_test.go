@@ -0,0 +1,187 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package producer
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newBlockEvent(num uint64) *peer.Event {
+	return &peer.Event{Event: &peer.Event_Block{Block: &common.Block{Header: &common.BlockHeader{Number: num}}}}
+}
+
+// expectSent returns the sequences of the next n events sent
+func expectSent(t *testing.T, sent chan *peer.Event, n int) []uint64 {
+	var sequences []uint64
+	for i := 0; i < n; i++ {
+		select {
+		case e := <-sent:
+			sequences = append(sequences, e.Sequence)
+		case <-time.After(time.Second):
+			t.Fatalf("Expected %d more events to be sent", n-i)
+		}
+	}
+	return sequences
+}
+
+func expectNoneSent(t *testing.T, sent chan *peer.Event) {
+	select {
+	case e := <-sent:
+		t.Fatalf("Expected no event to be sent before an acknowledgement, got %d", e.Sequence)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestAckStream(t *testing.T) {
+	sent := make(chan *peer.Event, 10)
+	evicted := make(chan error, 1)
+	s := newAckStream(AckDelivery{MaxBuffered: 3, Overflow: AckOverflowDrop}, 2,
+		func(e *peer.Event) error { sent <- e; return nil },
+		func(err error) { evicted <- err })
+	go s.run()
+	defer s.stop()
+
+	shared := newBlockEvent(1)
+	s.enqueue(shared)
+	s.enqueue(newBlockEvent(2))
+	s.enqueue(newBlockEvent(3))
+	assert.Equal(t, []uint64{1, 2}, expectSent(t, sent, 2))
+	assert.Equal(t, uint64(0), shared.Sequence, "Expected the shared event not to be numbered")
+	expectNoneSent(t, sent)
+
+	assert.NoError(t, s.ack(1))
+	assert.Equal(t, []uint64{3}, expectSent(t, sent, 1))
+	assert.Error(t, s.ack(4), "Expected an error for the acknowledgement of an event not sent")
+
+	// The buffer of 3 events is full, the oldest event is dropped
+	for i := uint64(4); i <= 7; i++ {
+		s.enqueue(newBlockEvent(i))
+	}
+	expectNoneSent(t, sent)
+	assert.NoError(t, s.ack(3))
+	assert.Equal(t, []uint64{5, 6}, expectSent(t, sent, 2))
+	assert.NoError(t, s.ack(6))
+	assert.Equal(t, []uint64{7}, expectSent(t, sent, 1))
+	assert.Len(t, evicted, 0)
+}
+
+func TestAckStreamEviction(t *testing.T) {
+	evicted := make(chan error, 1)
+	evict := func(err error) { evicted <- err }
+	send := func(e *peer.Event) error { return nil }
+
+	s := newAckStream(AckDelivery{MaxBuffered: 1}, 1, send, evict)
+	go s.run()
+	s.enqueue(newBlockEvent(1))
+	s.enqueue(newBlockEvent(2))
+	s.enqueue(newBlockEvent(3))
+	select {
+	case err := <-evicted:
+		assert.Contains(t, err.Error(), "slow consumer")
+	case <-time.After(time.Second):
+		t.Fatal("Expected the consumer to be evicted once its buffer is full")
+	}
+	s.stop()
+
+	s = newAckStream(AckDelivery{Timeout: 50 * time.Millisecond}, 1, send, evict)
+	go s.run()
+	s.enqueue(newBlockEvent(1))
+	select {
+	case err := <-evicted:
+		assert.Contains(t, err.Error(), "no acknowledgement")
+	case <-time.After(time.Second):
+		t.Fatal("Expected the consumer to be evicted without acknowledgement")
+	}
+	s.stop()
+
+	s = newAckStream(AckDelivery{}, 1, func(e *peer.Event) error { return errors.New("broken stream") }, evict)
+	go s.run()
+	s.enqueue(newBlockEvent(1))
+	select {
+	case err := <-evicted:
+		assert.EqualError(t, err, "broken stream")
+	case <-time.After(time.Second):
+		t.Fatal("Expected the consumer to be evicted once the stream breaks")
+	}
+	s.stop()
+}
+
+type sendingStream struct {
+	mockstream
+	sent chan *peer.Event
+}
+
+func (s *sendingStream) Send(e *peer.Event) error {
+	s.sent <- e
+	return nil
+}
+
+func signedEvent(t *testing.T, e *peer.Event) *peer.SignedEvent {
+	e.Creator = signerSerialized
+	signedEvt, err := utils.GetSignedEvent(e, signer)
+	require.NoError(t, err)
+	return signedEvt
+}
+
+func TestHandlerAckMode(t *testing.T) {
+	stream := &sendingStream{sent: make(chan *peer.Event, 10)}
+	h, err := newEventHandler(stream)
+	require.NoError(t, err)
+	h.ackDelivery = &AckDelivery{MaxBuffered: 2}
+	defer h.Stop()
+
+	err = h.HandleMessage(signedEvent(t, &peer.Event{Event: &peer.Event_Ack{Ack: &peer.Ack{Sequence: 1}}}))
+	assert.Error(t, err, "Expected an error for an acknowledgement in the default mode")
+
+	register := &peer.Event{Event: &peer.Event_Register{Register: &peer.Register{AckWindow: 1}}}
+	require.NoError(t, h.HandleMessage(signedEvent(t, register)))
+	reply := <-stream.sent
+	assert.NotNil(t, reply.GetRegister(), "Expected the registration to be replied to")
+
+	h.SendMessage(newBlockEvent(1))
+	h.SendMessage(newBlockEvent(2))
+	assert.Equal(t, []uint64{1}, expectSent(t, stream.sent, 1))
+	require.NoError(t, h.HandleMessage(signedEvent(t, &peer.Event{Event: &peer.Event_Ack{Ack: &peer.Ack{Sequence: 1}}})))
+	assert.Equal(t, []uint64{2}, expectSent(t, stream.sent, 1))
+
+	h.SendMessage(newBlockEvent(3))
+	h.SendMessage(newBlockEvent(4))
+	h.SendMessage(newBlockEvent(5))
+	select {
+	case <-h.evicted:
+		assert.Contains(t, h.evictErr.Error(), "slow consumer")
+	case <-time.After(time.Second):
+		t.Fatal("Expected the consumer to be evicted once its buffer is full")
+	}
+}
+
+func TestChatAckMode(t *testing.T) {
+	recvChan := make(chan *streamEvent)
+	stream := &sendingStream{mockstream: mockstream{c: recvChan}, sent: make(chan *peer.Event, 10)}
+	server := &EventsServer{ackDelivery: &AckDelivery{}}
+	errs := make(chan error, 1)
+	go func() { errs <- server.Chat(stream) }()
+
+	register := &peer.Event{Event: &peer.Event_Register{Register: &peer.Register{AckWindow: 1}}}
+	recvChan <- &streamEvent{event: signedEvent(t, register)}
+	<-stream.sent
+	recvChan <- &streamEvent{event: signedEvent(t, &peer.Event{Event: &peer.Event_Ack{Ack: &peer.Ack{Sequence: 5}}})}
+	select {
+	case err := <-errs:
+		assert.Error(t, err, "Expected the stream to end on the acknowledgement of an event not sent")
+	case <-time.After(time.Second):
+		t.Fatal("Expected the stream to end")
+	}
+}
@@ -233,8 +233,13 @@ func (ep *eventProcessor) start() {
 		//lock the handler map lock
 		ep.Unlock()
 
+		// The block events are only sent to the handlers registered for their channel
+		channelID := ""
+		if block := e.GetBlock(); block != nil {
+			channelID = blockChannel(block)
+		}
 		hl.foreach(e, func(h *handler) {
-			if e.Event != nil {
+			if e.Event != nil && (channelID == "" || h.receivesBlocksOf(channelID)) {
 				h.SendMessage(e)
 			}
 		})
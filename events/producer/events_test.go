@@ -18,11 +18,15 @@ package producer
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"testing"
 	"time"
 
+	mockpolicies "github.com/hyperledger/fabric/common/mocks/policies"
+	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/core/comm"
+	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/peer"
 	ehpb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/utils"
@@ -140,6 +144,68 @@ func TestRegister(t *testing.T) {
 	assert.NoError(t, registerHandler(&peer.Interest{EventType: peer.EventType_BLOCK}, handler))
 }
 
+func TestAccessControl(t *testing.T) {
+	managers := map[string]*mockpolicies.Manager{
+		"ch1": {PolicyMap: map[string]policies.Policy{"Readers": &mockpolicies.Policy{}}},
+		"ch2": {PolicyMap: map[string]policies.Policy{"Readers": &mockpolicies.Policy{Err: fmt.Errorf("not a reader")}}},
+	}
+	ac := &AccessControl{
+		Policies: map[peer.EventType]string{peer.EventType_BLOCK: "Readers", peer.EventType_CHAINCODE: "Readers"},
+		PolicyManager: func(channelID string) policies.Manager {
+			if pm, ok := managers[channelID]; ok {
+				return pm
+			}
+			return nil
+		},
+		Channels:         func() []string { return []string{"ch1", "ch2"} },
+		MaxSubscriptions: 2,
+	}
+	signedData := &common.SignedData{Identity: []byte("client")}
+	newHandler := func() *handler {
+		h, err := newEventHandler(&mockstream{c: make(chan *streamEvent)})
+		assert.NoError(t, err)
+		h.accessControl = ac
+		return h
+	}
+	ccInterest := func(eventName string) *peer.Interest {
+		return &peer.Interest{EventType: peer.EventType_CHAINCODE, RegInfo: &peer.Interest_ChaincodeRegInfo{ChaincodeRegInfo: &peer.ChaincodeReg{ChaincodeId: "acl", EventName: eventName}}}
+	}
+
+	h1 := newHandler()
+	defer h1.Stop()
+	assert.EqualError(t, h1.register([]*peer.Interest{{EventType: peer.EventType_BLOCK, ChainID: "ch2"}}, signedData),
+		"access denied to the BLOCK events of channel ch2: the creator does not satisfy policy Readers: not a reader")
+	assert.EqualError(t, h1.register([]*peer.Interest{{EventType: peer.EventType_BLOCK, ChainID: "ch3"}}, signedData),
+		"access denied to the BLOCK events of channel ch3: the peer has not joined the channel")
+	assert.EqualError(t, h1.register([]*peer.Interest{{EventType: peer.EventType_BLOCK}}, signedData),
+		"access denied to the BLOCK events of channel ch2: the creator does not satisfy policy Readers: not a reader")
+	assert.Error(t, h1.register([]*peer.Interest{ccInterest("event1")}, signedData), "Chaincode events should be checked on all the channels")
+	assert.Empty(t, h1.interestedEvents)
+
+	assert.NoError(t, h1.register([]*peer.Interest{{EventType: peer.EventType_BLOCK, ChainID: "ch1"}, {EventType: peer.EventType_REJECTION}}, signedData))
+	assert.Len(t, h1.interestedEvents, 2)
+	// Registering the same interests again replaces them without counting them twice
+	assert.NoError(t, h1.register([]*peer.Interest{{EventType: peer.EventType_BLOCK, ChainID: "ch1"}, {EventType: peer.EventType_REJECTION}, {EventType: peer.EventType_REJECTION}}, signedData))
+	assert.Len(t, h1.interestedEvents, 2)
+	assert.Equal(t, 2, subscriptions.counts["client"])
+	assert.True(t, h1.receivesBlocksOf("ch1"))
+	assert.False(t, h1.receivesBlocksOf("ch2"), "Should only receive the blocks of the channel registered")
+
+	// The subscriptions are limited across the streams of the identity
+	h2 := newHandler()
+	defer h2.Stop()
+	assert.EqualError(t, h2.register([]*peer.Interest{{EventType: peer.EventType_REJECTION}}, signedData),
+		"the creator has reached the maximum of 2 concurrent subscriptions")
+	assert.NoError(t, h2.register([]*peer.Interest{{EventType: peer.EventType_REJECTION}}, &common.SignedData{Identity: []byte("other")}))
+
+	assert.NoError(t, h1.deregister([]*peer.Interest{{EventType: peer.EventType_REJECTION}}))
+	assert.NoError(t, h2.register([]*peer.Interest{{EventType: peer.EventType_BLOCK, ChainID: "ch1"}}, signedData))
+	h1.Stop()
+	h2.Stop()
+	assert.NotContains(t, subscriptions.counts, "client", "Should have released the subscriptions of the stopped handlers")
+	assert.NotContains(t, subscriptions.counts, "other", "Should have released the subscriptions of the stopped handlers")
+}
+
 func TestProcessEvents(t *testing.T) {
 	cl := newClient()
 	interests := []*peer.Interest{
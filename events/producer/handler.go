@@ -19,33 +19,85 @@ package producer
 import (
 	"fmt"
 	"strconv"
+	"sync"
 
 	"github.com/golang/protobuf/proto"
 
 	"github.com/hyperledger/fabric/msp/mgmt"
+	"github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric/protos/peer"
 )
 
 type handler struct {
+	sync.RWMutex
 	ChatStream       pb.Events_ChatServer
 	interestedEvents map[string]*pb.Interest
+	// subscribers maps the keys of the interests to the identity which registered them
+	subscribers   map[string]string
+	accessControl *AccessControl
+	ackDelivery   *AckDelivery
+	// acks delivers the events once the stream is registered in acknowledged mode
+	acks *ackStream
+
+	// sendLock serializes the sends of the event processor and of the replies to the consumer
+	sendLock  sync.Mutex
+	evicted   chan struct{}
+	evictOnce sync.Once
+	evictErr  error
 }
 
 func newEventHandler(stream pb.Events_ChatServer) (*handler, error) {
 	d := &handler{
 		ChatStream: stream,
+		evicted:    make(chan struct{}),
 	}
 	d.interestedEvents = make(map[string]*pb.Interest)
+	d.subscribers = make(map[string]string)
 	return d, nil
 }
 
 // Stop stops this handler
 func (d *handler) Stop() error {
 	d.deregisterAll()
-	d.interestedEvents = nil
+	if acks := d.getAcks(); acks != nil {
+		acks.stop()
+	}
 	return nil
 }
 
+// evict ends the stream of a slow consumer, whose events are no longer delivered
+func (d *handler) evict(err error) {
+	d.evictOnce.Do(func() {
+		d.evictErr = err
+		if acks := d.getAcks(); acks != nil {
+			acks.stop()
+		}
+		close(d.evicted)
+	})
+}
+
+func (d *handler) getAcks() *ackStream {
+	d.RLock()
+	defer d.RUnlock()
+	return d.acks
+}
+
+// enableAcks switches the stream to the acknowledged delivery, with the window requested by the consumer. The
+// delivery mode of a stream is set by its first registration
+func (d *handler) enableAcks(window uint32) {
+	d.Lock()
+	defer d.Unlock()
+	if d.acks != nil || len(d.interestedEvents) > 0 {
+		return
+	}
+	conf := AckDelivery{}
+	if d.ackDelivery != nil {
+		conf = *d.ackDelivery
+	}
+	d.acks = newAckStream(conf, window, d.send, d.evict)
+	go d.acks.run()
+}
+
 func getInterestKey(interest pb.Interest) string {
 	var key string
 	switch interest.EventType {
@@ -62,15 +114,51 @@ func getInterestKey(interest pb.Interest) string {
 	return key
 }
 
-func (d *handler) register(iMsg []*pb.Interest) error {
+func (d *handler) register(iMsg []*pb.Interest, signedData *common.SignedData) error {
+	maxSubscriptions := 0
+	if d.accessControl != nil {
+		if err := d.accessControl.authorize(iMsg, signedData); err != nil {
+			return err
+		}
+		maxSubscriptions = d.accessControl.MaxSubscriptions
+	}
+	identity := string(signedData.Identity)
+	// Only the keys the identity does not hold yet count as new subscriptions, since
+	// registering an interest again replaces the previous one
+	counted := make(map[string]bool)
+	d.RLock()
+	for _, v := range iMsg {
+		key := getInterestKey(*v)
+		if d.subscribers[key] != identity {
+			counted[key] = true
+		}
+	}
+	d.RUnlock()
+	if !subscriptions.acquire(identity, len(counted), maxSubscriptions) {
+		return fmt.Errorf("the creator has reached the maximum of %d concurrent subscriptions", maxSubscriptions)
+	}
+
 	// Could consider passing interest array to registerHandler
 	// and only lock once for entire array here
 	for _, v := range iMsg {
+		key := getInterestKey(*v)
 		if err := registerHandler(v, d); err != nil {
 			logger.Errorf("could not register %s: %s", v, err)
+			if counted[key] {
+				subscriptions.release(identity, 1)
+				delete(counted, key)
+			}
 			continue
 		}
-		d.interestedEvents[getInterestKey(*v)] = v
+		d.Lock()
+		if previous, ok := d.subscribers[key]; ok && counted[key] {
+			// the interest was registered by another identity, whose subscription it no longer is
+			subscriptions.release(previous, 1)
+		}
+		delete(counted, key)
+		d.interestedEvents[key] = v
+		d.subscribers[key] = identity
+		d.Unlock()
 	}
 
 	return nil
@@ -82,19 +170,44 @@ func (d *handler) deregister(iMsg []*pb.Interest) error {
 			logger.Errorf("could not deregister %s", v)
 			continue
 		}
-		delete(d.interestedEvents, getInterestKey(*v))
+		d.forget(getInterestKey(*v))
 	}
 	return nil
 }
 
 func (d *handler) deregisterAll() {
+	d.RLock()
+	interests := make(map[string]*pb.Interest, len(d.interestedEvents))
 	for k, v := range d.interestedEvents {
+		interests[k] = v
+	}
+	d.RUnlock()
+	for k, v := range interests {
 		if err := deRegisterHandler(v, d); err != nil {
 			logger.Errorf("could not deregister %s", v)
 			continue
 		}
-		delete(d.interestedEvents, k)
+		d.forget(k)
+	}
+}
+
+// forget removes a deregistered interest, and releases it from the subscriptions of its identity
+func (d *handler) forget(key string) {
+	d.Lock()
+	defer d.Unlock()
+	if identity, ok := d.subscribers[key]; ok {
+		subscriptions.release(identity, 1)
+		delete(d.subscribers, key)
 	}
+	delete(d.interestedEvents, key)
+}
+
+// receivesBlocksOf returns true if the handler registered for the block events of all the channels, or of channelID
+func (d *handler) receivesBlocksOf(channelID string) bool {
+	d.RLock()
+	defer d.RUnlock()
+	interest, ok := d.interestedEvents[getInterestKey(pb.Interest{EventType: pb.EventType_BLOCK})]
+	return !ok || interest.ChainID == "" || interest.ChainID == channelID
 }
 
 // HandleMessage handles the Openchain messages for the Peer.
@@ -107,7 +220,11 @@ func (d *handler) HandleMessage(msg *pb.SignedEvent) error {
 	switch evt.Event.(type) {
 	case *pb.Event_Register:
 		eventsObj := evt.GetRegister()
-		if err := d.register(eventsObj.Events); err != nil {
+		if eventsObj.AckWindow > 0 {
+			d.enableAcks(eventsObj.AckWindow)
+		}
+		signedData := &common.SignedData{Data: msg.EventBytes, Identity: evt.Creator, Signature: msg.Signature}
+		if err := d.register(eventsObj.Events, signedData); err != nil {
 			return fmt.Errorf("could not register events %s", err)
 		}
 	case *pb.Event_Unregister:
@@ -115,27 +232,45 @@ func (d *handler) HandleMessage(msg *pb.SignedEvent) error {
 		if err := d.deregister(eventsObj.Events); err != nil {
 			return fmt.Errorf("could not unregister events %s", err)
 		}
+	case *pb.Event_Ack:
+		acks := d.getAcks()
+		if acks == nil {
+			return fmt.Errorf("acknowledgement on a stream not registered in acknowledged mode")
+		}
+		// the acknowledgements are not replied to
+		return acks.ack(evt.GetAck().Sequence)
 	case nil:
 	default:
 		return fmt.Errorf("invalide type from client %T", evt.Event)
 	}
 	//TODO return supported events.. for now just return the received msg
-	if err := d.ChatStream.Send(evt); err != nil {
+	if err := d.send(evt); err != nil {
 		return fmt.Errorf("error sending response to %v:  %s", msg, err)
 	}
 
 	return nil
 }
 
-// SendMessage sends a message to the remote PEER through the stream
+// SendMessage sends a message to the remote PEER through the stream, or queues it if the stream is registered
+// in acknowledged mode
 func (d *handler) SendMessage(msg *pb.Event) error {
-	err := d.ChatStream.Send(msg)
+	if acks := d.getAcks(); acks != nil {
+		acks.enqueue(msg)
+		return nil
+	}
+	err := d.send(msg)
 	if err != nil {
 		return fmt.Errorf("error Sending message through ChatStream: %s", err)
 	}
 	return nil
 }
 
+func (d *handler) send(msg *pb.Event) error {
+	d.sendLock.Lock()
+	defer d.sendLock.Unlock()
+	return d.ChatStream.Send(msg)
+}
+
 // Validates event messages by validating the Creator and verifying
 // the signature. Returns the unmarshaled Event object
 // Validation of the creator identity's validity is done by checking with local MSP to ensure the
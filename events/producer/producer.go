@@ -22,6 +22,7 @@ import (
 	"time"
 
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/comm"
 	pb "github.com/hyperledger/fabric/protos/peer"
 )
 
@@ -29,6 +30,8 @@ var logger = flogging.MustGetLogger("eventhub_producer")
 
 // EventsServer implementation of the Peer service
 type EventsServer struct {
+	accessControl *AccessControl
+	ackDelivery   *AckDelivery
 }
 
 //singleton - if we want to create multiple servers, we need to subsume events.gEventConsumers into EventsServer
@@ -45,28 +48,74 @@ func NewEventsServer(bufferSize uint, timeout time.Duration) *EventsServer {
 	return globalEventsServer
 }
 
+// SetAccessControl restricts the registrations of the streams opened afterwards
+func (p *EventsServer) SetAccessControl(ac *AccessControl) {
+	p.accessControl = ac
+}
+
+// SetAckDelivery configures the delivery of the streams registered in acknowledged mode opened afterwards
+func (p *EventsServer) SetAckDelivery(ad *AckDelivery) {
+	p.ackDelivery = ad
+}
+
 // Chat implementation of the Chat bidi streaming RPC function
 func (p *EventsServer) Chat(stream pb.Events_ChatServer) error {
 	handler, err := newEventHandler(stream)
 	if err != nil {
 		return fmt.Errorf("error creating handler during handleChat initiation: %s", err)
 	}
+	handler.accessControl = p.accessControl
+	handler.ackDelivery = p.ackDelivery
 	defer handler.Stop()
-	for {
-		in, err := stream.Recv()
-		if err == io.EOF {
-			logger.Debug("Received EOF, ending Chat")
-			return nil
+
+	// The messages are received aside, so that a slow consumer can be evicted while no message comes
+	done := make(chan struct{})
+	defer close(done)
+	msgs := make(chan *pb.SignedEvent)
+	errs := make(chan error, 1)
+	go func() {
+		for {
+			in, err := stream.Recv()
+			if err != nil {
+				errs <- err
+				return
+			}
+			select {
+			case msgs <- in:
+			case <-done:
+				return
+			}
 		}
-		if err != nil {
+	}()
+
+	for {
+		select {
+		case <-handler.evicted:
+			logger.Warningf("Evicting event consumer: %s", handler.evictErr)
+			return handler.evictErr
+		case err := <-errs:
+			if err == io.EOF {
+				if comm.IsGRPCWeb(stream.Context()) {
+					// Browsers close their side of the stream once registered, the events are sent
+					// until they disconnect
+					select {
+					case <-stream.Context().Done():
+					case <-handler.evicted:
+						return handler.evictErr
+					}
+				}
+				logger.Debug("Received EOF, ending Chat")
+				return nil
+			}
 			e := fmt.Errorf("error during Chat, stopping handler: %s", err)
 			logger.Error(e.Error())
 			return e
-		}
-		err = handler.HandleMessage(in)
-		if err != nil {
-			logger.Errorf("Error handling message: %s", err)
-			return err
+		case in := <-msgs:
+			err = handler.HandleMessage(in)
+			if err != nil {
+				logger.Errorf("Error handling message: %s", err)
+				return err
+			}
 		}
 	}
 }
@@ -45,6 +45,9 @@ const (
 	defConnTimeout  = time.Second * time.Duration(2)
 	defRecvBuffSize = 20
 	defSendBuffSize = 20
+	// defMaxMsgSize matches the default maximum size of gRPC messages received by the peer
+	defMaxMsgSize        = 100 * 1024 * 1024
+	defMaxControlMsgSize = 10 * 1024 * 1024
 )
 
 // SetDialTimeout sets the dial timeout
@@ -22,6 +22,7 @@ import (
 	"crypto/sha256"
 	"crypto/tls"
 	"fmt"
+	"io"
 	"math/rand"
 	"net"
 	"os"
@@ -196,6 +197,8 @@ func TestViperConfig(t *testing.T) {
 	assert.Equal(t, time.Duration(300)*time.Millisecond, util.GetDurationOrDefault("peer.gossip.dialTimeout", 0))
 	assert.Equal(t, 20, util.GetIntOrDefault("peer.gossip.recvBuffSize", 0))
 	assert.Equal(t, 200, util.GetIntOrDefault("peer.gossip.sendBuffSize", 0))
+	assert.Equal(t, 104857600, util.GetIntOrDefault("peer.gossip.maxMessageSize", 0))
+	assert.Equal(t, 10485760, util.GetIntOrDefault("peer.gossip.maxControlMessageSize", 0))
 }
 
 func TestHandshake(t *testing.T) {
@@ -710,6 +713,71 @@ func TestPresumedDead(t *testing.T) {
 	}
 }
 
+type fakeServerStream struct {
+	grpc.ServerStream
+	envelopes chan *proto.Envelope
+}
+
+func (fss *fakeServerStream) Send(envelope *proto.Envelope) error {
+	return nil
+}
+
+func (fss *fakeServerStream) Recv() (*proto.Envelope, error) {
+	envelope, ok := <-fss.envelopes
+	if !ok {
+		return nil, io.EOF
+	}
+	return envelope, nil
+}
+
+func TestMessageSizeLimits(t *testing.T) {
+	t.Parallel()
+	fss := &fakeServerStream{envelopes: make(chan *proto.Envelope, 4)}
+	conn := newConnection(nil, nil, nil, fss)
+	conn.logger = util.GetLogger(util.LoggingCommModule, "TestMessageSizeLimits")
+	conn.maxMsgSize = 2000
+	conn.maxControlMsgSize = 1000
+
+	padding := make([]byte, 1500)
+	dataMsg := func(nonce uint64, payload []byte) *proto.Envelope {
+		msg, _ := (&proto.GossipMessage{
+			Nonce: nonce,
+			Content: &proto.GossipMessage_DataMsg{
+				DataMsg: &proto.DataMessage{Payload: &proto.Payload{Data: payload}},
+			},
+		}).NoopSign()
+		return msg.Envelope
+	}
+	aliveMsg := func(nonce uint64, metadata []byte) *proto.Envelope {
+		msg, _ := (&proto.GossipMessage{
+			Nonce: nonce,
+			Content: &proto.GossipMessage_AliveMsg{
+				AliveMsg: &proto.AliveMessage{Membership: &proto.Member{Metadata: metadata}},
+			},
+		}).NoopSign()
+		return msg.Envelope
+	}
+
+	rejected := util.RejectedMessages.Count(util.RejectTooLarge)
+	fss.envelopes <- dataMsg(1, padding)
+	fss.envelopes <- aliveMsg(2, padding)
+	fss.envelopes <- dataMsg(3, append(padding, padding...))
+	fss.envelopes <- aliveMsg(4, nil)
+	close(fss.envelopes)
+
+	errChan := make(chan error, 1)
+	msgChan := make(chan *proto.SignedGossipMessage, 4)
+	conn.readFromStream(errChan, msgChan)
+	assert.Equal(t, io.EOF, <-errChan)
+	close(msgChan)
+	var received []uint64
+	for msg := range msgChan {
+		received = append(received, msg.Nonce)
+	}
+	assert.Equal(t, []uint64{1, 4}, received, "Should have discarded the messages exceeding the size limits")
+	assert.True(t, util.RejectedMessages.Count(util.RejectTooLarge) >= rejected+2)
+}
+
 func createGossipMsg() *proto.SignedGossipMessage {
 	msg, _ := (&proto.GossipMessage{
 		Tag:   proto.GossipMessage_EMPTY,
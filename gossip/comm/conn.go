@@ -194,13 +194,15 @@ func (cs *connectionStore) closeByPKIid(pkiID common.PKIidType) {
 
 func newConnection(cl proto.GossipClient, c *grpc.ClientConn, cs proto.Gossip_GossipStreamClient, ss proto.Gossip_GossipStreamServer) *connection {
 	connection := &connection{
-		outBuff:      make(chan *msgSending, util.GetIntOrDefault("peer.gossip.sendBuffSize", defSendBuffSize)),
-		cl:           cl,
-		conn:         c,
-		clientStream: cs,
-		serverStream: ss,
-		stopFlag:     int32(0),
-		stopChan:     make(chan struct{}, 1),
+		outBuff:           make(chan *msgSending, util.GetIntOrDefault("peer.gossip.sendBuffSize", defSendBuffSize)),
+		maxMsgSize:        util.GetIntOrDefault("peer.gossip.maxMessageSize", defMaxMsgSize),
+		maxControlMsgSize: util.GetIntOrDefault("peer.gossip.maxControlMessageSize", defMaxControlMsgSize),
+		cl:                cl,
+		conn:              c,
+		clientStream:      cs,
+		serverStream:      ss,
+		stopFlag:          int32(0),
+		stopChan:          make(chan struct{}, 1),
 	}
 
 	return connection
@@ -220,6 +222,9 @@ type connection struct {
 	stopFlag     int32                           // indicates whether this connection is in process of stopping
 	stopChan     chan struct{}                   // a method to stop the server-side gRPC call from a different go-routine
 	sync.RWMutex                                 // synchronizes access to shared variables
+
+	maxMsgSize        int // maximum size of a received message
+	maxControlMsgSize int // maximum size of a received message which doesn't carry blocks
 }
 
 func (conn *connection) close() {
@@ -346,15 +351,33 @@ func (conn *connection) readFromStream(errChan chan error, msgChan chan *proto.S
 			conn.logger.Debug(conn.pkiID, "Got error, aborting:", err)
 			return
 		}
+		if len(envelope.Payload) > conn.maxMsgSize {
+			util.RejectedMessages.Inc(util.RejectTooLarge)
+			conn.logger.Warningf("%s sent a message of %d bytes, exceeding the maximum of %d bytes, discarding it",
+				conn.pkiID, len(envelope.Payload), conn.maxMsgSize)
+			continue
+		}
 		msg, err := envelope.ToGossipMessage()
 		if err != nil {
 			errChan <- err
 			conn.logger.Warning(conn.pkiID, "Got error, aborting:", err)
 		}
+		if msg != nil && !carriesBlocks(msg) && len(envelope.Payload) > conn.maxControlMsgSize {
+			util.RejectedMessages.Inc(util.RejectTooLarge)
+			conn.logger.Warningf("%s sent a message without blocks of %d bytes, exceeding the maximum of %d bytes, discarding it",
+				conn.pkiID, len(envelope.Payload), conn.maxControlMsgSize)
+			continue
+		}
 		msgChan <- msg
 	}
 }
 
+// carriesBlocks returns whether the message is one of the kinds that carry blocks,
+// and are hence subject to the maximum message size rather than the maximum control message size
+func carriesBlocks(msg *proto.SignedGossipMessage) bool {
+	return msg.IsDataMsg() || msg.GetStateResponse() != nil || msg.GetDataUpdate() != nil
+}
+
 func (conn *connection) getStream() stream {
 	conn.Lock()
 	defer conn.Unlock()
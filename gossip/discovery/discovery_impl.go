@@ -35,6 +35,7 @@ import (
 )
 
 const defaultHelloInterval = time.Duration(5) * time.Second
+const defaultMembershipRequestTTL = time.Minute
 const msgExpirationFactor = 20
 
 var aliveExpirationCheckInterval time.Duration
@@ -56,6 +57,12 @@ func SetAliveExpirationCheckInterval(interval time.Duration) {
 	aliveExpirationCheckInterval = interval
 }
 
+// SetMembershipRequestTTL sets the time after which membership requests expire.
+// It also bounds the tolerated clock skew between peers
+func SetMembershipRequestTTL(ttl time.Duration) {
+	util.SetDuration("peer.gossip.membershipRequestTTL", ttl)
+}
+
 // SetReconnectInterval sets the reconnect interval
 func SetReconnectInterval(interval time.Duration) {
 	util.SetDuration("peer.gossip.reconnectInterval", interval)
@@ -338,6 +345,11 @@ func (d *gossipDiscoveryImpl) handleMsgFromComm(m *proto.SignedGossipMessage) {
 			return
 		}
 
+		if err := d.validateMembershipRequest(selfInfoGossipMsg); err != nil {
+			d.logger.Warning("Rejecting membership request:", err)
+			return
+		}
+
 		if d.msgStore.CheckValid(selfInfoGossipMsg) {
 			d.handleAliveMessage(selfInfoGossipMsg)
 		}
@@ -410,6 +422,35 @@ func (d *gossipDiscoveryImpl) handleMsgFromComm(m *proto.SignedGossipMessage) {
 	}
 }
 
+// validateMembershipRequest checks that the alive message carried by a membership request is
+// authentic and has not expired. Requests of peers which don't set an expiration are accepted
+func (d *gossipDiscoveryImpl) validateMembershipRequest(selfInfo *proto.SignedGossipMessage) error {
+	am := selfInfo.GetAliveMsg()
+	if am == nil || am.Membership == nil {
+		util.RejectedMessages.Inc(util.RejectInvalid)
+		return fmt.Errorf("self information isn't an alive message: %v", selfInfo)
+	}
+	if !d.crypt.ValidateAliveMsg(selfInfo) {
+		util.RejectedMessages.Inc(util.RejectUnauthentic)
+		return fmt.Errorf("self information of %s isn't authentic", am.Membership.Endpoint)
+	}
+	if am.Expiration == 0 {
+		return nil
+	}
+	now := time.Now()
+	expiration := time.Unix(0, am.Expiration)
+	if now.After(expiration) {
+		util.RejectedMessages.Inc(util.RejectExpired)
+		return fmt.Errorf("request of %s expired at %s", am.Membership.Endpoint, expiration)
+	}
+	// An expiration too far ahead would make the request replayable at will
+	if expiration.Sub(now) > 2*getMembershipRequestTTL() {
+		util.RejectedMessages.Inc(util.RejectExpired)
+		return fmt.Errorf("request of %s expires too far in the future, at %s", am.Membership.Endpoint, expiration)
+	}
+	return nil
+}
+
 func (d *gossipDiscoveryImpl) sendMemResponse(targetMember *proto.Member, internalEndpoint string, nonce uint64) {
 	d.logger.Debug("Entering", targetMember)
 
@@ -488,6 +529,7 @@ func (d *gossipDiscoveryImpl) handleAliveMessage(m *proto.SignedGossipMessage) {
 	defer d.logger.Debug("Exiting")
 
 	if !d.crypt.ValidateAliveMsg(m) {
+		util.RejectedMessages.Inc(util.RejectUnauthentic)
 		d.logger.Debugf("Alive message isn't authentic, someone must be spoofing %s's identity", m.GetAliveMsg())
 		return
 	}
@@ -633,7 +675,7 @@ func (d *gossipDiscoveryImpl) sendMembershipRequest(member *NetworkMember, inclu
 }
 
 func (d *gossipDiscoveryImpl) createMembershipRequest(includeInternalEndpoint bool) (*proto.GossipMessage, error) {
-	am, err := d.createAliveMessage(includeInternalEndpoint)
+	am, err := d.createSignedAliveMessage(includeInternalEndpoint, time.Now().Add(getMembershipRequestTTL()).UnixNano())
 	if err != nil {
 		return nil, err
 	}
@@ -742,6 +784,11 @@ func (d *gossipDiscoveryImpl) periodicalSendAlive() {
 }
 
 func (d *gossipDiscoveryImpl) createAliveMessage(includeInternalEndpoint bool) (*proto.SignedGossipMessage, error) {
+	return d.createSignedAliveMessage(includeInternalEndpoint, 0)
+}
+
+// createSignedAliveMessage creates an alive message expiring at the given time in nanoseconds, or never if 0
+func (d *gossipDiscoveryImpl) createSignedAliveMessage(includeInternalEndpoint bool, expiration int64) (*proto.SignedGossipMessage, error) {
 	d.lock.Lock()
 	d.seqNum++
 	seqNum := d.seqNum
@@ -766,6 +813,7 @@ func (d *gossipDiscoveryImpl) createAliveMessage(includeInternalEndpoint bool) (
 					IncNum: uint64(d.incTime),
 					SeqNum: seqNum,
 				},
+				Expiration: expiration,
 			},
 		},
 	}
@@ -996,6 +1044,10 @@ func getAliveExpirationCheckInterval() time.Duration {
 	return time.Duration(getAliveExpirationTimeout() / 10)
 }
 
+func getMembershipRequestTTL() time.Duration {
+	return util.GetDurationOrDefault("peer.gossip.membershipRequestTTL", defaultMembershipRequestTTL)
+}
+
 func getReconnectInterval() time.Duration {
 	return util.GetDurationOrDefault("peer.gossip.reconnectInterval", getAliveExpirationTimeout())
 }
@@ -802,6 +802,7 @@ func TestConfigFromFile(t *testing.T) {
 	assert.Equal(t, time.Duration(25)*time.Second, getAliveExpirationTimeout())
 	assert.Equal(t, time.Duration(25)*time.Second/10, getAliveExpirationCheckInterval())
 	assert.Equal(t, time.Duration(25)*time.Second, getReconnectInterval())
+	assert.Equal(t, time.Minute, getMembershipRequestTTL())
 
 	//Verify reading the values from config file
 	viper.Reset()
@@ -817,6 +818,62 @@ func TestConfigFromFile(t *testing.T) {
 	assert.Equal(t, time.Duration(25)*time.Second, getAliveExpirationTimeout())
 	assert.Equal(t, time.Duration(25)*time.Second/10, getAliveExpirationCheckInterval())
 	assert.Equal(t, time.Duration(25)*time.Second, getReconnectInterval())
+	assert.Equal(t, time.Duration(60)*time.Second, getMembershipRequestTTL())
+}
+
+type rejectingCrypt struct {
+	*dummyCommModule
+}
+
+func (rejectingCrypt) ValidateAliveMsg(am *proto.SignedGossipMessage) bool {
+	return false
+}
+
+func TestValidateMembershipRequest(t *testing.T) {
+	t.Parallel()
+	selfInfo := func(expiration time.Time) *proto.SignedGossipMessage {
+		am := &proto.AliveMessage{
+			Membership: &proto.Member{Endpoint: "localhost:1234", PkiId: []byte("localhost:1234")},
+			Timestamp:  &proto.PeerTime{IncNum: uint64(time.Now().UnixNano()), SeqNum: 1},
+		}
+		if !expiration.IsZero() {
+			am.Expiration = expiration.UnixNano()
+		}
+		msg, _ := (&proto.GossipMessage{Content: &proto.GossipMessage_AliveMsg{AliveMsg: am}}).NoopSign()
+		return msg
+	}
+
+	d := &gossipDiscoveryImpl{crypt: &dummyCommModule{}}
+	assert.NoError(t, d.validateMembershipRequest(selfInfo(time.Time{})), "Should accept requests of peers which don't set an expiration")
+	assert.NoError(t, d.validateMembershipRequest(selfInfo(time.Now().Add(getMembershipRequestTTL()))))
+
+	expired := util.RejectedMessages.Count(util.RejectExpired)
+	assert.Error(t, d.validateMembershipRequest(selfInfo(time.Now().Add(-time.Second))))
+	assert.Error(t, d.validateMembershipRequest(selfInfo(time.Now().Add(3*getMembershipRequestTTL()))))
+	assert.True(t, util.RejectedMessages.Count(util.RejectExpired) >= expired+2)
+
+	notAlive, _ := (&proto.GossipMessage{Content: &proto.GossipMessage_MemReq{MemReq: &proto.MembershipRequest{}}}).NoopSign()
+	assert.Error(t, d.validateMembershipRequest(notAlive))
+
+	d.crypt = rejectingCrypt{&dummyCommModule{}}
+	assert.Error(t, d.validateMembershipRequest(selfInfo(time.Now().Add(getMembershipRequestTTL()))), "Should reject unauthentic requests")
+}
+
+func TestMembershipRequestExpiration(t *testing.T) {
+	t.Parallel()
+	inst := createDiscoveryInstance(13711, "d1", []string{})
+	defer inst.Stop()
+	req, err := inst.discoveryImpl().createMembershipRequest(true)
+	assert.NoError(t, err)
+	selfInfo, err := req.GetMemReq().SelfInformation.ToGossipMessage()
+	assert.NoError(t, err)
+	expiration := time.Unix(0, selfInfo.GetAliveMsg().Expiration)
+	assert.True(t, expiration.After(time.Now()))
+	assert.True(t, expiration.Before(time.Now().Add(getMembershipRequestTTL()+time.Second)))
+
+	alive, err := inst.discoveryImpl().createAliveMessage(true)
+	assert.NoError(t, err)
+	assert.Zero(t, alive.GetAliveMsg().Expiration, "Should not set an expiration on periodic alive messages")
 }
 
 func TestMsgStoreExpiration(t *testing.T) {
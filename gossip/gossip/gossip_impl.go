@@ -361,6 +361,7 @@ func (g *gossipServiceImpl) handleMessage(m proto.ReceivedMessage) {
 	defer g.logger.Debug("Exiting")
 
 	if !g.validateMsg(m) {
+		util.RejectedMessages.Inc(util.RejectInvalid)
 		g.logger.Warning("Message", msg, "isn't valid")
 		return
 	}
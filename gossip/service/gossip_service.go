@@ -55,6 +55,8 @@ type GossipService interface {
 	GetBlock(chainID string, index uint64) *common.Block
 	// AddPayload appends message payload to for given chain
 	AddPayload(chainID string, payload *proto.Payload) error
+	// PeerOrg returns the organization of the peer with the given PKI-ID, or nil if its identity is unknown
+	PeerOrg(pkiID gossipCommon.PKIidType) api.OrgIdentityType
 }
 
 // DeliveryServiceFactory factory to create and initialize delivery service instance
@@ -165,6 +167,15 @@ func GetGossipService() GossipService {
 	return gossipServiceInstance
 }
 
+// PeerOrg returns the organization of the peer with the given PKI-ID, or nil if its identity is unknown
+func (g *gossipServiceImpl) PeerOrg(pkiID gossipCommon.PKIidType) api.OrgIdentityType {
+	identity, err := g.idMapper.Get(pkiID)
+	if err != nil {
+		return nil
+	}
+	return g.secAdv.OrgByPeerIdentity(identity)
+}
+
 // NewConfigEventer creates a ConfigProcessor which the configtx.Manager can ultimately route config updates to
 func (g *gossipServiceImpl) NewConfigEventer() ConfigProcessor {
 	return newConfigEventer(g)
@@ -177,6 +188,11 @@ func (g *gossipServiceImpl) InitializeChannel(chainID string, committer committe
 	// Initialize new state provider for given committer
 	logger.Debug("Creating state provider for chainID", chainID)
 	g.chains[chainID] = state.NewGossipStateProvider(chainID, g, committer, g.mcs)
+	if viper.GetBool("peer.replica.enabled") {
+		// A read-only replica only receives blocks from the peers of its organization
+		logger.Info("This peer is a read-only replica, it receives the blocks of channel", chainID, "from the peers of its organization")
+		return
+	}
 	if g.deliveryService == nil {
 		var err error
 		g.deliveryService, err = g.deliveryFactory.Service(gossipServiceInstance, endpoints, g.mcs)
@@ -0,0 +1,60 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package util
+
+import "sync"
+
+// Reasons for which messages received from remote peers are rejected
+const (
+	// RejectTooLarge is for messages exceeding the configured size limits
+	RejectTooLarge = "too_large"
+	// RejectExpired is for membership requests whose expiration has passed
+	RejectExpired = "expired"
+	// RejectUnauthentic is for messages whose signature could not be verified
+	RejectUnauthentic = "unauthentic"
+	// RejectInvalid is for messages which are malformed or fail validation
+	RejectInvalid = "invalid"
+)
+
+// RejectedMessages counts the messages rejected by the gossip layer of this process, per reason
+var RejectedMessages = NewRejectionCounter()
+
+// RejectionCounter counts rejected messages per reason
+type RejectionCounter struct {
+	lock   sync.RWMutex
+	counts map[string]uint64
+}
+
+// NewRejectionCounter creates a RejectionCounter with all counts at zero
+func NewRejectionCounter() *RejectionCounter {
+	return &RejectionCounter{counts: make(map[string]uint64)}
+}
+
+// Inc increments the count of messages rejected for the given reason
+func (rc *RejectionCounter) Inc(reason string) {
+	rc.lock.Lock()
+	defer rc.lock.Unlock()
+	rc.counts[reason]++
+}
+
+// Count returns the number of messages rejected for the given reason
+func (rc *RejectionCounter) Count(reason string) uint64 {
+	rc.lock.RLock()
+	defer rc.lock.RUnlock()
+	return rc.counts[reason]
+}
+
+// Snapshot returns the number of rejected messages of every reason
+func (rc *RejectionCounter) Snapshot() map[string]uint64 {
+	rc.lock.RLock()
+	defer rc.lock.RUnlock()
+	snapshot := make(map[string]uint64, len(rc.counts))
+	for reason, count := range rc.counts {
+		snapshot[reason] = count
+	}
+	return snapshot
+}
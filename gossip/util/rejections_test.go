@@ -0,0 +1,28 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRejectionCounter(t *testing.T) {
+	rc := NewRejectionCounter()
+	assert.Equal(t, uint64(0), rc.Count(RejectTooLarge))
+	rc.Inc(RejectTooLarge)
+	rc.Inc(RejectTooLarge)
+	rc.Inc(RejectExpired)
+	assert.Equal(t, uint64(2), rc.Count(RejectTooLarge))
+	snapshot := rc.Snapshot()
+	assert.Equal(t, map[string]uint64{RejectTooLarge: 2, RejectExpired: 1}, snapshot)
+
+	// Snapshots are not affected by later rejections
+	rc.Inc(RejectExpired)
+	assert.Equal(t, uint64(1), snapshot[RejectExpired])
+}
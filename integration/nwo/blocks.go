@@ -0,0 +1,102 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package nwo
+
+import (
+	"fmt"
+	"testing"
+
+	cb "github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// ChannelHeaders returns the channel headers of the envelopes of a block
+func ChannelHeaders(block *cb.Block) ([]*cb.ChannelHeader, error) {
+	if block.Data == nil {
+		return nil, nil
+	}
+	headers := make([]*cb.ChannelHeader, len(block.Data.Data))
+	for i, data := range block.Data.Data {
+		env, err := utils.GetEnvelopeFromBlock(data)
+		if err != nil {
+			return nil, fmt.Errorf("envelope %d: %s", i, err)
+		}
+		payload, err := utils.GetPayload(env)
+		if err != nil {
+			return nil, fmt.Errorf("envelope %d: %s", i, err)
+		}
+		if payload.Header == nil {
+			return nil, fmt.Errorf("envelope %d has no header", i)
+		}
+		if headers[i], err = utils.UnmarshalChannelHeader(payload.Header.ChannelHeader); err != nil {
+			return nil, fmt.Errorf("envelope %d: %s", i, err)
+		}
+	}
+	return headers, nil
+}
+
+// TxIDs returns the IDs of the transactions of a block
+func TxIDs(block *cb.Block) ([]string, error) {
+	headers, err := ChannelHeaders(block)
+	if err != nil {
+		return nil, err
+	}
+	txIDs := make([]string, len(headers))
+	for i, header := range headers {
+		txIDs[i] = header.TxId
+	}
+	return txIDs, nil
+}
+
+// ValidationCodes returns the validation codes the committing peers set for
+// the transactions of a block
+func ValidationCodes(block *cb.Block) []pb.TxValidationCode {
+	if block.Metadata == nil || len(block.Metadata.Metadata) <= int(cb.BlockMetadataIndex_TRANSACTIONS_FILTER) {
+		return nil
+	}
+	flags := block.Metadata.Metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER]
+	codes := make([]pb.TxValidationCode, len(flags))
+	for i, flag := range flags {
+		codes[i] = pb.TxValidationCode(flag)
+	}
+	return codes
+}
+
+// RequireConfigBlock fails the test unless the block carries a single
+// configuration transaction of the given channel
+func RequireConfigBlock(t testing.TB, block *cb.Block, channelName string) {
+	headers, err := ChannelHeaders(block)
+	require.NoError(t, err)
+	require.Len(t, headers, 1, "Expected a single envelope in config block %d", block.Header.Number)
+	require.Equal(t, int32(cb.HeaderType_CONFIG), headers[0].Type, "Expected a config transaction in block %d", block.Header.Number)
+	require.Equal(t, channelName, headers[0].ChannelId)
+}
+
+// RequireTxIDs fails the test unless the block carries the transactions of
+// the given IDs, in that order
+func RequireTxIDs(t testing.TB, block *cb.Block, txIDs ...string) {
+	actual, err := TxIDs(block)
+	require.NoError(t, err)
+	require.Equal(t, txIDs, actual, "Unexpected transactions in block %d", block.Header.Number)
+}
+
+// RequireTxCount fails the test unless the block carries the given number of
+// transactions
+func RequireTxCount(t testing.TB, block *cb.Block, count int) {
+	require.NotNil(t, block.Data)
+	require.Len(t, block.Data.Data, count, "Unexpected number of transactions in block %d", block.Header.Number)
+}
+
+// RequireValid fails the test unless the committing peer validated all the
+// transactions of the block
+func RequireValid(t testing.TB, block *cb.Block) {
+	for i, code := range ValidationCodes(block) {
+		require.Equal(t, pb.TxValidationCode_VALID, code, "Transaction %d of block %d is invalid", i, block.Header.Number)
+	}
+}
@@ -0,0 +1,173 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package nwo
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric/protos/common"
+)
+
+// Chaincode describes a chaincode deployed on a channel
+type Chaincode struct {
+	Name    string
+	Version string
+	// Path is the import path of the chaincode
+	Path string
+	// Ctor is the JSON encoded instantiation arguments
+	Ctor string
+	// Policy is the endorsement policy, by default a member of any
+	// organization of the channel endorses
+	Policy string
+}
+
+// peerCLI runs the peer CLI as the admin of the organization of a peer
+func (n *Network) peerCLI(p *Peer, args ...string) ([]byte, error) {
+	cmd := n.Components.Command("peer", n.AdminEnv(p), args...)
+	cmd.Dir = n.RootDir
+	return output(cmd)
+}
+
+// ChannelBlockPath is the genesis block of an application channel, written
+// when the channel is created
+func (n *Network) ChannelBlockPath(channelName string) string {
+	return filepath.Join(n.RootDir, channelName+".block")
+}
+
+// CreateChannel creates an application channel through an orderer, as the
+// admin of the organization of a peer
+func (n *Network) CreateChannel(channelName string, o *Orderer, p *Peer) error {
+	_, err := n.peerCLI(p, "channel", "create",
+		"-o", n.OrdererAddress(o),
+		"-c", channelName,
+		"-f", n.CreateChannelTxPath(channelName),
+	)
+	return err
+}
+
+// JoinChannel joins peers to an application channel that was created
+func (n *Network) JoinChannel(channelName string, peers ...*Peer) error {
+	for _, p := range peers {
+		if _, err := n.peerCLI(p, "channel", "join", "-b", n.ChannelBlockPath(channelName)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateAndJoinChannel creates an application channel through an orderer and
+// joins the peers of the organizations of the channel to it
+func (n *Network) CreateAndJoinChannel(channelName string, o *Orderer) error {
+	peers := n.PeersInChannel(channelName)
+	if len(peers) == 0 {
+		return fmt.Errorf("channel %s has no peers", channelName)
+	}
+	if err := n.CreateChannel(channelName, o, peers[0]); err != nil {
+		return err
+	}
+	return n.JoinChannel(channelName, peers...)
+}
+
+// FetchBlock fetches a block of a channel from an orderer, as the admin of the
+// organization of a peer. It waits until the block is cut, or the timeout
+// expires
+func (n *Network) FetchBlock(channelName string, o *Orderer, p *Peer, number uint64, timeout time.Duration) (*cb.Block, error) {
+	dir := filepath.Join(n.RootDir, "blocks")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, fmt.Sprintf("%s_%d.block", channelName, number))
+	defer os.Remove(path)
+
+	cmd := n.Components.Command("peer", n.AdminEnv(p), "channel", "fetch", strconv.FormatUint(number, 10), path,
+		"-o", n.OrdererAddress(o),
+		"-c", channelName,
+	)
+	cmd.Dir = n.RootDir
+	if _, err := outputWithTimeout(cmd, timeout); err != nil {
+		return nil, err
+	}
+	data, err := readFile("block", path)
+	if err != nil {
+		return nil, err
+	}
+	block := &cb.Block{}
+	if err := proto.Unmarshal(data, block); err != nil {
+		return nil, fmt.Errorf("failed unmarshaling block %d of channel %s: %s", number, channelName, err)
+	}
+	if block.Header == nil || block.Header.Number != number {
+		return nil, fmt.Errorf("fetched a block other than block %d of channel %s", number, channelName)
+	}
+	return block, nil
+}
+
+// DeployChaincode installs a chaincode on the peers of a channel and
+// instantiates it. Chaincodes run in Docker containers
+func (n *Network) DeployChaincode(channelName string, o *Orderer, cc Chaincode) error {
+	peers := n.PeersInChannel(channelName)
+	if len(peers) == 0 {
+		return fmt.Errorf("channel %s has no peers", channelName)
+	}
+	for _, p := range peers {
+		if _, err := n.peerCLI(p, "chaincode", "install", "-n", cc.Name, "-v", cc.Version, "-p", cc.Path); err != nil {
+			return err
+		}
+	}
+
+	policy := cc.Policy
+	if policy == "" {
+		var members []string
+		for _, orgName := range n.Channel(channelName).Organizations {
+			members = append(members, fmt.Sprintf("'%s.member'", n.Organization(orgName).MSPID))
+		}
+		policy = fmt.Sprintf("OR(%s)", strings.Join(members, ","))
+	}
+	ctor := cc.Ctor
+	if ctor == "" {
+		ctor = `{"Args":[]}`
+	}
+	_, err := n.peerCLI(peers[0], "chaincode", "instantiate",
+		"-o", n.OrdererAddress(o),
+		"-C", channelName,
+		"-n", cc.Name,
+		"-v", cc.Version,
+		"-c", ctor,
+		"-P", policy,
+	)
+	return err
+}
+
+// InvokeChaincode invokes a chaincode through a peer, and submits the
+// transaction to an orderer
+func (n *Network) InvokeChaincode(channelName string, o *Orderer, p *Peer, name, ctor string) error {
+	_, err := n.peerCLI(p, "chaincode", "invoke",
+		"-o", n.OrdererAddress(o),
+		"-C", channelName,
+		"-n", name,
+		"-c", ctor,
+	)
+	return err
+}
+
+// QueryChaincode queries a chaincode on a peer and returns the result
+func (n *Network) QueryChaincode(channelName string, p *Peer, name, ctor string) (string, error) {
+	out, err := n.peerCLI(p, "chaincode", "query",
+		"-C", channelName,
+		"-n", name,
+		"-c", ctor,
+	)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(string(out)), "Query Result:")), nil
+}
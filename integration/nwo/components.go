@@ -0,0 +1,108 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package nwo
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+)
+
+// packages are the import paths of the binaries of the networks
+var packages = map[string]string{
+	"orderer":     "github.com/hyperledger/fabric/orderer",
+	"peer":        "github.com/hyperledger/fabric/peer",
+	"cryptogen":   "github.com/hyperledger/fabric/common/tools/cryptogen",
+	"configtxgen": "github.com/hyperledger/fabric/common/configtx/tool/configtxgen",
+}
+
+// Components are the binaries the networks run, built from the source tree
+type Components struct {
+	Dir string
+	// BuildTags are passed to go build
+	BuildTags string
+}
+
+// Build builds the orderer, peer, cryptogen and configtxgen binaries
+func (c *Components) Build() error {
+	if err := os.MkdirAll(c.Dir, 0755); err != nil {
+		return err
+	}
+	for name, pkg := range packages {
+		args := []string{"build", "-o", c.Path(name)}
+		if c.BuildTags != "" {
+			args = append(args, "-tags", c.BuildTags)
+		}
+		cmd := exec.Command("go", append(args, pkg)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("failed building %s: %s\n%s", name, err, out)
+		}
+	}
+	return nil
+}
+
+// Path returns the path of a binary
+func (c *Components) Path(name string) string {
+	return filepath.Join(c.Dir, name)
+}
+
+// Command returns the command running a binary with the given environment
+// variables in addition to the ones of the current process
+func (c *Components) Command(name string, env []string, args ...string) *exec.Cmd {
+	cmd := exec.Command(c.Path(name), args...)
+	cmd.Env = append(os.Environ(), env...)
+	return cmd
+}
+
+// Run runs a binary to completion
+func (c *Components) Run(name string, env []string, args ...string) error {
+	_, err := c.Output(name, env, args...)
+	return err
+}
+
+// Output runs a binary to completion and returns its standard output
+func (c *Components) Output(name string, env []string, args ...string) ([]byte, error) {
+	return output(c.Command(name, env, args...))
+}
+
+// output runs a command to completion and returns its standard output
+func output(cmd *exec.Cmd) ([]byte, error) {
+	return outputWithTimeout(cmd, 0)
+}
+
+// outputWithTimeout runs a command to completion, killing it if the timeout
+// expires first, and returns its standard output. A zero timeout waits forever
+func outputWithTimeout(cmd *exec.Cmd, timeout time.Duration) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	logger.Debugf("Running %v", cmd.Args)
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("%v failed: %s", cmd.Args, err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+	var expired <-chan time.Time
+	if timeout > 0 {
+		expired = time.After(timeout)
+	}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return nil, fmt.Errorf("%v failed: %s\n%s%s", cmd.Args, err, stdout.Bytes(), stderr.Bytes())
+		}
+		return stdout.Bytes(), nil
+	case <-expired:
+		cmd.Process.Kill()
+		<-done
+		return nil, fmt.Errorf("%v did not complete in %s", cmd.Args, timeout)
+	}
+}
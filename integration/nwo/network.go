@@ -0,0 +1,398 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package nwo assembles networks of orderers and peers running as local
+// processes, for end-to-end tests. A network is described by a Config, its
+// crypto material and channel artifacts are generated with cryptogen and
+// configtxgen, and its nodes are driven with the peer CLI.
+package nwo
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"text/template"
+
+	"github.com/hyperledger/fabric/common/flogging"
+)
+
+var logger = flogging.MustGetLogger("nwo")
+
+const (
+	// DefaultSystemChannel is the name of the system channel of the networks
+	// which do not name one
+	DefaultSystemChannel = "systemchannel"
+	// DefaultConsortium is the name of the consortium of the networks which do
+	// not name one
+	DefaultConsortium = "SampleConsortium"
+)
+
+// Config describes the nodes and the channels of a network
+type Config struct {
+	Organizations []*Organization
+	Consensus     Consensus
+	SystemChannel string
+	Consortium    string
+	Orderers      []*Orderer
+	Peers         []*Peer
+	Channels      []*Channel
+}
+
+// Organization is an organization of the network. An organization runs
+// either orderers or peers
+type Organization struct {
+	Name   string
+	MSPID  string
+	Domain string
+}
+
+// Consensus selects the consenter of the orderers
+type Consensus struct {
+	// Type is solo or kafka
+	Type string
+	// Brokers are the addresses of the Kafka brokers, which the network does
+	// not run
+	Brokers []string
+}
+
+// Orderer is an orderer of the network
+type Orderer struct {
+	Name         string
+	Organization string
+}
+
+// ID identifies the orderer in the network
+func (o *Orderer) ID() string {
+	return o.Organization + "." + o.Name
+}
+
+// Peer is a peer of the network
+type Peer struct {
+	Name         string
+	Organization string
+	// StateDatabase is goleveldb, the default, or CouchDB
+	StateDatabase string
+	// CouchDBAddress is the address of the CouchDB server of the peer, which
+	// the network does not run
+	CouchDBAddress string
+}
+
+// ID identifies the peer in the network
+func (p *Peer) ID() string {
+	return p.Organization + "." + p.Name
+}
+
+// Channel is an application channel of the network
+type Channel struct {
+	Name          string
+	Organizations []string
+}
+
+// Ports are the ports a node listens on
+type Ports struct {
+	Listen    int
+	Chaincode int
+	Events    int
+}
+
+// Network is a network of orderers and peers running as local processes
+type Network struct {
+	Config
+	RootDir    string
+	Components *Components
+	StartPort  int
+
+	ports     map[string]Ports
+	processes []*Process
+}
+
+// New returns a network described by the given config, whose files are
+// written in rootDir and whose nodes listen on the ports from startPort
+func New(config Config, rootDir string, components *Components, startPort int) *Network {
+	n := &Network{
+		Config:     config,
+		RootDir:    rootDir,
+		Components: components,
+		StartPort:  startPort,
+		ports:      map[string]Ports{},
+	}
+	if n.SystemChannel == "" {
+		n.SystemChannel = DefaultSystemChannel
+	}
+	if n.Consortium == "" {
+		n.Consortium = DefaultConsortium
+	}
+	if n.Consensus.Type == "" {
+		n.Consensus.Type = "solo"
+	}
+
+	port := startPort
+	for _, o := range n.Orderers {
+		n.ports[o.ID()] = Ports{Listen: port}
+		port++
+	}
+	for _, p := range n.Peers {
+		n.ports[p.ID()] = Ports{Listen: port, Chaincode: port + 1, Events: port + 2}
+		port += 3
+	}
+	return n
+}
+
+// Organization returns the organization of the given name, or nil
+func (n *Network) Organization(name string) *Organization {
+	for _, org := range n.Organizations {
+		if org.Name == name {
+			return org
+		}
+	}
+	return nil
+}
+
+// OrdererOrganizations returns the organizations running orderers
+func (n *Network) OrdererOrganizations() []*Organization {
+	var orgs []*Organization
+	for _, org := range n.Organizations {
+		if len(n.OrderersInOrg(org.Name)) > 0 {
+			orgs = append(orgs, org)
+		}
+	}
+	return orgs
+}
+
+// PeerOrganizations returns the organizations running peers
+func (n *Network) PeerOrganizations() []*Organization {
+	var orgs []*Organization
+	for _, org := range n.Organizations {
+		if len(n.PeersInOrg(org.Name)) > 0 {
+			orgs = append(orgs, org)
+		}
+	}
+	return orgs
+}
+
+// OrderersInOrg returns the orderers of an organization
+func (n *Network) OrderersInOrg(orgName string) []*Orderer {
+	var orderers []*Orderer
+	for _, o := range n.Orderers {
+		if o.Organization == orgName {
+			orderers = append(orderers, o)
+		}
+	}
+	return orderers
+}
+
+// PeersInOrg returns the peers of an organization
+func (n *Network) PeersInOrg(orgName string) []*Peer {
+	var peers []*Peer
+	for _, p := range n.Peers {
+		if p.Organization == orgName {
+			peers = append(peers, p)
+		}
+	}
+	return peers
+}
+
+// Peer returns the peer of the given name in an organization, or nil
+func (n *Network) Peer(orgName, name string) *Peer {
+	for _, p := range n.Peers {
+		if p.Organization == orgName && p.Name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// Orderer returns the orderer of the given name in an organization, or nil
+func (n *Network) Orderer(orgName, name string) *Orderer {
+	for _, o := range n.Orderers {
+		if o.Organization == orgName && o.Name == name {
+			return o
+		}
+	}
+	return nil
+}
+
+// Channel returns the channel of the given name, or nil
+func (n *Network) Channel(name string) *Channel {
+	for _, c := range n.Channels {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// PeersInChannel returns the peers of the organizations of a channel
+func (n *Network) PeersInChannel(channelName string) []*Peer {
+	var peers []*Peer
+	if c := n.Channel(channelName); c != nil {
+		for _, orgName := range c.Organizations {
+			peers = append(peers, n.PeersInOrg(orgName)...)
+		}
+	}
+	return peers
+}
+
+// Ports returns the ports of the node of the given ID
+func (n *Network) Ports(id string) Ports {
+	return n.ports[id]
+}
+
+// OrdererAddress returns the address of an orderer
+func (n *Network) OrdererAddress(o *Orderer) string {
+	return fmt.Sprintf("127.0.0.1:%d", n.ports[o.ID()].Listen)
+}
+
+// PeerAddress returns the address of a peer
+func (n *Network) PeerAddress(p *Peer) string {
+	return fmt.Sprintf("127.0.0.1:%d", n.ports[p.ID()].Listen)
+}
+
+// CryptoDir is the directory of the crypto material generated by cryptogen
+func (n *Network) CryptoDir() string {
+	return filepath.Join(n.RootDir, "crypto")
+}
+
+// OrgMSPDir is the verifying MSP of an organization
+func (n *Network) OrgMSPDir(org *Organization) string {
+	return filepath.Join(n.orgDir(org), "msp")
+}
+
+// OrdererMSPDir is the local MSP of an orderer
+func (n *Network) OrdererMSPDir(o *Orderer) string {
+	org := n.Organization(o.Organization)
+	return filepath.Join(n.orgDir(org), "orderers", o.Name+"."+org.Domain, "msp")
+}
+
+// PeerMSPDir is the local MSP of a peer
+func (n *Network) PeerMSPDir(p *Peer) string {
+	org := n.Organization(p.Organization)
+	return filepath.Join(n.orgDir(org), "peers", p.Name+"."+org.Domain, "msp")
+}
+
+// AdminMSPDir is the local MSP of the admin of an organization
+func (n *Network) AdminMSPDir(org *Organization) string {
+	return filepath.Join(n.orgDir(org), "users", "Admin@"+org.Domain, "msp")
+}
+
+func (n *Network) orgDir(org *Organization) string {
+	kind := "peerOrganizations"
+	if len(n.OrderersInOrg(org.Name)) > 0 {
+		kind = "ordererOrganizations"
+	}
+	return filepath.Join(n.CryptoDir(), kind, org.Domain)
+}
+
+// GenesisBlockPath is the genesis block of the system channel
+func (n *Network) GenesisBlockPath() string {
+	return filepath.Join(n.RootDir, n.SystemChannel+"_block.pb")
+}
+
+// CreateChannelTxPath is the transaction creating an application channel
+func (n *Network) CreateChannelTxPath(channelName string) string {
+	return filepath.Join(n.RootDir, channelName+"_tx.pb")
+}
+
+// NodeDir is the directory of the ledgers and the logs of the node of the
+// given ID
+func (n *Network) NodeDir(id string) string {
+	return filepath.Join(n.RootDir, "nodes", id)
+}
+
+// sampleConfigDir is the directory of the sample core.yaml and orderer.yaml
+// the nodes start from
+func sampleConfigDir() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "..", "..", "sampleconfig")
+}
+
+// GenerateConfigTree generates the crypto material of the organizations, the
+// genesis block of the system channel and the creation transactions of the
+// application channels
+func (n *Network) GenerateConfigTree() error {
+	if err := n.validate(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(n.RootDir, 0755); err != nil {
+		return err
+	}
+
+	cryptoConfig := filepath.Join(n.RootDir, "crypto-config.yaml")
+	if err := n.writeTemplate(cryptoConfig, cryptoConfigTemplate); err != nil {
+		return err
+	}
+	if err := n.Components.Run("cryptogen", nil, "generate", "--config", cryptoConfig, "--output", n.CryptoDir()); err != nil {
+		return err
+	}
+
+	if err := n.writeTemplate(filepath.Join(n.RootDir, "configtx.yaml"), configtxTemplate); err != nil {
+		return err
+	}
+	env := []string{"FABRIC_CFG_PATH=" + n.RootDir}
+	err := n.Components.Run("configtxgen", env, "-profile", n.SystemChannel, "-channelID", n.SystemChannel, "-outputBlock", n.GenesisBlockPath())
+	if err != nil {
+		return err
+	}
+	for _, c := range n.Channels {
+		err := n.Components.Run("configtxgen", env, "-profile", c.Name, "-channelID", c.Name, "-outputCreateChannelTx", n.CreateChannelTxPath(c.Name))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (n *Network) validate() error {
+	for _, o := range n.Orderers {
+		if n.Organization(o.Organization) == nil {
+			return fmt.Errorf("orderer %s belongs to unknown organization %s", o.Name, o.Organization)
+		}
+	}
+	for _, p := range n.Peers {
+		org := n.Organization(p.Organization)
+		if org == nil {
+			return fmt.Errorf("peer %s belongs to unknown organization %s", p.Name, p.Organization)
+		}
+		if len(n.OrderersInOrg(org.Name)) > 0 {
+			return fmt.Errorf("organization %s runs both orderers and peers", org.Name)
+		}
+	}
+	for _, c := range n.Channels {
+		for _, orgName := range c.Organizations {
+			if org := n.Organization(orgName); org == nil || len(n.PeersInOrg(orgName)) == 0 {
+				return fmt.Errorf("channel %s includes %s, which is not an organization running peers", c.Name, orgName)
+			}
+		}
+	}
+	if len(n.Orderers) == 0 {
+		return fmt.Errorf("the network has no orderer")
+	}
+	return nil
+}
+
+func (n *Network) writeTemplate(path, text string) error {
+	t, err := template.New(filepath.Base(path)).Parse(text)
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return t.Execute(f, n)
+}
+
+// readFile is ioutil.ReadFile, wrapping the error with the purpose of the file
+func readFile(purpose, path string) ([]byte, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading %s: %s", purpose, err)
+	}
+	return data, nil
+}
@@ -0,0 +1,134 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package nwo
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func basicConfig() Config {
+	return Config{
+		Organizations: []*Organization{
+			{Name: "OrdererOrg", MSPID: "OrdererMSP", Domain: "example.com"},
+			{Name: "Org1", MSPID: "Org1MSP", Domain: "org1.example.com"},
+			{Name: "Org2", MSPID: "Org2MSP", Domain: "org2.example.com"},
+		},
+		Orderers: []*Orderer{{Name: "orderer", Organization: "OrdererOrg"}},
+		Peers: []*Peer{
+			{Name: "peer0", Organization: "Org1"},
+			{Name: "peer0", Organization: "Org2"},
+		},
+		Channels: []*Channel{{Name: "testchannel", Organizations: []string{"Org1", "Org2"}}},
+	}
+}
+
+func buildComponents(t *testing.T, dir string) *Components {
+	components := &Components{Dir: filepath.Join(dir, "bin"), BuildTags: "nopkcs11"}
+	require.NoError(t, components.Build())
+	return components
+}
+
+func TestValidate(t *testing.T) {
+	config := basicConfig()
+	config.Peers = append(config.Peers, &Peer{Name: "peer1", Organization: "Org3"})
+	assert.Error(t, New(config, "", nil, 0).validate())
+
+	config = basicConfig()
+	config.Peers = append(config.Peers, &Peer{Name: "peer1", Organization: "OrdererOrg"})
+	assert.Error(t, New(config, "", nil, 0).validate())
+
+	config = basicConfig()
+	config.Channels[0].Organizations = append(config.Channels[0].Organizations, "OrdererOrg")
+	assert.Error(t, New(config, "", nil, 0).validate())
+
+	config = basicConfig()
+	config.Orderers = nil
+	assert.Error(t, New(config, "", nil, 0).validate())
+
+	assert.NoError(t, New(basicConfig(), "", nil, 0).validate())
+}
+
+func TestPorts(t *testing.T) {
+	n := New(basicConfig(), "", nil, 20000)
+	assert.Equal(t, "127.0.0.1:20000", n.OrdererAddress(n.Orderers[0]))
+	assert.Equal(t, Ports{Listen: 20001, Chaincode: 20002, Events: 20003}, n.Ports(n.Peers[0].ID()))
+	assert.Equal(t, DefaultSystemChannel, n.SystemChannel)
+	assert.Equal(t, "solo", n.Consensus.Type)
+}
+
+func TestGenerateConfigTree(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping the build of the network binaries in short mode")
+	}
+	dir, err := ioutil.TempDir("", "nwo")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	n := New(basicConfig(), dir, buildComponents(t, dir), 20000)
+	require.NoError(t, n.GenerateConfigTree())
+
+	for _, path := range []string{
+		n.OrdererMSPDir(n.Orderers[0]),
+		n.PeerMSPDir(n.Peers[1]),
+		n.AdminMSPDir(n.Organization("Org2")),
+		n.OrgMSPDir(n.Organization("Org1")),
+	} {
+		_, err := os.Stat(path)
+		assert.NoError(t, err)
+	}
+
+	data, err := ioutil.ReadFile(n.GenesisBlockPath())
+	require.NoError(t, err)
+	block, err := utils.GetBlockFromBlockBytes(data)
+	require.NoError(t, err)
+	RequireConfigBlock(t, block, n.SystemChannel)
+
+	data, err = ioutil.ReadFile(n.CreateChannelTxPath("testchannel"))
+	require.NoError(t, err)
+	env, err := utils.UnmarshalEnvelope(data)
+	require.NoError(t, err)
+	payload, err := utils.GetPayload(env)
+	require.NoError(t, err)
+	header, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	require.NoError(t, err)
+	assert.Equal(t, int32(cb.HeaderType_CONFIG_UPDATE), header.Type)
+	assert.Equal(t, "testchannel", header.ChannelId)
+}
+
+func TestNetwork(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping the network processes in short mode")
+	}
+	dir, err := ioutil.TempDir("", "nwo")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	n := New(basicConfig(), dir, buildComponents(t, dir), 21000+os.Getpid()%1000*10)
+	require.NoError(t, n.GenerateConfigTree())
+	require.NoError(t, n.Start())
+	defer n.Stop()
+
+	orderer := n.Orderers[0]
+	require.NoError(t, n.CreateAndJoinChannel("testchannel", orderer))
+
+	for _, p := range n.Peers {
+		block, err := n.FetchBlock("testchannel", orderer, p, 0, 30*time.Second)
+		require.NoError(t, err)
+		RequireConfigBlock(t, block, "testchannel")
+	}
+	_, err = n.FetchBlock("testchannel", orderer, n.Peers[0], 1, time.Second)
+	assert.Error(t, err, "The channel has no block 1")
+}
@@ -0,0 +1,121 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package nwo
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// startTimeout bounds the time a node takes to listen on its port
+const startTimeout = time.Minute
+
+// OrdererEnv returns the environment of an orderer process
+func (n *Network) OrdererEnv(o *Orderer) []string {
+	org := n.Organization(o.Organization)
+	return []string{
+		"FABRIC_CFG_PATH=" + sampleConfigDir(),
+		"ORDERER_GENERAL_LISTENADDRESS=127.0.0.1",
+		fmt.Sprintf("ORDERER_GENERAL_LISTENPORT=%d", n.ports[o.ID()].Listen),
+		"ORDERER_GENERAL_TLS_ENABLED=false",
+		"ORDERER_GENERAL_LEDGERTYPE=file",
+		"ORDERER_GENERAL_GENESISMETHOD=file",
+		"ORDERER_GENERAL_GENESISFILE=" + n.GenesisBlockPath(),
+		"ORDERER_GENERAL_LOCALMSPDIR=" + n.OrdererMSPDir(o),
+		"ORDERER_GENERAL_LOCALMSPID=" + org.MSPID,
+		"ORDERER_FILELEDGER_LOCATION=" + filepath.Join(n.NodeDir(o.ID()), "ledger"),
+	}
+}
+
+// PeerEnv returns the environment of a peer process
+func (n *Network) PeerEnv(p *Peer) []string {
+	org := n.Organization(p.Organization)
+	ports := n.ports[p.ID()]
+	address := n.PeerAddress(p)
+	env := []string{
+		"FABRIC_CFG_PATH=" + sampleConfigDir(),
+		"CORE_PEER_ID=" + p.Name + "." + org.Domain,
+		"CORE_PEER_ADDRESS=" + address,
+		"CORE_PEER_LISTENADDRESS=" + address,
+		fmt.Sprintf("CORE_PEER_CHAINCODELISTENADDRESS=127.0.0.1:%d", ports.Chaincode),
+		fmt.Sprintf("CORE_PEER_EVENTS_ADDRESS=127.0.0.1:%d", ports.Events),
+		"CORE_PEER_GOSSIP_BOOTSTRAP=" + address,
+		"CORE_PEER_GOSSIP_EXTERNALENDPOINT=" + address,
+		"CORE_PEER_TLS_ENABLED=false",
+		"CORE_PEER_MSPCONFIGPATH=" + n.PeerMSPDir(p),
+		"CORE_PEER_LOCALMSPID=" + org.MSPID,
+		"CORE_PEER_FILESYSTEMPATH=" + filepath.Join(n.NodeDir(p.ID()), "ledger"),
+	}
+	if p.StateDatabase != "" {
+		env = append(env, "CORE_LEDGER_STATE_STATEDATABASE="+p.StateDatabase)
+	}
+	if p.CouchDBAddress != "" {
+		env = append(env, "CORE_LEDGER_STATE_COUCHDBCONFIG_COUCHDBADDRESS="+p.CouchDBAddress)
+	}
+	return env
+}
+
+// AdminEnv returns the environment of the peer CLI acting as the admin of the
+// organization of a peer, against this peer
+func (n *Network) AdminEnv(p *Peer) []string {
+	org := n.Organization(p.Organization)
+	return []string{
+		"FABRIC_CFG_PATH=" + sampleConfigDir(),
+		"CORE_PEER_ADDRESS=" + n.PeerAddress(p),
+		"CORE_PEER_TLS_ENABLED=false",
+		"CORE_PEER_MSPCONFIGPATH=" + n.AdminMSPDir(org),
+		"CORE_PEER_LOCALMSPID=" + org.MSPID,
+	}
+}
+
+// Start starts the orderers, then the peers, and waits until they all listen
+// on their ports. The nodes already started are stopped if one fails to start
+func (n *Network) Start() error {
+	for _, o := range n.Orderers {
+		if err := n.start(o.ID(), "orderer", n.OrdererAddress(o), n.OrdererEnv(o)); err != nil {
+			n.Stop()
+			return err
+		}
+	}
+	for _, p := range n.Peers {
+		if err := n.start(p.ID(), "peer", n.PeerAddress(p), n.PeerEnv(p), "node", "start"); err != nil {
+			n.Stop()
+			return err
+		}
+	}
+	return nil
+}
+
+func (n *Network) start(id, component, address string, env []string, args ...string) error {
+	dir := n.NodeDir(id)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	cmd := n.Components.Command(component, env, args...)
+	cmd.Dir = dir
+	p, err := startProcess(id, cmd, filepath.Join(dir, component+".log"))
+	if err != nil {
+		return err
+	}
+	n.processes = append(n.processes, p)
+	return p.WaitForPort(address, startTimeout)
+}
+
+// Processes returns the processes of the started nodes
+func (n *Network) Processes() []*Process {
+	return n.processes
+}
+
+// Stop stops the nodes, the peers first
+func (n *Network) Stop() {
+	for i := len(n.processes) - 1; i >= 0; i-- {
+		n.processes[i].Stop()
+	}
+	n.processes = nil
+}
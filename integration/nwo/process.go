@@ -0,0 +1,98 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package nwo
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+// stopTimeout bounds the graceful shutdown of a process before it is killed
+const stopTimeout = 10 * time.Second
+
+// Process is a node of a network running as a local process, whose output
+// is logged to a file
+type Process struct {
+	Name    string
+	LogPath string
+
+	cmd    *exec.Cmd
+	log    *os.File
+	exited chan struct{}
+	err    error
+}
+
+func startProcess(name string, cmd *exec.Cmd, logPath string) (*Process, error) {
+	if err := os.MkdirAll(filepath.Dir(logPath), 0755); err != nil {
+		return nil, err
+	}
+	log, err := os.Create(logPath)
+	if err != nil {
+		return nil, err
+	}
+	cmd.Stdout, cmd.Stderr = log, log
+	if err := cmd.Start(); err != nil {
+		log.Close()
+		return nil, fmt.Errorf("failed starting %s: %s", name, err)
+	}
+
+	p := &Process{Name: name, LogPath: logPath, cmd: cmd, log: log, exited: make(chan struct{})}
+	go func() {
+		p.err = cmd.Wait()
+		log.Close()
+		close(p.exited)
+	}()
+	return p, nil
+}
+
+// WaitForPort waits until the process listens on the given address
+func (p *Process) WaitForPort(address string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		select {
+		case <-p.exited:
+			return fmt.Errorf("%s exited: %v, see %s", p.Name, p.err, p.LogPath)
+		default:
+		}
+		conn, err := net.DialTimeout("tcp", address, time.Second)
+		if err == nil {
+			conn.Close()
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%s is not listening on %s after %s, see %s", p.Name, address, timeout, p.LogPath)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// Exited returns a channel which is closed when the process exits
+func (p *Process) Exited() <-chan struct{} {
+	return p.exited
+}
+
+// Stop terminates the process, and kills it if it does not exit in time
+func (p *Process) Stop() {
+	select {
+	case <-p.exited:
+		return
+	default:
+	}
+	p.cmd.Process.Signal(syscall.SIGTERM)
+	select {
+	case <-p.exited:
+	case <-time.After(stopTimeout):
+		logger.Warningf("%s did not exit after %s, killing it", p.Name, stopTimeout)
+		p.cmd.Process.Kill()
+		<-p.exited
+	}
+}
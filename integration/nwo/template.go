@@ -0,0 +1,75 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package nwo
+
+// cryptoConfigTemplate is the cryptogen configuration of a network
+const cryptoConfigTemplate = `---
+OrdererOrgs:{{range $org := .OrdererOrganizations}}
+  - Name: {{$org.Name}}
+    Domain: {{$org.Domain}}
+    Specs:{{range $.OrderersInOrg $org.Name}}
+      - Hostname: {{.Name}}{{end}}
+{{- end}}
+PeerOrgs:{{range $org := .PeerOrganizations}}
+  - Name: {{$org.Name}}
+    Domain: {{$org.Domain}}
+    Specs:{{range $.PeersInOrg $org.Name}}
+      - Hostname: {{.Name}}{{end}}
+    Users:
+      Count: 1
+{{- end}}
+`
+
+// configtxTemplate is the configtxgen configuration of a network, with a
+// profile for the system channel and one for each application channel
+const configtxTemplate = `---
+Organizations:{{range $org := .Organizations}}
+  - &{{$org.Name}}
+    Name: {{$org.Name}}
+    ID: {{$org.MSPID}}
+    MSPDir: {{$.OrgMSPDir $org}}
+    AdminPrincipal: Role.ADMIN
+{{- with $.PeersInOrg $org.Name}}
+    AnchorPeers:
+      - Host: 127.0.0.1
+        Port: {{($.Ports (index . 0).ID).Listen}}
+{{- end}}
+{{- end}}
+
+Orderer: &OrdererDefaults
+  OrdererType: {{.Consensus.Type}}
+  Addresses:{{range .Orderers}}
+    - {{$.OrdererAddress .}}{{end}}
+  BatchTimeout: 1s
+  BatchSize:
+    MaxMessageCount: 1
+    AbsoluteMaxBytes: 98 MB
+    PreferredMaxBytes: 512 KB
+  MaxChannels: 0
+  Kafka:
+    Brokers:{{range .Consensus.Brokers}}
+      - {{.}}{{else}} []{{end}}
+  Organizations:
+
+Profiles:
+  {{.SystemChannel}}:
+    Orderer:
+      <<: *OrdererDefaults
+      Organizations:{{range .OrdererOrganizations}}
+        - *{{.Name}}{{end}}
+    Consortiums:
+      {{.Consortium}}:
+        Organizations:{{range .PeerOrganizations}}
+          - *{{.Name}}{{end}}
+{{- range .Channels}}
+  {{.Name}}:
+    Consortium: {{$.Consortium}}
+    Application:
+      Organizations:{{range .Organizations}}
+        - *{{.}}{{end}}
+{{- end}}
+`
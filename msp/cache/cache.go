@@ -0,0 +1,189 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package cache caches the identities deserialized and validated by an MSP, which are
+// otherwise parsed and checked against the certification chains of the MSP on every proposal,
+// endorsement and block signature
+package cache
+
+import (
+	"strconv"
+	"sync/atomic"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/msp"
+	pmsp "github.com/hyperledger/fabric/protos/msp"
+	gometrics "github.com/rcrowley/go-metrics"
+)
+
+var logger = flogging.MustGetLogger("msp/cache")
+
+// DefaultSize is the number of identities cached by an MSP unless set otherwise
+const DefaultSize = 1000
+
+var size int32 = DefaultSize
+
+// SetSize sets the number of identities cached by the MSPs created afterwards. A size of zero
+// disables the cache
+func SetSize(n int) {
+	atomic.StoreInt32(&size, int32(n))
+}
+
+// Stats counts the lookups in the caches of all the MSPs
+type Stats struct {
+	Hits        uint64
+	Misses      uint64
+	Evictions   uint64
+	Expirations uint64
+}
+
+// The counters of the lookups in the caches of all the MSPs, published with the metrics of the node
+var (
+	hits        = gometrics.GetOrRegisterCounter("msp.cache.hits", metrics.Registry)
+	misses      = gometrics.GetOrRegisterCounter("msp.cache.misses", metrics.Registry)
+	evictions   = gometrics.GetOrRegisterCounter("msp.cache.evictions", metrics.Registry)
+	expirations = gometrics.GetOrRegisterCounter("msp.cache.expirations", metrics.Registry)
+)
+
+// GetStats returns the counts of the lookups in the caches since the process started
+func GetStats() Stats {
+	return Stats{
+		Hits:        uint64(hits.Count()),
+		Misses:      uint64(misses.Count()),
+		Evictions:   uint64(evictions.Count()),
+		Expirations: uint64(expirations.Count()),
+	}
+}
+
+type cachedMSP struct {
+	msp.MSP
+
+	// the identities deserialized, keyed by their serialized form
+	deserializeIdentityCache *lru
+	// the serialized identities found valid
+	validateIdentityCache *lru
+	// the serialized identities and principals they were found to satisfy
+	satisfiesPrincipalCache *lru
+}
+
+// New wraps an MSP with caches of its identities, or returns it as is if the cache is disabled.
+// The identities are cached until their certificate expires, or the MSP is set up again with a
+// new configuration, such as new CRLs. Only successful validations are cached
+func New(o msp.MSP) msp.MSP {
+	n := int(atomic.LoadInt32(&size))
+	if n <= 0 {
+		return o
+	}
+	logger.Debugf("Caching up to %d identities", n)
+	return &cachedMSP{
+		MSP:                      o,
+		deserializeIdentityCache: newLRU(n),
+		validateIdentityCache:    newLRU(n),
+		satisfiesPrincipalCache:  newLRU(n),
+	}
+}
+
+func (c *cachedMSP) Setup(config *pmsp.MSPConfig) error {
+	err := c.MSP.Setup(config)
+
+	// The cached results may not hold under the new configuration
+	c.deserializeIdentityCache.purge()
+	c.validateIdentityCache.purge()
+	c.satisfiesPrincipalCache.purge()
+
+	return err
+}
+
+func (c *cachedMSP) DeserializeIdentity(serializedIdentity []byte) (msp.Identity, error) {
+	if id, ok := c.deserializeIdentityCache.get(string(serializedIdentity)); ok {
+		return id.(*cachedIdentity), nil
+	}
+
+	id, err := c.MSP.DeserializeIdentity(serializedIdentity)
+	if err != nil {
+		return nil, err
+	}
+	cached := &cachedIdentity{
+		Identity:   id,
+		serialized: append([]byte{}, serializedIdentity...),
+		msp:        c,
+	}
+	if !expired(id.ExpiresAt()) {
+		c.deserializeIdentityCache.add(string(serializedIdentity), cached, id.ExpiresAt())
+	}
+	return cached, nil
+}
+
+func (c *cachedMSP) Validate(id msp.Identity) error {
+	id, serialized, err := unwrap(id)
+	if err != nil {
+		return c.MSP.Validate(id)
+	}
+	if _, ok := c.validateIdentityCache.get(string(serialized)); ok {
+		return nil
+	}
+
+	if err := c.MSP.Validate(id); err != nil {
+		return err
+	}
+	c.validateIdentityCache.add(string(serialized), struct{}{}, id.ExpiresAt())
+	return nil
+}
+
+func (c *cachedMSP) SatisfiesPrincipal(id msp.Identity, principal *pmsp.MSPPrincipal) error {
+	id, serialized, err := unwrap(id)
+	if err != nil {
+		return c.MSP.SatisfiesPrincipal(id, principal)
+	}
+	principalBytes, err := proto.Marshal(principal)
+	if err != nil {
+		return c.MSP.SatisfiesPrincipal(id, principal)
+	}
+	key := strconv.Itoa(len(principalBytes)) + ":" + string(principalBytes) + string(serialized)
+	if _, ok := c.satisfiesPrincipalCache.get(key); ok {
+		return nil
+	}
+
+	if err := c.MSP.SatisfiesPrincipal(id, principal); err != nil {
+		return err
+	}
+	c.satisfiesPrincipalCache.add(key, struct{}{}, id.ExpiresAt())
+	return nil
+}
+
+// unwrap returns the identity deserialized by the wrapped MSP, which only knows about its own
+// identities, along with its serialized form
+func unwrap(id msp.Identity) (msp.Identity, []byte, error) {
+	if cached, ok := id.(*cachedIdentity); ok {
+		return cached.Identity, cached.serialized, nil
+	}
+	serialized, err := id.Serialize()
+	return id, serialized, err
+}
+
+// cachedIdentity is an identity deserialized by a cachedMSP, which it validates through the
+// caches of the MSP
+type cachedIdentity struct {
+	msp.Identity
+	serialized []byte
+	msp        *cachedMSP
+}
+
+// Unwrap implements msp.WrappedIdentity, so that the signatures of the identity are verified in
+// batches
+func (id *cachedIdentity) Unwrap() msp.Identity {
+	return id.Identity
+}
+
+func (id *cachedIdentity) Validate() error {
+	return id.msp.Validate(id)
+}
+
+func (id *cachedIdentity) SatisfiesPrincipal(principal *pmsp.MSPPrincipal) error {
+	return id.msp.SatisfiesPrincipal(id, principal)
+}
@@ -0,0 +1,237 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cache
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/config"
+	"github.com/hyperledger/fabric/msp"
+	pmsp "github.com/hyperledger/fabric/protos/msp"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockIdentity struct {
+	msp.Identity
+	serialized []byte
+	expiresAt  time.Time
+}
+
+func (id *mockIdentity) Serialize() ([]byte, error) {
+	return id.serialized, nil
+}
+
+func (id *mockIdentity) ExpiresAt() time.Time {
+	return id.expiresAt
+}
+
+type mockMSP struct {
+	msp.MSP
+	expiresAt time.Time
+	invalid   bool
+
+	setups, deserializations, validations, principalChecks int
+}
+
+func (m *mockMSP) Setup(config *pmsp.MSPConfig) error {
+	m.setups++
+	return nil
+}
+
+func (m *mockMSP) DeserializeIdentity(serializedIdentity []byte) (msp.Identity, error) {
+	m.deserializations++
+	return &mockIdentity{serialized: serializedIdentity, expiresAt: m.expiresAt}, nil
+}
+
+func (m *mockMSP) Validate(id msp.Identity) error {
+	m.validations++
+	if _, isMock := id.(*mockIdentity); !isMock {
+		return errors.New("identity not deserialized by this MSP")
+	}
+	if m.invalid {
+		return errors.New("invalid identity")
+	}
+	return nil
+}
+
+func (m *mockMSP) SatisfiesPrincipal(id msp.Identity, principal *pmsp.MSPPrincipal) error {
+	m.principalChecks++
+	if _, isMock := id.(*mockIdentity); !isMock {
+		return errors.New("identity not deserialized by this MSP")
+	}
+	return nil
+}
+
+func setClock(t time.Time) func() {
+	now = func() time.Time { return t }
+	return func() { now = time.Now }
+}
+
+func TestDisabled(t *testing.T) {
+	defer SetSize(DefaultSize)
+	SetSize(0)
+	inner := &mockMSP{}
+	assert.Equal(t, msp.MSP(inner), New(inner))
+}
+
+func TestDeserializeIdentity(t *testing.T) {
+	inner := &mockMSP{}
+	c := New(inner)
+
+	id, err := c.DeserializeIdentity([]byte("alice"))
+	assert.NoError(t, err)
+	id2, err := c.DeserializeIdentity([]byte("alice"))
+	assert.NoError(t, err)
+	assert.True(t, id == id2)
+	assert.Equal(t, 1, inner.deserializations)
+
+	// The identity validates itself through the cache, unwrapped for the wrapped MSP
+	assert.NoError(t, id.Validate())
+	assert.NoError(t, id2.Validate())
+	assert.NoError(t, c.Validate(id))
+	assert.Equal(t, 1, inner.validations)
+
+	_, err = c.DeserializeIdentity([]byte("bob"))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, inner.deserializations)
+}
+
+func TestSatisfiesPrincipal(t *testing.T) {
+	inner := &mockMSP{}
+	c := New(inner)
+	member := &pmsp.MSPPrincipal{
+		PrincipalClassification: pmsp.MSPPrincipal_ROLE,
+		Principal:               mustMarshal(&pmsp.MSPRole{MspIdentifier: "Org1MSP", Role: pmsp.MSPRole_MEMBER}),
+	}
+	admin := &pmsp.MSPPrincipal{
+		PrincipalClassification: pmsp.MSPPrincipal_ROLE,
+		Principal:               mustMarshal(&pmsp.MSPRole{MspIdentifier: "Org1MSP", Role: pmsp.MSPRole_ADMIN}),
+	}
+
+	id, err := c.DeserializeIdentity([]byte("alice"))
+	assert.NoError(t, err)
+	assert.NoError(t, id.SatisfiesPrincipal(member))
+	assert.NoError(t, c.SatisfiesPrincipal(id, member))
+	assert.Equal(t, 1, inner.principalChecks)
+	assert.NoError(t, id.SatisfiesPrincipal(admin))
+	assert.Equal(t, 2, inner.principalChecks)
+
+	// Identities which were not deserialized by the MSP are looked up by their serialized form
+	assert.NoError(t, c.SatisfiesPrincipal(&mockIdentity{serialized: []byte("alice")}, member))
+	assert.Equal(t, 2, inner.principalChecks)
+}
+
+func TestValidationFailuresNotCached(t *testing.T) {
+	inner := &mockMSP{invalid: true}
+	c := New(inner)
+
+	id, err := c.DeserializeIdentity([]byte("alice"))
+	assert.NoError(t, err)
+	assert.Error(t, id.Validate())
+	assert.Error(t, id.Validate())
+	assert.Equal(t, 2, inner.validations)
+
+	inner.invalid = false
+	assert.NoError(t, id.Validate())
+	assert.NoError(t, id.Validate())
+	assert.Equal(t, 3, inner.validations)
+}
+
+func TestExpiration(t *testing.T) {
+	issued := time.Now()
+	defer setClock(issued)()
+	inner := &mockMSP{expiresAt: issued.Add(time.Hour)}
+	c := New(inner)
+
+	id, err := c.DeserializeIdentity([]byte("alice"))
+	assert.NoError(t, err)
+	assert.NoError(t, id.Validate())
+	assert.NoError(t, id.Validate())
+	assert.Equal(t, 1, inner.validations)
+
+	setClock(issued.Add(time.Hour))
+	before := GetStats()
+	assert.NoError(t, id.Validate())
+	assert.Equal(t, 2, inner.validations, "Validations of expired identities should not be cached")
+	_, err = c.DeserializeIdentity([]byte("alice"))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, inner.deserializations, "Expired identities should be deserialized again")
+	assert.Equal(t, before.Expirations+2, GetStats().Expirations)
+
+	// Identities expired already are not cached
+	_, err = c.DeserializeIdentity([]byte("alice"))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, inner.deserializations)
+}
+
+func TestSetupPurges(t *testing.T) {
+	inner := &mockMSP{}
+	c := New(inner)
+
+	id, err := c.DeserializeIdentity([]byte("alice"))
+	assert.NoError(t, err)
+	assert.NoError(t, id.Validate())
+
+	// A new configuration, such as new CRLs, may revoke the identities validated before
+	assert.NoError(t, c.Setup(&pmsp.MSPConfig{}))
+	assert.Equal(t, 1, inner.setups)
+	assert.NoError(t, id.Validate())
+	assert.Equal(t, 2, inner.validations)
+	_, err = c.DeserializeIdentity([]byte("alice"))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, inner.deserializations)
+}
+
+func TestBccspMsp(t *testing.T) {
+	dir, err := config.GetDevMspDir()
+	assert.NoError(t, err)
+	conf, err := msp.GetLocalMspConfig(dir, nil, "DEFAULT")
+	assert.NoError(t, err)
+	inner, err := msp.NewBccspMsp()
+	assert.NoError(t, err)
+	c := New(inner)
+	assert.NoError(t, c.Setup(conf))
+
+	signer, err := c.GetDefaultSigningIdentity()
+	assert.NoError(t, err)
+	serialized, err := signer.Serialize()
+	assert.NoError(t, err)
+
+	id, err := c.DeserializeIdentity(serialized)
+	assert.NoError(t, err)
+	assert.Equal(t, "DEFAULT", id.GetMSPIdentifier())
+	assert.NoError(t, id.Validate())
+	assert.NoError(t, id.Validate())
+	assert.NoError(t, id.SatisfiesPrincipal(&pmsp.MSPPrincipal{
+		PrincipalClassification: pmsp.MSPPrincipal_ROLE,
+		Principal:               mustMarshal(&pmsp.MSPRole{MspIdentifier: "DEFAULT", Role: pmsp.MSPRole_ADMIN}),
+	}))
+	assert.NoError(t, id.SatisfiesPrincipal(&pmsp.MSPPrincipal{
+		PrincipalClassification: pmsp.MSPPrincipal_IDENTITY,
+		Principal:               serialized,
+	}))
+	assert.Error(t, id.SatisfiesPrincipal(&pmsp.MSPPrincipal{
+		PrincipalClassification: pmsp.MSPPrincipal_ROLE,
+		Principal:               mustMarshal(&pmsp.MSPRole{MspIdentifier: "OtherMSP", Role: pmsp.MSPRole_MEMBER}),
+	}))
+
+	msg := []byte("hello")
+	sig, err := signer.Sign(msg)
+	assert.NoError(t, err)
+	assert.NoError(t, id.Verify(msg, sig))
+}
+
+func mustMarshal(msg proto.Message) []byte {
+	b, err := proto.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
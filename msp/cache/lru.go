@@ -0,0 +1,102 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// now is replaced by the tests to move the clock past the expiration of the entries
+var now = time.Now
+
+type entry struct {
+	key       string
+	value     interface{}
+	expiresAt time.Time
+}
+
+// lru is a cache of bounded size, which evicts its least recently used entries first. Its
+// entries are dropped once they expire
+type lru struct {
+	lock  sync.Mutex
+	size  int
+	ll    *list.List
+	items map[string]*list.Element
+}
+
+func newLRU(size int) *lru {
+	return &lru{
+		size:  size,
+		ll:    list.New(),
+		items: make(map[string]*list.Element),
+	}
+}
+
+func (c *lru) get(key string) (interface{}, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		misses.Inc(1)
+		return nil, false
+	}
+	e := elem.Value.(*entry)
+	if expired(e.expiresAt) {
+		c.remove(elem)
+		expirations.Inc(1)
+		misses.Inc(1)
+		return nil, false
+	}
+	c.ll.MoveToFront(elem)
+	hits.Inc(1)
+	return e.value, true
+}
+
+// add adds an entry expiring at expiresAt, or never if expiresAt is the zero time
+func (c *lru) add(key string, value interface{}, expiresAt time.Time) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		elem.Value = &entry{key: key, value: value, expiresAt: expiresAt}
+		c.ll.MoveToFront(elem)
+		return
+	}
+	c.items[key] = c.ll.PushFront(&entry{key: key, value: value, expiresAt: expiresAt})
+	if c.ll.Len() > c.size {
+		c.remove(c.ll.Back())
+		evictions.Inc(1)
+	}
+}
+
+// purge drops all the entries
+func (c *lru) purge() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.ll.Init()
+	c.items = make(map[string]*list.Element)
+}
+
+func (c *lru) len() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.ll.Len()
+}
+
+// expired reports whether an entry expiring at expiresAt expired
+func expired(expiresAt time.Time) bool {
+	return !expiresAt.IsZero() && !now().Before(expiresAt)
+}
+
+func (c *lru) remove(elem *list.Element) {
+	c.ll.Remove(elem)
+	delete(c.items, elem.Value.(*entry).key)
+}
@@ -0,0 +1,80 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/common/metrics"
+	gometrics "github.com/rcrowley/go-metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRU(t *testing.T) {
+	before := GetStats()
+	c := newLRU(2)
+
+	c.add("a", 1, time.Time{})
+	c.add("b", 2, time.Time{})
+	v, ok := c.get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+
+	// b is the least recently used entry
+	c.add("c", 3, time.Time{})
+	assert.Equal(t, 2, c.len())
+	_, ok = c.get("b")
+	assert.False(t, ok)
+	_, ok = c.get("a")
+	assert.True(t, ok)
+	_, ok = c.get("c")
+	assert.True(t, ok)
+
+	// Adding an entry again replaces it
+	c.add("c", 4, time.Time{})
+	v, _ = c.get("c")
+	assert.Equal(t, 4, v)
+	assert.Equal(t, 2, c.len())
+
+	c.purge()
+	assert.Equal(t, 0, c.len())
+	_, ok = c.get("a")
+	assert.False(t, ok)
+
+	stats := GetStats()
+	assert.Equal(t, before.Hits+4, stats.Hits)
+	assert.Equal(t, before.Misses+2, stats.Misses)
+	assert.Equal(t, before.Evictions+1, stats.Evictions)
+
+	// The counts are published with the metrics of the node
+	for name, count := range map[string]uint64{
+		"msp.cache.hits":        stats.Hits,
+		"msp.cache.misses":      stats.Misses,
+		"msp.cache.evictions":   stats.Evictions,
+		"msp.cache.expirations": stats.Expirations,
+	} {
+		counter, ok := metrics.Registry.Get(name).(gometrics.Counter)
+		assert.True(t, ok, "Should have registered %s", name)
+		assert.Equal(t, int64(count), counter.Count())
+	}
+}
+
+func TestLRUExpiration(t *testing.T) {
+	start := time.Now()
+	defer setClock(start)()
+	c := newLRU(2)
+
+	c.add("a", 1, start.Add(time.Minute))
+	_, ok := c.get("a")
+	assert.True(t, ok)
+
+	setClock(start.Add(time.Minute))
+	_, ok = c.get("a")
+	assert.False(t, ok)
+	assert.Equal(t, 0, c.len(), "Expired entries should be dropped")
+}
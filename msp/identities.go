@@ -14,6 +14,7 @@ import (
 	"encoding/pem"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/bccsp"
@@ -93,6 +94,11 @@ func (id *identity) GetOrganizationalUnits() []*OUIdentifier {
 	return res
 }
 
+// ExpiresAt returns the time at which the certificate of this instance expires
+func (id *identity) ExpiresAt() time.Time {
+	return id.cert.NotAfter
+}
+
 // NewSerializedIdentity returns a serialized identity
 // having as content the passed mspID and x509 certificate in PEM format.
 // This method does not check the validity of certificate nor
@@ -114,15 +120,9 @@ func NewSerializedIdentity(mspID string, certPEM []byte) ([]byte, error) {
 func (id *identity) Verify(msg []byte, sig []byte) error {
 	// mspIdentityLogger.Infof("Verifying signature")
 
-	// Compute Hash
-	hashOpt, err := id.getHashOpt(id.msp.cryptoConfig.SignatureHashFamily)
-	if err != nil {
-		return fmt.Errorf("Failed getting hash function options [%s]", err)
-	}
-
-	digest, err := id.msp.bccsp.Hash(msg, hashOpt)
+	digest, err := id.digest(msg)
 	if err != nil {
-		return fmt.Errorf("Failed computing digest [%s]", err)
+		return err
 	}
 
 	if mspIdentityLogger.IsEnabledFor(logging.DEBUG) {
@@ -140,6 +140,87 @@ func (id *identity) Verify(msg []byte, sig []byte) error {
 	return nil
 }
 
+// digest computes the digest of msg signed by this identity
+func (id *identity) digest(msg []byte) ([]byte, error) {
+	hashOpt, err := id.getHashOpt(id.msp.cryptoConfig.SignatureHashFamily)
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting hash function options [%s]", err)
+	}
+
+	digest, err := id.msp.bccsp.Hash(msg, hashOpt)
+	if err != nil {
+		return nil, fmt.Errorf("Failed computing digest [%s]", err)
+	}
+	return digest, nil
+}
+
+// WrappedIdentity is implemented by the identities which wrap an identity of an MSP, such as
+// those deserialized by the MSP caches, so that VerifyBatch reaches the BCCSP of their MSP
+type WrappedIdentity interface {
+	Identity
+
+	// Unwrap returns the wrapped identity
+	Unwrap() Identity
+}
+
+// unwrapIdentity returns the identity of an MSP wrapped by id, if any
+func unwrapIdentity(id Identity) (*identity, bool) {
+	for {
+		switch i := id.(type) {
+		case *identity:
+			return i, true
+		case *signingidentity:
+			return &i.identity, true
+		case WrappedIdentity:
+			id = i.Unwrap()
+		default:
+			return nil, false
+		}
+	}
+}
+
+// VerifyBatch verifies the signatures sigs of msgs by ids, as their Verify method does. The
+// signatures of the identities whose MSP relies on a BCCSP implementing bccsp.BatchVerifier are
+// handed to it together, the others are verified one after the other. It returns the error of the
+// verification of each signature, nil if it is valid
+func VerifyBatch(ids []Identity, msgs, sigs [][]byte) []error {
+	errs := make([]error, len(ids))
+	requests := make(map[bccsp.BatchVerifier][]bccsp.VerifyRequest)
+	indices := make(map[bccsp.BatchVerifier][]int)
+	for i, id := range ids {
+		mspID, isMSPIdentity := unwrapIdentity(id)
+		if !isMSPIdentity {
+			errs[i] = id.Verify(msgs[i], sigs[i])
+			continue
+		}
+		verifier, canBatch := mspID.msp.bccsp.(bccsp.BatchVerifier)
+		if !canBatch {
+			errs[i] = id.Verify(msgs[i], sigs[i])
+			continue
+		}
+		digest, err := mspID.digest(msgs[i])
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		requests[verifier] = append(requests[verifier], bccsp.VerifyRequest{Key: mspID.pk, Signature: sigs[i], Digest: digest})
+		indices[verifier] = append(indices[verifier], i)
+	}
+
+	for verifier, reqs := range requests {
+		valid, err := verifier.VerifyBatch(reqs)
+		for j, i := range indices[verifier] {
+			switch {
+			case err != nil:
+				errs[i] = fmt.Errorf("Could not determine the validity of the signature, err %s", err)
+			case !valid[j]:
+				errs[i] = errors.New("The signature is invalid")
+			}
+		}
+	}
+	return errs
+}
+
 // Serialize returns a byte array representation of this identity
 func (id *identity) Serialize() ([]byte, error) {
 	// mspIdentityLogger.Infof("Serializing identity %s", id.id)
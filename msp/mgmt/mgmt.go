@@ -27,6 +27,7 @@ import (
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/core/config"
 	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/msp/cache"
 )
 
 // LoadLocalMsp loads the local MSP from the specified directory
@@ -79,7 +80,7 @@ func GetManagerForChain(chainID string) msp.MSPManager {
 		case *configvaluesmsp.MSPConfigHandler:
 			// check for nil MSPManager interface as it can exist but not be
 			// instantiated
-			if mgr.MSPManager == nil {
+			if mgr.Manager() == nil {
 				mspLogger.Debugf("MSPManager is not instantiated; no MSPs are defined for this channel.")
 				// return nil so the MSPManager methods cannot be accidentally called,
 				// which would result in a panic
@@ -139,6 +140,7 @@ func GetLocalMSP() msp.MSP {
 			if err != nil {
 				mspLogger.Fatalf("Failed to initialize local MSP, received err %s", err)
 			}
+			lclMsp = cache.New(lclMsp)
 			localMsp = lclMsp
 		}
 	}
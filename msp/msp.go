@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package msp
 
 import (
+	"time"
+
 	"github.com/hyperledger/fabric/protos/msp"
 )
 
@@ -143,6 +145,10 @@ type Identity interface {
 	// Verify a signature over some message using this identity as reference
 	Verify(msg []byte, sig []byte) error
 
+	// ExpiresAt returns the time at which the identity expires, or the zero
+	// time if the identity does not expire
+	ExpiresAt() time.Time
+
 	// Serialize converts an identity to bytes
 	Serialize() ([]byte, error)
 
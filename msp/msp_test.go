@@ -404,6 +404,45 @@ func TestSignAndVerify(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestVerifyBatch(t *testing.T) {
+	id, err := localMsp.GetDefaultSigningIdentity()
+	assert.NoError(t, err)
+	serializedID, err := id.Serialize()
+	assert.NoError(t, err)
+	idBack, err := localMsp.DeserializeIdentity(serializedID)
+	assert.NoError(t, err)
+
+	msgs := [][]byte{[]byte("foo"), []byte("bar"), []byte("baz"), []byte("qux")}
+	sigs := make([][]byte, len(msgs))
+	for i, msg := range msgs {
+		sigs[i], err = id.Sign(msg)
+		assert.NoError(t, err)
+	}
+	sigs[2] = sigs[1]
+	sigs[3] = []byte("garbage")
+
+	// The signing identity and the wrapped one are verified along with the deserialized one
+	wrapped := &wrappedIdentity{Identity: idBack}
+	_, ok := unwrapIdentity(wrapped)
+	assert.True(t, ok)
+	_, ok = unwrapIdentity(id)
+	assert.True(t, ok)
+	errs := VerifyBatch([]Identity{idBack, id, wrapped, idBack}, msgs, sigs)
+	assert.Len(t, errs, 4)
+	assert.NoError(t, errs[0])
+	assert.NoError(t, errs[1])
+	assert.Error(t, errs[2])
+	assert.Error(t, errs[3])
+}
+
+type wrappedIdentity struct {
+	Identity
+}
+
+func (id *wrappedIdentity) Unwrap() Identity {
+	return id.Identity
+}
+
 func TestSignAndVerifyFailures(t *testing.T) {
 	msg := []byte("foo")
 
@@ -613,14 +652,16 @@ func TestOUPolicyPrincipalBadMSPID(t *testing.T) {
 	assert.Error(t, err)
 }
 
-func TestOUPolicyPrincipalBadPath(t *testing.T) {
+func TestOUPolicyPrincipalNoCertifiers(t *testing.T) {
+	backup := localMsp.(*bccspmsp).ouIdentifiers
+	defer func() { localMsp.(*bccspmsp).ouIdentifiers = backup }()
+
 	id, err := localMsp.GetDefaultSigningIdentity()
 	assert.NoError(t, err)
 
 	ou := &msp.OrganizationUnit{
 		OrganizationalUnitIdentifier: "COP",
 		MspIdentifier:                "DEFAULT",
-		CertifiersIdentifier:         nil,
 	}
 	bytes, err := proto.Marshal(ou)
 	assert.NoError(t, err)
@@ -630,13 +671,20 @@ func TestOUPolicyPrincipalBadPath(t *testing.T) {
 		Principal:               bytes,
 	}
 
+	// the MSP recognizes no CA for the OU
+	localMsp.(*bccspmsp).ouIdentifiers = nil
 	err = id.SatisfiesPrincipal(principal)
 	assert.Error(t, err)
 
+	localMsp.(*bccspmsp).ouIdentifiers = map[string][][]byte{
+		"COP": {id.GetOrganizationalUnits()[0].CertifiersIdentifier},
+	}
+	err = id.SatisfiesPrincipal(principal)
+	assert.NoError(t, err)
+
 	ou = &msp.OrganizationUnit{
-		OrganizationalUnitIdentifier: "COP",
+		OrganizationalUnitIdentifier: "department-a",
 		MspIdentifier:                "DEFAULT",
-		CertifiersIdentifier:         []byte{0, 1, 2, 3, 4},
 	}
 	bytes, err = proto.Marshal(ou)
 	assert.NoError(t, err)
@@ -650,6 +698,27 @@ func TestOUPolicyPrincipalBadPath(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestOUPolicyPrincipalBadPath(t *testing.T) {
+	id, err := localMsp.GetDefaultSigningIdentity()
+	assert.NoError(t, err)
+
+	ou := &msp.OrganizationUnit{
+		OrganizationalUnitIdentifier: "COP",
+		MspIdentifier:                "DEFAULT",
+		CertifiersIdentifier:         []byte{0, 1, 2, 3, 4},
+	}
+	bytes, err := proto.Marshal(ou)
+	assert.NoError(t, err)
+
+	principal := &msp.MSPPrincipal{
+		PrincipalClassification: msp.MSPPrincipal_ORGANIZATION_UNIT,
+		Principal:               bytes,
+	}
+
+	err = id.SatisfiesPrincipal(principal)
+	assert.Error(t, err)
+}
+
 func TestPolicyPrincipalBogusType(t *testing.T) {
 	id, err := localMsp.GetDefaultSigningIdentity()
 	assert.NoError(t, err)
@@ -23,6 +23,7 @@ import (
 	"github.com/hyperledger/fabric/bccsp"
 	"github.com/hyperledger/fabric/bccsp/factory"
 	"github.com/hyperledger/fabric/bccsp/signer"
+	"github.com/hyperledger/fabric/common/crypto/fips"
 	m "github.com/hyperledger/fabric/protos/msp"
 )
 
@@ -290,6 +291,13 @@ func (msp *bccspmsp) Setup(conf1 *m.MSPConfig) error {
 	msp.name = conf.Name
 	mspLogger.Debugf("Setting up MSP instance %s", msp.name)
 
+	// in FIPS mode, reject the MSPs using algorithms which are not approved
+	if fips.Enabled() {
+		if err := fips.CheckFabricMSPConfig(conf); err != nil {
+			return fmt.Errorf("MSP %s is not FIPS compliant: %s", msp.name, err)
+		}
+	}
+
 	// setup crypto config
 	if err := msp.setupCrypto(conf); err != nil {
 		return err
@@ -539,10 +547,20 @@ func (msp *bccspmsp) SatisfiesPrincipal(id Identity, principal *m.MSPPrincipal)
 			return err
 		}
 
-		// now we check whether any of this identity's OUs match the requested one
+		// now we check whether any of this identity's OUs match the requested one.
+		// A principal without certifiers identifier matches the OU if it was
+		// certified by any of the CAs this MSP recognizes for it
 		for _, ou := range id.GetOrganizationalUnits() {
-			if ou.OrganizationalUnitIdentifier == OU.OrganizationalUnitIdentifier &&
-				bytes.Equal(ou.CertifiersIdentifier, OU.CertifiersIdentifier) {
+			if ou.OrganizationalUnitIdentifier != OU.OrganizationalUnitIdentifier {
+				continue
+			}
+			if len(OU.CertifiersIdentifier) == 0 {
+				for _, certifiersIdentifier := range msp.ouIdentifiers[OU.OrganizationalUnitIdentifier] {
+					if bytes.Equal(ou.CertifiersIdentifier, certifiersIdentifier) {
+						return nil
+					}
+				}
+			} else if bytes.Equal(ou.CertifiersIdentifier, OU.CertifiersIdentifier) {
 				return nil
 			}
 		}
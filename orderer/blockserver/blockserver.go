@@ -0,0 +1,265 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package blockserver serves the blocks of the ledgers of the orderer over HTTP as immutable objects, so that
+// they may be mirrored to an object storage by the organizations allowed to read them, and the peers catching up
+// on a long chain fetch them from there rather than through the Deliver service of a single orderer.
+//
+// Once written, a block never changes, hence the blocks and the complete segments of consecutive blocks are
+// served with a strong ETag and cached forever by the client, but never by a shared cache, as the blocks are only
+// readable by the holders of a token bound to their channel. They support the HTTP range requests, so that an
+// interrupted download is resumed where it stopped. A segment is the concatenation of the blocks it holds, each
+// marshaled block being preceded by its length as a 4 bytes big endian integer.
+package blockserver
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/subtle"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/gorilla/mux"
+	"github.com/hyperledger/fabric/orderer/ledger"
+	cb "github.com/hyperledger/fabric/protos/common"
+	logging "github.com/op/go-logging"
+)
+
+var logger = logging.MustGetLogger("orderer/blockserver")
+
+// immutable is the Cache-Control of the blocks and of the complete segments. They are private, so that a shared
+// cache does not serve them to clients without a token for their channel
+const immutable = "private, max-age=31536000, immutable"
+
+// ChainReaders looks up the ledgers of the chains served
+type ChainReaders interface {
+	// Reader returns the ledger of the chain with the given ID, and false if the chain does not exist
+	Reader(chainID string) (ledger.Reader, bool)
+}
+
+// Info describes the blocks of a chain available from the server
+type Info struct {
+	// Height is the number of blocks of the chain
+	Height uint64 `json:"height"`
+	// SegmentSize is the number of blocks of the segments
+	SegmentSize uint64 `json:"segment_size"`
+	// Segments is the number of complete segments
+	Segments uint64 `json:"segments"`
+}
+
+// Token authorizes its bearer to read the blocks of some channels
+type Token struct {
+	// Value is the secret presented in the Authorization header
+	Value string
+	// Channels are the IDs of the channels whose blocks the bearer may read
+	Channels []string
+}
+
+type token struct {
+	value    []byte
+	channels map[string]bool
+}
+
+type server struct {
+	readers     ChainReaders
+	segmentSize uint64
+	tokens      []token
+}
+
+// NewHandler creates the http.Handler of the block server, which only answers the requests bearing in their
+// Authorization header one of the tokens bound to the channel requested. It serves
+//
+//	GET /{channel}/info              the JSON Info of the channel, which is never cached
+//	GET /{channel}/blocks/{number}   the marshaled block
+//	GET /{channel}/segments/{index}  the segment of the blocks index*segmentSize to (index+1)*segmentSize-1,
+//	                                 once all of them are written
+func NewHandler(readers ChainReaders, segmentSize uint64, tokens []Token) http.Handler {
+	s := &server{readers: readers, segmentSize: segmentSize}
+	for _, t := range tokens {
+		bound := token{value: []byte(t.Value), channels: make(map[string]bool)}
+		for _, channel := range t.Channels {
+			bound.channels[channel] = true
+		}
+		s.tokens = append(s.tokens, bound)
+	}
+
+	router := mux.NewRouter()
+	router.
+		HandleFunc("/{channel}/info", s.authorize(s.info)).
+		Methods("GET", "HEAD")
+	router.
+		HandleFunc("/{channel}/blocks/{number:[0-9]+}", s.authorize(s.block)).
+		Methods("GET", "HEAD")
+	router.
+		HandleFunc("/{channel}/segments/{index:[0-9]+}", s.authorize(s.segment)).
+		Methods("GET", "HEAD")
+	return router
+}
+
+// LoadTokens reads the tokens of the clients of the block server from a file holding one token per line, followed
+// by the IDs of the channels it is bound to, separated by spaces. Empty lines and lines starting with # are ignored
+func LoadTokens(path string) ([]Token, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var tokens []Token
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("a token of %s is bound to no channel", path)
+		}
+		tokens = append(tokens, Token{Value: fields[0], Channels: fields[1:]})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("no token in %s", path)
+	}
+	return tokens, nil
+}
+
+// authorize only calls next for the requests bearing a token bound to the channel requested. The requests without
+// a valid token and those for another channel are rejected alike, so that the tokens do not reveal which channels
+// exist
+func (s *server) authorize(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		channel := mux.Vars(r)["channel"]
+		auth := r.Header.Get("Authorization")
+		if strings.HasPrefix(auth, "Bearer ") {
+			presented := []byte(strings.TrimPrefix(auth, "Bearer "))
+			for _, token := range s.tokens {
+				if subtle.ConstantTimeCompare(presented, token.value) == 1 && token.channels[channel] {
+					next(w, r)
+					return
+				}
+			}
+		}
+		logger.Warningf("Rejecting unauthorized request from %s for %s", r.RemoteAddr, r.URL.Path)
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	}
+}
+
+func (s *server) reader(w http.ResponseWriter, r *http.Request) (ledger.Reader, bool) {
+	channel := mux.Vars(r)["channel"]
+	rl, ok := s.readers.Reader(channel)
+	if !ok {
+		w.Header().Set("Cache-Control", "no-store")
+		http.Error(w, fmt.Sprintf("channel %s not found", channel), http.StatusNotFound)
+	}
+	return rl, ok
+}
+
+func (s *server) info(w http.ResponseWriter, r *http.Request) {
+	rl, ok := s.reader(w, r)
+	if !ok {
+		return
+	}
+	height := rl.Height()
+	info, err := json.Marshal(&Info{Height: height, SegmentSize: s.segmentSize, Segments: height / s.segmentSize})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(info)
+}
+
+func (s *server) block(w http.ResponseWriter, r *http.Request) {
+	rl, ok := s.reader(w, r)
+	if !ok {
+		return
+	}
+	number, err := strconv.ParseUint(mux.Vars(r)["number"], 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	block, ok := readBlock(w, rl, number)
+	if !ok {
+		return
+	}
+	blockBytes, err := proto.Marshal(block)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	serveImmutable(w, r, block, blockBytes)
+}
+
+func (s *server) segment(w http.ResponseWriter, r *http.Request) {
+	rl, ok := s.reader(w, r)
+	if !ok {
+		return
+	}
+	index, err := strconv.ParseUint(mux.Vars(r)["index"], 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	start := index * s.segmentSize
+	if start/s.segmentSize != index || start+s.segmentSize > rl.Height() {
+		w.Header().Set("Cache-Control", "no-store")
+		http.Error(w, fmt.Sprintf("segment %d is not complete", index), http.StatusNotFound)
+		return
+	}
+
+	var buf bytes.Buffer
+	var last *cb.Block
+	for number := start; number < start+s.segmentSize; number++ {
+		block, ok := readBlock(w, rl, number)
+		if !ok {
+			return
+		}
+		blockBytes, err := proto.Marshal(block)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		binary.Write(&buf, binary.BigEndian, uint32(len(blockBytes)))
+		buf.Write(blockBytes)
+		last = block
+	}
+	serveImmutable(w, r, last, buf.Bytes())
+}
+
+func readBlock(w http.ResponseWriter, rl ledger.Reader, number uint64) (*cb.Block, bool) {
+	block := ledger.GetBlock(rl, number)
+	if block == nil || block.Header == nil {
+		w.Header().Set("Cache-Control", "no-store")
+		http.Error(w, fmt.Sprintf("block %d not found", number), http.StatusNotFound)
+		return nil, false
+	}
+	return block, true
+}
+
+// serveImmutable serves content, whose ETag is derived from the hash of the header of its last block, as chained
+// blocks commit to all the blocks before them
+func serveImmutable(w http.ResponseWriter, r *http.Request, last *cb.Block, content []byte) {
+	w.Header().Set("Cache-Control", immutable)
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("ETag", fmt.Sprintf("\"%d-%s\"", len(content), hex.EncodeToString(last.Header.Hash())))
+	http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(content))
+}
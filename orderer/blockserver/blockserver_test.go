@@ -0,0 +1,194 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blockserver
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/orderer/ledger"
+	ramledger "github.com/hyperledger/fabric/orderer/ledger/ram"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockReaders map[string]ledger.Reader
+
+func (mr mockReaders) Reader(chainID string) (ledger.Reader, bool) {
+	rl, ok := mr[chainID]
+	return rl, ok
+}
+
+// newLedger returns a ledger of height blocks, each holding a transaction
+func newLedger(t *testing.T, height int) ledger.ReadWriter {
+	rl, err := ramledger.New(height + 1).GetOrCreate("mychannel")
+	require.NoError(t, err)
+	for i := 0; i < height; i++ {
+		block := ledger.CreateNextBlock(rl, []*cb.Envelope{{Payload: []byte{byte(i)}}})
+		require.NoError(t, rl.Append(block))
+	}
+	return rl
+}
+
+func startServer(t *testing.T, height int) (*httptest.Server, ledger.ReadWriter) {
+	rl := newLedger(t, height)
+	server := httptest.NewServer(NewHandler(mockReaders{"mychannel": rl}, 2, []Token{
+		{Value: "secret", Channels: []string{"mychannel", "otherchannel"}},
+		{Value: "other", Channels: []string{"otherchannel"}},
+	}))
+	return server, rl
+}
+
+func get(t *testing.T, url, token string, header http.Header) *http.Response {
+	req, err := http.NewRequest("GET", url, nil)
+	require.NoError(t, err)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	return resp
+}
+
+func TestAuthentication(t *testing.T) {
+	server, _ := startServer(t, 1)
+	defer server.Close()
+
+	for _, token := range []string{"", "wrong", "secre", "other"} {
+		resp := get(t, server.URL+"/mychannel/info", token, nil)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode, "Expected token %q to be rejected", token)
+		assert.Equal(t, "no-store", resp.Header.Get("Cache-Control"))
+	}
+	resp := get(t, server.URL+"/mychannel/info", "secret", nil)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// A token bound to a channel which does not exist does not reveal it
+	resp = get(t, server.URL+"/unknownchannel/info", "secret", nil)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestBlocks(t *testing.T) {
+	server, rl := startServer(t, 3)
+	defer server.Close()
+
+	resp := get(t, server.URL+"/mychannel/blocks/1", "secret", nil)
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, immutable, resp.Header.Get("Cache-Control"))
+	block := &cb.Block{}
+	require.NoError(t, proto.Unmarshal(body, block))
+	assert.True(t, proto.Equal(ledger.GetBlock(rl, 1), block))
+
+	// The block is resumed from a given offset
+	resp = get(t, server.URL+"/mychannel/blocks/1", "secret", http.Header{"Range": {"bytes=10-"}})
+	partial, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusPartialContent, resp.StatusCode)
+	assert.Equal(t, body[10:], partial)
+
+	// The block is not modified since cached
+	resp = get(t, server.URL+"/mychannel/blocks/1", "secret", http.Header{"If-None-Match": {resp.Header.Get("ETag")}})
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+
+	for _, path := range []string{"/mychannel/blocks/3", "/otherchannel/blocks/0"} {
+		resp = get(t, server.URL+path, "secret", nil)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode, path)
+		assert.Equal(t, "no-store", resp.Header.Get("Cache-Control"), "Expected the missing blocks not to be cached")
+	}
+}
+
+func TestClient(t *testing.T) {
+	server, rl := startServer(t, 5)
+	defer server.Close()
+	client := &Client{URL: server.URL, Token: "secret"}
+
+	info, err := client.Info("mychannel")
+	require.NoError(t, err)
+	assert.Equal(t, &Info{Height: 5, SegmentSize: 2, Segments: 2}, info)
+
+	for index := uint64(0); index < info.Segments; index++ {
+		blocks, err := client.Segment("mychannel", index, info.SegmentSize)
+		require.NoError(t, err)
+		require.Len(t, blocks, 2)
+		for i, block := range blocks {
+			assert.True(t, proto.Equal(ledger.GetBlock(rl, index*2+uint64(i)), block))
+		}
+	}
+	_, err = client.Segment("mychannel", 2, info.SegmentSize)
+	assert.Error(t, err, "Expected an error for an incomplete segment")
+
+	block, err := client.Block("mychannel", 4)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4), block.Header.Number)
+	_, err = client.Block("mychannel", 5)
+	assert.Error(t, err)
+
+	_, err = (&Client{URL: server.URL, Token: "wrong"}).Info("mychannel")
+	assert.Error(t, err)
+}
+
+func TestCheckBlock(t *testing.T) {
+	rl := newLedger(t, 2)
+	first, second := ledger.GetBlock(rl, 0), ledger.GetBlock(rl, 1)
+	assert.NoError(t, checkBlock(second, 1, first))
+	assert.Error(t, checkBlock(second, 2, first), "Expected an error for an unexpected number")
+	assert.Error(t, checkBlock(first, 0, second), "Expected an error for blocks which are not chained")
+
+	tampered := proto.Clone(second).(*cb.Block)
+	tampered.Data.Data[0] = []byte("tampered")
+	assert.Error(t, checkBlock(tampered, 1, first), "Expected an error for data not matching the header")
+}
+
+func TestReadSegment(t *testing.T) {
+	_, err := ReadSegment(bytes.NewReader([]byte{0, 0, 0, 9, 1}))
+	assert.Error(t, err, "Expected an error for a truncated segment")
+	blocks, err := ReadSegment(bytes.NewReader(nil))
+	assert.NoError(t, err)
+	assert.Empty(t, blocks)
+}
+
+func TestLoadTokens(t *testing.T) {
+	f, err := ioutil.TempFile("", "tokens")
+	require.NoError(t, err)
+	defer os.Remove(f.Name())
+	_, err = f.WriteString("# mirror\nsecret mychannel\n\n  other  mychannel   otherchannel \n")
+	require.NoError(t, err)
+	f.Close()
+
+	tokens, err := LoadTokens(f.Name())
+	require.NoError(t, err)
+	assert.Equal(t, []Token{
+		{Value: "secret", Channels: []string{"mychannel"}},
+		{Value: "other", Channels: []string{"mychannel", "otherchannel"}},
+	}, tokens)
+
+	require.NoError(t, ioutil.WriteFile(f.Name(), []byte("# no token\n"), 0600))
+	_, err = LoadTokens(f.Name())
+	assert.Error(t, err)
+	require.NoError(t, ioutil.WriteFile(f.Name(), []byte("secret\n"), 0600))
+	_, err = LoadTokens(f.Name())
+	assert.EqualError(t, err, "a token of "+f.Name()+" is bound to no channel")
+	_, err = LoadTokens(f.Name() + ".missing")
+	assert.Error(t, err)
+}
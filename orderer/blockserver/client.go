@@ -0,0 +1,150 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blockserver
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric/protos/common"
+)
+
+// Client fetches the blocks of a chain from a block server, or from a mirror of it. The blocks fetched are
+// checked to be chained to each other, but as the mirror is not trusted, their signatures must still be
+// validated, as for the blocks received from the Deliver service
+type Client struct {
+	// URL is the base URL of the block server
+	URL string
+	// Token is the token bearing the requests, which must be bound to the chains fetched
+	Token string
+	// HTTPClient sends the requests, http.DefaultClient if nil
+	HTTPClient *http.Client
+}
+
+// Info returns the Info of a chain
+func (c *Client) Info(chainID string) (*Info, error) {
+	body, err := c.get(chainID, "info")
+	if err != nil {
+		return nil, err
+	}
+	info := &Info{}
+	if err := json.Unmarshal(body, info); err != nil {
+		return nil, fmt.Errorf("malformed info of channel %s: %s", chainID, err)
+	}
+	return info, nil
+}
+
+// Block returns a block of a chain
+func (c *Client) Block(chainID string, number uint64) (*cb.Block, error) {
+	body, err := c.get(chainID, fmt.Sprintf("blocks/%d", number))
+	if err != nil {
+		return nil, err
+	}
+	block := &cb.Block{}
+	if err := proto.Unmarshal(body, block); err != nil {
+		return nil, fmt.Errorf("malformed block %d of channel %s: %s", number, chainID, err)
+	}
+	if err := checkBlock(block, number, nil); err != nil {
+		return nil, err
+	}
+	return block, nil
+}
+
+// Segment returns the blocks of a segment of a chain, whose size is the SegmentSize of the Info of the chain
+func (c *Client) Segment(chainID string, index, segmentSize uint64) ([]*cb.Block, error) {
+	body, err := c.get(chainID, fmt.Sprintf("segments/%d", index))
+	if err != nil {
+		return nil, err
+	}
+	blocks, err := ReadSegment(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("malformed segment %d of channel %s: %s", index, chainID, err)
+	}
+	if uint64(len(blocks)) != segmentSize {
+		return nil, fmt.Errorf("segment %d of channel %s holds %d blocks instead of %d", index, chainID, len(blocks), segmentSize)
+	}
+	var previous *cb.Block
+	for i, block := range blocks {
+		if err := checkBlock(block, index*segmentSize+uint64(i), previous); err != nil {
+			return nil, err
+		}
+		previous = block
+	}
+	return blocks, nil
+}
+
+// ReadSegment reads the blocks of a segment
+func ReadSegment(r io.Reader) ([]*cb.Block, error) {
+	var blocks []*cb.Block
+	for {
+		var size uint32
+		if err := binary.Read(r, binary.BigEndian, &size); err == io.EOF {
+			return blocks, nil
+		} else if err != nil {
+			return nil, err
+		}
+		blockBytes := make([]byte, size)
+		if _, err := io.ReadFull(r, blockBytes); err != nil {
+			return nil, err
+		}
+		block := &cb.Block{}
+		if err := proto.Unmarshal(blockBytes, block); err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, block)
+	}
+}
+
+// checkBlock checks that a block has the expected number, that its data matches its header, and that it is
+// chained to the previous block if any
+func checkBlock(block *cb.Block, number uint64, previous *cb.Block) error {
+	if block.Header == nil || block.Data == nil {
+		return fmt.Errorf("block %d is missing its header or data", number)
+	}
+	if block.Header.Number != number {
+		return fmt.Errorf("expected block %d, got block %d", number, block.Header.Number)
+	}
+	if !bytes.Equal(block.Data.Hash(), block.Header.DataHash) {
+		return fmt.Errorf("the data of block %d does not match its header", number)
+	}
+	if previous != nil && !bytes.Equal(previous.Header.Hash(), block.Header.PreviousHash) {
+		return fmt.Errorf("block %d is not chained to block %d", number, previous.Header.Number)
+	}
+	return nil
+}
+
+func (c *Client) get(chainID, path string) ([]byte, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/%s/%s", c.URL, url.PathEscape(chainID), path), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s failed with status %s: %s", req.URL.Path, resp.Status, bytes.TrimSpace(body))
+	}
+	return body, nil
+}
@@ -0,0 +1,404 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/configtx"
+	genesisconfig "github.com/hyperledger/fabric/common/configtx/tool/localconfig"
+	"github.com/hyperledger/fabric/common/configtx/tool/provisional"
+	mspmgmt "github.com/hyperledger/fabric/msp/mgmt"
+	"github.com/hyperledger/fabric/orderer/kafka"
+	"github.com/hyperledger/fabric/orderer/localconfig"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+)
+
+// Statuses of the checks of the configuration
+const (
+	checkOK      = "OK"
+	checkWarning = "WARNING"
+	checkFailed  = "FAILED"
+)
+
+// certificateExpiryWarning is how long before the expiry of a certificate it is reported
+const certificateExpiryWarning = 30 * 24 * time.Hour
+
+// checkResult is the outcome of a check of the configuration
+type checkResult struct {
+	Check  string `json:"check"`
+	Status string `json:"status"`
+	Detail string `json:"detail"`
+}
+
+// configReport gathers the outcomes of the checks of the configuration, in the order they ran
+type configReport struct {
+	Results []checkResult `json:"results"`
+	Failed  bool          `json:"failed"`
+}
+
+func (r *configReport) add(check, status, format string, args ...interface{}) {
+	r.Results = append(r.Results, checkResult{Check: check, Status: status, Detail: fmt.Sprintf(format, args...)})
+	if status == checkFailed {
+		r.Failed = true
+	}
+}
+
+func (r *configReport) ok(check, format string, args ...interface{}) {
+	r.add(check, checkOK, format, args...)
+}
+
+func (r *configReport) warn(check, format string, args ...interface{}) {
+	r.add(check, checkWarning, format, args...)
+}
+
+func (r *configReport) fail(check, format string, args ...interface{}) {
+	r.add(check, checkFailed, format, args...)
+}
+
+// write prints the report, as JSON or as a table
+func (r *configReport) write(w io.Writer, asJSON bool) error {
+	if asJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(r)
+	}
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	for _, result := range r.Results {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", result.Status, result.Check, result.Detail)
+	}
+	if r.Failed {
+		fmt.Fprintln(tw, "\nThe orderer is not expected to start with this configuration")
+	}
+	return tw.Flush()
+}
+
+// checkConfiguration loads the configuration of the orderer and checks it as far as possible without starting the
+// orderer, so that the misconfigurations are reported at once rather than as panics deep in the startup
+func checkConfiguration() *configReport {
+	report := &configReport{}
+	conf, err := loadConfigForCheck()
+	if err != nil {
+		report.fail("config", "Cannot load the configuration: %s", err)
+		return report
+	}
+	report.ok("config", "Loaded the configuration, with ledger type %s and genesis method %s", conf.General.LedgerType, conf.General.GenesisMethod)
+
+	checkListenAddresses(report, conf)
+	checkDirectories(report, conf)
+	checkServerTLS(report, conf)
+	kafkaTLS := checkKafkaTLS(report, conf)
+	mspID := checkLocalMSP(report, conf)
+	genesis := checkGenesisBlock(report, conf)
+	if genesis != nil {
+		checkGenesisConfig(report, conf, genesis, mspID, kafkaTLS)
+	}
+	return report
+}
+
+// loadConfigForCheck loads the configuration, recovering from the panics by which the errors are reported
+func loadConfigForCheck() (conf *config.TopLevel, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	return config.Load(), nil
+}
+
+// checkListenAddresses checks that the addresses the orderer listens on are free
+func checkListenAddresses(report *configReport, conf *config.TopLevel) {
+	addresses := map[string]string{"General.ListenAddress": fmt.Sprintf("%s:%d", conf.General.ListenAddress, conf.General.ListenPort)}
+	if conf.General.Profile.Enabled {
+		addresses["General.Profile.Address"] = conf.General.Profile.Address
+	}
+	if conf.General.Gateway.Enabled {
+		addresses["General.Gateway.Address"] = conf.General.Gateway.Address
+	}
+	if conf.General.BlockServer.Enabled {
+		addresses["General.BlockServer.Address"] = conf.General.BlockServer.Address
+	}
+	var keys []string
+	for key := range addresses {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		l, err := net.Listen("tcp", addresses[key])
+		if err != nil {
+			report.fail("listen", "Cannot listen on %s (%s): %s", addresses[key], key, err)
+			continue
+		}
+		l.Close()
+		report.ok("listen", "Can listen on %s (%s)", addresses[key], key)
+	}
+}
+
+// checkDirectories checks that the ledger directory can be written, or created, and the MSP directory read
+func checkDirectories(report *configReport, conf *config.TopLevel) {
+	if conf.General.LedgerType == "file" || conf.General.LedgerType == "json" {
+		if conf.FileLedger.Location == "" {
+			report.warn("directories", "FileLedger.Location is not set, the ledger is written to a temporary directory and lost on restart")
+		} else if err := checkWritableDir(conf.FileLedger.Location); err != nil {
+			report.fail("directories", "Ledger directory %s: %s", conf.FileLedger.Location, err)
+		} else {
+			report.ok("directories", "Ledger directory %s is writable", conf.FileLedger.Location)
+		}
+	} else {
+		report.warn("directories", "The %s ledger is not persisted across restarts", conf.General.LedgerType)
+	}
+
+	if _, err := ioutil.ReadDir(conf.General.LocalMSPDir); err != nil {
+		report.fail("directories", "MSP directory %s: %s", conf.General.LocalMSPDir, err)
+	} else {
+		report.ok("directories", "MSP directory %s is readable", conf.General.LocalMSPDir)
+	}
+}
+
+// checkWritableDir checks that a file can be created in dir, or, if dir does not exist, in its nearest existing
+// parent, in which the orderer creates it
+func checkWritableDir(dir string) error {
+	for {
+		info, err := os.Stat(dir)
+		if os.IsNotExist(err) && filepath.Dir(dir) != dir {
+			dir = filepath.Dir(dir)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("%s is not a directory", dir)
+		}
+		break
+	}
+	f, err := ioutil.TempFile(dir, ".check-config")
+	if err != nil {
+		return err
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
+
+// checkServerTLS checks the certificate, key and root CAs of the TLS server of the orderer
+func checkServerTLS(report *configReport, conf *config.TopLevel) {
+	tlsConf := conf.General.TLS
+	if !tlsConf.Enabled {
+		report.warn("tls", "TLS is disabled")
+		return
+	}
+	certificate, err := ioutil.ReadFile(tlsConf.Certificate)
+	if err != nil {
+		report.fail("tls", "Cannot read General.TLS.Certificate: %s", err)
+		return
+	}
+	key, err := ioutil.ReadFile(tlsConf.PrivateKey)
+	if err != nil {
+		report.fail("tls", "Cannot read General.TLS.PrivateKey: %s", err)
+		return
+	}
+	if checkKeyPair(report, "tls", "General.TLS", certificate, key) {
+		checkCertificateValidity(report, "tls", "General.TLS.Certificate", certificate)
+	}
+	checkRootCAFiles(report, "General.TLS.RootCAs", tlsConf.RootCAs)
+	if tlsConf.ClientAuthEnabled {
+		if len(tlsConf.ClientRootCAs) == 0 {
+			report.fail("tls", "General.TLS.ClientRootCAs must be set if General.TLS.ClientAuthEnabled is set")
+		}
+		checkRootCAFiles(report, "General.TLS.ClientRootCAs", tlsConf.ClientRootCAs)
+	}
+}
+
+func checkRootCAFiles(report *configReport, key string, files []string) {
+	for _, file := range files {
+		root, err := ioutil.ReadFile(file)
+		if err != nil {
+			report.fail("tls", "Cannot read %s file %s: %s", key, file, err)
+			continue
+		}
+		if !x509.NewCertPool().AppendCertsFromPEM(root) {
+			report.fail("tls", "%s file %s holds no PEM encoded certificate", key, file)
+			continue
+		}
+		checkCertificateValidity(report, "tls", key+" file "+file, root)
+	}
+}
+
+// checkKafkaTLS checks the certificate, key and root CAs with which the orderer connects to the Kafka brokers, and
+// returns whether they are usable
+func checkKafkaTLS(report *configReport, conf *config.TopLevel) bool {
+	tlsConf := conf.Kafka.TLS
+	if !tlsConf.Enabled {
+		return true
+	}
+	valid := checkKeyPair(report, "kafka tls", "Kafka.TLS", []byte(tlsConf.Certificate), []byte(tlsConf.PrivateKey))
+	if valid {
+		checkCertificateValidity(report, "kafka tls", "Kafka.TLS.Certificate", []byte(tlsConf.Certificate))
+	}
+	for i, root := range tlsConf.RootCAs {
+		if !x509.NewCertPool().AppendCertsFromPEM([]byte(root)) {
+			report.fail("kafka tls", "Kafka.TLS.RootCAs entry %d holds no PEM encoded certificate", i)
+			valid = false
+			continue
+		}
+		checkCertificateValidity(report, "kafka tls", fmt.Sprintf("Kafka.TLS.RootCAs entry %d", i), []byte(root))
+	}
+	return valid
+}
+
+func checkKeyPair(report *configReport, check, key string, certificate, privateKey []byte) bool {
+	if _, err := tls.X509KeyPair(certificate, privateKey); err != nil {
+		report.fail(check, "%s.Certificate and %s.PrivateKey do not make a key pair: %s", key, key, err)
+		return false
+	}
+	return true
+}
+
+// checkCertificateValidity reports the first certificate of the PEM data if it is not yet valid, expired or about
+// to expire
+func checkCertificateValidity(report *configReport, check, name string, pemData []byte) {
+	block, _ := pem.Decode(pemData)
+	if block == nil {
+		report.fail(check, "%s holds no PEM encoded certificate", name)
+		return
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		report.fail(check, "%s cannot be parsed: %s", name, err)
+		return
+	}
+	now := time.Now()
+	switch {
+	case now.Before(cert.NotBefore):
+		report.fail(check, "%s is not valid before %s", name, cert.NotBefore)
+	case now.After(cert.NotAfter):
+		report.fail(check, "%s expired on %s", name, cert.NotAfter)
+	case now.Add(certificateExpiryWarning).After(cert.NotAfter):
+		report.warn(check, "%s expires on %s", name, cert.NotAfter)
+	default:
+		report.ok(check, "%s is valid until %s", name, cert.NotAfter)
+	}
+}
+
+// checkLocalMSP loads the local MSP and its signing identity, and returns the ID of the MSP, empty if it cannot be
+// loaded
+func checkLocalMSP(report *configReport, conf *config.TopLevel) string {
+	if err := mspmgmt.LoadLocalMsp(conf.General.LocalMSPDir, conf.General.BCCSP, conf.General.LocalMSPID); err != nil {
+		report.fail("msp", "Cannot load the local MSP from %s: %s", conf.General.LocalMSPDir, err)
+		return ""
+	}
+	signer, err := mspmgmt.GetLocalMSP().GetDefaultSigningIdentity()
+	if err != nil {
+		report.fail("msp", "The local MSP has no signing identity: %s", err)
+		return ""
+	}
+	if expiresAt := signer.ExpiresAt(); !expiresAt.IsZero() && time.Now().After(expiresAt) {
+		report.fail("msp", "The signing identity of the local MSP %s expired on %s", conf.General.LocalMSPID, expiresAt)
+	} else if !expiresAt.IsZero() && time.Now().Add(certificateExpiryWarning).After(expiresAt) {
+		report.warn("msp", "The signing identity of the local MSP %s expires on %s", conf.General.LocalMSPID, expiresAt)
+	} else {
+		report.ok("msp", "Loaded the local MSP %s and its signing identity", conf.General.LocalMSPID)
+	}
+	return conf.General.LocalMSPID
+}
+
+// checkGenesisBlock loads the genesis block the orderer bootstraps the system channel from, recovering from the
+// panics by which the errors are reported
+func checkGenesisBlock(report *configReport, conf *config.TopLevel) (genesisBlock *cb.Block) {
+	defer func() {
+		if r := recover(); r != nil {
+			report.fail("genesis", "Cannot load the genesis block with method %s: %v", conf.General.GenesisMethod, r)
+			genesisBlock = nil
+		}
+	}()
+
+	switch conf.General.GenesisMethod {
+	case "provisional":
+		genesisBlock = provisional.New(genesisconfig.Load(conf.General.GenesisProfile)).GenesisBlock()
+	case "file":
+		data, err := ioutil.ReadFile(conf.General.GenesisFile)
+		if err != nil {
+			report.fail("genesis", "Cannot read General.GenesisFile: %s", err)
+			return nil
+		}
+		genesisBlock = &cb.Block{}
+		if err := proto.Unmarshal(data, genesisBlock); err != nil {
+			report.fail("genesis", "Cannot unmarshal General.GenesisFile %s: %s", conf.General.GenesisFile, err)
+			return nil
+		}
+	default:
+		report.fail("genesis", "Unknown General.GenesisMethod %s", conf.General.GenesisMethod)
+		return nil
+	}
+	return genesisBlock
+}
+
+// checkGenesisConfig checks the config of the system channel in the genesis block, that the local MSP is one of its
+// orderer organizations and that the Kafka brokers it lists can be reached
+func checkGenesisConfig(report *configReport, conf *config.TopLevel, genesisBlock *cb.Block, mspID string, kafkaTLS bool) {
+	env, err := utils.ExtractEnvelope(genesisBlock, 0)
+	if err != nil {
+		report.fail("genesis", "The genesis block holds no config transaction: %s", err)
+		return
+	}
+	configManager, err := configtx.NewManagerImpl(env, configtx.NewInitializer(), nil)
+	if err != nil {
+		report.fail("genesis", "The config of the genesis block is not valid: %s", err)
+		return
+	}
+	ordererConfig, ok := configManager.OrdererConfig()
+	if !ok {
+		report.fail("genesis", "The config of the genesis block has no orderer section")
+		return
+	}
+	report.ok("genesis", "System channel %s with orderer type %s, used if the ledger has no channel yet",
+		configManager.ChainID(), ordererConfig.ConsensusType())
+
+	if mspID != "" {
+		var orgMSPIDs []string
+		for _, org := range ordererConfig.Organizations() {
+			orgMSPIDs = append(orgMSPIDs, org.MSPID())
+		}
+		sort.Strings(orgMSPIDs)
+		if i := sort.SearchStrings(orgMSPIDs, mspID); i == len(orgMSPIDs) || orgMSPIDs[i] != mspID {
+			report.warn("genesis", "The local MSP %s is not one of the orderer organizations %s, the peers may not validate the blocks it signs",
+				mspID, strings.Join(orgMSPIDs, ", "))
+		}
+	}
+
+	if ordererConfig.ConsensusType() != "kafka" {
+		return
+	}
+	if !kafkaTLS {
+		report.fail("kafka", "The Kafka brokers are not checked, as the Kafka TLS settings are not valid")
+		return
+	}
+	brokers := ordererConfig.KafkaBrokers()
+	errs := kafka.CheckBrokers(conf.Kafka, brokers)
+	for _, broker := range brokers {
+		if err, ok := errs[broker]; ok {
+			report.fail("kafka", "Cannot reach broker %s: %s", broker, err)
+		} else {
+			report.ok("kafka", "Reached broker %s", broker)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	genesisconfig "github.com/hyperledger/fabric/common/configtx/tool/localconfig"
+	"github.com/hyperledger/fabric/common/configtx/tool/provisional"
+	config "github.com/hyperledger/fabric/orderer/localconfig"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newCheckedKeyPair returns a PEM encoded self signed certificate, valid until notAfter, and its private key
+func newCheckedKeyPair(t *testing.T, notAfter time.Time) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "orderer"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func TestConfigReport(t *testing.T) {
+	report := &configReport{}
+	report.ok("foo", "all %s", "good")
+	report.warn("bar", "not so good")
+	assert.False(t, report.Failed)
+	report.fail("baz", "bad")
+	assert.True(t, report.Failed)
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, report.write(buf, false))
+	assert.Contains(t, buf.String(), "OK       foo  all good")
+	assert.Contains(t, buf.String(), "not expected to start")
+
+	buf.Reset()
+	require.NoError(t, report.write(buf, true))
+	decoded := &configReport{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), decoded))
+	assert.Equal(t, report, decoded)
+}
+
+func TestCheckWritableDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "check-config")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	assert.NoError(t, checkWritableDir(dir))
+	assert.NoError(t, checkWritableDir(filepath.Join(dir, "not", "created", "yet")), "Expected the parent to be checked")
+
+	file := filepath.Join(dir, "file")
+	require.NoError(t, ioutil.WriteFile(file, []byte("foo"), 0644))
+	assert.Error(t, checkWritableDir(file))
+	assert.Error(t, checkWritableDir(filepath.Join(file, "sub")))
+}
+
+func TestCheckListenAddresses(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	port := l.Addr().(*net.TCPAddr).Port
+
+	report := &configReport{}
+	checkListenAddresses(report, &config.TopLevel{General: config.General{
+		ListenAddress: "127.0.0.1",
+		ListenPort:    uint16(port),
+		Gateway:       config.Gateway{Enabled: true, Address: "127.0.0.1:0"},
+	}})
+	require.Len(t, report.Results, 2)
+	// The addresses are checked in the order of their keys
+	assert.Equal(t, checkOK, report.Results[0].Status)
+	assert.Equal(t, checkFailed, report.Results[1].Status, "Expected the listen address in use to be reported")
+}
+
+func TestCheckServerTLS(t *testing.T) {
+	dir, err := ioutil.TempDir("", "check-config")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	write := func(name string, data []byte) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, ioutil.WriteFile(path, data, 0600))
+		return path
+	}
+	cert, key := newCheckedKeyPair(t, time.Now().Add(365*24*time.Hour))
+	expiringCert, expiringKey := newCheckedKeyPair(t, time.Now().Add(24*time.Hour))
+	_, otherKey := newCheckedKeyPair(t, time.Now().Add(365*24*time.Hour))
+
+	testCases := []struct {
+		name        string
+		certificate string
+		privateKey  string
+		status      string
+	}{
+		{"Valid", write("cert.pem", cert), write("key.pem", key), checkOK},
+		{"Expiring", write("expiring.pem", expiringCert), write("expiring_key.pem", expiringKey), checkWarning},
+		{"Mismatch", write("cert.pem", cert), write("other_key.pem", otherKey), checkFailed},
+		{"Missing", filepath.Join(dir, "does_not_exist"), write("key.pem", key), checkFailed},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			report := &configReport{}
+			checkServerTLS(report, &config.TopLevel{General: config.General{TLS: config.TLS{
+				Enabled:     true,
+				Certificate: tc.certificate,
+				PrivateKey:  tc.privateKey,
+				RootCAs:     []string{tc.certificate},
+			}}})
+			require.NotEmpty(t, report.Results)
+			assert.Equal(t, tc.status, report.Results[0].Status, report.Results[0].Detail)
+		})
+	}
+
+	t.Run("ClientAuthWithoutRoots", func(t *testing.T) {
+		report := &configReport{}
+		checkServerTLS(report, &config.TopLevel{General: config.General{TLS: config.TLS{
+			Enabled:           true,
+			ClientAuthEnabled: true,
+			Certificate:       write("cert.pem", cert),
+			PrivateKey:        write("key.pem", key),
+		}}})
+		assert.True(t, report.Failed)
+	})
+
+	t.Run("KafkaRootCA", func(t *testing.T) {
+		report := &configReport{}
+		assert.False(t, checkKafkaTLS(report, &config.TopLevel{Kafka: config.Kafka{TLS: config.TLS{
+			Enabled:     true,
+			Certificate: string(cert),
+			PrivateKey:  string(key),
+			RootCAs:     []string{"not a certificate"},
+		}}}))
+		assert.True(t, report.Failed)
+	})
+}
+
+func TestCheckGenesisBlock(t *testing.T) {
+	report := &configReport{}
+	genesisBlock := checkGenesisBlock(report, &config.TopLevel{General: config.General{
+		GenesisMethod:  "provisional",
+		GenesisProfile: "SampleSingleMSPSolo",
+	}})
+	require.NotNil(t, genesisBlock)
+	assert.False(t, report.Failed)
+
+	file, err := ioutil.TempFile("", "genesisblock")
+	require.NoError(t, err)
+	defer os.Remove(file.Name())
+	_, err = file.Write(utils.MarshalOrPanic(genesisBlock))
+	require.NoError(t, err)
+	file.Close()
+	assert.NotNil(t, checkGenesisBlock(report, &config.TopLevel{General: config.General{
+		GenesisMethod: "file",
+		GenesisFile:   file.Name(),
+	}}))
+	assert.False(t, report.Failed)
+
+	for _, conf := range []config.General{
+		{GenesisMethod: "provisional", GenesisProfile: "does_not_exist"},
+		{GenesisMethod: "file", GenesisFile: "does_not_exist"},
+		{GenesisMethod: "invalid"},
+	} {
+		report := &configReport{}
+		assert.Nil(t, checkGenesisBlock(report, &config.TopLevel{General: conf}))
+		assert.True(t, report.Failed, "Expected genesis method %s to fail", conf.GenesisMethod)
+	}
+}
+
+func TestCheckGenesisConfig(t *testing.T) {
+	genesisBlock := provisional.New(genesisconfig.Load("SampleSingleMSPSolo")).GenesisBlock()
+
+	report := &configReport{}
+	checkGenesisConfig(report, &config.TopLevel{}, genesisBlock, "DEFAULT", true)
+	require.Len(t, report.Results, 1)
+	assert.Equal(t, checkOK, report.Results[0].Status)
+
+	report = &configReport{}
+	checkGenesisConfig(report, &config.TopLevel{}, genesisBlock, "OtherMSP", true)
+	require.Len(t, report.Results, 2)
+	assert.Equal(t, checkWarning, report.Results[1].Status, "Expected the local MSP to be reported as not an orderer organization")
+}
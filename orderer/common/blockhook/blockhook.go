@@ -0,0 +1,180 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package blockhook runs the hooks of the operators, such as push notifications, the mirroring of the blocks to an
+// external archive or the update of an external monitoring, once the blocks are written to the ledgers of the
+// orderer. The hooks run apart from the consenters, so that a slow or failing hook never delays the ordering.
+package blockhook
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/hyperledger/fabric/common/metrics"
+	localconfig "github.com/hyperledger/fabric/orderer/localconfig"
+	cb "github.com/hyperledger/fabric/protos/common"
+	logging "github.com/op/go-logging"
+	gometrics "github.com/rcrowley/go-metrics"
+)
+
+var logger = logging.MustGetLogger("orderer/common/blockhook")
+
+// Block describes a block written to the ledger of a chain
+type Block struct {
+	ChainID    string
+	Number     uint64
+	HeaderHash []byte
+	TxCount    int
+}
+
+// Hook is run for every block written to the ledgers of the orderer
+type Hook interface {
+	// BlockCommitted is invoked with the blocks of each chain in order. An error is logged and counted, and does
+	// not prevent the hook from being run for the next blocks
+	BlockCommitted(block *Block) error
+}
+
+// HookFunc adapts a function to a Hook
+type HookFunc func(block *Block) error
+
+// BlockCommitted invokes the function
+func (f HookFunc) BlockCommitted(block *Block) error {
+	return f(block)
+}
+
+var (
+	registryLock sync.Mutex
+	registry     = make(map[string]Hook)
+)
+
+// Register registers a hook by name, so that orderers built with an operator package registering its hooks in its
+// init function run them once the block hooks are enabled
+func Register(name string, hook Hook) {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	if _, ok := registry[name]; ok {
+		logger.Panicf("Block hook %s registered twice", name)
+	}
+	registry[name] = hook
+}
+
+// Dispatcher hands the blocks written to the hooks. Each hook has its own queue and goroutine, so that a slow hook
+// does not delay the others, and the blocks reaching a full queue are dropped and counted in the
+// orderer.block_hooks.<hook>.dropped metric
+type Dispatcher struct {
+	hooks []*queue
+}
+
+type queue struct {
+	name    string
+	hook    Hook
+	blocks  chan *Block
+	dropped gometrics.Counter
+	failed  gometrics.Counter
+}
+
+// New returns a dispatcher to the hooks registered and to the commands of the configuration, or nil if the block
+// hooks are not enabled
+func New(conf localconfig.BlockHooks) *Dispatcher {
+	if !conf.Enabled {
+		return nil
+	}
+	hooks := make(map[string]Hook)
+	registryLock.Lock()
+	for name, hook := range registry {
+		hooks[name] = hook
+	}
+	registryLock.Unlock()
+	for name, command := range conf.Commands {
+		if _, ok := hooks[name]; ok {
+			logger.Panicf("Block hook command %s has the name of a registered hook", name)
+		}
+		hooks[name] = CommandHook(command)
+	}
+	return NewDispatcher(hooks, conf.QueueSize)
+}
+
+// NewDispatcher returns a dispatcher to the given hooks, each with a queue of the given size
+func NewDispatcher(hooks map[string]Hook, queueSize int) *Dispatcher {
+	var names []string
+	for name := range hooks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	d := &Dispatcher{}
+	for _, name := range names {
+		q := &queue{
+			name:    name,
+			hook:    hooks[name],
+			blocks:  make(chan *Block, queueSize),
+			dropped: gometrics.GetOrRegisterCounter("orderer.block_hooks."+name+".dropped", metrics.Registry),
+			failed:  gometrics.GetOrRegisterCounter("orderer.block_hooks."+name+".failed", metrics.Registry),
+		}
+		go q.run()
+		d.hooks = append(d.hooks, q)
+		logger.Infof("Running block hook %s", name)
+	}
+	return d
+}
+
+// Notify queues the block written to the ledger of a chain for every hook, without blocking
+func (d *Dispatcher) Notify(chainID string, block *cb.Block) {
+	b := &Block{
+		ChainID:    chainID,
+		Number:     block.Header.Number,
+		HeaderHash: block.Header.Hash(),
+	}
+	if block.Data != nil {
+		b.TxCount = len(block.Data.Data)
+	}
+	for _, q := range d.hooks {
+		select {
+		case q.blocks <- b:
+		default:
+			logger.Warningf("[channel: %s] Queue of block hook %s full, dropping block %d", chainID, q.name, b.Number)
+			q.dropped.Inc(1)
+		}
+	}
+}
+
+// Stop stops the hooks once they have run for the blocks queued
+func (d *Dispatcher) Stop() {
+	for _, q := range d.hooks {
+		close(q.blocks)
+	}
+}
+
+func (q *queue) run() {
+	for block := range q.blocks {
+		if err := q.hook.BlockCommitted(block); err != nil {
+			logger.Warningf("[channel: %s] Block hook %s failed for block %d: %s", block.ChainID, q.name, block.Number, err)
+			q.failed.Inc(1)
+		}
+	}
+}
+
+// CommandHook returns a hook running a shell command for every block, with the CHANNEL, BLOCK_NUMBER, BLOCK_HASH
+// (hex encoded) and TX_COUNT environment variables describing the block
+func CommandHook(command string) Hook {
+	return HookFunc(func(block *Block) error {
+		cmd := exec.Command("sh", "-c", command)
+		cmd.Env = append(os.Environ(),
+			"CHANNEL="+block.ChainID,
+			"BLOCK_NUMBER="+strconv.FormatUint(block.Number, 10),
+			fmt.Sprintf("BLOCK_HASH=%x", block.HeaderHash),
+			"TX_COUNT="+strconv.Itoa(block.TxCount),
+		)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("%s: %s", err, out)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,127 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blockhook
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	localconfig "github.com/hyperledger/fabric/orderer/localconfig"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newBlock(number uint64, txs int) *cb.Block {
+	block := cb.NewBlock(number, []byte("previous"))
+	for i := 0; i < txs; i++ {
+		block.Data.Data = append(block.Data.Data, []byte(fmt.Sprintf("tx%d", i)))
+	}
+	return block
+}
+
+func TestDispatcher(t *testing.T) {
+	blocks := make(chan *Block, 10)
+	d := NewDispatcher(map[string]Hook{
+		"ok": HookFunc(func(block *Block) error {
+			blocks <- block
+			return nil
+		}),
+		"failing": HookFunc(func(block *Block) error {
+			return fmt.Errorf("failing hook")
+		}),
+	}, 10)
+	defer d.Stop()
+
+	for i := uint64(1); i <= 3; i++ {
+		block := newBlock(i, int(i))
+		d.Notify("foo", block)
+		select {
+		case hooked := <-blocks:
+			assert.Equal(t, &Block{ChainID: "foo", Number: i, HeaderHash: block.Header.Hash(), TxCount: int(i)}, hooked, "Should have run the hooks in order")
+		case <-time.After(time.Second):
+			t.Fatalf("Should have run the hook for block %d despite the failing one", i)
+		}
+	}
+}
+
+func TestDispatcherDropsWhenFull(t *testing.T) {
+	release := make(chan struct{})
+	blocks := make(chan *Block, 10)
+	d := NewDispatcher(map[string]Hook{
+		"slow": HookFunc(func(block *Block) error {
+			<-release
+			blocks <- block
+			return nil
+		}),
+	}, 1)
+
+	dropped := d.hooks[0].dropped.Count()
+	d.Notify("foo", newBlock(1, 0))
+	// Wait for the hook to dequeue the first block, so that the queue holds the second one only
+	deadline := time.After(time.Second)
+	for len(d.hooks[0].blocks) > 0 {
+		select {
+		case <-deadline:
+			t.Fatal("Hook did not dequeue the first block")
+		case <-time.After(time.Millisecond):
+		}
+	}
+	d.Notify("foo", newBlock(2, 0))
+	d.Notify("foo", newBlock(3, 0))
+	assert.Equal(t, dropped+1, d.hooks[0].dropped.Count(), "Should have dropped the block reaching a full queue")
+
+	close(release)
+	d.Stop()
+	assert.Equal(t, uint64(1), (<-blocks).Number)
+	assert.Equal(t, uint64(2), (<-blocks).Number)
+}
+
+func TestCommandHook(t *testing.T) {
+	dir, err := ioutil.TempDir("", "blockhook")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	out := filepath.Join(dir, "out")
+
+	hook := CommandHook(`printf '%s %s %s %s' "$CHANNEL" "$BLOCK_NUMBER" "$BLOCK_HASH" "$TX_COUNT" > ` + out)
+	assert.NoError(t, hook.BlockCommitted(&Block{ChainID: "foo", Number: 7, HeaderHash: []byte{0xca, 0xfe}, TxCount: 2}))
+	written, err := ioutil.ReadFile(out)
+	require.NoError(t, err)
+	assert.Equal(t, "foo 7 cafe 2", string(written))
+
+	err = CommandHook("echo oops; exit 3").BlockCommitted(&Block{ChainID: "foo"})
+	assert.Error(t, err, "Should have reported the failure of the command")
+	assert.Contains(t, err.Error(), "oops", "Should have reported the output of the command")
+}
+
+func TestNew(t *testing.T) {
+	assert.Nil(t, New(localconfig.BlockHooks{Enabled: false}), "Should run no hooks unless enabled")
+
+	Register("registered", HookFunc(func(block *Block) error { return nil }))
+	defer func() {
+		registryLock.Lock()
+		delete(registry, "registered")
+		registryLock.Unlock()
+	}()
+	assert.Panics(t, func() { Register("registered", HookFunc(func(block *Block) error { return nil })) }, "Should not register a hook twice")
+
+	d := New(localconfig.BlockHooks{Enabled: true, QueueSize: 10, Commands: map[string]string{"command": "true"}})
+	defer d.Stop()
+	var names []string
+	for _, q := range d.hooks {
+		names = append(names, q.name)
+	}
+	assert.Equal(t, []string{"command", "registered"}, names)
+
+	assert.Panics(t, func() {
+		New(localconfig.BlockHooks{Enabled: true, QueueSize: 10, Commands: map[string]string{"registered": "true"}})
+	}, "Should not shadow a registered hook with a command")
+}
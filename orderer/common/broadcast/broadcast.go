@@ -18,18 +18,38 @@ package broadcast
 
 import (
 	"github.com/hyperledger/fabric/orderer/common/filter"
+	"github.com/hyperledger/fabric/orderer/common/headerguard"
+	"github.com/hyperledger/fabric/orderer/common/tracing"
 	cb "github.com/hyperledger/fabric/protos/common"
 	ab "github.com/hyperledger/fabric/protos/orderer"
 	"github.com/op/go-logging"
 
+	"fmt"
 	"io"
+	"sync"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/protos/utils"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 var logger = logging.MustGetLogger("orderer/common/broadcast")
 
+const (
+	// BroadcastModeKey is the gRPC metadata key with which a client selects the mode of a broadcast stream
+	BroadcastModeKey = "broadcast-mode"
+
+	// BroadcastModeCommitAck is the broadcast mode in which, besides the response sent once an envelope has been
+	// enqueued, a second response carrying a CommitNotification is sent once the envelope has been included in a
+	// block written by this orderer. Only envelopes with a non-empty transaction ID in their channel header are
+	// tracked. Envelopes which are dropped after having been enqueued never receive the second response, so clients
+	// are expected to bound their wait
+	BroadcastModeCommitAck = "commit-ack"
+)
+
 // ConfigUpdateProcessor is used to transform CONFIG_UPDATE transactions which are used to generate other envelope
 // message types with preprocessing by the orderer
 type ConfigUpdateProcessor interface {
@@ -54,27 +74,149 @@ type SupportManager interface {
 
 // Support provides the backing resources needed to support broadcast on a chain
 type Support interface {
-	// Enqueue accepts a message and returns true on acceptance, or false on shutdown
-	Enqueue(env *cb.Envelope) bool
+	// Enqueue accepts a message along with its trace ID and returns true on acceptance, or false on shutdown
+	Enqueue(env *cb.Envelope, traceID string) bool
 
 	// Filters returns the set of broadcast filters for this chain
 	Filters() *filter.RuleSet
+
+	// AwaitCommit registers for a notification once the transaction with the given ID has been included in a block
+	// written by this orderer. The notification is sent once on the returned channel; cancel must be invoked once
+	// the caller is no longer waiting for it
+	AwaitCommit(txID string) (notification <-chan *ab.CommitNotification, cancel func())
+
+	// State returns the state of the chain, which is reported to the clients along with the envelopes the chain
+	// failed to enqueue
+	State() ab.BroadcastError_ChannelState
+
+	// Shed returns true if the next envelope should be rejected to protect the latency of the envelopes accepted,
+	// along with the delay after which the client should retry it
+	Shed() (retryAfter time.Duration, shed bool)
+
+	// Admit counts the envelope against the usage quotas of the chain and of its consortium, and returns an error if
+	// it exceeds one of them, along with the delay after which the quota is renewed
+	Admit(env *cb.Envelope) (retryAfter time.Duration, err error)
+}
+
+// retryAfter holds the delays after which the clients are told to retry the envelopes which could not be enqueued,
+// by state of the chain
+var retryAfter = map[ab.BroadcastError_ChannelState]time.Duration{
+	// The consenter is applying backpressure or failed to reach the consensus
+	ab.BroadcastError_ACTIVE: 500 * time.Millisecond,
+	// The consenter is starting, or reconnecting to the consensus
+	ab.BroadcastError_ERRORED: 5 * time.Second,
+	// Disk space has to be freed by an operator
+	ab.BroadcastError_QUIESCED: 30 * time.Second,
+	// The chain has to be resumed by an administrator
+	ab.BroadcastError_PAUSED: 30 * time.Second,
+}
+
+// rejection builds the response to an envelope rejected with the given status and class of error. The message is
+// also logged as a warning
+func rejection(status cb.Status, class ab.BroadcastError_Class, format string, args ...interface{}) *ab.BroadcastResponse {
+	message := fmt.Sprintf(format, args...)
+	logger.Warning(message)
+	return &ab.BroadcastResponse{Status: status, Error: &ab.BroadcastError{Class: class, Message: message}}
+}
+
+// unavailable builds the response to an envelope the chain failed to enqueue, with a retry hint depending on the
+// state of the chain
+func unavailable(chainID string, state ab.BroadcastError_ChannelState) *ab.BroadcastResponse {
+	resp := rejection(cb.Status_SERVICE_UNAVAILABLE, ab.BroadcastError_UNAVAILABLE,
+		"[channel: %s] Rejecting broadcast message because the chain failed to enqueue it, chain state is %s", chainID, state)
+	resp.Error.ChannelState = state
+	resp.Error.RetryAfterMs = uint64(retryAfter[state] / time.Millisecond)
+	return resp
 }
 
 type handlerImpl struct {
-	sm SupportManager
+	sm             SupportManager
+	ingress        *filter.RuleSet
+	guard          *headerguard.Guard
+	assignTraceIDs bool
 }
 
 // NewHandlerImpl constructs a new implementation of the Handler interface
 func NewHandlerImpl(sm SupportManager) Handler {
+	return NewHandlerImplWithIngressFilters(sm, nil)
+}
+
+// NewHandlerImplWithIngressFilters constructs a new implementation of the Handler interface which applies the
+// ingress filters to the envelopes as they are received, before any CONFIG_UPDATE processing and the filters of
+// the chain. Unlike the filters of the chain, which are applied again when the envelopes are ordered, the ingress
+// filters are only applied here, so they may depend on the local state of the orderer such as its clock
+func NewHandlerImplWithIngressFilters(sm SupportManager, ingress *filter.RuleSet) Handler {
+	return NewHandlerImplWithHeaderGuard(sm, ingress, nil)
+}
+
+// NewHandlerImplWithHeaderGuard constructs a new implementation of the Handler interface which, besides the
+// ingress filters, checks the channel headers of the envelopes with the guard, if any, as they are received and
+// once a CONFIG_UPDATE has been processed. Envelopes failing the guard are rejected with the class of its error
+func NewHandlerImplWithHeaderGuard(sm SupportManager, ingress *filter.RuleSet, guard *headerguard.Guard) Handler {
+	return NewHandlerImplWithTraceIDs(sm, ingress, guard, false)
+}
+
+// NewHandlerImplWithTraceIDs constructs a new implementation of the Handler interface which, if assignTraceIDs is
+// set, assigns a random trace ID to the envelopes of the streams whose client did not supply one
+func NewHandlerImplWithTraceIDs(sm SupportManager, ingress *filter.RuleSet, guard *headerguard.Guard, assignTraceIDs bool) Handler {
 	return &handlerImpl{
-		sm: sm,
+		sm:             sm,
+		ingress:        ingress,
+		guard:          guard,
+		assignTraceIDs: assignTraceIDs,
 	}
 }
 
-// Handle starts a service thread for a given gRPC connection and services the broadcast connection
+// Handle starts a service thread for a given gRPC connection and services the broadcast connection.
+// The stream ends with the gRPC status matching the response sent for an envelope which could not be enqueued
 func (bh *handlerImpl) Handle(srv ab.AtomicBroadcast_BroadcastServer) error {
+	if !commitAckRequested(srv.Context()) {
+		return grpcError(bh.handle(srv, nil))
+	}
+
+	logger.Debugf("Broadcast stream opened in commit acknowledgement mode")
+	acker := newCommitAcker(srv)
+	err := bh.handle(acker, acker)
+	// On a graceful hangup, or once an envelope has been rejected, the client may still be waiting for the
+	// notifications of the envelopes it sent before
+	_, rejected := err.(*terminalError)
+	acker.close(err == nil || rejected)
+	return grpcError(err)
+}
+
+// terminalError ends a broadcast stream once the response rejecting an envelope has been sent
+type terminalError struct {
+	status *status.Status
+}
+
+func (te *terminalError) Error() string {
+	return te.status.Message()
+}
+
+// terminate sends the response rejecting an envelope of the given channel, and returns the error ending the stream
+// with the matching gRPC status
+func terminate(srv ab.AtomicBroadcast_BroadcastServer, chainID string, resp *ab.BroadcastResponse) error {
+	if err := srv.Send(resp); err != nil {
+		return err
+	}
+	message := resp.Status.String()
+	if resp.Error != nil {
+		message = resp.Error.Message
+	}
+	return &terminalError{status: utils.NewErrorStatus(utils.BroadcastErrorDetail(chainID, resp), message)}
+}
+
+func grpcError(err error) error {
+	if te, ok := err.(*terminalError); ok {
+		return te.status.Err()
+	}
+	return err
+}
+
+func (bh *handlerImpl) handle(srv ab.AtomicBroadcast_BroadcastServer, acker *commitAcker) error {
 	logger.Debugf("Starting new broadcast loop")
+	// A trace ID supplied by the client applies to all the envelopes of the stream
+	streamTraceID := tracing.FromContext(srv.Context())
 	for {
 		msg, err := srv.Recv()
 		if err == io.EOF {
@@ -88,51 +230,65 @@ func (bh *handlerImpl) Handle(srv ab.AtomicBroadcast_BroadcastServer) error {
 
 		payload, err := utils.UnmarshalPayload(msg.Payload)
 		if err != nil {
-			logger.Warningf("Received malformed message, dropping connection: %s", err)
-			return srv.Send(&ab.BroadcastResponse{Status: cb.Status_BAD_REQUEST})
+			return terminate(srv, "", rejection(cb.Status_BAD_REQUEST, ab.BroadcastError_MALFORMED, "Received malformed message, dropping connection: %s", err))
 		}
 
 		if payload.Header == nil {
-			logger.Warningf("Received malformed message, with missing header, dropping connection")
-			return srv.Send(&ab.BroadcastResponse{Status: cb.Status_BAD_REQUEST})
+			return terminate(srv, "", rejection(cb.Status_BAD_REQUEST, ab.BroadcastError_MALFORMED, "Received malformed message, with missing header, dropping connection"))
 		}
 
 		chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
 		if err != nil {
-			logger.Warningf("Received malformed message (bad channel header), dropping connection: %s", err)
-			return srv.Send(&ab.BroadcastResponse{Status: cb.Status_BAD_REQUEST})
+			return terminate(srv, "", rejection(cb.Status_BAD_REQUEST, ab.BroadcastError_MALFORMED, "Received malformed message (bad channel header), dropping connection: %s", err))
+		}
+
+		if bh.guard != nil {
+			if err := bh.guard.Submitted(chdr); err != nil {
+				return terminate(srv, chdr.ChannelId, rejection(cb.Status_BAD_REQUEST, err.Class, "[channel: %s] Rejecting broadcast message because of invalid channel header: %s", chdr.ChannelId, err))
+			}
+		}
+
+		if bh.ingress != nil {
+			if _, err := bh.ingress.Apply(msg); err != nil {
+				return terminate(srv, chdr.ChannelId, rejection(cb.Status_BAD_REQUEST, ab.BroadcastError_REJECTED, "[channel: %s] Rejecting broadcast message because of ingress filter error: %s", chdr.ChannelId, err))
+			}
 		}
 
 		if chdr.Type == int32(cb.HeaderType_CONFIG_UPDATE) {
 			logger.Debugf("Preprocessing CONFIG_UPDATE")
+			updateChannelID := chdr.ChannelId
 			msg, err = bh.sm.Process(msg)
 			if err != nil {
-				logger.Warningf("Rejecting CONFIG_UPDATE because: %s", err)
-				return srv.Send(&ab.BroadcastResponse{Status: cb.Status_BAD_REQUEST})
+				return terminate(srv, chdr.ChannelId, rejection(cb.Status_BAD_REQUEST, ab.BroadcastError_REJECTED, "Rejecting CONFIG_UPDATE because: %s", err))
 			}
 
 			err = proto.Unmarshal(msg.Payload, payload)
 			if err != nil || payload.Header == nil {
 				logger.Criticalf("Generated bad transaction after CONFIG_UPDATE processing")
-				return srv.Send(&ab.BroadcastResponse{Status: cb.Status_INTERNAL_SERVER_ERROR})
+				return terminate(srv, chdr.ChannelId, internalError("Generated bad transaction after CONFIG_UPDATE processing"))
 			}
 
 			chdr, err = utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
 			if err != nil {
 				logger.Criticalf("Generated bad transaction after CONFIG_UPDATE processing (bad channel header): %s", err)
-				return srv.Send(&ab.BroadcastResponse{Status: cb.Status_INTERNAL_SERVER_ERROR})
+				return terminate(srv, "", internalError("Generated bad transaction after CONFIG_UPDATE processing (bad channel header)"))
 			}
 
 			if chdr.ChannelId == "" {
 				logger.Criticalf("Generated bad transaction after CONFIG_UPDATE processing (empty channel ID)")
-				return srv.Send(&ab.BroadcastResponse{Status: cb.Status_INTERNAL_SERVER_ERROR})
+				return terminate(srv, chdr.ChannelId, internalError("Generated bad transaction after CONFIG_UPDATE processing (empty channel ID)"))
+			}
+
+			if bh.guard != nil {
+				if err := bh.guard.Processed(updateChannelID, msg, chdr); err != nil {
+					return terminate(srv, updateChannelID, rejection(cb.Status_BAD_REQUEST, err.Class, "[channel: %s] Rejecting CONFIG_UPDATE because of invalid channel header: %s", updateChannelID, err))
+				}
 			}
 		}
 
 		support, ok := bh.sm.GetChain(chdr.ChannelId)
 		if !ok {
-			logger.Warningf("Rejecting broadcast because channel %s was not found", chdr.ChannelId)
-			return srv.Send(&ab.BroadcastResponse{Status: cb.Status_NOT_FOUND})
+			return terminate(srv, chdr.ChannelId, rejection(cb.Status_NOT_FOUND, ab.BroadcastError_NOT_FOUND, "Rejecting broadcast because channel %s was not found", chdr.ChannelId))
 		}
 
 		logger.Debugf("[channel: %s] Broadcast is filtering message of type %s", chdr.ChannelId, cb.HeaderType_name[chdr.Type])
@@ -141,22 +297,133 @@ func (bh *handlerImpl) Handle(srv ab.AtomicBroadcast_BroadcastServer) error {
 		_, filterErr := support.Filters().Apply(msg)
 
 		if filterErr != nil {
-			logger.Warningf("[channel: %s] Rejecting broadcast message because of filter error: %s", chdr.ChannelId, filterErr)
-			return srv.Send(&ab.BroadcastResponse{Status: cb.Status_BAD_REQUEST})
+			state := support.State()
+			resp := rejection(cb.Status_BAD_REQUEST, ab.BroadcastError_REJECTED, "[channel: %s] Rejecting broadcast message because of filter error: %s", chdr.ChannelId, filterErr)
+			if state == ab.BroadcastError_MAINTENANCE {
+				// The message may be retried once the maintenance is over
+				resp = rejection(cb.Status_SERVICE_UNAVAILABLE, ab.BroadcastError_UNAVAILABLE, "[channel: %s] Rejecting broadcast message because of filter error: %s", chdr.ChannelId, filterErr)
+			}
+			resp.Error.ChannelState = state
+			return terminate(srv, chdr.ChannelId, resp)
+		}
+
+		if retryAfter, shed := support.Shed(); shed {
+			resp := rejection(cb.Status_SERVICE_UNAVAILABLE, ab.BroadcastError_UNAVAILABLE,
+				"[channel: %s] Rejecting broadcast message because the chain is overloaded", chdr.ChannelId)
+			resp.Error.ChannelState = support.State()
+			resp.Error.RetryAfterMs = uint64(retryAfter / time.Millisecond)
+			return terminate(srv, chdr.ChannelId, resp)
+		}
+
+		// Config transactions are not subject to the quotas, so that the tenants may still administer their channels
+		if chdr.Type != int32(cb.HeaderType_CONFIG) && chdr.Type != int32(cb.HeaderType_ORDERER_TRANSACTION) {
+			if retryAfter, err := support.Admit(msg); err != nil {
+				resp := rejection(cb.Status_FORBIDDEN, ab.BroadcastError_QUOTA_EXCEEDED,
+					"[channel: %s] Rejecting broadcast message because of usage quota: %s", chdr.ChannelId, err)
+				resp.Error.ChannelState = support.State()
+				resp.Error.RetryAfterMs = uint64(retryAfter / time.Millisecond)
+				return terminate(srv, chdr.ChannelId, resp)
+			}
 		}
 
-		if !support.Enqueue(msg) {
-			return srv.Send(&ab.BroadcastResponse{Status: cb.Status_SERVICE_UNAVAILABLE})
+		// Register before enqueueing, as the block may be written before the enqueue response is sent
+		var notification <-chan *ab.CommitNotification
+		cancel := func() {}
+		if acker != nil && chdr.TxId != "" {
+			notification, cancel = support.AwaitCommit(chdr.TxId)
+		}
+
+		traceID := streamTraceID
+		if traceID == "" && bh.assignTraceIDs {
+			traceID = tracing.NewID()
+		}
+
+		if !support.Enqueue(msg, traceID) {
+			cancel()
+			return terminate(srv, chdr.ChannelId, unavailable(chdr.ChannelId, support.State()))
 		}
 
 		if logger.IsEnabledFor(logging.DEBUG) {
-			logger.Debugf("[channel: %s] Broadcast has successfully enqueued message of type %s", chdr.ChannelId, cb.HeaderType_name[chdr.Type])
+			logger.Debugf("[channel: %s] Broadcast has successfully enqueued message of type %s with trace ID %s", chdr.ChannelId, cb.HeaderType_name[chdr.Type], traceID)
 		}
 
-		err = srv.Send(&ab.BroadcastResponse{Status: cb.Status_SUCCESS})
+		err = srv.Send(&ab.BroadcastResponse{Status: cb.Status_SUCCESS, TraceId: traceID})
 		if err != nil {
+			cancel()
 			logger.Warningf("[channel: %s] Error sending to stream: %s", chdr.ChannelId, err)
 			return err
 		}
+
+		if notification != nil {
+			acker.ack(chdr.ChannelId, notification, cancel)
+		}
+	}
+}
+
+// internalError builds the response to an envelope the orderer failed to process
+func internalError(message string) *ab.BroadcastResponse {
+	return &ab.BroadcastResponse{
+		Status: cb.Status_INTERNAL_SERVER_ERROR,
+		Error:  &ab.BroadcastError{Class: ab.BroadcastError_INTERNAL, Message: message},
+	}
+}
+
+func commitAckRequested(ctx context.Context) bool {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return false
+	}
+	for _, mode := range md[BroadcastModeKey] {
+		if mode == BroadcastModeCommitAck {
+			return true
+		}
+	}
+	return false
+}
+
+// commitAcker wraps a broadcast stream opened in the commit acknowledgement mode, serializing the responses
+// sent by the broadcast loop with the commit notifications sent as blocks are written
+type commitAcker struct {
+	ab.AtomicBroadcast_BroadcastServer
+	lock sync.Mutex
+	wg   sync.WaitGroup
+	done chan struct{}
+}
+
+func newCommitAcker(srv ab.AtomicBroadcast_BroadcastServer) *commitAcker {
+	return &commitAcker{
+		AtomicBroadcast_BroadcastServer: srv,
+		done:                            make(chan struct{}),
+	}
+}
+
+func (ca *commitAcker) Send(resp *ab.BroadcastResponse) error {
+	ca.lock.Lock()
+	defer ca.lock.Unlock()
+	return ca.AtomicBroadcast_BroadcastServer.Send(resp)
+}
+
+// ack sends the commit notification once it is received, unless the stream is closed first
+func (ca *commitAcker) ack(chainID string, notification <-chan *ab.CommitNotification, cancel func()) {
+	ca.wg.Add(1)
+	go func() {
+		defer ca.wg.Done()
+		defer cancel()
+		select {
+		case commit := <-notification:
+			if err := ca.Send(&ab.BroadcastResponse{Status: cb.Status_SUCCESS, Commit: commit}); err != nil {
+				logger.Warningf("[channel: %s] Error sending commit notification to stream: %s", chainID, err)
+			}
+		case <-ca.done:
+		case <-ca.Context().Done():
+		}
+	}()
+}
+
+// close waits for the outstanding commit notifications to be sent if wait is true, and abandons them otherwise
+func (ca *commitAcker) close(wait bool) {
+	if !wait {
+		close(ca.done)
 	}
+	ca.wg.Wait()
 }
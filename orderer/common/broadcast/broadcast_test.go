@@ -23,13 +23,18 @@ import (
 	"time"
 
 	"github.com/hyperledger/fabric/orderer/common/filter"
+	"github.com/hyperledger/fabric/orderer/common/headerguard"
+	"github.com/hyperledger/fabric/orderer/common/tracing"
 	cb "github.com/hyperledger/fabric/protos/common"
 	ab "github.com/hyperledger/fabric/protos/orderer"
 	"github.com/hyperledger/fabric/protos/utils"
 
 	logging "github.com/op/go-logging"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 )
 
 func init() {
@@ -40,17 +45,23 @@ var systemChain = "systemChain"
 
 type mockB struct {
 	grpc.ServerStream
+	ctx      context.Context
 	recvChan chan *cb.Envelope
 	sendChan chan *ab.BroadcastResponse
 }
 
 func newMockB() *mockB {
 	return &mockB{
+		ctx:      context.Background(),
 		recvChan: make(chan *cb.Envelope),
 		sendChan: make(chan *ab.BroadcastResponse),
 	}
 }
 
+func (m *mockB) Context() context.Context {
+	return m.ctx
+}
+
 func (m *mockB) Send(br *ab.BroadcastResponse) error {
 	m.sendChan <- br
 	return nil
@@ -68,6 +79,10 @@ type erroneousRecvMockB struct {
 	grpc.ServerStream
 }
 
+func (m *erroneousRecvMockB) Context() context.Context {
+	return context.Background()
+}
+
 func (m *erroneousRecvMockB) Send(br *ab.BroadcastResponse) error {
 	return nil
 }
@@ -83,6 +98,10 @@ type erroneousSendMockB struct {
 	recvVal *cb.Envelope
 }
 
+func (m *erroneousSendMockB) Context() context.Context {
+	return context.Background()
+}
+
 func (m *erroneousSendMockB) Send(br *ab.BroadcastResponse) error {
 	// The point here is to simulate an error other than EOF.
 	// We don't bother to create a new custom error type.
@@ -121,6 +140,12 @@ func (mm *mockSupportManager) Process(configTx *cb.Envelope) (*cb.Envelope, erro
 type mockSupport struct {
 	filters       *filter.RuleSet
 	rejectEnqueue bool
+	state         ab.BroadcastError_ChannelState
+	shedFor       time.Duration
+	quotaErr      error
+	admitted      int
+	commits       map[string]chan *ab.CommitNotification
+	traceIDs      []string
 }
 
 func (ms *mockSupport) Filters() *filter.RuleSet {
@@ -128,10 +153,33 @@ func (ms *mockSupport) Filters() *filter.RuleSet {
 }
 
 // Enqueue sends a message for ordering
-func (ms *mockSupport) Enqueue(env *cb.Envelope) bool {
+func (ms *mockSupport) Enqueue(env *cb.Envelope, traceID string) bool {
+	ms.traceIDs = append(ms.traceIDs, traceID)
 	return !ms.rejectEnqueue
 }
 
+func (ms *mockSupport) State() ab.BroadcastError_ChannelState {
+	return ms.state
+}
+
+func (ms *mockSupport) Shed() (time.Duration, bool) {
+	return ms.shedFor, ms.shedFor > 0
+}
+
+func (ms *mockSupport) Admit(env *cb.Envelope) (time.Duration, error) {
+	if ms.quotaErr != nil {
+		return time.Minute, ms.quotaErr
+	}
+	ms.admitted++
+	return 0, nil
+}
+
+func (ms *mockSupport) AwaitCommit(txID string) (<-chan *ab.CommitNotification, func()) {
+	notification := make(chan *ab.CommitNotification, 1)
+	ms.commits[txID] = notification
+	return notification, func() { delete(ms.commits, txID) }
+}
+
 func makeConfigMessage(chainID string) *cb.Envelope {
 	payload := &cb.Payload{
 		Data: utils.MarshalOrPanic(&cb.ConfigEnvelope{}),
@@ -171,6 +219,7 @@ func getMockSupportManager() (*mockSupportManager, *mockSupport) {
 	}
 	mSysChain := &mockSupport{
 		filters: filters,
+		commits: make(map[string]chan *ab.CommitNotification),
 	}
 	mm.chains[string(systemChain)] = mSysChain
 	return mm, mSysChain
@@ -196,11 +245,203 @@ func TestEnqueueFailure(t *testing.T) {
 	}
 
 	mSysChain.rejectEnqueue = true
+	mSysChain.state = ab.BroadcastError_ERRORED
 	m.recvChan <- makeMessage(systemChain, []byte("Some bytes"))
 	reply := <-m.sendChan
 	if reply.Status != cb.Status_SERVICE_UNAVAILABLE {
 		t.Fatalf("Should not have successfully queued the message")
 	}
+	assert.Equal(t, ab.BroadcastError_UNAVAILABLE, reply.Error.Class)
+	assert.Equal(t, ab.BroadcastError_ERRORED, reply.Error.ChannelState)
+	assert.Equal(t, uint64(5000), reply.Error.RetryAfterMs, "Should have hinted to retry once the consenter is back")
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("Should have terminated the stream")
+	}
+}
+
+func TestShed(t *testing.T) {
+	mm, mSysChain := getMockSupportManager()
+	bh := NewHandlerImpl(mm)
+	m := newMockB()
+	defer close(m.recvChan)
+	done := make(chan struct{})
+	go func() {
+		bh.Handle(m)
+		close(done)
+	}()
+
+	mSysChain.state = ab.BroadcastError_ACTIVE
+	mSysChain.shedFor = 2 * time.Second
+	m.recvChan <- makeMessage(systemChain, []byte("Some bytes"))
+	reply := <-m.sendChan
+	assert.Equal(t, cb.Status_SERVICE_UNAVAILABLE, reply.Status)
+	assert.Equal(t, ab.BroadcastError_UNAVAILABLE, reply.Error.Class)
+	assert.Equal(t, ab.BroadcastError_ACTIVE, reply.Error.ChannelState)
+	assert.Equal(t, uint64(2000), reply.Error.RetryAfterMs, "Should have hinted to retry after the delay of the chain")
+	assert.Empty(t, mSysChain.traceIDs, "Should not have enqueued the message")
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("Should have terminated the stream")
+	}
+}
+
+func TestQuotaExceeded(t *testing.T) {
+	mm, mSysChain := getMockSupportManager()
+	bh := NewHandlerImpl(mm)
+	m := newMockB()
+	defer close(m.recvChan)
+	go bh.Handle(m)
+
+	m.recvChan <- makeMessage(systemChain, []byte("Some bytes"))
+	reply := <-m.sendChan
+	assert.Equal(t, cb.Status_SUCCESS, reply.Status)
+	assert.Equal(t, 1, mSysChain.admitted, "Should have counted the message against the quotas")
+
+	mSysChain.quotaErr = fmt.Errorf("quota of 1 transactions reached")
+	m.recvChan <- makeMessage(systemChain, []byte("Some bytes"))
+	reply = <-m.sendChan
+	assert.Equal(t, cb.Status_FORBIDDEN, reply.Status)
+	assert.Equal(t, ab.BroadcastError_QUOTA_EXCEEDED, reply.Error.Class)
+	assert.Equal(t, uint64(60000), reply.Error.RetryAfterMs, "Should have hinted to retry once the quota is renewed")
+	assert.Len(t, mSysChain.traceIDs, 1, "Should not have enqueued the message")
+}
+
+func TestQuotaExemptsConfig(t *testing.T) {
+	mm, mSysChain := getMockSupportManager()
+	mSysChain.quotaErr = fmt.Errorf("quota of 1 transactions reached")
+	mm.ProcessVal = &cb.Envelope{Payload: utils.MarshalOrPanic(&cb.Payload{Header: &cb.Header{ChannelHeader: utils.MarshalOrPanic(&cb.ChannelHeader{
+		ChannelId: systemChain,
+		Type:      int32(cb.HeaderType_CONFIG),
+	})}})}
+	bh := NewHandlerImpl(mm)
+	m := newMockB()
+	defer close(m.recvChan)
+	go bh.Handle(m)
+
+	m.recvChan <- makeConfigMessage(systemChain)
+	reply := <-m.sendChan
+	assert.Equal(t, cb.Status_SUCCESS, reply.Status, "Config transactions should not be subject to the quotas")
+}
+
+func makeMessageWithTxID(chainID string, txID string) *cb.Envelope {
+	payload := &cb.Payload{
+		Header: &cb.Header{
+			ChannelHeader: utils.MarshalOrPanic(&cb.ChannelHeader{
+				ChannelId: chainID,
+				TxId:      txID,
+			}),
+		},
+	}
+	return &cb.Envelope{
+		Payload: utils.MarshalOrPanic(payload),
+	}
+}
+
+func TestCommitAck(t *testing.T) {
+	mm, mSysChain := getMockSupportManager()
+	bh := NewHandlerImpl(mm)
+	m := newMockB()
+	m.ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs(BroadcastModeKey, BroadcastModeCommitAck))
+	done := make(chan struct{})
+	go func() {
+		assert.NoError(t, bh.Handle(m))
+		close(done)
+	}()
+
+	m.recvChan <- makeMessageWithTxID(systemChain, "tx1")
+	reply := <-m.sendChan
+	assert.Equal(t, cb.Status_SUCCESS, reply.Status)
+	assert.Nil(t, reply.Commit, "Should have acknowledged the enqueueing first")
+
+	// Envelopes without a transaction ID are not tracked
+	m.recvChan <- makeMessage(systemChain, []byte("Some bytes"))
+	reply = <-m.sendChan
+	assert.Equal(t, cb.Status_SUCCESS, reply.Status)
+	assert.Nil(t, reply.Commit)
+
+	// The stream is kept open on hangup until the outstanding commit notifications are sent
+	close(m.recvChan)
+	mSysChain.commits["tx1"] <- &ab.CommitNotification{TxId: "tx1", BlockNumber: 3, TxIndex: 1}
+	reply = <-m.sendChan
+	assert.Equal(t, cb.Status_SUCCESS, reply.Status)
+	assert.Equal(t, &ab.CommitNotification{TxId: "tx1", BlockNumber: 3, TxIndex: 1}, reply.Commit)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("Should have terminated the stream")
+	}
+	assert.Empty(t, mSysChain.commits, "Should have released the commit registration")
+}
+
+func TestTraceIDs(t *testing.T) {
+	mm, mSysChain := getMockSupportManager()
+	bh := NewHandlerImplWithTraceIDs(mm, nil, nil, true)
+	m := newMockB()
+	defer close(m.recvChan)
+	go bh.Handle(m)
+
+	for i := 0; i < 2; i++ {
+		m.recvChan <- makeMessage(systemChain, []byte("Some bytes"))
+		reply := <-m.sendChan
+		assert.Equal(t, cb.Status_SUCCESS, reply.Status)
+		assert.NotEmpty(t, reply.TraceId, "Should have generated a trace ID")
+		assert.Equal(t, mSysChain.traceIDs[i], reply.TraceId, "Should have enqueued the message with its trace ID")
+	}
+	assert.NotEqual(t, mSysChain.traceIDs[0], mSysChain.traceIDs[1], "Should have generated a trace ID per message")
+}
+
+func TestNoAssignedTraceIDs(t *testing.T) {
+	mm, mSysChain := getMockSupportManager()
+	bh := NewHandlerImpl(mm)
+	m := newMockB()
+	defer close(m.recvChan)
+	go bh.Handle(m)
+
+	m.recvChan <- makeMessage(systemChain, []byte("Some bytes"))
+	reply := <-m.sendChan
+	assert.Equal(t, cb.Status_SUCCESS, reply.Status)
+	assert.Empty(t, reply.TraceId, "Should not have generated a trace ID")
+	assert.Equal(t, []string{""}, mSysChain.traceIDs)
+}
+
+func TestSuppliedTraceID(t *testing.T) {
+	mm, mSysChain := getMockSupportManager()
+	bh := NewHandlerImpl(mm)
+	m := newMockB()
+	m.ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs(tracing.TraceIDKey, "support-ticket-42"))
+	defer close(m.recvChan)
+	go bh.Handle(m)
+
+	m.recvChan <- makeMessage(systemChain, []byte("Some bytes"))
+	reply := <-m.sendChan
+	assert.Equal(t, cb.Status_SUCCESS, reply.Status)
+	assert.Equal(t, "support-ticket-42", reply.TraceId)
+	assert.Equal(t, []string{"support-ticket-42"}, mSysChain.traceIDs)
+}
+
+func TestCommitAckStreamCanceled(t *testing.T) {
+	mm, _ := getMockSupportManager()
+	bh := NewHandlerImpl(mm)
+	m := newMockB()
+	ctx, cancel := context.WithCancel(metadata.NewIncomingContext(context.Background(), metadata.Pairs(BroadcastModeKey, BroadcastModeCommitAck)))
+	m.ctx = ctx
+	done := make(chan struct{})
+	go func() {
+		bh.Handle(m)
+		close(done)
+	}()
+
+	m.recvChan <- makeMessageWithTxID(systemChain, "tx1")
+	reply := <-m.sendChan
+	assert.Equal(t, cb.Status_SUCCESS, reply.Status)
+	close(m.recvChan)
+	cancel()
 
 	select {
 	case <-done:
@@ -249,6 +490,8 @@ func TestBadChannelId(t *testing.T) {
 	if reply.Status != cb.Status_NOT_FOUND {
 		t.Fatalf("Should have rejected message to a chain which does not exist")
 	}
+	assert.Equal(t, ab.BroadcastError_NOT_FOUND, reply.Error.Class)
+	assert.Zero(t, reply.Error.RetryAfterMs)
 
 	select {
 	case <-done:
@@ -306,6 +549,130 @@ func TestRejected(t *testing.T) {
 	m.recvChan <- makeConfigMessage(newChannelId)
 	reply := <-m.sendChan
 	assert.Equal(t, cb.Status_BAD_REQUEST, reply.Status, "Should have rejected CONFIG_UPDATE")
+	assert.Equal(t, ab.BroadcastError_REJECTED, reply.Error.Class)
+	assert.Contains(t, reply.Error.Message, "filter error")
+}
+
+func TestRejectionStatus(t *testing.T) {
+	mm, _ := getMockSupportManager()
+	bh := NewHandlerImpl(mm)
+	m := newMockB()
+	defer close(m.recvChan)
+	errs := make(chan error)
+	go func() {
+		errs <- bh.Handle(m)
+	}()
+
+	m.recvChan <- makeMessage("Wrong chain", []byte("Some bytes"))
+	<-m.sendChan
+	err := <-errs
+	assert.Equal(t, codes.NotFound, grpc.Code(err), "Should have ended the stream with the status of the rejection")
+	detail, ok := utils.GetErrorDetail(err)
+	if assert.True(t, ok, "Should have attached the details of the rejection") {
+		assert.Equal(t, utils.BroadcastErrorDomain, detail.Domain)
+		assert.Equal(t, "Wrong chain", detail.ChannelId)
+		assert.Equal(t, ab.BroadcastError_NOT_FOUND, detail.Class)
+		assert.False(t, detail.Retriable)
+	}
+}
+
+func TestIngressFilters(t *testing.T) {
+	mm, mSysChain := getMockSupportManager()
+	bh := NewHandlerImplWithIngressFilters(mm, filter.NewRuleSet([]filter.Rule{RejectRule}))
+	m := newMockB()
+	defer close(m.recvChan)
+	go bh.Handle(m)
+
+	m.recvChan <- makeConfigMessage(systemChain)
+	reply := <-m.sendChan
+	assert.Equal(t, cb.Status_BAD_REQUEST, reply.Status, "Should have rejected the CONFIG_UPDATE before processing it")
+	assert.Equal(t, ab.BroadcastError_REJECTED, reply.Error.Class)
+	assert.Empty(t, mSysChain.traceIDs, "Should not have enqueued the message")
+}
+
+func makeHeaderMessage(chdr *cb.ChannelHeader) *cb.Envelope {
+	return &cb.Envelope{Payload: utils.MarshalOrPanic(&cb.Payload{
+		Data:   []byte("Some bytes"),
+		Header: &cb.Header{ChannelHeader: utils.MarshalOrPanic(chdr)},
+	})}
+}
+
+func TestHeaderGuard(t *testing.T) {
+	guard := headerguard.New(systemChain, []cb.HeaderType{cb.HeaderType_ENDORSER_TRANSACTION, cb.HeaderType_CONFIG_UPDATE})
+	for _, test := range []struct {
+		name      string
+		msg       *cb.Envelope
+		processed *cb.Envelope
+		status    cb.Status
+		class     ab.BroadcastError_Class
+	}{
+		{
+			name:   "Allowed",
+			msg:    makeHeaderMessage(&cb.ChannelHeader{ChannelId: systemChain, Type: int32(cb.HeaderType_ENDORSER_TRANSACTION)}),
+			status: cb.Status_SUCCESS,
+		},
+		{
+			name:   "TypeNotAllowed",
+			msg:    makeMessage(systemChain, []byte("Some bytes")),
+			status: cb.Status_BAD_REQUEST,
+			class:  ab.BroadcastError_TYPE_NOT_ALLOWED,
+		},
+		{
+			name:   "InvalidEpoch",
+			msg:    makeHeaderMessage(&cb.ChannelHeader{ChannelId: systemChain, Type: int32(cb.HeaderType_ENDORSER_TRANSACTION), Epoch: 1}),
+			status: cb.Status_BAD_REQUEST,
+			class:  ab.BroadcastError_INVALID_EPOCH,
+		},
+		{
+			name:      "ConfigOfSameChannel",
+			msg:       makeConfigMessage(systemChain),
+			processed: makeHeaderMessage(&cb.ChannelHeader{ChannelId: systemChain, Type: int32(cb.HeaderType_CONFIG)}),
+			status:    cb.Status_SUCCESS,
+		},
+		{
+			name:      "ConfigOfOtherChannel",
+			msg:       makeConfigMessage("Other chain"),
+			processed: makeHeaderMessage(&cb.ChannelHeader{ChannelId: systemChain, Type: int32(cb.HeaderType_CONFIG)}),
+			status:    cb.Status_BAD_REQUEST,
+			class:     ab.BroadcastError_CHANNEL_MISMATCH,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			mm, mSysChain := getMockSupportManager()
+			mm.ProcessVal = test.processed
+			bh := NewHandlerImplWithHeaderGuard(mm, nil, guard)
+			m := newMockB()
+			defer close(m.recvChan)
+			go bh.Handle(m)
+
+			m.recvChan <- test.msg
+			reply := <-m.sendChan
+			assert.Equal(t, test.status, reply.Status)
+			if test.status == cb.Status_SUCCESS {
+				assert.Len(t, mSysChain.traceIDs, 1, "Should have enqueued the message")
+				return
+			}
+			assert.Equal(t, test.class, reply.Error.Class)
+			assert.Empty(t, mSysChain.traceIDs, "Should not have enqueued the message")
+		})
+	}
+}
+
+func TestRejectedInMaintenance(t *testing.T) {
+	filters := filter.NewRuleSet([]filter.Rule{RejectRule})
+	mm := &mockSupportManager{
+		chains: map[string]*mockSupport{string(systemChain): {filters: filters, state: ab.BroadcastError_MAINTENANCE}},
+	}
+	bh := NewHandlerImpl(mm)
+	m := newMockB()
+	defer close(m.recvChan)
+	go bh.Handle(m)
+
+	m.recvChan <- makeMessage(systemChain, []byte("Some bytes"))
+	reply := <-m.sendChan
+	assert.Equal(t, cb.Status_SERVICE_UNAVAILABLE, reply.Status, "Should have answered that the message may be retried")
+	assert.Equal(t, ab.BroadcastError_UNAVAILABLE, reply.Error.Class)
+	assert.Equal(t, ab.BroadcastError_MAINTENANCE, reply.Error.ChannelState)
 }
 
 func TestBadStreamRecv(t *testing.T) {
@@ -331,6 +698,8 @@ func TestMalformedEnvelope(t *testing.T) {
 	m.recvChan <- &cb.Envelope{Payload: []byte("foo")}
 	reply := <-m.sendChan
 	assert.Equal(t, cb.Status_BAD_REQUEST, reply.Status, "Should have rejected the malformed message")
+	assert.Equal(t, ab.BroadcastError_MALFORMED, reply.Error.Class)
+	assert.Zero(t, reply.Error.RetryAfterMs, "Should not have hinted to retry a malformed message")
 }
 
 func TestMissingHeader(t *testing.T) {
@@ -368,6 +737,7 @@ func TestBadPayloadAfterProcessing(t *testing.T) {
 	m.recvChan <- makeConfigMessage("New Chain")
 	reply := <-m.sendChan
 	assert.Equal(t, cb.Status_INTERNAL_SERVER_ERROR, reply.Status, "Should respond with internal server error")
+	assert.Equal(t, ab.BroadcastError_INTERNAL, reply.Error.Class)
 }
 
 func TestNilHeaderAfterProcessing(t *testing.T) {
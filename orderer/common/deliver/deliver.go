@@ -36,6 +36,9 @@ var logger = logging.MustGetLogger("orderer/common/deliver")
 // Handler defines an interface which handles Deliver requests
 type Handler interface {
 	Handle(srv ab.AtomicBroadcast_DeliverServer) error
+
+	// HandleMultiChannel serves the seek requests of a MultiChannelDeliver stream, for several channels at once
+	HandleMultiChannel(srv ab.MultiChannelDeliver_DeliverServer) error
 }
 
 // SupportManager provides a way for the Handler to look up the Support for a chain
@@ -69,6 +72,8 @@ func NewHandlerImpl(sm SupportManager) Handler {
 	}
 }
 
+// Handle serves the seek requests of a Deliver stream. The stream ends with the gRPC status matching the status
+// sent for a request which failed
 func (ds *deliverServer) Handle(srv ab.AtomicBroadcast_DeliverServer) error {
 	logger.Debugf("Starting new deliver loop")
 	for {
@@ -84,128 +89,146 @@ func (ds *deliverServer) Handle(srv ab.AtomicBroadcast_DeliverServer) error {
 			return err
 		}
 
-		payload, err := utils.UnmarshalPayload(envelope.Payload)
+		chainID, status, err := ds.deliverBlocks(envelope, func(block *cb.Block) error {
+			return sendBlockReply(srv, block)
+		}, nil)
 		if err != nil {
-			logger.Warningf("Received an envelope with no payload: %s", err)
-			return sendStatusReply(srv, cb.Status_BAD_REQUEST)
+			return err
 		}
-
-		if payload.Header == nil {
-			logger.Warningf("Malformed envelope received with bad header")
-			return sendStatusReply(srv, cb.Status_BAD_REQUEST)
+		if status != cb.Status_SUCCESS {
+			return terminate(srv, chainID, status)
 		}
 
-		chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
-		if err != nil {
-			logger.Warningf("Failed to unmarshal channel header: %s", err)
-			return sendStatusReply(srv, cb.Status_BAD_REQUEST)
+		if err := sendStatusReply(srv, cb.Status_SUCCESS); err != nil {
+			logger.Warningf("[channel: %s] Error sending to stream: %s", chainID, err)
+			return err
 		}
 
-		chain, ok := ds.sm.GetChain(chdr.ChannelId)
-		if !ok {
-			// Note, we log this at DEBUG because SDKs will poll waiting for channels to be created
-			// So we would expect our log to be somewhat flooded with these
-			logger.Debugf("Rejecting deliver because channel %s not found", chdr.ChannelId)
-			return sendStatusReply(srv, cb.Status_NOT_FOUND)
-		}
+		logger.Debugf("[channel: %s] Done delivering, waiting for new SeekInfo", chainID)
+	}
+}
 
-		erroredChan := chain.Errored()
-		select {
-		case <-erroredChan:
-			logger.Warningf("[channel: %s] Rejecting deliver request because of consenter error", chdr.ChannelId)
-			return sendStatusReply(srv, cb.Status_SERVICE_UNAVAILABLE)
-		default:
+// deliverBlocks serves the seek request of envelope, sending the blocks with send until the stop position, or
+// until done is closed. It returns the channel of the request, if known, and the status the request ended with,
+// SUCCESS if all the blocks were sent, or the error of send
+func (ds *deliverServer) deliverBlocks(envelope *cb.Envelope, send func(*cb.Block) error, done <-chan struct{}) (string, cb.Status, error) {
+	payload, err := utils.UnmarshalPayload(envelope.Payload)
+	if err != nil {
+		logger.Warningf("Received an envelope with no payload: %s", err)
+		return "", cb.Status_BAD_REQUEST, nil
+	}
 
-		}
+	if payload.Header == nil {
+		logger.Warningf("Malformed envelope received with bad header")
+		return "", cb.Status_BAD_REQUEST, nil
+	}
 
-		lastConfigSequence := chain.Sequence()
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		logger.Warningf("Failed to unmarshal channel header: %s", err)
+		return "", cb.Status_BAD_REQUEST, nil
+	}
 
-		sf := sigfilter.New(policies.ChannelReaders, chain.PolicyManager())
-		result, _ := sf.Apply(envelope)
-		if result != filter.Forward {
-			logger.Warningf("[channel: %s] Received unauthorized deliver request", chdr.ChannelId)
-			return sendStatusReply(srv, cb.Status_FORBIDDEN)
-		}
+	chain, ok := ds.sm.GetChain(chdr.ChannelId)
+	if !ok {
+		// Note, we log this at DEBUG because SDKs will poll waiting for channels to be created
+		// So we would expect our log to be somewhat flooded with these
+		logger.Debugf("Rejecting deliver because channel %s not found", chdr.ChannelId)
+		return chdr.ChannelId, cb.Status_NOT_FOUND, nil
+	}
 
-		seekInfo := &ab.SeekInfo{}
-		if err = proto.Unmarshal(payload.Data, seekInfo); err != nil {
-			logger.Warningf("[channel: %s] Received a signed deliver request with malformed seekInfo payload: %s", chdr.ChannelId, err)
-			return sendStatusReply(srv, cb.Status_BAD_REQUEST)
-		}
+	erroredChan := chain.Errored()
+	select {
+	case <-erroredChan:
+		logger.Warningf("[channel: %s] Rejecting deliver request because of consenter error", chdr.ChannelId)
+		return chdr.ChannelId, cb.Status_SERVICE_UNAVAILABLE, nil
+	default:
 
-		if seekInfo.Start == nil || seekInfo.Stop == nil {
-			logger.Warningf("[channel: %s] Received seekInfo message with missing start or stop %v, %v", chdr.ChannelId, seekInfo.Start, seekInfo.Stop)
-			return sendStatusReply(srv, cb.Status_BAD_REQUEST)
-		}
+	}
 
-		logger.Debugf("[channel: %s] Received seekInfo (%p) %v", chdr.ChannelId, seekInfo, seekInfo)
-
-		cursor, number := chain.Reader().Iterator(seekInfo.Start)
-		var stopNum uint64
-		switch stop := seekInfo.Stop.Type.(type) {
-		case *ab.SeekPosition_Oldest:
-			stopNum = number
-		case *ab.SeekPosition_Newest:
-			stopNum = chain.Reader().Height() - 1
-		case *ab.SeekPosition_Specified:
-			stopNum = stop.Specified.Number
-			if stopNum < number {
-				logger.Warningf("[channel: %s] Received invalid seekInfo message: start number %d greater than stop number %d", chdr.ChannelId, number, stopNum)
-				return sendStatusReply(srv, cb.Status_BAD_REQUEST)
-			}
-		}
+	lastConfigSequence := chain.Sequence()
 
-		for {
-			if seekInfo.Behavior == ab.SeekInfo_BLOCK_UNTIL_READY {
-				select {
-				case <-erroredChan:
-					logger.Warningf("[channel: %s] Aborting deliver request because of consenter error", chdr.ChannelId)
-					return sendStatusReply(srv, cb.Status_SERVICE_UNAVAILABLE)
-				case <-cursor.ReadyChan():
-				}
-			} else {
-				select {
-				case <-cursor.ReadyChan():
-				default:
-					return sendStatusReply(srv, cb.Status_NOT_FOUND)
-				}
-			}
+	sf := sigfilter.New(policies.ChannelReaders, chain.PolicyManager())
+	result, _ := sf.Apply(envelope)
+	if result != filter.Forward {
+		logger.Warningf("[channel: %s] Received unauthorized deliver request", chdr.ChannelId)
+		return chdr.ChannelId, cb.Status_FORBIDDEN, nil
+	}
 
-			currentConfigSequence := chain.Sequence()
-			if currentConfigSequence > lastConfigSequence {
-				lastConfigSequence = currentConfigSequence
-				sf := sigfilter.New(policies.ChannelReaders, chain.PolicyManager())
-				result, _ := sf.Apply(envelope)
-				if result != filter.Forward {
-					logger.Warningf("[channel: %s] Client authorization revoked for deliver request", chdr.ChannelId)
-					return sendStatusReply(srv, cb.Status_FORBIDDEN)
-				}
-			}
+	seekInfo := &ab.SeekInfo{}
+	if err = proto.Unmarshal(payload.Data, seekInfo); err != nil {
+		logger.Warningf("[channel: %s] Received a signed deliver request with malformed seekInfo payload: %s", chdr.ChannelId, err)
+		return chdr.ChannelId, cb.Status_BAD_REQUEST, nil
+	}
 
-			block, status := cursor.Next()
-			if status != cb.Status_SUCCESS {
-				logger.Errorf("[channel: %s] Error reading from channel, cause was: %v", chdr.ChannelId, status)
-				return sendStatusReply(srv, status)
-			}
+	if seekInfo.Start == nil || seekInfo.Stop == nil {
+		logger.Warningf("[channel: %s] Received seekInfo message with missing start or stop %v, %v", chdr.ChannelId, seekInfo.Start, seekInfo.Stop)
+		return chdr.ChannelId, cb.Status_BAD_REQUEST, nil
+	}
 
-			logger.Debugf("[channel: %s] Delivering block for (%p)", chdr.ChannelId, seekInfo)
+	logger.Debugf("[channel: %s] Received seekInfo (%p) %v", chdr.ChannelId, seekInfo, seekInfo)
+
+	cursor, number := chain.Reader().Iterator(seekInfo.Start)
+	var stopNum uint64
+	switch stop := seekInfo.Stop.Type.(type) {
+	case *ab.SeekPosition_Oldest:
+		stopNum = number
+	case *ab.SeekPosition_Newest:
+		stopNum = chain.Reader().Height() - 1
+	case *ab.SeekPosition_Specified:
+		stopNum = stop.Specified.Number
+		if stopNum < number {
+			logger.Warningf("[channel: %s] Received invalid seekInfo message: start number %d greater than stop number %d", chdr.ChannelId, number, stopNum)
+			return chdr.ChannelId, cb.Status_BAD_REQUEST, nil
+		}
+	}
 
-			if err := sendBlockReply(srv, block); err != nil {
-				logger.Warningf("[channel: %s] Error sending to stream: %s", chdr.ChannelId, err)
-				return err
+	for {
+		if seekInfo.Behavior == ab.SeekInfo_BLOCK_UNTIL_READY {
+			select {
+			case <-erroredChan:
+				logger.Warningf("[channel: %s] Aborting deliver request because of consenter error", chdr.ChannelId)
+				return chdr.ChannelId, cb.Status_SERVICE_UNAVAILABLE, nil
+			case <-done:
+				logger.Debugf("[channel: %s] Aborting deliver request as the stream is done", chdr.ChannelId)
+				return chdr.ChannelId, cb.Status_SERVICE_UNAVAILABLE, errStreamDone
+			case <-cursor.ReadyChan():
+			}
+		} else {
+			select {
+			case <-cursor.ReadyChan():
+			default:
+				return chdr.ChannelId, cb.Status_NOT_FOUND, nil
 			}
+		}
 
-			if stopNum == block.Header.Number {
-				break
+		currentConfigSequence := chain.Sequence()
+		if currentConfigSequence > lastConfigSequence {
+			lastConfigSequence = currentConfigSequence
+			sf := sigfilter.New(policies.ChannelReaders, chain.PolicyManager())
+			result, _ := sf.Apply(envelope)
+			if result != filter.Forward {
+				logger.Warningf("[channel: %s] Client authorization revoked for deliver request", chdr.ChannelId)
+				return chdr.ChannelId, cb.Status_FORBIDDEN, nil
 			}
 		}
 
-		if err := sendStatusReply(srv, cb.Status_SUCCESS); err != nil {
+		block, status := cursor.Next()
+		if status != cb.Status_SUCCESS {
+			logger.Errorf("[channel: %s] Error reading from channel, cause was: %v", chdr.ChannelId, status)
+			return chdr.ChannelId, status, nil
+		}
+
+		logger.Debugf("[channel: %s] Delivering block for (%p)", chdr.ChannelId, seekInfo)
+
+		if err := send(blockContent(block, seekInfo.ContentType)); err != nil {
 			logger.Warningf("[channel: %s] Error sending to stream: %s", chdr.ChannelId, err)
-			return err
+			return chdr.ChannelId, cb.Status_SERVICE_UNAVAILABLE, err
 		}
 
-		logger.Debugf("[channel: %s] Done delivering for (%p), waiting for new SeekInfo", chdr.ChannelId, seekInfo)
+		if stopNum == block.Header.Number {
+			return chdr.ChannelId, cb.Status_SUCCESS, nil
+		}
 	}
 }
 
@@ -216,6 +239,24 @@ func sendStatusReply(srv ab.AtomicBroadcast_DeliverServer, status cb.Status) err
 
 }
 
+// terminate sends the status of a request for the blocks of the given channel which failed, and returns the error
+// ending the stream with the matching gRPC status
+func terminate(srv ab.AtomicBroadcast_DeliverServer, chainID string, status cb.Status) error {
+	if err := sendStatusReply(srv, status); err != nil {
+		return err
+	}
+	return utils.NewErrorStatus(utils.DeliverErrorDetail(chainID, status), "deliver failed with status "+status.String()).Err()
+}
+
+// blockContent returns the portion of the block requested by the content type of the seekInfo, without modifying
+// the block read from the ledger
+func blockContent(block *cb.Block, contentType ab.SeekInfo_SeekContentType) *cb.Block {
+	if contentType == ab.SeekInfo_HEADER_WITH_SIG {
+		return &cb.Block{Header: block.Header, Metadata: block.Metadata}
+	}
+	return block
+}
+
 func sendBlockReply(srv ab.AtomicBroadcast_DeliverServer, block *cb.Block) error {
 	return srv.Send(&ab.DeliverResponse{
 		Type: &ab.DeliverResponse_Block{Block: block},
@@ -268,6 +268,45 @@ func TestSpecificSeek(t *testing.T) {
 	}
 }
 
+func TestHeaderOnlySeek(t *testing.T) {
+	m := newMockD()
+	defer close(m.recvChan)
+
+	ds := initializeDeliverHandler()
+	go ds.Handle(m)
+
+	m.recvChan <- makeSeek(systemChainID, &ab.SeekInfo{Start: seekOldest, Stop: seekNewest, Behavior: ab.SeekInfo_BLOCK_UNTIL_READY, ContentType: ab.SeekInfo_HEADER_WITH_SIG})
+
+	var previous *cb.BlockHeader
+	for count := 0; count <= ledgerSize; count++ {
+		select {
+		case deliverReply := <-m.sendChan:
+			block := deliverReply.GetBlock()
+			if block == nil {
+				assert.Equal(t, cb.Status_SUCCESS, deliverReply.GetStatus())
+				assert.Equal(t, ledgerSize, count)
+				break
+			}
+			assert.Nil(t, block.Data, "Should not have delivered the data of the block")
+			assert.NotNil(t, block.Metadata, "Should have delivered the metadata of the block")
+			if previous != nil {
+				assert.Equal(t, previous.Hash(), block.Header.PreviousHash, "Should be able to follow the hash chain")
+			}
+			previous = block.Header
+		case <-time.After(time.Second):
+			t.Fatalf("Timed out waiting to get all blocks")
+		}
+	}
+
+	m.recvChan <- makeSeek(systemChainID, &ab.SeekInfo{Start: seekNewest, Stop: seekNewest, Behavior: ab.SeekInfo_BLOCK_UNTIL_READY})
+	select {
+	case deliverReply := <-m.sendChan:
+		assert.NotNil(t, deliverReply.GetBlock().Data, "Should not have stripped the data of the block in the ledger")
+	case <-time.After(time.Second):
+		t.Fatalf("Timed out waiting to get the block")
+	}
+}
+
 func TestUnauthorizedSeek(t *testing.T) {
 	mm := newMockMultichainManager()
 	for i := 1; i < ledgerSize; i++ {
@@ -0,0 +1,143 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deliver
+
+import (
+	"errors"
+	"io"
+	"sync"
+
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"golang.org/x/net/context"
+)
+
+// errStreamDone is returned for the requests aborted as their stream is done
+var errStreamDone = errors.New("the deliver stream is done")
+
+// multiChannelStream serializes the replies of the concurrent requests of a MultiChannelDeliver stream, and
+// tracks the channels with a request in progress
+type multiChannelStream struct {
+	srv    ab.MultiChannelDeliver_DeliverServer
+	lock   sync.Mutex
+	active map[string]bool
+	err    error
+}
+
+func (s *multiChannelStream) send(chainID string, response *ab.DeliverResponse) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.srv.Send(&ab.ChannelDeliverResponse{ChannelId: chainID, Response: response})
+}
+
+func (s *multiChannelStream) sendStatus(chainID string, status cb.Status) error {
+	return s.send(chainID, &ab.DeliverResponse{Type: &ab.DeliverResponse_Status{Status: status}})
+}
+
+// start marks a request for the channel as in progress, and returns false if one already is
+func (s *multiChannelStream) start(chainID string) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.active[chainID] {
+		return false
+	}
+	s.active[chainID] = true
+	return true
+}
+
+// end marks the request for the channel as done, recording the error it failed to send with, if any
+func (s *multiChannelStream) end(chainID string, err error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.active, chainID)
+	if err != nil && err != errStreamDone && s.err == nil {
+		s.err = err
+	}
+}
+
+func (s *multiChannelStream) firstErr() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.err
+}
+
+// HandleMultiChannel serves the seek requests of a MultiChannelDeliver stream. The requests for different channels
+// are served concurrently, each reply being labeled with the channel of its request, and a request for a channel
+// with one in progress is rejected with BAD_REQUEST. A request which fails ends with its status, without ending the
+// stream. Once the client hangs up, the stream ends as the requests in progress complete
+func (ds *deliverServer) HandleMultiChannel(srv ab.MultiChannelDeliver_DeliverServer) error {
+	logger.Debugf("Starting new multi-channel deliver loop")
+	ctx, cancel := context.WithCancel(srv.Context())
+	var wg sync.WaitGroup
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	stream := &multiChannelStream{srv: srv, active: make(map[string]bool)}
+	for {
+		envelope, err := srv.Recv()
+		if err == io.EOF {
+			logger.Debugf("Received EOF, waiting for the requests in progress")
+			wg.Wait()
+			return stream.firstErr()
+		}
+		if err != nil {
+			logger.Warningf("Error reading from stream: %s", err)
+			return err
+		}
+		if err := stream.firstErr(); err != nil {
+			return err
+		}
+
+		chainID, err := channelID(envelope)
+		if err != nil {
+			logger.Warningf("Received a malformed deliver request: %s", err)
+			if err := stream.sendStatus("", cb.Status_BAD_REQUEST); err != nil {
+				return err
+			}
+			continue
+		}
+		if !stream.start(chainID) {
+			logger.Warningf("[channel: %s] Rejecting deliver request as one is in progress for the channel", chainID)
+			if err := stream.sendStatus(chainID, cb.Status_BAD_REQUEST); err != nil {
+				return err
+			}
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, status, err := ds.deliverBlocks(envelope, func(block *cb.Block) error {
+				return stream.send(chainID, &ab.DeliverResponse{Type: &ab.DeliverResponse_Block{Block: block}})
+			}, ctx.Done())
+			if err == nil {
+				err = stream.sendStatus(chainID, status)
+			}
+			stream.end(chainID, err)
+			logger.Debugf("[channel: %s] Done delivering with status %s", chainID, status)
+		}()
+	}
+}
+
+// channelID returns the channel of the header of the envelope
+func channelID(envelope *cb.Envelope) (string, error) {
+	payload, err := utils.UnmarshalPayload(envelope.Payload)
+	if err != nil {
+		return "", err
+	}
+	if payload.Header == nil {
+		return "", errors.New("missing header")
+	}
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return "", err
+	}
+	return chdr.ChannelId, nil
+}
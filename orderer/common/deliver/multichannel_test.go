@@ -0,0 +1,157 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deliver
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	mockpolicies "github.com/hyperledger/fabric/common/mocks/policies"
+	"github.com/hyperledger/fabric/orderer/ledger"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+const otherChainID = "otherChain"
+
+type mockMultiD struct {
+	grpc.ServerStream
+	ctx      context.Context
+	recvChan chan *cb.Envelope
+	sendChan chan *ab.ChannelDeliverResponse
+}
+
+func newMockMultiD(ctx context.Context) *mockMultiD {
+	return &mockMultiD{
+		ctx:      ctx,
+		recvChan: make(chan *cb.Envelope),
+		sendChan: make(chan *ab.ChannelDeliverResponse),
+	}
+}
+
+func (m *mockMultiD) Context() context.Context {
+	return m.ctx
+}
+
+func (m *mockMultiD) Send(resp *ab.ChannelDeliverResponse) error {
+	select {
+	case m.sendChan <- resp:
+		return nil
+	case <-m.ctx.Done():
+		return m.ctx.Err()
+	}
+}
+
+func (m *mockMultiD) Recv() (*cb.Envelope, error) {
+	select {
+	case msg, ok := <-m.recvChan:
+		if !ok {
+			return nil, io.EOF
+		}
+		return msg, nil
+	case <-m.ctx.Done():
+		return nil, m.ctx.Err()
+	}
+}
+
+func (m *mockMultiD) next(t *testing.T) *ab.ChannelDeliverResponse {
+	select {
+	case resp := <-m.sendChan:
+		return resp
+	case <-time.After(time.Second):
+		t.Fatalf("Timed out waiting for a reply")
+		return nil
+	}
+}
+
+// initializeMultiChannelManager returns a manager of the system chain, with ledgerSize blocks, and of another chain
+// with only its genesis block
+func initializeMultiChannelManager() *mockSupportManager {
+	mm := newMockMultichainManager()
+	l := mm.chains[systemChainID].ledger
+	for i := 1; i < ledgerSize; i++ {
+		l.Append(ledger.CreateNextBlock(l, []*cb.Envelope{{Payload: []byte{byte(i)}}}))
+	}
+	mm.chains[otherChainID] = &mockSupport{
+		ledger:        NewRAMLedger(),
+		policyManager: &mockpolicies.Manager{Policy: &mockpolicies.Policy{}},
+		erroredChan:   make(chan struct{}),
+	}
+	return mm
+}
+
+func TestMultiChannelSeek(t *testing.T) {
+	mm := initializeMultiChannelManager()
+	m := newMockMultiD(context.Background())
+	handlerDone := make(chan error)
+	go func() { handlerDone <- NewHandlerImpl(mm).HandleMultiChannel(m) }()
+
+	// The request for the other chain waits for block 1, while the system chain is delivered
+	m.recvChan <- makeSeek(otherChainID, &ab.SeekInfo{Start: seekSpecified(1), Stop: seekSpecified(1), Behavior: ab.SeekInfo_BLOCK_UNTIL_READY})
+	m.recvChan <- makeSeek(systemChainID, &ab.SeekInfo{Start: seekOldest, Stop: seekNewest, Behavior: ab.SeekInfo_BLOCK_UNTIL_READY})
+	for i := uint64(0); i < ledgerSize; i++ {
+		resp := m.next(t)
+		assert.Equal(t, systemChainID, resp.ChannelId)
+		require.NotNil(t, resp.Response.GetBlock(), "Expected block %d", i)
+		assert.Equal(t, i, resp.Response.GetBlock().Header.Number)
+	}
+	resp := m.next(t)
+	assert.Equal(t, &ab.ChannelDeliverResponse{ChannelId: systemChainID, Response: &ab.DeliverResponse{Type: &ab.DeliverResponse_Status{Status: cb.Status_SUCCESS}}}, resp)
+
+	// A second request for the other chain is rejected, and a failed request does not end the stream
+	m.recvChan <- makeSeek(otherChainID, &ab.SeekInfo{Start: seekOldest, Stop: seekNewest})
+	resp = m.next(t)
+	assert.Equal(t, otherChainID, resp.ChannelId)
+	assert.Equal(t, cb.Status_BAD_REQUEST, resp.Response.GetStatus())
+	m.recvChan <- makeSeek("unknownChain", &ab.SeekInfo{Start: seekOldest, Stop: seekNewest})
+	resp = m.next(t)
+	assert.Equal(t, "unknownChain", resp.ChannelId)
+	assert.Equal(t, cb.Status_NOT_FOUND, resp.Response.GetStatus())
+	m.recvChan <- &cb.Envelope{Payload: []byte("garbage")}
+	resp = m.next(t)
+	assert.Equal(t, "", resp.ChannelId)
+	assert.Equal(t, cb.Status_BAD_REQUEST, resp.Response.GetStatus())
+
+	// Once the client hangs up, the stream ends with the requests in progress
+	close(m.recvChan)
+	l := mm.chains[otherChainID].ledger
+	l.Append(ledger.CreateNextBlock(l, []*cb.Envelope{{Payload: []byte("1")}}))
+	resp = m.next(t)
+	assert.Equal(t, otherChainID, resp.ChannelId)
+	require.NotNil(t, resp.Response.GetBlock())
+	assert.Equal(t, uint64(1), resp.Response.GetBlock().Header.Number)
+	resp = m.next(t)
+	assert.Equal(t, cb.Status_SUCCESS, resp.Response.GetStatus())
+	select {
+	case err := <-handlerDone:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatalf("Timed out waiting for the stream to end")
+	}
+}
+
+func TestMultiChannelSeekCanceled(t *testing.T) {
+	mm := initializeMultiChannelManager()
+	ctx, cancel := context.WithCancel(context.Background())
+	m := newMockMultiD(ctx)
+	handlerDone := make(chan error)
+	go func() { handlerDone <- NewHandlerImpl(mm).HandleMultiChannel(m) }()
+
+	m.recvChan <- makeSeek(otherChainID, &ab.SeekInfo{Start: seekSpecified(1), Stop: seekSpecified(1), Behavior: ab.SeekInfo_BLOCK_UNTIL_READY})
+	cancel()
+	select {
+	case err := <-handlerDone:
+		assert.Error(t, err)
+	case <-time.After(time.Second):
+		t.Fatalf("Timed out waiting for the stream to end")
+	}
+}
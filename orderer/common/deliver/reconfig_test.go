@@ -0,0 +1,178 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deliver
+
+import (
+	"io/ioutil"
+	"math"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/common/configtx"
+	configtxapi "github.com/hyperledger/fabric/common/configtx/api"
+	configtxtest "github.com/hyperledger/fabric/common/configtx/test"
+	"github.com/hyperledger/fabric/orderer/ledger"
+	ramledger "github.com/hyperledger/fabric/orderer/ledger/ram"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// channelSupport is a channel whose config is managed by a config manager, updated with the
+// config blocks written to its ledger as the orderer does
+type channelSupport struct {
+	configtxapi.Manager
+	ledger ledger.ReadWriter
+}
+
+func (cs *channelSupport) Errored() <-chan struct{} {
+	return nil
+}
+
+func (cs *channelSupport) Reader() ledger.Reader {
+	return cs.ledger
+}
+
+// commit writes the block of a config applied to the channel. The orderer applies the configs
+// before writing their blocks
+func (cs *channelSupport) commit(t *testing.T) func(*cb.ConfigEnvelope, error) {
+	return func(configEnv *cb.ConfigEnvelope, err error) {
+		require.NoError(t, err)
+		configTx, err := utils.CreateSignedEnvelope(cb.HeaderType_CONFIG, cs.ChainID(), nil, configEnv, 0, 0)
+		require.NoError(t, err)
+		require.NoError(t, cs.ledger.Append(ledger.CreateNextBlock(cs.ledger, []*cb.Envelope{configTx})))
+	}
+}
+
+type channelSupportManager map[string]*channelSupport
+
+func (csm channelSupportManager) GetChain(chainID string) (Support, bool) {
+	cs, ok := csm[chainID]
+	return cs, ok
+}
+
+type deliverStream struct {
+	*mockD
+	t *testing.T
+}
+
+// seek starts delivering the blocks of the channel to a member, until the stream is closed
+func seek(t *testing.T, handler Handler, chainID string, member *configtxtest.Member) *deliverStream {
+	env, err := utils.CreateSignedEnvelope(cb.HeaderType_DELIVER_SEEK_INFO, chainID, member, &ab.SeekInfo{
+		Start:    &ab.SeekPosition{Type: &ab.SeekPosition_Oldest{Oldest: &ab.SeekOldest{}}},
+		Stop:     seekSpecified(math.MaxUint64),
+		Behavior: ab.SeekInfo_BLOCK_UNTIL_READY,
+	}, 0, 0)
+	require.NoError(t, err)
+
+	stream := &deliverStream{mockD: newMockD(), t: t}
+	go handler.Handle(stream.mockD)
+	stream.recvChan <- env
+	return stream
+}
+
+func (s *deliverStream) response() *ab.DeliverResponse {
+	select {
+	case resp := <-s.sendChan:
+		return resp
+	case <-time.After(5 * time.Second):
+		s.t.Fatal("Timed out waiting for a deliver response")
+		return nil
+	}
+}
+
+func (s *deliverStream) expectBlocks(from, to uint64) {
+	for number := from; number <= to; number++ {
+		block := s.response().GetBlock()
+		require.NotNil(s.t, block, "Expected block %d", number)
+		assert.Equal(s.t, number, block.Header.Number)
+	}
+}
+
+func (s *deliverStream) expectStatus(status cb.Status) {
+	assert.Equal(s.t, status, s.response().GetStatus())
+}
+
+func (s *deliverStream) close() {
+	close(s.recvChan)
+}
+
+func TestDeliverOrgReconfiguration(t *testing.T) {
+	dir, err := ioutil.TempDir("", "deliver-reconfig")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var orgs []*configtxtest.Org
+	for _, name := range []string{"OrdererMSP", "Org1MSP", "Org2MSP", "Org3MSP"} {
+		org, err := configtxtest.NewOrg(dir, name)
+		require.NoError(t, err)
+		orgs = append(orgs, org)
+	}
+	ordererOrg, org1, org2, org3 := orgs[0], orgs[1], orgs[2], orgs[3]
+	member1, err := org1.NewMember("peer0.org1")
+	require.NoError(t, err)
+	member2, err := org2.NewMember("peer0.org2")
+	require.NoError(t, err)
+	member3, err := org3.NewMember("peer0.org3")
+	require.NoError(t, err)
+
+	chainID := "reconfig"
+	genesisBlock, err := configtxtest.MakeGenesisBlockFromOrgs(chainID, ordererOrg, org1, org2)
+	require.NoError(t, err)
+	cm, err := configtx.NewManagerImpl(utils.ExtractEnvelopeOrPanic(genesisBlock, 0), configtx.NewInitializer(), nil)
+	require.NoError(t, err)
+	rl, err := ramledger.New(ledgerSize).GetOrCreate(chainID)
+	require.NoError(t, err)
+	require.NoError(t, rl.Append(genesisBlock))
+	cs := &channelSupport{Manager: cm, ledger: rl}
+	handler := NewHandlerImpl(channelSupportManager{chainID: cs})
+
+	stream1 := seek(t, handler, chainID, member1)
+	defer stream1.close()
+	stream1.expectBlocks(0, 0)
+	stream2 := seek(t, handler, chainID, member2)
+	defer stream2.close()
+	stream2.expectBlocks(0, 0)
+	seek(t, handler, chainID, member3).expectStatus(cb.Status_FORBIDDEN)
+
+	t.Run("OrgAddition", func(t *testing.T) {
+		// A majority of the admins of the organizations of the channel adds Org3
+		cs.commit(t)(configtxtest.AddApplicationOrg(cs, org3, org1.Admin, org2.Admin))
+		stream1.expectBlocks(1, 1)
+		stream2.expectBlocks(1, 1)
+
+		stream3 := seek(t, handler, chainID, member3)
+		defer stream3.close()
+		stream3.expectBlocks(0, 1)
+
+		require.NoError(t, rl.Append(ledger.CreateNextBlock(rl, []*cb.Envelope{{Payload: []byte("tx")}})))
+		stream1.expectBlocks(2, 2)
+		stream2.expectBlocks(2, 2)
+		stream3.expectBlocks(2, 2)
+	})
+
+	t.Run("OrgRemoval", func(t *testing.T) {
+		stream3 := seek(t, handler, chainID, member3)
+		defer stream3.close()
+		stream3.expectBlocks(0, 2)
+
+		// The streams of Org2 are revoked as soon as the config which removes it is committed,
+		// without delivering its block
+		cs.commit(t)(configtxtest.RemoveApplicationOrg(cs, org2, org1.Admin, org3.Admin))
+		stream2.expectStatus(cb.Status_FORBIDDEN)
+		stream1.expectBlocks(3, 3)
+		stream3.expectBlocks(3, 3)
+		seek(t, handler, chainID, member2).expectStatus(cb.Status_FORBIDDEN)
+
+		require.NoError(t, rl.Append(ledger.CreateNextBlock(rl, []*cb.Envelope{{Payload: []byte("tx")}})))
+		stream1.expectBlocks(4, 4)
+		stream3.expectBlocks(4, 4)
+	})
+}
@@ -41,6 +41,14 @@ type Rule interface {
 	Apply(message *ab.Envelope) (Action, Committer)
 }
 
+// ExplainingRule is implemented by the rules which tell why they reject an Envelope
+type ExplainingRule interface {
+	Rule
+
+	// ApplyAndExplain behaves as Apply, and returns the reason of the rejection along with the Reject action
+	ApplyAndExplain(message *ab.Envelope) (Action, Committer, error)
+}
+
 // Committer is returned by postfiltering and should be invoked once the message has been written to the blockchain
 type Committer interface {
 	// Commit performs whatever action should be performed upon committing of a message
@@ -94,11 +102,21 @@ func NewRuleSet(rules []Rule) *RuleSet {
 // Apply applies the rules given for this set in order, returning the committer, nil on valid, or nil, err on invalid
 func (rs *RuleSet) Apply(message *ab.Envelope) (Committer, error) {
 	for _, rule := range rs.rules {
-		action, committer := rule.Apply(message)
+		var action Action
+		var committer Committer
+		var reason error
+		if explaining, ok := rule.(ExplainingRule); ok {
+			action, committer, reason = explaining.ApplyAndExplain(message)
+		} else {
+			action, committer = rule.Apply(message)
+		}
 		switch action {
 		case Accept:
 			return committer, nil
 		case Reject:
+			if reason != nil {
+				return nil, fmt.Errorf("Rejected by rule: %T: %s", rule, reason)
+			}
 			return nil, fmt.Errorf("Rejected by rule: %T", rule)
 		default:
 		}
@@ -17,6 +17,7 @@ limitations under the License.
 package filter
 
 import (
+	"fmt"
 	"testing"
 
 	cb "github.com/hyperledger/fabric/protos/common"
@@ -39,6 +40,14 @@ func (r forwardRule) Apply(message *cb.Envelope) (Action, Committer) {
 	return Forward, nil
 }
 
+type explainingRejectRule struct {
+	rejectRule
+}
+
+func (r explainingRejectRule) ApplyAndExplain(message *cb.Envelope) (Action, Committer, error) {
+	return Reject, nil, fmt.Errorf("the reason")
+}
+
 func TestNoopCommitter(t *testing.T) {
 	var nc noopCommitter
 	assert.False(t, nc.Isolated(), "Should return false")
@@ -71,6 +80,12 @@ func TestRejectAccept(t *testing.T) {
 	}
 }
 
+func TestExplainedReject(t *testing.T) {
+	rs := NewRuleSet([]Rule{ForwardRule, explainingRejectRule{}, AcceptRule})
+	_, err := rs.Apply(&cb.Envelope{})
+	assert.EqualError(t, err, "Rejected by rule: filter.explainingRejectRule: the reason")
+}
+
 func TestForwardAccept(t *testing.T) {
 	rs := NewRuleSet([]Rule{ForwardRule, AcceptRule})
 	_, err := rs.Apply(&cb.Envelope{})
@@ -0,0 +1,126 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package headerguard
+
+import (
+	"fmt"
+
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+	logging "github.com/op/go-logging"
+)
+
+var logger = logging.MustGetLogger("orderer/common/headerguard")
+
+// Error is the reason why the guard rejects an envelope, along with the class of the broadcast error returned to
+// the client
+type Error struct {
+	Class   ab.BroadcastError_Class
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func rejectf(class ab.BroadcastError_Class, format string, args ...interface{}) *Error {
+	return &Error{Class: class, Message: fmt.Sprintf(format, args...)}
+}
+
+// Guard checks the channel headers of the broadcasted envelopes, so that the envelopes the peers would refuse
+// to validate are rejected when they are submitted rather than once they are ordered
+type Guard struct {
+	systemChannelID string
+	allowedTypes    map[int32]bool
+}
+
+// New creates a guard which only admits the envelopes whose channel header is of one of the allowed types.
+// The envelopes produced by the processing of a CONFIG_UPDATE are checked against the channel of the update,
+// the system channel ID being the channel of the channel creation requests
+func New(systemChannelID string, allowedTypes []cb.HeaderType) *Guard {
+	g := &Guard{
+		systemChannelID: systemChannelID,
+		allowedTypes:    make(map[int32]bool),
+	}
+	for _, t := range allowedTypes {
+		g.allowedTypes[int32(t)] = true
+	}
+	return g
+}
+
+// Submitted checks the channel header of an envelope as it is received
+func (g *Guard) Submitted(chdr *cb.ChannelHeader) *Error {
+	if !g.allowedTypes[chdr.Type] {
+		return rejectf(ab.BroadcastError_TYPE_NOT_ALLOWED, "header type %s is not allowed", typeName(chdr.Type))
+	}
+	if chdr.Epoch != 0 {
+		return rejectf(ab.BroadcastError_INVALID_EPOCH, "epoch is %d, it must be 0", chdr.Epoch)
+	}
+	return nil
+}
+
+// Processed checks the envelope produced by the processing of a CONFIG_UPDATE submitted to channelID, whose
+// channel header is chdr. An update of an existing channel produces a CONFIG envelope for that channel, and a
+// channel creation request an ORDERER_TRANSACTION for the system channel which wraps the CONFIG envelope of
+// the new channel
+func (g *Guard) Processed(channelID string, env *cb.Envelope, chdr *cb.ChannelHeader) *Error {
+	switch cb.HeaderType(chdr.Type) {
+	case cb.HeaderType_CONFIG:
+		if chdr.ChannelId != channelID {
+			return rejectf(ab.BroadcastError_CHANNEL_MISMATCH, "config update of channel %s produced a config for channel %s", channelID, chdr.ChannelId)
+		}
+	case cb.HeaderType_ORDERER_TRANSACTION:
+		if chdr.ChannelId != g.systemChannelID {
+			return rejectf(ab.BroadcastError_CHANNEL_MISMATCH, "config update of channel %s produced an orderer transaction for channel %s instead of the system channel", channelID, chdr.ChannelId)
+		}
+		wrapped, err := wrappedChannelHeader(env)
+		if err != nil {
+			return rejectf(ab.BroadcastError_MALFORMED, "config update of channel %s produced a malformed orderer transaction: %s", channelID, err)
+		}
+		if wrapped.Type != int32(cb.HeaderType_CONFIG) {
+			return rejectf(ab.BroadcastError_TYPE_NOT_ALLOWED, "config update of channel %s produced an orderer transaction wrapping a %s", channelID, typeName(wrapped.Type))
+		}
+		if wrapped.ChannelId != channelID {
+			return rejectf(ab.BroadcastError_CHANNEL_MISMATCH, "config update of channel %s produced a config for channel %s", channelID, wrapped.ChannelId)
+		}
+	default:
+		return rejectf(ab.BroadcastError_TYPE_NOT_ALLOWED, "config update of channel %s produced a %s", channelID, typeName(chdr.Type))
+	}
+	if chdr.Epoch != 0 {
+		return rejectf(ab.BroadcastError_INVALID_EPOCH, "config update of channel %s produced an epoch of %d", channelID, chdr.Epoch)
+	}
+	logger.Debugf("[channel: %s] Config update produced a valid %s", channelID, typeName(chdr.Type))
+	return nil
+}
+
+// wrappedChannelHeader returns the channel header of the envelope wrapped by an ORDERER_TRANSACTION
+func wrappedChannelHeader(env *cb.Envelope) (*cb.ChannelHeader, error) {
+	payload, err := utils.UnmarshalPayload(env.Payload)
+	if err != nil {
+		return nil, err
+	}
+	wrapped, err := utils.UnmarshalEnvelope(payload.Data)
+	if err != nil {
+		return nil, err
+	}
+	wrappedPayload, err := utils.UnmarshalPayload(wrapped.Payload)
+	if err != nil {
+		return nil, err
+	}
+	if wrappedPayload.Header == nil {
+		return nil, fmt.Errorf("missing header")
+	}
+	return utils.UnmarshalChannelHeader(wrappedPayload.Header.ChannelHeader)
+}
+
+func typeName(t int32) string {
+	if name, ok := cb.HeaderType_name[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("custom type %d", t)
+}
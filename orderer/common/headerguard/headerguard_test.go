@@ -0,0 +1,81 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package headerguard
+
+import (
+	"testing"
+
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+const systemChannelID = "system"
+
+func makeEnvelope(chdr *cb.ChannelHeader, data []byte) *cb.Envelope {
+	return &cb.Envelope{Payload: utils.MarshalOrPanic(&cb.Payload{
+		Header: &cb.Header{ChannelHeader: utils.MarshalOrPanic(chdr)},
+		Data:   data,
+	})}
+}
+
+func makeOrdererTransaction(wrapped *cb.ChannelHeader) (*cb.Envelope, *cb.ChannelHeader) {
+	chdr := &cb.ChannelHeader{ChannelId: systemChannelID, Type: int32(cb.HeaderType_ORDERER_TRANSACTION)}
+	return makeEnvelope(chdr, utils.MarshalOrPanic(makeEnvelope(wrapped, nil))), chdr
+}
+
+func assertClass(t *testing.T, class ab.BroadcastError_Class, err *Error) {
+	if assert.NotNil(t, err) {
+		assert.Equal(t, class, err.Class, err.Message)
+	}
+}
+
+func TestSubmitted(t *testing.T) {
+	g := New(systemChannelID, []cb.HeaderType{cb.HeaderType_ENDORSER_TRANSACTION, cb.HeaderType_CONFIG_UPDATE, cb.HeaderType(10000)})
+
+	assert.Nil(t, g.Submitted(&cb.ChannelHeader{ChannelId: "mychannel", Type: int32(cb.HeaderType_ENDORSER_TRANSACTION)}))
+	assert.Nil(t, g.Submitted(&cb.ChannelHeader{ChannelId: "mychannel", Type: int32(cb.HeaderType_CONFIG_UPDATE)}))
+	assert.Nil(t, g.Submitted(&cb.ChannelHeader{ChannelId: "mychannel", Type: 10000}), "Should allow the custom types configured")
+
+	assertClass(t, ab.BroadcastError_TYPE_NOT_ALLOWED, g.Submitted(&cb.ChannelHeader{ChannelId: "mychannel", Type: int32(cb.HeaderType_CONFIG)}))
+	assertClass(t, ab.BroadcastError_TYPE_NOT_ALLOWED, g.Submitted(&cb.ChannelHeader{ChannelId: "mychannel", Type: 10001}))
+	assertClass(t, ab.BroadcastError_INVALID_EPOCH, g.Submitted(&cb.ChannelHeader{ChannelId: "mychannel", Type: int32(cb.HeaderType_ENDORSER_TRANSACTION), Epoch: 3}))
+}
+
+func TestProcessedConfig(t *testing.T) {
+	g := New(systemChannelID, nil)
+
+	chdr := &cb.ChannelHeader{ChannelId: "mychannel", Type: int32(cb.HeaderType_CONFIG)}
+	assert.Nil(t, g.Processed("mychannel", makeEnvelope(chdr, nil), chdr))
+	assertClass(t, ab.BroadcastError_CHANNEL_MISMATCH, g.Processed("otherchannel", makeEnvelope(chdr, nil), chdr))
+
+	chdr = &cb.ChannelHeader{ChannelId: "mychannel", Type: int32(cb.HeaderType_CONFIG), Epoch: 1}
+	assertClass(t, ab.BroadcastError_INVALID_EPOCH, g.Processed("mychannel", makeEnvelope(chdr, nil), chdr))
+
+	chdr = &cb.ChannelHeader{ChannelId: "mychannel", Type: int32(cb.HeaderType_ENDORSER_TRANSACTION)}
+	assertClass(t, ab.BroadcastError_TYPE_NOT_ALLOWED, g.Processed("mychannel", makeEnvelope(chdr, nil), chdr))
+}
+
+func TestProcessedChannelCreation(t *testing.T) {
+	g := New(systemChannelID, nil)
+
+	env, chdr := makeOrdererTransaction(&cb.ChannelHeader{ChannelId: "newchannel", Type: int32(cb.HeaderType_CONFIG)})
+	assert.Nil(t, g.Processed("newchannel", env, chdr))
+	assertClass(t, ab.BroadcastError_CHANNEL_MISMATCH, g.Processed("otherchannel", env, chdr))
+
+	env, chdr = makeOrdererTransaction(&cb.ChannelHeader{ChannelId: "newchannel", Type: int32(cb.HeaderType_ENDORSER_TRANSACTION)})
+	assertClass(t, ab.BroadcastError_TYPE_NOT_ALLOWED, g.Processed("newchannel", env, chdr))
+
+	env, chdr = makeOrdererTransaction(&cb.ChannelHeader{ChannelId: "newchannel", Type: int32(cb.HeaderType_CONFIG)})
+	chdr.ChannelId = "newchannel"
+	assertClass(t, ab.BroadcastError_CHANNEL_MISMATCH, g.Processed("newchannel", env, chdr))
+
+	chdr = &cb.ChannelHeader{ChannelId: systemChannelID, Type: int32(cb.HeaderType_ORDERER_TRANSACTION)}
+	env = makeEnvelope(chdr, []byte("garbage"))
+	assertClass(t, ab.BroadcastError_MALFORMED, g.Processed("newchannel", env, chdr))
+}
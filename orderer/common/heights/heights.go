@@ -0,0 +1,208 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package heights
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/orderer/common/filter"
+	"github.com/hyperledger/fabric/orderer/common/sigfilter"
+	"github.com/hyperledger/fabric/orderer/ledger"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/op/go-logging"
+)
+
+var logger = logging.MustGetLogger("orderer/common/heights")
+
+// Handler defines an interface which handles the subscriptions to the heights of the ledgers
+type Handler interface {
+	Handle(env *cb.Envelope, srv ab.LedgerHeights_SubscribeServer) error
+}
+
+// SupportManager provides a way for the Handler to look up the Support for a chain, and to be notified of the
+// heights of the ledgers of the chains
+type SupportManager interface {
+	// GetChain returns the Support of the chain with the given ID, and whether it exists
+	GetChain(chainID string) (Support, bool)
+
+	// ChainIDs returns the IDs of the chains
+	ChainIDs() []string
+
+	// SubscribeHeights returns a channel on which the heights of the ledger of the chain with the given ID, or
+	// of all the chains if it is empty, are sent as blocks are written, and a function which unsubscribes. The
+	// channel is closed if the subscriber lags behind
+	SubscribeHeights(chainID string) (<-chan *ab.HeightNotification, func())
+}
+
+// Support provides the backing resources needed to notify the heights of the ledger of a chain
+type Support interface {
+	// Sequence returns the current config sequence number, can be used to detect config changes
+	Sequence() uint64
+
+	// PolicyManager returns the current policy manager as specified by the chain configuration
+	PolicyManager() policies.Manager
+
+	// Reader returns the chain Reader for the chain
+	Reader() ledger.Reader
+}
+
+type handlerImpl struct {
+	sm SupportManager
+}
+
+// NewHandlerImpl creates an implementation of the Handler interface
+func NewHandlerImpl(sm SupportManager) Handler {
+	return &handlerImpl{sm: sm}
+}
+
+// authorization is the outcome of the evaluation of the Readers policy of a chain, valid as long as its config
+// sequence does not change
+type authorization struct {
+	sequence uint64
+	allowed  bool
+}
+
+// subscription holds the state of a subscription to the heights of the ledgers of some chains, or of all the
+// chains the signer of the envelope may read if all is set
+type subscription struct {
+	sm             SupportManager
+	env            *cb.Envelope
+	srv            ab.LedgerHeights_SubscribeServer
+	all            bool
+	chainIDs       map[string]bool
+	authorizations map[string]authorization
+	heights        map[string]uint64
+}
+
+// Handle sends the current heights of the ledgers of the chains the envelope subscribes to, then the heights
+// notified as blocks are written, until the client hangs up. The stream ends with the gRPC status matching the
+// status the subscription failed with. A client lagging behind the notifications is dropped with
+// SERVICE_UNAVAILABLE, and should subscribe again.
+func (h *handlerImpl) Handle(env *cb.Envelope, srv ab.LedgerHeights_SubscribeServer) error {
+	payload, err := utils.UnmarshalPayload(env.Payload)
+	if err != nil {
+		logger.Warningf("Received an envelope with no payload: %s", err)
+		return terminate("", cb.Status_BAD_REQUEST)
+	}
+	request := &ab.HeightSubscription{}
+	if err := proto.Unmarshal(payload.Data, request); err != nil {
+		logger.Warningf("Received a height subscription with a malformed payload: %s", err)
+		return terminate("", cb.Status_BAD_REQUEST)
+	}
+
+	s := &subscription{
+		sm:             h.sm,
+		env:            env,
+		srv:            srv,
+		all:            len(request.ChannelIds) == 0,
+		chainIDs:       make(map[string]bool),
+		authorizations: make(map[string]authorization),
+		heights:        make(map[string]uint64),
+	}
+	subscribedID := ""
+	if len(request.ChannelIds) == 1 {
+		subscribedID = request.ChannelIds[0]
+	}
+	// Subscribed before reading the heights, so that no block written in between is missed
+	notifications, unsubscribe := h.sm.SubscribeHeights(subscribedID)
+	defer unsubscribe()
+
+	chainIDs := request.ChannelIds
+	if s.all {
+		chainIDs = h.sm.ChainIDs()
+	}
+	for _, chainID := range chainIDs {
+		s.chainIDs[chainID] = true
+		chain, ok := h.sm.GetChain(chainID)
+		if !ok {
+			if s.all {
+				continue
+			}
+			logger.Debugf("Rejecting height subscription because channel %s not found", chainID)
+			return terminate(chainID, cb.Status_NOT_FOUND)
+		}
+		if err := s.sendCurrent(chainID, chain); err != nil {
+			return err
+		}
+	}
+	logger.Debugf("Subscribed to the heights of channels %v", request.ChannelIds)
+
+	for {
+		select {
+		case notification, ok := <-notifications:
+			if !ok {
+				logger.Warningf("Dropped a height subscriber which lags behind")
+				return terminate("", cb.Status_SERVICE_UNAVAILABLE)
+			}
+			if err := s.send(notification); err != nil {
+				return err
+			}
+		case <-srv.Context().Done():
+			logger.Debugf("Height subscriber hung up")
+			return nil
+		}
+	}
+}
+
+// sendCurrent sends the current height of the ledger of the chain, and the hash of the header of its last block
+func (s *subscription) sendCurrent(chainID string, chain Support) error {
+	reader := chain.Reader()
+	height := reader.Height()
+	block := ledger.GetBlock(reader, height-1)
+	if block == nil {
+		logger.Errorf("[channel: %s] Could not read the last block of the ledger", chainID)
+		return terminate(chainID, cb.Status_INTERNAL_SERVER_ERROR)
+	}
+	return s.send(&ab.HeightNotification{ChannelId: chainID, Height: height, HeaderHash: block.Header.Hash()})
+}
+
+// send sends the notification if the subscription covers its chain, and if it is newer than the height sent
+// last for the chain
+func (s *subscription) send(notification *ab.HeightNotification) error {
+	chainID := notification.ChannelId
+	if (!s.all && !s.chainIDs[chainID]) || notification.Height <= s.heights[chainID] {
+		return nil
+	}
+	allowed, err := s.authorized(chainID)
+	if err != nil || !allowed {
+		return err
+	}
+	if err := s.srv.Send(notification); err != nil {
+		logger.Warningf("[channel: %s] Error sending to stream: %s", chainID, err)
+		return err
+	}
+	s.heights[chainID] = notification.Height
+	return nil
+}
+
+// authorized returns whether the signer of the envelope satisfies the Readers policy of the chain, evaluated
+// again whenever the config of the chain changes. The chains the signer may not read are skipped when subscribed
+// to all the chains, and end the subscription otherwise
+func (s *subscription) authorized(chainID string) (bool, error) {
+	chain, ok := s.sm.GetChain(chainID)
+	if !ok {
+		return false, nil
+	}
+	sequence := chain.Sequence()
+	if auth, ok := s.authorizations[chainID]; ok && auth.sequence == sequence {
+		return auth.allowed, nil
+	}
+	result, _ := sigfilter.New(policies.ChannelReaders, chain.PolicyManager()).Apply(s.env)
+	allowed := result == filter.Forward
+	s.authorizations[chainID] = authorization{sequence: sequence, allowed: allowed}
+	if !allowed && !s.all {
+		logger.Warningf("[channel: %s] Received unauthorized height subscription", chainID)
+		return false, terminate(chainID, cb.Status_FORBIDDEN)
+	}
+	return allowed, nil
+}
+
+func terminate(chainID string, status cb.Status) error {
+	return utils.NewErrorStatus(utils.HeightsErrorDetail(chainID, status), "height subscription failed with status "+status.String()).Err()
+}
@@ -0,0 +1,252 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package heights
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	mockpolicies "github.com/hyperledger/fabric/common/mocks/policies"
+	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/orderer/ledger"
+	ramledger "github.com/hyperledger/fabric/orderer/ledger/ram"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+const timeout = time.Second
+
+type mockStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent chan *ab.HeightNotification
+}
+
+func newMockStream() (*mockStream, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &mockStream{ctx: ctx, sent: make(chan *ab.HeightNotification, 10)}, cancel
+}
+
+func (m *mockStream) Context() context.Context {
+	return m.ctx
+}
+
+func (m *mockStream) Send(notification *ab.HeightNotification) error {
+	m.sent <- notification
+	return nil
+}
+
+func (m *mockStream) next(t *testing.T) *ab.HeightNotification {
+	select {
+	case notification := <-m.sent:
+		return notification
+	case <-time.After(timeout):
+		t.Fatal("Expected a height notification")
+		return nil
+	}
+}
+
+type mockSupport struct {
+	ledger        ledger.ReadWriter
+	policyManager *mockpolicies.Manager
+	configSeq     uint64
+}
+
+func (ms *mockSupport) Sequence() uint64 {
+	return ms.configSeq
+}
+
+func (ms *mockSupport) PolicyManager() policies.Manager {
+	return ms.policyManager
+}
+
+func (ms *mockSupport) Reader() ledger.Reader {
+	return ms.ledger
+}
+
+type mockSupportManager struct {
+	lock          sync.Mutex
+	chains        map[string]*mockSupport
+	notifications chan *ab.HeightNotification
+	subscribed    chan string
+}
+
+func newMockSupportManager() *mockSupportManager {
+	return &mockSupportManager{
+		chains:        make(map[string]*mockSupport),
+		notifications: make(chan *ab.HeightNotification),
+		subscribed:    make(chan string, 1),
+	}
+}
+
+// addChain adds a chain whose ledger holds the given number of blocks
+func (mm *mockSupportManager) addChain(chainID string, height int, policyErr error) *mockSupport {
+	rl, _ := ramledger.New(10).GetOrCreate(chainID)
+	rl.Append(cb.NewBlock(0, nil))
+	for i := 1; i < height; i++ {
+		rl.Append(ledger.CreateNextBlock(rl, []*cb.Envelope{{Payload: []byte(fmt.Sprintf("%d", i))}}))
+	}
+	chain := &mockSupport{
+		ledger:        rl,
+		policyManager: &mockpolicies.Manager{Policy: &mockpolicies.Policy{Err: policyErr}},
+	}
+	mm.lock.Lock()
+	mm.chains[chainID] = chain
+	mm.lock.Unlock()
+	return chain
+}
+
+func (mm *mockSupportManager) GetChain(chainID string) (Support, bool) {
+	mm.lock.Lock()
+	defer mm.lock.Unlock()
+	chain, ok := mm.chains[chainID]
+	return chain, ok
+}
+
+func (mm *mockSupportManager) ChainIDs() []string {
+	mm.lock.Lock()
+	defer mm.lock.Unlock()
+	var chainIDs []string
+	for chainID := range mm.chains {
+		chainIDs = append(chainIDs, chainID)
+	}
+	return chainIDs
+}
+
+func (mm *mockSupportManager) SubscribeHeights(chainID string) (<-chan *ab.HeightNotification, func()) {
+	mm.subscribed <- chainID
+	return mm.notifications, func() {}
+}
+
+func makeSubscription(chainIDs ...string) *cb.Envelope {
+	return &cb.Envelope{
+		Payload: utils.MarshalOrPanic(&cb.Payload{
+			Header: &cb.Header{
+				ChannelHeader:   utils.MarshalOrPanic(&cb.ChannelHeader{}),
+				SignatureHeader: utils.MarshalOrPanic(&cb.SignatureHeader{}),
+			},
+			Data: utils.MarshalOrPanic(&ab.HeightSubscription{ChannelIds: chainIDs}),
+		}),
+	}
+}
+
+func lastHeaderHash(chain *mockSupport) []byte {
+	return ledger.GetBlock(chain.ledger, chain.ledger.Height()-1).Header.Hash()
+}
+
+func TestSubscribeChannel(t *testing.T) {
+	mm := newMockSupportManager()
+	foo := mm.addChain("foo", 3, nil)
+	mm.addChain("bar", 1, nil)
+	stream, cancel := newMockStream()
+	done := make(chan error)
+	go func() {
+		done <- NewHandlerImpl(mm).Handle(makeSubscription("foo"), stream)
+	}()
+
+	assert.Equal(t, "foo", <-mm.subscribed, "Expected a single channel to be subscribed to alone")
+	assert.Equal(t, &ab.HeightNotification{ChannelId: "foo", Height: 3, HeaderHash: lastHeaderHash(foo)}, stream.next(t),
+		"Expected the current height to be sent first")
+
+	// Written before the current height was read
+	mm.notifications <- &ab.HeightNotification{ChannelId: "foo", Height: 3}
+	mm.notifications <- &ab.HeightNotification{ChannelId: "bar", Height: 2}
+	next := &ab.HeightNotification{ChannelId: "foo", Height: 4, HeaderHash: []byte("hash")}
+	mm.notifications <- next
+	assert.Equal(t, next, stream.next(t), "Expected the notifications of the heights sent already and of the other channels to be skipped")
+
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+func TestSubscribeAll(t *testing.T) {
+	mm := newMockSupportManager()
+	foo := mm.addChain("foo", 2, nil)
+	mm.addChain("bar", 2, fmt.Errorf("forbidden"))
+	stream, cancel := newMockStream()
+	defer cancel()
+	go NewHandlerImpl(mm).Handle(makeSubscription(), stream)
+
+	assert.Equal(t, "", <-mm.subscribed)
+	assert.Equal(t, &ab.HeightNotification{ChannelId: "foo", Height: 2, HeaderHash: lastHeaderHash(foo)}, stream.next(t),
+		"Expected the channels which cannot be read to be skipped")
+
+	mm.notifications <- &ab.HeightNotification{ChannelId: "bar", Height: 3}
+	mm.addChain("baz", 1, nil)
+	created := &ab.HeightNotification{ChannelId: "baz", Height: 1}
+	mm.notifications <- created
+	assert.Equal(t, created, stream.next(t), "Expected the channels created after subscribing to be notified")
+}
+
+func TestSubscribeFailures(t *testing.T) {
+	mm := newMockSupportManager()
+	foo := mm.addChain("foo", 1, nil)
+	mm.addChain("bar", 1, fmt.Errorf("forbidden"))
+
+	handle := func(env *cb.Envelope) (*mockStream, chan error) {
+		stream, _ := newMockStream()
+		done := make(chan error, 1)
+		go func() {
+			done <- NewHandlerImpl(mm).Handle(env, stream)
+		}()
+		<-mm.subscribed
+		return stream, done
+	}
+	code := func(done chan error) codes.Code {
+		select {
+		case err := <-done:
+			return grpc.Code(err)
+		case <-time.After(timeout):
+			t.Fatal("Expected the subscription to end")
+			return codes.OK
+		}
+	}
+
+	t.Run("MalformedPayload", func(t *testing.T) {
+		err := NewHandlerImpl(mm).Handle(&cb.Envelope{Payload: []byte("garbage")}, nil)
+		assert.Equal(t, codes.InvalidArgument, grpc.Code(err))
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		_, done := handle(makeSubscription("foo", "missing"))
+		assert.Equal(t, codes.NotFound, code(done))
+	})
+
+	t.Run("Forbidden", func(t *testing.T) {
+		_, done := handle(makeSubscription("bar"))
+		assert.Equal(t, codes.PermissionDenied, code(done))
+	})
+
+	t.Run("Revoked", func(t *testing.T) {
+		stream, done := handle(makeSubscription("foo"))
+		stream.next(t)
+		foo.policyManager.Policy = &mockpolicies.Policy{Err: fmt.Errorf("revoked")}
+		foo.configSeq++
+		mm.notifications <- &ab.HeightNotification{ChannelId: "foo", Height: 2}
+		assert.Equal(t, codes.PermissionDenied, code(done), "Expected the policy to be evaluated again once the config changed")
+	})
+
+	t.Run("LaggingBehind", func(t *testing.T) {
+		mm.addChain("foo", 1, nil)
+		stream, done := handle(makeSubscription("foo"))
+		stream.next(t)
+		close(mm.notifications)
+		err := <-done
+		require.Equal(t, codes.Unavailable, grpc.Code(err))
+		detail, ok := utils.GetErrorDetail(err)
+		require.True(t, ok)
+		assert.True(t, detail.Retriable, "Expected a dropped subscriber to subscribe again")
+	})
+}
@@ -0,0 +1,69 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package maintenancefilter
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric/common/config"
+	"github.com/hyperledger/fabric/orderer/common/filter"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+	logging "github.com/op/go-logging"
+)
+
+var logger = logging.MustGetLogger("orderer/common/maintenancefilter")
+
+// Support defines the subset of the channel support required to create this filter
+type Support interface {
+	ConsensusState() ab.ConsensusType_State
+	HasCapability(capability string) bool
+}
+
+// InMaintenance returns whether the channel is in maintenance mode. An orderer
+// predating the maintenance state ignores it, so it only applies once the
+// Maintenance capability of the channel is enabled
+func InMaintenance(support Support) bool {
+	return support.HasCapability(config.MaintenanceCapability) && support.ConsensusState() == ab.ConsensusType_STATE_MAINTENANCE
+}
+
+// New creates a rule which, while the channel is in maintenance mode, rejects
+// all the messages but the config transactions
+func New(support Support) filter.Rule {
+	return &maintenanceRule{support: support}
+}
+
+type maintenanceRule struct {
+	support Support
+}
+
+func (r *maintenanceRule) Apply(message *cb.Envelope) (filter.Action, filter.Committer) {
+	action, committer, _ := r.ApplyAndExplain(message)
+	return action, committer
+}
+
+// ApplyAndExplain applies the rule, and returns the reason why a message is rejected
+func (r *maintenanceRule) ApplyAndExplain(message *cb.Envelope) (filter.Action, filter.Committer, error) {
+	if !InMaintenance(r.support) {
+		return filter.Forward, nil, nil
+	}
+
+	payload, err := utils.UnmarshalPayload(message.Payload)
+	if err != nil || payload.Header == nil {
+		return filter.Reject, nil, fmt.Errorf("the channel is in maintenance mode")
+	}
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return filter.Reject, nil, fmt.Errorf("the channel is in maintenance mode")
+	}
+	if chdr.Type != int32(cb.HeaderType_CONFIG) {
+		logger.Debugf("[channel: %s] Rejecting message of type %s as the channel is in maintenance mode", chdr.ChannelId, cb.HeaderType_name[chdr.Type])
+		return filter.Reject, nil, fmt.Errorf("the channel is in maintenance mode, only config updates are accepted")
+	}
+	return filter.Forward, nil, nil
+}
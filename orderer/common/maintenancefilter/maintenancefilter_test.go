@@ -0,0 +1,61 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package maintenancefilter
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/common/config"
+	mockconfig "github.com/hyperledger/fabric/common/mocks/config"
+	"github.com/hyperledger/fabric/orderer/common/filter"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func makeMessage(headerType cb.HeaderType) *cb.Envelope {
+	payload := &cb.Payload{
+		Header: &cb.Header{ChannelHeader: utils.MarshalOrPanic(&cb.ChannelHeader{Type: int32(headerType), ChannelId: "foo"})},
+	}
+	return &cb.Envelope{Payload: utils.MarshalOrPanic(payload)}
+}
+
+func TestMaintenanceRule(t *testing.T) {
+	support := &mockconfig.Orderer{}
+	rs := filter.NewRuleSet([]filter.Rule{New(support), filter.AcceptRule})
+
+	t.Run("Normal", func(t *testing.T) {
+		_, err := rs.Apply(makeMessage(cb.HeaderType_ENDORSER_TRANSACTION))
+		assert.NoError(t, err)
+		_, err = rs.Apply(&cb.Envelope{Payload: []byte("garbage")})
+		assert.NoError(t, err, "Should have left the malformed messages to the other rules")
+	})
+
+	support.ConsensusStateVal = ab.ConsensusType_STATE_MAINTENANCE
+
+	t.Run("WithoutCapability", func(t *testing.T) {
+		_, err := rs.Apply(makeMessage(cb.HeaderType_ENDORSER_TRANSACTION))
+		assert.NoError(t, err, "Should have ignored the maintenance state without the Maintenance capability")
+	})
+
+	support.CapabilitiesVal = []string{config.MaintenanceCapability}
+
+	t.Run("Config", func(t *testing.T) {
+		_, err := rs.Apply(makeMessage(cb.HeaderType_CONFIG))
+		assert.NoError(t, err)
+	})
+
+	t.Run("Rejected", func(t *testing.T) {
+		for _, headerType := range []cb.HeaderType{cb.HeaderType_ENDORSER_TRANSACTION, cb.HeaderType_ORDERER_TRANSACTION, cb.HeaderType_MESSAGE} {
+			_, err := rs.Apply(makeMessage(headerType))
+			assert.Error(t, err, "Should have rejected a message of type %s", headerType)
+		}
+		_, err := rs.Apply(&cb.Envelope{Payload: []byte("garbage")})
+		assert.Error(t, err)
+	})
+}
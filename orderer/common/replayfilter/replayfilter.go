@@ -0,0 +1,123 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package replayfilter
+
+import (
+	"sync"
+
+	"github.com/hyperledger/fabric/orderer/common/filter"
+	"github.com/hyperledger/fabric/orderer/ledger"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	logging "github.com/op/go-logging"
+)
+
+var logger = logging.MustGetLogger("orderer/common/replayfilter")
+
+// Window tracks the IDs of the most recently committed transactions of a chain
+type Window struct {
+	lock sync.RWMutex
+	ids  map[string]int
+	ring []string
+	next int
+}
+
+// NewWindow creates a window which retains the IDs of the last size committed transactions
+func NewWindow(size int) *Window {
+	return &Window{
+		ids:  make(map[string]int),
+		ring: make([]string, size),
+	}
+}
+
+// Load fills the window from the most recent blocks of the ledger, so that it survives restarts
+func (w *Window) Load(rl ledger.Reader) {
+	var blocks []*cb.Block
+	numTxs := 0
+	for number := rl.Height(); number > 0 && numTxs < len(w.ring); number-- {
+		block := ledger.GetBlock(rl, number-1)
+		if block == nil || block.Data == nil {
+			break
+		}
+		blocks = append(blocks, block)
+		numTxs += len(block.Data.Data)
+	}
+	for i := len(blocks) - 1; i >= 0; i-- {
+		w.AddBlock(blocks[i])
+	}
+	logger.Debugf("Loaded %d transaction IDs from the last %d blocks", len(w.ids), len(blocks))
+}
+
+// AddBlock adds the IDs of the transactions of a committed block to the window, evicting the oldest ones
+func (w *Window) AddBlock(block *cb.Block) {
+	if block.Data == nil {
+		return
+	}
+	for _, envBytes := range block.Data.Data {
+		env, err := utils.UnmarshalEnvelope(envBytes)
+		if err != nil {
+			continue
+		}
+		if txID := txID(env); txID != "" {
+			w.add(txID)
+		}
+	}
+}
+
+func (w *Window) add(txID string) {
+	if len(w.ring) == 0 {
+		return
+	}
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if evicted := w.ring[w.next]; evicted != "" {
+		if w.ids[evicted]--; w.ids[evicted] == 0 {
+			delete(w.ids, evicted)
+		}
+	}
+	w.ring[w.next] = txID
+	w.ids[txID]++
+	w.next = (w.next + 1) % len(w.ring)
+}
+
+// Contains returns whether the transaction ID is in the window
+func (w *Window) Contains(txID string) bool {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+	_, ok := w.ids[txID]
+	return ok
+}
+
+func txID(env *cb.Envelope) string {
+	payload, err := utils.UnmarshalPayload(env.Payload)
+	if err != nil || payload.Header == nil {
+		return ""
+	}
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return ""
+	}
+	return chdr.TxId
+}
+
+// New creates a rule which rejects the messages whose transaction ID is in the window of recently committed
+// transactions, so that envelopes resubmitted by clients do not make it to a block once again
+func New(window *Window) filter.Rule {
+	return &replayRule{window: window}
+}
+
+type replayRule struct {
+	window *Window
+}
+
+func (r *replayRule) Apply(message *cb.Envelope) (filter.Action, filter.Committer) {
+	if txID := txID(message); txID != "" && r.window.Contains(txID) {
+		logger.Warningf("Rejecting message with transaction ID %s which was already committed", txID)
+		return filter.Reject, nil
+	}
+	return filter.Forward, nil
+}
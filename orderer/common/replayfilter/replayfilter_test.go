@@ -0,0 +1,82 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package replayfilter
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/fabric/orderer/common/filter"
+	"github.com/hyperledger/fabric/orderer/ledger"
+	ramledger "github.com/hyperledger/fabric/orderer/ledger/ram"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func makeTx(txID string) *cb.Envelope {
+	return &cb.Envelope{Payload: utils.MarshalOrPanic(&cb.Payload{
+		Header: &cb.Header{ChannelHeader: utils.MarshalOrPanic(&cb.ChannelHeader{ChannelId: "foo", TxId: txID})},
+	})}
+}
+
+func makeBlock(number uint64, txIDs ...string) *cb.Block {
+	block := cb.NewBlock(number, nil)
+	for _, txID := range txIDs {
+		block.Data.Data = append(block.Data.Data, utils.MarshalOrPanic(makeTx(txID)))
+	}
+	return block
+}
+
+func TestReplayRule(t *testing.T) {
+	window := NewWindow(3)
+	rule := New(window)
+	window.AddBlock(makeBlock(0, "tx1", "tx2", ""))
+
+	action, _ := rule.Apply(makeTx("tx1"))
+	assert.EqualValues(t, filter.Reject, action, "Should have rejected a committed transaction")
+	action, _ = rule.Apply(makeTx("tx3"))
+	assert.EqualValues(t, filter.Forward, action)
+	action, _ = rule.Apply(makeTx(""))
+	assert.EqualValues(t, filter.Forward, action, "Should not filter messages without a transaction ID")
+	action, _ = rule.Apply(&cb.Envelope{Payload: []byte("garbage")})
+	assert.EqualValues(t, filter.Forward, action, "Should leave malformed messages to the other rules")
+
+	// The oldest transaction IDs are evicted from the window
+	window.AddBlock(makeBlock(1, "tx3", "tx4"))
+	assert.False(t, window.Contains("tx1"))
+	assert.True(t, window.Contains("tx2"))
+	assert.True(t, window.Contains("tx3"))
+	assert.True(t, window.Contains("tx4"))
+
+	// Duplicates within the window are retained until their last occurrence is evicted
+	window.AddBlock(makeBlock(2, "tx4"))
+	window.AddBlock(makeBlock(3, "tx5", "tx6"))
+	assert.True(t, window.Contains("tx4"))
+	assert.False(t, window.Contains("tx3"))
+	assert.Len(t, window.ids, 3)
+}
+
+func TestWindowLoad(t *testing.T) {
+	rlf := ramledger.New(10)
+	rl, _ := rlf.GetOrCreate("foo")
+	rl.Append(makeBlock(0))
+	for i := 1; i < 5; i++ {
+		rl.Append(ledger.CreateNextBlock(rl, []*cb.Envelope{makeTx(fmt.Sprintf("tx%d-0", i)), makeTx(fmt.Sprintf("tx%d-1", i))}))
+	}
+
+	window := NewWindow(5)
+	window.Load(rl)
+	for i := 3; i < 5; i++ {
+		assert.True(t, window.Contains(fmt.Sprintf("tx%d-0", i)))
+		assert.True(t, window.Contains(fmt.Sprintf("tx%d-1", i)))
+	}
+	assert.True(t, window.Contains("tx2-1"))
+	assert.False(t, window.Contains("tx2-0"), "Should have retained only the last transactions")
+	assert.False(t, window.Contains("tx1-1"), "Should not have read blocks beyond the window")
+	assert.Len(t, window.ids, 5)
+}
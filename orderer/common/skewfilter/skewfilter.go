@@ -0,0 +1,73 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package skewfilter
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/orderer/common/filter"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	logging "github.com/op/go-logging"
+	gometrics "github.com/rcrowley/go-metrics"
+)
+
+var logger = logging.MustGetLogger("orderer/common/skewfilter")
+
+// New creates a rule which rejects the messages whose channel header timestamp is further than window from the
+// clock of the orderer, in the past or in the future, or which only measures the skew of the messages if window is
+// 0. Messages without a timestamp are forwarded. The skew, the clock of the orderer minus the timestamp, is
+// published as the orderer.clock_skew_ms histogram and the number of messages rejected as the
+// orderer.clock_skew_rejections metric.
+//
+// As the result depends on the clock of the orderer, the rule must only be applied when the messages are
+// broadcasted, and never when they are ordered
+func New(window time.Duration) filter.Rule {
+	return &skewRule{
+		window:     window,
+		now:        time.Now,
+		skew:       gometrics.GetOrRegisterHistogram("orderer.clock_skew_ms", metrics.Registry, gometrics.NewExpDecaySample(1028, 0.015)),
+		rejections: gometrics.GetOrRegisterCounter("orderer.clock_skew_rejections", metrics.Registry),
+	}
+}
+
+type skewRule struct {
+	window     time.Duration
+	now        func() time.Time
+	skew       gometrics.Histogram
+	rejections gometrics.Counter
+}
+
+func (r *skewRule) Apply(message *cb.Envelope) (filter.Action, filter.Committer) {
+	action, committer, _ := r.ApplyAndExplain(message)
+	return action, committer
+}
+
+// ApplyAndExplain applies the rule, and returns the reason why a message is rejected
+func (r *skewRule) ApplyAndExplain(message *cb.Envelope) (filter.Action, filter.Committer, error) {
+	payload, err := utils.UnmarshalPayload(message.Payload)
+	if err != nil || payload.Header == nil {
+		return filter.Forward, nil, nil
+	}
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil || chdr.Timestamp == nil {
+		return filter.Forward, nil, nil
+	}
+
+	timestamp := time.Unix(chdr.Timestamp.Seconds, int64(chdr.Timestamp.Nanos))
+	skew := r.now().Sub(timestamp)
+	r.skew.Update(int64(skew / time.Millisecond))
+	if r.window != 0 && (skew > r.window || skew < -r.window) {
+		r.rejections.Inc(1)
+		logger.Debugf("[channel: %s] Rejecting message with timestamp %s skewed by %s", chdr.ChannelId, timestamp, skew)
+		return filter.Reject, nil, fmt.Errorf("the timestamp %s is skewed by %s from the clock of the orderer, beyond %s",
+			timestamp.UTC().Format(time.RFC3339), skew, r.window)
+	}
+	return filter.Forward, nil, nil
+}
@@ -0,0 +1,61 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package skewfilter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/hyperledger/fabric/orderer/common/filter"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+var now = time.Unix(1500000000, 0)
+
+func makeMessage(ts *timestamp.Timestamp) *cb.Envelope {
+	payload := &cb.Payload{
+		Header: &cb.Header{ChannelHeader: utils.MarshalOrPanic(&cb.ChannelHeader{ChannelId: "foo", Timestamp: ts})},
+	}
+	return &cb.Envelope{Payload: utils.MarshalOrPanic(payload)}
+}
+
+func makeMessageAt(t time.Time) *cb.Envelope {
+	return makeMessage(&timestamp.Timestamp{Seconds: t.Unix(), Nanos: int32(t.Nanosecond())})
+}
+
+func TestSkewRule(t *testing.T) {
+	rule := New(time.Minute)
+	rule.(*skewRule).now = func() time.Time { return now }
+	rs := filter.NewRuleSet([]filter.Rule{rule, filter.AcceptRule})
+
+	for _, skew := range []time.Duration{0, 59 * time.Second, -59 * time.Second, time.Minute} {
+		_, err := rs.Apply(makeMessageAt(now.Add(skew)))
+		assert.NoError(t, err, "Should have accepted a message skewed by %s", skew)
+	}
+
+	for _, skew := range []time.Duration{61 * time.Second, -61 * time.Second, -24 * time.Hour} {
+		_, err := rs.Apply(makeMessageAt(now.Add(skew)))
+		assert.Error(t, err, "Should have rejected a message skewed by %s", skew)
+	}
+
+	_, err := rs.Apply(makeMessage(nil))
+	assert.NoError(t, err, "Should have forwarded a message without timestamp")
+	_, err = rs.Apply(&cb.Envelope{Payload: []byte("garbage")})
+	assert.NoError(t, err, "Should have left the malformed messages to the other rules")
+}
+
+func TestSkewRuleMeasureOnly(t *testing.T) {
+	rule := New(0)
+	rule.(*skewRule).now = func() time.Time { return now }
+	rs := filter.NewRuleSet([]filter.Rule{rule, filter.AcceptRule})
+
+	_, err := rs.Apply(makeMessageAt(now.Add(-24 * time.Hour)))
+	assert.NoError(t, err, "Should only have measured the skew")
+}
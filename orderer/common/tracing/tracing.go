@@ -0,0 +1,253 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package tracing follows broadcasted envelopes through the orderer. Each envelope is assigned a trace
+// ID when it is broadcast, which is carried along with the envelope through the consenter, recorded
+// in the TRACE_IDS metadata of the block the envelope is cut into, and indexed so that the block and
+// the position of the envelope can be looked up from the trace ID. The times at which the envelopes
+// were received and cut into their block are recorded along with their trace IDs, so that the peers
+// measure the latency of the transactions from their receipt by the orderer to their commit. A block
+// only carries the TRACE_IDS metadata when at least one of its envelopes has a trace ID.
+package tracing
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/orderer/ledger"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+	logging "github.com/op/go-logging"
+	gometrics "github.com/rcrowley/go-metrics"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/metadata"
+)
+
+var logger = logging.MustGetLogger("orderer/common/tracing")
+
+// TraceIDKey is the gRPC metadata key with which a client supplies the trace ID of the envelopes it
+// broadcasts on a stream, instead of letting the orderer generate one for each envelope
+const TraceIDKey = "trace-id"
+
+// NewID generates a random trace ID
+func NewID() string {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		logger.Panicf("Failed generating trace ID: %s", err)
+	}
+	return hex.EncodeToString(id)
+}
+
+// FromContext returns the trace ID supplied in the gRPC metadata of a stream, or the empty string
+func FromContext(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	for _, traceID := range md[TraceIDKey] {
+		if traceID != "" {
+			return traceID
+		}
+	}
+	return ""
+}
+
+// Pending holds the trace IDs and the receipt times of the envelopes of a chain which are being ordered,
+// until the envelopes are cut into a block. The envelopes are identified by the hash of their content, as
+// the consenter may hand a copy of an envelope to the blockcutter, and the traces of identical envelopes
+// are matched in the order they were set. The time from the receipt of the envelopes to the cut of their
+// block is published as the orderer.latency.<chain>.ordering_ms histogram
+type Pending struct {
+	lock     sync.Mutex
+	traces   map[string][]pendingTrace
+	now      func() time.Time
+	ordering gometrics.Histogram
+}
+
+type pendingTrace struct {
+	id       string
+	received time.Time
+}
+
+// NewPending creates an empty set of pending traces of a chain
+func NewPending(chainID string) *Pending {
+	return &Pending{
+		traces:   make(map[string][]pendingTrace),
+		now:      time.Now,
+		ordering: gometrics.GetOrRegisterHistogram("orderer.latency."+chainID+".ordering_ms", metrics.Registry, gometrics.NewExpDecaySample(1028, 0.015)),
+	}
+}
+
+// Set records the trace ID of an envelope about to be ordered, and the time at which it was received,
+// which is zero when unknown
+func (p *Pending) Set(env *cb.Envelope, traceID string, received time.Time) {
+	if traceID == "" && received.IsZero() {
+		return
+	}
+	key := envelopeKey(env)
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.traces[key] = append(p.traces[key], pendingTrace{id: traceID, received: received})
+}
+
+// Forget drops the trace of an envelope which will not be cut into a block
+func (p *Pending) Forget(env *cb.Envelope) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.take(envelopeKey(env))
+}
+
+// take removes and returns the oldest trace of the envelopes with the given key
+func (p *Pending) take(key string) (pendingTrace, bool) {
+	traces, ok := p.traces[key]
+	if !ok {
+		return pendingTrace{}, false
+	}
+	if len(traces) > 1 {
+		p.traces[key] = traces[1:]
+	} else {
+		delete(p.traces, key)
+	}
+	return traces[0], true
+}
+
+// envelopeKey identifies an envelope by the hash of its payload and signature
+func envelopeKey(env *cb.Envelope) string {
+	h := sha256.New()
+	size := make([]byte, 8)
+	binary.BigEndian.PutUint64(size, uint64(len(env.Payload)))
+	h.Write(size)
+	h.Write(env.Payload)
+	h.Write(env.Signature)
+	return string(h.Sum(nil))
+}
+
+// Metadata removes the traces of the envelopes of a batch being cut and returns the encoded value of the
+// TRACE_IDS metadata of their block, or nil when none of them has a trace ID. The receipt times of the
+// envelopes without a trace ID only feed the ordering latency histogram
+func (p *Pending) Metadata(batch []*cb.Envelope) []byte {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	ordered := p.now()
+	traceIDs := make([]string, len(batch))
+	receivedAt := make([]int64, len(batch))
+	traced := false
+	for i, env := range batch {
+		trace, ok := p.take(envelopeKey(env))
+		if !ok {
+			continue
+		}
+		traceIDs[i] = trace.id
+		if !trace.received.IsZero() {
+			receivedAt[i] = trace.received.UnixNano()
+			p.ordering.Update(int64(ordered.Sub(trace.received) / time.Millisecond))
+		}
+		if trace.id != "" {
+			traced = true
+		}
+	}
+	if !traced {
+		return nil
+	}
+	return utils.MarshalOrPanic(&cb.Metadata{
+		Value: utils.MarshalOrPanic(&ab.TraceMetadata{
+			TraceIds:   traceIDs,
+			ReceivedAt: receivedAt,
+			OrderedAt:  ordered.UnixNano(),
+		}),
+	})
+}
+
+// BlockTraceIDs returns the trace IDs recorded in the TRACE_IDS metadata of a block, or nil when the
+// block has none
+func BlockTraceIDs(block *cb.Block) ([]string, error) {
+	traces, err := utils.GetTraceMetadataFromBlock(block)
+	if err != nil || traces == nil {
+		return nil, err
+	}
+	return traces.TraceIds, nil
+}
+
+// Index locates the most recently committed traced envelopes of a chain by their trace ID. A trace
+// ID supplied by a client may be shared by several envelopes
+type Index struct {
+	lock      sync.RWMutex
+	positions map[string][]*ab.TracePosition
+	ring      []string
+	next      int
+}
+
+// NewIndex creates an index which retains the positions of the last size traced envelopes
+func NewIndex(size int) *Index {
+	return &Index{
+		positions: make(map[string][]*ab.TracePosition),
+		ring:      make([]string, size),
+	}
+}
+
+// Load fills the index from the most recent blocks of the ledger, so that it survives restarts
+func (idx *Index) Load(rl ledger.Reader) {
+	var blocks []*cb.Block
+	numTxs := 0
+	for number := rl.Height(); number > 0 && numTxs < len(idx.ring); number-- {
+		block := ledger.GetBlock(rl, number-1)
+		if block == nil || block.Data == nil {
+			break
+		}
+		blocks = append(blocks, block)
+		numTxs += len(block.Data.Data)
+	}
+	for i := len(blocks) - 1; i >= 0; i-- {
+		idx.AddBlock(blocks[i])
+	}
+	logger.Debugf("Loaded %d trace IDs from the last %d blocks", len(idx.positions), len(blocks))
+}
+
+// AddBlock indexes the traced envelopes of a committed block, evicting the oldest ones
+func (idx *Index) AddBlock(block *cb.Block) {
+	traceIDs, err := BlockTraceIDs(block)
+	if err != nil {
+		logger.Warningf("Not indexing the trace IDs of block %d: %s", block.Header.Number, err)
+		return
+	}
+	for i, traceID := range traceIDs {
+		if traceID != "" {
+			idx.add(&ab.TracePosition{TraceId: traceID, BlockNumber: block.Header.Number, TxIndex: uint64(i)})
+		}
+	}
+}
+
+func (idx *Index) add(position *ab.TracePosition) {
+	if len(idx.ring) == 0 {
+		return
+	}
+	idx.lock.Lock()
+	defer idx.lock.Unlock()
+	// Positions are added in order, so the evicted one is the oldest of its trace ID
+	if evicted := idx.ring[idx.next]; evicted != "" {
+		if positions := idx.positions[evicted][1:]; len(positions) > 0 {
+			idx.positions[evicted] = positions
+		} else {
+			delete(idx.positions, evicted)
+		}
+	}
+	idx.ring[idx.next] = position.TraceId
+	idx.positions[position.TraceId] = append(idx.positions[position.TraceId], position)
+	idx.next = (idx.next + 1) % len(idx.ring)
+}
+
+// Lookup returns the positions of the envelopes with the given trace ID, oldest first
+func (idx *Index) Lookup(traceID string) []*ab.TracePosition {
+	idx.lock.RLock()
+	defer idx.lock.RUnlock()
+	return append([]*ab.TracePosition(nil), idx.positions[traceID]...)
+}
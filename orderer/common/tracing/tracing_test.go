@@ -0,0 +1,170 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tracing
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/orderer/ledger"
+	ramledger "github.com/hyperledger/fabric/orderer/ledger/ram"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+	gometrics "github.com/rcrowley/go-metrics"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/metadata"
+)
+
+func makeBlock(number uint64, traceIDs ...string) *cb.Block {
+	block := cb.NewBlock(number, nil)
+	pending := NewPending("mychannel")
+	var batch []*cb.Envelope
+	for _, traceID := range traceIDs {
+		env := &cb.Envelope{Payload: []byte(traceID)}
+		pending.Set(env, traceID, time.Time{})
+		batch = append(batch, env)
+		block.Data.Data = append(block.Data.Data, utils.MarshalOrPanic(env))
+	}
+	block.Metadata.Metadata[cb.BlockMetadataIndex_TRACE_IDS] = pending.Metadata(batch)
+	return block
+}
+
+func TestNewID(t *testing.T) {
+	id := NewID()
+	assert.Len(t, id, 32)
+	assert.NotEqual(t, id, NewID(), "Should generate distinct IDs")
+}
+
+func TestFromContext(t *testing.T) {
+	assert.Empty(t, FromContext(context.Background()))
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(TraceIDKey, "trace1"))
+	assert.Equal(t, "trace1", FromContext(ctx))
+}
+
+func TestPending(t *testing.T) {
+	pending := NewPending("mychannel")
+	env1, env2, env3 := &cb.Envelope{Payload: []byte("1")}, &cb.Envelope{Payload: []byte("2")}, &cb.Envelope{Payload: []byte("3")}
+	pending.Set(env1, "trace1", time.Time{})
+	pending.Set(env2, "", time.Time{})
+	pending.Set(env3, "trace3", time.Time{})
+	pending.Forget(env3)
+
+	assert.Nil(t, pending.Metadata([]*cb.Envelope{env2, env3}), "Should not write metadata for untraced batches")
+
+	block := cb.NewBlock(0, nil)
+	block.Metadata.Metadata[cb.BlockMetadataIndex_TRACE_IDS] = pending.Metadata([]*cb.Envelope{env2, env1})
+	traceIDs, err := BlockTraceIDs(block)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"", "trace1"}, traceIDs)
+
+	assert.Nil(t, pending.Metadata([]*cb.Envelope{env1}), "Should have removed the trace IDs of the cut envelopes")
+}
+
+func TestPendingByContent(t *testing.T) {
+	pending := NewPending("mychannel")
+	pending.Set(&cb.Envelope{Payload: []byte("payload"), Signature: []byte("signature")}, "trace1", time.Time{})
+	pending.Set(&cb.Envelope{Payload: []byte("payload"), Signature: []byte("signature")}, "trace2", time.Time{})
+	pending.Set(&cb.Envelope{Payload: []byte("payloadsig"), Signature: []byte("nature")}, "trace3", time.Time{})
+
+	// The consenter may hand copies of the envelopes to the blockcutter
+	env := &cb.Envelope{Payload: []byte("payload"), Signature: []byte("signature")}
+	block := cb.NewBlock(0, nil)
+	block.Metadata.Metadata[cb.BlockMetadataIndex_TRACE_IDS] = pending.Metadata([]*cb.Envelope{env, env})
+	traceIDs, err := BlockTraceIDs(block)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"trace1", "trace2"}, traceIDs, "Should match the traces of identical envelopes in order")
+	assert.Empty(t, pending.traces[envelopeKey(env)])
+	assert.Len(t, pending.traces, 1, "Should not match envelopes splitting the same bytes differently")
+}
+
+func TestPendingReceiptTimes(t *testing.T) {
+	pending := NewPending("latencychannel")
+	ordered := time.Unix(1500000000, 0)
+	pending.now = func() time.Time { return ordered }
+	env1, env2, env3 := &cb.Envelope{Payload: []byte("1")}, &cb.Envelope{Payload: []byte("2")}, &cb.Envelope{Payload: []byte("3")}
+	pending.Set(env1, "trace1", ordered.Add(-250*time.Millisecond))
+	pending.Set(env2, "", ordered.Add(-50*time.Millisecond))
+	pending.Set(env3, "trace3", time.Time{})
+
+	block := cb.NewBlock(0, nil)
+	block.Metadata.Metadata[cb.BlockMetadataIndex_TRACE_IDS] = pending.Metadata([]*cb.Envelope{env1, env2, env3})
+	traces, err := utils.GetTraceMetadataFromBlock(block)
+	assert.NoError(t, err)
+	assert.Equal(t, &ab.TraceMetadata{
+		TraceIds:   []string{"trace1", "", "trace3"},
+		ReceivedAt: []int64{ordered.Add(-250 * time.Millisecond).UnixNano(), ordered.Add(-50 * time.Millisecond).UnixNano(), 0},
+		OrderedAt:  ordered.UnixNano(),
+	}, traces)
+
+	histogram := gometrics.GetOrRegisterHistogram("orderer.latency.latencychannel.ordering_ms", metrics.Registry, nil)
+	assert.Equal(t, int64(2), histogram.Count(), "Should only measure the envelopes whose receipt time is known")
+	assert.Equal(t, int64(250), histogram.Max())
+	assert.Equal(t, int64(50), histogram.Min())
+
+	pending.Set(env2, "", ordered.Add(-100*time.Millisecond))
+	assert.Nil(t, pending.Metadata([]*cb.Envelope{env2}), "Should not write metadata for envelopes without a trace ID")
+	assert.Equal(t, int64(3), histogram.Count(), "Should measure the envelopes without a trace ID")
+}
+
+func TestBlockTraceIDs(t *testing.T) {
+	traceIDs, err := BlockTraceIDs(&cb.Block{Metadata: &cb.BlockMetadata{Metadata: make([][]byte, 4)}})
+	assert.NoError(t, err)
+	assert.Nil(t, traceIDs, "Blocks which predate the trace metadata have no trace IDs")
+
+	block := cb.NewBlock(0, nil)
+	block.Metadata.Metadata[cb.BlockMetadataIndex_TRACE_IDS] = []byte("garbage")
+	_, err = BlockTraceIDs(block)
+	assert.Error(t, err)
+}
+
+func TestIndex(t *testing.T) {
+	index := NewIndex(3)
+	index.AddBlock(makeBlock(1, "trace1", "trace2"))
+	index.AddBlock(makeBlock(2, "", "trace1"))
+	assert.Equal(t, []*ab.TracePosition{
+		{TraceId: "trace1", BlockNumber: 1, TxIndex: 0},
+		{TraceId: "trace1", BlockNumber: 2, TxIndex: 1},
+	}, index.Lookup("trace1"))
+	assert.Equal(t, []*ab.TracePosition{{TraceId: "trace2", BlockNumber: 1, TxIndex: 1}}, index.Lookup("trace2"))
+
+	// The oldest positions are evicted
+	index.AddBlock(makeBlock(3, "trace3"))
+	assert.Equal(t, []*ab.TracePosition{{TraceId: "trace1", BlockNumber: 2, TxIndex: 1}}, index.Lookup("trace1"))
+	index.AddBlock(makeBlock(4, "trace4"))
+	assert.Empty(t, index.Lookup("trace2"))
+	assert.Len(t, index.Lookup("trace3"), 1)
+	assert.Len(t, index.Lookup("trace4"), 1)
+}
+
+func TestIndexLoad(t *testing.T) {
+	rlf := ramledger.New(10)
+	rl, _ := rlf.GetOrCreate("foo")
+	rl.Append(cb.NewBlock(0, nil))
+	pending := NewPending("foo")
+	for i := 1; i < 5; i++ {
+		batch := []*cb.Envelope{{Payload: []byte{byte(i), 0}}, {Payload: []byte{byte(i), 1}}}
+		pending.Set(batch[0], fmt.Sprintf("trace%d-0", i), time.Time{})
+		pending.Set(batch[1], fmt.Sprintf("trace%d-1", i), time.Time{})
+		block := ledger.CreateNextBlock(rl, batch)
+		block.Metadata.Metadata[cb.BlockMetadataIndex_TRACE_IDS] = pending.Metadata(batch)
+		rl.Append(block)
+	}
+
+	index := NewIndex(5)
+	index.Load(rl)
+	for i := 3; i < 5; i++ {
+		assert.Equal(t, []*ab.TracePosition{{TraceId: fmt.Sprintf("trace%d-1", i), BlockNumber: uint64(i), TxIndex: 1}}, index.Lookup(fmt.Sprintf("trace%d-1", i)))
+		assert.Len(t, index.Lookup(fmt.Sprintf("trace%d-0", i)), 1)
+	}
+	assert.Len(t, index.Lookup("trace2-1"), 1)
+	assert.Empty(t, index.Lookup("trace2-0"), "Should have retained only the last trace IDs")
+	assert.Empty(t, index.Lookup("trace1-1"), "Should not have read blocks beyond the index")
+}
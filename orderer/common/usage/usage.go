@@ -0,0 +1,322 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package usage accounts the transactions, bytes and blocks ordered by the orderer per channel and per
+// consortium, so that the providers of a shared ordering service may bill their tenants, and enforces the
+// optional quotas of transactions and bytes admitted per period at broadcast.
+//
+// The usage written is derived from the ledgers, so that it survives the restarts of the orderer and every
+// orderer reports the same usage. The usage admitted, which the quotas apply to, only counts the envelopes
+// broadcasted to this orderer since it started: the quotas apply per orderer.
+package usage
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/orderer/ledger"
+	localconfig "github.com/hyperledger/fabric/orderer/localconfig"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+	logging "github.com/op/go-logging"
+	gometrics "github.com/rcrowley/go-metrics"
+)
+
+var logger = logging.MustGetLogger("orderer/common/usage")
+
+// account holds the usage of a channel or a consortium
+type account struct {
+	name       string
+	consortium string // The consortium of a channel, empty for a consortium
+	quota      localconfig.Quota
+	total      ab.Usage
+	period     ab.Usage
+	admitted   ab.Usage
+
+	transactions gometrics.Counter
+	bytes        gometrics.Counter
+	blocks       gometrics.Counter
+}
+
+func newAccount(kind, name string, quota localconfig.Quota) *account {
+	prefix := "orderer.usage." + kind + "." + name + "."
+	return &account{
+		name:         name,
+		quota:        quota,
+		transactions: gometrics.GetOrRegisterCounter(prefix+"transactions", metrics.Registry),
+		bytes:        gometrics.GetOrRegisterCounter(prefix+"bytes", metrics.Registry),
+		blocks:       gometrics.GetOrRegisterCounter(prefix+"blocks", metrics.Registry),
+	}
+}
+
+// exceeds returns an error if admitting an envelope of the given size would exceed the quota of the account
+func (a *account) exceeds(size uint64) error {
+	if a.quota.Transactions != 0 && a.admitted.Transactions+1 > a.quota.Transactions {
+		return fmt.Errorf("quota of %d transactions reached", a.quota.Transactions)
+	}
+	if a.quota.Bytes != 0 && a.admitted.Bytes+size > a.quota.Bytes {
+		return fmt.Errorf("quota of %d bytes reached", a.quota.Bytes)
+	}
+	return nil
+}
+
+func (a *account) report() *ab.TenantUsage {
+	total, period, admitted := a.total, a.period, a.admitted
+	return &ab.TenantUsage{
+		ChannelId:  a.name,
+		Consortium: a.consortium,
+		Total:      &total,
+		Period:     &period,
+		Admitted:   &admitted,
+		Quota:      &ab.Quota{Transactions: a.quota.Transactions, Bytes: a.quota.Bytes},
+	}
+}
+
+// Accountant accounts the usage of the channels and consortiums of the orderer. The usage is counted in total
+// and per period, the periods being aligned on multiples of their duration since the Unix epoch. The
+// transactions and bytes are counted when the envelopes are admitted at broadcast, which the quotas apply to,
+// since the Accountant was created, and when their block is written, which the billing applies to, since the
+// channel was created. A block written belongs to the period of the latest timestamp of its envelopes, so that
+// all the orderers attribute it to the same period.
+type Accountant struct {
+	conf localconfig.Usage
+	now  func() time.Time
+
+	lock        sync.Mutex
+	since       time.Time
+	periodStart time.Time
+	channels    map[string]*account
+	consortiums map[string]*account
+
+	rejections gometrics.Counter
+}
+
+// New creates an Accountant. The number of envelopes rejected for exceeding a quota is published as the
+// orderer.usage.quota_rejections metric, and the usage as the orderer.usage.channel.<channel>.* and
+// orderer.usage.consortium.<consortium>.* metrics
+func New(conf localconfig.Usage) *Accountant {
+	return newAccountant(conf, time.Now)
+}
+
+func newAccountant(conf localconfig.Usage, now func() time.Time) *Accountant {
+	if conf.Period <= 0 {
+		logger.Panicf("Invalid usage period %s", conf.Period)
+	}
+	start := now()
+	return &Accountant{
+		conf:        conf,
+		now:         now,
+		since:       start,
+		periodStart: start.Truncate(conf.Period),
+		channels:    make(map[string]*account),
+		consortiums: make(map[string]*account),
+		rejections:  gometrics.GetOrRegisterCounter("orderer.usage.quota_rejections", metrics.Registry),
+	}
+}
+
+// Admit counts an envelope broadcast to the given channel against the quotas of the channel and of its
+// consortium, which may be empty for the system channel. An error is returned if the envelope exceeds one of
+// the quotas, along with the delay until the end of the period. Envelopes which are admitted and then fail to
+// be ordered remain counted
+func (ac *Accountant) Admit(chainID, consortium string, env *cb.Envelope) (time.Duration, error) {
+	ac.lock.Lock()
+	defer ac.lock.Unlock()
+	ac.roll()
+
+	size := uint64(len(env.Payload) + len(env.Signature))
+	channel := ac.channel(chainID, consortium)
+	tenants := []*account{channel}
+	if consortium != "" {
+		tenants = append(tenants, ac.consortium(consortium))
+	}
+
+	if ac.conf.Quotas.Enabled {
+		for _, tenant := range tenants {
+			if err := tenant.exceeds(size); err != nil {
+				ac.rejections.Inc(1)
+				if tenant != channel {
+					err = fmt.Errorf("consortium %s %s", consortium, err)
+				}
+				return ac.periodStart.Add(ac.conf.Period).Sub(ac.now()), err
+			}
+		}
+	}
+
+	for _, tenant := range tenants {
+		tenant.admitted.Transactions++
+		tenant.admitted.Bytes += size
+	}
+	return 0, nil
+}
+
+// Record counts the transactions, bytes and block of a block written to the given channel. A block without
+// timestamps belongs to the current period
+func (ac *Accountant) Record(chainID, consortium string, block *cb.Block) {
+	ac.record(chainID, consortium, block, true)
+}
+
+// Load counts the blocks already written to the ledger of the given channel, as Record does once they are
+// written. The blocks without timestamps are only counted in the total
+func (ac *Accountant) Load(chainID, consortium string, rl ledger.Reader) {
+	height := rl.Height()
+	it, _ := rl.Iterator(&ab.SeekPosition{Type: &ab.SeekPosition_Oldest{Oldest: &ab.SeekOldest{}}})
+	for number := uint64(0); number < height; number++ {
+		block, status := it.Next()
+		if status != cb.Status_SUCCESS || block.Data == nil {
+			logger.Warningf("[channel: %s] Could not read block %d, its usage and the usage of the later blocks are not counted", chainID, number)
+			return
+		}
+		ac.record(chainID, consortium, block, false)
+	}
+	logger.Debugf("[channel: %s] Loaded the usage of %d blocks", chainID, height)
+}
+
+func (ac *Accountant) record(chainID, consortium string, block *cb.Block, live bool) {
+	var size uint64
+	for _, data := range block.Data.Data {
+		size += uint64(len(data))
+	}
+	usage := ab.Usage{Transactions: uint64(len(block.Data.Data)), Bytes: size, Blocks: 1}
+	timestamp, ok := blockTime(block)
+
+	ac.lock.Lock()
+	defer ac.lock.Unlock()
+	ac.roll()
+
+	// The blocks whose timestamps are ahead of the clock of the orderer belong to the current period
+	inPeriod := !timestamp.Before(ac.periodStart)
+	if !ok {
+		inPeriod = live
+	}
+	tenants := []*account{ac.channel(chainID, consortium)}
+	if consortium != "" {
+		tenants = append(tenants, ac.consortium(consortium))
+	}
+	for _, tenant := range tenants {
+		add(&tenant.total, &usage)
+		if inPeriod {
+			add(&tenant.period, &usage)
+		}
+		tenant.transactions.Inc(int64(usage.Transactions))
+		tenant.bytes.Inc(int64(usage.Bytes))
+		tenant.blocks.Inc(1)
+	}
+}
+
+// Report returns the usage of the channels and consortiums which have been accounted so far, sorted by name
+func (ac *Accountant) Report() *ab.UsageReport {
+	ac.lock.Lock()
+	defer ac.lock.Unlock()
+	ac.roll()
+
+	report := &ab.UsageReport{
+		Since:       timestampProto(ac.since),
+		PeriodStart: timestampProto(ac.periodStart),
+		PeriodEnd:   timestampProto(ac.periodStart.Add(ac.conf.Period)),
+	}
+	for _, name := range sortedNames(ac.channels) {
+		report.Channels = append(report.Channels, ac.channels[name].report())
+	}
+	for _, name := range sortedNames(ac.consortiums) {
+		usage := ac.consortiums[name].report()
+		usage.ChannelId = ""
+		usage.Consortium = name
+		report.Consortiums = append(report.Consortiums, usage)
+	}
+	return report
+}
+
+// roll resets the usage of the period once it is over
+func (ac *Accountant) roll() {
+	periodStart := ac.now().Truncate(ac.conf.Period)
+	if !periodStart.After(ac.periodStart) {
+		return
+	}
+	logger.Debugf("Usage period starting at %s is over, starting period at %s", ac.periodStart, periodStart)
+	ac.periodStart = periodStart
+	for _, accounts := range []map[string]*account{ac.channels, ac.consortiums} {
+		for _, tenant := range accounts {
+			tenant.period = ab.Usage{}
+			tenant.admitted = ab.Usage{}
+		}
+	}
+}
+
+func (ac *Accountant) channel(chainID, consortium string) *account {
+	channel, ok := ac.channels[chainID]
+	if !ok {
+		quota := ac.conf.Quotas.Channel
+		if override, ok := ac.conf.Quotas.Channels[chainID]; ok {
+			quota = override
+		}
+		channel = newAccount("channel", chainID, quota)
+		ac.channels[chainID] = channel
+	}
+	channel.consortium = consortium
+	return channel
+}
+
+func (ac *Accountant) consortium(name string) *account {
+	consortium, ok := ac.consortiums[name]
+	if !ok {
+		quota := ac.conf.Quotas.Consortium
+		if override, ok := ac.conf.Quotas.Consortiums[name]; ok {
+			quota = override
+		}
+		consortium = newAccount("consortium", name, quota)
+		ac.consortiums[name] = consortium
+	}
+	return consortium
+}
+
+// blockTime returns the latest timestamp of the channel headers of the envelopes of a block, and false if none
+// of them has one
+func blockTime(block *cb.Block) (time.Time, bool) {
+	var latest time.Time
+	found := false
+	for _, data := range block.Data.Data {
+		env, err := utils.UnmarshalEnvelope(data)
+		if err != nil {
+			continue
+		}
+		payload, err := utils.UnmarshalPayload(env.Payload)
+		if err != nil || payload.Header == nil {
+			continue
+		}
+		chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+		if err != nil || chdr.Timestamp == nil {
+			continue
+		}
+		if t := time.Unix(chdr.Timestamp.Seconds, int64(chdr.Timestamp.Nanos)); !found || t.After(latest) {
+			latest, found = t, true
+		}
+	}
+	return latest, found
+}
+
+func add(to, usage *ab.Usage) {
+	to.Transactions += usage.Transactions
+	to.Bytes += usage.Bytes
+	to.Blocks += usage.Blocks
+}
+
+func sortedNames(accounts map[string]*account) []string {
+	names := make([]string, 0, len(accounts))
+	for name := range accounts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func timestampProto(t time.Time) *timestamp.Timestamp {
+	return &timestamp.Timestamp{Seconds: t.Unix(), Nanos: int32(t.Nanosecond())}
+}
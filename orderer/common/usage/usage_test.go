@@ -0,0 +1,181 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package usage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	ramledger "github.com/hyperledger/fabric/orderer/ledger/ram"
+	localconfig "github.com/hyperledger/fabric/orderer/localconfig"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+func newTestAccountant(quotas localconfig.UsageQuotas) (*Accountant, *clock) {
+	c := &clock{now: time.Unix(0, 0).Add(30 * time.Minute)}
+	return newAccountant(localconfig.Usage{Period: time.Hour, Quotas: quotas}, c.Now), c
+}
+
+func envelope(size int) *cb.Envelope {
+	return &cb.Envelope{Payload: make([]byte, size)}
+}
+
+func block(sizes ...int) *cb.Block {
+	block := cb.NewBlock(0, nil)
+	for _, size := range sizes {
+		block.Data.Data = append(block.Data.Data, make([]byte, size))
+	}
+	return block
+}
+
+func TestRecord(t *testing.T) {
+	ac, _ := newTestAccountant(localconfig.UsageQuotas{})
+	ac.Record("foo", "SampleConsortium", block(10, 20))
+	ac.Record("bar", "SampleConsortium", block(5))
+	ac.Record("system", "", block(1))
+
+	report := ac.Report()
+	assert.Equal(t, int64(1800), report.Since.Seconds)
+	assert.Equal(t, int64(0), report.PeriodStart.Seconds)
+	assert.Equal(t, int64(3600), report.PeriodEnd.Seconds)
+	if assert.Len(t, report.Channels, 3) {
+		assert.Equal(t, "bar", report.Channels[0].ChannelId)
+		assert.Equal(t, "foo", report.Channels[1].ChannelId)
+		assert.Equal(t, "SampleConsortium", report.Channels[1].Consortium)
+		assert.Equal(t, &ab.Usage{Transactions: 2, Bytes: 30, Blocks: 1}, report.Channels[1].Total)
+		assert.Equal(t, "system", report.Channels[2].ChannelId)
+	}
+	if assert.Len(t, report.Consortiums, 1) {
+		assert.Equal(t, "SampleConsortium", report.Consortiums[0].Consortium)
+		assert.Equal(t, &ab.Usage{Transactions: 3, Bytes: 35, Blocks: 2}, report.Consortiums[0].Total)
+		assert.Equal(t, &ab.Usage{Transactions: 3, Bytes: 35, Blocks: 2}, report.Consortiums[0].Period)
+	}
+}
+
+// timedBlock returns a block of one envelope whose channel header is stamped at the given time
+func timedBlock(number uint64, previousHash []byte, at time.Time) *cb.Block {
+	block := cb.NewBlock(number, previousHash)
+	block.Data.Data = [][]byte{utils.MarshalOrPanic(&cb.Envelope{
+		Payload: utils.MarshalOrPanic(&cb.Payload{
+			Header: &cb.Header{
+				ChannelHeader: utils.MarshalOrPanic(&cb.ChannelHeader{
+					Type:      int32(cb.HeaderType_ENDORSER_TRANSACTION),
+					ChannelId: "foo",
+					Timestamp: &timestamp.Timestamp{Seconds: at.Unix()},
+				}),
+			},
+		}),
+	})}
+	return block
+}
+
+func TestRecordPeriodOfBlock(t *testing.T) {
+	ac, _ := newTestAccountant(localconfig.UsageQuotas{})
+	ac.Record("foo", "", timedBlock(0, nil, time.Unix(0, 0).Add(-time.Minute)))
+	ac.Record("foo", "", timedBlock(1, nil, time.Unix(0, 0).Add(10*time.Minute)))
+	ac.Record("foo", "", block(1))
+
+	report := ac.Report()
+	if assert.Len(t, report.Channels, 1) {
+		assert.Equal(t, uint64(3), report.Channels[0].Total.Blocks)
+		assert.Equal(t, uint64(2), report.Channels[0].Period.Blocks, "The block of the previous period should not be counted in the period")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	rl, err := ramledger.New(10).GetOrCreate("foo")
+	assert.NoError(t, err)
+	previous := timedBlock(0, nil, time.Unix(0, 0).Add(-time.Minute))
+	assert.NoError(t, rl.Append(previous))
+	previous = timedBlock(1, previous.Header.Hash(), time.Unix(0, 0).Add(10*time.Minute))
+	assert.NoError(t, rl.Append(previous))
+	untimed := cb.NewBlock(2, previous.Header.Hash())
+	untimed.Data.Data = [][]byte{make([]byte, 1)}
+	assert.NoError(t, rl.Append(untimed))
+
+	ac, _ := newTestAccountant(localconfig.UsageQuotas{})
+	ac.Load("foo", "SampleConsortium", rl)
+
+	report := ac.Report()
+	if assert.Len(t, report.Channels, 1) {
+		assert.Equal(t, uint64(3), report.Channels[0].Total.Blocks)
+		assert.Equal(t, uint64(3), report.Channels[0].Total.Transactions)
+		assert.Equal(t, uint64(1), report.Channels[0].Period.Blocks, "Only the block stamped within the period should be counted in the period")
+		assert.Equal(t, uint64(0), report.Channels[0].Admitted.Transactions)
+	}
+	if assert.Len(t, report.Consortiums, 1) {
+		assert.Equal(t, uint64(3), report.Consortiums[0].Total.Blocks)
+	}
+}
+
+func TestQuotas(t *testing.T) {
+	ac, c := newTestAccountant(localconfig.UsageQuotas{
+		Enabled:     true,
+		Channel:     localconfig.Quota{Transactions: 2},
+		Consortium:  localconfig.Quota{Bytes: 100},
+		Channels:    map[string]localconfig.Quota{"unlimited": {}},
+		Consortiums: map[string]localconfig.Quota{"Other": {Transactions: 1}},
+	})
+
+	for i := 0; i < 2; i++ {
+		_, err := ac.Admit("foo", "SampleConsortium", envelope(10))
+		assert.NoError(t, err)
+	}
+	retryAfter, err := ac.Admit("foo", "SampleConsortium", envelope(10))
+	assert.Error(t, err, "Should have exceeded the quota of transactions of the channel")
+	assert.Equal(t, 30*time.Minute, retryAfter)
+
+	_, err = ac.Admit("unlimited", "SampleConsortium", envelope(70))
+	assert.NoError(t, err)
+	_, err = ac.Admit("unlimited", "SampleConsortium", envelope(11))
+	assert.Error(t, err, "Should have exceeded the quota of bytes of the consortium")
+
+	_, err = ac.Admit("bar", "Other", envelope(1))
+	assert.NoError(t, err)
+	_, err = ac.Admit("baz", "Other", envelope(1))
+	assert.Error(t, err, "Should have exceeded the overridden quota of the consortium")
+
+	_, err = ac.Admit("system", "", envelope(1000))
+	assert.NoError(t, err, "The system channel has no consortium quota")
+
+	report := ac.Report()
+	assert.Equal(t, &ab.Usage{Transactions: 3, Bytes: 90}, report.Consortiums[1].Admitted)
+	assert.Equal(t, &ab.Quota{Bytes: 100}, report.Consortiums[1].Quota)
+
+	// The quotas are renewed with the period
+	c.now = c.now.Add(45 * time.Minute)
+	_, err = ac.Admit("foo", "SampleConsortium", envelope(10))
+	assert.NoError(t, err)
+	report = ac.Report()
+	assert.Equal(t, int64(3600), report.PeriodStart.Seconds)
+	assert.Equal(t, &ab.Usage{Transactions: 1, Bytes: 10}, report.Consortiums[1].Admitted)
+}
+
+func TestQuotasDisabled(t *testing.T) {
+	ac, _ := newTestAccountant(localconfig.UsageQuotas{Channel: localconfig.Quota{Transactions: 1}})
+	for i := 0; i < 2; i++ {
+		_, err := ac.Admit("foo", "SampleConsortium", envelope(10))
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, uint64(2), ac.Report().Channels[0].Admitted.Transactions, "Should still count the envelopes admitted")
+}
+
+func TestInvalidPeriod(t *testing.T) {
+	assert.Panics(t, func() { New(localconfig.Usage{}) })
+}
@@ -0,0 +1,715 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package gateway exposes the Broadcast and Deliver services of the orderer over HTTP and
+// WebSocket for the clients which cannot speak gRPC. Messages are exchanged in the JSON form
+// produced by the protolator package, with the opaque fields expanded.
+//
+// As the orderer checks the signatures over the marshaled payloads, clients first obtain the
+// bytes to sign by posting the JSON payload to /protolator/encode/common.Payload, and then submit
+// the envelope carrying these bytes, base64 encoded, with the signature
+package gateway
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/gorilla/mux"
+	"github.com/hyperledger/fabric/common/tools/configtxlator/rest"
+	"github.com/hyperledger/fabric/common/tools/protolator"
+	"github.com/hyperledger/fabric/orderer/common/broadcast"
+	"github.com/hyperledger/fabric/orderer/common/tracing"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+	logging "github.com/op/go-logging"
+	"golang.org/x/net/context"
+	"golang.org/x/net/websocket"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	// Import these to register the proto types
+	_ "github.com/hyperledger/fabric/protos/peer"
+)
+
+var logger = logging.MustGetLogger("orderer/gateway")
+
+// BroadcastModeHeader is the HTTP header with which a client selects the mode of a broadcast, as
+// the broadcast-mode gRPC metadata does
+const BroadcastModeHeader = "Broadcast-Mode"
+
+// TraceIDHeader is the HTTP header with which a client supplies the trace ID of the envelope it
+// broadcasts, as the trace-id gRPC metadata does
+const TraceIDHeader = "Trace-Id"
+
+// Authorizer checks the signatures of the envelopes posted to the gateway against the policies of the chains of
+// the orderer
+type Authorizer interface {
+	// AuthorizeReader checks that the signed data satisfies the policy of the readers of the chain with the given
+	// ID: /Channel/Readers, or /Channel/Orderer/Admins for the system channel, whose config describes all the
+	// consortiums. It returns the status of the check, with the error which failed it
+	AuthorizeReader(chainID string, signedData []*cb.SignedData) (cb.Status, error)
+
+	// AuthorizeAdmin checks that the signed data satisfies the policy of the administrators of the orderers,
+	// /Channel/Orderer/Admins of the system channel. It returns the status of the check, with the error which
+	// failed it
+	AuthorizeAdmin(signedData []*cb.SignedData) (cb.Status, error)
+}
+
+// TraceLookup looks up the positions of traced envelopes in the chains of the orderer
+type TraceLookup interface {
+	// LookupTrace returns the positions of the recently committed envelopes of a chain with the
+	// given trace ID, and false if the chain does not exist
+	LookupTrace(chainID, traceID string) ([]*ab.TracePosition, bool)
+}
+
+// TxLookup locates the ordered transactions in the chains of the orderer
+type TxLookup interface {
+	// LocateTx returns the position of the most recently committed transaction of a chain with
+	// the given ID, or nil if there is no such transaction, and false if the chain does not exist
+	LocateTx(chainID, txID string) (*ab.CommitNotification, bool, error)
+}
+
+// ConfigSubscriber notifies the config changes of the chains of the orderer
+type ConfigSubscriber interface {
+	// SubscribeConfig returns a channel on which the config changes of the chain with the given
+	// ID, or of all the chains if it is empty, are sent, and a function which unsubscribes. The
+	// channel is closed if the subscriber lags behind
+	SubscribeConfig(chainID string) (<-chan *ab.ConfigNotification, func())
+}
+
+// PolicyExplainer explains the decisions of the policies of the chains of the orderer
+type PolicyExplainer interface {
+	// ExplainPolicy returns the decision tree of the evaluation of the policy with the given path
+	// of a chain over the signed data, and false if the chain or the policy does not exist
+	ExplainPolicy(chainID, policyPath string, signedData []*cb.SignedData) (*cb.PolicyDecision, bool)
+}
+
+// ConfigValidator validates config updates against the current config of the chains of the orderer
+type ConfigValidator interface {
+	// ValidateConfigUpdate processes a CONFIG_UPDATE envelope as the orderer would once broadcasted, without
+	// ordering it, and returns the outcome
+	ValidateConfigUpdate(env *cb.Envelope) *ab.ConfigUpdateValidation
+}
+
+// UsageReporter reports the usage of the channels and consortiums of the orderer
+type UsageReporter interface {
+	// Report returns the usage of the channels and consortiums accounted so far
+	Report() *ab.UsageReport
+}
+
+// ChainPauser pauses and resumes the chains of the orderer on behalf of their administrators
+type ChainPauser interface {
+	// PauseChain pauses the chain with the given ID, or resumes it if pause is false, if the signed data
+	// satisfies the policy of the administrators of the orderers of the chain. It returns the status of the
+	// operation, with the error which failed it
+	PauseChain(chainID string, pause bool, signedData []*cb.SignedData) (cb.Status, error)
+}
+
+type gateway struct {
+	server   ab.AtomicBroadcastServer
+	authz    Authorizer
+	traces   TraceLookup
+	txs      TxLookup
+	configs  ConfigSubscriber
+	policies PolicyExplainer
+	usage    UsageReporter
+	updates  ConfigValidator
+	pausers  ChainPauser
+}
+
+// NewHandler creates the http.Handler of the gateway in front of server. It serves
+//
+//	POST /broadcast      a JSON envelope, answered with the JSON broadcast responses, one per line
+//	POST /deliver        a JSON seek envelope, answered with the JSON deliver responses, one per line
+//	GET  /deliver        a WebSocket over which JSON seek envelopes and deliver responses are exchanged
+//	POST /protolator/... the encode and decode operations of configtxlator
+//	POST /trace/{channel}/{traceID}
+//	                     a JSON envelope of the channel, signed by a reader of the channel, answered
+//	                     with the JSON positions of the envelopes with the trace ID, one per line,
+//	                     when traces is not nil
+//	POST /tx/{channel}/{txID}
+//	                     a JSON envelope of the channel, signed by a reader of the channel, answered
+//	                     with the JSON position of the transaction with the ID, when txs is not nil
+//	POST /config, /config/{channel}
+//	                     a JSON envelope, of the channel if any, answered with the JSON notifications
+//	                     of the config changes of all the channels its signer may read, or of the
+//	                     channel, one per line as they happen, when configs is not nil. The response
+//	                     ends when the client lags behind, which should then refresh the configs it
+//	                     caches and reconnect, or when its signer may no longer read the channel
+//	POST /policy/{channel}/{policy path}
+//	                     a JSON envelope of the channel, signed by a reader of the channel, answered
+//	                     with the JSON decision tree of the evaluation of the policy over its signed
+//	                     data, when policies is not nil
+//	POST /usage          a JSON envelope, signed by an administrator of the orderers, answered with
+//	                     the JSON usage report of the channels and consortiums, when usage is not nil
+//	POST /validate/configupdate
+//	                     a JSON CONFIG_UPDATE envelope, answered with the JSON outcome of its dry
+//	                     run, holding the resulting config or why the update is not valid, when
+//	                     updates is not nil
+//	POST /admin/pause/{channel}, /admin/resume/{channel}
+//	                     a JSON envelope of the channel, signed by administrators of its orderers,
+//	                     which pauses the consenter of the channel, or resumes it, when pausers is
+//	                     not nil. A paused channel rejects the broadcasts as SERVICE_UNAVAILABLE
+//	                     with the PAUSED channel state
+//
+// The envelopes of the readers of a channel are checked by authz, and those of the requests which fail
+// the check are answered with its status. The status code of the HTTP responses is the status of the
+// first response of the orderer
+func NewHandler(server ab.AtomicBroadcastServer, authz Authorizer, traces TraceLookup, txs TxLookup, configs ConfigSubscriber, policies PolicyExplainer, usage UsageReporter, updates ConfigValidator, pausers ChainPauser) http.Handler {
+	g := &gateway{server: server, authz: authz, traces: traces, txs: txs, configs: configs, policies: policies, usage: usage, updates: updates, pausers: pausers}
+
+	router := mux.NewRouter().StrictSlash(true)
+	router.
+		HandleFunc("/broadcast", g.broadcast).
+		Methods("POST")
+	router.
+		HandleFunc("/deliver", g.deliver).
+		Methods("POST")
+	router.
+		Handle("/deliver", websocket.Handler(g.deliverWebSocket)).
+		Methods("GET")
+	router.
+		HandleFunc("/protolator/encode/{msgName}", rest.Encode).
+		Methods("POST")
+	router.
+		HandleFunc("/protolator/decode/{msgName}", rest.Decode).
+		Methods("POST")
+	if traces != nil {
+		router.
+			HandleFunc("/trace/{channel}/{traceID}", g.trace).
+			Methods("POST")
+	}
+	if txs != nil {
+		router.
+			HandleFunc("/tx/{channel}/{txID}", g.tx).
+			Methods("POST")
+	}
+	if configs != nil {
+		router.
+			HandleFunc("/config", g.config).
+			Methods("POST")
+		router.
+			HandleFunc("/config/{channel}", g.config).
+			Methods("POST")
+	}
+	if policies != nil {
+		router.
+			HandleFunc("/policy/{channel}/{path:.+}", g.policy).
+			Methods("POST")
+	}
+	if usage != nil {
+		router.
+			HandleFunc("/usage", g.usageReport).
+			Methods("POST")
+	}
+	if updates != nil {
+		router.
+			HandleFunc("/validate/configupdate", g.validateConfigUpdate).
+			Methods("POST")
+	}
+	if pausers != nil {
+		router.
+			HandleFunc("/admin/{operation:pause|resume}/{channel}", g.pause).
+			Methods("POST")
+	}
+
+	return router
+}
+
+func (g *gateway) broadcast(w http.ResponseWriter, r *http.Request) {
+	env := &cb.Envelope{}
+	if err := protolator.DeepUnmarshalJSON(r.Body, env); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	md := metadata.MD{}
+	if mode := r.Header.Get(BroadcastModeHeader); mode != "" {
+		md[broadcast.BroadcastModeKey] = []string{mode}
+	}
+	if traceID := r.Header.Get(TraceIDHeader); traceID != "" {
+		md[tracing.TraceIDKey] = []string{traceID}
+	}
+	ctx := metadata.NewIncomingContext(r.Context(), md)
+
+	stream := &broadcastStream{ctx: ctx, recv: singleEnvelope(env), writer: newResponseWriter(w)}
+	if err := g.server.Broadcast(stream); err != nil {
+		logStreamError("Broadcast from %s failed: %s", r.RemoteAddr, err)
+	}
+}
+
+func (g *gateway) deliver(w http.ResponseWriter, r *http.Request) {
+	env := &cb.Envelope{}
+	if err := protolator.DeepUnmarshalJSON(r.Body, env); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	stream := &deliverStream{
+		ctx:  r.Context(),
+		recv: singleEnvelope(env),
+		send: newResponseWriter(w).writeDeliverResponse,
+	}
+	if err := g.server.Deliver(stream); err != nil {
+		logStreamError("Deliver to %s failed: %s", r.RemoteAddr, err)
+	}
+}
+
+func (g *gateway) trace(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	if _, ok := g.authorizeReader(w, r, vars["channel"]); !ok {
+		return
+	}
+	positions, ok := g.traces.LookupTrace(vars["channel"], vars["traceID"])
+	if !ok || len(positions) == 0 {
+		http.Error(w, "trace ID not found", http.StatusNotFound)
+		return
+	}
+
+	writer := newResponseWriter(w)
+	for _, position := range positions {
+		var buf bytes.Buffer
+		if err := protolator.DeepMarshalJSON(&buf, position); err != nil {
+			logger.Warningf("Failed marshaling trace position: %s", err)
+			return
+		}
+		if err := writer.write(cb.Status_SUCCESS, buf.Bytes()); err != nil {
+			logger.Warningf("Failed sending trace positions to %s: %s", r.RemoteAddr, err)
+			return
+		}
+	}
+}
+
+func (g *gateway) tx(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	if _, ok := g.authorizeReader(w, r, vars["channel"]); !ok {
+		return
+	}
+	position, ok, err := g.txs.LocateTx(vars["channel"], vars["txID"])
+	if err != nil {
+		logger.Warningf("Failed locating transaction %s: %s", vars["txID"], err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !ok || position == nil {
+		http.Error(w, "transaction ID not found", http.StatusNotFound)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := protolator.DeepMarshalJSON(&buf, position); err != nil {
+		logger.Warningf("Failed marshaling transaction position: %s", err)
+		return
+	}
+	if err := newResponseWriter(w).write(cb.Status_SUCCESS, buf.Bytes()); err != nil {
+		logger.Warningf("Failed sending transaction position to %s: %s", r.RemoteAddr, err)
+	}
+}
+
+func (g *gateway) config(w http.ResponseWriter, r *http.Request) {
+	chainID := mux.Vars(r)["channel"]
+	var signedData []*cb.SignedData
+	var ok bool
+	if chainID != "" {
+		signedData, ok = g.authorizeReader(w, r, chainID)
+	} else {
+		_, signedData, ok = readSignedData(w, r)
+	}
+	if !ok {
+		return
+	}
+	notifications, unsubscribe := g.configs.SubscribeConfig(chainID)
+	defer unsubscribe()
+
+	// The header is sent at once, as the first notification may take long
+	writer := newResponseWriter(w)
+	writer.writeHeader(cb.Status_SUCCESS)
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case notification, ok := <-notifications:
+			if !ok {
+				logger.Warningf("Dropped config subscriber %s lagging behind", r.RemoteAddr)
+				return
+			}
+			// The policy is checked again on every change, as the change may remove the signer from the readers
+			if _, err := g.authz.AuthorizeReader(notification.ChannelId, signedData); err != nil {
+				if chainID != "" {
+					logger.Warningf("Dropped config subscriber %s of channel %s: %s", r.RemoteAddr, chainID, err)
+					return
+				}
+				continue
+			}
+			var buf bytes.Buffer
+			if err := protolator.DeepMarshalJSON(&buf, notification); err != nil {
+				logger.Warningf("Failed marshaling config notification: %s", err)
+				return
+			}
+			if err := writer.write(cb.Status_SUCCESS, buf.Bytes()); err != nil {
+				logger.Warningf("Failed sending config notification to %s: %s", r.RemoteAddr, err)
+				return
+			}
+		}
+	}
+}
+
+func (g *gateway) policy(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	signedData, ok := g.authorizeReader(w, r, vars["channel"])
+	if !ok {
+		return
+	}
+
+	decision, ok := g.policies.ExplainPolicy(vars["channel"], "/"+vars["path"], signedData)
+	if !ok {
+		http.Error(w, "policy not found", http.StatusNotFound)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := protolator.DeepMarshalJSON(&buf, decision); err != nil {
+		logger.Warningf("Failed marshaling policy decision: %s", err)
+		return
+	}
+	if err := newResponseWriter(w).write(cb.Status_SUCCESS, buf.Bytes()); err != nil {
+		logger.Warningf("Failed sending policy decision to %s: %s", r.RemoteAddr, err)
+	}
+}
+
+func (g *gateway) usageReport(w http.ResponseWriter, r *http.Request) {
+	_, signedData, ok := readSignedData(w, r)
+	if !ok {
+		return
+	}
+	if status, err := g.authz.AuthorizeAdmin(signedData); err != nil {
+		logger.Warningf("Rejecting request from %s for %s: %s", r.RemoteAddr, r.URL.Path, err)
+		http.Error(w, err.Error(), statusCode(status))
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := protolator.DeepMarshalJSON(&buf, g.usage.Report()); err != nil {
+		logger.Warningf("Failed marshaling usage report: %s", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err := newResponseWriter(w).write(cb.Status_SUCCESS, buf.Bytes()); err != nil {
+		logger.Warningf("Failed sending usage report to %s: %s", r.RemoteAddr, err)
+	}
+}
+
+func (g *gateway) validateConfigUpdate(w http.ResponseWriter, r *http.Request) {
+	env := &cb.Envelope{}
+	if err := protolator.DeepUnmarshalJSON(r.Body, env); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	validation := g.updates.ValidateConfigUpdate(env)
+	var buf bytes.Buffer
+	if err := protolator.DeepMarshalJSON(&buf, validation); err != nil {
+		logger.Warningf("Failed marshaling config update validation: %s", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	status := cb.Status_SUCCESS
+	if !validation.Valid {
+		status = cb.Status_BAD_REQUEST
+	}
+	if err := newResponseWriter(w).write(status, buf.Bytes()); err != nil {
+		logger.Warningf("Failed sending config update validation to %s: %s", r.RemoteAddr, err)
+	}
+}
+
+func (g *gateway) pause(w http.ResponseWriter, r *http.Request) {
+	env := &cb.Envelope{}
+	if err := protolator.DeepUnmarshalJSON(r.Body, env); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	vars := mux.Vars(r)
+	// The signatures of the administrators are bound to the channel they apply to
+	chainID, err := channelID(env)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if chainID != vars["channel"] {
+		http.Error(w, "envelope of channel "+chainID+" does not apply to channel "+vars["channel"], http.StatusBadRequest)
+		return
+	}
+	signedData, err := env.AsSignedData()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	pause := vars["operation"] == "pause"
+	status, err := g.pausers.PauseChain(vars["channel"], pause, signedData)
+	if err != nil {
+		logger.Warningf("Failed to %s channel %s for %s: %s", vars["operation"], vars["channel"], r.RemoteAddr, err)
+		http.Error(w, err.Error(), statusCode(status))
+		return
+	}
+	logger.Infof("Channel %s %sd for %s", vars["channel"], vars["operation"], r.RemoteAddr)
+
+	msg, err := json.Marshal(map[string]interface{}{"channel_id": vars["channel"], "paused": pause})
+	if err != nil {
+		logger.Warningf("Failed marshaling pause response: %s", err)
+		return
+	}
+	if err := newResponseWriter(w).write(status, msg); err != nil {
+		logger.Warningf("Failed sending pause response to %s: %s", r.RemoteAddr, err)
+	}
+}
+
+func (g *gateway) deliverWebSocket(conn *websocket.Conn) {
+	stream := &deliverStream{
+		ctx: conn.Request().Context(),
+		recv: func() (*cb.Envelope, error) {
+			var msg []byte
+			if err := websocket.Message.Receive(conn, &msg); err != nil {
+				return nil, err
+			}
+			env := &cb.Envelope{}
+			if err := protolator.DeepUnmarshalJSON(bytes.NewReader(msg), env); err != nil {
+				// The deliver handler answers envelopes it cannot parse with BAD_REQUEST
+				logger.Warningf("Received malformed seek envelope from %s: %s", conn.Request().RemoteAddr, err)
+				return &cb.Envelope{}, nil
+			}
+			return env, nil
+		},
+		send: func(resp *ab.DeliverResponse) error {
+			msg, err := marshalDeliverResponse(resp)
+			if err != nil {
+				return err
+			}
+			return websocket.Message.Send(conn, string(msg))
+		},
+	}
+	if err := g.server.Deliver(stream); err != nil {
+		logStreamError("Deliver to %s failed: %s", conn.Request().RemoteAddr, err)
+	}
+}
+
+// logStreamError logs the error a stream ended with. The errors carrying the details of a failure which has
+// already been sent to the client as a response are only logged at debug level
+func logStreamError(format, remoteAddr string, err error) {
+	if _, ok := utils.GetErrorDetail(err); ok {
+		logger.Debugf(format, remoteAddr, err)
+		return
+	}
+	logger.Warningf(format, remoteAddr, err)
+}
+
+// authorizeReader reads the JSON envelope posted, which must be of the channel, and checks that its signer may read
+// the channel. It returns the signed data of the envelope, or answers the request and returns false if the check
+// fails
+func (g *gateway) authorizeReader(w http.ResponseWriter, r *http.Request, chainID string) ([]*cb.SignedData, bool) {
+	envChainID, signedData, ok := readSignedData(w, r)
+	if !ok {
+		return nil, false
+	}
+	// The signatures of the readers are bound to the channel they apply to
+	if envChainID != chainID {
+		http.Error(w, "envelope of channel "+envChainID+" does not apply to channel "+chainID, http.StatusBadRequest)
+		return nil, false
+	}
+	if status, err := g.authz.AuthorizeReader(chainID, signedData); err != nil {
+		logger.Warningf("Rejecting request from %s for %s: %s", r.RemoteAddr, r.URL.Path, err)
+		http.Error(w, err.Error(), statusCode(status))
+		return nil, false
+	}
+	return signedData, true
+}
+
+// readSignedData reads the JSON envelope posted, and returns its channel ID and its signed data, or answers the
+// request and returns false if it is malformed
+func readSignedData(w http.ResponseWriter, r *http.Request) (string, []*cb.SignedData, bool) {
+	env := &cb.Envelope{}
+	if err := protolator.DeepUnmarshalJSON(r.Body, env); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return "", nil, false
+	}
+	chainID, err := channelID(env)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return "", nil, false
+	}
+	signedData, err := env.AsSignedData()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return "", nil, false
+	}
+	return chainID, signedData, true
+}
+
+// channelID returns the channel ID of the channel header of env
+func channelID(env *cb.Envelope) (string, error) {
+	payload, err := utils.UnmarshalPayload(env.Payload)
+	if err != nil {
+		return "", err
+	}
+	if payload.Header == nil {
+		return "", errors.New("missing header")
+	}
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return "", err
+	}
+	return chdr.ChannelId, nil
+}
+
+// singleEnvelope returns env once, and io.EOF afterwards
+func singleEnvelope(env *cb.Envelope) func() (*cb.Envelope, error) {
+	return func() (*cb.Envelope, error) {
+		if env == nil {
+			return nil, io.EOF
+		}
+		defer func() { env = nil }()
+		return env, nil
+	}
+}
+
+type broadcastStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	recv   func() (*cb.Envelope, error)
+	writer *responseWriter
+}
+
+func (bs *broadcastStream) Context() context.Context {
+	return bs.ctx
+}
+
+func (bs *broadcastStream) Recv() (*cb.Envelope, error) {
+	return bs.recv()
+}
+
+func (bs *broadcastStream) Send(resp *ab.BroadcastResponse) error {
+	var buf bytes.Buffer
+	if err := protolator.DeepMarshalJSON(&buf, resp); err != nil {
+		return err
+	}
+	if retryAfter := resp.GetError().GetRetryAfterMs(); retryAfter > 0 && !bs.writer.wroteHeader {
+		// Retry-After is in whole seconds
+		bs.writer.w.Header().Set("Retry-After", strconv.FormatUint((retryAfter+999)/1000, 10))
+	}
+	return bs.writer.write(resp.Status, buf.Bytes())
+}
+
+type deliverStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	recv func() (*cb.Envelope, error)
+	send func(*ab.DeliverResponse) error
+}
+
+func (ds *deliverStream) Context() context.Context {
+	return ds.ctx
+}
+
+func (ds *deliverStream) Recv() (*cb.Envelope, error) {
+	return ds.recv()
+}
+
+func (ds *deliverStream) Send(resp *ab.DeliverResponse) error {
+	if err := ds.ctx.Err(); err != nil {
+		return err
+	}
+	return ds.send(resp)
+}
+
+// responseWriter writes the responses of a stream to an HTTP response, one compact JSON object
+// per line, flushing each of them
+type responseWriter struct {
+	w           http.ResponseWriter
+	wroteHeader bool
+}
+
+func newResponseWriter(w http.ResponseWriter) *responseWriter {
+	return &responseWriter{w: w}
+}
+
+// writeHeader writes the header of the response with the status, unless it was already written
+func (rw *responseWriter) writeHeader(status cb.Status) {
+	if rw.wroteHeader {
+		return
+	}
+	rw.w.Header().Set("Content-Type", "application/json")
+	rw.w.WriteHeader(statusCode(status))
+	rw.wroteHeader = true
+	if flusher, ok := rw.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (rw *responseWriter) write(status cb.Status, msg []byte) error {
+	rw.writeHeader(status)
+
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, msg); err != nil {
+		return err
+	}
+	buf.WriteByte('\n')
+	if _, err := rw.w.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	if flusher, ok := rw.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return nil
+}
+
+func (rw *responseWriter) writeDeliverResponse(resp *ab.DeliverResponse) error {
+	msg, err := marshalDeliverResponse(resp)
+	if err != nil {
+		return err
+	}
+	// A stream starting with a block is successful, whatever its final status
+	status := cb.Status_SUCCESS
+	if s, ok := resp.Type.(*ab.DeliverResponse_Status); ok {
+		status = s.Status
+	}
+	return rw.write(status, msg)
+}
+
+// statusCode maps a status of the orderer to an HTTP status code, which they are modelled after
+func statusCode(status cb.Status) int {
+	if code := int(status); code >= 100 && code < 600 {
+		return code
+	}
+	return http.StatusInternalServerError
+}
+
+// marshalDeliverResponse marshals the blocks of the deliver responses itself, as protolator does
+// not expand the oneof fields. Blocks carrying transactions protolator cannot decode are
+// marshaled without expanding their opaque fields
+func marshalDeliverResponse(resp *ab.DeliverResponse) ([]byte, error) {
+	blockResp, ok := resp.Type.(*ab.DeliverResponse_Block)
+	if !ok {
+		var buf bytes.Buffer
+		err := protolator.DeepMarshalJSON(&buf, resp)
+		return buf.Bytes(), err
+	}
+
+	var block bytes.Buffer
+	if err := protolator.DeepMarshalJSON(&block, blockResp.Block); err != nil {
+		logger.Debugf("Marshaling block without decoding its opaque fields: %s", err)
+		block.Reset()
+		if err := (&jsonpb.Marshaler{OrigName: true}).Marshal(&block, blockResp.Block); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(map[string]json.RawMessage{"block": block.Bytes()})
+}
@@ -0,0 +1,737 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/hyperledger/fabric/common/tools/protolator"
+	"github.com/hyperledger/fabric/orderer/common/broadcast"
+	"github.com/hyperledger/fabric/orderer/common/tracing"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/websocket"
+	"google.golang.org/grpc/metadata"
+)
+
+// mockServer answers each envelope broadcast with the status given by the channel ID and the trace
+// ID supplied, adding a commit notification in the commit-ack mode, and each seek with the blocks
+// requested
+type mockServer struct {
+	blocks []*cb.Block
+}
+
+func (ms *mockServer) Broadcast(srv ab.AtomicBroadcast_BroadcastServer) error {
+	md, _ := metadata.FromIncomingContext(srv.Context())
+	commitAck := len(md[broadcast.BroadcastModeKey]) > 0 && md[broadcast.BroadcastModeKey][0] == broadcast.BroadcastModeCommitAck
+	traceID := ""
+	if len(md[tracing.TraceIDKey]) > 0 {
+		traceID = md[tracing.TraceIDKey][0]
+	}
+	for {
+		env, err := srv.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		payload, err := utils.UnmarshalPayload(env.Payload)
+		if err != nil {
+			return srv.Send(&ab.BroadcastResponse{Status: cb.Status_BAD_REQUEST})
+		}
+		chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+		if err != nil {
+			return srv.Send(&ab.BroadcastResponse{Status: cb.Status_BAD_REQUEST})
+		}
+		if chdr.ChannelId == "busychannel" {
+			return srv.Send(&ab.BroadcastResponse{Status: cb.Status_SERVICE_UNAVAILABLE, Error: &ab.BroadcastError{
+				Class:        ab.BroadcastError_UNAVAILABLE,
+				RetryAfterMs: 1500,
+				ChannelState: ab.BroadcastError_ACTIVE,
+			}})
+		}
+		if chdr.ChannelId != "mychannel" {
+			return srv.Send(&ab.BroadcastResponse{Status: cb.Status_NOT_FOUND})
+		}
+		if err := srv.Send(&ab.BroadcastResponse{Status: cb.Status_SUCCESS, TraceId: traceID}); err != nil {
+			return err
+		}
+		if commitAck {
+			commit := &ab.CommitNotification{TxId: chdr.TxId, BlockNumber: 1}
+			if err := srv.Send(&ab.BroadcastResponse{Status: cb.Status_SUCCESS, Commit: commit}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (ms *mockServer) Deliver(srv ab.AtomicBroadcast_DeliverServer) error {
+	for {
+		env, err := srv.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		seekInfo := &ab.SeekInfo{}
+		if _, err := utils.UnmarshalEnvelopeOfType(env, cb.HeaderType_DELIVER_SEEK_INFO, seekInfo); err != nil {
+			return srv.Send(&ab.DeliverResponse{Type: &ab.DeliverResponse_Status{Status: cb.Status_BAD_REQUEST}})
+		}
+		start := seekInfo.Start.GetSpecified().GetNumber()
+		stop := seekInfo.Stop.GetSpecified().GetNumber()
+		if stop >= uint64(len(ms.blocks)) {
+			if err := srv.Send(&ab.DeliverResponse{Type: &ab.DeliverResponse_Status{Status: cb.Status_NOT_FOUND}}); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, block := range ms.blocks[start : stop+1] {
+			if err := srv.Send(&ab.DeliverResponse{Type: &ab.DeliverResponse_Block{Block: block}}); err != nil {
+				return err
+			}
+		}
+		if err := srv.Send(&ab.DeliverResponse{Type: &ab.DeliverResponse_Status{Status: cb.Status_SUCCESS}}); err != nil {
+			return err
+		}
+	}
+}
+
+func newBlock(number uint64, envs ...*cb.Envelope) *cb.Block {
+	block := cb.NewBlock(number, nil)
+	for _, env := range envs {
+		block.Data.Data = append(block.Data.Data, utils.MarshalOrPanic(env))
+	}
+	return block
+}
+
+func marshalJSON(t *testing.T, env *cb.Envelope) string {
+	var buf bytes.Buffer
+	assert.NoError(t, protolator.DeepMarshalJSON(&buf, env))
+	return buf.String()
+}
+
+func seekEnvelope(t *testing.T, start, stop uint64) *cb.Envelope {
+	env, err := utils.CreateSignedEnvelope(cb.HeaderType_DELIVER_SEEK_INFO, "mychannel", nil, &ab.SeekInfo{
+		Start:    &ab.SeekPosition{Type: &ab.SeekPosition_Specified{Specified: &ab.SeekSpecified{Number: start}}},
+		Stop:     &ab.SeekPosition{Type: &ab.SeekPosition_Specified{Specified: &ab.SeekSpecified{Number: stop}}},
+		Behavior: ab.SeekInfo_BLOCK_UNTIL_READY,
+	}, 0, 0)
+	assert.NoError(t, err)
+	return env
+}
+
+func txEnvelope(t *testing.T, channelID string) *cb.Envelope {
+	payload := &cb.Payload{
+		Header: &cb.Header{
+			ChannelHeader: utils.MarshalOrPanic(&cb.ChannelHeader{
+				Type:      int32(cb.HeaderType_CONFIG_UPDATE),
+				ChannelId: channelID,
+				TxId:      "tx1",
+			}),
+			SignatureHeader: utils.MarshalOrPanic(&cb.SignatureHeader{}),
+		},
+		Data: utils.MarshalOrPanic(&cb.ConfigUpdateEnvelope{}),
+	}
+	return &cb.Envelope{Payload: utils.MarshalOrPanic(payload), Signature: []byte("signature")}
+}
+
+// mockAuthorizer authorizes the envelopes created by "reader" to read the channels, but secretchannel
+type mockAuthorizer struct{}
+
+func (mockAuthorizer) AuthorizeReader(chainID string, signedData []*cb.SignedData) (cb.Status, error) {
+	if chainID == "otherchannel" {
+		return cb.Status_NOT_FOUND, fmt.Errorf("channel %s was not found", chainID)
+	}
+	if len(signedData) != 1 || string(signedData[0].Identity) != "reader" || chainID == "secretchannel" {
+		return cb.Status_FORBIDDEN, fmt.Errorf("policy /Channel/Readers not satisfied")
+	}
+	return cb.Status_SUCCESS, nil
+}
+
+func (mockAuthorizer) AuthorizeAdmin(signedData []*cb.SignedData) (cb.Status, error) {
+	if len(signedData) != 1 || string(signedData[0].Identity) != "admin" {
+		return cb.Status_FORBIDDEN, fmt.Errorf("policy /Channel/Orderer/Admins not satisfied")
+	}
+	return cb.Status_SUCCESS, nil
+}
+
+// signedRequest returns the JSON envelope of the channel created by creator
+func signedRequest(t *testing.T, channelID, creator string) []byte {
+	env := &cb.Envelope{Payload: utils.MarshalOrPanic(&cb.Payload{Header: &cb.Header{
+		ChannelHeader:   utils.MarshalOrPanic(&cb.ChannelHeader{ChannelId: channelID}),
+		SignatureHeader: utils.MarshalOrPanic(&cb.SignatureHeader{Creator: []byte(creator)}),
+	}})}
+	var body bytes.Buffer
+	assert.NoError(t, protolator.DeepMarshalJSON(&body, env))
+	return body.Bytes()
+}
+
+// postSigned posts the JSON envelope of the channel created by creator to url
+func postSigned(t *testing.T, url, channelID, creator string) *http.Response {
+	resp, err := http.Post(url, "application/json", bytes.NewReader(signedRequest(t, channelID, creator)))
+	assert.NoError(t, err)
+	return resp
+}
+
+// readLines reads the JSON objects of a response, one per line
+func readLines(t *testing.T, body io.Reader) []map[string]interface{} {
+	var lines []map[string]interface{}
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		line := map[string]interface{}{}
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &line), "Lines should be JSON objects")
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestBroadcast(t *testing.T) {
+	server := httptest.NewServer(NewHandler(&mockServer{}, mockAuthorizer{}, nil, nil, nil, nil, nil, nil, nil))
+	defer server.Close()
+
+	t.Run("Success", func(t *testing.T) {
+		resp, err := http.Post(server.URL+"/broadcast", "application/json", strings.NewReader(marshalJSON(t, txEnvelope(t, "mychannel"))))
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, []map[string]interface{}{{"status": "SUCCESS"}}, readLines(t, resp.Body))
+	})
+
+	t.Run("CommitAck", func(t *testing.T) {
+		req, err := http.NewRequest("POST", server.URL+"/broadcast", strings.NewReader(marshalJSON(t, txEnvelope(t, "mychannel"))))
+		assert.NoError(t, err)
+		req.Header.Set(BroadcastModeHeader, broadcast.BroadcastModeCommitAck)
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		lines := readLines(t, resp.Body)
+		if assert.Len(t, lines, 2) {
+			assert.Equal(t, map[string]interface{}{"tx_id": "tx1", "block_number": "1"}, lines[1]["commit"])
+		}
+	})
+
+	t.Run("TraceID", func(t *testing.T) {
+		req, err := http.NewRequest("POST", server.URL+"/broadcast", strings.NewReader(marshalJSON(t, txEnvelope(t, "mychannel"))))
+		assert.NoError(t, err)
+		req.Header.Set(TraceIDHeader, "trace1")
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, []map[string]interface{}{{"status": "SUCCESS", "trace_id": "trace1"}}, readLines(t, resp.Body))
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		resp, err := http.Post(server.URL+"/broadcast", "application/json", strings.NewReader(marshalJSON(t, txEnvelope(t, "otherchannel"))))
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.Equal(t, []map[string]interface{}{{"status": "NOT_FOUND"}}, readLines(t, resp.Body))
+	})
+
+	t.Run("Unavailable", func(t *testing.T) {
+		resp, err := http.Post(server.URL+"/broadcast", "application/json", strings.NewReader(marshalJSON(t, txEnvelope(t, "busychannel"))))
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.Equal(t, "2", resp.Header.Get("Retry-After"), "Should have rounded the retry hint up to whole seconds")
+		assert.Equal(t, []map[string]interface{}{{
+			"status": "SERVICE_UNAVAILABLE",
+			"error":  map[string]interface{}{"class": "UNAVAILABLE", "retry_after_ms": "1500", "channel_state": "ACTIVE"},
+		}}, readLines(t, resp.Body))
+	})
+
+	t.Run("MalformedEnvelope", func(t *testing.T) {
+		resp, err := http.Post(server.URL+"/broadcast", "application/json", strings.NewReader(`{"payload": 1}`))
+		assert.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestDeliver(t *testing.T) {
+	garbage := &cb.Envelope{Payload: []byte("garbage")}
+	blocks := []*cb.Block{
+		newBlock(0),
+		newBlock(1, seekEnvelope(t, 0, 0)),
+		// Protolator cannot decode this transaction
+		newBlock(2, garbage),
+	}
+	server := httptest.NewServer(NewHandler(&mockServer{blocks: blocks}, mockAuthorizer{}, nil, nil, nil, nil, nil, nil, nil))
+	defer server.Close()
+
+	t.Run("Blocks", func(t *testing.T) {
+		resp, err := http.Post(server.URL+"/deliver", "application/json", strings.NewReader(marshalJSON(t, seekEnvelope(t, 1, 2))))
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		lines := readLines(t, resp.Body)
+		if !assert.Len(t, lines, 3) {
+			return
+		}
+
+		// The transactions are decoded
+		payload := lines[0]["block"].(map[string]interface{})["data"].(map[string]interface{})["data"].([]interface{})[0].(map[string]interface{})["payload"]
+		chdr := payload.(map[string]interface{})["header"].(map[string]interface{})["channel_header"].(map[string]interface{})
+		assert.Equal(t, "mychannel", chdr["channel_id"])
+
+		// Unless they are malformed
+		assert.Equal(t, []interface{}{base64.StdEncoding.EncodeToString(utils.MarshalOrPanic(garbage))}, lines[1]["block"].(map[string]interface{})["data"].(map[string]interface{})["data"])
+		assert.Equal(t, map[string]interface{}{"status": "SUCCESS"}, lines[2])
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		resp, err := http.Post(server.URL+"/deliver", "application/json", strings.NewReader(marshalJSON(t, seekEnvelope(t, 1, 5))))
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.Equal(t, []map[string]interface{}{{"status": "NOT_FOUND"}}, readLines(t, resp.Body))
+	})
+}
+
+func TestDeliverWebSocket(t *testing.T) {
+	server := httptest.NewServer(NewHandler(&mockServer{blocks: []*cb.Block{newBlock(0), newBlock(1)}}, mockAuthorizer{}, nil, nil, nil, nil, nil, nil, nil))
+	defer server.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/deliver"
+	conn, err := websocket.Dial(wsURL, "", server.URL)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer conn.Close()
+
+	receive := func() map[string]interface{} {
+		var msg []byte
+		assert.NoError(t, websocket.Message.Receive(conn, &msg))
+		resp := map[string]interface{}{}
+		assert.NoError(t, json.Unmarshal(msg, &resp))
+		return resp
+	}
+
+	// Several seeks may be sent over the same connection
+	for _, seek := range [][2]uint64{{0, 1}, {1, 1}} {
+		assert.NoError(t, websocket.Message.Send(conn, marshalJSON(t, seekEnvelope(t, seek[0], seek[1]))))
+		for number := seek[0]; number <= seek[1]; number++ {
+			header := receive()["block"].(map[string]interface{})["header"].(map[string]interface{})
+			if number > 0 {
+				assert.Equal(t, fmt.Sprint(number), header["number"])
+			}
+		}
+		assert.Equal(t, map[string]interface{}{"status": "SUCCESS"}, receive())
+	}
+
+	// Malformed seeks are answered with BAD_REQUEST
+	assert.NoError(t, websocket.Message.Send(conn, "{"))
+	assert.Equal(t, map[string]interface{}{"status": "BAD_REQUEST"}, receive())
+}
+
+func TestStatusCode(t *testing.T) {
+	assert.Equal(t, http.StatusServiceUnavailable, statusCode(cb.Status_SERVICE_UNAVAILABLE))
+	assert.Equal(t, http.StatusInternalServerError, statusCode(cb.Status_UNKNOWN))
+}
+
+type mockTraceLookup map[string]map[string][]*ab.TracePosition
+
+func (mtl mockTraceLookup) LookupTrace(chainID, traceID string) ([]*ab.TracePosition, bool) {
+	traces, ok := mtl[chainID]
+	return traces[traceID], ok
+}
+
+func TestTrace(t *testing.T) {
+	traces := mockTraceLookup{"mychannel": {"trace1": {
+		{TraceId: "trace1", BlockNumber: 3, TxIndex: 1},
+		{TraceId: "trace1", BlockNumber: 5},
+	}}}
+	server := httptest.NewServer(NewHandler(&mockServer{}, mockAuthorizer{}, traces, nil, nil, nil, nil, nil, nil))
+	defer server.Close()
+
+	resp := postSigned(t, server.URL+"/trace/mychannel/trace1", "mychannel", "reader")
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []map[string]interface{}{
+		{"trace_id": "trace1", "block_number": "3", "tx_index": "1"},
+		{"trace_id": "trace1", "block_number": "5"},
+	}, readLines(t, resp.Body))
+
+	for _, channelID := range []string{"mychannel", "otherchannel"} {
+		resp := postSigned(t, server.URL+"/trace/"+channelID+"/trace2", channelID, "reader")
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode, channelID)
+	}
+
+	// The envelope must be signed by a reader of the channel, and bound to it
+	resp = postSigned(t, server.URL+"/trace/mychannel/trace1", "mychannel", "stranger")
+	resp.Body.Close()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	resp = postSigned(t, server.URL+"/trace/mychannel/trace1", "yourchannel", "reader")
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	resp, err := http.Get(server.URL + "/trace/mychannel/trace1")
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode, "Should not have served the lookup without an envelope")
+
+	// The lookup is not served without traces
+	noTraces := httptest.NewServer(NewHandler(&mockServer{}, mockAuthorizer{}, nil, nil, nil, nil, nil, nil, nil))
+	defer noTraces.Close()
+	resp = postSigned(t, noTraces.URL+"/trace/mychannel/trace1", "mychannel", "reader")
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+type mockTxLookup map[string]map[string]*ab.CommitNotification
+
+func (mtl mockTxLookup) LocateTx(chainID, txID string) (*ab.CommitNotification, bool, error) {
+	if chainID == "brokenchannel" {
+		return nil, true, fmt.Errorf("not indexed")
+	}
+	txs, ok := mtl[chainID]
+	return txs[txID], ok, nil
+}
+
+func TestTx(t *testing.T) {
+	txs := mockTxLookup{"mychannel": {"tx1": {TxId: "tx1", BlockNumber: 3, TxIndex: 1}}}
+	server := httptest.NewServer(NewHandler(&mockServer{}, mockAuthorizer{}, nil, txs, nil, nil, nil, nil, nil))
+	defer server.Close()
+
+	resp := postSigned(t, server.URL+"/tx/mychannel/tx1", "mychannel", "reader")
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []map[string]interface{}{
+		{"tx_id": "tx1", "block_number": "3", "tx_index": "1"},
+	}, readLines(t, resp.Body))
+
+	for _, channelID := range []string{"mychannel", "otherchannel"} {
+		resp := postSigned(t, server.URL+"/tx/"+channelID+"/tx2", channelID, "reader")
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode, channelID)
+	}
+
+	resp = postSigned(t, server.URL+"/tx/brokenchannel/tx1", "brokenchannel", "reader")
+	resp.Body.Close()
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+
+	// The envelope must be signed by a reader of the channel, and bound to it
+	resp = postSigned(t, server.URL+"/tx/mychannel/tx1", "mychannel", "stranger")
+	resp.Body.Close()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	resp = postSigned(t, server.URL+"/tx/mychannel/tx1", "yourchannel", "reader")
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	// The lookup is not served without a transaction index
+	noTxs := httptest.NewServer(NewHandler(&mockServer{}, mockAuthorizer{}, nil, nil, nil, nil, nil, nil, nil))
+	defer noTxs.Close()
+	resp = postSigned(t, noTxs.URL+"/tx/mychannel/tx1", "mychannel", "reader")
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+type mockConfigSubscriber chan *ab.ConfigNotification
+
+func (mcs mockConfigSubscriber) SubscribeConfig(chainID string) (<-chan *ab.ConfigNotification, func()) {
+	return mcs, func() {}
+}
+
+func TestConfig(t *testing.T) {
+	// subscribe posts the envelope to path, and returns the response to the notifications, after which the
+	// subscriber is dropped
+	subscribe := func(path, channelID, creator string, notifications ...*ab.ConfigNotification) *http.Response {
+		configs := make(mockConfigSubscriber, len(notifications))
+		for _, notification := range notifications {
+			configs <- notification
+		}
+		close(configs)
+		server := httptest.NewServer(NewHandler(&mockServer{}, mockAuthorizer{}, nil, nil, configs, nil, nil, nil, nil))
+		defer server.Close()
+		return postSigned(t, server.URL+path, channelID, creator)
+	}
+
+	resp := subscribe("/config/mychannel", "mychannel", "reader",
+		&ab.ConfigNotification{ChannelId: "mychannel", BlockNumber: 3, Sequence: 2},
+		&ab.ConfigNotification{ChannelId: "mychannel", BlockNumber: 5, Sequence: 3})
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []map[string]interface{}{
+		{"channel_id": "mychannel", "block_number": "3", "sequence": "2"},
+		{"channel_id": "mychannel", "block_number": "5", "sequence": "3"},
+	}, readLines(t, resp.Body))
+
+	// The changes of the channels the signer may not read are skipped
+	resp = subscribe("/config", "", "reader",
+		&ab.ConfigNotification{ChannelId: "secretchannel", BlockNumber: 3, Sequence: 2},
+		&ab.ConfigNotification{ChannelId: "mychannel", BlockNumber: 5, Sequence: 3})
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []map[string]interface{}{
+		{"channel_id": "mychannel", "block_number": "5", "sequence": "3"},
+	}, readLines(t, resp.Body))
+
+	// The envelope must be signed by a reader of the channel subscribed to, and bound to it
+	resp = subscribe("/config/mychannel", "mychannel", "stranger")
+	resp.Body.Close()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	resp = subscribe("/config/mychannel", "yourchannel", "reader")
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	// The notifications are not served without a subscriber
+	noConfigs := httptest.NewServer(NewHandler(&mockServer{}, mockAuthorizer{}, nil, nil, nil, nil, nil, nil, nil))
+	defer noConfigs.Close()
+	resp = postSigned(t, noConfigs.URL+"/config", "", "reader")
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+type mockPolicyExplainer map[string]*cb.PolicyDecision
+
+func (mpe mockPolicyExplainer) ExplainPolicy(chainID, policyPath string, signedData []*cb.SignedData) (*cb.PolicyDecision, bool) {
+	if len(signedData) != 1 || string(signedData[0].Identity) != "reader" {
+		return &cb.PolicyDecision{Policy: policyPath, Error: "unexpected signed data"}, true
+	}
+	decision, ok := mpe[chainID+policyPath]
+	return decision, ok
+}
+
+func TestPolicy(t *testing.T) {
+	policies := mockPolicyExplainer{"mychannel/Channel/Writers": {
+		Policy: "/Channel/Writers",
+		Rule:   "ANY Writers",
+		Error:  "Failed to reach implicit threshold of 1 sub-policies, required 1 remaining",
+		SubDecisions: []*cb.PolicyDecision{{
+			Policy: "Application/Writers",
+			Rule:   "Reject",
+		}},
+	}}
+	server := httptest.NewServer(NewHandler(&mockServer{}, mockAuthorizer{}, nil, nil, nil, policies, nil, nil, nil))
+	defer server.Close()
+
+	resp := postSigned(t, server.URL+"/policy/mychannel/Channel/Writers", "mychannel", "reader")
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	lines := readLines(t, resp.Body)
+	if assert.Len(t, lines, 1) {
+		assert.Equal(t, "ANY Writers", lines[0]["rule"])
+		assert.Equal(t, []interface{}{map[string]interface{}{"policy": "Application/Writers", "rule": "Reject"}}, lines[0]["sub_decisions"])
+	}
+
+	resp = postSigned(t, server.URL+"/policy/mychannel/Channel/Admins", "mychannel", "reader")
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp, err := http.Post(server.URL+"/policy/mychannel/Channel/Writers", "application/json", strings.NewReader("{"))
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	// The envelope must be signed by a reader of the channel, and bound to it
+	resp = postSigned(t, server.URL+"/policy/mychannel/Channel/Writers", "mychannel", "stranger")
+	resp.Body.Close()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	resp = postSigned(t, server.URL+"/policy/mychannel/Channel/Writers", "yourchannel", "reader")
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	// The explanations are not served without an explainer
+	noPolicies := httptest.NewServer(NewHandler(&mockServer{}, mockAuthorizer{}, nil, nil, nil, nil, nil, nil, nil))
+	defer noPolicies.Close()
+	resp = postSigned(t, noPolicies.URL+"/policy/mychannel/Channel/Writers", "mychannel", "reader")
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+type mockUsageReporter ab.UsageReport
+
+func (mur *mockUsageReporter) Report() *ab.UsageReport {
+	return (*ab.UsageReport)(mur)
+}
+
+func TestUsage(t *testing.T) {
+	usage := &mockUsageReporter{
+		Since: &timestamp.Timestamp{Seconds: 1500000000},
+		Channels: []*ab.TenantUsage{{
+			ChannelId:  "mychannel",
+			Consortium: "SampleConsortium",
+			Total:      &ab.Usage{Transactions: 10, Bytes: 1000, Blocks: 2},
+		}},
+	}
+	server := httptest.NewServer(NewHandler(&mockServer{}, mockAuthorizer{}, nil, nil, nil, nil, usage, nil, nil))
+	defer server.Close()
+
+	resp := postSigned(t, server.URL+"/usage", "system", "admin")
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	lines := readLines(t, resp.Body)
+	if assert.Len(t, lines, 1) {
+		assert.Equal(t, "2017-07-14T02:40:00.000Z", lines[0]["since"])
+		assert.Equal(t, []interface{}{map[string]interface{}{
+			"channel_id": "mychannel",
+			"consortium": "SampleConsortium",
+			"total":      map[string]interface{}{"transactions": "10", "bytes": "1000", "blocks": "2"},
+		}}, lines[0]["channels"])
+	}
+
+	// The envelope must be signed by an administrator of the orderers
+	resp = postSigned(t, server.URL+"/usage", "system", "reader")
+	resp.Body.Close()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	// The usage is not served without a reporter
+	noUsage := httptest.NewServer(NewHandler(&mockServer{}, mockAuthorizer{}, nil, nil, nil, nil, nil, nil, nil))
+	defer noUsage.Close()
+	resp = postSigned(t, noUsage.URL+"/usage", "system", "admin")
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+type mockConfigValidator struct{}
+
+func (mockConfigValidator) ValidateConfigUpdate(env *cb.Envelope) *ab.ConfigUpdateValidation {
+	payload, err := utils.UnmarshalPayload(env.Payload)
+	if err != nil || payload.Header == nil {
+		return &ab.ConfigUpdateValidation{Class: ab.BroadcastError_MALFORMED, Message: "malformed envelope"}
+	}
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil || chdr.ChannelId != "mychannel" {
+		return &ab.ConfigUpdateValidation{ChannelId: chdr.ChannelId, Class: ab.BroadcastError_REJECTED, Message: "policy not satisfied"}
+	}
+	return &ab.ConfigUpdateValidation{ChannelId: chdr.ChannelId, Valid: true, Config: &cb.Config{Sequence: 4}}
+}
+
+func TestValidateConfigUpdate(t *testing.T) {
+	server := httptest.NewServer(NewHandler(&mockServer{}, mockAuthorizer{}, nil, nil, nil, nil, nil, mockConfigValidator{}, nil))
+	defer server.Close()
+
+	post := func(channelID string) *http.Response {
+		env := &cb.Envelope{Payload: utils.MarshalOrPanic(&cb.Payload{Header: &cb.Header{
+			ChannelHeader: utils.MarshalOrPanic(&cb.ChannelHeader{Type: int32(cb.HeaderType_CONFIG_UPDATE), ChannelId: channelID}),
+		}})}
+		var body bytes.Buffer
+		assert.NoError(t, protolator.DeepMarshalJSON(&body, env))
+		resp, err := http.Post(server.URL+"/validate/configupdate", "application/json", bytes.NewReader(body.Bytes()))
+		assert.NoError(t, err)
+		return resp
+	}
+
+	resp := post("mychannel")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	lines := readLines(t, resp.Body)
+	resp.Body.Close()
+	if assert.Len(t, lines, 1) {
+		assert.Equal(t, true, lines[0]["valid"])
+		assert.Equal(t, "4", lines[0]["config"].(map[string]interface{})["sequence"])
+	}
+
+	resp = post("otherchannel")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	lines = readLines(t, resp.Body)
+	resp.Body.Close()
+	if assert.Len(t, lines, 1) {
+		assert.Nil(t, lines[0]["valid"])
+		assert.Equal(t, "REJECTED", lines[0]["class"])
+		assert.Equal(t, "policy not satisfied", lines[0]["message"])
+	}
+
+	resp, err := http.Post(server.URL+"/validate/configupdate", "application/json", strings.NewReader("{"))
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	// The config updates are not validated without a validator
+	noUpdates := httptest.NewServer(NewHandler(&mockServer{}, mockAuthorizer{}, nil, nil, nil, nil, nil, nil, nil))
+	defer noUpdates.Close()
+	resp, err = http.Post(noUpdates.URL+"/validate/configupdate", "application/json", strings.NewReader("{}"))
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+type mockChainPauser map[string]bool
+
+func (mcp mockChainPauser) PauseChain(chainID string, pause bool, signedData []*cb.SignedData) (cb.Status, error) {
+	if _, ok := mcp[chainID]; !ok {
+		return cb.Status_NOT_FOUND, fmt.Errorf("channel %s not found", chainID)
+	}
+	if len(signedData) != 1 || string(signedData[0].Identity) != "admin" {
+		return cb.Status_FORBIDDEN, fmt.Errorf("policy not satisfied")
+	}
+	mcp[chainID] = pause
+	return cb.Status_SUCCESS, nil
+}
+
+func TestPause(t *testing.T) {
+	pausers := mockChainPauser{"mychannel": false}
+	server := httptest.NewServer(NewHandler(&mockServer{}, mockAuthorizer{}, nil, nil, nil, nil, nil, nil, pausers))
+	defer server.Close()
+
+	post := func(path, channelID, creator string) *http.Response {
+		env := &cb.Envelope{Payload: utils.MarshalOrPanic(&cb.Payload{Header: &cb.Header{
+			ChannelHeader:   utils.MarshalOrPanic(&cb.ChannelHeader{Type: int32(cb.HeaderType_MESSAGE), ChannelId: channelID}),
+			SignatureHeader: utils.MarshalOrPanic(&cb.SignatureHeader{Creator: []byte(creator)}),
+		}})}
+		var body bytes.Buffer
+		assert.NoError(t, protolator.DeepMarshalJSON(&body, env))
+		resp, err := http.Post(server.URL+path, "application/json", bytes.NewReader(body.Bytes()))
+		assert.NoError(t, err)
+		return resp
+	}
+
+	resp := post("/admin/pause/mychannel", "mychannel", "admin")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	lines := readLines(t, resp.Body)
+	resp.Body.Close()
+	if assert.Len(t, lines, 1) {
+		assert.Equal(t, map[string]interface{}{"channel_id": "mychannel", "paused": true}, lines[0])
+	}
+	assert.True(t, pausers["mychannel"], "Should have paused the channel")
+
+	resp = post("/admin/resume/mychannel", "mychannel", "admin")
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.False(t, pausers["mychannel"], "Should have resumed the channel")
+
+	resp = post("/admin/pause/mychannel", "mychannel", "client")
+	resp.Body.Close()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.False(t, pausers["mychannel"])
+
+	resp = post("/admin/pause/mychannel", "otherchannel", "admin")
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode, "Should have rejected the envelope of another channel")
+
+	resp = post("/admin/pause/otherchannel", "otherchannel", "admin")
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp = post("/admin/halt/mychannel", "mychannel", "admin")
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	// The chains cannot be paused without a pauser
+	noPausers := httptest.NewServer(NewHandler(&mockServer{}, mockAuthorizer{}, nil, nil, nil, nil, nil, nil, nil))
+	defer noPausers.Close()
+	resp, err := http.Post(noPausers.URL+"/admin/pause/mychannel", "application/json", strings.NewReader("{}"))
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
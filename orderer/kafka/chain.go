@@ -9,10 +9,12 @@ package kafka
 import (
 	"fmt"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/Shopify/sarama"
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/config"
 	localconfig "github.com/hyperledger/fabric/orderer/localconfig"
 	"github.com/hyperledger/fabric/orderer/multichain"
 	cb "github.com/hyperledger/fabric/protos/common"
@@ -49,10 +51,13 @@ func newChain(consenter commonConsenter, support multichain.ConsenterSupport, la
 		channel:             newChannel(support.ChainID(), defaultPartition),
 		lastOffsetPersisted: lastOffsetPersisted,
 		lastCutBlockNumber:  lastCutBlockNumber,
+		clock:               wallClock{},
 
 		errorChan: errorChan,
 		haltChan:  make(chan struct{}),
 		startChan: make(chan struct{}),
+		exitChan:  make(chan struct{}),
+		pauseChan: make(chan pauseRequest),
 	}, nil
 }
 
@@ -64,9 +69,22 @@ type chainImpl struct {
 	lastOffsetPersisted int64
 	lastCutBlockNumber  uint64
 
+	// Starts the batch and heartbeat timers. Left nil, the wall clock is used.
+	clock clock
+	// Fraction of the batch timeout by which the batch timers are delayed at
+	// random, see newJitterClock.
+	batchTimeoutJitter float64
+
+	// The brokers the Kafka clients were set up against. When a config block
+	// changes the brokers of the channel, the clients are set up again, see
+	// rebootstrap.
+	brokers         []string
 	producer        sarama.SyncProducer
 	parentConsumer  sarama.Consumer
 	channelConsumer sarama.PartitionConsumer
+	// Guards the Kafka clients, which the processMessagesToBlocks loop swaps,
+	// against their use by Enqueue and Halt.
+	clientLock sync.RWMutex
 
 	// When the partition consumer errors, close the channel. Otherwise, make
 	// this an open, unbuffered channel.
@@ -77,6 +95,13 @@ type chainImpl struct {
 	haltChan chan struct{}
 	// // Close when the retriable steps in Start have completed.
 	startChan chan struct{}
+	// Closed when the processMessagesToBlocks loop has exited.
+	exitChan chan struct{}
+	// Carries the Pause() and Resume() requests to the processMessagesToBlocks
+	// loop, which serves them.
+	pauseChan chan pauseRequest
+	// Set to 1 while the chain is paused, accessed atomically.
+	paused int32
 }
 
 // Errored returns a channel which will close when a partition consumer error
@@ -113,7 +138,7 @@ func (chain *chainImpl) Halt() {
 
 // Enqueue accepts a message and returns true on acceptance, or false otheriwse.
 // Implements the multichain.Chain interface. Called by Broadcast().
-func (chain *chainImpl) Enqueue(env *cb.Envelope) bool {
+func (chain *chainImpl) Enqueue(env *cb.Envelope, traceID string) bool {
 	logger.Debugf("[channel: %s] Enqueueing envelope...", chain.support.ChainID())
 	select {
 	case <-chain.startChan: // The Start phase has completed
@@ -122,15 +147,26 @@ func (chain *chainImpl) Enqueue(env *cb.Envelope) bool {
 			logger.Warningf("[channel: %s] Will not enqueue, consenter for this channel has been halted", chain.support.ChainID())
 			return false
 		default: // The post path
+			if chain.Paused() {
+				logger.Warningf("[channel: %s] Will not enqueue, consenter for this channel has been paused", chain.support.ChainID())
+				return false
+			}
 			marshaledEnv, err := utils.Marshal(env)
 			if err != nil {
 				logger.Errorf("[channel: %s] cannot enqueue, unable to marshal envelope = %s", chain.support.ChainID(), err)
 				return false
 			}
 			// We're good to go
-			payload := utils.MarshalOrPanic(newRegularMessage(marshaledEnv))
+			regularMessage := newRegularMessage(marshaledEnv)
+			// Carry the trace ID and the time of receipt to all the orderers of the channel
+			regularMessage.GetRegular().TraceId = traceID
+			regularMessage.GetRegular().ReceivedAt = time.Now().UnixNano()
+			payload := utils.MarshalOrPanic(regularMessage)
 			message := newProducerMessage(chain.channel, payload)
-			if _, _, err := chain.producer.SendMessage(message); err != nil {
+			chain.clientLock.RLock()
+			_, _, err = chain.producer.SendMessage(message)
+			chain.clientLock.RUnlock()
+			if err != nil {
 				logger.Errorf("[channel: %s] cannot enqueue envelope = %s", chain.support.ChainID(), err)
 				return false
 			}
@@ -148,11 +184,15 @@ func startThread(chain *chainImpl) {
 	var err error
 
 	// Set up the producer
-	chain.producer, err = setupProducerForChannel(chain.consenter.retryOptions(), chain.haltChan, chain.support.SharedConfig().KafkaBrokers(), chain.consenter.brokerConfig(), chain.channel)
+	chain.brokers = chain.support.SharedConfig().KafkaBrokers()
+	chain.producer, err = setupProducerForChannel(chain.consenter.retryOptions(), chain.haltChan, chain.brokers, chain.consenter.brokerConfig(), chain.channel)
 	if err != nil {
 		logger.Panicf("[channel: %s] Cannot set up producer = %s", chain.channel.topic(), err)
 	}
 	logger.Infof("[channel: %s] Producer set up successfully", chain.support.ChainID())
+	if wrapper := chain.consenter.clientWrapper(); wrapper != nil {
+		chain.producer = wrapper.WrapProducer(chain.support.ChainID(), chain.producer)
+	}
 
 	// Have the producer post the CONNECT message
 	if err = sendConnectMessage(chain.consenter.retryOptions(), chain.haltChan, chain.producer, chain.channel); err != nil {
@@ -161,11 +201,14 @@ func startThread(chain *chainImpl) {
 	logger.Infof("[channel: %s] CONNECT message posted successfully", chain.channel.topic())
 
 	// Set up the parent consumer
-	chain.parentConsumer, err = setupParentConsumerForChannel(chain.consenter.retryOptions(), chain.haltChan, chain.support.SharedConfig().KafkaBrokers(), chain.consenter.brokerConfig(), chain.channel)
+	chain.parentConsumer, err = setupParentConsumerForChannel(chain.consenter.retryOptions(), chain.haltChan, chain.brokers, chain.consenter.brokerConfig(), chain.channel)
 	if err != nil {
 		logger.Panicf("[channel: %s] Cannot set up parent consumer = %s", chain.channel.topic(), err)
 	}
 	logger.Infof("[channel: %s] Parent consumer set up successfully", chain.channel.topic())
+	if wrapper := chain.consenter.clientWrapper(); wrapper != nil {
+		chain.parentConsumer = wrapper.WrapConsumer(chain.support.ChainID(), chain.parentConsumer)
+	}
 
 	// Set up the channel consumer
 	chain.channelConsumer, err = setupChannelConsumerForChannel(chain.consenter.retryOptions(), chain.haltChan, chain.parentConsumer, chain.channel, chain.lastOffsetPersisted+1)
@@ -180,6 +223,7 @@ func startThread(chain *chainImpl) {
 	logger.Infof("[channel: %s] Start phase completed successfully", chain.channel.topic())
 
 	chain.processMessagesToBlocks() // Keep up to date with the channel
+	close(chain.exitChan)
 }
 
 // processMessagesToBlocks drains the Kafka consumer for the given channel, and
@@ -189,6 +233,28 @@ func (chain *chainImpl) processMessagesToBlocks() ([]uint64, error) {
 	counts := make([]uint64, 11) // For metrics and tests
 	msg := new(ab.KafkaMessage)
 	var timer <-chan time.Time
+	clock := chain.clock
+	if clock == nil {
+		clock = wallClock{}
+	}
+	heartbeat := newHeartbeatTimer(chain.support, clock)
+	// The batch timers are jittered, so that typically a single orderer posts
+	// the time-to-cut message and the others stop their timers on receiving it
+	batchClock := newJitterClock(clock, chain.batchTimeoutJitter)
+	// The offset of the last message consumed, from which a paused chain resumes
+	lastOffsetConsumed := chain.lastOffsetPersisted
+	// Whether the batch timer was running when the chain was paused
+	var batchPending bool
+	// Started when the clients cannot be set up against changed brokers
+	var rebootstrapRetry <-chan time.Time
+	rebootstrap := func() {
+		rebootstrapRetry = nil
+		if err := chain.rebootstrap(lastOffsetConsumed); err != nil {
+			logger.Errorf("[channel: %s] Cannot switch to the new Kafka brokers, retrying in %s = %s",
+				chain.support.ChainID(), chain.consenter.retryOptions().ShortInterval, err)
+			rebootstrapRetry = clock.After(chain.consenter.retryOptions().ShortInterval)
+		}
+	}
 
 	defer func() { // When Halt() is called
 		select {
@@ -226,6 +292,7 @@ func (chain *chainImpl) processMessagesToBlocks() ([]uint64, error) {
 				logger.Criticalf("[channel: %s] Kafka consumer closed.", chain.support.ChainID())
 				return counts, nil
 			}
+			lastOffsetConsumed = in.Offset
 			select {
 			case <-chain.errorChan: // If this channel was closed...
 				chain.errorChan = make(chan struct{}) // ...make a new one.
@@ -241,6 +308,7 @@ func (chain *chainImpl) processMessagesToBlocks() ([]uint64, error) {
 				logger.Debugf("[channel: %s] Successfully unmarshalled consumed message, offset is %d. Inspecting type...", chain.support.ChainID(), in.Offset)
 				counts[indexRecvPass]++
 			}
+			lastCutBlockNumber := chain.lastCutBlockNumber
 			switch msg.Type.(type) {
 			case *ab.KafkaMessage_Connect:
 				_ = processConnect(chain.support.ChainID())
@@ -254,13 +322,27 @@ func (chain *chainImpl) processMessagesToBlocks() ([]uint64, error) {
 				}
 				counts[indexProcessTimeToCutPass]++
 			case *ab.KafkaMessage_Regular:
-				if err := processRegular(msg.GetRegular(), chain.support, &timer, in.Offset, &chain.lastCutBlockNumber); err != nil {
+				if err := processRegular(msg.GetRegular(), chain.support, batchClock, &timer, in.Offset, &chain.lastCutBlockNumber); err != nil {
 					logger.Warningf("[channel: %s] Error when processing incoming message of type REGULAR = %s", chain.support.ChainID(), err)
 					counts[indexProcessRegularError]++
 				} else {
 					counts[indexProcessRegularPass]++
 				}
 			}
+			if chain.lastCutBlockNumber != lastCutBlockNumber {
+				// The block schedule counts from the last block cut
+				heartbeat = newHeartbeatTimer(chain.support, clock)
+				// A config block may have changed the brokers
+				if chain.brokersChanged() {
+					rebootstrap()
+				}
+			}
+		case <-rebootstrapRetry:
+			if chain.brokersChanged() {
+				rebootstrap()
+			} else {
+				rebootstrapRetry = nil
+			}
 		case <-timer:
 			if err := sendTimeToCut(chain.producer, chain.channel, chain.lastCutBlockNumber+1, &timer); err != nil {
 				logger.Errorf("[channel: %s] cannot post time-to-cut message = %s", chain.support.ChainID(), err)
@@ -269,6 +351,39 @@ func (chain *chainImpl) processMessagesToBlocks() ([]uint64, error) {
 			} else {
 				counts[indexSendTimeToCutPass]++
 			}
+		case <-heartbeat:
+			if err := sendHeartbeat(chain.producer, chain.channel, chain.lastCutBlockNumber+1, &heartbeat); err != nil {
+				logger.Errorf("[channel: %s] cannot post heartbeat time-to-cut message, retrying in %s = %s",
+					chain.support.ChainID(), chain.consenter.retryOptions().ShortInterval, err)
+				// Unlike the batch timer, which the next message restarts, nothing else would
+				// restart the heartbeat timer of an idle channel
+				heartbeat = clock.After(chain.consenter.retryOptions().ShortInterval)
+				counts[indexSendTimeToCutError]++
+			} else {
+				counts[indexSendTimeToCutPass]++
+			}
+		case req := <-chain.pauseChan:
+			if req.pause == chain.Paused() {
+				req.done <- nil // Already in the requested state
+				continue
+			}
+			if req.pause {
+				batchPending = timer != nil
+				timer, heartbeat = nil, nil
+				chain.pause(lastOffsetConsumed)
+				req.done <- nil
+				continue
+			}
+			if err := chain.resume(lastOffsetConsumed + 1); err != nil {
+				// Still paused, or halted while resuming
+				req.done <- err
+				continue
+			}
+			heartbeat = newHeartbeatTimer(chain.support, clock)
+			if batchPending {
+				timer = batchClock.After(chain.support.SharedConfig().BatchTimeout())
+			}
+			req.done <- nil
 		}
 	}
 }
@@ -276,6 +391,9 @@ func (chain *chainImpl) processMessagesToBlocks() ([]uint64, error) {
 func (chain *chainImpl) closeKafkaObjects() []error {
 	var errs []error
 
+	chain.clientLock.Lock()
+	defer chain.clientLock.Unlock()
+
 	err := chain.channelConsumer.Close()
 	if err != nil {
 		logger.Errorf("[channel: %s] could not close channelConsumer cleanly = %s", chain.support.ChainID(), err)
@@ -310,18 +428,26 @@ func getLastCutBlockNumber(blockchainHeight uint64) uint64 {
 }
 
 func getLastOffsetPersisted(metadataValue []byte, chainID string) int64 {
-	if metadataValue != nil {
-		// Extract orderer-related metadata from the tip of the ledger first
-		kafkaMetadata := &ab.KafkaMetadata{}
-		if err := proto.Unmarshal(metadataValue, kafkaMetadata); err != nil {
-			logger.Panicf("[channel: %s] Ledger may be corrupted:"+
-				"cannot unmarshal orderer metadata in most recent block", chainID)
-		}
+	if kafkaMetadata := getKafkaMetadata(metadataValue, chainID); kafkaMetadata != nil {
 		return kafkaMetadata.LastOffsetPersisted
 	}
 	return (sarama.OffsetOldest - 1) // default
 }
 
+// getKafkaMetadata returns the orderer-related metadata of a block, or nil if
+// it has none, as is the case of the genesis block
+func getKafkaMetadata(metadataValue []byte, chainID string) *ab.KafkaMetadata {
+	if metadataValue == nil {
+		return nil
+	}
+	kafkaMetadata := &ab.KafkaMetadata{}
+	if err := proto.Unmarshal(metadataValue, kafkaMetadata); err != nil {
+		logger.Panicf("[channel: %s] Ledger may be corrupted:"+
+			"cannot unmarshal orderer metadata in most recent block", chainID)
+	}
+	return kafkaMetadata
+}
+
 func newConnectMessage() *ab.KafkaMessage {
 	return &ab.KafkaMessage{
 		Type: &ab.KafkaMessage_Connect{
@@ -352,6 +478,17 @@ func newTimeToCutMessage(blockNumber uint64) *ab.KafkaMessage {
 	}
 }
 
+func newHeartbeatMessage(blockNumber uint64) *ab.KafkaMessage {
+	return &ab.KafkaMessage{
+		Type: &ab.KafkaMessage_TimeToCut{
+			TimeToCut: &ab.KafkaMessageTimeToCut{
+				BlockNumber: blockNumber,
+				Heartbeat:   true,
+			},
+		},
+	}
+}
+
 func newProducerMessage(channel channel, pld []byte) *sarama.ProducerMessage {
 	return &sarama.ProducerMessage{
 		Topic: channel.topic(),
@@ -365,16 +502,21 @@ func processConnect(channelName string) error {
 	return nil
 }
 
-func processRegular(regularMessage *ab.KafkaMessageRegular, support multichain.ConsenterSupport, timer *<-chan time.Time, receivedOffset int64, lastCutBlockNumber *uint64) error {
+func processRegular(regularMessage *ab.KafkaMessageRegular, support multichain.ConsenterSupport, clock clock, timer *<-chan time.Time, receivedOffset int64, lastCutBlockNumber *uint64) error {
 	env := new(cb.Envelope)
 	if err := proto.Unmarshal(regularMessage.Payload, env); err != nil {
 		// This shouldn't happen, it should be filtered at ingress
 		return fmt.Errorf("unmarshal/%s", err)
 	}
+	var received time.Time
+	if regularMessage.ReceivedAt != 0 {
+		received = time.Unix(0, regularMessage.ReceivedAt)
+	}
+	support.Trace(env, regularMessage.TraceId, received)
 	batches, committers, ok, pending := support.BlockCutter().Ordered(env)
 	logger.Debugf("[channel: %s] Ordering results: items in batch = %d, ok = %v, pending = %v", support.ChainID(), len(batches), ok, pending)
 	if ok && len(batches) == 0 && *timer == nil {
-		*timer = time.After(support.SharedConfig().BatchTimeout())
+		*timer = clock.After(support.SharedConfig().BatchTimeout())
 		logger.Debugf("[channel: %s] Just began %s batch timer", support.ChainID(), support.SharedConfig().BatchTimeout().String())
 		return nil
 	}
@@ -389,7 +531,7 @@ func processRegular(regularMessage *ab.KafkaMessageRegular, support multichain.C
 	// If !ok, batches == nil, so this will be skipped
 	for i, batch := range batches {
 		block := support.CreateNextBlock(batch)
-		encodedLastOffsetPersisted := utils.MarshalOrPanic(&ab.KafkaMetadata{LastOffsetPersisted: offset})
+		encodedLastOffsetPersisted := utils.MarshalOrPanic(&ab.KafkaMetadata{LastOffsetPersisted: offset, LastCutBlockNumber: *lastCutBlockNumber + 1})
 		support.WriteBlock(block, committers[i], encodedLastOffsetPersisted)
 		*lastCutBlockNumber++
 		logger.Debugf("[channel: %s] Batch filled, just cut block %d - last persisted offset is now %d", support.ChainID(), *lastCutBlockNumber, offset)
@@ -409,12 +551,17 @@ func processTimeToCut(ttcMessage *ab.KafkaMessageTimeToCut, support multichain.C
 		*timer = nil
 		logger.Debugf("[channel: %s] Nil'd the timer", support.ChainID())
 		batch, committers := support.BlockCutter().Cut()
-		if len(batch) == 0 {
+		if len(batch) == 0 && ttcMessage.GetHeartbeat() && !support.SharedConfig().HasCapability(config.BlockScheduleCapability) {
+			// A heartbeat posted before the capability was disabled cuts no empty block
+			logger.Debugf("[channel: %s] Ignoring the heartbeat for block %d without the BlockSchedule capability", support.ChainID(), ttcNumber)
+			return nil
+		}
+		if len(batch) == 0 && !ttcMessage.GetHeartbeat() {
 			return fmt.Errorf("got right time-to-cut message (for block %d),"+
 				" no pending requests though; this might indicate a bug", *lastCutBlockNumber+1)
 		}
 		block := support.CreateNextBlock(batch)
-		encodedLastOffsetPersisted := utils.MarshalOrPanic(&ab.KafkaMetadata{LastOffsetPersisted: receivedOffset})
+		encodedLastOffsetPersisted := utils.MarshalOrPanic(&ab.KafkaMetadata{LastOffsetPersisted: receivedOffset, LastCutBlockNumber: *lastCutBlockNumber + 1})
 		support.WriteBlock(block, committers, encodedLastOffsetPersisted)
 		*lastCutBlockNumber++
 		logger.Debugf("[channel: %s] Proper time-to-cut received, just cut block %d", support.ChainID(), *lastCutBlockNumber)
@@ -454,6 +601,38 @@ func sendTimeToCut(producer sarama.SyncProducer, channel channel, timeToCutBlock
 	return err
 }
 
+// sendHeartbeat posts the time-to-cut message of the block due by the block
+// schedule of the channel. Every orderer of the channel posts its own, and
+// the ones received after the block is cut are ignored as stale.
+//
+// An orderer predating block schedules would drop the heartbeat flag of the
+// message, and refuse to cut an empty block on it while the others do, so the
+// heartbeats are only posted, and cut into empty blocks, once the
+// BlockSchedule capability of the channel is enabled.
+func sendHeartbeat(producer sarama.SyncProducer, channel channel, timeToCutBlockNumber uint64, heartbeat *<-chan time.Time) error {
+	logger.Debugf("[channel: %s] Block schedule due for block %d", channel.topic(), timeToCutBlockNumber)
+	*heartbeat = nil
+	payload := utils.MarshalOrPanic(newHeartbeatMessage(timeToCutBlockNumber))
+	message := newProducerMessage(channel, payload)
+	_, _, err := producer.SendMessage(message)
+	return err
+}
+
+// newHeartbeatTimer starts the timer of the block due by the block schedule
+// of the channel, counting from now as a block was just cut, or returns nil if
+// the channel has no schedule or lacks the BlockSchedule capability.
+func newHeartbeatTimer(support multichain.ConsenterSupport, clock clock) <-chan time.Time {
+	if !support.SharedConfig().HasCapability(config.BlockScheduleCapability) {
+		return nil
+	}
+	now := time.Now()
+	next := support.SharedConfig().BlockSchedule().NextHeartbeat(now)
+	if next.IsZero() {
+		return nil
+	}
+	return clock.After(next.Sub(now))
+}
+
 // Sets up the partition consumer for a channel using the given retry options.
 func setupChannelConsumerForChannel(retryOptions localconfig.Retry, haltChan chan struct{}, parentConsumer sarama.Consumer, channel channel, startFrom int64) (sarama.PartitionConsumer, error) {
 	var err error
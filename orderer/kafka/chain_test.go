@@ -13,6 +13,7 @@ import (
 
 	"github.com/Shopify/sarama"
 	"github.com/Shopify/sarama/mocks"
+	"github.com/hyperledger/fabric/common/config"
 	mockconfig "github.com/hyperledger/fabric/common/mocks/config"
 	mockblockcutter "github.com/hyperledger/fabric/orderer/mocks/blockcutter"
 	mockmultichain "github.com/hyperledger/fabric/orderer/mocks/multichain"
@@ -30,6 +31,27 @@ var (
 	hitBranch = 50 * time.Millisecond
 )
 
+// mockClock hands the batch timers of a chain to the test, which fires them
+type mockClock struct {
+	timers chan chan time.Time
+}
+
+func newMockClock() *mockClock {
+	return &mockClock{timers: make(chan chan time.Time, 1)}
+}
+
+func (c *mockClock) After(d time.Duration) <-chan time.Time {
+	timer := make(chan time.Time)
+	c.timers <- timer
+	return timer
+}
+
+// fire waits for the chain to start a timer and fires it. It returns once the
+// chain has received the expiration
+func (c *mockClock) fire() {
+	(<-c.timers) <- time.Now()
+}
+
 func TestChain(t *testing.T) {
 
 	oldestOffset := int64(0)
@@ -216,7 +238,7 @@ func TestChain(t *testing.T) {
 				SetMessage(mockChannel.topic(), mockChannel.partition(), newestOffset, message),
 		})
 
-		assert.False(t, chain.Enqueue(newMockEnvelope("fooMessage")), "Expected Enqueue call to return false")
+		assert.False(t, chain.Enqueue(newMockEnvelope("fooMessage"), ""), "Expected Enqueue call to return false")
 	})
 
 	t.Run("StartWithConsumerForChannelError", func(t *testing.T) {
@@ -275,7 +297,7 @@ func TestChain(t *testing.T) {
 
 		// Enqueue should have access to the post path, and its ProduceRequest
 		// should go by without error
-		assert.True(t, chain.Enqueue(newMockEnvelope("fooMessage")), "Expected Enqueue call to return true")
+		assert.True(t, chain.Enqueue(newMockEnvelope("fooMessage"), ""), "Expected Enqueue call to return true")
 
 		chain.Halt()
 	})
@@ -308,7 +330,7 @@ func TestChain(t *testing.T) {
 		chain.Halt()
 
 		// haltChan should close access to the post path
-		assert.False(t, chain.Enqueue(newMockEnvelope("fooMessage")), "Expected Enqueue call to return false")
+		assert.False(t, chain.Enqueue(newMockEnvelope("fooMessage"), ""), "Expected Enqueue call to return false")
 	})
 
 	t.Run("EnqueueError", func(t *testing.T) {
@@ -345,7 +367,7 @@ func TestChain(t *testing.T) {
 				SetError(mockChannel.topic(), mockChannel.partition(), sarama.ErrNotLeaderForPartition),
 		})
 
-		assert.False(t, chain.Enqueue(newMockEnvelope("fooMessage")), "Expected Enqueue call to return false")
+		assert.False(t, chain.Enqueue(newMockEnvelope("fooMessage"), ""), "Expected Enqueue call to return false")
 	})
 }
 
@@ -436,7 +458,8 @@ func TestCloseKafkaObjects(t *testing.T) {
 	mockChannel := newChannel(channelNameForTest(t), defaultPartition)
 
 	mockSupport := &mockmultichain.ConsenterSupport{
-		ChainIDVal: mockChannel.topic(),
+		ChainIDVal:      mockChannel.topic(),
+		SharedConfigVal: &mockconfig.Orderer{},
 	}
 
 	oldestOffset := int64(0)
@@ -658,6 +681,31 @@ func TestSendTimeToCut(t *testing.T) {
 	})
 }
 
+func TestProcessRegularTraceID(t *testing.T) {
+	mockSupport := &mockmultichain.ConsenterSupport{
+		Blocks:          make(chan *cb.Block, 1),
+		BlockCutterVal:  mockblockcutter.NewReceiver(),
+		SharedConfigVal: &mockconfig.Orderer{BatchTimeoutVal: longTimeout},
+	}
+	close(mockSupport.BlockCutterVal.Block)
+	mockSupport.BlockCutterVal.CutNext = true
+
+	regularMessage := newRegularMessage(utils.MarshalOrPanic(newMockEnvelope("fooMessage"))).GetRegular()
+	regularMessage.TraceId = "trace1"
+	regularMessage.ReceivedAt = time.Unix(1500000000, 0).UnixNano()
+	var timer <-chan time.Time
+	lastCutBlockNumber := uint64(0)
+	assert.NoError(t, processRegular(regularMessage, mockSupport, wallClock{}, &timer, 1, &lastCutBlockNumber))
+	<-mockSupport.Blocks
+	assert.Equal(t, []string{"trace1"}, mockSupport.TraceIDsVal, "Expected the trace ID carried by the message to be recorded")
+	assert.Equal(t, []time.Time{time.Unix(1500000000, 0)}, mockSupport.ReceivedVal, "Expected the receipt time carried by the message to be recorded")
+
+	regularMessage.ReceivedAt = 0
+	assert.NoError(t, processRegular(regularMessage, mockSupport, wallClock{}, &timer, 2, &lastCutBlockNumber))
+	<-mockSupport.Blocks
+	assert.True(t, mockSupport.ReceivedVal[1].IsZero(), "Expected no receipt time for the messages of the orderers which do not carry it")
+}
+
 func TestProcessMessagesToBlocks(t *testing.T) {
 	mockBroker := sarama.NewMockBroker(t, 0)
 	defer func() { mockBroker.Close() }()
@@ -687,7 +735,8 @@ func TestProcessMessagesToBlocks(t *testing.T) {
 		haltChan := make(chan struct{})
 
 		mockSupport := &mockmultichain.ConsenterSupport{
-			ChainIDVal: mockChannel.topic(),
+			ChainIDVal:      mockChannel.topic(),
+			SharedConfigVal: &mockconfig.Orderer{},
 		}
 
 		bareMinimumChain := &chainImpl{
@@ -728,7 +777,8 @@ func TestProcessMessagesToBlocks(t *testing.T) {
 		haltChan := make(chan struct{})
 
 		mockSupport := &mockmultichain.ConsenterSupport{
-			ChainIDVal: mockChannel.topic(),
+			ChainIDVal:      mockChannel.topic(),
+			SharedConfigVal: &mockconfig.Orderer{},
 		}
 
 		bareMinimumChain := &chainImpl{
@@ -1045,8 +1095,6 @@ func TestProcessMessagesToBlocks(t *testing.T) {
 	})
 
 	t.Run("ReceiveRegularAndSendTimeToCut", func(t *testing.T) {
-		t.Skip("Skipping test as it introduces a race condition")
-
 		// NB We haven't set a handlermap for the mock broker so we need to set
 		// the ProduceResponse
 		successResponse := new(sarama.ProduceResponse)
@@ -1070,6 +1118,8 @@ func TestProcessMessagesToBlocks(t *testing.T) {
 		}
 		defer close(mockSupport.BlockCutterVal.Block)
 
+		mockClock := newMockClock()
+
 		bareMinimumChain := &chainImpl{
 			producer:        producer,
 			parentConsumer:  mockParentConsumer,
@@ -1078,6 +1128,7 @@ func TestProcessMessagesToBlocks(t *testing.T) {
 			channel:            mockChannel,
 			support:            mockSupport,
 			lastCutBlockNumber: lastCutBlockNumber,
+			clock:              mockClock,
 
 			errorChan: errorChan,
 			haltChan:  haltChan,
@@ -1097,9 +1148,9 @@ func TestProcessMessagesToBlocks(t *testing.T) {
 		mockSupport.BlockCutterVal.Block <- struct{}{} // Let the `mockblockcutter.Ordered` call return
 		logger.Debugf("Mock blockcutter's Ordered call has returned")
 
-		// Sleep so that the timer branch is activated before the exitChan one.
-		// TODO This is a race condition, will fix in follow-up changeset
-		time.Sleep(hitBranch)
+		// Fire the batch timer, so that the timer branch is activated before
+		// the exitChan one
+		mockClock.fire()
 
 		logger.Debug("Closing haltChan to exit the infinite for-loop")
 		close(haltChan) // Identical to chain.Halt()
@@ -1113,14 +1164,139 @@ func TestProcessMessagesToBlocks(t *testing.T) {
 		assert.Equal(t, lastCutBlockNumber, bareMinimumChain.lastCutBlockNumber, "Expected lastCutBlockNumber to stay the same")
 	})
 
+	t.Run("SendHeartbeat", func(t *testing.T) {
+		successResponse := new(sarama.ProduceResponse)
+		successResponse.AddTopicPartition(mockChannel.topic(), mockChannel.partition(), sarama.ErrNoError)
+		mockBroker.Returns(successResponse)
+
+		errorChan := make(chan struct{})
+		close(errorChan)
+		haltChan := make(chan struct{})
+
+		lastCutBlockNumber := uint64(3)
+
+		mockSupport := &mockmultichain.ConsenterSupport{
+			Blocks:         make(chan *cb.Block), // WriteBlock will post here
+			BlockCutterVal: mockblockcutter.NewReceiver(),
+			ChainIDVal:     mockChannel.topic(),
+			HeightVal:      lastCutBlockNumber, // Incremented during the WriteBlock call
+			SharedConfigVal: &mockconfig.Orderer{
+				BlockScheduleVal: config.BlockSchedule{MaxInterval: time.Minute},
+				CapabilitiesVal:  []string{config.BlockScheduleCapability},
+			},
+		}
+		defer close(mockSupport.BlockCutterVal.Block)
+
+		mockClock := newMockClock()
+
+		bareMinimumChain := &chainImpl{
+			producer:        producer,
+			parentConsumer:  mockParentConsumer,
+			channelConsumer: mockChannelConsumer,
+
+			channel:            mockChannel,
+			support:            mockSupport,
+			lastCutBlockNumber: lastCutBlockNumber,
+			clock:              mockClock,
+
+			errorChan: errorChan,
+			haltChan:  haltChan,
+		}
+
+		var counts []uint64
+		done := make(chan struct{})
+
+		go func() {
+			counts, err = bareMinimumChain.processMessagesToBlocks()
+			done <- struct{}{}
+		}()
+
+		// Fire the heartbeat timer started along with the loop
+		mockClock.fire()
+
+		logger.Debug("Closing haltChan to exit the infinite for-loop")
+		close(haltChan) // Identical to chain.Halt()
+		logger.Debug("haltChan closed")
+		<-done
+
+		assert.NoError(t, err, "Expected the processMessagesToBlocks call to return without errors")
+		assert.Equal(t, uint64(1), counts[indexSendTimeToCutPass], "Expected 1 heartbeat TIMER event processed")
+		assert.Equal(t, lastCutBlockNumber, bareMinimumChain.lastCutBlockNumber, "Expected lastCutBlockNumber to stay the same")
+	})
+
+	t.Run("SendHeartbeatError", func(t *testing.T) {
+		failureResponse := new(sarama.ProduceResponse)
+		failureResponse.AddTopicPartition(mockChannel.topic(), mockChannel.partition(), sarama.ErrNotEnoughReplicas)
+		mockBroker.Returns(failureResponse)
+		mockBroker.Returns(failureResponse)
+
+		errorChan := make(chan struct{})
+		close(errorChan)
+		haltChan := make(chan struct{})
+
+		lastCutBlockNumber := uint64(3)
+
+		mockSupport := &mockmultichain.ConsenterSupport{
+			Blocks:         make(chan *cb.Block), // WriteBlock will post here
+			BlockCutterVal: mockblockcutter.NewReceiver(),
+			ChainIDVal:     mockChannel.topic(),
+			HeightVal:      lastCutBlockNumber, // Incremented during the WriteBlock call
+			SharedConfigVal: &mockconfig.Orderer{
+				BlockScheduleVal: config.BlockSchedule{MaxInterval: time.Minute},
+				CapabilitiesVal:  []string{config.BlockScheduleCapability},
+			},
+		}
+		defer close(mockSupport.BlockCutterVal.Block)
+
+		mockClock := newMockClock()
+
+		bareMinimumChain := &chainImpl{
+			consenter:       &consenterImpl{}, // For the retry interval
+			producer:        producer,
+			parentConsumer:  mockParentConsumer,
+			channelConsumer: mockChannelConsumer,
+
+			channel:            mockChannel,
+			support:            mockSupport,
+			lastCutBlockNumber: lastCutBlockNumber,
+			clock:              mockClock,
+
+			errorChan: errorChan,
+			haltChan:  haltChan,
+		}
+
+		var counts []uint64
+		done := make(chan struct{})
+
+		go func() {
+			counts, err = bareMinimumChain.processMessagesToBlocks()
+			done <- struct{}{}
+		}()
+
+		// Fire the heartbeat timer started along with the loop, whose message
+		// cannot be posted
+		mockClock.fire()
+
+		// Fire the heartbeat timer restarted after the failure, which would
+		// block if it had not been restarted
+		mockClock.fire()
+
+		logger.Debug("Closing haltChan to exit the infinite for-loop")
+		close(haltChan) // Identical to chain.Halt()
+		logger.Debug("haltChan closed")
+		<-done
+
+		assert.NoError(t, err, "Expected the processMessagesToBlocks call to return without errors")
+		assert.Equal(t, uint64(2), counts[indexSendTimeToCutError], "Expected 2 faulty heartbeat TIMER events processed")
+		assert.Equal(t, lastCutBlockNumber, bareMinimumChain.lastCutBlockNumber, "Expected lastCutBlockNumber to stay the same")
+	})
+
 	t.Run("ReceiveRegularAndSendTimeToCutError", func(t *testing.T) {
 		// Note that this test is affected by the following parameters:
 		// - Net.ReadTimeout
 		// - Consumer.Retry.Backoff
 		// - Metadata.Retry.Max
 
-		t.Skip("Skipping test as it introduces a race condition")
-
 		// Exact same test as ReceiveRegularAndSendTimeToCut.
 		// Only difference is that the producer's attempt to send a TTC will
 		// fail with an ErrNotEnoughReplicas error.
@@ -1145,6 +1321,8 @@ func TestProcessMessagesToBlocks(t *testing.T) {
 		}
 		defer close(mockSupport.BlockCutterVal.Block)
 
+		mockClock := newMockClock()
+
 		bareMinimumChain := &chainImpl{
 			producer:        producer,
 			parentConsumer:  mockParentConsumer,
@@ -1153,6 +1331,7 @@ func TestProcessMessagesToBlocks(t *testing.T) {
 			channel:            mockChannel,
 			support:            mockSupport,
 			lastCutBlockNumber: lastCutBlockNumber,
+			clock:              mockClock,
 
 			errorChan: errorChan,
 			haltChan:  haltChan,
@@ -1172,9 +1351,9 @@ func TestProcessMessagesToBlocks(t *testing.T) {
 		mockSupport.BlockCutterVal.Block <- struct{}{} // Let the `mockblockcutter.Ordered` call return
 		logger.Debugf("Mock blockcutter's Ordered call has returned")
 
-		// Sleep so that the timer branch is activated before the exitChan one.
-		// TODO This is a race condition, will fix in follow-up changeset
-		time.Sleep(hitBranch)
+		// Fire the batch timer, so that the timer branch is activated before
+		// the exitChan one
+		mockClock.fire()
 
 		logger.Debug("Closing haltChan to exit the infinite for-loop")
 		close(haltChan) // Identical to chain.Halt()
@@ -1196,10 +1375,11 @@ func TestProcessMessagesToBlocks(t *testing.T) {
 		lastCutBlockNumber := uint64(3)
 
 		mockSupport := &mockmultichain.ConsenterSupport{
-			Blocks:         make(chan *cb.Block), // WriteBlock will post here
-			BlockCutterVal: mockblockcutter.NewReceiver(),
-			ChainIDVal:     mockChannel.topic(),
-			HeightVal:      lastCutBlockNumber, // Incremented during the WriteBlock call
+			Blocks:          make(chan *cb.Block), // WriteBlock will post here
+			BlockCutterVal:  mockblockcutter.NewReceiver(),
+			ChainIDVal:      mockChannel.topic(),
+			HeightVal:       lastCutBlockNumber, // Incremented during the WriteBlock call
+			SharedConfigVal: &mockconfig.Orderer{},
 		}
 		defer close(mockSupport.BlockCutterVal.Block)
 
@@ -1255,10 +1435,11 @@ func TestProcessMessagesToBlocks(t *testing.T) {
 		lastCutBlockNumber := uint64(3)
 
 		mockSupport := &mockmultichain.ConsenterSupport{
-			Blocks:         make(chan *cb.Block), // WriteBlock will post here
-			BlockCutterVal: mockblockcutter.NewReceiver(),
-			ChainIDVal:     mockChannel.topic(),
-			HeightVal:      lastCutBlockNumber, // Incremented during the WriteBlock call
+			Blocks:          make(chan *cb.Block), // WriteBlock will post here
+			BlockCutterVal:  mockblockcutter.NewReceiver(),
+			ChainIDVal:      mockChannel.topic(),
+			HeightVal:       lastCutBlockNumber, // Incremented during the WriteBlock call
+			SharedConfigVal: &mockconfig.Orderer{},
 		}
 		defer close(mockSupport.BlockCutterVal.Block)
 
@@ -1296,6 +1477,110 @@ func TestProcessMessagesToBlocks(t *testing.T) {
 		assert.Equal(t, lastCutBlockNumber, bareMinimumChain.lastCutBlockNumber, "Expected lastCutBlockNumber to stay the same")
 	})
 
+	t.Run("ReceiveHeartbeatZeroBatch", func(t *testing.T) {
+		errorChan := make(chan struct{})
+		close(errorChan)
+		haltChan := make(chan struct{})
+
+		lastCutBlockNumber := uint64(3)
+
+		mockSupport := &mockmultichain.ConsenterSupport{
+			Blocks:          make(chan *cb.Block), // WriteBlock will post here
+			BlockCutterVal:  mockblockcutter.NewReceiver(),
+			ChainIDVal:      mockChannel.topic(),
+			HeightVal:       lastCutBlockNumber, // Incremented during the WriteBlock call
+			SharedConfigVal: &mockconfig.Orderer{CapabilitiesVal: []string{config.BlockScheduleCapability}},
+		}
+		defer close(mockSupport.BlockCutterVal.Block)
+
+		bareMinimumChain := &chainImpl{
+			parentConsumer:  mockParentConsumer,
+			channelConsumer: mockChannelConsumer,
+
+			channel:            mockChannel,
+			support:            mockSupport,
+			lastCutBlockNumber: lastCutBlockNumber,
+
+			errorChan: errorChan,
+			haltChan:  haltChan,
+		}
+
+		var counts []uint64
+		done := make(chan struct{})
+
+		go func() {
+			counts, err = bareMinimumChain.processMessagesToBlocks()
+			done <- struct{}{}
+		}()
+
+		// This is the wrappedMessage that the for-loop will process
+		mpc.YieldMessage(newMockConsumerMessage(newHeartbeatMessage(lastCutBlockNumber + 1)))
+
+		block := <-mockSupport.Blocks // Let the `mockmultichain.WriteBlock` go through
+		assert.Empty(t, block.Data.Data, "Expected an empty heartbeat block")
+
+		logger.Debug("Closing haltChan to exit the infinite for-loop")
+		close(haltChan) // Identical to chain.Halt()
+		logger.Debug("haltChan closed")
+		<-done
+
+		assert.NoError(t, err, "Expected the processMessagesToBlocks call to return without errors")
+		assert.Equal(t, uint64(1), counts[indexRecvPass], "Expected 1 message received and unmarshaled")
+		assert.Equal(t, uint64(1), counts[indexProcessTimeToCutPass], "Expected 1 TIMETOCUT message processed")
+		assert.Equal(t, lastCutBlockNumber+1, bareMinimumChain.lastCutBlockNumber, "Expected lastCutBlockNumber to be bumped up by one")
+	})
+
+	t.Run("ReceiveHeartbeatZeroBatchWithoutCapability", func(t *testing.T) {
+		errorChan := make(chan struct{})
+		close(errorChan)
+		haltChan := make(chan struct{})
+
+		lastCutBlockNumber := uint64(3)
+
+		mockSupport := &mockmultichain.ConsenterSupport{
+			Blocks:          make(chan *cb.Block), // WriteBlock will post here
+			BlockCutterVal:  mockblockcutter.NewReceiver(),
+			ChainIDVal:      mockChannel.topic(),
+			HeightVal:       lastCutBlockNumber, // Incremented during the WriteBlock call
+			SharedConfigVal: &mockconfig.Orderer{},
+		}
+		defer close(mockSupport.BlockCutterVal.Block)
+
+		bareMinimumChain := &chainImpl{
+			parentConsumer:  mockParentConsumer,
+			channelConsumer: mockChannelConsumer,
+
+			channel:            mockChannel,
+			support:            mockSupport,
+			lastCutBlockNumber: lastCutBlockNumber,
+
+			errorChan: errorChan,
+			haltChan:  haltChan,
+		}
+
+		var counts []uint64
+		done := make(chan struct{})
+
+		go func() {
+			counts, err = bareMinimumChain.processMessagesToBlocks()
+			done <- struct{}{}
+		}()
+
+		// This is the wrappedMessage that the for-loop will process
+		mpc.YieldMessage(newMockConsumerMessage(newHeartbeatMessage(lastCutBlockNumber + 1)))
+
+		logger.Debug("Closing haltChan to exit the infinite for-loop")
+		close(haltChan) // Identical to chain.Halt()
+		logger.Debug("haltChan closed")
+		<-done
+
+		assert.NoError(t, err, "Expected the processMessagesToBlocks call to return without errors")
+		assert.Equal(t, uint64(1), counts[indexRecvPass], "Expected 1 message received and unmarshaled")
+		assert.Equal(t, uint64(1), counts[indexProcessTimeToCutPass], "Expected the heartbeat to be ignored without the BlockSchedule capability")
+		assert.Empty(t, mockSupport.Blocks, "Expected no empty block without the BlockSchedule capability")
+		assert.Equal(t, lastCutBlockNumber, bareMinimumChain.lastCutBlockNumber, "Expected lastCutBlockNumber to stay the same")
+	})
+
 	t.Run("ReceiveTimeToCutLargerThanExpected", func(t *testing.T) {
 		errorChan := make(chan struct{})
 		close(errorChan)
@@ -1304,10 +1589,11 @@ func TestProcessMessagesToBlocks(t *testing.T) {
 		lastCutBlockNumber := uint64(3)
 
 		mockSupport := &mockmultichain.ConsenterSupport{
-			Blocks:         make(chan *cb.Block), // WriteBlock will post here
-			BlockCutterVal: mockblockcutter.NewReceiver(),
-			ChainIDVal:     mockChannel.topic(),
-			HeightVal:      lastCutBlockNumber, // Incremented during the WriteBlock call
+			Blocks:          make(chan *cb.Block), // WriteBlock will post here
+			BlockCutterVal:  mockblockcutter.NewReceiver(),
+			ChainIDVal:      mockChannel.topic(),
+			HeightVal:       lastCutBlockNumber, // Incremented during the WriteBlock call
+			SharedConfigVal: &mockconfig.Orderer{},
 		}
 		defer close(mockSupport.BlockCutterVal.Block)
 
@@ -1353,10 +1639,11 @@ func TestProcessMessagesToBlocks(t *testing.T) {
 		lastCutBlockNumber := uint64(3)
 
 		mockSupport := &mockmultichain.ConsenterSupport{
-			Blocks:         make(chan *cb.Block), // WriteBlock will post here
-			BlockCutterVal: mockblockcutter.NewReceiver(),
-			ChainIDVal:     mockChannel.topic(),
-			HeightVal:      lastCutBlockNumber, // Incremented during the WriteBlock call
+			Blocks:          make(chan *cb.Block), // WriteBlock will post here
+			BlockCutterVal:  mockblockcutter.NewReceiver(),
+			ChainIDVal:      mockChannel.topic(),
+			HeightVal:       lastCutBlockNumber, // Incremented during the WriteBlock call
+			SharedConfigVal: &mockconfig.Orderer{},
 		}
 		defer close(mockSupport.BlockCutterVal.Block)
 
@@ -1415,7 +1702,8 @@ func TestProcessMessagesToBlocks(t *testing.T) {
 		haltChan := make(chan struct{})
 
 		mockSupport := &mockmultichain.ConsenterSupport{
-			ChainIDVal: mockChannel.topic(),
+			ChainIDVal:      mockChannel.topic(),
+			SharedConfigVal: &mockconfig.Orderer{},
 		}
 
 		bareMinimumChain := &chainImpl{
@@ -1482,7 +1770,8 @@ func TestProcessMessagesToBlocks(t *testing.T) {
 		haltChan := make(chan struct{})
 
 		mockSupport := &mockmultichain.ConsenterSupport{
-			ChainIDVal: mockChannel.topic(),
+			ChainIDVal:      mockChannel.topic(),
+			SharedConfigVal: &mockconfig.Orderer{},
 		}
 
 		bareMinimumChain := &chainImpl{
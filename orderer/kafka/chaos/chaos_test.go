@@ -0,0 +1,144 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaos
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/Shopify/sarama/mocks"
+	"github.com/hyperledger/fabric/orderer/kafka"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const topic = "chaos"
+
+var _ kafka.ClientWrapper = &Wrapper{}
+
+func newMessage() *sarama.ProducerMessage {
+	return &sarama.ProducerMessage{Topic: topic, Value: sarama.StringEncoder("foo")}
+}
+
+func TestProducerLeaderFailover(t *testing.T) {
+	mockProducer := mocks.NewSyncProducer(t, nil)
+	defer mockProducer.Close()
+	producer := NewWrapper(Faults{LeaderFailover: 1}).WrapProducer(topic, mockProducer)
+
+	_, _, err := producer.SendMessage(newMessage())
+	assert.Equal(t, sarama.ErrNotLeaderForPartition, err)
+	err = producer.SendMessages([]*sarama.ProducerMessage{newMessage(), newMessage()})
+	assert.Len(t, err, 2)
+}
+
+func TestProducerDuplicate(t *testing.T) {
+	mockProducer := mocks.NewSyncProducer(t, nil)
+	defer mockProducer.Close()
+	mockProducer.ExpectSendMessageAndSucceed()
+	mockProducer.ExpectSendMessageAndSucceed()
+	producer := NewWrapper(Faults{Duplicate: 1}).WrapProducer(topic, mockProducer)
+
+	_, offset, err := producer.SendMessage(newMessage())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), offset, "Expected the offset of the first post")
+}
+
+func TestProducerSlowBroker(t *testing.T) {
+	mockProducer := mocks.NewSyncProducer(t, nil)
+	defer mockProducer.Close()
+	mockProducer.ExpectSendMessageAndSucceed()
+	producer := NewWrapper(Faults{SlowBroker: 50 * time.Millisecond}).WrapProducer(topic, mockProducer)
+
+	start := time.Now()
+	_, _, err := producer.SendMessage(newMessage())
+	assert.NoError(t, err)
+	assert.True(t, time.Since(start) >= 50*time.Millisecond)
+}
+
+// consume starts consuming the partition with the given failures, after
+// yielding n messages at offsets 1 to n
+func consume(t *testing.T, faults Faults, n int) (sarama.PartitionConsumer, func()) {
+	mockConsumer := mocks.NewConsumer(t, nil)
+	mockPartitionConsumer := mockConsumer.ExpectConsumePartition(topic, 0, sarama.OffsetOldest)
+	for i := 0; i < n; i++ {
+		mockPartitionConsumer.YieldMessage(&sarama.ConsumerMessage{Topic: topic})
+	}
+
+	consumer := NewWrapper(faults).WrapConsumer(topic, mockConsumer)
+	partitionConsumer, err := consumer.ConsumePartition(topic, 0, sarama.OffsetOldest)
+	require.NoError(t, err)
+	return partitionConsumer, func() {
+		partitionConsumer.Close()
+		consumer.Close()
+	}
+}
+
+func receive(t *testing.T, pc sarama.PartitionConsumer, n int) []int64 {
+	var offsets []int64
+	for len(offsets) < n {
+		select {
+		case msg := <-pc.Messages():
+			offsets = append(offsets, msg.Offset)
+		case <-time.After(time.Second):
+			t.Fatalf("Expected %d messages, got %v", n, offsets)
+		}
+	}
+	return offsets
+}
+
+func TestConsumerNoFaults(t *testing.T) {
+	pc, stop := consume(t, Faults{}, 3)
+	defer stop()
+	assert.Equal(t, []int64{1, 2, 3}, receive(t, pc, 3))
+}
+
+func TestConsumerDuplicate(t *testing.T) {
+	pc, stop := consume(t, Faults{Duplicate: 1}, 2)
+	defer stop()
+	assert.Equal(t, []int64{1, 1, 2, 2}, receive(t, pc, 4))
+}
+
+func TestConsumerOffsetReset(t *testing.T) {
+	pc, stop := consume(t, Faults{OffsetReset: 1}, 2)
+	defer stop()
+	assert.Equal(t, []int64{1, 1, 2}, receive(t, pc, 3))
+}
+
+func TestConsumerLeaderFailover(t *testing.T) {
+	mockConsumer := mocks.NewConsumer(t, nil)
+	defer mockConsumer.Close()
+	consumer := NewWrapper(Faults{LeaderFailover: 1}).WrapConsumer(topic, mockConsumer)
+	_, err := consumer.ConsumePartition(topic, 0, sarama.OffsetOldest)
+	assert.Equal(t, sarama.ErrNotLeaderForPartition, err)
+
+	// Failovers of running partition consumers are reported as errors
+	mockPartitionConsumer := mockConsumer.ExpectConsumePartition(topic, 0, sarama.OffsetOldest)
+	mockPartitionConsumer.YieldMessage(&sarama.ConsumerMessage{Topic: topic})
+	inner, err := mockConsumer.ConsumePartition(topic, 0, sarama.OffsetOldest)
+	require.NoError(t, err)
+	pc := newPartitionConsumer(inner, NewWrapper(Faults{LeaderFailover: 1}), topic, 0)
+	defer pc.Close()
+
+	select {
+	case consumerErr := <-pc.Errors():
+		assert.Equal(t, sarama.ErrNotLeaderForPartition, consumerErr.Err)
+	case <-time.After(time.Second):
+		t.Fatal("Expected a consumer error")
+	}
+	assert.Equal(t, []int64{1}, receive(t, pc, 1))
+}
+
+func TestConsumerClose(t *testing.T) {
+	pc, stop := consume(t, Faults{Duplicate: 1}, 1)
+	// Nobody consumes the messages, the relays stop on close
+	stop()
+	_, ok := <-pc.Messages()
+	assert.False(t, ok)
+	_, ok = <-pc.Errors()
+	assert.False(t, ok)
+}
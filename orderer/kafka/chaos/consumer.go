@@ -0,0 +1,166 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaos
+
+import (
+	"sync"
+
+	"github.com/Shopify/sarama"
+)
+
+// historySize is the number of consumed messages a partition consumer keeps
+// to deliver them again when rewinding
+const historySize = 16
+
+// parentConsumer injects failures in the partition consumers it starts
+type parentConsumer struct {
+	sarama.Consumer
+	wrapper *Wrapper
+}
+
+// ConsumePartition starts consuming the partition from the given offset
+func (c *parentConsumer) ConsumePartition(topic string, partition int32, offset int64) (sarama.PartitionConsumer, error) {
+	c.wrapper.slowDown()
+	if c.wrapper.happens(c.wrapper.faults.LeaderFailover) {
+		return nil, sarama.ErrNotLeaderForPartition
+	}
+	pc, err := c.Consumer.ConsumePartition(topic, partition, offset)
+	if err != nil {
+		return nil, err
+	}
+	return newPartitionConsumer(pc, c.wrapper, topic, partition), nil
+}
+
+// partitionConsumer relays the messages and errors of a partition consumer,
+// injecting failures in between
+type partitionConsumer struct {
+	sarama.PartitionConsumer
+	wrapper   *Wrapper
+	topic     string
+	partition int32
+
+	messages chan *sarama.ConsumerMessage
+	errors   chan *sarama.ConsumerError
+	history  []*sarama.ConsumerMessage
+
+	done      chan struct{}
+	closeOnce sync.Once
+	relaying  sync.WaitGroup
+}
+
+func newPartitionConsumer(pc sarama.PartitionConsumer, wrapper *Wrapper, topic string, partition int32) *partitionConsumer {
+	c := &partitionConsumer{
+		PartitionConsumer: pc,
+		wrapper:           wrapper,
+		topic:             topic,
+		partition:         partition,
+		messages:          make(chan *sarama.ConsumerMessage),
+		errors:            make(chan *sarama.ConsumerError),
+		done:              make(chan struct{}),
+	}
+	c.relaying.Add(2)
+	go c.relayMessages()
+	go c.relayErrors()
+	go func() {
+		// Both relays may send errors
+		c.relaying.Wait()
+		close(c.messages)
+		close(c.errors)
+	}()
+	return c
+}
+
+// Messages returns the channel of the consumed messages
+func (c *partitionConsumer) Messages() <-chan *sarama.ConsumerMessage {
+	return c.messages
+}
+
+// Errors returns the channel of the consumption errors
+func (c *partitionConsumer) Errors() <-chan *sarama.ConsumerError {
+	return c.errors
+}
+
+// AsyncClose stops the relays and closes the wrapped partition consumer
+func (c *partitionConsumer) AsyncClose() {
+	c.closeOnce.Do(func() { close(c.done) })
+	c.PartitionConsumer.AsyncClose()
+}
+
+// Close stops the relays and closes the wrapped partition consumer, waiting
+// for its shutdown
+func (c *partitionConsumer) Close() error {
+	c.closeOnce.Do(func() { close(c.done) })
+	err := c.PartitionConsumer.Close()
+	c.relaying.Wait()
+	return err
+}
+
+func (c *partitionConsumer) relayMessages() {
+	defer c.relaying.Done()
+
+	for msg := range c.PartitionConsumer.Messages() {
+		c.wrapper.slowDown()
+		if c.wrapper.happens(c.wrapper.faults.LeaderFailover) {
+			logger.Debugf("Injecting a leader failover before offset %d of topic %s", msg.Offset, msg.Topic)
+			if !c.sendError(&sarama.ConsumerError{Topic: c.topic, Partition: c.partition, Err: sarama.ErrNotLeaderForPartition}) {
+				return
+			}
+		}
+		if len(c.history) > 0 && c.wrapper.happens(c.wrapper.faults.OffsetReset) {
+			rewind := c.history[len(c.history)-1-c.wrapper.intn(len(c.history)):]
+			logger.Debugf("Rewinding topic %s to offset %d", msg.Topic, rewind[0].Offset)
+			for _, old := range rewind {
+				if !c.sendMessage(old) {
+					return
+				}
+			}
+		}
+		if !c.sendMessage(msg) {
+			return
+		}
+		if c.wrapper.happens(c.wrapper.faults.Duplicate) {
+			logger.Debugf("Delivering offset %d of topic %s twice", msg.Offset, msg.Topic)
+			if !c.sendMessage(msg) {
+				return
+			}
+		}
+		c.history = append(c.history, msg)
+		if len(c.history) > historySize {
+			c.history = c.history[1:]
+		}
+	}
+}
+
+func (c *partitionConsumer) relayErrors() {
+	defer c.relaying.Done()
+
+	for err := range c.PartitionConsumer.Errors() {
+		if !c.sendError(err) {
+			return
+		}
+	}
+}
+
+// sendMessage relays a message, unless the consumer is closed first
+func (c *partitionConsumer) sendMessage(msg *sarama.ConsumerMessage) bool {
+	select {
+	case c.messages <- msg:
+		return true
+	case <-c.done:
+		return false
+	}
+}
+
+// sendError relays an error, unless the consumer is closed first
+func (c *partitionConsumer) sendError(err *sarama.ConsumerError) bool {
+	select {
+	case c.errors <- err:
+		return true
+	case <-c.done:
+		return false
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package chaos injects failures in the Kafka clients of the Kafka-based
+// consenter, and checks that the chains it orders keep their invariants.
+package chaos
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/hyperledger/fabric/common/flogging"
+)
+
+var logger = flogging.MustGetLogger("orderer/kafka/chaos")
+
+// Faults configures the failures injected in the Kafka clients.
+// Probabilities are in [0, 1], and zero values disable the failures
+type Faults struct {
+	// LeaderFailover is the probability that a request fails as if the leader
+	// of the partition was being re-elected
+	LeaderFailover float64
+	// Duplicate is the probability that a message is delivered twice
+	Duplicate float64
+	// OffsetReset is the probability that the consumer rewinds to an earlier
+	// offset and delivers the messages from it again
+	OffsetReset float64
+	// SlowBroker delays every request to the brokers
+	SlowBroker time.Duration
+	// Seed seeds the pseudo-random injection of failures, to replay a run
+	Seed int64
+}
+
+// Wrapper injects the configured failures in the Kafka clients it wraps.
+// It satisfies the kafka.ClientWrapper interface
+type Wrapper struct {
+	faults Faults
+
+	lock sync.Mutex
+	rand *rand.Rand
+}
+
+// NewWrapper returns a Wrapper injecting the given failures
+func NewWrapper(faults Faults) *Wrapper {
+	return &Wrapper{
+		faults: faults,
+		rand:   rand.New(rand.NewSource(faults.Seed)),
+	}
+}
+
+// WrapProducer returns a producer injecting failures in the given one
+func (w *Wrapper) WrapProducer(chainID string, producer sarama.SyncProducer) sarama.SyncProducer {
+	logger.Warningf("[channel: %s] Injecting failures in the Kafka producer: %+v", chainID, w.faults)
+	return &syncProducer{SyncProducer: producer, wrapper: w}
+}
+
+// WrapConsumer returns a consumer injecting failures in the partition
+// consumers of the given one
+func (w *Wrapper) WrapConsumer(chainID string, consumer sarama.Consumer) sarama.Consumer {
+	logger.Warningf("[channel: %s] Injecting failures in the Kafka consumer: %+v", chainID, w.faults)
+	return &parentConsumer{Consumer: consumer, wrapper: w}
+}
+
+// happens draws whether a failure of the given probability happens
+func (w *Wrapper) happens(probability float64) bool {
+	if probability <= 0 {
+		return false
+	}
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.rand.Float64() < probability
+}
+
+// intn draws a number in [0, n)
+func (w *Wrapper) intn(n int) int {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.rand.Intn(n)
+}
+
+func (w *Wrapper) slowDown() {
+	if w.faults.SlowBroker > 0 {
+		time.Sleep(w.faults.SlowBroker)
+	}
+}
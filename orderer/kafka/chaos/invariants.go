@@ -0,0 +1,109 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaos
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/orderer/ledger"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+)
+
+// Checker checks the invariants of the blocks cut by a Kafka-based chain, in
+// their order: the block numbers follow each other without gaps, each block
+// links to the hash of the previous one, and the Kafka offsets persisted in
+// the orderer metadata of the blocks strictly increase
+type Checker struct {
+	previous   *cb.Block
+	lastOffset int64
+	hasOffset  bool
+}
+
+// NewChecker returns a Checker expecting the genesis block first
+func NewChecker() *Checker {
+	return &Checker{}
+}
+
+// Check checks the next block of the chain
+func (c *Checker) Check(block *cb.Block) error {
+	if block == nil || block.Header == nil {
+		return errors.New("block without header")
+	}
+	number := block.Header.Number
+
+	if c.previous == nil {
+		if number != 0 {
+			return fmt.Errorf("expected the genesis block, got block %d", number)
+		}
+	} else {
+		if expected := c.previous.Header.Number + 1; number != expected {
+			return fmt.Errorf("gap in the chain: expected block %d, got block %d", expected, number)
+		}
+		if !bytes.Equal(block.Header.PreviousHash, c.previous.Header.Hash()) {
+			return fmt.Errorf("block %d does not link to the hash of block %d", number, number-1)
+		}
+	}
+
+	offset, ok, err := persistedOffset(block)
+	if err != nil {
+		return fmt.Errorf("block %d: %s", number, err)
+	}
+	switch {
+	case !ok && number > 0:
+		return fmt.Errorf("block %d has no Kafka offset in its metadata", number)
+	case ok && c.hasOffset && offset <= c.lastOffset:
+		return fmt.Errorf("Kafka offset of block %d is %d, not beyond the offset %d of the previous block", number, offset, c.lastOffset)
+	}
+	if ok {
+		c.lastOffset, c.hasOffset = offset, true
+	}
+
+	c.previous = block
+	return nil
+}
+
+// CheckLedger checks the invariants of all the blocks of a chain
+func CheckLedger(reader ledger.Reader) error {
+	height := reader.Height()
+	iterator, _ := reader.Iterator(&ab.SeekPosition{Type: &ab.SeekPosition_Oldest{Oldest: &ab.SeekOldest{}}})
+	checker := NewChecker()
+	for i := uint64(0); i < height; i++ {
+		block, status := iterator.Next()
+		if status != cb.Status_SUCCESS {
+			return fmt.Errorf("failed reading block %d: %s", i, status)
+		}
+		if err := checker.Check(block); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// persistedOffset returns the last Kafka offset persisted in the orderer
+// metadata of a block, if any
+func persistedOffset(block *cb.Block) (int64, bool, error) {
+	if block.Metadata == nil || len(block.Metadata.Metadata) <= int(cb.BlockMetadataIndex_ORDERER) {
+		return 0, false, nil
+	}
+	metadata, err := utils.GetMetadataFromBlock(block, cb.BlockMetadataIndex_ORDERER)
+	if err != nil {
+		return 0, false, err
+	}
+	if len(metadata.Value) == 0 {
+		return 0, false, nil
+	}
+	kafkaMetadata := &ab.KafkaMetadata{}
+	if err := proto.Unmarshal(metadata.Value, kafkaMetadata); err != nil {
+		return 0, false, fmt.Errorf("cannot unmarshal Kafka metadata: %s", err)
+	}
+	return kafkaMetadata.LastOffsetPersisted, true, nil
+}
@@ -0,0 +1,73 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaos
+
+import (
+	"testing"
+
+	ramledger "github.com/hyperledger/fabric/orderer/ledger/ram"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newBlock returns the block following the previous one, with the given
+// offset in its orderer metadata
+func newBlock(previous *cb.Block, offset int64) *cb.Block {
+	block := cb.NewBlock(previous.Header.Number+1, previous.Header.Hash())
+	block.Metadata.Metadata[cb.BlockMetadataIndex_ORDERER] = utils.MarshalOrPanic(&cb.Metadata{
+		Value: utils.MarshalOrPanic(&ab.KafkaMetadata{LastOffsetPersisted: offset}),
+	})
+	return block
+}
+
+func TestChecker(t *testing.T) {
+	genesis := cb.NewBlock(0, nil)
+	block1 := newBlock(genesis, 3)
+	block2 := newBlock(block1, 5)
+
+	checker := NewChecker()
+	for _, block := range []*cb.Block{genesis, block1, block2} {
+		assert.NoError(t, checker.Check(block))
+	}
+
+	checker = NewChecker()
+	assert.Error(t, checker.Check(block1), "The chain starts with the genesis block")
+	assert.Error(t, checker.Check(nil))
+
+	checker = NewChecker()
+	require.NoError(t, checker.Check(genesis))
+	assert.Error(t, checker.Check(block2), "Block 1 is missing")
+
+	checker = NewChecker()
+	require.NoError(t, checker.Check(genesis))
+	forked := newBlock(cb.NewBlock(0, []byte("another chain")), 3)
+	assert.Error(t, checker.Check(forked), "Block 1 does not follow the genesis block")
+
+	checker = NewChecker()
+	require.NoError(t, checker.Check(genesis))
+	require.NoError(t, checker.Check(block1))
+	assert.Error(t, checker.Check(newBlock(block1, 3)), "The offset does not increase")
+	assert.Error(t, checker.Check(newBlock(block1, 2)), "The offset goes back")
+	assert.Error(t, checker.Check(cb.NewBlock(2, block1.Header.Hash())), "The offset is missing")
+}
+
+func TestCheckLedger(t *testing.T) {
+	genesis := cb.NewBlock(0, nil)
+	block1 := newBlock(genesis, 3)
+
+	rl, err := ramledger.New(10).GetOrCreate("foo")
+	require.NoError(t, err)
+	require.NoError(t, rl.Append(genesis))
+	require.NoError(t, rl.Append(block1))
+	assert.NoError(t, CheckLedger(rl))
+
+	require.NoError(t, rl.Append(newBlock(block1, 1)))
+	assert.Error(t, CheckLedger(rl))
+}
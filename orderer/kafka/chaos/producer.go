@@ -0,0 +1,51 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaos
+
+import (
+	"github.com/Shopify/sarama"
+)
+
+// syncProducer injects failures in the messages posted by a producer
+type syncProducer struct {
+	sarama.SyncProducer
+	wrapper *Wrapper
+}
+
+// SendMessage fails as during a leader election, or posts the message once,
+// or twice as when the acknowledgement of the broker is lost and the message
+// is retried
+func (p *syncProducer) SendMessage(msg *sarama.ProducerMessage) (int32, int64, error) {
+	p.wrapper.slowDown()
+	if p.wrapper.happens(p.wrapper.faults.LeaderFailover) {
+		logger.Debugf("Failing the post of a message to topic %s", msg.Topic)
+		return -1, -1, sarama.ErrNotLeaderForPartition
+	}
+	partition, offset, err := p.SyncProducer.SendMessage(msg)
+	if err == nil && p.wrapper.happens(p.wrapper.faults.Duplicate) {
+		logger.Debugf("Duplicating the message posted at offset %d of topic %s", offset, msg.Topic)
+		dup := *msg
+		if _, _, err := p.SyncProducer.SendMessage(&dup); err != nil {
+			logger.Debugf("Failed posting the duplicate message: %s", err)
+		}
+	}
+	return partition, offset, err
+}
+
+// SendMessages posts the messages one by one, injecting failures in each
+func (p *syncProducer) SendMessages(msgs []*sarama.ProducerMessage) error {
+	var errs sarama.ProducerErrors
+	for _, msg := range msgs {
+		if _, _, err := p.SendMessage(msg); err != nil {
+			errs = append(errs, &sarama.ProducerError{Msg: msg, Err: err})
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
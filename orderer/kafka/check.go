@@ -0,0 +1,45 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kafka
+
+import (
+	"fmt"
+
+	"github.com/Shopify/sarama"
+	localconfig "github.com/hyperledger/fabric/orderer/localconfig"
+)
+
+// CheckBrokers connects to each of the brokers as the chains would, with the
+// TLS and SASL settings of conf, which must be valid, and returns the errors of
+// the brokers which cannot be reached, by address.
+func CheckBrokers(conf localconfig.Kafka, brokers []string) map[string]error {
+	brokerConfig := newBrokerConfig(conf.TLS, conf.SASL, conf.Retry, conf.Version, defaultPartition)
+	return checkBrokers(brokerConfig, brokers)
+}
+
+// checkBrokers connects to each of the brokers with the given config, and
+// returns the errors of the brokers which cannot be reached, by address.
+func checkBrokers(brokerConfig *sarama.Config, brokers []string) map[string]error {
+	errs := make(map[string]error)
+	for _, address := range brokers {
+		broker := sarama.NewBroker(address)
+		if err := broker.Open(brokerConfig); err != nil {
+			errs[address] = err
+			continue
+		}
+		// Blocks until the connection is established or has failed
+		if connected, err := broker.Connected(); !connected {
+			errs[address] = err
+			continue
+		}
+		if _, err := broker.GetMetadata(&sarama.MetadataRequest{}); err != nil {
+			errs[address] = fmt.Errorf("connected, but the metadata request failed: %s", err)
+		}
+		broker.Close()
+	}
+	return errs
+}
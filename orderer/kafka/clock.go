@@ -0,0 +1,50 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kafka
+
+import (
+	"math/rand"
+	"time"
+)
+
+// clock provides the batch timers of a chain. Injecting one lets tests fire
+// the timers deterministically, and lets the chain pause them.
+type clock interface {
+	// After waits for the duration to elapse and then sends the current time
+	// on the returned channel.
+	After(d time.Duration) <-chan time.Time
+}
+
+// wallClock is the clock of the chains outside of tests.
+type wallClock struct{}
+
+func (wallClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// jitterClock delays the timers of another clock by a random fraction of
+// their duration, lower than jitter, so that the orderers of a channel do not
+// all fire their batch timers, and post their time-to-cut messages, at once.
+type jitterClock struct {
+	clock
+	jitter float64
+	rand   *rand.Rand
+}
+
+// newJitterClock returns the clock delaying the timers of the given clock by
+// up to the jitter fraction of their duration, or the clock itself if jitter
+// is 0.
+func newJitterClock(c clock, jitter float64) clock {
+	if jitter == 0 {
+		return c
+	}
+	return &jitterClock{clock: c, jitter: jitter, rand: rand.New(rand.NewSource(time.Now().UnixNano()))}
+}
+
+func (c *jitterClock) After(d time.Duration) <-chan time.Time {
+	return c.clock.After(d + time.Duration(c.rand.Float64()*c.jitter*float64(d)))
+}
@@ -0,0 +1,41 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kafka
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingClock records the durations of the timers it is asked for.
+type recordingClock struct {
+	durations []time.Duration
+}
+
+func (c *recordingClock) After(d time.Duration) <-chan time.Time {
+	c.durations = append(c.durations, d)
+	return nil
+}
+
+func TestJitterClock(t *testing.T) {
+	inner := &recordingClock{}
+	assert.Equal(t, inner, newJitterClock(inner, 0), "Should not wrap the clock without jitter")
+
+	const timeout = time.Second
+	c := newJitterClock(inner, 0.25)
+	for i := 0; i < 100; i++ {
+		c.After(timeout)
+	}
+	distinct := make(map[time.Duration]struct{})
+	for _, d := range inner.durations {
+		assert.True(t, d >= timeout && d < timeout+timeout/4, "Expected a duration in [%s, %s), got %s", timeout, timeout+timeout/4, d)
+		distinct[d] = struct{}{}
+	}
+	assert.True(t, len(distinct) > 1, "Expected the durations to vary")
+}
@@ -11,10 +11,11 @@ import (
 	"crypto/x509"
 
 	"github.com/Shopify/sarama"
+	"github.com/hyperledger/fabric/common/crypto/fips"
 	localconfig "github.com/hyperledger/fabric/orderer/localconfig"
 )
 
-func newBrokerConfig(tlsConfig localconfig.TLS, retryOptions localconfig.Retry, kafkaVersion sarama.KafkaVersion, chosenStaticPartition int32) *sarama.Config {
+func newBrokerConfig(tlsConfig localconfig.TLS, saslConfig localconfig.SASL, retryOptions localconfig.Retry, kafkaVersion sarama.KafkaVersion, chosenStaticPartition int32) *sarama.Config {
 	// Max. size for request headers, etc. Set in bytes. Too big on purpose.
 	paddingDelta := 1 * 1024 * 1024
 
@@ -34,10 +35,15 @@ func newBrokerConfig(tlsConfig localconfig.TLS, retryOptions localconfig.Retry,
 
 	brokerConfig.Net.TLS.Enable = tlsConfig.Enabled
 	if brokerConfig.Net.TLS.Enable {
-		// create public/private key pair structure
-		keyPair, err := tls.X509KeyPair([]byte(tlsConfig.Certificate), []byte(tlsConfig.PrivateKey))
-		if err != nil {
-			logger.Panic("Unable to decode public/private key pair:", err)
+		// create public/private key pair structure, unless the orderer
+		// authenticates with SASL rather than with a client certificate
+		var certificates []tls.Certificate
+		if tlsConfig.Certificate != "" || !saslConfig.Enabled {
+			keyPair, err := tls.X509KeyPair([]byte(tlsConfig.Certificate), []byte(tlsConfig.PrivateKey))
+			if err != nil {
+				logger.Panic("Unable to decode public/private key pair:", err)
+			}
+			certificates = []tls.Certificate{keyPair}
 		}
 		// create root CA pool
 		rootCAs := x509.NewCertPool()
@@ -46,12 +52,21 @@ func newBrokerConfig(tlsConfig localconfig.TLS, retryOptions localconfig.Retry,
 				logger.Panic("Unable to parse the root certificate authority certificates (Kafka.Tls.RootCAs)")
 			}
 		}
-		brokerConfig.Net.TLS.Config = &tls.Config{
-			Certificates: []tls.Certificate{keyPair},
+		brokerConfig.Net.TLS.Config = fips.ConfigureTLS(&tls.Config{
+			Certificates: certificates,
 			RootCAs:      rootCAs,
 			MinVersion:   tls.VersionTLS12,
 			MaxVersion:   0, // Latest supported TLS version
+		})
+	}
+
+	brokerConfig.Net.SASL.Enable = saslConfig.Enabled
+	if brokerConfig.Net.SASL.Enable {
+		if !brokerConfig.Net.TLS.Enable {
+			logger.Warning("SASL/PLAIN is enabled without TLS, the password is sent to the Kafka brokers in the clear")
 		}
+		brokerConfig.Net.SASL.User = saslConfig.Username
+		brokerConfig.Net.SASL.Password = saslConfig.Password
 	}
 
 	// Set equivalent of Kafka producer config max.request.bytes to the default
@@ -40,7 +40,7 @@ func TestBrokerConfig(t *testing.T) {
 	})
 
 	t.Run("Partitioner", func(t *testing.T) {
-		mockBrokerConfig2 := newBrokerConfig(mockLocalConfig.General.TLS, mockLocalConfig.Kafka.Retry, mockLocalConfig.Kafka.Version, differentPartition)
+		mockBrokerConfig2 := newBrokerConfig(mockLocalConfig.General.TLS, mockLocalConfig.Kafka.SASL, mockLocalConfig.Kafka.Retry, mockLocalConfig.Kafka.Version, differentPartition)
 		producer, _ := sarama.NewSyncProducer([]string{mockBroker.Addr()}, mockBrokerConfig2)
 		defer func() { producer.Close() }()
 
@@ -84,7 +84,7 @@ func TestBrokerConfigTLSConfigEnabled(t *testing.T) {
 			PrivateKey:  privateKey,
 			Certificate: publicKey,
 			RootCAs:     []string{caPublicKey},
-		}, mockLocalConfig.Kafka.Retry, mockLocalConfig.Kafka.Version, defaultPartition)
+		}, localconfig.SASL{}, mockLocalConfig.Kafka.Retry, mockLocalConfig.Kafka.Version, defaultPartition)
 
 		assert.True(t, testBrokerConfig.Net.TLS.Enable)
 		assert.NotNil(t, testBrokerConfig.Net.TLS.Config)
@@ -100,13 +100,43 @@ func TestBrokerConfigTLSConfigEnabled(t *testing.T) {
 			PrivateKey:  privateKey,
 			Certificate: publicKey,
 			RootCAs:     []string{caPublicKey},
-		}, mockLocalConfig.Kafka.Retry, mockLocalConfig.Kafka.Version, defaultPartition)
+		}, localconfig.SASL{}, mockLocalConfig.Kafka.Retry, mockLocalConfig.Kafka.Version, defaultPartition)
 
 		assert.False(t, testBrokerConfig.Net.TLS.Enable)
 		assert.Zero(t, testBrokerConfig.Net.TLS.Config)
 	})
 }
 
+func TestBrokerConfigSASL(t *testing.T) {
+	caPublicKey, _, _ := util.GenerateMockPublicPrivateKeyPairPEM(true)
+	sasl := localconfig.SASL{Enabled: true, Username: "orderer", Password: "secret"}
+
+	t.Run("OverTLS", func(t *testing.T) {
+		// The orderer authenticates with its password, without a client certificate
+		testBrokerConfig := newBrokerConfig(localconfig.TLS{
+			Enabled: true,
+			RootCAs: []string{caPublicKey},
+		}, sasl, mockLocalConfig.Kafka.Retry, mockLocalConfig.Kafka.Version, defaultPartition)
+
+		assert.True(t, testBrokerConfig.Net.SASL.Enable)
+		assert.True(t, testBrokerConfig.Net.SASL.Handshake)
+		assert.Equal(t, "orderer", testBrokerConfig.Net.SASL.User)
+		assert.Equal(t, "secret", testBrokerConfig.Net.SASL.Password)
+		assert.True(t, testBrokerConfig.Net.TLS.Enable)
+		assert.Empty(t, testBrokerConfig.Net.TLS.Config.Certificates)
+		assert.NoError(t, testBrokerConfig.Validate())
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		testBrokerConfig := newBrokerConfig(mockLocalConfig.General.TLS, localconfig.SASL{Username: "orderer", Password: "secret"},
+			mockLocalConfig.Kafka.Retry, mockLocalConfig.Kafka.Version, defaultPartition)
+
+		assert.False(t, testBrokerConfig.Net.SASL.Enable)
+		assert.Empty(t, testBrokerConfig.Net.SASL.User)
+		assert.NoError(t, testBrokerConfig.Validate())
+	})
+}
+
 func TestBrokerConfigTLSConfigBadCert(t *testing.T) {
 	publicKey, privateKey, _ := util.GenerateMockPublicPrivateKeyPairPEM(false)
 	caPublicKey, _, _ := util.GenerateMockPublicPrivateKeyPairPEM(true)
@@ -118,7 +148,7 @@ func TestBrokerConfigTLSConfigBadCert(t *testing.T) {
 				PrivateKey:  privateKey,
 				Certificate: "TRASH",
 				RootCAs:     []string{caPublicKey},
-			}, mockLocalConfig.Kafka.Retry, mockLocalConfig.Kafka.Version, defaultPartition)
+			}, localconfig.SASL{}, mockLocalConfig.Kafka.Retry, mockLocalConfig.Kafka.Version, defaultPartition)
 		})
 	})
 	t.Run("BadPublicKey", func(t *testing.T) {
@@ -128,7 +158,7 @@ func TestBrokerConfigTLSConfigBadCert(t *testing.T) {
 				PrivateKey:  "TRASH",
 				Certificate: publicKey,
 				RootCAs:     []string{caPublicKey},
-			}, mockLocalConfig.Kafka.Retry, mockLocalConfig.Kafka.Version, defaultPartition)
+			}, localconfig.SASL{}, mockLocalConfig.Kafka.Retry, mockLocalConfig.Kafka.Version, defaultPartition)
 		})
 	})
 	t.Run("BadRootCAs", func(t *testing.T) {
@@ -138,7 +168,7 @@ func TestBrokerConfigTLSConfigBadCert(t *testing.T) {
 				PrivateKey:  privateKey,
 				Certificate: publicKey,
 				RootCAs:     []string{"TRASH"},
-			}, mockLocalConfig.Kafka.Retry, mockLocalConfig.Kafka.Version, defaultPartition)
+			}, localconfig.SASL{}, mockLocalConfig.Kafka.Retry, mockLocalConfig.Kafka.Version, defaultPartition)
 		})
 	})
 }
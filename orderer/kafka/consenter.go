@@ -25,22 +25,56 @@ func init() {
 
 // New creates a Kafka-based consenter. Called by orderer's main.go.
 func New(tlsConfig localconfig.TLS, retryOptions localconfig.Retry, kafkaVersion sarama.KafkaVersion) multichain.Consenter {
-	brokerConfig := newBrokerConfig(tlsConfig, retryOptions, kafkaVersion, defaultPartition)
+	return NewWithClientWrapper(tlsConfig, retryOptions, kafkaVersion, nil)
+}
+
+// ClientWrapper decorates the Kafka clients of the chains of a consenter, for
+// instance to inject failures in them.
+type ClientWrapper interface {
+	// WrapProducer returns the producer the chain will post its messages with
+	WrapProducer(chainID string, producer sarama.SyncProducer) sarama.SyncProducer
+	// WrapConsumer returns the consumer the chain will consume its partition with
+	WrapConsumer(chainID string, consumer sarama.Consumer) sarama.Consumer
+}
+
+// NewWithClientWrapper creates a Kafka-based consenter whose Kafka clients are
+// decorated by the given wrapper. A nil wrapper leaves the clients untouched.
+func NewWithClientWrapper(tlsConfig localconfig.TLS, retryOptions localconfig.Retry, kafkaVersion sarama.KafkaVersion, wrapper ClientWrapper) multichain.Consenter {
+	return NewWithBatchTimeoutJitter(tlsConfig, retryOptions, kafkaVersion, wrapper, 0)
+}
+
+// NewWithBatchTimeoutJitter creates a Kafka-based consenter as
+// NewWithClientWrapper does, whose chains delay their batch timers by a random fraction of the batch
+// timeout lower than jitter, so that the orderers of a channel do not all post
+// their time-to-cut messages at once.
+func NewWithBatchTimeoutJitter(tlsConfig localconfig.TLS, retryOptions localconfig.Retry, kafkaVersion sarama.KafkaVersion, wrapper ClientWrapper, jitter float64) multichain.Consenter {
+	return NewWithSASL(tlsConfig, localconfig.SASL{}, retryOptions, kafkaVersion, wrapper, jitter)
+}
+
+// NewWithSASL creates a Kafka-based consenter as NewWithBatchTimeoutJitter
+// does, whose Kafka clients authenticate to the brokers with SASL/PLAIN if it
+// is enabled by saslConfig.
+func NewWithSASL(tlsConfig localconfig.TLS, saslConfig localconfig.SASL, retryOptions localconfig.Retry, kafkaVersion sarama.KafkaVersion, wrapper ClientWrapper, jitter float64) multichain.Consenter {
+	brokerConfig := newBrokerConfig(tlsConfig, saslConfig, retryOptions, kafkaVersion, defaultPartition)
 	return &consenterImpl{
-		brokerConfigVal: brokerConfig,
-		tlsConfigVal:    tlsConfig,
-		retryOptionsVal: retryOptions,
-		kafkaVersionVal: kafkaVersion}
+		brokerConfigVal:       brokerConfig,
+		tlsConfigVal:          tlsConfig,
+		retryOptionsVal:       retryOptions,
+		kafkaVersionVal:       kafkaVersion,
+		clientWrapperVal:      wrapper,
+		batchTimeoutJitterVal: jitter}
 }
 
 // consenterImpl holds the implementation of type that satisfies the
 // multichain.Consenter interface --as the HandleChain contract requires-- and
 // the commonConsenter one.
 type consenterImpl struct {
-	brokerConfigVal *sarama.Config
-	tlsConfigVal    localconfig.TLS
-	retryOptionsVal localconfig.Retry
-	kafkaVersionVal sarama.KafkaVersion
+	brokerConfigVal       *sarama.Config
+	tlsConfigVal          localconfig.TLS
+	retryOptionsVal       localconfig.Retry
+	kafkaVersionVal       sarama.KafkaVersion
+	clientWrapperVal      ClientWrapper
+	batchTimeoutJitterVal float64
 }
 
 // HandleChain creates/returns a reference to a multichain.Chain object for the
@@ -49,8 +83,15 @@ type consenterImpl struct {
 // multichain.NewManagerImpl() when ranging over the ledgerFactory's
 // existingChains.
 func (consenter *consenterImpl) HandleChain(support multichain.ConsenterSupport, metadata *cb.Metadata) (multichain.Chain, error) {
+	kafkaMetadata := getKafkaMetadata(metadata.Value, support.ChainID())
 	lastOffsetPersisted := getLastOffsetPersisted(metadata.Value, support.ChainID())
-	return newChain(consenter, support, lastOffsetPersisted)
+	chain, err := newChain(consenter, support, lastOffsetPersisted)
+	if err != nil {
+		return nil, err
+	}
+	chain.batchTimeoutJitter = consenter.batchTimeoutJitter()
+	chain.verifyLastCutBlock(kafkaMetadata)
+	return chain, nil
 }
 
 // commonConsenter allows us to retrieve the configuration options set on the
@@ -60,6 +101,8 @@ func (consenter *consenterImpl) HandleChain(support multichain.ConsenterSupport,
 type commonConsenter interface {
 	brokerConfig() *sarama.Config
 	retryOptions() localconfig.Retry
+	clientWrapper() ClientWrapper
+	batchTimeoutJitter() float64
 }
 
 func (consenter *consenterImpl) brokerConfig() *sarama.Config {
@@ -70,6 +113,14 @@ func (consenter *consenterImpl) retryOptions() localconfig.Retry {
 	return consenter.retryOptionsVal
 }
 
+func (consenter *consenterImpl) clientWrapper() ClientWrapper {
+	return consenter.clientWrapperVal
+}
+
+func (consenter *consenterImpl) batchTimeoutJitter() float64 {
+	return consenter.batchTimeoutJitterVal
+}
+
 // closeable allows the shut down of the calling resource.
 type closeable interface {
 	close() error
@@ -98,6 +98,55 @@ func TestHandleChain(t *testing.T) {
 	assert.NoError(t, err, "Expected the HandleChain call to return without errors")
 }
 
+func TestClientWrapper(t *testing.T) {
+	wrapper := &mockClientWrapper{}
+	consenter := NewWithClientWrapper(mockLocalConfig.General.TLS, mockLocalConfig.Kafka.Retry, mockLocalConfig.Kafka.Version, wrapper)
+
+	oldestOffset := int64(0)
+	newestOffset := int64(5)
+	message := sarama.StringEncoder("messageFoo")
+
+	mockChannel := newChannel(channelNameForTest(t), defaultPartition)
+
+	mockBroker := sarama.NewMockBroker(t, 0)
+	defer func() { mockBroker.Close() }()
+	mockBroker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(mockBroker.Addr(), mockBroker.BrokerID()).
+			SetLeader(mockChannel.topic(), mockChannel.partition(), mockBroker.BrokerID()),
+		"ProduceRequest": sarama.NewMockProduceResponse(t).
+			SetError(mockChannel.topic(), mockChannel.partition(), sarama.ErrNoError),
+		"OffsetRequest": sarama.NewMockOffsetResponse(t).
+			SetOffset(mockChannel.topic(), mockChannel.partition(), sarama.OffsetOldest, oldestOffset).
+			SetOffset(mockChannel.topic(), mockChannel.partition(), sarama.OffsetNewest, newestOffset),
+		"FetchRequest": sarama.NewMockFetchResponse(t, 1).
+			SetMessage(mockChannel.topic(), mockChannel.partition(), newestOffset, message),
+	})
+
+	mockSupport := &mockmultichain.ConsenterSupport{
+		ChainIDVal: mockChannel.topic(),
+		SharedConfigVal: &mockconfig.Orderer{
+			KafkaBrokersVal: []string{mockBroker.Addr()},
+		},
+	}
+
+	mockMetadata := &cb.Metadata{Value: utils.MarshalOrPanic(&ab.KafkaMetadata{LastOffsetPersisted: newestOffset - 1})}
+
+	chain, err := consenter.HandleChain(mockSupport, mockMetadata)
+	assert.NoError(t, err, "Expected the HandleChain call to return without errors")
+
+	chain.Start()
+	select {
+	case <-chain.(*chainImpl).startChan:
+	case <-time.After(shortTimeout):
+		t.Fatal("startChan should have been closed by now")
+	}
+	defer chain.Halt()
+
+	assert.Equal(t, []string{mockChannel.topic()}, wrapper.producers, "Expected the producer of the chain to be wrapped")
+	assert.Equal(t, []string{mockChannel.topic()}, wrapper.consumers, "Expected the consumer of the chain to be wrapped")
+}
+
 // Test helper functions and mock objects defined here
 
 var mockConsenter commonConsenter
@@ -113,7 +162,7 @@ func extractEncodedOffset(marshalledOrdererMetadata []byte) int64 {
 }
 
 func newMockBrokerConfig(tlsConfig localconfig.TLS, retryOptions localconfig.Retry, kafkaVersion sarama.KafkaVersion, chosenStaticPartition int32) *sarama.Config {
-	brokerConfig := newBrokerConfig(tlsConfig, retryOptions, kafkaVersion, chosenStaticPartition)
+	brokerConfig := newBrokerConfig(tlsConfig, localconfig.SASL{}, retryOptions, kafkaVersion, chosenStaticPartition)
 	brokerConfig.ClientID = "test"
 	return brokerConfig
 }
@@ -165,7 +214,7 @@ func setupTestLogging(logLevel string, verbose bool) {
 
 // Taken from orderer/solo/consensus_test.go
 func syncQueueMessage(message *cb.Envelope, chain *chainImpl, mockBlockcutter *mockblockcutter.Receiver) {
-	chain.Enqueue(message)
+	chain.Enqueue(message, "")
 	mockBlockcutter.Block <- struct{}{} // We'll move past this line (and the function will return) only when the mock blockcutter is about to return
 }
 
@@ -178,3 +227,19 @@ func channelNameForTest(t *testing.T) string {
 	name := strings.Split(fmt.Sprint(t), " ")[18] // w/golang 1.8, use t.Name()
 	return fmt.Sprintf("%s.channel", strings.Replace(strings.ToLower(name), "/", ".", -1))
 }
+
+// mockClientWrapper records the chains whose clients it wraps
+type mockClientWrapper struct {
+	producers []string
+	consumers []string
+}
+
+func (w *mockClientWrapper) WrapProducer(chainID string, producer sarama.SyncProducer) sarama.SyncProducer {
+	w.producers = append(w.producers, chainID)
+	return producer
+}
+
+func (w *mockClientWrapper) WrapConsumer(chainID string, consumer sarama.Consumer) sarama.Consumer {
+	w.consumers = append(w.consumers, chainID)
+	return consumer
+}
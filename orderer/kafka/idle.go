@@ -0,0 +1,146 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kafka
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric/common/metrics"
+	localconfig "github.com/hyperledger/fabric/orderer/localconfig"
+	"github.com/hyperledger/fabric/orderer/multichain"
+	cb "github.com/hyperledger/fabric/protos/common"
+	gometrics "github.com/rcrowley/go-metrics"
+)
+
+// idleChecksPerPeriod is the number of times per idle period a chain is checked for idleness
+const idleChecksPerPeriod = 10
+
+// WithIdleWatch returns a consenter whose chains are watched for idleness. A
+// chain which has not cut a block for the configured period is logged, counted
+// in the kafka.idle_channels metric, and has the configured command run for its
+// topic, so that the operators of a shared Kafka cluster can reclaim the data
+// retained for abandoned channels. The vendored Kafka client cannot alter the
+// configuration of topics, hence the command.
+func WithIdleWatch(consenter multichain.Consenter, conf localconfig.IdleChannels) multichain.Consenter {
+	return &idleWatchConsenter{Consenter: consenter, conf: conf}
+}
+
+type idleWatchConsenter struct {
+	multichain.Consenter
+	conf localconfig.IdleChannels
+}
+
+func (consenter *idleWatchConsenter) HandleChain(support multichain.ConsenterSupport, metadata *cb.Metadata) (multichain.Chain, error) {
+	chain, err := consenter.Consenter.HandleChain(support, metadata)
+	if err != nil {
+		return nil, err
+	}
+	return newIdleWatcher(chain, support, consenter.conf), nil
+}
+
+// idleWatcher decorates a chain with the periodic check of its height
+type idleWatcher struct {
+	multichain.Chain
+	support multichain.ConsenterSupport
+	conf    localconfig.IdleChannels
+	channel channel
+	now     func() time.Time
+
+	lastHeight    uint64
+	lastBlockTime time.Time
+	idle          bool
+
+	idleChannels gometrics.Counter
+	idleSeconds  gometrics.Gauge
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+func newIdleWatcher(chain multichain.Chain, support multichain.ConsenterSupport, conf localconfig.IdleChannels) *idleWatcher {
+	return &idleWatcher{
+		Chain:        chain,
+		support:      support,
+		conf:         conf,
+		channel:      newChannel(support.ChainID(), defaultPartition),
+		now:          time.Now,
+		idleChannels: gometrics.GetOrRegisterCounter("kafka.idle_channels", metrics.Registry),
+		idleSeconds:  gometrics.GetOrRegisterGauge("kafka."+support.ChainID()+".idle_seconds", metrics.Registry),
+		stop:         make(chan struct{}),
+	}
+}
+
+// Start starts the chain, and then checks it for idleness until it is halted
+func (w *idleWatcher) Start() {
+	w.Chain.Start()
+	w.lastHeight = w.support.Height()
+	w.lastBlockTime = w.now()
+	go func() {
+		ticker := time.NewTicker(w.conf.Period / idleChecksPerPeriod)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				w.check()
+			case <-w.stop:
+				if w.idle {
+					w.idleChannels.Dec(1)
+				}
+				return
+			}
+		}
+	}()
+}
+
+// Halt stops the checks and halts the chain
+func (w *idleWatcher) Halt() {
+	w.stopOnce.Do(func() { close(w.stop) })
+	w.Chain.Halt()
+}
+
+// check marks the chain as idle once it has not cut a block for the idle
+// period, and as active again as soon as it cuts one. As the time a block was
+// cut is not recorded, the chain is idle for at least the time since the
+// orderer started
+func (w *idleWatcher) check() {
+	now := w.now()
+	if height := w.support.Height(); height != w.lastHeight {
+		w.lastHeight = height
+		w.lastBlockTime = now
+		if w.idle {
+			w.idle = false
+			w.idleChannels.Dec(1)
+			logger.Infof("[channel: %s] Channel is no longer idle", w.support.ChainID())
+		}
+	}
+
+	idleFor := now.Sub(w.lastBlockTime)
+	w.idleSeconds.Update(int64(idleFor / time.Second))
+	if w.idle || idleFor < w.conf.Period {
+		return
+	}
+
+	w.idle = true
+	w.idleChannels.Inc(1)
+	logger.Warningf("[channel: %s] Channel has not cut a block for %s, consider reducing the retention of topic %s",
+		w.support.ChainID(), idleFor, w.channel.topic())
+	if w.conf.Command == "" {
+		return
+	}
+	cmd := exec.Command("sh", "-c", w.conf.Command)
+	cmd.Env = append(os.Environ(), "CHANNEL="+w.support.ChainID(), "TOPIC="+w.channel.topic())
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		logger.Errorf("[channel: %s] Idle channel command failed: %s: %s", w.support.ChainID(), err, strings.TrimSpace(string(output)))
+		return
+	}
+	logger.Infof("[channel: %s] Idle channel command output: %s", w.support.ChainID(), strings.TrimSpace(string(output)))
+}
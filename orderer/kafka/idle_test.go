@@ -0,0 +1,91 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kafka
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	localconfig "github.com/hyperledger/fabric/orderer/localconfig"
+	mockmultichain "github.com/hyperledger/fabric/orderer/mocks/multichain"
+	"github.com/hyperledger/fabric/orderer/multichain"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockIdleChain struct {
+	started, halted bool
+}
+
+func (c *mockIdleChain) Enqueue(env *cb.Envelope, traceID string) bool { return true }
+func (c *mockIdleChain) Errored() <-chan struct{}                      { return nil }
+func (c *mockIdleChain) Start()                                        { c.started = true }
+func (c *mockIdleChain) Halt()                                         { c.halted = true }
+
+type mockIdleConsenter struct {
+	chain *mockIdleChain
+}
+
+func (c *mockIdleConsenter) HandleChain(support multichain.ConsenterSupport, metadata *cb.Metadata) (multichain.Chain, error) {
+	return c.chain, nil
+}
+
+func TestIdleWatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "idlechannels")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	out := filepath.Join(dir, "out")
+
+	mockChain := &mockIdleChain{}
+	support := &mockmultichain.ConsenterSupport{ChainIDVal: "idlechannel", HeightVal: 5}
+	consenter := WithIdleWatch(&mockIdleConsenter{chain: mockChain}, localconfig.IdleChannels{
+		Enabled: true,
+		Period:  time.Hour,
+		Command: "printf '%s %s ' \"$CHANNEL\" \"$TOPIC\" >> " + out,
+	})
+	chain, err := consenter.HandleChain(support, &cb.Metadata{})
+	require.NoError(t, err)
+	w := chain.(*idleWatcher)
+	now := time.Date(2017, 6, 1, 12, 0, 0, 0, time.UTC)
+	w.now = func() time.Time { return now }
+	idleChannels := w.idleChannels.Count()
+
+	w.Start()
+	defer w.Halt()
+	assert.True(t, mockChain.started)
+
+	now = now.Add(30 * time.Minute)
+	w.check()
+	assert.False(t, w.idle)
+	assert.Equal(t, int64(30*60), w.idleSeconds.Value())
+
+	now = now.Add(30 * time.Minute)
+	w.check()
+	assert.True(t, w.idle, "Should be idle after the period without a block")
+	assert.Equal(t, idleChannels+1, w.idleChannels.Count())
+	now = now.Add(time.Hour)
+	w.check()
+	assert.Equal(t, idleChannels+1, w.idleChannels.Count(), "Should have counted the channel once")
+	data, err := ioutil.ReadFile(out)
+	require.NoError(t, err)
+	assert.Equal(t, "idlechannel idlechannel ", string(data), "Should have run the command once")
+
+	support.HeightVal++
+	now = now.Add(time.Minute)
+	w.check()
+	assert.False(t, w.idle, "Should be active again after a block")
+	assert.Equal(t, idleChannels, w.idleChannels.Count())
+	assert.Equal(t, int64(0), w.idleSeconds.Value())
+
+	w.Halt()
+	w.Halt()
+	assert.True(t, mockChain.halted)
+}
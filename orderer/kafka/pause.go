@@ -0,0 +1,145 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kafka
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/Shopify/sarama"
+	"github.com/hyperledger/fabric/orderer/multichain"
+)
+
+// pauseRequest asks the processMessagesToBlocks loop to pause the chain, or to
+// resume it. The outcome is sent on done.
+type pauseRequest struct {
+	pause bool
+	done  chan error
+}
+
+// Pause stops the consumption of the channel and the cutting of blocks, and
+// rejects the broadcasts, until Resume is called. The channel consumer is
+// closed, so that the chain rides out the maintenance of the Kafka cluster
+// without erroring, and the Deliver clients are still served the blocks
+// already written. Pausing a paused chain does nothing. Implements the
+// multichain.Pauser interface.
+func (chain *chainImpl) Pause() error {
+	return chain.requestPause(true)
+}
+
+// Resume consumes the channel again from the offset following the last
+// message consumed before the chain was paused, so that no message is skipped
+// or processed twice. Resuming a running chain does nothing. Implements the
+// multichain.Pauser interface.
+func (chain *chainImpl) Resume() error {
+	return chain.requestPause(false)
+}
+
+// Paused returns whether the chain is paused. Implements the
+// multichain.Pauser interface.
+func (chain *chainImpl) Paused() bool {
+	return atomic.LoadInt32(&chain.paused) == 1
+}
+
+// requestPause hands the request over to the processMessagesToBlocks loop,
+// which owns the channel consumer and the timers, and waits for its outcome
+func (chain *chainImpl) requestPause(pause bool) error {
+	select {
+	case <-chain.startChan:
+	default:
+		return fmt.Errorf("consenter for this channel hasn't started yet")
+	}
+
+	req := pauseRequest{pause: pause, done: make(chan error, 1)}
+	select {
+	case chain.pauseChan <- req:
+	case <-chain.haltChan:
+		return fmt.Errorf("consenter for this channel has been halted")
+	case <-chain.exitChan:
+		return fmt.Errorf("consenter for this channel has exited")
+	}
+	return <-req.done
+}
+
+// pause closes the channel consumer. Called by the processMessagesToBlocks
+// loop, which stops the timers.
+func (chain *chainImpl) pause(lastOffsetConsumed int64) {
+	chain.clientLock.Lock()
+	channelConsumer := chain.channelConsumer
+	chain.channelConsumer = pausedConsumer{}
+	chain.clientLock.Unlock()
+	if err := channelConsumer.Close(); err != nil {
+		logger.Errorf("[channel: %s] could not close channelConsumer cleanly = %s", chain.support.ChainID(), err)
+	}
+	atomic.StoreInt32(&chain.paused, 1)
+	logger.Warningf("[channel: %s] Consenter paused after offset %d", chain.support.ChainID(), lastOffsetConsumed)
+}
+
+// resume sets up the channel consumer again, from the given offset. Called by
+// the processMessagesToBlocks loop, which restarts the timers on success.
+func (chain *chainImpl) resume(startFrom int64) error {
+	channelConsumer, err := setupChannelConsumerForChannel(chain.consenter.retryOptions(), chain.haltChan, chain.parentConsumer, chain.channel, startFrom)
+	if err != nil {
+		logger.Errorf("[channel: %s] Cannot resume consenter = %s", chain.support.ChainID(), err)
+		return fmt.Errorf("cannot set up channel consumer: %s", err)
+	}
+	chain.clientLock.Lock()
+	chain.channelConsumer = channelConsumer
+	chain.clientLock.Unlock()
+	atomic.StoreInt32(&chain.paused, 0)
+	logger.Infof("[channel: %s] Consenter resumed from offset %d", chain.support.ChainID(), startFrom)
+	return nil
+}
+
+// pausedConsumer stands for the channel consumer of a paused chain: it
+// delivers nothing, and can be closed when the chain is halted
+type pausedConsumer struct{}
+
+func (pausedConsumer) AsyncClose()                              {}
+func (pausedConsumer) Close() error                             { return nil }
+func (pausedConsumer) Messages() <-chan *sarama.ConsumerMessage { return nil }
+func (pausedConsumer) Errors() <-chan *sarama.ConsumerError     { return nil }
+func (pausedConsumer) HighWaterMarkOffset() int64               { return 0 }
+
+// pauseDecorated pauses, or resumes, the chain wrapped by a decorator
+func pauseDecorated(chain multichain.Chain, pause bool) error {
+	pauser, ok := chain.(multichain.Pauser)
+	if !ok {
+		return fmt.Errorf("consenter for this channel cannot be paused")
+	}
+	if pause {
+		return pauser.Pause()
+	}
+	return pauser.Resume()
+}
+
+// pausedDecorated returns whether the chain wrapped by a decorator is paused
+func pausedDecorated(chain multichain.Chain) bool {
+	pauser, ok := chain.(multichain.Pauser)
+	return ok && pauser.Paused()
+}
+
+// Pause pauses the chain watched. Implements the multichain.Pauser interface.
+func (w *idleWatcher) Pause() error { return pauseDecorated(w.Chain, true) }
+
+// Resume resumes the chain watched. Implements the multichain.Pauser interface.
+func (w *idleWatcher) Resume() error { return pauseDecorated(w.Chain, false) }
+
+// Paused returns whether the chain watched is paused. Implements the
+// multichain.Pauser interface.
+func (w *idleWatcher) Paused() bool { return pausedDecorated(w.Chain) }
+
+// Pause pauses the chain decorated. Implements the multichain.Pauser interface.
+func (s *loadShedder) Pause() error { return pauseDecorated(s.Chain, true) }
+
+// Resume resumes the chain decorated. Implements the multichain.Pauser
+// interface.
+func (s *loadShedder) Resume() error { return pauseDecorated(s.Chain, false) }
+
+// Paused returns whether the chain decorated is paused. Implements the
+// multichain.Pauser interface.
+func (s *loadShedder) Paused() bool { return pausedDecorated(s.Chain) }
@@ -0,0 +1,97 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kafka
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	mockconfig "github.com/hyperledger/fabric/common/mocks/config"
+	localconfig "github.com/hyperledger/fabric/orderer/localconfig"
+	mockmultichain "github.com/hyperledger/fabric/orderer/mocks/multichain"
+	"github.com/hyperledger/fabric/orderer/multichain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPause(t *testing.T) {
+	mockChannel := newChannel(channelNameForTest(t), defaultPartition)
+	mockBroker := sarama.NewMockBroker(t, 0)
+	defer mockBroker.Close()
+	mockBroker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(mockBroker.Addr(), mockBroker.BrokerID()).
+			SetLeader(mockChannel.topic(), mockChannel.partition(), mockBroker.BrokerID()),
+		"ProduceRequest": sarama.NewMockProduceResponse(t).
+			SetError(mockChannel.topic(), mockChannel.partition(), sarama.ErrNoError),
+		"OffsetRequest": sarama.NewMockOffsetResponse(t).
+			SetOffset(mockChannel.topic(), mockChannel.partition(), sarama.OffsetOldest, 0).
+			SetOffset(mockChannel.topic(), mockChannel.partition(), sarama.OffsetNewest, 6),
+		"FetchRequest": sarama.NewMockFetchResponse(t, 1).
+			SetMessage(mockChannel.topic(), mockChannel.partition(), 5, sarama.StringEncoder("messageFoo")),
+	})
+	mockSupport := &mockmultichain.ConsenterSupport{
+		ChainIDVal:      mockChannel.topic(),
+		HeightVal:       uint64(3),
+		SharedConfigVal: &mockconfig.Orderer{KafkaBrokersVal: []string{mockBroker.Addr()}},
+	}
+	chain, _ := newChain(mockConsenter, mockSupport, 4)
+
+	assert.Error(t, chain.Pause(), "Expected an error before the chain started")
+
+	chain.Start()
+	select {
+	case <-chain.startChan:
+	case <-time.After(shortTimeout):
+		t.Fatal("startChan should have been closed by now")
+	}
+	assert.True(t, chain.Enqueue(newMockEnvelope("fooMessage"), ""), "Expected the message to be enqueued")
+
+	require.NoError(t, chain.Pause())
+	assert.True(t, chain.Paused())
+	assert.Equal(t, pausedConsumer{}, chain.channelConsumer, "Expected the channel consumer to be closed")
+	assert.False(t, chain.Enqueue(newMockEnvelope("fooMessage"), ""), "Expected the message to be rejected while paused")
+	assert.NoError(t, chain.Pause(), "Expected pausing a paused chain to do nothing")
+
+	require.NoError(t, chain.Resume())
+	assert.False(t, chain.Paused())
+	assert.NotEqual(t, pausedConsumer{}, chain.channelConsumer, "Expected the channel consumer to be set up again")
+	assert.True(t, chain.Enqueue(newMockEnvelope("fooMessage"), ""), "Expected the message to be enqueued once resumed")
+	assert.NoError(t, chain.Resume(), "Expected resuming a running chain to do nothing")
+
+	require.NoError(t, chain.Pause())
+	assert.NotPanics(t, chain.Halt, "Expected a paused chain to halt")
+	assert.Error(t, chain.Resume(), "Expected an error once the chain is halted")
+}
+
+type mockPausingChain struct {
+	mockIdleChain
+	paused bool
+}
+
+func (mpc *mockPausingChain) Pause() error  { mpc.paused = true; return nil }
+func (mpc *mockPausingChain) Resume() error { mpc.paused = false; return nil }
+func (mpc *mockPausingChain) Paused() bool  { return mpc.paused }
+
+func TestPauseDecorated(t *testing.T) {
+	chain := &mockPausingChain{}
+	support := &mockmultichain.ConsenterSupport{ChainIDVal: "mychannel"}
+	decorated := multichain.Chain(newLoadShedder(newIdleWatcher(chain, support, localconfig.IdleChannels{Period: time.Hour}), "mychannel", localconfig.LoadShedding{Window: 10}))
+
+	pauser, ok := decorated.(multichain.Pauser)
+	require.True(t, ok, "Expected the decorated chain to be pausable")
+	assert.NoError(t, pauser.Pause())
+	assert.True(t, chain.paused)
+	assert.True(t, pauser.Paused())
+	assert.NoError(t, pauser.Resume())
+	assert.False(t, pauser.Paused())
+
+	pauser = newIdleWatcher(&mockIdleChain{}, support, localconfig.IdleChannels{Period: time.Hour})
+	assert.Error(t, pauser.Pause(), "Expected an error for a chain which cannot be paused")
+	assert.False(t, pauser.Paused())
+}
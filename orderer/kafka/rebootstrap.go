@@ -0,0 +1,127 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kafka
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Shopify/sarama"
+)
+
+// brokersChanged returns whether the brokers of the channel config differ
+// from the ones the Kafka clients of the chain were set up against
+func (chain *chainImpl) brokersChanged() bool {
+	return !sameBrokers(chain.brokers, chain.support.SharedConfig().KafkaBrokers())
+}
+
+// rebootstrap sets up the producer and the consumers of the chain against the
+// brokers of the channel config, without halting the chain, and closes the
+// former ones. The new brokers are checked first, and the switch only happens
+// if at least one of them can be reached, as the clients need a single one to
+// bootstrap the metadata of the cluster. The channel consumer resumes from the
+// offset following the last message consumed, so that no message is skipped
+// or processed twice. Called by the processMessagesToBlocks loop, which owns
+// the consumers, once a config block changing the brokers is written, and
+// again on the expiration of the retry timer if it fails.
+func (chain *chainImpl) rebootstrap(lastOffsetConsumed int64) error {
+	brokers := chain.support.SharedConfig().KafkaBrokers()
+	brokerConfig := chain.consenter.brokerConfig()
+	logger.Infof("[channel: %s] Kafka brokers changed from %v to %v, setting up the producer and the consumers again",
+		chain.support.ChainID(), chain.brokers, brokers)
+
+	errs := checkBrokers(brokerConfig, brokers)
+	if len(errs) == len(brokers) {
+		return fmt.Errorf("none of the brokers %v can be reached: %s", brokers, brokerErrors(errs))
+	}
+	if len(errs) > 0 {
+		logger.Warningf("[channel: %s] Some of the new Kafka brokers cannot be reached: %s", chain.support.ChainID(), brokerErrors(errs))
+	}
+
+	producer, err := sarama.NewSyncProducer(brokers, brokerConfig)
+	if err != nil {
+		return fmt.Errorf("cannot set up producer: %s", err)
+	}
+	parentConsumer, err := sarama.NewConsumer(brokers, brokerConfig)
+	if err != nil {
+		producer.Close()
+		return fmt.Errorf("cannot set up parent consumer: %s", err)
+	}
+	if wrapper := chain.consenter.clientWrapper(); wrapper != nil {
+		producer = wrapper.WrapProducer(chain.support.ChainID(), producer)
+		parentConsumer = wrapper.WrapConsumer(chain.support.ChainID(), parentConsumer)
+	}
+	// A paused chain sets up its channel consumer when resumed
+	var channelConsumer sarama.PartitionConsumer = pausedConsumer{}
+	if !chain.Paused() {
+		channelConsumer, err = parentConsumer.ConsumePartition(chain.channel.topic(), chain.channel.partition(), lastOffsetConsumed+1)
+		if err != nil {
+			parentConsumer.Close()
+			producer.Close()
+			return fmt.Errorf("cannot set up channel consumer: %s", err)
+		}
+	}
+
+	chain.clientLock.Lock()
+	select {
+	case <-chain.haltChan:
+		// Halt closed the former clients already
+		chain.clientLock.Unlock()
+		channelConsumer.Close()
+		parentConsumer.Close()
+		producer.Close()
+		return fmt.Errorf("consenter for this channel has been halted")
+	default:
+	}
+	oldProducer, oldParentConsumer, oldChannelConsumer := chain.producer, chain.parentConsumer, chain.channelConsumer
+	chain.producer, chain.parentConsumer, chain.channelConsumer = producer, parentConsumer, channelConsumer
+	chain.brokers = brokers
+	chain.clientLock.Unlock()
+
+	if err := oldChannelConsumer.Close(); err != nil {
+		logger.Errorf("[channel: %s] could not close former channelConsumer cleanly = %s", chain.support.ChainID(), err)
+	}
+	if err := oldParentConsumer.Close(); err != nil {
+		logger.Errorf("[channel: %s] could not close former parentConsumer cleanly = %s", chain.support.ChainID(), err)
+	}
+	if err := oldProducer.Close(); err != nil {
+		logger.Errorf("[channel: %s] could not close former producer cleanly = %s", chain.support.ChainID(), err)
+	}
+	logger.Infof("[channel: %s] Producer and consumers set up against Kafka brokers %v, consuming from offset %d",
+		chain.support.ChainID(), brokers, lastOffsetConsumed+1)
+	return nil
+}
+
+// sameBrokers returns whether the two lists hold the same brokers, in any
+// order
+func sameBrokers(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	sortedA := append([]string(nil), a...)
+	sortedB := append([]string(nil), b...)
+	sort.Strings(sortedA)
+	sort.Strings(sortedB)
+	for i := range sortedA {
+		if sortedA[i] != sortedB[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// brokerErrors formats the errors of the brokers which cannot be reached,
+// sorted by address
+func brokerErrors(errs map[string]error) string {
+	var descriptions []string
+	for address, err := range errs {
+		descriptions = append(descriptions, fmt.Sprintf("%s: %s", address, err))
+	}
+	sort.Strings(descriptions)
+	return strings.Join(descriptions, ", ")
+}
@@ -0,0 +1,193 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kafka
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/Shopify/sarama/mocks"
+	mockconfig "github.com/hyperledger/fabric/common/mocks/config"
+	mockblockcutter "github.com/hyperledger/fabric/orderer/mocks/blockcutter"
+	mockmultichain "github.com/hyperledger/fabric/orderer/mocks/multichain"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRebootstrapBroker returns a mock broker leading the partition of the
+// channel, which holds a single regular message at the given offset
+func newRebootstrapBroker(t *testing.T, mockChannel channel, offset int64) *sarama.MockBroker {
+	mockBroker := sarama.NewMockBroker(t, 0)
+	mockBroker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(mockBroker.Addr(), mockBroker.BrokerID()).
+			SetLeader(mockChannel.topic(), mockChannel.partition(), mockBroker.BrokerID()),
+		"ProduceRequest": sarama.NewMockProduceResponse(t).
+			SetError(mockChannel.topic(), mockChannel.partition(), sarama.ErrNoError),
+		"OffsetRequest": sarama.NewMockOffsetResponse(t).
+			SetOffset(mockChannel.topic(), mockChannel.partition(), sarama.OffsetOldest, 0).
+			SetOffset(mockChannel.topic(), mockChannel.partition(), sarama.OffsetNewest, offset+1),
+		"FetchRequest": sarama.NewMockFetchResponse(t, 1).
+			SetMessage(mockChannel.topic(), mockChannel.partition(), offset,
+				sarama.ByteEncoder(utils.MarshalOrPanic(newRegularMessage(utils.MarshalOrPanic(newMockEnvelope("fooMessage")))))),
+	})
+	return mockBroker
+}
+
+func TestRebootstrap(t *testing.T) {
+	newestOffset := int64(5)
+	mockChannel := newChannel(channelNameForTest(t), defaultPartition)
+	brokerA := newRebootstrapBroker(t, mockChannel, newestOffset)
+	defer brokerA.Close()
+	brokerB := newRebootstrapBroker(t, mockChannel, newestOffset)
+	defer brokerB.Close()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	unreachable := l.Addr().String()
+	l.Close()
+
+	sharedConfig := &mockconfig.Orderer{KafkaBrokersVal: []string{brokerA.Addr()}}
+	startChan := make(chan struct{})
+	close(startChan)
+	chain := &chainImpl{
+		consenter: mockConsenter,
+		support:   &mockmultichain.ConsenterSupport{ChainIDVal: mockChannel.topic(), SharedConfigVal: sharedConfig},
+		channel:   mockChannel,
+		brokers:   []string{brokerA.Addr()},
+		haltChan:  make(chan struct{}),
+		startChan: startChan,
+	}
+	chain.producer, err = setupProducerForChannel(mockConsenter.retryOptions(), chain.haltChan, chain.brokers, mockBrokerConfig, mockChannel)
+	require.NoError(t, err)
+	chain.parentConsumer, err = setupParentConsumerForChannel(mockConsenter.retryOptions(), chain.haltChan, chain.brokers, mockBrokerConfig, mockChannel)
+	require.NoError(t, err)
+	chain.channelConsumer, err = setupChannelConsumerForChannel(mockConsenter.retryOptions(), chain.haltChan, chain.parentConsumer, mockChannel, newestOffset)
+	require.NoError(t, err)
+	defer chain.Halt()
+
+	assert.False(t, chain.brokersChanged())
+
+	t.Run("Unreachable", func(t *testing.T) {
+		producer := chain.producer
+		sharedConfig.KafkaBrokersVal = []string{unreachable}
+		assert.True(t, chain.brokersChanged())
+		assert.Error(t, chain.rebootstrap(newestOffset-1), "Expected an error when none of the new brokers can be reached")
+		assert.Equal(t, []string{brokerA.Addr()}, chain.brokers, "Expected the former brokers to be kept")
+		assert.Equal(t, producer, chain.producer, "Expected the former producer to be kept")
+	})
+
+	t.Run("Proper", func(t *testing.T) {
+		sharedConfig.KafkaBrokersVal = []string{unreachable, brokerB.Addr()}
+		require.NoError(t, chain.rebootstrap(newestOffset-1), "Expected a single reachable broker to be enough")
+		assert.Equal(t, []string{unreachable, brokerB.Addr()}, chain.brokers)
+		assert.False(t, chain.brokersChanged())
+
+		select {
+		case in := <-chain.channelConsumer.Messages():
+			assert.Equal(t, newestOffset, in.Offset, "Expected the consumption to resume after the last message consumed")
+		case <-time.After(shortTimeout):
+			t.Fatal("Expected a message from the new channel consumer")
+		}
+		assert.True(t, chain.Enqueue(newMockEnvelope("fooMessage"), ""), "Expected the message to be posted to the new brokers")
+	})
+
+	t.Run("Paused", func(t *testing.T) {
+		chain.pause(newestOffset)
+		sharedConfig.KafkaBrokersVal = []string{brokerA.Addr()}
+		require.NoError(t, chain.rebootstrap(newestOffset))
+		assert.Equal(t, pausedConsumer{}, chain.channelConsumer, "Expected the channel consumer to be set up when resumed")
+		require.NoError(t, chain.resume(newestOffset))
+		assert.NotEqual(t, pausedConsumer{}, chain.channelConsumer)
+	})
+
+	t.Run("Halted", func(t *testing.T) {
+		chain.Halt()
+		sharedConfig.KafkaBrokersVal = []string{brokerB.Addr()}
+		assert.Error(t, chain.rebootstrap(newestOffset))
+		assert.Equal(t, []string{brokerA.Addr()}, chain.brokers)
+	})
+}
+
+func TestRebootstrapAfterConfigBlock(t *testing.T) {
+	mockChannel := newChannel(channelNameForTest(t), defaultPartition)
+	newestOffset := int64(0)
+	// The mock partition consumer yields the message of the config block at
+	// offset 1, and the brokers hold the following one
+	mockBroker := newRebootstrapBroker(t, mockChannel, 2)
+	defer mockBroker.Close()
+
+	mockBrokerConfigCopy := *mockBrokerConfig
+	mockBrokerConfigCopy.ChannelBufferSize = 0
+	mockParentConsumer := mocks.NewConsumer(t, &mockBrokerConfigCopy)
+	mpc := mockParentConsumer.ExpectConsumePartition(mockChannel.topic(), mockChannel.partition(), newestOffset)
+	mockChannelConsumer, err := mockParentConsumer.ConsumePartition(mockChannel.topic(), mockChannel.partition(), newestOffset)
+	require.NoError(t, err)
+
+	errorChan := make(chan struct{})
+	close(errorChan)
+	mockSupport := &mockmultichain.ConsenterSupport{
+		Blocks:         make(chan *cb.Block), // WriteBlock will post here
+		BlockCutterVal: mockblockcutter.NewReceiver(),
+		ChainIDVal:     mockChannel.topic(),
+		HeightVal:      1,
+		SharedConfigVal: &mockconfig.Orderer{
+			BatchTimeoutVal: longTimeout,
+			// As changed by the config block cut
+			KafkaBrokersVal: []string{mockBroker.Addr()},
+		},
+	}
+	defer close(mockSupport.BlockCutterVal.Block)
+
+	bareMinimumChain := &chainImpl{
+		consenter:       mockConsenter,
+		brokers:         []string{"former.broker:9092"},
+		producer:        mocks.NewSyncProducer(t, nil),
+		parentConsumer:  mockParentConsumer,
+		channelConsumer: mockChannelConsumer,
+
+		channel:            mockChannel,
+		support:            mockSupport,
+		lastCutBlockNumber: 1,
+
+		errorChan: errorChan,
+		haltChan:  make(chan struct{}),
+	}
+
+	done := make(chan struct{})
+	go func() {
+		bareMinimumChain.processMessagesToBlocks()
+		close(done)
+	}()
+
+	mockSupport.BlockCutterVal.CutNext = true
+	mpc.YieldMessage(newMockConsumerMessage(newRegularMessage(utils.MarshalOrPanic(newMockEnvelope("fooMessage")))))
+	mockSupport.BlockCutterVal.Block <- struct{}{} // Let the `mockblockcutter.Ordered` call return
+	<-mockSupport.Blocks                           // Let the `mockConsenterSupport.WriteBlock` proceed
+
+	// The message following the one of the config block is consumed from the new brokers
+	mockSupport.BlockCutterVal.CutNext = false
+	select {
+	case mockSupport.BlockCutterVal.Block <- struct{}{}:
+	case <-time.After(shortTimeout):
+		t.Fatal("Expected the message following the config block to be consumed from the new brokers")
+	}
+
+	bareMinimumChain.Halt()
+	<-done
+	assert.Equal(t, []string{mockBroker.Addr()}, bareMinimumChain.brokers, "Expected the clients to be set up against the new brokers")
+}
+
+func TestSameBrokers(t *testing.T) {
+	assert.True(t, sameBrokers(nil, []string{}))
+	assert.True(t, sameBrokers([]string{"a:9092", "b:9092"}, []string{"b:9092", "a:9092"}))
+	assert.False(t, sameBrokers([]string{"a:9092"}, []string{"a:9092", "b:9092"}))
+	assert.False(t, sameBrokers([]string{"a:9092", "b:9092"}, []string{"a:9092", "c:9092"}))
+}
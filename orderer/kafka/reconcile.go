@@ -0,0 +1,145 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kafka
+
+import (
+	"bytes"
+
+	"github.com/Shopify/sarama"
+	"github.com/hyperledger/fabric/orderer/common/filter"
+	"github.com/hyperledger/fabric/orderer/ledger"
+	"github.com/hyperledger/fabric/orderer/multichain"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+)
+
+// ledgerReader is implemented by the consenter supports which give access to
+// the blocks of their ledger
+type ledgerReader interface {
+	Reader() ledger.Reader
+}
+
+// verifyLastCutBlock checks that the ledger height matches the last cut block
+// persisted with the offset in the metadata of the newest block. On mismatch,
+// the chain enters a reconciliation mode, re-consuming from the offset of the
+// newest block whose metadata can be verified, and cutting again the blocks
+// already in the ledger without writing them, rather than double-cutting or
+// skipping messages.
+func (chain *chainImpl) verifyLastCutBlock(kafkaMetadata *ab.KafkaMetadata) {
+	if kafkaMetadata == nil || kafkaMetadata.LastCutBlockNumber == 0 {
+		// The genesis block, or a block written before the block numbers
+		// were persisted, which cannot be verified
+		return
+	}
+	if kafkaMetadata.LastCutBlockNumber == chain.lastCutBlockNumber {
+		return
+	}
+
+	height := chain.support.Height()
+	logger.Errorf("[channel: %s] Ledger height %d does not match last cut block %d persisted with offset %d, reconciling",
+		chain.support.ChainID(), height, kafkaMetadata.LastCutBlockNumber, kafkaMetadata.LastOffsetPersisted)
+
+	var reader ledger.Reader
+	if lr, ok := chain.support.(ledgerReader); ok {
+		reader = lr.Reader()
+	}
+	chain.lastCutBlockNumber, chain.lastOffsetPersisted = lastVerifiedBlock(reader, chain.lastCutBlockNumber, chain.support.ChainID())
+	logger.Warningf("[channel: %s] Re-consuming from offset %d, after block %d, to reconcile blocks up to %d",
+		chain.support.ChainID(), chain.lastOffsetPersisted+1, chain.lastCutBlockNumber, height-1)
+
+	chain.support = &reconciler{
+		ConsenterSupport:   chain.support,
+		reader:             reader,
+		height:             height,
+		lastCutBlockNumber: &chain.lastCutBlockNumber,
+	}
+}
+
+// lastVerifiedBlock returns the number of the newest block before the given one
+// whose metadata holds its own number, or was written before the block numbers
+// were persisted, along with the offset persisted in it. Without a reader, or
+// if there is no such block, the partition is re-consumed from the beginning.
+func lastVerifiedBlock(reader ledger.Reader, before uint64, chainID string) (uint64, int64) {
+	if reader != nil && before > 1 {
+		for number := before - 1; number > 0; number-- {
+			it, start := reader.Iterator(&ab.SeekPosition{Type: &ab.SeekPosition_Specified{Specified: &ab.SeekSpecified{Number: number}}})
+			if start != number {
+				logger.Warningf("[channel: %s] Cannot seek block %d to verify its metadata", chainID, number)
+				break
+			}
+			block, status := it.Next()
+			if status != cb.Status_SUCCESS || block == nil || block.Header == nil {
+				logger.Warningf("[channel: %s] Cannot read block %d to verify its metadata: %s", chainID, number, status)
+				break
+			}
+			metadata, err := utils.GetMetadataFromBlock(block, cb.BlockMetadataIndex_ORDERER)
+			if err != nil {
+				logger.Warningf("[channel: %s] Cannot read the orderer metadata of block %d: %s", chainID, number, err)
+				break
+			}
+			kafkaMetadata := getKafkaMetadata(metadata.Value, chainID)
+			if kafkaMetadata == nil {
+				break
+			}
+			if kafkaMetadata.LastCutBlockNumber == 0 || kafkaMetadata.LastCutBlockNumber == number {
+				return number, kafkaMetadata.LastOffsetPersisted
+			}
+		}
+	}
+	return 0, sarama.OffsetOldest - 1
+}
+
+// reconciler wraps the support of a chain in reconciliation mode: the blocks
+// cut again which are already in the ledger are verified against it rather
+// than written, until the chain catches up with the ledger height
+type reconciler struct {
+	multichain.ConsenterSupport
+	reader ledger.Reader
+	// height is the ledger height when the reconciliation started
+	height uint64
+	// lastCutBlockNumber points to the last cut block number of the chain
+	lastCutBlockNumber *uint64
+	caughtUp           bool
+}
+
+func (r *reconciler) CreateNextBlock(messages []*cb.Envelope) *cb.Block {
+	block := r.ConsenterSupport.CreateNextBlock(messages)
+	if number := *r.lastCutBlockNumber + 1; number < r.height {
+		// The ledger numbers the next block after its newest one
+		block.Header.Number = number
+	}
+	return block
+}
+
+func (r *reconciler) WriteBlock(block *cb.Block, committers []filter.Committer, encodedMetadataValue []byte) *cb.Block {
+	if block.Header.Number >= r.height {
+		if !r.caughtUp {
+			r.caughtUp = true
+			logger.Infof("[channel: %s] Reconciliation complete, writing block %d", r.ChainID(), block.Header.Number)
+		}
+		return r.ConsenterSupport.WriteBlock(block, committers, encodedMetadataValue)
+	}
+
+	// The committers already ran when the block was first written
+	if r.reader != nil {
+		// The ledgers return an iterator failing with NOT_FOUND, starting at 0, when they cannot seek the block
+		it, start := r.reader.Iterator(&ab.SeekPosition{Type: &ab.SeekPosition_Specified{Specified: &ab.SeekSpecified{Number: block.Header.Number}}})
+		if start != block.Header.Number {
+			logger.Panicf("[channel: %s] Cannot seek block %d to reconcile it", r.ChainID(), block.Header.Number)
+		}
+		written, status := it.Next()
+		if status != cb.Status_SUCCESS || written == nil || written.Header == nil {
+			logger.Panicf("[channel: %s] Cannot read block %d to reconcile it: %s", r.ChainID(), block.Header.Number, status)
+		}
+		if !bytes.Equal(written.Header.DataHash, block.Header.DataHash) {
+			logger.Panicf("[channel: %s] Ledger may be corrupted: block %d cut again does not match the one in the ledger", r.ChainID(), block.Header.Number)
+		}
+	}
+	logger.Debugf("[channel: %s] Reconciled block %d already in the ledger", r.ChainID(), block.Header.Number)
+	return block
+}
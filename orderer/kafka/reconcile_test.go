@@ -0,0 +1,154 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kafka
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/hyperledger/fabric/orderer/ledger"
+	ramledger "github.com/hyperledger/fabric/orderer/ledger/ram"
+	mockmultichain "github.com/hyperledger/fabric/orderer/mocks/multichain"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockReaderSupport numbers the blocks after the newest one of its ledger, as
+// the support of the multichain manager does
+type mockReaderSupport struct {
+	*mockmultichain.ConsenterSupport
+	rl ledger.ReadWriter
+}
+
+func (mrs *mockReaderSupport) Reader() ledger.Reader {
+	return mrs.rl
+}
+
+func (mrs *mockReaderSupport) CreateNextBlock(messages []*cb.Envelope) *cb.Block {
+	return ledger.CreateNextBlock(mrs.rl, messages)
+}
+
+func newReconcileTestEnvelope(number uint64) *cb.Envelope {
+	return &cb.Envelope{Payload: []byte(fmt.Sprintf("block %d", number))}
+}
+
+// newReconcileTestLedger returns a ledger with a genesis block followed by a
+// block per metadata
+func newReconcileTestLedger(t *testing.T, metadata ...*ab.KafkaMetadata) ledger.ReadWriter {
+	rl, err := ramledger.New(10).GetOrCreate("reconcile")
+	require.NoError(t, err)
+	require.NoError(t, rl.Append(cb.NewBlock(0, nil)))
+	for _, kafkaMetadata := range metadata {
+		block := ledger.CreateNextBlock(rl, []*cb.Envelope{newReconcileTestEnvelope(rl.Height())})
+		block.Metadata.Metadata[cb.BlockMetadataIndex_ORDERER] = utils.MarshalOrPanic(&cb.Metadata{Value: utils.MarshalOrPanic(kafkaMetadata)})
+		require.NoError(t, rl.Append(block))
+	}
+	return rl
+}
+
+func TestVerifyLastCutBlock(t *testing.T) {
+	newSupport := func(rl ledger.ReadWriter) *mockReaderSupport {
+		return &mockReaderSupport{
+			ConsenterSupport: &mockmultichain.ConsenterSupport{
+				ChainIDVal: "reconcile",
+				HeightVal:  rl.Height(),
+				Blocks:     make(chan *cb.Block, 10),
+			},
+			rl: rl,
+		}
+	}
+
+	t.Run("Verified", func(t *testing.T) {
+		tip := &ab.KafkaMetadata{LastOffsetPersisted: 12, LastCutBlockNumber: 2}
+		support := newSupport(newReconcileTestLedger(t, &ab.KafkaMetadata{LastOffsetPersisted: 10, LastCutBlockNumber: 1}, tip))
+		chain, err := newChain(nil, support, tip.LastOffsetPersisted)
+		require.NoError(t, err)
+
+		chain.verifyLastCutBlock(tip)
+		assert.Equal(t, support, chain.support, "Should not have entered the reconciliation mode")
+		assert.Equal(t, uint64(2), chain.lastCutBlockNumber)
+		assert.Equal(t, int64(12), chain.lastOffsetPersisted)
+	})
+
+	t.Run("Unverifiable", func(t *testing.T) {
+		tip := &ab.KafkaMetadata{LastOffsetPersisted: 12}
+		support := newSupport(newReconcileTestLedger(t, &ab.KafkaMetadata{LastOffsetPersisted: 10}, tip))
+		chain, _ := newChain(nil, support, tip.LastOffsetPersisted)
+
+		chain.verifyLastCutBlock(tip)
+		assert.Equal(t, support, chain.support, "Should not verify the metadata without a block number")
+		chain.verifyLastCutBlock(nil)
+		assert.Equal(t, support, chain.support)
+	})
+
+	t.Run("Mismatch", func(t *testing.T) {
+		// Block 3 was written with the metadata of block 2
+		tip := &ab.KafkaMetadata{LastOffsetPersisted: 14, LastCutBlockNumber: 2}
+		rl := newReconcileTestLedger(t,
+			&ab.KafkaMetadata{LastOffsetPersisted: 10, LastCutBlockNumber: 1},
+			&ab.KafkaMetadata{LastOffsetPersisted: 12, LastCutBlockNumber: 2},
+			tip)
+		support := newSupport(rl)
+		chain, _ := newChain(nil, support, tip.LastOffsetPersisted)
+
+		chain.verifyLastCutBlock(tip)
+		assert.Equal(t, uint64(2), chain.lastCutBlockNumber, "Should re-consume after the last verified block")
+		assert.Equal(t, int64(12), chain.lastOffsetPersisted, "Should re-consume from the last verified offset")
+		require.IsType(t, &reconciler{}, chain.support)
+
+		// Block 3 is cut again but not written
+		block := chain.support.CreateNextBlock([]*cb.Envelope{newReconcileTestEnvelope(3)})
+		assert.Equal(t, uint64(3), block.Header.Number)
+		chain.support.WriteBlock(block, nil, nil)
+		chain.lastCutBlockNumber++
+		assert.Empty(t, support.Blocks, "Should not have written a block already in the ledger")
+
+		// Block 4 is written
+		block = chain.support.CreateNextBlock([]*cb.Envelope{newReconcileTestEnvelope(4)})
+		assert.Equal(t, uint64(4), block.Header.Number)
+		chain.support.WriteBlock(block, nil, nil)
+		assert.Len(t, support.Blocks, 1)
+	})
+
+	t.Run("Diverged", func(t *testing.T) {
+		tip := &ab.KafkaMetadata{LastOffsetPersisted: 12, LastCutBlockNumber: 1}
+		support := newSupport(newReconcileTestLedger(t, &ab.KafkaMetadata{LastOffsetPersisted: 10, LastCutBlockNumber: 1}, tip))
+		chain, _ := newChain(nil, support, tip.LastOffsetPersisted)
+		chain.verifyLastCutBlock(tip)
+
+		block := chain.support.CreateNextBlock([]*cb.Envelope{newReconcileTestEnvelope(5)})
+		assert.Panics(t, func() { chain.support.WriteBlock(block, nil, nil) }, "Should panic when the block cut again differs")
+	})
+
+	t.Run("Unreadable", func(t *testing.T) {
+		// The ledger lost the blocks it had when the reconciliation started
+		support := newSupport(newReconcileTestLedger(t))
+		lastCutBlockNumber := uint64(1)
+		r := &reconciler{ConsenterSupport: support, reader: support.rl, height: 4, lastCutBlockNumber: &lastCutBlockNumber}
+
+		block := r.CreateNextBlock([]*cb.Envelope{newReconcileTestEnvelope(2)})
+		assert.Equal(t, uint64(2), block.Header.Number)
+		defer func() {
+			assert.Equal(t, "[channel: reconcile] Cannot seek block 2 to reconcile it", recover(), "Should panic when the block cannot be read")
+		}()
+		r.WriteBlock(block, nil, nil)
+	})
+
+	t.Run("NoReader", func(t *testing.T) {
+		tip := &ab.KafkaMetadata{LastOffsetPersisted: 12, LastCutBlockNumber: 1}
+		support := newSupport(newReconcileTestLedger(t, &ab.KafkaMetadata{LastOffsetPersisted: 10, LastCutBlockNumber: 1}, tip))
+		chain, _ := newChain(nil, support.ConsenterSupport, tip.LastOffsetPersisted)
+
+		chain.verifyLastCutBlock(tip)
+		assert.Equal(t, uint64(0), chain.lastCutBlockNumber)
+		assert.Equal(t, sarama.OffsetOldest-1, chain.lastOffsetPersisted, "Should re-consume the partition from the beginning")
+	})
+}
@@ -0,0 +1,147 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kafka
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric/common/metrics"
+	localconfig "github.com/hyperledger/fabric/orderer/localconfig"
+	"github.com/hyperledger/fabric/orderer/multichain"
+	cb "github.com/hyperledger/fabric/protos/common"
+	gometrics "github.com/rcrowley/go-metrics"
+)
+
+// percentileUpdatesPerWindow is the number of times the percentile is computed
+// again while the window of latencies is renewed
+const percentileUpdatesPerWindow = 100
+
+// WithLoadShedding returns a consenter whose chains reject a fraction of the
+// broadcasts while the 99th percentile of the time they take to post the
+// messages to the Kafka cluster exceeds the configured objective. Rejecting
+// the broadcasts early, with a hint of when to retry them, keeps the latency
+// of the transactions accepted from growing with the backlog of the producer.
+// The percentile is published as the kafka.<channel>.produce_p99_ms metric and
+// the number of broadcasts rejected as kafka.shed_broadcasts.
+func WithLoadShedding(consenter multichain.Consenter, conf localconfig.LoadShedding) multichain.Consenter {
+	return &loadSheddingConsenter{Consenter: consenter, conf: conf}
+}
+
+type loadSheddingConsenter struct {
+	multichain.Consenter
+	conf localconfig.LoadShedding
+}
+
+func (consenter *loadSheddingConsenter) HandleChain(support multichain.ConsenterSupport, metadata *cb.Metadata) (multichain.Chain, error) {
+	chain, err := consenter.Consenter.HandleChain(support, metadata)
+	if err != nil {
+		return nil, err
+	}
+	return newLoadShedder(chain, support.ChainID(), consenter.conf), nil
+}
+
+// loadShedder decorates a chain with the measure of the latency of Enqueue,
+// which posts the message to the Kafka cluster, and implements
+// multichain.LoadShedder
+type loadShedder struct {
+	multichain.Chain
+	chainID string
+	conf    localconfig.LoadShedding
+	now     func() time.Time
+
+	lock       sync.Mutex
+	latencies  []time.Duration // The ring of the most recent latencies
+	next       int             // The index of the oldest latency, overwritten by the next one
+	sinceP99   int             // The number of latencies recorded since the percentile was computed
+	p99        time.Duration
+	overloaded bool
+	credit     float64 // Accumulates the fraction per broadcast, a broadcast is rejected for each unit
+
+	p99Gauge       gometrics.Gauge
+	shedBroadcasts gometrics.Counter
+}
+
+func newLoadShedder(chain multichain.Chain, chainID string, conf localconfig.LoadShedding) *loadShedder {
+	return &loadShedder{
+		Chain:          chain,
+		chainID:        chainID,
+		conf:           conf,
+		now:            time.Now,
+		latencies:      make([]time.Duration, 0, conf.Window),
+		p99Gauge:       gometrics.GetOrRegisterGauge("kafka."+chainID+".produce_p99_ms", metrics.Registry),
+		shedBroadcasts: gometrics.GetOrRegisterCounter("kafka.shed_broadcasts", metrics.Registry),
+	}
+}
+
+// Enqueue posts the message and records the time it took. The messages which
+// failed to be posted are recorded too, as their latency is seen by the clients
+func (s *loadShedder) Enqueue(env *cb.Envelope, traceID string) bool {
+	start := s.now()
+	ok := s.Chain.Enqueue(env, traceID)
+	s.record(s.now().Sub(start))
+	return ok
+}
+
+// Shed returns true for the configured fraction of the calls while the chain
+// is overloaded. The calls rejected are spread evenly between those accepted
+func (s *loadShedder) Shed() (time.Duration, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if !s.overloaded {
+		return 0, false
+	}
+	s.credit += s.conf.Fraction
+	if s.credit < 1 {
+		return 0, false
+	}
+	s.credit--
+	s.shedBroadcasts.Inc(1)
+	return s.conf.RetryAfter, true
+}
+
+func (s *loadShedder) record(latency time.Duration) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if len(s.latencies) < s.conf.Window {
+		s.latencies = append(s.latencies, latency)
+	} else {
+		s.latencies[s.next] = latency
+		s.next = (s.next + 1) % s.conf.Window
+	}
+
+	// Sorting the window for every message would cost more than posting it
+	s.sinceP99++
+	if s.sinceP99 < s.conf.Window/percentileUpdatesPerWindow {
+		return
+	}
+	s.sinceP99 = 0
+	sorted := make(durations, len(s.latencies))
+	copy(sorted, s.latencies)
+	sort.Sort(sorted)
+	s.p99 = sorted[(len(sorted)*99-1)/100]
+	s.p99Gauge.Update(int64(s.p99 / time.Millisecond))
+
+	switch {
+	case !s.overloaded && s.p99 > s.conf.SLO:
+		s.overloaded = true
+		logger.Warningf("[channel: %s] The 99th percentile of the produce latency is %s, above the objective of %s, rejecting %v of the broadcasts",
+			s.chainID, s.p99, s.conf.SLO, s.conf.Fraction)
+	case s.overloaded && s.p99 <= s.conf.SLO:
+		s.overloaded = false
+		s.credit = 0
+		logger.Infof("[channel: %s] The 99th percentile of the produce latency is %s, back within the objective of %s, accepting all the broadcasts",
+			s.chainID, s.p99, s.conf.SLO)
+	}
+}
+
+type durations []time.Duration
+
+func (d durations) Len() int           { return len(d) }
+func (d durations) Less(i, j int) bool { return d[i] < d[j] }
+func (d durations) Swap(i, j int)      { d[i], d[j] = d[j], d[i] }
@@ -0,0 +1,87 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kafka
+
+import (
+	"testing"
+	"time"
+
+	localconfig "github.com/hyperledger/fabric/orderer/localconfig"
+	mockmultichain "github.com/hyperledger/fabric/orderer/mocks/multichain"
+	"github.com/hyperledger/fabric/orderer/multichain"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadShedding(t *testing.T) {
+	mockChain := &mockIdleChain{}
+	support := &mockmultichain.ConsenterSupport{ChainIDVal: "busychannel"}
+	consenter := WithLoadShedding(&mockIdleConsenter{chain: mockChain}, localconfig.LoadShedding{
+		Enabled:    true,
+		SLO:        100 * time.Millisecond,
+		Window:     200,
+		Fraction:   0.25,
+		RetryAfter: 3 * time.Second,
+	})
+	chain, err := consenter.HandleChain(support, &cb.Metadata{})
+	require.NoError(t, err)
+	s := chain.(*loadShedder)
+	_, ok := chain.(multichain.LoadShedder)
+	assert.True(t, ok, "Should be a load shedder")
+
+	now := time.Date(2017, 6, 1, 12, 0, 0, 0, time.UTC)
+	latency := 10 * time.Millisecond
+	calls := 0
+	s.now = func() time.Time {
+		// Called before and after each post
+		calls++
+		if calls%2 == 0 {
+			now = now.Add(latency)
+		}
+		return now
+	}
+	post := func(n int) {
+		for i := 0; i < n; i++ {
+			assert.True(t, s.Enqueue(&cb.Envelope{}, ""))
+		}
+	}
+	shed := func(n int) int {
+		count := 0
+		for i := 0; i < n; i++ {
+			if retryAfter, ok := s.Shed(); ok {
+				assert.Equal(t, 3*time.Second, retryAfter)
+				count++
+			}
+		}
+		return count
+	}
+
+	post(200)
+	assert.Equal(t, 10*time.Millisecond, s.p99)
+	assert.Equal(t, int64(10), s.p99Gauge.Value())
+	assert.Equal(t, 0, shed(100), "Should not shed within the objective")
+
+	// One slow post in a hundred is within the objective
+	latency = time.Second
+	post(2)
+	latency = 10 * time.Millisecond
+	post(198)
+	assert.Equal(t, 0, shed(100), "Should not shed within the objective")
+
+	latency = time.Second
+	post(4)
+	assert.Equal(t, time.Second, s.p99)
+	shedBroadcasts := s.shedBroadcasts.Count()
+	assert.Equal(t, 25, shed(100), "Should have shed the configured fraction")
+	assert.Equal(t, shedBroadcasts+25, s.shedBroadcasts.Count())
+
+	latency = 10 * time.Millisecond
+	post(200)
+	assert.Equal(t, 10*time.Millisecond, s.p99)
+	assert.Equal(t, 0, shed(100), "Should not shed once back within the objective")
+}
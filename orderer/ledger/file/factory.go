@@ -19,6 +19,7 @@ package fileledger
 import (
 	"sync"
 
+	"github.com/hyperledger/fabric/common/crypto/keyring"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage/fsblkstorage"
 	"github.com/hyperledger/fabric/orderer/ledger"
@@ -28,6 +29,8 @@ type fileLedgerFactory struct {
 	blkstorageProvider blkstorage.BlockStoreProvider
 	ledgers            map[string]ledger.ReadWriter
 	mutex              sync.Mutex
+	txIndex            bool
+	readAhead          uint64
 }
 
 // GetOrCreate gets an existing ledger (if it exists) or creates it if it does not
@@ -46,7 +49,11 @@ func (flf *fileLedgerFactory) GetOrCreate(chainID string) (ledger.ReadWriter, er
 	if err != nil {
 		return nil, err
 	}
-	ledger = &fileLedger{blockStore: blockStore, signal: make(chan struct{})}
+	fl := &fileLedger{blockStore: blockStore, signal: make(chan struct{}), readAhead: flf.readAhead}
+	ledger = fl
+	if flf.txIndex {
+		ledger = &txIndexedFileLedger{fileLedger: fl}
+	}
 	flf.ledgers[key] = ledger
 	return ledger, nil
 }
@@ -65,14 +72,61 @@ func (flf *fileLedgerFactory) Close() {
 	flf.blkstorageProvider.Close()
 }
 
+// Options tunes the ledgers of a factory
+type Options struct {
+	// TxIndex also indexes the blocks by the IDs of their transactions, so
+	// that the ledgers implement ledger.TxIndex. Only the blocks written once
+	// the index is enabled are indexed
+	TxIndex bool
+
+	// ReadAhead is the number of blocks the iterators read ahead at once when
+	// replaying the ledger, so that reading the disk overlaps sending the
+	// blocks. Up to twice as many blocks are held in memory. 0 disables it
+	ReadAhead uint64
+
+	// Keyring encrypts the blocks written to the files, and decrypts those
+	// read from them. The blocks written before are still read in plaintext
+	Keyring *keyring.Keyring
+
+	// IndexShardSize splits the index of the ledgers into shards of that
+	// many blocks, each a leveldb of its own, for very long chains. 0 keeps
+	// a single index. Changing it rebuilds the index from the blocks
+	IndexShardSize uint64
+
+	// MmapReads reads the blocks of the complete block files through memory
+	// maps
+	MmapReads bool
+}
+
 // New creates a new ledger factory
 func New(directory string) ledger.Factory {
+	return NewWithOptions(directory, Options{})
+}
+
+// NewWithTxIndex creates a new ledger factory whose ledgers also index their
+// blocks by the IDs of their transactions, and so implement ledger.TxIndex.
+// Only the blocks written once the index is enabled are indexed
+func NewWithTxIndex(directory string) ledger.Factory {
+	return NewWithOptions(directory, Options{TxIndex: true})
+}
+
+// NewWithOptions creates a new ledger factory whose ledgers are tuned by opts
+func NewWithOptions(directory string, opts Options) ledger.Factory {
+	attrsToIndex := []blkstorage.IndexableAttr{blkstorage.IndexableAttrBlockNum}
+	if opts.TxIndex {
+		attrsToIndex = append(attrsToIndex, blkstorage.IndexableAttrBlockTxID)
+	}
+	conf := fsblkstorage.NewConfWithScaleConf(directory, -1, nil, opts.Keyring, &fsblkstorage.ScaleConf{
+		IndexShardSize: opts.IndexShardSize,
+		MmapReads:      opts.MmapReads,
+	})
 	return &fileLedgerFactory{
 		blkstorageProvider: fsblkstorage.NewProvider(
-			fsblkstorage.NewConf(directory, -1),
-			&blkstorage.IndexConfig{
-				AttrsToIndex: []blkstorage.IndexableAttr{blkstorage.IndexableAttrBlockNum}},
+			conf,
+			&blkstorage.IndexConfig{AttrsToIndex: attrsToIndex},
 		),
-		ledgers: make(map[string]ledger.ReadWriter),
+		ledgers:   make(map[string]ledger.ReadWriter),
+		txIndex:   opts.TxIndex,
+		readAhead: opts.ReadAhead,
 	}
 }
@@ -17,10 +17,13 @@ limitations under the License.
 package fileledger
 
 import (
+	"fmt"
+
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	ledger "github.com/hyperledger/fabric/orderer/ledger"
 	cb "github.com/hyperledger/fabric/protos/common"
 	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/op/go-logging"
 )
 
@@ -35,29 +38,95 @@ func init() {
 type fileLedger struct {
 	blockStore blkstorage.BlockStore
 	signal     chan struct{}
+	readAhead  uint64
 }
 
 type fileLedgerIterator struct {
 	ledger      *fileLedger
 	blockNumber uint64
+	// batches are the blocks being read ahead, in order, starting with
+	// blockNumber
+	batches []*blockBatch
+}
+
+// blockBatch is a batch of consecutive blocks read ahead by a routine of its
+// own. As its channel can hold all its blocks, the routine never blocks on an
+// iterator which is no longer used
+type blockBatch struct {
+	end    uint64
+	blocks chan *cb.Block
 }
 
 // Next blocks until there is a new block available, or returns an error if the
 // next block is no longer retrievable
 func (i *fileLedgerIterator) Next() (*cb.Block, cb.Status) {
 	for {
-		if i.blockNumber < i.ledger.Height() {
-			block, err := i.ledger.blockStore.RetrieveBlockByNumber(i.blockNumber)
-			if err != nil {
+		height := i.ledger.Height()
+		if i.blockNumber < height {
+			if i.ledger.readAhead == 0 || (len(i.batches) == 0 && i.blockNumber+1 == height) {
+				// Nothing to read ahead
+				block, err := i.ledger.blockStore.RetrieveBlockByNumber(i.blockNumber)
+				if err != nil {
+					return nil, cb.Status_SERVICE_UNAVAILABLE
+				}
+				i.blockNumber++
+				return block, cb.Status_SUCCESS
+			}
+
+			i.readAhead(height)
+			batch := i.batches[0]
+			block, ok := <-batch.blocks
+			if !ok {
+				// The batch failed to read the block, and the next ones are
+				// not worth more
+				i.batches = nil
 				return nil, cb.Status_SERVICE_UNAVAILABLE
 			}
 			i.blockNumber++
+			if i.blockNumber == batch.end {
+				i.batches = i.batches[1:]
+			}
 			return block, cb.Status_SUCCESS
 		}
 		<-i.ledger.signal
 	}
 }
 
+// readAhead keeps two batches of blocks in flight, so that the next batch is
+// read from the disk while the blocks of the current one are sent
+func (i *fileLedgerIterator) readAhead(height uint64) {
+	end := i.blockNumber
+	if len(i.batches) > 0 {
+		end = i.batches[len(i.batches)-1].end
+	}
+	for len(i.batches) < 2 && end < height {
+		start := end
+		end = start + i.ledger.readAhead
+		if end > height {
+			end = height
+		}
+		i.batches = append(i.batches, i.ledger.readBatch(start, end))
+	}
+}
+
+// readBatch reads the blocks from start to end, excluded, in the background.
+// The channel of the batch is closed early if a block could not be read
+func (fl *fileLedger) readBatch(start, end uint64) *blockBatch {
+	batch := &blockBatch{end: end, blocks: make(chan *cb.Block, end-start)}
+	go func() {
+		defer close(batch.blocks)
+		for number := start; number < end; number++ {
+			block, err := fl.blockStore.RetrieveBlockByNumber(number)
+			if err != nil {
+				logger.Warningf("Error reading ahead block %d: %s", number, err)
+				return
+			}
+			batch.blocks <- block
+		}
+	}()
+	return batch
+}
+
 // ReadyChan supplies a channel which will block until Next will not block
 func (i *fileLedgerIterator) ReadyChan() <-chan struct{} {
 	signal := i.ledger.signal
@@ -109,3 +178,40 @@ func (fl *fileLedger) Append(block *cb.Block) error {
 	}
 	return err
 }
+
+// txIndexedFileLedger is a file ledger whose block store indexes the blocks by
+// the IDs of their transactions
+type txIndexedFileLedger struct {
+	*fileLedger
+}
+
+// LocateTx returns the position in the ledger of the most recently written
+// transaction with the given ID, or nil if there is no such transaction
+func (fl *txIndexedFileLedger) LocateTx(txID string) (*ab.CommitNotification, error) {
+	block, err := fl.blockStore.RetrieveBlockByTxID(txID)
+	if err == blkstorage.ErrNotFoundInIndex {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	// The index only locates the block, so look for the transaction in its data
+	for i := len(block.Data.Data) - 1; i >= 0; i-- {
+		env, err := utils.ExtractEnvelope(block, i)
+		if err != nil {
+			continue
+		}
+		payload, err := utils.UnmarshalPayload(env.Payload)
+		if err != nil || payload.Header == nil {
+			continue
+		}
+		chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+		if err != nil {
+			continue
+		}
+		if chdr.TxId == txID {
+			return &ab.CommitNotification{TxId: txID, BlockNumber: block.Header.Number, TxIndex: uint64(i)}, nil
+		}
+	}
+	return nil, fmt.Errorf("transaction %s is not in block %d as indexed", txID, block.Header.Number)
+}
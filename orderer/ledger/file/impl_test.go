@@ -28,6 +28,7 @@ import (
 	cb "github.com/hyperledger/fabric/protos/common"
 	ab "github.com/hyperledger/fabric/protos/orderer"
 	"github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
 	logging "github.com/op/go-logging"
 	"github.com/stretchr/testify/assert"
 )
@@ -307,3 +308,78 @@ func TestBlockstoreError(t *testing.T) {
 		assert.Equal(t, cb.Status_SERVICE_UNAVAILABLE, status, "Expected service unavailable error")
 	}
 }
+
+func TestReadAhead(t *testing.T) {
+	tev, fl := initialize(t)
+	defer tev.tearDown()
+	fl.readAhead = 3
+	for i := 0; i < 9; i++ {
+		fl.Append(ledger.CreateNextBlock(fl, []*cb.Envelope{&cb.Envelope{Payload: []byte("My Data")}}))
+	}
+
+	it, _ := fl.Iterator(&ab.SeekPosition{Type: &ab.SeekPosition_Specified{Specified: &ab.SeekSpecified{Number: 1}}})
+	for number := uint64(1); number < 10; number++ {
+		block, status := it.Next()
+		assert.Equal(t, cb.Status_SUCCESS, status, "Expected to successfully read block %d", number)
+		assert.Equal(t, number, block.Header.Number, "Expected to read the blocks in order")
+		if number == 1 {
+			assert.Len(t, it.(*fileLedgerIterator).batches, 2, "Expected the next batch to be read ahead")
+		}
+	}
+	assert.Empty(t, it.(*fileLedgerIterator).batches)
+
+	// The blocks appended afterwards are read as they come
+	fl.Append(ledger.CreateNextBlock(fl, []*cb.Envelope{&cb.Envelope{Payload: []byte("My Data")}}))
+	block, status := it.Next()
+	assert.Equal(t, cb.Status_SUCCESS, status)
+	assert.Equal(t, uint64(10), block.Header.Number)
+
+	// A block which cannot be read fails the iterator
+	fl = &fileLedger{
+		blockStore: &mockBlockStore{
+			blockchainInfo:             &cb.BlockchainInfo{Height: uint64(5)},
+			retrieveBlockByNumberError: fmt.Errorf("Error retrieving block by number"),
+		},
+		signal:    make(chan struct{}),
+		readAhead: 2,
+	}
+	it, _ = fl.Iterator(&ab.SeekPosition{Type: &ab.SeekPosition_Oldest{}})
+	_, status = it.Next()
+	assert.Equal(t, cb.Status_SERVICE_UNAVAILABLE, status, "Expected service unavailable error")
+	assert.Empty(t, it.(*fileLedgerIterator).batches, "Expected the batches read ahead to be dropped")
+}
+
+func makeTx(txID string) *cb.Envelope {
+	return &cb.Envelope{Payload: utils.MarshalOrPanic(&cb.Payload{
+		Header: &cb.Header{ChannelHeader: utils.MarshalOrPanic(&cb.ChannelHeader{ChannelId: provisional.TestChainID, TxId: txID})},
+	})}
+}
+
+func TestLocateTx(t *testing.T) {
+	name, err := ioutil.TempDir("", "hyperledger_fabric")
+	assert.NoError(t, err, "Error creating temp dir: %s", err)
+	defer os.RemoveAll(name)
+	flf := NewWithTxIndex(name)
+	defer flf.Close()
+
+	rw, err := flf.GetOrCreate(provisional.TestChainID)
+	assert.NoError(t, err, "Error GetOrCreate chain")
+	fl, ok := rw.(ledger.TxIndex)
+	assert.True(t, ok, "Should index the transactions")
+
+	rw.Append(genesisBlock)
+	rw.Append(ledger.CreateNextBlock(rw, []*cb.Envelope{makeTx("tx1"), makeTx("tx2")}))
+
+	position, err := fl.LocateTx("tx2")
+	assert.NoError(t, err)
+	assert.Equal(t, &ab.CommitNotification{TxId: "tx2", BlockNumber: 1, TxIndex: 1}, position)
+
+	position, err = fl.LocateTx("tx3")
+	assert.NoError(t, err)
+	assert.Nil(t, position, "Should not locate an unknown transaction")
+
+	tev, unindexed := initialize(t)
+	defer tev.tearDown()
+	_, ok = interface{}(unindexed).(ledger.TxIndex)
+	assert.False(t, ok, "Should not index the transactions by default")
+}
@@ -19,10 +19,14 @@ package ledger_test
 import (
 	"io/ioutil"
 	"os"
+	"testing"
+	"time"
 
 	"github.com/hyperledger/fabric/common/configtx/tool/provisional"
 	. "github.com/hyperledger/fabric/orderer/ledger"
 	fileledger "github.com/hyperledger/fabric/orderer/ledger/file"
+	ramledger "github.com/hyperledger/fabric/orderer/ledger/ram"
+	"github.com/stretchr/testify/assert"
 )
 
 func init() {
@@ -75,3 +79,19 @@ func (env *fileLedgerTestFactory) New() (Factory, ReadWriter) {
 	}
 	return flf, fl
 }
+
+func TestGetTxIndex(t *testing.T) {
+	location, err := ioutil.TempDir("", "hyperledger")
+	assert.NoError(t, err)
+	defer os.RemoveAll(location)
+
+	flf := WithWriteTimeout(fileledger.NewWithTxIndex(location), time.Second)
+	defer flf.Close()
+	fl, err := flf.GetOrCreate(provisional.TestChainID)
+	assert.NoError(t, err)
+	assert.NotNil(t, GetTxIndex(fl), "Should have found the transaction index behind the wrapper")
+
+	rl, err := ramledger.New(10).GetOrCreate(provisional.TestChainID)
+	assert.NoError(t, err)
+	assert.Nil(t, GetTxIndex(rl), "The RAM ledger does not index transactions")
+}
@@ -63,3 +63,10 @@ type ReadWriter interface {
 	Reader
 	Writer
 }
+
+// TxIndex is implemented by the ledgers which index their transactions by ID
+type TxIndex interface {
+	// LocateTx returns the position in the ledger of the most recently written
+	// transaction with the given ID, or nil if there is no such transaction
+	LocateTx(txID string) (*ab.CommitNotification, error)
+}
@@ -0,0 +1,41 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledger
+
+import (
+	"github.com/hyperledger/fabric/common/diskwatch"
+	cb "github.com/hyperledger/fabric/protos/common"
+)
+
+// WithDiskWatch wraps a Factory so that the Append calls on its ledgers block while the watcher has quiesced
+// the orderer, rather than failing halfway through a block on a full disk
+func WithDiskWatch(factory Factory, watcher *diskwatch.Watcher) Factory {
+	return &quiescingFactory{Factory: factory, watcher: watcher}
+}
+
+type quiescingFactory struct {
+	Factory
+	watcher *diskwatch.Watcher
+}
+
+func (qf *quiescingFactory) GetOrCreate(chainID string) (ReadWriter, error) {
+	rw, err := qf.Factory.GetOrCreate(chainID)
+	if err != nil {
+		return nil, err
+	}
+	return &quiescingReadWriter{ReadWriter: rw, watcher: qf.watcher}, nil
+}
+
+type quiescingReadWriter struct {
+	ReadWriter
+	watcher *diskwatch.Watcher
+}
+
+func (qrw *quiescingReadWriter) Append(block *cb.Block) error {
+	qrw.watcher.WaitWritable()
+	return qrw.ReadWriter.Append(block)
+}
@@ -0,0 +1,53 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledger_test
+
+import (
+	"math"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/common/configtx/tool/provisional"
+	"github.com/hyperledger/fabric/common/diskwatch"
+	. "github.com/hyperledger/fabric/orderer/ledger"
+	ramledger "github.com/hyperledger/fabric/orderer/ledger/ram"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiskWatch(t *testing.T) {
+	lf := WithDiskWatch(ramledger.New(10), nil)
+	rl, err := lf.GetOrCreate(provisional.TestChainID)
+	assert.NoError(t, err)
+	assert.NoError(t, rl.Append(cb.NewBlock(0, nil)), "Should not block without a watcher")
+
+	// No file system has that much free space
+	watcher, err := diskwatch.New(diskwatch.Conf{
+		Paths:            []string{os.TempDir()},
+		Interval:         time.Hour,
+		WarningFreeSpace: math.MaxUint64,
+		QuiesceFreeSpace: math.MaxUint64,
+	})
+	assert.NoError(t, err)
+	watcher.Check()
+	assert.True(t, watcher.Quiesced())
+
+	lf = WithDiskWatch(ramledger.New(10), watcher)
+	rl, err = lf.GetOrCreate(provisional.TestChainID)
+	assert.NoError(t, err)
+	appended := make(chan error, 1)
+	go func() {
+		appended <- rl.Append(cb.NewBlock(0, nil))
+	}()
+	select {
+	case <-appended:
+		t.Fatal("Should have blocked the append while quiesced")
+	case <-time.After(50 * time.Millisecond):
+	}
+	assert.Equal(t, uint64(0), rl.Height())
+}
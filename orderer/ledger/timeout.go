@@ -0,0 +1,69 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledger
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	cb "github.com/hyperledger/fabric/protos/common"
+)
+
+// WithWriteTimeout wraps a Factory so that the Append calls on its ledgers fail once they exceed timeout.
+// As the timed out write may still complete in the background, a ledger whose write timed out refuses any
+// further Append
+func WithWriteTimeout(factory Factory, timeout time.Duration) Factory {
+	return &timeoutFactory{Factory: factory, timeout: timeout}
+}
+
+type timeoutFactory struct {
+	Factory
+	timeout time.Duration
+}
+
+func (tf *timeoutFactory) GetOrCreate(chainID string) (ReadWriter, error) {
+	rw, err := tf.Factory.GetOrCreate(chainID)
+	if err != nil {
+		return nil, err
+	}
+	return &timeoutReadWriter{ReadWriter: rw, chainID: chainID, timeout: tf.timeout}, nil
+}
+
+type timeoutReadWriter struct {
+	ReadWriter
+	chainID string
+	timeout time.Duration
+
+	lock   sync.Mutex
+	wedged bool
+}
+
+func (trw *timeoutReadWriter) Append(block *cb.Block) error {
+	trw.lock.Lock()
+	defer trw.lock.Unlock()
+
+	if trw.wedged {
+		return fmt.Errorf("ledger of chain %s refuses writes after a previous write timed out", trw.chainID)
+	}
+
+	result := make(chan error, 1)
+	go func() {
+		result <- trw.ReadWriter.Append(block)
+	}()
+
+	timer := time.NewTimer(trw.timeout)
+	defer timer.Stop()
+	select {
+	case err := <-result:
+		return err
+	case <-timer.C:
+		trw.wedged = true
+		return fmt.Errorf("append of block %d to the ledger of chain %s did not complete within %s",
+			block.Header.Number, trw.chainID, trw.timeout)
+	}
+}
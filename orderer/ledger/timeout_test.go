@@ -0,0 +1,58 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledger_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/common/configtx/tool/provisional"
+	. "github.com/hyperledger/fabric/orderer/ledger"
+	ramledger "github.com/hyperledger/fabric/orderer/ledger/ram"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/stretchr/testify/assert"
+)
+
+type blockingFactory struct {
+	Factory
+	release chan struct{}
+}
+
+func (bf *blockingFactory) GetOrCreate(chainID string) (ReadWriter, error) {
+	rw, err := bf.Factory.GetOrCreate(chainID)
+	return &blockingReadWriter{ReadWriter: rw, release: bf.release}, err
+}
+
+type blockingReadWriter struct {
+	ReadWriter
+	release chan struct{}
+}
+
+func (brw *blockingReadWriter) Append(block *cb.Block) error {
+	<-brw.release
+	return brw.ReadWriter.Append(block)
+}
+
+func TestWriteTimeout(t *testing.T) {
+	release := make(chan struct{})
+	lf := WithWriteTimeout(&blockingFactory{Factory: ramledger.New(10), release: release}, 50*time.Millisecond)
+	rl, err := lf.GetOrCreate(provisional.TestChainID)
+	assert.NoError(t, err)
+
+	genesis := cb.NewBlock(0, nil)
+	close(release)
+	assert.NoError(t, rl.Append(genesis), "Should have appended within the timeout")
+	assert.Equal(t, uint64(1), rl.Height())
+
+	release = make(chan struct{})
+	lf = WithWriteTimeout(&blockingFactory{Factory: ramledger.New(10), release: release}, 50*time.Millisecond)
+	rl, err = lf.GetOrCreate(provisional.TestChainID)
+	assert.NoError(t, err)
+	assert.Error(t, rl.Append(genesis), "Should have timed out while the write is stalled")
+	close(release)
+	assert.Error(t, rl.Append(CreateNextBlock(rl, nil)), "Should refuse writes after a timeout")
+}
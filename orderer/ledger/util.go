@@ -103,3 +103,20 @@ func GetBlock(rl Reader, index uint64) *cb.Block {
 		return nil
 	}
 }
+
+// GetTxIndex returns the transaction index of a ledger, looking through the
+// wrappers of this package, or nil if the ledger does not index its transactions
+func GetTxIndex(rl Reader) TxIndex {
+	for {
+		switch l := rl.(type) {
+		case TxIndex:
+			return l
+		case *quiescingReadWriter:
+			rl = l.ReadWriter
+		case *timeoutReadWriter:
+			rl = l.ReadWriter
+		default:
+			return nil
+		}
+	}
+}
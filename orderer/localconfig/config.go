@@ -64,23 +64,38 @@ type TopLevel struct {
 	FileLedger FileLedger
 	RAMLedger  RAMLedger
 	Kafka      Kafka
+	Consensus  Consensus
 }
 
 // General contains config which should be common among all orderer types.
 type General struct {
-	LedgerType     string
-	ListenAddress  string
-	ListenPort     uint16
-	TLS            TLS
-	GenesisMethod  string
-	GenesisProfile string
-	GenesisFile    string
-	Profile        Profile
-	LogLevel       string
-	LogFormat      string
-	LocalMSPDir    string
-	LocalMSPID     string
-	BCCSP          *bccsp.FactoryOpts
+	LedgerType            string
+	LedgerWriteTimeout    time.Duration
+	DiskWatch             DiskWatch
+	ListenAddress         string
+	ListenPort            uint16
+	TLS                   TLS
+	GenesisMethod         string
+	GenesisProfile        string
+	GenesisFile           string
+	Profile               Profile
+	Tracing               Tracing
+	Gateway               Gateway
+	BlockServer           BlockServer
+	MSPCache              MSPCache
+	FIPS                  FIPS
+	MaxChannels           uint64
+	ChannelCreationPolicy string
+	ClockSkew             ClockSkew
+	HeaderGuard           HeaderGuard
+	Usage                 Usage
+	BlockHooks            BlockHooks
+	LogLevel              string
+	LogFormat             string
+	LogRedaction          LogRedaction
+	LocalMSPDir           string
+	LocalMSPID            string
+	BCCSP                 *bccsp.FactoryOpts
 }
 
 // TLS contains config for TLS connections.
@@ -93,16 +108,127 @@ type TLS struct {
 	ClientRootCAs     []string
 }
 
+// DiskWatch contains configuration for the monitoring of the free space of
+// the ledger directory.
+type DiskWatch struct {
+	Enabled          bool
+	Interval         time.Duration
+	WarningFreeSpace uint64
+	QuiesceFreeSpace uint64
+}
+
 // Profile contains configuration for Go pprof profiling.
 type Profile struct {
 	Enabled bool
 	Address string
 }
 
+// Tracing contains configuration for the trace IDs of the broadcasted envelopes.
+type Tracing struct {
+	AssignIDs bool
+}
+
+// Gateway contains configuration for the HTTP and WebSocket gateway to the
+// Broadcast and Deliver services.
+type Gateway struct {
+	Enabled               bool
+	Address               string
+	ExplainPolicies       bool
+	ReportUsage           bool
+	ValidateConfigUpdates bool
+	PauseChains           bool
+}
+
+// BlockServer contains configuration for the HTTP server of the blocks of the
+// ledgers, for the catch-up of the peers through a CDN. TokensFile holds the
+// tokens of the clients, one per line.
+type BlockServer struct {
+	Enabled     bool
+	Address     string
+	SegmentSize uint64
+	TokensFile  string
+}
+
+// LogRedaction contains configuration for the masking of the sensitive data
+// written in the logs.
+type LogRedaction struct {
+	Payloads     bool
+	Types        []string
+	Certificates bool
+	Patterns     []string
+}
+
+// MSPCache contains configuration for the caches of the identities deserialized
+// and validated by the MSPs.
+type MSPCache struct {
+	Enabled bool
+	Size    int
+}
+
+// FIPS contains configuration for restricting the cryptography of the orderer
+// to FIPS approved algorithms.
+type FIPS struct {
+	Enabled bool
+}
+
+// ClockSkew contains configuration for the rejection of the broadcasted
+// messages whose timestamp is too far from the clock of the orderer.
+type ClockSkew struct {
+	Enabled bool
+	Window  time.Duration
+}
+
+// HeaderGuard contains configuration for the checks of the channel headers of
+// the broadcasted messages against the channel and the path they are submitted
+// to. AllowedTypes are the names of the header types, or the numbers of the
+// custom types, admitted at broadcast.
+type HeaderGuard struct {
+	Enabled      bool
+	AllowedTypes []string
+}
+
+// Usage contains configuration for the accounting of the usage of the
+// channels and consortiums, and for the quotas enforced at broadcast.
+type Usage struct {
+	Period time.Duration
+	Quotas UsageQuotas
+}
+
+// BlockHooks contains configuration for the hooks run once the blocks are
+// written to the ledgers, the commands being keyed by the name of their hook.
+type BlockHooks struct {
+	Enabled   bool
+	QueueSize int
+	Commands  map[string]string
+}
+
+// UsageQuotas contains the quotas of usage per period of the channels and
+// consortiums. The quotas set for a channel or a consortium by name override
+// the default ones.
+type UsageQuotas struct {
+	Enabled     bool
+	Channel     Quota
+	Consortium  Quota
+	Channels    map[string]Quota
+	Consortiums map[string]Quota
+}
+
+// Quota contains the maximum number of transactions and bytes admitted per
+// period, 0 meaning no maximum.
+type Quota struct {
+	Transactions uint64
+	Bytes        uint64
+}
+
 // FileLedger contains configuration for the file-based ledger.
 type FileLedger struct {
-	Location string
-	Prefix   string
+	Location       string
+	Prefix         string
+	TxIndex        bool
+	ReadAhead      uint
+	Keyring        string
+	IndexShardSize uint64
+	MmapReads      bool
 }
 
 // RAMLedger contains configuration for the RAM ledger.
@@ -110,12 +236,62 @@ type RAMLedger struct {
 	HistorySize uint
 }
 
+// Consensus contains configuration for the consenters registered by plugins,
+// the sections of their consensus types being keyed by type.
+type Consensus struct {
+	Plugins []string
+	Types   map[string]interface{}
+}
+
 // Kafka contains configuration for the Kafka-based orderer.
 type Kafka struct {
-	Retry   Retry
-	Verbose bool
-	Version sarama.KafkaVersion // TODO Move this to global config
-	TLS     TLS
+	Retry              Retry
+	Verbose            bool
+	Version            sarama.KafkaVersion // TODO Move this to global config
+	TLS                TLS
+	SASL               SASL
+	Chaos              Chaos
+	IdleChannels       IdleChannels
+	LoadShedding       LoadShedding
+	BatchTimeoutJitter float64
+}
+
+// SASL contains configuration for the SASL/PLAIN authentication of the
+// orderer to the Kafka brokers.
+type SASL struct {
+	Enabled  bool
+	Username string
+	Password string
+}
+
+// IdleChannels contains configuration for the detection of the channels which
+// have not cut a block for a while, whose topics may retain data on the Kafka
+// cluster for no purpose.
+type IdleChannels struct {
+	Enabled bool
+	Period  time.Duration
+	Command string
+}
+
+// LoadShedding contains configuration for the rejection of a fraction of the
+// broadcasts of the channels whose produce latency exceeds its objective.
+type LoadShedding struct {
+	Enabled    bool
+	SLO        time.Duration
+	Window     int
+	Fraction   float64
+	RetryAfter time.Duration
+}
+
+// Chaos contains the failures to inject in the Kafka clients of the orderer,
+// to test the Kafka-based consenter against a staging cluster.
+type Chaos struct {
+	Enabled        bool
+	LeaderFailover float64
+	Duplicate      float64
+	OffsetReset    float64
+	SlowBroker     time.Duration
+	Seed           int64
 }
 
 // Retry contains configuration related to retries and timeouts when the
@@ -169,10 +345,49 @@ var defaults = TopLevel{
 		GenesisMethod:  "provisional",
 		GenesisProfile: "SampleSingleMSPSolo",
 		GenesisFile:    "genesisblock",
+		DiskWatch: DiskWatch{
+			Enabled:  false,
+			Interval: 10 * time.Second,
+		},
 		Profile: Profile{
 			Enabled: false,
 			Address: "0.0.0.0:6060",
 		},
+		Tracing: Tracing{
+			AssignIDs: false,
+		},
+		Gateway: Gateway{
+			Enabled:               false,
+			Address:               "0.0.0.0:7080",
+			ExplainPolicies:       false,
+			ReportUsage:           false,
+			ValidateConfigUpdates: false,
+			PauseChains:           false,
+		},
+		BlockServer: BlockServer{
+			Enabled:     false,
+			Address:     "0.0.0.0:7090",
+			SegmentSize: 100,
+		},
+		MSPCache: MSPCache{
+			Enabled: true,
+			Size:    1000,
+		},
+		ClockSkew: ClockSkew{
+			Enabled: false,
+			Window:  15 * time.Minute,
+		},
+		HeaderGuard: HeaderGuard{
+			Enabled:      false,
+			AllowedTypes: []string{"ENDORSER_TRANSACTION", "CONFIG_UPDATE"},
+		},
+		Usage: Usage{
+			Period: 24 * time.Hour,
+		},
+		BlockHooks: BlockHooks{
+			Enabled:   false,
+			QueueSize: 1000,
+		},
 		LogLevel:    "INFO",
 		LogFormat:   "%{color}%{time:2006-01-02 15:04:05.000 MST} [%{module}] %{shortfunc} -> %{level:.4s} %{id:03x}%{color:reset} %{message}",
 		LocalMSPDir: "msp",
@@ -214,6 +429,20 @@ var defaults = TopLevel{
 		TLS: TLS{
 			Enabled: false,
 		},
+		SASL: SASL{
+			Enabled: false,
+		},
+		IdleChannels: IdleChannels{
+			Enabled: false,
+			Period:  7 * 24 * time.Hour,
+		},
+		LoadShedding: LoadShedding{
+			Enabled:    false,
+			SLO:        time.Second,
+			Window:     1000,
+			Fraction:   0.5,
+			RetryAfter: time.Second,
+		},
 	},
 }
 
@@ -253,6 +482,9 @@ func (c *TopLevel) completeInitialization(configDir string) {
 		cf.TranslatePathInPlace(configDir, &c.General.TLS.Certificate)
 		cf.TranslatePathInPlace(configDir, &c.General.GenesisFile)
 		cf.TranslatePathInPlace(configDir, &c.General.LocalMSPDir)
+		for i := range c.Consensus.Plugins {
+			cf.TranslatePathInPlace(configDir, &c.Consensus.Plugins[i])
+		}
 	}()
 
 	for {
@@ -282,17 +514,57 @@ func (c *TopLevel) completeInitialization(configDir string) {
 		case c.General.GenesisProfile == "":
 			c.General.GenesisProfile = defaults.General.GenesisProfile
 
-		case c.Kafka.TLS.Enabled && c.Kafka.TLS.Certificate == "":
+		case c.Kafka.TLS.Enabled && !c.Kafka.SASL.Enabled && c.Kafka.TLS.Certificate == "":
 			logger.Panicf("General.Kafka.TLS.Certificate must be set if General.Kafka.TLS.Enabled is set to true.")
-		case c.Kafka.TLS.Enabled && c.Kafka.TLS.PrivateKey == "":
+		case c.Kafka.TLS.Enabled && !c.Kafka.SASL.Enabled && c.Kafka.TLS.PrivateKey == "":
 			logger.Panicf("General.Kafka.TLS.PrivateKey must be set if General.Kafka.TLS.Enabled is set to true.")
+		case c.Kafka.TLS.Enabled && (c.Kafka.TLS.Certificate == "") != (c.Kafka.TLS.PrivateKey == ""):
+			logger.Panicf("General.Kafka.TLS.Certificate and General.Kafka.TLS.PrivateKey must be set together.")
 		case c.Kafka.TLS.Enabled && c.Kafka.TLS.RootCAs == nil:
 			logger.Panicf("General.Kafka.TLS.CertificatePool must be set if General.Kafka.TLS.Enabled is set to true.")
+		case c.Kafka.SASL.Enabled && (c.Kafka.SASL.Username == "" || c.Kafka.SASL.Password == ""):
+			logger.Panicf("Kafka.SASL.Username and Kafka.SASL.Password must be set if Kafka.SASL.Enabled is set to true.")
+
+		case c.General.DiskWatch.Enabled && c.General.DiskWatch.Interval == 0:
+			logger.Infof("General.DiskWatch.Interval unset, setting to %s", defaults.General.DiskWatch.Interval)
+			c.General.DiskWatch.Interval = defaults.General.DiskWatch.Interval
 
 		case c.General.Profile.Enabled && c.General.Profile.Address == "":
 			logger.Infof("Profiling enabled and General.Profile.Address unset, setting to %s", defaults.General.Profile.Address)
 			c.General.Profile.Address = defaults.General.Profile.Address
 
+		case c.General.Gateway.Enabled && c.General.Gateway.Address == "":
+			logger.Infof("Gateway enabled and General.Gateway.Address unset, setting to %s", defaults.General.Gateway.Address)
+			c.General.Gateway.Address = defaults.General.Gateway.Address
+
+		case c.General.BlockServer.Enabled && c.General.BlockServer.Address == "":
+			logger.Infof("Block server enabled and General.BlockServer.Address unset, setting to %s", defaults.General.BlockServer.Address)
+			c.General.BlockServer.Address = defaults.General.BlockServer.Address
+
+		case c.General.BlockServer.Enabled && c.General.BlockServer.SegmentSize == 0:
+			logger.Infof("General.BlockServer.SegmentSize unset, setting to %d", defaults.General.BlockServer.SegmentSize)
+			c.General.BlockServer.SegmentSize = defaults.General.BlockServer.SegmentSize
+
+		case c.General.MSPCache.Enabled && c.General.MSPCache.Size == 0:
+			logger.Infof("General.MSPCache.Size unset, setting to %d", defaults.General.MSPCache.Size)
+			c.General.MSPCache.Size = defaults.General.MSPCache.Size
+
+		case c.General.ClockSkew.Enabled && c.General.ClockSkew.Window == 0:
+			logger.Infof("General.ClockSkew.Window unset, setting to %s", defaults.General.ClockSkew.Window)
+			c.General.ClockSkew.Window = defaults.General.ClockSkew.Window
+
+		case c.General.HeaderGuard.Enabled && len(c.General.HeaderGuard.AllowedTypes) == 0:
+			logger.Infof("General.HeaderGuard.AllowedTypes unset, setting to %v", defaults.General.HeaderGuard.AllowedTypes)
+			c.General.HeaderGuard.AllowedTypes = defaults.General.HeaderGuard.AllowedTypes
+
+		case c.General.Usage.Period == 0:
+			logger.Infof("General.Usage.Period unset, setting to %s", defaults.General.Usage.Period)
+			c.General.Usage.Period = defaults.General.Usage.Period
+
+		case c.General.BlockHooks.Enabled && c.General.BlockHooks.QueueSize == 0:
+			logger.Infof("General.BlockHooks.QueueSize unset, setting to %d", defaults.General.BlockHooks.QueueSize)
+			c.General.BlockHooks.QueueSize = defaults.General.BlockHooks.QueueSize
+
 		case c.General.LocalMSPDir == "":
 			logger.Infof("General.LocalMSPDir unset, setting to %s", defaults.General.LocalMSPDir)
 			c.General.LocalMSPDir = defaults.General.LocalMSPDir
@@ -349,6 +621,28 @@ func (c *TopLevel) completeInitialization(configDir string) {
 			logger.Infof("Kafka.Version unset, setting to %v", defaults.Kafka.Version)
 			c.Kafka.Version = defaults.Kafka.Version
 
+		case c.Kafka.IdleChannels.Enabled && c.Kafka.IdleChannels.Period == 0:
+			logger.Infof("Kafka.IdleChannels.Period unset, setting to %s", defaults.Kafka.IdleChannels.Period)
+			c.Kafka.IdleChannels.Period = defaults.Kafka.IdleChannels.Period
+
+		case c.Kafka.LoadShedding.Enabled && c.Kafka.LoadShedding.SLO == 0:
+			logger.Infof("Kafka.LoadShedding.SLO unset, setting to %s", defaults.Kafka.LoadShedding.SLO)
+			c.Kafka.LoadShedding.SLO = defaults.Kafka.LoadShedding.SLO
+
+		case c.Kafka.LoadShedding.Enabled && c.Kafka.LoadShedding.Window <= 0:
+			logger.Infof("Kafka.LoadShedding.Window unset, setting to %d", defaults.Kafka.LoadShedding.Window)
+			c.Kafka.LoadShedding.Window = defaults.Kafka.LoadShedding.Window
+
+		case c.Kafka.LoadShedding.Enabled && (c.Kafka.LoadShedding.Fraction <= 0 || c.Kafka.LoadShedding.Fraction >= 1):
+			logger.Panicf("Kafka.LoadShedding.Fraction must be greater than 0 and lower than 1, got %v", c.Kafka.LoadShedding.Fraction)
+
+		case c.Kafka.LoadShedding.Enabled && c.Kafka.LoadShedding.RetryAfter == 0:
+			logger.Infof("Kafka.LoadShedding.RetryAfter unset, setting to %s", defaults.Kafka.LoadShedding.RetryAfter)
+			c.Kafka.LoadShedding.RetryAfter = defaults.Kafka.LoadShedding.RetryAfter
+
+		case c.Kafka.BatchTimeoutJitter < 0 || c.Kafka.BatchTimeoutJitter >= 1:
+			logger.Panicf("Kafka.BatchTimeoutJitter must be at least 0 and lower than 1, got %v", c.Kafka.BatchTimeoutJitter)
+
 		default:
 			return
 		}
@@ -21,10 +21,13 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	cf "github.com/hyperledger/fabric/core/config"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGoodConfig(t *testing.T) {
@@ -111,8 +114,118 @@ func TestKafkaTLSConfig(t *testing.T) {
 	}
 }
 
+func TestKafkaSASLConfig(t *testing.T) {
+	testCases := []struct {
+		name        string
+		tls         TLS
+		sasl        SASL
+		shouldPanic bool
+	}{
+		{"Disabled", TLS{}, SASL{Enabled: false}, false},
+		{"Enabled", TLS{}, SASL{Enabled: true, Username: "orderer", Password: "secret"}, false},
+		{"EnabledNoUsername", TLS{}, SASL{Enabled: true, Password: "secret"}, true},
+		{"EnabledNoPassword", TLS{}, SASL{Enabled: true, Username: "orderer"}, true},
+		{"OverTLSNoKeyPair", TLS{Enabled: true, RootCAs: []string{"root.crt"}}, SASL{Enabled: true, Username: "orderer", Password: "secret"}, false},
+		{"OverTLSNoPrivateKey", TLS{Enabled: true, Certificate: "public.key", RootCAs: []string{"root.crt"}}, SASL{Enabled: true, Username: "orderer", Password: "secret"}, true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			uconf := &TopLevel{Kafka: Kafka{TLS: tc.tls, SASL: tc.sasl}}
+			if tc.shouldPanic {
+				assert.Panics(t, func() { uconf.completeInitialization(DummyPath) }, "should panic")
+			} else {
+				assert.NotPanics(t, func() { uconf.completeInitialization(DummyPath) }, "should not panic")
+			}
+		})
+	}
+}
+
+func TestKafkaBatchTimeoutJitterConfig(t *testing.T) {
+	testCases := []struct {
+		name        string
+		jitter      float64
+		shouldPanic bool
+	}{
+		{"Disabled", 0, false},
+		{"Enabled", 0.2, false},
+		{"Negative", -0.1, true},
+		{"TooLarge", 1, true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			uconf := &TopLevel{Kafka: Kafka{BatchTimeoutJitter: tc.jitter}}
+			if tc.shouldPanic {
+				assert.Panics(t, func() { uconf.completeInitialization(DummyPath) }, "should panic")
+			} else {
+				assert.NotPanics(t, func() { uconf.completeInitialization(DummyPath) }, "should not panic")
+			}
+		})
+	}
+}
+
 func TestProfileConfig(t *testing.T) {
 	uconf := &TopLevel{General: General{Profile: Profile{Enabled: true}}}
 	uconf.completeInitialization(DummyPath)
 	assert.Equal(t, defaults.General.Profile.Address, uconf.General.Profile.Address, "Expected profile address to be filled with default value")
 }
+
+func TestMSPCacheConfig(t *testing.T) {
+	uconf := &TopLevel{General: General{MSPCache: MSPCache{Enabled: true}}}
+	uconf.completeInitialization(DummyPath)
+	assert.Equal(t, defaults.General.MSPCache.Size, uconf.General.MSPCache.Size, "Expected MSP cache size to be filled with default value")
+
+	uconf = &TopLevel{General: General{MSPCache: MSPCache{Enabled: false}}}
+	uconf.completeInitialization(DummyPath)
+	assert.Zero(t, uconf.General.MSPCache.Size, "Expected MSP cache size to be left unset when the cache is disabled")
+}
+
+func TestGatewayConfig(t *testing.T) {
+	uconf := &TopLevel{General: General{Gateway: Gateway{Enabled: true}}}
+	uconf.completeInitialization(DummyPath)
+	assert.Equal(t, defaults.General.Gateway.Address, uconf.General.Gateway.Address, "Expected gateway address to be filled with default value")
+}
+
+func TestBlockServerConfig(t *testing.T) {
+	uconf := &TopLevel{General: General{BlockServer: BlockServer{Enabled: true}}}
+	uconf.completeInitialization(DummyPath)
+	assert.Equal(t, defaults.General.BlockServer.Address, uconf.General.BlockServer.Address, "Expected block server address to be filled with default value")
+	assert.Equal(t, defaults.General.BlockServer.SegmentSize, uconf.General.BlockServer.SegmentSize, "Expected block server segment size to be filled with default value")
+}
+
+func TestUsageConfig(t *testing.T) {
+	uconf := &TopLevel{}
+	uconf.completeInitialization(DummyPath)
+	assert.Equal(t, defaults.General.Usage.Period, uconf.General.Usage.Period, "Expected usage period to be filled with default value")
+}
+
+func TestClockSkewConfig(t *testing.T) {
+	uconf := &TopLevel{General: General{ClockSkew: ClockSkew{Enabled: true}}}
+	uconf.completeInitialization(DummyPath)
+	assert.Equal(t, defaults.General.ClockSkew.Window, uconf.General.ClockSkew.Window, "Expected clock skew window to be filled with default value")
+}
+
+func TestHeaderGuardConfig(t *testing.T) {
+	uconf := &TopLevel{General: General{HeaderGuard: HeaderGuard{Enabled: true}}}
+	uconf.completeInitialization(DummyPath)
+	assert.Equal(t, defaults.General.HeaderGuard.AllowedTypes, uconf.General.HeaderGuard.AllowedTypes, "Expected allowed header types to be filled with default value")
+}
+
+func TestConsensusConfig(t *testing.T) {
+	devConfigDir, err := cf.GetDevConfigDir()
+	require.NoError(t, err)
+	sample, err := ioutil.ReadFile(filepath.Join(devConfigDir, "orderer.yaml"))
+	require.NoError(t, err)
+	dir, err := ioutil.TempDir("", "orderer_config")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	yaml := strings.Replace(string(sample), "    Plugins: []", "    Plugins: [plugins/mybft.so]", 1)
+	yaml = strings.Replace(yaml, "      # mybft:\n      #   RequestTimeout: 10s", "      mybft:\n        RequestTimeout: 10s", 1)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "orderer.yaml"), []byte(yaml), 0600))
+	os.Setenv("FABRIC_CFG_PATH", dir)
+	defer os.Unsetenv("FABRIC_CFG_PATH")
+
+	conf := Load()
+	assert.Equal(t, []string{filepath.Join(dir, "plugins", "mybft.so")}, conf.Consensus.Plugins, "Expected the plugin paths to be relative to the config")
+	assert.Equal(t, map[string]interface{}{"mybft": map[string]interface{}{"RequestTimeout": "10s"}}, conf.Consensus.Types)
+}
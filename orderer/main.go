@@ -17,6 +17,8 @@ limitations under the License.
 package main
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"io/ioutil"
 	"log"
@@ -24,14 +26,26 @@ import (
 	"net/http"
 	_ "net/http/pprof"
 	"os"
+	"strconv"
+	"time"
 
 	genesisconfig "github.com/hyperledger/fabric/common/configtx/tool/localconfig"
 	"github.com/hyperledger/fabric/common/configtx/tool/provisional"
 	"github.com/hyperledger/fabric/common/crypto"
+	"github.com/hyperledger/fabric/common/crypto/fips"
+	"github.com/hyperledger/fabric/common/diskwatch"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/core/comm"
+	"github.com/hyperledger/fabric/orderer/blockserver"
+	"github.com/hyperledger/fabric/orderer/common/blockhook"
 	"github.com/hyperledger/fabric/orderer/common/bootstrap/file"
+	"github.com/hyperledger/fabric/orderer/common/filter"
+	"github.com/hyperledger/fabric/orderer/common/headerguard"
+	"github.com/hyperledger/fabric/orderer/common/skewfilter"
+	"github.com/hyperledger/fabric/orderer/common/usage"
+	"github.com/hyperledger/fabric/orderer/gateway"
 	"github.com/hyperledger/fabric/orderer/kafka"
+	"github.com/hyperledger/fabric/orderer/kafka/chaos"
 	"github.com/hyperledger/fabric/orderer/ledger"
 	"github.com/hyperledger/fabric/orderer/localconfig"
 	"github.com/hyperledger/fabric/orderer/metadata"
@@ -43,6 +57,7 @@ import (
 
 	"github.com/Shopify/sarama"
 	"github.com/hyperledger/fabric/common/localmsp"
+	"github.com/hyperledger/fabric/msp/cache"
 	mspmgmt "github.com/hyperledger/fabric/msp/mgmt"
 	logging "github.com/op/go-logging"
 	"gopkg.in/alecthomas/kingpin.v2"
@@ -56,6 +71,9 @@ var (
 
 	start   = app.Command("start", "Start the orderer node").Default()
 	version = app.Command("version", "Show version information")
+
+	checkConfig     = app.Command("check-config", "Check the configuration of the orderer node without starting it")
+	checkConfigJSON = checkConfig.Flag("json", "Print the report as JSON").Bool()
 )
 
 func main() {
@@ -69,24 +87,71 @@ func main() {
 		conf := config.Load()
 		initializeLoggingLevel(conf)
 		initializeProfilingService(conf)
+		initializeFIPS(conf)
 		grpcServer := initializeGrpcServer(conf)
 		initializeLocalMsp(conf)
 		signer := localmsp.NewSigner()
-		manager := initializeMultiChainManager(conf, signer)
-		server := NewServer(manager, signer)
+		watcher := initializeDiskWatcher(conf)
+		accountant := usage.New(conf.General.Usage)
+		manager := initializeMultiChainManager(conf, signer, watcher, accountant)
+		server := NewServer(manager, signer, watcher, initializeIngressFilters(conf, manager), initializeHeaderGuard(conf, manager), conf.General.Tracing.AssignIDs)
 		ab.RegisterAtomicBroadcastServer(grpcServer.Server(), server)
+		ab.RegisterMultiChannelDeliverServer(grpcServer.Server(), NewMultiChannelDeliverServer(manager))
+		if subscriber, ok := manager.(multichain.HeightSubscriber); ok {
+			ab.RegisterLedgerHeightsServer(grpcServer.Server(), NewHeightsServer(manager, subscriber))
+		}
+		var txs gateway.TxLookup
+		if conf.FileLedger.TxIndex {
+			txs = txLookup{Manager: manager}
+		}
+		configs, _ := manager.(gateway.ConfigSubscriber)
+		var policies gateway.PolicyExplainer
+		if conf.General.Gateway.ExplainPolicies {
+			policies = policyExplainer{Manager: manager}
+		}
+		var usageReporter gateway.UsageReporter
+		if conf.General.Gateway.ReportUsage {
+			usageReporter = accountant
+		}
+		var updates gateway.ConfigValidator
+		if conf.General.Gateway.ValidateConfigUpdates {
+			updates = newConfigValidator(manager, signer)
+		}
+		var pausers gateway.ChainPauser
+		if conf.General.Gateway.PauseChains {
+			pausers = chainPauser{Manager: manager}
+		}
+		initializeGateway(conf, server, gatewayAuthorizer{Manager: manager}, traceLookup{Manager: manager}, txs, configs, policies, usageReporter, updates, pausers)
+		initializeBlockServer(conf, manager)
 		logger.Info("Beginning to serve requests")
 		grpcServer.Start()
 	// "version" command
 	case version.FullCommand():
 		fmt.Println(metadata.GetVersionInfo())
+	// "check-config" command
+	case checkConfig.FullCommand():
+		report := checkConfiguration()
+		if err := report.write(os.Stdout, *checkConfigJSON); err != nil {
+			logger.Fatal("Failed to print the report:", err)
+		}
+		if report.Failed {
+			os.Exit(1)
+		}
 	}
 
 }
 
 // Set the logging level
 func initializeLoggingLevel(conf *config.TopLevel) {
-	flogging.InitBackend(flogging.SetFormat(conf.General.LogFormat), os.Stderr)
+	redaction := flogging.RedactionRules{
+		Payloads:     conf.General.LogRedaction.Payloads,
+		Types:        conf.General.LogRedaction.Types,
+		Certificates: conf.General.LogRedaction.Certificates,
+		Patterns:     conf.General.LogRedaction.Patterns,
+	}
+	if err := flogging.InitBackendWithRedaction(flogging.SetFormat(conf.General.LogFormat), os.Stderr, redaction); err != nil {
+		logger.Panicf("Failed initializing the logging redaction: %s", err)
+	}
 	flogging.InitFromSpec(conf.General.LogLevel)
 	if conf.Kafka.Verbose {
 		sarama.Logger = log.New(os.Stdout, "[sarama] ", log.Ldate|log.Lmicroseconds|log.Lshortfile)
@@ -104,6 +169,122 @@ func initializeProfilingService(conf *config.TopLevel) {
 	}
 }
 
+// Create the filters applied to the messages as they are broadcasted. The skew of
+// their timestamp is measured even if its check is disabled. The channel creation
+// restrictions of the local configuration are checked here, and not as the messages
+// are ordered, as they may differ between orderers
+func initializeIngressFilters(conf *config.TopLevel, manager multichain.Manager) *filter.RuleSet {
+	var window time.Duration
+	if conf.General.ClockSkew.Enabled {
+		window = conf.General.ClockSkew.Window
+	}
+	return filter.NewRuleSet([]filter.Rule{
+		skewfilter.New(window),
+		multichain.NewChannelRestrictionsRule(manager, multichain.ChannelRestrictions{
+			MaxChannels:    conf.General.MaxChannels,
+			CreationPolicy: conf.General.ChannelCreationPolicy,
+		}),
+		filter.AcceptRule,
+	})
+}
+
+// Create the guard of the channel headers of the broadcasted messages if enabled
+func initializeHeaderGuard(conf *config.TopLevel, manager multichain.Manager) *headerguard.Guard {
+	if !conf.General.HeaderGuard.Enabled {
+		return nil
+	}
+	var allowedTypes []cb.HeaderType
+	for _, name := range conf.General.HeaderGuard.AllowedTypes {
+		if t, ok := cb.HeaderType_value[name]; ok {
+			allowedTypes = append(allowedTypes, cb.HeaderType(t))
+			continue
+		}
+		t, err := strconv.ParseInt(name, 10, 32)
+		if err != nil {
+			logger.Panicf("Unknown header type %s in General.HeaderGuard.AllowedTypes", name)
+		}
+		allowedTypes = append(allowedTypes, cb.HeaderType(t))
+	}
+	logger.Infof("Guarding the channel headers of the broadcasted messages, allowing types %v", conf.General.HeaderGuard.AllowedTypes)
+	return headerguard.New(manager.SystemChannelID(), allowedTypes)
+}
+
+// Start the HTTP and WebSocket gateway if enabled, with the TLS configuration of
+// the gRPC server
+func initializeGateway(conf *config.TopLevel, server ab.AtomicBroadcastServer, authz gateway.Authorizer, traces gateway.TraceLookup, txs gateway.TxLookup, configs gateway.ConfigSubscriber, policies gateway.PolicyExplainer, usage gateway.UsageReporter, updates gateway.ConfigValidator, pausers gateway.ChainPauser) {
+	if !conf.General.Gateway.Enabled {
+		return
+	}
+
+	httpServer := &http.Server{
+		Addr:    conf.General.Gateway.Address,
+		Handler: gateway.NewHandler(server, authz, traces, txs, configs, policies, usage, updates, pausers),
+	}
+	if conf.General.TLS.Enabled {
+		httpServer.TLSConfig = initializeGatewayTLSConfig(initializeSecureServerConfig(conf))
+	}
+
+	go func() {
+		logger.Info("Starting gateway on:", conf.General.Gateway.Address)
+		var err error
+		if httpServer.TLSConfig != nil {
+			err = httpServer.ListenAndServeTLS("", "")
+		} else {
+			err = httpServer.ListenAndServe()
+		}
+		logger.Panic("Gateway failed:", err)
+	}()
+}
+
+// Start the block server if enabled, with the TLS configuration of the gRPC server
+func initializeBlockServer(conf *config.TopLevel, manager multichain.Manager) {
+	if !conf.General.BlockServer.Enabled {
+		return
+	}
+
+	tokens, err := blockserver.LoadTokens(conf.General.BlockServer.TokensFile)
+	if err != nil {
+		logger.Panicf("Failed to load the tokens of the block server: %s", err)
+	}
+	httpServer := &http.Server{
+		Addr:    conf.General.BlockServer.Address,
+		Handler: blockserver.NewHandler(blockReaders{Manager: manager}, conf.General.BlockServer.SegmentSize, tokens),
+	}
+	if conf.General.TLS.Enabled {
+		httpServer.TLSConfig = initializeGatewayTLSConfig(initializeSecureServerConfig(conf))
+	}
+
+	go func() {
+		logger.Info("Starting block server on:", conf.General.BlockServer.Address)
+		var err error
+		if httpServer.TLSConfig != nil {
+			err = httpServer.ListenAndServeTLS("", "")
+		} else {
+			err = httpServer.ListenAndServe()
+		}
+		logger.Panic("Block server failed:", err)
+	}()
+}
+
+func initializeGatewayTLSConfig(secureConfig comm.SecureServerConfig) *tls.Config {
+	cert, err := tls.X509KeyPair(secureConfig.ServerCertificate, secureConfig.ServerKey)
+	if err != nil {
+		logger.Fatal("Failed to load the gateway certificate:", err)
+	}
+	tlsConfig := &tls.Config{Certificates: []tls.Certificate{cert}}
+	if secureConfig.RequireClientCert {
+		clientCAs := x509.NewCertPool()
+		for _, root := range secureConfig.ClientRootCAs {
+			if !clientCAs.AppendCertsFromPEM(root) {
+				logger.Fatal("Failed to load the client root CAs of the gateway")
+			}
+		}
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+		tlsConfig.ClientCAs = clientCAs
+	}
+	return fips.ConfigureTLS(tlsConfig)
+}
+
 func initializeSecureServerConfig(conf *config.TopLevel) comm.SecureServerConfig {
 	// secure server config
 	secureConfig := comm.SecureServerConfig{
@@ -196,7 +377,28 @@ func initializeGrpcServer(conf *config.TopLevel) comm.GRPCServer {
 	return grpcServer
 }
 
+// Restrict the cryptography to FIPS approved algorithms, before any TLS config,
+// BCCSP or MSP is set up
+func initializeFIPS(conf *config.TopLevel) {
+	if conf.General.FIPS.Enabled {
+		fips.Enable()
+	}
+	if !fips.Enabled() {
+		return
+	}
+	if err := fips.CheckBCCSPOpts(conf.General.BCCSP); err != nil {
+		logger.Fatal("BCCSP is not FIPS compliant:", err)
+	}
+}
+
 func initializeLocalMsp(conf *config.TopLevel) {
+	// Size the identity caches before any MSP is created
+	if conf.General.MSPCache.Enabled {
+		cache.SetSize(conf.General.MSPCache.Size)
+	} else {
+		cache.SetSize(0)
+	}
+
 	// Load local MSP
 	err := mspmgmt.LoadLocalMsp(conf.General.LocalMSPDir, conf.General.BCCSP, conf.General.LocalMSPID)
 	if err != nil { // Handle errors reading the config file
@@ -204,8 +406,42 @@ func initializeLocalMsp(conf *config.TopLevel) {
 	}
 }
 
-func initializeMultiChainManager(conf *config.TopLevel, signer crypto.LocalSigner) multichain.Manager {
+// Start monitoring the free space of the ledger directory if enabled.
+func initializeDiskWatcher(conf *config.TopLevel) *diskwatch.Watcher {
+	if !conf.General.DiskWatch.Enabled {
+		return nil
+	}
+	if conf.General.LedgerType != "file" && conf.General.LedgerType != "json" {
+		logger.Warningf("Disk watch is not applicable to the %s ledger", conf.General.LedgerType)
+		return nil
+	}
+	ld := conf.FileLedger.Location
+	if ld == "" {
+		// The ledger is created in a temporary directory
+		ld = os.TempDir()
+	}
+	watcher, err := diskwatch.New(diskwatch.Conf{
+		Paths:            []string{ld},
+		Interval:         conf.General.DiskWatch.Interval,
+		WarningFreeSpace: conf.General.DiskWatch.WarningFreeSpace,
+		QuiesceFreeSpace: conf.General.DiskWatch.QuiesceFreeSpace,
+	})
+	if err != nil {
+		logger.Fatal("Failed to initialize the disk watcher:", err)
+	}
+	watcher.Start()
+	return watcher
+}
+
+func initializeMultiChainManager(conf *config.TopLevel, signer crypto.LocalSigner, watcher *diskwatch.Watcher, accountant *usage.Accountant) multichain.Manager {
 	lf, _ := createLedgerFactory(conf)
+	if conf.General.LedgerWriteTimeout > 0 {
+		lf = ledger.WithWriteTimeout(lf, conf.General.LedgerWriteTimeout)
+	}
+	if watcher != nil {
+		// Waiting for disk space is not accounted to the write timeout
+		lf = ledger.WithDiskWatch(lf, watcher)
+	}
 	// Are we bootstrapping?
 	if len(lf.ChainIDs()) == 0 {
 		initializeBootstrapChannel(conf, lf)
@@ -215,7 +451,31 @@ func initializeMultiChainManager(conf *config.TopLevel, signer crypto.LocalSigne
 
 	consenters := make(map[string]multichain.Consenter)
 	consenters["solo"] = solo.New()
-	consenters["kafka"] = kafka.New(conf.Kafka.TLS, conf.Kafka.Retry, conf.Kafka.Version)
+	var kafkaClientWrapper kafka.ClientWrapper
+	if chaosConf := conf.Kafka.Chaos; chaosConf.Enabled {
+		logger.Warning("Injecting failures in the Kafka clients, do not use in production")
+		kafkaClientWrapper = chaos.NewWrapper(chaos.Faults{
+			LeaderFailover: chaosConf.LeaderFailover,
+			Duplicate:      chaosConf.Duplicate,
+			OffsetReset:    chaosConf.OffsetReset,
+			SlowBroker:     chaosConf.SlowBroker,
+			Seed:           chaosConf.Seed,
+		})
+	}
+	consenters["kafka"] = kafka.NewWithSASL(conf.Kafka.TLS, conf.Kafka.SASL, conf.Kafka.Retry, conf.Kafka.Version, kafkaClientWrapper, conf.Kafka.BatchTimeoutJitter)
+	if conf.Kafka.IdleChannels.Enabled {
+		consenters["kafka"] = kafka.WithIdleWatch(consenters["kafka"], conf.Kafka.IdleChannels)
+	}
+	if conf.Kafka.LoadShedding.Enabled {
+		// Applied last, so that the chains it returns implement multichain.LoadShedder
+		consenters["kafka"] = kafka.WithLoadShedding(consenters["kafka"], conf.Kafka.LoadShedding)
+	}
+	if err := multichain.LoadConsenterPlugins(conf.Consensus.Plugins); err != nil {
+		logger.Panicf("Failed to load the consenter plugins: %s", err)
+	}
+	if err := multichain.AddRegisteredConsenters(consenters, conf.Consensus.Types); err != nil {
+		logger.Panicf("Failed to set up the registered consenters: %s", err)
+	}
 
-	return multichain.NewManagerImpl(lf, consenters, signer)
+	return multichain.NewManagerImplWithBlockHooks(lf, consenters, signer, accountant, blockhook.New(conf.General.BlockHooks))
 }
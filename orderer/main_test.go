@@ -33,6 +33,7 @@ import (
 	"github.com/hyperledger/fabric/common/localmsp"
 	coreconfig "github.com/hyperledger/fabric/core/config"
 	config "github.com/hyperledger/fabric/orderer/localconfig"
+	"github.com/hyperledger/fabric/orderer/multichain"
 	logging "github.com/op/go-logging"
 	// logging "github.com/op/go-logging"
 	"github.com/stretchr/testify/assert"
@@ -77,6 +78,44 @@ func TestInitializeProfilingService(t *testing.T) {
 	}
 }
 
+func TestInitializeGateway(t *testing.T) {
+	// get a free random port
+	listenAddr := func() string {
+		l, _ := net.Listen("tcp", "localhost:0")
+		l.Close()
+		return l.Addr().String()
+	}()
+	initializeGateway(
+		&config.TopLevel{
+			General: config.General{
+				Gateway: config.Gateway{
+					Enabled: true,
+					Address: listenAddr,
+				}},
+		},
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+	var resp *http.Response
+	var err error
+	for i := 0; i < 10; i++ {
+		if resp, err = http.Post("http://"+listenAddr+"/broadcast", "application/json", strings.NewReader("{")); err == nil {
+			break
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+	if assert.NoError(t, err, "Expected the gateway to be up") {
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	}
+}
+
 func TestInitializeSecureServerConfig(t *testing.T) {
 	initializeSecureServerConfig(
 		&config.TopLevel{
@@ -240,10 +279,47 @@ func TestInitializeMultiChainManager(t *testing.T) {
 	}
 	assert.NotPanics(t, func() {
 		initializeLocalMsp(conf)
-		initializeMultiChainManager(conf, localmsp.NewSigner())
+		initializeMultiChainManager(conf, localmsp.NewSigner(), nil, nil)
 	})
 }
 
+func TestInitializeDiskWatcher(t *testing.T) {
+	conf := &config.TopLevel{General: config.General{LedgerType: "file"}}
+	assert.Nil(t, initializeDiskWatcher(conf), "Should not watch unless enabled")
+
+	conf.General.DiskWatch = config.DiskWatch{Enabled: true, Interval: time.Hour}
+	conf.General.LedgerType = "ram"
+	assert.Nil(t, initializeDiskWatcher(conf), "Should not watch the RAM ledger")
+
+	conf.General.LedgerType = "file"
+	conf.FileLedger.Location = os.TempDir()
+	watcher := initializeDiskWatcher(conf)
+	assert.NotNil(t, watcher)
+	defer watcher.Stop()
+	assert.False(t, watcher.Quiesced())
+}
+
+type systemChannelManager struct {
+	multichain.Manager
+}
+
+func (systemChannelManager) SystemChannelID() string {
+	return "system"
+}
+
+func TestInitializeHeaderGuard(t *testing.T) {
+	conf := &config.TopLevel{General: config.General{HeaderGuard: config.HeaderGuard{
+		AllowedTypes: []string{"ENDORSER_TRANSACTION", "10000"},
+	}}}
+	assert.Nil(t, initializeHeaderGuard(conf, systemChannelManager{}), "Should not guard unless enabled")
+
+	conf.General.HeaderGuard.Enabled = true
+	assert.NotNil(t, initializeHeaderGuard(conf, systemChannelManager{}))
+
+	conf.General.HeaderGuard.AllowedTypes = []string{"NOT_A_TYPE"}
+	assert.Panics(t, func() { initializeHeaderGuard(conf, systemChannelManager{}) }, "Should panic on an unknown header type")
+}
+
 func TestInitializeGrpcServer(t *testing.T) {
 	// get a free random port
 	listenAddr := func() string {
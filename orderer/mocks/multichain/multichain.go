@@ -17,6 +17,8 @@ limitations under the License.
 package multichain
 
 import (
+	"time"
+
 	"github.com/hyperledger/fabric/common/config"
 	mockconfig "github.com/hyperledger/fabric/common/mocks/config"
 	"github.com/hyperledger/fabric/orderer/common/blockcutter"
@@ -50,6 +52,12 @@ type ConsenterSupport struct {
 
 	// NextBlockVal stores the block created by the most recent CreateNextBlock() call
 	NextBlockVal *cb.Block
+
+	// TraceIDsVal stores the trace IDs passed to Trace(), in order
+	TraceIDsVal []string
+
+	// ReceivedVal stores the receipt times passed to Trace(), in order
+	ReceivedVal []time.Time
 }
 
 // BlockCutter returns BlockCutterVal
@@ -74,6 +82,12 @@ func (mcs *ConsenterSupport) CreateNextBlock(data []*cb.Envelope) *cb.Block {
 	return block
 }
 
+// Trace appends the trace ID to TraceIDsVal and the receipt time to ReceivedVal
+func (mcs *ConsenterSupport) Trace(env *cb.Envelope, traceID string, received time.Time) {
+	mcs.TraceIDsVal = append(mcs.TraceIDsVal, traceID)
+	mcs.ReceivedVal = append(mcs.ReceivedVal, received)
+}
+
 // WriteBlock writes data to the Blocks channel
 // Note that _committers is ignored by this mock implementation
 func (mcs *ConsenterSupport) WriteBlock(block *cb.Block, _committers []filter.Committer, encodedMetadataValue []byte) *cb.Block {
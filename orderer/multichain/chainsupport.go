@@ -17,6 +17,9 @@ limitations under the License.
 package multichain
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/hyperledger/fabric/common/config"
 	"github.com/hyperledger/fabric/common/crypto"
 	"github.com/hyperledger/fabric/common/policies"
@@ -25,10 +28,14 @@ import (
 	"github.com/hyperledger/fabric/orderer/common/broadcast"
 	"github.com/hyperledger/fabric/orderer/common/configtxfilter"
 	"github.com/hyperledger/fabric/orderer/common/filter"
+	"github.com/hyperledger/fabric/orderer/common/maintenancefilter"
+	"github.com/hyperledger/fabric/orderer/common/replayfilter"
 	"github.com/hyperledger/fabric/orderer/common/sigfilter"
 	"github.com/hyperledger/fabric/orderer/common/sizefilter"
+	"github.com/hyperledger/fabric/orderer/common/tracing"
 	"github.com/hyperledger/fabric/orderer/ledger"
 	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
 	"github.com/hyperledger/fabric/protos/utils"
 )
 
@@ -50,8 +57,9 @@ type Consenter interface {
 // 1. Messages are ordered into a stream, the stream is cut into blocks, the blocks are committed (solo, kafka)
 // 2. Messages are cut into blocks, the blocks are ordered, then the blocks are committed (sbft)
 type Chain interface {
-	// Enqueue accepts a message and returns true on acceptance, or false on failure
-	Enqueue(env *cb.Envelope) bool
+	// Enqueue accepts a message along with its trace ID, which may be empty, and returns true on acceptance, or
+	// false on failure
+	Enqueue(env *cb.Envelope, traceID string) bool
 
 	// Errored returns a channel which will close when an error has occurred
 	// This is especially useful for the Deliver client, who must terminate waiting
@@ -67,12 +75,39 @@ type Chain interface {
 	Halt()
 }
 
+// LoadShedder is implemented by the chains which reject a fraction of the messages broadcasted to them when
+// overloaded
+type LoadShedder interface {
+	// Shed returns true if the next message broadcasted should be rejected, along with the delay after which the
+	// client should retry it
+	Shed() (retryAfter time.Duration, shed bool)
+}
+
+// Pauser is implemented by the chains which an administrator can pause, e.g. for the maintenance of the consensus,
+// without halting them
+type Pauser interface {
+	// Pause stops the chain from ordering messages, and from accepting new ones, until it is resumed
+	Pause() error
+
+	// Resume resumes the ordering of the messages from where the chain was paused
+	Resume() error
+
+	// Paused returns whether the chain is paused
+	Paused() bool
+}
+
 // ConsenterSupport provides the resources available to a Consenter implementation
 type ConsenterSupport interface {
 	crypto.LocalSigner
 	BlockCutter() blockcutter.Receiver
 	SharedConfig() config.Orderer
 	CreateNextBlock(messages []*cb.Envelope) *cb.Block
+	// Trace records the trace ID of a message about to be passed to the blockcutter, and the time at which it was
+	// received for ordering, zero if unknown, so that they are written in the TRACE_IDS metadata of the block the
+	// message is cut into
+	Trace(env *cb.Envelope, traceID string, received time.Time)
+	// WriteBlock applies the committers of the block and appends it to the ledger, and returns it, or nil if it
+	// could not be appended: the chain is then halted and writes no further blocks
 	WriteBlock(block *cb.Block, committers []filter.Committer, encodedMetadataValue []byte) *cb.Block
 	ChainID() string // ChainID returns the chain ID this specific consenter instance is associated with
 	Height() uint64  // Returns the number of blocks on the chain this specific consenter instance is associated with
@@ -100,6 +135,16 @@ type ChainSupport interface {
 
 	// ProposeConfigUpdate applies a CONFIG_UPDATE to an existing config to produce a *cb.ConfigEnvelope
 	ProposeConfigUpdate(env *cb.Envelope) (*cb.ConfigEnvelope, error)
+
+	// LookupTrace returns the positions of the recently committed envelopes with the given trace ID
+	LookupTrace(traceID string) []*ab.TracePosition
+
+	// LocateTx returns the position of the most recently committed transaction with the given ID, or nil if there
+	// is no such transaction, and an error if the ledger of the chain does not index its transactions
+	LocateTx(txID string) (*ab.CommitNotification, error)
+
+	// Pauser returns the chain if it can be paused, and false otherwise
+	Pauser() (Pauser, bool)
 }
 
 type chainSupport struct {
@@ -110,6 +155,11 @@ type chainSupport struct {
 	signer        crypto.LocalSigner
 	lastConfig    uint64
 	lastConfigSeq uint64
+	commits       *commitNotifier
+	traces        *tracing.Pending
+
+	// halted is set by WriteBlock, on the goroutine of the consenter, once an append failed
+	halted bool
 }
 
 func newChainSupport(
@@ -119,7 +169,11 @@ func newChainSupport(
 	signer crypto.LocalSigner,
 ) *chainSupport {
 
-	cutter := blockcutter.NewReceiverImpl(ledgerResources.SharedConfig(), filters)
+	traces := tracing.NewPending(ledgerResources.ChainID())
+	cutter := &tracingCutter{
+		Receiver: blockcutter.NewReceiverImpl(ledgerResources.SharedConfig(), filters),
+		traces:   traces,
+	}
 	consenterType := ledgerResources.SharedConfig().ConsensusType()
 	consenter, ok := consenters[consenterType]
 	if !ok {
@@ -131,6 +185,8 @@ func newChainSupport(
 		cutter:          cutter,
 		filters:         filters,
 		signer:          signer,
+		commits:         newCommitNotifier(),
+		traces:          traces,
 	}
 
 	cs.lastConfigSeq = cs.Sequence()
@@ -169,7 +225,9 @@ func createStandardFilters(ledgerResources *ledgerResources) *filter.RuleSet {
 	return filter.NewRuleSet([]filter.Rule{
 		filter.EmptyRejectRule,
 		sizefilter.MaxBytesRule(ledgerResources.SharedConfig()),
+		replayfilter.New(ledgerResources.replayWindow),
 		sigfilter.New(policies.ChannelWriters, ledgerResources.PolicyManager()),
+		maintenancefilter.New(ledgerResources.SharedConfig()),
 		configtxfilter.NewFilter(ledgerResources),
 		filter.AcceptRule,
 	})
@@ -181,7 +239,9 @@ func createSystemChainFilters(ml *multiLedger, ledgerResources *ledgerResources)
 	return filter.NewRuleSet([]filter.Rule{
 		filter.EmptyRejectRule,
 		sizefilter.MaxBytesRule(ledgerResources.SharedConfig()),
+		replayfilter.New(ledgerResources.replayWindow),
 		sigfilter.New(policies.ChannelWriters, ledgerResources.PolicyManager()),
+		maintenancefilter.New(ledgerResources.SharedConfig()),
 		newSystemChainFilter(ledgerResources, ml),
 		configtxfilter.NewFilter(ledgerResources),
 		filter.AcceptRule,
@@ -212,16 +272,79 @@ func (cs *chainSupport) Reader() ledger.Reader {
 	return cs.ledger
 }
 
-func (cs *chainSupport) Enqueue(env *cb.Envelope) bool {
-	return cs.chain.Enqueue(env)
+func (cs *chainSupport) Enqueue(env *cb.Envelope, traceID string) bool {
+	return cs.chain.Enqueue(env, traceID)
+}
+
+func (cs *chainSupport) AwaitCommit(txID string) (<-chan *ab.CommitNotification, func()) {
+	return cs.commits.register(txID)
 }
 
 func (cs *chainSupport) Errored() <-chan struct{} {
 	return cs.chain.Errored()
 }
 
+func (cs *chainSupport) State() ab.BroadcastError_ChannelState {
+	if maintenancefilter.InMaintenance(cs.SharedConfig()) {
+		return ab.BroadcastError_MAINTENANCE
+	}
+	if pauser, ok := cs.Pauser(); ok && pauser.Paused() {
+		return ab.BroadcastError_PAUSED
+	}
+	select {
+	case <-cs.chain.Errored():
+		return ab.BroadcastError_ERRORED
+	default:
+		return ab.BroadcastError_ACTIVE
+	}
+}
+
+func (cs *chainSupport) Shed() (time.Duration, bool) {
+	if shedder, ok := cs.chain.(LoadShedder); ok {
+		return shedder.Shed()
+	}
+	return 0, false
+}
+
+func (cs *chainSupport) Pauser() (Pauser, bool) {
+	pauser, ok := cs.chain.(Pauser)
+	return pauser, ok
+}
+
+func (cs *chainSupport) Admit(env *cb.Envelope) (time.Duration, error) {
+	if cs.usage == nil {
+		return 0, nil
+	}
+	return cs.usage.Admit(cs.ChainID(), cs.ChannelConfig().ConsortiumName(), env)
+}
+
 func (cs *chainSupport) CreateNextBlock(messages []*cb.Envelope) *cb.Block {
-	return ledger.CreateNextBlock(cs.ledger, messages)
+	block := ledger.CreateNextBlock(cs.ledger, messages)
+	if cs.traces != nil {
+		block.Metadata.Metadata[cb.BlockMetadataIndex_TRACE_IDS] = cs.traces.Metadata(messages)
+	}
+	return block
+}
+
+func (cs *chainSupport) Trace(env *cb.Envelope, traceID string, received time.Time) {
+	if cs.traces != nil {
+		cs.traces.Set(env, traceID, received)
+	}
+}
+
+func (cs *chainSupport) LookupTrace(traceID string) []*ab.TracePosition {
+	if cs.traceIndex == nil {
+		return nil
+	}
+	return cs.traceIndex.Lookup(traceID)
+}
+
+func (cs *chainSupport) LocateTx(txID string) (*ab.CommitNotification, error) {
+	txIndex := ledger.GetTxIndex(cs.ledger)
+	if txIndex == nil {
+		return nil, fmt.Errorf("the ledger of chain %s does not index transactions", cs.ChainID())
+	}
+	return txIndex.LocateTx(txID)
 }
 
 func (cs *chainSupport) addBlockSignature(block *cb.Block) {
@@ -272,7 +395,19 @@ func (cs *chainSupport) addLastConfigSignature(block *cb.Block) {
 }
 
 func (cs *chainSupport) WriteBlock(block *cb.Block, committers []filter.Committer, encodedMetadataValue []byte) *cb.Block {
+	if cs.halted {
+		// The committers of the block must not be applied as the block is not written
+		logger.Errorf("[channel: %s] Not writing block %d as the chain halted", cs.ChainID(), block.Header.Number)
+		return nil
+	}
+	// The config committers must run before the block is signed, as they set its LAST_CONFIG, while the creation
+	// of a channel cannot be undone, hence only runs once the block creating it is written
+	var channelCreations []filter.Committer
 	for _, committer := range committers {
+		if _, ok := committer.(*systemChainCommitter); ok {
+			channelCreations = append(channelCreations, committer)
+			continue
+		}
 		committer.Commit()
 	}
 	// Set the orderer-related metadata field
@@ -284,9 +419,40 @@ func (cs *chainSupport) WriteBlock(block *cb.Block, committers []filter.Committe
 
 	err := cs.ledger.Append(block)
 	if err != nil {
-		logger.Panicf("[channel: %s] Could not append block: %s", cs.ChainID(), err)
+		// Halting (rather than panicking or blocking) stops the chain from accepting further messages and
+		// terminates its Deliver clients while the remaining chains of the orderer are still served. The chain
+		// is halted before returning, so that no further block is written nor has its committers applied; the
+		// consenters only signal their processing loop to exit, so Halt returns while the loop is calling.
+		// The config committers of the block already applied its config to the config manager of the chain,
+		// which is no longer used to order and is loaded from the ledger again when the orderer restarts
+		logger.Criticalf("[channel: %s] Could not append block %d, halting the chain: %s", cs.ChainID(), block.Header.Number, err)
+		cs.halted = true
+		cs.chain.Halt()
+		return nil
+	}
+	for _, committer := range channelCreations {
+		committer.Commit()
 	}
 	logger.Debugf("[channel: %s] Wrote block %d", cs.ChainID(), block.GetHeader().Number)
+	if cs.replayWindow != nil {
+		cs.replayWindow.AddBlock(block)
+	}
+	if cs.traceIndex != nil {
+		cs.traceIndex.AddBlock(block)
+	}
+	if cs.usage != nil {
+		cs.usage.Record(cs.ChainID(), cs.ChannelConfig().ConsortiumName(), block)
+	}
+	cs.commits.notify(block)
+	if cs.blockHooks != nil {
+		cs.blockHooks.Notify(cs.ChainID(), block)
+	}
+	if cs.heights != nil {
+		cs.heights.notify(cs.ChainID(), block)
+	}
+	if cs.configs != nil && utils.IsConfigBlock(block) {
+		cs.configs.notify(&ab.ConfigNotification{ChannelId: cs.ChainID(), BlockNumber: block.Header.Number, Sequence: cs.Sequence()})
+	}
 
 	return block
 }
@@ -294,3 +460,18 @@ func (cs *chainSupport) WriteBlock(block *cb.Block, committers []filter.Committe
 func (cs *chainSupport) Height() uint64 {
 	return cs.Reader().Height()
 }
+
+// tracingCutter forgets the trace IDs of the messages which the blockcutter rejects, as they are never cut into
+// a block
+type tracingCutter struct {
+	blockcutter.Receiver
+	traces *tracing.Pending
+}
+
+func (tc *tracingCutter) Ordered(msg *cb.Envelope) ([][]*cb.Envelope, [][]filter.Committer, bool, bool) {
+	batches, committers, ok, pending := tc.Receiver.Ordered(msg)
+	if !ok {
+		tc.traces.Forget(msg)
+	}
+	return batches, committers, ok, pending
+}
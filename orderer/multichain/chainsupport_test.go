@@ -17,12 +17,20 @@ limitations under the License.
 package multichain
 
 import (
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/config"
+	mockconfig "github.com/hyperledger/fabric/common/mocks/config"
 	mockconfigtx "github.com/hyperledger/fabric/common/mocks/configtx"
 	"github.com/hyperledger/fabric/common/mocks/crypto"
+	"github.com/hyperledger/fabric/orderer/common/blockcutter"
+	"github.com/hyperledger/fabric/orderer/common/blockhook"
 	"github.com/hyperledger/fabric/orderer/common/filter"
+	"github.com/hyperledger/fabric/orderer/common/replayfilter"
+	"github.com/hyperledger/fabric/orderer/common/tracing"
 	"github.com/hyperledger/fabric/orderer/ledger"
 	cb "github.com/hyperledger/fabric/protos/common"
 	ab "github.com/hyperledger/fabric/protos/orderer"
@@ -66,7 +74,7 @@ func (mc *mockCommitter) Commit() {
 func TestCommitConfig(t *testing.T) {
 	ml := &mockLedgerReadWriter{}
 	cm := &mockconfigtx.Manager{}
-	cs := &chainSupport{ledgerResources: &ledgerResources{configResources: &configResources{Manager: cm}, ledger: ml}, signer: mockCrypto()}
+	cs := &chainSupport{ledgerResources: &ledgerResources{configResources: &configResources{Manager: cm}, ledger: ml}, signer: mockCrypto(), commits: newCommitNotifier()}
 	assert.Equal(t, uint64(0), cs.Height(), "Should has height of 0")
 
 	txs := []*cb.Envelope{makeNormalTx("foo", 0), makeNormalTx("bar", 1)}
@@ -90,7 +98,7 @@ func TestCommitConfig(t *testing.T) {
 func TestWriteBlockSignatures(t *testing.T) {
 	ml := &mockLedgerReadWriter{}
 	cm := &mockconfigtx.Manager{}
-	cs := &chainSupport{ledgerResources: &ledgerResources{configResources: &configResources{Manager: cm}, ledger: ml}, signer: mockCrypto()}
+	cs := &chainSupport{ledgerResources: &ledgerResources{configResources: &configResources{Manager: cm}, ledger: ml}, signer: mockCrypto(), commits: newCommitNotifier()}
 
 	actual := utils.GetMetadataFromBlockOrPanic(cs.WriteBlock(cb.NewBlock(0, nil), nil, nil), cb.BlockMetadataIndex_SIGNATURES)
 	assert.NotNil(t, actual, "Block should have block signature")
@@ -99,7 +107,7 @@ func TestWriteBlockSignatures(t *testing.T) {
 func TestWriteBlockOrdererMetadata(t *testing.T) {
 	ml := &mockLedgerReadWriter{}
 	cm := &mockconfigtx.Manager{}
-	cs := &chainSupport{ledgerResources: &ledgerResources{configResources: &configResources{Manager: cm}, ledger: ml}, signer: mockCrypto()}
+	cs := &chainSupport{ledgerResources: &ledgerResources{configResources: &configResources{Manager: cm}, ledger: ml}, signer: mockCrypto(), commits: newCommitNotifier()}
 
 	value := []byte("foo")
 	expected := &cb.Metadata{Value: value}
@@ -108,10 +116,212 @@ func TestWriteBlockOrdererMetadata(t *testing.T) {
 	assert.True(t, proto.Equal(expected, actual), "Orderer metadata not written to block correctly")
 }
 
+type failingLedgerReadWriter struct {
+	mockLedgerReadWriter
+}
+
+func (flw *failingLedgerReadWriter) Append(block *cb.Block) error {
+	return fmt.Errorf("disk full")
+}
+
+type haltRecordingChain struct {
+	mockChain
+	halted chan struct{}
+}
+
+func (hc *haltRecordingChain) Halt() {
+	close(hc.halted)
+}
+
+func TestWriteBlockCreatesChannelOnceWritten(t *testing.T) {
+	ml := &mockLedgerReadWriter{}
+	cm := &mockconfigtx.Manager{}
+	cs := &chainSupport{ledgerResources: &ledgerResources{configResources: &configResources{Manager: cm}, ledger: ml}, signer: mockCrypto(), commits: newCommitNotifier()}
+
+	mcc := newMockChainCreator()
+	creation := &systemChainCommitter{filter: &systemChainFilter{cc: mcc}, configTx: &cb.Envelope{}}
+	assert.NotNil(t, cs.WriteBlock(cb.NewBlock(0, nil), []filter.Committer{creation}, nil), "Should have written the block")
+	assert.Equal(t, uint64(1), ml.height, "Should have appended the block")
+	assert.Len(t, mcc.newChains, 1, "Should have created the channel once the block was written")
+}
+
+func TestWriteBlockFailureHaltsChain(t *testing.T) {
+	ml := &failingLedgerReadWriter{}
+	cm := &mockconfigtx.Manager{}
+	chain := &haltRecordingChain{halted: make(chan struct{})}
+	cs := &chainSupport{ledgerResources: &ledgerResources{configResources: &configResources{Manager: cm}, ledger: ml}, chain: chain, signer: mockCrypto(), commits: newCommitNotifier()}
+
+	mcc := newMockChainCreator()
+	creation := &systemChainCommitter{filter: &systemChainFilter{cc: mcc}, configTx: &cb.Envelope{}}
+	assert.Nil(t, cs.WriteBlock(cb.NewBlock(0, nil), []filter.Committer{creation}, nil), "Should not have returned the block as written")
+	select {
+	case <-chain.halted:
+	default:
+		t.Fatalf("Should have halted the chain before returning")
+	}
+	assert.Empty(t, mcc.newChains, "Should not have created the channel of a block not written")
+
+	committer := &mockCommitter{}
+	assert.Nil(t, cs.WriteBlock(cb.NewBlock(1, nil), []filter.Committer{committer}, nil), "Should not have written the block")
+	assert.Equal(t, 0, committer.committed, "Should not have applied the committers of the block")
+}
+
+type erroredChain struct {
+	mockChain
+	errored chan struct{}
+}
+
+func (ec *erroredChain) Errored() <-chan struct{} {
+	return ec.errored
+}
+
+type pausedChain struct {
+	erroredChain
+	paused bool
+}
+
+func (pc *pausedChain) Pause() error  { pc.paused = true; return nil }
+func (pc *pausedChain) Resume() error { pc.paused = false; return nil }
+func (pc *pausedChain) Paused() bool  { return pc.paused }
+
+func TestState(t *testing.T) {
+	chain := &erroredChain{errored: make(chan struct{})}
+	oc := &mockconfig.Orderer{}
+	cm := &mockconfigtx.Manager{Initializer: mockconfigtx.Initializer{Resources: mockconfigtx.Resources{OrdererConfigVal: oc}}}
+	cs := &chainSupport{chain: chain, ledgerResources: &ledgerResources{configResources: &configResources{Manager: cm}}}
+	assert.Equal(t, ab.BroadcastError_ACTIVE, cs.State())
+	close(chain.errored)
+	assert.Equal(t, ab.BroadcastError_ERRORED, cs.State(), "Should have reported the errored consenter")
+	oc.ConsensusStateVal = ab.ConsensusType_STATE_MAINTENANCE
+	assert.Equal(t, ab.BroadcastError_ERRORED, cs.State(), "Should have ignored the maintenance mode without the Maintenance capability")
+	oc.CapabilitiesVal = []string{config.MaintenanceCapability}
+	assert.Equal(t, ab.BroadcastError_MAINTENANCE, cs.State(), "Should have reported the maintenance mode")
+	_, ok := cs.Pauser()
+	assert.False(t, ok, "Should not have paused a chain which cannot be")
+
+	paused := &pausedChain{erroredChain: erroredChain{errored: make(chan struct{})}}
+	oc.ConsensusStateVal = ab.ConsensusType_STATE_NORMAL
+	cs.chain = paused
+	pauser, ok := cs.Pauser()
+	assert.True(t, ok, "Should have returned the chain as pauser")
+	assert.NoError(t, pauser.Pause())
+	close(paused.errored)
+	assert.Equal(t, ab.BroadcastError_PAUSED, cs.State(), "Should have reported the paused consenter")
+	assert.NoError(t, pauser.Resume())
+	assert.Equal(t, ab.BroadcastError_ERRORED, cs.State())
+}
+
+func TestWriteBlockReplayWindow(t *testing.T) {
+	ml := &mockLedgerReadWriter{}
+	cm := &mockconfigtx.Manager{}
+	window := replayfilter.NewWindow(10)
+	cs := &chainSupport{ledgerResources: &ledgerResources{configResources: &configResources{Manager: cm}, ledger: ml, replayWindow: window}, signer: mockCrypto(), commits: newCommitNotifier()}
+
+	tx := &cb.Envelope{Payload: utils.MarshalOrPanic(&cb.Payload{
+		Header: &cb.Header{ChannelHeader: utils.MarshalOrPanic(&cb.ChannelHeader{ChannelId: "foo", TxId: "tx1"})},
+	})}
+	cs.WriteBlock(cs.CreateNextBlock([]*cb.Envelope{tx}), nil, nil)
+	assert.True(t, window.Contains("tx1"), "Should have added the written transactions to the replay window")
+}
+
+func TestWriteBlockTraceIDs(t *testing.T) {
+	ml := &mockLedgerReadWriter{}
+	cm := &mockconfigtx.Manager{}
+	index := tracing.NewIndex(10)
+	cs := &chainSupport{ledgerResources: &ledgerResources{configResources: &configResources{Manager: cm}, ledger: ml, traceIndex: index}, signer: mockCrypto(), commits: newCommitNotifier(), traces: tracing.NewPending("mychannel")}
+
+	traced, untraced := &cb.Envelope{Payload: []byte("traced")}, &cb.Envelope{Payload: []byte("untraced")}
+	cs.Trace(traced, "trace1", time.Now())
+	block := cs.WriteBlock(cs.CreateNextBlock([]*cb.Envelope{untraced, traced}), nil, nil)
+
+	traceIDs, err := tracing.BlockTraceIDs(block)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"", "trace1"}, traceIDs, "Should have recorded the trace IDs in the block metadata")
+	assert.Equal(t, []*ab.TracePosition{{TraceId: "trace1", BlockNumber: block.Header.Number, TxIndex: 1}}, cs.LookupTrace("trace1"))
+	assert.Empty(t, cs.LookupTrace("trace2"))
+
+	assert.Nil(t, cs.traces.Metadata([]*cb.Envelope{traced}), "Should have released the trace ID once cut")
+}
+
+func TestWriteBlockHooks(t *testing.T) {
+	ml := &mockLedgerReadWriter{}
+	cm := &mockconfigtx.Manager{ChainIDVal: "foo"}
+	written := make(chan *blockhook.Block, 1)
+	hooks := blockhook.NewDispatcher(map[string]blockhook.Hook{"test": blockhook.HookFunc(func(block *blockhook.Block) error {
+		written <- block
+		return nil
+	})}, 1)
+	defer hooks.Stop()
+	cs := &chainSupport{ledgerResources: &ledgerResources{configResources: &configResources{Manager: cm}, ledger: ml, blockHooks: hooks}, signer: mockCrypto(), commits: newCommitNotifier()}
+
+	block := cs.WriteBlock(cs.CreateNextBlock([]*cb.Envelope{{Payload: []byte("tx")}}), nil, nil)
+	select {
+	case hooked := <-written:
+		assert.Equal(t, &blockhook.Block{ChainID: "foo", Number: block.Header.Number, HeaderHash: block.Header.Hash(), TxCount: 1}, hooked)
+	case <-time.After(time.Second):
+		t.Fatal("Should have run the block hook for the block written")
+	}
+}
+
+func TestWriteBlockHeights(t *testing.T) {
+	ml := &mockLedgerReadWriter{}
+	cm := &mockconfigtx.Manager{ChainIDVal: "foo"}
+	heights := newHeightNotifier()
+	subscriber, unsubscribe := heights.subscribe("foo")
+	defer unsubscribe()
+	cs := &chainSupport{ledgerResources: &ledgerResources{configResources: &configResources{Manager: cm}, ledger: ml, heights: heights}, signer: mockCrypto(), commits: newCommitNotifier()}
+
+	block := cs.WriteBlock(cs.CreateNextBlock([]*cb.Envelope{{Payload: []byte("tx")}}), nil, nil)
+	select {
+	case notification := <-subscriber:
+		assert.Equal(t, &ab.HeightNotification{ChannelId: "foo", Height: block.Header.Number + 1, HeaderHash: block.Header.Hash()}, notification)
+	default:
+		t.Fatal("Should have notified the height of the ledger once the block was written")
+	}
+}
+
+type mockTxIndexedLedger struct {
+	mockLedgerReadWriter
+	positions map[string]*ab.CommitNotification
+}
+
+func (mtl *mockTxIndexedLedger) LocateTx(txID string) (*ab.CommitNotification, error) {
+	return mtl.positions[txID], nil
+}
+
+func TestLocateTx(t *testing.T) {
+	cm := &mockconfigtx.Manager{}
+	cs := &chainSupport{ledgerResources: &ledgerResources{configResources: &configResources{Manager: cm}, ledger: &mockLedgerReadWriter{}}}
+	_, err := cs.LocateTx("tx1")
+	assert.Error(t, err, "Should not locate transactions without a transaction index")
+
+	position := &ab.CommitNotification{TxId: "tx1", BlockNumber: 3, TxIndex: 1}
+	ml := &mockTxIndexedLedger{positions: map[string]*ab.CommitNotification{"tx1": position}}
+	cs = &chainSupport{ledgerResources: &ledgerResources{configResources: &configResources{Manager: cm}, ledger: ml}}
+	located, err := cs.LocateTx("tx1")
+	assert.NoError(t, err)
+	assert.Equal(t, position, located)
+}
+
+func TestTracingCutterForgetsRejected(t *testing.T) {
+	traces := tracing.NewPending("mychannel")
+	filters := filter.NewRuleSet([]filter.Rule{filter.EmptyRejectRule, filter.AcceptRule})
+	cutter := &tracingCutter{
+		Receiver: blockcutter.NewReceiverImpl(&mockconfig.Orderer{BatchSizeVal: &ab.BatchSize{MaxMessageCount: 10, AbsoluteMaxBytes: 1000, PreferredMaxBytes: 1000}}, filters),
+		traces:   traces,
+	}
+
+	rejected := &cb.Envelope{}
+	traces.Set(rejected, "trace1", time.Now())
+	_, _, ok, _ := cutter.Ordered(rejected)
+	assert.False(t, ok, "Should have rejected the empty message")
+	assert.Nil(t, traces.Metadata([]*cb.Envelope{rejected}), "Should have forgotten the trace ID of the rejected message")
+}
+
 func TestSignature(t *testing.T) {
 	ml := &mockLedgerReadWriter{}
 	cm := &mockconfigtx.Manager{}
-	cs := &chainSupport{ledgerResources: &ledgerResources{configResources: &configResources{Manager: cm}, ledger: ml}, signer: mockCrypto()}
+	cs := &chainSupport{ledgerResources: &ledgerResources{configResources: &configResources{Manager: cm}, ledger: ml}, signer: mockCrypto(), commits: newCommitNotifier()}
 
 	message := []byte("Darth Vader")
 	signed, _ := cs.Sign(message)
@@ -125,7 +335,7 @@ func TestSignature(t *testing.T) {
 func TestWriteLastConfig(t *testing.T) {
 	ml := &mockLedgerReadWriter{}
 	cm := &mockconfigtx.Manager{}
-	cs := &chainSupport{ledgerResources: &ledgerResources{configResources: &configResources{Manager: cm}, ledger: ml}, signer: mockCrypto()}
+	cs := &chainSupport{ledgerResources: &ledgerResources{configResources: &configResources{Manager: cm}, ledger: ml}, signer: mockCrypto(), commits: newCommitNotifier()}
 
 	expected := uint64(0)
 	lc := utils.GetLastConfigIndexFromBlockOrPanic(cs.WriteBlock(cb.NewBlock(0, nil), nil, nil))
@@ -145,7 +355,7 @@ func TestWriteLastConfig(t *testing.T) {
 		cm.SequenceVal = 2
 		expected = uint64(4)
 
-		cs = &chainSupport{ledgerResources: &ledgerResources{configResources: &configResources{Manager: cm}, ledger: ml}, signer: mockCrypto()}
+		cs = &chainSupport{ledgerResources: &ledgerResources{configResources: &configResources{Manager: cm}, ledger: ml}, signer: mockCrypto(), commits: newCommitNotifier()}
 		lc := utils.GetLastConfigIndexFromBlockOrPanic(cs.WriteBlock(cb.NewBlock(4, nil), nil, nil))
 		assert.Equal(t, expected, lc, "Second block should have config block index of %d, but got %d", expected, lc)
 
@@ -0,0 +1,85 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package multichain
+
+import (
+	"sync"
+
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+)
+
+// commitNotifier notifies the registered listeners once the transactions they wait for are included in a written block
+type commitNotifier struct {
+	lock      sync.Mutex
+	listeners map[string][]chan *ab.CommitNotification
+}
+
+func newCommitNotifier() *commitNotifier {
+	return &commitNotifier{listeners: make(map[string][]chan *ab.CommitNotification)}
+}
+
+// register returns a channel on which the position of the transaction with the given ID is sent once its block
+// is written, and a function which deregisters the listener
+func (cn *commitNotifier) register(txID string) (<-chan *ab.CommitNotification, func()) {
+	// Buffered, so that notifying never blocks the writing of blocks
+	listener := make(chan *ab.CommitNotification, 1)
+	cn.lock.Lock()
+	cn.listeners[txID] = append(cn.listeners[txID], listener)
+	cn.lock.Unlock()
+	return listener, func() { cn.deregister(txID, listener) }
+}
+
+func (cn *commitNotifier) deregister(txID string, listener chan *ab.CommitNotification) {
+	cn.lock.Lock()
+	defer cn.lock.Unlock()
+	listeners := cn.listeners[txID]
+	for i, l := range listeners {
+		if l == listener {
+			listeners = append(listeners[:i], listeners[i+1:]...)
+			break
+		}
+	}
+	if len(listeners) == 0 {
+		delete(cn.listeners, txID)
+		return
+	}
+	cn.listeners[txID] = listeners
+}
+
+// notify sends the position of each of the transactions of the block which have registered listeners
+func (cn *commitNotifier) notify(block *cb.Block) {
+	cn.lock.Lock()
+	defer cn.lock.Unlock()
+	if len(cn.listeners) == 0 {
+		return
+	}
+	for i, envBytes := range block.Data.Data {
+		env, err := utils.UnmarshalEnvelope(envBytes)
+		if err != nil {
+			continue
+		}
+		payload, err := utils.UnmarshalPayload(env.Payload)
+		if err != nil || payload.Header == nil {
+			continue
+		}
+		chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+		if err != nil || chdr.TxId == "" {
+			continue
+		}
+		listeners, ok := cn.listeners[chdr.TxId]
+		if !ok {
+			continue
+		}
+		for _, listener := range listeners {
+			listener <- &ab.CommitNotification{TxId: chdr.TxId, BlockNumber: block.Header.Number, TxIndex: uint64(i)}
+		}
+		// A transaction is notified only once, listeners which wait for its resubmission must register again
+		delete(cn.listeners, chdr.TxId)
+	}
+}
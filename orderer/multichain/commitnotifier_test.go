@@ -0,0 +1,49 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package multichain
+
+import (
+	"testing"
+
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func makeTxWithID(chainID string, txID string) *cb.Envelope {
+	return &cb.Envelope{Payload: utils.MarshalOrPanic(&cb.Payload{
+		Header: &cb.Header{ChannelHeader: utils.MarshalOrPanic(&cb.ChannelHeader{ChannelId: chainID, TxId: txID})},
+	})}
+}
+
+func TestCommitNotifier(t *testing.T) {
+	cn := newCommitNotifier()
+	notification1, cancel1 := cn.register("tx1")
+	notification2, cancel2 := cn.register("tx2")
+	duplicate1, _ := cn.register("tx1")
+	canceled, cancel3 := cn.register("tx3")
+	cancel3()
+
+	block := cb.NewBlock(5, nil)
+	block.Data.Data = [][]byte{
+		utils.MarshalOrPanic(makeTxWithID("foo", "tx0")),
+		utils.MarshalOrPanic(makeTxWithID("foo", "tx1")),
+		utils.MarshalOrPanic(makeTxWithID("foo", "tx3")),
+		[]byte("garbage"),
+	}
+	cn.notify(block)
+
+	assert.Equal(t, &ab.CommitNotification{TxId: "tx1", BlockNumber: 5, TxIndex: 1}, <-notification1)
+	assert.Equal(t, &ab.CommitNotification{TxId: "tx1", BlockNumber: 5, TxIndex: 1}, <-duplicate1)
+	assert.Len(t, notification2, 0, "Should not have notified a transaction which is not in the block")
+	assert.Len(t, canceled, 0, "Should not have notified a canceled listener")
+
+	cancel1()
+	cancel2()
+	assert.Empty(t, cn.listeners, "Should not retain listeners after being notified or canceled")
+}
@@ -0,0 +1,71 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package multichain
+
+import (
+	"sync"
+
+	ab "github.com/hyperledger/fabric/protos/orderer"
+)
+
+// configNotificationBuffer is the number of config notifications a subscriber may lag behind before being
+// dropped
+const configNotificationBuffer = 16
+
+// ConfigSubscriber is implemented by the managers which notify the config changes of their chains
+type ConfigSubscriber interface {
+	// SubscribeConfig returns a channel on which the config changes of the chain with the given ID, or of all
+	// the chains if it is empty, are sent, and a function which unsubscribes. The channel is closed if the
+	// subscriber lags behind, in which case it should refresh the configs it caches and subscribe again
+	SubscribeConfig(chainID string) (<-chan *ab.ConfigNotification, func())
+}
+
+// configNotifier notifies the subscribers once the config blocks of the chains are written
+type configNotifier struct {
+	lock        sync.Mutex
+	subscribers map[chan *ab.ConfigNotification]string
+}
+
+func newConfigNotifier() *configNotifier {
+	return &configNotifier{subscribers: make(map[chan *ab.ConfigNotification]string)}
+}
+
+func (cn *configNotifier) subscribe(chainID string) (<-chan *ab.ConfigNotification, func()) {
+	// Buffered, so that notifying never blocks the writing of blocks
+	subscriber := make(chan *ab.ConfigNotification, configNotificationBuffer)
+	cn.lock.Lock()
+	cn.subscribers[subscriber] = chainID
+	cn.lock.Unlock()
+	return subscriber, func() { cn.unsubscribe(subscriber) }
+}
+
+func (cn *configNotifier) unsubscribe(subscriber chan *ab.ConfigNotification) {
+	cn.lock.Lock()
+	defer cn.lock.Unlock()
+	if _, ok := cn.subscribers[subscriber]; ok {
+		delete(cn.subscribers, subscriber)
+		close(subscriber)
+	}
+}
+
+// notify sends the notification to the subscribers of its chain, and drops those which lag behind
+func (cn *configNotifier) notify(notification *ab.ConfigNotification) {
+	cn.lock.Lock()
+	defer cn.lock.Unlock()
+	for subscriber, chainID := range cn.subscribers {
+		if chainID != "" && chainID != notification.ChannelId {
+			continue
+		}
+		select {
+		case subscriber <- notification:
+		default:
+			logger.Warningf("[channel: %s] Dropping a config subscriber which lags behind", notification.ChannelId)
+			delete(cn.subscribers, subscriber)
+			close(subscriber)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package multichain
+
+import (
+	"testing"
+
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigNotifier(t *testing.T) {
+	cn := newConfigNotifier()
+	all, unsubscribeAll := cn.subscribe("")
+	foo, unsubscribeFoo := cn.subscribe("foo")
+	bar, unsubscribeBar := cn.subscribe("bar")
+
+	notification := &ab.ConfigNotification{ChannelId: "foo", BlockNumber: 3, Sequence: 2}
+	cn.notify(notification)
+	assert.Equal(t, notification, <-all)
+	assert.Equal(t, notification, <-foo)
+	assert.Len(t, bar, 0, "Should not have notified the subscriber of another chain")
+
+	for i := 0; i <= configNotificationBuffer; i++ {
+		cn.notify(&ab.ConfigNotification{ChannelId: "bar", BlockNumber: uint64(i)})
+	}
+	assert.Len(t, cn.subscribers, 1, "Should have dropped the subscribers lagging behind")
+	for range all {
+	}
+	for range bar {
+	}
+
+	unsubscribeAll()
+	unsubscribeBar()
+	unsubscribeFoo()
+	unsubscribeFoo()
+	assert.Empty(t, cn.subscribers, "Should not retain subscribers after unsubscribing")
+	_, ok := <-foo
+	assert.False(t, ok, "Should have closed the channel of the unsubscribed subscriber")
+}
@@ -0,0 +1,82 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package multichain
+
+import (
+	"sync"
+
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+)
+
+// heightNotificationBuffer is the number of height notifications a subscriber may lag behind before being
+// dropped. A notification is sent for every block, hence the buffer is larger than the one of the config
+// notifications
+const heightNotificationBuffer = 128
+
+// HeightSubscriber is implemented by the managers which notify the heights of the ledgers of their chains
+type HeightSubscriber interface {
+	// SubscribeHeights returns a channel on which the heights of the ledger of the chain with the given ID, or of
+	// all the chains if it is empty, are sent as blocks are written, and a function which unsubscribes. The
+	// channel is closed if the subscriber lags behind, in which case it should read the heights and subscribe again
+	SubscribeHeights(chainID string) (<-chan *ab.HeightNotification, func())
+}
+
+// heightNotifier notifies the subscribers once the blocks of the chains are written
+type heightNotifier struct {
+	lock        sync.Mutex
+	subscribers map[chan *ab.HeightNotification]string
+}
+
+func newHeightNotifier() *heightNotifier {
+	return &heightNotifier{subscribers: make(map[chan *ab.HeightNotification]string)}
+}
+
+func (hn *heightNotifier) subscribe(chainID string) (<-chan *ab.HeightNotification, func()) {
+	// Buffered, so that notifying never blocks the writing of blocks
+	subscriber := make(chan *ab.HeightNotification, heightNotificationBuffer)
+	hn.lock.Lock()
+	hn.subscribers[subscriber] = chainID
+	hn.lock.Unlock()
+	return subscriber, func() { hn.unsubscribe(subscriber) }
+}
+
+func (hn *heightNotifier) unsubscribe(subscriber chan *ab.HeightNotification) {
+	hn.lock.Lock()
+	defer hn.lock.Unlock()
+	if _, ok := hn.subscribers[subscriber]; ok {
+		delete(hn.subscribers, subscriber)
+		close(subscriber)
+	}
+}
+
+// notify sends the height of the ledger the block was written to to the subscribers of its chain, and drops
+// those which lag behind
+func (hn *heightNotifier) notify(chainID string, block *cb.Block) {
+	hn.lock.Lock()
+	defer hn.lock.Unlock()
+	if len(hn.subscribers) == 0 {
+		return
+	}
+	notification := &ab.HeightNotification{
+		ChannelId:  chainID,
+		Height:     block.Header.Number + 1,
+		HeaderHash: block.Header.Hash(),
+	}
+	for subscriber, subscribedID := range hn.subscribers {
+		if subscribedID != "" && subscribedID != chainID {
+			continue
+		}
+		select {
+		case subscriber <- notification:
+		default:
+			logger.Warningf("[channel: %s] Dropping a height subscriber which lags behind", chainID)
+			delete(hn.subscribers, subscriber)
+			close(subscriber)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package multichain
+
+import (
+	"testing"
+
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeightNotifier(t *testing.T) {
+	hn := newHeightNotifier()
+	hn.notify("foo", cb.NewBlock(0, nil)) // Without subscribers
+
+	all, unsubscribeAll := hn.subscribe("")
+	foo, unsubscribeFoo := hn.subscribe("foo")
+	bar, unsubscribeBar := hn.subscribe("bar")
+
+	block := cb.NewBlock(4, []byte("previous"))
+	hn.notify("foo", block)
+	expected := &ab.HeightNotification{ChannelId: "foo", Height: 5, HeaderHash: block.Header.Hash()}
+	assert.Equal(t, expected, <-all)
+	assert.Equal(t, expected, <-foo)
+	assert.Len(t, bar, 0, "Should not have notified the subscriber of another chain")
+
+	for i := 0; i <= heightNotificationBuffer; i++ {
+		hn.notify("bar", cb.NewBlock(uint64(i), nil))
+	}
+	assert.Len(t, hn.subscribers, 1, "Should have dropped the subscribers lagging behind")
+	for range all {
+	}
+	for range bar {
+	}
+
+	unsubscribeAll()
+	unsubscribeBar()
+	unsubscribeFoo()
+	unsubscribeFoo()
+	assert.Empty(t, hn.subscribers, "Should not retain subscribers after unsubscribing")
+	_, ok := <-foo
+	assert.False(t, ok, "Should have closed the channel of the unsubscribed subscriber")
+}
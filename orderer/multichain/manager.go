@@ -7,15 +7,23 @@ package multichain
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/hyperledger/fabric/common/config"
 	"github.com/hyperledger/fabric/common/configtx"
 	configtxapi "github.com/hyperledger/fabric/common/configtx/api"
+	"github.com/hyperledger/fabric/common/metrics"
 	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/orderer/common/blockhook"
+	"github.com/hyperledger/fabric/orderer/common/replayfilter"
+	"github.com/hyperledger/fabric/orderer/common/tracing"
+	"github.com/hyperledger/fabric/orderer/common/usage"
 	"github.com/hyperledger/fabric/orderer/ledger"
 	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
 	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/op/go-logging"
+	gometrics "github.com/rcrowley/go-metrics"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/crypto"
@@ -26,6 +34,14 @@ var logger = logging.MustGetLogger("orderer/multichain")
 const (
 	msgVersion = int32(0)
 	epoch      = 0
+
+	// replayWindowSize is the number of most recently committed transaction IDs of each chain which are
+	// rejected when resubmitted
+	replayWindowSize = 10000
+
+	// traceIndexSize is the number of most recently committed traced envelopes of each chain which can be
+	// looked up by their trace ID
+	traceIndexSize = 100000
 )
 
 // Manager coordinates the creation and access of chains
@@ -36,6 +52,9 @@ type Manager interface {
 	// SystemChannelID returns the channel ID for the system channel
 	SystemChannelID() string
 
+	// ChainIDs returns the IDs of the chains, sorted
+	ChainIDs() []string
+
 	// NewChannelConfig returns a bare bones configuration ready for channel
 	// creation request to be applied on top of it
 	NewChannelConfig(envConfigUpdate *cb.Envelope) (configtxapi.Manager, error)
@@ -55,7 +74,13 @@ func (cr *configResources) SharedConfig() config.Orderer {
 
 type ledgerResources struct {
 	*configResources
-	ledger ledger.ReadWriter
+	ledger       ledger.ReadWriter
+	replayWindow *replayfilter.Window
+	traceIndex   *tracing.Index
+	configs      *configNotifier
+	heights      *heightNotifier
+	usage        *usage.Accountant
+	blockHooks   *blockhook.Dispatcher
 }
 
 type multiLedger struct {
@@ -65,6 +90,11 @@ type multiLedger struct {
 	signer          crypto.LocalSigner
 	systemChannelID string
 	systemChannel   *chainSupport
+	channels        gometrics.Gauge
+	configs         *configNotifier
+	heights         *heightNotifier
+	usage           *usage.Accountant
+	blockHooks      *blockhook.Dispatcher
 }
 
 func getConfigTx(reader ledger.Reader) *cb.Envelope {
@@ -81,13 +111,31 @@ func getConfigTx(reader ledger.Reader) *cb.Envelope {
 	return utils.ExtractEnvelopeOrPanic(configBlock, 0)
 }
 
-// NewManagerImpl produces an instance of a Manager
+// NewManagerImpl produces an instance of a Manager. The number of channels is published as the orderer.channels
+// metric
 func NewManagerImpl(ledgerFactory ledger.Factory, consenters map[string]Consenter, signer crypto.LocalSigner) Manager {
+	return NewManagerImplWithUsage(ledgerFactory, consenters, signer, nil)
+}
+
+// NewManagerImplWithUsage produces an instance of a Manager whose chains account their usage with the given
+// accountant, and reject the broadcasts exceeding its quotas. A nil accountant accounts no usage
+func NewManagerImplWithUsage(ledgerFactory ledger.Factory, consenters map[string]Consenter, signer crypto.LocalSigner, accountant *usage.Accountant) Manager {
+	return NewManagerImplWithBlockHooks(ledgerFactory, consenters, signer, accountant, nil)
+}
+
+// NewManagerImplWithBlockHooks produces an instance of a Manager whose chains hand the blocks they write to the
+// given block hooks. A nil dispatcher runs no hooks
+func NewManagerImplWithBlockHooks(ledgerFactory ledger.Factory, consenters map[string]Consenter, signer crypto.LocalSigner, accountant *usage.Accountant, hooks *blockhook.Dispatcher) Manager {
 	ml := &multiLedger{
 		chains:        make(map[string]*chainSupport),
 		ledgerFactory: ledgerFactory,
 		consenters:    consenters,
 		signer:        signer,
+		channels:      gometrics.GetOrRegisterGauge("orderer.channels", metrics.Registry),
+		configs:       newConfigNotifier(),
+		heights:       newHeightNotifier(),
+		usage:         accountant,
+		blockHooks:    hooks,
 	}
 
 	existingChains := ledgerFactory.ChainIDs()
@@ -132,6 +180,7 @@ func NewManagerImpl(ledgerFactory ledger.Factory, consenters map[string]Consente
 	if ml.systemChannelID == "" {
 		logger.Panicf("No system chain found.  If bootstrapping, does your system channel contain a consortiums group definition?")
 	}
+	ml.channels.Update(int64(len(ml.chains)))
 
 	return ml
 }
@@ -140,6 +189,17 @@ func (ml *multiLedger) SystemChannelID() string {
 	return ml.systemChannelID
 }
 
+// ChainIDs returns the IDs of the chains, sorted
+func (ml *multiLedger) ChainIDs() []string {
+	chains := ml.chains
+	chainIDs := make([]string, 0, len(chains))
+	for chainID := range chains {
+		chainIDs = append(chainIDs, chainID)
+	}
+	sort.Strings(chainIDs)
+	return chainIDs
+}
+
 // GetChain retrieves the chain support for a chain (and whether it exists)
 func (ml *multiLedger) GetChain(chainID string) (ChainSupport, bool) {
 	cs, ok := ml.chains[chainID]
@@ -160,15 +220,36 @@ func (ml *multiLedger) newLedgerResources(configTx *cb.Envelope) *ledgerResource
 		logger.Panicf("Error getting ledger for %s", chainID)
 	}
 
+	replayWindow := replayfilter.NewWindow(replayWindowSize)
+	replayWindow.Load(ledger)
+
+	traceIndex := tracing.NewIndex(traceIndexSize)
+	traceIndex.Load(ledger)
+
+	if ml.usage != nil {
+		ml.usage.Load(chainID, configManager.ChannelConfig().ConsortiumName(), ledger)
+	}
+
 	return &ledgerResources{
 		configResources: &configResources{Manager: configManager},
 		ledger:          ledger,
+		replayWindow:    replayWindow,
+		traceIndex:      traceIndex,
+		configs:         ml.configs,
+		heights:         ml.heights,
+		usage:           ml.usage,
+		blockHooks:      ml.blockHooks,
 	}
 }
 
 func (ml *multiLedger) newChain(configtx *cb.Envelope) {
 	ledgerResources := ml.newLedgerResources(configtx)
-	ledgerResources.ledger.Append(ledger.CreateNextBlock(ledgerResources.ledger, []*cb.Envelope{configtx}))
+	genesisBlock := ledger.CreateNextBlock(ledgerResources.ledger, []*cb.Envelope{configtx})
+	ledgerResources.ledger.Append(genesisBlock)
+	if ml.usage != nil {
+		// Counted as Load counts it once the orderer restarts
+		ml.usage.Record(ledgerResources.ChainID(), ledgerResources.ChannelConfig().ConsortiumName(), genesisBlock)
+	}
 
 	// Copy the map to allow concurrent reads from broadcast/deliver while the new chainSupport is
 	newChains := make(map[string]*chainSupport)
@@ -185,6 +266,21 @@ func (ml *multiLedger) newChain(configtx *cb.Envelope) {
 	cs.start()
 
 	ml.chains = newChains
+	ml.channels.Update(int64(len(newChains)))
+	ml.configs.notify(&ab.ConfigNotification{ChannelId: chainID, BlockNumber: genesisBlock.Header.Number, Sequence: cs.Sequence()})
+	ml.heights.notify(chainID, genesisBlock)
+}
+
+// SubscribeConfig returns a channel on which the config changes of the chain with the given ID, or of all the chains
+// if it is empty, are sent
+func (ml *multiLedger) SubscribeConfig(chainID string) (<-chan *ab.ConfigNotification, func()) {
+	return ml.configs.subscribe(chainID)
+}
+
+// SubscribeHeights returns a channel on which the heights of the ledger of the chain with the given ID, or of all
+// the chains if it is empty, are sent as blocks are written
+func (ml *multiLedger) SubscribeHeights(chainID string) (<-chan *ab.HeightNotification, func()) {
+	return ml.heights.subscribe(chainID)
 }
 
 func (ml *multiLedger) channelsCount() int {
@@ -19,8 +19,10 @@ import (
 	"github.com/hyperledger/fabric/common/configtx/tool/provisional"
 	mockcrypto "github.com/hyperledger/fabric/common/mocks/crypto"
 	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/orderer/common/usage"
 	"github.com/hyperledger/fabric/orderer/ledger"
 	ramledger "github.com/hyperledger/fabric/orderer/ledger/ram"
+	localconfig "github.com/hyperledger/fabric/orderer/localconfig"
 	cb "github.com/hyperledger/fabric/protos/common"
 	ab "github.com/hyperledger/fabric/protos/orderer"
 	"github.com/hyperledger/fabric/protos/utils"
@@ -209,7 +211,7 @@ func TestFilterCreation(t *testing.T) {
 			}),
 		}
 
-		assert.True(t, chainSupport.Enqueue(messages[i]), "Should have successfully enqueued message")
+		assert.True(t, chainSupport.Enqueue(messages[i], ""), "Should have successfully enqueued message")
 	}
 
 	it, _ := rl.Iterator(&ab.SeekPosition{Type: &ab.SeekPosition_Specified{Specified: &ab.SeekSpecified{Number: 1}}})
@@ -246,7 +248,7 @@ func TestManagerImpl(t *testing.T) {
 	}
 
 	for _, message := range messages {
-		chainSupport.Enqueue(message)
+		chainSupport.Enqueue(message, "")
 	}
 
 	it, _ := rl.Iterator(&ab.SeekPosition{Type: &ab.SeekPosition_Specified{Specified: &ab.SeekSpecified{Number: 1}}})
@@ -262,6 +264,58 @@ func TestManagerImpl(t *testing.T) {
 	}
 }
 
+func TestManagerUsage(t *testing.T) {
+	lf, rl := NewRAMLedgerAndFactory(10)
+
+	consenters := make(map[string]Consenter)
+	consenters[conf.Orderer.OrdererType] = &mockConsenter{}
+
+	maxMessageCount := uint64(conf.Orderer.BatchSize.MaxMessageCount)
+	accountant := usage.New(localconfig.Usage{Period: time.Hour, Quotas: localconfig.UsageQuotas{
+		Enabled: true,
+		Channel: localconfig.Quota{Transactions: maxMessageCount},
+	}})
+	manager := NewManagerImplWithUsage(lf, consenters, mockCrypto(), accountant)
+	chainSupport, _ := manager.GetChain(provisional.TestChainID)
+
+	for i := 0; i < int(maxMessageCount); i++ {
+		message := makeNormalTx(provisional.TestChainID, i)
+		_, err := chainSupport.Admit(message)
+		assert.NoError(t, err, "Should have admitted message %d", i)
+		chainSupport.Enqueue(message, "")
+	}
+	retryAfter, err := chainSupport.Admit(makeNormalTx(provisional.TestChainID, int(maxMessageCount)))
+	assert.Error(t, err, "Should have exceeded the quota of the chain")
+	assert.True(t, retryAfter > 0 && retryAfter <= time.Hour, "Should have hinted to retry once the period is over")
+
+	it, _ := rl.Iterator(&ab.SeekPosition{Type: &ab.SeekPosition_Specified{Specified: &ab.SeekSpecified{Number: 1}}})
+	select {
+	case <-it.ReadyChan():
+		it.Next()
+	case <-time.After(time.Second):
+		t.Fatalf("Block 1 not produced after timeout")
+	}
+
+	// The genesis block is loaded from the ledger
+	report := accountant.Report()
+	if assert.Len(t, report.Channels, 1) {
+		assert.Equal(t, provisional.TestChainID, report.Channels[0].ChannelId)
+		assert.Equal(t, maxMessageCount+1, report.Channels[0].Total.Transactions)
+		assert.Equal(t, uint64(2), report.Channels[0].Total.Blocks)
+		assert.Equal(t, maxMessageCount, report.Channels[0].Admitted.Transactions)
+	}
+	assert.Empty(t, report.Consortiums, "The system channel belongs to no consortium")
+
+	// The usage written survives a restart, unlike the usage admitted
+	restarted := usage.New(localconfig.Usage{Period: time.Hour})
+	NewManagerImplWithUsage(lf, consenters, mockCrypto(), restarted)
+	restartedReport := restarted.Report()
+	if assert.Len(t, restartedReport.Channels, 1) {
+		assert.Equal(t, report.Channels[0].Total, restartedReport.Channels[0].Total)
+		assert.Equal(t, uint64(0), restartedReport.Channels[0].Admitted.Transactions)
+	}
+}
+
 func TestNewChannelConfig(t *testing.T) {
 	lf, _ := NewRAMLedgerAndFactoryWithMSP()
 
@@ -556,7 +610,7 @@ func TestNewChain(t *testing.T) {
 	chainSupport, ok := manager.GetChain(manager.SystemChannelID())
 	assert.True(t, ok, "Could not find system channel")
 
-	chainSupport.Enqueue(wrapped)
+	chainSupport.Enqueue(wrapped, "")
 
 	it, _ := rl.Iterator(&ab.SeekPosition{Type: &ab.SeekPosition_Specified{Specified: &ab.SeekSpecified{Number: 1}}})
 	select {
@@ -586,7 +640,7 @@ func TestNewChain(t *testing.T) {
 	}
 
 	for _, message := range messages {
-		chainSupport.Enqueue(message)
+		chainSupport.Enqueue(message, "")
 	}
 
 	it, _ = chainSupport.Reader().Iterator(&ab.SeekPosition{Type: &ab.SeekPosition_Specified{Specified: &ab.SeekSpecified{Number: 0}}})
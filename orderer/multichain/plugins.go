@@ -0,0 +1,108 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package multichain
+
+import (
+	"fmt"
+	"plugin"
+	"sort"
+	"sync"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+// ConsenterSettings is the section of orderer.yaml of a consensus type registered by a plugin, under
+// Consensus.Types
+type ConsenterSettings map[string]interface{}
+
+// Decode decodes the settings into the struct pointed to by out. The keys match the names of the fields
+// case insensitively, durations are parsed from strings such as "10s", and a key matching no field is an error
+func (s ConsenterSettings) Decode(out interface{}) error {
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		ErrorUnused:      true,
+		Result:           out,
+		WeaklyTypedInput: true,
+		DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+	})
+	if err != nil {
+		return err
+	}
+	return decoder.Decode(map[string]interface{}(s))
+}
+
+// ConsenterFactory creates the consenter of a consensus type registered by a plugin, from the settings of the
+// type in orderer.yaml, nil if it has none
+type ConsenterFactory func(settings ConsenterSettings) (Consenter, error)
+
+var (
+	consenterFactoriesLock sync.Mutex
+	consenterFactories     = make(map[string]ConsenterFactory)
+)
+
+// RegisterConsenter registers the factory of the consenter of a consensus type, so that the orderers loading
+// the plugin or built with the package registering it in its init function serve the channels whose
+// configuration sets the type
+func RegisterConsenter(consensusType string, factory ConsenterFactory) {
+	consenterFactoriesLock.Lock()
+	defer consenterFactoriesLock.Unlock()
+	if _, ok := consenterFactories[consensusType]; ok {
+		logger.Panicf("Consenter of type %s registered twice", consensusType)
+	}
+	consenterFactories[consensusType] = factory
+}
+
+// LoadConsenterPlugins opens the Go plugins at the given paths, built with "go build -buildmode=plugin" against
+// the same sources as the orderer. The plugins register their consenters with RegisterConsenter in their init
+// function, which runs as they are opened
+func LoadConsenterPlugins(paths []string) error {
+	for _, path := range paths {
+		if _, err := plugin.Open(path); err != nil {
+			return fmt.Errorf("cannot load consenter plugin %s: %s", path, err)
+		}
+		logger.Infof("Loaded consenter plugin %s", path)
+	}
+	return nil
+}
+
+// AddRegisteredConsenters creates the consenters registered, from the settings of their types keyed by
+// consensus type, and adds them to consenters. A registered type cannot replace one of the consenters
+// already there, and the settings of a type which is not registered are an error
+func AddRegisteredConsenters(consenters map[string]Consenter, settings map[string]interface{}) error {
+	consenterFactoriesLock.Lock()
+	defer consenterFactoriesLock.Unlock()
+	for consensusType := range settings {
+		if _, ok := consenterFactories[consensusType]; !ok {
+			return fmt.Errorf("no consenter of type %s is registered", consensusType)
+		}
+	}
+
+	consensusTypes := make([]string, 0, len(consenterFactories))
+	for consensusType := range consenterFactories {
+		consensusTypes = append(consensusTypes, consensusType)
+	}
+	sort.Strings(consensusTypes)
+	for _, consensusType := range consensusTypes {
+		if _, ok := consenters[consensusType]; ok {
+			return fmt.Errorf("the consenter registered for type %s conflicts with the built-in one", consensusType)
+		}
+		var typeSettings ConsenterSettings
+		if s, ok := settings[consensusType]; ok && s != nil {
+			m, ok := s.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("the settings of consensus type %s are not a section", consensusType)
+			}
+			typeSettings = ConsenterSettings(m)
+		}
+		consenter, err := consenterFactories[consensusType](typeSettings)
+		if err != nil {
+			return fmt.Errorf("cannot create the consenter of type %s: %s", consensusType, err)
+		}
+		consenters[consensusType] = consenter
+		logger.Infof("Registered consenter of type %s", consensusType)
+	}
+	return nil
+}
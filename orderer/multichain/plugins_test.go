@@ -0,0 +1,81 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package multichain
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// registerTestConsenter registers a consenter for the duration of the test
+func registerTestConsenter(consensusType string, factory ConsenterFactory) func() {
+	RegisterConsenter(consensusType, factory)
+	return func() {
+		consenterFactoriesLock.Lock()
+		delete(consenterFactories, consensusType)
+		consenterFactoriesLock.Unlock()
+	}
+}
+
+type testConsenterSettings struct {
+	RequestTimeout time.Duration
+	Nodes          []string
+}
+
+func TestAddRegisteredConsenters(t *testing.T) {
+	var decoded testConsenterSettings
+	defer registerTestConsenter("test.plugin", func(settings ConsenterSettings) (Consenter, error) {
+		if err := settings.Decode(&decoded); err != nil {
+			return nil, err
+		}
+		return &mockConsenter{}, nil
+	})()
+	assert.Panics(t, func() { RegisterConsenter("test.plugin", nil) }, "Expected a type registered twice to panic")
+
+	consenters := map[string]Consenter{"solo": &mockConsenter{}}
+	require.NoError(t, AddRegisteredConsenters(consenters, map[string]interface{}{
+		"test.plugin": map[string]interface{}{"requesttimeout": "10s", "nodes": []interface{}{"a", "b"}},
+	}))
+	assert.Contains(t, consenters, "test.plugin")
+	assert.Equal(t, testConsenterSettings{RequestTimeout: 10 * time.Second, Nodes: []string{"a", "b"}}, decoded)
+
+	t.Run("NoSettings", func(t *testing.T) {
+		decoded = testConsenterSettings{}
+		require.NoError(t, AddRegisteredConsenters(map[string]Consenter{}, nil))
+		assert.Equal(t, testConsenterSettings{}, decoded)
+	})
+
+	t.Run("Failures", func(t *testing.T) {
+		for name, settings := range map[string]map[string]interface{}{
+			"UnknownKey":  {"test.plugin": map[string]interface{}{"unknown": 1}},
+			"NotASection": {"test.plugin": "foo"},
+			"Unregistered": {
+				"test.unregistered": map[string]interface{}{},
+			},
+		} {
+			assert.Error(t, AddRegisteredConsenters(map[string]Consenter{}, settings), name)
+		}
+		assert.Error(t, AddRegisteredConsenters(map[string]Consenter{"test.plugin": &mockConsenter{}}, nil),
+			"Expected an error for a type conflicting with a built-in consenter")
+	})
+
+	t.Run("FactoryError", func(t *testing.T) {
+		defer registerTestConsenter("test.failing", func(ConsenterSettings) (Consenter, error) {
+			return nil, fmt.Errorf("no nodes")
+		})()
+		assert.Error(t, AddRegisteredConsenters(map[string]Consenter{}, nil))
+	})
+}
+
+func TestLoadConsenterPlugins(t *testing.T) {
+	assert.NoError(t, LoadConsenterPlugins(nil))
+	assert.Error(t, LoadConsenterPlugins([]string{"/nonexistent/consenter.so"}))
+}
@@ -0,0 +1,120 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package multichain
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric/common/configtx"
+	"github.com/hyperledger/fabric/orderer/common/filter"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+)
+
+// ChannelRestrictions restrict the creation of channels by the orderer, on top of the restrictions set by the config
+// of the system channel
+type ChannelRestrictions struct {
+	// MaxChannels is the maximum number of channels, the system channel excluded, or 0 for no maximum
+	MaxChannels uint64
+
+	// CreationPolicy is the name of a policy of the system channel which the signatures of the channel creation
+	// requests must satisfy, or empty for no such policy
+	CreationPolicy string
+}
+
+// NewChannelRestrictionsRule creates a rule which rejects the channel creation requests, the CONFIG_UPDATE messages
+// for a channel which does not exist yet, violating the given restrictions. The other messages are forwarded.
+//
+// As the restrictions come from the local configuration of the orderer, which may differ between orderers, the rule
+// must only be applied when the messages are broadcasted, and never when they are ordered
+func NewChannelRestrictionsRule(manager Manager, restrictions ChannelRestrictions) filter.Rule {
+	return &restrictionsRule{
+		manager:      manager,
+		restrictions: restrictions,
+	}
+}
+
+type restrictionsRule struct {
+	manager      Manager
+	restrictions ChannelRestrictions
+}
+
+func (r *restrictionsRule) Apply(message *cb.Envelope) (filter.Action, filter.Committer) {
+	action, committer, _ := r.ApplyAndExplain(message)
+	return action, committer
+}
+
+// ApplyAndExplain applies the rule, and returns the reason why a channel creation request is rejected
+func (r *restrictionsRule) ApplyAndExplain(message *cb.Envelope) (filter.Action, filter.Committer, error) {
+	payload, err := utils.UnmarshalPayload(message.Payload)
+	if err != nil || payload.Header == nil {
+		return filter.Forward, nil, nil
+	}
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil || chdr.Type != int32(cb.HeaderType_CONFIG_UPDATE) {
+		return filter.Forward, nil, nil
+	}
+	if _, ok := r.manager.GetChain(chdr.ChannelId); ok {
+		return filter.Forward, nil, nil
+	}
+
+	if err = r.checkChannelsCount(); err != nil {
+		logger.Warningf("[channel: %s] Rejecting channel creation because %s", chdr.ChannelId, err)
+		return filter.Reject, nil, err
+	}
+	if err = r.checkCreationPolicy(message, payload); err != nil {
+		logger.Debugf("[channel: %s] Rejecting channel creation because %s", chdr.ChannelId, err)
+		return filter.Reject, nil, err
+	}
+	return filter.Forward, nil, nil
+}
+
+// checkChannelsCount returns an error if creating a channel would exceed the maximum number of channels set by the
+// configuration of the orderer
+func (r *restrictionsRule) checkChannelsCount() error {
+	// We check for strictly greater than to accommodate the system channel
+	count := uint64(len(r.manager.ChainIDs()))
+	if maxChannels := r.restrictions.MaxChannels; maxChannels > 0 && count > maxChannels {
+		return fmt.Errorf("the orderer has reached the maximum number of channels set by its local configuration, %d", maxChannels)
+	}
+	return nil
+}
+
+// checkCreationPolicy returns an error if the signatures of the channel creation request, that of its creator and
+// those of the config update, do not satisfy the channel creation policy of the orderer, if any
+func (r *restrictionsRule) checkCreationPolicy(configUpdateEnv *cb.Envelope, payload *cb.Payload) error {
+	policyName := r.restrictions.CreationPolicy
+	if policyName == "" {
+		return nil
+	}
+	systemChannel, ok := r.manager.GetChain(r.manager.SystemChannelID())
+	if !ok {
+		return fmt.Errorf("the system channel %s does not exist", r.manager.SystemChannelID())
+	}
+	policy, ok := systemChannel.PolicyManager().GetPolicy(policyName)
+	if !ok {
+		return fmt.Errorf("the channel creation policy %s does not exist in the system channel", policyName)
+	}
+
+	signedData, err := configUpdateEnv.AsSignedData()
+	if err != nil {
+		return fmt.Errorf("error reading the signature of the config update: %s", err)
+	}
+	configUpdateEnvelope, err := configtx.UnmarshalConfigUpdateEnvelope(payload.Data)
+	if err != nil {
+		return fmt.Errorf("error unmarshaling config update envelope: %s", err)
+	}
+	updateSignedData, err := configUpdateEnvelope.AsSignedData()
+	if err != nil {
+		return fmt.Errorf("error reading the signatures of the config update: %s", err)
+	}
+
+	if err = policy.Evaluate(append(signedData, updateSignedData...)); err != nil {
+		return fmt.Errorf("the channel creation request does not satisfy the channel creation policy %s of the orderer: %s", policyName, err)
+	}
+	return nil
+}
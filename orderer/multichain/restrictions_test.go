@@ -0,0 +1,118 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package multichain
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/config"
+	"github.com/hyperledger/fabric/common/configtx"
+	mockpolicies "github.com/hyperledger/fabric/common/mocks/policies"
+	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/orderer/common/filter"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockRestrictionsChain struct {
+	ChainSupport
+	mpm *mockpolicies.Manager
+}
+
+func (mrc *mockRestrictionsChain) PolicyManager() policies.Manager {
+	return mrc.mpm
+}
+
+type mockRestrictionsManager struct {
+	Manager
+	chains map[string]*mockRestrictionsChain
+}
+
+func newMockRestrictionsManager(chainIDs ...string) *mockRestrictionsManager {
+	mrm := &mockRestrictionsManager{chains: make(map[string]*mockRestrictionsChain)}
+	for _, chainID := range append([]string{"system"}, chainIDs...) {
+		mrm.chains[chainID] = &mockRestrictionsChain{mpm: &mockpolicies.Manager{}}
+	}
+	return mrm
+}
+
+func (mrm *mockRestrictionsManager) GetChain(chainID string) (ChainSupport, bool) {
+	cs, ok := mrm.chains[chainID]
+	return cs, ok
+}
+
+func (mrm *mockRestrictionsManager) SystemChannelID() string {
+	return "system"
+}
+
+func (mrm *mockRestrictionsManager) ChainIDs() []string {
+	var chainIDs []string
+	for chainID := range mrm.chains {
+		chainIDs = append(chainIDs, chainID)
+	}
+	return chainIDs
+}
+
+func makeChannelCreationRequest(t *testing.T, chainID string) *cb.Envelope {
+	configEnv, err := configtx.NewCompositeTemplate(
+		configtx.NewSimpleTemplate(
+			config.DefaultHashingAlgorithm(),
+			config.DefaultBlockDataHashingStructure(),
+			config.TemplateOrdererAddresses([]string{"foo"}),
+		),
+		configtx.NewChainCreationTemplate("SampleConsortium", []string{}),
+	).Envelope(chainID)
+	assert.Nil(t, err, "Error constructing configtx")
+	configUpdateTx, err := utils.CreateSignedEnvelope(cb.HeaderType_CONFIG_UPDATE, chainID, mockCrypto(), configEnv, msgVersion, epoch)
+	assert.Nil(t, err, "Error constructing config update")
+	return configUpdateTx
+}
+
+func TestChannelRestrictionsMaxChannels(t *testing.T) {
+	manager := newMockRestrictionsManager("foo", "bar")
+	rule := NewChannelRestrictionsRule(manager, ChannelRestrictions{MaxChannels: 2}).(filter.ExplainingRule)
+
+	action, _, err := rule.ApplyAndExplain(makeChannelCreationRequest(t, "baz"))
+	assert.EqualValues(t, filter.Reject, action, "Transaction had created too many channels")
+	assert.EqualError(t, err, "the orderer has reached the maximum number of channels set by its local configuration, 2")
+
+	action, _, err = rule.ApplyAndExplain(makeConfigTxFromConfigUpdateEnvelope("foo", &cb.ConfigUpdateEnvelope{}))
+	assert.EqualValues(t, filter.Forward, action, "Should have forwarded a message which is not a config update")
+	assert.NoError(t, err)
+
+	action, _, err = rule.ApplyAndExplain(makeChannelCreationRequest(t, "foo"))
+	assert.EqualValues(t, filter.Forward, action, "Should have forwarded the config update of an existing channel")
+	assert.NoError(t, err)
+
+	rule = NewChannelRestrictionsRule(manager, ChannelRestrictions{MaxChannels: 3}).(filter.ExplainingRule)
+	action, _, err = rule.ApplyAndExplain(makeChannelCreationRequest(t, "baz"))
+	assert.EqualValues(t, filter.Forward, action, "Should have forwarded below the local maximum")
+	assert.NoError(t, err)
+}
+
+func TestChannelRestrictionsCreationPolicy(t *testing.T) {
+	manager := newMockRestrictionsManager()
+	rule := NewChannelRestrictionsRule(manager, ChannelRestrictions{CreationPolicy: "ChannelCreators"}).(filter.ExplainingRule)
+	request := makeChannelCreationRequest(t, "foo")
+
+	action, _, err := rule.ApplyAndExplain(request)
+	assert.EqualValues(t, filter.Reject, action, "Should have rejected without the policy")
+	assert.EqualError(t, err, "the channel creation policy ChannelCreators does not exist in the system channel")
+
+	manager.chains["system"].mpm.Policy = &mockpolicies.Policy{Err: fmt.Errorf("signature set did not satisfy policy")}
+	action, _, err = rule.ApplyAndExplain(request)
+	assert.EqualValues(t, filter.Reject, action, "Should have rejected an unauthorized creator")
+	assert.EqualError(t, err, "the channel creation request does not satisfy the channel creation policy ChannelCreators of the orderer: signature set did not satisfy policy")
+
+	manager.chains["system"].mpm.Policy = &mockpolicies.Policy{}
+	action, _, err = rule.ApplyAndExplain(request)
+	assert.EqualValues(t, filter.Forward, action, "Should have forwarded an authorized creator")
+	assert.NoError(t, err)
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/hyperledger/fabric/common/config"
 	"github.com/hyperledger/fabric/common/configtx"
 	configtxapi "github.com/hyperledger/fabric/common/configtx/api"
+	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/orderer/common/filter"
 	cb "github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/utils"
@@ -38,6 +39,7 @@ type chainCreator interface {
 
 type limitedSupport interface {
 	SharedConfig() config.Orderer
+	PolicyManager() policies.Manager
 }
 
 type systemChainCommitter struct {
@@ -66,51 +68,65 @@ func newSystemChainFilter(ls limitedSupport, cc chainCreator) filter.Rule {
 }
 
 func (scf *systemChainFilter) Apply(env *cb.Envelope) (filter.Action, filter.Committer) {
+	action, committer, _ := scf.ApplyAndExplain(env)
+	return action, committer
+}
+
+// ApplyAndExplain applies the filter, and returns the reason why a channel creation is rejected
+func (scf *systemChainFilter) ApplyAndExplain(env *cb.Envelope) (filter.Action, filter.Committer, error) {
 	msgData := &cb.Payload{}
 
 	err := proto.Unmarshal(env.Payload, msgData)
 	if err != nil {
-		return filter.Forward, nil
+		return filter.Forward, nil, nil
 	}
 
 	if msgData.Header == nil {
-		return filter.Forward, nil
+		return filter.Forward, nil, nil
 	}
 
 	chdr, err := utils.UnmarshalChannelHeader(msgData.Header.ChannelHeader)
 	if err != nil {
-		return filter.Forward, nil
+		return filter.Forward, nil, nil
 	}
 
 	if chdr.Type != int32(cb.HeaderType_ORDERER_TRANSACTION) {
-		return filter.Forward, nil
+		return filter.Forward, nil, nil
 	}
 
-	maxChannels := scf.support.SharedConfig().MaxChannelsCount()
-	if maxChannels > 0 {
-		// We check for strictly greater than to accommodate the system channel
-		if uint64(scf.cc.channelsCount()) > maxChannels {
-			logger.Warningf("Rejecting channel creation because the orderer has reached the maximum number of channels, %d", maxChannels)
-			return filter.Reject, nil
-		}
+	if err = scf.checkChannelsCount(); err != nil {
+		logger.Warningf("Rejecting channel creation because %s", err)
+		return filter.Reject, nil, err
 	}
 
 	configTx := &cb.Envelope{}
 	err = proto.Unmarshal(msgData.Data, configTx)
 	if err != nil {
-		return filter.Reject, nil
+		return filter.Reject, nil, fmt.Errorf("Rejecting chain proposal: Error unmarshaling config transaction: %s", err)
 	}
 
 	err = scf.authorizeAndInspect(configTx)
 	if err != nil {
 		logger.Debugf("Rejecting channel creation because: %s", err)
-		return filter.Reject, nil
+		return filter.Reject, nil, err
 	}
 
 	return filter.Accept, &systemChainCommitter{
 		filter:   scf,
 		configTx: configTx,
+	}, nil
+}
+
+// checkChannelsCount returns an error if creating a channel would exceed the maximum number of channels set by the
+// config of the system channel. The restrictions of the local configuration of the orderer are not checked here, as
+// the filter runs as the messages are ordered and must reach the same result on every orderer
+func (scf *systemChainFilter) checkChannelsCount() error {
+	// We check for strictly greater than to accommodate the system channel
+	count := uint64(scf.cc.channelsCount())
+	if maxChannels := scf.support.SharedConfig().MaxChannelsCount(); maxChannels > 0 && count > maxChannels {
+		return fmt.Errorf("the orderer has reached the maximum number of channels set by the config of the system channel, %d", maxChannels)
 	}
+	return nil
 }
 
 func (scf *systemChainFilter) authorize(configEnvelope *cb.ConfigEnvelope) (configtxapi.Manager, error) {
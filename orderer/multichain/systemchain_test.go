@@ -26,6 +26,8 @@ import (
 	configtxapi "github.com/hyperledger/fabric/common/configtx/api"
 	mockconfig "github.com/hyperledger/fabric/common/mocks/config"
 	mockconfigtx "github.com/hyperledger/fabric/common/mocks/configtx"
+	mockpolicies "github.com/hyperledger/fabric/common/mocks/policies"
+	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/orderer/common/filter"
 	cb "github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/utils"
@@ -36,11 +38,13 @@ import (
 
 type mockSupport struct {
 	msc *mockconfig.Orderer
+	mpm *mockpolicies.Manager
 }
 
 func newMockSupport() *mockSupport {
 	return &mockSupport{
 		msc: &mockconfig.Orderer{},
+		mpm: &mockpolicies.Manager{},
 	}
 }
 
@@ -48,6 +52,10 @@ func (ms *mockSupport) SharedConfig() config.Orderer {
 	return ms.msc
 }
 
+func (ms *mockSupport) PolicyManager() policies.Manager {
+	return ms.mpm
+}
+
 type mockChainCreator struct {
 	ms                  *mockSupport
 	newChains           []*cb.Envelope
@@ -18,6 +18,7 @@ package multichain
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/hyperledger/fabric/common/config"
 	"github.com/hyperledger/fabric/common/configtx"
@@ -52,7 +53,8 @@ func (mch *mockChain) Errored() <-chan struct{} {
 	return nil
 }
 
-func (mch *mockChain) Enqueue(env *cb.Envelope) bool {
+func (mch *mockChain) Enqueue(env *cb.Envelope, traceID string) bool {
+	mch.support.Trace(env, traceID, time.Now())
 	mch.queue <- env
 	return true
 }
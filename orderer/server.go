@@ -17,12 +17,23 @@ limitations under the License.
 package main
 
 import (
+	"fmt"
+
+	"github.com/hyperledger/fabric/common/configtx"
 	"github.com/hyperledger/fabric/common/crypto"
+	"github.com/hyperledger/fabric/common/diskwatch"
+	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/orderer/common/broadcast"
 	"github.com/hyperledger/fabric/orderer/common/deliver"
+	"github.com/hyperledger/fabric/orderer/common/filter"
+	"github.com/hyperledger/fabric/orderer/common/headerguard"
+	"github.com/hyperledger/fabric/orderer/common/heights"
 	"github.com/hyperledger/fabric/orderer/configupdate"
+	"github.com/hyperledger/fabric/orderer/ledger"
 	"github.com/hyperledger/fabric/orderer/multichain"
+	cb "github.com/hyperledger/fabric/protos/common"
 	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
 
 	"runtime/debug"
 )
@@ -38,10 +49,245 @@ func (cus configUpdateSupport) GetChain(chainID string) (configupdate.Support, b
 type broadcastSupport struct {
 	multichain.Manager
 	broadcast.ConfigUpdateProcessor
+	watcher *diskwatch.Watcher
 }
 
 func (bs broadcastSupport) GetChain(chainID string) (broadcast.Support, bool) {
-	return bs.Manager.GetChain(chainID)
+	cs, ok := bs.Manager.GetChain(chainID)
+	if !ok {
+		return nil, false
+	}
+	return quiescingSupport{Support: cs, watcher: bs.watcher}, true
+}
+
+// quiescingSupport refuses new messages while the disk watcher has quiesced the orderer
+type quiescingSupport struct {
+	broadcast.Support
+	watcher *diskwatch.Watcher
+}
+
+func (qs quiescingSupport) Enqueue(env *cb.Envelope, traceID string) bool {
+	if qs.watcher.Quiesced() {
+		logger.Warningf("Rejecting message as the orderer is quiesced for lack of disk space")
+		return false
+	}
+	return qs.Support.Enqueue(env, traceID)
+}
+
+func (qs quiescingSupport) State() ab.BroadcastError_ChannelState {
+	if qs.watcher.Quiesced() {
+		return ab.BroadcastError_QUIESCED
+	}
+	return qs.Support.State()
+}
+
+// gatewayAuthorizer checks the envelopes posted to the gateway against the policies of the chains of the manager
+type gatewayAuthorizer struct {
+	multichain.Manager
+}
+
+func (ga gatewayAuthorizer) AuthorizeReader(chainID string, signedData []*cb.SignedData) (cb.Status, error) {
+	if chainID == ga.Manager.SystemChannelID() {
+		return ga.authorize(chainID, policies.ChannelOrdererAdmins, signedData)
+	}
+	return ga.authorize(chainID, policies.ChannelReaders, signedData)
+}
+
+func (ga gatewayAuthorizer) AuthorizeAdmin(signedData []*cb.SignedData) (cb.Status, error) {
+	return ga.authorize(ga.Manager.SystemChannelID(), policies.ChannelOrdererAdmins, signedData)
+}
+
+func (ga gatewayAuthorizer) authorize(chainID, policyName string, signedData []*cb.SignedData) (cb.Status, error) {
+	cs, ok := ga.Manager.GetChain(chainID)
+	if !ok {
+		return cb.Status_NOT_FOUND, fmt.Errorf("channel %s was not found", chainID)
+	}
+	policy, ok := cs.PolicyManager().GetPolicy(policyName)
+	if !ok {
+		return cb.Status_FORBIDDEN, fmt.Errorf("policy %s was not found", policyName)
+	}
+	if err := policy.Evaluate(signedData); err != nil {
+		return cb.Status_FORBIDDEN, fmt.Errorf("policy %s not satisfied: %s", policyName, err)
+	}
+	return cb.Status_SUCCESS, nil
+}
+
+// traceLookup looks up traced envelopes in the chains of the manager
+type traceLookup struct {
+	multichain.Manager
+}
+
+func (tl traceLookup) LookupTrace(chainID, traceID string) ([]*ab.TracePosition, bool) {
+	cs, ok := tl.Manager.GetChain(chainID)
+	if !ok {
+		return nil, false
+	}
+	return cs.LookupTrace(traceID), true
+}
+
+// txLookup locates ordered transactions in the chains of the manager
+type txLookup struct {
+	multichain.Manager
+}
+
+func (tl txLookup) LocateTx(chainID, txID string) (*ab.CommitNotification, bool, error) {
+	cs, ok := tl.Manager.GetChain(chainID)
+	if !ok {
+		return nil, false, nil
+	}
+	position, err := cs.LocateTx(txID)
+	return position, true, err
+}
+
+// policyExplainer explains the decisions of the policies of the chains of the manager
+type policyExplainer struct {
+	multichain.Manager
+}
+
+func (pe policyExplainer) ExplainPolicy(chainID, policyPath string, signedData []*cb.SignedData) (*cb.PolicyDecision, bool) {
+	cs, ok := pe.Manager.GetChain(chainID)
+	if !ok {
+		return nil, false
+	}
+	return policies.ExplainPath(cs.PolicyManager(), policyPath, signedData)
+}
+
+// chainPauser pauses and resumes the chains of the manager on behalf of the administrators of their orderers
+type chainPauser struct {
+	multichain.Manager
+}
+
+func (cp chainPauser) PauseChain(chainID string, pause bool, signedData []*cb.SignedData) (cb.Status, error) {
+	cs, ok := cp.Manager.GetChain(chainID)
+	if !ok {
+		return cb.Status_NOT_FOUND, fmt.Errorf("channel %s was not found", chainID)
+	}
+	policy, ok := cs.PolicyManager().GetPolicy(policies.ChannelOrdererAdmins)
+	if !ok {
+		return cb.Status_FORBIDDEN, fmt.Errorf("policy %s was not found", policies.ChannelOrdererAdmins)
+	}
+	if err := policy.Evaluate(signedData); err != nil {
+		return cb.Status_FORBIDDEN, fmt.Errorf("policy %s not satisfied: %s", policies.ChannelOrdererAdmins, err)
+	}
+	pauser, ok := cs.Pauser()
+	if !ok {
+		return cb.Status_BAD_REQUEST, fmt.Errorf("the consenter of channel %s cannot be paused", chainID)
+	}
+	var err error
+	if pause {
+		err = pauser.Pause()
+	} else {
+		err = pauser.Resume()
+	}
+	if err != nil {
+		return cb.Status_SERVICE_UNAVAILABLE, err
+	}
+	return cb.Status_SUCCESS, nil
+}
+
+// blockReaders looks up the ledgers of the chains of the manager for the block server
+type blockReaders struct {
+	multichain.Manager
+}
+
+func (br blockReaders) Reader(chainID string) (ledger.Reader, bool) {
+	cs, ok := br.Manager.GetChain(chainID)
+	if !ok {
+		return nil, false
+	}
+	return cs.Reader(), true
+}
+
+// configValidator dry runs the config updates with the CONFIG_UPDATE processor and the filters of the chains
+// of the manager, as the broadcast handler would, without ordering them
+type configValidator struct {
+	multichain.Manager
+	processor broadcast.ConfigUpdateProcessor
+}
+
+func newConfigValidator(ml multichain.Manager, signer crypto.LocalSigner) configValidator {
+	return configValidator{
+		Manager:   ml,
+		processor: configupdate.New(ml.SystemChannelID(), configUpdateSupport{Manager: ml}, signer),
+	}
+}
+
+func (cv configValidator) ValidateConfigUpdate(env *cb.Envelope) *ab.ConfigUpdateValidation {
+	chdr, err := channelHeader(env)
+	if err != nil {
+		return invalidConfigUpdate(&ab.ConfigUpdateValidation{}, ab.BroadcastError_MALFORMED, "malformed envelope: %s", err)
+	}
+	validation := &ab.ConfigUpdateValidation{ChannelId: chdr.ChannelId}
+	if chdr.Type != int32(cb.HeaderType_CONFIG_UPDATE) {
+		return invalidConfigUpdate(validation, ab.BroadcastError_MALFORMED, "expected a CONFIG_UPDATE envelope, got %s", cb.HeaderType(chdr.Type))
+	}
+	_, exists := cv.Manager.GetChain(chdr.ChannelId)
+	validation.NewChannel = !exists
+
+	configTx, err := cv.processor.Process(env)
+	if err != nil {
+		return invalidConfigUpdate(validation, ab.BroadcastError_REJECTED, "%s", err)
+	}
+	configTxChdr, err := channelHeader(configTx)
+	if err != nil {
+		return invalidConfigUpdate(validation, ab.BroadcastError_INTERNAL, "generated bad transaction: %s", err)
+	}
+	cs, ok := cv.Manager.GetChain(configTxChdr.ChannelId)
+	if !ok {
+		return invalidConfigUpdate(validation, ab.BroadcastError_NOT_FOUND, "channel %s was not found", configTxChdr.ChannelId)
+	}
+	// The committers of the filters are not run, hence the update is not applied
+	if _, err := cs.Filters().Apply(configTx); err != nil {
+		return invalidConfigUpdate(validation, ab.BroadcastError_REJECTED, "filter error: %s", err)
+	}
+
+	validation.Config, err = resultingConfig(configTx, configTxChdr)
+	if err != nil {
+		return invalidConfigUpdate(validation, ab.BroadcastError_INTERNAL, "generated bad transaction: %s", err)
+	}
+	validation.Valid = true
+	return validation
+}
+
+func invalidConfigUpdate(validation *ab.ConfigUpdateValidation, class ab.BroadcastError_Class, format string, args ...interface{}) *ab.ConfigUpdateValidation {
+	validation.Class = class
+	validation.Message = fmt.Sprintf(format, args...)
+	logger.Debugf("[channel: %s] Config update is not valid: %s", validation.ChannelId, validation.Message)
+	return validation
+}
+
+func channelHeader(env *cb.Envelope) (*cb.ChannelHeader, error) {
+	payload, err := utils.UnmarshalPayload(env.Payload)
+	if err != nil {
+		return nil, err
+	}
+	if payload.Header == nil {
+		return nil, fmt.Errorf("missing header")
+	}
+	return utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+}
+
+// resultingConfig returns the config carried by the CONFIG transaction of an existing channel, or by the
+// CONFIG transaction wrapped in the ORDERER_TRANSACTION creating a channel
+func resultingConfig(configTx *cb.Envelope, chdr *cb.ChannelHeader) (*cb.Config, error) {
+	payload, err := utils.UnmarshalPayload(configTx.Payload)
+	if err != nil {
+		return nil, err
+	}
+	if chdr.Type == int32(cb.HeaderType_ORDERER_TRANSACTION) {
+		wrapped, err := utils.UnmarshalEnvelope(payload.Data)
+		if err != nil {
+			return nil, err
+		}
+		if payload, err = utils.UnmarshalPayload(wrapped.Payload); err != nil {
+			return nil, err
+		}
+	}
+	configEnv, err := configtx.UnmarshalConfigEnvelope(payload.Data)
+	if err != nil {
+		return nil, err
+	}
+	return configEnv.Config, nil
 }
 
 type deliverSupport struct {
@@ -57,14 +303,18 @@ type server struct {
 	dh deliver.Handler
 }
 
-// NewServer creates an ab.AtomicBroadcastServer based on the broadcast target and ledger Reader
-func NewServer(ml multichain.Manager, signer crypto.LocalSigner) ab.AtomicBroadcastServer {
+// NewServer creates an ab.AtomicBroadcastServer based on the broadcast target and ledger Reader.
+// While the disk watcher, if any, has quiesced the orderer, Broadcast answers with SERVICE_UNAVAILABLE.
+// The ingress filters and the header guard, if any, are applied to the broadcasted messages as they are received,
+// and a trace ID is assigned to the messages broadcast without one if assignTraceIDs is set
+func NewServer(ml multichain.Manager, signer crypto.LocalSigner, watcher *diskwatch.Watcher, ingress *filter.RuleSet, guard *headerguard.Guard, assignTraceIDs bool) ab.AtomicBroadcastServer {
 	s := &server{
 		dh: deliver.NewHandlerImpl(deliverSupport{Manager: ml}),
-		bh: broadcast.NewHandlerImpl(broadcastSupport{
+		bh: broadcast.NewHandlerImplWithTraceIDs(broadcastSupport{
 			Manager:               ml,
 			ConfigUpdateProcessor: configupdate.New(ml.SystemChannelID(), configUpdateSupport{Manager: ml}, signer),
-		}),
+			watcher:               watcher,
+		}, ingress, guard, assignTraceIDs),
 	}
 	return s
 }
@@ -92,3 +342,58 @@ func (s *server) Deliver(srv ab.AtomicBroadcast_DeliverServer) error {
 	}()
 	return s.dh.Handle(srv)
 }
+
+type multiChannelDeliverServer struct {
+	dh deliver.Handler
+}
+
+// NewMultiChannelDeliverServer creates an ab.MultiChannelDeliverServer serving the blocks of several chains of the
+// manager on each stream
+func NewMultiChannelDeliverServer(ml multichain.Manager) ab.MultiChannelDeliverServer {
+	return &multiChannelDeliverServer{dh: deliver.NewHandlerImpl(deliverSupport{Manager: ml})}
+}
+
+// Deliver sends a stream of the blocks of several chains to a client, labeled with their chain
+func (s *multiChannelDeliverServer) Deliver(srv ab.MultiChannelDeliver_DeliverServer) error {
+	logger.Debugf("Starting new multi-channel Deliver handler")
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Criticalf("Multi-channel Deliver client triggered panic: %s\n%s", r, debug.Stack())
+		}
+		logger.Debugf("Closing multi-channel Deliver stream")
+	}()
+	return s.dh.HandleMultiChannel(srv)
+}
+
+// heightsSupport looks up the chains of the manager, and subscribes to the heights of their ledgers, for the
+// height subscriptions
+type heightsSupport struct {
+	multichain.Manager
+	multichain.HeightSubscriber
+}
+
+func (hs heightsSupport) GetChain(chainID string) (heights.Support, bool) {
+	return hs.Manager.GetChain(chainID)
+}
+
+type heightsServer struct {
+	hh heights.Handler
+}
+
+// NewHeightsServer creates an ab.LedgerHeightsServer notifying the heights of the ledgers of the chains of the
+// manager as the subscriber sends them
+func NewHeightsServer(ml multichain.Manager, subscriber multichain.HeightSubscriber) ab.LedgerHeightsServer {
+	return &heightsServer{hh: heights.NewHandlerImpl(heightsSupport{Manager: ml, HeightSubscriber: subscriber})}
+}
+
+// Subscribe sends a stream of the heights of the ledgers of the chains as blocks are written
+func (s *heightsServer) Subscribe(env *cb.Envelope, srv ab.LedgerHeights_SubscribeServer) error {
+	logger.Debugf("Starting new height subscription handler")
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Criticalf("Height subscriber triggered panic: %s\n%s", r, debug.Stack())
+		}
+		logger.Debugf("Closing height subscription stream")
+	}()
+	return s.hh.Handle(env, srv)
+}
@@ -17,9 +17,38 @@ limitations under the License.
 package main
 
 import (
+	"fmt"
+	"math"
+	"os"
 	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/common/diskwatch"
+	mockpolicies "github.com/hyperledger/fabric/common/mocks/policies"
+	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/orderer/common/broadcast"
+	"github.com/hyperledger/fabric/orderer/common/filter"
+	"github.com/hyperledger/fabric/orderer/multichain"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
 )
 
+type mockBroadcastSupport struct {
+	broadcast.Support
+	enqueued int
+}
+
+func (mbs *mockBroadcastSupport) State() ab.BroadcastError_ChannelState {
+	return ab.BroadcastError_ACTIVE
+}
+
+func (mbs *mockBroadcastSupport) Enqueue(env *cb.Envelope, traceID string) bool {
+	mbs.enqueued++
+	return true
+}
+
 func TestBroadcastNoPanic(t *testing.T) {
 	// Defer recovers from the panic
 	_ = (&server{}).Broadcast(nil)
@@ -29,3 +58,218 @@ func TestDeliverNoPanic(t *testing.T) {
 	// Defer recovers from the panic
 	_ = (&server{}).Deliver(nil)
 }
+
+func TestMultiChannelDeliverNoPanic(t *testing.T) {
+	// Defer recovers from the panic
+	_ = (&multiChannelDeliverServer{}).Deliver(nil)
+}
+
+func TestQuiescingSupport(t *testing.T) {
+	mbs := &mockBroadcastSupport{}
+	assert.True(t, quiescingSupport{Support: mbs}.Enqueue(&cb.Envelope{}, ""), "Should enqueue without a watcher")
+	assert.Equal(t, ab.BroadcastError_ACTIVE, quiescingSupport{Support: mbs}.State())
+
+	// No file system has that much free space
+	watcher, err := diskwatch.New(diskwatch.Conf{
+		Paths:            []string{os.TempDir()},
+		Interval:         time.Hour,
+		WarningFreeSpace: math.MaxUint64,
+		QuiesceFreeSpace: math.MaxUint64,
+	})
+	assert.NoError(t, err)
+	watcher.Check()
+	assert.False(t, quiescingSupport{Support: mbs, watcher: watcher}.Enqueue(&cb.Envelope{}, ""), "Should refuse messages while quiesced")
+	assert.Equal(t, 1, mbs.enqueued)
+	assert.Equal(t, ab.BroadcastError_QUIESCED, quiescingSupport{Support: mbs, watcher: watcher}.State())
+}
+
+type mockValidatorManager struct {
+	multichain.Manager
+	chains map[string]multichain.ChainSupport
+}
+
+func (mvm *mockValidatorManager) GetChain(chainID string) (multichain.ChainSupport, bool) {
+	cs, ok := mvm.chains[chainID]
+	return cs, ok
+}
+
+func (mvm *mockValidatorManager) SystemChannelID() string {
+	return "system"
+}
+
+type mockFilteringSupport struct {
+	multichain.ChainSupport
+	filters *filter.RuleSet
+}
+
+func (mfs *mockFilteringSupport) Filters() *filter.RuleSet {
+	return mfs.filters
+}
+
+// mockConfigUpdateProcessor produces the config transactions of the channels, and fails for the others
+type mockConfigUpdateProcessor map[string]*cb.Envelope
+
+func (mcup mockConfigUpdateProcessor) Process(env *cb.Envelope) (*cb.Envelope, error) {
+	chdr, err := channelHeader(env)
+	if err != nil {
+		return nil, err
+	}
+	configTx, ok := mcup[chdr.ChannelId]
+	if !ok {
+		return nil, fmt.Errorf("policy for [Groups] /Channel/Application not satisfied")
+	}
+	return configTx, nil
+}
+
+func makeEnvelope(headerType cb.HeaderType, chainID string, data []byte) *cb.Envelope {
+	return &cb.Envelope{Payload: utils.MarshalOrPanic(&cb.Payload{
+		Header: &cb.Header{ChannelHeader: utils.MarshalOrPanic(&cb.ChannelHeader{Type: int32(headerType), ChannelId: chainID})},
+		Data:   data,
+	})}
+}
+
+func TestConfigValidator(t *testing.T) {
+	accept := &mockFilteringSupport{filters: filter.NewRuleSet([]filter.Rule{filter.AcceptRule})}
+	reject := &mockFilteringSupport{filters: filter.NewRuleSet([]filter.Rule{filter.EmptyRejectRule})}
+	configEnv := func(sequence uint64) []byte {
+		return utils.MarshalOrPanic(&cb.ConfigEnvelope{Config: &cb.Config{Sequence: sequence}})
+	}
+	cv := configValidator{
+		Manager: &mockValidatorManager{chains: map[string]multichain.ChainSupport{
+			"system":    accept,
+			"mychannel": accept,
+			"rejecting": reject,
+		}},
+		processor: mockConfigUpdateProcessor{
+			"mychannel": makeEnvelope(cb.HeaderType_CONFIG, "mychannel", configEnv(5)),
+			"newchannel": makeEnvelope(cb.HeaderType_ORDERER_TRANSACTION, "system",
+				utils.MarshalOrPanic(makeEnvelope(cb.HeaderType_CONFIG, "newchannel", configEnv(1)))),
+			"rejecting": makeEnvelope(cb.HeaderType_CONFIG, "rejecting", nil),
+		},
+	}
+
+	validation := cv.ValidateConfigUpdate(makeEnvelope(cb.HeaderType_CONFIG_UPDATE, "mychannel", nil))
+	assert.True(t, validation.Valid, validation.Message)
+	assert.False(t, validation.NewChannel)
+	assert.Equal(t, uint64(5), validation.Config.Sequence)
+
+	validation = cv.ValidateConfigUpdate(makeEnvelope(cb.HeaderType_CONFIG_UPDATE, "newchannel", nil))
+	assert.True(t, validation.Valid, validation.Message)
+	assert.True(t, validation.NewChannel)
+	assert.Equal(t, "newchannel", validation.ChannelId)
+	assert.Equal(t, uint64(1), validation.Config.Sequence, "Should return the config of the channel created")
+
+	validation = cv.ValidateConfigUpdate(makeEnvelope(cb.HeaderType_CONFIG_UPDATE, "otherchannel", nil))
+	assert.False(t, validation.Valid)
+	assert.Equal(t, ab.BroadcastError_REJECTED, validation.Class)
+	assert.Contains(t, validation.Message, "not satisfied")
+	assert.Nil(t, validation.Config)
+
+	validation = cv.ValidateConfigUpdate(makeEnvelope(cb.HeaderType_CONFIG_UPDATE, "rejecting", nil))
+	assert.False(t, validation.Valid)
+	assert.Equal(t, ab.BroadcastError_REJECTED, validation.Class)
+	assert.Contains(t, validation.Message, "filter error")
+
+	validation = cv.ValidateConfigUpdate(makeEnvelope(cb.HeaderType_ENDORSER_TRANSACTION, "mychannel", nil))
+	assert.False(t, validation.Valid)
+	assert.Equal(t, ab.BroadcastError_MALFORMED, validation.Class)
+
+	validation = cv.ValidateConfigUpdate(&cb.Envelope{Payload: []byte("garbage")})
+	assert.False(t, validation.Valid)
+	assert.Equal(t, ab.BroadcastError_MALFORMED, validation.Class)
+}
+
+type mockPausingSupport struct {
+	multichain.ChainSupport
+	policy *mockpolicies.Policy
+	chain  multichain.Pauser
+}
+
+func (mps *mockPausingSupport) PolicyManager() policies.Manager {
+	return &mockpolicies.Manager{Policy: mps.policy}
+}
+
+func (mps *mockPausingSupport) Pauser() (multichain.Pauser, bool) {
+	return mps.chain, mps.chain != nil
+}
+
+type mockPauser struct {
+	paused bool
+	err    error
+}
+
+func (mp *mockPauser) Pause() error  { mp.paused = mp.err == nil; return mp.err }
+func (mp *mockPauser) Resume() error { mp.paused = false; return mp.err }
+func (mp *mockPauser) Paused() bool  { return mp.paused }
+
+func TestChainPauser(t *testing.T) {
+	chain := &mockPauser{}
+	admins := &mockpolicies.Policy{}
+	cp := chainPauser{Manager: &mockValidatorManager{chains: map[string]multichain.ChainSupport{
+		"mychannel": &mockPausingSupport{policy: admins, chain: chain},
+		"solo":      &mockPausingSupport{policy: admins},
+	}}}
+
+	status, err := cp.PauseChain("mychannel", true, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, cb.Status_SUCCESS, status)
+	assert.True(t, chain.paused, "Should have paused the chain")
+	status, err = cp.PauseChain("mychannel", false, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, cb.Status_SUCCESS, status)
+	assert.False(t, chain.paused, "Should have resumed the chain")
+
+	status, err = cp.PauseChain("otherchannel", true, nil)
+	assert.Error(t, err)
+	assert.Equal(t, cb.Status_NOT_FOUND, status)
+
+	status, err = cp.PauseChain("solo", true, nil)
+	assert.Error(t, err)
+	assert.Equal(t, cb.Status_BAD_REQUEST, status, "Should not have paused a chain which cannot be")
+
+	chain.err = fmt.Errorf("consenter for this channel has been halted")
+	status, err = cp.PauseChain("mychannel", true, nil)
+	assert.Error(t, err)
+	assert.Equal(t, cb.Status_SERVICE_UNAVAILABLE, status)
+
+	admins.Err = fmt.Errorf("signature set did not satisfy policy")
+	chain.err = nil
+	status, err = cp.PauseChain("mychannel", true, nil)
+	assert.Error(t, err)
+	assert.Equal(t, cb.Status_FORBIDDEN, status)
+	assert.False(t, chain.paused, "Should not have paused the chain without the consent of its administrators")
+}
+
+func TestGatewayAuthorizer(t *testing.T) {
+	readers := &mockpolicies.Policy{}
+	ga := gatewayAuthorizer{Manager: &mockValidatorManager{chains: map[string]multichain.ChainSupport{
+		"system":    &mockPausingSupport{policy: &mockpolicies.Policy{Err: fmt.Errorf("signature set did not satisfy policy")}},
+		"mychannel": &mockPausingSupport{policy: readers},
+		"nopolicy":  &mockPausingSupport{},
+	}}}
+
+	status, err := ga.AuthorizeReader("mychannel", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, cb.Status_SUCCESS, status)
+
+	status, err = ga.AuthorizeReader("otherchannel", nil)
+	assert.EqualError(t, err, "channel otherchannel was not found")
+	assert.Equal(t, cb.Status_NOT_FOUND, status)
+
+	status, err = ga.AuthorizeReader("nopolicy", nil)
+	assert.EqualError(t, err, "policy /Channel/Readers was not found")
+	assert.Equal(t, cb.Status_FORBIDDEN, status)
+
+	readers.Err = fmt.Errorf("signature set did not satisfy policy")
+	status, err = ga.AuthorizeReader("mychannel", nil)
+	assert.EqualError(t, err, "policy /Channel/Readers not satisfied: signature set did not satisfy policy")
+	assert.Equal(t, cb.Status_FORBIDDEN, status)
+
+	// Only the administrators of the orderers read the system channel
+	status, err = ga.AuthorizeReader("system", nil)
+	assert.EqualError(t, err, "policy /Channel/Orderer/Admins not satisfied: signature set did not satisfy policy")
+	assert.Equal(t, cb.Status_FORBIDDEN, status)
+	status, err = ga.AuthorizeAdmin(nil)
+	assert.EqualError(t, err, "policy /Channel/Orderer/Admins not satisfied: signature set did not satisfy policy")
+	assert.Equal(t, cb.Status_FORBIDDEN, status)
+}
@@ -19,6 +19,7 @@ package solo
 import (
 	"time"
 
+	"github.com/hyperledger/fabric/common/config"
 	"github.com/hyperledger/fabric/orderer/multichain"
 	cb "github.com/hyperledger/fabric/protos/common"
 	"github.com/op/go-logging"
@@ -28,9 +29,15 @@ var logger = logging.MustGetLogger("orderer/solo")
 
 type consenter struct{}
 
+type tracedEnvelope struct {
+	env      *cb.Envelope
+	traceID  string
+	received time.Time
+}
+
 type chain struct {
 	support  multichain.ConsenterSupport
-	sendChan chan *cb.Envelope
+	sendChan chan *tracedEnvelope
 	exitChan chan struct{}
 }
 
@@ -49,7 +56,7 @@ func (solo *consenter) HandleChain(support multichain.ConsenterSupport, metadata
 func newChain(support multichain.ConsenterSupport) *chain {
 	return &chain{
 		support:  support,
-		sendChan: make(chan *cb.Envelope),
+		sendChan: make(chan *tracedEnvelope),
 		exitChan: make(chan struct{}),
 	}
 }
@@ -68,9 +75,9 @@ func (ch *chain) Halt() {
 }
 
 // Enqueue accepts a message and returns true on acceptance, or false on shutdown
-func (ch *chain) Enqueue(env *cb.Envelope) bool {
+func (ch *chain) Enqueue(env *cb.Envelope, traceID string) bool {
 	select {
-	case ch.sendChan <- env:
+	case ch.sendChan <- &tracedEnvelope{env: env, traceID: traceID, received: time.Now()}:
 		return true
 	case <-ch.exitChan:
 		return false
@@ -84,11 +91,13 @@ func (ch *chain) Errored() <-chan struct{} {
 
 func (ch *chain) main() {
 	var timer <-chan time.Time
+	heartbeat := ch.heartbeatTimer()
 
 	for {
 		select {
 		case msg := <-ch.sendChan:
-			batches, committers, ok, _ := ch.support.BlockCutter().Ordered(msg)
+			ch.support.Trace(msg.env, msg.traceID, msg.received)
+			batches, committers, ok, _ := ch.support.BlockCutter().Ordered(msg.env)
 			if ok && len(batches) == 0 && timer == nil {
 				timer = time.After(ch.support.SharedConfig().BatchTimeout())
 				continue
@@ -99,6 +108,7 @@ func (ch *chain) main() {
 			}
 			if len(batches) > 0 {
 				timer = nil
+				heartbeat = ch.heartbeatTimer()
 			}
 		case <-timer:
 			//clear the timer
@@ -112,9 +122,34 @@ func (ch *chain) main() {
 			logger.Debugf("Batch timer expired, creating block")
 			block := ch.support.CreateNextBlock(batch)
 			ch.support.WriteBlock(block, committers, nil)
+			heartbeat = ch.heartbeatTimer()
+		case <-heartbeat:
+			// The pending requests, if any, are cut along with the heartbeat
+			timer = nil
+
+			batch, committers := ch.support.BlockCutter().Cut()
+			logger.Debugf("Block schedule due, creating block with %d messages", len(batch))
+			block := ch.support.CreateNextBlock(batch)
+			ch.support.WriteBlock(block, committers, nil)
+			heartbeat = ch.heartbeatTimer()
 		case <-ch.exitChan:
 			logger.Debugf("Exiting")
 			return
 		}
 	}
 }
+
+// heartbeatTimer starts the timer of the block due by the block schedule of
+// the chain, counting from now as a block was just cut, or returns nil if the
+// chain has no schedule or lacks the BlockSchedule capability
+func (ch *chain) heartbeatTimer() <-chan time.Time {
+	if !ch.support.SharedConfig().HasCapability(config.BlockScheduleCapability) {
+		return nil
+	}
+	now := time.Now()
+	next := ch.support.SharedConfig().BlockSchedule().NextHeartbeat(now)
+	if next.IsZero() {
+		return nil
+	}
+	return time.After(next.Sub(now))
+}
@@ -20,6 +20,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/hyperledger/fabric/common/config"
 	mockconfig "github.com/hyperledger/fabric/common/mocks/config"
 	mockblockcutter "github.com/hyperledger/fabric/orderer/mocks/blockcutter"
 	mockmultichain "github.com/hyperledger/fabric/orderer/mocks/multichain"
@@ -36,7 +37,7 @@ func init() {
 var testMessage = &cb.Envelope{Payload: []byte("TEST_MESSAGE")}
 
 func syncQueueMessage(msg *cb.Envelope, chain *chain, bc *mockblockcutter.Receiver) {
-	chain.Enqueue(msg)
+	chain.Enqueue(msg, "")
 	bc.Block <- struct{}{}
 }
 
@@ -90,7 +91,7 @@ func TestStart(t *testing.T) {
 	defer bs.Halt()
 
 	support.BlockCutterVal.CutNext = true
-	bs.Enqueue(testMessage)
+	bs.Enqueue(testMessage, "")
 	select {
 	case <-support.Blocks:
 	case <-bs.Errored():
@@ -98,6 +99,27 @@ func TestStart(t *testing.T) {
 	}
 }
 
+func TestTraceID(t *testing.T) {
+	support := &mockmultichain.ConsenterSupport{
+		Blocks:          make(chan *cb.Block),
+		BlockCutterVal:  mockblockcutter.NewReceiver(),
+		SharedConfigVal: &mockconfig.Orderer{BatchTimeoutVal: time.Hour},
+	}
+	close(support.BlockCutterVal.Block)
+	bs, _ := New().HandleChain(support, nil)
+	bs.Start()
+	defer bs.Halt()
+
+	support.BlockCutterVal.CutNext = true
+	before := time.Now()
+	bs.Enqueue(testMessage, "trace1")
+	<-support.Blocks
+	assert.Equal(t, []string{"trace1"}, support.TraceIDsVal, "Should have traced the message before ordering it")
+	if assert.Len(t, support.ReceivedVal, 1) {
+		assert.False(t, support.ReceivedVal[0].Before(before), "Should have recorded the time the message was enqueued")
+	}
+}
+
 func TestEnqueueAfterHalt(t *testing.T) {
 	batchTimeout, _ := time.ParseDuration("1ms")
 	support := &mockmultichain.ConsenterSupport{
@@ -108,7 +130,7 @@ func TestEnqueueAfterHalt(t *testing.T) {
 	defer close(support.BlockCutterVal.Block)
 	bs := newChain(support)
 	bs.Halt()
-	assert.False(t, bs.Enqueue(testMessage), "Enqueue should not be accepted after halt")
+	assert.False(t, bs.Enqueue(testMessage, ""), "Enqueue should not be accepted after halt")
 	select {
 	case <-bs.Errored():
 	default:
@@ -159,6 +181,67 @@ func TestBatchTimer(t *testing.T) {
 	}
 }
 
+func TestBlockSchedule(t *testing.T) {
+	support := &mockmultichain.ConsenterSupport{
+		Blocks:         make(chan *cb.Block),
+		BlockCutterVal: mockblockcutter.NewReceiver(),
+		SharedConfigVal: &mockconfig.Orderer{
+			BatchTimeoutVal:  time.Hour,
+			BlockScheduleVal: config.BlockSchedule{MaxInterval: 10 * time.Millisecond},
+			CapabilitiesVal:  []string{config.BlockScheduleCapability},
+		},
+	}
+	defer close(support.BlockCutterVal.Block)
+	bs := newChain(support)
+	wg := goWithWait(bs.main)
+	defer bs.Halt()
+
+	select {
+	case block := <-support.Blocks:
+		assert.Empty(t, block.Data.Data, "Expected an empty heartbeat block")
+	case <-time.After(time.Second):
+		t.Fatalf("Expected a block to be cut because of the block schedule but did not")
+	}
+
+	syncQueueMessage(testMessage, bs, support.BlockCutterVal)
+	timeout := time.After(time.Second)
+	for cut := false; !cut; {
+		select {
+		case block := <-support.Blocks:
+			// Skip the heartbeats which may have raced the message
+			cut = len(block.Data.Data) == 1
+		case <-timeout:
+			t.Fatalf("Expected the pending message to be cut because of the block schedule but it was not")
+		}
+	}
+
+	bs.Halt()
+	<-wg.done
+}
+
+func TestBlockScheduleWithoutCapability(t *testing.T) {
+	support := &mockmultichain.ConsenterSupport{
+		Blocks:         make(chan *cb.Block),
+		BlockCutterVal: mockblockcutter.NewReceiver(),
+		SharedConfigVal: &mockconfig.Orderer{
+			BatchTimeoutVal:  time.Hour,
+			BlockScheduleVal: config.BlockSchedule{MaxInterval: 10 * time.Millisecond},
+		},
+	}
+	defer close(support.BlockCutterVal.Block)
+	bs := newChain(support)
+	wg := goWithWait(bs.main)
+
+	select {
+	case <-support.Blocks:
+		t.Fatalf("Expected no block to be cut by the block schedule without the BlockSchedule capability")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	bs.Halt()
+	<-wg.done
+}
+
 func TestBatchTimerHaltOnFilledBatch(t *testing.T) {
 	batchTimeout, _ := time.ParseDuration("1h")
 	support := &mockmultichain.ConsenterSupport{
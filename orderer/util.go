@@ -21,6 +21,8 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/hyperledger/fabric/bccsp/factory"
+	"github.com/hyperledger/fabric/common/crypto/keyring"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage/fsblkstorage"
 	"github.com/hyperledger/fabric/orderer/ledger"
 	fileledger "github.com/hyperledger/fabric/orderer/ledger/file"
@@ -39,7 +41,13 @@ func createLedgerFactory(conf *config.TopLevel) (ledger.Factory, string) {
 			ld = createTempDir(conf.FileLedger.Prefix)
 		}
 		logger.Debug("Ledger dir:", ld)
-		lf = fileledger.New(ld)
+		lf = fileledger.NewWithOptions(ld, fileledger.Options{
+			TxIndex:        conf.FileLedger.TxIndex,
+			ReadAhead:      uint64(conf.FileLedger.ReadAhead),
+			Keyring:        loadKeyring(conf.FileLedger.Keyring),
+			IndexShardSize: conf.FileLedger.IndexShardSize,
+			MmapReads:      conf.FileLedger.MmapReads,
+		})
 		// The file-based ledger stores the blocks for each channel
 		// in a fsblkstorage.ChainsDir sub-directory that we have
 		// to create separately. Otherwise the call to the ledger
@@ -60,6 +68,20 @@ func createLedgerFactory(conf *config.TopLevel) (ledger.Factory, string) {
 	return lf, ld
 }
 
+// loadKeyring loads the keys encrypting the file ledger from the BCCSP of the
+// local MSP, or returns nil if the ledger is not encrypted
+func loadKeyring(path string) *keyring.Keyring {
+	if path == "" {
+		return nil
+	}
+	kr, err := keyring.Load(path, factory.GetDefault())
+	if err != nil {
+		logger.Panic("Error loading the ledger keyring:", err)
+	}
+	logger.Infof("Encrypting the ledger with key %s", kr.ActiveKeyID())
+	return kr
+}
+
 func createTempDir(dirPrefix string) string {
 	dirPath, err := ioutil.TempDir("", dirPrefix)
 	if err != nil {
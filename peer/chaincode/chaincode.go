@@ -76,6 +76,11 @@ var (
 	orderingEndpoint  string
 	tls               bool
 	caFile            string
+	exportFile        string
+	proposalFile      string
+	transactionFile   string
+	signatureFile     string
+	detached          bool
 )
 
 var chaincodeCmd = &cobra.Command{
@@ -116,6 +121,16 @@ func resetFlags() {
 		fmt.Sprint("The name of the endorsement system chaincode to be used for this chaincode"))
 	flags.StringVarP(&vscc, "vscc", "V", common.UndefinedParamValue,
 		fmt.Sprint("The name of the verification system chaincode to be used for this chaincode"))
+	flags.StringVarP(&exportFile, "export", "", common.UndefinedParamValue,
+		fmt.Sprint("File to write the payload to sign offline to, instead of signing it"))
+	flags.StringVarP(&proposalFile, "proposal", "", common.UndefinedParamValue,
+		fmt.Sprint("Payload of the proposal exported previously, signed offline"))
+	flags.StringVarP(&transactionFile, "transaction", "", common.UndefinedParamValue,
+		fmt.Sprint("Payload of the transaction exported previously, signed offline"))
+	flags.StringVarP(&signatureFile, "signature", "", common.UndefinedParamValue,
+		fmt.Sprint("File containing the detached signature of the payload signed offline, or of the package to install"))
+	flags.BoolVarP(&detached, "detached", "", false,
+		fmt.Sprint("Write a detached signature of the package to the output file instead of a signed package"))
 }
 
 func attachFlags(cmd *cobra.Command, names []string) {
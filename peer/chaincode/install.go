@@ -60,6 +60,7 @@ func installCmd(cf *ChaincodeCmdFactory) *cobra.Command {
 		"path",
 		"name",
 		"version",
+		"signature",
 	}
 	attachFlags(chaincodeInstallCmd, flagList)
 
@@ -73,7 +74,17 @@ func install(msg proto.Message, cf *ChaincodeCmdFactory) error {
 		return fmt.Errorf("Error serializing identity for %s: %s", cf.Signer.GetIdentifier(), err)
 	}
 
-	prop, _, err := utils.CreateInstallProposalFromCDS(msg, creator)
+	var prop *pb.Proposal
+	if signatureFile != common.UndefinedParamValue {
+		//send the detached signature of the package written by "signpackage --detached"
+		signature, err := ioutil.ReadFile(signatureFile)
+		if err != nil {
+			return fmt.Errorf("Error reading the signature of the package: %s", err)
+		}
+		prop, _, err = utils.CreateInstallProposalFromCDSWithSignature(msg, signature, creator)
+	} else {
+		prop, _, err = utils.CreateInstallProposalFromCDS(msg, creator)
+	}
 	if err != nil {
 		return fmt.Errorf("Error creating proposal  %s: %s", chainFuncName, err)
 	}
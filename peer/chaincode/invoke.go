@@ -19,6 +19,7 @@ package chaincode
 import (
 	"fmt"
 
+	"github.com/hyperledger/fabric/peer/common"
 	"github.com/spf13/cobra"
 )
 
@@ -29,7 +30,7 @@ func invokeCmd(cf *ChaincodeCmdFactory) *cobra.Command {
 	chaincodeInvokeCmd = &cobra.Command{
 		Use:       "invoke",
 		Short:     fmt.Sprintf("Invoke the specified %s.", chainFuncName),
-		Long:      fmt.Sprintf("Invoke the specified %s. It will try to commit the endorsed transaction to the network.", chainFuncName),
+		Long:      fmt.Sprintf("Invoke the specified %s. It will try to commit the endorsed transaction to the network. With offline signing, exports the proposal and the transaction to sign in turn with '--export', and commits the transaction signed offline with '--transaction'.", chainFuncName),
 		ValidArgs: []string{"1"},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return chaincodeInvoke(cmd, args, cf)
@@ -39,6 +40,10 @@ func invokeCmd(cf *ChaincodeCmdFactory) *cobra.Command {
 		"name",
 		"ctor",
 		"channelID",
+		"export",
+		"proposal",
+		"transaction",
+		"signature",
 	}
 	attachFlags(chaincodeInvokeCmd, flagList)
 
@@ -46,6 +51,10 @@ func invokeCmd(cf *ChaincodeCmdFactory) *cobra.Command {
 }
 
 func chaincodeInvoke(cmd *cobra.Command, args []string, cf *ChaincodeCmdFactory) error {
+	if exportFile != common.UndefinedParamValue || proposalFile != common.UndefinedParamValue || transactionFile != common.UndefinedParamValue {
+		return chaincodeInvokeOffline(cmd, cf)
+	}
+
 	var err error
 	if cf == nil {
 		cf, err = InitCmdFactory(true, true)
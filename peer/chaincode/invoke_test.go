@@ -20,6 +20,9 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/hyperledger/fabric/common/flogging"
@@ -246,3 +249,70 @@ func createCIS() *pb.ChaincodeInvocationSpec {
 			ChaincodeId: &pb.ChaincodeID{Name: "chaincode_name"},
 			Input:       &pb.ChaincodeInput{Args: [][]byte{[]byte("arg1"), []byte("arg2")}}}}
 }
+
+func TestInvokeCmdOffline(t *testing.T) {
+	InitMSP()
+	mockCF, err := getMockChaincodeCmdFactory()
+	assert.NoError(t, err, "Error getting mock chaincode command factory")
+	creator, err := mockCF.Signer.Serialize()
+	assert.NoError(t, err)
+
+	getOfflineCreator := common.GetOfflineCreatorFnc
+	defer func() {
+		common.GetOfflineCreatorFnc = getOfflineCreator
+		resetFlags()
+	}()
+	common.GetOfflineCreatorFnc = func() ([]byte, error) {
+		return creator, nil
+	}
+
+	dir, err := ioutil.TempDir("", "offline")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// sign stands for the offline signer, which writes the detached signature
+	// of the exported file
+	sign := func(file string) string {
+		payload, err := ioutil.ReadFile(file)
+		assert.NoError(t, err)
+		signature, err := mockCF.Signer.Sign(payload)
+		assert.NoError(t, err)
+		assert.NoError(t, ioutil.WriteFile(file+".sig", signature, 0644))
+		return file + ".sig"
+	}
+	invoke := func(args ...string) error {
+		resetFlags()
+		cmd := invokeCmd(mockCF)
+		addFlags(cmd)
+		cmd.SetArgs(args)
+		return cmd.Execute()
+	}
+
+	propFile := filepath.Join(dir, "proposal")
+	err = invoke("-n", "example02", "-c", "{\"Args\": [\"invoke\",\"a\",\"b\",\"10\"]}", "--export", propFile)
+	assert.NoError(t, err, "Run chaincode invoke cmd error")
+	propBytes, err := ioutil.ReadFile(propFile)
+	assert.NoError(t, err)
+	prop, err := utils.GetProposal(propBytes)
+	assert.NoError(t, err, "Expected the proposal to be exported")
+	hdr, err := utils.GetHeader(prop.Header)
+	assert.NoError(t, err)
+	shdr, err := utils.GetSignatureHeader(hdr.SignatureHeader)
+	assert.NoError(t, err)
+	assert.Equal(t, creator, shdr.Creator)
+
+	txFile := filepath.Join(dir, "transaction")
+	assert.Error(t, invoke("--proposal", propFile, "--export", txFile), "Expected error without signature")
+	assert.Error(t, invoke("--proposal", propFile, "--transaction", txFile), "Expected error with both proposal and transaction")
+	propSig := sign(propFile)
+	assert.NoError(t, invoke("--proposal", propFile, "--signature", propSig, "--export", txFile))
+	payloadBytes, err := ioutil.ReadFile(txFile)
+	assert.NoError(t, err)
+	payload, err := utils.UnmarshalPayload(payloadBytes)
+	assert.NoError(t, err, "Expected the transaction to be exported")
+	_, err = utils.GetTransaction(payload.Data)
+	assert.NoError(t, err)
+
+	assert.Error(t, invoke("--transaction", txFile, "--signature", propSig), "Expected error with the signature of the proposal")
+	assert.NoError(t, invoke("--transaction", txFile, "--signature", sign(txFile)))
+}
@@ -0,0 +1,170 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/hyperledger/fabric/peer/common"
+	pcommon "github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	putils "github.com/hyperledger/fabric/protos/utils"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+)
+
+// chaincodeInvokeOffline runs the step of an invoke signed offline selected by
+// the flags:
+//  1. '-n mycc -c {...} --export prop.req' exports the proposal
+//  2. '--proposal prop.req --signature prop.der --export tx.req' endorses the
+//     signed proposal, and exports the transaction
+//  3. '--transaction tx.req --signature tx.der' sends the signed transaction
+func chaincodeInvokeOffline(cmd *cobra.Command, cf *ChaincodeCmdFactory) error {
+	creator, err := common.GetOfflineCreatorFnc()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case proposalFile != common.UndefinedParamValue && transactionFile != common.UndefinedParamValue:
+		return errors.New("Options --proposal and --transaction are not compatible")
+	case transactionFile != common.UndefinedParamValue:
+		return sendOfflineTx(creator, cf)
+	case proposalFile != common.UndefinedParamValue:
+		return endorseOfflineProposal(creator, cf)
+	default:
+		return exportProposal(cmd, creator)
+	}
+}
+
+// exportProposal exports the invoke proposal for the creator to sign offline
+func exportProposal(cmd *cobra.Command, creator []byte) error {
+	spec, err := getChaincodeSpec(cmd)
+	if err != nil {
+		return err
+	}
+
+	invocation := &pb.ChaincodeInvocationSpec{ChaincodeSpec: spec}
+	if customIDGenAlg != common.UndefinedParamValue {
+		invocation.IdGenerationAlg = customIDGenAlg
+	}
+
+	prop, txID, err := putils.CreateProposalFromCIS(pcommon.HeaderType_ENDORSER_TRANSACTION, chainID, invocation, creator)
+	if err != nil {
+		return fmt.Errorf("Error creating proposal invoke: %s", err)
+	}
+	propBytes, err := putils.GetBytesProposal(prop)
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("Exporting the proposal of transaction %s", txID)
+	return common.ExportSigningRequest(exportFile, propBytes)
+}
+
+// endorseOfflineProposal sends the proposal signed offline for endorsement,
+// and exports the endorsed transaction for the creator to sign offline
+func endorseOfflineProposal(creator []byte, cf *ChaincodeCmdFactory) error {
+	if signatureFile == common.UndefinedParamValue {
+		return errors.New("Must supply the detached signature of the proposal")
+	}
+	if exportFile == common.UndefinedParamValue {
+		return errors.New("Must supply the file to export the transaction to")
+	}
+
+	propBytes, signature, err := common.ImportDetachedSignature(proposalFile, signatureFile, creator)
+	if err != nil {
+		return err
+	}
+	signedProp, err := putils.GetSignedProposalFromSignature(propBytes, signature)
+	if err != nil {
+		return err
+	}
+	prop, err := putils.GetProposal(propBytes)
+	if err != nil {
+		return err
+	}
+	hdr, err := putils.GetHeader(prop.Header)
+	if err != nil {
+		return err
+	}
+	shdr, err := putils.GetSignatureHeader(hdr.SignatureHeader)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(shdr.Creator, creator) {
+		return errors.New("The proposal was exported for another creator")
+	}
+
+	if cf == nil {
+		// The default factory needs the private key to sign
+		endorserClient, err := common.GetEndorserClientFnc()
+		if err != nil {
+			return fmt.Errorf("Error getting endorser client %s: %s", chainFuncName, err)
+		}
+		cf = &ChaincodeCmdFactory{EndorserClient: endorserClient}
+	}
+
+	proposalResp, err := cf.EndorserClient.ProcessProposal(context.Background(), signedProp)
+	if err != nil {
+		return fmt.Errorf("Error endorsing invoke: %s", err)
+	}
+	if proposalResp == nil || proposalResp.Response == nil {
+		return errors.New("Error endorsing invoke: no response")
+	}
+	if proposalResp.Response.Status >= shim.ERROR {
+		return fmt.Errorf("Endorsement failure during invoke: %d - %s", proposalResp.Response.Status, proposalResp.Response.Message)
+	}
+
+	payload, err := putils.CreateUnsignedTx(prop, proposalResp)
+	if err != nil {
+		return fmt.Errorf("Could not assemble transaction, err %s", err)
+	}
+	payloadBytes, err := putils.GetBytesPayload(payload)
+	if err != nil {
+		return err
+	}
+	return common.ExportSigningRequest(exportFile, payloadBytes)
+}
+
+// sendOfflineTx sends the transaction signed offline for ordering
+func sendOfflineTx(creator []byte, cf *ChaincodeCmdFactory) error {
+	if signatureFile == common.UndefinedParamValue {
+		return errors.New("Must supply the detached signature of the transaction")
+	}
+
+	payloadBytes, signature, err := common.ImportDetachedSignature(transactionFile, signatureFile, creator)
+	if err != nil {
+		return err
+	}
+	env, err := putils.CreateEnvelopeFromSignature(payloadBytes, signature)
+	if err != nil {
+		return err
+	}
+
+	if cf == nil {
+		// The orderer endpoints of the channel cannot be queried without
+		// signing
+		if orderingEndpoint == "" {
+			return errors.New("Must supply the ordering service endpoint to send a transaction signed offline")
+		}
+		broadcastClient, err := common.GetBroadcastClientFnc(orderingEndpoint, tls, caFile)
+		if err != nil {
+			return fmt.Errorf("Error getting broadcast client: %s", err)
+		}
+		cf = &ChaincodeCmdFactory{BroadcastClient: broadcastClient}
+	}
+	defer cf.BroadcastClient.Close()
+
+	if err := cf.BroadcastClient.Send(env); err != nil {
+		return fmt.Errorf("Error sending transaction invoke: %s", err)
+	}
+	return nil
+}
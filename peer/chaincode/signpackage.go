@@ -23,6 +23,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/hyperledger/fabric/core/common/ccpackage"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/protos/utils"
 )
 
@@ -31,7 +32,7 @@ func signpackageCmd(cf *ChaincodeCmdFactory) *cobra.Command {
 	spCmd := &cobra.Command{
 		Use:       "signpackage",
 		Short:     "Sign the specified chaincode package",
-		Long:      "Sign the specified chaincode package",
+		Long:      "Sign the specified chaincode package, or write a detached signature of it with --detached",
 		ValidArgs: []string{"2"},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) < 2 {
@@ -40,6 +41,7 @@ func signpackageCmd(cf *ChaincodeCmdFactory) *cobra.Command {
 			return signpackage(cmd, args[0], args[1], cf)
 		},
 	}
+	attachFlags(spCmd, []string{"detached"})
 
 	return spCmd
 }
@@ -58,6 +60,10 @@ func signpackage(cmd *cobra.Command, ipackageFile string, opackageFile string, c
 		return err
 	}
 
+	if detached {
+		return writeDetachedSignature(b, opackageFile, cf)
+	}
+
 	env := utils.UnmarshalEnvelopeOrPanic(b)
 
 	env, err = ccpackage.SignExistingPackage(env, cf.Signer)
@@ -75,3 +81,26 @@ func signpackage(cmd *cobra.Command, ipackageFile string, opackageFile string, c
 
 	return nil
 }
+
+// writeDetachedSignature writes the detached signature of a package, raw or signed, to be
+// passed to "install" with --signature
+func writeDetachedSignature(b []byte, signatureFile string, cf *ChaincodeCmdFactory) error {
+	ccpack, err := ccprovider.GetCCPackage(b)
+	if err != nil {
+		return err
+	}
+
+	signature, err := ccprovider.CreateDetachedSignature(ccpack, cf.Signer)
+	if err != nil {
+		return err
+	}
+
+	err = ioutil.WriteFile(signatureFile, signature, 0700)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Wrote detached signature of the package to %s successfully\n", signatureFile)
+
+	return nil
+}
@@ -24,8 +24,10 @@ import (
 
 	"github.com/golang/protobuf/proto"
 
+	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/peer/common"
 	pcommon "github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
 )
 
 //helper to sign an existing package
@@ -132,3 +134,66 @@ func TestFailSignUnsignedPackage(t *testing.T) {
 		t.Fatalf("expected signing a package that's not originally signed to fail")
 	}
 }
+
+// TestSignPackageDetached writes the detached signature of a package and installs the package with it
+func TestSignPackageDetached(t *testing.T) {
+	defer resetFlags()
+	pdir := newTempDir()
+	defer os.RemoveAll(pdir)
+
+	ccpackfile := pdir + "/ccpack.file"
+	err := createSignedCDSPackage([]string{"-n", "somecc", "-p", "some/go/package", "-v", "0", ccpackfile}, false)
+	if err != nil {
+		t.Fatalf("could not create package :%v", err)
+	}
+
+	signer, err := common.GetDefaultSigner()
+	if err != nil {
+		t.Fatalf("Get default signer error: %v", err)
+	}
+	cmd := signpackageCmd(&ChaincodeCmdFactory{Signer: signer})
+	addFlags(cmd)
+	sigfile := pdir + "/ccpack.sig"
+	cmd.SetArgs([]string{"--detached", ccpackfile, sigfile})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("could not write the detached signature: %v", err)
+	}
+
+	b, err := ioutil.ReadFile(ccpackfile)
+	if err != nil {
+		t.Fatalf("package file %s not created", ccpackfile)
+	}
+	ccpack, err := ccprovider.GetCCPackage(b)
+	if err != nil {
+		t.Fatalf("could not read package: %v", err)
+	}
+	signature, err := ioutil.ReadFile(sigfile)
+	if err != nil {
+		t.Fatalf("signature file %s not created", sigfile)
+	}
+	sd, err := ccprovider.GetDetachedSignatureSignedData(ccpack, signature)
+	if err != nil {
+		t.Fatalf("could not read the detached signature: %v", err)
+	}
+	if err := signer.Verify(sd[0].Data, sd[0].Signature); err != nil {
+		t.Fatalf("invalid detached signature: %v", err)
+	}
+
+	fsPath := "/tmp/installtest"
+	installCmd, mockCF := initInstallTest(fsPath, t)
+	defer finitInstallTest(fsPath)
+	mockCF.EndorserClient = common.GetMockEndorserClient(&pb.ProposalResponse{
+		Response:    &pb.Response{Status: 200},
+		Endorsement: &pb.Endorsement{},
+	}, nil)
+
+	installCmd.SetArgs([]string{"--signature", sigfile, ccpackfile})
+	if err := installCmd.Execute(); err != nil {
+		t.Fatalf("error executing install command with signature: %v", err)
+	}
+
+	installCmd.SetArgs([]string{"--signature", pdir + "/missing.sig", ccpackfile})
+	if err := installCmd.Execute(); err == nil {
+		t.Fatal("expected install to fail without signature file")
+	}
+}
@@ -34,8 +34,8 @@ import (
 
 const (
 	channelFuncName = "channel"
-	shortDes        = "Operate a channel: create|fetch|join|list|update."
-	longDes         = "Operate a channel: create|fetch|join|list|update."
+	shortDes        = "Operate a channel: create|fetch|join|list|update|update-anchor-peers|compare|export."
+	longDes         = "Operate a channel: create|fetch|join|list|update|update-anchor-peers|compare|export."
 )
 
 var logger = flogging.MustGetLogger("channelCmd")
@@ -61,6 +61,19 @@ var (
 	tls              bool
 	caFile           string
 	timeout          int
+
+	// update-anchor-peers related variables
+	anchorPeers []string
+
+	// compare and export related variables
+	startBlock int64
+	endBlock   int64
+
+	// offline signing related variables
+	exportFile          string
+	configSignatureFile string
+	transactionFile     string
+	signatureFile       string
 )
 
 // Cmd returns the cobra command for Node
@@ -72,6 +85,9 @@ func Cmd(cf *ChannelCmdFactory) *cobra.Command {
 	channelCmd.AddCommand(joinCmd(cf))
 	channelCmd.AddCommand(listCmd(cf))
 	channelCmd.AddCommand(updateCmd(cf))
+	channelCmd.AddCommand(updateAnchorPeersCmd(cf))
+	channelCmd.AddCommand(compareCmd(cf))
+	channelCmd.AddCommand(exportCmd(cf))
 
 	return channelCmd
 }
@@ -99,6 +115,13 @@ func resetFlags() {
 	flags.StringVarP(&chainID, "channelID", "c", common.UndefinedParamValue, "In case of a newChain command, the channel ID to create.")
 	flags.StringVarP(&channelTxFile, "file", "f", "", "Configuration transaction file generated by a tool such as configtxgen for submitting to orderer")
 	flags.IntVarP(&timeout, "timeout", "t", 5, "Channel creation timeout")
+	flags.StringSliceVarP(&anchorPeers, "anchorPeers", "", nil, "Comma separated host:port endpoints of the anchor peers of the organization")
+	flags.Int64VarP(&startBlock, "startBlock", "", 0, "Number of the first block to compare or export")
+	flags.Int64VarP(&endBlock, "endBlock", "", -1, "Number of the last block to compare or export, the last block of the (shortest) ledger if negative")
+	flags.StringVarP(&exportFile, "export", "", "", "File to write the payload to sign offline to, instead of signing it")
+	flags.StringVarP(&configSignatureFile, "configSignature", "", "", "Payload of the config update signature exported previously, signed offline")
+	flags.StringVarP(&transactionFile, "transaction", "", "", "Payload of the transaction exported previously, signed offline")
+	flags.StringVarP(&signatureFile, "signature", "", "", "File containing the detached signature of the payload signed offline")
 }
 
 func attachFlags(cmd *cobra.Command, names []string) {
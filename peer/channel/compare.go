@@ -0,0 +1,395 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/hyperledger/fabric/core/peer"
+	"github.com/hyperledger/fabric/core/scc/qscc"
+	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/peer/common"
+	cb "github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+)
+
+// newEndorserClient returns an endorser client connected to the peer at the address, it is a
+// variable to facilitate tests
+var newEndorserClient = func(address string) (pb.EndorserClient, error) {
+	conn, err := peer.NewPeerClientConnectionWithAddress(address)
+	if err != nil {
+		return nil, fmt.Errorf("Error connecting to peer %s: %s", address, err)
+	}
+	return pb.NewEndorserClient(conn), nil
+}
+
+func compareCmd(cf *ChannelCmdFactory) *cobra.Command {
+	compareCmd := &cobra.Command{
+		Use:   "compare <peer address> [peer address]",
+		Short: "Compare the blocks of a channel on two peers.",
+		Long: "Compare the blocks of a channel on two peers, or on a peer and the ordering service if a single peer is given, " +
+			"and report the first block where they diverge: in their bytes, the validation codes of their transactions, or the state updates they result in.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return compare(cmd, args, cf)
+		},
+	}
+	flagList := []string{
+		"channelID",
+		"startBlock",
+		"endBlock",
+	}
+	attachFlags(compareCmd, flagList)
+
+	return compareCmd
+}
+
+// blockSource is the ledger of the channel on a peer or on the ordering service
+type blockSource interface {
+	name() string
+	height() (uint64, error)
+	block(num uint64) (*cb.Block, error)
+}
+
+// peerBlockSource retrieves the blocks committed by a peer through its query system chaincode
+type peerBlockSource struct {
+	address string
+	client  pb.EndorserClient
+	signer  msp.SigningIdentity
+}
+
+func (ps *peerBlockSource) name() string {
+	return "peer " + ps.address
+}
+
+func (ps *peerBlockSource) query(fname string, args ...string) ([]byte, error) {
+	input := [][]byte{[]byte(fname), []byte(chainID)}
+	for _, arg := range args {
+		input = append(input, []byte(arg))
+	}
+	invocation := &pb.ChaincodeInvocationSpec{
+		ChaincodeSpec: &pb.ChaincodeSpec{
+			Type:        pb.ChaincodeSpec_Type(pb.ChaincodeSpec_Type_value["GOLANG"]),
+			ChaincodeId: &pb.ChaincodeID{Name: "qscc"},
+			Input:       &pb.ChaincodeInput{Args: input},
+		},
+	}
+
+	creator, err := ps.signer.Serialize()
+	if err != nil {
+		return nil, fmt.Errorf("Error serializing identity for %s: %s", ps.signer.GetIdentifier(), err)
+	}
+	prop, _, err := utils.CreateProposalFromCIS(cb.HeaderType_ENDORSER_TRANSACTION, "", invocation, creator)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot create proposal, due to %s", err)
+	}
+	signedProp, err := utils.GetSignedProposal(prop, ps.signer)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot create signed proposal, due to %s", err)
+	}
+
+	proposalResp, err := ps.client.ProcessProposal(context.Background(), signedProp)
+	if err != nil {
+		return nil, fmt.Errorf("Failed sending %s proposal to %s, got %s", fname, ps.name(), err)
+	}
+	if proposalResp.Response == nil || proposalResp.Response.Status != 200 {
+		return nil, fmt.Errorf("Received bad %s response from %s: %v", fname, ps.name(), proposalResp.Response)
+	}
+	return proposalResp.Response.Payload, nil
+}
+
+func (ps *peerBlockSource) height() (uint64, error) {
+	payload, err := ps.query(qscc.GetChainInfo)
+	if err != nil {
+		return 0, err
+	}
+	info := &cb.BlockchainInfo{}
+	if err := proto.Unmarshal(payload, info); err != nil {
+		return 0, fmt.Errorf("Cannot read chain info response of %s, %s", ps.name(), err)
+	}
+	return info.Height, nil
+}
+
+func (ps *peerBlockSource) block(num uint64) (*cb.Block, error) {
+	payload, err := ps.query(qscc.GetBlockByNumber, strconv.FormatUint(num, 10))
+	if err != nil {
+		return nil, err
+	}
+	return utils.GetBlockFromBlockBytes(payload)
+}
+
+// ordererBlockSource retrieves the blocks of the ordering service through its deliver service
+type ordererBlockSource struct {
+	client deliverClientIntf
+}
+
+func (obs *ordererBlockSource) name() string {
+	return "orderer " + orderingEndpoint
+}
+
+func (obs *ordererBlockSource) height() (uint64, error) {
+	block, err := obs.client.getNewestBlock()
+	if err != nil {
+		return 0, err
+	}
+	return block.Header.Number + 1, nil
+}
+
+func (obs *ordererBlockSource) block(num uint64) (*cb.Block, error) {
+	return obs.client.getSpecifiedBlock(num)
+}
+
+// divergence describes the first block where two ledgers diverge
+type divergence struct {
+	blockNumber uint64
+	// txIndex is the index of the first transaction which diverges, or -1 if the divergence
+	// is not specific to a transaction
+	txIndex int
+	reason  string
+}
+
+func (d *divergence) String() string {
+	if d.txIndex < 0 {
+		return fmt.Sprintf("block %d: %s", d.blockNumber, d.reason)
+	}
+	return fmt.Sprintf("block %d, transaction %d: %s", d.blockNumber, d.txIndex, d.reason)
+}
+
+// ledgerReplay replays the blocks of a ledger, in order to compute the state hash of the
+// ledger, i.e. the hash chaining the writes of the valid transactions of its blocks
+type ledgerReplay struct {
+	source    blockSource
+	stateHash []byte
+}
+
+// compareBlocks compares the blocks of the range [start, end] of the two ledgers. It returns
+// the first divergence, or nil if the blocks match
+func compareBlocks(a, b *ledgerReplay, start, end uint64) (*divergence, error) {
+	for num := start; num <= end; num++ {
+		blockA, err := a.source.block(num)
+		if err != nil {
+			return nil, fmt.Errorf("Error getting block %d from %s: %s", num, a.source.name(), err)
+		}
+		blockB, err := b.source.block(num)
+		if err != nil {
+			return nil, fmt.Errorf("Error getting block %d from %s: %s", num, b.source.name(), err)
+		}
+		if d, err := compareBlock(a, b, blockA, blockB); d != nil || err != nil {
+			return d, err
+		}
+		logger.Debugf("Block %d matches, state hash %x", num, a.stateHash)
+	}
+	return nil, nil
+}
+
+func compareBlock(a, b *ledgerReplay, blockA, blockB *cb.Block) (*divergence, error) {
+	num := blockA.Header.Number
+	if !proto.Equal(blockA.Header, blockB.Header) {
+		return &divergence{blockNumber: num, txIndex: -1, reason: fmt.Sprintf("the block headers differ, hash %x on %s and %x on %s",
+			blockA.Header.Hash(), a.source.name(), blockB.Header.Hash(), b.source.name())}, nil
+	}
+	txsA, txsB := blockA.Data.Data, blockB.Data.Data
+	for i := 0; i < len(txsA) && i < len(txsB); i++ {
+		if !bytes.Equal(txsA[i], txsB[i]) {
+			return &divergence{blockNumber: num, txIndex: i, reason: "the transactions differ"}, nil
+		}
+	}
+	if len(txsA) != len(txsB) {
+		return &divergence{blockNumber: num, txIndex: -1, reason: fmt.Sprintf("the blocks hold %d transactions on %s and %d on %s",
+			len(txsA), a.source.name(), len(txsB), b.source.name())}, nil
+	}
+
+	// the blocks of the ordering service are not validated, only those of the peers carry
+	// the validation codes of their transactions
+	flagsA, validatedA := validationFlags(blockA)
+	flagsB, validatedB := validationFlags(blockB)
+	if !validatedA || !validatedB {
+		return nil, nil
+	}
+	for i := range txsA {
+		if flagsA.Flag(i) != flagsB.Flag(i) {
+			return &divergence{blockNumber: num, txIndex: i, reason: fmt.Sprintf("the transaction is %s on %s and %s on %s",
+				flagsA.Flag(i), a.source.name(), flagsB.Flag(i), b.source.name())}, nil
+		}
+	}
+
+	var err error
+	if a.stateHash, err = nextStateHash(a.stateHash, blockA, flagsA); err != nil {
+		return nil, fmt.Errorf("Error replaying block %d of %s: %s", num, a.source.name(), err)
+	}
+	if b.stateHash, err = nextStateHash(b.stateHash, blockB, flagsB); err != nil {
+		return nil, fmt.Errorf("Error replaying block %d of %s: %s", num, b.source.name(), err)
+	}
+	if !bytes.Equal(a.stateHash, b.stateHash) {
+		return &divergence{blockNumber: num, txIndex: -1, reason: fmt.Sprintf("the state hashes differ, %x on %s and %x on %s",
+			a.stateHash, a.source.name(), b.stateHash, b.source.name())}, nil
+	}
+	return nil, nil
+}
+
+func validationFlags(block *cb.Block) (util.TxValidationFlags, bool) {
+	if block.Metadata == nil || len(block.Metadata.Metadata) <= int(cb.BlockMetadataIndex_TRANSACTIONS_FILTER) {
+		return nil, false
+	}
+	flags := util.TxValidationFlags(block.Metadata.Metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	return flags, len(flags) == len(block.Data.Data)
+}
+
+// nextStateHash chains the state hash with the writes of the valid endorser transactions of the block
+func nextStateHash(stateHash []byte, block *cb.Block, flags util.TxValidationFlags) ([]byte, error) {
+	h := sha256.New()
+	h.Write(stateHash)
+	for i, envBytes := range block.Data.Data {
+		if !flags.IsValid(i) {
+			continue
+		}
+		env, err := utils.GetEnvelopeFromBlock(envBytes)
+		if err != nil {
+			return nil, err
+		}
+		payload, err := utils.GetPayload(env)
+		if err != nil {
+			return nil, err
+		}
+		chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+		if err != nil {
+			return nil, err
+		}
+		if cb.HeaderType(chdr.Type) != cb.HeaderType_ENDORSER_TRANSACTION {
+			continue
+		}
+		action, err := utils.GetActionFromEnvelope(envBytes)
+		if err != nil {
+			return nil, err
+		}
+		txRWSet := &rwsetutil.TxRwSet{}
+		if err := txRWSet.FromProtoBytes(action.Results); err != nil {
+			return nil, err
+		}
+		for _, nsRWSet := range txRWSet.NsRwSets {
+			for _, write := range nsRWSet.KvRwSet.Writes {
+				writeBytes, err := proto.Marshal(write)
+				if err != nil {
+					return nil, err
+				}
+				writeLengthPrefixed(h, []byte(nsRWSet.NameSpace))
+				writeLengthPrefixed(h, writeBytes)
+			}
+		}
+	}
+	return h.Sum(nil), nil
+}
+
+func writeLengthPrefixed(w io.Writer, b []byte) {
+	length := make([]byte, 8)
+	binary.BigEndian.PutUint64(length, uint64(len(b)))
+	w.Write(length)
+	w.Write(b)
+}
+
+func compare(cmd *cobra.Command, args []string, cf *ChannelCmdFactory) error {
+	if chainID == common.UndefinedParamValue {
+		return errors.New("Must supply channel ID")
+	}
+	if len(args) == 0 {
+		return errors.New("Must supply the address of the peer to compare")
+	}
+	if len(args) > 2 {
+		return errors.New("trailing args detected")
+	}
+
+	var err error
+	if cf == nil {
+		cf, err = InitCmdFactory(EndorserNotRequired, OrdererRequirement(len(args) == 1))
+		if err != nil {
+			return err
+		}
+	}
+
+	var sources []blockSource
+	for _, address := range args {
+		client, err := newEndorserClient(address)
+		if err != nil {
+			return err
+		}
+		sources = append(sources, &peerBlockSource{address: address, client: client, signer: cf.Signer})
+	}
+	if len(args) == 1 {
+		defer cf.DeliverClient.Close()
+		sources = append(sources, &ordererBlockSource{client: cf.DeliverClient})
+	}
+
+	start, end, err := compareRange(sources[0], sources[1])
+	if err != nil {
+		return err
+	}
+	a, b := &ledgerReplay{source: sources[0]}, &ledgerReplay{source: sources[1]}
+	d, err := compareBlocks(a, b, start, end)
+	if err != nil {
+		return err
+	}
+	if d != nil {
+		return fmt.Errorf("%s and %s diverge at %s", a.source.name(), b.source.name(), d)
+	}
+	fmt.Printf("Blocks [%d, %d] of channel %s match on %s and %s\n", start, end, chainID, a.source.name(), b.source.name())
+	if start == 0 && a.stateHash != nil {
+		fmt.Printf("State hash: %x\n", a.stateHash)
+	}
+	return nil
+}
+
+// compareRange returns the range of the blocks to compare, which ends by default with the
+// last block of the shortest ledger. The state hash is computed from the first block, so
+// it is only reported if the range starts with the genesis block
+func compareRange(a, b blockSource) (uint64, uint64, error) {
+	heightA, err := a.height()
+	if err != nil {
+		return 0, 0, fmt.Errorf("Error getting the height of %s: %s", a.name(), err)
+	}
+	heightB, err := b.height()
+	if err != nil {
+		return 0, 0, fmt.Errorf("Error getting the height of %s: %s", b.name(), err)
+	}
+	if heightA != heightB {
+		logger.Warningf("The ledger of %s is %d blocks high and the one of %s %d blocks high", a.name(), heightA, b.name(), heightB)
+	}
+	height := heightA
+	if heightB < height {
+		height = heightB
+	}
+	return blockRange(height)
+}
+
+// blockRange returns the range of blocks given by the startBlock and endBlock flags, within a
+// ledger of the given height. The range ends by default with the last block of the ledger
+func blockRange(height uint64) (uint64, uint64, error) {
+	if startBlock < 0 {
+		return 0, 0, fmt.Errorf("Invalid start block %d", startBlock)
+	}
+	end := int64(height) - 1
+	if endBlock >= 0 {
+		if endBlock >= int64(height) {
+			return 0, 0, fmt.Errorf("End block %d is beyond the height %d of the ledger", endBlock, height)
+		}
+		end = endBlock
+	}
+	if startBlock > end {
+		return 0, 0, fmt.Errorf("Start block %d is beyond the end block %d", startBlock, end)
+	}
+	return uint64(startBlock), uint64(end), nil
+}
@@ -0,0 +1,187 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/hyperledger/fabric/peer/common"
+	cb "github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockBlockSource struct {
+	blocks []*cb.Block
+}
+
+func (ms *mockBlockSource) name() string {
+	return "mock"
+}
+
+func (ms *mockBlockSource) height() (uint64, error) {
+	return uint64(len(ms.blocks)), nil
+}
+
+func (ms *mockBlockSource) block(num uint64) (*cb.Block, error) {
+	if num >= uint64(len(ms.blocks)) {
+		return nil, fmt.Errorf("block %d not found", num)
+	}
+	return ms.blocks[num], nil
+}
+
+// endorserTx returns an endorser transaction writing the value of the key
+func endorserTx(t *testing.T, key, value string) []byte {
+	builder := rwsetutil.NewRWSetBuilder()
+	builder.AddToWriteSet("mycc", key, []byte(value))
+	results, err := builder.GetTxReadWriteSet().ToProtoBytes()
+	assert.NoError(t, err)
+	prp, err := utils.GetBytesProposalResponsePayload([]byte("hash"), &pb.Response{Status: 200}, results, nil, &pb.ChaincodeID{Name: "mycc"})
+	assert.NoError(t, err)
+	capBytes, err := utils.GetBytesChaincodeActionPayload(&pb.ChaincodeActionPayload{Action: &pb.ChaincodeEndorsedAction{ProposalResponsePayload: prp}})
+	assert.NoError(t, err)
+	txBytes, err := utils.GetBytesTransaction(&pb.Transaction{Actions: []*pb.TransactionAction{{Payload: capBytes}}})
+	assert.NoError(t, err)
+	payload := &cb.Payload{
+		Header: utils.MakePayloadHeader(utils.MakeChannelHeader(cb.HeaderType_ENDORSER_TRANSACTION, 0, "mychannel", 0), &cb.SignatureHeader{}),
+		Data:   txBytes,
+	}
+	return utils.MarshalOrPanic(&cb.Envelope{Payload: utils.MarshalOrPanic(payload)})
+}
+
+// ledger returns the blocks of a ledger, each of the given transactions in its own block
+func ledger(txs [][]byte, codes []pb.TxValidationCode) []*cb.Block {
+	var blocks []*cb.Block
+	var previousHash []byte
+	for i, tx := range txs {
+		block := cb.NewBlock(uint64(i), previousHash)
+		block.Data.Data = [][]byte{tx}
+		block.Header.DataHash = block.Data.Hash()
+		if codes != nil {
+			flags := util.NewTxValidationFlags(1)
+			flags.SetFlag(0, codes[i])
+			block.Metadata.Metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER] = flags
+		}
+		previousHash = block.Header.Hash()
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
+func TestCompareBlocks(t *testing.T) {
+	txs := [][]byte{endorserTx(t, "a", "1"), endorserTx(t, "b", "2"), endorserTx(t, "a", "3")}
+	valid := []pb.TxValidationCode{pb.TxValidationCode_VALID, pb.TxValidationCode_VALID, pb.TxValidationCode_VALID}
+
+	t.Run("Match", func(t *testing.T) {
+		a := &ledgerReplay{source: &mockBlockSource{blocks: ledger(txs, valid)}}
+		b := &ledgerReplay{source: &mockBlockSource{blocks: ledger(txs, valid)}}
+		d, err := compareBlocks(a, b, 0, 2)
+		assert.NoError(t, err)
+		assert.Nil(t, d)
+		assert.NotNil(t, a.stateHash)
+		assert.Equal(t, a.stateHash, b.stateHash)
+	})
+
+	t.Run("Orderer", func(t *testing.T) {
+		a := &ledgerReplay{source: &mockBlockSource{blocks: ledger(txs, valid)}}
+		b := &ledgerReplay{source: &mockBlockSource{blocks: ledger(txs, nil)}}
+		d, err := compareBlocks(a, b, 0, 2)
+		assert.NoError(t, err)
+		assert.Nil(t, d, "Blocks without validation codes should only be compared by their bytes")
+	})
+
+	t.Run("Transactions", func(t *testing.T) {
+		a := &ledgerReplay{source: &mockBlockSource{blocks: ledger(txs, valid)}}
+		forked := ledger(txs, valid)
+		forked[1].Data.Data[0] = endorserTx(t, "b", "20")
+		b := &ledgerReplay{source: &mockBlockSource{blocks: forked}}
+		d, err := compareBlocks(a, b, 0, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, &divergence{blockNumber: 1, txIndex: 0, reason: "the transactions differ"}, d)
+	})
+
+	t.Run("Headers", func(t *testing.T) {
+		a := &ledgerReplay{source: &mockBlockSource{blocks: ledger(txs, valid)}}
+		b := &ledgerReplay{source: &mockBlockSource{blocks: ledger([][]byte{txs[0], txs[2], txs[1]}, valid)}}
+		d, err := compareBlocks(a, b, 0, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(1), d.blockNumber)
+		assert.Equal(t, -1, d.txIndex)
+		assert.Contains(t, d.reason, "the block headers differ")
+	})
+
+	t.Run("ValidationCodes", func(t *testing.T) {
+		a := &ledgerReplay{source: &mockBlockSource{blocks: ledger(txs, valid)}}
+		b := &ledgerReplay{source: &mockBlockSource{blocks: ledger(txs, []pb.TxValidationCode{pb.TxValidationCode_VALID, pb.TxValidationCode_VALID, pb.TxValidationCode_MVCC_READ_CONFLICT})}}
+		d, err := compareBlocks(a, b, 0, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(2), d.blockNumber)
+		assert.Equal(t, 0, d.txIndex)
+		assert.Contains(t, d.reason, "the transaction is VALID on mock and MVCC_READ_CONFLICT on mock")
+	})
+
+	t.Run("MissingBlock", func(t *testing.T) {
+		a := &ledgerReplay{source: &mockBlockSource{blocks: ledger(txs, valid)}}
+		b := &ledgerReplay{source: &mockBlockSource{blocks: ledger(txs[:2], valid)}}
+		_, err := compareBlocks(a, b, 0, 2)
+		assert.Error(t, err)
+	})
+}
+
+func TestCompareRange(t *testing.T) {
+	defer resetFlags()
+	long := &mockBlockSource{blocks: make([]*cb.Block, 5)}
+	short := &mockBlockSource{blocks: make([]*cb.Block, 3)}
+
+	start, end, err := compareRange(long, short)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), start)
+	assert.Equal(t, uint64(2), end, "The range should end with the last block of the shortest ledger")
+
+	startBlock, endBlock = 1, 1
+	start, end, err = compareRange(long, short)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), start)
+	assert.Equal(t, uint64(1), end)
+
+	startBlock, endBlock = 0, 3
+	_, _, err = compareRange(long, short)
+	assert.Error(t, err, "The end block should be within the shortest ledger")
+
+	startBlock, endBlock = 2, 1
+	_, _, err = compareRange(long, short)
+	assert.Error(t, err, "The start block should not be beyond the end block")
+}
+
+func TestPeerBlockSource(t *testing.T) {
+	InitMSP()
+	signer, err := common.GetDefaultSigner()
+	assert.NoError(t, err)
+
+	block := cb.NewBlock(3, []byte("previous"))
+	mockResponse := &pb.ProposalResponse{Response: &pb.Response{Status: 200, Payload: utils.MarshalOrPanic(block)}}
+	ps := &peerBlockSource{address: "peer0:7051", client: common.GetMockEndorserClient(mockResponse, nil), signer: signer}
+	b, err := ps.block(3)
+	assert.NoError(t, err)
+	assert.Equal(t, block.Header, b.Header)
+
+	mockResponse = &pb.ProposalResponse{Response: &pb.Response{Status: 200, Payload: utils.MarshalOrPanic(&cb.BlockchainInfo{Height: 4})}}
+	ps.client = common.GetMockEndorserClient(mockResponse, nil)
+	height, err := ps.height()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(4), height)
+
+	mockResponse = &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: "Invalid chain ID"}}
+	ps.client = common.GetMockEndorserClient(mockResponse, nil)
+	_, err = ps.height()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "peer peer0:7051")
+}
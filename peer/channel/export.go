@@ -0,0 +1,105 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/ledgerbundle"
+	"github.com/hyperledger/fabric/common/localmsp"
+	"github.com/hyperledger/fabric/peer/common"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func exportCmd(cf *ChannelCmdFactory) *cobra.Command {
+	exportCmd := &cobra.Command{
+		Use:   "export <outputfile>",
+		Short: "Export a range of blocks into a signed bundle.",
+		Long: "Export a range of the blocks of a channel committed by the peer, along with the last config block of the channel as of the first of them " +
+			"and the certificates of its ordering service organizations, into a bundle signed by the local MSP, which can be verified offline.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return export(cmd, args, cf)
+		},
+	}
+	flagList := []string{
+		"channelID",
+		"startBlock",
+		"endBlock",
+	}
+	attachFlags(exportCmd, flagList)
+
+	return exportCmd
+}
+
+func export(cmd *cobra.Command, args []string, cf *ChannelCmdFactory) error {
+	if chainID == common.UndefinedParamValue {
+		return errors.New("Must supply channel ID")
+	}
+	if len(args) == 0 {
+		return errors.New("Must supply the output file of the bundle")
+	}
+	if len(args) > 1 {
+		return errors.New("trailing args detected")
+	}
+
+	var err error
+	if cf == nil {
+		cf, err = InitCmdFactory(EndorserRequired, OrdererNotRequired)
+		if err != nil {
+			return err
+		}
+	}
+	source := &peerBlockSource{address: viper.GetString("peer.address"), client: cf.EndorserClient, signer: cf.Signer}
+
+	height, err := source.height()
+	if err != nil {
+		return err
+	}
+	start, end, err := blockRange(height)
+	if err != nil {
+		return err
+	}
+	var blocks []*cb.Block
+	for num := start; num <= end; num++ {
+		block, err := source.block(num)
+		if err != nil {
+			return fmt.Errorf("Error getting block %d: %s", num, err)
+		}
+		blocks = append(blocks, block)
+	}
+
+	lastConfig, err := utils.GetLastConfigIndexFromBlock(blocks[0])
+	if err != nil {
+		return fmt.Errorf("Error getting the last config of block %d: %s", start, err)
+	}
+	configBlock := blocks[0]
+	if lastConfig != start {
+		if configBlock, err = source.block(lastConfig); err != nil {
+			return fmt.Errorf("Error getting config block %d: %s", lastConfig, err)
+		}
+	}
+
+	bundle, err := ledgerbundle.New(configBlock, blocks, localmsp.NewSigner())
+	if err != nil {
+		return fmt.Errorf("Error creating the bundle: %s", err)
+	}
+	b, err := proto.Marshal(bundle)
+	if err != nil {
+		return err
+	}
+	if err = ioutil.WriteFile(args[0], b, 0644); err != nil {
+		return err
+	}
+	logger.Infof("Exported blocks [%d, %d] of channel %s into %s", start, end, chainID, args[0])
+	return nil
+}
@@ -0,0 +1,130 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	configtxtest "github.com/hyperledger/fabric/common/configtx/test"
+	"github.com/hyperledger/fabric/common/ledgerbundle"
+	"github.com/hyperledger/fabric/common/localmsp"
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/scc/qscc"
+	"github.com/hyperledger/fabric/peer/common"
+	cb "github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+// mockLedgerEndorserClient answers the queries of the blocks of a ledger
+type mockLedgerEndorserClient struct {
+	blocks []*cb.Block
+}
+
+func (m *mockLedgerEndorserClient) ProcessProposal(ctx context.Context, in *pb.SignedProposal, opts ...grpc.CallOption) (*pb.ProposalResponse, error) {
+	prop, err := utils.GetProposal(in.ProposalBytes)
+	if err != nil {
+		return nil, err
+	}
+	cis, err := utils.GetChaincodeInvocationSpec(prop)
+	if err != nil {
+		return nil, err
+	}
+	args := cis.ChaincodeSpec.Input.Args
+	switch string(args[0]) {
+	case qscc.GetChainInfo:
+		info := &cb.BlockchainInfo{Height: uint64(len(m.blocks))}
+		return &pb.ProposalResponse{Response: &pb.Response{Status: 200, Payload: utils.MarshalOrPanic(info)}}, nil
+	case qscc.GetBlockByNumber:
+		num, _ := strconv.Atoi(string(args[2]))
+		return &pb.ProposalResponse{Response: &pb.Response{Status: 200, Payload: utils.MarshalOrPanic(m.blocks[num])}}, nil
+	}
+	return &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: "unexpected query"}}, nil
+}
+
+func (m *mockLedgerEndorserClient) SimulateProposal(ctx context.Context, in *pb.SignedProposal, opts ...grpc.CallOption) (*pb.ProposalResponse, error) {
+	return nil, fmt.Errorf("unexpected simulation")
+}
+
+func (m *mockLedgerEndorserClient) SimulateProposalAt(ctx context.Context, in *pb.HistoricalProposal, opts ...grpc.CallOption) (*pb.ProposalResponse, error) {
+	return nil, fmt.Errorf("unexpected simulation")
+}
+
+// signedLedger returns the genesis block of the test channel followed by numBlocks blocks signed
+// the way the orderer does
+func signedLedger(t *testing.T, numBlocks int) []*cb.Block {
+	genesis, err := configtxtest.MakeGenesisBlock(util.GetTestChainID())
+	assert.NoError(t, err)
+	blocks := []*cb.Block{genesis}
+	signer := localmsp.NewSigner()
+	for i := 1; i <= numBlocks; i++ {
+		block := cb.NewBlock(uint64(i), blocks[i-1].Header.Hash())
+		block.Data.Data = [][]byte{[]byte(fmt.Sprintf("tx%d", i))}
+		block.Header.DataHash = block.Data.Hash()
+		for index, value := range map[cb.BlockMetadataIndex][]byte{
+			cb.BlockMetadataIndex_SIGNATURES:  nil,
+			cb.BlockMetadataIndex_LAST_CONFIG: utils.MarshalOrPanic(&cb.LastConfig{Index: 0}),
+		} {
+			sig := &cb.MetadataSignature{SignatureHeader: utils.MarshalOrPanic(utils.NewSignatureHeaderOrPanic(signer))}
+			sig.Signature = utils.SignOrPanic(signer, util.ConcatenateBytes(value, sig.SignatureHeader, block.Header.Bytes()))
+			block.Metadata.Metadata[index] = utils.MarshalOrPanic(&cb.Metadata{Value: value, Signatures: []*cb.MetadataSignature{sig}})
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
+func TestExport(t *testing.T) {
+	InitMSP()
+	defer resetFlags()
+
+	dir, err := ioutil.TempDir("", "export")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "bundle")
+
+	signer, err := common.GetDefaultSigner()
+	assert.NoError(t, err)
+	mockCF := &ChannelCmdFactory{
+		EndorserClient: &mockLedgerEndorserClient{blocks: signedLedger(t, 4)},
+		Signer:         signer,
+	}
+
+	cmd := exportCmd(mockCF)
+	AddFlags(cmd)
+	cmd.SetArgs([]string{"-c", util.GetTestChainID(), "--startBlock", "2", "--endBlock", "3", file})
+	assert.NoError(t, cmd.Execute())
+
+	b, err := ioutil.ReadFile(file)
+	assert.NoError(t, err)
+	bundle := &cb.LedgerBundle{}
+	assert.NoError(t, proto.Unmarshal(b, bundle))
+	content, _, err := ledgerbundle.Verify(bundle)
+	assert.NoError(t, err)
+	assert.Len(t, content.Blocks, 2)
+	assert.Equal(t, uint64(2), content.Blocks[0].Header.Number)
+	assert.Equal(t, uint64(0), content.ConfigBlock.Header.Number)
+
+	cmd = exportCmd(mockCF)
+	AddFlags(cmd)
+	cmd.SetArgs([]string{"-c", util.GetTestChainID(), "--startBlock", "2", "--endBlock", "5", file})
+	assert.Error(t, cmd.Execute(), "Should not export blocks beyond the height of the ledger")
+
+	cmd = exportCmd(mockCF)
+	AddFlags(cmd)
+	cmd.SetArgs([]string{"-c", util.GetTestChainID()})
+	assert.Error(t, cmd.Execute(), "Should require an output file")
+}
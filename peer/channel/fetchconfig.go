@@ -69,15 +69,7 @@ func fetch(cmd *cobra.Command, args []string, cf *ChannelCmdFactory) error {
 	case "newest":
 		block, err = cf.DeliverClient.getNewestBlock()
 	case "config":
-		iBlock, err := cf.DeliverClient.getNewestBlock()
-		if err != nil {
-			return err
-		}
-		lc, err := utils.GetLastConfigIndexFromBlock(iBlock)
-		if err != nil {
-			return err
-		}
-		block, err = cf.DeliverClient.getSpecifiedBlock(lc)
+		block, err = getConfigBlock(cf.DeliverClient)
 	default:
 		num, err := strconv.Atoi(args[0])
 		if err != nil {
@@ -108,3 +100,16 @@ func fetch(cmd *cobra.Command, args []string, cf *ChannelCmdFactory) error {
 
 	return nil
 }
+
+// getConfigBlock returns the last config block of the channel
+func getConfigBlock(dc deliverClientIntf) (*cb.Block, error) {
+	iBlock, err := dc.getNewestBlock()
+	if err != nil {
+		return nil, err
+	}
+	lc, err := utils.GetLastConfigIndexFromBlock(iBlock)
+	if err != nil {
+		return nil, err
+	}
+	return dc.getSpecifiedBlock(lc)
+}
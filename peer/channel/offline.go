@@ -0,0 +1,127 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/hyperledger/fabric/peer/common"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+)
+
+// updateOffline runs the step of a config update signed offline selected by
+// the flags:
+//  1. '-f update.tx --export sig.req' exports the signature of the config update
+//  2. '-f update.tx --configSignature sig.req --signature sig.der --export tx.req'
+//     adds the signature to the config update, and exports the transaction
+//  3. '--transaction tx.req --signature tx.der' sends the signed transaction
+func updateOffline(cf *ChannelCmdFactory) error {
+	creator, err := common.GetOfflineCreatorFnc()
+	if err != nil {
+		return err
+	}
+
+	if transactionFile != "" {
+		return sendOfflineConfigTx(creator, cf)
+	}
+
+	if channelTxFile == "" {
+		return InvalidCreateTx("No configtx file name supplied")
+	}
+	if exportFile == "" {
+		return errors.New("Must supply the file to export the payload to sign to")
+	}
+
+	fileData, err := ioutil.ReadFile(channelTxFile)
+	if err != nil {
+		return ConfigTxFileNotFound(err.Error())
+	}
+	ctxEnv, err := utils.UnmarshalEnvelope(fileData)
+	if err != nil {
+		return err
+	}
+	configUpdateEnv, err := sanityCheckConfigTx(ctxEnv)
+	if err != nil {
+		return err
+	}
+
+	if configSignatureFile == "" {
+		configSig, err := utils.CreateUnsignedConfigSignature(creator)
+		if err != nil {
+			return err
+		}
+		return common.ExportSigningRequest(exportFile, utils.ConfigSignatureBytes(configSig, configUpdateEnv.ConfigUpdate))
+	}
+
+	if signatureFile == "" {
+		return errors.New("Must supply the detached signature of the config update")
+	}
+	request, signature, err := common.ImportDetachedSignature(configSignatureFile, signatureFile, creator)
+	if err != nil {
+		return err
+	}
+	configSig, err := utils.GetConfigSignatureFromSignature(request, configUpdateEnv.ConfigUpdate, signature)
+	if err != nil {
+		return err
+	}
+	sigHeader, err := utils.GetSignatureHeader(configSig.SignatureHeader)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(sigHeader.Creator, creator) {
+		return errors.New("The config update signature was exported for another creator")
+	}
+	configUpdateEnv.Signatures = append(configUpdateEnv.Signatures, configSig)
+
+	payload, err := utils.CreateUnsignedEnvelope(cb.HeaderType_CONFIG_UPDATE, chainID, creator, configUpdateEnv, 0, 0)
+	if err != nil {
+		return err
+	}
+	payloadBytes, err := utils.GetBytesPayload(payload)
+	if err != nil {
+		return err
+	}
+	return common.ExportSigningRequest(exportFile, payloadBytes)
+}
+
+// sendOfflineConfigTx sends the config update transaction signed offline
+func sendOfflineConfigTx(creator []byte, cf *ChannelCmdFactory) error {
+	if signatureFile == "" {
+		return errors.New("Must supply the detached signature of the transaction")
+	}
+	payloadBytes, signature, err := common.ImportDetachedSignature(transactionFile, signatureFile, creator)
+	if err != nil {
+		return err
+	}
+	env, err := utils.CreateEnvelopeFromSignature(payloadBytes, signature)
+	if err != nil {
+		return err
+	}
+	if _, err := sanityCheckConfigTx(env); err != nil {
+		return err
+	}
+
+	if cf == nil {
+		// The default factory needs the private key to sign the deliver requests
+		cf = &ChannelCmdFactory{
+			BroadcastFactory: func() (common.BroadcastClient, error) {
+				return common.GetBroadcastClientFnc(orderingEndpoint, tls, caFile)
+			},
+		}
+	}
+	broadcastClient, err := cf.BroadcastFactory()
+	if err != nil {
+		return fmt.Errorf("Error getting broadcast client: %s", err)
+	}
+
+	defer broadcastClient.Close()
+	return broadcastClient.Send(env)
+}
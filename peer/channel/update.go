@@ -32,7 +32,7 @@ func updateCmd(cf *ChannelCmdFactory) *cobra.Command {
 	updateCmd := &cobra.Command{
 		Use:   "update",
 		Short: "Send a configtx update.",
-		Long:  "Signs and sends the supplied configtx update file to the channel. Requires '-f', '-o', '-c'.",
+		Long:  "Signs and sends the supplied configtx update file to the channel. Requires '-f', '-o', '-c'. With offline signing, exports the config update signature and the transaction to sign in turn with '--export', and sends the transaction signed offline with '--transaction'.",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return update(cmd, args, cf)
 		},
@@ -40,6 +40,10 @@ func updateCmd(cf *ChannelCmdFactory) *cobra.Command {
 	flagList := []string{
 		"channelID",
 		"file",
+		"export",
+		"configSignature",
+		"transaction",
+		"signature",
 	}
 	attachFlags(updateCmd, flagList)
 
@@ -52,6 +56,10 @@ func update(cmd *cobra.Command, args []string, cf *ChannelCmdFactory) error {
 		return errors.New("Must supply channel ID")
 	}
 
+	if exportFile != "" || configSignatureFile != "" || transactionFile != "" {
+		return updateOffline(cf)
+	}
+
 	if channelTxFile == "" {
 		return InvalidCreateTx("No configtx file name supplied")
 	}
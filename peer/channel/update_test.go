@@ -151,3 +151,71 @@ func TestUpdateChannelMissingChannelID(t *testing.T) {
 
 	assert.Error(t, cmd.Execute())
 }
+
+func TestUpdateChannelOffline(t *testing.T) {
+	InitMSP()
+
+	dir, err := ioutil.TempDir("/tmp", "offlinetest-")
+	if err != nil {
+		t.Fatalf("couldn't create temp dir")
+	}
+	defer os.RemoveAll(dir) // clean up
+
+	configtxFile := filepath.Join(dir, mockChannel)
+	if _, err = createTxFile(configtxFile, cb.HeaderType_CONFIG_UPDATE, mockChannel); err != nil {
+		t.Fatalf("couldn't create tx file")
+	}
+
+	signer, err := common.GetDefaultSigner()
+	if err != nil {
+		t.Fatalf("Get default signer error: %v", err)
+	}
+	creator, err := signer.Serialize()
+	assert.NoError(t, err)
+
+	getOfflineCreator := common.GetOfflineCreatorFnc
+	defer func() {
+		common.GetOfflineCreatorFnc = getOfflineCreator
+		resetFlags()
+	}()
+	common.GetOfflineCreatorFnc = func() ([]byte, error) {
+		return creator, nil
+	}
+
+	mockCF := &ChannelCmdFactory{
+		BroadcastFactory: mockBroadcastClientFactory,
+	}
+
+	// sign stands for the offline signer, which writes the detached signature
+	// of the exported file
+	sign := func(file string) string {
+		payload, err := ioutil.ReadFile(file)
+		assert.NoError(t, err)
+		signature, err := signer.Sign(payload)
+		assert.NoError(t, err)
+		assert.NoError(t, ioutil.WriteFile(file+".sig", signature, 0644))
+		return file + ".sig"
+	}
+	update := func(args ...string) error {
+		resetFlags()
+		cmd := updateCmd(mockCF)
+		AddFlags(cmd)
+		cmd.SetArgs(append([]string{"-c", mockChannel, "-o", "localhost:7050"}, args...))
+		return cmd.Execute()
+	}
+
+	configSigFile := filepath.Join(dir, "configsig")
+	txFile := filepath.Join(dir, "transaction")
+	assert.Error(t, update("--export", configSigFile), "Expected error without config update")
+	assert.NoError(t, update("-f", configtxFile, "--export", configSigFile))
+	assert.Error(t, update("-f", configtxFile, "--configSignature", configSigFile, "--export", txFile), "Expected error without signature")
+	assert.NoError(t, update("-f", configtxFile, "--configSignature", configSigFile, "--signature", sign(configSigFile), "--export", txFile))
+
+	payloadBytes, err := ioutil.ReadFile(txFile)
+	assert.NoError(t, err)
+	configUpdateEnv, err := sanityCheckConfigTx(&cb.Envelope{Payload: payloadBytes})
+	assert.NoError(t, err, "Expected the config update transaction to be exported")
+	assert.Len(t, configUpdateEnv.Signatures, 1)
+
+	assert.NoError(t, update("--transaction", txFile, "--signature", sign(txFile)))
+}
@@ -0,0 +1,128 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/hyperledger/fabric/common/configtx"
+	"github.com/hyperledger/fabric/common/configtx/overlay"
+	"github.com/hyperledger/fabric/peer/common"
+	cb "github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+
+	"github.com/spf13/cobra"
+)
+
+func updateAnchorPeersCmd(cf *ChannelCmdFactory) *cobra.Command {
+	updateAnchorPeersCmd := &cobra.Command{
+		Use:   "update-anchor-peers",
+		Short: "Set the anchor peers of the organization.",
+		Long:  "Fetches the config of the channel, and signs and sends the config update setting the anchor peers of the organization of the local MSP to the supplied endpoints. Requires '-c', '-o', '--anchorPeers'.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return updateAnchorPeers(cmd, args, cf)
+		},
+	}
+	flagList := []string{
+		"channelID",
+		"anchorPeers",
+	}
+	attachFlags(updateAnchorPeersCmd, flagList)
+
+	return updateAnchorPeersCmd
+}
+
+func updateAnchorPeers(cmd *cobra.Command, args []string, cf *ChannelCmdFactory) error {
+	//the global chainID filled by the "-c" command
+	if chainID == common.UndefinedParamValue {
+		return errors.New("Must supply channel ID")
+	}
+
+	peers, err := parseAnchorPeers(anchorPeers)
+	if err != nil {
+		return err
+	}
+
+	if cf == nil {
+		cf, err = InitCmdFactory(EndorserNotRequired, OrdererRequired)
+		if err != nil {
+			return err
+		}
+	}
+
+	conf, err := getChannelConfig(cf.DeliverClient)
+	if err != nil {
+		return fmt.Errorf("Error fetching the config of channel %s: %s", chainID, err)
+	}
+
+	ctxEnv, err := overlay.Update(chainID, conf, overlay.SetAnchorPeers(cf.Signer.GetMSPIdentifier(), peers))
+	if err != nil {
+		return fmt.Errorf("Error computing the config update: %s", err)
+	}
+
+	sCtxEnv, err := sanityCheckAndSignConfigTx(ctxEnv)
+	if err != nil {
+		return err
+	}
+
+	var broadcastClient common.BroadcastClient
+	broadcastClient, err = cf.BroadcastFactory()
+	if err != nil {
+		return fmt.Errorf("Error getting broadcast client: %s", err)
+	}
+
+	defer broadcastClient.Close()
+	return broadcastClient.Send(sCtxEnv)
+}
+
+// parseAnchorPeers parses host:port endpoints
+func parseAnchorPeers(endpoints []string) ([]*pb.AnchorPeer, error) {
+	if len(endpoints) == 0 {
+		return nil, errors.New("Must supply at least one anchor peer")
+	}
+	var peers []*pb.AnchorPeer
+	for _, endpoint := range endpoints {
+		host, portStr, err := net.SplitHostPort(endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid anchor peer %s: %s", endpoint, err)
+		}
+		port, err := strconv.ParseUint(portStr, 10, 16)
+		if err != nil || host == "" {
+			return nil, fmt.Errorf("Invalid anchor peer %s, expected host:port", endpoint)
+		}
+		peers = append(peers, &pb.AnchorPeer{Host: host, Port: int32(port)})
+	}
+	return peers, nil
+}
+
+// getChannelConfig returns the current config of the channel, from its last config block
+func getChannelConfig(dc deliverClientIntf) (*cb.Config, error) {
+	block, err := getConfigBlock(dc)
+	if err != nil {
+		return nil, err
+	}
+	env, err := utils.ExtractEnvelope(block, 0)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := utils.ExtractPayload(env)
+	if err != nil {
+		return nil, err
+	}
+	configEnv, err := configtx.UnmarshalConfigEnvelope(payload.Data)
+	if err != nil {
+		return nil, err
+	}
+	if configEnv.Config == nil {
+		return nil, errors.New("config block has no config")
+	}
+	return configEnv.Config, nil
+}
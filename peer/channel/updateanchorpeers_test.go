@@ -0,0 +1,143 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/config"
+	configmsp "github.com/hyperledger/fabric/common/config/msp"
+	"github.com/hyperledger/fabric/common/configtx"
+	"github.com/hyperledger/fabric/peer/common"
+	cb "github.com/hyperledger/fabric/protos/common"
+	mspprotos "github.com/hyperledger/fabric/protos/msp"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// configDeliverClient delivers the same config block whatever the block requested
+type configDeliverClient struct {
+	mockDeliverClient
+	block *cb.Block
+}
+
+func (m *configDeliverClient) getSpecifiedBlock(num uint64) (*cb.Block, error) {
+	return m.block, nil
+}
+
+func (m *configDeliverClient) getNewestBlock() (*cb.Block, error) {
+	return m.block, nil
+}
+
+type capturingBroadcastClient struct {
+	envs []*cb.Envelope
+}
+
+func (c *capturingBroadcastClient) Send(env *cb.Envelope) error {
+	c.envs = append(c.envs, env)
+	return nil
+}
+
+func (c *capturingBroadcastClient) Close() error {
+	return nil
+}
+
+func configBlock(mspID string) *cb.Block {
+	org := cb.NewConfigGroup()
+	org.Values[configmsp.MSPKey] = &cb.ConfigValue{Value: utils.MarshalOrPanic(&mspprotos.MSPConfig{
+		Config: utils.MarshalOrPanic(&mspprotos.FabricMSPConfig{Name: mspID}),
+	})}
+	application := cb.NewConfigGroup()
+	application.Groups["Org1"] = org
+	channel := cb.NewConfigGroup()
+	channel.Groups[config.ApplicationGroupKey] = application
+
+	env, err := utils.CreateSignedEnvelope(cb.HeaderType_CONFIG, mockChannel, nil, &cb.ConfigEnvelope{
+		Config: &cb.Config{ChannelGroup: channel},
+	}, 0, 0)
+	if err != nil {
+		panic(err)
+	}
+	block := cb.NewBlock(0, nil)
+	block.Data.Data = [][]byte{utils.MarshalOrPanic(env)}
+	block.Metadata.Metadata[cb.BlockMetadataIndex_LAST_CONFIG] = utils.MarshalOrPanic(&cb.Metadata{
+		Value: utils.MarshalOrPanic(&cb.LastConfig{Index: 0}),
+	})
+	return block
+}
+
+func TestUpdateAnchorPeers(t *testing.T) {
+	InitMSP()
+	resetFlags()
+
+	signer, err := common.GetDefaultSigner()
+	require.NoError(t, err)
+
+	broadcastClient := &capturingBroadcastClient{}
+	mockCF := &ChannelCmdFactory{
+		BroadcastFactory: func() (common.BroadcastClient, error) { return broadcastClient, nil },
+		Signer:           signer,
+		DeliverClient:    &configDeliverClient{block: configBlock(signer.GetMSPIdentifier())},
+	}
+
+	cmd := updateAnchorPeersCmd(mockCF)
+	AddFlags(cmd)
+	cmd.SetArgs([]string{"-c", mockChannel, "-o", "localhost:7050", "--anchorPeers", "peer0.org1.example.com:7051,peer1.org1.example.com:8051"})
+	require.NoError(t, cmd.Execute())
+
+	require.Len(t, broadcastClient.envs, 1)
+	payload, err := utils.ExtractPayload(broadcastClient.envs[0])
+	require.NoError(t, err)
+	configUpdateEnv, err := configtx.UnmarshalConfigUpdateEnvelope(payload.Data)
+	require.NoError(t, err)
+	assert.Len(t, configUpdateEnv.Signatures, 1, "Should have signed the config update")
+	configUpdate, err := configtx.UnmarshalConfigUpdate(configUpdateEnv.ConfigUpdate)
+	require.NoError(t, err)
+	assert.Equal(t, mockChannel, configUpdate.ChannelId)
+
+	anchorPeers := &pb.AnchorPeers{}
+	value := configUpdate.WriteSet.Groups[config.ApplicationGroupKey].Groups["Org1"].Values[config.AnchorPeersKey]
+	require.NotNil(t, value)
+	require.NoError(t, proto.Unmarshal(value.Value, anchorPeers))
+	assert.Equal(t, []*pb.AnchorPeer{
+		{Host: "peer0.org1.example.com", Port: 7051},
+		{Host: "peer1.org1.example.com", Port: 8051},
+	}, anchorPeers.AnchorPeers)
+}
+
+func TestUpdateAnchorPeersErrors(t *testing.T) {
+	InitMSP()
+
+	signer, err := common.GetDefaultSigner()
+	require.NoError(t, err)
+
+	for _, test := range []struct {
+		name  string
+		mspID string
+		args  []string
+	}{
+		{"no channel ID", signer.GetMSPIdentifier(), []string{"--anchorPeers", "peer0:7051"}},
+		{"no anchor peers", signer.GetMSPIdentifier(), []string{"-c", mockChannel}},
+		{"no port", signer.GetMSPIdentifier(), []string{"-c", mockChannel, "--anchorPeers", "peer0"}},
+		{"invalid port", signer.GetMSPIdentifier(), []string{"-c", mockChannel, "--anchorPeers", "peer0:70510"}},
+		{"organization not in the channel", "OtherMSP", []string{"-c", mockChannel, "--anchorPeers", "peer0:7051"}},
+	} {
+		resetFlags()
+		mockCF := &ChannelCmdFactory{
+			BroadcastFactory: mockBroadcastClientFactory,
+			Signer:           signer,
+			DeliverClient:    &configDeliverClient{block: configBlock(test.mspID)},
+		}
+		cmd := updateAnchorPeersCmd(mockCF)
+		AddFlags(cmd)
+		cmd.SetArgs(append(test.args, "-o", "localhost:7050"))
+		assert.Error(t, cmd.Execute(), "Should have failed with %s", test.name)
+	}
+}
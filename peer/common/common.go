@@ -23,6 +23,7 @@ import (
 	"github.com/hyperledger/fabric/bccsp/factory"
 	"github.com/hyperledger/fabric/common/configtx"
 	configtxapi "github.com/hyperledger/fabric/common/configtx/api"
+	"github.com/hyperledger/fabric/common/crypto/fips"
 	"github.com/hyperledger/fabric/common/errors"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/viperutil"
@@ -30,6 +31,7 @@ import (
 	"github.com/hyperledger/fabric/core/peer"
 	"github.com/hyperledger/fabric/core/scc/cscc"
 	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/msp/cache"
 	mspmgmt "github.com/hyperledger/fabric/msp/mgmt"
 	pcommon "github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric/protos/peer"
@@ -86,27 +88,65 @@ func InitConfig(cmdRoot string) error {
 
 //InitCrypto initializes crypto for this peer
 func InitCrypto(mspMgrConfigDir string, localMSPID string) error {
+	bccspConfig, err := getBCCSPConfig(mspMgrConfigDir)
+	if err != nil {
+		return err
+	}
+
+	err = mspmgmt.LoadLocalMsp(mspMgrConfigDir, bccspConfig, localMSPID)
+	if err != nil {
+		return fmt.Errorf("error when setting up MSP from directory %s: err %s", mspMgrConfigDir, err)
+	}
+
+	return nil
+}
+
+// getBCCSPConfig returns the BCCSP configuration of the local MSP, after
+// checking that its folder exists and setting up the FIPS mode and the
+// identity caches
+func getBCCSPConfig(mspMgrConfigDir string) (*factory.FactoryOpts, error) {
 	var err error
 	// Check whenever msp folder exists
 	_, err = os.Stat(mspMgrConfigDir)
 	if os.IsNotExist(err) {
 		// No need to try to load MSP from folder which is not available
-		return fmt.Errorf("cannot init crypto, missing %s folder", mspMgrConfigDir)
+		return nil, fmt.Errorf("cannot init crypto, missing %s folder", mspMgrConfigDir)
 	}
 
 	// Init the BCCSP
 	var bccspConfig *factory.FactoryOpts
 	err = viperutil.EnhancedExactUnmarshalKey("peer.BCCSP", &bccspConfig)
 	if err != nil {
-		return fmt.Errorf("could not parse YAML config [%s]", err)
+		return nil, fmt.Errorf("could not parse YAML config [%s]", err)
 	}
 
-	err = mspmgmt.LoadLocalMsp(mspMgrConfigDir, bccspConfig, localMSPID)
-	if err != nil {
-		return fmt.Errorf("error when setting up MSP from directory %s: err %s", mspMgrConfigDir, err)
+	// Restrict the cryptography to FIPS approved algorithms before the BCCSP and
+	// the MSPs are set up
+	if viper.GetBool("peer.fips.enabled") {
+		fips.Enable()
+	}
+	if fips.Enabled() {
+		if err := fips.CheckBCCSPOpts(bccspConfig); err != nil {
+			return nil, fmt.Errorf("BCCSP is not FIPS compliant: %s", err)
+		}
 	}
 
-	return nil
+	// Size the identity caches before any MSP is created
+	cache.SetSize(mspCacheSize())
+
+	return bccspConfig, nil
+}
+
+// mspCacheSize returns the number of identities cached per MSP, or zero if the
+// cache is disabled
+func mspCacheSize() int {
+	if viper.IsSet("peer.mspCache.enabled") && !viper.GetBool("peer.mspCache.enabled") {
+		return 0
+	}
+	if size := viper.GetInt("peer.mspCache.size"); size > 0 {
+		return size
+	}
+	return cache.DefaultSize
 }
 
 // GetEndorserClient returns a new endorser client connection for this peer
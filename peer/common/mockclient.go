@@ -41,6 +41,14 @@ func (m *mockEndorserClient) ProcessProposal(ctx context.Context, in *pb.SignedP
 	return m.response, m.err
 }
 
+func (m *mockEndorserClient) SimulateProposal(ctx context.Context, in *pb.SignedProposal, opts ...grpc.CallOption) (*pb.ProposalResponse, error) {
+	return m.response, m.err
+}
+
+func (m *mockEndorserClient) SimulateProposalAt(ctx context.Context, in *pb.HistoricalProposal, opts ...grpc.CallOption) (*pb.ProposalResponse, error) {
+	return m.response, m.err
+}
+
 func GetMockBroadcastClient(err error) BroadcastClient {
 	return &mockBroadcastClient{err: err}
 }
@@ -94,3 +102,23 @@ func (m *mockAdminClient) SetModuleLogLevel(ctx context.Context, in *pb.LogLevel
 func (m *mockAdminClient) RevertLogLevels(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*empty.Empty, error) {
 	return &empty.Empty{}, m.err
 }
+
+func (m *mockAdminClient) CompactLedgers(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*empty.Empty, error) {
+	return &empty.Empty{}, m.err
+}
+
+func (m *mockAdminClient) ExplainPolicy(ctx context.Context, in *pb.PolicyExplanationRequest, opts ...grpc.CallOption) (*cb.PolicyDecision, error) {
+	return &cb.PolicyDecision{Policy: in.Policy}, m.err
+}
+
+func (m *mockAdminClient) GetIndexDeployments(ctx context.Context, in *pb.IndexDeploymentsRequest, opts ...grpc.CallOption) (*pb.IndexDeployments, error) {
+	return &pb.IndexDeployments{}, m.err
+}
+
+func (m *mockAdminClient) BuildChaincodeImages(ctx context.Context, in *pb.ChaincodeImagesRequest, opts ...grpc.CallOption) (*pb.ChaincodeImages, error) {
+	return &pb.ChaincodeImages{}, m.err
+}
+
+func (m *mockAdminClient) RemoveStaleChaincodeImages(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*pb.ChaincodeImages, error) {
+	return &pb.ChaincodeImages{}, m.err
+}
@@ -0,0 +1,126 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric/msp"
+	mspmgmt "github.com/hyperledger/fabric/msp/mgmt"
+	mspprotos "github.com/hyperledger/fabric/protos/msp"
+)
+
+// offlineCreator is the serialized identity of the local MSP, set when the
+// CLI signs offline
+var offlineCreator []byte
+
+// GetOfflineCreatorFnc returns the serialized identity which signs offline,
+// by default it is set to GetOfflineCreator function
+var GetOfflineCreatorFnc = GetOfflineCreator
+
+// InitOfflineCrypto initializes crypto for a CLI which signs offline: the local
+// MSP is set up with the certificate of its signing identity but without its
+// private key, which is kept on another machine. The CLI then exports the
+// payloads to sign, and imports their detached signatures.
+func InitOfflineCrypto(mspMgrConfigDir string, localMSPID string) error {
+	if localMSPID == "" {
+		return errors.New("The local MSP must have an ID")
+	}
+
+	bccspConfig, err := getBCCSPConfig(mspMgrConfigDir)
+	if err != nil {
+		return err
+	}
+
+	conf, err := msp.GetLocalMspConfig(mspMgrConfigDir, bccspConfig, localMSPID)
+	if err != nil {
+		return fmt.Errorf("error when setting up MSP from directory %s: err %s", mspMgrConfigDir, err)
+	}
+
+	// Leave out the signing identity, whose private key is not available
+	fabricConf := &mspprotos.FabricMSPConfig{}
+	if err := proto.Unmarshal(conf.Config, fabricConf); err != nil {
+		return fmt.Errorf("error when reading the MSP config: %s", err)
+	}
+	cert := fabricConf.SigningIdentity.PublicSigner
+	fabricConf.SigningIdentity = nil
+	if conf.Config, err = proto.Marshal(fabricConf); err != nil {
+		return err
+	}
+
+	if err := mspmgmt.GetLocalMSP().Setup(conf); err != nil {
+		return fmt.Errorf("error when setting up MSP from directory %s: err %s", mspMgrConfigDir, err)
+	}
+
+	offlineCreator, err = proto.Marshal(&mspprotos.SerializedIdentity{Mspid: localMSPID, IdBytes: cert})
+	return err
+}
+
+// GetOfflineCreator returns the serialized identity of the local MSP, which
+// signs offline, or an error if the CLI signs with a local private key
+func GetOfflineCreator() ([]byte, error) {
+	if offlineCreator == nil {
+		return nil, errors.New("Offline signing is not enabled, set peer.offlineSigning")
+	}
+	return offlineCreator, nil
+}
+
+// ExportSigningRequest writes the bytes the offline signer must sign. The
+// file holds exactly these bytes, so that any tool can sign it.
+func ExportSigningRequest(file string, bytes []byte) error {
+	if err := ioutil.WriteFile(file, bytes, 0644); err != nil {
+		return fmt.Errorf("Error writing the payload to sign: %s", err)
+	}
+	fmt.Printf("Exported the payload to sign to %s\n", file)
+	return nil
+}
+
+// ImportDetachedSignature reads the bytes exported by ExportSigningRequest and
+// their detached signature by the creator, a DER encoded ECDSA signature of
+// their SHA-256 digest. The signature is normalized to its low-S form, and
+// checked against the certificate of the creator.
+func ImportDetachedSignature(requestFile, signatureFile string, creator []byte) (request []byte, signature []byte, err error) {
+	if request, err = ioutil.ReadFile(requestFile); err != nil {
+		return nil, nil, fmt.Errorf("Error reading the signed payload: %s", err)
+	}
+	if signature, err = ioutil.ReadFile(signatureFile); err != nil {
+		return nil, nil, fmt.Errorf("Error reading the signature: %s", err)
+	}
+
+	id, err := mspmgmt.GetLocalMSP().DeserializeIdentity(creator)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error deserializing the creator: %s", err)
+	}
+
+	// Tools other than the BCCSP may produce high-S signatures, which the
+	// peers and the orderers reject
+	sid := &mspprotos.SerializedIdentity{}
+	if err := proto.Unmarshal(creator, sid); err != nil {
+		return nil, nil, fmt.Errorf("Error deserializing the creator: %s", err)
+	}
+	if block, _ := pem.Decode(sid.IdBytes); block != nil {
+		if cert, err := x509.ParseCertificate(block.Bytes); err == nil {
+			if pk, ok := cert.PublicKey.(*ecdsa.PublicKey); ok {
+				if signature, err = sw.SignatureToLowS(pk, signature); err != nil {
+					return nil, nil, fmt.Errorf("Invalid signature: %s", err)
+				}
+			}
+		}
+	}
+
+	if err := id.Verify(request, signature); err != nil {
+		return nil, nil, fmt.Errorf("The signature does not match the payload and the creator: %s", err)
+	}
+	return request, signature, nil
+}
@@ -0,0 +1,90 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/config"
+	mspmgmt "github.com/hyperledger/fabric/msp/mgmt"
+	"github.com/hyperledger/fabric/peer/common"
+	mspprotos "github.com/hyperledger/fabric/protos/msp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// signHighS signs the SHA-256 digest of msg as an offline tool may, with the
+// high-S form of the signature
+func signHighS(t *testing.T, key *ecdsa.PrivateKey, msg []byte) []byte {
+	digest := sha256.Sum256(msg)
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	require.NoError(t, err)
+	halfOrder := new(big.Int).Rsh(key.Params().N, 1)
+	if s.Cmp(halfOrder) <= 0 {
+		s.Sub(key.Params().N, s)
+	}
+	sig, err := asn1.Marshal(struct{ R, S *big.Int }{r, s})
+	require.NoError(t, err)
+	return sig
+}
+
+func TestOfflineSigning(t *testing.T) {
+	mspConfigPath, err := config.GetDevMspDir()
+	require.NoError(t, err)
+	defer common.InitCrypto(mspConfigPath, "DEFAULT")
+
+	_, err = common.GetOfflineCreator()
+	assert.Error(t, err, "Expected an error before offline signing is enabled")
+	assert.Error(t, common.InitOfflineCrypto(mspConfigPath, ""), "Expected an error without MSP ID")
+
+	require.NoError(t, common.InitOfflineCrypto(mspConfigPath, "DEFAULT"))
+	creator, err := common.GetOfflineCreator()
+	require.NoError(t, err)
+	sid := &mspprotos.SerializedIdentity{}
+	require.NoError(t, proto.Unmarshal(creator, sid))
+	assert.Equal(t, "DEFAULT", sid.Mspid)
+	_, err = mspmgmt.GetLocalMSP().DeserializeIdentity(creator)
+	assert.NoError(t, err, "Expected the creator to be valid")
+
+	keyPEM, err := ioutil.ReadFile(filepath.Join(mspConfigPath, "keystore", "key.pem"))
+	require.NoError(t, err)
+	block, _ := pem.Decode(keyPEM)
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	require.NoError(t, err)
+
+	dir, err := ioutil.TempDir("", "offline")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	requestFile := filepath.Join(dir, "payload")
+	signatureFile := filepath.Join(dir, "payload.sig")
+	require.NoError(t, common.ExportSigningRequest(requestFile, []byte("payload")))
+	require.NoError(t, ioutil.WriteFile(signatureFile, signHighS(t, key.(*ecdsa.PrivateKey), []byte("payload")), 0644))
+
+	request, signature, err := common.ImportDetachedSignature(requestFile, signatureFile, creator)
+	assert.NoError(t, err, "Expected the high-S signature to be normalized")
+	assert.Equal(t, []byte("payload"), request)
+	id, err := mspmgmt.GetLocalMSP().DeserializeIdentity(creator)
+	require.NoError(t, err)
+	assert.NoError(t, id.Verify(request, signature))
+
+	require.NoError(t, common.ExportSigningRequest(requestFile, []byte("another payload")))
+	_, _, err = common.ImportDetachedSignature(requestFile, signatureFile, creator)
+	assert.Error(t, err, "Expected an error with the signature of another payload")
+	_, _, err = common.ImportDetachedSignature(requestFile, filepath.Join(dir, "missing"), creator)
+	assert.Error(t, err, "Expected an error without signature")
+}
@@ -22,6 +22,7 @@ import (
 	"fmt"
 
 	"errors"
+	"time"
 
 	mockpolicies "github.com/hyperledger/fabric/common/mocks/policies"
 	"github.com/hyperledger/fabric/common/policies"
@@ -139,6 +140,10 @@ func (id *Identity) GetOrganizationalUnits() []*msp.OUIdentifier {
 	return nil
 }
 
+func (id *Identity) ExpiresAt() time.Time {
+	return time.Time{}
+}
+
 func (id *Identity) Verify(msg []byte, sig []byte) error {
 	fmt.Printf("VERIFY [% x], [% x], [% x]\n", string(id.Msg), string(msg), string(sig))
 	if bytes.Equal(id.Msg, msg) {
@@ -0,0 +1,107 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gossip
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/common/configtx"
+	configtxapi "github.com/hyperledger/fabric/common/configtx/api"
+	configtxtest "github.com/hyperledger/fabric/common/configtx/test"
+	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/gossip/api"
+	"github.com/hyperledger/fabric/msp/mgmt"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type channelPolicyManagerGetter map[string]configtxapi.Manager
+
+func (cpmg channelPolicyManagerGetter) Manager(channelID string) (policies.Manager, bool) {
+	cm, ok := cpmg[channelID]
+	if !ok {
+		return nil, false
+	}
+	return cm.PolicyManager(), true
+}
+
+func TestOrgReconfiguration(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gossip-reconfig")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var orgs []*configtxtest.Org
+	for _, name := range []string{"OrdererMSP", "Org1MSP", "Org2MSP", "Org3MSP"} {
+		org, err := configtxtest.NewOrg(dir, name)
+		require.NoError(t, err)
+		orgs = append(orgs, org)
+	}
+	ordererOrg, org1, org2, org3 := orgs[0], orgs[1], orgs[2], orgs[3]
+	peer1, err := org1.NewMember("peer0.org1")
+	require.NoError(t, err)
+	peer2, err := org2.NewMember("peer0.org2")
+	require.NoError(t, err)
+	peer3, err := org3.NewMember("peer0.org3")
+	require.NoError(t, err)
+
+	chainID := "gossipreconfig"
+	genesisBlock, err := configtxtest.MakeGenesisBlockFromOrgs(chainID, ordererOrg, org1, org2)
+	require.NoError(t, err)
+	cm, err := configtx.NewManagerImpl(utils.ExtractEnvelopeOrPanic(genesisBlock, 0), configtx.NewInitializer(), nil)
+	require.NoError(t, err)
+	// The peer registers the MSP manager of a channel once, when it joins the channel
+	mgmt.XXXSetMSPManager(chainID, cm.MSPManager())
+
+	mcs := NewMCS(channelPolicyManagerGetter{chainID: cm}, peer1, mgmt.NewDeserializersManager())
+	advisor := NewSecurityAdvisor(mgmt.NewDeserializersManager())
+	msg := []byte("alive")
+	verify := func(peer *configtxtest.Member) error {
+		signature, err := peer.Sign(msg)
+		require.NoError(t, err)
+		if err := mcs.ValidateIdentity(peer.Serialize()); err != nil {
+			return err
+		}
+		return mcs.VerifyByChannel([]byte(chainID), peer.Serialize(), signature, msg)
+	}
+
+	// Policies built by the peer on the MSP manager of the channel, such as endorsement policies
+	org3Policy, _, err := cauthdsl.NewPolicyProvider(mgmt.GetManagerForChain(chainID)).NewPolicy(utils.MarshalOrPanic(cauthdsl.SignedByMspMember(org3.Name)))
+	require.NoError(t, err)
+	evaluateOrg3Policy := func() error {
+		signature, err := peer3.Sign(msg)
+		require.NoError(t, err)
+		return org3Policy.Evaluate([]*common.SignedData{{Data: msg, Identity: peer3.Serialize(), Signature: signature}})
+	}
+
+	assert.NoError(t, verify(peer2))
+	assert.Error(t, verify(peer3))
+	assert.Nil(t, advisor.OrgByPeerIdentity(peer3.Serialize()))
+	assert.Error(t, evaluateOrg3Policy())
+
+	t.Run("OrgAddition", func(t *testing.T) {
+		_, err := configtxtest.AddApplicationOrg(cm, org3, org1.Admin, org2.Admin)
+		require.NoError(t, err)
+
+		assert.NoError(t, verify(peer3))
+		assert.Equal(t, api.OrgIdentityType(org3.Name), advisor.OrgByPeerIdentity(peer3.Serialize()))
+		assert.NoError(t, evaluateOrg3Policy())
+	})
+
+	t.Run("OrgRemoval", func(t *testing.T) {
+		_, err := configtxtest.RemoveApplicationOrg(cm, org2, org1.Admin, org3.Admin)
+		require.NoError(t, err)
+
+		assert.Error(t, verify(peer2))
+		assert.Nil(t, advisor.OrgByPeerIdentity(peer2.Serialize()))
+		assert.NoError(t, verify(peer3))
+	})
+}
@@ -103,15 +103,32 @@ func main() {
 	runtime.GOMAXPROCS(viper.GetInt("peer.gomaxprocs"))
 
 	// setup system-wide logging backend based on settings from core.yaml
-	flogging.InitBackend(flogging.SetFormat(viper.GetString("logging.format")), logOutput)
+	redaction := flogging.RedactionRules{
+		Payloads:     viper.GetBool("logging.redaction.payloads"),
+		Types:        viper.GetStringSlice("logging.redaction.types"),
+		Certificates: viper.GetBool("logging.redaction.certificates"),
+		Patterns:     viper.GetStringSlice("logging.redaction.patterns"),
+	}
+	err = flogging.InitBackendWithRedaction(flogging.SetFormat(viper.GetString("logging.format")), logOutput, redaction)
+	if err != nil {
+		panic(fmt.Errorf("Fatal error when initializing the logging redaction: %s\n", err))
+	}
 
 	// Init the MSP
 	var mspMgrConfigDir = config.GetPath("peer.mspConfigPath")
 	var mspID = viper.GetString("peer.localMspId")
-	err = common.InitCrypto(mspMgrConfigDir, mspID)
+	if viper.GetBool("peer.offlineSigning") {
+		// The private key of the local MSP is kept on another machine
+		err = common.InitOfflineCrypto(mspMgrConfigDir, mspID)
+	} else {
+		err = common.InitCrypto(mspMgrConfigDir, mspID)
+	}
 	if err != nil { // Handle errors reading the config file
-		logger.Errorf("Cannot run peer because %s", err.Error())
-		os.Exit(1)
+		// The doctor command reports the errors of the MSP itself
+		if cmd, _, findErr := mainCmd.Find(os.Args[1:]); findErr != nil || !node.IsDoctorCmd(cmd) {
+			logger.Errorf("Cannot run peer because %s", err.Error())
+			os.Exit(1)
+		}
 	}
 	// On failure Cobra prints the usage message and error string, so we only
 	// need to exit with a non-0 status
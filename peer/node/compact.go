@@ -0,0 +1,44 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/hyperledger/fabric/peer/common"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+)
+
+func compactCmd() *cobra.Command {
+	return nodeCompactCmd
+}
+
+var nodeCompactCmd = &cobra.Command{
+	Use:   "compact",
+	Short: "Compacts the ledger databases of the node.",
+	Long:  `Triggers a compaction of the goleveldb databases backing the ledgers of the running node.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return compact()
+	},
+}
+
+func compact() error {
+	adminClient, err := common.GetAdminClient()
+	if err != nil {
+		logger.Warningf("%s", err)
+		return err
+	}
+
+	if _, err = adminClient.CompactLedgers(context.Background(), &empty.Empty{}); err != nil {
+		logger.Infof("Error trying to compact the ledgers of the local peer: %s", err)
+		return fmt.Errorf("Error trying to compact the ledgers of the local peer: %s", err)
+	}
+	fmt.Println("Compaction of the ledger databases completed")
+	return nil
+}
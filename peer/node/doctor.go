@@ -0,0 +1,386 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/hyperledger/fabric/core/config"
+	cutil "github.com/hyperledger/fabric/core/container/util"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	"github.com/hyperledger/fabric/core/ledger/util/couchdb"
+	"github.com/hyperledger/fabric/core/peer"
+	mspmgmt "github.com/hyperledger/fabric/msp/mgmt"
+	"github.com/hyperledger/fabric/peer/common"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Statuses of the checks of the doctor command
+const (
+	doctorOK      = "OK"
+	doctorWarning = "WARNING"
+	doctorFailed  = "FAILED"
+)
+
+const (
+	// certificateExpiryWarning is how long before the expiry of a certificate it is reported
+	certificateExpiryWarning = 30 * 24 * time.Hour
+	// doctorDialTimeout bounds the connection to each gossip bootstrap peer
+	doctorDialTimeout = 3 * time.Second
+)
+
+var doctorJSON bool
+
+func doctorCmd() *cobra.Command {
+	nodeDoctorCmd.Flags().BoolVarP(&doctorJSON, "json", "", false, "Print the report as JSON")
+	return nodeDoctorCmd
+}
+
+var nodeDoctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Checks the configuration of the node without starting it.",
+	Long: `Checks core.yaml, the local MSP, the TLS material, the file system path, the ports the node listens on, ` +
+		`and the reachability of CouchDB, Docker and the gossip bootstrap peers, without starting the node. ` +
+		`Exits with a non-zero status if a check failed.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		report := doctor()
+		if err := report.write(os.Stdout, doctorJSON); err != nil {
+			return err
+		}
+		if report.Failed {
+			return fmt.Errorf("The node is not expected to start with this configuration")
+		}
+		return nil
+	},
+}
+
+// IsDoctorCmd returns whether the command is the doctor command, which reports the errors of the local MSP rather
+// than being prevented from running by them
+func IsDoctorCmd(cmd *cobra.Command) bool {
+	return cmd == nodeDoctorCmd
+}
+
+// doctorResult is the outcome of a check of the doctor command
+type doctorResult struct {
+	Check  string `json:"check"`
+	Status string `json:"status"`
+	Detail string `json:"detail"`
+}
+
+// doctorReport gathers the outcomes of the checks of the doctor command, in the order they ran
+type doctorReport struct {
+	Results []doctorResult `json:"results"`
+	Failed  bool           `json:"failed"`
+}
+
+func (r *doctorReport) add(check, status, format string, args ...interface{}) {
+	r.Results = append(r.Results, doctorResult{Check: check, Status: status, Detail: fmt.Sprintf(format, args...)})
+	if status == doctorFailed {
+		r.Failed = true
+	}
+}
+
+func (r *doctorReport) ok(check, format string, args ...interface{}) {
+	r.add(check, doctorOK, format, args...)
+}
+
+func (r *doctorReport) warn(check, format string, args ...interface{}) {
+	r.add(check, doctorWarning, format, args...)
+}
+
+func (r *doctorReport) fail(check, format string, args ...interface{}) {
+	r.add(check, doctorFailed, format, args...)
+}
+
+// write prints the report, as JSON for the provisioning pipelines or as a table
+func (r *doctorReport) write(w io.Writer, asJSON bool) error {
+	if asJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(r)
+	}
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	for _, result := range r.Results {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", result.Status, result.Check, result.Detail)
+	}
+	return tw.Flush()
+}
+
+// doctor runs the checks of the configuration of the node
+func doctor() *doctorReport {
+	report := &doctorReport{}
+	if !doctorConfig(report) {
+		return report
+	}
+	doctorMSP(report)
+	doctorTLS(report)
+	doctorFileSystem(report)
+	doctorListenAddresses(report)
+	doctorCouchDB(report)
+	doctorDocker(report)
+	doctorGossipBootstrap(report)
+	return report
+}
+
+// doctorConfig checks that the configuration was read and the endpoint of the peer can be determined, and returns
+// whether the other checks can run
+func doctorConfig(report *doctorReport) bool {
+	if viper.ConfigFileUsed() == "" {
+		report.fail("config", "No core.yaml was found, set FABRIC_CFG_PATH to the directory holding it")
+		return false
+	}
+	if err := peer.CacheConfiguration(); err != nil {
+		report.fail("config", "Invalid configuration in %s: %s", viper.ConfigFileUsed(), err)
+		return false
+	}
+	report.ok("config", "Loaded %s", viper.ConfigFileUsed())
+	return true
+}
+
+// doctorMSP checks the structure of the directory of the local MSP, that it can be set up and that its signing
+// identity is valid
+func doctorMSP(report *doctorReport) {
+	dir := config.GetPath("peer.mspConfigPath")
+	mspID := viper.GetString("peer.localMspId")
+	offline := viper.GetBool("peer.offlineSigning")
+
+	subdirs := []struct {
+		name     string
+		required bool
+	}{
+		{"cacerts", true},
+		{"signcerts", true},
+		{"keystore", !offline},
+		{"admincerts", false},
+	}
+	for _, subdir := range subdirs {
+		files, err := ioutil.ReadDir(filepath.Join(dir, subdir.name))
+		switch {
+		case (err != nil || len(files) == 0) && subdir.required:
+			report.fail("msp", "The %s folder of the MSP directory %s is missing or empty", subdir.name, dir)
+		case err != nil || len(files) == 0:
+			report.warn("msp", "The %s folder of the MSP directory %s is missing or empty", subdir.name, dir)
+		}
+	}
+
+	var err error
+	if offline {
+		err = common.InitOfflineCrypto(dir, mspID)
+	} else {
+		err = common.InitCrypto(dir, mspID)
+	}
+	if err != nil {
+		report.fail("msp", "Cannot set up the local MSP %s: %s", mspID, err)
+		return
+	}
+	signer, err := mspmgmt.GetLocalMSP().GetDefaultSigningIdentity()
+	if err != nil {
+		report.fail("msp", "The local MSP %s has no signing identity: %s", mspID, err)
+		return
+	}
+	expiresAt := signer.ExpiresAt()
+	switch {
+	case !expiresAt.IsZero() && time.Now().After(expiresAt):
+		report.fail("msp", "The signing identity of the local MSP %s expired on %s", mspID, expiresAt)
+	case !expiresAt.IsZero() && time.Now().Add(certificateExpiryWarning).After(expiresAt):
+		report.warn("msp", "The signing identity of the local MSP %s expires on %s", mspID, expiresAt)
+	default:
+		report.ok("msp", "Set up the local MSP %s from %s", mspID, dir)
+	}
+}
+
+// doctorTLS checks the TLS certificate, key and root certificate of the peer
+func doctorTLS(report *doctorReport) {
+	secureConfig, err := peer.GetSecureConfig()
+	if err != nil {
+		report.fail("tls", "%s", err)
+		return
+	}
+	if !secureConfig.UseTLS {
+		report.warn("tls", "TLS is disabled")
+		return
+	}
+	if _, err := tls.X509KeyPair(secureConfig.ServerCertificate, secureConfig.ServerKey); err != nil {
+		report.fail("tls", "peer.tls.cert.file and peer.tls.key.file do not make a key pair: %s", err)
+	} else {
+		doctorCertificate(report, "peer.tls.cert.file", secureConfig.ServerCertificate)
+	}
+	for _, rootCert := range secureConfig.ServerRootCAs {
+		if !x509.NewCertPool().AppendCertsFromPEM(rootCert) {
+			report.fail("tls", "peer.tls.rootcert.file holds no PEM encoded certificate")
+			continue
+		}
+		doctorCertificate(report, "peer.tls.rootcert.file", rootCert)
+	}
+}
+
+// doctorCertificate reports the first certificate of the PEM data if it is not yet valid, expired or about to expire
+func doctorCertificate(report *doctorReport, name string, pemData []byte) {
+	block, _ := pem.Decode(pemData)
+	if block == nil {
+		report.fail("tls", "%s holds no PEM encoded certificate", name)
+		return
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		report.fail("tls", "%s cannot be parsed: %s", name, err)
+		return
+	}
+	now := time.Now()
+	switch {
+	case now.Before(cert.NotBefore):
+		report.fail("tls", "%s is not valid before %s", name, cert.NotBefore)
+	case now.After(cert.NotAfter):
+		report.fail("tls", "%s expired on %s", name, cert.NotAfter)
+	case now.Add(certificateExpiryWarning).After(cert.NotAfter):
+		report.warn("tls", "%s expires on %s", name, cert.NotAfter)
+	default:
+		report.ok("tls", "%s is valid until %s", name, cert.NotAfter)
+	}
+}
+
+// doctorFileSystem checks that the node can write to peer.fileSystemPath, or create it
+func doctorFileSystem(report *doctorReport) {
+	dir := config.GetPath("peer.fileSystemPath")
+	if err := checkWritableDir(dir); err != nil {
+		report.fail("filesystem", "peer.fileSystemPath %s: %s", dir, err)
+		return
+	}
+	report.ok("filesystem", "peer.fileSystemPath %s is writable", dir)
+}
+
+// checkWritableDir checks that a file can be created in dir, or, if dir does not exist, in its nearest existing
+// parent, in which the node creates it
+func checkWritableDir(dir string) error {
+	for {
+		info, err := os.Stat(dir)
+		if os.IsNotExist(err) && filepath.Dir(dir) != dir {
+			dir = filepath.Dir(dir)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("%s is not a directory", dir)
+		}
+		break
+	}
+	f, err := ioutil.TempFile(dir, ".doctor")
+	if err != nil {
+		return err
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
+
+// doctorListenAddresses checks that the addresses the node listens on are free and distinct
+func doctorListenAddresses(report *doctorReport) {
+	keys := []string{"peer.listenAddress", "peer.chaincodeListenAddress", "peer.events.address"}
+	for _, endpoint := range []string{"peer.profile", "peer.grpcWeb", "peer.operations"} {
+		if !viper.GetBool(endpoint + ".enabled") {
+			continue
+		}
+		if viper.IsSet(endpoint + ".listenAddress") {
+			keys = append(keys, endpoint+".listenAddress")
+		} else {
+			keys = append(keys, endpoint+".address")
+		}
+	}
+
+	used := make(map[string]string)
+	for _, key := range keys {
+		address := viper.GetString(key)
+		if address == "" {
+			continue
+		}
+		if other, ok := used[address]; ok {
+			// The chaincodes connect to the peer server if the chaincode listen address is its own
+			if key != "peer.chaincodeListenAddress" || other != "peer.listenAddress" {
+				report.fail("ports", "%s %s is also used by %s", key, address, other)
+			}
+			continue
+		}
+		used[address] = key
+		l, err := net.Listen("tcp", address)
+		if err != nil {
+			report.fail("ports", "Cannot listen on %s %s: %s", key, address, err)
+			continue
+		}
+		l.Close()
+		report.ok("ports", "Can listen on %s %s", key, address)
+	}
+}
+
+// doctorCouchDB checks that CouchDB can be reached with the credentials of the node, if it is the state database
+func doctorCouchDB(report *doctorReport) {
+	if !ledgerconfig.IsCouchDBEnabled() {
+		return
+	}
+	def := couchdb.GetCouchDBDefinition()
+	if _, err := couchdb.CreateCouchInstance(def.URL, def.Username, def.Password, def.MaxRetries, 1, def.RequestTimeout); err != nil {
+		report.fail("couchdb", "Cannot reach CouchDB at %s: %s", def.URL, err)
+		return
+	}
+	report.ok("couchdb", "Reached CouchDB at %s", def.URL)
+}
+
+// doctorDocker checks that the Docker daemon running the chaincodes can be reached
+func doctorDocker(report *doctorReport) {
+	endpoint := viper.GetString("vm.endpoint")
+	client, err := cutil.NewDockerClient()
+	if err == nil {
+		err = client.Ping()
+	}
+	if err != nil {
+		report.fail("docker", "Cannot reach Docker at %s: %s", endpoint, err)
+		return
+	}
+	report.ok("docker", "Reached Docker at %s", endpoint)
+}
+
+// doctorGossipBootstrap checks that the gossip bootstrap peers accept connections. The node itself, which is
+// commonly its own bootstrap peer, is skipped.
+func doctorGossipBootstrap(report *doctorReport) {
+	self := map[string]bool{viper.GetString("peer.address"): true, viper.GetString("peer.listenAddress"): true}
+	if endpoint := viper.GetString("peer.gossip.endpoint"); endpoint != "" {
+		self[endpoint] = true
+	}
+	_, listenPort, _ := net.SplitHostPort(viper.GetString("peer.listenAddress"))
+
+	for _, bootstrap := range viper.GetStringSlice("peer.gossip.bootstrap") {
+		host, port, err := net.SplitHostPort(bootstrap)
+		if err != nil {
+			report.fail("gossip", "Invalid bootstrap peer %s: %s", bootstrap, err)
+			continue
+		}
+		if ip := net.ParseIP(host); self[bootstrap] || (port == listenPort && (host == "localhost" || (ip != nil && ip.IsLoopback()))) {
+			report.ok("gossip", "Bootstrap peer %s is this node", bootstrap)
+			continue
+		}
+		conn, err := net.DialTimeout("tcp", bootstrap, doctorDialTimeout)
+		if err != nil {
+			report.warn("gossip", "Cannot reach bootstrap peer %s, the node joins gossip once it is up: %s", bootstrap, err)
+			continue
+		}
+		conn.Close()
+		report.ok("gossip", "Reached bootstrap peer %s", bootstrap)
+	}
+}
@@ -0,0 +1,165 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric/msp/mgmt/testtools"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setDoctorConfig sets the given keys for the duration of the test
+func setDoctorConfig(t *testing.T, values map[string]interface{}) func() {
+	previous := make(map[string]interface{})
+	for key, value := range values {
+		previous[key] = viper.Get(key)
+		viper.Set(key, value)
+	}
+	return func() {
+		for key, value := range previous {
+			viper.Set(key, value)
+		}
+	}
+}
+
+func TestDoctorReport(t *testing.T) {
+	report := &doctorReport{}
+	report.ok("foo", "all %s", "good")
+	report.warn("bar", "not so good")
+	assert.False(t, report.Failed)
+	report.fail("baz", "bad")
+	assert.True(t, report.Failed)
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, report.write(buf, false))
+	assert.Contains(t, buf.String(), "FAILED   baz  bad")
+
+	buf.Reset()
+	require.NoError(t, report.write(buf, true))
+	decoded := &doctorReport{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), decoded))
+	assert.Equal(t, report, decoded)
+
+	assert.True(t, IsDoctorCmd(doctorCmd()))
+	assert.False(t, IsDoctorCmd(&cobra.Command{Use: "doctor"}))
+}
+
+func TestDoctorListenAddresses(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	free, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	freeAddress := free.Addr().String()
+	free.Close()
+
+	defer setDoctorConfig(t, map[string]interface{}{
+		"peer.listenAddress":          freeAddress,
+		"peer.chaincodeListenAddress": freeAddress,
+		"peer.events.address":         l.Addr().String(),
+		"peer.profile.enabled":        true,
+		"peer.profile.listenAddress":  l.Addr().String(),
+		"peer.grpcWeb.enabled":        false,
+		"peer.operations.enabled":     false,
+	})()
+
+	report := &doctorReport{}
+	doctorListenAddresses(report)
+	require.Len(t, report.Results, 3, "Expected the chaincode listen address shared with the peer to be skipped")
+	assert.Equal(t, doctorOK, report.Results[0].Status)
+	assert.Equal(t, doctorFailed, report.Results[1].Status, "Expected the listen address in use to be reported")
+	assert.Equal(t, doctorFailed, report.Results[2].Status, "Expected the listen address used twice to be reported")
+	assert.Contains(t, report.Results[2].Detail, "peer.events.address")
+}
+
+func TestDoctorGossipBootstrap(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	unreachable := closed.Addr().String()
+	closed.Close()
+
+	defer setDoctorConfig(t, map[string]interface{}{
+		"peer.address":          "0.0.0.0:7051",
+		"peer.listenAddress":    "0.0.0.0:7051",
+		"peer.gossip.endpoint":  "",
+		"peer.gossip.bootstrap": []string{"127.0.0.1:7051", l.Addr().String(), unreachable, "invalid"},
+	})()
+
+	report := &doctorReport{}
+	doctorGossipBootstrap(report)
+	require.Len(t, report.Results, 4)
+	assert.Contains(t, report.Results[0].Detail, "is this node")
+	assert.Equal(t, doctorOK, report.Results[1].Status)
+	assert.Equal(t, doctorWarning, report.Results[2].Status, "Expected an unreachable bootstrap peer to be a warning")
+	assert.Equal(t, doctorFailed, report.Results[3].Status)
+}
+
+func TestDoctorFileSystem(t *testing.T) {
+	dir, err := ioutil.TempDir("", "doctor")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "file")
+	require.NoError(t, ioutil.WriteFile(file, []byte("foo"), 0644))
+
+	for path, failed := range map[string]bool{
+		dir:                               false,
+		filepath.Join(dir, "not", "yet"):  false,
+		file:                              true,
+		filepath.Join(file, "production"): true,
+	} {
+		restore := setDoctorConfig(t, map[string]interface{}{"peer.fileSystemPath": path})
+		report := &doctorReport{}
+		doctorFileSystem(report)
+		restore()
+		assert.Equal(t, failed, report.Failed, "Unexpected outcome for %s", path)
+	}
+}
+
+func TestDoctorMSP(t *testing.T) {
+	// As by the peer command, the BCCSP is initialized before the doctor command runs
+	require.NoError(t, msptesttools.LoadMSPSetupForTesting())
+	dir, err := ioutil.TempDir("", "doctor")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "cacerts"), 0755))
+
+	defer setDoctorConfig(t, map[string]interface{}{
+		"peer.mspConfigPath":  dir,
+		"peer.localMspId":     "DEFAULT",
+		"peer.offlineSigning": false,
+	})()
+
+	report := &doctorReport{}
+	doctorMSP(report)
+	// The failed setup leaves the local MSP unusable by the other tests
+	require.NoError(t, msptesttools.LoadMSPSetupForTesting())
+	assert.True(t, report.Failed)
+	var failed, warned int
+	for _, result := range report.Results {
+		switch result.Status {
+		case doctorFailed:
+			failed++
+		case doctorWarning:
+			warned++
+		}
+	}
+	assert.Equal(t, 4, failed, "Expected the empty cacerts, signcerts and keystore folders and the MSP setup to fail")
+	assert.Equal(t, 1, warned, "Expected the missing admincerts folder to be a warning")
+}
@@ -0,0 +1,104 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/hyperledger/fabric/peer/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+)
+
+var (
+	imageChaincodeName    string
+	imageChaincodeVersion string
+)
+
+func buildImagesCmd() *cobra.Command {
+	flags := nodeBuildImagesCmd.Flags()
+	flags.StringVarP(&imageChaincodeName, "name", "n", "", "Name of the chaincode whose image is built, all the chaincodes installed if empty")
+	flags.StringVarP(&imageChaincodeVersion, "version", "v", "", "Version of the chaincode whose image is built")
+	return nodeBuildImagesCmd
+}
+
+func pruneImagesCmd() *cobra.Command {
+	return nodePruneImagesCmd
+}
+
+var nodeBuildImagesCmd = &cobra.Command{
+	Use:   "buildimages",
+	Short: "Builds the images of the installed chaincodes.",
+	Long: `Builds the images of the chaincodes installed on the running node ahead of their first launch, ` +
+		`so that their first invocation does not wait for the build. The images built already are kept.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return buildImages()
+	},
+}
+
+var nodePruneImagesCmd = &cobra.Command{
+	Use:   "pruneimages",
+	Short: "Removes the images of the chaincodes no longer installed.",
+	Long: `Removes the images built by the running node of the chaincodes which are no longer installed ` +
+		`on it, such as the former versions of the chaincodes upgraded.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return pruneImages()
+	},
+}
+
+func buildImages() error {
+	if imageChaincodeName != "" && imageChaincodeVersion == "" {
+		return fmt.Errorf("Must supply the version of chaincode %s", imageChaincodeName)
+	}
+	adminClient, err := common.GetAdminClient()
+	if err != nil {
+		logger.Warningf("%s", err)
+		return err
+	}
+
+	images, err := adminClient.BuildChaincodeImages(context.Background(), &pb.ChaincodeImagesRequest{
+		ChaincodeName:    imageChaincodeName,
+		ChaincodeVersion: imageChaincodeVersion,
+	})
+	if err != nil {
+		return fmt.Errorf("Error trying to build the chaincode images of the local peer: %s", err)
+	}
+	return printImages(images)
+}
+
+func pruneImages() error {
+	adminClient, err := common.GetAdminClient()
+	if err != nil {
+		logger.Warningf("%s", err)
+		return err
+	}
+
+	images, err := adminClient.RemoveStaleChaincodeImages(context.Background(), &empty.Empty{})
+	if err != nil {
+		return fmt.Errorf("Error trying to remove the chaincode images of the local peer: %s", err)
+	}
+	return printImages(images)
+}
+
+// printImages prints the outcome for each image, and fails if any of them failed
+func printImages(images *pb.ChaincodeImages) error {
+	failed := 0
+	for _, image := range images.Images {
+		if image.State == pb.ChaincodeImage_FAILED {
+			failed++
+			fmt.Printf("%s:%s %s: %s\n", image.ChaincodeName, image.ChaincodeVersion, image.State, image.Error)
+			continue
+		}
+		fmt.Printf("%s:%s %s\n", image.ChaincodeName, image.ChaincodeVersion, image.State)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of the %d chaincode images failed", failed, len(images.Images))
+	}
+	return nil
+}
@@ -25,8 +25,8 @@ import (
 
 const (
 	nodeFuncName = "node"
-	shortDes     = "Operate a peer node: start|status."
-	longDes      = "Operate a peer node: start|status."
+	shortDes     = "Operate a peer node: start|status|compact|exportstate|importstate|buildimages|pruneimages|doctor."
+	longDes      = "Operate a peer node: start|status|compact|exportstate|importstate|buildimages|pruneimages|doctor."
 )
 
 var logger = flogging.MustGetLogger("nodeCmd")
@@ -35,6 +35,12 @@ var logger = flogging.MustGetLogger("nodeCmd")
 func Cmd() *cobra.Command {
 	nodeCmd.AddCommand(startCmd())
 	nodeCmd.AddCommand(statusCmd())
+	nodeCmd.AddCommand(compactCmd())
+	nodeCmd.AddCommand(exportStateCmd())
+	nodeCmd.AddCommand(importStateCmd())
+	nodeCmd.AddCommand(buildImagesCmd())
+	nodeCmd.AddCommand(pruneImagesCmd())
+	nodeCmd.AddCommand(doctorCmd())
 
 	return nodeCmd
 }
@@ -0,0 +1,115 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/fabric/common/localmsp"
+	"github.com/hyperledger/fabric/core/config"
+	"github.com/hyperledger/fabric/core/peer"
+	"github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/spf13/viper"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// recoveryConf is the configuration of the recovery of the channels whose ledger lost its blocks
+type recoveryConf struct {
+	Orderers   []string
+	TLSEnabled bool
+	RootCert   string
+	Timeout    time.Duration
+}
+
+// getRecoveryConf reads the configuration of the recovery of the channels whose ledger lost its blocks
+func getRecoveryConf() recoveryConf {
+	return recoveryConf{
+		Orderers:   viper.GetStringSlice("ledger.recovery.orderers"),
+		TLSEnabled: viper.GetBool("ledger.recovery.tls.enabled"),
+		RootCert:   config.GetPath("ledger.recovery.tls.rootcert.file"),
+		Timeout:    viper.GetDuration("ledger.recovery.timeout"),
+	}
+}
+
+// newGenesisBlockFetcher returns the fetcher of the genesis blocks of the channels, which asks the orderers
+// of the configuration in turn until one of them delivers the block
+func newGenesisBlockFetcher(conf recoveryConf) (peer.GenesisBlockFetcher, error) {
+	if len(conf.Orderers) == 0 {
+		return nil, fmt.Errorf("no orderer is configured in ledger.recovery.orderers")
+	}
+	if conf.Timeout <= 0 {
+		return nil, fmt.Errorf("invalid ledger.recovery.timeout %s, must be positive", conf.Timeout)
+	}
+	opts := []grpc.DialOption{grpc.WithBlock()}
+	if conf.TLSEnabled {
+		creds, err := credentials.NewClientTLSFromFile(conf.RootCert, "")
+		if err != nil {
+			return nil, fmt.Errorf("cannot load the TLS root certificate of the orderers: %s", err)
+		}
+		opts = append(opts, grpc.WithTransportCredentials(creds))
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+	}
+
+	return func(cid string) (*common.Block, error) {
+		var errs []string
+		for _, address := range conf.Orderers {
+			block, err := fetchGenesisBlock(address, opts, cid, conf.Timeout)
+			if err == nil {
+				return block, nil
+			}
+			logger.Warningf("Failed to fetch the genesis block of channel %s from orderer %s (%s)", cid, address, err)
+			errs = append(errs, fmt.Sprintf("%s: %s", address, err))
+		}
+		return nil, fmt.Errorf("none of the orderers delivered the genesis block: %s", strings.Join(errs, ", "))
+	}, nil
+}
+
+// fetchGenesisBlock pulls the genesis block of the channel from the orderer at address, with a Deliver
+// request signed by the local MSP
+func fetchGenesisBlock(address string, opts []grpc.DialOption, cid string, timeout time.Duration) (*common.Block, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, address, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("cannot connect: %s", err)
+	}
+	defer conn.Close()
+	client, err := ab.NewAtomicBroadcastClient(conn).Deliver(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open the deliver stream: %s", err)
+	}
+
+	genesis := &ab.SeekPosition{Type: &ab.SeekPosition_Specified{Specified: &ab.SeekSpecified{Number: 0}}}
+	seekInfo := &ab.SeekInfo{Start: genesis, Stop: genesis, Behavior: ab.SeekInfo_FAIL_IF_NOT_READY}
+	env, err := utils.CreateSignedEnvelope(common.HeaderType_DELIVER_SEEK_INFO, cid, localmsp.NewSigner(), seekInfo, 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("cannot sign the deliver request: %s", err)
+	}
+	if err = client.Send(env); err != nil {
+		return nil, fmt.Errorf("cannot send the deliver request: %s", err)
+	}
+
+	msg, err := client.Recv()
+	if err != nil {
+		return nil, fmt.Errorf("cannot receive the block: %s", err)
+	}
+	switch t := msg.Type.(type) {
+	case *ab.DeliverResponse_Block:
+		return t.Block, nil
+	case *ab.DeliverResponse_Status:
+		return nil, fmt.Errorf("the orderer replied with status %s", t.Status)
+	default:
+		return nil, fmt.Errorf("unexpected response of type %T", t)
+	}
+}
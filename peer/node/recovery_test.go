@@ -0,0 +1,78 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/msp/mgmt/testtools"
+	"github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// mockGenesisOrderer delivers the genesis block of a single channel
+type mockGenesisOrderer struct {
+	chainID string
+}
+
+func (mo *mockGenesisOrderer) Broadcast(srv ab.AtomicBroadcast_BroadcastServer) error {
+	return nil
+}
+
+func (mo *mockGenesisOrderer) Deliver(srv ab.AtomicBroadcast_DeliverServer) error {
+	env, err := srv.Recv()
+	if err != nil {
+		return err
+	}
+	payload, err := utils.UnmarshalPayload(env.Payload)
+	if err != nil {
+		return err
+	}
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return err
+	}
+	if chdr.ChannelId != mo.chainID {
+		return srv.Send(&ab.DeliverResponse{Type: &ab.DeliverResponse_Status{Status: common.Status_NOT_FOUND}})
+	}
+	return srv.Send(&ab.DeliverResponse{Type: &ab.DeliverResponse_Block{Block: common.NewBlock(0, nil)}})
+}
+
+func TestGenesisBlockFetcher(t *testing.T) {
+	require.NoError(t, msptesttools.LoadMSPSetupForTesting())
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	ab.RegisterAtomicBroadcastServer(server, &mockGenesisOrderer{chainID: "foo"})
+	go server.Serve(l)
+	defer server.Stop()
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	unreachable := closed.Addr().String()
+	closed.Close()
+
+	_, err = newGenesisBlockFetcher(recoveryConf{Timeout: time.Second})
+	assert.Error(t, err, "Expected an error without orderers")
+	_, err = newGenesisBlockFetcher(recoveryConf{Orderers: []string{l.Addr().String()}})
+	assert.Error(t, err, "Expected an error without timeout")
+	_, err = newGenesisBlockFetcher(recoveryConf{Orderers: []string{l.Addr().String()}, Timeout: time.Second, TLSEnabled: true, RootCert: "/nonexistent"})
+	assert.Error(t, err, "Expected an error for a missing root certificate")
+
+	fetch, err := newGenesisBlockFetcher(recoveryConf{Orderers: []string{unreachable, l.Addr().String()}, Timeout: time.Second})
+	require.NoError(t, err)
+	block, err := fetch("foo")
+	require.NoError(t, err, "Expected the next orderer to be asked")
+	assert.Equal(t, uint64(0), block.Header.Number)
+	_, err = fetch("bar")
+	assert.Error(t, err, "Expected an error for a channel none of the orderers has")
+}
@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package node
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"io/ioutil"
 	"net"
@@ -18,17 +20,29 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/hyperledger/fabric/common/crypto/fips"
+	"github.com/hyperledger/fabric/common/diskwatch"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/localmsp"
+	"github.com/hyperledger/fabric/common/viperutil"
 	"github.com/hyperledger/fabric/core"
+	"github.com/hyperledger/fabric/core/audit"
 	"github.com/hyperledger/fabric/core/chaincode"
 	"github.com/hyperledger/fabric/core/comm"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/committer/txvalidator"
 	"github.com/hyperledger/fabric/core/config"
+	cutil "github.com/hyperledger/fabric/core/container/util"
 	"github.com/hyperledger/fabric/core/endorser"
+	"github.com/hyperledger/fabric/core/gateway"
+	"github.com/hyperledger/fabric/core/ledger/backup"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
 	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
+	"github.com/hyperledger/fabric/core/ledger/util/couchdb"
+	"github.com/hyperledger/fabric/core/operations"
 	"github.com/hyperledger/fabric/core/peer"
 	"github.com/hyperledger/fabric/core/scc"
+	"github.com/hyperledger/fabric/events/bridge"
 	"github.com/hyperledger/fabric/events/producer"
 	"github.com/hyperledger/fabric/gossip/service"
 	"github.com/hyperledger/fabric/msp/mgmt"
@@ -42,6 +56,133 @@ import (
 	"google.golang.org/grpc/grpclog"
 )
 
+// getAuditConf reads the configuration of the audit trail of administrative operations
+func getAuditConf() *audit.Conf {
+	path := config.GetPath("peer.audit.path")
+	if path == "" {
+		path = filepath.Join(config.GetPath("peer.fileSystemPath"), "audit", "audit.log")
+	}
+	return &audit.Conf{
+		Path:        path,
+		MaxFileSize: int64(viper.GetSizeInBytes("peer.audit.maxFileSize")),
+		MaxBackups:  viper.GetInt("peer.audit.maxBackups"),
+	}
+}
+
+// getDiskWatchConf reads the configuration of the monitoring of the free space of the ledgers
+func getDiskWatchConf() diskwatch.Conf {
+	return diskwatch.Conf{
+		Paths:            []string{config.GetPath("peer.fileSystemPath")},
+		Interval:         viper.GetDuration("peer.diskWatch.interval"),
+		WarningFreeSpace: uint64(viper.GetSizeInBytes("peer.diskWatch.warningFreeSpace")),
+		QuiesceFreeSpace: uint64(viper.GetSizeInBytes("peer.diskWatch.quiesceFreeSpace")),
+	}
+}
+
+// getValidationConcurrencyConf reads the configuration of the workers validating the transactions
+func getValidationConcurrencyConf() txvalidator.ConcurrencyConf {
+	return txvalidator.ConcurrencyConf{
+		Workers:    viper.GetInt("peer.validator.workers"),
+		Adaptive:   viper.GetBool("peer.validator.adaptive.enabled"),
+		MinWorkers: viper.GetInt("peer.validator.adaptive.minWorkers"),
+		MaxWorkers: viper.GetInt("peer.validator.adaptive.maxWorkers"),
+	}
+}
+
+// getBackupConf reads the configuration of the periodic backups of the ledgers
+func getBackupConf() backup.Conf {
+	return backup.Conf{
+		Interval:  viper.GetDuration("ledger.backup.interval"),
+		Method:    viper.GetString("ledger.backup.method"),
+		Directory: config.GetPath("ledger.backup.directory"),
+		Command:   viper.GetString("ledger.backup.command"),
+		Retention: viper.GetInt("ledger.backup.retention"),
+	}
+}
+
+// newEventBridge creates the bridge republishing the events of the channels to the message bus
+// configured under peer.eventBridge
+func newEventBridge() (*bridge.Bridge, error) {
+	tlsConfig, err := getEventBridgeTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	publisher, err := newEventPublisher(tlsConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to the message bus: %s", err)
+	}
+
+	checkpointDir := config.GetPath("peer.eventBridge.checkpointDir")
+	if checkpointDir == "" {
+		checkpointDir = filepath.Join(config.GetPath("peer.fileSystemPath"), "eventbridge")
+	}
+	b, err := bridge.New(bridge.Config{
+		BlockTopic:          viper.GetString("peer.eventBridge.blockTopic"),
+		ChaincodeEventTopic: viper.GetString("peer.eventBridge.chaincodeEventTopic"),
+		Chaincodes:          viper.GetStringSlice("peer.eventBridge.chaincodes"),
+		CheckpointDir:       checkpointDir,
+		RetryInterval:       viper.GetDuration("peer.eventBridge.retryInterval"),
+		FromGenesis:         viper.GetBool("peer.eventBridge.fromGenesis"),
+	}, publisher)
+	if err != nil {
+		publisher.Close()
+		return nil, err
+	}
+	return b, nil
+}
+
+// newEventPublisher connects to the message bus selected by peer.eventBridge.bus
+func newEventPublisher(tlsConfig *tls.Config) (bridge.Publisher, error) {
+	switch bus := viper.GetString("peer.eventBridge.bus"); bus {
+	case "kafka":
+		version, err := viperutil.ParseKafkaVersion(viper.GetString("peer.eventBridge.kafka.version"))
+		if err != nil {
+			return nil, err
+		}
+		return bridge.NewKafkaPublisher(bridge.KafkaConfig{
+			Brokers: viper.GetStringSlice("peer.eventBridge.kafka.brokers"),
+			Version: version,
+			TLS:     tlsConfig,
+		})
+	case "nats":
+		return bridge.NewNATSPublisher(bridge.NATSConfig{
+			Address: viper.GetString("peer.eventBridge.nats.address"),
+			Timeout: viper.GetDuration("peer.eventBridge.nats.timeout"),
+			TLS:     tlsConfig,
+		})
+	default:
+		return nil, fmt.Errorf("unknown message bus %s, expecting kafka or nats", bus)
+	}
+}
+
+// getEventBridgeTLSConfig reads the TLS configuration of the connections to the message bus, nil
+// if TLS is disabled
+func getEventBridgeTLSConfig() (*tls.Config, error) {
+	if !viper.GetBool("peer.eventBridge.tls.enabled") {
+		return nil, nil
+	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if rootCert := config.GetPath("peer.eventBridge.tls.rootCert.file"); rootCert != "" {
+		pem, err := ioutil.ReadFile(rootCert)
+		if err != nil {
+			return nil, fmt.Errorf("error reading the root certificate of the message bus: %s", err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("invalid root certificate for the message bus in %s", rootCert)
+		}
+	}
+	if clientCert := config.GetPath("peer.eventBridge.tls.clientCert.file"); clientCert != "" {
+		keyPair, err := tls.LoadX509KeyPair(clientCert, config.GetPath("peer.eventBridge.tls.clientKey.file"))
+		if err != nil {
+			return nil, fmt.Errorf("error loading the client certificate for the message bus: %s", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{keyPair}
+	}
+	return fips.ConfigureTLS(tlsConfig), nil
+}
+
 //function used by chaincode support
 type ccEndpointFunc func() (*pb.PeerEndpoint, error)
 
@@ -139,11 +280,57 @@ func serve(args []string) error {
 
 	logger.Debugf("Running peer")
 
+	if viper.GetBool("peer.audit.enabled") {
+		if err := audit.Init(getAuditConf(), localmsp.NewSigner()); err != nil {
+			logger.Fatalf("Failed to initialize the audit trail (%s)", err)
+		}
+	}
+
+	var watcher *diskwatch.Watcher
+	if viper.GetBool("peer.diskWatch.enabled") {
+		watcher, err = diskwatch.New(getDiskWatchConf())
+		if err != nil {
+			logger.Fatalf("Failed to initialize the disk watcher (%s)", err)
+		}
+		watcher.Start()
+		peer.SetDiskWatcher(watcher)
+	}
+
+	concurrency, err := txvalidator.NewConcurrency(getValidationConcurrencyConf())
+	if err != nil {
+		logger.Fatalf("Failed to initialize the validation workers (%s)", err)
+	}
+	peer.SetValidationConcurrency(concurrency)
+
+	if viper.GetBool("ledger.backup.enabled") {
+		scheduler, err := backup.New(getBackupConf(), ledgermgmt.Backup)
+		if err != nil {
+			logger.Fatalf("Failed to initialize the ledger backups (%s)", err)
+		}
+		scheduler.Start()
+		defer scheduler.Stop()
+	}
+
+	if viper.GetBool("peer.eventBridge.enabled") {
+		eventBridge, err := newEventBridge()
+		if err != nil {
+			logger.Fatalf("Failed to initialize the event bridge (%s)", err)
+		}
+		defer eventBridge.Close()
+		peer.SetEventBridge(eventBridge)
+	}
+
 	// Register the Admin server
 	pb.RegisterAdminServer(peerServer.Server(), core.NewAdminServer())
 
 	// Register the Endorser server
-	serverEndorser := endorser.NewEndorserServer()
+	var serverEndorser pb.EndorserServer
+	if viper.GetBool("peer.replica.enabled") {
+		logger.Info("Starting as a read-only replica, proposals writing to the ledger will not be endorsed")
+		serverEndorser = endorser.NewReadOnlyEndorserServer()
+	} else {
+		serverEndorser = endorser.NewEndorserServer()
+	}
 	pb.RegisterEndorserServer(peerServer.Server(), serverEndorser)
 
 	// Initialize gossip component
@@ -184,9 +371,37 @@ func serve(args []string) error {
 	}
 	defer service.GetGossipService().Stop()
 
+	if viper.GetBool("peer.gateway.enabled") {
+		timeout := viper.GetDuration("peer.gateway.endorsementTimeout")
+		if timeout <= 0 {
+			logger.Fatalf("Invalid peer.gateway.endorsementTimeout %s, must be positive", timeout)
+		}
+		notifier := gateway.NewNotifier()
+		peer.SetCommitListener(notifier)
+		breakerConf := comm.BreakerConfig{
+			FailureThreshold: viper.GetInt("peer.gateway.ordererBreaker.failureThreshold"),
+			OpenInterval:     viper.GetDuration("peer.gateway.ordererBreaker.openInterval"),
+			ProbeInterval:    viper.GetDuration("peer.gateway.ordererBreaker.probeInterval"),
+		}
+		if breakerConf.FailureThreshold <= 0 {
+			logger.Fatalf("Invalid peer.gateway.ordererBreaker.failureThreshold %d, must be positive", breakerConf.FailureThreshold)
+		}
+		ordererBreaker := comm.NewEndpointBreaker("gateway.orderer", breakerConf)
+		support := gateway.NewSupport(notifier, secureDialOpts, ordererBreaker)
+		pb.RegisterGatewayServer(peerServer.Server(), gateway.NewServer(peerEndpoint.Address, serverEndorser, support, timeout))
+	}
+
 	//initialize system chaincodes
 	initSysCCs()
 
+	if viper.GetBool("ledger.recovery.enabled") {
+		fetcher, err := newGenesisBlockFetcher(getRecoveryConf())
+		if err != nil {
+			logger.Fatalf("Failed to initialize the recovery of the lost ledgers (%s)", err)
+		}
+		peer.SetGenesisBlockFetcher(fetcher)
+	}
+
 	//this brings up all the chains (including testchainid)
 	peer.Initialize(func(cid string) {
 		logger.Debugf("Deploying system CC, for chain <%s>", cid)
@@ -227,6 +442,16 @@ func serve(args []string) error {
 		go ehubGrpcServer.Start()
 	}
 
+	// Start the gRPC-web endpoint if enabled
+	if viper.GetBool("peer.grpcWeb.enabled") {
+		go serveGRPCWeb(peerServer, ehubGrpcServer)
+	}
+
+	// Start the operations endpoint if enabled
+	if viper.GetBool("peer.operations.enabled") {
+		go serveOperations(peerServer, peerEndpoint.Id.Name, watcher)
+	}
+
 	// Start profiling http endpoint if enabled
 	if viper.GetBool("peer.profile.enabled") {
 		go func() {
@@ -361,11 +586,152 @@ func createEventHubServer(secureConfig comm.SecureServerConfig) (comm.GRPCServer
 	ehServer := producer.NewEventsServer(
 		uint(viper.GetInt("peer.events.buffersize")),
 		viper.GetDuration("peer.events.timeout"))
+	ehServer.SetAccessControl(&producer.AccessControl{
+		Policies: map[pb.EventType]string{
+			pb.EventType_BLOCK:     viper.GetString("peer.events.acl.block"),
+			pb.EventType_CHAINCODE: viper.GetString("peer.events.acl.chaincode"),
+			pb.EventType_REJECTION: viper.GetString("peer.events.acl.rejection"),
+		},
+		PolicyManager: peer.GetPolicyManager,
+		Channels: func() []string {
+			var channels []string
+			for _, info := range peer.GetChannelsInfo() {
+				channels = append(channels, info.ChannelId)
+			}
+			return channels
+		},
+		MaxSubscriptions: viper.GetInt("peer.events.maxSubscriptions"),
+	})
+	ehServer.SetAckDelivery(&producer.AckDelivery{
+		MaxBuffered: viper.GetInt("peer.events.ack.maxBuffered"),
+		Overflow:    viper.GetString("peer.events.ack.overflow"),
+		Timeout:     viper.GetDuration("peer.events.ack.timeout"),
+		MaxWindow:   uint32(viper.GetInt("peer.events.ack.maxWindow")),
+	})
 
 	pb.RegisterEventsServer(grpcServer.Server(), ehServer)
 	return grpcServer, nil
 }
 
+// serveGRPCWeb serves the services of the peer and of its event hub to the browsers, which speak
+// gRPC-web, on the same TLS configuration as the peer
+func serveGRPCWeb(peerServer, ehubGrpcServer comm.GRPCServer) {
+	address := viper.GetString("peer.grpcWeb.address")
+	allowedOrigins := viper.GetStringSlice("peer.grpcWeb.allowedOrigins")
+	mux := http.NewServeMux()
+	mux.Handle("/", comm.NewGRPCWebHandler(peerServer.Server(), allowedOrigins))
+	if ehubGrpcServer != nil {
+		mux.Handle("/protos.Events/", comm.NewGRPCWebHandler(ehubGrpcServer.Server(), allowedOrigins))
+	}
+	srv := &http.Server{Addr: address, Handler: mux}
+
+	logger.Infof("Starting gRPC-web server with address = %s", address)
+	var err error
+	if peerServer.TLSEnabled() {
+		srv.TLSConfig = fips.ConfigureTLS(&tls.Config{Certificates: []tls.Certificate{peerServer.ServerCertificate()}})
+		err = srv.ListenAndServeTLS("", "")
+	} else {
+		err = srv.ListenAndServe()
+	}
+	logger.Errorf("Error serving gRPC-web: %s", err)
+}
+
+// serveOperations serves the data of the ops dashboards on the operations endpoint. The disk
+// watcher, if any, reports the peer as unhealthy while it is quiesced
+func serveOperations(peerServer comm.GRPCServer, peerID string, watcher *diskwatch.Watcher) {
+	timeout := viper.GetDuration("peer.operations.healthCheckTimeout")
+	if timeout <= 0 {
+		logger.Fatalf("Invalid peer.operations.healthCheckTimeout %s, must be positive", timeout)
+	}
+	handler := operations.NewHandler(peerID, operations.NewSupport(), timeout)
+
+	if ledgerconfig.IsCouchDBEnabled() {
+		def := couchdb.GetCouchDBDefinition()
+		couchInstance, err := couchdb.CreateCouchInstance(def.URL, def.Username, def.Password,
+			def.MaxRetries, def.MaxRetriesOnStartup, def.RequestTimeout)
+		if err != nil {
+			logger.Errorf("Failed connecting to CouchDB for its health checks: %s", err)
+			handler.RegisterChecker("couchdb", operations.HealthCheckerFunc(func() error { return err }))
+		} else {
+			handler.RegisterChecker("couchdb", couchInstance)
+		}
+	}
+	handler.RegisterChecker("docker", operations.HealthCheckerFunc(func() error {
+		client, err := cutil.NewDockerClient()
+		if err != nil {
+			return err
+		}
+		return client.Ping()
+	}))
+	if watcher != nil {
+		handler.RegisterChecker("diskwatch", watcher)
+	}
+
+	address := viper.GetString("peer.operations.listenAddress")
+	tlsConfig, err := getOperationsTLSConfig(peerServer, address)
+	if err != nil {
+		logger.Fatalf("Invalid operations endpoint configuration: %s", err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/dashboard", handler)
+	srv := &http.Server{Addr: address, Handler: mux, TLSConfig: tlsConfig}
+
+	logger.Infof("Starting operations server with listenAddress = %s", address)
+	if tlsConfig != nil {
+		err = srv.ListenAndServeTLS("", "")
+	} else {
+		err = srv.ListenAndServe()
+	}
+	logger.Errorf("Error serving the operations endpoint: %s", err)
+}
+
+// getOperationsTLSConfig returns the TLS configuration of the operations endpoint, nil without TLS.
+// As the endpoint discloses the membership and the chaincodes of the channels, it must either listen
+// on a loopback address or require the clients to present a certificate issued by one of the
+// authorities of peer.operations.tls.clientRootCAs.file, over the TLS of the peer
+func getOperationsTLSConfig(peerServer comm.GRPCServer, address string) (*tls.Config, error) {
+	clientRootCAsFile := config.GetPath("peer.operations.tls.clientRootCAs.file")
+	if clientRootCAsFile == "" {
+		if !isLoopbackAddress(address) {
+			return nil, fmt.Errorf("listenAddress %s is not a loopback address, which requires peer.operations.tls.clientRootCAs.file", address)
+		}
+		if !peerServer.TLSEnabled() {
+			return nil, nil
+		}
+		return fips.ConfigureTLS(&tls.Config{Certificates: []tls.Certificate{peerServer.ServerCertificate()}}), nil
+	}
+
+	if !peerServer.TLSEnabled() {
+		return nil, fmt.Errorf("peer.operations.tls.clientRootCAs.file requires peer.tls.enabled")
+	}
+	pemCerts, err := ioutil.ReadFile(clientRootCAsFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the client root CAs: %s", err)
+	}
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(pemCerts) {
+		return nil, fmt.Errorf("no client root CA certificate found in %s", clientRootCAsFile)
+	}
+	return fips.ConfigureTLS(&tls.Config{
+		Certificates: []tls.Certificate{peerServer.ServerCertificate()},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+	}), nil
+}
+
+// isLoopbackAddress returns whether the host of the address is localhost or a loopback IP
+func isLoopbackAddress(address string) bool {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
 func writePid(fileName string, pid int) error {
 	err := os.MkdirAll(filepath.Dir(fileName), 0755)
 	if err != nil {
@@ -17,13 +17,17 @@ limitations under the License.
 package node
 
 import (
+	"crypto/tls"
+	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strconv"
 	"syscall"
 	"testing"
 	"time"
 
+	"github.com/hyperledger/fabric/core/comm"
 	"github.com/hyperledger/fabric/msp/mgmt/testtools"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
@@ -116,3 +120,75 @@ func TestWritePid(t *testing.T) {
 		})
 	}
 }
+
+func TestNewEventBridge(t *testing.T) {
+	defer viper.Reset()
+
+	viper.Set("peer.eventBridge.bus", "rabbitmq")
+	_, err := newEventBridge()
+	assert.Error(t, err, "Should reject an unknown message bus")
+
+	viper.Set("peer.eventBridge.bus", "kafka")
+	viper.Set("peer.eventBridge.kafka.version", "0.7")
+	_, err = newEventBridge()
+	assert.Error(t, err, "Should reject an unsupported Kafka version")
+
+	viper.Set("peer.eventBridge.bus", "nats")
+	viper.Set("peer.eventBridge.nats.address", "127.0.0.1:1")
+	viper.Set("peer.eventBridge.nats.timeout", "1s")
+	_, err = newEventBridge()
+	assert.Error(t, err, "Should fail without a NATS server")
+
+	viper.Set("peer.eventBridge.tls.enabled", true)
+	viper.Set("peer.eventBridge.tls.rootCert.file", "/does/not/exist")
+	_, err = newEventBridge()
+	assert.Error(t, err, "Should fail without the root certificate")
+}
+
+type mockOperationsServer struct {
+	comm.GRPCServer
+	tlsEnabled bool
+}
+
+func (m *mockOperationsServer) TLSEnabled() bool {
+	return m.tlsEnabled
+}
+
+func (m *mockOperationsServer) ServerCertificate() tls.Certificate {
+	return tls.Certificate{}
+}
+
+func TestGetOperationsTLSConfig(t *testing.T) {
+	defer viper.Reset()
+	server := &mockOperationsServer{}
+
+	tlsConfig, err := getOperationsTLSConfig(server, "127.0.0.1:9443")
+	assert.NoError(t, err)
+	assert.Nil(t, tlsConfig, "Should serve without TLS when the peer has none")
+
+	server.tlsEnabled = true
+	tlsConfig, err = getOperationsTLSConfig(server, "localhost:9443")
+	assert.NoError(t, err)
+	assert.Equal(t, tls.NoClientCert, tlsConfig.ClientAuth)
+
+	for _, address := range []string{"0.0.0.0:9443", ":9443", "peer0.org1.example.com:9443"} {
+		_, err = getOperationsTLSConfig(server, address)
+		assert.EqualError(t, err, fmt.Sprintf("listenAddress %s is not a loopback address, which requires peer.operations.tls.clientRootCAs.file", address))
+	}
+
+	viper.Set("peer.operations.tls.clientRootCAs.file", "/does/not/exist")
+	_, err = getOperationsTLSConfig(server, "0.0.0.0:9443")
+	assert.Error(t, err, "Should fail without the client root CAs")
+
+	caFile, err := filepath.Abs("../../core/comm/testdata/certs/Org1-cert.pem")
+	assert.NoError(t, err)
+	viper.Set("peer.operations.tls.clientRootCAs.file", caFile)
+	tlsConfig, err = getOperationsTLSConfig(server, "0.0.0.0:9443")
+	assert.NoError(t, err)
+	assert.Equal(t, tls.RequireAndVerifyClientCert, tlsConfig.ClientAuth)
+	assert.NotNil(t, tlsConfig.ClientCAs)
+
+	server.tlsEnabled = false
+	_, err = getOperationsTLSConfig(server, "0.0.0.0:9443")
+	assert.EqualError(t, err, "peer.operations.tls.clientRootCAs.file requires peer.tls.enabled")
+}
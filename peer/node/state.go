@@ -0,0 +1,87 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hyperledger/fabric/core/ledger/kvledger"
+	"github.com/spf13/cobra"
+)
+
+var (
+	stateChannelID  string
+	stateNamespaces []string
+	stateDir        string
+)
+
+func exportStateCmd() *cobra.Command {
+	flags := nodeExportStateCmd.Flags()
+	flags.StringVarP(&stateChannelID, "channelID", "c", "", "The channel whose state is exported")
+	flags.StringSliceVarP(&stateNamespaces, "namespaces", "n", nil, "Comma separated list of the namespaces (chaincode names) to export")
+	flags.StringVarP(&stateDir, "dir", "d", "", "The directory to export the state into, created if missing; must be empty")
+	return nodeExportStateCmd
+}
+
+func importStateCmd() *cobra.Command {
+	flags := nodeImportStateCmd.Flags()
+	flags.StringVarP(&stateChannelID, "channelID", "c", "", "The channel whose state is imported")
+	flags.StringVarP(&stateDir, "dir", "d", "", "The directory containing a state export")
+	return nodeImportStateCmd
+}
+
+var nodeExportStateCmd = &cobra.Command{
+	Use:   "exportstate",
+	Short: "Exports the state of namespaces of a channel.",
+	Long: `Exports the state of the given namespaces of a channel into a directory, along with a manifest ` +
+		`recording the SHA-256 hash of each exported file. Must be run while the peer is stopped.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return exportState()
+	},
+}
+
+var nodeImportStateCmd = &cobra.Command{
+	Use:   "importstate",
+	Short: "Imports a state export into a channel.",
+	Long: `Verifies the hashes of a state export and imports it into the state of a channel the peer has ` +
+		`already joined. Must be run while the peer is stopped.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return importState()
+	},
+}
+
+func exportState() error {
+	if stateChannelID == "" || stateDir == "" {
+		return errors.New("Must supply channel ID and directory")
+	}
+	if len(stateNamespaces) == 0 {
+		return errors.New("Must supply at least one namespace")
+	}
+	manifest, err := kvledger.ExportState(stateChannelID, stateNamespaces, stateDir)
+	if err != nil {
+		return fmt.Errorf("Error exporting the state of channel [%s]: %s", stateChannelID, err)
+	}
+	for _, ns := range manifest.Namespaces {
+		fmt.Printf("Exported %d entries of namespace %s (sha256: %s)\n", ns.NumEntries, ns.Namespace, ns.SHA256)
+	}
+	return nil
+}
+
+func importState() error {
+	if stateChannelID == "" || stateDir == "" {
+		return errors.New("Must supply channel ID and directory")
+	}
+	manifest, err := kvledger.ImportState(stateChannelID, stateDir)
+	if err != nil {
+		return fmt.Errorf("Error importing the state of channel [%s]: %s", stateChannelID, err)
+	}
+	for _, ns := range manifest.Namespaces {
+		fmt.Printf("Imported %d entries of namespace %s\n", ns.NumEntries, ns.Namespace)
+	}
+	return nil
+}
@@ -33,6 +33,11 @@ func (e *Envelope) StaticallyOpaqueFieldProto(name string) (proto.Message, error
 	return &Payload{}, nil
 }
 
+// PayloadDataMap breaks the dependency cycle the same way the ChannelGroupMap does: the
+// protos/orderer and protos/peer packages register the messages carried by the data of the
+// payloads of their header types when they are loaded
+var PayloadDataMap = map[HeaderType]func() proto.Message{}
+
 func (p *Payload) VariablyOpaqueFields() []string {
 	return []string{"data"}
 }
@@ -54,8 +59,10 @@ func (p *Payload) VariablyOpaqueFieldProto(name string) (proto.Message, error) {
 		return &ConfigEnvelope{}, nil
 	case int32(HeaderType_CONFIG_UPDATE):
 		return &ConfigUpdateEnvelope{}, nil
-	// TODO implement the other well known types, particularly endorser txs
 	default:
+		if newMsg, ok := PayloadDataMap[HeaderType(ch.Type)]; ok {
+			return newMsg(), nil
+		}
 		return nil, fmt.Errorf("decoding type %v is unimplemented", ch.Type)
 	}
 }
@@ -13,6 +13,8 @@ It is generated from these files:
 
 It has these top-level messages:
 	LastConfig
+	MetadataExtension
+	MetadataExtensions
 	Metadata
 	MetadataSignature
 	Header
@@ -24,6 +26,7 @@ It has these top-level messages:
 	BlockHeader
 	BlockData
 	BlockMetadata
+	InclusionProof
 	ConfigEnvelope
 	ConfigGroupSchema
 	ConfigValueSchema
@@ -40,10 +43,15 @@ It has these top-level messages:
 	OrdererAddresses
 	Consortium
 	BlockchainInfo
+	LedgerBundle
+	LedgerBundleContent
+	LedgerBundleOrg
 	Policy
 	SignaturePolicyEnvelope
 	SignaturePolicy
 	ImplicitMetaPolicy
+	PolicyDecision
+	IdentityCheck
 */
 package common
 
@@ -147,6 +155,9 @@ const (
 	BlockMetadataIndex_LAST_CONFIG         BlockMetadataIndex = 1
 	BlockMetadataIndex_TRANSACTIONS_FILTER BlockMetadataIndex = 2
 	BlockMetadataIndex_ORDERER             BlockMetadataIndex = 3
+	// e.g. For Kafka, this is where we store the last offset written to the local ledger.
+	BlockMetadataIndex_TRACE_IDS  BlockMetadataIndex = 4
+	BlockMetadataIndex_EXTENSIONS BlockMetadataIndex = 5
 )
 
 var BlockMetadataIndex_name = map[int32]string{
@@ -154,12 +165,16 @@ var BlockMetadataIndex_name = map[int32]string{
 	1: "LAST_CONFIG",
 	2: "TRANSACTIONS_FILTER",
 	3: "ORDERER",
+	4: "TRACE_IDS",
+	5: "EXTENSIONS",
 }
 var BlockMetadataIndex_value = map[string]int32{
 	"SIGNATURES":          0,
 	"LAST_CONFIG":         1,
 	"TRANSACTIONS_FILTER": 2,
 	"ORDERER":             3,
+	"TRACE_IDS":           4,
+	"EXTENSIONS":          5,
 }
 
 func (x BlockMetadataIndex) String() string {
@@ -184,6 +199,58 @@ func (m *LastConfig) GetIndex() uint64 {
 	return 0
 }
 
+// MetadataExtension is a typed and versioned value attached to a block, e.g. an attestation set or a bridge
+// checkpoint, so that the consenters and plugins do not collide on raw block metadata positions
+type MetadataExtension struct {
+	Type    string `protobuf:"bytes,1,opt,name=type" json:"type,omitempty"`
+	Version uint32 `protobuf:"varint,2,opt,name=version" json:"version,omitempty"`
+	Value   []byte `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (m *MetadataExtension) Reset()                    { *m = MetadataExtension{} }
+func (m *MetadataExtension) String() string            { return proto.CompactTextString(m) }
+func (*MetadataExtension) ProtoMessage()               {}
+func (*MetadataExtension) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+func (m *MetadataExtension) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *MetadataExtension) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *MetadataExtension) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+// MetadataExtensions is the encoded value for the Metadata message which is encoded in the EXTENSIONS block
+// metadata index, holding at most one extension per type
+type MetadataExtensions struct {
+	Extensions []*MetadataExtension `protobuf:"bytes,1,rep,name=extensions" json:"extensions,omitempty"`
+}
+
+func (m *MetadataExtensions) Reset()                    { *m = MetadataExtensions{} }
+func (m *MetadataExtensions) String() string            { return proto.CompactTextString(m) }
+func (*MetadataExtensions) ProtoMessage()               {}
+func (*MetadataExtensions) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+func (m *MetadataExtensions) GetExtensions() []*MetadataExtension {
+	if m != nil {
+		return m.Extensions
+	}
+	return nil
+}
+
 // Metadata is a common structure to be used to encode block metadata
 type Metadata struct {
 	Value      []byte               `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
@@ -193,7 +260,7 @@ type Metadata struct {
 func (m *Metadata) Reset()                    { *m = Metadata{} }
 func (m *Metadata) String() string            { return proto.CompactTextString(m) }
 func (*Metadata) ProtoMessage()               {}
-func (*Metadata) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+func (*Metadata) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
 
 func (m *Metadata) GetValue() []byte {
 	if m != nil {
@@ -217,7 +284,7 @@ type MetadataSignature struct {
 func (m *MetadataSignature) Reset()                    { *m = MetadataSignature{} }
 func (m *MetadataSignature) String() string            { return proto.CompactTextString(m) }
 func (*MetadataSignature) ProtoMessage()               {}
-func (*MetadataSignature) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+func (*MetadataSignature) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
 
 func (m *MetadataSignature) GetSignatureHeader() []byte {
 	if m != nil {
@@ -241,7 +308,7 @@ type Header struct {
 func (m *Header) Reset()                    { *m = Header{} }
 func (m *Header) String() string            { return proto.CompactTextString(m) }
 func (*Header) ProtoMessage()               {}
-func (*Header) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+func (*Header) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
 
 func (m *Header) GetChannelHeader() []byte {
 	if m != nil {
@@ -289,7 +356,7 @@ type ChannelHeader struct {
 func (m *ChannelHeader) Reset()                    { *m = ChannelHeader{} }
 func (m *ChannelHeader) String() string            { return proto.CompactTextString(m) }
 func (*ChannelHeader) ProtoMessage()               {}
-func (*ChannelHeader) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+func (*ChannelHeader) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
 
 func (m *ChannelHeader) GetType() int32 {
 	if m != nil {
@@ -350,7 +417,7 @@ type SignatureHeader struct {
 func (m *SignatureHeader) Reset()                    { *m = SignatureHeader{} }
 func (m *SignatureHeader) String() string            { return proto.CompactTextString(m) }
 func (*SignatureHeader) ProtoMessage()               {}
-func (*SignatureHeader) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+func (*SignatureHeader) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
 
 func (m *SignatureHeader) GetCreator() []byte {
 	if m != nil {
@@ -377,7 +444,7 @@ type Payload struct {
 func (m *Payload) Reset()                    { *m = Payload{} }
 func (m *Payload) String() string            { return proto.CompactTextString(m) }
 func (*Payload) ProtoMessage()               {}
-func (*Payload) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+func (*Payload) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
 
 func (m *Payload) GetHeader() *Header {
 	if m != nil {
@@ -404,7 +471,7 @@ type Envelope struct {
 func (m *Envelope) Reset()                    { *m = Envelope{} }
 func (m *Envelope) String() string            { return proto.CompactTextString(m) }
 func (*Envelope) ProtoMessage()               {}
-func (*Envelope) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+func (*Envelope) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
 
 func (m *Envelope) GetPayload() []byte {
 	if m != nil {
@@ -433,7 +500,7 @@ type Block struct {
 func (m *Block) Reset()                    { *m = Block{} }
 func (m *Block) String() string            { return proto.CompactTextString(m) }
 func (*Block) ProtoMessage()               {}
-func (*Block) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+func (*Block) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
 
 func (m *Block) GetHeader() *BlockHeader {
 	if m != nil {
@@ -468,7 +535,7 @@ type BlockHeader struct {
 func (m *BlockHeader) Reset()                    { *m = BlockHeader{} }
 func (m *BlockHeader) String() string            { return proto.CompactTextString(m) }
 func (*BlockHeader) ProtoMessage()               {}
-func (*BlockHeader) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+func (*BlockHeader) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
 
 func (m *BlockHeader) GetNumber() uint64 {
 	if m != nil {
@@ -498,7 +565,7 @@ type BlockData struct {
 func (m *BlockData) Reset()                    { *m = BlockData{} }
 func (m *BlockData) String() string            { return proto.CompactTextString(m) }
 func (*BlockData) ProtoMessage()               {}
-func (*BlockData) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+func (*BlockData) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
 
 func (m *BlockData) GetData() [][]byte {
 	if m != nil {
@@ -514,7 +581,7 @@ type BlockMetadata struct {
 func (m *BlockMetadata) Reset()                    { *m = BlockMetadata{} }
 func (m *BlockMetadata) String() string            { return proto.CompactTextString(m) }
 func (*BlockMetadata) ProtoMessage()               {}
-func (*BlockMetadata) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+func (*BlockMetadata) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
 
 func (m *BlockMetadata) GetMetadata() [][]byte {
 	if m != nil {
@@ -523,8 +590,62 @@ func (m *BlockMetadata) GetMetadata() [][]byte {
 	return nil
 }
 
+// InclusionProof proves that a transaction is included in a block of a channel, without
+// requiring the verifier to hold the rest of the block. As the BlockData hash is a flat hash
+// over the concatenation of the transactions, which does not delimit them, the proof carries
+// all the entries of the block data, so that the verifier checks where each of them starts
+type InclusionProof struct {
+	ChannelId  string       `protobuf:"bytes,1,opt,name=channel_id,json=channelId" json:"channel_id,omitempty"`
+	Header     *BlockHeader `protobuf:"bytes,2,opt,name=header" json:"header,omitempty"`
+	Signatures []byte       `protobuf:"bytes,3,opt,name=signatures,proto3" json:"signatures,omitempty"`
+	TxIndex    uint64       `protobuf:"varint,4,opt,name=tx_index,json=txIndex" json:"tx_index,omitempty"`
+	Data       [][]byte     `protobuf:"bytes,5,rep,name=data,proto3" json:"data,omitempty"`
+}
+
+func (m *InclusionProof) Reset()                    { *m = InclusionProof{} }
+func (m *InclusionProof) String() string            { return proto.CompactTextString(m) }
+func (*InclusionProof) ProtoMessage()               {}
+func (*InclusionProof) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
+
+func (m *InclusionProof) GetChannelId() string {
+	if m != nil {
+		return m.ChannelId
+	}
+	return ""
+}
+
+func (m *InclusionProof) GetHeader() *BlockHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *InclusionProof) GetSignatures() []byte {
+	if m != nil {
+		return m.Signatures
+	}
+	return nil
+}
+
+func (m *InclusionProof) GetTxIndex() uint64 {
+	if m != nil {
+		return m.TxIndex
+	}
+	return 0
+}
+
+func (m *InclusionProof) GetData() [][]byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*LastConfig)(nil), "common.LastConfig")
+	proto.RegisterType((*MetadataExtension)(nil), "common.MetadataExtension")
+	proto.RegisterType((*MetadataExtensions)(nil), "common.MetadataExtensions")
 	proto.RegisterType((*Metadata)(nil), "common.Metadata")
 	proto.RegisterType((*MetadataSignature)(nil), "common.MetadataSignature")
 	proto.RegisterType((*Header)(nil), "common.Header")
@@ -536,6 +657,7 @@ func init() {
 	proto.RegisterType((*BlockHeader)(nil), "common.BlockHeader")
 	proto.RegisterType((*BlockData)(nil), "common.BlockData")
 	proto.RegisterType((*BlockMetadata)(nil), "common.BlockMetadata")
+	proto.RegisterType((*InclusionProof)(nil), "common.InclusionProof")
 	proto.RegisterEnum("common.Status", Status_name, Status_value)
 	proto.RegisterEnum("common.HeaderType", HeaderType_name, HeaderType_value)
 	proto.RegisterEnum("common.BlockMetadataIndex", BlockMetadataIndex_name, BlockMetadataIndex_value)
@@ -544,62 +666,68 @@ func init() {
 func init() { proto.RegisterFile("common/common.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 900 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x84, 0x55, 0xdf, 0x6e, 0xe3, 0xc4,
-	0x1b, 0xad, 0xe3, 0xfc, 0x69, 0xbe, 0x34, 0xad, 0x3b, 0xd9, 0xfe, 0xd6, 0xbf, 0xc2, 0x6a, 0x23,
-	0xc3, 0xa2, 0xd2, 0x4a, 0x89, 0x28, 0x37, 0x70, 0xe9, 0xd8, 0x93, 0xd6, 0x6a, 0xd6, 0x2e, 0x63,
-	0x67, 0x11, 0xbb, 0x48, 0x96, 0x93, 0x4c, 0x13, 0x8b, 0xc4, 0x8e, 0x6c, 0xa7, 0x6a, 0x5f, 0x02,
-	0x21, 0xc1, 0x0d, 0x17, 0xbc, 0x00, 0x4f, 0xc2, 0x5b, 0xf0, 0x12, 0x48, 0xdc, 0xa2, 0xf1, 0x8c,
-	0xbd, 0x49, 0x59, 0x89, 0xab, 0xcc, 0x39, 0x73, 0x3c, 0xdf, 0x99, 0xf3, 0x7d, 0xb1, 0xa1, 0x33,
-	0x8d, 0x57, 0xab, 0x38, 0xea, 0xf3, 0x9f, 0xde, 0x3a, 0x89, 0xb3, 0x18, 0xd5, 0x39, 0x3a, 0x7d,
-	0x39, 0x8f, 0xe3, 0xf9, 0x92, 0xf6, 0x73, 0x76, 0xb2, 0xb9, 0xeb, 0x67, 0xe1, 0x8a, 0xa6, 0x59,
-	0xb0, 0x5a, 0x73, 0xa1, 0xa6, 0x01, 0x8c, 0x82, 0x34, 0x33, 0xe2, 0xe8, 0x2e, 0x9c, 0xa3, 0x67,
-	0x50, 0x0b, 0xa3, 0x19, 0x7d, 0x50, 0xa5, 0xae, 0x74, 0x56, 0x25, 0x1c, 0x68, 0xef, 0x60, 0xff,
-	0x35, 0xcd, 0x82, 0x59, 0x90, 0x05, 0x4c, 0x71, 0x1f, 0x2c, 0x37, 0x34, 0x57, 0x1c, 0x10, 0x0e,
-	0xd0, 0xd7, 0x00, 0x69, 0x38, 0x8f, 0x82, 0x6c, 0x93, 0xd0, 0x54, 0xad, 0x74, 0xe5, 0xb3, 0xd6,
-	0xe5, 0xff, 0x7b, 0xc2, 0x51, 0xf1, 0xac, 0x5b, 0x28, 0xc8, 0x96, 0x58, 0xfb, 0x1e, 0x8e, 0xff,
-	0x25, 0x40, 0x9f, 0x83, 0x52, 0x4a, 0xfc, 0x05, 0x0d, 0x66, 0x34, 0x11, 0x05, 0x8f, 0x4a, 0xfe,
-	0x3a, 0xa7, 0xd1, 0xc7, 0xd0, 0x2c, 0x29, 0xb5, 0x92, 0x6b, 0xde, 0x13, 0xda, 0x5b, 0xa8, 0x0b,
-	0xdd, 0x2b, 0x38, 0x9c, 0x2e, 0x82, 0x28, 0xa2, 0xcb, 0xdd, 0x03, 0xdb, 0x82, 0x15, 0xb2, 0x0f,
-	0x55, 0xae, 0x7c, 0xb0, 0xb2, 0xf6, 0xa7, 0x04, 0x6d, 0x63, 0xe7, 0x61, 0x04, 0xd5, 0xec, 0x71,
-	0xcd, 0xb3, 0xa9, 0x91, 0x7c, 0x8d, 0x54, 0x68, 0xdc, 0xd3, 0x24, 0x0d, 0xe3, 0x28, 0x3f, 0xa7,
-	0x46, 0x0a, 0x88, 0xbe, 0x82, 0x66, 0xd9, 0x0d, 0x55, 0xee, 0x4a, 0x67, 0xad, 0xcb, 0xd3, 0x1e,
-	0xef, 0x57, 0xaf, 0xe8, 0x57, 0xcf, 0x2b, 0x14, 0xe4, 0xbd, 0x18, 0xbd, 0x00, 0x28, 0xee, 0x12,
-	0xce, 0xd4, 0x6a, 0x57, 0x3a, 0x6b, 0x92, 0xa6, 0x60, 0xac, 0x19, 0xea, 0x40, 0x2d, 0x7b, 0x60,
-	0x3b, 0xb5, 0x7c, 0xa7, 0x9a, 0x3d, 0x58, 0x33, 0xd6, 0x38, 0xba, 0x8e, 0xa7, 0x0b, 0xb5, 0xce,
-	0x5b, 0x9b, 0x03, 0x96, 0x1e, 0x7d, 0xc8, 0x68, 0x94, 0xfb, 0x6b, 0xf0, 0xf4, 0x4a, 0x42, 0xd3,
-	0xe1, 0xc8, 0x7d, 0x12, 0xb7, 0x0a, 0x8d, 0x69, 0x42, 0x83, 0x2c, 0x2e, 0xf2, 0x2b, 0x20, 0x2b,
-	0x10, 0xc5, 0xd1, 0xb4, 0x68, 0x02, 0x07, 0x1a, 0x86, 0xc6, 0x6d, 0xf0, 0xb8, 0x8c, 0x83, 0x19,
-	0xfa, 0x0c, 0xea, 0x5b, 0xc9, 0xb7, 0x2e, 0x0f, 0x8b, 0x01, 0xe1, 0x47, 0x13, 0xb1, 0xcb, 0x52,
-	0x64, 0xd3, 0x20, 0xce, 0xc9, 0xd7, 0xda, 0x00, 0xf6, 0x71, 0x74, 0x4f, 0x97, 0x31, 0x4f, 0x74,
-	0xcd, 0x8f, 0x2c, 0x2c, 0x08, 0xf8, 0x1f, 0xb3, 0xf0, 0xa3, 0x04, 0xb5, 0xc1, 0x32, 0x9e, 0xfe,
-	0x80, 0x2e, 0x9e, 0x38, 0xe9, 0x14, 0x4e, 0xf2, 0xed, 0x27, 0x76, 0x5e, 0x6d, 0xd9, 0x69, 0x5d,
-	0x1e, 0xef, 0x48, 0xcd, 0x20, 0x0b, 0xb8, 0x43, 0xf4, 0x05, 0xec, 0xaf, 0xc4, 0x1c, 0x8b, 0x66,
-	0x9e, 0xec, 0x48, 0x8b, 0x21, 0x27, 0xa5, 0x4c, 0x9b, 0x43, 0x6b, 0xab, 0x20, 0xfa, 0x1f, 0xd4,
-	0xa3, 0xcd, 0x6a, 0x22, 0x5c, 0x55, 0x89, 0x40, 0xe8, 0x13, 0x68, 0xaf, 0x13, 0x7a, 0x1f, 0xc6,
-	0x9b, 0xd4, 0x5f, 0x04, 0xe9, 0x42, 0xdc, 0xec, 0xa0, 0x20, 0xaf, 0x83, 0x74, 0x81, 0x3e, 0x82,
-	0x26, 0x3b, 0x93, 0x0b, 0xe4, 0x5c, 0xb0, 0xcf, 0x08, 0xb6, 0xa9, 0xbd, 0x84, 0x66, 0x69, 0xb7,
-	0x8c, 0x57, 0xea, 0xca, 0x65, 0xbc, 0x17, 0xd0, 0xde, 0x31, 0x89, 0x4e, 0xb7, 0x6e, 0xc3, 0x85,
-	0x25, 0x3e, 0xff, 0x5d, 0x82, 0xba, 0x9b, 0x05, 0xd9, 0x26, 0x45, 0x2d, 0x68, 0x8c, 0xed, 0x1b,
-	0xdb, 0xf9, 0xd6, 0x56, 0xf6, 0xd0, 0x01, 0x34, 0xdc, 0xb1, 0x61, 0x60, 0xd7, 0x55, 0xfe, 0x90,
-	0x90, 0x02, 0xad, 0x81, 0x6e, 0xfa, 0x04, 0x7f, 0x33, 0xc6, 0xae, 0xa7, 0xfc, 0x24, 0xa3, 0x43,
-	0x68, 0x0e, 0x1d, 0x32, 0xb0, 0x4c, 0x13, 0xdb, 0xca, 0xcf, 0x39, 0xb6, 0x1d, 0xcf, 0x1f, 0x3a,
-	0x63, 0xdb, 0x54, 0x7e, 0x91, 0xd1, 0x0b, 0x50, 0x85, 0xda, 0xc7, 0xb6, 0x67, 0x79, 0xdf, 0xf9,
-	0x9e, 0xe3, 0xf8, 0x23, 0x9d, 0x5c, 0x61, 0xe5, 0x37, 0x19, 0x9d, 0xc2, 0x89, 0x65, 0x7b, 0x98,
-	0xd8, 0xfa, 0xc8, 0x77, 0x31, 0x79, 0x83, 0x89, 0x8f, 0x09, 0x71, 0x88, 0xf2, 0x97, 0x8c, 0x54,
-	0xe8, 0x30, 0xca, 0x32, 0xb0, 0x3f, 0xb6, 0xf5, 0x37, 0xba, 0x35, 0xd2, 0x07, 0x23, 0xac, 0xfc,
-	0x2d, 0x9f, 0xff, 0x2a, 0x01, 0xf0, 0x7c, 0x3d, 0xf6, 0x6f, 0x6c, 0x41, 0xe3, 0x35, 0x76, 0x5d,
-	0xfd, 0x0a, 0x2b, 0x7b, 0x08, 0xa0, 0x6e, 0x38, 0xf6, 0xd0, 0xba, 0x52, 0x24, 0x74, 0x0c, 0x6d,
-	0xbe, 0xf6, 0xc7, 0xb7, 0xa6, 0xee, 0x61, 0xa5, 0x82, 0x54, 0x78, 0x86, 0x6d, 0xd3, 0x21, 0x2e,
-	0x26, 0xbe, 0x47, 0x74, 0xdb, 0xd5, 0x0d, 0xcf, 0x72, 0x6c, 0x45, 0x46, 0xcf, 0xa1, 0xe3, 0x10,
-	0x13, 0x93, 0x27, 0x1b, 0x55, 0x74, 0x02, 0xc7, 0x26, 0x1e, 0x59, 0xcc, 0x9b, 0x8b, 0xf1, 0x8d,
-	0x6f, 0xd9, 0x43, 0x47, 0xa9, 0x31, 0xda, 0xb8, 0xd6, 0x2d, 0xdb, 0x70, 0x4c, 0xec, 0xdf, 0xea,
-	0xc6, 0x0d, 0xab, 0x5f, 0x3f, 0x7f, 0x07, 0x68, 0x27, 0x75, 0x8b, 0xbd, 0x6d, 0xd1, 0x21, 0x80,
-	0x6b, 0x5d, 0xd9, 0xba, 0x37, 0x26, 0xd8, 0x55, 0xf6, 0xd0, 0x11, 0xb4, 0x46, 0xba, 0xeb, 0xf9,
-	0xa5, 0xd5, 0xe7, 0xd0, 0xd9, 0xaa, 0xea, 0xfa, 0x43, 0x6b, 0xe4, 0x61, 0xa2, 0x54, 0xd8, 0xe5,
-	0x84, 0x2d, 0x45, 0x1e, 0xb8, 0xf0, 0x69, 0x9c, 0xcc, 0x7b, 0x8b, 0xc7, 0x35, 0x4d, 0x96, 0x74,
-	0x36, 0xa7, 0x49, 0xef, 0x2e, 0x98, 0x24, 0xe1, 0x94, 0xbf, 0x5b, 0x52, 0x31, 0x9c, 0x6f, 0x2f,
-	0xe6, 0x61, 0xb6, 0xd8, 0x4c, 0x18, 0xec, 0x6f, 0x89, 0xfb, 0x5c, 0xcc, 0x3f, 0x1c, 0xa9, 0xf8,
-	0xb8, 0x4c, 0xea, 0x39, 0xfc, 0xf2, 0x9f, 0x00, 0x00, 0x00, 0xff, 0xff, 0xa1, 0xcb, 0xe1, 0xb4,
-	0x74, 0x06, 0x00, 0x00,
+	// 1008 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x84, 0x55, 0xdd, 0x6e, 0xe3, 0x44,
+	0x14, 0x5e, 0xe7, 0xb7, 0x39, 0x69, 0x5a, 0x77, 0xb2, 0x65, 0xbd, 0x85, 0x65, 0x2b, 0xc3, 0xa2,
+	0xd2, 0x4a, 0xa9, 0x28, 0x37, 0x70, 0xe9, 0xd8, 0xd3, 0xd6, 0x6a, 0xd6, 0x2e, 0x33, 0xce, 0x2e,
+	0x2c, 0x48, 0x96, 0x9b, 0x4c, 0x93, 0x88, 0xc4, 0x8e, 0x6c, 0xa7, 0x4a, 0x79, 0x08, 0x84, 0x04,
+	0x37, 0x5c, 0xf0, 0x02, 0xf0, 0x22, 0xbc, 0x05, 0x2f, 0x81, 0xc4, 0x2d, 0x1a, 0x8f, 0xed, 0x38,
+	0x69, 0xa5, 0xbd, 0xca, 0x7c, 0x67, 0xbe, 0x9c, 0x9f, 0xef, 0x7c, 0x1a, 0x43, 0x7b, 0x10, 0xcc,
+	0x66, 0x81, 0x7f, 0x2a, 0x7e, 0x3a, 0xf3, 0x30, 0x88, 0x03, 0x54, 0x13, 0xe8, 0xe0, 0xe5, 0x28,
+	0x08, 0x46, 0x53, 0x76, 0x9a, 0x44, 0x6f, 0x16, 0xb7, 0xa7, 0xf1, 0x64, 0xc6, 0xa2, 0xd8, 0x9b,
+	0xcd, 0x05, 0x51, 0x55, 0x01, 0x7a, 0x5e, 0x14, 0xeb, 0x81, 0x7f, 0x3b, 0x19, 0xa1, 0xa7, 0x50,
+	0x9d, 0xf8, 0x43, 0xb6, 0x54, 0xa4, 0x43, 0xe9, 0xa8, 0x42, 0x04, 0x50, 0xdf, 0xc2, 0xde, 0x6b,
+	0x16, 0x7b, 0x43, 0x2f, 0xf6, 0xf0, 0x32, 0x66, 0x7e, 0x34, 0x09, 0x7c, 0x84, 0xa0, 0x12, 0xdf,
+	0xcf, 0x59, 0xc2, 0x6c, 0x90, 0xe4, 0x8c, 0x14, 0xa8, 0xdf, 0xb1, 0x90, 0x5f, 0x2b, 0xa5, 0x43,
+	0xe9, 0xa8, 0x45, 0x32, 0xc8, 0x13, 0xdf, 0x79, 0xd3, 0x05, 0x53, 0xca, 0x87, 0xd2, 0xd1, 0x36,
+	0x11, 0x40, 0xb5, 0x01, 0x3d, 0x48, 0x1c, 0xa1, 0xaf, 0x01, 0x58, 0x8e, 0x14, 0xe9, 0xb0, 0x7c,
+	0xd4, 0x3c, 0x7b, 0xde, 0x49, 0xc7, 0x7b, 0xc0, 0x27, 0x05, 0xb2, 0xfa, 0x3d, 0x6c, 0x65, 0x84,
+	0x55, 0x49, 0xa9, 0x50, 0x92, 0x27, 0x8f, 0x26, 0x23, 0xdf, 0x8b, 0x17, 0x21, 0x8b, 0x94, 0xd2,
+	0xe3, 0xc9, 0x69, 0xc6, 0x20, 0x05, 0xb2, 0xfa, 0x03, 0xec, 0x3d, 0x20, 0xa0, 0xcf, 0x41, 0xce,
+	0x29, 0xee, 0x98, 0x79, 0x43, 0x16, 0xa6, 0x05, 0x77, 0xf3, 0xf8, 0x65, 0x12, 0x46, 0x1f, 0x41,
+	0x23, 0x0f, 0x25, 0xfa, 0x6c, 0x93, 0x55, 0x40, 0x7d, 0x07, 0xb5, 0x94, 0xf7, 0x0a, 0x76, 0x06,
+	0x63, 0xcf, 0xf7, 0xd9, 0x74, 0x3d, 0x61, 0x2b, 0x8d, 0xa6, 0xb4, 0xc7, 0x2a, 0x97, 0x1e, 0xad,
+	0xac, 0xfe, 0x23, 0x41, 0x4b, 0x5f, 0xfb, 0x73, 0x71, 0x7b, 0xd5, 0xc7, 0xb7, 0x57, 0x5d, 0x6d,
+	0xef, 0x2b, 0x68, 0xe4, 0xbe, 0x49, 0x36, 0xd8, 0x3c, 0x3b, 0xe8, 0x08, 0x67, 0x75, 0x32, 0x67,
+	0x75, 0x9c, 0x8c, 0x41, 0x56, 0x64, 0xf4, 0x02, 0x20, 0x9b, 0x65, 0x32, 0x54, 0x2a, 0x89, 0x57,
+	0x1a, 0x69, 0xc4, 0x1c, 0xa2, 0x36, 0x54, 0xe3, 0x25, 0xbf, 0xa9, 0xa6, 0x2e, 0x5a, 0x9a, 0x43,
+	0xbe, 0x38, 0x36, 0x0f, 0x06, 0x63, 0xa5, 0x26, 0x4c, 0x98, 0x00, 0xae, 0x5e, 0xbe, 0x68, 0xa5,
+	0x2e, 0xd4, 0xcb, 0x03, 0xaa, 0x06, 0xbb, 0x74, 0x43, 0x6e, 0x05, 0xea, 0x83, 0x90, 0x79, 0x71,
+	0x90, 0xe9, 0x97, 0x41, 0x5e, 0xc0, 0x0f, 0xfc, 0x41, 0xb6, 0x04, 0x01, 0x54, 0x0c, 0xf5, 0x6b,
+	0xef, 0x7e, 0x1a, 0x78, 0x43, 0xf4, 0x19, 0xd4, 0x0a, 0xca, 0x37, 0xcf, 0x76, 0x32, 0x83, 0x88,
+	0xd4, 0xa4, 0x36, 0xce, 0x55, 0xe4, 0x6e, 0x48, 0xf3, 0x24, 0x67, 0xb5, 0x0b, 0x5b, 0xd8, 0xbf,
+	0x63, 0xd3, 0x40, 0x28, 0x3a, 0x17, 0x29, 0xb3, 0x16, 0x52, 0xf8, 0x1e, 0x2f, 0xfc, 0x2c, 0x41,
+	0xb5, 0x3b, 0x0d, 0x06, 0x3f, 0xa2, 0x93, 0x8d, 0x4e, 0xda, 0x59, 0x27, 0xc9, 0xf5, 0x46, 0x3b,
+	0xaf, 0x0a, 0xed, 0x34, 0xcf, 0xf6, 0xd6, 0xa8, 0x86, 0x17, 0x7b, 0xa2, 0x43, 0xf4, 0x05, 0x6c,
+	0xcd, 0x52, 0x1f, 0xa7, 0xcb, 0xdc, 0x5f, 0xa3, 0x66, 0x26, 0x27, 0x39, 0x4d, 0x1d, 0x41, 0xb3,
+	0x50, 0x10, 0x7d, 0x00, 0x35, 0x7f, 0x31, 0xbb, 0x49, 0xbb, 0xaa, 0x90, 0x14, 0xa1, 0x4f, 0xa0,
+	0x35, 0x0f, 0xd9, 0xdd, 0x24, 0x58, 0x44, 0xee, 0xd8, 0x8b, 0xc6, 0xe9, 0x64, 0xdb, 0x59, 0xf0,
+	0xd2, 0x8b, 0xc6, 0xe8, 0x43, 0x68, 0xf0, 0x9c, 0x82, 0x20, 0x9e, 0x83, 0x2d, 0x1e, 0xe0, 0x97,
+	0xea, 0x4b, 0x68, 0xe4, 0xed, 0xe6, 0xf2, 0xf2, 0x27, 0x20, 0x93, 0xf7, 0x04, 0x5a, 0x6b, 0x4d,
+	0xa2, 0x83, 0xc2, 0x34, 0x82, 0xb8, 0x6a, 0xfb, 0x2f, 0x09, 0x76, 0x4c, 0x7f, 0x30, 0x5d, 0x70,
+	0x8f, 0x5c, 0x87, 0x41, 0x70, 0xbb, 0x61, 0x48, 0x69, 0xd3, 0x90, 0x2b, 0xbd, 0x4b, 0xef, 0xd7,
+	0xfb, 0xe3, 0xb5, 0xb7, 0x44, 0x8c, 0x52, 0x88, 0xa0, 0xe7, 0xb0, 0xc5, 0xdd, 0x9d, 0x3c, 0xa8,
+	0x95, 0x44, 0xa8, 0x7a, 0xbc, 0x34, 0x39, 0xcc, 0x47, 0xab, 0xae, 0x46, 0x3b, 0xfe, 0x53, 0x82,
+	0x1a, 0x8d, 0xbd, 0x78, 0x11, 0xa1, 0x26, 0xd4, 0xfb, 0xd6, 0x95, 0x65, 0xbf, 0xb5, 0xe4, 0x27,
+	0x68, 0x1b, 0xea, 0xb4, 0xaf, 0xeb, 0x98, 0x52, 0xf9, 0x6f, 0x09, 0xc9, 0xd0, 0xec, 0x6a, 0x86,
+	0x4b, 0xf0, 0x37, 0x7d, 0x4c, 0x1d, 0xf9, 0x97, 0x32, 0xda, 0x81, 0xc6, 0xb9, 0x4d, 0xba, 0xa6,
+	0x61, 0x60, 0x4b, 0xfe, 0x35, 0xc1, 0x96, 0xed, 0xb8, 0xe7, 0x76, 0xdf, 0x32, 0xe4, 0xdf, 0xca,
+	0xe8, 0x05, 0x28, 0x29, 0xdb, 0xc5, 0x96, 0x63, 0x3a, 0xdf, 0xb9, 0x8e, 0x6d, 0xbb, 0x3d, 0x8d,
+	0x5c, 0x60, 0xf9, 0x8f, 0x32, 0x3a, 0x80, 0x7d, 0xd3, 0x72, 0x30, 0xb1, 0xb4, 0x9e, 0x4b, 0x31,
+	0x79, 0x83, 0x89, 0x8b, 0x09, 0xb1, 0x89, 0xfc, 0x6f, 0x19, 0x29, 0xd0, 0xe6, 0x21, 0x53, 0xc7,
+	0x6e, 0xdf, 0xd2, 0xde, 0x68, 0x66, 0x4f, 0xeb, 0xf6, 0xb0, 0xfc, 0x5f, 0xf9, 0xf8, 0x77, 0x09,
+	0x40, 0xc8, 0xe1, 0xf0, 0xb7, 0xa3, 0x09, 0xf5, 0xd7, 0x98, 0x52, 0xed, 0x02, 0xcb, 0x4f, 0x10,
+	0x40, 0x4d, 0xb7, 0xad, 0x73, 0xf3, 0x42, 0x96, 0xd0, 0x1e, 0xb4, 0xc4, 0xd9, 0xed, 0x5f, 0x1b,
+	0x9a, 0x83, 0xe5, 0x12, 0x52, 0xe0, 0x29, 0xb6, 0x0c, 0x9b, 0x50, 0x4c, 0x5c, 0x87, 0x68, 0x16,
+	0xd5, 0x74, 0xc7, 0xb4, 0x2d, 0xb9, 0x8c, 0x9e, 0x41, 0xdb, 0x26, 0x06, 0x26, 0x1b, 0x17, 0x15,
+	0xb4, 0x0f, 0x7b, 0x06, 0xee, 0x99, 0xbc, 0x37, 0x8a, 0xf1, 0x95, 0x6b, 0x5a, 0xe7, 0xb6, 0x5c,
+	0xe5, 0x61, 0xfd, 0x52, 0x33, 0x2d, 0xdd, 0x36, 0xb0, 0x7b, 0xad, 0xe9, 0x57, 0xbc, 0x7e, 0xed,
+	0xf8, 0x27, 0x40, 0x6b, 0x1e, 0x11, 0x92, 0xef, 0x00, 0x50, 0xf3, 0xc2, 0xd2, 0x9c, 0x3e, 0xc1,
+	0x54, 0x7e, 0x82, 0x76, 0xa1, 0xd9, 0xd3, 0xa8, 0xe3, 0xe6, 0xad, 0x3e, 0x83, 0x76, 0xa1, 0x2a,
+	0x75, 0xcf, 0xcd, 0x9e, 0x83, 0x89, 0x5c, 0xe2, 0xc3, 0xa5, 0x6d, 0xc9, 0x65, 0xd4, 0x82, 0x86,
+	0x43, 0x34, 0x1d, 0xbb, 0xa6, 0x41, 0xe5, 0x0a, 0xcf, 0x8a, 0xbf, 0x75, 0xb0, 0x45, 0xf9, 0x5f,
+	0xe4, 0x6a, 0x97, 0xc2, 0xa7, 0x41, 0x38, 0xea, 0x8c, 0xef, 0xe7, 0x2c, 0x9c, 0xb2, 0xe1, 0x88,
+	0x85, 0x9d, 0x5b, 0xef, 0x26, 0x9c, 0x0c, 0xc4, 0x43, 0x19, 0xa5, 0x7e, 0x7a, 0x77, 0x32, 0x9a,
+	0xc4, 0xe3, 0xc5, 0x0d, 0x87, 0xa7, 0x05, 0xf2, 0xa9, 0x20, 0x8b, 0xef, 0x75, 0x94, 0x7e, 0xd3,
+	0x6f, 0x6a, 0x09, 0xfc, 0xf2, 0xff, 0x01, 0x00, 0xb3, 0xd6, 0xdf, 0x2f, 0xeb, 0x07, 0x00, 0x00,
 }
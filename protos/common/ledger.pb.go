@@ -46,24 +46,149 @@ func (m *BlockchainInfo) GetPreviousBlockHash() []byte {
 	return nil
 }
 
+// LedgerBundle is a signed export of a range of the blocks of a channel, along with the
+// material needed to verify the blocks offline
+type LedgerBundle struct {
+	Content         []byte `protobuf:"bytes,1,opt,name=content,proto3" json:"content,omitempty"`
+	SignatureHeader []byte `protobuf:"bytes,2,opt,name=signature_header,json=signatureHeader,proto3" json:"signature_header,omitempty"`
+	Signature       []byte `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (m *LedgerBundle) Reset()                    { *m = LedgerBundle{} }
+func (m *LedgerBundle) String() string            { return proto.CompactTextString(m) }
+func (*LedgerBundle) ProtoMessage()               {}
+func (*LedgerBundle) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{1} }
+
+func (m *LedgerBundle) GetContent() []byte {
+	if m != nil {
+		return m.Content
+	}
+	return nil
+}
+
+func (m *LedgerBundle) GetSignatureHeader() []byte {
+	if m != nil {
+		return m.SignatureHeader
+	}
+	return nil
+}
+
+func (m *LedgerBundle) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+// LedgerBundleContent is the content of a LedgerBundle
+type LedgerBundleContent struct {
+	ChannelId   string             `protobuf:"bytes,1,opt,name=channel_id,json=channelId" json:"channel_id,omitempty"`
+	ConfigBlock *Block             `protobuf:"bytes,2,opt,name=config_block,json=configBlock" json:"config_block,omitempty"`
+	OrdererOrgs []*LedgerBundleOrg `protobuf:"bytes,3,rep,name=orderer_orgs,json=ordererOrgs" json:"orderer_orgs,omitempty"`
+	Blocks      []*Block           `protobuf:"bytes,4,rep,name=blocks" json:"blocks,omitempty"`
+}
+
+func (m *LedgerBundleContent) Reset()                    { *m = LedgerBundleContent{} }
+func (m *LedgerBundleContent) String() string            { return proto.CompactTextString(m) }
+func (*LedgerBundleContent) ProtoMessage()               {}
+func (*LedgerBundleContent) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{2} }
+
+func (m *LedgerBundleContent) GetChannelId() string {
+	if m != nil {
+		return m.ChannelId
+	}
+	return ""
+}
+
+func (m *LedgerBundleContent) GetConfigBlock() *Block {
+	if m != nil {
+		return m.ConfigBlock
+	}
+	return nil
+}
+
+func (m *LedgerBundleContent) GetOrdererOrgs() []*LedgerBundleOrg {
+	if m != nil {
+		return m.OrdererOrgs
+	}
+	return nil
+}
+
+func (m *LedgerBundleContent) GetBlocks() []*Block {
+	if m != nil {
+		return m.Blocks
+	}
+	return nil
+}
+
+// LedgerBundleOrg holds the certificates of an ordering service organization of a LedgerBundle
+type LedgerBundleOrg struct {
+	MspId             string   `protobuf:"bytes,1,opt,name=msp_id,json=mspId" json:"msp_id,omitempty"`
+	RootCerts         [][]byte `protobuf:"bytes,2,rep,name=root_certs,json=rootCerts,proto3" json:"root_certs,omitempty"`
+	IntermediateCerts [][]byte `protobuf:"bytes,3,rep,name=intermediate_certs,json=intermediateCerts,proto3" json:"intermediate_certs,omitempty"`
+}
+
+func (m *LedgerBundleOrg) Reset()                    { *m = LedgerBundleOrg{} }
+func (m *LedgerBundleOrg) String() string            { return proto.CompactTextString(m) }
+func (*LedgerBundleOrg) ProtoMessage()               {}
+func (*LedgerBundleOrg) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{3} }
+
+func (m *LedgerBundleOrg) GetMspId() string {
+	if m != nil {
+		return m.MspId
+	}
+	return ""
+}
+
+func (m *LedgerBundleOrg) GetRootCerts() [][]byte {
+	if m != nil {
+		return m.RootCerts
+	}
+	return nil
+}
+
+func (m *LedgerBundleOrg) GetIntermediateCerts() [][]byte {
+	if m != nil {
+		return m.IntermediateCerts
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*BlockchainInfo)(nil), "common.BlockchainInfo")
+	proto.RegisterType((*LedgerBundle)(nil), "common.LedgerBundle")
+	proto.RegisterType((*LedgerBundleContent)(nil), "common.LedgerBundleContent")
+	proto.RegisterType((*LedgerBundleOrg)(nil), "common.LedgerBundleOrg")
 }
 
 func init() { proto.RegisterFile("common/ledger.proto", fileDescriptor3) }
 
 var fileDescriptor3 = []byte{
-	// 186 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xe2, 0x12, 0x4e, 0xce, 0xcf, 0xcd,
-	0xcd, 0xcf, 0xd3, 0xcf, 0x49, 0x4d, 0x49, 0x4f, 0x2d, 0xd2, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17,
-	0x62, 0x83, 0x08, 0x2a, 0x35, 0x31, 0x72, 0xf1, 0x39, 0xe5, 0xe4, 0x27, 0x67, 0x27, 0x67, 0x24,
-	0x66, 0xe6, 0x79, 0xe6, 0xa5, 0xe5, 0x0b, 0x89, 0x71, 0xb1, 0x65, 0xa4, 0x66, 0xa6, 0x67, 0x94,
-	0x48, 0x30, 0x2a, 0x30, 0x6a, 0xb0, 0x04, 0x41, 0x79, 0x42, 0x5a, 0x5c, 0x02, 0xc9, 0xa5, 0x45,
-	0x45, 0xa9, 0x79, 0x25, 0x60, 0x0d, 0x1e, 0x89, 0xc5, 0x19, 0x12, 0x4c, 0x0a, 0x8c, 0x1a, 0x3c,
-	0x41, 0x18, 0xe2, 0x42, 0x3a, 0x5c, 0x82, 0x05, 0x45, 0xa9, 0x65, 0x99, 0xf9, 0xa5, 0xc5, 0x08,
-	0xc5, 0xcc, 0x60, 0xc5, 0x98, 0x12, 0x4e, 0xc1, 0x5c, 0x2a, 0xf9, 0x45, 0xe9, 0x7a, 0x19, 0x95,
-	0x05, 0xa9, 0x45, 0x50, 0x57, 0xa6, 0x25, 0x26, 0x15, 0x65, 0x26, 0x43, 0x1c, 0x5b, 0xac, 0x07,
-	0x71, 0x6c, 0x94, 0x76, 0x7a, 0x66, 0x49, 0x46, 0x69, 0x12, 0x88, 0xab, 0x8f, 0xa4, 0x58, 0x1f,
-	0xa2, 0x58, 0x1f, 0xa2, 0x58, 0x1f, 0xa2, 0x38, 0x89, 0x0d, 0xcc, 0x35, 0x06, 0x04, 0x00, 0x00,
-	0xff, 0xff, 0x9f, 0xcc, 0x05, 0xd1, 0xff, 0x00, 0x00, 0x00,
+	// 405 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x64, 0x92, 0x5f, 0x8b, 0xd3, 0x40,
+	0x14, 0xc5, 0xc9, 0x66, 0x8d, 0xf4, 0x36, 0xba, 0xbb, 0xb3, 0xa8, 0x41, 0x14, 0x42, 0x51, 0xa8,
+	0xff, 0x5a, 0x59, 0xdf, 0x7c, 0xec, 0xbe, 0x6c, 0x41, 0x58, 0x88, 0x6f, 0xbe, 0x84, 0x74, 0x72,
+	0x3b, 0x19, 0x6c, 0x66, 0xe2, 0x9d, 0xc9, 0x82, 0xaf, 0x7e, 0x3b, 0xbf, 0x95, 0x64, 0x66, 0xda,
+	0x06, 0xfb, 0x14, 0xee, 0x39, 0xbf, 0xc9, 0x39, 0x33, 0x5c, 0xb8, 0xe6, 0xba, 0x6d, 0xb5, 0x5a,
+	0xee, 0xb0, 0x16, 0x48, 0x8b, 0x8e, 0xb4, 0xd5, 0x2c, 0xf1, 0xe2, 0xcb, 0xbd, 0xe9, 0x3f, 0xde,
+	0x9c, 0xfd, 0x89, 0xe0, 0xe9, 0x6a, 0xa7, 0xf9, 0x4f, 0xde, 0x54, 0x52, 0xad, 0xd5, 0x56, 0xb3,
+	0xe7, 0x90, 0x34, 0x28, 0x45, 0x63, 0xb3, 0x28, 0x8f, 0xe6, 0xe7, 0x45, 0x98, 0xd8, 0x7b, 0xb8,
+	0xe4, 0x3d, 0x11, 0x2a, 0xeb, 0x0e, 0xdc, 0x55, 0xa6, 0xc9, 0xce, 0xf2, 0x68, 0x9e, 0x16, 0x27,
+	0x3a, 0xfb, 0x08, 0x57, 0x1d, 0xe1, 0x83, 0xd4, 0xbd, 0x39, 0xc2, 0xb1, 0x83, 0x4f, 0x8d, 0xd9,
+	0x2f, 0x48, 0xbf, 0xb9, 0xc6, 0xab, 0x5e, 0xd5, 0x3b, 0x64, 0x19, 0x3c, 0xe6, 0x5a, 0x59, 0x54,
+	0xbe, 0x42, 0x5a, 0xec, 0x47, 0xf6, 0x0e, 0x2e, 0x8d, 0x14, 0xaa, 0xb2, 0x3d, 0x61, 0xd9, 0x60,
+	0x55, 0x23, 0x85, 0x0e, 0x17, 0x07, 0xfd, 0xce, 0xc9, 0xec, 0x15, 0x4c, 0x0e, 0x52, 0x88, 0x3e,
+	0x0a, 0xb3, 0xbf, 0x11, 0x5c, 0x8f, 0x33, 0x6f, 0x43, 0xc0, 0x6b, 0x00, 0xde, 0x54, 0x4a, 0xe1,
+	0xae, 0x94, 0xb5, 0x4b, 0x9f, 0x14, 0x93, 0xa0, 0xac, 0x6b, 0xf6, 0x19, 0x52, 0xae, 0xd5, 0x56,
+	0x8a, 0x72, 0x33, 0xb4, 0x77, 0xd9, 0xd3, 0x9b, 0x27, 0x8b, 0xf0, 0xa6, 0xee, 0x4a, 0xc5, 0xd4,
+	0x23, 0x6e, 0x60, 0x5f, 0x21, 0xd5, 0x54, 0x23, 0x21, 0x95, 0x9a, 0x84, 0xc9, 0xe2, 0x3c, 0x9e,
+	0x4f, 0x6f, 0x5e, 0xec, 0x4f, 0x8c, 0x3b, 0xdc, 0x93, 0x28, 0xa6, 0x01, 0xbe, 0x27, 0x61, 0xd8,
+	0x5b, 0x48, 0x5c, 0x8c, 0xc9, 0xce, 0xf3, 0xf8, 0x34, 0x27, 0x98, 0xb3, 0x07, 0xb8, 0xf8, 0xef,
+	0x37, 0xec, 0x19, 0x24, 0xad, 0xe9, 0x8e, 0x57, 0x78, 0xd4, 0x9a, 0x6e, 0x5d, 0x0f, 0xb7, 0x23,
+	0xad, 0x6d, 0xc9, 0x91, 0xac, 0xc9, 0xce, 0xf2, 0x78, 0x78, 0x94, 0x41, 0xb9, 0x1d, 0x04, 0xf6,
+	0x09, 0x98, 0x54, 0x16, 0xa9, 0xc5, 0x5a, 0x56, 0x16, 0x03, 0x16, 0x3b, 0xec, 0x6a, 0xec, 0x38,
+	0x7c, 0xf5, 0x1d, 0xde, 0x68, 0x12, 0x8b, 0xe6, 0x77, 0x87, 0x14, 0x36, 0x6e, 0x5b, 0x6d, 0x48,
+	0x72, 0xbf, 0x5b, 0x26, 0xb4, 0xfd, 0xf1, 0x41, 0x48, 0xdb, 0xf4, 0x9b, 0x61, 0x5c, 0x8e, 0xe0,
+	0xa5, 0x87, 0x97, 0x1e, 0x0e, 0x6b, 0xb9, 0x49, 0xdc, 0xf8, 0xe5, 0xdf, 0x00, 0xaa, 0x14, 0x93,
+	0xfd, 0xcb, 0x02, 0x00, 0x00,
 }
@@ -301,12 +301,121 @@ func (m *ImplicitMetaPolicy) GetRule() ImplicitMetaPolicy_Rule {
 	return ImplicitMetaPolicy_ANY
 }
 
+// PolicyDecision explains the evaluation of a policy over a set of signed data, as a tree of the
+// decisions of its rules, to debug the authorization failures
+type PolicyDecision struct {
+	Policy         string            `protobuf:"bytes,1,opt,name=policy" json:"policy,omitempty"`
+	Rule           string            `protobuf:"bytes,2,opt,name=rule" json:"rule,omitempty"`
+	Satisfied      bool              `protobuf:"varint,3,opt,name=satisfied" json:"satisfied,omitempty"`
+	Error          string            `protobuf:"bytes,4,opt,name=error" json:"error,omitempty"`
+	SubDecisions   []*PolicyDecision `protobuf:"bytes,5,rep,name=sub_decisions,json=subDecisions" json:"sub_decisions,omitempty"`
+	IdentityChecks []*IdentityCheck  `protobuf:"bytes,6,rep,name=identity_checks,json=identityChecks" json:"identity_checks,omitempty"`
+}
+
+func (m *PolicyDecision) Reset()                    { *m = PolicyDecision{} }
+func (m *PolicyDecision) String() string            { return proto.CompactTextString(m) }
+func (*PolicyDecision) ProtoMessage()               {}
+func (*PolicyDecision) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{4} }
+
+func (m *PolicyDecision) GetPolicy() string {
+	if m != nil {
+		return m.Policy
+	}
+	return ""
+}
+
+func (m *PolicyDecision) GetRule() string {
+	if m != nil {
+		return m.Rule
+	}
+	return ""
+}
+
+func (m *PolicyDecision) GetSatisfied() bool {
+	if m != nil {
+		return m.Satisfied
+	}
+	return false
+}
+
+func (m *PolicyDecision) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *PolicyDecision) GetSubDecisions() []*PolicyDecision {
+	if m != nil {
+		return m.SubDecisions
+	}
+	return nil
+}
+
+func (m *PolicyDecision) GetIdentityChecks() []*IdentityCheck {
+	if m != nil {
+		return m.IdentityChecks
+	}
+	return nil
+}
+
+// IdentityCheck explains the check of a signing identity against the principal of a SignedBy rule
+type IdentityCheck struct {
+	Index            uint32 `protobuf:"varint,1,opt,name=index" json:"index,omitempty"`
+	MspId            string `protobuf:"bytes,2,opt,name=msp_id,json=mspId" json:"msp_id,omitempty"`
+	PrincipalMatched bool   `protobuf:"varint,3,opt,name=principal_matched,json=principalMatched" json:"principal_matched,omitempty"`
+	SignatureValid   bool   `protobuf:"varint,4,opt,name=signature_valid,json=signatureValid" json:"signature_valid,omitempty"`
+	Error            string `protobuf:"bytes,5,opt,name=error" json:"error,omitempty"`
+}
+
+func (m *IdentityCheck) Reset()                    { *m = IdentityCheck{} }
+func (m *IdentityCheck) String() string            { return proto.CompactTextString(m) }
+func (*IdentityCheck) ProtoMessage()               {}
+func (*IdentityCheck) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{5} }
+
+func (m *IdentityCheck) GetIndex() uint32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *IdentityCheck) GetMspId() string {
+	if m != nil {
+		return m.MspId
+	}
+	return ""
+}
+
+func (m *IdentityCheck) GetPrincipalMatched() bool {
+	if m != nil {
+		return m.PrincipalMatched
+	}
+	return false
+}
+
+func (m *IdentityCheck) GetSignatureValid() bool {
+	if m != nil {
+		return m.SignatureValid
+	}
+	return false
+}
+
+func (m *IdentityCheck) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*Policy)(nil), "common.Policy")
 	proto.RegisterType((*SignaturePolicyEnvelope)(nil), "common.SignaturePolicyEnvelope")
 	proto.RegisterType((*SignaturePolicy)(nil), "common.SignaturePolicy")
 	proto.RegisterType((*SignaturePolicy_NOutOf)(nil), "common.SignaturePolicy.NOutOf")
 	proto.RegisterType((*ImplicitMetaPolicy)(nil), "common.ImplicitMetaPolicy")
+	proto.RegisterType((*PolicyDecision)(nil), "common.PolicyDecision")
+	proto.RegisterType((*IdentityCheck)(nil), "common.IdentityCheck")
 	proto.RegisterEnum("common.Policy_PolicyType", Policy_PolicyType_name, Policy_PolicyType_value)
 	proto.RegisterEnum("common.ImplicitMetaPolicy_Rule", ImplicitMetaPolicy_Rule_name, ImplicitMetaPolicy_Rule_value)
 }
@@ -314,35 +423,47 @@ func init() {
 func init() { proto.RegisterFile("common/policies.proto", fileDescriptor4) }
 
 var fileDescriptor4 = []byte{
-	// 480 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x74, 0x52, 0xdf, 0x8b, 0xda, 0x40,
-	0x10, 0x76, 0xfd, 0x11, 0x75, 0xf4, 0xda, 0x74, 0xb9, 0xa2, 0x1c, 0xb4, 0x95, 0x50, 0x8a, 0x70,
-	0x34, 0x01, 0xaf, 0x4f, 0x7d, 0xd3, 0x56, 0x7a, 0x69, 0x4d, 0x94, 0xd5, 0xa3, 0x5c, 0x5f, 0x82,
-	0xd1, 0xd5, 0x5b, 0x88, 0xbb, 0x4b, 0x76, 0x23, 0xf5, 0xbf, 0xe8, 0x53, 0xff, 0x99, 0xfe, 0x73,
-	0x25, 0x59, 0x2d, 0x72, 0xe5, 0xde, 0xe6, 0x9b, 0xfd, 0x66, 0xf6, 0xfb, 0x66, 0x06, 0x5e, 0xae,
-	0xc4, 0x6e, 0x27, 0xb8, 0x27, 0x45, 0xc2, 0x56, 0x8c, 0x2a, 0x57, 0xa6, 0x42, 0x0b, 0x6c, 0x99,
-	0xf4, 0x55, 0x67, 0xa7, 0xa4, 0xb7, 0x53, 0x32, 0x92, 0x29, 0xe3, 0x2b, 0x26, 0x97, 0x89, 0x21,
-	0x38, 0x3f, 0xc1, 0x9a, 0xe5, 0x25, 0x07, 0x8c, 0xa1, 0xaa, 0x0f, 0x92, 0x76, 0x51, 0x0f, 0xf5,
-	0x6b, 0xa4, 0x88, 0xf1, 0x25, 0xd4, 0xf6, 0xcb, 0x24, 0xa3, 0xdd, 0x72, 0x0f, 0xf5, 0xdb, 0xc4,
-	0x00, 0xe7, 0x33, 0x80, 0xa9, 0x59, 0xe4, 0x9c, 0x16, 0xd4, 0xef, 0xc2, 0x6f, 0xe1, 0xf4, 0x7b,
-	0x68, 0x97, 0xf0, 0x05, 0x34, 0xe7, 0xfe, 0x97, 0x70, 0xb8, 0xb8, 0x23, 0x63, 0x1b, 0xe1, 0x3a,
-	0x54, 0x82, 0xf9, 0xcc, 0x2e, 0xe3, 0x17, 0x70, 0xe1, 0x07, 0xb3, 0x89, 0xff, 0xc9, 0x5f, 0x44,
-	0xc1, 0x78, 0x31, 0xb4, 0x2b, 0xce, 0x6f, 0x04, 0x9d, 0x39, 0xdb, 0xf2, 0xa5, 0xce, 0x52, 0x6a,
-	0xfa, 0x8d, 0xf9, 0x9e, 0x26, 0x42, 0x52, 0xdc, 0x85, 0xfa, 0x9e, 0xa6, 0x8a, 0x09, 0x7e, 0x94,
-	0x73, 0x82, 0xf8, 0x1a, 0xaa, 0x69, 0x96, 0x18, 0x41, 0xad, 0x41, 0xc7, 0x35, 0xfe, 0xdc, 0x47,
-	0x8d, 0x48, 0x41, 0xc2, 0x1f, 0x00, 0xd8, 0x9a, 0x72, 0xcd, 0x34, 0xa3, 0xaa, 0x5b, 0xe9, 0x55,
-	0xfa, 0xad, 0xc1, 0xe5, 0xa9, 0x24, 0x98, 0xcf, 0x66, 0xa7, 0x61, 0x90, 0x33, 0x9e, 0xf3, 0x07,
-	0xc1, 0xf3, 0x47, 0xfd, 0xf0, 0x2b, 0x68, 0x2a, 0xb6, 0xe5, 0x74, 0x1d, 0xc5, 0x07, 0x23, 0xe9,
-	0xb6, 0x44, 0x1a, 0x26, 0x35, 0x3a, 0xe0, 0x8f, 0xd0, 0xe0, 0x91, 0xc8, 0x74, 0x24, 0x36, 0x47,
-	0x65, 0xaf, 0x9f, 0x50, 0xe6, 0x86, 0xd3, 0x4c, 0x4f, 0x37, 0xb7, 0x25, 0x62, 0xf1, 0x22, 0xba,
-	0x1a, 0x83, 0x65, 0x72, 0xb8, 0x0d, 0xe8, 0xe4, 0x17, 0x71, 0xfc, 0x1e, 0x6a, 0xb9, 0x09, 0xd5,
-	0x2d, 0x17, 0xba, 0x9f, 0xb4, 0x6a, 0x58, 0x23, 0x0b, 0xaa, 0xf9, 0x3a, 0x9c, 0x5f, 0x08, 0xb0,
-	0xbf, 0x93, 0xf9, 0x15, 0xe8, 0x80, 0xea, 0xe5, 0x3f, 0x03, 0xa0, 0xb2, 0x38, 0x2a, 0xce, 0xc3,
-	0x38, 0x68, 0x92, 0xa6, 0xca, 0xe2, 0xe3, 0xf3, 0xcd, 0xd9, 0x58, 0x9f, 0x0d, 0xde, 0x9c, 0xfe,
-	0xfa, 0xbf, 0x91, 0x4b, 0xb2, 0x84, 0x9a, 0xf1, 0x3a, 0xef, 0xa0, 0x9a, 0xa3, 0x7c, 0xcb, 0xc3,
-	0xf0, 0xde, 0x2e, 0x15, 0xc1, 0x64, 0x62, 0x23, 0xdc, 0x86, 0x46, 0x30, 0xfc, 0x3a, 0x25, 0xfe,
-	0xe2, 0xde, 0x2e, 0x8f, 0xe6, 0xf0, 0x56, 0xa4, 0x5b, 0xf7, 0xe1, 0x20, 0x69, 0x9a, 0xd0, 0xf5,
-	0x96, 0xa6, 0xee, 0x66, 0x19, 0xa7, 0x6c, 0x65, 0x6e, 0x50, 0x1d, 0x7f, 0xfb, 0x71, 0xbd, 0x65,
-	0xfa, 0x21, 0x8b, 0x73, 0xe8, 0x9d, 0x91, 0x3d, 0x43, 0xf6, 0x0c, 0xd9, 0x33, 0xe4, 0xd8, 0x2a,
-	0xe0, 0xcd, 0xdf, 0x00, 0x00, 0x00, 0xff, 0xff, 0x34, 0xab, 0x8a, 0xb5, 0xf9, 0x02, 0x00, 0x00,
+	// 660 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x7c, 0x54, 0x5b, 0x6e, 0xda, 0x4c,
+	0x18, 0xc5, 0x5c, 0x1c, 0xf8, 0x02, 0xc4, 0x19, 0xe5, 0x62, 0x45, 0xff, 0x05, 0x59, 0x55, 0x8b,
+	0x14, 0x15, 0xa4, 0xa4, 0x4f, 0xad, 0x54, 0x89, 0x24, 0xa8, 0xa1, 0x8d, 0x01, 0x0d, 0xa4, 0x55,
+	0xfa, 0x62, 0xf9, 0x32, 0xc0, 0xa8, 0xbe, 0xc9, 0x63, 0xa3, 0xb0, 0x8b, 0x3e, 0x75, 0x0d, 0xdd,
+	0x43, 0xd7, 0xd4, 0x3d, 0x54, 0xe3, 0xb1, 0x1d, 0x92, 0xaa, 0x7d, 0x9b, 0xf3, 0xcd, 0xf9, 0xc6,
+	0xe7, 0x9c, 0xf9, 0xc6, 0x70, 0x68, 0x07, 0x9e, 0x17, 0xf8, 0xfd, 0x30, 0x70, 0xa9, 0x4d, 0x09,
+	0xeb, 0x85, 0x51, 0x10, 0x07, 0x48, 0x16, 0xe5, 0x93, 0x63, 0x8f, 0x85, 0x7d, 0x8f, 0x85, 0x46,
+	0x18, 0x51, 0xdf, 0xa6, 0xa1, 0xe9, 0x0a, 0x82, 0x76, 0x0f, 0xf2, 0x94, 0xb7, 0x6c, 0x10, 0x82,
+	0x6a, 0xbc, 0x09, 0x89, 0x2a, 0x75, 0xa4, 0x6e, 0x0d, 0xa7, 0x6b, 0x74, 0x00, 0xb5, 0xb5, 0xe9,
+	0x26, 0x44, 0x2d, 0x77, 0xa4, 0x6e, 0x13, 0x0b, 0xa0, 0x5d, 0x01, 0x88, 0x9e, 0x39, 0xe7, 0xec,
+	0xc2, 0xce, 0xed, 0xf8, 0xc3, 0x78, 0xf2, 0x69, 0xac, 0x94, 0x50, 0x0b, 0x1a, 0xb3, 0xd1, 0xbb,
+	0xf1, 0x60, 0x7e, 0x8b, 0x87, 0x8a, 0x84, 0x76, 0xa0, 0xa2, 0xcf, 0xa6, 0x4a, 0x19, 0xed, 0x43,
+	0x6b, 0xa4, 0x4f, 0x6f, 0x46, 0x97, 0xa3, 0xb9, 0xa1, 0x0f, 0xe7, 0x03, 0xa5, 0xa2, 0x7d, 0x93,
+	0xe0, 0x78, 0x46, 0x97, 0xbe, 0x19, 0x27, 0x11, 0x11, 0xe7, 0x0d, 0xfd, 0x35, 0x71, 0x83, 0x90,
+	0x20, 0x15, 0x76, 0xd6, 0x24, 0x62, 0x34, 0xf0, 0x33, 0x39, 0x39, 0x44, 0xa7, 0x50, 0x8d, 0x12,
+	0x57, 0x08, 0xda, 0x3d, 0x3b, 0xee, 0x09, 0x7f, 0xbd, 0x27, 0x07, 0xe1, 0x94, 0x84, 0x5e, 0x01,
+	0x50, 0x87, 0xf8, 0x31, 0x8d, 0x29, 0x61, 0x6a, 0xa5, 0x53, 0xe9, 0xee, 0x9e, 0x1d, 0xe4, 0x2d,
+	0xfa, 0x6c, 0x3a, 0xcd, 0xc3, 0xc0, 0x5b, 0x3c, 0xed, 0x87, 0x04, 0x7b, 0x4f, 0xce, 0x43, 0xff,
+	0x42, 0x83, 0xd1, 0xa5, 0x4f, 0x1c, 0xc3, 0xda, 0x08, 0x49, 0xd7, 0x25, 0x5c, 0x17, 0xa5, 0x8b,
+	0x0d, 0x7a, 0x0d, 0x75, 0xdf, 0x08, 0x92, 0xd8, 0x08, 0x16, 0x99, 0xb2, 0xff, 0xfe, 0xa0, 0xac,
+	0x37, 0x9e, 0x24, 0xf1, 0x64, 0x71, 0x5d, 0xc2, 0xb2, 0x9f, 0xae, 0x4e, 0x86, 0x20, 0x8b, 0x1a,
+	0x6a, 0x82, 0x94, 0xfb, 0x95, 0x7c, 0xf4, 0x12, 0x6a, 0xdc, 0x04, 0x53, 0xcb, 0x9d, 0xca, 0xdf,
+	0xac, 0x0a, 0xd6, 0x85, 0x0c, 0x55, 0x7e, 0x1d, 0xda, 0x57, 0x09, 0xd0, 0xc8, 0x0b, 0xf9, 0x14,
+	0xc4, 0x3a, 0x89, 0xcd, 0xc2, 0x00, 0xb0, 0xc4, 0x32, 0xd2, 0xf1, 0x10, 0x0e, 0x1a, 0xb8, 0xc1,
+	0x12, 0x2b, 0xdb, 0x3e, 0xdf, 0x8a, 0xb5, 0x7d, 0xf6, 0x7f, 0xfe, 0xad, 0xdf, 0x0f, 0xea, 0xe1,
+	0xc4, 0x25, 0x22, 0x5e, 0xed, 0x39, 0x54, 0x39, 0xe2, 0xb7, 0x3c, 0x18, 0xdf, 0x29, 0xa5, 0x74,
+	0x71, 0x73, 0xa3, 0x48, 0xa8, 0x09, 0x75, 0x7d, 0xf0, 0x7e, 0x82, 0x47, 0xf3, 0x3b, 0xa5, 0xac,
+	0xfd, 0x94, 0xa0, 0x2d, 0xba, 0xaf, 0x88, 0x4d, 0xd3, 0x6b, 0x3c, 0x02, 0xf9, 0x91, 0x14, 0x39,
+	0x2c, 0x86, 0xb0, 0xd0, 0xd1, 0xc8, 0x6e, 0xf1, 0x1f, 0x68, 0x30, 0x33, 0xa6, 0x6c, 0x41, 0x89,
+	0xa3, 0x56, 0x3a, 0x52, 0xb7, 0x8e, 0x1f, 0x0a, 0x7c, 0x44, 0x49, 0x14, 0x05, 0x91, 0x5a, 0x4d,
+	0x5b, 0x04, 0x40, 0x6f, 0xa0, 0xc5, 0xed, 0x3a, 0xd9, 0xf7, 0x98, 0x5a, 0x4b, 0x43, 0x3c, 0xca,
+	0x8d, 0x3d, 0x96, 0x83, 0x9b, 0x2c, 0xb1, 0x72, 0xc0, 0xd0, 0x5b, 0xd8, 0xcb, 0xc6, 0x61, 0x63,
+	0xd8, 0x2b, 0x62, 0x7f, 0x61, 0xaa, 0x9c, 0xb6, 0x1f, 0x16, 0xb9, 0x64, 0xdb, 0x97, 0x7c, 0x17,
+	0xb7, 0xe9, 0x36, 0x64, 0xda, 0x77, 0x09, 0x5a, 0x8f, 0x18, 0x5c, 0x24, 0xf5, 0x1d, 0x72, 0x9f,
+	0xba, 0x6d, 0x61, 0x01, 0xd0, 0x21, 0xc8, 0xfc, 0x49, 0x52, 0x27, 0xb3, 0x5b, 0xf3, 0x58, 0x38,
+	0x72, 0xd0, 0x29, 0xec, 0x17, 0xaf, 0xd4, 0xf0, 0xcc, 0xd8, 0x5e, 0x15, 0xbe, 0x95, 0x62, 0x43,
+	0x17, 0x75, 0xf4, 0x02, 0xf6, 0x58, 0x3e, 0x10, 0xc6, 0xda, 0x74, 0xa9, 0x93, 0x06, 0x51, 0xc7,
+	0xed, 0xa2, 0xfc, 0x91, 0x57, 0x1f, 0x72, 0xaa, 0x6d, 0xe5, 0x74, 0x31, 0x83, 0x67, 0x41, 0xb4,
+	0xec, 0xad, 0x36, 0x21, 0x89, 0x5c, 0xe2, 0x2c, 0x49, 0xd4, 0x5b, 0x98, 0x56, 0x44, 0x6d, 0xf1,
+	0x7b, 0x60, 0x99, 0xe1, 0xcf, 0xa7, 0x4b, 0x1a, 0xaf, 0x12, 0x8b, 0xc3, 0xfe, 0x16, 0xb9, 0x2f,
+	0xc8, 0x7d, 0x41, 0xee, 0x0b, 0xb2, 0x25, 0xa7, 0xf0, 0xfc, 0xd7, 0x00, 0xcb, 0x2e, 0xc1, 0xde,
+	0x94, 0x04, 0x00, 0x00,
 }
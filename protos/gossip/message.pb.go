@@ -1221,6 +1221,11 @@ type AliveMessage struct {
 	Membership *Member   `protobuf:"bytes,1,opt,name=membership" json:"membership,omitempty"`
 	Timestamp  *PeerTime `protobuf:"bytes,2,opt,name=timestamp" json:"timestamp,omitempty"`
 	Identity   []byte    `protobuf:"bytes,4,opt,name=identity,proto3" json:"identity,omitempty"`
+	// expiration is the time, in nanoseconds since the epoch, after which
+	// the message must not be honored. It is set on the alive messages
+	// carried by membership requests, so that captured requests cannot be
+	// replayed.
+	Expiration int64 `protobuf:"varint,5,opt,name=expiration" json:"expiration,omitempty"`
 }
 
 func (m *AliveMessage) Reset()                    { *m = AliveMessage{} }
@@ -1249,6 +1254,13 @@ func (m *AliveMessage) GetIdentity() []byte {
 	return nil
 }
 
+func (m *AliveMessage) GetExpiration() int64 {
+	if m != nil {
+		return m.Expiration
+	}
+	return 0
+}
+
 // Leadership Message is sent during leader election to inform
 // remote peers about intent of peer to proclaim itself as leader
 type LeadershipMessage struct {
@@ -1620,91 +1632,91 @@ var _Gossip_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("gossip/message.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 1366 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xb4, 0x57, 0xdd, 0x6f, 0xdc, 0x44,
-	0x10, 0x3f, 0x27, 0xf7, 0x39, 0xf7, 0x91, 0xcb, 0x26, 0x05, 0x13, 0x2a, 0x88, 0x2c, 0x5a, 0x05,
-	0x52, 0x2e, 0x55, 0xca, 0x47, 0xa5, 0x82, 0xd0, 0x25, 0x77, 0xe4, 0x22, 0x7a, 0x49, 0xe4, 0xa4,
-	0x82, 0xf2, 0x62, 0x6d, 0xce, 0x13, 0x9f, 0xa9, 0xbd, 0x76, 0xbc, 0x7b, 0x85, 0x3c, 0x22, 0xde,
-	0x78, 0xe1, 0x95, 0x3f, 0x17, 0x79, 0xd7, 0xf6, 0xd9, 0x75, 0x52, 0xa9, 0x95, 0x78, 0xf3, 0x7c,
-	0xfc, 0x66, 0x66, 0x67, 0xe7, 0x63, 0x0d, 0x9b, 0x4e, 0xc0, 0xb9, 0x1b, 0xee, 0xf9, 0xc8, 0x39,
-	0x75, 0x70, 0x10, 0x46, 0x81, 0x08, 0x48, 0x5d, 0x71, 0x8d, 0xbf, 0x34, 0x68, 0x8e, 0xd9, 0x6b,
-	0xf4, 0x82, 0x10, 0x89, 0x0e, 0x8d, 0x90, 0xde, 0x78, 0x01, 0xb5, 0x75, 0x6d, 0x5b, 0xdb, 0xe9,
-	0x98, 0x29, 0x49, 0xee, 0x43, 0x8b, 0xbb, 0x0e, 0xa3, 0x62, 0x11, 0xa1, 0xbe, 0x22, 0x65, 0x4b,
-	0x06, 0xf9, 0x01, 0xd6, 0x38, 0xce, 0x22, 0x14, 0x16, 0x26, 0xa6, 0xf4, 0xd5, 0x6d, 0x6d, 0xa7,
-	0xbd, 0xff, 0xc1, 0x40, 0xb9, 0x19, 0x9c, 0x4b, 0x71, 0xea, 0xc8, 0xec, 0xf1, 0x02, 0x6d, 0x4c,
-	0xa0, 0x57, 0xd4, 0x78, 0xdf, 0x50, 0x8c, 0x21, 0xd4, 0x95, 0x25, 0xf2, 0x08, 0xfa, 0x2e, 0x13,
-	0x18, 0x31, 0xea, 0x8d, 0x99, 0x1d, 0x06, 0x2e, 0x13, 0xd2, 0x54, 0x6b, 0x52, 0x31, 0x4b, 0x92,
-	0x83, 0x16, 0x34, 0x66, 0x01, 0x13, 0xc8, 0x84, 0xf1, 0x6f, 0x0b, 0xba, 0x47, 0x32, 0xec, 0xa9,
-	0x4a, 0x19, 0xd9, 0x84, 0x1a, 0x0b, 0xd8, 0x0c, 0x25, 0xbe, 0x6a, 0x2a, 0x22, 0x0e, 0x71, 0x36,
-	0xa7, 0x8c, 0xa1, 0x97, 0x84, 0x91, 0x92, 0x64, 0x17, 0x56, 0x05, 0x75, 0x64, 0x0e, 0x7a, 0xfb,
-	0x1f, 0xa5, 0x39, 0x28, 0xd8, 0x1c, 0x5c, 0x50, 0xc7, 0x8c, 0xb5, 0xc8, 0x13, 0x68, 0x51, 0xcf,
-	0x7d, 0x8d, 0x96, 0xcf, 0x1d, 0xbd, 0x26, 0xd3, 0xb6, 0x99, 0x42, 0x86, 0xb1, 0x20, 0x41, 0x4c,
-	0x2a, 0x66, 0x53, 0x2a, 0x4e, 0xb9, 0x43, 0xbe, 0x82, 0x86, 0x8f, 0xbe, 0x15, 0xe1, 0xb5, 0x5e,
-	0x97, 0x90, 0xcc, 0xcb, 0x14, 0xfd, 0x4b, 0x8c, 0xf8, 0xdc, 0x0d, 0x4d, 0xbc, 0x5e, 0x20, 0x17,
-	0x93, 0x8a, 0x59, 0xf7, 0xd1, 0x37, 0xf1, 0x9a, 0x7c, 0x9d, 0xa2, 0xb8, 0xde, 0x90, 0xa8, 0xad,
-	0xdb, 0x50, 0x3c, 0x0c, 0x18, 0xc7, 0x0c, 0xc6, 0xc9, 0x63, 0x68, 0xda, 0x54, 0x50, 0x19, 0x60,
-	0x53, 0xe2, 0x36, 0x52, 0xdc, 0x88, 0x0a, 0xba, 0x8c, 0xaf, 0x11, 0xab, 0xc5, 0xe1, 0xed, 0x42,
-	0x6d, 0x8e, 0x9e, 0x17, 0xe8, 0xad, 0xa2, 0xba, 0x4a, 0xc1, 0x24, 0x16, 0x4d, 0x2a, 0xa6, 0xd2,
-	0x21, 0x7b, 0x89, 0x79, 0xdb, 0x75, 0x74, 0x90, 0xfa, 0x24, 0x6f, 0x7e, 0xe4, 0x3a, 0xea, 0x14,
-	0xd2, 0xfa, 0xc8, 0x75, 0xb2, 0x78, 0xe2, 0xd3, 0xb7, 0xcb, 0xf1, 0x2c, 0xcf, 0x2d, 0x11, 0xea,
-	0xe0, 0x6d, 0x89, 0x58, 0x84, 0x36, 0x15, 0xa8, 0x77, 0xca, 0x5e, 0x5e, 0x48, 0xc9, 0xa4, 0x62,
-	0x82, 0x9d, 0x51, 0xe4, 0x01, 0xd4, 0xd0, 0x0f, 0xc5, 0x8d, 0xde, 0x95, 0x80, 0x6e, 0x0a, 0x18,
-	0xc7, 0xcc, 0xf8, 0x00, 0x52, 0x4a, 0x76, 0xa1, 0x3a, 0x0b, 0x18, 0xd3, 0x7b, 0x52, 0xeb, 0x5e,
-	0xaa, 0x75, 0x18, 0x30, 0x36, 0xe6, 0x82, 0x5e, 0x7a, 0x2e, 0x9f, 0x4f, 0x2a, 0xa6, 0x54, 0x22,
-	0xfb, 0x00, 0x5c, 0x50, 0x81, 0x96, 0xcb, 0xae, 0x02, 0x7d, 0x4d, 0x42, 0xd6, 0xb3, 0x36, 0x89,
-	0x25, 0xc7, 0xec, 0x2a, 0xce, 0x4e, 0x8b, 0xa7, 0x04, 0x39, 0x80, 0x9e, 0xc2, 0x70, 0x46, 0x43,
-	0x3e, 0x0f, 0x84, 0xde, 0x2f, 0x5e, 0x7a, 0x86, 0x3b, 0x4f, 0x14, 0x26, 0x15, 0xb3, 0x2b, 0x21,
-	0x29, 0x83, 0x4c, 0x61, 0x63, 0xe9, 0xd7, 0x0a, 0x17, 0x9e, 0x27, 0xf3, 0xb7, 0x2e, 0x0d, 0xdd,
-	0x2f, 0x19, 0x3a, 0x5b, 0x78, 0xde, 0x32, 0x91, 0x7d, 0xfe, 0x06, 0x9f, 0x0c, 0x41, 0xd9, 0x8f,
-	0x8d, 0xc4, 0x4a, 0x3a, 0x29, 0x16, 0x94, 0x89, 0x7e, 0x20, 0x50, 0x9a, 0x5b, 0x9a, 0xe9, 0xf0,
-	0x1c, 0x4d, 0x46, 0xe9, 0xa9, 0xa2, 0xa4, 0xe4, 0xf4, 0x0d, 0x69, 0xe3, 0xe3, 0x5b, 0x6d, 0x64,
-	0x55, 0xd9, 0xe5, 0x79, 0x46, 0x9c, 0x1b, 0x0f, 0xa9, 0xad, 0x8a, 0x57, 0x96, 0xe8, 0x66, 0x31,
-	0x37, 0xcf, 0x33, 0xe9, 0xb2, 0x50, 0xbb, 0x4b, 0x48, 0x5c, 0xae, 0xcf, 0xa0, 0x1b, 0x22, 0x46,
-	0x96, 0x6b, 0x23, 0x13, 0xae, 0xb8, 0xd1, 0xef, 0x15, 0xdb, 0xf0, 0x0c, 0x31, 0x3a, 0x4e, 0x64,
-	0xf1, 0x31, 0xc2, 0x1c, 0x6d, 0x58, 0xb0, 0x7a, 0x41, 0x1d, 0xd2, 0x85, 0xd6, 0x8b, 0x93, 0xd1,
-	0xf8, 0xc7, 0xe3, 0x93, 0xf1, 0xa8, 0x5f, 0x21, 0x2d, 0xa8, 0x8d, 0xa7, 0x67, 0x17, 0x2f, 0xfb,
-	0x1a, 0xe9, 0x40, 0xf3, 0xd4, 0x3c, 0xb2, 0x4e, 0x4f, 0x9e, 0xbf, 0xec, 0xaf, 0xc4, 0x7a, 0x87,
-	0x93, 0xe1, 0x89, 0x22, 0x57, 0x49, 0x1f, 0x3a, 0x92, 0x1c, 0x9e, 0x8c, 0xac, 0x53, 0xf3, 0xa8,
-	0x5f, 0x25, 0x6b, 0xd0, 0x56, 0x0a, 0xa6, 0x64, 0xd4, 0xf2, 0xa3, 0xe9, 0x1f, 0x0d, 0x5a, 0xd9,
-	0x15, 0x91, 0x2d, 0x68, 0xfa, 0x28, 0x68, 0x5c, 0xb0, 0xc9, 0x90, 0xcc, 0x68, 0x32, 0x80, 0x96,
-	0x70, 0x7d, 0xe4, 0x82, 0xfa, 0xa1, 0x1c, 0x4f, 0xed, 0xfd, 0x7e, 0xfe, 0x38, 0x17, 0xae, 0x8f,
-	0xe6, 0x52, 0x85, 0xdc, 0x83, 0x7a, 0xf8, 0xca, 0xb5, 0x5c, 0x5b, 0x4e, 0xad, 0x8e, 0x59, 0x0b,
-	0x5f, 0xb9, 0xc7, 0x36, 0xf9, 0x14, 0xda, 0xc9, 0x50, 0xb3, 0xa6, 0xc3, 0x43, 0xbd, 0x2a, 0x65,
-	0x90, 0xb0, 0xa6, 0xc3, 0x43, 0x63, 0x08, 0xeb, 0xa5, 0xe2, 0x23, 0x8f, 0xa0, 0x89, 0x1e, 0xfa,
-	0xc8, 0x04, 0xd7, 0xb5, 0xed, 0xd5, 0xbc, 0xef, 0x6c, 0x05, 0x64, 0x1a, 0xc6, 0xb7, 0xb0, 0x79,
-	0x5b, 0xd9, 0xbd, 0xe9, 0x5b, 0x2b, 0xf9, 0xbe, 0x82, 0x6e, 0xa1, 0xc7, 0x72, 0x87, 0xd0, 0xf2,
-	0x87, 0xd8, 0x82, 0x66, 0x76, 0xb3, 0x6a, 0x52, 0x67, 0x34, 0x31, 0xa0, 0x2b, 0x3c, 0x6e, 0xcd,
-	0x30, 0x12, 0xd6, 0x9c, 0xf2, 0x79, 0x72, 0xfc, 0xb6, 0xf0, 0xf8, 0x21, 0x46, 0x62, 0x42, 0xf9,
-	0xdc, 0x78, 0x01, 0x9d, 0x7c, 0x05, 0xdc, 0xe5, 0x86, 0x40, 0x35, 0x36, 0x93, 0xb8, 0x90, 0xdf,
-	0x85, 0x2b, 0x5a, 0x2d, 0x5e, 0x91, 0xe1, 0x43, 0x3b, 0x37, 0xae, 0xee, 0x5e, 0x32, 0xb6, 0x1c,
-	0x80, 0x5c, 0x5f, 0xd9, 0x5e, 0xdd, 0x69, 0x99, 0x29, 0x49, 0x06, 0xd0, 0xf4, 0xb9, 0x63, 0x89,
-	0x9b, 0x64, 0xdb, 0xf6, 0x96, 0x53, 0x30, 0xce, 0xe2, 0x94, 0x3b, 0x17, 0x37, 0x21, 0x9a, 0x0d,
-	0x5f, 0x7d, 0x18, 0x01, 0xb4, 0x73, 0xe3, 0xf7, 0x0e, 0x77, 0xf9, 0x78, 0x57, 0x4a, 0x25, 0xf5,
-	0x6e, 0x0e, 0xff, 0x00, 0x58, 0x4e, 0xd6, 0x3b, 0xfc, 0x7d, 0x06, 0xd5, 0xc4, 0xd7, 0xed, 0x55,
-	0x52, 0x7d, 0x2f, 0xcf, 0x9e, 0xf2, 0xac, 0x36, 0xc7, 0xff, 0x9e, 0xd8, 0xa7, 0xea, 0x1e, 0xd3,
-	0xc7, 0xc2, 0xe7, 0xc5, 0x97, 0x4b, 0x7b, 0x7f, 0x2d, 0x43, 0x2b, 0x76, 0xf6, 0x94, 0x31, 0xbe,
-	0x81, 0x46, 0xc2, 0x23, 0x1f, 0x42, 0x83, 0xe3, 0xb5, 0xc5, 0x16, 0x7e, 0x12, 0x66, 0x9d, 0xe3,
-	0xf5, 0xc9, 0xc2, 0x8f, 0xab, 0x2a, 0x77, 0x1b, 0xf2, 0xdb, 0xf8, 0x5b, 0x83, 0x4e, 0xfe, 0x69,
-	0x40, 0x06, 0x00, 0x7e, 0xb6, 0xc1, 0x13, 0xb7, 0xbd, 0xe2, 0x6e, 0x37, 0x73, 0x1a, 0xef, 0x3c,
-	0x1d, 0xf2, 0x1d, 0x54, 0x2d, 0x76, 0x90, 0xf1, 0xa7, 0x06, 0xeb, 0xa5, 0x19, 0x7b, 0x57, 0x8f,
-	0xbc, 0xab, 0xe3, 0x07, 0xd0, 0x73, 0xb9, 0x65, 0xe3, 0xcc, 0xa3, 0x11, 0x15, 0x6e, 0xc0, 0xe4,
-	0x8d, 0x34, 0xcd, 0xae, 0xcb, 0x47, 0x4b, 0xa6, 0xf1, 0x1d, 0x34, 0x53, 0x74, 0x9c, 0x49, 0x97,
-	0xcd, 0xf2, 0x99, 0x74, 0xd9, 0x2c, 0xce, 0x64, 0x2e, 0xc5, 0x2b, 0xf9, 0x14, 0x1b, 0x57, 0xb0,
-	0x5e, 0x7a, 0x35, 0x91, 0x67, 0xd0, 0xe7, 0xe8, 0x5d, 0xc9, 0x75, 0x19, 0xf9, 0xca, 0xb7, 0x56,
-	0x0c, 0x38, 0xab, 0xd2, 0xb5, 0x58, 0xf3, 0x78, 0xa9, 0x18, 0x97, 0xdc, 0x2b, 0x16, 0xfc, 0xce,
-	0x64, 0x69, 0x75, 0x4c, 0x45, 0x18, 0x97, 0x40, 0xca, 0xef, 0x2c, 0xf2, 0x10, 0x6a, 0xf2, 0x59,
-	0x77, 0xe7, 0xa4, 0x54, 0x62, 0xd9, 0x2a, 0x48, 0xed, 0xb7, 0xb4, 0x0a, 0x52, 0xdb, 0xf8, 0x19,
-	0xea, 0xca, 0x47, 0x7c, 0x67, 0x58, 0x78, 0xf7, 0x9a, 0x19, 0xfd, 0xd6, 0x36, 0xbf, 0x7d, 0x13,
-	0x18, 0x0d, 0xa8, 0xc9, 0x67, 0x8f, 0xf1, 0x0b, 0x90, 0xf2, 0x72, 0x8f, 0xe7, 0x28, 0x17, 0x34,
-	0x12, 0x56, 0xb1, 0x8a, 0xdb, 0x92, 0x79, 0xae, 0x4a, 0xf9, 0x13, 0x68, 0x23, 0xb3, 0xad, 0xe2,
-	0x25, 0xb4, 0x90, 0xd9, 0x4a, 0x6e, 0x1c, 0xc0, 0xc6, 0x2d, 0x2b, 0x9f, 0xec, 0x42, 0x33, 0x69,
-	0x98, 0x74, 0x9b, 0x94, 0x3a, 0x2a, 0x53, 0xf8, 0xe2, 0x7b, 0x68, 0xe7, 0x9a, 0xf4, 0xcd, 0xad,
-	0xdc, 0x85, 0xd6, 0xc1, 0xf3, 0xd3, 0xc3, 0x9f, 0xac, 0xe9, 0xf9, 0x51, 0x5f, 0x8b, 0x97, 0xef,
-	0xf1, 0x68, 0x7c, 0x72, 0x71, 0x7c, 0xf1, 0x52, 0x72, 0x56, 0xf6, 0x7f, 0x83, 0xba, 0x1a, 0x92,
-	0xe4, 0x29, 0x74, 0xd4, 0xd7, 0xb9, 0x88, 0x90, 0xfa, 0xa4, 0x94, 0xf0, 0xad, 0x12, 0xc7, 0xa8,
-	0xec, 0x68, 0x8f, 0x35, 0xf2, 0x10, 0xaa, 0x67, 0x2e, 0x73, 0x48, 0xf1, 0xb9, 0xb8, 0x55, 0x24,
-	0x8d, 0xca, 0xc1, 0x97, 0xbf, 0xee, 0x3a, 0xae, 0x98, 0x2f, 0x2e, 0x07, 0xb3, 0xc0, 0xdf, 0x9b,
-	0xdf, 0x84, 0x18, 0x79, 0x68, 0x3b, 0x18, 0xed, 0x5d, 0xd1, 0xcb, 0xc8, 0x9d, 0xed, 0xc9, 0x3f,
-	0x35, 0xbe, 0xa7, 0x60, 0x97, 0x75, 0x49, 0x3e, 0xf9, 0x2f, 0x00, 0x00, 0xff, 0xff, 0x74, 0x25,
-	0x05, 0xf1, 0xd0, 0x0d, 0x00, 0x00,
+	// 1373 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xb4, 0x17, 0x6b, 0x6f, 0xdb, 0x54,
+	0x34, 0x6e, 0xde, 0x27, 0x8f, 0xa6, 0xb7, 0x1d, 0x98, 0x32, 0x8d, 0xca, 0x62, 0x53, 0xa1, 0x23,
+	0x9d, 0x3a, 0x1e, 0x93, 0x06, 0x42, 0x69, 0x13, 0x9a, 0x8a, 0xa5, 0xad, 0xdc, 0x4e, 0x30, 0xbe,
+	0x58, 0xb7, 0xf1, 0xa9, 0x63, 0x66, 0x5f, 0xbb, 0xbe, 0x37, 0x63, 0xfd, 0x88, 0xf8, 0x01, 0x7c,
+	0xe5, 0x37, 0xf0, 0x2b, 0x91, 0xef, 0xb5, 0x1d, 0xbb, 0x69, 0x27, 0x6d, 0x12, 0xdf, 0x7c, 0xde,
+	0xe7, 0x9e, 0xb7, 0x61, 0xc3, 0x09, 0x38, 0x77, 0xc3, 0x5d, 0x1f, 0x39, 0xa7, 0x0e, 0xf6, 0xc3,
+	0x28, 0x10, 0x01, 0xa9, 0x29, 0xac, 0xf1, 0x97, 0x06, 0x8d, 0x11, 0x7b, 0x83, 0x5e, 0x10, 0x22,
+	0xd1, 0xa1, 0x1e, 0xd2, 0x6b, 0x2f, 0xa0, 0xb6, 0xae, 0x6d, 0x69, 0xdb, 0x6d, 0x33, 0x05, 0xc9,
+	0x7d, 0x68, 0x72, 0xd7, 0x61, 0x54, 0xcc, 0x23, 0xd4, 0x57, 0x24, 0x6d, 0x81, 0x20, 0x3f, 0xc2,
+	0x2a, 0xc7, 0x69, 0x84, 0xc2, 0xc2, 0x44, 0x95, 0x5e, 0xde, 0xd2, 0xb6, 0x5b, 0x7b, 0x1f, 0xf5,
+	0x95, 0x99, 0xfe, 0x99, 0x24, 0xa7, 0x86, 0xcc, 0x2e, 0x2f, 0xc0, 0xc6, 0x18, 0xba, 0x45, 0x8e,
+	0x0f, 0x75, 0xc5, 0x18, 0x40, 0x4d, 0x69, 0x22, 0x8f, 0xa1, 0xe7, 0x32, 0x81, 0x11, 0xa3, 0xde,
+	0x88, 0xd9, 0x61, 0xe0, 0x32, 0x21, 0x55, 0x35, 0xc7, 0x25, 0x73, 0x89, 0xb2, 0xdf, 0x84, 0xfa,
+	0x34, 0x60, 0x02, 0x99, 0x30, 0xfe, 0x69, 0x42, 0xe7, 0x50, 0xba, 0x3d, 0x51, 0x21, 0x23, 0x1b,
+	0x50, 0x65, 0x01, 0x9b, 0xa2, 0x94, 0xaf, 0x98, 0x0a, 0x88, 0x5d, 0x9c, 0xce, 0x28, 0x63, 0xe8,
+	0x25, 0x6e, 0xa4, 0x20, 0xd9, 0x81, 0xb2, 0xa0, 0x8e, 0x8c, 0x41, 0x77, 0xef, 0x93, 0x34, 0x06,
+	0x05, 0x9d, 0xfd, 0x73, 0xea, 0x98, 0x31, 0x17, 0x79, 0x0a, 0x4d, 0xea, 0xb9, 0x6f, 0xd0, 0xf2,
+	0xb9, 0xa3, 0x57, 0x65, 0xd8, 0x36, 0x52, 0x91, 0x41, 0x4c, 0x48, 0x24, 0xc6, 0x25, 0xb3, 0x21,
+	0x19, 0x27, 0xdc, 0x21, 0x5f, 0x43, 0xdd, 0x47, 0xdf, 0x8a, 0xf0, 0x4a, 0xaf, 0x49, 0x91, 0xcc,
+	0xca, 0x04, 0xfd, 0x0b, 0x8c, 0xf8, 0xcc, 0x0d, 0x4d, 0xbc, 0x9a, 0x23, 0x17, 0xe3, 0x92, 0x59,
+	0xf3, 0xd1, 0x37, 0xf1, 0x8a, 0x7c, 0x93, 0x4a, 0x71, 0xbd, 0x2e, 0xa5, 0x36, 0x6f, 0x93, 0xe2,
+	0x61, 0xc0, 0x38, 0x66, 0x62, 0x9c, 0x3c, 0x81, 0x86, 0x4d, 0x05, 0x95, 0x0e, 0x36, 0xa4, 0xdc,
+	0x7a, 0x2a, 0x37, 0xa4, 0x82, 0x2e, 0xfc, 0xab, 0xc7, 0x6c, 0xb1, 0x7b, 0x3b, 0x50, 0x9d, 0xa1,
+	0xe7, 0x05, 0x7a, 0xb3, 0xc8, 0xae, 0x42, 0x30, 0x8e, 0x49, 0xe3, 0x92, 0xa9, 0x78, 0xc8, 0x6e,
+	0xa2, 0xde, 0x76, 0x1d, 0x1d, 0x24, 0x3f, 0xc9, 0xab, 0x1f, 0xba, 0x8e, 0x7a, 0x85, 0xd4, 0x3e,
+	0x74, 0x9d, 0xcc, 0x9f, 0xf8, 0xf5, 0xad, 0x65, 0x7f, 0x16, 0xef, 0x96, 0x12, 0xea, 0xe1, 0x2d,
+	0x29, 0x31, 0x0f, 0x6d, 0x2a, 0x50, 0x6f, 0x2f, 0x5b, 0x79, 0x29, 0x29, 0xe3, 0x92, 0x09, 0x76,
+	0x06, 0x91, 0x87, 0x50, 0x45, 0x3f, 0x14, 0xd7, 0x7a, 0x47, 0x0a, 0x74, 0x52, 0x81, 0x51, 0x8c,
+	0x8c, 0x1f, 0x20, 0xa9, 0x64, 0x07, 0x2a, 0xd3, 0x80, 0x31, 0xbd, 0x2b, 0xb9, 0xee, 0xa5, 0x5c,
+	0x07, 0x01, 0x63, 0x23, 0x2e, 0xe8, 0x85, 0xe7, 0xf2, 0xd9, 0xb8, 0x64, 0x4a, 0x26, 0xb2, 0x07,
+	0xc0, 0x05, 0x15, 0x68, 0xb9, 0xec, 0x32, 0xd0, 0x57, 0xa5, 0xc8, 0x5a, 0xd6, 0x26, 0x31, 0xe5,
+	0x88, 0x5d, 0xc6, 0xd1, 0x69, 0xf2, 0x14, 0x20, 0xfb, 0xd0, 0x55, 0x32, 0x9c, 0xd1, 0x90, 0xcf,
+	0x02, 0xa1, 0xf7, 0x8a, 0x49, 0xcf, 0xe4, 0xce, 0x12, 0x86, 0x71, 0xc9, 0xec, 0x48, 0x91, 0x14,
+	0x41, 0x26, 0xb0, 0xbe, 0xb0, 0x6b, 0x85, 0x73, 0xcf, 0x93, 0xf1, 0x5b, 0x93, 0x8a, 0xee, 0x2f,
+	0x29, 0x3a, 0x9d, 0x7b, 0xde, 0x22, 0x90, 0x3d, 0x7e, 0x03, 0x4f, 0x06, 0xa0, 0xf4, 0x5b, 0x91,
+	0x62, 0xd2, 0x49, 0xb1, 0xa0, 0x4c, 0xf4, 0x03, 0x81, 0x52, 0xdd, 0x42, 0x4d, 0x9b, 0xe7, 0x60,
+	0x32, 0x4c, 0x5f, 0x15, 0x25, 0x25, 0xa7, 0xaf, 0x4b, 0x1d, 0x9f, 0xde, 0xaa, 0x23, 0xab, 0xca,
+	0x0e, 0xcf, 0x23, 0xe2, 0xd8, 0x78, 0x48, 0x6d, 0x55, 0xbc, 0xb2, 0x44, 0x37, 0x8a, 0xb1, 0x79,
+	0x91, 0x51, 0x17, 0x85, 0xda, 0x59, 0x88, 0xc4, 0xe5, 0xfa, 0x1c, 0x3a, 0x21, 0x62, 0x64, 0xb9,
+	0x36, 0x32, 0xe1, 0x8a, 0x6b, 0xfd, 0x5e, 0xb1, 0x0d, 0x4f, 0x11, 0xa3, 0xa3, 0x84, 0x16, 0x3f,
+	0x23, 0xcc, 0xc1, 0x86, 0x05, 0xe5, 0x73, 0xea, 0x90, 0x0e, 0x34, 0x5f, 0x1e, 0x0f, 0x47, 0x3f,
+	0x1d, 0x1d, 0x8f, 0x86, 0xbd, 0x12, 0x69, 0x42, 0x75, 0x34, 0x39, 0x3d, 0x7f, 0xd5, 0xd3, 0x48,
+	0x1b, 0x1a, 0x27, 0xe6, 0xa1, 0x75, 0x72, 0xfc, 0xe2, 0x55, 0x6f, 0x25, 0xe6, 0x3b, 0x18, 0x0f,
+	0x8e, 0x15, 0x58, 0x26, 0x3d, 0x68, 0x4b, 0x70, 0x70, 0x3c, 0xb4, 0x4e, 0xcc, 0xc3, 0x5e, 0x85,
+	0xac, 0x42, 0x4b, 0x31, 0x98, 0x12, 0x51, 0xcd, 0x8f, 0xa6, 0xbf, 0x35, 0x68, 0x66, 0x29, 0x22,
+	0x9b, 0xd0, 0xf0, 0x51, 0xd0, 0xb8, 0x60, 0x93, 0x21, 0x99, 0xc1, 0xa4, 0x0f, 0x4d, 0xe1, 0xfa,
+	0xc8, 0x05, 0xf5, 0x43, 0x39, 0x9e, 0x5a, 0x7b, 0xbd, 0xfc, 0x73, 0xce, 0x5d, 0x1f, 0xcd, 0x05,
+	0x0b, 0xb9, 0x07, 0xb5, 0xf0, 0xb5, 0x6b, 0xb9, 0xb6, 0x9c, 0x5a, 0x6d, 0xb3, 0x1a, 0xbe, 0x76,
+	0x8f, 0x6c, 0xf2, 0x19, 0xb4, 0x92, 0xa1, 0x66, 0x4d, 0x06, 0x07, 0x7a, 0x45, 0xd2, 0x20, 0x41,
+	0x4d, 0x06, 0x07, 0xc6, 0x00, 0xd6, 0x96, 0x8a, 0x8f, 0x3c, 0x86, 0x06, 0x7a, 0xe8, 0x23, 0x13,
+	0x5c, 0xd7, 0xb6, 0xca, 0x79, 0xdb, 0xd9, 0x0a, 0xc8, 0x38, 0x8c, 0xef, 0x60, 0xe3, 0xb6, 0xb2,
+	0xbb, 0x69, 0x5b, 0x5b, 0xb2, 0x7d, 0x09, 0x9d, 0x42, 0x8f, 0xe5, 0x1e, 0xa1, 0xe5, 0x1f, 0xb1,
+	0x09, 0x8d, 0x2c, 0xb3, 0x6a, 0x52, 0x67, 0x30, 0x31, 0xa0, 0x23, 0x3c, 0x6e, 0x4d, 0x31, 0x12,
+	0xd6, 0x8c, 0xf2, 0x59, 0xf2, 0xfc, 0x96, 0xf0, 0xf8, 0x01, 0x46, 0x62, 0x4c, 0xf9, 0xcc, 0x78,
+	0x09, 0xed, 0x7c, 0x05, 0xdc, 0x65, 0x86, 0x40, 0x25, 0x56, 0x93, 0x98, 0x90, 0xdf, 0x85, 0x14,
+	0x95, 0x8b, 0x29, 0x32, 0x7c, 0x68, 0xe5, 0xc6, 0xd5, 0xdd, 0x4b, 0xc6, 0x96, 0x03, 0x90, 0xeb,
+	0x2b, 0x5b, 0xe5, 0xed, 0xa6, 0x99, 0x82, 0xa4, 0x0f, 0x0d, 0x9f, 0x3b, 0x96, 0xb8, 0x4e, 0xb6,
+	0x6d, 0x77, 0x31, 0x05, 0xe3, 0x28, 0x4e, 0xb8, 0x73, 0x7e, 0x1d, 0xa2, 0x59, 0xf7, 0xd5, 0x87,
+	0x11, 0x40, 0x2b, 0x37, 0x7e, 0xef, 0x30, 0x97, 0xf7, 0x77, 0x65, 0xa9, 0xa4, 0xde, 0xcf, 0xe0,
+	0x5b, 0x80, 0xc5, 0x64, 0xbd, 0xc3, 0xde, 0xe7, 0x50, 0x49, 0x6c, 0xdd, 0x5e, 0x25, 0x95, 0x0f,
+	0xb2, 0xec, 0x01, 0x2c, 0x36, 0xc7, 0xff, 0x1e, 0xd8, 0x67, 0x2a, 0x8f, 0xe9, 0xb1, 0xf0, 0x45,
+	0xf1, 0x72, 0x69, 0xed, 0xad, 0x66, 0xd2, 0x0a, 0x9d, 0x9d, 0x32, 0xc6, 0xb7, 0x50, 0x4f, 0x70,
+	0xe4, 0x63, 0xa8, 0x73, 0xbc, 0xb2, 0xd8, 0xdc, 0x4f, 0xdc, 0xac, 0x71, 0xbc, 0x3a, 0x9e, 0xfb,
+	0x71, 0x55, 0xe5, 0xb2, 0x21, 0xbf, 0x8d, 0x7f, 0x35, 0x68, 0xe7, 0x4f, 0x03, 0xd2, 0x07, 0xf0,
+	0xb3, 0x0d, 0x9e, 0x98, 0xed, 0x16, 0x77, 0xbb, 0x99, 0xe3, 0x78, 0xef, 0xe9, 0x90, 0xef, 0xa0,
+	0xca, 0x8d, 0x0e, 0x7a, 0x00, 0x80, 0x6f, 0x43, 0x37, 0xa2, 0xc2, 0x0d, 0x98, 0x3c, 0x60, 0xca,
+	0x66, 0x0e, 0x63, 0xfc, 0xa9, 0xc1, 0xda, 0xd2, 0x0c, 0xbe, 0xab, 0x87, 0xde, 0xd7, 0xb1, 0x87,
+	0xd0, 0x75, 0xb9, 0x65, 0xe3, 0xd4, 0xa3, 0x89, 0x03, 0x71, 0xc6, 0x1a, 0x66, 0xc7, 0xe5, 0xc3,
+	0x05, 0xd2, 0xf8, 0x1e, 0x1a, 0xa9, 0x74, 0x1c, 0x69, 0x97, 0x4d, 0xf3, 0x91, 0x76, 0xd9, 0x34,
+	0x8e, 0x74, 0x2e, 0x05, 0x2b, 0xf9, 0x14, 0x18, 0x97, 0xb0, 0xb6, 0x74, 0x55, 0x91, 0xe7, 0xd0,
+	0xe3, 0xe8, 0x5d, 0xca, 0x75, 0x1a, 0xf9, 0xca, 0xb6, 0xb6, 0xa5, 0xdd, 0x5a, 0xc5, 0xab, 0x31,
+	0xe7, 0xd1, 0x82, 0x31, 0x2e, 0xc9, 0xd7, 0x2c, 0xf8, 0x83, 0xc9, 0xd2, 0x6b, 0x9b, 0x0a, 0x30,
+	0x2e, 0x80, 0x2c, 0xdf, 0x61, 0xe4, 0x11, 0x54, 0xe5, 0xd9, 0x77, 0xe7, 0x24, 0x55, 0x64, 0xd9,
+	0x4a, 0x48, 0xed, 0x77, 0xb4, 0x12, 0x52, 0xdb, 0xf8, 0x05, 0x6a, 0xca, 0x46, 0x9c, 0x53, 0x2c,
+	0xdc, 0xc5, 0x66, 0x06, 0xbf, 0x73, 0x0c, 0xdc, 0xbe, 0x29, 0x8c, 0x3a, 0x54, 0xe5, 0x59, 0x64,
+	0xfc, 0x0a, 0x64, 0x79, 0xf9, 0xc7, 0x73, 0x96, 0x0b, 0x1a, 0x09, 0xab, 0x58, 0xe5, 0x2d, 0x89,
+	0x3c, 0x53, 0xa5, 0xfe, 0x00, 0x5a, 0xc8, 0x6c, 0xab, 0x98, 0x84, 0x26, 0x32, 0x5b, 0xd1, 0x8d,
+	0x7d, 0x58, 0xbf, 0xe5, 0x24, 0x20, 0x3b, 0xd0, 0x48, 0x1a, 0x2a, 0xdd, 0x36, 0x4b, 0x1d, 0x97,
+	0x31, 0x7c, 0xf9, 0x03, 0xb4, 0x72, 0x4d, 0x7c, 0x73, 0x6b, 0x77, 0xa0, 0xb9, 0xff, 0xe2, 0xe4,
+	0xe0, 0x67, 0x6b, 0x72, 0x76, 0xd8, 0xd3, 0xe2, 0xe5, 0x7c, 0x34, 0x1c, 0x1d, 0x9f, 0x1f, 0x9d,
+	0xbf, 0x92, 0x98, 0x95, 0xbd, 0xdf, 0xa1, 0xa6, 0x86, 0x28, 0x79, 0x06, 0x6d, 0xf5, 0x75, 0x26,
+	0x22, 0xa4, 0x3e, 0x59, 0x0a, 0xf8, 0xe6, 0x12, 0xc6, 0x28, 0x6d, 0x6b, 0x4f, 0x34, 0xf2, 0x08,
+	0x2a, 0xa7, 0x2e, 0x73, 0x48, 0xf1, 0x9c, 0xdc, 0x2c, 0x82, 0x46, 0x69, 0xff, 0xab, 0xdf, 0x76,
+	0x1c, 0x57, 0xcc, 0xe6, 0x17, 0xfd, 0x69, 0xe0, 0xef, 0xce, 0xae, 0x43, 0x8c, 0x3c, 0xb4, 0x1d,
+	0x8c, 0x76, 0x2f, 0xe9, 0x45, 0xe4, 0x4e, 0x77, 0xe5, 0x9f, 0x1c, 0xdf, 0x55, 0x62, 0x17, 0x35,
+	0x09, 0x3e, 0xfd, 0x6f, 0x00, 0x98, 0x50, 0xf7, 0x1a, 0xf0, 0x0d, 0x00, 0x00,
 }
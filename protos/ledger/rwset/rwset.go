@@ -0,0 +1,27 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package rwset
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+)
+
+// The key-value data model is the only one defined, so the read-write sets of the
+// namespaces are always decoded as KVRWSet messages
+func (nrws *NsReadWriteSet) StaticallyOpaqueFields() []string {
+	return []string{"rwset"}
+}
+
+func (nrws *NsReadWriteSet) StaticallyOpaqueFieldProto(name string) (proto.Message, error) {
+	if name != nrws.StaticallyOpaqueFields()[0] {
+		return nil, fmt.Errorf("not a marshaled field: %s", name)
+	}
+	return &kvrwset.KVRWSet{}, nil
+}
@@ -11,15 +11,30 @@ It is generated from these files:
 
 It has these top-level messages:
 	BroadcastResponse
+	BroadcastError
+	ErrorDetail
+	CommitNotification
+	ConfigNotification
+	HeightSubscription
+	HeightNotification
+	Usage
+	Quota
+	TenantUsage
+	UsageReport
+	ConfigUpdateValidation
+	TraceMetadata
+	TracePosition
 	SeekNewest
 	SeekOldest
 	SeekSpecified
 	SeekPosition
 	SeekInfo
 	DeliverResponse
+	ChannelDeliverResponse
 	ConsensusType
 	BatchSize
 	BatchTimeout
+	BlockSchedule
 	KafkaBrokers
 	ChannelRestrictions
 	KafkaMessage
@@ -34,6 +49,8 @@ import proto "github.com/golang/protobuf/proto"
 import fmt "fmt"
 import math "math"
 import common "github.com/hyperledger/fabric/protos/common"
+import common3 "github.com/hyperledger/fabric/protos/common"
+import google_protobuf "github.com/golang/protobuf/ptypes/timestamp"
 
 import (
 	context "golang.org/x/net/context"
@@ -51,6 +68,86 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
+type BroadcastError_Class int32
+
+const (
+	BroadcastError_UNKNOWN          BroadcastError_Class = 0
+	BroadcastError_MALFORMED        BroadcastError_Class = 1
+	BroadcastError_REJECTED         BroadcastError_Class = 2
+	BroadcastError_NOT_FOUND        BroadcastError_Class = 3
+	BroadcastError_UNAVAILABLE      BroadcastError_Class = 4
+	BroadcastError_INTERNAL         BroadcastError_Class = 5
+	BroadcastError_QUOTA_EXCEEDED   BroadcastError_Class = 6
+	BroadcastError_CHANNEL_MISMATCH BroadcastError_Class = 7
+	BroadcastError_TYPE_NOT_ALLOWED BroadcastError_Class = 8
+	BroadcastError_INVALID_EPOCH    BroadcastError_Class = 9
+)
+
+var BroadcastError_Class_name = map[int32]string{
+	0: "UNKNOWN",
+	1: "MALFORMED",
+	2: "REJECTED",
+	3: "NOT_FOUND",
+	4: "UNAVAILABLE",
+	5: "INTERNAL",
+	6: "QUOTA_EXCEEDED",
+	7: "CHANNEL_MISMATCH",
+	8: "TYPE_NOT_ALLOWED",
+	9: "INVALID_EPOCH",
+}
+var BroadcastError_Class_value = map[string]int32{
+	"UNKNOWN":          0,
+	"MALFORMED":        1,
+	"REJECTED":         2,
+	"NOT_FOUND":        3,
+	"UNAVAILABLE":      4,
+	"INTERNAL":         5,
+	"QUOTA_EXCEEDED":   6,
+	"CHANNEL_MISMATCH": 7,
+	"TYPE_NOT_ALLOWED": 8,
+	"INVALID_EPOCH":    9,
+}
+
+func (x BroadcastError_Class) String() string {
+	return proto.EnumName(BroadcastError_Class_name, int32(x))
+}
+func (BroadcastError_Class) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{1, 0} }
+
+type BroadcastError_ChannelState int32
+
+const (
+	BroadcastError_STATE_UNKNOWN BroadcastError_ChannelState = 0
+	BroadcastError_ACTIVE        BroadcastError_ChannelState = 1
+	BroadcastError_ERRORED       BroadcastError_ChannelState = 2
+	BroadcastError_QUIESCED      BroadcastError_ChannelState = 3
+	BroadcastError_MAINTENANCE   BroadcastError_ChannelState = 4
+	BroadcastError_PAUSED        BroadcastError_ChannelState = 5
+)
+
+var BroadcastError_ChannelState_name = map[int32]string{
+	0: "STATE_UNKNOWN",
+	1: "ACTIVE",
+	2: "ERRORED",
+	3: "QUIESCED",
+	4: "MAINTENANCE",
+	5: "PAUSED",
+}
+var BroadcastError_ChannelState_value = map[string]int32{
+	"STATE_UNKNOWN": 0,
+	"ACTIVE":        1,
+	"ERRORED":       2,
+	"QUIESCED":      3,
+	"MAINTENANCE":   4,
+	"PAUSED":        5,
+}
+
+func (x BroadcastError_ChannelState) String() string {
+	return proto.EnumName(BroadcastError_ChannelState_name, int32(x))
+}
+func (BroadcastError_ChannelState) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{1, 1}
+}
+
 type SeekInfo_SeekBehavior int32
 
 const (
@@ -70,10 +167,44 @@ var SeekInfo_SeekBehavior_value = map[string]int32{
 func (x SeekInfo_SeekBehavior) String() string {
 	return proto.EnumName(SeekInfo_SeekBehavior_name, int32(x))
 }
-func (SeekInfo_SeekBehavior) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{5, 0} }
+func (SeekInfo_SeekBehavior) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{18, 0} }
+
+// SeekContentType indicates what portion of the blocks is delivered.  HEADER_WITH_SIG delivers the header and
+// the metadata of the blocks, including the signatures of the orderers, but not the data, which is enough for
+// light clients to follow the hash chain
+type SeekInfo_SeekContentType int32
+
+const (
+	SeekInfo_BLOCK           SeekInfo_SeekContentType = 0
+	SeekInfo_HEADER_WITH_SIG SeekInfo_SeekContentType = 1
+)
+
+var SeekInfo_SeekContentType_name = map[int32]string{
+	0: "BLOCK",
+	1: "HEADER_WITH_SIG",
+}
+var SeekInfo_SeekContentType_value = map[string]int32{
+	"BLOCK":           0,
+	"HEADER_WITH_SIG": 1,
+}
+
+func (x SeekInfo_SeekContentType) String() string {
+	return proto.EnumName(SeekInfo_SeekContentType_name, int32(x))
+}
+func (SeekInfo_SeekContentType) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{18, 1}
+}
 
 type BroadcastResponse struct {
 	Status common.Status `protobuf:"varint,1,opt,name=status,enum=common.Status" json:"status,omitempty"`
+	// Set only when the broadcast stream was opened in the commit acknowledgement mode, on the second
+	// response for an envelope, once the envelope has been included in a block written by the orderer
+	Commit *CommitNotification `protobuf:"bytes,2,opt,name=commit" json:"commit,omitempty"`
+	// The trace ID of the envelope, supplied by the client or assigned by the orderer, set on the response
+	// sent once the envelope is enqueued, empty if the envelope has none
+	TraceId string `protobuf:"bytes,3,opt,name=trace_id,json=traceId" json:"trace_id,omitempty"`
+	// Set when the envelope was rejected, along with a status other than SUCCESS
+	Error *BroadcastError `protobuf:"bytes,4,opt,name=error" json:"error,omitempty"`
 }
 
 func (m *BroadcastResponse) Reset()                    { *m = BroadcastResponse{} }
@@ -88,13 +219,551 @@ func (m *BroadcastResponse) GetStatus() common.Status {
 	return common.Status_UNKNOWN
 }
 
+func (m *BroadcastResponse) GetCommit() *CommitNotification {
+	if m != nil {
+		return m.Commit
+	}
+	return nil
+}
+
+func (m *BroadcastResponse) GetTraceId() string {
+	if m != nil {
+		return m.TraceId
+	}
+	return ""
+}
+
+func (m *BroadcastResponse) GetError() *BroadcastError {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+// BroadcastError details why an envelope was rejected, so that clients can tell the failures worth
+// retrying from the others, and when to retry them
+type BroadcastError struct {
+	Class        BroadcastError_Class        `protobuf:"varint,1,opt,name=class,enum=orderer.BroadcastError_Class" json:"class,omitempty"`
+	Message      string                      `protobuf:"bytes,2,opt,name=message" json:"message,omitempty"`
+	RetryAfterMs uint64                      `protobuf:"varint,3,opt,name=retry_after_ms,json=retryAfterMs" json:"retry_after_ms,omitempty"`
+	ChannelState BroadcastError_ChannelState `protobuf:"varint,4,opt,name=channel_state,json=channelState,enum=orderer.BroadcastError_ChannelState" json:"channel_state,omitempty"`
+}
+
+func (m *BroadcastError) Reset()                    { *m = BroadcastError{} }
+func (m *BroadcastError) String() string            { return proto.CompactTextString(m) }
+func (*BroadcastError) ProtoMessage()               {}
+func (*BroadcastError) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+func (m *BroadcastError) GetClass() BroadcastError_Class {
+	if m != nil {
+		return m.Class
+	}
+	return BroadcastError_UNKNOWN
+}
+
+func (m *BroadcastError) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *BroadcastError) GetRetryAfterMs() uint64 {
+	if m != nil {
+		return m.RetryAfterMs
+	}
+	return 0
+}
+
+func (m *BroadcastError) GetChannelState() BroadcastError_ChannelState {
+	if m != nil {
+		return m.ChannelState
+	}
+	return BroadcastError_STATE_UNKNOWN
+}
+
+// ErrorDetail is attached to the gRPC status with which the Broadcast and Deliver streams end when
+// an envelope fails, after the response carrying the failure has been sent. The gRPC code of the
+// status is derived from the status and class of the failure
+type ErrorDetail struct {
+	Domain       string                      `protobuf:"bytes,1,opt,name=domain" json:"domain,omitempty"`
+	ChannelId    string                      `protobuf:"bytes,2,opt,name=channel_id,json=channelId" json:"channel_id,omitempty"`
+	Status       common.Status               `protobuf:"varint,3,opt,name=status,enum=common.Status" json:"status,omitempty"`
+	Class        BroadcastError_Class        `protobuf:"varint,4,opt,name=class,enum=orderer.BroadcastError_Class" json:"class,omitempty"`
+	ChannelState BroadcastError_ChannelState `protobuf:"varint,5,opt,name=channel_state,json=channelState,enum=orderer.BroadcastError_ChannelState" json:"channel_state,omitempty"`
+	Retriable    bool                        `protobuf:"varint,6,opt,name=retriable" json:"retriable,omitempty"`
+	RetryAfterMs uint64                      `protobuf:"varint,7,opt,name=retry_after_ms,json=retryAfterMs" json:"retry_after_ms,omitempty"`
+}
+
+func (m *ErrorDetail) Reset()                    { *m = ErrorDetail{} }
+func (m *ErrorDetail) String() string            { return proto.CompactTextString(m) }
+func (*ErrorDetail) ProtoMessage()               {}
+func (*ErrorDetail) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+func (m *ErrorDetail) GetDomain() string {
+	if m != nil {
+		return m.Domain
+	}
+	return ""
+}
+
+func (m *ErrorDetail) GetChannelId() string {
+	if m != nil {
+		return m.ChannelId
+	}
+	return ""
+}
+
+func (m *ErrorDetail) GetStatus() common.Status {
+	if m != nil {
+		return m.Status
+	}
+	return common.Status_UNKNOWN
+}
+
+func (m *ErrorDetail) GetClass() BroadcastError_Class {
+	if m != nil {
+		return m.Class
+	}
+	return BroadcastError_UNKNOWN
+}
+
+func (m *ErrorDetail) GetChannelState() BroadcastError_ChannelState {
+	if m != nil {
+		return m.ChannelState
+	}
+	return BroadcastError_STATE_UNKNOWN
+}
+
+func (m *ErrorDetail) GetRetriable() bool {
+	if m != nil {
+		return m.Retriable
+	}
+	return false
+}
+
+func (m *ErrorDetail) GetRetryAfterMs() uint64 {
+	if m != nil {
+		return m.RetryAfterMs
+	}
+	return 0
+}
+
+// CommitNotification identifies the position of a broadcasted envelope in the chain
+type CommitNotification struct {
+	TxId        string `protobuf:"bytes,1,opt,name=tx_id,json=txId" json:"tx_id,omitempty"`
+	BlockNumber uint64 `protobuf:"varint,2,opt,name=block_number,json=blockNumber" json:"block_number,omitempty"`
+	TxIndex     uint64 `protobuf:"varint,3,opt,name=tx_index,json=txIndex" json:"tx_index,omitempty"`
+}
+
+func (m *CommitNotification) Reset()                    { *m = CommitNotification{} }
+func (m *CommitNotification) String() string            { return proto.CompactTextString(m) }
+func (*CommitNotification) ProtoMessage()               {}
+func (*CommitNotification) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
+func (m *CommitNotification) GetTxId() string {
+	if m != nil {
+		return m.TxId
+	}
+	return ""
+}
+
+func (m *CommitNotification) GetBlockNumber() uint64 {
+	if m != nil {
+		return m.BlockNumber
+	}
+	return 0
+}
+
+func (m *CommitNotification) GetTxIndex() uint64 {
+	if m != nil {
+		return m.TxIndex
+	}
+	return 0
+}
+
+// ConfigNotification tells the subscribers that the config of a channel changed, so that they refresh
+// the config they cache by delivering the config block
+type ConfigNotification struct {
+	ChannelId   string `protobuf:"bytes,1,opt,name=channel_id,json=channelId" json:"channel_id,omitempty"`
+	BlockNumber uint64 `protobuf:"varint,2,opt,name=block_number,json=blockNumber" json:"block_number,omitempty"`
+	Sequence    uint64 `protobuf:"varint,3,opt,name=sequence" json:"sequence,omitempty"`
+}
+
+func (m *ConfigNotification) Reset()                    { *m = ConfigNotification{} }
+func (m *ConfigNotification) String() string            { return proto.CompactTextString(m) }
+func (*ConfigNotification) ProtoMessage()               {}
+func (*ConfigNotification) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+
+func (m *ConfigNotification) GetChannelId() string {
+	if m != nil {
+		return m.ChannelId
+	}
+	return ""
+}
+
+func (m *ConfigNotification) GetBlockNumber() uint64 {
+	if m != nil {
+		return m.BlockNumber
+	}
+	return 0
+}
+
+func (m *ConfigNotification) GetSequence() uint64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
+// HeightSubscription is the payload data of the envelope subscribing to the heights of channels. The signature
+// of the envelope must satisfy the Readers policy of each of the channels
+type HeightSubscription struct {
+	ChannelIds []string `protobuf:"bytes,1,rep,name=channel_ids,json=channelIds" json:"channel_ids,omitempty"`
+}
+
+func (m *HeightSubscription) Reset()                    { *m = HeightSubscription{} }
+func (m *HeightSubscription) String() string            { return proto.CompactTextString(m) }
+func (*HeightSubscription) ProtoMessage()               {}
+func (*HeightSubscription) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+
+func (m *HeightSubscription) GetChannelIds() []string {
+	if m != nil {
+		return m.ChannelIds
+	}
+	return nil
+}
+
+// HeightNotification tells the subscribers that a block was written to a channel
+type HeightNotification struct {
+	ChannelId  string `protobuf:"bytes,1,opt,name=channel_id,json=channelId" json:"channel_id,omitempty"`
+	Height     uint64 `protobuf:"varint,2,opt,name=height" json:"height,omitempty"`
+	HeaderHash []byte `protobuf:"bytes,3,opt,name=header_hash,json=headerHash,proto3" json:"header_hash,omitempty"`
+}
+
+func (m *HeightNotification) Reset()                    { *m = HeightNotification{} }
+func (m *HeightNotification) String() string            { return proto.CompactTextString(m) }
+func (*HeightNotification) ProtoMessage()               {}
+func (*HeightNotification) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+
+func (m *HeightNotification) GetChannelId() string {
+	if m != nil {
+		return m.ChannelId
+	}
+	return ""
+}
+
+func (m *HeightNotification) GetHeight() uint64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *HeightNotification) GetHeaderHash() []byte {
+	if m != nil {
+		return m.HeaderHash
+	}
+	return nil
+}
+
+// Usage accounts the ordering resources consumed by a channel or a consortium
+type Usage struct {
+	Transactions uint64 `protobuf:"varint,1,opt,name=transactions" json:"transactions,omitempty"`
+	Bytes        uint64 `protobuf:"varint,2,opt,name=bytes" json:"bytes,omitempty"`
+	Blocks       uint64 `protobuf:"varint,3,opt,name=blocks" json:"blocks,omitempty"`
+}
+
+func (m *Usage) Reset()                    { *m = Usage{} }
+func (m *Usage) String() string            { return proto.CompactTextString(m) }
+func (*Usage) ProtoMessage()               {}
+func (*Usage) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+
+func (m *Usage) GetTransactions() uint64 {
+	if m != nil {
+		return m.Transactions
+	}
+	return 0
+}
+
+func (m *Usage) GetBytes() uint64 {
+	if m != nil {
+		return m.Bytes
+	}
+	return 0
+}
+
+func (m *Usage) GetBlocks() uint64 {
+	if m != nil {
+		return m.Blocks
+	}
+	return 0
+}
+
+// Quota caps the envelopes a channel or a consortium may broadcast within an accounting period,
+// a zero field setting no cap
+type Quota struct {
+	Transactions uint64 `protobuf:"varint,1,opt,name=transactions" json:"transactions,omitempty"`
+	Bytes        uint64 `protobuf:"varint,2,opt,name=bytes" json:"bytes,omitempty"`
+}
+
+func (m *Quota) Reset()                    { *m = Quota{} }
+func (m *Quota) String() string            { return proto.CompactTextString(m) }
+func (*Quota) ProtoMessage()               {}
+func (*Quota) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+
+func (m *Quota) GetTransactions() uint64 {
+	if m != nil {
+		return m.Transactions
+	}
+	return 0
+}
+
+func (m *Quota) GetBytes() uint64 {
+	if m != nil {
+		return m.Bytes
+	}
+	return 0
+}
+
+// TenantUsage is the usage of a channel, or of all the channels of a consortium
+type TenantUsage struct {
+	ChannelId  string `protobuf:"bytes,1,opt,name=channel_id,json=channelId" json:"channel_id,omitempty"`
+	Consortium string `protobuf:"bytes,2,opt,name=consortium" json:"consortium,omitempty"`
+	Total      *Usage `protobuf:"bytes,3,opt,name=total" json:"total,omitempty"`
+	Period     *Usage `protobuf:"bytes,4,opt,name=period" json:"period,omitempty"`
+	Admitted   *Usage `protobuf:"bytes,5,opt,name=admitted" json:"admitted,omitempty"`
+	Quota      *Quota `protobuf:"bytes,6,opt,name=quota" json:"quota,omitempty"`
+}
+
+func (m *TenantUsage) Reset()                    { *m = TenantUsage{} }
+func (m *TenantUsage) String() string            { return proto.CompactTextString(m) }
+func (*TenantUsage) ProtoMessage()               {}
+func (*TenantUsage) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+
+func (m *TenantUsage) GetChannelId() string {
+	if m != nil {
+		return m.ChannelId
+	}
+	return ""
+}
+
+func (m *TenantUsage) GetConsortium() string {
+	if m != nil {
+		return m.Consortium
+	}
+	return ""
+}
+
+func (m *TenantUsage) GetTotal() *Usage {
+	if m != nil {
+		return m.Total
+	}
+	return nil
+}
+
+func (m *TenantUsage) GetPeriod() *Usage {
+	if m != nil {
+		return m.Period
+	}
+	return nil
+}
+
+func (m *TenantUsage) GetAdmitted() *Usage {
+	if m != nil {
+		return m.Admitted
+	}
+	return nil
+}
+
+func (m *TenantUsage) GetQuota() *Quota {
+	if m != nil {
+		return m.Quota
+	}
+	return nil
+}
+
+// UsageReport is the usage of the channels and of the consortiums of an orderer
+type UsageReport struct {
+	Since       *google_protobuf.Timestamp `protobuf:"bytes,1,opt,name=since" json:"since,omitempty"`
+	PeriodStart *google_protobuf.Timestamp `protobuf:"bytes,2,opt,name=period_start,json=periodStart" json:"period_start,omitempty"`
+	PeriodEnd   *google_protobuf.Timestamp `protobuf:"bytes,3,opt,name=period_end,json=periodEnd" json:"period_end,omitempty"`
+	Channels    []*TenantUsage             `protobuf:"bytes,4,rep,name=channels" json:"channels,omitempty"`
+	Consortiums []*TenantUsage             `protobuf:"bytes,5,rep,name=consortiums" json:"consortiums,omitempty"`
+}
+
+func (m *UsageReport) Reset()                    { *m = UsageReport{} }
+func (m *UsageReport) String() string            { return proto.CompactTextString(m) }
+func (*UsageReport) ProtoMessage()               {}
+func (*UsageReport) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+
+func (m *UsageReport) GetSince() *google_protobuf.Timestamp {
+	if m != nil {
+		return m.Since
+	}
+	return nil
+}
+
+func (m *UsageReport) GetPeriodStart() *google_protobuf.Timestamp {
+	if m != nil {
+		return m.PeriodStart
+	}
+	return nil
+}
+
+func (m *UsageReport) GetPeriodEnd() *google_protobuf.Timestamp {
+	if m != nil {
+		return m.PeriodEnd
+	}
+	return nil
+}
+
+func (m *UsageReport) GetChannels() []*TenantUsage {
+	if m != nil {
+		return m.Channels
+	}
+	return nil
+}
+
+func (m *UsageReport) GetConsortiums() []*TenantUsage {
+	if m != nil {
+		return m.Consortiums
+	}
+	return nil
+}
+
+// ConfigUpdateValidation is the outcome of the dry run of a CONFIG_UPDATE envelope, which the orderer processes
+// and filters as if it was broadcasted, without ordering it
+type ConfigUpdateValidation struct {
+	ChannelId  string               `protobuf:"bytes,1,opt,name=channel_id,json=channelId" json:"channel_id,omitempty"`
+	NewChannel bool                 `protobuf:"varint,2,opt,name=new_channel,json=newChannel" json:"new_channel,omitempty"`
+	Valid      bool                 `protobuf:"varint,3,opt,name=valid" json:"valid,omitempty"`
+	Config     *common3.Config      `protobuf:"bytes,4,opt,name=config" json:"config,omitempty"`
+	Class      BroadcastError_Class `protobuf:"varint,5,opt,name=class,enum=orderer.BroadcastError_Class" json:"class,omitempty"`
+	Message    string               `protobuf:"bytes,6,opt,name=message" json:"message,omitempty"`
+}
+
+func (m *ConfigUpdateValidation) Reset()                    { *m = ConfigUpdateValidation{} }
+func (m *ConfigUpdateValidation) String() string            { return proto.CompactTextString(m) }
+func (*ConfigUpdateValidation) ProtoMessage()               {}
+func (*ConfigUpdateValidation) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+
+func (m *ConfigUpdateValidation) GetChannelId() string {
+	if m != nil {
+		return m.ChannelId
+	}
+	return ""
+}
+
+func (m *ConfigUpdateValidation) GetNewChannel() bool {
+	if m != nil {
+		return m.NewChannel
+	}
+	return false
+}
+
+func (m *ConfigUpdateValidation) GetValid() bool {
+	if m != nil {
+		return m.Valid
+	}
+	return false
+}
+
+func (m *ConfigUpdateValidation) GetConfig() *common3.Config {
+	if m != nil {
+		return m.Config
+	}
+	return nil
+}
+
+func (m *ConfigUpdateValidation) GetClass() BroadcastError_Class {
+	if m != nil {
+		return m.Class
+	}
+	return BroadcastError_UNKNOWN
+}
+
+func (m *ConfigUpdateValidation) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+// TraceMetadata is the encoded value of the Metadata message in the TRACE_IDS block metadata index
+type TraceMetadata struct {
+	TraceIds   []string `protobuf:"bytes,1,rep,name=trace_ids,json=traceIds" json:"trace_ids,omitempty"`
+	ReceivedAt []int64  `protobuf:"varint,2,rep,packed,name=received_at,json=receivedAt" json:"received_at,omitempty"`
+	// for ordering, in order, 0 when unknown
+	OrderedAt int64 `protobuf:"varint,3,opt,name=ordered_at,json=orderedAt" json:"ordered_at,omitempty"`
+}
+
+func (m *TraceMetadata) Reset()                    { *m = TraceMetadata{} }
+func (m *TraceMetadata) String() string            { return proto.CompactTextString(m) }
+func (*TraceMetadata) ProtoMessage()               {}
+func (*TraceMetadata) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+
+func (m *TraceMetadata) GetTraceIds() []string {
+	if m != nil {
+		return m.TraceIds
+	}
+	return nil
+}
+
+func (m *TraceMetadata) GetReceivedAt() []int64 {
+	if m != nil {
+		return m.ReceivedAt
+	}
+	return nil
+}
+
+func (m *TraceMetadata) GetOrderedAt() int64 {
+	if m != nil {
+		return m.OrderedAt
+	}
+	return 0
+}
+
+// TracePosition locates a traced envelope in the chain
+type TracePosition struct {
+	TraceId     string `protobuf:"bytes,1,opt,name=trace_id,json=traceId" json:"trace_id,omitempty"`
+	BlockNumber uint64 `protobuf:"varint,2,opt,name=block_number,json=blockNumber" json:"block_number,omitempty"`
+	TxIndex     uint64 `protobuf:"varint,3,opt,name=tx_index,json=txIndex" json:"tx_index,omitempty"`
+}
+
+func (m *TracePosition) Reset()                    { *m = TracePosition{} }
+func (m *TracePosition) String() string            { return proto.CompactTextString(m) }
+func (*TracePosition) ProtoMessage()               {}
+func (*TracePosition) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
+
+func (m *TracePosition) GetTraceId() string {
+	if m != nil {
+		return m.TraceId
+	}
+	return ""
+}
+
+func (m *TracePosition) GetBlockNumber() uint64 {
+	if m != nil {
+		return m.BlockNumber
+	}
+	return 0
+}
+
+func (m *TracePosition) GetTxIndex() uint64 {
+	if m != nil {
+		return m.TxIndex
+	}
+	return 0
+}
+
 type SeekNewest struct {
 }
 
 func (m *SeekNewest) Reset()                    { *m = SeekNewest{} }
 func (m *SeekNewest) String() string            { return proto.CompactTextString(m) }
 func (*SeekNewest) ProtoMessage()               {}
-func (*SeekNewest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+func (*SeekNewest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
 
 type SeekOldest struct {
 }
@@ -102,7 +771,7 @@ type SeekOldest struct {
 func (m *SeekOldest) Reset()                    { *m = SeekOldest{} }
 func (m *SeekOldest) String() string            { return proto.CompactTextString(m) }
 func (*SeekOldest) ProtoMessage()               {}
-func (*SeekOldest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+func (*SeekOldest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
 
 type SeekSpecified struct {
 	Number uint64 `protobuf:"varint,1,opt,name=number" json:"number,omitempty"`
@@ -111,7 +780,7 @@ type SeekSpecified struct {
 func (m *SeekSpecified) Reset()                    { *m = SeekSpecified{} }
 func (m *SeekSpecified) String() string            { return proto.CompactTextString(m) }
 func (*SeekSpecified) ProtoMessage()               {}
-func (*SeekSpecified) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+func (*SeekSpecified) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
 
 func (m *SeekSpecified) GetNumber() uint64 {
 	if m != nil {
@@ -131,7 +800,7 @@ type SeekPosition struct {
 func (m *SeekPosition) Reset()                    { *m = SeekPosition{} }
 func (m *SeekPosition) String() string            { return proto.CompactTextString(m) }
 func (*SeekPosition) ProtoMessage()               {}
-func (*SeekPosition) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+func (*SeekPosition) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
 
 type isSeekPosition_Type interface {
 	isSeekPosition_Type()
@@ -281,15 +950,16 @@ func _SeekPosition_OneofSizer(msg proto.Message) (n int) {
 // as they are created, behavior should be set to BLOCK_UNTIL_READY and the stop should be set to
 // specified with a number of MAX_UINT64
 type SeekInfo struct {
-	Start    *SeekPosition         `protobuf:"bytes,1,opt,name=start" json:"start,omitempty"`
-	Stop     *SeekPosition         `protobuf:"bytes,2,opt,name=stop" json:"stop,omitempty"`
-	Behavior SeekInfo_SeekBehavior `protobuf:"varint,3,opt,name=behavior,enum=orderer.SeekInfo_SeekBehavior" json:"behavior,omitempty"`
+	Start       *SeekPosition            `protobuf:"bytes,1,opt,name=start" json:"start,omitempty"`
+	Stop        *SeekPosition            `protobuf:"bytes,2,opt,name=stop" json:"stop,omitempty"`
+	Behavior    SeekInfo_SeekBehavior    `protobuf:"varint,3,opt,name=behavior,enum=orderer.SeekInfo_SeekBehavior" json:"behavior,omitempty"`
+	ContentType SeekInfo_SeekContentType `protobuf:"varint,4,opt,name=content_type,json=contentType,enum=orderer.SeekInfo_SeekContentType" json:"content_type,omitempty"`
 }
 
 func (m *SeekInfo) Reset()                    { *m = SeekInfo{} }
 func (m *SeekInfo) String() string            { return proto.CompactTextString(m) }
 func (*SeekInfo) ProtoMessage()               {}
-func (*SeekInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+func (*SeekInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
 
 func (m *SeekInfo) GetStart() *SeekPosition {
 	if m != nil {
@@ -312,6 +982,13 @@ func (m *SeekInfo) GetBehavior() SeekInfo_SeekBehavior {
 	return SeekInfo_BLOCK_UNTIL_READY
 }
 
+func (m *SeekInfo) GetContentType() SeekInfo_SeekContentType {
+	if m != nil {
+		return m.ContentType
+	}
+	return SeekInfo_BLOCK
+}
+
 type DeliverResponse struct {
 	// Types that are valid to be assigned to Type:
 	//	*DeliverResponse_Status
@@ -322,7 +999,7 @@ type DeliverResponse struct {
 func (m *DeliverResponse) Reset()                    { *m = DeliverResponse{} }
 func (m *DeliverResponse) String() string            { return proto.CompactTextString(m) }
 func (*DeliverResponse) ProtoMessage()               {}
-func (*DeliverResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+func (*DeliverResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
 
 type isDeliverResponse_Type interface {
 	isDeliverResponse_Type()
@@ -428,15 +1105,58 @@ func _DeliverResponse_OneofSizer(msg proto.Message) (n int) {
 	return n
 }
 
+// ChannelDeliverResponse is a response to the seek requests of the channels multiplexed on a MultiChannelDeliver
+// stream, labeled with the channel it is for
+type ChannelDeliverResponse struct {
+	ChannelId string           `protobuf:"bytes,1,opt,name=channel_id,json=channelId" json:"channel_id,omitempty"`
+	Response  *DeliverResponse `protobuf:"bytes,2,opt,name=response" json:"response,omitempty"`
+}
+
+func (m *ChannelDeliverResponse) Reset()                    { *m = ChannelDeliverResponse{} }
+func (m *ChannelDeliverResponse) String() string            { return proto.CompactTextString(m) }
+func (*ChannelDeliverResponse) ProtoMessage()               {}
+func (*ChannelDeliverResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
+
+func (m *ChannelDeliverResponse) GetChannelId() string {
+	if m != nil {
+		return m.ChannelId
+	}
+	return ""
+}
+
+func (m *ChannelDeliverResponse) GetResponse() *DeliverResponse {
+	if m != nil {
+		return m.Response
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*BroadcastResponse)(nil), "orderer.BroadcastResponse")
+	proto.RegisterType((*BroadcastError)(nil), "orderer.BroadcastError")
+	proto.RegisterType((*ErrorDetail)(nil), "orderer.ErrorDetail")
+	proto.RegisterType((*CommitNotification)(nil), "orderer.CommitNotification")
+	proto.RegisterType((*ConfigNotification)(nil), "orderer.ConfigNotification")
+	proto.RegisterType((*HeightSubscription)(nil), "orderer.HeightSubscription")
+	proto.RegisterType((*HeightNotification)(nil), "orderer.HeightNotification")
+	proto.RegisterType((*Usage)(nil), "orderer.Usage")
+	proto.RegisterType((*Quota)(nil), "orderer.Quota")
+	proto.RegisterType((*TenantUsage)(nil), "orderer.TenantUsage")
+	proto.RegisterType((*UsageReport)(nil), "orderer.UsageReport")
+	proto.RegisterType((*ConfigUpdateValidation)(nil), "orderer.ConfigUpdateValidation")
+	proto.RegisterType((*TraceMetadata)(nil), "orderer.TraceMetadata")
+	proto.RegisterType((*TracePosition)(nil), "orderer.TracePosition")
 	proto.RegisterType((*SeekNewest)(nil), "orderer.SeekNewest")
 	proto.RegisterType((*SeekOldest)(nil), "orderer.SeekOldest")
 	proto.RegisterType((*SeekSpecified)(nil), "orderer.SeekSpecified")
 	proto.RegisterType((*SeekPosition)(nil), "orderer.SeekPosition")
 	proto.RegisterType((*SeekInfo)(nil), "orderer.SeekInfo")
 	proto.RegisterType((*DeliverResponse)(nil), "orderer.DeliverResponse")
+	proto.RegisterType((*ChannelDeliverResponse)(nil), "orderer.ChannelDeliverResponse")
+	proto.RegisterEnum("orderer.BroadcastError_Class", BroadcastError_Class_name, BroadcastError_Class_value)
+	proto.RegisterEnum("orderer.BroadcastError_ChannelState", BroadcastError_ChannelState_name, BroadcastError_ChannelState_value)
 	proto.RegisterEnum("orderer.SeekInfo_SeekBehavior", SeekInfo_SeekBehavior_name, SeekInfo_SeekBehavior_value)
+	proto.RegisterEnum("orderer.SeekInfo_SeekContentType", SeekInfo_SeekContentType_name, SeekInfo_SeekContentType_value)
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -612,39 +1332,306 @@ var _AtomicBroadcast_serviceDesc = grpc.ServiceDesc{
 	Metadata: "orderer/ab.proto",
 }
 
+// Client API for MultiChannelDeliver service
+
+type MultiChannelDeliverClient interface {
+	// deliver receives Envelopes of type DELIVER_SEEK_INFO like AtomicBroadcast.Deliver, for any of the channels the
+	// client is authorized to read, and serves the requests for different channels concurrently on the stream. Each
+	// reply is labeled with the channel of its request; a failed request ends with its status, not the stream
+	Deliver(ctx context.Context, opts ...grpc.CallOption) (MultiChannelDeliver_DeliverClient, error)
+}
+
+type multiChannelDeliverClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewMultiChannelDeliverClient(cc *grpc.ClientConn) MultiChannelDeliverClient {
+	return &multiChannelDeliverClient{cc}
+}
+
+func (c *multiChannelDeliverClient) Deliver(ctx context.Context, opts ...grpc.CallOption) (MultiChannelDeliver_DeliverClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_MultiChannelDeliver_serviceDesc.Streams[0], c.cc, "/orderer.MultiChannelDeliver/Deliver", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &multiChannelDeliverDeliverClient{stream}
+	return x, nil
+}
+
+type MultiChannelDeliver_DeliverClient interface {
+	Send(*common.Envelope) error
+	Recv() (*ChannelDeliverResponse, error)
+	grpc.ClientStream
+}
+
+type multiChannelDeliverDeliverClient struct {
+	grpc.ClientStream
+}
+
+func (x *multiChannelDeliverDeliverClient) Send(m *common.Envelope) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *multiChannelDeliverDeliverClient) Recv() (*ChannelDeliverResponse, error) {
+	m := new(ChannelDeliverResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Server API for MultiChannelDeliver service
+
+type MultiChannelDeliverServer interface {
+	// deliver receives Envelopes of type DELIVER_SEEK_INFO like AtomicBroadcast.Deliver, for any of the channels the
+	// client is authorized to read, and serves the requests for different channels concurrently on the stream. Each
+	// reply is labeled with the channel of its request; a failed request ends with its status, not the stream
+	Deliver(MultiChannelDeliver_DeliverServer) error
+}
+
+func RegisterMultiChannelDeliverServer(s *grpc.Server, srv MultiChannelDeliverServer) {
+	s.RegisterService(&_MultiChannelDeliver_serviceDesc, srv)
+}
+
+func _MultiChannelDeliver_Deliver_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(MultiChannelDeliverServer).Deliver(&multiChannelDeliverDeliverServer{stream})
+}
+
+type MultiChannelDeliver_DeliverServer interface {
+	Send(*ChannelDeliverResponse) error
+	Recv() (*common.Envelope, error)
+	grpc.ServerStream
+}
+
+type multiChannelDeliverDeliverServer struct {
+	grpc.ServerStream
+}
+
+func (x *multiChannelDeliverDeliverServer) Send(m *ChannelDeliverResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *multiChannelDeliverDeliverServer) Recv() (*common.Envelope, error) {
+	m := new(common.Envelope)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+var _MultiChannelDeliver_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "orderer.MultiChannelDeliver",
+	HandlerType: (*MultiChannelDeliverServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Deliver",
+			Handler:       _MultiChannelDeliver_Deliver_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "orderer/ab.proto",
+}
+
+// Client API for LedgerHeights service
+
+type LedgerHeightsClient interface {
+	// subscribe requires an Envelope with Payload data as a marshaled HeightSubscription, then sends the current height
+	// of each of the channels followed by a notification for each block written to them
+	Subscribe(ctx context.Context, in *common.Envelope, opts ...grpc.CallOption) (LedgerHeights_SubscribeClient, error)
+}
+
+type ledgerHeightsClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewLedgerHeightsClient(cc *grpc.ClientConn) LedgerHeightsClient {
+	return &ledgerHeightsClient{cc}
+}
+
+func (c *ledgerHeightsClient) Subscribe(ctx context.Context, in *common.Envelope, opts ...grpc.CallOption) (LedgerHeights_SubscribeClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_LedgerHeights_serviceDesc.Streams[0], c.cc, "/orderer.LedgerHeights/Subscribe", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &ledgerHeightsSubscribeClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type LedgerHeights_SubscribeClient interface {
+	Recv() (*HeightNotification, error)
+	grpc.ClientStream
+}
+
+type ledgerHeightsSubscribeClient struct {
+	grpc.ClientStream
+}
+
+func (x *ledgerHeightsSubscribeClient) Recv() (*HeightNotification, error) {
+	m := new(HeightNotification)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Server API for LedgerHeights service
+
+type LedgerHeightsServer interface {
+	// subscribe requires an Envelope with Payload data as a marshaled HeightSubscription, then sends the current height
+	// of each of the channels followed by a notification for each block written to them
+	Subscribe(*common.Envelope, LedgerHeights_SubscribeServer) error
+}
+
+func RegisterLedgerHeightsServer(s *grpc.Server, srv LedgerHeightsServer) {
+	s.RegisterService(&_LedgerHeights_serviceDesc, srv)
+}
+
+func _LedgerHeights_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(common.Envelope)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LedgerHeightsServer).Subscribe(m, &ledgerHeightsSubscribeServer{stream})
+}
+
+type LedgerHeights_SubscribeServer interface {
+	Send(*HeightNotification) error
+	grpc.ServerStream
+}
+
+type ledgerHeightsSubscribeServer struct {
+	grpc.ServerStream
+}
+
+func (x *ledgerHeightsSubscribeServer) Send(m *HeightNotification) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _LedgerHeights_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "orderer.LedgerHeights",
+	HandlerType: (*LedgerHeightsServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Subscribe",
+			Handler:       _LedgerHeights_Subscribe_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "orderer/ab.proto",
+}
+
 func init() { proto.RegisterFile("orderer/ab.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 492 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x7c, 0x93, 0xdb, 0x6e, 0xd3, 0x4c,
-	0x10, 0xc7, 0xe3, 0xef, 0x4b, 0xd3, 0x76, 0x68, 0xd3, 0x74, 0xab, 0x56, 0x51, 0x2e, 0x10, 0xb2,
-	0x54, 0x08, 0x02, 0x6c, 0x14, 0x24, 0x2e, 0x28, 0x12, 0x8a, 0x69, 0xab, 0x44, 0x44, 0x09, 0x72,
-	0xd2, 0x0b, 0xb8, 0x89, 0x7c, 0x98, 0x24, 0xa6, 0x8e, 0xd7, 0xda, 0xdd, 0x04, 0xf5, 0x29, 0x78,
-	0x11, 0x1e, 0x89, 0x87, 0x41, 0x7b, 0xb0, 0x43, 0xa0, 0x70, 0x65, 0xcf, 0xcc, 0xef, 0x3f, 0x27,
-	0xcd, 0x42, 0x83, 0xb2, 0x18, 0x19, 0x32, 0x37, 0x08, 0x9d, 0x9c, 0x51, 0x41, 0xc9, 0xae, 0xf1,
-	0xb4, 0x4e, 0x22, 0xba, 0x5c, 0xd2, 0xcc, 0xd5, 0x1f, 0x1d, 0xb5, 0x2f, 0xe0, 0xd8, 0x63, 0x34,
-	0x88, 0xa3, 0x80, 0x0b, 0x1f, 0x79, 0x4e, 0x33, 0x8e, 0xe4, 0x31, 0xd4, 0xb8, 0x08, 0xc4, 0x8a,
-	0x37, 0xad, 0x47, 0x56, 0xbb, 0xde, 0xa9, 0x3b, 0x46, 0x33, 0x56, 0x5e, 0xdf, 0x44, 0xed, 0x03,
-	0x80, 0x31, 0xe2, 0xed, 0x10, 0xbf, 0x22, 0x17, 0x85, 0x35, 0x4a, 0x63, 0x69, 0x3d, 0x81, 0x43,
-	0x69, 0x8d, 0x73, 0x8c, 0x92, 0x59, 0x82, 0x31, 0x39, 0x83, 0x5a, 0xb6, 0x5a, 0x86, 0xc8, 0x54,
-	0xd2, 0xaa, 0x6f, 0x2c, 0xfb, 0xbb, 0x05, 0x07, 0x92, 0xfc, 0x48, 0x79, 0x22, 0x12, 0x9a, 0x91,
-	0x17, 0x50, 0xcb, 0x54, 0x46, 0x05, 0x3e, 0xe8, 0x9c, 0x38, 0x66, 0x02, 0x67, 0x53, 0xac, 0x57,
-	0xf1, 0x0d, 0x24, 0x71, 0xaa, 0x4a, 0x36, 0xff, 0xbb, 0x07, 0xd7, 0xdd, 0x48, 0x5c, 0x43, 0xe4,
-	0x35, 0xec, 0xf3, 0xa2, 0xa7, 0xe6, 0xff, 0x4a, 0x71, 0xb6, 0xa5, 0x28, 0x3b, 0xee, 0x55, 0xfc,
-	0x0d, 0xea, 0xd5, 0xa0, 0x3a, 0xb9, 0xcb, 0xd1, 0xfe, 0x61, 0xc1, 0x9e, 0xc4, 0xfa, 0xd9, 0x8c,
-	0x92, 0x67, 0xb0, 0xc3, 0x45, 0xc0, 0x8a, 0x4e, 0x4f, 0xb7, 0x12, 0x15, 0x03, 0xf9, 0x9a, 0x21,
-	0x4f, 0xa1, 0xca, 0x05, 0xcd, 0x4d, 0x9b, 0x7f, 0x61, 0x15, 0x42, 0xde, 0xc0, 0x5e, 0x88, 0x8b,
-	0x60, 0x9d, 0x50, 0xa6, 0x7a, 0xac, 0x77, 0x1e, 0x6e, 0xe1, 0xb2, 0xb8, 0xfa, 0xf1, 0x0c, 0xe5,
-	0x97, 0xbc, 0xfd, 0x56, 0xaf, 0xb3, 0x88, 0x90, 0x53, 0x38, 0xf6, 0x06, 0xa3, 0xf7, 0x1f, 0xa6,
-	0x37, 0xc3, 0x49, 0x7f, 0x30, 0xf5, 0xaf, 0xba, 0x97, 0x9f, 0x1a, 0x15, 0xe9, 0xbe, 0xee, 0xf6,
-	0x07, 0xd3, 0xfe, 0xf5, 0x74, 0x38, 0x9a, 0x18, 0xb7, 0x65, 0x7f, 0x81, 0xa3, 0x4b, 0x4c, 0x93,
-	0x35, 0xb2, 0xf2, 0x1a, 0xda, 0xff, 0xbe, 0x06, 0xb9, 0x5b, 0x1d, 0x27, 0xe7, 0xb0, 0x13, 0xa6,
-	0x34, 0xba, 0x35, 0x23, 0x1e, 0x16, 0xa0, 0x27, 0x9d, 0xbd, 0x8a, 0xaf, 0xa3, 0xc5, 0x2a, 0x3b,
-	0xdf, 0x2c, 0x38, 0xea, 0x0a, 0xba, 0x4c, 0xa2, 0xf2, 0x04, 0xc9, 0x3b, 0xd8, 0xdf, 0x18, 0x8d,
-	0x22, 0xc1, 0x55, 0xb6, 0xc6, 0x94, 0xe6, 0xd8, 0x6a, 0x95, 0x6b, 0xf8, 0xe3, 0x6a, 0xed, 0x4a,
-	0xdb, 0x7a, 0x69, 0x91, 0x0b, 0xd8, 0x35, 0x03, 0xdc, 0x23, 0x6f, 0x96, 0xf2, 0xdf, 0x86, 0xd4,
-	0x62, 0xef, 0x06, 0xce, 0x29, 0x9b, 0x3b, 0x8b, 0xbb, 0x1c, 0x59, 0x8a, 0xf1, 0x1c, 0x99, 0x33,
-	0x0b, 0x42, 0x96, 0x44, 0xfa, 0xb5, 0xf0, 0x42, 0xfe, 0xf9, 0xf9, 0x3c, 0x11, 0x8b, 0x55, 0x28,
-	0x0b, 0xb8, 0xbf, 0xd0, 0xae, 0xa6, 0x5d, 0x4d, 0xbb, 0x86, 0x0e, 0x6b, 0xca, 0x7e, 0xf5, 0x33,
-	0x00, 0x00, 0xff, 0xff, 0xee, 0xc5, 0xfc, 0x14, 0x9d, 0x03, 0x00, 0x00,
+	// 1605 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xa4, 0x57, 0xdd, 0x76, 0xe2, 0xc8,
+	0x11, 0x46, 0x06, 0xf1, 0x53, 0x60, 0x5b, 0xd3, 0x9e, 0x71, 0x88, 0x37, 0xbb, 0x33, 0xab, 0x33,
+	0x9b, 0x90, 0x9f, 0xc5, 0x13, 0x4f, 0xb2, 0xe7, 0xe4, 0xff, 0x08, 0xd0, 0x04, 0x65, 0x41, 0xcc,
+	0x34, 0xe0, 0x89, 0x73, 0xa3, 0x23, 0xa4, 0x36, 0x28, 0x0b, 0x12, 0x2b, 0x35, 0x1e, 0xfb, 0x29,
+	0xf2, 0x10, 0xb9, 0xcd, 0x45, 0x4e, 0x2e, 0xf3, 0x1e, 0x79, 0x84, 0xdc, 0xed, 0x43, 0xe4, 0xf4,
+	0x8f, 0x04, 0xd8, 0x8c, 0xbd, 0x3b, 0xb9, 0x82, 0xaa, 0xfa, 0xaa, 0xea, 0xeb, 0xea, 0xea, 0xea,
+	0x16, 0x68, 0x51, 0xec, 0x93, 0x98, 0xc4, 0xa7, 0xee, 0xa4, 0xb9, 0x8c, 0x23, 0x1a, 0xa1, 0x92,
+	0xd4, 0x9c, 0x1c, 0x79, 0xd1, 0x62, 0x11, 0x85, 0xa7, 0xe2, 0x47, 0x58, 0x4f, 0x9e, 0x64, 0xca,
+	0xf0, 0x32, 0x98, 0xd2, 0x6b, 0xa9, 0x7e, 0x3a, 0x8d, 0xa2, 0xe9, 0x9c, 0x9c, 0x72, 0x69, 0xb2,
+	0xba, 0x3c, 0xa5, 0xc1, 0x82, 0x24, 0xd4, 0x5d, 0x2c, 0x05, 0x40, 0xff, 0xb7, 0x02, 0x8f, 0x5a,
+	0x71, 0xe4, 0xfa, 0x9e, 0x9b, 0x50, 0x4c, 0x92, 0x65, 0x14, 0x26, 0x04, 0xfd, 0x10, 0x8a, 0x09,
+	0x75, 0xe9, 0x2a, 0xa9, 0x2b, 0xcf, 0x94, 0xc6, 0xc1, 0xd9, 0x41, 0x53, 0x26, 0x1b, 0x72, 0x2d,
+	0x96, 0x56, 0xf4, 0x12, 0x8a, 0xcc, 0x10, 0xd0, 0xfa, 0xde, 0x33, 0xa5, 0x51, 0x3d, 0xfb, 0xa8,
+	0x29, 0x49, 0x36, 0xdb, 0x5c, 0x6d, 0x47, 0x34, 0xb8, 0x0c, 0x3c, 0x97, 0x06, 0x51, 0x88, 0x25,
+	0x14, 0x7d, 0x1f, 0xca, 0x34, 0x76, 0x3d, 0xe2, 0x04, 0x7e, 0x3d, 0xff, 0x4c, 0x69, 0x54, 0x70,
+	0x89, 0xcb, 0x96, 0x8f, 0x3e, 0x07, 0x95, 0xc4, 0x71, 0x14, 0xd7, 0x0b, 0x3c, 0xdc, 0xf7, 0xb2,
+	0x70, 0x19, 0x45, 0x93, 0x99, 0xb1, 0x40, 0xe9, 0xdf, 0xe4, 0xe1, 0x60, 0xdb, 0x82, 0x5e, 0x82,
+	0xea, 0xcd, 0xdd, 0x24, 0x25, 0xfe, 0xf1, 0x7b, 0x22, 0x34, 0xdb, 0x0c, 0x84, 0x05, 0x16, 0xd5,
+	0xa1, 0xb4, 0x20, 0x49, 0xe2, 0x4e, 0x09, 0x5f, 0x47, 0x05, 0xa7, 0x22, 0x7a, 0x0e, 0x07, 0x31,
+	0xa1, 0xf1, 0x8d, 0xe3, 0x5e, 0x52, 0x12, 0x3b, 0x8b, 0x84, 0x33, 0x2e, 0xe0, 0x1a, 0xd7, 0x1a,
+	0x4c, 0xd9, 0x4f, 0x90, 0x05, 0xfb, 0xde, 0xcc, 0x0d, 0x43, 0x32, 0x77, 0x58, 0x61, 0x08, 0xa7,
+	0x7f, 0x70, 0xf6, 0xfc, 0xbd, 0xc9, 0x05, 0x98, 0x15, 0x93, 0xe0, 0x9a, 0xb7, 0x21, 0xe9, 0xff,
+	0x52, 0x40, 0xe5, 0xdc, 0x50, 0x15, 0x4a, 0x63, 0xfb, 0x4b, 0x7b, 0xf0, 0xd6, 0xd6, 0x72, 0x68,
+	0x1f, 0x2a, 0x7d, 0xa3, 0xf7, 0x6a, 0x80, 0xfb, 0x66, 0x47, 0x53, 0x50, 0x0d, 0xca, 0xd8, 0xfc,
+	0x93, 0xd9, 0x1e, 0x99, 0x1d, 0x6d, 0x8f, 0x19, 0xed, 0xc1, 0xc8, 0x79, 0x35, 0x18, 0xdb, 0x1d,
+	0x2d, 0x8f, 0x0e, 0xa1, 0x3a, 0xb6, 0x8d, 0x73, 0xc3, 0xea, 0x19, 0xad, 0x9e, 0xa9, 0x15, 0x18,
+	0xda, 0xb2, 0x47, 0x26, 0xb6, 0x8d, 0x9e, 0xa6, 0x22, 0x04, 0x07, 0x6f, 0xc6, 0x83, 0x91, 0xe1,
+	0x98, 0x7f, 0x6e, 0x9b, 0x66, 0xc7, 0xec, 0x68, 0x45, 0xf4, 0x18, 0xb4, 0x76, 0xd7, 0xb0, 0x6d,
+	0xb3, 0xe7, 0xf4, 0xad, 0x61, 0xdf, 0x18, 0xb5, 0xbb, 0x5a, 0x89, 0x69, 0x47, 0x17, 0xaf, 0x4d,
+	0x87, 0x05, 0x37, 0x7a, 0xbd, 0xc1, 0x5b, 0xb3, 0xa3, 0x95, 0xd1, 0x23, 0xd8, 0xb7, 0xec, 0x73,
+	0xa3, 0x67, 0x75, 0x1c, 0xf3, 0xf5, 0xa0, 0xdd, 0xd5, 0x2a, 0x3a, 0x81, 0xda, 0xe6, 0x92, 0x18,
+	0x64, 0x38, 0x32, 0x46, 0xa6, 0xb3, 0x5e, 0x00, 0x40, 0xd1, 0x68, 0x8f, 0xac, 0x73, 0x53, 0x53,
+	0xd8, 0xca, 0x4c, 0x8c, 0x07, 0x98, 0x93, 0xaf, 0x41, 0xf9, 0xcd, 0xd8, 0x32, 0x87, 0x6d, 0x53,
+	0x72, 0xef, 0x1b, 0x8c, 0xac, 0x6d, 0xd8, 0x6d, 0xc6, 0x1d, 0xa0, 0xf8, 0xda, 0x18, 0x0f, 0xcd,
+	0x8e, 0xa6, 0xea, 0xff, 0xdc, 0x83, 0x2a, 0x2f, 0x60, 0x87, 0x50, 0x37, 0x98, 0xa3, 0x63, 0x28,
+	0xfa, 0xd1, 0xc2, 0x0d, 0x42, 0xbe, 0xd9, 0x15, 0x2c, 0x25, 0xf4, 0x31, 0x40, 0xba, 0x1d, 0x81,
+	0x2f, 0x77, 0xb4, 0x22, 0x35, 0x96, 0xbf, 0xd1, 0xdc, 0xf9, 0x07, 0x9a, 0x5b, 0xb6, 0x52, 0xe1,
+	0x3b, 0xb4, 0xd2, 0x9d, 0x56, 0x50, 0x3f, 0xb4, 0x15, 0xd0, 0x0f, 0xa0, 0xc2, 0xba, 0x2c, 0x70,
+	0x27, 0x73, 0x52, 0x2f, 0x3e, 0x53, 0x1a, 0x65, 0xbc, 0x56, 0xec, 0xe8, 0xcc, 0xd2, 0xdd, 0xce,
+	0xd4, 0xa7, 0x80, 0xee, 0x9e, 0x44, 0x74, 0x04, 0x2a, 0xbd, 0x66, 0xb5, 0x11, 0x75, 0x2b, 0xd0,
+	0x6b, 0xcb, 0x47, 0x9f, 0x42, 0x6d, 0x32, 0x8f, 0xbc, 0xaf, 0x9c, 0x70, 0xb5, 0x98, 0x90, 0x98,
+	0xd7, 0xad, 0x80, 0xab, 0x5c, 0x67, 0x73, 0x15, 0x3f, 0xb9, 0xd7, 0x4e, 0x10, 0xfa, 0xe4, 0x5a,
+	0x9e, 0x83, 0x12, 0xbd, 0xb6, 0x98, 0xa8, 0xc7, 0x2c, 0x11, 0x1b, 0x3d, 0x5b, 0x89, 0xb6, 0x77,
+	0x42, 0xb9, 0xbd, 0x13, 0xdf, 0x22, 0xe5, 0x09, 0x94, 0x13, 0xf2, 0xf5, 0x8a, 0x84, 0x1e, 0x91,
+	0x29, 0x33, 0x59, 0xff, 0x25, 0xa0, 0x2e, 0x09, 0xa6, 0x33, 0x3a, 0x5c, 0x4d, 0x12, 0x2f, 0x0e,
+	0x96, 0x3c, 0xe7, 0x53, 0xa8, 0xae, 0x73, 0xb2, 0x39, 0x90, 0x6f, 0x54, 0x30, 0x64, 0x49, 0x13,
+	0x7d, 0x9e, 0xba, 0x7d, 0x17, 0xaa, 0xc7, 0x50, 0x9c, 0x71, 0x27, 0x49, 0x52, 0x4a, 0x2c, 0xdb,
+	0x8c, 0xb8, 0x3e, 0x89, 0x9d, 0x99, 0x9b, 0xcc, 0x38, 0xc5, 0x1a, 0x06, 0xa1, 0xea, 0xba, 0xc9,
+	0x4c, 0xbf, 0x00, 0x75, 0xcc, 0x47, 0x89, 0x0e, 0x35, 0x1a, 0xbb, 0x61, 0xe2, 0x7a, 0x2c, 0x9f,
+	0x18, 0x50, 0x05, 0xbc, 0xa5, 0x43, 0x8f, 0x41, 0x9d, 0xdc, 0x50, 0x92, 0xc8, 0x24, 0x42, 0x60,
+	0xb9, 0x79, 0x49, 0xd2, 0xe1, 0x23, 0x25, 0xdd, 0x00, 0xf5, 0xcd, 0x2a, 0xa2, 0xee, 0x87, 0x87,
+	0xd6, 0xbf, 0x51, 0xa0, 0x3a, 0x22, 0xa1, 0x1b, 0x52, 0x41, 0xf2, 0x81, 0x2a, 0x7c, 0x02, 0xe0,
+	0x45, 0x61, 0x12, 0xc5, 0x34, 0x58, 0x2d, 0xe4, 0xc9, 0xda, 0xd0, 0xa0, 0xe7, 0xa0, 0xd2, 0x88,
+	0xba, 0x73, 0x4e, 0xb4, 0x7a, 0x76, 0x90, 0x75, 0x3d, 0x8f, 0x8e, 0x85, 0x91, 0x1d, 0xc0, 0x25,
+	0x89, 0x83, 0xc8, 0xaf, 0x17, 0x76, 0xc2, 0xa4, 0x15, 0xfd, 0x04, 0xca, 0xae, 0xbf, 0x08, 0x28,
+	0x25, 0x7e, 0x5d, 0xdd, 0x89, 0xcc, 0xec, 0x2c, 0xf3, 0xd7, 0xac, 0x16, 0xf5, 0xe2, 0x2d, 0x20,
+	0xaf, 0x10, 0x16, 0x46, 0xfd, 0xef, 0x7b, 0x50, 0x15, 0x9e, 0x64, 0x19, 0xc5, 0x14, 0xbd, 0x00,
+	0x35, 0x09, 0x58, 0x6b, 0x29, 0xdc, 0xeb, 0xa4, 0x29, 0xae, 0xcb, 0x66, 0x7a, 0x5d, 0x36, 0x47,
+	0xe9, 0x75, 0x89, 0x05, 0x10, 0xfd, 0x0e, 0x6a, 0x82, 0x1d, 0x3b, 0xde, 0x71, 0x7a, 0xef, 0xdd,
+	0xe7, 0x58, 0x15, 0xf8, 0x21, 0x83, 0xa3, 0x5f, 0x01, 0x48, 0x77, 0x12, 0xfa, 0xf5, 0xfc, 0x83,
+	0xce, 0x15, 0x81, 0x36, 0x43, 0x1f, 0xbd, 0x80, 0xb2, 0xdc, 0x08, 0x36, 0x91, 0xf2, 0x8d, 0xea,
+	0xd9, 0xe3, 0x6c, 0x91, 0x1b, 0x5b, 0x88, 0x33, 0x14, 0xfa, 0x02, 0xaa, 0xeb, 0xbd, 0x49, 0xea,
+	0xea, 0x3d, 0x4e, 0x9b, 0x40, 0xfd, 0xbf, 0x0a, 0x1c, 0x8b, 0xc3, 0x3c, 0x5e, 0xfa, 0x2e, 0x25,
+	0xe7, 0xee, 0x3c, 0xf0, 0xbf, 0xd5, 0x29, 0x79, 0x0a, 0xd5, 0x90, 0xbc, 0x73, 0xa4, 0x82, 0x17,
+	0xa7, 0x8c, 0x21, 0x24, 0xef, 0xe4, 0x98, 0x63, 0x5d, 0x78, 0xc5, 0xa2, 0xf1, 0xa5, 0x97, 0xb1,
+	0x10, 0x58, 0x43, 0x88, 0x77, 0x4b, 0xd6, 0x10, 0x72, 0x22, 0x0b, 0x16, 0x58, 0x5a, 0xd7, 0x13,
+	0x59, 0xfd, 0xb0, 0xcb, 0xbd, 0xb8, 0x75, 0xb9, 0xeb, 0x73, 0xd8, 0x1f, 0xb1, 0x87, 0x47, 0x9f,
+	0x50, 0xd7, 0x77, 0xa9, 0x8b, 0x3e, 0x82, 0x4a, 0xfa, 0x32, 0x49, 0x07, 0x47, 0x59, 0x3e, 0x4d,
+	0x12, 0xb6, 0xb6, 0x98, 0x78, 0x24, 0xb8, 0x22, 0xbe, 0xe3, 0xb2, 0x8d, 0xcf, 0x37, 0xf2, 0x18,
+	0x52, 0x95, 0x41, 0x59, 0x6d, 0x04, 0x1f, 0x6e, 0x67, 0x0b, 0xcc, 0xe3, 0x8a, 0xd4, 0x18, 0x54,
+	0x9f, 0xc9, 0x6c, 0xaf, 0xa3, 0x24, 0xe0, 0xb5, 0xdc, 0x7c, 0x07, 0x29, 0xdb, 0xef, 0xa0, 0xff,
+	0x6f, 0x16, 0xd7, 0x00, 0x86, 0x84, 0x7c, 0x65, 0x93, 0x77, 0x24, 0xa1, 0xa9, 0x34, 0x98, 0xfb,
+	0x4c, 0xfa, 0x11, 0xec, 0x33, 0x69, 0xb8, 0x24, 0x5e, 0x70, 0x19, 0x10, 0x3e, 0xd8, 0x64, 0x12,
+	0x31, 0x35, 0xa4, 0xa4, 0xff, 0x43, 0x81, 0x1a, 0x43, 0x66, 0x74, 0x3f, 0x87, 0x62, 0xc8, 0x23,
+	0xca, 0xc3, 0x72, 0x94, 0x55, 0x7f, 0x9d, 0xac, 0x9b, 0xc3, 0x12, 0xc4, 0xe0, 0x11, 0x4f, 0x59,
+	0xdf, 0xdb, 0x01, 0x17, 0x6c, 0x18, 0x5c, 0x80, 0xd0, 0x17, 0x50, 0x49, 0x52, 0x4e, 0xf2, 0x5c,
+	0x1c, 0x6f, 0x79, 0x64, 0x8c, 0xbb, 0x39, 0xbc, 0x86, 0xb6, 0x8a, 0x50, 0x18, 0xdd, 0x2c, 0x89,
+	0xfe, 0x9f, 0x3d, 0x28, 0x33, 0x98, 0x15, 0x5e, 0x46, 0xe8, 0xa7, 0xa0, 0x8a, 0xd3, 0x29, 0x98,
+	0x3e, 0xd9, 0x0a, 0x94, 0x2e, 0x08, 0x0b, 0x0c, 0xfa, 0x31, 0x14, 0x12, 0x1a, 0x2d, 0xeb, 0x7b,
+	0xf7, 0x61, 0x39, 0x04, 0xfd, 0x1a, 0xca, 0x13, 0x32, 0x73, 0xaf, 0x82, 0x28, 0x96, 0x6f, 0x87,
+	0x4f, 0xb6, 0xe0, 0x2c, 0x39, 0xff, 0xd3, 0x92, 0x28, 0x9c, 0xe1, 0x51, 0x07, 0x6a, 0x5e, 0x14,
+	0x52, 0x12, 0x52, 0x87, 0xde, 0x2c, 0xd3, 0x27, 0xe2, 0xa7, 0xbb, 0xfd, 0xdb, 0x02, 0xc9, 0x56,
+	0xc6, 0x8f, 0x66, 0x2a, 0xe8, 0xbf, 0x85, 0xda, 0x66, 0x7c, 0xf4, 0x04, 0x1e, 0xb5, 0x7a, 0x83,
+	0xf6, 0x97, 0xce, 0xd8, 0x1e, 0x59, 0x3d, 0x07, 0x9b, 0x46, 0xe7, 0x42, 0xcb, 0x31, 0xf5, 0x2b,
+	0xc3, 0xea, 0x39, 0xd6, 0x2b, 0xfe, 0x78, 0x13, 0x6a, 0x45, 0xff, 0x39, 0x1c, 0xde, 0x8a, 0x8e,
+	0x2a, 0xa0, 0xf2, 0x00, 0x5a, 0x0e, 0x1d, 0xc1, 0x61, 0xd7, 0x34, 0x3a, 0x26, 0x76, 0xde, 0x5a,
+	0xa3, 0xae, 0x33, 0xb4, 0xfe, 0xa8, 0x29, 0xfa, 0x5f, 0xe1, 0xb0, 0x43, 0xe6, 0xc1, 0x15, 0x89,
+	0xb3, 0x8f, 0x83, 0xc6, 0xfd, 0x1f, 0x07, 0x6c, 0x53, 0x85, 0x1d, 0x7d, 0x06, 0x2a, 0x6f, 0x59,
+	0x59, 0xdb, 0xfd, 0x14, 0xd8, 0x62, 0xca, 0x6e, 0x0e, 0x0b, 0x6b, 0xb6, 0x87, 0x0b, 0x38, 0x96,
+	0x73, 0xe2, 0x76, 0xca, 0x07, 0xc6, 0xce, 0x2f, 0xa0, 0x1c, 0x4b, 0xa8, 0x4c, 0x55, 0xcf, 0xea,
+	0x7a, 0x2b, 0x14, 0xce, 0x90, 0x67, 0x7f, 0x53, 0xe0, 0xd0, 0xa0, 0xd1, 0x22, 0xf0, 0xb2, 0xf1,
+	0x81, 0xfe, 0x00, 0x95, 0xb5, 0xa0, 0xa5, 0x7c, 0xcd, 0xf0, 0x8a, 0xcc, 0xa3, 0x25, 0x39, 0x39,
+	0xb9, 0x3b, 0x71, 0xd2, 0xc0, 0x7a, 0xae, 0xa1, 0xbc, 0x50, 0xd0, 0x6f, 0xa0, 0x24, 0x33, 0xee,
+	0x70, 0x7f, 0x2f, 0x2b, 0xe1, 0x7c, 0x76, 0x01, 0x47, 0xfd, 0xd5, 0x9c, 0x06, 0xdb, 0x55, 0x40,
+	0xad, 0xfb, 0x62, 0x3e, 0x5d, 0x7f, 0x72, 0xed, 0xac, 0x9d, 0x0c, 0x3d, 0x80, 0xfd, 0x1e, 0xf1,
+	0xa7, 0x24, 0x16, 0x4f, 0x9f, 0x04, 0xfd, 0x1e, 0x2a, 0xf2, 0xd9, 0x34, 0x21, 0x3b, 0xc2, 0xae,
+	0xbf, 0xe4, 0xee, 0xbe, 0x95, 0xf4, 0xdc, 0x0b, 0xa5, 0x35, 0x86, 0xcf, 0xa2, 0x78, 0xda, 0x9c,
+	0xdd, 0x2c, 0x49, 0x3c, 0xe7, 0x91, 0x9b, 0x97, 0xee, 0x24, 0x0e, 0x3c, 0x71, 0x8d, 0x25, 0xa9,
+	0xff, 0x5f, 0x7e, 0x36, 0x0d, 0xe8, 0x6c, 0x35, 0x61, 0x19, 0x4e, 0x37, 0xd0, 0xa7, 0x02, 0x2d,
+	0xbe, 0x4c, 0x93, 0x53, 0x89, 0x9e, 0x14, 0xb9, 0xfc, 0xf2, 0x7f, 0x03, 0x00, 0x80, 0xc4, 0x3e,
+	0x37, 0x00, 0x0f, 0x00, 0x00,
 }
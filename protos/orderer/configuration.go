@@ -27,6 +27,7 @@ import (
 
 func init() {
 	common.ChannelGroupMap["Orderer"] = DynamicOrdererGroupFactory{}
+	common.PayloadDataMap[common.HeaderType_DELIVER_SEEK_INFO] = func() proto.Message { return &SeekInfo{} }
 }
 
 type DynamicOrdererGroupFactory struct{}
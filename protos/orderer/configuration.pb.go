@@ -12,8 +12,34 @@ var _ = proto.Marshal
 var _ = fmt.Errorf
 var _ = math.Inf
 
+// The state of the channel, which in maintenance mode only accepts
+// config updates, so that operators can perform migrations, certificate
+// rotations or repairs without client traffic interfering. The state is
+// only honored once the Maintenance capability of the channel is enabled
+type ConsensusType_State int32
+
+const (
+	ConsensusType_STATE_NORMAL      ConsensusType_State = 0
+	ConsensusType_STATE_MAINTENANCE ConsensusType_State = 1
+)
+
+var ConsensusType_State_name = map[int32]string{
+	0: "STATE_NORMAL",
+	1: "STATE_MAINTENANCE",
+}
+var ConsensusType_State_value = map[string]int32{
+	"STATE_NORMAL":      0,
+	"STATE_MAINTENANCE": 1,
+}
+
+func (x ConsensusType_State) String() string {
+	return proto.EnumName(ConsensusType_State_name, int32(x))
+}
+func (ConsensusType_State) EnumDescriptor() ([]byte, []int) { return fileDescriptor1, []int{0, 0} }
+
 type ConsensusType struct {
-	Type string `protobuf:"bytes,1,opt,name=type" json:"type,omitempty"`
+	Type  string              `protobuf:"bytes,1,opt,name=type" json:"type,omitempty"`
+	State ConsensusType_State `protobuf:"varint,2,opt,name=state,enum=orderer.ConsensusType_State" json:"state,omitempty"`
 }
 
 func (m *ConsensusType) Reset()                    { *m = ConsensusType{} }
@@ -28,6 +54,13 @@ func (m *ConsensusType) GetType() string {
 	return ""
 }
 
+func (m *ConsensusType) GetState() ConsensusType_State {
+	if m != nil {
+		return m.State
+	}
+	return ConsensusType_STATE_NORMAL
+}
+
 type BatchSize struct {
 	// Simply specified as number of messages for now, in the future
 	// we may want to allow this to be specified by size in bytes
@@ -84,6 +117,48 @@ func (m *BatchTimeout) GetTimeout() string {
 	return ""
 }
 
+// BlockSchedule cuts blocks regardless of the batch size and timeout, so that
+// the block cadence of a channel can serve as a heartbeat
+type BlockSchedule struct {
+	// The maximum interval between two blocks, as any duration string
+	// parseable by ParseDuration(). When it elapses without a block being cut,
+	// the pending messages are cut into a block, or an empty block is cut if
+	// there are none. Empty disables the schedule.
+	MaxInterval string `protobuf:"bytes,1,opt,name=max_interval,json=maxInterval" json:"max_interval,omitempty"`
+	// The hours of the day, in UTC, from start_hour included to end_hour
+	// excluded, during which the schedule applies. The window wraps around
+	// midnight when start_hour is greater than end_hour, and spans the whole
+	// day when they are equal.
+	StartHour uint32 `protobuf:"varint,2,opt,name=start_hour,json=startHour" json:"start_hour,omitempty"`
+	EndHour   uint32 `protobuf:"varint,3,opt,name=end_hour,json=endHour" json:"end_hour,omitempty"`
+}
+
+func (m *BlockSchedule) Reset()                    { *m = BlockSchedule{} }
+func (m *BlockSchedule) String() string            { return proto.CompactTextString(m) }
+func (*BlockSchedule) ProtoMessage()               {}
+func (*BlockSchedule) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{3} }
+
+func (m *BlockSchedule) GetMaxInterval() string {
+	if m != nil {
+		return m.MaxInterval
+	}
+	return ""
+}
+
+func (m *BlockSchedule) GetStartHour() uint32 {
+	if m != nil {
+		return m.StartHour
+	}
+	return 0
+}
+
+func (m *BlockSchedule) GetEndHour() uint32 {
+	if m != nil {
+		return m.EndHour
+	}
+	return 0
+}
+
 // Carries a list of bootstrap brokers, i.e. this is not the exclusive set of
 // brokers an ordering service
 type KafkaBrokers struct {
@@ -95,7 +170,7 @@ type KafkaBrokers struct {
 func (m *KafkaBrokers) Reset()                    { *m = KafkaBrokers{} }
 func (m *KafkaBrokers) String() string            { return proto.CompactTextString(m) }
 func (*KafkaBrokers) ProtoMessage()               {}
-func (*KafkaBrokers) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{3} }
+func (*KafkaBrokers) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{4} }
 
 func (m *KafkaBrokers) GetBrokers() []string {
 	if m != nil {
@@ -112,7 +187,7 @@ type ChannelRestrictions struct {
 func (m *ChannelRestrictions) Reset()                    { *m = ChannelRestrictions{} }
 func (m *ChannelRestrictions) String() string            { return proto.CompactTextString(m) }
 func (*ChannelRestrictions) ProtoMessage()               {}
-func (*ChannelRestrictions) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{4} }
+func (*ChannelRestrictions) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{5} }
 
 func (m *ChannelRestrictions) GetMaxCount() uint64 {
 	if m != nil {
@@ -121,36 +196,82 @@ func (m *ChannelRestrictions) GetMaxCount() uint64 {
 	return 0
 }
 
+// Capabilities lists the capabilities of the orderers of a channel, the
+// features changing how the orderers order the transactions, which may only be
+// enabled once all the orderers of the channel support them. A capability is
+// enabled by its presence in the map.
+type Capabilities struct {
+	Capabilities map[string]*Capability `protobuf:"bytes,1,rep,name=capabilities" json:"capabilities,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+}
+
+func (m *Capabilities) Reset()                    { *m = Capabilities{} }
+func (m *Capabilities) String() string            { return proto.CompactTextString(m) }
+func (*Capabilities) ProtoMessage()               {}
+func (*Capabilities) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{6} }
+
+func (m *Capabilities) GetCapabilities() map[string]*Capability {
+	if m != nil {
+		return m.Capabilities
+	}
+	return nil
+}
+
+// Capability is empty, it only marks a capability as enabled
+type Capability struct {
+}
+
+func (m *Capability) Reset()                    { *m = Capability{} }
+func (m *Capability) String() string            { return proto.CompactTextString(m) }
+func (*Capability) ProtoMessage()               {}
+func (*Capability) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{7} }
+
 func init() {
 	proto.RegisterType((*ConsensusType)(nil), "orderer.ConsensusType")
 	proto.RegisterType((*BatchSize)(nil), "orderer.BatchSize")
 	proto.RegisterType((*BatchTimeout)(nil), "orderer.BatchTimeout")
+	proto.RegisterType((*BlockSchedule)(nil), "orderer.BlockSchedule")
 	proto.RegisterType((*KafkaBrokers)(nil), "orderer.KafkaBrokers")
 	proto.RegisterType((*ChannelRestrictions)(nil), "orderer.ChannelRestrictions")
+	proto.RegisterType((*Capabilities)(nil), "orderer.Capabilities")
+	proto.RegisterType((*Capability)(nil), "orderer.Capability")
+	proto.RegisterEnum("orderer.ConsensusType_State", ConsensusType_State_name, ConsensusType_State_value)
 }
 
 func init() { proto.RegisterFile("orderer/configuration.proto", fileDescriptor1) }
 
 var fileDescriptor1 = []byte{
-	// 313 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x4c, 0xd0, 0xcd, 0x4a, 0xc3, 0x40,
-	0x10, 0x07, 0x70, 0x62, 0x8b, 0xb5, 0x8b, 0x45, 0xbb, 0xbd, 0x04, 0x7a, 0x29, 0x11, 0xa1, 0x48,
-	0x49, 0x40, 0xdf, 0x20, 0x3d, 0x4a, 0x2f, 0xb1, 0x5e, 0xbc, 0x94, 0x4d, 0x3a, 0x49, 0x96, 0x36,
-	0x3b, 0x61, 0x76, 0x03, 0x89, 0xef, 0xe1, 0xfb, 0xca, 0x6e, 0x52, 0xed, 0x6d, 0x3e, 0x7e, 0x0b,
-	0xb3, 0x7f, 0xb6, 0x44, 0x3a, 0x02, 0x01, 0x45, 0x19, 0xaa, 0x5c, 0x16, 0x0d, 0x09, 0x23, 0x51,
-	0x85, 0x35, 0xa1, 0x41, 0x3e, 0x19, 0x96, 0xc1, 0x13, 0x9b, 0x6d, 0x51, 0x69, 0x50, 0xba, 0xd1,
-	0xfb, 0xae, 0x06, 0xce, 0xd9, 0xd8, 0x74, 0x35, 0xf8, 0xde, 0xca, 0x5b, 0x4f, 0x13, 0x57, 0x07,
-	0x3f, 0x1e, 0x9b, 0xc6, 0xc2, 0x64, 0xe5, 0x87, 0xfc, 0x06, 0xfe, 0xc2, 0xe6, 0x95, 0x68, 0x0f,
-	0x15, 0x68, 0x2d, 0x0a, 0x38, 0x64, 0xd8, 0x28, 0xe3, 0xf8, 0x2c, 0x79, 0xa8, 0x44, 0xbb, 0xeb,
-	0xe7, 0x5b, 0x3b, 0xe6, 0x1b, 0xc6, 0x45, 0xaa, 0xf1, 0xdc, 0x18, 0x38, 0xd8, 0x47, 0x69, 0x67,
-	0x40, 0xfb, 0x37, 0x0e, 0x3f, 0x5e, 0x36, 0x3b, 0xd1, 0xc6, 0x76, 0xce, 0x43, 0xb6, 0xa8, 0x09,
-	0x72, 0x20, 0x82, 0xe3, 0x15, 0x1f, 0x39, 0x3e, 0xff, 0x5b, 0x5d, 0x7c, 0xb0, 0x66, 0xf7, 0xee,
-	0xac, 0xbd, 0xac, 0x00, 0x1b, 0xc3, 0x7d, 0x36, 0x31, 0x7d, 0x39, 0x9c, 0x7f, 0x69, 0xad, 0x7c,
-	0x17, 0xf9, 0x49, 0xc4, 0x84, 0x27, 0x20, 0x6d, 0x65, 0xda, 0x97, 0xbe, 0xb7, 0x1a, 0x59, 0x39,
-	0xb4, 0xc1, 0x2b, 0x5b, 0x6c, 0x4b, 0xa1, 0x14, 0x9c, 0x13, 0xd0, 0x86, 0x64, 0x66, 0x53, 0xd3,
-	0x7c, 0xc9, 0xa6, 0xf6, 0xa0, 0xff, 0xcf, 0x8e, 0x93, 0xbb, 0x4a, 0xb4, 0xee, 0x97, 0xf1, 0x27,
-	0x7b, 0x46, 0x2a, 0xc2, 0xb2, 0xab, 0x81, 0xce, 0x70, 0x2c, 0x80, 0xc2, 0x5c, 0xa4, 0x24, 0xb3,
-	0x3e, 0x6d, 0x1d, 0x0e, 0x69, 0x7f, 0x6d, 0x0a, 0x69, 0xca, 0x26, 0x0d, 0x33, 0xac, 0xa2, 0x2b,
-	0x1d, 0xf5, 0x3a, 0xea, 0x75, 0x34, 0xe8, 0xf4, 0xd6, 0xf5, 0x6f, 0xbf, 0x01, 0x00, 0x00, 0xff,
-	0xff, 0x0c, 0x47, 0xa2, 0x55, 0xca, 0x01, 0x00, 0x00,
+	// 524 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x64, 0x93, 0xd1, 0x8e, 0xd2, 0x40,
+	0x14, 0x86, 0xed, 0xb2, 0xc8, 0x72, 0x28, 0x0a, 0x43, 0x4c, 0xd0, 0xd5, 0x04, 0x9b, 0x18, 0xd1,
+	0x6c, 0x8a, 0xc1, 0x1b, 0xe3, 0x1d, 0x10, 0x12, 0x37, 0x2b, 0x98, 0x94, 0x7a, 0xe3, 0x0d, 0x99,
+	0xb6, 0x07, 0xda, 0xd0, 0x76, 0x9a, 0x99, 0xe9, 0x86, 0xfa, 0x06, 0x3e, 0x80, 0x0f, 0xe2, 0x1b,
+	0x9a, 0xe9, 0x94, 0x05, 0xb2, 0x77, 0xe7, 0xfc, 0xff, 0x77, 0x3a, 0x7f, 0x4e, 0x4e, 0xe1, 0x9a,
+	0xf1, 0x00, 0x39, 0xf2, 0x91, 0xcf, 0xd2, 0x4d, 0xb4, 0xcd, 0x39, 0x95, 0x11, 0x4b, 0xed, 0x8c,
+	0x33, 0xc9, 0x48, 0xa3, 0x32, 0xad, 0x3f, 0x06, 0xb4, 0x67, 0x2c, 0x15, 0x98, 0x8a, 0x5c, 0xb8,
+	0x45, 0x86, 0x84, 0xc0, 0xa5, 0x2c, 0x32, 0xec, 0x1b, 0x03, 0x63, 0xd8, 0x74, 0xca, 0x9a, 0x8c,
+	0xa1, 0x2e, 0x24, 0x95, 0xd8, 0xbf, 0x18, 0x18, 0xc3, 0x67, 0xe3, 0xd7, 0x76, 0x35, 0x6e, 0x9f,
+	0x8d, 0xda, 0x2b, 0xc5, 0x38, 0x1a, 0xb5, 0x3e, 0x41, 0xbd, 0xec, 0x49, 0x07, 0xcc, 0x95, 0x3b,
+	0x71, 0xe7, 0xeb, 0xe5, 0x0f, 0x67, 0x31, 0xf9, 0xde, 0x79, 0x42, 0x5e, 0x40, 0x57, 0x2b, 0x8b,
+	0xc9, 0xed, 0xd2, 0x9d, 0x2f, 0x27, 0xcb, 0xd9, 0xbc, 0x63, 0x58, 0x7f, 0x0d, 0x68, 0x4e, 0xa9,
+	0xf4, 0xc3, 0x55, 0xf4, 0x1b, 0xc9, 0x47, 0xe8, 0x26, 0x74, 0xbf, 0x4e, 0x50, 0x08, 0xba, 0xc5,
+	0xb5, 0xcf, 0xf2, 0x54, 0x96, 0xa1, 0xda, 0xce, 0xf3, 0x84, 0xee, 0x17, 0x5a, 0x9f, 0x29, 0x99,
+	0xdc, 0x00, 0xa1, 0x9e, 0x60, 0x71, 0x2e, 0x71, 0xad, 0x86, 0xbc, 0x42, 0xa2, 0x28, 0xc3, 0xb6,
+	0x9d, 0xce, 0xc1, 0x59, 0xd0, 0xfd, 0x54, 0xe9, 0xc4, 0x86, 0x5e, 0xc6, 0x71, 0x83, 0x9c, 0x63,
+	0x70, 0x82, 0xd7, 0x4a, 0xbc, 0xfb, 0x60, 0x1d, 0x78, 0x6b, 0x08, 0x66, 0x19, 0xcb, 0x8d, 0x12,
+	0x64, 0xb9, 0x24, 0x7d, 0x68, 0x48, 0x5d, 0x56, 0x4b, 0x3a, 0xb4, 0x56, 0x0c, 0xed, 0x69, 0xcc,
+	0xfc, 0xdd, 0xca, 0x0f, 0x31, 0xc8, 0x63, 0x24, 0x6f, 0xc1, 0x54, 0x0f, 0x44, 0xa9, 0x44, 0x7e,
+	0x4f, 0xe3, 0x8a, 0x6f, 0x25, 0x74, 0x7f, 0x5b, 0x49, 0xe4, 0x0d, 0x80, 0x90, 0x94, 0xcb, 0x75,
+	0xc8, 0x72, 0x5e, 0x65, 0x6e, 0x96, 0xca, 0x37, 0x96, 0x73, 0xf2, 0x12, 0xae, 0x30, 0x0d, 0xb4,
+	0xa9, 0x13, 0x36, 0x30, 0x0d, 0x94, 0xa5, 0x72, 0xdd, 0xd1, 0xcd, 0x8e, 0x4e, 0x39, 0xdb, 0x21,
+	0x17, 0x2a, 0x97, 0xa7, 0xcb, 0xbe, 0x31, 0xa8, 0xa9, 0x5c, 0x55, 0x6b, 0x8d, 0xa1, 0x37, 0x0b,
+	0x69, 0x9a, 0x62, 0xec, 0xa0, 0x90, 0x3c, 0xf2, 0xd5, 0x29, 0x08, 0x72, 0x0d, 0x4d, 0x95, 0xee,
+	0xb8, 0xda, 0x4b, 0xe7, 0x2a, 0xa1, 0xfb, 0x72, 0xa7, 0xd6, 0x3f, 0x03, 0xcc, 0x19, 0xcd, 0xa8,
+	0x17, 0xc5, 0x91, 0x8c, 0x50, 0x90, 0x3b, 0x30, 0xfd, 0x93, 0xbe, 0x7c, 0xa3, 0x35, 0x7e, 0x7f,
+	0xbc, 0x85, 0x13, 0xf3, 0xac, 0x99, 0xa7, 0x92, 0x17, 0xce, 0xd9, 0xf0, 0x2b, 0x17, 0xba, 0x8f,
+	0x10, 0xd2, 0x81, 0xda, 0x0e, 0x8b, 0x6a, 0x49, 0xaa, 0x24, 0x1f, 0xa0, 0x7e, 0x4f, 0xe3, 0x5c,
+	0x1f, 0x5e, 0x6b, 0xdc, 0x7b, 0xfc, 0x58, 0xe1, 0x68, 0xe2, 0xeb, 0xc5, 0x17, 0xc3, 0x32, 0x01,
+	0x8e, 0xc6, 0xf4, 0x27, 0xbc, 0x63, 0x7c, 0x6b, 0x87, 0x45, 0x86, 0x3c, 0xc6, 0x60, 0x8b, 0xdc,
+	0xde, 0x50, 0x8f, 0x47, 0xbe, 0xfe, 0x09, 0xc4, 0xe1, 0x63, 0xbf, 0x6e, 0xb6, 0x91, 0x0c, 0x73,
+	0xcf, 0xf6, 0x59, 0x32, 0x3a, 0xa1, 0x47, 0x9a, 0x1e, 0x69, 0x7a, 0x54, 0xd1, 0xde, 0xd3, 0xb2,
+	0xff, 0xfc, 0x7f, 0x00, 0xdf, 0x89, 0x7e, 0x00, 0x61, 0x03, 0x00, 0x00,
 }
@@ -168,7 +168,9 @@ func _KafkaMessage_OneofSizer(msg proto.Message) (n int) {
 
 // KafkaMessageRegular wraps a marshalled envelope.
 type KafkaMessageRegular struct {
-	Payload []byte `protobuf:"bytes,1,opt,name=payload,proto3" json:"payload,omitempty"`
+	Payload    []byte `protobuf:"bytes,1,opt,name=payload,proto3" json:"payload,omitempty"`
+	TraceId    string `protobuf:"bytes,2,opt,name=trace_id,json=traceId" json:"trace_id,omitempty"`
+	ReceivedAt int64  `protobuf:"varint,3,opt,name=received_at,json=receivedAt" json:"received_at,omitempty"`
 }
 
 func (m *KafkaMessageRegular) Reset()                    { *m = KafkaMessageRegular{} }
@@ -183,10 +185,28 @@ func (m *KafkaMessageRegular) GetPayload() []byte {
 	return nil
 }
 
+func (m *KafkaMessageRegular) GetTraceId() string {
+	if m != nil {
+		return m.TraceId
+	}
+	return ""
+}
+
+func (m *KafkaMessageRegular) GetReceivedAt() int64 {
+	if m != nil {
+		return m.ReceivedAt
+	}
+	return 0
+}
+
 // KafkaMessageTimeToCut is used to signal to the orderers
 // that it is time to cut block <block_number>.
 type KafkaMessageTimeToCut struct {
 	BlockNumber uint64 `protobuf:"varint,1,opt,name=block_number,json=blockNumber" json:"block_number,omitempty"`
+	// Set when the block schedule of the channel is due, in which case the
+	// block is cut even when there are no pending messages. Orderers predating
+	// the flag do not cut an empty block on such a message
+	Heartbeat bool `protobuf:"varint,2,opt,name=heartbeat" json:"heartbeat,omitempty"`
 }
 
 func (m *KafkaMessageTimeToCut) Reset()                    { *m = KafkaMessageTimeToCut{} }
@@ -201,6 +221,13 @@ func (m *KafkaMessageTimeToCut) GetBlockNumber() uint64 {
 	return 0
 }
 
+func (m *KafkaMessageTimeToCut) GetHeartbeat() bool {
+	if m != nil {
+		return m.Heartbeat
+	}
+	return false
+}
+
 // KafkaMessageConnect is posted by an orderer upon booting up.
 // It is used to prevent the panic that would be caused if we
 // were to consume an empty partition. It is ignored by all
@@ -226,6 +253,10 @@ func (m *KafkaMessageConnect) GetPayload() []byte {
 // of the Kafka-based orderer.
 type KafkaMetadata struct {
 	LastOffsetPersisted int64 `protobuf:"varint,1,opt,name=last_offset_persisted,json=lastOffsetPersisted" json:"last_offset_persisted,omitempty"`
+	// The number of the block the metadata is written to, persisted along
+	// with the offset so that the ledger height can be verified on restart.
+	// Left unset by the orderers which did not persist it.
+	LastCutBlockNumber uint64 `protobuf:"varint,2,opt,name=last_cut_block_number,json=lastCutBlockNumber" json:"last_cut_block_number,omitempty"`
 }
 
 func (m *KafkaMetadata) Reset()                    { *m = KafkaMetadata{} }
@@ -240,6 +271,13 @@ func (m *KafkaMetadata) GetLastOffsetPersisted() int64 {
 	return 0
 }
 
+func (m *KafkaMetadata) GetLastCutBlockNumber() uint64 {
+	if m != nil {
+		return m.LastCutBlockNumber
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*KafkaMessage)(nil), "orderer.KafkaMessage")
 	proto.RegisterType((*KafkaMessageRegular)(nil), "orderer.KafkaMessageRegular")
@@ -251,25 +289,30 @@ func init() {
 func init() { proto.RegisterFile("orderer/kafka.proto", fileDescriptor2) }
 
 var fileDescriptor2 = []byte{
-	// 316 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x7c, 0x91, 0x4f, 0x6b, 0xc2, 0x40,
-	0x10, 0xc5, 0xb5, 0x8a, 0xd2, 0xd1, 0x5e, 0x22, 0x42, 0x0e, 0xa5, 0xb4, 0x42, 0xa1, 0x87, 0x92,
-	0x80, 0xbd, 0x94, 0x9e, 0x8a, 0x5e, 0x84, 0xd2, 0x3f, 0x2c, 0xf6, 0xd2, 0x4b, 0xd8, 0x6c, 0x26,
-	0x31, 0x98, 0xb8, 0x61, 0x77, 0x72, 0xf0, 0x3b, 0xf6, 0x43, 0x95, 0xec, 0x6e, 0x40, 0x4a, 0xf0,
-	0x38, 0xf3, 0x7e, 0x8f, 0xf7, 0x76, 0x16, 0x66, 0x52, 0x25, 0xa8, 0x50, 0x85, 0x7b, 0x9e, 0xee,
-	0x79, 0x50, 0x29, 0x49, 0xd2, 0x1b, 0xbb, 0xe5, 0xe2, 0xb7, 0x0f, 0xd3, 0xb7, 0x46, 0x78, 0x47,
-	0xad, 0x79, 0x86, 0xde, 0x33, 0x8c, 0x15, 0x66, 0x75, 0xc1, 0x95, 0xdf, 0xbf, 0xed, 0x3f, 0x4c,
-	0x96, 0xd7, 0x81, 0x63, 0x83, 0x53, 0x8e, 0x59, 0x66, 0xd3, 0x63, 0x2d, 0xee, 0xbd, 0xc2, 0x84,
-	0xf2, 0x12, 0x23, 0x92, 0x91, 0xa8, 0xc9, 0xbf, 0x30, 0xee, 0x9b, 0x4e, 0xf7, 0x36, 0x2f, 0x71,
-	0x2b, 0xd7, 0x35, 0x6d, 0x7a, 0xec, 0x92, 0xda, 0xa1, 0xc9, 0x16, 0xf2, 0x70, 0x40, 0x41, 0xfe,
-	0xe0, 0x4c, 0xf6, 0xda, 0x32, 0x4d, 0xb6, 0xc3, 0x57, 0x23, 0x18, 0x6e, 0x8f, 0x15, 0x2e, 0x42,
-	0x98, 0x75, 0xb4, 0xf4, 0x7c, 0x18, 0x57, 0xfc, 0x58, 0x48, 0x9e, 0x98, 0x47, 0x4d, 0x59, 0x3b,
-	0x2e, 0x5e, 0x60, 0xde, 0x59, 0xcc, 0xbb, 0x83, 0x69, 0x5c, 0x48, 0xb1, 0x8f, 0x0e, 0x75, 0x19,
-	0xa3, 0x3d, 0xc6, 0x90, 0x4d, 0xcc, 0xee, 0xc3, 0xac, 0xfe, 0x87, 0xb9, 0x5a, 0x67, 0xc2, 0xd6,
-	0x70, 0xe5, 0x0c, 0xc4, 0x13, 0x4e, 0xdc, 0x5b, 0xc2, 0xbc, 0xe0, 0x9a, 0x22, 0x99, 0xa6, 0x1a,
-	0x29, 0xaa, 0x50, 0xe9, 0x5c, 0x13, 0x5a, 0xe3, 0x80, 0xcd, 0x1a, 0xf1, 0xd3, 0x68, 0x5f, 0xad,
-	0xb4, 0xfa, 0x86, 0x7b, 0xa9, 0xb2, 0x60, 0x77, 0xac, 0x50, 0x15, 0x98, 0x64, 0xa8, 0x82, 0x94,
-	0xc7, 0x2a, 0x17, 0xf6, 0x6b, 0x75, 0x7b, 0xb2, 0x9f, 0xc7, 0x2c, 0xa7, 0x5d, 0x1d, 0x07, 0x42,
-	0x96, 0xe1, 0x09, 0x1d, 0x5a, 0x3a, 0xb4, 0x74, 0xe8, 0xe8, 0x78, 0x64, 0xe6, 0xa7, 0xbf, 0x00,
-	0x00, 0x00, 0xff, 0xff, 0x15, 0x78, 0x4d, 0xd8, 0x2f, 0x02, 0x00, 0x00,
+	// 385 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x7c, 0x92, 0x4f, 0x8b, 0x9c, 0x40,
+	0x10, 0xc5, 0x77, 0x76, 0x96, 0x75, 0xa7, 0x9c, 0x5c, 0x5a, 0x16, 0x0c, 0x2c, 0xf9, 0x23, 0x04,
+	0x72, 0x08, 0x4a, 0x36, 0x97, 0x1c, 0x93, 0x99, 0xcb, 0x86, 0x90, 0x3f, 0x34, 0x13, 0x08, 0xb9,
+	0x34, 0x6d, 0x5b, 0x3a, 0xa2, 0x4e, 0x4b, 0x5b, 0x2e, 0xcc, 0x77, 0xcc, 0x87, 0x0a, 0x76, 0x2b,
+	0x33, 0x01, 0x93, 0x63, 0xd5, 0xfb, 0x3d, 0xea, 0xbd, 0x56, 0x08, 0xb4, 0xc9, 0xd0, 0xa0, 0x49,
+	0x2a, 0x99, 0x57, 0x32, 0x6e, 0x8d, 0x26, 0xcd, 0xbc, 0x71, 0x19, 0xfd, 0x5e, 0xc0, 0xfa, 0xf3,
+	0x20, 0x7c, 0xc1, 0xae, 0x93, 0x05, 0xb2, 0xf7, 0xe0, 0x19, 0x2c, 0xfa, 0x5a, 0x9a, 0x70, 0xf1,
+	0x62, 0xf1, 0xda, 0xbf, 0xbf, 0x8b, 0x47, 0x36, 0x3e, 0xe7, 0xb8, 0x63, 0x1e, 0x2e, 0xf8, 0x84,
+	0xb3, 0x0f, 0xe0, 0x53, 0xd9, 0xa0, 0x20, 0x2d, 0x54, 0x4f, 0xe1, 0xa5, 0x75, 0x3f, 0x9b, 0x75,
+	0xef, 0xca, 0x06, 0x77, 0x7a, 0xdb, 0xd3, 0xc3, 0x05, 0x5f, 0xd1, 0x34, 0x0c, 0xb7, 0x95, 0x3e,
+	0x1c, 0x50, 0x51, 0xb8, 0xfc, 0xcf, 0xed, 0xad, 0x63, 0x86, 0xdb, 0x23, 0xbe, 0xb9, 0x86, 0xab,
+	0xdd, 0xb1, 0xc5, 0xa8, 0x82, 0x60, 0x26, 0x25, 0x0b, 0xc1, 0x6b, 0xe5, 0xb1, 0xd6, 0x32, 0xb3,
+	0xa5, 0xd6, 0x7c, 0x1a, 0xd9, 0x53, 0xb8, 0x21, 0x23, 0x15, 0x8a, 0x32, 0xb3, 0x89, 0x57, 0xdc,
+	0xb3, 0xf3, 0xa7, 0x8c, 0x3d, 0x07, 0xdf, 0xa0, 0xc2, 0xf2, 0x11, 0x33, 0x21, 0x5d, 0xa2, 0x25,
+	0x87, 0x69, 0xf5, 0x91, 0xa2, 0x9f, 0x70, 0x3b, 0x5b, 0x8a, 0xbd, 0x84, 0x75, 0x5a, 0x6b, 0x55,
+	0x89, 0x43, 0xdf, 0xa4, 0xe8, 0x1e, 0xf2, 0x8a, 0xfb, 0x76, 0xf7, 0xd5, 0xae, 0xd8, 0x1d, 0xac,
+	0xf6, 0x28, 0x0d, 0xa5, 0x28, 0xdd, 0x53, 0xdd, 0xf0, 0xd3, 0x22, 0x4a, 0x20, 0x98, 0x29, 0xfc,
+	0xef, 0x1a, 0xd1, 0x23, 0x3c, 0x19, 0x0d, 0x24, 0x33, 0x49, 0x92, 0xdd, 0xc3, 0x6d, 0x2d, 0x3b,
+	0x12, 0x3a, 0xcf, 0x3b, 0x24, 0xd1, 0xa2, 0xe9, 0xca, 0x8e, 0xd0, 0x19, 0x97, 0x3c, 0x18, 0xc4,
+	0x6f, 0x56, 0xfb, 0x3e, 0x49, 0xec, 0xed, 0xe8, 0x51, 0x3d, 0x89, 0xbf, 0xf2, 0x5f, 0xda, 0xfc,
+	0x6c, 0x10, 0xb7, 0x3d, 0x6d, 0x4e, 0x35, 0x36, 0x3f, 0xe0, 0x95, 0x36, 0x45, 0xbc, 0x3f, 0xb6,
+	0x68, 0x6a, 0xcc, 0x0a, 0x34, 0x71, 0x2e, 0x53, 0x53, 0x2a, 0xf7, 0x9f, 0x75, 0xd3, 0xf7, 0xfb,
+	0xf5, 0xa6, 0x28, 0x69, 0xdf, 0xa7, 0xb1, 0xd2, 0x4d, 0x72, 0x46, 0x27, 0x8e, 0x4e, 0x1c, 0x9d,
+	0x8c, 0x74, 0x7a, 0x6d, 0xe7, 0x77, 0x7f, 0x06, 0x00, 0xe3, 0x82, 0xe8, 0xe6, 0xbc, 0x02, 0x00,
+	0x00,
 }
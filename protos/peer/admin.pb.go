@@ -11,6 +11,7 @@ It is generated from these files:
 	peer/chaincode_shim.proto
 	peer/configuration.proto
 	peer/events.proto
+	peer/gateway.proto
 	peer/peer.proto
 	peer/proposal.proto
 	peer/proposal_response.proto
@@ -22,6 +23,13 @@ It has these top-level messages:
 	ServerStatus
 	LogLevelRequest
 	LogLevelResponse
+	PolicyExplanationRequest
+	IndexDeploymentsRequest
+	IndexDeployment
+	IndexDeployments
+	ChaincodeImagesRequest
+	ChaincodeImage
+	ChaincodeImages
 	ChaincodeID
 	ChaincodeInput
 	ChaincodeSpec
@@ -39,6 +47,9 @@ It has these top-level messages:
 	QueryResponse
 	AnchorPeers
 	AnchorPeer
+	TxIDWindow
+	Capabilities
+	Capability
 	ChaincodeReg
 	Interest
 	Register
@@ -46,6 +57,10 @@ It has these top-level messages:
 	Unregister
 	SignedEvent
 	Event
+	SubmitRequest
+	SubmitResponse
+	PreparedTransaction
+	TransactionStatus
 	PeerID
 	PeerEndpoint
 	SignedProposal
@@ -75,6 +90,9 @@ import proto "github.com/golang/protobuf/proto"
 import fmt "fmt"
 import math "math"
 import google_protobuf "github.com/golang/protobuf/ptypes/empty"
+import google_protobuf1 "github.com/golang/protobuf/ptypes/timestamp"
+import common "github.com/hyperledger/fabric/protos/common"
+import common2 "github.com/hyperledger/fabric/protos/common"
 
 import (
 	context "golang.org/x/net/context"
@@ -125,6 +143,57 @@ func (x ServerStatus_StatusCode) String() string {
 }
 func (ServerStatus_StatusCode) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{0, 0} }
 
+type IndexDeployment_State int32
+
+const (
+	IndexDeployment_PENDING_INSTALL IndexDeployment_State = 0
+	IndexDeployment_DEPLOYED        IndexDeployment_State = 1
+	IndexDeployment_FAILED          IndexDeployment_State = 2
+)
+
+var IndexDeployment_State_name = map[int32]string{
+	0: "PENDING_INSTALL",
+	1: "DEPLOYED",
+	2: "FAILED",
+}
+var IndexDeployment_State_value = map[string]int32{
+	"PENDING_INSTALL": 0,
+	"DEPLOYED":        1,
+	"FAILED":          2,
+}
+
+func (x IndexDeployment_State) String() string {
+	return proto.EnumName(IndexDeployment_State_name, int32(x))
+}
+func (IndexDeployment_State) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{5, 0} }
+
+type ChaincodeImage_State int32
+
+const (
+	ChaincodeImage_EXISTING ChaincodeImage_State = 0
+	ChaincodeImage_BUILT    ChaincodeImage_State = 1
+	ChaincodeImage_REMOVED  ChaincodeImage_State = 2
+	ChaincodeImage_FAILED   ChaincodeImage_State = 3
+)
+
+var ChaincodeImage_State_name = map[int32]string{
+	0: "EXISTING",
+	1: "BUILT",
+	2: "REMOVED",
+	3: "FAILED",
+}
+var ChaincodeImage_State_value = map[string]int32{
+	"EXISTING": 0,
+	"BUILT":    1,
+	"REMOVED":  2,
+	"FAILED":   3,
+}
+
+func (x ChaincodeImage_State) String() string {
+	return proto.EnumName(ChaincodeImage_State_name, int32(x))
+}
+func (ChaincodeImage_State) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{8, 0} }
+
 type ServerStatus struct {
 	Status ServerStatus_StatusCode `protobuf:"varint,1,opt,name=status,enum=protos.ServerStatus_StatusCode" json:"status,omitempty"`
 }
@@ -189,11 +258,223 @@ func (m *LogLevelResponse) GetLogLevel() string {
 	return ""
 }
 
+type PolicyExplanationRequest struct {
+	ChannelId string           `protobuf:"bytes,1,opt,name=channel_id,json=channelId" json:"channel_id,omitempty"`
+	Policy    string           `protobuf:"bytes,2,opt,name=policy" json:"policy,omitempty"`
+	Envelope  *common.Envelope `protobuf:"bytes,3,opt,name=envelope" json:"envelope,omitempty"`
+}
+
+func (m *PolicyExplanationRequest) Reset()                    { *m = PolicyExplanationRequest{} }
+func (m *PolicyExplanationRequest) String() string            { return proto.CompactTextString(m) }
+func (*PolicyExplanationRequest) ProtoMessage()               {}
+func (*PolicyExplanationRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
+func (m *PolicyExplanationRequest) GetChannelId() string {
+	if m != nil {
+		return m.ChannelId
+	}
+	return ""
+}
+
+func (m *PolicyExplanationRequest) GetPolicy() string {
+	if m != nil {
+		return m.Policy
+	}
+	return ""
+}
+
+func (m *PolicyExplanationRequest) GetEnvelope() *common.Envelope {
+	if m != nil {
+		return m.Envelope
+	}
+	return nil
+}
+
+type IndexDeploymentsRequest struct {
+	ChannelId string `protobuf:"bytes,1,opt,name=channel_id,json=channelId" json:"channel_id,omitempty"`
+}
+
+func (m *IndexDeploymentsRequest) Reset()                    { *m = IndexDeploymentsRequest{} }
+func (m *IndexDeploymentsRequest) String() string            { return proto.CompactTextString(m) }
+func (*IndexDeploymentsRequest) ProtoMessage()               {}
+func (*IndexDeploymentsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+
+func (m *IndexDeploymentsRequest) GetChannelId() string {
+	if m != nil {
+		return m.ChannelId
+	}
+	return ""
+}
+
+// IndexDeployment is the status of the deployment of the state database indexes
+// shipped in the package of a chaincode deployed on a channel
+type IndexDeployment struct {
+	ChaincodeName    string                      `protobuf:"bytes,1,opt,name=chaincode_name,json=chaincodeName" json:"chaincode_name,omitempty"`
+	ChaincodeVersion string                      `protobuf:"bytes,2,opt,name=chaincode_version,json=chaincodeVersion" json:"chaincode_version,omitempty"`
+	State            IndexDeployment_State       `protobuf:"varint,3,opt,name=state,enum=protos.IndexDeployment_State" json:"state,omitempty"`
+	Indexes          []string                    `protobuf:"bytes,4,rep,name=indexes" json:"indexes,omitempty"`
+	Error            string                      `protobuf:"bytes,5,opt,name=error" json:"error,omitempty"`
+	Timestamp        *google_protobuf1.Timestamp `protobuf:"bytes,6,opt,name=timestamp" json:"timestamp,omitempty"`
+}
+
+func (m *IndexDeployment) Reset()                    { *m = IndexDeployment{} }
+func (m *IndexDeployment) String() string            { return proto.CompactTextString(m) }
+func (*IndexDeployment) ProtoMessage()               {}
+func (*IndexDeployment) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+
+func (m *IndexDeployment) GetChaincodeName() string {
+	if m != nil {
+		return m.ChaincodeName
+	}
+	return ""
+}
+
+func (m *IndexDeployment) GetChaincodeVersion() string {
+	if m != nil {
+		return m.ChaincodeVersion
+	}
+	return ""
+}
+
+func (m *IndexDeployment) GetState() IndexDeployment_State {
+	if m != nil {
+		return m.State
+	}
+	return IndexDeployment_PENDING_INSTALL
+}
+
+func (m *IndexDeployment) GetIndexes() []string {
+	if m != nil {
+		return m.Indexes
+	}
+	return nil
+}
+
+func (m *IndexDeployment) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *IndexDeployment) GetTimestamp() *google_protobuf1.Timestamp {
+	if m != nil {
+		return m.Timestamp
+	}
+	return nil
+}
+
+type IndexDeployments struct {
+	Deployments []*IndexDeployment `protobuf:"bytes,1,rep,name=deployments" json:"deployments,omitempty"`
+}
+
+func (m *IndexDeployments) Reset()                    { *m = IndexDeployments{} }
+func (m *IndexDeployments) String() string            { return proto.CompactTextString(m) }
+func (*IndexDeployments) ProtoMessage()               {}
+func (*IndexDeployments) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+
+func (m *IndexDeployments) GetDeployments() []*IndexDeployment {
+	if m != nil {
+		return m.Deployments
+	}
+	return nil
+}
+
+type ChaincodeImagesRequest struct {
+	ChaincodeName    string `protobuf:"bytes,1,opt,name=chaincode_name,json=chaincodeName" json:"chaincode_name,omitempty"`
+	ChaincodeVersion string `protobuf:"bytes,2,opt,name=chaincode_version,json=chaincodeVersion" json:"chaincode_version,omitempty"`
+}
+
+func (m *ChaincodeImagesRequest) Reset()                    { *m = ChaincodeImagesRequest{} }
+func (m *ChaincodeImagesRequest) String() string            { return proto.CompactTextString(m) }
+func (*ChaincodeImagesRequest) ProtoMessage()               {}
+func (*ChaincodeImagesRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+
+func (m *ChaincodeImagesRequest) GetChaincodeName() string {
+	if m != nil {
+		return m.ChaincodeName
+	}
+	return ""
+}
+
+func (m *ChaincodeImagesRequest) GetChaincodeVersion() string {
+	if m != nil {
+		return m.ChaincodeVersion
+	}
+	return ""
+}
+
+// ChaincodeImage is the outcome of the build, or of the removal, of the image of a chaincode
+type ChaincodeImage struct {
+	ChaincodeName    string               `protobuf:"bytes,1,opt,name=chaincode_name,json=chaincodeName" json:"chaincode_name,omitempty"`
+	ChaincodeVersion string               `protobuf:"bytes,2,opt,name=chaincode_version,json=chaincodeVersion" json:"chaincode_version,omitempty"`
+	State            ChaincodeImage_State `protobuf:"varint,3,opt,name=state,enum=protos.ChaincodeImage_State" json:"state,omitempty"`
+	Error            string               `protobuf:"bytes,4,opt,name=error" json:"error,omitempty"`
+}
+
+func (m *ChaincodeImage) Reset()                    { *m = ChaincodeImage{} }
+func (m *ChaincodeImage) String() string            { return proto.CompactTextString(m) }
+func (*ChaincodeImage) ProtoMessage()               {}
+func (*ChaincodeImage) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+
+func (m *ChaincodeImage) GetChaincodeName() string {
+	if m != nil {
+		return m.ChaincodeName
+	}
+	return ""
+}
+
+func (m *ChaincodeImage) GetChaincodeVersion() string {
+	if m != nil {
+		return m.ChaincodeVersion
+	}
+	return ""
+}
+
+func (m *ChaincodeImage) GetState() ChaincodeImage_State {
+	if m != nil {
+		return m.State
+	}
+	return ChaincodeImage_EXISTING
+}
+
+func (m *ChaincodeImage) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type ChaincodeImages struct {
+	Images []*ChaincodeImage `protobuf:"bytes,1,rep,name=images" json:"images,omitempty"`
+}
+
+func (m *ChaincodeImages) Reset()                    { *m = ChaincodeImages{} }
+func (m *ChaincodeImages) String() string            { return proto.CompactTextString(m) }
+func (*ChaincodeImages) ProtoMessage()               {}
+func (*ChaincodeImages) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+
+func (m *ChaincodeImages) GetImages() []*ChaincodeImage {
+	if m != nil {
+		return m.Images
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*ServerStatus)(nil), "protos.ServerStatus")
 	proto.RegisterType((*LogLevelRequest)(nil), "protos.LogLevelRequest")
 	proto.RegisterType((*LogLevelResponse)(nil), "protos.LogLevelResponse")
+	proto.RegisterType((*PolicyExplanationRequest)(nil), "protos.PolicyExplanationRequest")
+	proto.RegisterType((*IndexDeploymentsRequest)(nil), "protos.IndexDeploymentsRequest")
+	proto.RegisterType((*IndexDeployment)(nil), "protos.IndexDeployment")
+	proto.RegisterType((*IndexDeployments)(nil), "protos.IndexDeployments")
+	proto.RegisterType((*ChaincodeImagesRequest)(nil), "protos.ChaincodeImagesRequest")
+	proto.RegisterType((*ChaincodeImage)(nil), "protos.ChaincodeImage")
+	proto.RegisterType((*ChaincodeImages)(nil), "protos.ChaincodeImages")
 	proto.RegisterEnum("protos.ServerStatus_StatusCode", ServerStatus_StatusCode_name, ServerStatus_StatusCode_value)
+	proto.RegisterEnum("protos.IndexDeployment_State", IndexDeployment_State_name, IndexDeployment_State_value)
+	proto.RegisterEnum("protos.ChaincodeImage_State", ChaincodeImage_State_name, ChaincodeImage_State_value)
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -213,6 +494,16 @@ type AdminClient interface {
 	GetModuleLogLevel(ctx context.Context, in *LogLevelRequest, opts ...grpc.CallOption) (*LogLevelResponse, error)
 	SetModuleLogLevel(ctx context.Context, in *LogLevelRequest, opts ...grpc.CallOption) (*LogLevelResponse, error)
 	RevertLogLevels(ctx context.Context, in *google_protobuf.Empty, opts ...grpc.CallOption) (*google_protobuf.Empty, error)
+	// Compact the local databases backing the ledgers.
+	CompactLedgers(ctx context.Context, in *google_protobuf.Empty, opts ...grpc.CallOption) (*google_protobuf.Empty, error)
+	// Explain the decision of a policy of a channel over the signed data of an envelope.
+	ExplainPolicy(ctx context.Context, in *PolicyExplanationRequest, opts ...grpc.CallOption) (*common2.PolicyDecision, error)
+	// Return the status of the deployment of the indexes of the chaincodes of a channel.
+	GetIndexDeployments(ctx context.Context, in *IndexDeploymentsRequest, opts ...grpc.CallOption) (*IndexDeployments, error)
+	// Build the images of the chaincodes installed on the peer ahead of their launch.
+	BuildChaincodeImages(ctx context.Context, in *ChaincodeImagesRequest, opts ...grpc.CallOption) (*ChaincodeImages, error)
+	// Remove the images built by the peer of the chaincodes no longer installed.
+	RemoveStaleChaincodeImages(ctx context.Context, in *google_protobuf.Empty, opts ...grpc.CallOption) (*ChaincodeImages, error)
 }
 
 type adminClient struct {
@@ -268,6 +559,51 @@ func (c *adminClient) RevertLogLevels(ctx context.Context, in *google_protobuf.E
 	return out, nil
 }
 
+func (c *adminClient) CompactLedgers(ctx context.Context, in *google_protobuf.Empty, opts ...grpc.CallOption) (*google_protobuf.Empty, error) {
+	out := new(google_protobuf.Empty)
+	err := grpc.Invoke(ctx, "/protos.Admin/CompactLedgers", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ExplainPolicy(ctx context.Context, in *PolicyExplanationRequest, opts ...grpc.CallOption) (*common2.PolicyDecision, error) {
+	out := new(common2.PolicyDecision)
+	err := grpc.Invoke(ctx, "/protos.Admin/ExplainPolicy", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) GetIndexDeployments(ctx context.Context, in *IndexDeploymentsRequest, opts ...grpc.CallOption) (*IndexDeployments, error) {
+	out := new(IndexDeployments)
+	err := grpc.Invoke(ctx, "/protos.Admin/GetIndexDeployments", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) BuildChaincodeImages(ctx context.Context, in *ChaincodeImagesRequest, opts ...grpc.CallOption) (*ChaincodeImages, error) {
+	out := new(ChaincodeImages)
+	err := grpc.Invoke(ctx, "/protos.Admin/BuildChaincodeImages", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) RemoveStaleChaincodeImages(ctx context.Context, in *google_protobuf.Empty, opts ...grpc.CallOption) (*ChaincodeImages, error) {
+	out := new(ChaincodeImages)
+	err := grpc.Invoke(ctx, "/protos.Admin/RemoveStaleChaincodeImages", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for Admin service
 
 type AdminServer interface {
@@ -277,6 +613,16 @@ type AdminServer interface {
 	GetModuleLogLevel(context.Context, *LogLevelRequest) (*LogLevelResponse, error)
 	SetModuleLogLevel(context.Context, *LogLevelRequest) (*LogLevelResponse, error)
 	RevertLogLevels(context.Context, *google_protobuf.Empty) (*google_protobuf.Empty, error)
+	// Compact the local databases backing the ledgers.
+	CompactLedgers(context.Context, *google_protobuf.Empty) (*google_protobuf.Empty, error)
+	// Explain the decision of a policy of a channel over the signed data of an envelope.
+	ExplainPolicy(context.Context, *PolicyExplanationRequest) (*common2.PolicyDecision, error)
+	// Return the status of the deployment of the indexes of the chaincodes of a channel.
+	GetIndexDeployments(context.Context, *IndexDeploymentsRequest) (*IndexDeployments, error)
+	// Build the images of the chaincodes installed on the peer ahead of their launch.
+	BuildChaincodeImages(context.Context, *ChaincodeImagesRequest) (*ChaincodeImages, error)
+	// Remove the images built by the peer of the chaincodes no longer installed.
+	RemoveStaleChaincodeImages(context.Context, *google_protobuf.Empty) (*ChaincodeImages, error)
 }
 
 func RegisterAdminServer(s *grpc.Server, srv AdminServer) {
@@ -373,6 +719,96 @@ func _Admin_RevertLogLevels_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Admin_CompactLedgers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(google_protobuf.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).CompactLedgers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/protos.Admin/CompactLedgers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).CompactLedgers(ctx, req.(*google_protobuf.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_ExplainPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PolicyExplanationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ExplainPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/protos.Admin/ExplainPolicy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ExplainPolicy(ctx, req.(*PolicyExplanationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_GetIndexDeployments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IndexDeploymentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).GetIndexDeployments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/protos.Admin/GetIndexDeployments",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).GetIndexDeployments(ctx, req.(*IndexDeploymentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_BuildChaincodeImages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChaincodeImagesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).BuildChaincodeImages(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/protos.Admin/BuildChaincodeImages",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).BuildChaincodeImages(ctx, req.(*ChaincodeImagesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_RemoveStaleChaincodeImages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(google_protobuf.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).RemoveStaleChaincodeImages(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/protos.Admin/RemoveStaleChaincodeImages",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).RemoveStaleChaincodeImages(ctx, req.(*google_protobuf.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Admin_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "protos.Admin",
 	HandlerType: (*AdminServer)(nil),
@@ -397,6 +833,26 @@ var _Admin_serviceDesc = grpc.ServiceDesc{
 			MethodName: "RevertLogLevels",
 			Handler:    _Admin_RevertLogLevels_Handler,
 		},
+		{
+			MethodName: "CompactLedgers",
+			Handler:    _Admin_CompactLedgers_Handler,
+		},
+		{
+			MethodName: "ExplainPolicy",
+			Handler:    _Admin_ExplainPolicy_Handler,
+		},
+		{
+			MethodName: "GetIndexDeployments",
+			Handler:    _Admin_GetIndexDeployments_Handler,
+		},
+		{
+			MethodName: "BuildChaincodeImages",
+			Handler:    _Admin_BuildChaincodeImages_Handler,
+		},
+		{
+			MethodName: "RemoveStaleChaincodeImages",
+			Handler:    _Admin_RemoveStaleChaincodeImages_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "peer/admin.proto",
@@ -405,32 +861,62 @@ var _Admin_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("peer/admin.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 418 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xac, 0x93, 0x41, 0x6f, 0xd3, 0x30,
-	0x14, 0xc7, 0x9b, 0x42, 0x0b, 0x79, 0x1b, 0xcc, 0x58, 0x08, 0xaa, 0x4e, 0x08, 0x94, 0x13, 0x5c,
-	0x1c, 0x69, 0x1c, 0x38, 0x20, 0x0e, 0xdd, 0x12, 0x06, 0x62, 0x4b, 0x23, 0x67, 0x15, 0x02, 0x09,
-	0x4d, 0x49, 0xf3, 0xe6, 0x55, 0x38, 0x73, 0xb0, 0x9d, 0x4a, 0xfb, 0x3a, 0x7c, 0x2e, 0x3e, 0x0c,
-	0x4a, 0xdc, 0x68, 0x13, 0xb0, 0x03, 0x82, 0x93, 0xf3, 0xde, 0xfb, 0xff, 0xff, 0x71, 0x7e, 0xb1,
-	0x81, 0xd4, 0x88, 0x3a, 0xcc, 0xcb, 0x6a, 0x75, 0xc1, 0x6a, 0xad, 0xac, 0xa2, 0xe3, 0x6e, 0x31,
-	0xd3, 0x5d, 0xa1, 0x94, 0x90, 0x18, 0x76, 0x65, 0xd1, 0x9c, 0x85, 0x58, 0xd5, 0xf6, 0xd2, 0x89,
-	0x82, 0xef, 0x1e, 0x6c, 0x67, 0xa8, 0xd7, 0xa8, 0x33, 0x9b, 0xdb, 0xc6, 0xd0, 0x57, 0x30, 0x36,
-	0xdd, 0xd3, 0xc4, 0x7b, 0xe6, 0x3d, 0xbf, 0xbf, 0xf7, 0xd4, 0x09, 0x0d, 0xbb, 0xae, 0x62, 0x6e,
-	0x39, 0x50, 0x25, 0xf2, 0x8d, 0x3c, 0xf8, 0x04, 0x70, 0xd5, 0xa5, 0xf7, 0xc0, 0x5f, 0x24, 0x51,
-	0xfc, 0xf6, 0x7d, 0x12, 0x47, 0x64, 0x40, 0xb7, 0xe0, 0x4e, 0x76, 0x32, 0xe3, 0x27, 0x71, 0x44,
-	0x3c, 0x57, 0xcc, 0xd3, 0x34, 0x8e, 0xc8, 0x90, 0x02, 0x8c, 0xd3, 0xd9, 0x22, 0x8b, 0x23, 0x72,
-	0x8b, 0xfa, 0x30, 0x8a, 0x39, 0x9f, 0x73, 0x72, 0xbb, 0xd5, 0x2c, 0x92, 0x0f, 0xc9, 0xfc, 0x63,
-	0x42, 0x46, 0xc1, 0x31, 0xec, 0x1c, 0x29, 0x71, 0x84, 0x6b, 0x94, 0x1c, 0xbf, 0x35, 0x68, 0x2c,
-	0x7d, 0x02, 0x20, 0x95, 0x38, 0xad, 0x54, 0xd9, 0x48, 0xec, 0xb6, 0xea, 0x73, 0x5f, 0x2a, 0x71,
-	0xdc, 0x35, 0xe8, 0x2e, 0xb4, 0xc5, 0xa9, 0x6c, 0x2d, 0x93, 0x61, 0x37, 0xbd, 0x2b, 0x37, 0x11,
-	0x41, 0x02, 0xe4, 0x2a, 0xce, 0xd4, 0xea, 0xc2, 0xe0, 0xbf, 0xe4, 0xed, 0xfd, 0x18, 0xc2, 0x68,
-	0xd6, 0x82, 0xa7, 0xaf, 0xc1, 0x3f, 0x44, 0xbb, 0x21, 0xf9, 0x88, 0x39, 0xf0, 0xac, 0x07, 0xcf,
-	0xe2, 0x16, 0xfc, 0xf4, 0xe1, 0x9f, 0x88, 0x06, 0x03, 0xfa, 0x06, 0xb6, 0x32, 0x9b, 0x6b, 0xeb,
-	0xda, 0x7f, 0x6d, 0x7f, 0x07, 0x0f, 0x0e, 0xd1, 0xba, 0xfd, 0xf6, 0x9f, 0x47, 0x1f, 0xf7, 0xe2,
-	0x5f, 0xf8, 0x4d, 0x27, 0xbf, 0x0f, 0x1c, 0x09, 0x97, 0x94, 0xfd, 0x9f, 0xa4, 0x03, 0xd8, 0xe1,
-	0xb8, 0x46, 0x6d, 0xfb, 0xd9, 0xcd, 0x54, 0x6e, 0xe8, 0x07, 0x83, 0xfd, 0x2f, 0x10, 0x28, 0x2d,
-	0xd8, 0xf9, 0x65, 0x8d, 0x5a, 0x62, 0x29, 0x50, 0xb3, 0xb3, 0xbc, 0xd0, 0xab, 0x65, 0xff, 0xe2,
-	0xf6, 0xe4, 0xef, 0x6f, 0x77, 0x7f, 0x20, 0xcd, 0x97, 0x5f, 0x73, 0x81, 0x9f, 0x5f, 0x88, 0x95,
-	0x3d, 0x6f, 0x0a, 0xb6, 0x54, 0x55, 0x78, 0xcd, 0x18, 0x3a, 0xa3, 0xbb, 0x0a, 0x26, 0x6c, 0x8d,
-	0x85, 0xbb, 0x26, 0x2f, 0x7f, 0x06, 0x00, 0x00, 0xff, 0xff, 0x6e, 0xe6, 0xef, 0xb7, 0x41, 0x03,
-	0x00, 0x00,
+	// 907 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xb4, 0x56, 0xdd, 0x6e, 0xe2, 0x56,
+	0x10, 0x86, 0x25, 0xb0, 0x61, 0x48, 0xc0, 0x7b, 0x92, 0x12, 0x8b, 0xed, 0x36, 0xc8, 0x52, 0xa5,
+	0x54, 0xad, 0x8c, 0xc4, 0x4a, 0xed, 0xae, 0xaa, 0x5e, 0x40, 0xf0, 0x52, 0x6b, 0x89, 0xa1, 0x36,
+	0xd9, 0x76, 0x2b, 0x55, 0xc8, 0xb1, 0x67, 0x1d, 0xab, 0xb6, 0x8f, 0x6b, 0x1f, 0xd0, 0x72, 0xd5,
+	0x77, 0xe9, 0x73, 0xf4, 0x75, 0x7a, 0xd1, 0xb7, 0xa8, 0xec, 0x63, 0x03, 0x71, 0x82, 0xd4, 0xbf,
+	0xbd, 0xb2, 0x67, 0xe6, 0x9b, 0x39, 0xc7, 0xf3, 0xcd, 0x7c, 0x32, 0x08, 0x21, 0x62, 0xd4, 0x33,
+	0x6d, 0xdf, 0x0d, 0xe4, 0x30, 0xa2, 0x8c, 0x92, 0x5a, 0xfa, 0x88, 0x3b, 0x4f, 0x1d, 0x4a, 0x1d,
+	0x0f, 0x7b, 0xa9, 0x79, 0xb3, 0x7c, 0xd7, 0x43, 0x3f, 0x64, 0x6b, 0x0e, 0xea, 0x9c, 0x17, 0x83,
+	0xcc, 0xf5, 0x31, 0x66, 0xa6, 0x1f, 0x66, 0x80, 0x13, 0x8b, 0xfa, 0x3e, 0x0d, 0x7a, 0xfc, 0x91,
+	0x39, 0x3f, 0xca, 0x9c, 0x21, 0xf5, 0x5c, 0xcb, 0xc5, 0x98, 0xbb, 0xa5, 0xdf, 0xca, 0x70, 0x64,
+	0x60, 0xb4, 0xc2, 0xc8, 0x60, 0x26, 0x5b, 0xc6, 0xe4, 0x2b, 0xa8, 0xc5, 0xe9, 0x9b, 0x58, 0xee,
+	0x96, 0x2f, 0x9a, 0xfd, 0x73, 0x0e, 0x8c, 0xe5, 0x5d, 0x94, 0xcc, 0x1f, 0x97, 0xd4, 0x46, 0x3d,
+	0x83, 0x4b, 0x6f, 0x01, 0xb6, 0x5e, 0x72, 0x0c, 0xf5, 0x6b, 0x6d, 0xa4, 0xbc, 0x52, 0x35, 0x65,
+	0x24, 0x94, 0x48, 0x03, 0x1e, 0x1b, 0xf3, 0x81, 0x3e, 0x57, 0x46, 0x42, 0x99, 0x1b, 0xd3, 0xd9,
+	0x4c, 0x19, 0x09, 0x8f, 0x08, 0x40, 0x6d, 0x36, 0xb8, 0x36, 0x94, 0x91, 0x50, 0x21, 0x75, 0xa8,
+	0x2a, 0xba, 0x3e, 0xd5, 0x85, 0x83, 0x04, 0x73, 0xad, 0xbd, 0xd6, 0xa6, 0xdf, 0x6b, 0x42, 0x55,
+	0xba, 0x82, 0xd6, 0x84, 0x3a, 0x13, 0x5c, 0xa1, 0xa7, 0xe3, 0x2f, 0x4b, 0x8c, 0x19, 0x79, 0x06,
+	0xe0, 0x51, 0x67, 0xe1, 0x53, 0x7b, 0xe9, 0x61, 0x7a, 0xd5, 0xba, 0x5e, 0xf7, 0xa8, 0x73, 0x95,
+	0x3a, 0xc8, 0x53, 0x48, 0x8c, 0x85, 0x97, 0xa4, 0x88, 0x8f, 0xd2, 0xe8, 0xa1, 0x97, 0x95, 0x90,
+	0x34, 0x10, 0xb6, 0xe5, 0xe2, 0x90, 0x06, 0x31, 0xfe, 0xa7, 0x7a, 0xbf, 0x82, 0x38, 0x4b, 0xba,
+	0xba, 0x56, 0xde, 0x87, 0x9e, 0x19, 0x98, 0xcc, 0xa5, 0xc1, 0xce, 0x3d, 0xad, 0x5b, 0x33, 0x08,
+	0xd0, 0x5b, 0xb8, 0x76, 0x5e, 0x37, 0xf3, 0xa8, 0x36, 0x69, 0x43, 0x2d, 0x25, 0x64, 0x9d, 0x15,
+	0xcd, 0x2c, 0xf2, 0x05, 0x1c, 0x62, 0xb0, 0x42, 0x8f, 0x86, 0x28, 0x56, 0xba, 0xe5, 0x8b, 0x46,
+	0x5f, 0x90, 0x33, 0x3a, 0x95, 0xcc, 0xaf, 0x6f, 0x10, 0xd2, 0x0b, 0x38, 0x53, 0x03, 0x1b, 0xdf,
+	0x8f, 0x30, 0xf4, 0xe8, 0xda, 0xc7, 0x80, 0xc5, 0x7f, 0xef, 0x7c, 0xe9, 0xf7, 0x47, 0xd0, 0x2a,
+	0xa4, 0x92, 0x4f, 0xa1, 0x69, 0xdd, 0x9a, 0x6e, 0x60, 0x51, 0x1b, 0x17, 0x81, 0xe9, 0xe7, 0xed,
+	0x38, 0xde, 0x78, 0x35, 0xd3, 0x47, 0xf2, 0x39, 0x3c, 0xd9, 0xc2, 0x56, 0x18, 0xc5, 0x2e, 0x0d,
+	0xb2, 0xaf, 0x10, 0x36, 0x81, 0x37, 0xdc, 0x4f, 0x9e, 0x43, 0x35, 0x19, 0x13, 0xfe, 0x31, 0xcd,
+	0xfe, 0xb3, 0x7c, 0xa8, 0x0a, 0x67, 0xa7, 0x73, 0x85, 0x3a, 0xc7, 0x12, 0x11, 0x1e, 0xbb, 0x49,
+	0x1c, 0x63, 0xf1, 0xa0, 0x5b, 0xb9, 0xa8, 0xeb, 0xb9, 0x49, 0x4e, 0xa1, 0x8a, 0x51, 0x44, 0x23,
+	0xb1, 0x9a, 0x9e, 0xc7, 0x0d, 0xf2, 0x02, 0xea, 0x9b, 0x55, 0x10, 0x6b, 0x69, 0xd7, 0x3a, 0x32,
+	0x5f, 0x16, 0x39, 0x5f, 0x16, 0x79, 0x9e, 0x23, 0xf4, 0x2d, 0x58, 0xfa, 0x12, 0xaa, 0xe9, 0xc9,
+	0xe4, 0x04, 0x5a, 0x33, 0x45, 0x1b, 0xa9, 0xda, 0x78, 0xa1, 0x6a, 0xc6, 0x7c, 0x30, 0x99, 0x08,
+	0x25, 0x72, 0x04, 0x87, 0x23, 0x65, 0x36, 0x99, 0xbe, 0x4d, 0xa7, 0x17, 0xa0, 0xf6, 0x6a, 0xa0,
+	0x4e, 0x92, 0xe1, 0x95, 0xae, 0x40, 0x28, 0x36, 0x9e, 0xbc, 0x84, 0x86, 0xbd, 0x35, 0xc5, 0x72,
+	0xb7, 0x72, 0xd1, 0xe8, 0x9f, 0xed, 0xf9, 0x60, 0x7d, 0x17, 0x2b, 0x79, 0xd0, 0xbe, 0xcc, 0x3b,
+	0xa7, 0xfa, 0xa6, 0x83, 0x1b, 0x1a, 0x3f, 0x00, 0x27, 0xd2, 0x9f, 0x65, 0x68, 0xde, 0x3d, 0xee,
+	0x83, 0x50, 0xdf, 0xbf, 0x4b, 0xfd, 0xc7, 0x79, 0x27, 0xee, 0x1e, 0x7d, 0x97, 0xf9, 0x0d, 0xbf,
+	0x07, 0x3b, 0xfc, 0x4a, 0x2f, 0x73, 0x96, 0x8e, 0xe0, 0x50, 0xf9, 0x41, 0x35, 0xe6, 0xaa, 0x36,
+	0x16, 0x4a, 0x89, 0x6a, 0x0c, 0xaf, 0xd5, 0xc9, 0x9c, 0x2b, 0x8b, 0xae, 0x5c, 0x4d, 0xdf, 0xe4,
+	0xca, 0x92, 0x11, 0x55, 0x91, 0x06, 0xd0, 0x2a, 0x74, 0x96, 0xc8, 0x50, 0x73, 0xd3, 0xb7, 0x8c,
+	0xa2, 0xf6, 0xc3, 0x17, 0xd3, 0x33, 0x54, 0xff, 0x8f, 0x2a, 0x54, 0x07, 0x89, 0x56, 0x93, 0xaf,
+	0xa1, 0x3e, 0x46, 0x96, 0xe9, 0x65, 0xfb, 0xde, 0x84, 0x29, 0x89, 0x56, 0x77, 0x4e, 0x1f, 0xd2,
+	0x4d, 0xa9, 0x44, 0xbe, 0x81, 0x86, 0xc1, 0xcc, 0x88, 0x71, 0xf7, 0x3f, 0x4e, 0xff, 0x16, 0x9e,
+	0x8c, 0x91, 0x71, 0x55, 0xca, 0x45, 0x8c, 0x6c, 0xa6, 0xab, 0xa0, 0x92, 0x1d, 0xf1, 0x7e, 0x80,
+	0xeb, 0x1d, 0xaf, 0x64, 0xfc, 0x3f, 0x95, 0x2e, 0xa1, 0xa5, 0xe3, 0x0a, 0x23, 0x96, 0xc7, 0xf6,
+	0x77, 0x65, 0x8f, 0x5f, 0x2a, 0x91, 0x21, 0x34, 0x2f, 0xa9, 0x1f, 0x9a, 0x16, 0x9b, 0xa0, 0xed,
+	0x60, 0xf4, 0x6f, 0x6a, 0xbc, 0x86, 0xe3, 0x54, 0x82, 0xdd, 0x80, 0xeb, 0x31, 0xe9, 0xe6, 0xb7,
+	0xde, 0xa7, 0xcf, 0x9d, 0x76, 0x2e, 0xab, 0x1c, 0x31, 0x42, 0xcb, 0x4d, 0x97, 0xa3, 0x44, 0x74,
+	0x38, 0x19, 0x23, 0xbb, 0xb7, 0xde, 0xe7, 0x7b, 0x36, 0x39, 0xbe, 0xd7, 0xa9, 0x22, 0x40, 0x2a,
+	0x91, 0xef, 0xe0, 0x74, 0xb8, 0x74, 0x3d, 0xbb, 0x38, 0x8b, 0x9f, 0x3c, 0x3c, 0x7b, 0x9b, 0x9a,
+	0x67, 0x7b, 0xe2, 0x52, 0x89, 0x4c, 0xa1, 0xa3, 0xa3, 0x4f, 0x57, 0x68, 0x30, 0xd3, 0xc3, 0x62,
+	0xe1, 0x7d, 0x3d, 0xdc, 0x5f, 0x70, 0xf8, 0x13, 0x48, 0x34, 0x72, 0xe4, 0xdb, 0x75, 0x88, 0x91,
+	0x97, 0x52, 0x21, 0xbf, 0x33, 0x6f, 0x22, 0xd7, 0xca, 0x53, 0x92, 0xbf, 0x96, 0xe1, 0x51, 0xba,
+	0x0a, 0x33, 0xd3, 0xfa, 0xd9, 0x74, 0xf0, 0xc7, 0xcf, 0x1c, 0x97, 0xdd, 0x2e, 0x6f, 0x92, 0x5e,
+	0xf6, 0x76, 0x12, 0x7b, 0x3c, 0x91, 0xff, 0xa9, 0xc4, 0xbd, 0x24, 0xf1, 0x86, 0xff, 0xe2, 0x3c,
+	0xff, 0x6b, 0x00, 0x39, 0x67, 0x74, 0xd4, 0xfd, 0x08, 0x00, 0x00,
 }
@@ -54,26 +54,73 @@ func (m *ChaincodeEvent) GetPayload() []byte {
 	return nil
 }
 
+// ChaincodeEventChunk is the payload of the chaincode events carrying a chunk
+// of a larger event, which is split over the events of several transactions.
+// The chunks of an event share its event_id and are numbered from 0 to count-1
+type ChaincodeEventChunk struct {
+	EventId string `protobuf:"bytes,1,opt,name=event_id,json=eventId" json:"event_id,omitempty"`
+	Index   uint32 `protobuf:"varint,2,opt,name=index" json:"index,omitempty"`
+	Count   uint32 `protobuf:"varint,3,opt,name=count" json:"count,omitempty"`
+	Data    []byte `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (m *ChaincodeEventChunk) Reset()                    { *m = ChaincodeEventChunk{} }
+func (m *ChaincodeEventChunk) String() string            { return proto.CompactTextString(m) }
+func (*ChaincodeEventChunk) ProtoMessage()               {}
+func (*ChaincodeEventChunk) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{1} }
+
+func (m *ChaincodeEventChunk) GetEventId() string {
+	if m != nil {
+		return m.EventId
+	}
+	return ""
+}
+
+func (m *ChaincodeEventChunk) GetIndex() uint32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *ChaincodeEventChunk) GetCount() uint32 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func (m *ChaincodeEventChunk) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*ChaincodeEvent)(nil), "protos.ChaincodeEvent")
+	proto.RegisterType((*ChaincodeEventChunk)(nil), "protos.ChaincodeEventChunk")
 }
 
 func init() { proto.RegisterFile("peer/chaincode_event.proto", fileDescriptor2) }
 
 var fileDescriptor2 = []byte{
-	// 218 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xe2, 0x92, 0x2a, 0x48, 0x4d, 0x2d,
-	0xd2, 0x4f, 0xce, 0x48, 0xcc, 0xcc, 0x4b, 0xce, 0x4f, 0x49, 0x8d, 0x4f, 0x2d, 0x4b, 0xcd, 0x2b,
-	0xd1, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x62, 0x03, 0x53, 0xc5, 0x4a, 0x8d, 0x8c, 0x5c, 0x7c,
-	0xce, 0x30, 0x15, 0xae, 0x20, 0x05, 0x42, 0x8a, 0x5c, 0x3c, 0x08, 0x3d, 0x99, 0x29, 0x12, 0x8c,
-	0x0a, 0x8c, 0x1a, 0x9c, 0x41, 0xdc, 0x70, 0x31, 0xcf, 0x14, 0x21, 0x61, 0x2e, 0xd6, 0x92, 0x0a,
-	0x90, 0x1c, 0x13, 0x58, 0x8e, 0xa5, 0xa4, 0xc2, 0x33, 0x45, 0x48, 0x96, 0x8b, 0x0b, 0x6c, 0x43,
-	0x7c, 0x5e, 0x62, 0x6e, 0xaa, 0x04, 0x33, 0x58, 0x86, 0x13, 0x2c, 0xe2, 0x97, 0x98, 0x9b, 0x2a,
-	0x24, 0xc1, 0xc5, 0x5e, 0x90, 0x58, 0x99, 0x93, 0x9f, 0x98, 0x22, 0xc1, 0xa2, 0xc0, 0xa8, 0xc1,
-	0x13, 0x04, 0xe3, 0x3a, 0xa5, 0x71, 0x29, 0xe5, 0x17, 0xa5, 0xeb, 0x65, 0x54, 0x16, 0xa4, 0x16,
-	0xe5, 0xa4, 0xa6, 0xa4, 0xa7, 0x16, 0xe9, 0xa5, 0x25, 0x26, 0x15, 0x65, 0x26, 0x43, 0xdc, 0x5a,
-	0xac, 0x07, 0xf2, 0x87, 0x93, 0x28, 0xaa, 0x33, 0x03, 0x12, 0x93, 0xb3, 0x13, 0xd3, 0x53, 0xa3,
-	0x34, 0xd3, 0x33, 0x4b, 0x32, 0x4a, 0x93, 0xf4, 0x92, 0xf3, 0x73, 0xf5, 0x91, 0x4c, 0xd0, 0x87,
-	0x98, 0xa0, 0x0f, 0x31, 0x41, 0x1f, 0x64, 0x42, 0x12, 0xc4, 0xcf, 0xc6, 0x80, 0x00, 0x00, 0x00,
-	0xff, 0xff, 0x4a, 0x9d, 0xa8, 0x17, 0x18, 0x01, 0x00, 0x00,
+	// 266 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x54, 0x50, 0xb1, 0x4e, 0xc3, 0x30,
+	0x14, 0x54, 0xa0, 0xa5, 0xf4, 0xd1, 0x32, 0xb8, 0x20, 0x05, 0x24, 0xa4, 0x92, 0xa9, 0x2c, 0xc9,
+	0xc0, 0x1f, 0xb4, 0x62, 0xc8, 0x82, 0x50, 0x46, 0x96, 0xea, 0xc5, 0x7e, 0x4d, 0xa2, 0x36, 0xb6,
+	0xe5, 0x3a, 0x28, 0x1d, 0xf9, 0x73, 0x64, 0x9b, 0x16, 0x3a, 0x25, 0x77, 0xf7, 0x7c, 0x77, 0x3a,
+	0x78, 0xd4, 0x44, 0x26, 0xe3, 0x35, 0x36, 0x92, 0x2b, 0x41, 0x6b, 0xfa, 0x22, 0x69, 0x53, 0x6d,
+	0x94, 0x55, 0xec, 0xca, 0x7f, 0xf6, 0xc9, 0x77, 0x04, 0xb7, 0xab, 0xe3, 0xc5, 0x9b, 0x3b, 0x60,
+	0xcf, 0x30, 0xf9, 0x7b, 0xd3, 0x88, 0x38, 0x9a, 0x47, 0x8b, 0x71, 0x71, 0x73, 0xe2, 0x72, 0xc1,
+	0x66, 0x30, 0xb4, 0xbd, 0xd3, 0x2e, 0xbc, 0x36, 0xb0, 0x7d, 0x2e, 0xd8, 0x13, 0x80, 0x4f, 0x58,
+	0x4b, 0x6c, 0x29, 0xbe, 0xf4, 0xca, 0xd8, 0x33, 0xef, 0xd8, 0x12, 0x8b, 0x61, 0xa4, 0xf1, 0xb0,
+	0x53, 0x28, 0xe2, 0xc1, 0x3c, 0x5a, 0x4c, 0x8a, 0x23, 0x4c, 0x34, 0xcc, 0xce, 0x2b, 0xac, 0xea,
+	0x4e, 0x6e, 0xd9, 0x03, 0x5c, 0x07, 0xbf, 0x53, 0x87, 0x91, 0xc7, 0xb9, 0x60, 0x77, 0x30, 0x6c,
+	0xa4, 0xa0, 0xde, 0xe7, 0x4f, 0x8b, 0x00, 0x1c, 0xcb, 0x55, 0x27, 0xad, 0xcf, 0x9e, 0x16, 0x01,
+	0x30, 0x06, 0x03, 0x81, 0x16, 0x7f, 0x43, 0xfd, 0xff, 0x72, 0x03, 0x89, 0x32, 0x55, 0x5a, 0x1f,
+	0x34, 0x99, 0x1d, 0x89, 0x8a, 0x4c, 0xba, 0xc1, 0xd2, 0x34, 0x3c, 0xac, 0xb3, 0x4f, 0xdd, 0x72,
+	0xcb, 0xfb, 0xf3, 0x56, 0x1f, 0xc8, 0xb7, 0x58, 0xd1, 0xe7, 0x4b, 0xd5, 0xd8, 0xba, 0x2b, 0x53,
+	0xae, 0xda, 0xec, 0x9f, 0x43, 0x16, 0x1c, 0xb2, 0xe0, 0x90, 0x39, 0x87, 0x32, 0xac, 0xfc, 0xfa,
+	0x33, 0x00, 0xe6, 0xe3, 0x2b, 0xb7, 0x8a, 0x01, 0x00, 0x00,
 }
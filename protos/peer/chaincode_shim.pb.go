@@ -90,6 +90,27 @@ func (x ChaincodeMessage_Type) String() string {
 }
 func (ChaincodeMessage_Type) EnumDescriptor() ([]byte, []int) { return fileDescriptor3, []int{0, 0} }
 
+type ChaincodeMetric_Type int32
+
+const (
+	ChaincodeMetric_COUNTER ChaincodeMetric_Type = 0
+	ChaincodeMetric_GAUGE   ChaincodeMetric_Type = 1
+)
+
+var ChaincodeMetric_Type_name = map[int32]string{
+	0: "COUNTER",
+	1: "GAUGE",
+}
+var ChaincodeMetric_Type_value = map[string]int32{
+	"COUNTER": 0,
+	"GAUGE":   1,
+}
+
+func (x ChaincodeMetric_Type) String() string {
+	return proto.EnumName(ChaincodeMetric_Type_name, int32(x))
+}
+func (ChaincodeMetric_Type) EnumDescriptor() ([]byte, []int) { return fileDescriptor3, []int{1, 0} }
+
 type ChaincodeMessage struct {
 	Type      ChaincodeMessage_Type       `protobuf:"varint,1,opt,name=type,enum=protos.ChaincodeMessage_Type" json:"type,omitempty"`
 	Timestamp *google_protobuf1.Timestamp `protobuf:"bytes,2,opt,name=timestamp" json:"timestamp,omitempty"`
@@ -100,6 +121,9 @@ type ChaincodeMessage struct {
 	// This event is then stored (currently)
 	// with Block.NonHashData.TransactionResult
 	ChaincodeEvent *ChaincodeEvent `protobuf:"bytes,6,opt,name=chaincode_event,json=chaincodeEvent" json:"chaincode_event,omitempty"`
+	// Metrics updated by the chaincode through the shim during an Init or an
+	// Invoke, sent with its COMPLETED or ERROR message
+	Metrics []*ChaincodeMetric `protobuf:"bytes,7,rep,name=metrics" json:"metrics,omitempty"`
 }
 
 func (m *ChaincodeMessage) Reset()                    { *m = ChaincodeMessage{} }
@@ -149,6 +173,58 @@ func (m *ChaincodeMessage) GetChaincodeEvent() *ChaincodeEvent {
 	return nil
 }
 
+func (m *ChaincodeMessage) GetMetrics() []*ChaincodeMetric {
+	if m != nil {
+		return m.Metrics
+	}
+	return nil
+}
+
+// ChaincodeMetric is the update of a metric of a chaincode by a transaction.
+// The peer aggregates the metrics of a chaincode under
+// chaincode.<chaincode name>.app.<name>
+type ChaincodeMetric struct {
+	Name string               `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Type ChaincodeMetric_Type `protobuf:"varint,2,opt,name=type,enum=protos.ChaincodeMetric_Type" json:"type,omitempty"`
+	// Amount added to a counter by the transaction
+	Increment int64 `protobuf:"varint,3,opt,name=increment" json:"increment,omitempty"`
+	// Last value set to a gauge by the transaction
+	Value float64 `protobuf:"fixed64,4,opt,name=value" json:"value,omitempty"`
+}
+
+func (m *ChaincodeMetric) Reset()                    { *m = ChaincodeMetric{} }
+func (m *ChaincodeMetric) String() string            { return proto.CompactTextString(m) }
+func (*ChaincodeMetric) ProtoMessage()               {}
+func (*ChaincodeMetric) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{1} }
+
+func (m *ChaincodeMetric) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ChaincodeMetric) GetType() ChaincodeMetric_Type {
+	if m != nil {
+		return m.Type
+	}
+	return ChaincodeMetric_COUNTER
+}
+
+func (m *ChaincodeMetric) GetIncrement() int64 {
+	if m != nil {
+		return m.Increment
+	}
+	return 0
+}
+
+func (m *ChaincodeMetric) GetValue() float64 {
+	if m != nil {
+		return m.Value
+	}
+	return 0
+}
+
 type PutStateInfo struct {
 	Key   string `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
 	Value []byte `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
@@ -157,7 +233,7 @@ type PutStateInfo struct {
 func (m *PutStateInfo) Reset()                    { *m = PutStateInfo{} }
 func (m *PutStateInfo) String() string            { return proto.CompactTextString(m) }
 func (*PutStateInfo) ProtoMessage()               {}
-func (*PutStateInfo) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{1} }
+func (*PutStateInfo) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{2} }
 
 func (m *PutStateInfo) GetKey() string {
 	if m != nil {
@@ -181,7 +257,7 @@ type GetStateByRange struct {
 func (m *GetStateByRange) Reset()                    { *m = GetStateByRange{} }
 func (m *GetStateByRange) String() string            { return proto.CompactTextString(m) }
 func (*GetStateByRange) ProtoMessage()               {}
-func (*GetStateByRange) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{2} }
+func (*GetStateByRange) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{3} }
 
 func (m *GetStateByRange) GetStartKey() string {
 	if m != nil {
@@ -204,7 +280,7 @@ type GetQueryResult struct {
 func (m *GetQueryResult) Reset()                    { *m = GetQueryResult{} }
 func (m *GetQueryResult) String() string            { return proto.CompactTextString(m) }
 func (*GetQueryResult) ProtoMessage()               {}
-func (*GetQueryResult) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{3} }
+func (*GetQueryResult) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{4} }
 
 func (m *GetQueryResult) GetQuery() string {
 	if m != nil {
@@ -220,7 +296,7 @@ type GetHistoryForKey struct {
 func (m *GetHistoryForKey) Reset()                    { *m = GetHistoryForKey{} }
 func (m *GetHistoryForKey) String() string            { return proto.CompactTextString(m) }
 func (*GetHistoryForKey) ProtoMessage()               {}
-func (*GetHistoryForKey) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{4} }
+func (*GetHistoryForKey) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{5} }
 
 func (m *GetHistoryForKey) GetKey() string {
 	if m != nil {
@@ -236,7 +312,7 @@ type QueryStateNext struct {
 func (m *QueryStateNext) Reset()                    { *m = QueryStateNext{} }
 func (m *QueryStateNext) String() string            { return proto.CompactTextString(m) }
 func (*QueryStateNext) ProtoMessage()               {}
-func (*QueryStateNext) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{5} }
+func (*QueryStateNext) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{6} }
 
 func (m *QueryStateNext) GetId() string {
 	if m != nil {
@@ -252,7 +328,7 @@ type QueryStateClose struct {
 func (m *QueryStateClose) Reset()                    { *m = QueryStateClose{} }
 func (m *QueryStateClose) String() string            { return proto.CompactTextString(m) }
 func (*QueryStateClose) ProtoMessage()               {}
-func (*QueryStateClose) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{6} }
+func (*QueryStateClose) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{7} }
 
 func (m *QueryStateClose) GetId() string {
 	if m != nil {
@@ -268,7 +344,7 @@ type QueryResultBytes struct {
 func (m *QueryResultBytes) Reset()                    { *m = QueryResultBytes{} }
 func (m *QueryResultBytes) String() string            { return proto.CompactTextString(m) }
 func (*QueryResultBytes) ProtoMessage()               {}
-func (*QueryResultBytes) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{7} }
+func (*QueryResultBytes) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{8} }
 
 func (m *QueryResultBytes) GetResultBytes() []byte {
 	if m != nil {
@@ -286,7 +362,7 @@ type QueryResponse struct {
 func (m *QueryResponse) Reset()                    { *m = QueryResponse{} }
 func (m *QueryResponse) String() string            { return proto.CompactTextString(m) }
 func (*QueryResponse) ProtoMessage()               {}
-func (*QueryResponse) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{8} }
+func (*QueryResponse) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{9} }
 
 func (m *QueryResponse) GetResults() []*QueryResultBytes {
 	if m != nil {
@@ -311,6 +387,7 @@ func (m *QueryResponse) GetId() string {
 
 func init() {
 	proto.RegisterType((*ChaincodeMessage)(nil), "protos.ChaincodeMessage")
+	proto.RegisterType((*ChaincodeMetric)(nil), "protos.ChaincodeMetric")
 	proto.RegisterType((*PutStateInfo)(nil), "protos.PutStateInfo")
 	proto.RegisterType((*GetStateByRange)(nil), "protos.GetStateByRange")
 	proto.RegisterType((*GetQueryResult)(nil), "protos.GetQueryResult")
@@ -320,6 +397,7 @@ func init() {
 	proto.RegisterType((*QueryResultBytes)(nil), "protos.QueryResultBytes")
 	proto.RegisterType((*QueryResponse)(nil), "protos.QueryResponse")
 	proto.RegisterEnum("protos.ChaincodeMessage_Type", ChaincodeMessage_Type_name, ChaincodeMessage_Type_value)
+	proto.RegisterEnum("protos.ChaincodeMetric_Type", ChaincodeMetric_Type_name, ChaincodeMetric_Type_value)
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -429,54 +507,59 @@ var _ChaincodeSupport_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("peer/chaincode_shim.proto", fileDescriptor3) }
 
 var fileDescriptor3 = []byte{
-	// 771 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x74, 0x94, 0x6f, 0x6f, 0xe2, 0x46,
-	0x10, 0xc6, 0x8f, 0x3f, 0x49, 0x60, 0x20, 0xb0, 0xb7, 0xb9, 0xa6, 0x3e, 0xa4, 0xaa, 0xd4, 0xaa,
-	0x2a, 0xfa, 0xc6, 0xb4, 0xb4, 0xaa, 0xfa, 0xae, 0x22, 0xb0, 0x21, 0x56, 0x88, 0xcd, 0xad, 0x9d,
-	0xd3, 0xd1, 0x37, 0x96, 0x03, 0x1b, 0x63, 0x15, 0x58, 0x77, 0x77, 0x39, 0x9d, 0x3f, 0x61, 0xbf,
-	0x4c, 0x3f, 0xc4, 0x69, 0x6d, 0x4c, 0xb8, 0x9c, 0xf2, 0xca, 0x7e, 0x66, 0x7e, 0xf3, 0xcc, 0xec,
-	0x6a, 0x35, 0xf0, 0x36, 0x61, 0x4c, 0xf4, 0x17, 0xab, 0x30, 0xde, 0x2e, 0xf8, 0x92, 0x05, 0x72,
-	0x15, 0x6f, 0xac, 0x44, 0x70, 0xc5, 0xf1, 0x69, 0xf6, 0x91, 0x9d, 0xce, 0x33, 0x84, 0x7d, 0x64,
-	0x5b, 0x95, 0x33, 0x9d, 0x8b, 0x2c, 0x97, 0x08, 0x9e, 0x70, 0x19, 0xae, 0xf7, 0xc1, 0xef, 0x23,
-	0xce, 0xa3, 0x35, 0xeb, 0x67, 0xea, 0x61, 0xf7, 0xd8, 0x57, 0xf1, 0x86, 0x49, 0x15, 0x6e, 0x92,
-	0x1c, 0x30, 0xff, 0xaf, 0x02, 0x1a, 0x15, 0x7e, 0x77, 0x4c, 0xca, 0x30, 0x62, 0xf8, 0x57, 0xa8,
-	0xaa, 0x34, 0x61, 0x46, 0xa9, 0x5b, 0xea, 0xb5, 0x06, 0xdf, 0xe5, 0xa8, 0xb4, 0x9e, 0x73, 0x96,
-	0x9f, 0x26, 0x8c, 0x66, 0x28, 0xfe, 0x13, 0xea, 0x07, 0x6b, 0xa3, 0xdc, 0x2d, 0xf5, 0x1a, 0x83,
-	0x8e, 0x95, 0x37, 0xb7, 0x8a, 0xe6, 0x96, 0x5f, 0x10, 0xf4, 0x09, 0xc6, 0x06, 0x9c, 0x25, 0x61,
-	0xba, 0xe6, 0xe1, 0xd2, 0xa8, 0x74, 0x4b, 0xbd, 0x26, 0x2d, 0x24, 0xc6, 0x50, 0x55, 0x9f, 0xe2,
-	0xa5, 0x51, 0xed, 0x96, 0x7a, 0x75, 0x9a, 0xfd, 0xe3, 0x01, 0xd4, 0x8a, 0x23, 0x1a, 0x27, 0x59,
-	0x9b, 0xcb, 0x62, 0x3c, 0x2f, 0x8e, 0xb6, 0x6c, 0x39, 0xdb, 0x67, 0xe9, 0x81, 0xc3, 0x7f, 0x41,
-	0xfb, 0xd9, 0x95, 0x19, 0xa7, 0x5f, 0x96, 0x1e, 0x4e, 0x46, 0x74, 0x96, 0xb6, 0x16, 0x5f, 0x68,
-	0xf3, 0xbf, 0x32, 0x54, 0xf5, 0x59, 0xf1, 0x39, 0xd4, 0xef, 0x9d, 0x31, 0xb9, 0xb6, 0x1d, 0x32,
-	0x46, 0xaf, 0x70, 0x13, 0x6a, 0x94, 0x4c, 0x6c, 0xcf, 0x27, 0x14, 0x95, 0x70, 0x0b, 0xa0, 0x50,
-	0x64, 0x8c, 0xca, 0xb8, 0x06, 0x55, 0xdb, 0xb1, 0x7d, 0x54, 0xc1, 0x75, 0x38, 0xa1, 0x64, 0x38,
-	0x9e, 0xa3, 0x2a, 0x6e, 0x43, 0xc3, 0xa7, 0x43, 0xc7, 0x1b, 0x8e, 0x7c, 0xdb, 0x75, 0xd0, 0x89,
-	0xb6, 0x1c, 0xb9, 0x77, 0xb3, 0x29, 0xf1, 0xc9, 0x18, 0x9d, 0x6a, 0x94, 0x50, 0xea, 0x52, 0x74,
-	0xa6, 0x33, 0x13, 0xe2, 0x07, 0x9e, 0x3f, 0xf4, 0x09, 0xaa, 0x69, 0x39, 0xbb, 0x2f, 0x64, 0x5d,
-	0xcb, 0x31, 0x99, 0xee, 0x25, 0xe0, 0x37, 0x80, 0x6c, 0xe7, 0xbd, 0x7b, 0x4b, 0x82, 0xd1, 0xcd,
-	0xd0, 0x76, 0x46, 0xee, 0x98, 0xa0, 0x46, 0x3e, 0xa0, 0x37, 0x73, 0x1d, 0x8f, 0xa0, 0x73, 0x7c,
-	0x09, 0xf8, 0x60, 0x18, 0x5c, 0xcd, 0x03, 0x3a, 0x74, 0x26, 0x04, 0xb5, 0x74, 0xad, 0x8e, 0xbf,
-	0xbb, 0x27, 0x74, 0x1e, 0x50, 0xe2, 0xdd, 0x4f, 0x7d, 0xd4, 0xd6, 0xd1, 0x3c, 0x92, 0xf3, 0x0e,
-	0xf9, 0xe0, 0x23, 0x84, 0xbf, 0x81, 0xd7, 0xc7, 0xd1, 0xd1, 0xd4, 0xf5, 0x08, 0x7a, 0xad, 0xa7,
-	0xb9, 0x25, 0x64, 0x36, 0x9c, 0xda, 0xef, 0x09, 0xc2, 0xf8, 0x5b, 0xb8, 0xd0, 0x8e, 0x37, 0xb6,
-	0xe7, 0xbb, 0x74, 0x1e, 0x5c, 0xbb, 0x34, 0xb8, 0x25, 0x73, 0x74, 0x61, 0xfe, 0x01, 0xcd, 0xd9,
-	0x4e, 0x79, 0x2a, 0x54, 0xcc, 0xde, 0x3e, 0x72, 0x8c, 0xa0, 0xf2, 0x0f, 0x4b, 0xb3, 0x87, 0x56,
-	0xa7, 0xfa, 0x17, 0xbf, 0x81, 0x93, 0x8f, 0xe1, 0x7a, 0xc7, 0xb2, 0x47, 0xd4, 0xa4, 0xb9, 0x30,
-	0x09, 0xb4, 0x27, 0x2c, 0xaf, 0xbb, 0x4a, 0x69, 0xb8, 0x8d, 0x18, 0xee, 0x40, 0x4d, 0xaa, 0x50,
-	0xa8, 0xdb, 0x43, 0xfd, 0x41, 0xe3, 0x4b, 0x38, 0x65, 0xdb, 0xa5, 0xce, 0x94, 0xb3, 0xcc, 0x5e,
-	0x99, 0x3f, 0x41, 0x6b, 0xc2, 0xd4, 0xbb, 0x1d, 0x13, 0x29, 0x65, 0x72, 0xb7, 0x56, 0xba, 0xdd,
-	0xbf, 0x5a, 0xee, 0x2d, 0x72, 0x61, 0xfe, 0x08, 0x68, 0xc2, 0xd4, 0x4d, 0x2c, 0x15, 0x17, 0xe9,
-	0x35, 0x17, 0xda, 0xf3, 0xab, 0x51, 0xcd, 0x2e, 0xb4, 0x32, 0xab, 0x6c, 0x2c, 0x87, 0x7d, 0x52,
-	0xb8, 0x05, 0xe5, 0x78, 0xb9, 0x47, 0xca, 0xf1, 0xd2, 0xfc, 0x01, 0xda, 0x4f, 0xc4, 0x68, 0xcd,
-	0x25, 0xfb, 0x0a, 0xf9, 0x1d, 0xd0, 0xd1, 0x3c, 0x57, 0xa9, 0x62, 0x12, 0x77, 0xa1, 0x21, 0x9e,
-	0x64, 0x06, 0x37, 0xe9, 0x71, 0xc8, 0xdc, 0xc2, 0x79, 0x51, 0x95, 0xf0, 0xad, 0x64, 0x78, 0x00,
-	0x67, 0x79, 0x5e, 0xe3, 0x95, 0x5e, 0x63, 0x60, 0x14, 0x6f, 0xfb, 0xb9, 0x3b, 0x2d, 0x40, 0xfc,
-	0x16, 0x6a, 0xab, 0x50, 0x06, 0x1b, 0x2e, 0xf2, 0xdb, 0xae, 0xd1, 0xb3, 0x55, 0x28, 0xef, 0xb8,
-	0x28, 0xa6, 0xac, 0x14, 0x53, 0x0e, 0x3e, 0x1c, 0x6d, 0x09, 0x6f, 0x97, 0x24, 0x5c, 0x28, 0x3c,
-	0x86, 0x1a, 0x65, 0x51, 0x2c, 0x15, 0x13, 0xd8, 0x78, 0x69, 0x47, 0x74, 0x5e, 0xcc, 0x98, 0xaf,
-	0x7a, 0xa5, 0x5f, 0x4a, 0x57, 0x2e, 0x98, 0x5c, 0x44, 0xd6, 0x2a, 0x4d, 0x98, 0x58, 0xb3, 0x65,
-	0xc4, 0x84, 0xf5, 0x18, 0x3e, 0x88, 0x78, 0x51, 0xd4, 0xe9, 0xb5, 0xf6, 0xf7, 0xcf, 0x51, 0xac,
-	0x56, 0xbb, 0x07, 0x6b, 0xc1, 0x37, 0xfd, 0x23, 0xb4, 0x9f, 0xa3, 0xf9, 0x7a, 0x93, 0x7d, 0x8d,
-	0x3e, 0xe4, 0xbb, 0xf2, 0xb7, 0xcf, 0x01, 0x00, 0x00, 0xff, 0xff, 0xad, 0x9f, 0x80, 0x68, 0x4f,
-	0x05, 0x00, 0x00,
+	// 863 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x74, 0x95, 0xdf, 0x72, 0xda, 0xc6,
+	0x17, 0xc7, 0x2d, 0xfe, 0x18, 0x38, 0xd8, 0xb0, 0x59, 0xe7, 0xe7, 0x28, 0x4c, 0x7e, 0x2d, 0xd5,
+	0x74, 0x3a, 0xf4, 0x06, 0x12, 0xda, 0xe9, 0xf4, 0xae, 0x83, 0x61, 0x8d, 0x35, 0xc6, 0x12, 0x59,
+	0x89, 0x4c, 0xe8, 0x0d, 0x23, 0xc3, 0x1a, 0x34, 0x05, 0xad, 0xaa, 0x5d, 0x32, 0xe1, 0x69, 0xfa,
+	0x00, 0x7d, 0x90, 0xbe, 0x56, 0x67, 0xf5, 0x07, 0x08, 0xa9, 0xaf, 0xac, 0xef, 0x39, 0x9f, 0xf3,
+	0xdd, 0xb3, 0xbb, 0xc7, 0x0b, 0xbc, 0x0e, 0x19, 0x8b, 0x3a, 0xf3, 0x95, 0xe7, 0x07, 0x73, 0xbe,
+	0x60, 0x33, 0xb1, 0xf2, 0x37, 0xed, 0x30, 0xe2, 0x92, 0xe3, 0xf3, 0xf8, 0x8f, 0x68, 0x34, 0x4e,
+	0x10, 0xf6, 0x89, 0x05, 0x32, 0x61, 0x1a, 0x57, 0x71, 0x2e, 0x8c, 0x78, 0xc8, 0x85, 0xb7, 0x4e,
+	0x83, 0xdf, 0x2e, 0x39, 0x5f, 0xae, 0x59, 0x27, 0x56, 0x8f, 0xdb, 0xa7, 0x8e, 0xf4, 0x37, 0x4c,
+	0x48, 0x6f, 0x13, 0x26, 0x80, 0xf1, 0x57, 0x11, 0x50, 0x3f, 0xf3, 0x7b, 0x60, 0x42, 0x78, 0x4b,
+	0x86, 0xdf, 0x41, 0x41, 0xee, 0x42, 0xa6, 0x6b, 0x4d, 0xad, 0x55, 0xeb, 0xfe, 0x3f, 0x41, 0x45,
+	0xfb, 0x94, 0x6b, 0xbb, 0xbb, 0x90, 0xd1, 0x18, 0xc5, 0xbf, 0x42, 0x65, 0x6f, 0xad, 0xe7, 0x9a,
+	0x5a, 0xab, 0xda, 0x6d, 0xb4, 0x93, 0xc5, 0xdb, 0xd9, 0xe2, 0x6d, 0x37, 0x23, 0xe8, 0x01, 0xc6,
+	0x3a, 0x94, 0x42, 0x6f, 0xb7, 0xe6, 0xde, 0x42, 0xcf, 0x37, 0xb5, 0xd6, 0x05, 0xcd, 0x24, 0xc6,
+	0x50, 0x90, 0x9f, 0xfd, 0x85, 0x5e, 0x68, 0x6a, 0xad, 0x0a, 0x8d, 0xbf, 0x71, 0x17, 0xca, 0xd9,
+	0x16, 0xf5, 0x62, 0xbc, 0xcc, 0x75, 0xd6, 0x9e, 0xe3, 0x2f, 0x03, 0xb6, 0x18, 0xa7, 0x59, 0xba,
+	0xe7, 0xf0, 0x6f, 0x50, 0x3f, 0x39, 0x32, 0xfd, 0xfc, 0xcb, 0xd2, 0xfd, 0xce, 0x88, 0xca, 0xd2,
+	0xda, 0xfc, 0x0b, 0x8d, 0xdf, 0x41, 0x69, 0xc3, 0x64, 0xe4, 0xcf, 0x85, 0x5e, 0x6a, 0xe6, 0x5b,
+	0xd5, 0xee, 0xab, 0xff, 0x38, 0x12, 0x95, 0xa7, 0x19, 0x67, 0xfc, 0x93, 0x83, 0x82, 0x3a, 0x1e,
+	0x7c, 0x09, 0x95, 0x89, 0x35, 0x20, 0xb7, 0xa6, 0x45, 0x06, 0xe8, 0x0c, 0x5f, 0x40, 0x99, 0x92,
+	0xa1, 0xe9, 0xb8, 0x84, 0x22, 0x0d, 0xd7, 0x00, 0x32, 0x45, 0x06, 0x28, 0x87, 0xcb, 0x50, 0x30,
+	0x2d, 0xd3, 0x45, 0x79, 0x5c, 0x81, 0x22, 0x25, 0xbd, 0xc1, 0x14, 0x15, 0x70, 0x1d, 0xaa, 0x2e,
+	0xed, 0x59, 0x4e, 0xaf, 0xef, 0x9a, 0xb6, 0x85, 0x8a, 0xca, 0xb2, 0x6f, 0x3f, 0x8c, 0x47, 0xc4,
+	0x25, 0x03, 0x74, 0xae, 0x50, 0x42, 0xa9, 0x4d, 0x51, 0x49, 0x65, 0x86, 0xc4, 0x9d, 0x39, 0x6e,
+	0xcf, 0x25, 0xa8, 0xac, 0xe4, 0x78, 0x92, 0xc9, 0x8a, 0x92, 0x03, 0x32, 0x4a, 0x25, 0xe0, 0x97,
+	0x80, 0x4c, 0xeb, 0x83, 0x7d, 0x4f, 0x66, 0xfd, 0xbb, 0x9e, 0x69, 0xf5, 0xed, 0x01, 0x41, 0xd5,
+	0xa4, 0x41, 0x67, 0x6c, 0x5b, 0x0e, 0x41, 0x97, 0xf8, 0x1a, 0xf0, 0xde, 0x70, 0x76, 0x33, 0x9d,
+	0xd1, 0x9e, 0x35, 0x24, 0xa8, 0xa6, 0x6a, 0x55, 0xfc, 0xfd, 0x84, 0xd0, 0xe9, 0x8c, 0x12, 0x67,
+	0x32, 0x72, 0x51, 0x5d, 0x45, 0x93, 0x48, 0xc2, 0x5b, 0xe4, 0xa3, 0x8b, 0x10, 0xfe, 0x1f, 0xbc,
+	0x38, 0x8e, 0xf6, 0x47, 0xb6, 0x43, 0xd0, 0x0b, 0xd5, 0xcd, 0x3d, 0x21, 0xe3, 0xde, 0xc8, 0xfc,
+	0x40, 0x10, 0xc6, 0xaf, 0xe0, 0x4a, 0x39, 0xde, 0x99, 0x8e, 0x6b, 0xd3, 0xe9, 0xec, 0xd6, 0xa6,
+	0xb3, 0x7b, 0x32, 0x45, 0x57, 0xc6, 0xdf, 0x1a, 0xd4, 0x4f, 0x8e, 0x59, 0x4d, 0x46, 0xe0, 0x6d,
+	0x92, 0x01, 0xad, 0xd0, 0xf8, 0x1b, 0xbf, 0x4d, 0x87, 0x36, 0x17, 0x0f, 0xed, 0x9b, 0x67, 0x6e,
+	0xe8, 0x78, 0x66, 0xdf, 0x40, 0xc5, 0x0f, 0xe6, 0x11, 0xdb, 0xa8, 0x89, 0x50, 0xb3, 0x97, 0xa7,
+	0x87, 0x00, 0x7e, 0x09, 0xc5, 0x4f, 0xde, 0x7a, 0xcb, 0xe2, 0xf1, 0xd3, 0x68, 0x22, 0x8c, 0x6f,
+	0xd2, 0x6b, 0xad, 0x42, 0xa9, 0x6f, 0x4f, 0x2c, 0x75, 0x8d, 0x67, 0xea, 0x06, 0x86, 0xbd, 0xc9,
+	0x90, 0x20, 0xcd, 0xf8, 0x05, 0x2e, 0xc6, 0x5b, 0xe9, 0x48, 0x4f, 0x32, 0x33, 0x78, 0xe2, 0x18,
+	0x41, 0xfe, 0x0f, 0xb6, 0x4b, 0x1b, 0x55, 0x9f, 0x07, 0xdf, 0x5c, 0x3c, 0xed, 0xa9, 0x2f, 0x81,
+	0xfa, 0x90, 0x25, 0x75, 0x37, 0x3b, 0xea, 0x05, 0x4b, 0x86, 0x1b, 0x50, 0x16, 0xd2, 0x8b, 0xe4,
+	0xfd, 0xbe, 0x7e, 0xaf, 0xf1, 0x35, 0x9c, 0xb3, 0x60, 0xa1, 0x32, 0xb9, 0x38, 0x93, 0x2a, 0xe3,
+	0x07, 0xa8, 0x0d, 0x99, 0x7c, 0xbf, 0x65, 0xd1, 0x8e, 0x32, 0xb1, 0x5d, 0xc7, 0xdb, 0xf8, 0x53,
+	0xc9, 0xd4, 0x22, 0x11, 0xc6, 0xf7, 0x80, 0x86, 0x4c, 0xde, 0xf9, 0x42, 0xf2, 0x68, 0x77, 0xcb,
+	0x23, 0xe5, 0xf9, 0x55, 0xab, 0x46, 0x13, 0x6a, 0xb1, 0x55, 0xdc, 0x96, 0xc5, 0x3e, 0x4b, 0x5c,
+	0x83, 0x9c, 0xbf, 0x48, 0x91, 0x9c, 0xbf, 0x30, 0xbe, 0x83, 0xfa, 0x81, 0xe8, 0xaf, 0xb9, 0x60,
+	0x5f, 0x21, 0x3f, 0x03, 0x3a, 0xea, 0xe7, 0x66, 0x27, 0x99, 0xc0, 0x4d, 0xa8, 0x46, 0x07, 0x19,
+	0xc3, 0x17, 0xf4, 0x38, 0x64, 0x04, 0x70, 0x99, 0x55, 0x85, 0x3c, 0x10, 0x0c, 0x77, 0xa1, 0x94,
+	0xe4, 0x15, 0xae, 0xfe, 0x07, 0xf5, 0xec, 0x86, 0x4f, 0xdd, 0x69, 0x06, 0xe2, 0xd7, 0x50, 0x5e,
+	0x79, 0x62, 0xb6, 0xe1, 0x51, 0x72, 0xda, 0x65, 0x5a, 0x5a, 0x79, 0xe2, 0x81, 0x47, 0x59, 0x97,
+	0xf9, 0xac, 0xcb, 0xee, 0xc7, 0xa3, 0x67, 0xd0, 0xd9, 0x86, 0x21, 0x8f, 0x24, 0x1e, 0x40, 0x99,
+	0xb2, 0xa5, 0x2f, 0x24, 0x8b, 0xb0, 0xfe, 0xdc, 0x23, 0xd8, 0x78, 0x36, 0x63, 0x9c, 0xb5, 0xb4,
+	0xb7, 0xda, 0x8d, 0x0d, 0x06, 0x8f, 0x96, 0xed, 0xd5, 0x2e, 0x64, 0xd1, 0x9a, 0x2d, 0x96, 0x2c,
+	0x6a, 0x3f, 0x79, 0x8f, 0x6a, 0x14, 0xd3, 0x3a, 0xf5, 0x6e, 0xff, 0xfe, 0xe3, 0xd2, 0x97, 0xab,
+	0xed, 0x63, 0x7b, 0xce, 0x37, 0x9d, 0x23, 0xb4, 0x93, 0xa0, 0xc9, 0xfb, 0x2d, 0x3a, 0x0a, 0x7d,
+	0x4c, 0x7e, 0x0c, 0x7e, 0xfa, 0x77, 0x00, 0x8d, 0x48, 0x6f, 0xe3, 0x30, 0x06, 0x00, 0x00,
 }
@@ -57,25 +57,118 @@ func (m *AnchorPeer) GetPort() int32 {
 	return 0
 }
 
+// TxIDWindow is the number of blocks within which a transaction ID must be unique
+// on an application channel. A transaction reusing the ID of a transaction of the
+// window of blocks preceding its own, or of an earlier transaction of its own block,
+// is invalidated as a duplicate. 0 checks against the whole history of the channel.
+type TxIDWindow struct {
+	Blocks uint64 `protobuf:"varint,1,opt,name=blocks" json:"blocks,omitempty"`
+}
+
+func (m *TxIDWindow) Reset()                    { *m = TxIDWindow{} }
+func (m *TxIDWindow) String() string            { return proto.CompactTextString(m) }
+func (*TxIDWindow) ProtoMessage()               {}
+func (*TxIDWindow) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{2} }
+
+func (m *TxIDWindow) GetBlocks() uint64 {
+	if m != nil {
+		return m.Blocks
+	}
+	return 0
+}
+
+// RWSetLimits caps the size of the read set and of the write set of each
+// transaction of an application channel, measured as the size of the
+// protobuf encoding of their reads, range queries and writes across all the
+// namespaces. A transaction exceeding either cap is invalidated with the
+// RWSET_SIZE_EXCEEDED validation code. 0 leaves the size unbounded.
+type RWSetLimits struct {
+	MaxReadSetBytes  uint64 `protobuf:"varint,1,opt,name=max_read_set_bytes,json=maxReadSetBytes" json:"max_read_set_bytes,omitempty"`
+	MaxWriteSetBytes uint64 `protobuf:"varint,2,opt,name=max_write_set_bytes,json=maxWriteSetBytes" json:"max_write_set_bytes,omitempty"`
+}
+
+func (m *RWSetLimits) Reset()                    { *m = RWSetLimits{} }
+func (m *RWSetLimits) String() string            { return proto.CompactTextString(m) }
+func (*RWSetLimits) ProtoMessage()               {}
+func (*RWSetLimits) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{3} }
+
+func (m *RWSetLimits) GetMaxReadSetBytes() uint64 {
+	if m != nil {
+		return m.MaxReadSetBytes
+	}
+	return 0
+}
+
+func (m *RWSetLimits) GetMaxWriteSetBytes() uint64 {
+	if m != nil {
+		return m.MaxWriteSetBytes
+	}
+	return 0
+}
+
+// Capabilities lists the capabilities of an application channel, the features
+// changing the content or the validation of the transactions which may only be
+// enabled once all the peers of the channel support them. A capability is
+// enabled by its presence in the map.
+type Capabilities struct {
+	Capabilities map[string]*Capability `protobuf:"bytes,1,rep,name=capabilities" json:"capabilities,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+}
+
+func (m *Capabilities) Reset()                    { *m = Capabilities{} }
+func (m *Capabilities) String() string            { return proto.CompactTextString(m) }
+func (*Capabilities) ProtoMessage()               {}
+func (*Capabilities) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{4} }
+
+func (m *Capabilities) GetCapabilities() map[string]*Capability {
+	if m != nil {
+		return m.Capabilities
+	}
+	return nil
+}
+
+// Capability is empty, it only marks a capability as enabled
+type Capability struct {
+}
+
+func (m *Capability) Reset()                    { *m = Capability{} }
+func (m *Capability) String() string            { return proto.CompactTextString(m) }
+func (*Capability) ProtoMessage()               {}
+func (*Capability) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{5} }
+
 func init() {
 	proto.RegisterType((*AnchorPeers)(nil), "protos.AnchorPeers")
 	proto.RegisterType((*AnchorPeer)(nil), "protos.AnchorPeer")
+	proto.RegisterType((*TxIDWindow)(nil), "protos.TxIDWindow")
+	proto.RegisterType((*RWSetLimits)(nil), "protos.RWSetLimits")
+	proto.RegisterType((*Capabilities)(nil), "protos.Capabilities")
+	proto.RegisterType((*Capability)(nil), "protos.Capability")
 }
 
 func init() { proto.RegisterFile("peer/configuration.proto", fileDescriptor4) }
 
 var fileDescriptor4 = []byte{
-	// 192 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x4c, 0x8f, 0x3d, 0x8f, 0xc3, 0x20,
-	0x0c, 0x86, 0xc5, 0x7d, 0x49, 0x47, 0x6e, 0x62, 0x62, 0x8c, 0x32, 0xe5, 0x16, 0x90, 0xee, 0xe3,
-	0x07, 0xb4, 0xea, 0xde, 0x2a, 0x63, 0x97, 0x8a, 0x50, 0x02, 0x48, 0x6d, 0x8c, 0x0c, 0x19, 0xfa,
-	0xef, 0x2b, 0x40, 0x55, 0x3a, 0xf1, 0x62, 0x3f, 0x8f, 0x6c, 0x53, 0x1e, 0x8c, 0x41, 0xa9, 0x61,
-	0x9e, 0xbc, 0x5d, 0x50, 0x25, 0x0f, 0xb3, 0x08, 0x08, 0x09, 0xd8, 0x47, 0x79, 0x62, 0xb7, 0xa3,
-	0xcd, 0x66, 0xd6, 0x0e, 0xf0, 0x60, 0x0c, 0x46, 0xf6, 0x4f, 0xbf, 0x54, 0xf9, 0x9e, 0xb2, 0x19,
-	0x39, 0x69, 0x5f, 0xfb, 0xe6, 0x87, 0x55, 0x29, 0x8a, 0x15, 0x1d, 0x1a, 0xb5, 0x6a, 0xdd, 0x1f,
-	0xa5, 0x6b, 0x8b, 0x31, 0xfa, 0xe6, 0x20, 0x26, 0x4e, 0x5a, 0xd2, 0x7f, 0x0e, 0x25, 0xe7, 0x5a,
-	0x00, 0x4c, 0xfc, 0xa5, 0x25, 0xfd, 0xfb, 0x50, 0xf2, 0x76, 0x4f, 0x3b, 0x40, 0x2b, 0xdc, 0x2d,
-	0x18, 0xbc, 0x98, 0xb3, 0x35, 0x28, 0x26, 0x35, 0xa2, 0xd7, 0x8f, 0x71, 0x79, 0x87, 0xe3, 0xb7,
-	0xf5, 0xc9, 0x2d, 0xa3, 0xd0, 0x70, 0x95, 0x4f, 0xa8, 0xac, 0xa8, 0xac, 0xa8, 0xcc, 0xe8, 0x58,
-	0x8f, 0xfa, 0xbd, 0x07, 0x00, 0x00, 0xff, 0xff, 0x6d, 0xf2, 0x7b, 0x6f, 0xf7, 0x00, 0x00, 0x00,
+	// 358 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x64, 0x92, 0x4d, 0xab, 0x9b, 0x40,
+	0x14, 0x86, 0x31, 0x5f, 0xd0, 0xa3, 0xd0, 0x74, 0x0a, 0x45, 0xba, 0x0a, 0x52, 0x8a, 0xa5, 0x54,
+	0xa1, 0x1f, 0x50, 0xba, 0x6b, 0x9a, 0x2e, 0x5a, 0x0a, 0x2d, 0x93, 0x42, 0xa0, 0x1b, 0x19, 0xf5,
+	0x44, 0x87, 0xa8, 0x23, 0x33, 0x93, 0x46, 0x7f, 0xd5, 0xfd, 0x8b, 0x97, 0xd1, 0x78, 0x35, 0xdc,
+	0x95, 0xe7, 0x9d, 0xf3, 0x3c, 0x22, 0xaf, 0x03, 0x6e, 0x8d, 0x28, 0xc3, 0x44, 0x54, 0x47, 0x9e,
+	0x9d, 0x25, 0xd3, 0x5c, 0x54, 0x41, 0x2d, 0x85, 0x16, 0x64, 0xd5, 0x3d, 0x94, 0xb7, 0x03, 0xfb,
+	0x6b, 0x95, 0xe4, 0x42, 0xfe, 0x41, 0x94, 0x8a, 0x7c, 0x02, 0x87, 0x75, 0x31, 0x32, 0xa6, 0x72,
+	0xad, 0xcd, 0xdc, 0xb7, 0xdf, 0x93, 0x5e, 0x52, 0xc1, 0x88, 0x52, 0x9b, 0x8d, 0x9a, 0xf7, 0x11,
+	0x60, 0x5c, 0x11, 0x02, 0x8b, 0x5c, 0x28, 0xed, 0x5a, 0x1b, 0xcb, 0x7f, 0x42, 0xbb, 0xd9, 0x9c,
+	0xd5, 0x42, 0x6a, 0x77, 0xb6, 0xb1, 0xfc, 0x25, 0xed, 0x66, 0xef, 0x15, 0xc0, 0xdf, 0xe6, 0xc7,
+	0xee, 0xc0, 0xab, 0x54, 0x5c, 0xc8, 0x0b, 0x58, 0xc5, 0x85, 0x48, 0x4e, 0xaa, 0xf3, 0x16, 0xf4,
+	0x9a, 0x3c, 0x0e, 0x36, 0x3d, 0xec, 0x51, 0xff, 0xe2, 0x25, 0xd7, 0x8a, 0xbc, 0x05, 0x52, 0xb2,
+	0x26, 0x92, 0xc8, 0xd2, 0x48, 0xa1, 0x8e, 0xe2, 0x56, 0xe3, 0xa0, 0x3c, 0x2d, 0x59, 0x43, 0x91,
+	0xa5, 0x7b, 0xd4, 0x5b, 0x73, 0x4c, 0xde, 0xc1, 0x73, 0x03, 0x5f, 0x24, 0xd7, 0x38, 0xa1, 0x67,
+	0x1d, 0xbd, 0x2e, 0x59, 0x73, 0x30, 0x9b, 0x01, 0xf7, 0xee, 0x2c, 0x70, 0xbe, 0xb1, 0x9a, 0xc5,
+	0xbc, 0xe0, 0x9a, 0xa3, 0x22, 0x3f, 0xc1, 0x49, 0x26, 0xf9, 0x5a, 0xc7, 0xeb, 0xa1, 0x8e, 0x29,
+	0x7b, 0x13, 0xbe, 0x57, 0x5a, 0xb6, 0xf4, 0xc6, 0x7d, 0xb9, 0x87, 0x67, 0x8f, 0x10, 0xb2, 0x86,
+	0xf9, 0x09, 0xdb, 0x6b, 0x53, 0x66, 0x24, 0x3e, 0x2c, 0xff, 0xb3, 0xe2, 0x8c, 0xdd, 0x47, 0x4e,
+	0xaa, 0x7f, 0x70, 0x5b, 0xda, 0x03, 0x5f, 0x66, 0x9f, 0x2d, 0xcf, 0x01, 0x18, 0x17, 0xdb, 0xdf,
+	0xe0, 0x09, 0x99, 0x05, 0x79, 0x5b, 0xa3, 0x2c, 0x30, 0xcd, 0x50, 0x06, 0x47, 0x16, 0x4b, 0x9e,
+	0x0c, 0x2f, 0x31, 0x3f, 0xf5, 0xdf, 0x9b, 0x8c, 0xeb, 0xfc, 0x1c, 0x07, 0x89, 0x28, 0xc3, 0x09,
+	0x1a, 0xf6, 0x68, 0xd8, 0xa3, 0xa1, 0x41, 0xe3, 0xfe, 0x96, 0x7c, 0xb8, 0x1f, 0x00, 0xca, 0x26,
+	0x7e, 0xb0, 0x48, 0x02, 0x00, 0x00,
 }
@@ -187,6 +187,10 @@ func _Interest_OneofSizer(msg proto.Message) (n int) {
 // string type - "register"
 type Register struct {
 	Events []*Interest `protobuf:"bytes,1,rep,name=events" json:"events,omitempty"`
+	// When > 0, the events are delivered in acknowledged mode: they are
+	// numbered by their sequence, and the producer sends at most ack_window
+	// events ahead of the Ack of the consumer, buffering the others
+	AckWindow uint32 `protobuf:"varint,2,opt,name=ack_window,json=ackWindow" json:"ack_window,omitempty"`
 }
 
 func (m *Register) Reset()                    { *m = Register{} }
@@ -201,6 +205,13 @@ func (m *Register) GetEvents() []*Interest {
 	return nil
 }
 
+func (m *Register) GetAckWindow() uint32 {
+	if m != nil {
+		return m.AckWindow
+	}
+	return 0
+}
+
 // Rejection is sent by consumers for erroneous transaction rejection events
 // string type - "rejection"
 type Rejection struct {
@@ -227,6 +238,24 @@ func (m *Rejection) GetErrorMsg() string {
 	return ""
 }
 
+// Ack is sent by consumers registered in acknowledged mode to acknowledge
+// the events up to sequence
+type Ack struct {
+	Sequence uint64 `protobuf:"varint,1,opt,name=sequence" json:"sequence,omitempty"`
+}
+
+func (m *Ack) Reset()                    { *m = Ack{} }
+func (m *Ack) String() string            { return proto.CompactTextString(m) }
+func (*Ack) ProtoMessage()               {}
+func (*Ack) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{4} }
+
+func (m *Ack) GetSequence() uint64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
 // ---------- producer events ---------
 type Unregister struct {
 	Events []*Interest `protobuf:"bytes,1,rep,name=events" json:"events,omitempty"`
@@ -235,7 +264,7 @@ type Unregister struct {
 func (m *Unregister) Reset()                    { *m = Unregister{} }
 func (m *Unregister) String() string            { return proto.CompactTextString(m) }
 func (*Unregister) ProtoMessage()               {}
-func (*Unregister) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{4} }
+func (*Unregister) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{5} }
 
 func (m *Unregister) GetEvents() []*Interest {
 	if m != nil {
@@ -255,7 +284,7 @@ type SignedEvent struct {
 func (m *SignedEvent) Reset()                    { *m = SignedEvent{} }
 func (m *SignedEvent) String() string            { return proto.CompactTextString(m) }
 func (*SignedEvent) ProtoMessage()               {}
-func (*SignedEvent) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{5} }
+func (*SignedEvent) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{6} }
 
 func (m *SignedEvent) GetSignature() []byte {
 	if m != nil {
@@ -281,15 +310,19 @@ type Event struct {
 	//	*Event_ChaincodeEvent
 	//	*Event_Rejection
 	//	*Event_Unregister
+	//	*Event_Ack
 	Event isEvent_Event `protobuf_oneof:"Event"`
 	// Creator of the event, specified as a certificate chain
 	Creator []byte `protobuf:"bytes,6,opt,name=creator,proto3" json:"creator,omitempty"`
+	// Sequence of the event on a stream registered in acknowledged mode,
+	// starting at 1. A gap tells the consumer that events were dropped
+	Sequence uint64 `protobuf:"varint,8,opt,name=sequence" json:"sequence,omitempty"`
 }
 
 func (m *Event) Reset()                    { *m = Event{} }
 func (m *Event) String() string            { return proto.CompactTextString(m) }
 func (*Event) ProtoMessage()               {}
-func (*Event) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{6} }
+func (*Event) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{7} }
 
 type isEvent_Event interface {
 	isEvent_Event()
@@ -310,12 +343,16 @@ type Event_Rejection struct {
 type Event_Unregister struct {
 	Unregister *Unregister `protobuf:"bytes,5,opt,name=unregister,oneof"`
 }
+type Event_Ack struct {
+	Ack *Ack `protobuf:"bytes,7,opt,name=ack,oneof"`
+}
 
 func (*Event_Register) isEvent_Event()       {}
 func (*Event_Block) isEvent_Event()          {}
 func (*Event_ChaincodeEvent) isEvent_Event() {}
 func (*Event_Rejection) isEvent_Event()      {}
 func (*Event_Unregister) isEvent_Event()     {}
+func (*Event_Ack) isEvent_Event()            {}
 
 func (m *Event) GetEvent() isEvent_Event {
 	if m != nil {
@@ -359,6 +396,13 @@ func (m *Event) GetUnregister() *Unregister {
 	return nil
 }
 
+func (m *Event) GetAck() *Ack {
+	if x, ok := m.GetEvent().(*Event_Ack); ok {
+		return x.Ack
+	}
+	return nil
+}
+
 func (m *Event) GetCreator() []byte {
 	if m != nil {
 		return m.Creator
@@ -366,6 +410,13 @@ func (m *Event) GetCreator() []byte {
 	return nil
 }
 
+func (m *Event) GetSequence() uint64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
 // XXX_OneofFuncs is for the internal use of the proto package.
 func (*Event) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
 	return _Event_OneofMarshaler, _Event_OneofUnmarshaler, _Event_OneofSizer, []interface{}{
@@ -374,6 +425,7 @@ func (*Event) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error,
 		(*Event_ChaincodeEvent)(nil),
 		(*Event_Rejection)(nil),
 		(*Event_Unregister)(nil),
+		(*Event_Ack)(nil),
 	}
 }
 
@@ -406,6 +458,11 @@ func _Event_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
 		if err := b.EncodeMessage(x.Unregister); err != nil {
 			return err
 		}
+	case *Event_Ack:
+		b.EncodeVarint(7<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.Ack); err != nil {
+			return err
+		}
 	case nil:
 	default:
 		return fmt.Errorf("Event.Event has unexpected type %T", x)
@@ -456,6 +513,14 @@ func _Event_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.Buffer)
 		err := b.DecodeMessage(msg)
 		m.Event = &Event_Unregister{msg}
 		return true, err
+	case 7: // Event.ack
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(Ack)
+		err := b.DecodeMessage(msg)
+		m.Event = &Event_Ack{msg}
+		return true, err
 	default:
 		return false, nil
 	}
@@ -490,6 +555,11 @@ func _Event_OneofSizer(msg proto.Message) (n int) {
 		n += proto.SizeVarint(5<<3 | proto.WireBytes)
 		n += proto.SizeVarint(uint64(s))
 		n += s
+	case *Event_Ack:
+		s := proto.Size(x.Ack)
+		n += proto.SizeVarint(7<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
 	case nil:
 	default:
 		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
@@ -502,6 +572,7 @@ func init() {
 	proto.RegisterType((*Interest)(nil), "protos.Interest")
 	proto.RegisterType((*Register)(nil), "protos.Register")
 	proto.RegisterType((*Rejection)(nil), "protos.Rejection")
+	proto.RegisterType((*Ack)(nil), "protos.Ack")
 	proto.RegisterType((*Unregister)(nil), "protos.Unregister")
 	proto.RegisterType((*SignedEvent)(nil), "protos.SignedEvent")
 	proto.RegisterType((*Event)(nil), "protos.Event")
@@ -617,44 +688,47 @@ var _Events_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("peer/events.proto", fileDescriptor5) }
 
 var fileDescriptor5 = []byte{
-	// 611 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x94, 0x54, 0x4f, 0x6f, 0x9b, 0x4e,
-	0x10, 0x05, 0x27, 0x76, 0xcc, 0xd8, 0xce, 0xcf, 0xd9, 0xfc, 0x14, 0x21, 0xf7, 0x8f, 0x52, 0xaa,
-	0x4a, 0x6e, 0x0f, 0x26, 0xa5, 0x51, 0x0f, 0xbd, 0x05, 0x82, 0x0a, 0x4d, 0xf3, 0x47, 0x1b, 0xf7,
-	0xd2, 0x43, 0x2d, 0x8c, 0x27, 0x98, 0x26, 0x06, 0x6b, 0xd9, 0x54, 0xf1, 0x27, 0xea, 0xa9, 0xdf,
-	0xb1, 0x62, 0x61, 0x81, 0xb4, 0xa7, 0x9e, 0xf0, 0xcc, 0xbc, 0x37, 0xfb, 0xf6, 0xcd, 0xac, 0x61,
-	0x6f, 0x8d, 0xc8, 0x4c, 0xfc, 0x81, 0x09, 0xcf, 0x26, 0x6b, 0x96, 0xf2, 0x94, 0x74, 0xc4, 0x27,
-	0x1b, 0xed, 0x87, 0xe9, 0x6a, 0x95, 0x26, 0x66, 0xf1, 0x29, 0x8a, 0xa3, 0x91, 0xc0, 0x87, 0xcb,
-	0x20, 0x4e, 0xc2, 0x74, 0x81, 0x33, 0xc1, 0x2c, 0x6b, 0x07, 0xa2, 0xc6, 0x59, 0x90, 0x64, 0x41,
-	0xc8, 0x63, 0xc9, 0x31, 0xae, 0xa0, 0xef, 0x48, 0x02, 0xc5, 0x88, 0xbc, 0x80, 0x7e, 0xdd, 0x20,
-	0x5e, 0xe8, 0xea, 0xa1, 0x3a, 0xd6, 0x68, 0xaf, 0xca, 0xf9, 0x0b, 0xf2, 0x0c, 0x40, 0x74, 0x9e,
-	0x25, 0xc1, 0x0a, 0xf5, 0x96, 0x00, 0x68, 0x22, 0x73, 0x11, 0xac, 0xd0, 0xf8, 0xa9, 0x42, 0xd7,
-	0x4f, 0x38, 0x32, 0xcc, 0x38, 0x39, 0x92, 0x58, 0xbe, 0x59, 0xa3, 0x68, 0xb6, 0x6b, 0xed, 0x15,
-	0x47, 0x67, 0x13, 0x37, 0xaf, 0x4c, 0x37, 0x6b, 0x2c, 0xe9, 0xf9, 0x4f, 0x72, 0x0a, 0xa4, 0x16,
-	0xc0, 0x30, 0x9a, 0xc5, 0xc9, 0x4d, 0x2a, 0x4e, 0xe9, 0x59, 0xff, 0x4b, 0x66, 0x53, 0xb2, 0xa7,
-	0xd0, 0x61, 0xd8, 0x88, 0xfd, 0xe4, 0x26, 0x25, 0x3a, 0xec, 0x88, 0x9c, 0x7f, 0xaa, 0x6f, 0x09,
-	0x81, 0x32, 0xb4, 0x35, 0xd8, 0x29, 0x41, 0xc6, 0x31, 0x74, 0x29, 0x46, 0x71, 0xc6, 0x91, 0x91,
-	0x31, 0x74, 0x0a, 0xa3, 0x75, 0xf5, 0x70, 0x6b, 0xdc, 0xb3, 0x86, 0xf2, 0x28, 0x79, 0x15, 0x5a,
-	0xd6, 0x8d, 0x73, 0xd0, 0x28, 0x7e, 0x47, 0x61, 0x22, 0x79, 0x09, 0x2d, 0xfe, 0x20, 0xee, 0xd5,
-	0xb3, 0xf6, 0x25, 0x65, 0x5a, 0xbb, 0x4c, 0x5b, 0xfc, 0x81, 0x3c, 0x01, 0x0d, 0x19, 0x4b, 0xd9,
-	0x6c, 0x95, 0x45, 0xa5, 0x5f, 0x5d, 0x91, 0x38, 0xcf, 0x22, 0xe3, 0x3d, 0xc0, 0x97, 0x84, 0xfd,
-	0xbb, 0x8c, 0x33, 0xe8, 0x5d, 0xc7, 0x51, 0x82, 0x0b, 0xe1, 0x22, 0x79, 0x0a, 0x5a, 0x16, 0x47,
-	0x49, 0xc0, 0xef, 0x59, 0xe1, 0x73, 0x9f, 0xd6, 0x09, 0xf2, 0xbc, 0x1c, 0x83, 0xbd, 0xe1, 0x98,
-	0x09, 0x09, 0x7d, 0xda, 0xc8, 0x18, 0xbf, 0x5a, 0xd0, 0x2e, 0xfa, 0x4c, 0xa0, 0x2b, 0xc5, 0x94,
-	0xd7, 0xaa, 0x24, 0x48, 0xaf, 0x3c, 0x85, 0x56, 0x18, 0xf2, 0x0a, 0xda, 0xf3, 0xbb, 0x34, 0xbc,
-	0x2d, 0x27, 0x34, 0x98, 0x94, 0x1b, 0x69, 0xe7, 0x49, 0x4f, 0xa1, 0x45, 0x95, 0x9c, 0xc0, 0x7f,
-	0x7f, 0xec, 0xa5, 0x98, 0x4b, 0xcf, 0x3a, 0xf8, 0x6b, 0xa4, 0x42, 0x87, 0xa7, 0xd0, 0xdd, 0xf0,
-	0x51, 0x86, 0xbc, 0x05, 0x8d, 0x49, 0xdf, 0xf5, 0x6d, 0x41, 0xde, 0xab, 0xa5, 0x95, 0x05, 0x4f,
-	0xa1, 0x35, 0x8a, 0x1c, 0x03, 0xdc, 0x57, 0xde, 0xea, 0x6d, 0xc1, 0x21, 0x92, 0x53, 0xbb, 0xee,
-	0x29, 0xb4, 0x81, 0x13, 0xbb, 0xc3, 0x30, 0xe0, 0x29, 0xd3, 0x3b, 0xc2, 0x29, 0x19, 0xda, 0x3b,
-	0xa5, 0x4b, 0x6f, 0x6c, 0xd0, 0xaa, 0xe5, 0x25, 0x7d, 0xe8, 0x52, 0xf7, 0xa3, 0x7f, 0x3d, 0x75,
-	0xe9, 0x50, 0x21, 0x1a, 0xb4, 0xed, 0xcf, 0x97, 0xce, 0xd9, 0x50, 0x25, 0x03, 0xd0, 0x1c, 0xef,
-	0xc4, 0xbf, 0x70, 0x2e, 0x4f, 0xdd, 0x61, 0x2b, 0x0f, 0xa9, 0xfb, 0xc9, 0x75, 0xa6, 0xfe, 0xe5,
-	0xc5, 0x70, 0xcb, 0xfa, 0x00, 0x1d, 0xd1, 0x23, 0x23, 0x47, 0xb0, 0xed, 0x2c, 0x03, 0x4e, 0xaa,
-	0x05, 0x6a, 0x0c, 0x76, 0x34, 0x78, 0xf4, 0x5a, 0x0c, 0x65, 0xac, 0x1e, 0xa9, 0xf6, 0x37, 0x30,
-	0x52, 0x16, 0x4d, 0x96, 0x9b, 0x35, 0xb2, 0x3b, 0x5c, 0x44, 0xc8, 0x26, 0x37, 0xc1, 0x9c, 0xc5,
-	0xa1, 0x04, 0xe7, 0xaf, 0xdd, 0x1e, 0x14, 0xfd, 0xaf, 0x82, 0xf0, 0x36, 0x88, 0xf0, 0xeb, 0xeb,
-	0x28, 0xe6, 0xcb, 0xfb, 0x79, 0x3e, 0x21, 0xb3, 0xc1, 0x34, 0x0b, 0xa6, 0x59, 0x30, 0xcd, 0x9c,
-	0x39, 0x2f, 0xfe, 0x66, 0xde, 0xfd, 0x0e, 0x00, 0x00, 0xff, 0xff, 0x19, 0x83, 0x34, 0xd3, 0x82,
-	0x04, 0x00, 0x00,
+	// 672 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x94, 0x54, 0x5d, 0x6f, 0xda, 0x4a,
+	0x10, 0x35, 0x10, 0x3e, 0x3c, 0x40, 0x2e, 0xd9, 0x5c, 0x45, 0x16, 0xf7, 0xb6, 0x4d, 0x5c, 0x55,
+	0xa2, 0x7d, 0x80, 0x94, 0x56, 0x7d, 0xe8, 0x1b, 0x26, 0xa8, 0xd0, 0x34, 0x1f, 0xda, 0x50, 0x55,
+	0xea, 0x43, 0xd1, 0xb2, 0x4c, 0x8c, 0x4b, 0xb0, 0xe9, 0x7a, 0x69, 0xc2, 0x2f, 0xea, 0x7f, 0xeb,
+	0xaf, 0xa8, 0xbc, 0xf6, 0xda, 0xd0, 0x3e, 0xf5, 0xc9, 0xec, 0xcc, 0x39, 0xb3, 0x33, 0xe7, 0xcc,
+	0x02, 0x07, 0x2b, 0x44, 0xd1, 0xc1, 0xef, 0xe8, 0xcb, 0xb0, 0xbd, 0x12, 0x81, 0x0c, 0x48, 0x49,
+	0x7d, 0xc2, 0xe6, 0x21, 0x0f, 0x96, 0xcb, 0xc0, 0xef, 0xc4, 0x9f, 0x38, 0xd9, 0x6c, 0x2a, 0x3c,
+	0x9f, 0x33, 0xcf, 0xe7, 0xc1, 0x0c, 0x27, 0x8a, 0x99, 0xe4, 0x8e, 0x54, 0x4e, 0x0a, 0xe6, 0x87,
+	0x8c, 0x4b, 0x4f, 0x73, 0xec, 0x6b, 0xa8, 0xf5, 0x35, 0x81, 0xa2, 0x4b, 0x4e, 0xa0, 0x96, 0x15,
+	0xf0, 0x66, 0x56, 0xee, 0x38, 0xd7, 0x32, 0x69, 0x35, 0x8d, 0x8d, 0x66, 0xe4, 0x11, 0x80, 0xaa,
+	0x3c, 0xf1, 0xd9, 0x12, 0xad, 0xbc, 0x02, 0x98, 0x2a, 0x72, 0xc9, 0x96, 0x68, 0xff, 0xc8, 0x41,
+	0x65, 0xe4, 0x4b, 0x14, 0x18, 0x4a, 0x72, 0xaa, 0xb1, 0x72, 0xb3, 0x42, 0x55, 0x6c, 0xbf, 0x7b,
+	0x10, 0x5f, 0x1d, 0xb6, 0x07, 0x51, 0x66, 0xbc, 0x59, 0x61, 0x42, 0x8f, 0x7e, 0x92, 0x33, 0x20,
+	0x59, 0x03, 0x02, 0xdd, 0x89, 0xe7, 0xdf, 0x06, 0xea, 0x96, 0x6a, 0xf7, 0x5f, 0xcd, 0xdc, 0x6e,
+	0x79, 0x68, 0xd0, 0x06, 0xdf, 0x3a, 0x8f, 0xfc, 0xdb, 0x80, 0x58, 0x50, 0x56, 0xb1, 0xd1, 0x99,
+	0x55, 0x50, 0x0d, 0xea, 0xa3, 0x63, 0x42, 0x39, 0x01, 0xd9, 0x37, 0x50, 0xa1, 0xe8, 0x7a, 0xa1,
+	0x44, 0x41, 0x5a, 0x50, 0x8a, 0x85, 0xb6, 0x72, 0xc7, 0x85, 0x56, 0xb5, 0xdb, 0xd0, 0x57, 0xe9,
+	0x51, 0x68, 0x92, 0x8f, 0xc6, 0x67, 0x7c, 0x31, 0xb9, 0xf7, 0xfc, 0x59, 0x70, 0xaf, 0x1a, 0xab,
+	0x53, 0x93, 0xf1, 0xc5, 0x27, 0x15, 0xb0, 0x2f, 0xc0, 0xa4, 0xf8, 0x15, 0x95, 0xc6, 0xe4, 0x29,
+	0xe4, 0xe5, 0x83, 0x1a, 0xbb, 0xda, 0x3d, 0xd4, 0x15, 0xc7, 0x99, 0x09, 0x34, 0x2f, 0x1f, 0xc8,
+	0x7f, 0x60, 0xa2, 0x10, 0x81, 0x98, 0x2c, 0x43, 0x37, 0x91, 0xb3, 0xa2, 0x02, 0x17, 0xa1, 0x6b,
+	0x9f, 0x40, 0xa1, 0xc7, 0x17, 0xa4, 0x09, 0x95, 0x10, 0xbf, 0xad, 0xd1, 0xe7, 0xb1, 0x8a, 0x7b,
+	0x34, 0x3d, 0xdb, 0x6f, 0x00, 0x3e, 0xfa, 0xe2, 0xaf, 0x07, 0xb1, 0xcf, 0xa1, 0x7a, 0xe3, 0xb9,
+	0x3e, 0xce, 0x94, 0x0f, 0xe4, 0x7f, 0x30, 0x43, 0xcf, 0xf5, 0x99, 0x5c, 0x8b, 0xf8, 0x8e, 0x1a,
+	0xcd, 0x02, 0xe4, 0x71, 0x62, 0xa4, 0xb3, 0x91, 0x18, 0xaa, 0x2e, 0x6b, 0x74, 0x2b, 0x62, 0xff,
+	0xcc, 0x43, 0x31, 0xae, 0xd3, 0x86, 0x8a, 0x6e, 0x26, 0x99, 0x3c, 0x6d, 0x41, 0xab, 0x3d, 0x34,
+	0x68, 0x8a, 0x21, 0xcf, 0xa0, 0x38, 0xbd, 0x0b, 0xf8, 0x22, 0xf1, 0xb8, 0xde, 0x4e, 0x76, 0xda,
+	0x89, 0x82, 0x43, 0x83, 0xc6, 0x59, 0xd2, 0x83, 0x7f, 0x7e, 0xdb, 0x6c, 0xe5, 0x6c, 0xb5, 0x7b,
+	0xf4, 0xc7, 0x52, 0xa8, 0x3e, 0x86, 0x06, 0xdd, 0xe7, 0x3b, 0x11, 0xf2, 0x12, 0x4c, 0xa1, 0xad,
+	0xb1, 0xf6, 0x14, 0xf9, 0x20, 0x6b, 0x2d, 0x49, 0x0c, 0x0d, 0x9a, 0xa1, 0xc8, 0x6b, 0x80, 0x75,
+	0xaa, 0xad, 0x55, 0x54, 0x1c, 0xa2, 0x39, 0x99, 0xea, 0x43, 0x83, 0x6e, 0xe1, 0xc8, 0x13, 0x28,
+	0x30, 0xbe, 0xb0, 0xca, 0x0a, 0x5e, 0xd5, 0xf0, 0x9e, 0x1a, 0x27, 0xca, 0xa8, 0xf5, 0x14, 0xc8,
+	0x64, 0x20, 0xac, 0x92, 0x92, 0x52, 0x1f, 0x77, 0x8c, 0xae, 0xec, 0x1a, 0xed, 0x94, 0x13, 0x89,
+	0x5f, 0x38, 0x60, 0xa6, 0x6f, 0x87, 0xd4, 0xa0, 0x42, 0x07, 0xef, 0x46, 0x37, 0xe3, 0x01, 0x6d,
+	0x18, 0xc4, 0x84, 0xa2, 0xf3, 0xe1, 0xaa, 0x7f, 0xde, 0xc8, 0x91, 0x3a, 0x98, 0xfd, 0x61, 0x6f,
+	0x74, 0xd9, 0xbf, 0x3a, 0x1b, 0x34, 0xf2, 0xd1, 0x91, 0x0e, 0xde, 0x0f, 0xfa, 0xe3, 0xd1, 0xd5,
+	0x65, 0xa3, 0xd0, 0x7d, 0x0b, 0xa5, 0x41, 0xbc, 0xd0, 0xa7, 0xb0, 0xd7, 0x9f, 0x33, 0x49, 0xd2,
+	0x05, 0xdd, 0xda, 0x8a, 0x66, 0x7d, 0xe7, 0xb1, 0xda, 0x46, 0x2b, 0x77, 0x9a, 0x73, 0xbe, 0x80,
+	0x1d, 0x08, 0xb7, 0x3d, 0xdf, 0xac, 0x50, 0xdc, 0xe1, 0xcc, 0x45, 0xd1, 0xbe, 0x65, 0x53, 0xe1,
+	0x71, 0x0d, 0x5e, 0x21, 0x0a, 0xa7, 0x1e, 0xd7, 0xbf, 0x66, 0x7c, 0xc1, 0x5c, 0xfc, 0xfc, 0xdc,
+	0xf5, 0xe4, 0x7c, 0x3d, 0x8d, 0xec, 0xed, 0x6c, 0x31, 0x3b, 0x31, 0xb3, 0x13, 0x33, 0x3b, 0x11,
+	0x73, 0x1a, 0xff, 0xcb, 0xbd, 0xfa, 0x35, 0x00, 0x0e, 0x17, 0x63, 0x2d, 0x01, 0x05, 0x00, 0x00,
 }
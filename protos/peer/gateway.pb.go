@@ -0,0 +1,627 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: peer/gateway.proto
+
+package peer
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+import common "github.com/hyperledger/fabric/protos/common"
+
+import (
+	context "golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+type TransactionStatus_Stage int32
+
+const (
+	TransactionStatus_SUBMITTED TransactionStatus_Stage = 0
+	TransactionStatus_COMMITTED TransactionStatus_Stage = 1
+)
+
+var TransactionStatus_Stage_name = map[int32]string{
+	0: "SUBMITTED",
+	1: "COMMITTED",
+}
+var TransactionStatus_Stage_value = map[string]int32{
+	"SUBMITTED": 0,
+	"COMMITTED": 1,
+}
+
+func (x TransactionStatus_Stage) String() string {
+	return proto.EnumName(TransactionStatus_Stage_name, int32(x))
+}
+func (TransactionStatus_Stage) EnumDescriptor() ([]byte, []int) { return fileDescriptor6, []int{3, 0} }
+
+type SubmitRequest struct {
+	// Types that are valid to be assigned to Type:
+	//	*SubmitRequest_Proposal
+	//	*SubmitRequest_Signature
+	Type isSubmitRequest_Type `protobuf_oneof:"type"`
+}
+
+func (m *SubmitRequest) Reset()                    { *m = SubmitRequest{} }
+func (m *SubmitRequest) String() string            { return proto.CompactTextString(m) }
+func (*SubmitRequest) ProtoMessage()               {}
+func (*SubmitRequest) Descriptor() ([]byte, []int) { return fileDescriptor6, []int{0} }
+
+type isSubmitRequest_Type interface {
+	isSubmitRequest_Type()
+}
+
+type SubmitRequest_Proposal struct {
+	Proposal *SignedProposal `protobuf:"bytes,1,opt,name=proposal,oneof"`
+}
+type SubmitRequest_Signature struct {
+	Signature []byte `protobuf:"bytes,2,opt,name=signature,proto3,oneof"`
+}
+
+func (*SubmitRequest_Proposal) isSubmitRequest_Type()  {}
+func (*SubmitRequest_Signature) isSubmitRequest_Type() {}
+
+func (m *SubmitRequest) GetType() isSubmitRequest_Type {
+	if m != nil {
+		return m.Type
+	}
+	return nil
+}
+
+func (m *SubmitRequest) GetProposal() *SignedProposal {
+	if x, ok := m.GetType().(*SubmitRequest_Proposal); ok {
+		return x.Proposal
+	}
+	return nil
+}
+
+func (m *SubmitRequest) GetSignature() []byte {
+	if x, ok := m.GetType().(*SubmitRequest_Signature); ok {
+		return x.Signature
+	}
+	return nil
+}
+
+// XXX_OneofFuncs is for the internal use of the proto package.
+func (*SubmitRequest) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
+	return _SubmitRequest_OneofMarshaler, _SubmitRequest_OneofUnmarshaler, _SubmitRequest_OneofSizer, []interface{}{
+		(*SubmitRequest_Proposal)(nil),
+		(*SubmitRequest_Signature)(nil),
+	}
+}
+
+func _SubmitRequest_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
+	m := msg.(*SubmitRequest)
+	// type
+	switch x := m.Type.(type) {
+	case *SubmitRequest_Proposal:
+		b.EncodeVarint(1<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.Proposal); err != nil {
+			return err
+		}
+	case *SubmitRequest_Signature:
+		b.EncodeVarint(2<<3 | proto.WireBytes)
+		b.EncodeRawBytes(x.Signature)
+	case nil:
+	default:
+		return fmt.Errorf("SubmitRequest.Type has unexpected type %T", x)
+	}
+	return nil
+}
+
+func _SubmitRequest_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error) {
+	m := msg.(*SubmitRequest)
+	switch tag {
+	case 1: // type.proposal
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(SignedProposal)
+		err := b.DecodeMessage(msg)
+		m.Type = &SubmitRequest_Proposal{msg}
+		return true, err
+	case 2: // type.signature
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		x, err := b.DecodeRawBytes(true)
+		m.Type = &SubmitRequest_Signature{x}
+		return true, err
+	default:
+		return false, nil
+	}
+}
+
+func _SubmitRequest_OneofSizer(msg proto.Message) (n int) {
+	m := msg.(*SubmitRequest)
+	// type
+	switch x := m.Type.(type) {
+	case *SubmitRequest_Proposal:
+		s := proto.Size(x.Proposal)
+		n += proto.SizeVarint(1<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *SubmitRequest_Signature:
+		n += proto.SizeVarint(2<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(len(x.Signature)))
+		n += len(x.Signature)
+	case nil:
+	default:
+		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
+	}
+	return n
+}
+
+type SubmitResponse struct {
+	// Types that are valid to be assigned to Type:
+	//	*SubmitResponse_Prepared
+	//	*SubmitResponse_Status
+	Type isSubmitResponse_Type `protobuf_oneof:"type"`
+}
+
+func (m *SubmitResponse) Reset()                    { *m = SubmitResponse{} }
+func (m *SubmitResponse) String() string            { return proto.CompactTextString(m) }
+func (*SubmitResponse) ProtoMessage()               {}
+func (*SubmitResponse) Descriptor() ([]byte, []int) { return fileDescriptor6, []int{1} }
+
+type isSubmitResponse_Type interface {
+	isSubmitResponse_Type()
+}
+
+type SubmitResponse_Prepared struct {
+	Prepared *PreparedTransaction `protobuf:"bytes,1,opt,name=prepared,oneof"`
+}
+type SubmitResponse_Status struct {
+	Status *TransactionStatus `protobuf:"bytes,2,opt,name=status,oneof"`
+}
+
+func (*SubmitResponse_Prepared) isSubmitResponse_Type() {}
+func (*SubmitResponse_Status) isSubmitResponse_Type()   {}
+
+func (m *SubmitResponse) GetType() isSubmitResponse_Type {
+	if m != nil {
+		return m.Type
+	}
+	return nil
+}
+
+func (m *SubmitResponse) GetPrepared() *PreparedTransaction {
+	if x, ok := m.GetType().(*SubmitResponse_Prepared); ok {
+		return x.Prepared
+	}
+	return nil
+}
+
+func (m *SubmitResponse) GetStatus() *TransactionStatus {
+	if x, ok := m.GetType().(*SubmitResponse_Status); ok {
+		return x.Status
+	}
+	return nil
+}
+
+// XXX_OneofFuncs is for the internal use of the proto package.
+func (*SubmitResponse) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
+	return _SubmitResponse_OneofMarshaler, _SubmitResponse_OneofUnmarshaler, _SubmitResponse_OneofSizer, []interface{}{
+		(*SubmitResponse_Prepared)(nil),
+		(*SubmitResponse_Status)(nil),
+	}
+}
+
+func _SubmitResponse_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
+	m := msg.(*SubmitResponse)
+	// type
+	switch x := m.Type.(type) {
+	case *SubmitResponse_Prepared:
+		b.EncodeVarint(1<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.Prepared); err != nil {
+			return err
+		}
+	case *SubmitResponse_Status:
+		b.EncodeVarint(2<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.Status); err != nil {
+			return err
+		}
+	case nil:
+	default:
+		return fmt.Errorf("SubmitResponse.Type has unexpected type %T", x)
+	}
+	return nil
+}
+
+func _SubmitResponse_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error) {
+	m := msg.(*SubmitResponse)
+	switch tag {
+	case 1: // type.prepared
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(PreparedTransaction)
+		err := b.DecodeMessage(msg)
+		m.Type = &SubmitResponse_Prepared{msg}
+		return true, err
+	case 2: // type.status
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(TransactionStatus)
+		err := b.DecodeMessage(msg)
+		m.Type = &SubmitResponse_Status{msg}
+		return true, err
+	default:
+		return false, nil
+	}
+}
+
+func _SubmitResponse_OneofSizer(msg proto.Message) (n int) {
+	m := msg.(*SubmitResponse)
+	// type
+	switch x := m.Type.(type) {
+	case *SubmitResponse_Prepared:
+		s := proto.Size(x.Prepared)
+		n += proto.SizeVarint(1<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *SubmitResponse_Status:
+		s := proto.Size(x.Status)
+		n += proto.SizeVarint(2<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case nil:
+	default:
+		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
+	}
+	return n
+}
+
+// PreparedTransaction is the transaction assembled from the endorsements of a proposal
+type PreparedTransaction struct {
+	TxId string `protobuf:"bytes,1,opt,name=tx_id,json=txId" json:"tx_id,omitempty"`
+	// Response of the chaincode to the proposal
+	Response *Response `protobuf:"bytes,2,opt,name=response" json:"response,omitempty"`
+	// Marshaled common.Payload of the transaction, to be signed by the client
+	Payload []byte `protobuf:"bytes,3,opt,name=payload,proto3" json:"payload,omitempty"`
+	// Endpoints of the peers which endorsed the proposal
+	Endorsers []string `protobuf:"bytes,4,rep,name=endorsers" json:"endorsers,omitempty"`
+}
+
+func (m *PreparedTransaction) Reset()                    { *m = PreparedTransaction{} }
+func (m *PreparedTransaction) String() string            { return proto.CompactTextString(m) }
+func (*PreparedTransaction) ProtoMessage()               {}
+func (*PreparedTransaction) Descriptor() ([]byte, []int) { return fileDescriptor6, []int{2} }
+
+func (m *PreparedTransaction) GetTxId() string {
+	if m != nil {
+		return m.TxId
+	}
+	return ""
+}
+
+func (m *PreparedTransaction) GetResponse() *Response {
+	if m != nil {
+		return m.Response
+	}
+	return nil
+}
+
+func (m *PreparedTransaction) GetPayload() []byte {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+func (m *PreparedTransaction) GetEndorsers() []string {
+	if m != nil {
+		return m.Endorsers
+	}
+	return nil
+}
+
+type TransactionStatus struct {
+	Stage TransactionStatus_Stage `protobuf:"varint,1,opt,name=stage,enum=protos.TransactionStatus_Stage" json:"stage,omitempty"`
+	TxId  string                  `protobuf:"bytes,2,opt,name=tx_id,json=txId" json:"tx_id,omitempty"`
+	// Number of the block which includes the transaction, once committed
+	BlockNumber uint64 `protobuf:"varint,3,opt,name=block_number,json=blockNumber" json:"block_number,omitempty"`
+	// Validation code of the transaction, once committed
+	ValidationCode TxValidationCode `protobuf:"varint,4,opt,name=validation_code,json=validationCode,enum=protos.TxValidationCode" json:"validation_code,omitempty"`
+}
+
+func (m *TransactionStatus) Reset()                    { *m = TransactionStatus{} }
+func (m *TransactionStatus) String() string            { return proto.CompactTextString(m) }
+func (*TransactionStatus) ProtoMessage()               {}
+func (*TransactionStatus) Descriptor() ([]byte, []int) { return fileDescriptor6, []int{3} }
+
+func (m *TransactionStatus) GetStage() TransactionStatus_Stage {
+	if m != nil {
+		return m.Stage
+	}
+	return TransactionStatus_SUBMITTED
+}
+
+func (m *TransactionStatus) GetTxId() string {
+	if m != nil {
+		return m.TxId
+	}
+	return ""
+}
+
+func (m *TransactionStatus) GetBlockNumber() uint64 {
+	if m != nil {
+		return m.BlockNumber
+	}
+	return 0
+}
+
+func (m *TransactionStatus) GetValidationCode() TxValidationCode {
+	if m != nil {
+		return m.ValidationCode
+	}
+	return TxValidationCode_VALID
+}
+
+func init() {
+	proto.RegisterType((*SubmitRequest)(nil), "protos.SubmitRequest")
+	proto.RegisterType((*SubmitResponse)(nil), "protos.SubmitResponse")
+	proto.RegisterType((*PreparedTransaction)(nil), "protos.PreparedTransaction")
+	proto.RegisterType((*TransactionStatus)(nil), "protos.TransactionStatus")
+	proto.RegisterEnum("protos.TransactionStatus_Stage", TransactionStatus_Stage_name, TransactionStatus_Stage_value)
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for Gateway service
+
+type GatewayClient interface {
+	// Submit opens a stream on which the client sends a SignedProposal, and then the
+	// signature of the PreparedTransaction answered by the gateway. The gateway then
+	// streams the status of the transaction until it is committed by the peer.
+	Submit(ctx context.Context, opts ...grpc.CallOption) (Gateway_SubmitClient, error)
+	// Prepare endorses a SignedProposal and answers the PreparedTransaction to be signed by
+	// the client. Along with Commit, it splits Submit for the clients which cannot open
+	// bidirectional streams, such as browsers through gRPC-web
+	Prepare(ctx context.Context, in *SignedProposal, opts ...grpc.CallOption) (*PreparedTransaction, error)
+	// Commit sends a transaction, i.e. the payload of a PreparedTransaction along with its
+	// signature, to the ordering service and streams its status until it is committed by the peer
+	Commit(ctx context.Context, in *common.Envelope, opts ...grpc.CallOption) (Gateway_CommitClient, error)
+}
+
+type gatewayClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewGatewayClient(cc *grpc.ClientConn) GatewayClient {
+	return &gatewayClient{cc}
+}
+
+func (c *gatewayClient) Submit(ctx context.Context, opts ...grpc.CallOption) (Gateway_SubmitClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Gateway_serviceDesc.Streams[0], c.cc, "/protos.Gateway/Submit", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &gatewaySubmitClient{stream}
+	return x, nil
+}
+
+type Gateway_SubmitClient interface {
+	Send(*SubmitRequest) error
+	Recv() (*SubmitResponse, error)
+	grpc.ClientStream
+}
+
+type gatewaySubmitClient struct {
+	grpc.ClientStream
+}
+
+func (x *gatewaySubmitClient) Send(m *SubmitRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *gatewaySubmitClient) Recv() (*SubmitResponse, error) {
+	m := new(SubmitResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *gatewayClient) Prepare(ctx context.Context, in *SignedProposal, opts ...grpc.CallOption) (*PreparedTransaction, error) {
+	out := new(PreparedTransaction)
+	err := grpc.Invoke(ctx, "/protos.Gateway/Prepare", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gatewayClient) Commit(ctx context.Context, in *common.Envelope, opts ...grpc.CallOption) (Gateway_CommitClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Gateway_serviceDesc.Streams[1], c.cc, "/protos.Gateway/Commit", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &gatewayCommitClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Gateway_CommitClient interface {
+	Recv() (*TransactionStatus, error)
+	grpc.ClientStream
+}
+
+type gatewayCommitClient struct {
+	grpc.ClientStream
+}
+
+func (x *gatewayCommitClient) Recv() (*TransactionStatus, error) {
+	m := new(TransactionStatus)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Server API for Gateway service
+
+type GatewayServer interface {
+	// Submit opens a stream on which the client sends a SignedProposal, and then the
+	// signature of the PreparedTransaction answered by the gateway. The gateway then
+	// streams the status of the transaction until it is committed by the peer.
+	Submit(Gateway_SubmitServer) error
+	// Prepare endorses a SignedProposal and answers the PreparedTransaction to be signed by
+	// the client. Along with Commit, it splits Submit for the clients which cannot open
+	// bidirectional streams, such as browsers through gRPC-web
+	Prepare(context.Context, *SignedProposal) (*PreparedTransaction, error)
+	// Commit sends a transaction, i.e. the payload of a PreparedTransaction along with its
+	// signature, to the ordering service and streams its status until it is committed by the peer
+	Commit(*common.Envelope, Gateway_CommitServer) error
+}
+
+func RegisterGatewayServer(s *grpc.Server, srv GatewayServer) {
+	s.RegisterService(&_Gateway_serviceDesc, srv)
+}
+
+func _Gateway_Submit_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(GatewayServer).Submit(&gatewaySubmitServer{stream})
+}
+
+type Gateway_SubmitServer interface {
+	Send(*SubmitResponse) error
+	Recv() (*SubmitRequest, error)
+	grpc.ServerStream
+}
+
+type gatewaySubmitServer struct {
+	grpc.ServerStream
+}
+
+func (x *gatewaySubmitServer) Send(m *SubmitResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *gatewaySubmitServer) Recv() (*SubmitRequest, error) {
+	m := new(SubmitRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Gateway_Prepare_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignedProposal)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GatewayServer).Prepare(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/protos.Gateway/Prepare",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GatewayServer).Prepare(ctx, req.(*SignedProposal))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gateway_Commit_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(common.Envelope)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GatewayServer).Commit(m, &gatewayCommitServer{stream})
+}
+
+type Gateway_CommitServer interface {
+	Send(*TransactionStatus) error
+	grpc.ServerStream
+}
+
+type gatewayCommitServer struct {
+	grpc.ServerStream
+}
+
+func (x *gatewayCommitServer) Send(m *TransactionStatus) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _Gateway_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "protos.Gateway",
+	HandlerType: (*GatewayServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Prepare",
+			Handler:    _Gateway_Prepare_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Submit",
+			Handler:       _Gateway_Submit_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "Commit",
+			Handler:       _Gateway_Commit_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "peer/gateway.proto",
+}
+
+func init() { proto.RegisterFile("peer/gateway.proto", fileDescriptor6) }
+
+var fileDescriptor6 = []byte{
+	// 559 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x7c, 0x54, 0x41, 0x6f, 0xd3, 0x4c,
+	0x10, 0xb5, 0xdb, 0x34, 0x6d, 0xa6, 0x6d, 0xbe, 0x7e, 0x1b, 0x51, 0x99, 0x50, 0x41, 0xb1, 0x84,
+	0x14, 0x24, 0x64, 0x57, 0x29, 0x1c, 0x7a, 0x40, 0x82, 0x84, 0x8a, 0xf4, 0x50, 0x88, 0x9c, 0xc0,
+	0x81, 0x4b, 0xb4, 0xb6, 0x07, 0xd7, 0xaa, 0xed, 0x35, 0xbb, 0xeb, 0x90, 0xfc, 0x03, 0x8e, 0xfc,
+	0x29, 0xfe, 0x0d, 0x3f, 0x02, 0xd9, 0x6b, 0x3b, 0x29, 0x10, 0x4e, 0xd6, 0xbc, 0x79, 0x6f, 0xdf,
+	0x9b, 0xcd, 0x6c, 0x80, 0xa4, 0x88, 0xdc, 0x0e, 0xa8, 0xc4, 0xaf, 0x74, 0x69, 0xa5, 0x9c, 0x49,
+	0x46, 0x9a, 0xc5, 0x47, 0x74, 0x3b, 0x1e, 0x8b, 0x63, 0x96, 0xd8, 0xea, 0xa3, 0x9a, 0xdd, 0x4e,
+	0x21, 0x48, 0x39, 0x4b, 0x99, 0xa0, 0x51, 0x09, 0x9e, 0xdc, 0x01, 0x67, 0x1c, 0x45, 0xca, 0x12,
+	0x81, 0x65, 0xf7, 0xb8, 0xe8, 0x4a, 0x4e, 0x13, 0x41, 0x3d, 0x19, 0x56, 0x47, 0x99, 0x31, 0x1c,
+	0x4e, 0x32, 0x37, 0x0e, 0xa5, 0x83, 0x5f, 0x32, 0x14, 0x92, 0x3c, 0x87, 0xbd, 0xea, 0x0c, 0x43,
+	0x3f, 0xd5, 0x7b, 0xfb, 0xfd, 0x63, 0x45, 0x15, 0xd6, 0x24, 0x0c, 0x12, 0xf4, 0xc7, 0x65, 0x77,
+	0xa4, 0x39, 0x35, 0x93, 0x3c, 0x84, 0x96, 0x08, 0x83, 0x84, 0xca, 0x8c, 0xa3, 0xb1, 0x75, 0xaa,
+	0xf7, 0x0e, 0x46, 0x9a, 0xb3, 0x82, 0x06, 0x4d, 0x68, 0xc8, 0x65, 0x8a, 0xe6, 0x37, 0x1d, 0xda,
+	0x95, 0x9f, 0xca, 0x47, 0x2e, 0x72, 0x43, 0x4c, 0x29, 0x47, 0xbf, 0x34, 0x7c, 0x50, 0x19, 0x8e,
+	0x4b, 0x7c, 0xba, 0x8a, 0xad, 0x5c, 0x15, 0x4c, 0xce, 0xa1, 0x29, 0x24, 0x95, 0x99, 0x28, 0x2c,
+	0xf7, 0xfb, 0xf7, 0x2b, 0xe1, 0x9a, 0x60, 0x52, 0x10, 0x46, 0x9a, 0x53, 0x52, 0xeb, 0x28, 0xdf,
+	0x75, 0xe8, 0xfc, 0xc5, 0x80, 0x74, 0x60, 0x47, 0x2e, 0x66, 0xa1, 0x0a, 0xd3, 0x72, 0x1a, 0x72,
+	0x71, 0xe5, 0x93, 0x67, 0xb0, 0x57, 0x5d, 0x68, 0xe9, 0x75, 0x54, 0x79, 0x55, 0x83, 0x38, 0x35,
+	0x83, 0x18, 0xb0, 0x9b, 0xd2, 0x65, 0xc4, 0xa8, 0x6f, 0x6c, 0xe7, 0x77, 0xe1, 0x54, 0x25, 0x39,
+	0x81, 0x16, 0x26, 0x3e, 0xe3, 0x02, 0xb9, 0x30, 0x1a, 0xa7, 0xdb, 0xbd, 0x96, 0xb3, 0x02, 0xcc,
+	0x9f, 0x3a, 0xfc, 0xff, 0x47, 0x74, 0xf2, 0x02, 0x76, 0x84, 0xa4, 0x01, 0x16, 0x81, 0xda, 0xfd,
+	0x47, 0x1b, 0x87, 0xb4, 0x26, 0x39, 0xcd, 0x51, 0xec, 0xd5, 0x1c, 0x5b, 0x6b, 0x73, 0x3c, 0x86,
+	0x03, 0x37, 0x62, 0xde, 0xed, 0x2c, 0xc9, 0x62, 0x17, 0x79, 0x11, 0xaf, 0xe1, 0xec, 0x17, 0xd8,
+	0xbb, 0x02, 0x22, 0xaf, 0xe1, 0xbf, 0x39, 0x8d, 0x42, 0x9f, 0xe6, 0x07, 0xcf, 0x3c, 0xe6, 0xa3,
+	0xd1, 0x28, 0x8c, 0x8d, 0xda, 0x78, 0xf1, 0xb1, 0x26, 0x0c, 0x99, 0x8f, 0x4e, 0x7b, 0x7e, 0xa7,
+	0x36, 0x9f, 0xc0, 0x4e, 0x11, 0x85, 0x1c, 0x42, 0x6b, 0xf2, 0x61, 0x70, 0x7d, 0x35, 0x9d, 0x5e,
+	0xbe, 0x39, 0xd2, 0xf2, 0x72, 0xf8, 0xfe, 0xba, 0x2c, 0xf5, 0xfe, 0x0f, 0x1d, 0x76, 0xdf, 0xaa,
+	0xad, 0x27, 0x2f, 0xa1, 0xa9, 0xf6, 0x82, 0xdc, 0xab, 0xd7, 0x6d, 0x7d, 0x2f, 0xbb, 0xc7, 0xbf,
+	0xc3, 0xea, 0xae, 0x4d, 0xad, 0xa7, 0x9f, 0xe9, 0xe4, 0x15, 0xec, 0x96, 0xbf, 0x25, 0xd9, 0xb0,
+	0xae, 0xdd, 0x7f, 0x6d, 0x95, 0xa9, 0x91, 0x0b, 0x68, 0x0e, 0x59, 0x9c, 0x07, 0x38, 0xb2, 0xca,
+	0xc7, 0x76, 0x99, 0xcc, 0x31, 0x62, 0x29, 0x76, 0x37, 0xef, 0x95, 0xa9, 0x9d, 0xe9, 0x83, 0x19,
+	0x98, 0x8c, 0x07, 0xd6, 0xcd, 0x32, 0x45, 0x1e, 0xa1, 0x1f, 0x20, 0xb7, 0x3e, 0x53, 0x97, 0x87,
+	0x5e, 0x25, 0xcb, 0xdf, 0xde, 0xa0, 0x5d, 0x8e, 0x3a, 0xa6, 0xde, 0x2d, 0x0d, 0xf0, 0xd3, 0xd3,
+	0x20, 0x94, 0x37, 0x99, 0x9b, 0x9b, 0xd9, 0x6b, 0x52, 0x5b, 0x49, 0x6d, 0x25, 0xb5, 0x73, 0xa9,
+	0xab, 0xfe, 0x0c, 0xce, 0x7f, 0x0d, 0x00, 0x11, 0xe2, 0xa4, 0x04, 0x29, 0x04, 0x00, 0x00,
+}
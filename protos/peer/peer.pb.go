@@ -24,7 +24,7 @@ type PeerID struct {
 func (m *PeerID) Reset()                    { *m = PeerID{} }
 func (m *PeerID) String() string            { return proto.CompactTextString(m) }
 func (*PeerID) ProtoMessage()               {}
-func (*PeerID) Descriptor() ([]byte, []int) { return fileDescriptor6, []int{0} }
+func (*PeerID) Descriptor() ([]byte, []int) { return fileDescriptor7, []int{0} }
 
 func (m *PeerID) GetName() string {
 	if m != nil {
@@ -41,7 +41,7 @@ type PeerEndpoint struct {
 func (m *PeerEndpoint) Reset()                    { *m = PeerEndpoint{} }
 func (m *PeerEndpoint) String() string            { return proto.CompactTextString(m) }
 func (*PeerEndpoint) ProtoMessage()               {}
-func (*PeerEndpoint) Descriptor() ([]byte, []int) { return fileDescriptor6, []int{1} }
+func (*PeerEndpoint) Descriptor() ([]byte, []int) { return fileDescriptor7, []int{1} }
 
 func (m *PeerEndpoint) GetId() *PeerID {
 	if m != nil {
@@ -57,9 +57,36 @@ func (m *PeerEndpoint) GetAddress() string {
 	return ""
 }
 
+// HistoricalProposal is a proposal to simulate against the state as of a past
+// height of the ledger, i.e. after the commit of the blocks below the height
+type HistoricalProposal struct {
+	SignedProposal *SignedProposal `protobuf:"bytes,1,opt,name=signed_proposal,json=signedProposal" json:"signed_proposal,omitempty"`
+	Height         uint64          `protobuf:"varint,2,opt,name=height" json:"height,omitempty"`
+}
+
+func (m *HistoricalProposal) Reset()                    { *m = HistoricalProposal{} }
+func (m *HistoricalProposal) String() string            { return proto.CompactTextString(m) }
+func (*HistoricalProposal) ProtoMessage()               {}
+func (*HistoricalProposal) Descriptor() ([]byte, []int) { return fileDescriptor7, []int{2} }
+
+func (m *HistoricalProposal) GetSignedProposal() *SignedProposal {
+	if m != nil {
+		return m.SignedProposal
+	}
+	return nil
+}
+
+func (m *HistoricalProposal) GetHeight() uint64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*PeerID)(nil), "protos.PeerID")
 	proto.RegisterType((*PeerEndpoint)(nil), "protos.PeerEndpoint")
+	proto.RegisterType((*HistoricalProposal)(nil), "protos.HistoricalProposal")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -74,6 +101,17 @@ const _ = grpc.SupportPackageIsVersion4
 
 type EndorserClient interface {
 	ProcessProposal(ctx context.Context, in *SignedProposal, opts ...grpc.CallOption) (*ProposalResponse, error)
+	// SimulateProposal executes a proposal of an application chaincode like
+	// ProcessProposal, but returns its simulation results and the response of
+	// the chaincode without endorsing them, for applications to preview the
+	// outcome of a transaction
+	SimulateProposal(ctx context.Context, in *SignedProposal, opts ...grpc.CallOption) (*ProposalResponse, error)
+	// SimulateProposalAt executes a proposal of an application chaincode like
+	// SimulateProposal, but against the state of the channel as of a past
+	// height of its ledger, for audit queries. The chaincode may only read the
+	// state and the history of keys, not write to it nor run range or rich
+	// queries
+	SimulateProposalAt(ctx context.Context, in *HistoricalProposal, opts ...grpc.CallOption) (*ProposalResponse, error)
 }
 
 type endorserClient struct {
@@ -93,10 +131,39 @@ func (c *endorserClient) ProcessProposal(ctx context.Context, in *SignedProposal
 	return out, nil
 }
 
+func (c *endorserClient) SimulateProposal(ctx context.Context, in *SignedProposal, opts ...grpc.CallOption) (*ProposalResponse, error) {
+	out := new(ProposalResponse)
+	err := grpc.Invoke(ctx, "/protos.Endorser/SimulateProposal", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *endorserClient) SimulateProposalAt(ctx context.Context, in *HistoricalProposal, opts ...grpc.CallOption) (*ProposalResponse, error) {
+	out := new(ProposalResponse)
+	err := grpc.Invoke(ctx, "/protos.Endorser/SimulateProposalAt", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for Endorser service
 
 type EndorserServer interface {
 	ProcessProposal(context.Context, *SignedProposal) (*ProposalResponse, error)
+	// SimulateProposal executes a proposal of an application chaincode like
+	// ProcessProposal, but returns its simulation results and the response of
+	// the chaincode without endorsing them, for applications to preview the
+	// outcome of a transaction
+	SimulateProposal(context.Context, *SignedProposal) (*ProposalResponse, error)
+	// SimulateProposalAt executes a proposal of an application chaincode like
+	// SimulateProposal, but against the state of the channel as of a past
+	// height of its ledger, for audit queries. The chaincode may only read the
+	// state and the history of keys, not write to it nor run range or rich
+	// queries
+	SimulateProposalAt(context.Context, *HistoricalProposal) (*ProposalResponse, error)
 }
 
 func RegisterEndorserServer(s *grpc.Server, srv EndorserServer) {
@@ -121,6 +188,42 @@ func _Endorser_ProcessProposal_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Endorser_SimulateProposal_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignedProposal)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EndorserServer).SimulateProposal(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/protos.Endorser/SimulateProposal",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EndorserServer).SimulateProposal(ctx, req.(*SignedProposal))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Endorser_SimulateProposalAt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HistoricalProposal)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EndorserServer).SimulateProposalAt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/protos.Endorser/SimulateProposalAt",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EndorserServer).SimulateProposalAt(ctx, req.(*HistoricalProposal))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Endorser_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "protos.Endorser",
 	HandlerType: (*EndorserServer)(nil),
@@ -129,29 +232,41 @@ var _Endorser_serviceDesc = grpc.ServiceDesc{
 			MethodName: "ProcessProposal",
 			Handler:    _Endorser_ProcessProposal_Handler,
 		},
+		{
+			MethodName: "SimulateProposal",
+			Handler:    _Endorser_SimulateProposal_Handler,
+		},
+		{
+			MethodName: "SimulateProposalAt",
+			Handler:    _Endorser_SimulateProposalAt_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "peer/peer.proto",
 }
 
-func init() { proto.RegisterFile("peer/peer.proto", fileDescriptor6) }
-
-var fileDescriptor6 = []byte{
-	// 246 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x54, 0x90, 0x4f, 0x4b, 0xc4, 0x30,
-	0x10, 0xc5, 0xdd, 0x22, 0xab, 0x8e, 0xe2, 0x42, 0x04, 0x29, 0x65, 0x11, 0xe9, 0x49, 0x2f, 0x29,
-	0xac, 0xdf, 0x40, 0x2c, 0xe8, 0xc9, 0x5a, 0x6f, 0x5e, 0xa4, 0x6d, 0xc6, 0x6e, 0x60, 0x37, 0x13,
-	0x66, 0xea, 0xc1, 0x6f, 0x2f, 0x4d, 0x5a, 0x71, 0x2f, 0xf9, 0xf3, 0xde, 0x6f, 0x5e, 0x26, 0x03,
-	0x2b, 0x8f, 0xc8, 0xc5, 0xb8, 0x68, 0xcf, 0x34, 0x90, 0x5a, 0x86, 0x4d, 0xb2, 0xab, 0x68, 0x30,
-	0x79, 0x92, 0x66, 0x17, 0xcd, 0x6c, 0x7d, 0x20, 0x7e, 0x32, 0x8a, 0x27, 0x27, 0x18, 0xdd, 0x7c,
-	0x0d, 0xcb, 0x0a, 0x91, 0x5f, 0x9e, 0x94, 0x82, 0x63, 0xd7, 0xec, 0x31, 0x5d, 0xdc, 0x2e, 0xee,
-	0xce, 0xea, 0x70, 0xce, 0x9f, 0xe1, 0x62, 0x74, 0x4b, 0x67, 0x3c, 0x59, 0x37, 0xa8, 0x1b, 0x48,
-	0xac, 0x09, 0xc4, 0xf9, 0xe6, 0x32, 0x26, 0x88, 0x8e, 0xf5, 0x75, 0x62, 0x8d, 0x4a, 0xe1, 0xa4,
-	0x31, 0x86, 0x51, 0x24, 0x4d, 0x42, 0xcc, 0x7c, 0xdd, 0xbc, 0xc1, 0x69, 0xe9, 0x0c, 0xb1, 0x20,
-	0xab, 0x12, 0x56, 0x15, 0x53, 0x87, 0x22, 0xd5, 0xd4, 0x95, 0xba, 0x9e, 0xc3, 0xde, 0x6d, 0xef,
-	0xd0, 0xcc, 0x7a, 0x96, 0xfe, 0x3d, 0x32, 0x29, 0xf5, 0xd4, 0x7e, 0x7e, 0xf4, 0xf8, 0x0a, 0x39,
-	0x71, 0xaf, 0xb7, 0x3f, 0x1e, 0x79, 0x87, 0xa6, 0x47, 0xd6, 0x5f, 0x4d, 0xcb, 0xb6, 0x9b, 0x6b,
-	0xc6, 0x8f, 0x7f, 0xdc, 0xf7, 0x76, 0xd8, 0x7e, 0xb7, 0xba, 0xa3, 0x7d, 0xf1, 0x0f, 0x2d, 0x22,
-	0x5a, 0x44, 0x34, 0x0c, 0xb3, 0x8d, 0x63, 0x7c, 0xf8, 0x0d, 0x00, 0x00, 0xff, 0xff, 0xef, 0x32,
-	0xf2, 0x1f, 0x60, 0x01, 0x00, 0x00,
+func init() { proto.RegisterFile("peer/peer.proto", fileDescriptor7) }
+
+var fileDescriptor7 = []byte{
+	// 320 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xa4, 0x92, 0x41, 0x4b, 0xeb, 0x40,
+	0x10, 0xc7, 0x5f, 0x4b, 0xe9, 0x7b, 0x6f, 0x94, 0x56, 0x56, 0x28, 0x21, 0x14, 0x91, 0x9c, 0xf4,
+	0x92, 0x40, 0xfd, 0x00, 0xa2, 0x58, 0xa9, 0x20, 0x58, 0xd2, 0x9b, 0x97, 0x92, 0x66, 0xc7, 0x64,
+	0x21, 0xc9, 0x86, 0x99, 0xed, 0xc1, 0x4f, 0xec, 0xd7, 0x90, 0xee, 0x66, 0xd5, 0xaa, 0x78, 0xf1,
+	0x92, 0x64, 0xf2, 0xff, 0xff, 0x7f, 0x3b, 0x99, 0x0c, 0x8c, 0x5b, 0x44, 0x4a, 0x76, 0x97, 0xb8,
+	0x25, 0x6d, 0xb4, 0x18, 0xda, 0x1b, 0x87, 0xc7, 0x4e, 0x20, 0xdd, 0x6a, 0xce, 0x2a, 0x27, 0x86,
+	0xd3, 0xbd, 0x97, 0x6b, 0x42, 0x6e, 0x75, 0xc3, 0xe8, 0xd4, 0x68, 0x0a, 0xc3, 0x25, 0x22, 0xdd,
+	0xdd, 0x08, 0x01, 0x83, 0x26, 0xab, 0x31, 0xe8, 0x9d, 0xf6, 0xce, 0xfe, 0xa7, 0xf6, 0x39, 0x5a,
+	0xc0, 0xe1, 0x4e, 0x9d, 0x37, 0xb2, 0xd5, 0xaa, 0x31, 0xe2, 0x04, 0xfa, 0x4a, 0x5a, 0xc7, 0xc1,
+	0x6c, 0xe4, 0x08, 0x1c, 0xbb, 0x7c, 0xda, 0x57, 0x52, 0x04, 0xf0, 0x37, 0x93, 0x92, 0x90, 0x39,
+	0xe8, 0x5b, 0x8c, 0x2f, 0xa3, 0x1a, 0xc4, 0x42, 0xb1, 0xd1, 0xa4, 0xf2, 0xac, 0x5a, 0x76, 0xcd,
+	0x88, 0x4b, 0x18, 0xb3, 0x2a, 0x1a, 0x94, 0x6b, 0xdf, 0x5f, 0x07, 0x9f, 0x78, 0xf8, 0xca, 0xca,
+	0x3e, 0x90, 0x8e, 0x78, 0xaf, 0x16, 0x13, 0x18, 0x96, 0xa8, 0x8a, 0xd2, 0xd8, 0xf3, 0x06, 0x69,
+	0x57, 0xcd, 0x5e, 0x7a, 0xf0, 0x6f, 0xde, 0x48, 0x4d, 0x8c, 0x24, 0xe6, 0x30, 0x5e, 0x92, 0xce,
+	0x91, 0xf9, 0x3d, 0xf7, 0x3d, 0x3f, 0x0c, 0xde, 0x3e, 0xca, 0x9f, 0xd8, 0x8d, 0x2b, 0xfa, 0x23,
+	0x6e, 0xe1, 0x68, 0xa5, 0xea, 0x6d, 0x95, 0x19, 0xfc, 0x15, 0xe7, 0x1e, 0xc4, 0x67, 0xce, 0x95,
+	0x11, 0xa1, 0x4f, 0x7c, 0x1d, 0xd3, 0x4f, 0xb4, 0xeb, 0x07, 0x88, 0x34, 0x15, 0x71, 0xf9, 0xdc,
+	0x22, 0x55, 0x28, 0x0b, 0xa4, 0xf8, 0x29, 0xdb, 0x90, 0xca, 0x7d, 0x66, 0xf7, 0xfb, 0x1f, 0xcf,
+	0x0b, 0x65, 0xca, 0xed, 0x26, 0xce, 0x75, 0x9d, 0x7c, 0xb0, 0x26, 0xce, 0x9a, 0x38, 0xab, 0x5d,
+	0xa9, 0x8d, 0x5b, 0xa6, 0x8b, 0xd7, 0x01, 0x00, 0x32, 0xf7, 0xae, 0x98, 0x66, 0x02, 0x00, 0x00,
 }
@@ -48,7 +48,7 @@ type SignedProposal struct {
 func (m *SignedProposal) Reset()                    { *m = SignedProposal{} }
 func (m *SignedProposal) String() string            { return proto.CompactTextString(m) }
 func (*SignedProposal) ProtoMessage()               {}
-func (*SignedProposal) Descriptor() ([]byte, []int) { return fileDescriptor7, []int{0} }
+func (*SignedProposal) Descriptor() ([]byte, []int) { return fileDescriptor8, []int{0} }
 
 func (m *SignedProposal) GetProposalBytes() []byte {
 	if m != nil {
@@ -98,7 +98,7 @@ type Proposal struct {
 func (m *Proposal) Reset()                    { *m = Proposal{} }
 func (m *Proposal) String() string            { return proto.CompactTextString(m) }
 func (*Proposal) ProtoMessage()               {}
-func (*Proposal) Descriptor() ([]byte, []int) { return fileDescriptor7, []int{1} }
+func (*Proposal) Descriptor() ([]byte, []int) { return fileDescriptor8, []int{1} }
 
 func (m *Proposal) GetHeader() []byte {
 	if m != nil {
@@ -144,7 +144,7 @@ type ChaincodeHeaderExtension struct {
 func (m *ChaincodeHeaderExtension) Reset()                    { *m = ChaincodeHeaderExtension{} }
 func (m *ChaincodeHeaderExtension) String() string            { return proto.CompactTextString(m) }
 func (*ChaincodeHeaderExtension) ProtoMessage()               {}
-func (*ChaincodeHeaderExtension) Descriptor() ([]byte, []int) { return fileDescriptor7, []int{2} }
+func (*ChaincodeHeaderExtension) Descriptor() ([]byte, []int) { return fileDescriptor8, []int{2} }
 
 func (m *ChaincodeHeaderExtension) GetPayloadVisibility() []byte {
 	if m != nil {
@@ -177,7 +177,7 @@ type ChaincodeProposalPayload struct {
 func (m *ChaincodeProposalPayload) Reset()                    { *m = ChaincodeProposalPayload{} }
 func (m *ChaincodeProposalPayload) String() string            { return proto.CompactTextString(m) }
 func (*ChaincodeProposalPayload) ProtoMessage()               {}
-func (*ChaincodeProposalPayload) Descriptor() ([]byte, []int) { return fileDescriptor7, []int{3} }
+func (*ChaincodeProposalPayload) Descriptor() ([]byte, []int) { return fileDescriptor8, []int{3} }
 
 func (m *ChaincodeProposalPayload) GetInput() []byte {
 	if m != nil {
@@ -215,7 +215,7 @@ type ChaincodeAction struct {
 func (m *ChaincodeAction) Reset()                    { *m = ChaincodeAction{} }
 func (m *ChaincodeAction) String() string            { return proto.CompactTextString(m) }
 func (*ChaincodeAction) ProtoMessage()               {}
-func (*ChaincodeAction) Descriptor() ([]byte, []int) { return fileDescriptor7, []int{4} }
+func (*ChaincodeAction) Descriptor() ([]byte, []int) { return fileDescriptor8, []int{4} }
 
 func (m *ChaincodeAction) GetResults() []byte {
 	if m != nil {
@@ -253,10 +253,10 @@ func init() {
 	proto.RegisterType((*ChaincodeAction)(nil), "protos.ChaincodeAction")
 }
 
-func init() { proto.RegisterFile("peer/proposal.proto", fileDescriptor7) }
+func init() { proto.RegisterFile("peer/proposal.proto", fileDescriptor8) }
 
-var fileDescriptor7 = []byte{
-	// 452 bytes of a gzipped FileDescriptorProto
+var fileDescriptor8 = []byte{
+	// 449 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x8c, 0x53, 0x4d, 0x6f, 0xd3, 0x4c,
 	0x10, 0x96, 0x93, 0xf7, 0xed, 0xc7, 0x24, 0xf4, 0x63, 0x5b, 0x21, 0x2b, 0xea, 0xa1, 0xb2, 0x84,
 	0x54, 0x24, 0xb0, 0xa5, 0x20, 0x21, 0xc4, 0x05, 0x11, 0xa8, 0x44, 0x0f, 0x48, 0x95, 0x81, 0x1e,
@@ -284,6 +284,6 @@ var fileDescriptor7 = []byte{
 	0xfb, 0xe2, 0x2b, 0x24, 0x42, 0xd5, 0xe9, 0xaa, 0x93, 0xa8, 0x1a, 0xac, 0x6a, 0x54, 0xe9, 0x37,
 	0x5a, 0x28, 0x56, 0x7a, 0x66, 0xff, 0xd8, 0x17, 0x87, 0xf7, 0x1e, 0x96, 0x77, 0xb4, 0xc6, 0xdb,
 	0xa7, 0x35, 0x33, 0xab, 0xb6, 0x48, 0x4b, 0xf1, 0x3d, 0xdb, 0xe0, 0x66, 0x96, 0x9b, 0x59, 0x6e,
-	0xd6, 0x73, 0x0b, 0xfb, 0x31, 0xbd, 0xf8, 0x13, 0x00, 0x00, 0xff, 0xff, 0x12, 0x75, 0xb6, 0xaf,
-	0x6a, 0x03, 0x00, 0x00,
+	0xd6, 0x73, 0x0b, 0xfb, 0x31, 0xbd, 0xf8, 0x33, 0x00, 0x12, 0x75, 0xb6, 0xaf, 0x6a, 0x03, 0x00,
+	0x00,
 }
@@ -35,12 +35,18 @@ type ProposalResponse struct {
 	// The endorsement of the proposal, basically
 	// the endorser's signature over the payload
 	Endorsement *Endorsement `protobuf:"bytes,6,opt,name=endorsement" json:"endorsement,omitempty"`
+	// The height of the ledger of the channel, i.e. the number of blocks
+	// committed to its state, against which the proposal was simulated.
+	// Clients aggregating the responses of several peers use it to detect
+	// stale reads. It is not part of the payload, so that peers at different
+	// heights still produce matching endorsements
+	SimulationHeight uint64 `protobuf:"varint,7,opt,name=simulation_height,json=simulationHeight" json:"simulation_height,omitempty"`
 }
 
 func (m *ProposalResponse) Reset()                    { *m = ProposalResponse{} }
 func (m *ProposalResponse) String() string            { return proto.CompactTextString(m) }
 func (*ProposalResponse) ProtoMessage()               {}
-func (*ProposalResponse) Descriptor() ([]byte, []int) { return fileDescriptor8, []int{0} }
+func (*ProposalResponse) Descriptor() ([]byte, []int) { return fileDescriptor9, []int{0} }
 
 func (m *ProposalResponse) GetVersion() int32 {
 	if m != nil {
@@ -77,6 +83,13 @@ func (m *ProposalResponse) GetEndorsement() *Endorsement {
 	return nil
 }
 
+func (m *ProposalResponse) GetSimulationHeight() uint64 {
+	if m != nil {
+		return m.SimulationHeight
+	}
+	return 0
+}
+
 // A response with a representation similar to an HTTP response that can
 // be used within another message.
 type Response struct {
@@ -91,7 +104,7 @@ type Response struct {
 func (m *Response) Reset()                    { *m = Response{} }
 func (m *Response) String() string            { return proto.CompactTextString(m) }
 func (*Response) ProtoMessage()               {}
-func (*Response) Descriptor() ([]byte, []int) { return fileDescriptor8, []int{1} }
+func (*Response) Descriptor() ([]byte, []int) { return fileDescriptor9, []int{1} }
 
 func (m *Response) GetStatus() int32 {
 	if m != nil {
@@ -147,7 +160,7 @@ type ProposalResponsePayload struct {
 func (m *ProposalResponsePayload) Reset()                    { *m = ProposalResponsePayload{} }
 func (m *ProposalResponsePayload) String() string            { return proto.CompactTextString(m) }
 func (*ProposalResponsePayload) ProtoMessage()               {}
-func (*ProposalResponsePayload) Descriptor() ([]byte, []int) { return fileDescriptor8, []int{2} }
+func (*ProposalResponsePayload) Descriptor() ([]byte, []int) { return fileDescriptor9, []int{2} }
 
 func (m *ProposalResponsePayload) GetProposalHash() []byte {
 	if m != nil {
@@ -183,7 +196,7 @@ type Endorsement struct {
 func (m *Endorsement) Reset()                    { *m = Endorsement{} }
 func (m *Endorsement) String() string            { return proto.CompactTextString(m) }
 func (*Endorsement) ProtoMessage()               {}
-func (*Endorsement) Descriptor() ([]byte, []int) { return fileDescriptor8, []int{3} }
+func (*Endorsement) Descriptor() ([]byte, []int) { return fileDescriptor9, []int{3} }
 
 func (m *Endorsement) GetEndorser() []byte {
 	if m != nil {
@@ -206,31 +219,33 @@ func init() {
 	proto.RegisterType((*Endorsement)(nil), "protos.Endorsement")
 }
 
-func init() { proto.RegisterFile("peer/proposal_response.proto", fileDescriptor8) }
-
-var fileDescriptor8 = []byte{
-	// 367 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x6c, 0x92, 0x51, 0x4b, 0xfb, 0x30,
-	0x14, 0xc5, 0xe9, 0xfe, 0xff, 0xcd, 0x2d, 0x9b, 0x30, 0x2a, 0x68, 0x19, 0x03, 0x47, 0x7d, 0x99,
-	0x20, 0x29, 0x28, 0x82, 0xcf, 0x03, 0xd1, 0xc7, 0x11, 0xc4, 0x07, 0x11, 0x24, 0xdd, 0xee, 0xd2,
-	0x62, 0xdb, 0x84, 0xdc, 0x54, 0xdc, 0x07, 0xf6, 0x7b, 0x48, 0xd3, 0xa6, 0xab, 0xe2, 0xd3, 0x38,
-	0x77, 0x27, 0xbf, 0x7b, 0xcf, 0xed, 0x25, 0x73, 0x05, 0xa0, 0x23, 0xa5, 0xa5, 0x92, 0xc8, 0xb3,
-	0x37, 0x0d, 0xa8, 0x64, 0x81, 0x40, 0x95, 0x96, 0x46, 0xfa, 0x03, 0xfb, 0x83, 0xb3, 0x73, 0x21,
-	0xa5, 0xc8, 0x20, 0xb2, 0x32, 0x2e, 0x77, 0x91, 0x49, 0x73, 0x40, 0xc3, 0x73, 0x55, 0x1b, 0xc3,
-	0x2f, 0x8f, 0x4c, 0xd7, 0x0d, 0x84, 0x35, 0x0c, 0x3f, 0x20, 0x47, 0x1f, 0xa0, 0x31, 0x95, 0x45,
-	0xe0, 0x2d, 0xbc, 0x65, 0x9f, 0x39, 0xe9, 0xdf, 0x91, 0x51, 0x4b, 0x08, 0x7a, 0x0b, 0x6f, 0x39,
-	0xbe, 0x9e, 0xd1, 0xba, 0x07, 0x75, 0x3d, 0xe8, 0x93, 0x73, 0xb0, 0x83, 0xd9, 0xbf, 0x22, 0x43,
-	0x37, 0x63, 0xf0, 0xdf, 0x3e, 0x9c, 0xd6, 0x2f, 0x90, 0xba, 0xbe, 0xac, 0x75, 0x54, 0x13, 0x28,
-	0xbe, 0xcf, 0x24, 0xdf, 0x06, 0xfd, 0x85, 0xb7, 0x9c, 0x30, 0x27, 0xfd, 0x5b, 0x32, 0x86, 0x62,
-	0x2b, 0x35, 0x42, 0x0e, 0x85, 0x09, 0x06, 0x16, 0x75, 0xe2, 0x50, 0xf7, 0x87, 0xbf, 0x58, 0xd7,
-	0x17, 0x3e, 0x93, 0x61, 0x1b, 0xef, 0x94, 0x0c, 0xd0, 0x70, 0x53, 0x62, 0x93, 0xae, 0x51, 0x55,
-	0xd3, 0x1c, 0x10, 0xb9, 0x00, 0x1b, 0x6d, 0xc4, 0x9c, 0xec, 0x8e, 0xf3, 0xef, 0xc7, 0x38, 0xe1,
-	0x2b, 0x39, 0xfb, 0xbd, 0xbe, 0x75, 0x33, 0xe9, 0x05, 0x39, 0x6e, 0x3f, 0x4f, 0xc2, 0x31, 0xb1,
-	0xdd, 0x26, 0x6c, 0xe2, 0x8a, 0x8f, 0x1c, 0x13, 0x7f, 0x4e, 0x46, 0xf0, 0x69, 0xa0, 0xb0, 0xcb,
-	0xee, 0x59, 0xc3, 0xa1, 0x10, 0x3e, 0x90, 0x71, 0x27, 0x91, 0x3f, 0x23, 0xc3, 0x26, 0x93, 0x6e,
-	0x60, 0xad, 0xae, 0x40, 0x98, 0x8a, 0x82, 0x9b, 0x52, 0x83, 0x03, 0xb5, 0x85, 0x55, 0x42, 0x42,
-	0xa9, 0x05, 0x4d, 0xf6, 0x0a, 0x74, 0x06, 0x5b, 0x01, 0x9a, 0xee, 0x78, 0xac, 0xd3, 0x8d, 0x5b,
-	0x5c, 0x75, 0x4d, 0xab, 0x3f, 0xa2, 0x6c, 0xde, 0xb9, 0x80, 0x97, 0x4b, 0x91, 0x9a, 0xa4, 0x8c,
-	0xe9, 0x46, 0xe6, 0x51, 0x87, 0x11, 0xd5, 0x8c, 0xfa, 0xba, 0x30, 0xaa, 0x18, 0x71, 0x7d, 0x79,
-	0x37, 0xdf, 0x01, 0x00, 0x00, 0xff, 0xff, 0x0e, 0x52, 0x0b, 0x35, 0xa0, 0x02, 0x00, 0x00,
+func init() { proto.RegisterFile("peer/proposal_response.proto", fileDescriptor9) }
+
+var fileDescriptor9 = []byte{
+	// 393 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x6c, 0x92, 0x51, 0x8b, 0xd4, 0x30,
+	0x10, 0xc7, 0xe9, 0x7a, 0xb7, 0xb7, 0x9b, 0x5d, 0x61, 0x8d, 0xa0, 0x65, 0x39, 0x70, 0xa9, 0x2f,
+	0x15, 0x25, 0x05, 0x45, 0xf0, 0xf9, 0x40, 0xbc, 0xc7, 0x23, 0x88, 0x0f, 0x22, 0x1c, 0xe9, 0xee,
+	0x5c, 0x12, 0x6c, 0x9b, 0x90, 0x49, 0xc5, 0xfd, 0x1a, 0x7e, 0x62, 0x69, 0xda, 0xb4, 0x55, 0xee,
+	0x29, 0xfc, 0x27, 0x93, 0xdf, 0xcc, 0xfc, 0x33, 0xe4, 0xda, 0x02, 0xb8, 0xc2, 0x3a, 0x63, 0x0d,
+	0x8a, 0xea, 0xde, 0x01, 0x5a, 0xd3, 0x20, 0x30, 0xeb, 0x8c, 0x37, 0x74, 0x19, 0x0e, 0xdc, 0xbf,
+	0x92, 0xc6, 0xc8, 0x0a, 0x8a, 0x20, 0xcb, 0xf6, 0xa1, 0xf0, 0xba, 0x06, 0xf4, 0xa2, 0xb6, 0x7d,
+	0x62, 0xf6, 0x67, 0x41, 0x76, 0x77, 0x03, 0x84, 0x0f, 0x0c, 0x9a, 0x92, 0xab, 0x5f, 0xe0, 0x50,
+	0x9b, 0x26, 0x4d, 0x0e, 0x49, 0x7e, 0xc9, 0xa3, 0xa4, 0x9f, 0xc8, 0x7a, 0x24, 0xa4, 0x8b, 0x43,
+	0x92, 0x6f, 0xde, 0xef, 0x59, 0x5f, 0x83, 0xc5, 0x1a, 0xec, 0x6b, 0xcc, 0xe0, 0x53, 0x32, 0x7d,
+	0x47, 0x56, 0xb1, 0xc7, 0xf4, 0x22, 0x3c, 0xdc, 0xf5, 0x2f, 0x90, 0xc5, 0xba, 0x7c, 0xe5, 0x66,
+	0x1d, 0x58, 0x71, 0xae, 0x8c, 0x38, 0xa5, 0x97, 0x87, 0x24, 0xdf, 0xf2, 0x28, 0xe9, 0x47, 0xb2,
+	0x81, 0xe6, 0x64, 0x1c, 0x42, 0x0d, 0x8d, 0x4f, 0x97, 0x01, 0xf5, 0x3c, 0xa2, 0x3e, 0x4f, 0x57,
+	0x7c, 0x9e, 0x47, 0xdf, 0x92, 0x67, 0xa8, 0xeb, 0xb6, 0x12, 0x5e, 0x9b, 0xe6, 0x5e, 0x81, 0x96,
+	0xca, 0xa7, 0x57, 0x87, 0x24, 0xbf, 0xe0, 0xbb, 0xe9, 0xe2, 0x36, 0xc4, 0xb3, 0x6f, 0x64, 0x35,
+	0x7a, 0xf1, 0x82, 0x2c, 0xd1, 0x0b, 0xdf, 0xe2, 0x60, 0xc5, 0xa0, 0xba, 0x0e, 0x6b, 0x40, 0x14,
+	0x12, 0x82, 0x0f, 0x6b, 0x1e, 0xe5, 0xbc, 0xf7, 0x27, 0xff, 0xf4, 0x9e, 0xfd, 0x20, 0x2f, 0xff,
+	0xf7, 0xfa, 0x6e, 0x18, 0xeb, 0x35, 0x79, 0x3a, 0xfe, 0xa5, 0x12, 0xa8, 0x42, 0xb5, 0x2d, 0xdf,
+	0xc6, 0xe0, 0xad, 0x40, 0x45, 0xaf, 0xc9, 0x1a, 0x7e, 0x7b, 0x68, 0xc2, 0xcf, 0x2c, 0x42, 0xc2,
+	0x14, 0xc8, 0xbe, 0x90, 0xcd, 0x6c, 0x7c, 0xba, 0x27, 0xab, 0xc1, 0x00, 0x37, 0xc0, 0x46, 0xdd,
+	0x81, 0x50, 0xcb, 0x46, 0xf8, 0xd6, 0x41, 0x04, 0x8d, 0x81, 0x1b, 0x45, 0x32, 0xe3, 0x24, 0x53,
+	0x67, 0x0b, 0xae, 0x82, 0x93, 0x04, 0xc7, 0x1e, 0x44, 0xe9, 0xf4, 0x31, 0xba, 0xdc, 0xad, 0xde,
+	0xcd, 0x23, 0xa3, 0x1c, 0x7f, 0x0a, 0x09, 0xdf, 0xdf, 0x48, 0xed, 0x55, 0x5b, 0xb2, 0xa3, 0xa9,
+	0x8b, 0x19, 0xa3, 0xe8, 0x19, 0xfd, 0x2a, 0x62, 0xd1, 0x31, 0xca, 0x7e, 0x4d, 0x3f, 0xfc, 0x1d,
+	0x00, 0x54, 0x6f, 0x97, 0xa4, 0xcd, 0x02, 0x00, 0x00,
 }
@@ -23,7 +23,7 @@ type ChaincodeQueryResponse struct {
 func (m *ChaincodeQueryResponse) Reset()                    { *m = ChaincodeQueryResponse{} }
 func (m *ChaincodeQueryResponse) String() string            { return proto.CompactTextString(m) }
 func (*ChaincodeQueryResponse) ProtoMessage()               {}
-func (*ChaincodeQueryResponse) Descriptor() ([]byte, []int) { return fileDescriptor9, []int{0} }
+func (*ChaincodeQueryResponse) Descriptor() ([]byte, []int) { return fileDescriptor10, []int{0} }
 
 func (m *ChaincodeQueryResponse) GetChaincodes() []*ChaincodeInfo {
 	if m != nil {
@@ -48,12 +48,17 @@ type ChaincodeInfo struct {
 	// the name of the VSCC for this chaincode. This will be
 	// blank if the query is returning information about installed chaincodes.
 	Vscc string `protobuf:"bytes,6,opt,name=vscc" json:"vscc,omitempty"`
+	// the serialized identity of the verified signer of the detached signature
+	// of the package of an installed chaincode. This will be blank if the query
+	// is returning information about instantiated chaincodes, or if the package
+	// was installed without detached signature.
+	Signer []byte `protobuf:"bytes,7,opt,name=signer,proto3" json:"signer,omitempty"`
 }
 
 func (m *ChaincodeInfo) Reset()                    { *m = ChaincodeInfo{} }
 func (m *ChaincodeInfo) String() string            { return proto.CompactTextString(m) }
 func (*ChaincodeInfo) ProtoMessage()               {}
-func (*ChaincodeInfo) Descriptor() ([]byte, []int) { return fileDescriptor9, []int{1} }
+func (*ChaincodeInfo) Descriptor() ([]byte, []int) { return fileDescriptor10, []int{1} }
 
 func (m *ChaincodeInfo) GetName() string {
 	if m != nil {
@@ -97,6 +102,13 @@ func (m *ChaincodeInfo) GetVscc() string {
 	return ""
 }
 
+func (m *ChaincodeInfo) GetSigner() []byte {
+	if m != nil {
+		return m.Signer
+	}
+	return nil
+}
+
 // ChannelQueryResponse returns information about each channel that pertains
 // to a query in lscc.go, such as GetChannels (returns all channels for a
 // given peer)
@@ -107,7 +119,7 @@ type ChannelQueryResponse struct {
 func (m *ChannelQueryResponse) Reset()                    { *m = ChannelQueryResponse{} }
 func (m *ChannelQueryResponse) String() string            { return proto.CompactTextString(m) }
 func (*ChannelQueryResponse) ProtoMessage()               {}
-func (*ChannelQueryResponse) Descriptor() ([]byte, []int) { return fileDescriptor9, []int{2} }
+func (*ChannelQueryResponse) Descriptor() ([]byte, []int) { return fileDescriptor10, []int{2} }
 
 func (m *ChannelQueryResponse) GetChannels() []*ChannelInfo {
 	if m != nil {
@@ -124,7 +136,7 @@ type ChannelInfo struct {
 func (m *ChannelInfo) Reset()                    { *m = ChannelInfo{} }
 func (m *ChannelInfo) String() string            { return proto.CompactTextString(m) }
 func (*ChannelInfo) ProtoMessage()               {}
-func (*ChannelInfo) Descriptor() ([]byte, []int) { return fileDescriptor9, []int{3} }
+func (*ChannelInfo) Descriptor() ([]byte, []int) { return fileDescriptor10, []int{3} }
 
 func (m *ChannelInfo) GetChannelId() string {
 	if m != nil {
@@ -140,26 +152,27 @@ func init() {
 	proto.RegisterType((*ChannelInfo)(nil), "protos.ChannelInfo")
 }
 
-func init() { proto.RegisterFile("peer/query.proto", fileDescriptor9) }
-
-var fileDescriptor9 = []byte{
-	// 284 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x54, 0x91, 0xdf, 0x4a, 0xc3, 0x30,
-	0x14, 0xc6, 0xa9, 0xfb, 0xa3, 0x3b, 0x43, 0x90, 0x38, 0x25, 0x37, 0xc2, 0xe8, 0xd5, 0x04, 0x69,
-	0x40, 0xf1, 0x05, 0xdc, 0x85, 0xec, 0x6a, 0xb8, 0x4b, 0x6f, 0xa4, 0x4d, 0xcf, 0xda, 0xc0, 0x96,
-	0xc4, 0xa4, 0x1d, 0xec, 0x29, 0x7c, 0x65, 0x39, 0xc9, 0x3a, 0xba, 0xab, 0x9e, 0xf3, 0xfb, 0x7e,
-	0xa1, 0x7c, 0x09, 0xdc, 0x59, 0x44, 0x27, 0x7e, 0x5b, 0x74, 0xc7, 0xcc, 0x3a, 0xd3, 0x18, 0x36,
-	0x0e, 0x1f, 0x9f, 0xae, 0xe1, 0x71, 0x59, 0xe7, 0x4a, 0x4b, 0x53, 0xe2, 0x17, 0xe5, 0x1b, 0xf4,
-	0xd6, 0x68, 0x8f, 0xec, 0x1d, 0x40, 0x76, 0x89, 0xe7, 0xc9, 0x7c, 0xb0, 0x98, 0xbe, 0x3e, 0xc4,
-	0xd3, 0x3e, 0x3b, 0x9f, 0x59, 0xe9, 0xad, 0xd9, 0xf4, 0xc4, 0xf4, 0x2f, 0x81, 0xdb, 0x8b, 0x94,
-	0x31, 0x18, 0xea, 0x7c, 0x8f, 0x3c, 0x99, 0x27, 0x8b, 0xc9, 0x26, 0xcc, 0x8c, 0xc3, 0xf5, 0x01,
-	0x9d, 0x57, 0x46, 0xf3, 0xab, 0x80, 0xbb, 0x95, 0x6c, 0x9b, 0x37, 0x35, 0x1f, 0x44, 0x9b, 0x66,
-	0x36, 0x83, 0x91, 0xd2, 0xb6, 0x6d, 0xf8, 0x30, 0xc0, 0xb8, 0x90, 0x89, 0x5e, 0x4a, 0x3e, 0x8a,
-	0x26, 0xcd, 0xc4, 0x0e, 0xc4, 0xc6, 0x91, 0xd1, 0x9c, 0x7e, 0xc2, 0x6c, 0x59, 0xe7, 0x5a, 0xe3,
-	0xee, 0xb2, 0xa0, 0x80, 0x1b, 0x19, 0x79, 0x57, 0xef, 0xbe, 0x57, 0x8f, 0x78, 0x28, 0x77, 0x96,
-	0xd2, 0x17, 0x98, 0xf6, 0x02, 0xf6, 0x14, 0x2e, 0x88, 0xd6, 0x1f, 0x55, 0x9e, 0xda, 0x4d, 0x4e,
-	0x64, 0x55, 0x7e, 0xac, 0x21, 0x35, 0xae, 0xca, 0xea, 0xa3, 0x45, 0xb7, 0xc3, 0xb2, 0x42, 0x97,
-	0x6d, 0xf3, 0xc2, 0x29, 0xd9, 0xfd, 0x84, 0xde, 0xe4, 0xfb, 0xb9, 0x52, 0x4d, 0xdd, 0x16, 0x99,
-	0x34, 0x7b, 0xd1, 0x53, 0x45, 0x54, 0x45, 0x54, 0x05, 0xa9, 0x45, 0x7c, 0xb2, 0xb7, 0xff, 0x00,
-	0x00, 0x00, 0xff, 0xff, 0x56, 0xd1, 0xfe, 0x74, 0xcd, 0x01, 0x00, 0x00,
+func init() { proto.RegisterFile("peer/query.proto", fileDescriptor10) }
+
+var fileDescriptor10 = []byte{
+	// 296 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x54, 0x91, 0xcd, 0x6a, 0xeb, 0x30,
+	0x10, 0x85, 0xf1, 0xcd, 0xdf, 0xcd, 0xa4, 0x85, 0xa2, 0xa6, 0x41, 0x9b, 0x42, 0xf0, 0x2a, 0x85,
+	0x62, 0x41, 0x4b, 0x5f, 0xa0, 0x59, 0x94, 0xac, 0x42, 0xbd, 0xec, 0xa6, 0x38, 0xf2, 0xc4, 0x16,
+	0x24, 0x92, 0x2a, 0x39, 0x81, 0x3c, 0x51, 0x5f, 0xb3, 0x8c, 0x14, 0x07, 0x67, 0xe5, 0x99, 0xef,
+	0x7c, 0xc2, 0x1c, 0x09, 0xee, 0x2c, 0xa2, 0x13, 0x3f, 0x07, 0x74, 0xa7, 0xcc, 0x3a, 0xd3, 0x18,
+	0x36, 0x0c, 0x1f, 0x9f, 0xae, 0x61, 0xb6, 0xac, 0x0b, 0xa5, 0xa5, 0x29, 0xf1, 0x93, 0xf2, 0x1c,
+	0xbd, 0x35, 0xda, 0x23, 0x7b, 0x03, 0x90, 0x6d, 0xe2, 0x79, 0x32, 0xef, 0x2d, 0x26, 0x2f, 0x0f,
+	0xf1, 0xb4, 0xcf, 0x2e, 0x67, 0x56, 0x7a, 0x6b, 0xf2, 0x8e, 0x98, 0xfe, 0x26, 0x70, 0x7b, 0x95,
+	0x32, 0x06, 0x7d, 0x5d, 0xec, 0x91, 0x27, 0xf3, 0x64, 0x31, 0xce, 0xc3, 0xcc, 0x38, 0x8c, 0x8e,
+	0xe8, 0xbc, 0x32, 0x9a, 0xff, 0x0b, 0xb8, 0x5d, 0xc9, 0xb6, 0x45, 0x53, 0xf3, 0x5e, 0xb4, 0x69,
+	0x66, 0x53, 0x18, 0x28, 0x6d, 0x0f, 0x0d, 0xef, 0x07, 0x18, 0x17, 0x32, 0xd1, 0x4b, 0xc9, 0x07,
+	0xd1, 0xa4, 0x99, 0xd8, 0x91, 0xd8, 0x30, 0x32, 0x9a, 0xd9, 0x0c, 0x86, 0x5e, 0x55, 0x1a, 0x1d,
+	0x1f, 0xcd, 0x93, 0xc5, 0x4d, 0x7e, 0xde, 0xd2, 0x0f, 0x98, 0x2e, 0xeb, 0x42, 0x6b, 0xdc, 0x5d,
+	0x17, 0x17, 0xf0, 0x5f, 0x46, 0xde, 0xd6, 0xbe, 0xef, 0xd4, 0x26, 0x1e, 0x4a, 0x5f, 0xa4, 0xf4,
+	0x19, 0x26, 0x9d, 0x80, 0x3d, 0x86, 0x8b, 0xa3, 0xf5, 0x5b, 0x95, 0xe7, 0xd6, 0xe3, 0x33, 0x59,
+	0x95, 0xef, 0x6b, 0x48, 0x8d, 0xab, 0xb2, 0xfa, 0x64, 0xd1, 0xed, 0xb0, 0xac, 0xd0, 0x65, 0xdb,
+	0x62, 0xe3, 0x94, 0x6c, 0x7f, 0x42, 0x6f, 0xf5, 0xf5, 0x54, 0xa9, 0xa6, 0x3e, 0x6c, 0x32, 0x69,
+	0xf6, 0xa2, 0xa3, 0x8a, 0xa8, 0x8a, 0xa8, 0x0a, 0x52, 0x37, 0xf1, 0x29, 0x5f, 0xff, 0x06, 0x00,
+	0xf5, 0xa8, 0x4b, 0xed, 0xe5, 0x01, 0x00, 0x00,
 }
@@ -30,7 +30,7 @@ type SignedChaincodeDeploymentSpec struct {
 func (m *SignedChaincodeDeploymentSpec) Reset()                    { *m = SignedChaincodeDeploymentSpec{} }
 func (m *SignedChaincodeDeploymentSpec) String() string            { return proto.CompactTextString(m) }
 func (*SignedChaincodeDeploymentSpec) ProtoMessage()               {}
-func (*SignedChaincodeDeploymentSpec) Descriptor() ([]byte, []int) { return fileDescriptor10, []int{0} }
+func (*SignedChaincodeDeploymentSpec) Descriptor() ([]byte, []int) { return fileDescriptor11, []int{0} }
 
 func (m *SignedChaincodeDeploymentSpec) GetChaincodeDeploymentSpec() []byte {
 	if m != nil {
@@ -57,24 +57,24 @@ func init() {
 	proto.RegisterType((*SignedChaincodeDeploymentSpec)(nil), "protos.SignedChaincodeDeploymentSpec")
 }
 
-func init() { proto.RegisterFile("peer/signed_cc_dep_spec.proto", fileDescriptor10) }
+func init() { proto.RegisterFile("peer/signed_cc_dep_spec.proto", fileDescriptor11) }
 
-var fileDescriptor10 = []byte{
-	// 255 bytes of a gzipped FileDescriptorProto
+var fileDescriptor11 = []byte{
+	// 251 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x74, 0x90, 0xc1, 0x4a, 0xc3, 0x40,
-	0x10, 0x86, 0xa9, 0x05, 0x0f, 0xab, 0x17, 0x53, 0xc1, 0x28, 0x16, 0x4a, 0x4f, 0xf5, 0x92, 0xa0,
-	0xde, 0x3c, 0x56, 0x3d, 0x2b, 0xed, 0xcd, 0xcb, 0x92, 0x4c, 0xc6, 0x64, 0x21, 0xdd, 0x19, 0x66,
-	0x56, 0x24, 0xaf, 0xe9, 0x13, 0x49, 0x76, 0x2d, 0xd6, 0x83, 0xa7, 0x85, 0xfd, 0xbe, 0x7f, 0x66,
-	0xf8, 0xcd, 0x9c, 0x11, 0xa5, 0x54, 0xd7, 0x7a, 0x6c, 0x2c, 0x80, 0x6d, 0x90, 0xad, 0x32, 0x42,
-	0xc1, 0x42, 0x81, 0xb2, 0xe3, 0xf8, 0xe8, 0xd5, 0x75, 0xd4, 0x58, 0x88, 0x49, 0xab, 0xde, 0x0a,
-	0x2a, 0x93, 0x57, 0x4c, 0xd6, 0xf2, 0x6b, 0x62, 0xe6, 0xdb, 0x38, 0xe2, 0xb1, 0xab, 0x9c, 0x07,
-	0x6a, 0xf0, 0x09, 0xb9, 0xa7, 0x61, 0x87, 0x3e, 0x6c, 0x19, 0x21, 0x7b, 0x30, 0x97, 0xb0, 0x47,
-	0xe3, 0x8e, 0x1f, 0x16, 0x57, 0xe5, 0x93, 0xc5, 0x64, 0x75, 0xba, 0xb9, 0x80, 0x7f, 0xb2, 0xb7,
-	0xe6, 0xdc, 0x79, 0x0d, 0x95, 0x0f, 0xae, 0x0a, 0x8e, 0xbc, 0x65, 0xea, 0x1d, 0x0c, 0xf9, 0x51,
-	0x8c, 0xcd, 0xfe, 0xb0, 0xd7, 0x88, 0xb2, 0xb5, 0xc9, 0xe8, 0xd3, 0xa3, 0x58, 0xf4, 0x0d, 0x89,
-	0xe2, 0x38, 0x4b, 0xf3, 0xe9, 0x62, 0xba, 0x3a, 0xb9, 0x9b, 0xa5, 0xa3, 0xb5, 0x78, 0xfe, 0x65,
-	0x9b, 0xb3, 0xa8, 0x1f, 0xfc, 0xe8, 0xfa, 0xc5, 0x2c, 0x49, 0xda, 0xa2, 0x1b, 0x18, 0xa5, 0xc7,
-	0xa6, 0x45, 0x29, 0xde, 0xab, 0x5a, 0x1c, 0xec, 0xf3, 0x63, 0x25, 0x6f, 0x37, 0xad, 0x0b, 0xdd,
-	0x47, 0x5d, 0x00, 0xed, 0xca, 0x03, 0xb5, 0x4c, 0x6a, 0x99, 0xd4, 0x72, 0x54, 0xeb, 0xd4, 0xe5,
-	0xfd, 0x77, 0x00, 0x00, 0x00, 0xff, 0xff, 0x78, 0x40, 0x4c, 0x9e, 0x73, 0x01, 0x00, 0x00,
+	0x10, 0x86, 0x89, 0x05, 0x0f, 0xab, 0x17, 0x53, 0xc1, 0x28, 0x16, 0x4a, 0x4f, 0xf5, 0x92, 0xa0,
+	0xde, 0x3c, 0x56, 0x3d, 0x2b, 0xed, 0xcd, 0xcb, 0x92, 0xcc, 0x8e, 0xc9, 0x42, 0xba, 0x33, 0xcc,
+	0xac, 0x48, 0x5e, 0xd3, 0x27, 0x92, 0x6e, 0xa8, 0xd6, 0x83, 0xa7, 0x85, 0xfd, 0xbe, 0xff, 0x9f,
+	0x61, 0xcc, 0x8c, 0x11, 0xa5, 0x52, 0xdf, 0x06, 0x74, 0x16, 0xc0, 0x3a, 0x64, 0xab, 0x8c, 0x50,
+	0xb2, 0x50, 0xa4, 0xfc, 0x38, 0x3d, 0x7a, 0x75, 0x9d, 0x34, 0x16, 0x62, 0xd2, 0xba, 0xb7, 0x82,
+	0xca, 0x14, 0x14, 0x47, 0x6b, 0xf1, 0x95, 0x99, 0xd9, 0x26, 0x55, 0x3c, 0x76, 0xb5, 0x0f, 0x40,
+	0x0e, 0x9f, 0x90, 0x7b, 0x1a, 0xb6, 0x18, 0xe2, 0x86, 0x11, 0xf2, 0x07, 0x73, 0x09, 0x7b, 0x64,
+	0xdd, 0x0f, 0x4b, 0xa3, 0x8a, 0x6c, 0x9e, 0x2d, 0x4f, 0xd7, 0x17, 0xf0, 0x4f, 0xf6, 0xd6, 0x9c,
+	0xfb, 0xa0, 0xb1, 0x0e, 0xd1, 0xd7, 0xd1, 0x53, 0xb0, 0x4c, 0xbd, 0x87, 0xa1, 0x38, 0x4a, 0xb1,
+	0xe9, 0x1f, 0xf6, 0x9a, 0x50, 0xbe, 0x32, 0x39, 0x7d, 0x06, 0x14, 0x8b, 0xc1, 0x91, 0x28, 0xee,
+	0xba, 0xb4, 0x98, 0xcc, 0x27, 0xcb, 0x93, 0xbb, 0xe9, 0xb8, 0xb4, 0x96, 0xcf, 0xbf, 0x6c, 0x7d,
+	0x96, 0xf4, 0x83, 0x1f, 0x5d, 0xbd, 0x98, 0x05, 0x49, 0x5b, 0x76, 0x03, 0xa3, 0xf4, 0xe8, 0x5a,
+	0x94, 0xf2, 0xbd, 0x6e, 0xc4, 0xc3, 0x3e, 0xcf, 0x88, 0xf2, 0x76, 0xd3, 0xfa, 0xd8, 0x7d, 0x34,
+	0x25, 0xd0, 0xb6, 0x3a, 0x50, 0xab, 0x51, 0xad, 0x46, 0xb5, 0xda, 0xa9, 0xcd, 0x78, 0xcb, 0xfb,
+	0xef, 0x01, 0x00, 0x78, 0x40, 0x4c, 0x9e, 0x73, 0x01, 0x00, 0x00,
 }
@@ -0,0 +1,105 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package peer
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/rwset"
+	"github.com/hyperledger/fabric/protos/msp"
+)
+
+func init() {
+	common.PayloadDataMap[common.HeaderType_ENDORSER_TRANSACTION] = func() proto.Message { return &Transaction{} }
+}
+
+func (ta *TransactionAction) StaticallyOpaqueFields() []string {
+	return []string{"header", "payload"}
+}
+
+func (ta *TransactionAction) StaticallyOpaqueFieldProto(name string) (proto.Message, error) {
+	switch name {
+	case ta.StaticallyOpaqueFields()[0]:
+		return &common.SignatureHeader{}, nil
+	case ta.StaticallyOpaqueFields()[1]:
+		return &ChaincodeActionPayload{}, nil
+	default:
+		return nil, fmt.Errorf("not a marshaled field: %s", name)
+	}
+}
+
+func (ccap *ChaincodeActionPayload) StaticallyOpaqueFields() []string {
+	return []string{"chaincode_proposal_payload"}
+}
+
+func (ccap *ChaincodeActionPayload) StaticallyOpaqueFieldProto(name string) (proto.Message, error) {
+	if name != ccap.StaticallyOpaqueFields()[0] {
+		return nil, fmt.Errorf("not a marshaled field: %s", name)
+	}
+	return &ChaincodeProposalPayload{}, nil
+}
+
+func (cpp *ChaincodeProposalPayload) StaticallyOpaqueFields() []string {
+	return []string{"input"}
+}
+
+func (cpp *ChaincodeProposalPayload) StaticallyOpaqueFieldProto(name string) (proto.Message, error) {
+	if name != cpp.StaticallyOpaqueFields()[0] {
+		return nil, fmt.Errorf("not a marshaled field: %s", name)
+	}
+	return &ChaincodeInvocationSpec{}, nil
+}
+
+func (cea *ChaincodeEndorsedAction) StaticallyOpaqueFields() []string {
+	return []string{"proposal_response_payload"}
+}
+
+func (cea *ChaincodeEndorsedAction) StaticallyOpaqueFieldProto(name string) (proto.Message, error) {
+	if name != cea.StaticallyOpaqueFields()[0] {
+		return nil, fmt.Errorf("not a marshaled field: %s", name)
+	}
+	return &ProposalResponsePayload{}, nil
+}
+
+func (prp *ProposalResponsePayload) StaticallyOpaqueFields() []string {
+	return []string{"extension"}
+}
+
+func (prp *ProposalResponsePayload) StaticallyOpaqueFieldProto(name string) (proto.Message, error) {
+	if name != prp.StaticallyOpaqueFields()[0] {
+		return nil, fmt.Errorf("not a marshaled field: %s", name)
+	}
+	return &ChaincodeAction{}, nil
+}
+
+func (ca *ChaincodeAction) StaticallyOpaqueFields() []string {
+	return []string{"results", "events"}
+}
+
+func (ca *ChaincodeAction) StaticallyOpaqueFieldProto(name string) (proto.Message, error) {
+	switch name {
+	case ca.StaticallyOpaqueFields()[0]:
+		return &rwset.TxReadWriteSet{}, nil
+	case ca.StaticallyOpaqueFields()[1]:
+		return &ChaincodeEvent{}, nil
+	default:
+		return nil, fmt.Errorf("not a marshaled field: %s", name)
+	}
+}
+
+func (e *Endorsement) StaticallyOpaqueFields() []string {
+	return []string{"endorser"}
+}
+
+func (e *Endorsement) StaticallyOpaqueFieldProto(name string) (proto.Message, error) {
+	if name != e.StaticallyOpaqueFields()[0] {
+		return nil, fmt.Errorf("not a marshaled field: %s", name)
+	}
+	return &msp.SerializedIdentity{}, nil
+}
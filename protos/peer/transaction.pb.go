@@ -41,6 +41,7 @@ const (
 	TxValidationCode_BAD_RESPONSE_PAYLOAD         TxValidationCode = 21
 	TxValidationCode_BAD_RWSET                    TxValidationCode = 22
 	TxValidationCode_ILLEGAL_WRITESET             TxValidationCode = 23
+	TxValidationCode_RWSET_SIZE_EXCEEDED          TxValidationCode = 24
 	TxValidationCode_INVALID_OTHER_REASON         TxValidationCode = 255
 )
 
@@ -69,6 +70,7 @@ var TxValidationCode_name = map[int32]string{
 	21:  "BAD_RESPONSE_PAYLOAD",
 	22:  "BAD_RWSET",
 	23:  "ILLEGAL_WRITESET",
+	24:  "RWSET_SIZE_EXCEEDED",
 	255: "INVALID_OTHER_REASON",
 }
 var TxValidationCode_value = map[string]int32{
@@ -96,13 +98,14 @@ var TxValidationCode_value = map[string]int32{
 	"BAD_RESPONSE_PAYLOAD":         21,
 	"BAD_RWSET":                    22,
 	"ILLEGAL_WRITESET":             23,
+	"RWSET_SIZE_EXCEEDED":          24,
 	"INVALID_OTHER_REASON":         255,
 }
 
 func (x TxValidationCode) String() string {
 	return proto.EnumName(TxValidationCode_name, int32(x))
 }
-func (TxValidationCode) EnumDescriptor() ([]byte, []int) { return fileDescriptor11, []int{0} }
+func (TxValidationCode) EnumDescriptor() ([]byte, []int) { return fileDescriptor12, []int{0} }
 
 // This message is necessary to facilitate the verification of the signature
 // (in the signature field) over the bytes of the transaction (in the
@@ -120,7 +123,7 @@ type SignedTransaction struct {
 func (m *SignedTransaction) Reset()                    { *m = SignedTransaction{} }
 func (m *SignedTransaction) String() string            { return proto.CompactTextString(m) }
 func (*SignedTransaction) ProtoMessage()               {}
-func (*SignedTransaction) Descriptor() ([]byte, []int) { return fileDescriptor11, []int{0} }
+func (*SignedTransaction) Descriptor() ([]byte, []int) { return fileDescriptor12, []int{0} }
 
 func (m *SignedTransaction) GetTransactionBytes() []byte {
 	if m != nil {
@@ -152,7 +155,7 @@ type ProcessedTransaction struct {
 func (m *ProcessedTransaction) Reset()                    { *m = ProcessedTransaction{} }
 func (m *ProcessedTransaction) String() string            { return proto.CompactTextString(m) }
 func (*ProcessedTransaction) ProtoMessage()               {}
-func (*ProcessedTransaction) Descriptor() ([]byte, []int) { return fileDescriptor11, []int{1} }
+func (*ProcessedTransaction) Descriptor() ([]byte, []int) { return fileDescriptor12, []int{1} }
 
 func (m *ProcessedTransaction) GetTransactionEnvelope() *common.Envelope {
 	if m != nil {
@@ -189,7 +192,7 @@ type Transaction struct {
 func (m *Transaction) Reset()                    { *m = Transaction{} }
 func (m *Transaction) String() string            { return proto.CompactTextString(m) }
 func (*Transaction) ProtoMessage()               {}
-func (*Transaction) Descriptor() ([]byte, []int) { return fileDescriptor11, []int{2} }
+func (*Transaction) Descriptor() ([]byte, []int) { return fileDescriptor12, []int{2} }
 
 func (m *Transaction) GetActions() []*TransactionAction {
 	if m != nil {
@@ -211,7 +214,7 @@ type TransactionAction struct {
 func (m *TransactionAction) Reset()                    { *m = TransactionAction{} }
 func (m *TransactionAction) String() string            { return proto.CompactTextString(m) }
 func (*TransactionAction) ProtoMessage()               {}
-func (*TransactionAction) Descriptor() ([]byte, []int) { return fileDescriptor11, []int{3} }
+func (*TransactionAction) Descriptor() ([]byte, []int) { return fileDescriptor12, []int{3} }
 
 func (m *TransactionAction) GetHeader() []byte {
 	if m != nil {
@@ -248,7 +251,7 @@ type ChaincodeActionPayload struct {
 func (m *ChaincodeActionPayload) Reset()                    { *m = ChaincodeActionPayload{} }
 func (m *ChaincodeActionPayload) String() string            { return proto.CompactTextString(m) }
 func (*ChaincodeActionPayload) ProtoMessage()               {}
-func (*ChaincodeActionPayload) Descriptor() ([]byte, []int) { return fileDescriptor11, []int{4} }
+func (*ChaincodeActionPayload) Descriptor() ([]byte, []int) { return fileDescriptor12, []int{4} }
 
 func (m *ChaincodeActionPayload) GetChaincodeProposalPayload() []byte {
 	if m != nil {
@@ -279,7 +282,7 @@ type ChaincodeEndorsedAction struct {
 func (m *ChaincodeEndorsedAction) Reset()                    { *m = ChaincodeEndorsedAction{} }
 func (m *ChaincodeEndorsedAction) String() string            { return proto.CompactTextString(m) }
 func (*ChaincodeEndorsedAction) ProtoMessage()               {}
-func (*ChaincodeEndorsedAction) Descriptor() ([]byte, []int) { return fileDescriptor11, []int{5} }
+func (*ChaincodeEndorsedAction) Descriptor() ([]byte, []int) { return fileDescriptor12, []int{5} }
 
 func (m *ChaincodeEndorsedAction) GetProposalResponsePayload() []byte {
 	if m != nil {
@@ -305,61 +308,61 @@ func init() {
 	proto.RegisterEnum("protos.TxValidationCode", TxValidationCode_name, TxValidationCode_value)
 }
 
-func init() { proto.RegisterFile("peer/transaction.proto", fileDescriptor11) }
-
-var fileDescriptor11 = []byte{
-	// 833 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x74, 0x54, 0xd1, 0x6e, 0xe2, 0x46,
-	0x14, 0x2d, 0xd9, 0x4d, 0xd2, 0x0c, 0xd9, 0x64, 0x32, 0x10, 0x42, 0x50, 0xd4, 0x5d, 0xf1, 0x50,
-	0x6d, 0x5b, 0x09, 0xa4, 0xec, 0x43, 0xa5, 0xaa, 0x2f, 0x83, 0x3d, 0x09, 0x56, 0xcd, 0x8c, 0x35,
-	0x1e, 0x08, 0xe9, 0x43, 0x47, 0x06, 0xcf, 0x12, 0x54, 0xb0, 0x2d, 0xdb, 0x59, 0x35, 0xaf, 0xfd,
-	0x80, 0xf6, 0x3f, 0xfb, 0x13, 0xad, 0xc6, 0x63, 0x03, 0xc9, 0x76, 0x5f, 0x30, 0x73, 0xcf, 0xb9,
-	0xf7, 0x9c, 0x7b, 0xaf, 0x3d, 0xa0, 0x95, 0x28, 0x95, 0xf6, 0xf3, 0x34, 0x88, 0xb2, 0x60, 0x9e,
-	0x2f, 0xe3, 0xa8, 0x97, 0xa4, 0x71, 0x1e, 0xa3, 0x83, 0xe2, 0x91, 0x75, 0xde, 0x2e, 0xe2, 0x78,
-	0xb1, 0x52, 0xfd, 0xe2, 0x38, 0x7b, 0xfc, 0xd8, 0xcf, 0x97, 0x6b, 0x95, 0xe5, 0xc1, 0x3a, 0x31,
-	0xc4, 0xce, 0x55, 0x51, 0x20, 0x49, 0xe3, 0x24, 0xce, 0x82, 0x95, 0x4c, 0x55, 0x96, 0xc4, 0x51,
-	0xa6, 0x4a, 0xb4, 0x31, 0x8f, 0xd7, 0xeb, 0x38, 0xea, 0x9b, 0x87, 0x09, 0x76, 0x7f, 0x03, 0x67,
-	0xfe, 0x72, 0x11, 0xa9, 0x50, 0x6c, 0x65, 0xd1, 0x0f, 0xe0, 0x6c, 0xc7, 0x85, 0x9c, 0x3d, 0xe5,
-	0x2a, 0x6b, 0xd7, 0xde, 0xd5, 0xde, 0x1f, 0x73, 0xb8, 0x03, 0x0c, 0x74, 0x1c, 0x5d, 0x81, 0xa3,
-	0x6c, 0xb9, 0x88, 0x82, 0xfc, 0x31, 0x55, 0xed, 0xbd, 0x82, 0xb4, 0x0d, 0x74, 0xff, 0xac, 0x81,
-	0xa6, 0x97, 0xc6, 0x73, 0x95, 0x65, 0xcf, 0x35, 0x06, 0xa0, 0xb1, 0x53, 0x8a, 0x44, 0x9f, 0xd4,
-	0x2a, 0x4e, 0x54, 0xa1, 0x52, 0xbf, 0x86, 0xbd, 0xd2, 0x64, 0x15, 0xe7, 0xff, 0x47, 0x46, 0xdf,
-	0x82, 0x93, 0x4f, 0xc1, 0x6a, 0x19, 0x06, 0x3a, 0x6a, 0xc5, 0xa1, 0xd1, 0xdf, 0xe7, 0x2f, 0xa2,
-	0xdd, 0x01, 0xa8, 0xef, 0x4a, 0x7f, 0x00, 0x87, 0xe6, 0x9f, 0x6e, 0xea, 0xd5, 0xfb, 0xfa, 0xf5,
-	0xa5, 0x19, 0x46, 0xd6, 0xdb, 0x61, 0xe1, 0xe2, 0x97, 0x57, 0xcc, 0x2e, 0x01, 0x67, 0x9f, 0xa1,
-	0xa8, 0x05, 0x0e, 0x1e, 0x54, 0x10, 0xaa, 0xb4, 0x9c, 0x4e, 0x79, 0x42, 0x6d, 0x70, 0x98, 0x04,
-	0x4f, 0xab, 0x38, 0x08, 0xcb, 0x89, 0x54, 0xc7, 0xee, 0xdf, 0x35, 0xd0, 0xb2, 0x1e, 0x82, 0x65,
-	0x34, 0x8f, 0x43, 0x65, 0xaa, 0x78, 0x06, 0x42, 0x3f, 0x83, 0xce, 0xbc, 0x42, 0xe4, 0x66, 0x89,
-	0x55, 0x1d, 0x23, 0xd0, 0xde, 0x30, 0xbc, 0x92, 0x50, 0x65, 0xff, 0x08, 0x0e, 0x8c, 0xb5, 0x42,
-	0xb1, 0x7e, 0xfd, 0xb6, 0xea, 0x69, 0xa3, 0x46, 0xa2, 0x30, 0x4e, 0x33, 0x15, 0x96, 0x9d, 0x95,
-	0xf4, 0xee, 0x5f, 0x35, 0x70, 0xf1, 0x05, 0x0e, 0xfa, 0x09, 0x5c, 0x7e, 0xf6, 0x36, 0xbd, 0x70,
-	0x74, 0x51, 0x11, 0x78, 0x89, 0x6f, 0x0d, 0x1d, 0x2b, 0x53, 0x6d, 0xad, 0xa2, 0x3c, 0x6b, 0xef,
-	0x15, 0xa3, 0x6e, 0x54, 0xb6, 0xc8, 0x16, 0xe3, 0xcf, 0x88, 0xdf, 0xff, 0xf3, 0x1a, 0x40, 0xf1,
-	0xc7, 0xe4, 0xd9, 0x0a, 0xd1, 0x11, 0xd8, 0x9f, 0x60, 0xd7, 0xb1, 0xe1, 0x57, 0x08, 0x82, 0x63,
-	0xea, 0xb8, 0x92, 0xd0, 0x09, 0x71, 0x99, 0x47, 0x60, 0x0d, 0x9d, 0x82, 0xfa, 0x00, 0xdb, 0xd2,
-	0xc3, 0xf7, 0x2e, 0xc3, 0x36, 0xdc, 0x43, 0xe7, 0xe0, 0x4c, 0x07, 0x2c, 0x36, 0x1a, 0x31, 0x2a,
-	0x87, 0x04, 0xdb, 0x84, 0xc3, 0x57, 0xe8, 0x12, 0x9c, 0x17, 0x61, 0x4e, 0xb0, 0x60, 0x5c, 0xfa,
-	0xce, 0x2d, 0xc5, 0x62, 0xcc, 0x09, 0x7c, 0x8d, 0xde, 0x81, 0x2b, 0x87, 0x16, 0x0a, 0x92, 0x50,
-	0x9b, 0x71, 0x9f, 0x70, 0x29, 0x38, 0xa6, 0x3e, 0xb6, 0x84, 0xc3, 0x28, 0xdc, 0x47, 0xdf, 0x80,
-	0x4e, 0xc5, 0xb0, 0x18, 0xbd, 0x71, 0x6e, 0x9f, 0xe1, 0x07, 0xa8, 0x03, 0x5a, 0x63, 0xea, 0x8f,
-	0x3d, 0x8f, 0x71, 0x41, 0x6c, 0x29, 0xa6, 0x1b, 0x3f, 0x87, 0x95, 0x1f, 0x8f, 0x33, 0x8f, 0xf9,
-	0xd8, 0x95, 0x62, 0xea, 0xd8, 0xf0, 0x6b, 0x84, 0xc0, 0x89, 0x3d, 0xf6, 0x5c, 0xc7, 0xc2, 0x82,
-	0x98, 0xd8, 0x91, 0x96, 0x29, 0x0d, 0x8c, 0x08, 0x15, 0xd2, 0x63, 0xae, 0x63, 0xdd, 0xcb, 0x1b,
-	0xec, 0xb8, 0xda, 0x28, 0x40, 0x2d, 0x80, 0x46, 0x13, 0xcb, 0x92, 0x9c, 0x60, 0x63, 0xc4, 0x75,
-	0x2c, 0x01, 0xeb, 0xba, 0x37, 0x6f, 0x88, 0xa9, 0x60, 0xa3, 0x17, 0xd0, 0x31, 0x6a, 0x80, 0xd3,
-	0x31, 0xfd, 0x85, 0xb2, 0x3b, 0xaa, 0x5d, 0x89, 0x7b, 0x8f, 0xc0, 0x37, 0xda, 0xae, 0xc0, 0xfc,
-	0x96, 0x08, 0x69, 0x0d, 0xb1, 0x43, 0x25, 0x65, 0x42, 0xde, 0xb0, 0x31, 0xb5, 0xe1, 0x09, 0x6a,
-	0x02, 0x38, 0xc2, 0xdc, 0x1f, 0x16, 0x4e, 0x25, 0xe1, 0x9c, 0x71, 0x78, 0x5a, 0xcd, 0x5d, 0x4c,
-	0xcb, 0x96, 0xa1, 0x6e, 0x8b, 0x4c, 0x3d, 0x87, 0x13, 0xdb, 0x14, 0xb1, 0x98, 0x4d, 0xe0, 0x99,
-	0x6e, 0x61, 0x73, 0x94, 0x13, 0xc2, 0x7d, 0x87, 0xd1, 0xad, 0x1f, 0x84, 0xda, 0xa0, 0xa9, 0xa7,
-	0x61, 0xd6, 0x22, 0xc9, 0x54, 0x10, 0xaa, 0x29, 0xb0, 0xa1, 0x9b, 0x2b, 0x16, 0x34, 0xc4, 0x94,
-	0x12, 0xb7, 0x5a, 0x5c, 0xb3, 0xca, 0xe0, 0xc4, 0xf7, 0x18, 0xf5, 0xc9, 0x66, 0xb2, 0xe7, 0xe8,
-	0x0d, 0x38, 0x2a, 0x90, 0x3b, 0x9f, 0x08, 0xd8, 0xd2, 0xce, 0x1d, 0xd7, 0x25, 0xb7, 0xd8, 0x95,
-	0x77, 0xdc, 0x11, 0x44, 0x47, 0x2f, 0xd0, 0x25, 0x68, 0x56, 0xab, 0x63, 0x62, 0x48, 0xb8, 0x9e,
-	0x90, 0xcf, 0x28, 0xfc, 0xb7, 0x36, 0x98, 0x83, 0x6e, 0x9c, 0x2e, 0x7a, 0x0f, 0x4f, 0x89, 0x4a,
-	0x57, 0x2a, 0x5c, 0xa8, 0xb4, 0xf7, 0x31, 0x98, 0xa5, 0xcb, 0x79, 0xf5, 0x9e, 0xea, 0x2b, 0x75,
-	0x80, 0x76, 0x3e, 0x7d, 0x2f, 0x98, 0xff, 0x1e, 0x2c, 0xd4, 0xaf, 0xdf, 0x2d, 0x96, 0xf9, 0xc3,
-	0xe3, 0x4c, 0xdf, 0x54, 0xfd, 0x9d, 0xf4, 0xbe, 0x49, 0x37, 0x97, 0x74, 0xd6, 0xd7, 0xe9, 0x33,
-	0x73, 0x81, 0x7f, 0xf8, 0x2f, 0x00, 0x00, 0xff, 0xff, 0x19, 0x1c, 0xb2, 0xe4, 0xe1, 0x05, 0x00,
-	0x00,
+func init() { proto.RegisterFile("peer/transaction.proto", fileDescriptor12) }
+
+var fileDescriptor12 = []byte{
+	// 844 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x74, 0x54, 0xcf, 0x6f, 0xe2, 0x46,
+	0x14, 0x2e, 0xd9, 0x26, 0x69, 0x86, 0x6c, 0x32, 0x19, 0x08, 0x21, 0x28, 0xea, 0xae, 0x38, 0x54,
+	0xdb, 0x56, 0x02, 0x29, 0x7b, 0xa8, 0x54, 0xf5, 0x32, 0xd8, 0x2f, 0xc1, 0xaa, 0x99, 0xb1, 0xc6,
+	0x03, 0x21, 0x7b, 0xe8, 0xc8, 0xc0, 0x2c, 0x41, 0x05, 0x1b, 0xd9, 0xce, 0xaa, 0xb9, 0xb6, 0xf7,
+	0xf6, 0x4f, 0x6e, 0x35, 0xfe, 0x01, 0x24, 0xdb, 0xbd, 0x60, 0xe6, 0x7d, 0xdf, 0xbc, 0xef, 0x7b,
+	0xef, 0x8d, 0x1e, 0x6a, 0xac, 0xb5, 0x8e, 0xbb, 0x69, 0x1c, 0x84, 0x49, 0x30, 0x4d, 0x17, 0x51,
+	0xd8, 0x59, 0xc7, 0x51, 0x1a, 0x91, 0x83, 0xec, 0x93, 0xb4, 0xde, 0xcc, 0xa3, 0x68, 0xbe, 0xd4,
+	0xdd, 0xec, 0x38, 0x79, 0xfc, 0xd8, 0x4d, 0x17, 0x2b, 0x9d, 0xa4, 0xc1, 0x6a, 0x9d, 0x13, 0x5b,
+	0x57, 0x59, 0x82, 0x75, 0x1c, 0xad, 0xa3, 0x24, 0x58, 0xaa, 0x58, 0x27, 0xeb, 0x28, 0x4c, 0x74,
+	0x81, 0xd6, 0xa6, 0xd1, 0x6a, 0x15, 0x85, 0xdd, 0xfc, 0x93, 0x07, 0xdb, 0xbf, 0xa1, 0x33, 0x7f,
+	0x31, 0x0f, 0xf5, 0x4c, 0x6e, 0x65, 0xc9, 0x8f, 0xe8, 0x6c, 0xc7, 0x85, 0x9a, 0x3c, 0xa5, 0x3a,
+	0x69, 0x56, 0xde, 0x56, 0xde, 0x1d, 0x0b, 0xbc, 0x03, 0xf4, 0x4c, 0x9c, 0x5c, 0xa1, 0xa3, 0x64,
+	0x31, 0x0f, 0x83, 0xf4, 0x31, 0xd6, 0xcd, 0xbd, 0x8c, 0xb4, 0x0d, 0xb4, 0xff, 0xac, 0xa0, 0xba,
+	0x17, 0x47, 0x53, 0x9d, 0x24, 0xcf, 0x35, 0x7a, 0xa8, 0xb6, 0x93, 0x0a, 0xc2, 0x4f, 0x7a, 0x19,
+	0xad, 0x75, 0xa6, 0x52, 0xbd, 0xc6, 0x9d, 0xc2, 0x64, 0x19, 0x17, 0xff, 0x47, 0x26, 0xdf, 0xa1,
+	0x93, 0x4f, 0xc1, 0x72, 0x31, 0x0b, 0x4c, 0xd4, 0x8a, 0x66, 0xb9, 0xfe, 0xbe, 0x78, 0x11, 0x6d,
+	0xf7, 0x50, 0x75, 0x57, 0xfa, 0x3d, 0x3a, 0xcc, 0xff, 0x99, 0xa2, 0x5e, 0xbd, 0xab, 0x5e, 0x5f,
+	0xe6, 0xcd, 0x48, 0x3a, 0x3b, 0x2c, 0x9a, 0xfd, 0x8a, 0x92, 0xd9, 0x06, 0x74, 0xf6, 0x19, 0x4a,
+	0x1a, 0xe8, 0xe0, 0x41, 0x07, 0x33, 0x1d, 0x17, 0xdd, 0x29, 0x4e, 0xa4, 0x89, 0x0e, 0xd7, 0xc1,
+	0xd3, 0x32, 0x0a, 0x66, 0x45, 0x47, 0xca, 0x63, 0xfb, 0x9f, 0x0a, 0x6a, 0x58, 0x0f, 0xc1, 0x22,
+	0x9c, 0x46, 0x33, 0x9d, 0x67, 0xf1, 0x72, 0x88, 0xfc, 0x82, 0x5a, 0xd3, 0x12, 0x51, 0x9b, 0x21,
+	0x96, 0x79, 0x72, 0x81, 0xe6, 0x86, 0xe1, 0x15, 0x84, 0xf2, 0xf6, 0x4f, 0xe8, 0x20, 0xb7, 0x96,
+	0x29, 0x56, 0xaf, 0xdf, 0x94, 0x35, 0x6d, 0xd4, 0x20, 0x9c, 0x45, 0x71, 0xa2, 0x67, 0x45, 0x65,
+	0x05, 0xbd, 0xfd, 0x77, 0x05, 0x5d, 0x7c, 0x81, 0x43, 0x7e, 0x46, 0x97, 0x9f, 0xbd, 0xa6, 0x17,
+	0x8e, 0x2e, 0x4a, 0x82, 0x28, 0xf0, 0xad, 0xa1, 0x63, 0x9d, 0x67, 0x5b, 0xe9, 0x30, 0x4d, 0x9a,
+	0x7b, 0x59, 0xab, 0x6b, 0xa5, 0x2d, 0xd8, 0x62, 0xe2, 0x19, 0xf1, 0x87, 0xbf, 0xf6, 0x11, 0x96,
+	0x7f, 0x8c, 0x9e, 0x8d, 0x90, 0x1c, 0xa1, 0xfd, 0x11, 0x75, 0x1d, 0x1b, 0x7f, 0x45, 0x30, 0x3a,
+	0x66, 0x8e, 0xab, 0x80, 0x8d, 0xc0, 0xe5, 0x1e, 0xe0, 0x0a, 0x39, 0x45, 0xd5, 0x1e, 0xb5, 0x95,
+	0x47, 0xef, 0x5d, 0x4e, 0x6d, 0xbc, 0x47, 0xce, 0xd1, 0x99, 0x09, 0x58, 0x7c, 0x30, 0xe0, 0x4c,
+	0xf5, 0x81, 0xda, 0x20, 0xf0, 0x2b, 0x72, 0x89, 0xce, 0xb3, 0xb0, 0x00, 0x2a, 0xb9, 0x50, 0xbe,
+	0x73, 0xcb, 0xa8, 0x1c, 0x0a, 0xc0, 0x5f, 0x93, 0xb7, 0xe8, 0xca, 0x61, 0x99, 0x82, 0x02, 0x66,
+	0x73, 0xe1, 0x83, 0x50, 0x52, 0x50, 0xe6, 0x53, 0x4b, 0x3a, 0x9c, 0xe1, 0x7d, 0xf2, 0x2d, 0x6a,
+	0x95, 0x0c, 0x8b, 0xb3, 0x1b, 0xe7, 0xf6, 0x19, 0x7e, 0x40, 0x5a, 0xa8, 0x31, 0x64, 0xfe, 0xd0,
+	0xf3, 0xb8, 0x90, 0x60, 0x2b, 0x39, 0xde, 0xf8, 0x39, 0x2c, 0xfd, 0x78, 0x82, 0x7b, 0xdc, 0xa7,
+	0xae, 0x92, 0x63, 0xc7, 0xc6, 0xdf, 0x10, 0x82, 0x4e, 0xec, 0xa1, 0xe7, 0x3a, 0x16, 0x95, 0x90,
+	0xc7, 0x8e, 0x8c, 0x4c, 0x61, 0x60, 0x00, 0x4c, 0x2a, 0x8f, 0xbb, 0x8e, 0x75, 0xaf, 0x6e, 0xa8,
+	0xe3, 0x1a, 0xa3, 0x88, 0x34, 0x10, 0x19, 0x8c, 0x2c, 0x4b, 0x09, 0xa0, 0xb9, 0x11, 0xd7, 0xb1,
+	0x24, 0xae, 0x9a, 0xda, 0xbc, 0x3e, 0x65, 0x92, 0x0f, 0x5e, 0x40, 0xc7, 0xa4, 0x86, 0x4e, 0x87,
+	0xec, 0x57, 0xc6, 0xef, 0x98, 0x71, 0x25, 0xef, 0x3d, 0xc0, 0xaf, 0x8d, 0x5d, 0x49, 0xc5, 0x2d,
+	0x48, 0x65, 0xf5, 0xa9, 0xc3, 0x14, 0xe3, 0x52, 0xdd, 0xf0, 0x21, 0xb3, 0xf1, 0x09, 0xa9, 0x23,
+	0x3c, 0xa0, 0xc2, 0xef, 0x67, 0x4e, 0x15, 0x08, 0xc1, 0x05, 0x3e, 0x2d, 0xfb, 0x2e, 0xc7, 0x45,
+	0xc9, 0xd8, 0x94, 0x05, 0x63, 0xcf, 0x11, 0x60, 0xe7, 0x49, 0x2c, 0x6e, 0x03, 0x3e, 0x33, 0x25,
+	0x6c, 0x8e, 0x6a, 0x04, 0xc2, 0x77, 0x38, 0xdb, 0xfa, 0x21, 0xa4, 0x89, 0xea, 0xa6, 0x1b, 0xf9,
+	0x58, 0x14, 0x8c, 0x25, 0x30, 0x43, 0xc1, 0x35, 0x53, 0x5c, 0x36, 0xa0, 0x3e, 0x65, 0x0c, 0xdc,
+	0x72, 0x70, 0xf5, 0xf2, 0x86, 0x00, 0xdf, 0xe3, 0xcc, 0x87, 0x4d, 0x67, 0xcf, 0xc9, 0x6b, 0x74,
+	0x94, 0x21, 0x77, 0x3e, 0x48, 0xdc, 0x30, 0xce, 0x1d, 0xd7, 0x85, 0x5b, 0xea, 0xaa, 0x3b, 0xe1,
+	0x48, 0x30, 0xd1, 0x0b, 0x72, 0x81, 0x6a, 0x19, 0x41, 0xf9, 0xce, 0x07, 0x50, 0x30, 0xb6, 0x00,
+	0x6c, 0xb0, 0x71, 0x93, 0x5c, 0xa2, 0x7a, 0x39, 0x53, 0x2e, 0xfb, 0x20, 0x4c, 0xeb, 0x7c, 0xce,
+	0xf0, 0xbf, 0x95, 0xde, 0x14, 0xb5, 0xa3, 0x78, 0xde, 0x79, 0x78, 0x5a, 0xeb, 0x78, 0xa9, 0x67,
+	0x73, 0x1d, 0x77, 0x3e, 0x06, 0x93, 0x78, 0x31, 0x2d, 0x1f, 0xb0, 0xd9, 0xb5, 0x3d, 0xb2, 0xb3,
+	0x13, 0xbc, 0x60, 0xfa, 0x7b, 0x30, 0xd7, 0x1f, 0xbe, 0x9f, 0x2f, 0xd2, 0x87, 0xc7, 0x89, 0x59,
+	0x61, 0xdd, 0x9d, 0xeb, 0xdd, 0xfc, 0x7a, 0xbe, 0xbd, 0x93, 0xae, 0xb9, 0x3e, 0xc9, 0x37, 0xfb,
+	0xfb, 0xff, 0x06, 0x00, 0xec, 0xb5, 0xcb, 0x26, 0xfa, 0x05, 0x00, 0x00,
 }
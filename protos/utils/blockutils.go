@@ -21,6 +21,7 @@ import (
 
 	"github.com/golang/protobuf/proto"
 	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
 )
 
 // GetChainIDFromBlockBytes returns chain ID given byte array which represents the block
@@ -78,6 +79,24 @@ func GetMetadataFromBlockOrPanic(block *cb.Block, index cb.BlockMetadataIndex) *
 	return md
 }
 
+// GetTraceMetadataFromBlock retrieves the trace metadata written by the orderer in the TRACE_IDS
+// block metadata, or nil if the block has none
+func GetTraceMetadataFromBlock(block *cb.Block) (*ab.TraceMetadata, error) {
+	if block.Metadata == nil || len(block.Metadata.Metadata) <= int(cb.BlockMetadataIndex_TRACE_IDS) ||
+		len(block.Metadata.Metadata[cb.BlockMetadataIndex_TRACE_IDS]) == 0 {
+		return nil, nil
+	}
+	md, err := GetMetadataFromBlock(block, cb.BlockMetadataIndex_TRACE_IDS)
+	if err != nil {
+		return nil, fmt.Errorf("malformed trace metadata: %s", err)
+	}
+	traces := &ab.TraceMetadata{}
+	if err := proto.Unmarshal(md.Value, traces); err != nil {
+		return nil, fmt.Errorf("malformed trace metadata: %s", err)
+	}
+	return traces, nil
+}
+
 // GetLastConfigIndexFromBlock retrieves the index of the last config block as encoded in the block metadata
 func GetLastConfigIndexFromBlock(block *cb.Block) (uint64, error) {
 	md, err := GetMetadataFromBlock(block, cb.BlockMetadataIndex_LAST_CONFIG)
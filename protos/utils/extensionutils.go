@@ -0,0 +1,184 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package utils
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric/protos/common"
+)
+
+// MetadataExtensionCodec serializes the values of a type of block metadata extension. The codecs are registered
+// with RegisterMetadataExtensionCodec by the consenters and plugins attaching the extensions to the blocks, and by
+// the readers of the extensions.
+type MetadataExtensionCodec interface {
+	// Type returns the unique name of the type of the extensions, e.g. "org.example.attestations"
+	Type() string
+	// Version returns the version of the encoding written by Marshal
+	Version() uint32
+	// Marshal encodes a value with the current version of the encoding
+	Marshal(value interface{}) ([]byte, error)
+	// Unmarshal decodes a value encoded with the given version of the encoding
+	Unmarshal(version uint32, data []byte) (interface{}, error)
+}
+
+var metadataExtensionCodecs = struct {
+	sync.RWMutex
+	codecs map[string]MetadataExtensionCodec
+}{codecs: make(map[string]MetadataExtensionCodec)}
+
+// RegisterMetadataExtensionCodec registers the codec of a type of block metadata extension, and returns an error
+// if a codec is registered for the type already
+func RegisterMetadataExtensionCodec(codec MetadataExtensionCodec) error {
+	if codec.Type() == "" {
+		return fmt.Errorf("the type of a metadata extension must not be empty")
+	}
+	metadataExtensionCodecs.Lock()
+	defer metadataExtensionCodecs.Unlock()
+	if _, ok := metadataExtensionCodecs.codecs[codec.Type()]; ok {
+		return fmt.Errorf("a codec is registered for metadata extension %s already", codec.Type())
+	}
+	metadataExtensionCodecs.codecs[codec.Type()] = codec
+	return nil
+}
+
+func getMetadataExtensionCodec(extensionType string) (MetadataExtensionCodec, error) {
+	metadataExtensionCodecs.RLock()
+	defer metadataExtensionCodecs.RUnlock()
+	codec, ok := metadataExtensionCodecs.codecs[extensionType]
+	if !ok {
+		return nil, fmt.Errorf("no codec is registered for metadata extension %s", extensionType)
+	}
+	return codec, nil
+}
+
+// protoMetadataExtensionCodec encodes the values of the extensions of a type as protobuf messages
+type protoMetadataExtensionCodec struct {
+	extensionType string
+	version       uint32
+	newMessage    func() proto.Message
+}
+
+// NewProtoMetadataExtensionCodec returns a codec of the extensions whose values are protobuf messages, as returned
+// empty by newMessage. The values encoded by the later versions of the codec are rejected, the earlier ones being
+// expected to be decoded into the current message as the protobuf compatibility rules allow.
+func NewProtoMetadataExtensionCodec(extensionType string, version uint32, newMessage func() proto.Message) MetadataExtensionCodec {
+	return &protoMetadataExtensionCodec{extensionType: extensionType, version: version, newMessage: newMessage}
+}
+
+func (c *protoMetadataExtensionCodec) Type() string {
+	return c.extensionType
+}
+
+func (c *protoMetadataExtensionCodec) Version() uint32 {
+	return c.version
+}
+
+func (c *protoMetadataExtensionCodec) Marshal(value interface{}) ([]byte, error) {
+	msg, ok := value.(proto.Message)
+	if !ok || reflect.TypeOf(msg) != reflect.TypeOf(c.newMessage()) {
+		return nil, fmt.Errorf("metadata extension %s expects a %T value, got %T", c.extensionType, c.newMessage(), value)
+	}
+	return proto.Marshal(msg)
+}
+
+func (c *protoMetadataExtensionCodec) Unmarshal(version uint32, data []byte) (interface{}, error) {
+	if version > c.version {
+		return nil, fmt.Errorf("metadata extension %s has version %d, newer than the supported version %d", c.extensionType, version, c.version)
+	}
+	msg := c.newMessage()
+	if err := proto.Unmarshal(data, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// GetMetadataExtensionsFromBlock returns the extensions in the EXTENSIONS block metadata, or nil if the block has none
+func GetMetadataExtensionsFromBlock(block *cb.Block) (*cb.MetadataExtensions, error) {
+	if block.Metadata == nil || len(block.Metadata.Metadata) <= int(cb.BlockMetadataIndex_EXTENSIONS) ||
+		len(block.Metadata.Metadata[cb.BlockMetadataIndex_EXTENSIONS]) == 0 {
+		return nil, nil
+	}
+	md, err := GetMetadataFromBlock(block, cb.BlockMetadataIndex_EXTENSIONS)
+	if err != nil {
+		return nil, fmt.Errorf("malformed metadata extensions: %s", err)
+	}
+	extensions := &cb.MetadataExtensions{}
+	if err := proto.Unmarshal(md.Value, extensions); err != nil {
+		return nil, fmt.Errorf("malformed metadata extensions: %s", err)
+	}
+	return extensions, nil
+}
+
+// GetMetadataExtensionFromBlock decodes the extension of the given type of the block with the codec registered for
+// the type, and returns nil if the block has none
+func GetMetadataExtensionFromBlock(block *cb.Block, extensionType string) (interface{}, error) {
+	codec, err := getMetadataExtensionCodec(extensionType)
+	if err != nil {
+		return nil, err
+	}
+	extensions, err := GetMetadataExtensionsFromBlock(block)
+	if err != nil || extensions == nil {
+		return nil, err
+	}
+	for _, extension := range extensions.Extensions {
+		if extension.Type != extensionType {
+			continue
+		}
+		value, err := codec.Unmarshal(extension.Version, extension.Value)
+		if err != nil {
+			return nil, fmt.Errorf("malformed metadata extension %s: %s", extensionType, err)
+		}
+		return value, nil
+	}
+	return nil, nil
+}
+
+// SetMetadataExtensionInBlock encodes the value with the codec registered for the type of the extension and attaches
+// it to the block, replacing the extension of the type the block may have already. The extensions are kept sorted by
+// type, so that the orderers attaching the same extensions write the same metadata.
+func SetMetadataExtensionInBlock(block *cb.Block, extensionType string, value interface{}) error {
+	codec, err := getMetadataExtensionCodec(extensionType)
+	if err != nil {
+		return err
+	}
+	encoded, err := codec.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("cannot marshal metadata extension %s: %s", extensionType, err)
+	}
+	extensions, err := GetMetadataExtensionsFromBlock(block)
+	if err != nil {
+		return err
+	}
+	if extensions == nil {
+		extensions = &cb.MetadataExtensions{}
+	}
+
+	extension := &cb.MetadataExtension{Type: extensionType, Version: codec.Version(), Value: encoded}
+	i := sort.Search(len(extensions.Extensions), func(i int) bool { return extensions.Extensions[i].Type >= extensionType })
+	switch {
+	case i < len(extensions.Extensions) && extensions.Extensions[i].Type == extensionType:
+		extensions.Extensions[i] = extension
+	default:
+		extensions.Extensions = append(extensions.Extensions, nil)
+		copy(extensions.Extensions[i+1:], extensions.Extensions[i:])
+		extensions.Extensions[i] = extension
+	}
+
+	if block.Metadata == nil {
+		block.Metadata = &cb.BlockMetadata{}
+	}
+	for len(block.Metadata.Metadata) <= int(cb.BlockMetadataIndex_EXTENSIONS) {
+		block.Metadata.Metadata = append(block.Metadata.Metadata, []byte{})
+	}
+	block.Metadata.Metadata[cb.BlockMetadataIndex_EXTENSIONS] = MarshalOrPanic(&cb.Metadata{Value: MarshalOrPanic(extensions)})
+	return nil
+}
@@ -0,0 +1,78 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetadataExtensionCodecRegistration(t *testing.T) {
+	codec := NewProtoMetadataExtensionCodec("test.registration", 1, func() proto.Message { return &cb.LastConfig{} })
+	assert.NoError(t, RegisterMetadataExtensionCodec(codec))
+	assert.Error(t, RegisterMetadataExtensionCodec(codec), "Expected an error for a type registered twice")
+	assert.Error(t, RegisterMetadataExtensionCodec(NewProtoMetadataExtensionCodec("", 1, nil)), "Expected an error for an empty type")
+}
+
+func TestMetadataExtensions(t *testing.T) {
+	require.NoError(t, RegisterMetadataExtensionCodec(NewProtoMetadataExtensionCodec("test.b", 2, func() proto.Message { return &cb.LastConfig{} })))
+	require.NoError(t, RegisterMetadataExtensionCodec(NewProtoMetadataExtensionCodec("test.a", 1, func() proto.Message { return &cb.BlockHeader{} })))
+
+	block := cb.NewBlock(0, nil)
+	extensions, err := GetMetadataExtensionsFromBlock(block)
+	assert.NoError(t, err)
+	assert.Nil(t, extensions, "Expected no extensions in a new block")
+	value, err := GetMetadataExtensionFromBlock(block, "test.a")
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+
+	require.NoError(t, SetMetadataExtensionInBlock(block, "test.b", &cb.LastConfig{Index: 7}))
+	require.NoError(t, SetMetadataExtensionInBlock(block, "test.a", &cb.BlockHeader{Number: 3}))
+	require.NoError(t, SetMetadataExtensionInBlock(block, "test.b", &cb.LastConfig{Index: 8}))
+	assert.Error(t, SetMetadataExtensionInBlock(block, "test.b", &cb.BlockHeader{}), "Expected an error for a value of the wrong type")
+	assert.Error(t, SetMetadataExtensionInBlock(block, "test.unknown", &cb.LastConfig{}), "Expected an error for an unregistered type")
+
+	extensions, err = GetMetadataExtensionsFromBlock(block)
+	require.NoError(t, err)
+	require.Len(t, extensions.Extensions, 2, "Expected the extension of the same type to be replaced")
+	assert.Equal(t, "test.a", extensions.Extensions[0].Type, "Expected the extensions to be sorted by type")
+	assert.Equal(t, uint32(2), extensions.Extensions[1].Version)
+
+	value, err = GetMetadataExtensionFromBlock(block, "test.b")
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(&cb.LastConfig{Index: 8}, value.(proto.Message)))
+	value, err = GetMetadataExtensionFromBlock(block, "test.a")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), value.(*cb.BlockHeader).Number)
+
+	// The other metadata positions are left untouched
+	assert.Empty(t, block.Metadata.Metadata[cb.BlockMetadataIndex_ORDERER])
+
+	t.Run("NewerVersion", func(t *testing.T) {
+		extensions.Extensions[1].Version = 3
+		block.Metadata.Metadata[cb.BlockMetadataIndex_EXTENSIONS] = MarshalOrPanic(&cb.Metadata{Value: MarshalOrPanic(extensions)})
+		_, err := GetMetadataExtensionFromBlock(block, "test.b")
+		assert.Error(t, err, "Expected an error for a version newer than the codec")
+	})
+
+	t.Run("Malformed", func(t *testing.T) {
+		block.Metadata.Metadata[cb.BlockMetadataIndex_EXTENSIONS] = []byte("garbage")
+		_, err := GetMetadataExtensionsFromBlock(block)
+		assert.Error(t, err)
+		assert.Error(t, SetMetadataExtensionInBlock(block, "test.a", &cb.BlockHeader{}))
+	})
+
+	t.Run("ShortMetadata", func(t *testing.T) {
+		block := &cb.Block{Metadata: &cb.BlockMetadata{Metadata: [][]byte{{}, {}, {}}}}
+		require.NoError(t, SetMetadataExtensionInBlock(block, "test.a", &cb.BlockHeader{Number: 1}))
+		assert.Len(t, block.Metadata.Metadata, int(cb.BlockMetadataIndex_EXTENSIONS)+1)
+	})
+}
@@ -0,0 +1,127 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package utils
+
+import (
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/any"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// BroadcastErrorDomain is the domain of the ErrorDetail of the failures of Broadcast
+	BroadcastErrorDomain = "orderer.broadcast"
+
+	// DeliverErrorDomain is the domain of the ErrorDetail of the failures of Deliver
+	DeliverErrorDomain = "orderer.deliver"
+
+	// HeightsErrorDomain is the domain of the ErrorDetail of the failures of the height subscriptions
+	HeightsErrorDomain = "orderer.heights"
+
+	errorDetailTypeURL = "type.googleapis.com/orderer.ErrorDetail"
+)
+
+// GRPCCode maps a status of the orderer onto the gRPC code with the same meaning
+func GRPCCode(st cb.Status) codes.Code {
+	switch st {
+	case cb.Status_SUCCESS:
+		return codes.OK
+	case cb.Status_BAD_REQUEST, cb.Status_REQUEST_ENTITY_TOO_LARGE:
+		return codes.InvalidArgument
+	case cb.Status_FORBIDDEN:
+		return codes.PermissionDenied
+	case cb.Status_NOT_FOUND:
+		return codes.NotFound
+	case cb.Status_INTERNAL_SERVER_ERROR:
+		return codes.Internal
+	case cb.Status_SERVICE_UNAVAILABLE:
+		return codes.Unavailable
+	default:
+		return codes.Unknown
+	}
+}
+
+// BroadcastErrorDetail returns the ErrorDetail of a response of Broadcast rejecting an envelope of the given
+// channel. The envelopes may be retried if the orderer or the channel are unavailable, or if a quota is exceeded
+func BroadcastErrorDetail(chainID string, resp *ab.BroadcastResponse) *ab.ErrorDetail {
+	detail := &ab.ErrorDetail{
+		Domain:    BroadcastErrorDomain,
+		ChannelId: chainID,
+		Status:    resp.Status,
+		Retriable: resp.Status == cb.Status_SERVICE_UNAVAILABLE,
+	}
+	if resp.Error != nil {
+		detail.Class = resp.Error.Class
+		detail.ChannelState = resp.Error.ChannelState
+		detail.RetryAfterMs = resp.Error.RetryAfterMs
+		detail.Retriable = detail.Retriable || resp.Error.Class == ab.BroadcastError_QUOTA_EXCEEDED
+	}
+	return detail
+}
+
+// DeliverErrorDetail returns the ErrorDetail of a status of Deliver ending the delivery of the blocks of the
+// given channel. The delivery may be retried if the channel is unavailable
+func DeliverErrorDetail(chainID string, st cb.Status) *ab.ErrorDetail {
+	return &ab.ErrorDetail{
+		Domain:    DeliverErrorDomain,
+		ChannelId: chainID,
+		Status:    st,
+		Retriable: st == cb.Status_SERVICE_UNAVAILABLE,
+	}
+}
+
+// HeightsErrorDetail returns the ErrorDetail of a status ending a subscription to the heights of the given channel,
+// or of several channels if it is empty. The subscription may be retried if the orderer is unavailable or dropped
+// the subscriber for lagging behind
+func HeightsErrorDetail(chainID string, st cb.Status) *ab.ErrorDetail {
+	return &ab.ErrorDetail{
+		Domain:    HeightsErrorDomain,
+		ChannelId: chainID,
+		Status:    st,
+		Retriable: st == cb.Status_SERVICE_UNAVAILABLE,
+	}
+}
+
+// NewErrorStatus returns the gRPC status carrying the ErrorDetail, whose code is derived from the status and the
+// class of the detail
+func NewErrorStatus(detail *ab.ErrorDetail, message string) *status.Status {
+	code := GRPCCode(detail.Status)
+	if detail.Class == ab.BroadcastError_QUOTA_EXCEEDED {
+		code = codes.ResourceExhausted
+	}
+	return status.FromProto(&spb.Status{
+		Code:    int32(code),
+		Message: message,
+		Details: []*any.Any{{TypeUrl: errorDetailTypeURL, Value: MarshalOrPanic(detail)}},
+	})
+}
+
+// GetErrorDetail returns the ErrorDetail carried by the gRPC status of an error returned by the Broadcast or the
+// Deliver streams, and false if there is none
+func GetErrorDetail(err error) (*ab.ErrorDetail, bool) {
+	st, ok := status.FromError(err)
+	if !ok {
+		return nil, false
+	}
+	for _, detail := range st.Proto().Details {
+		if !strings.HasSuffix(detail.TypeUrl, "/orderer.ErrorDetail") {
+			continue
+		}
+		errorDetail := &ab.ErrorDetail{}
+		if err := proto.Unmarshal(detail.Value, errorDetail); err != nil {
+			return nil, false
+		}
+		return errorDetail, true
+	}
+	return nil, false
+}
@@ -0,0 +1,105 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package utils
+
+import (
+	"errors"
+	"testing"
+
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+func TestGRPCCode(t *testing.T) {
+	for st, code := range map[cb.Status]codes.Code{
+		cb.Status_SUCCESS:                  codes.OK,
+		cb.Status_BAD_REQUEST:              codes.InvalidArgument,
+		cb.Status_REQUEST_ENTITY_TOO_LARGE: codes.InvalidArgument,
+		cb.Status_FORBIDDEN:                codes.PermissionDenied,
+		cb.Status_NOT_FOUND:                codes.NotFound,
+		cb.Status_INTERNAL_SERVER_ERROR:    codes.Internal,
+		cb.Status_SERVICE_UNAVAILABLE:      codes.Unavailable,
+		cb.Status_UNKNOWN:                  codes.Unknown,
+	} {
+		assert.Equal(t, code, GRPCCode(st), "Unexpected code for status %s", st)
+	}
+}
+
+func TestBroadcastErrorStatus(t *testing.T) {
+	resp := &ab.BroadcastResponse{
+		Status: cb.Status_SERVICE_UNAVAILABLE,
+		Error: &ab.BroadcastError{
+			Class:        ab.BroadcastError_UNAVAILABLE,
+			Message:      "overloaded",
+			RetryAfterMs: 500,
+			ChannelState: ab.BroadcastError_ACTIVE,
+		},
+	}
+	err := NewErrorStatus(BroadcastErrorDetail("mychannel", resp), resp.Error.Message).Err()
+	assert.Equal(t, codes.Unavailable, grpc.Code(err))
+	assert.Equal(t, "overloaded", grpc.ErrorDesc(err))
+
+	detail, ok := GetErrorDetail(err)
+	assert.True(t, ok)
+	assert.Equal(t, &ab.ErrorDetail{
+		Domain:       BroadcastErrorDomain,
+		ChannelId:    "mychannel",
+		Status:       cb.Status_SERVICE_UNAVAILABLE,
+		Class:        ab.BroadcastError_UNAVAILABLE,
+		ChannelState: ab.BroadcastError_ACTIVE,
+		Retriable:    true,
+		RetryAfterMs: 500,
+	}, detail)
+
+	resp = &ab.BroadcastResponse{
+		Status: cb.Status_FORBIDDEN,
+		Error:  &ab.BroadcastError{Class: ab.BroadcastError_QUOTA_EXCEEDED},
+	}
+	err = NewErrorStatus(BroadcastErrorDetail("mychannel", resp), "quota").Err()
+	assert.Equal(t, codes.ResourceExhausted, grpc.Code(err), "The quotas exceeded should map onto ResourceExhausted")
+	detail, _ = GetErrorDetail(err)
+	assert.True(t, detail.Retriable, "The envelopes over quota may be retried in the next period")
+
+	resp = &ab.BroadcastResponse{Status: cb.Status_BAD_REQUEST, Error: &ab.BroadcastError{Class: ab.BroadcastError_MALFORMED}}
+	detail = BroadcastErrorDetail("", resp)
+	assert.False(t, detail.Retriable)
+}
+
+func TestDeliverErrorStatus(t *testing.T) {
+	err := NewErrorStatus(DeliverErrorDetail("mychannel", cb.Status_NOT_FOUND), "not found").Err()
+	assert.Equal(t, codes.NotFound, grpc.Code(err))
+	detail, ok := GetErrorDetail(err)
+	assert.True(t, ok)
+	assert.Equal(t, DeliverErrorDomain, detail.Domain)
+	assert.Equal(t, "mychannel", detail.ChannelId)
+	assert.False(t, detail.Retriable)
+
+	assert.True(t, DeliverErrorDetail("mychannel", cb.Status_SERVICE_UNAVAILABLE).Retriable)
+}
+
+func TestHeightsErrorStatus(t *testing.T) {
+	err := NewErrorStatus(HeightsErrorDetail("", cb.Status_SERVICE_UNAVAILABLE), "lagging behind").Err()
+	assert.Equal(t, codes.Unavailable, grpc.Code(err))
+	detail, ok := GetErrorDetail(err)
+	assert.True(t, ok)
+	assert.Equal(t, HeightsErrorDomain, detail.Domain)
+	assert.True(t, detail.Retriable)
+
+	assert.False(t, HeightsErrorDetail("mychannel", cb.Status_FORBIDDEN).Retriable)
+}
+
+func TestGetErrorDetailWithoutDetail(t *testing.T) {
+	_, ok := GetErrorDetail(errors.New("not a status"))
+	assert.False(t, ok)
+	_, ok = GetErrorDetail(grpc.Errorf(codes.Unavailable, "no detail"))
+	assert.False(t, ok)
+	_, ok = GetErrorDetail(nil)
+	assert.False(t, ok)
+}
@@ -425,6 +425,21 @@ func CreateInstallProposalFromCDS(ccpack proto.Message, creator []byte) (*peer.P
 	return createProposalFromCDS("", ccpack, creator, nil, nil, nil, "install")
 }
 
+// CreateInstallProposalFromCDSWithSignature returns a install proposal given a serialized identity,
+// a chaincode package and the detached signature of the package
+func CreateInstallProposalFromCDSWithSignature(ccpack proto.Message, signature []byte, creator []byte) (*peer.Proposal, string, error) {
+	b, err := proto.Marshal(ccpack)
+	if err != nil {
+		return nil, "", err
+	}
+	lsccSpec := &peer.ChaincodeInvocationSpec{
+		ChaincodeSpec: &peer.ChaincodeSpec{
+			Type:        peer.ChaincodeSpec_GOLANG,
+			ChaincodeId: &peer.ChaincodeID{Name: "lscc"},
+			Input:       &peer.ChaincodeInput{Args: [][]byte{[]byte("install"), b, signature}}}}
+	return CreateProposalFromCIS(common.HeaderType_ENDORSER_TRANSACTION, "", lsccSpec, creator)
+}
+
 // CreateDeployProposalFromCDS returns a deploy proposal given a serialized identity and a ChaincodeDeploymentSpec
 func CreateDeployProposalFromCDS(chainID string, cds *peer.ChaincodeDeploymentSpec, creator []byte, policy []byte, escc []byte, vscc []byte) (*peer.Proposal, string, error) {
 	return createProposalFromCDS(chainID, cds, creator, policy, escc, vscc, "deploy")
@@ -26,6 +26,7 @@ import (
 	"github.com/hyperledger/fabric/bccsp"
 	"github.com/hyperledger/fabric/bccsp/factory"
 	"github.com/hyperledger/fabric/common/crypto"
+	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/msp"
 	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/peer"
@@ -108,6 +109,89 @@ func CreateSignedEnvelope(txType common.HeaderType, channelID string, signer cry
 	return &common.Envelope{Payload: paylBytes, Signature: sig}, nil
 }
 
+// CreateUnsignedEnvelope creates the payload of an envelope of the desired type, with marshaled
+// dataMsg, for the given creator to sign offline. The signed envelope is then assembled with
+// CreateEnvelopeFromSignature
+func CreateUnsignedEnvelope(txType common.HeaderType, channelID string, creator []byte, dataMsg proto.Message, msgVersion int32, epoch uint64) (*common.Payload, error) {
+	nonce, err := CreateNonce()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := proto.Marshal(dataMsg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &common.Payload{
+		Header: MakePayloadHeader(MakeChannelHeader(txType, msgVersion, channelID, epoch), MakeSignatureHeader(creator, nonce)),
+		Data:   data,
+	}, nil
+}
+
+// CreateEnvelopeFromSignature assembles an Envelope message from the marshaled payload of a
+// transaction and its detached signature, as produced offline by the creator of the transaction
+func CreateEnvelopeFromSignature(payloadBytes []byte, signature []byte) (*common.Envelope, error) {
+	if len(signature) == 0 {
+		return nil, fmt.Errorf("Missing signature")
+	}
+
+	payload, err := UnmarshalPayload(payloadBytes)
+	if err != nil {
+		return nil, err
+	}
+	if payload.Header == nil {
+		return nil, fmt.Errorf("Missing payload header")
+	}
+
+	return &common.Envelope{Payload: payloadBytes, Signature: signature}, nil
+}
+
+// CreateUnsignedConfigSignature creates the signature of a config update by the given creator,
+// without its signature bytes. The bytes the creator signs offline are returned by
+// ConfigSignatureBytes
+func CreateUnsignedConfigSignature(creator []byte) (*common.ConfigSignature, error) {
+	nonce, err := CreateNonce()
+	if err != nil {
+		return nil, err
+	}
+
+	sigHeaderBytes, err := GetBytesSignatureHeader(MakeSignatureHeader(creator, nonce))
+	if err != nil {
+		return nil, err
+	}
+
+	return &common.ConfigSignature{SignatureHeader: sigHeaderBytes}, nil
+}
+
+// ConfigSignatureBytes returns the bytes to sign for the signature of a marshaled config update
+func ConfigSignatureBytes(configSig *common.ConfigSignature, configUpdate []byte) []byte {
+	return util.ConcatenateBytes(configSig.SignatureHeader, configUpdate)
+}
+
+// GetConfigSignatureFromSignature assembles the signature of a marshaled config update from the
+// bytes signed offline, as returned by ConfigSignatureBytes, and their detached signature
+func GetConfigSignatureFromSignature(signedBytes []byte, configUpdate []byte, signature []byte) (*common.ConfigSignature, error) {
+	if len(signature) == 0 {
+		return nil, fmt.Errorf("Missing signature")
+	}
+
+	if len(signedBytes) <= len(configUpdate) || !bytes.Equal(signedBytes[len(signedBytes)-len(configUpdate):], configUpdate) {
+		return nil, fmt.Errorf("The signed bytes do not end with the config update")
+	}
+
+	sigHeaderBytes := signedBytes[:len(signedBytes)-len(configUpdate)]
+	sigHeader, err := GetSignatureHeader(sigHeaderBytes)
+	if err != nil {
+		return nil, err
+	}
+	if len(sigHeader.Creator) == 0 || len(sigHeader.Nonce) == 0 {
+		return nil, fmt.Errorf("The signed bytes do not start with a signature header")
+	}
+
+	return &common.ConfigSignature{SignatureHeader: sigHeaderBytes, Signature: signature}, nil
+}
+
 // CreateSignedTx assembles an Envelope message from proposal, endorsements, and a signer.
 // This function should be called by a client when it has collected enough endorsements
 // for a proposal to create a transaction and submit it to peers for ordering
@@ -122,12 +206,6 @@ func CreateSignedTx(proposal *peer.Proposal, signer msp.SigningIdentity, resps .
 		return nil, fmt.Errorf("Could not unmarshal the proposal header")
 	}
 
-	// the original payload
-	pPayl, err := GetChaincodeProposalPayload(proposal.Payload)
-	if err != nil {
-		return nil, fmt.Errorf("Could not unmarshal the proposal payload")
-	}
-
 	// check that the signer is the same that is referenced in the header
 	// TODO: maybe worth removing?
 	signerBytes, err := signer.Serialize()
@@ -144,6 +222,44 @@ func CreateSignedTx(proposal *peer.Proposal, signer msp.SigningIdentity, resps .
 		return nil, fmt.Errorf("The signer needs to be the same as the one referenced in the header")
 	}
 
+	payl, err := CreateUnsignedTx(proposal, resps...)
+	if err != nil {
+		return nil, err
+	}
+	paylBytes, err := GetBytesPayload(payl)
+	if err != nil {
+		return nil, err
+	}
+
+	// sign the payload
+	sig, err := signer.Sign(paylBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	// here's the envelope
+	return &common.Envelope{Payload: paylBytes, Signature: sig}, nil
+}
+
+// CreateUnsignedTx assembles the payload of a transaction from proposal and endorsements,
+// leaving to the creator of the proposal to sign it
+func CreateUnsignedTx(proposal *peer.Proposal, resps ...*peer.ProposalResponse) (*common.Payload, error) {
+	if len(resps) == 0 {
+		return nil, fmt.Errorf("At least one proposal response is necessary")
+	}
+
+	// the original header
+	hdr, err := GetHeader(proposal.Header)
+	if err != nil {
+		return nil, fmt.Errorf("Could not unmarshal the proposal header")
+	}
+
+	// the original payload
+	pPayl, err := GetChaincodeProposalPayload(proposal.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("Could not unmarshal the proposal payload")
+	}
+
 	// get header extensions so we have the visibility field
 	hdrExt, err := GetChaincodeHeaderExtension(hdr)
 	if err != nil {
@@ -201,20 +317,7 @@ func CreateSignedTx(proposal *peer.Proposal, signer msp.SigningIdentity, resps .
 	}
 
 	// create the payload
-	payl := &common.Payload{Header: hdr, Data: txBytes}
-	paylBytes, err := GetBytesPayload(payl)
-	if err != nil {
-		return nil, err
-	}
-
-	// sign the payload
-	sig, err := signer.Sign(paylBytes)
-	if err != nil {
-		return nil, err
-	}
-
-	// here's the envelope
-	return &common.Envelope{Payload: paylBytes, Signature: sig}, nil
+	return &common.Payload{Header: hdr, Data: txBytes}, nil
 }
 
 // CreateProposalResponse creates a proposal response.
@@ -262,6 +365,18 @@ func CreateProposalResponse(hdrbytes []byte, payl []byte, response *peer.Respons
 // endorsement proposal fails either due to a endorsement failure or a chaincode
 // failure (chaincode response status >= shim.ERRORTHRESHOLD)
 func CreateProposalResponseFailure(hdrbytes []byte, payl []byte, response *peer.Response, results []byte, events []byte, ccid *peer.ChaincodeID, visibility []byte) (*peer.ProposalResponse, error) {
+	resp, err := CreateUnendorsedProposalResponse(hdrbytes, payl, response, results, events, ccid, visibility)
+	if err != nil {
+		return nil, err
+	}
+	resp.Response = &peer.Response{Status: 500, Message: "Chaincode Error"}
+
+	return resp, nil
+}
+
+// CreateUnendorsedProposalResponse creates a proposal response carrying the results of
+// a simulation and the response of the chaincode, without an endorsement
+func CreateUnendorsedProposalResponse(hdrbytes []byte, payl []byte, response *peer.Response, results []byte, events []byte, ccid *peer.ChaincodeID, visibility []byte) (*peer.ProposalResponse, error) {
 	hdr, err := GetHeader(hdrbytes)
 	if err != nil {
 		return nil, err
@@ -282,7 +397,7 @@ func CreateProposalResponseFailure(hdrbytes []byte, payl []byte, response *peer.
 	resp := &peer.ProposalResponse{
 		// Timestamp: TODO!
 		Payload:  prpBytes,
-		Response: &peer.Response{Status: 500, Message: "Chaincode Error"}}
+		Response: response}
 
 	return resp, nil
 }
@@ -307,6 +422,24 @@ func GetSignedProposal(prop *peer.Proposal, signer msp.SigningIdentity) (*peer.S
 	return &peer.SignedProposal{ProposalBytes: propBytes, Signature: signature}, nil
 }
 
+// GetSignedProposalFromSignature returns a signed proposal given the marshaled Proposal message
+// and its detached signature, as produced offline by the creator of the proposal
+func GetSignedProposalFromSignature(propBytes []byte, signature []byte) (*peer.SignedProposal, error) {
+	if len(signature) == 0 {
+		return nil, fmt.Errorf("Missing signature")
+	}
+
+	prop, err := GetProposal(propBytes)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := GetHeader(prop.Header); err != nil {
+		return nil, err
+	}
+
+	return &peer.SignedProposal{ProposalBytes: propBytes, Signature: signature}, nil
+}
+
 // GetSignedEvent returns a signed event given an Event message and a signing identity
 func GetSignedEvent(evt *peer.Event, signer msp.SigningIdentity) (*peer.SignedEvent, error) {
 	// check for nil argument
@@ -275,6 +275,71 @@ func TestCreateSignedEnvelopeNilSigner(t *testing.T) {
 	assert.Equal(t, msg, data, "Payload data does not match expected value")
 }
 
+func TestCreateUnsignedEnvelope(t *testing.T) {
+	msg := &cb.ConfigUpdateEnvelope{ConfigUpdate: []byte("update")}
+	payload, err := utils.CreateUnsignedEnvelope(cb.HeaderType_CONFIG_UPDATE, "mychannelID", []byte("creator"), msg, int32(0), uint64(0))
+	assert.NoError(t, err, "Unexpected error creating unsigned envelope")
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	assert.NoError(t, err, "Failed to unmarshal channel header")
+	assert.Equal(t, int32(cb.HeaderType_CONFIG_UPDATE), chdr.Type)
+	assert.Equal(t, "mychannelID", chdr.ChannelId)
+	shdr, err := utils.GetSignatureHeader(payload.Header.SignatureHeader)
+	assert.NoError(t, err, "Failed to unmarshal signature header")
+	assert.Equal(t, []byte("creator"), shdr.Creator)
+	assert.NotEmpty(t, shdr.Nonce, "Expected a nonce")
+
+	payloadBytes := utils.MarshalOrPanic(payload)
+	env, err := utils.CreateEnvelopeFromSignature(payloadBytes, []byte("signature"))
+	assert.NoError(t, err, "Unexpected error assembling the envelope")
+	assert.Equal(t, payloadBytes, env.Payload)
+	assert.Equal(t, []byte("signature"), env.Signature)
+
+	_, err = utils.CreateEnvelopeFromSignature(payloadBytes, nil)
+	assert.Error(t, err, "Expected error without signature")
+	_, err = utils.CreateEnvelopeFromSignature([]byte("garbage"), []byte("signature"))
+	assert.Error(t, err, "Expected error with a bad payload")
+	_, err = utils.CreateEnvelopeFromSignature(utils.MarshalOrPanic(&cb.Payload{Data: []byte("data")}), []byte("signature"))
+	assert.Error(t, err, "Expected error without payload header")
+}
+
+func TestConfigSignatureFromSignature(t *testing.T) {
+	configUpdate := []byte("config update")
+	configSig, err := utils.CreateUnsignedConfigSignature([]byte("creator"))
+	assert.NoError(t, err, "Unexpected error creating config signature")
+	assert.Empty(t, configSig.Signature)
+	signedBytes := utils.ConfigSignatureBytes(configSig, configUpdate)
+	assert.Equal(t, util.ConcatenateBytes(configSig.SignatureHeader, configUpdate), signedBytes)
+
+	signed, err := utils.GetConfigSignatureFromSignature(signedBytes, configUpdate, []byte("signature"))
+	assert.NoError(t, err, "Unexpected error assembling the config signature")
+	assert.Equal(t, configSig.SignatureHeader, signed.SignatureHeader)
+	assert.Equal(t, []byte("signature"), signed.Signature)
+
+	_, err = utils.GetConfigSignatureFromSignature(signedBytes, configUpdate, nil)
+	assert.Error(t, err, "Expected error without signature")
+	_, err = utils.GetConfigSignatureFromSignature(signedBytes, []byte("other update"), []byte("signature"))
+	assert.Error(t, err, "Expected error with another config update")
+	_, err = utils.GetConfigSignatureFromSignature(configUpdate, configUpdate, []byte("signature"))
+	assert.Error(t, err, "Expected error without signature header")
+}
+
+func TestGetSignedProposalFromSignature(t *testing.T) {
+	prop, _, err := utils.CreateChaincodeProposal(cb.HeaderType_ENDORSER_TRANSACTION, util.GetTestChainID(),
+		&pb.ChaincodeInvocationSpec{ChaincodeSpec: &pb.ChaincodeSpec{ChaincodeId: &pb.ChaincodeID{Name: "mycc"}}}, []byte("creator"))
+	assert.NoError(t, err, "Unexpected error creating proposal")
+	propBytes := utils.MarshalOrPanic(prop)
+
+	signedProp, err := utils.GetSignedProposalFromSignature(propBytes, []byte("signature"))
+	assert.NoError(t, err, "Unexpected error getting signed proposal")
+	assert.Equal(t, propBytes, signedProp.ProposalBytes)
+	assert.Equal(t, []byte("signature"), signedProp.Signature)
+
+	_, err = utils.GetSignedProposalFromSignature(propBytes, nil)
+	assert.Error(t, err, "Expected error without signature")
+	_, err = utils.GetSignedProposalFromSignature([]byte("garbage"), []byte("signature"))
+	assert.Error(t, err, "Expected error with a bad proposal")
+}
+
 func TestGetSignedProposal(t *testing.T) {
 	var signedProp *pb.SignedProposal
 	var err error
@@ -465,6 +530,27 @@ func TestCreateProposalResponseFailure(t *testing.T) {
 	assert.Equal(t, "Invalid function name", string(ca.Response.Payload))
 }
 
+func TestCreateUnendorsedProposalResponse(t *testing.T) {
+	prop, _, err := utils.CreateChaincodeProposal(cb.HeaderType_ENDORSER_TRANSACTION, util.GetTestChainID(), createCIS(), signerSerialized)
+	assert.NoError(t, err, "Could not create chaincode proposal")
+
+	response := &pb.Response{Status: 200, Payload: []byte("OK")}
+	ccid := &pb.ChaincodeID{Name: "foo", Version: "v1"}
+
+	presp, err := utils.CreateUnendorsedProposalResponse(prop.Header, prop.Payload, response, []byte("res"), []byte("event"), ccid, nil)
+	assert.NoError(t, err, "Could not create unendorsed proposal response")
+	assert.Nil(t, presp.Endorsement)
+	assert.Equal(t, response, presp.Response)
+
+	pRespPayload, err := utils.GetProposalResponsePayload(presp.Payload)
+	assert.NoError(t, err, "Error while unmarshaling proposal response payload")
+	ca, err := utils.GetChaincodeAction(pRespPayload.Extension)
+	assert.NoError(t, err, "Error while unmarshaling chaincode action")
+	assert.Equal(t, []byte("res"), ca.Results)
+	assert.Equal(t, []byte("event"), ca.Events)
+	assert.Equal(t, "foo", ca.ChaincodeId.Name)
+}
+
 // mock
 var badSigner = &mockLocalSigner{
 	returnError: true,